@@ -0,0 +1,212 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3metrics/metadata"
+	"github.com/m3db/m3metrics/policy"
+	"github.com/m3db/m3x/clock"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	// deadbandEntriesExpiry is how long the last stored value of a series is
+	// kept once the series stops receiving samples.
+	deadbandEntriesExpiry = 10 * time.Minute
+)
+
+// DeadbandConfiguration is the configuration for dropping gauge samples whose
+// value has not changed since the last sample stored for their series.
+type DeadbandConfiguration struct {
+	// Rules are the deadband rules, each applies to the samples matched by
+	// the mapping rules storing to its storage policy.
+	Rules []DeadbandRuleConfiguration `yaml:"rules"`
+}
+
+// DeadbandRuleConfiguration is the deadband applied to the samples of a
+// storage policy.
+type DeadbandRuleConfiguration struct {
+	// StoragePolicy is the storage policy of the mapping rules the rule
+	// applies to.
+	StoragePolicy policy.StoragePolicy `yaml:"storagePolicy" validate:"nonzero"`
+
+	// Epsilon is the change in value from the last stored sample below
+	// which samples are dropped.
+	Epsilon float64 `yaml:"epsilon" validate:"min=0"`
+
+	// MaxInterval is the interval after which a sample is stored even if
+	// its value has not changed, disabled if zero.
+	MaxInterval time.Duration `yaml:"maxInterval" validate:"min=0"`
+}
+
+// Validate validates the deadband configuration.
+func (c DeadbandConfiguration) Validate() error {
+	seen := make(map[policy.StoragePolicy]struct{}, len(c.Rules))
+	for _, rule := range c.Rules {
+		if rule.Epsilon < 0 || math.IsNaN(rule.Epsilon) {
+			return fmt.Errorf("invalid deadband epsilon for %s: %v",
+				rule.StoragePolicy.String(), rule.Epsilon)
+		}
+		if rule.MaxInterval < 0 {
+			return fmt.Errorf("invalid deadband max interval for %s: %v",
+				rule.StoragePolicy.String(), rule.MaxInterval)
+		}
+		if _, ok := seen[rule.StoragePolicy]; ok {
+			return fmt.Errorf("duplicate deadband rule for %s",
+				rule.StoragePolicy.String())
+		}
+		seen[rule.StoragePolicy] = struct{}{}
+	}
+	return nil
+}
+
+type deadbandMetrics struct {
+	stored  tally.Counter
+	dropped tally.Counter
+}
+
+func newDeadbandMetrics(scope tally.Scope) deadbandMetrics {
+	return deadbandMetrics{
+		stored:  scope.Counter("stored"),
+		dropped: scope.Counter("dropped"),
+	}
+}
+
+type deadbandEntry struct {
+	value    float64
+	stored   time.Time
+	lastSeen time.Time
+}
+
+// deadbandFilter tracks the last value stored for each series and storage
+// policy and drops the storage policies of samples within the deadband.
+type deadbandFilter struct {
+	sync.Mutex
+
+	nowFn       clock.NowFn
+	rules       map[policy.StoragePolicy]DeadbandRuleConfiguration
+	entries     map[string]deadbandEntry
+	lastExpired time.Time
+	metrics     deadbandMetrics
+}
+
+func newDeadbandFilter(
+	config DeadbandConfiguration,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *deadbandFilter {
+	if len(config.Rules) == 0 {
+		return nil
+	}
+	rules := make(map[policy.StoragePolicy]DeadbandRuleConfiguration,
+		len(config.Rules))
+	for _, rule := range config.Rules {
+		rules[rule.StoragePolicy] = rule
+	}
+	return &deadbandFilter{
+		nowFn:       nowFn,
+		rules:       rules,
+		entries:     make(map[string]deadbandEntry),
+		lastExpired: nowFn(),
+		metrics:     newDeadbandMetrics(scope),
+	}
+}
+
+// filter returns the staged metadatas without the storage policies the
+// gauge sample falls within the deadband of.
+func (f *deadbandFilter) filter(
+	id []byte,
+	t time.Time,
+	value float64,
+	metadatas metadata.StagedMetadatas,
+) metadata.StagedMetadatas {
+	var (
+		now     = f.nowFn()
+		checked []policy.StoragePolicy
+		dropped []policy.StoragePolicy
+	)
+
+	f.Lock()
+	for _, staged := range metadatas {
+		for _, pipeline := range staged.Pipelines {
+			for _, sp := range pipeline.StoragePolicies {
+				rule, ok := f.rules[sp]
+				if !ok || containsStoragePolicy(checked, sp) {
+					continue
+				}
+				checked = append(checked, sp)
+				if f.withinDeadbandWithLock(id, rule, t, now, value) {
+					dropped = append(dropped, sp)
+				}
+			}
+		}
+	}
+	f.expireWithLock(now)
+	f.Unlock()
+
+	if len(dropped) == 0 {
+		return metadatas
+	}
+	return withoutStoragePolicies(metadatas, dropped)
+}
+
+func (f *deadbandFilter) withinDeadbandWithLock(
+	id []byte,
+	rule DeadbandRuleConfiguration,
+	t time.Time,
+	now time.Time,
+	value float64,
+) bool {
+	key := flushedAggregateKey(id, rule.StoragePolicy)
+	entry, ok := f.entries[key]
+	within := ok &&
+		math.Abs(value-entry.value) <= rule.Epsilon &&
+		(rule.MaxInterval <= 0 || t.Sub(entry.stored) < rule.MaxInterval)
+	if within {
+		entry.lastSeen = now
+		f.entries[key] = entry
+		f.metrics.dropped.Inc(1)
+		return true
+	}
+
+	f.entries[key] = deadbandEntry{value: value, stored: t, lastSeen: now}
+	f.metrics.stored.Inc(1)
+	return false
+}
+
+func (f *deadbandFilter) expireWithLock(now time.Time) {
+	if now.Sub(f.lastExpired) < deadbandEntriesExpiry {
+		return
+	}
+	cutoff := now.Add(-deadbandEntriesExpiry)
+	for k, v := range f.entries {
+		if v.lastSeen.Before(cutoff) {
+			delete(f.entries, k)
+		}
+	}
+	f.lastExpired = now
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3metrics/policy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDeadbandConfigurationUnmarshal(t *testing.T) {
+	var cfg DeadbandConfiguration
+	err := yaml.Unmarshal([]byte(`
+rules:
+  - storagePolicy: 10s:2d
+    epsilon: 0.5
+    maxInterval: 5m
+`), &cfg)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, policy.MustParseStoragePolicy("10s:2d"), cfg.Rules[0].StoragePolicy)
+	assert.Equal(t, 0.5, cfg.Rules[0].Epsilon)
+	assert.Equal(t, 5*time.Minute, cfg.Rules[0].MaxInterval)
+
+	cfg.Rules = append(cfg.Rules, cfg.Rules[0])
+	require.Error(t, cfg.Validate())
+}
+
+func TestDeadbandFilter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	filter := newDeadbandFilter(DeadbandConfiguration{
+		Rules: []DeadbandRuleConfiguration{
+			{
+				StoragePolicy: policy.MustParseStoragePolicy("10s:2d"),
+				Epsilon:       0.5,
+				MaxInterval:   time.Minute,
+			},
+		},
+	}, func() time.Time { return now }, tally.NoopScope)
+	metadatas := testStagedMetadatas("10s:2d", "1m:40d")
+	id := []byte("foo")
+
+	// The first sample of a series is always stored.
+	assert.Equal(t, metadatas, filter.filter(id, now, 10, metadatas))
+
+	// Samples within the epsilon are dropped for the deadbanded policy only.
+	filtered := filter.filter(id, now.Add(10*time.Second), 10.4, metadatas)
+	require.Len(t, filtered, 1)
+	require.Len(t, filtered[0].Pipelines, 1)
+	assert.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("1m:40d"),
+	}, filtered[0].Pipelines[0].StoragePolicies)
+
+	// Samples beyond the epsilon are stored and become the new reference.
+	assert.Equal(t, metadatas, filter.filter(id, now.Add(20*time.Second), 11, metadatas))
+	assert.Len(t, filter.filter(id, now.Add(30*time.Second), 10.6, metadatas)[0].
+		Pipelines[0].StoragePolicies, 1)
+
+	// Unchanged samples are stored once the max interval elapses.
+	assert.Equal(t, metadatas, filter.filter(id, now.Add(80*time.Second), 11, metadatas))
+
+	// Other series are tracked separately.
+	assert.Equal(t, metadatas, filter.filter([]byte("bar"), now, 11, metadatas))
+}
+
+func TestNewDeadbandFilterNoRules(t *testing.T) {
+	assert.Nil(t, newDeadbandFilter(DeadbandConfiguration{}, time.Now, tally.NoopScope))
+}
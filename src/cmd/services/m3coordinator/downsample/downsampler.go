@@ -20,9 +20,14 @@
 
 package downsample
 
+import "time"
+
 // Downsampler is a downsampler.
 type Downsampler interface {
 	NewMetricsAppender() MetricsAppender
+
+	// Health returns the health of the aggregation state.
+	Health() DownsamplerHealth
 }
 
 // MetricsAppender is a metrics appender that can build a samples
@@ -39,6 +44,16 @@ type MetricsAppender interface {
 type SamplesAppender interface {
 	AppendCounterSample(value int64) error
 	AppendGaugeSample(value float64) error
+
+	// AppendCounterTimedSample appends a counter sample observed at a time,
+	// samples for windows already flushed are handled by the late arrival
+	// policy.
+	AppendCounterTimedSample(t time.Time, value int64) error
+
+	// AppendGaugeTimedSample appends a gauge sample observed at a time,
+	// samples for windows already flushed are handled by the late arrival
+	// policy.
+	AppendGaugeTimedSample(t time.Time, value float64) error
 }
 
 type downsampler struct {
@@ -68,9 +83,17 @@ func (d *downsampler) NewMetricsAppender() MetricsAppender {
 		tagEncoder:              d.agg.pools.tagEncoderPool.Get(),
 		matcher:                 d.agg.matcher,
 		encodedTagsIteratorPool: d.agg.pools.encodedTagsIteratorPool,
+		health:                  d.agg.health,
+		lateArrivals:            d.agg.lateArrivals,
+		deadband:                d.agg.deadband,
+		nameTag:                 d.agg.nameTag,
 	})
 }
 
+func (d *downsampler) Health() DownsamplerHealth {
+	return d.agg.health.Health()
+}
+
 func newMetricsAppender(opts metricsAppenderOptions) *metricsAppender {
 	return &metricsAppender{
 		metricsAppenderOptions: opts,
@@ -22,17 +22,18 @@ package downsample
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/ts"
-	"github.com/m3db/m3aggregator/aggregator/handler"
 	"github.com/m3db/m3aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3metrics/metric/aggregated"
 	"github.com/m3db/m3x/instrument"
 	xsync "github.com/m3db/m3x/sync"
+	xtime "github.com/m3db/m3x/time"
 
 	"github.com/uber-go/tally"
 )
@@ -46,6 +47,9 @@ type downsamplerFlushHandler struct {
 	storage                 storage.Storage
 	encodedTagsIteratorPool *encodedTagsIteratorPool
 	workerPool              xsync.WorkerPool
+	health                  *healthTracker
+	windows                 *flushedWindows
+	flushed                 *flushedAggregates
 	instrumentOpts          instrument.Options
 	metrics                 downsamplerFlushHandlerMetrics
 }
@@ -68,13 +72,19 @@ func newDownsamplerFlushHandler(
 	storage storage.Storage,
 	encodedTagsIteratorPool *encodedTagsIteratorPool,
 	workerPool xsync.WorkerPool,
+	health *healthTracker,
+	windows *flushedWindows,
+	flushed *flushedAggregates,
 	instrumentOpts instrument.Options,
-) handler.Handler {
+) *downsamplerFlushHandler {
 	scope := instrumentOpts.MetricsScope().SubScope("downsampler-flush-handler")
 	return &downsamplerFlushHandler{
 		storage:                 storage,
 		encodedTagsIteratorPool: encodedTagsIteratorPool,
 		workerPool:              workerPool,
+		health:                  health,
+		windows:                 windows,
+		flushed:                 flushed,
 		instrumentOpts:          instrumentOpts,
 		metrics:                 newDownsamplerFlushHandlerMetrics(scope),
 	}
@@ -92,6 +102,56 @@ func (h *downsamplerFlushHandler) NewWriter(
 func (h *downsamplerFlushHandler) Close() {
 }
 
+// write writes a datapoint for the series with the encoded tags of the ID,
+// adding an aggregation suffix tag if the suffix is not empty.
+func (h *downsamplerFlushHandler) write(
+	ctx context.Context,
+	id []byte,
+	suffix []byte,
+	timeNanos int64,
+	value float64,
+	unit xtime.Unit,
+	attrs storage.Attributes,
+) error {
+	iter := h.encodedTagsIteratorPool.Get()
+	iter.Reset(id)
+
+	expected := iter.NumTags()
+	if len(suffix) != 0 {
+		expected++
+	}
+
+	// Add extra tag since we may need to add an aggregation suffix tag
+	tags := make(models.Tags, expected+1)
+	for iter.Next() {
+		name, value := iter.Current()
+		tags[string(name)] = string(value)
+	}
+	if len(suffix) != 0 {
+		tags[aggregationSuffixTag] = string(suffix)
+	}
+
+	err := iter.Err()
+	iter.Close()
+	if err != nil {
+		return fmt.Errorf("preparing write: %v", err)
+	}
+
+	err = h.storage.Write(ctx, &storage.WriteQuery{
+		Tags: tags,
+		Datapoints: ts.Datapoints{ts.Datapoint{
+			Timestamp: time.Unix(0, timeNanos),
+			Value:     value,
+		}},
+		Unit:       unit,
+		Attributes: attrs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed write: %v", err)
+	}
+	return nil
+}
+
 type downsamplerFlushHandlerWriter struct {
 	wg      sync.WaitGroup
 	ctx     context.Context
@@ -101,53 +161,33 @@ type downsamplerFlushHandlerWriter struct {
 func (w *downsamplerFlushHandlerWriter) Write(
 	mp aggregated.ChunkedMetricWithStoragePolicy,
 ) error {
-	w.wg.Add(1)
-	w.handler.workerPool.Go(func() {
-		defer w.wg.Done()
-
-		logger := w.handler.instrumentOpts.Logger()
-
-		iter := w.handler.encodedTagsIteratorPool.Get()
-		iter.Reset(mp.ChunkedID.Data)
+	if w.handler.health != nil {
+		w.handler.health.recordFlush(mp.StoragePolicy.Resolution().Window, mp.TimeNanos)
+	}
 
-		expected := iter.NumTags()
-		if len(mp.ChunkedID.Suffix) != 0 {
-			expected++
-		}
+	if w.handler.windows != nil {
+		w.handler.windows.record(mp.ChunkedID.Data, mp.StoragePolicy, mp.TimeNanos)
+	}
 
-		// Add extra tag since we may need to add an aggregation suffix tag
-		tags := make(models.Tags, expected+1)
-		for iter.Next() {
-			name, value := iter.Current()
-			tags[string(name)] = string(value)
-		}
-		if len(mp.ChunkedID.Suffix) != 0 {
-			tags[aggregationSuffixTag] = string(mp.ChunkedID.Suffix)
-		}
+	if w.handler.flushed != nil {
+		w.handler.flushed.record(mp.ChunkedID.Data, mp.ChunkedID.Suffix,
+			mp.StoragePolicy, mp.TimeNanos, mp.Value)
+	}
 
-		err := iter.Err()
-		iter.Close()
-		if err != nil {
-			logger.Errorf("downsampler flush error preparing write: %v", err)
-			w.handler.metrics.flushErrors.Inc(1)
-			return
-		}
+	w.wg.Add(1)
+	w.handler.workerPool.Go(func() {
+		defer w.wg.Done()
 
-		err = w.handler.storage.Write(w.ctx, &storage.WriteQuery{
-			Tags: tags,
-			Datapoints: ts.Datapoints{ts.Datapoint{
-				Timestamp: time.Unix(0, mp.TimeNanos),
-				Value:     mp.Value,
-			}},
-			Unit: mp.StoragePolicy.Resolution().Precision,
-			Attributes: storage.Attributes{
+		err := w.handler.write(w.ctx, mp.ChunkedID.Data, mp.ChunkedID.Suffix,
+			mp.TimeNanos, mp.Value, mp.StoragePolicy.Resolution().Precision,
+			storage.Attributes{
 				MetricsType: storage.AggregatedMetricsType,
 				Retention:   mp.StoragePolicy.Retention().Duration(),
 				Resolution:  mp.StoragePolicy.Resolution().Window,
-			},
-		})
+			})
 		if err != nil {
-			logger.Errorf("downsampler flush error failed write: %v", err)
+			w.handler.instrumentOpts.Logger().Errorf(
+				"downsampler flush error: %v", err)
 			w.handler.metrics.flushErrors.Inc(1)
 			return
 		}
@@ -0,0 +1,408 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3metrics/metadata"
+	"github.com/m3db/m3x/clock"
+
+	"github.com/cespare/xxhash"
+	"github.com/uber-go/tally"
+)
+
+const (
+	// mappingRulesHealthKey is the rule key that series aggregated by mapping
+	// rules are reported under, as match results do not identify the rule.
+	mappingRulesHealthKey = "mapping"
+
+	// healthSeriesExpiry is how long a series is considered held by the
+	// aggregator after it was last appended to.
+	healthSeriesExpiry = 10 * time.Minute
+
+	// healthSeriesRefresh is how stale the last seen time of a series may
+	// get before an append updates it, so that most appends only read.
+	healthSeriesRefresh = time.Minute
+
+	// healthSeriesShards is the number of shards the series of a rule are
+	// tracked in, each with their own lock.
+	healthSeriesShards = 64
+
+	// estimatedAggregationBytes is the estimated memory held by the
+	// aggregator for each aggregation of a series, excluding the series ID.
+	estimatedAggregationBytes = 256
+)
+
+// DownsamplerHealth is the health of the downsampler aggregation state.
+type DownsamplerHealth struct {
+	Resolutions []ResolutionHealth `json:"resolutions"`
+	Rules       []RuleHealth       `json:"rules"`
+}
+
+// ResolutionHealth is the aggregation state of a resolution, windows are
+// open from the first window appended to after the last flush until the
+// latest window appended to. Dropped samples are samples that arrived after
+// their window was flushed and were dropped by the late arrival policy.
+type ResolutionHealth struct {
+	Resolution     string    `json:"resolution"`
+	OpenWindows    int       `json:"openWindows"`
+	LastFlushed    time.Time `json:"lastFlushed"`
+	FlushLagSec    float64   `json:"flushLagSec"`
+	DroppedSamples int64     `json:"droppedSamples"`
+	LateSamples    int64     `json:"lateSamples"`
+}
+
+// RuleHealth is the estimated aggregation state held for a rule, rollup
+// rules are keyed by the rollup metric name.
+type RuleHealth struct {
+	Rule           string `json:"rule"`
+	NumSeries      int    `json:"numSeries"`
+	EstimatedBytes int64  `json:"estimatedBytes"`
+}
+
+// resolutionHealth is updated with atomics, windows are tracked by the
+// start of the first and latest windows appended to and the end of the
+// latest window flushed in unix nanoseconds.
+type resolutionHealth struct {
+	resolution   int64
+	firstWindow  int64
+	latestWindow int64
+	lastFlushed  int64
+	flushLag     int64
+	dropped      int64
+	late         int64
+
+	openWindowsGauge tally.Gauge
+	flushLagGauge    tally.Gauge
+	droppedCounter   tally.Counter
+	lateCounter      tally.Counter
+}
+
+type ruleSeries struct {
+	bytes    int64
+	lastSeen int64
+}
+
+type ruleSeriesShard struct {
+	sync.RWMutex
+
+	series map[uint64]ruleSeries
+}
+
+type ruleHealth struct {
+	numSeries int64
+	bytes     int64
+	shards    [healthSeriesShards]ruleSeriesShard
+
+	seriesGauge tally.Gauge
+	bytesGauge  tally.Gauge
+}
+
+// healthTracker tracks the health of the aggregation state from the samples
+// added to and the metrics flushed by the aggregator. The resolutions and
+// rules are looked up under a read lock, their state is updated with
+// atomics and the series of a rule are sharded by the hash of their ID.
+type healthTracker struct {
+	sync.RWMutex
+
+	nowFn       clock.NowFn
+	scope       tally.Scope
+	resolutions map[time.Duration]*resolutionHealth
+	rules       map[string]*ruleHealth
+	lastExpired int64
+}
+
+func newHealthTracker(nowFn clock.NowFn, scope tally.Scope) *healthTracker {
+	return &healthTracker{
+		nowFn:       nowFn,
+		scope:       scope,
+		resolutions: make(map[time.Duration]*resolutionHealth),
+		rules:       make(map[string]*ruleHealth),
+		lastExpired: nowFn().UnixNano(),
+	}
+}
+
+// recordAppend records a series being appended to the aggregator for a rule
+// with the staged metadatas it matched.
+func (t *healthTracker) recordAppend(
+	id []byte,
+	rule string,
+	metadatas metadata.StagedMetadatas,
+) {
+	now := t.nowFn()
+	nowNanos := now.UnixNano()
+
+	numAggregations := 0
+	forEachResolution(metadatas, func(resolution time.Duration) {
+		t.resolution(resolution).recordAppend(now.Truncate(resolution).UnixNano())
+		numAggregations++
+	})
+
+	bytes := int64(len(id) + numAggregations*estimatedAggregationBytes)
+	t.rule(rule).recordAppend(xxhash.Sum64(id), bytes, nowNanos)
+
+	lastExpired := atomic.LoadInt64(&t.lastExpired)
+	if nowNanos-lastExpired >= int64(healthSeriesExpiry) &&
+		atomic.CompareAndSwapInt64(&t.lastExpired, lastExpired, nowNanos) {
+		t.expire(now)
+	}
+}
+
+// recordDropped records a sample of a resolution that arrived after its
+// window was flushed and was dropped.
+func (t *healthTracker) recordDropped(resolution time.Duration) {
+	r := t.resolution(resolution)
+	atomic.AddInt64(&r.dropped, 1)
+	r.droppedCounter.Inc(1)
+}
+
+// recordLate records a sample of a resolution that arrived after its window
+// was flushed.
+func (t *healthTracker) recordLate(resolution time.Duration) {
+	r := t.resolution(resolution)
+	atomic.AddInt64(&r.late, 1)
+	r.lateCounter.Inc(1)
+}
+
+// recordFlush records a metric of a resolution flushed by the aggregator,
+// closing the windows that end at or before the metric timestamp.
+func (t *healthTracker) recordFlush(resolution time.Duration, timeNanos int64) {
+	now := t.nowFn()
+	r := t.resolution(resolution)
+	for {
+		lastFlushed := atomic.LoadInt64(&r.lastFlushed)
+		if timeNanos <= lastFlushed {
+			return
+		}
+		if atomic.CompareAndSwapInt64(&r.lastFlushed, lastFlushed, timeNanos) {
+			break
+		}
+	}
+	flushLag := now.Sub(time.Unix(0, timeNanos))
+	atomic.StoreInt64(&r.flushLag, int64(flushLag))
+	r.flushLagGauge.Update(flushLag.Seconds())
+	r.openWindowsGauge.Update(float64(r.openWindows()))
+}
+
+// Health returns the health of the aggregation state.
+func (t *healthTracker) Health() DownsamplerHealth {
+	now := t.nowFn()
+	atomic.StoreInt64(&t.lastExpired, now.UnixNano())
+	t.expire(now)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	health := DownsamplerHealth{
+		Resolutions: make([]ResolutionHealth, 0, len(t.resolutions)),
+		Rules:       make([]RuleHealth, 0, len(t.rules)),
+	}
+	resolutions := make([]time.Duration, 0, len(t.resolutions))
+	for resolution := range t.resolutions {
+		resolutions = append(resolutions, resolution)
+	}
+	sort.Slice(resolutions, func(i, j int) bool {
+		return resolutions[i] < resolutions[j]
+	})
+	for _, resolution := range resolutions {
+		r := t.resolutions[resolution]
+		var lastFlushed time.Time
+		if nanos := atomic.LoadInt64(&r.lastFlushed); nanos != 0 {
+			lastFlushed = time.Unix(0, nanos)
+		}
+		health.Resolutions = append(health.Resolutions, ResolutionHealth{
+			Resolution:     resolution.String(),
+			OpenWindows:    r.openWindows(),
+			LastFlushed:    lastFlushed,
+			FlushLagSec:    time.Duration(atomic.LoadInt64(&r.flushLag)).Seconds(),
+			DroppedSamples: atomic.LoadInt64(&r.dropped),
+			LateSamples:    atomic.LoadInt64(&r.late),
+		})
+	}
+	for rule, rh := range t.rules {
+		numSeries := atomic.LoadInt64(&rh.numSeries)
+		if numSeries == 0 {
+			continue
+		}
+		health.Rules = append(health.Rules, RuleHealth{
+			Rule:           rule,
+			NumSeries:      int(numSeries),
+			EstimatedBytes: atomic.LoadInt64(&rh.bytes),
+		})
+	}
+	sort.Slice(health.Rules, func(i, j int) bool {
+		if health.Rules[i].EstimatedBytes == health.Rules[j].EstimatedBytes {
+			return health.Rules[i].Rule < health.Rules[j].Rule
+		}
+		return health.Rules[i].EstimatedBytes > health.Rules[j].EstimatedBytes
+	})
+	return health
+}
+
+func (t *healthTracker) expire(now time.Time) {
+	cutoff := now.Add(-healthSeriesExpiry).UnixNano()
+
+	t.RLock()
+	rules := make([]*ruleHealth, 0, len(t.rules))
+	for _, rh := range t.rules {
+		rules = append(rules, rh)
+	}
+	t.RUnlock()
+
+	for _, rh := range rules {
+		rh.expire(cutoff)
+	}
+}
+
+func (t *healthTracker) resolution(resolution time.Duration) *resolutionHealth {
+	t.RLock()
+	r, ok := t.resolutions[resolution]
+	t.RUnlock()
+	if ok {
+		return r
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if r, ok := t.resolutions[resolution]; ok {
+		return r
+	}
+	scope := t.scope.Tagged(map[string]string{"resolution": resolution.String()})
+	r = &resolutionHealth{
+		resolution:       int64(resolution),
+		openWindowsGauge: scope.Gauge("open-windows"),
+		flushLagGauge:    scope.Gauge("flush-lag"),
+		droppedCounter:   scope.Counter("dropped-samples"),
+		lateCounter:      scope.Counter("late-samples"),
+	}
+	t.resolutions[resolution] = r
+	return r
+}
+
+func (t *healthTracker) rule(rule string) *ruleHealth {
+	t.RLock()
+	rh, ok := t.rules[rule]
+	t.RUnlock()
+	if ok {
+		return rh
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if rh, ok := t.rules[rule]; ok {
+		return rh
+	}
+	scope := t.scope.Tagged(map[string]string{"rule": rule})
+	rh = &ruleHealth{
+		seriesGauge: scope.Gauge("rule-series"),
+		bytesGauge:  scope.Gauge("rule-estimated-bytes"),
+	}
+	for i := range rh.shards {
+		rh.shards[i].series = make(map[uint64]ruleSeries)
+	}
+	t.rules[rule] = rh
+	return rh
+}
+
+// recordAppend records a sample appended to the window starting at the
+// given unix nanoseconds, the gauge is only updated as windows open.
+func (r *resolutionHealth) recordAppend(window int64) {
+	atomic.CompareAndSwapInt64(&r.firstWindow, 0, window)
+	for {
+		latest := atomic.LoadInt64(&r.latestWindow)
+		if window <= latest {
+			return
+		}
+		if atomic.CompareAndSwapInt64(&r.latestWindow, latest, window) {
+			break
+		}
+	}
+	r.openWindowsGauge.Update(float64(r.openWindows()))
+}
+
+// openWindows returns the number of windows from the first window appended
+// to after the last flush to the latest window appended to.
+func (r *resolutionHealth) openWindows() int {
+	latest := atomic.LoadInt64(&r.latestWindow)
+	first := atomic.LoadInt64(&r.firstWindow)
+	if lastFlushed := atomic.LoadInt64(&r.lastFlushed); lastFlushed > first {
+		first = lastFlushed
+	}
+	if latest == 0 || latest < first {
+		return 0
+	}
+	return int((latest-first)/r.resolution) + 1
+}
+
+func (rh *ruleHealth) recordAppend(hash uint64, bytes int64, nowNanos int64) {
+	shard := &rh.shards[hash%healthSeriesShards]
+
+	shard.RLock()
+	prev, ok := shard.series[hash]
+	shard.RUnlock()
+	if ok && prev.bytes == bytes && nowNanos-prev.lastSeen < int64(healthSeriesRefresh) {
+		return
+	}
+
+	shard.Lock()
+	prev, ok = shard.series[hash]
+	shard.series[hash] = ruleSeries{bytes: bytes, lastSeen: nowNanos}
+	shard.Unlock()
+
+	if !ok {
+		atomic.AddInt64(&rh.numSeries, 1)
+	}
+	atomic.AddInt64(&rh.bytes, bytes-prev.bytes)
+}
+
+func (rh *ruleHealth) expire(cutoff int64) {
+	for i := range rh.shards {
+		shard := &rh.shards[i]
+		shard.Lock()
+		for hash, series := range shard.series {
+			if series.lastSeen < cutoff {
+				delete(shard.series, hash)
+				atomic.AddInt64(&rh.numSeries, -1)
+				atomic.AddInt64(&rh.bytes, -series.bytes)
+			}
+		}
+		shard.Unlock()
+	}
+	rh.seriesGauge.Update(float64(atomic.LoadInt64(&rh.numSeries)))
+	rh.bytesGauge.Update(float64(atomic.LoadInt64(&rh.bytes)))
+}
+
+func forEachResolution(
+	metadatas metadata.StagedMetadatas,
+	fn func(resolution time.Duration),
+) {
+	for _, staged := range metadatas {
+		for _, pipeline := range staged.Pipelines {
+			for _, storagePolicy := range pipeline.StoragePolicies {
+				fn(storagePolicy.Resolution().Window)
+			}
+		}
+	}
+}
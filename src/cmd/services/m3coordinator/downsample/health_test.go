@@ -0,0 +1,132 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3metrics/metadata"
+	"github.com/m3db/m3metrics/policy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func testStagedMetadatas(policies ...string) metadata.StagedMetadatas {
+	var storagePolicies policy.StoragePolicies
+	for _, p := range policies {
+		storagePolicies = append(storagePolicies, policy.MustParseStoragePolicy(p))
+	}
+	return metadata.StagedMetadatas{
+		{
+			Metadata: metadata.Metadata{
+				Pipelines: []metadata.PipelineMetadata{
+					{StoragePolicies: storagePolicies},
+				},
+			},
+		},
+	}
+}
+
+func TestHealthTrackerWindowsAndFlushLag(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := newHealthTracker(func() time.Time { return now }, tally.NoopScope)
+	metadatas := testStagedMetadatas("10s:2d", "1m:40d")
+
+	tracker.recordAppend([]byte("foo"), mappingRulesHealthKey, metadatas)
+	now = now.Add(10 * time.Second)
+	tracker.recordAppend([]byte("foo"), mappingRulesHealthKey, metadatas)
+
+	health := tracker.Health()
+	require.Len(t, health.Resolutions, 2)
+	assert.Equal(t, "10s", health.Resolutions[0].Resolution)
+	assert.Equal(t, 2, health.Resolutions[0].OpenWindows)
+	assert.Equal(t, "1m0s", health.Resolutions[1].Resolution)
+	assert.Equal(t, 1, health.Resolutions[1].OpenWindows)
+
+	// Flush the first 10s window two seconds after it closed.
+	now = now.Add(2 * time.Second)
+	tracker.recordFlush(10*time.Second, time.Unix(1010, 0).UnixNano())
+	tracker.recordDropped(10 * time.Second)
+
+	health = tracker.Health()
+	assert.Equal(t, 1, health.Resolutions[0].OpenWindows)
+	assert.Equal(t, time.Unix(1010, 0), health.Resolutions[0].LastFlushed)
+	assert.Equal(t, 2.0, health.Resolutions[0].FlushLagSec)
+	assert.Equal(t, int64(1), health.Resolutions[0].DroppedSamples)
+	assert.Equal(t, int64(0), health.Resolutions[1].DroppedSamples)
+}
+
+func TestHealthTrackerRuleMemory(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := newHealthTracker(func() time.Time { return now }, tally.NoopScope)
+
+	tracker.recordAppend([]byte("foo"), mappingRulesHealthKey, testStagedMetadatas("10s:2d"))
+	tracker.recordAppend([]byte("foo"), mappingRulesHealthKey, testStagedMetadatas("10s:2d"))
+	tracker.recordAppend([]byte("rollup"), "requests_by_host", testStagedMetadatas("10s:2d", "1m:40d"))
+
+	health := tracker.Health()
+	require.Equal(t, []RuleHealth{
+		{
+			Rule:           "requests_by_host",
+			NumSeries:      1,
+			EstimatedBytes: int64(len("rollup") + 2*estimatedAggregationBytes),
+		},
+		{
+			Rule:           mappingRulesHealthKey,
+			NumSeries:      1,
+			EstimatedBytes: int64(len("foo") + estimatedAggregationBytes),
+		},
+	}, health.Rules)
+
+	now = now.Add(healthSeriesExpiry + time.Second)
+	assert.Empty(t, tracker.Health().Rules)
+}
+
+func TestHealthTrackerConcurrentAppends(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := newHealthTracker(func() time.Time { return now }, tally.NoopScope)
+	metadatas := testStagedMetadatas("10s:2d")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := []byte(fmt.Sprintf("series-%d-%d", i, j))
+				tracker.recordAppend(id, mappingRulesHealthKey, metadatas)
+				tracker.recordAppend(id, mappingRulesHealthKey, metadatas)
+			}
+		}()
+	}
+	wg.Wait()
+
+	health := tracker.Health()
+	require.Len(t, health.Rules, 1)
+	assert.Equal(t, 800, health.Rules[0].NumSeries)
+	assert.Equal(t, 1, health.Resolutions[0].OpenWindows)
+}
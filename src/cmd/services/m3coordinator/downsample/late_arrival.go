@@ -0,0 +1,580 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3metrics/metadata"
+	"github.com/m3db/m3metrics/metric"
+	"github.com/m3db/m3metrics/metric/unaggregated"
+	"github.com/m3db/m3metrics/policy"
+	"github.com/m3db/m3x/clock"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errLateArrivalPolicyUnspecified = errors.New("late arrival policy unspecified")
+	errNoCorrectionsNamespace       = errors.New("late arrival corrections policy requires a corrections namespace")
+)
+
+// LateArrivalPolicy is the policy for samples that arrive after the
+// window they belong to has been flushed by the downsampler.
+type LateArrivalPolicy uint
+
+const (
+	// LateArrivalDrop drops late samples and counts them with a metric.
+	LateArrivalDrop LateArrivalPolicy = iota
+	// LateArrivalReemit applies late samples to the last flushed aggregates
+	// of their series and writes the corrected aggregates, samples for
+	// windows older than the last flushed window are dropped.
+	LateArrivalReemit
+	// LateArrivalCorrections writes late samples unaggregated to a
+	// corrections namespace.
+	LateArrivalCorrections
+
+	// DefaultLateArrivalPolicy is the default late arrival policy when late
+	// arrivals are handled.
+	DefaultLateArrivalPolicy = LateArrivalDrop
+)
+
+// ValidLateArrivalPolicies returns the valid late arrival policies.
+func ValidLateArrivalPolicies() []LateArrivalPolicy {
+	return []LateArrivalPolicy{LateArrivalDrop, LateArrivalReemit, LateArrivalCorrections}
+}
+
+func (p LateArrivalPolicy) String() string {
+	switch p {
+	case LateArrivalDrop:
+		return "drop"
+	case LateArrivalReemit:
+		return "reemit"
+	case LateArrivalCorrections:
+		return "corrections"
+	}
+	return "unknown"
+}
+
+// ValidateLateArrivalPolicy validates a late arrival policy.
+func ValidateLateArrivalPolicy(v LateArrivalPolicy) error {
+	for _, valid := range ValidLateArrivalPolicies() {
+		if valid == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid LateArrivalPolicy '%d' valid types are: %v",
+		uint(v), ValidLateArrivalPolicies())
+}
+
+// ParseLateArrivalPolicy parses a LateArrivalPolicy from a string.
+func ParseLateArrivalPolicy(str string) (LateArrivalPolicy, error) {
+	var r LateArrivalPolicy
+	if str == "" {
+		return r, errLateArrivalPolicyUnspecified
+	}
+	for _, valid := range ValidLateArrivalPolicies() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid LateArrivalPolicy '%s' valid types are: %v",
+		str, ValidLateArrivalPolicies())
+}
+
+// UnmarshalYAML unmarshals a LateArrivalPolicy into a valid type from string.
+func (p *LateArrivalPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseLateArrivalPolicy(str)
+	if err != nil {
+		return err
+	}
+	*p = r
+	return nil
+}
+
+// LateArrivalConfiguration is the configuration for handling samples that
+// arrive after their window has been flushed.
+type LateArrivalConfiguration struct {
+	// Policy is the late arrival policy.
+	Policy LateArrivalPolicy `yaml:"policy"`
+
+	// CorrectionsNamespace is the namespace late samples are written to
+	// with the corrections policy.
+	CorrectionsNamespace *CorrectionsNamespaceConfiguration `yaml:"correctionsNamespace"`
+}
+
+// Validate validates the late arrival configuration.
+func (c LateArrivalConfiguration) Validate() error {
+	if err := ValidateLateArrivalPolicy(c.Policy); err != nil {
+		return err
+	}
+	if c.Policy == LateArrivalCorrections && c.CorrectionsNamespace == nil {
+		return errNoCorrectionsNamespace
+	}
+	return nil
+}
+
+// CorrectionsNamespaceConfiguration identifies the aggregated namespace
+// that late samples are written to.
+type CorrectionsNamespaceConfiguration struct {
+	Retention  time.Duration `yaml:"retention" validate:"nonzero"`
+	Resolution time.Duration `yaml:"resolution" validate:"nonzero"`
+}
+
+type lateArrivalMetrics struct {
+	dropped   tally.Counter
+	reemitted tally.Counter
+	corrected tally.Counter
+	errors    tally.Counter
+}
+
+func newLateArrivalMetrics(scope tally.Scope) lateArrivalMetrics {
+	return lateArrivalMetrics{
+		dropped:   scope.Counter("dropped"),
+		reemitted: scope.Counter("reemitted"),
+		corrected: scope.Counter("corrected"),
+		errors:    scope.Counter("errors"),
+	}
+}
+
+// lateArrivalHandler detects samples for windows of their series that have
+// already been flushed and applies the late arrival policy to them.
+type lateArrivalHandler struct {
+	config  LateArrivalConfiguration
+	health  *healthTracker
+	windows *flushedWindows
+	flushed *flushedAggregates
+	handler *downsamplerFlushHandler
+	metrics lateArrivalMetrics
+}
+
+func newLateArrivalHandler(
+	config LateArrivalConfiguration,
+	health *healthTracker,
+	windows *flushedWindows,
+	flushed *flushedAggregates,
+	handler *downsamplerFlushHandler,
+	scope tally.Scope,
+) *lateArrivalHandler {
+	return &lateArrivalHandler{
+		config:  config,
+		health:  health,
+		windows: windows,
+		flushed: flushed,
+		handler: handler,
+		metrics: newLateArrivalMetrics(scope),
+	}
+}
+
+// lateStoragePolicies returns the storage policies of the staged metadatas
+// for which the window of the series containing the timestamp has already
+// been flushed.
+func (h *lateArrivalHandler) lateStoragePolicies(
+	id []byte,
+	timestamp time.Time,
+	metadatas metadata.StagedMetadatas,
+) []policy.StoragePolicy {
+	return h.windows.lateStoragePolicies(id, timestamp, metadatas)
+}
+
+// handle applies the late arrival policy to a sample that is late for the
+// given storage policies.
+func (h *lateArrivalHandler) handle(
+	id []byte,
+	t time.Time,
+	sample unaggregated.MetricUnion,
+	late []policy.StoragePolicy,
+) error {
+	for _, sp := range late {
+		h.health.recordLate(sp.Resolution().Window)
+	}
+
+	switch h.config.Policy {
+	case LateArrivalReemit:
+		return h.reemit(id, t, sample, late)
+	case LateArrivalCorrections:
+		return h.writeCorrection(id, t, sample)
+	}
+	for _, sp := range late {
+		h.health.recordDropped(sp.Resolution().Window)
+	}
+	h.metrics.dropped.Inc(1)
+	return nil
+}
+
+func (h *lateArrivalHandler) reemit(
+	id []byte,
+	t time.Time,
+	sample unaggregated.MetricUnion,
+	late []policy.StoragePolicy,
+) error {
+	for _, sp := range late {
+		resolution := sp.Resolution().Window
+		windowEnd := t.Truncate(resolution).Add(resolution).UnixNano()
+		corrected, ok := h.flushed.correct(id, sp, windowEnd, sample)
+		if !ok {
+			h.health.recordDropped(resolution)
+			h.metrics.dropped.Inc(1)
+			continue
+		}
+		for _, c := range corrected {
+			err := h.handler.write(context.Background(), id, c.suffix,
+				windowEnd, c.value, sp.Resolution().Precision,
+				storage.Attributes{
+					MetricsType: storage.AggregatedMetricsType,
+					Retention:   sp.Retention().Duration(),
+					Resolution:  resolution,
+				})
+			if err != nil {
+				h.metrics.errors.Inc(1)
+				return err
+			}
+		}
+		h.metrics.reemitted.Inc(1)
+	}
+	return nil
+}
+
+func (h *lateArrivalHandler) writeCorrection(
+	id []byte,
+	t time.Time,
+	sample unaggregated.MetricUnion,
+) error {
+	ns := h.config.CorrectionsNamespace
+	err := h.handler.write(context.Background(), id, nil, t.UnixNano(),
+		sampleValue(sample), xtime.Millisecond, storage.Attributes{
+			MetricsType: storage.AggregatedMetricsType,
+			Retention:   ns.Retention,
+			Resolution:  ns.Resolution,
+		})
+	if err != nil {
+		h.metrics.errors.Inc(1)
+		return err
+	}
+	h.metrics.corrected.Inc(1)
+	return nil
+}
+
+func sampleValue(sample unaggregated.MetricUnion) float64 {
+	if sample.Type == metric.CounterType {
+		return float64(sample.CounterVal)
+	}
+	return sample.GaugeVal
+}
+
+// withoutStoragePolicies returns the staged metadatas with the given storage
+// policies removed, pipelines left without storage policies are removed.
+func withoutStoragePolicies(
+	metadatas metadata.StagedMetadatas,
+	remove []policy.StoragePolicy,
+) metadata.StagedMetadatas {
+	result := make(metadata.StagedMetadatas, 0, len(metadatas))
+	for _, staged := range metadatas {
+		pipelines := make([]metadata.PipelineMetadata, 0, len(staged.Pipelines))
+		for _, pipeline := range staged.Pipelines {
+			storagePolicies := make([]policy.StoragePolicy, 0,
+				len(pipeline.StoragePolicies))
+			for _, sp := range pipeline.StoragePolicies {
+				if !containsStoragePolicy(remove, sp) {
+					storagePolicies = append(storagePolicies, sp)
+				}
+			}
+			if len(storagePolicies) == 0 {
+				continue
+			}
+			pipeline.StoragePolicies = storagePolicies
+			pipelines = append(pipelines, pipeline)
+		}
+		if len(pipelines) == 0 {
+			continue
+		}
+		staged.Pipelines = pipelines
+		result = append(result, staged)
+	}
+	return result
+}
+
+func containsStoragePolicy(policies []policy.StoragePolicy, sp policy.StoragePolicy) bool {
+	for _, p := range policies {
+		if p == sp {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// flushedAggregatesExpiry is how long the last flushed aggregates of a
+	// series are kept to correct late samples with the reemit policy.
+	flushedAggregatesExpiry = 10 * time.Minute
+
+	// flushedWindowsExpiry is how long the last flushed window of a series
+	// is kept to detect late samples of the series.
+	flushedWindowsExpiry = 10 * time.Minute
+)
+
+type flushedWindow struct {
+	timeNanos int64
+	lastSeen  time.Time
+}
+
+// flushedWindows keeps the end of the last window flushed for each series
+// and storage policy so that samples are only late with respect to windows
+// of their own series, series that have not been flushed, e.g. series only
+// aggregated by rollup rules, are never late.
+type flushedWindows struct {
+	sync.Mutex
+
+	nowFn       clock.NowFn
+	windows     map[string]flushedWindow
+	lastExpired time.Time
+}
+
+func newFlushedWindows(nowFn clock.NowFn) *flushedWindows {
+	return &flushedWindows{
+		nowFn:       nowFn,
+		windows:     make(map[string]flushedWindow),
+		lastExpired: nowFn(),
+	}
+}
+
+// record records a window of a series and storage policy ending at
+// timeNanos being flushed.
+func (f *flushedWindows) record(
+	id []byte,
+	sp policy.StoragePolicy,
+	timeNanos int64,
+) {
+	now := f.nowFn()
+	key := flushedAggregateKey(id, sp)
+
+	f.Lock()
+	defer f.Unlock()
+
+	if w, ok := f.windows[key]; !ok || timeNanos > w.timeNanos {
+		f.windows[key] = flushedWindow{timeNanos: timeNanos, lastSeen: now}
+	} else {
+		w.lastSeen = now
+		f.windows[key] = w
+	}
+
+	if now.Sub(f.lastExpired) >= flushedWindowsExpiry {
+		cutoff := now.Add(-flushedWindowsExpiry)
+		for k, v := range f.windows {
+			if v.lastSeen.Before(cutoff) {
+				delete(f.windows, k)
+			}
+		}
+		f.lastExpired = now
+	}
+}
+
+// lateStoragePolicies returns the storage policies of the staged metadatas
+// for which the window of the series containing the timestamp has already
+// been flushed.
+func (f *flushedWindows) lateStoragePolicies(
+	id []byte,
+	timestamp time.Time,
+	metadatas metadata.StagedMetadatas,
+) []policy.StoragePolicy {
+	var late []policy.StoragePolicy
+
+	f.Lock()
+	defer f.Unlock()
+
+	for _, staged := range metadatas {
+		for _, pipeline := range staged.Pipelines {
+			for _, sp := range pipeline.StoragePolicies {
+				w, ok := f.windows[flushedAggregateKey(id, sp)]
+				if !ok {
+					continue
+				}
+				resolution := sp.Resolution().Window
+				windowEnd := timestamp.Truncate(resolution).Add(resolution).UnixNano()
+				if windowEnd <= w.timeNanos {
+					late = append(late, sp)
+				}
+			}
+		}
+	}
+	return late
+}
+
+type correctedAggregate struct {
+	suffix []byte
+	value  float64
+}
+
+type flushedAggregate struct {
+	timeNanos int64
+	values    map[string]float64
+	lastSeen  time.Time
+}
+
+// flushedAggregates keeps the aggregates of the last window flushed for each
+// series and storage policy so that late samples can be applied to them.
+type flushedAggregates struct {
+	sync.Mutex
+
+	nowFn       clock.NowFn
+	aggregates  map[string]*flushedAggregate
+	lastExpired time.Time
+}
+
+func newFlushedAggregates(nowFn clock.NowFn) *flushedAggregates {
+	return &flushedAggregates{
+		nowFn:       nowFn,
+		aggregates:  make(map[string]*flushedAggregate),
+		lastExpired: nowFn(),
+	}
+}
+
+func flushedAggregateKey(id []byte, sp policy.StoragePolicy) string {
+	return sp.String() + ":" + string(id)
+}
+
+// record records an aggregate flushed for a series and storage policy.
+func (f *flushedAggregates) record(
+	id []byte,
+	suffix []byte,
+	sp policy.StoragePolicy,
+	timeNanos int64,
+	value float64,
+) {
+	now := f.nowFn()
+	key := flushedAggregateKey(id, sp)
+
+	f.Lock()
+	defer f.Unlock()
+
+	agg, ok := f.aggregates[key]
+	if !ok {
+		agg = &flushedAggregate{values: make(map[string]float64)}
+		f.aggregates[key] = agg
+	}
+	if timeNanos > agg.timeNanos {
+		agg.timeNanos = timeNanos
+		for k := range agg.values {
+			delete(agg.values, k)
+		}
+	}
+	if timeNanos == agg.timeNanos {
+		agg.values[string(suffix)] = value
+	}
+	agg.lastSeen = now
+
+	if now.Sub(f.lastExpired) >= flushedAggregatesExpiry {
+		cutoff := now.Add(-flushedAggregatesExpiry)
+		for k, v := range f.aggregates {
+			if v.lastSeen.Before(cutoff) {
+				delete(f.aggregates, k)
+			}
+		}
+		f.lastExpired = now
+	}
+}
+
+// correct applies a late sample to the flushed aggregates of the window
+// ending at timeNanos, returning false if the window is not the last flushed
+// window or an aggregate cannot be corrected with a single sample.
+func (f *flushedAggregates) correct(
+	id []byte,
+	sp policy.StoragePolicy,
+	timeNanos int64,
+	sample unaggregated.MetricUnion,
+) ([]correctedAggregate, bool) {
+	key := flushedAggregateKey(id, sp)
+	value := sampleValue(sample)
+
+	f.Lock()
+	defer f.Unlock()
+
+	agg, ok := f.aggregates[key]
+	if !ok || agg.timeNanos != timeNanos {
+		return nil, false
+	}
+
+	updated := make(map[string]float64, len(agg.values))
+	for suffix, prev := range agg.values {
+		next, ok := correctAggregate(suffix, sample.Type, prev, value)
+		if !ok {
+			return nil, false
+		}
+		updated[suffix] = next
+	}
+
+	corrected := make([]correctedAggregate, 0, len(updated))
+	for suffix, next := range updated {
+		agg.values[suffix] = next
+		corrected = append(corrected, correctedAggregate{
+			suffix: []byte(suffix),
+			value:  next,
+		})
+	}
+	return corrected, true
+}
+
+// correctAggregate applies a sample to an aggregate identified by its
+// aggregation suffix, aggregates without a suffix are the default
+// aggregation of the metric type.
+func correctAggregate(
+	suffix string,
+	metricType metric.Type,
+	prev, value float64,
+) (float64, bool) {
+	aggType := strings.ToLower(strings.TrimPrefix(suffix, "."))
+	if aggType == "" {
+		if metricType == metric.CounterType {
+			aggType = "sum"
+		} else {
+			aggType = "last"
+		}
+	}
+	switch aggType {
+	case "sum":
+		return prev + value, true
+	case "sumsq", "sum_sq":
+		return prev + value*value, true
+	case "count":
+		return prev + 1, true
+	case "max", "upper":
+		return math.Max(prev, value), true
+	case "min", "lower":
+		return math.Min(prev, value), true
+	case "last":
+		// The late sample was observed before the last flushed sample.
+		return prev, true
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package downsample
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3metrics/metric"
+	"github.com/m3db/m3metrics/metric/unaggregated"
+	"github.com/m3db/m3metrics/policy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestLateArrivalConfigurationUnmarshal(t *testing.T) {
+	var cfg LateArrivalConfiguration
+	err := yaml.Unmarshal([]byte(`
+policy: corrections
+correctionsNamespace:
+  retention: 48h
+  resolution: 1m
+`), &cfg)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, LateArrivalCorrections, cfg.Policy)
+	assert.Equal(t, time.Minute, cfg.CorrectionsNamespace.Resolution)
+
+	err = yaml.Unmarshal([]byte("policy: ignore"), &cfg)
+	require.Error(t, err)
+
+	cfg = LateArrivalConfiguration{Policy: LateArrivalCorrections}
+	require.Equal(t, errNoCorrectionsNamespace, cfg.Validate())
+}
+
+func TestFlushedWindowsLateStoragePolicies(t *testing.T) {
+	now := time.Unix(1000, 0)
+	windows := newFlushedWindows(func() time.Time { return now })
+	metadatas := testStagedMetadatas("10s:2d", "1m:40d")
+
+	windows.record([]byte("foo"), policy.MustParseStoragePolicy("10s:2d"),
+		time.Unix(1010, 0).UnixNano())
+
+	late := windows.lateStoragePolicies([]byte("foo"), time.Unix(1005, 0), metadatas)
+	require.Equal(t, []policy.StoragePolicy{
+		policy.MustParseStoragePolicy("10s:2d"),
+	}, late)
+	assert.Len(t, windows.lateStoragePolicies([]byte("foo"), time.Unix(1010, 0), metadatas), 0)
+
+	// Flushed windows of one series do not make samples of another late.
+	assert.Len(t, windows.lateStoragePolicies([]byte("bar"), time.Unix(1005, 0), metadatas), 0)
+
+	onTime := withoutStoragePolicies(metadatas, late)
+	require.Len(t, onTime, 1)
+	require.Len(t, onTime[0].Pipelines, 1)
+	assert.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("1m:40d"),
+	}, onTime[0].Pipelines[0].StoragePolicies)
+
+	windows.record([]byte("foo"), policy.MustParseStoragePolicy("1m:40d"),
+		time.Unix(1020, 0).UnixNano())
+	late = windows.lateStoragePolicies([]byte("foo"), time.Unix(1005, 0), metadatas)
+	require.Len(t, late, 2)
+	assert.Len(t, withoutStoragePolicies(metadatas, late), 0)
+
+	// Windows of series not flushed since the expiry are forgotten.
+	now = now.Add(flushedWindowsExpiry + time.Second)
+	windows.record([]byte("bar"), policy.MustParseStoragePolicy("10s:2d"),
+		time.Unix(1010, 0).UnixNano())
+	assert.Len(t, windows.lateStoragePolicies([]byte("foo"), time.Unix(1005, 0), metadatas), 0)
+}
+
+func TestFlushedAggregatesCorrect(t *testing.T) {
+	now := time.Unix(1000, 0)
+	flushed := newFlushedAggregates(func() time.Time { return now })
+	sp := policy.MustParseStoragePolicy("10s:2d")
+	windowEnd := time.Unix(1010, 0).UnixNano()
+
+	flushed.record([]byte("foo"), nil, sp, windowEnd, 10)
+	flushed.record([]byte("foo"), []byte("count"), sp, windowEnd, 2)
+	flushed.record([]byte("foo"), []byte("upper"), sp, windowEnd, 6)
+
+	sample := unaggregated.MetricUnion{Type: metric.CounterType, CounterVal: 7}
+	corrected, ok := flushed.correct([]byte("foo"), sp, windowEnd, sample)
+	require.True(t, ok)
+
+	values := make(map[string]float64)
+	for _, c := range corrected {
+		values[string(c.suffix)] = c.value
+	}
+	assert.Equal(t, map[string]float64{"": 17, "count": 3, "upper": 7}, values)
+
+	// Older windows and unknown series cannot be corrected.
+	_, ok = flushed.correct([]byte("foo"), sp, windowEnd-int64(10*time.Second), sample)
+	assert.False(t, ok)
+	_, ok = flushed.correct([]byte("bar"), sp, windowEnd, sample)
+	assert.False(t, ok)
+
+	// Aggregates that cannot be corrected with a single sample are dropped.
+	flushed.record([]byte("foo"), []byte("p99"), sp, windowEnd, 5)
+	_, ok = flushed.correct([]byte("foo"), sp, windowEnd, sample)
+	assert.False(t, ok)
+
+	// A newer flush replaces the aggregates of the previous window.
+	flushed.record([]byte("foo"), nil, sp, windowEnd+int64(10*time.Second), 1)
+	corrected, ok = flushed.correct([]byte("foo"), sp,
+		windowEnd+int64(10*time.Second), sample)
+	require.True(t, ok)
+	require.Len(t, corrected, 1)
+	assert.Equal(t, 8.0, corrected[0].value)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3aggregator/aggregator"
 	"github.com/m3db/m3metrics/matcher"
+	"github.com/m3db/m3metrics/metadata"
 	"github.com/m3db/m3x/clock"
 )
 
@@ -44,6 +45,10 @@ type metricsAppenderOptions struct {
 	tagEncoder              serialize.TagEncoder
 	matcher                 matcher.Matcher
 	encodedTagsIteratorPool *encodedTagsIteratorPool
+	health                  *healthTracker
+	lateArrivals            *lateArrivalHandler
+	deadband                *deadbandFilter
+	nameTag                 []byte
 }
 
 func (a *metricsAppender) AddTag(name, value string) {
@@ -83,9 +88,12 @@ func (a *metricsAppender) SamplesAppender() (SamplesAppender, error) {
 		// Only sample if going to actually aggregate
 		a.multiSamplesAppender.addSamplesAppender(samplesAppender{
 			agg:             a.agg,
+			lateArrivals:    a.lateArrivals,
+			deadband:        a.deadband,
 			unownedID:       unownedID,
 			stagedMetadatas: stagedMetadatas,
 		})
+		a.recordAppend(unownedID, mappingRulesHealthKey, stagedMetadatas)
 	}
 
 	numRollups := matchResult.NumNewRollupIDs()
@@ -93,14 +101,36 @@ func (a *metricsAppender) SamplesAppender() (SamplesAppender, error) {
 		rollup := matchResult.ForNewRollupIDsAt(i, nowNanos)
 		a.multiSamplesAppender.addSamplesAppender(samplesAppender{
 			agg:             a.agg,
+			lateArrivals:    a.lateArrivals,
 			unownedID:       rollup.ID,
 			stagedMetadatas: rollup.Metadatas,
 		})
+
+		// Rollup rules are keyed by the name of the rollup metric.
+		if a.health != nil {
+			name, err := resolveEncodedTagsNameTag(rollup.ID,
+				a.encodedTagsIteratorPool, a.nameTag)
+			if err != nil {
+				name = rollupTagName
+			}
+			a.recordAppend(rollup.ID, string(name), rollup.Metadatas)
+		}
 	}
 
 	return a.multiSamplesAppender, nil
 }
 
+func (a *metricsAppender) recordAppend(
+	id []byte,
+	rule string,
+	metadatas metadata.StagedMetadatas,
+) {
+	if a.health == nil {
+		return
+	}
+	a.health.recordAppend(id, rule, metadatas)
+}
+
 func (a *metricsAppender) Reset() {
 	a.tags.names = a.tags.names[:0]
 	a.tags.values = a.tags.values[:0]
@@ -30,7 +30,6 @@ import (
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3aggregator/aggregator"
-	"github.com/m3db/m3aggregator/aggregator/handler"
 	"github.com/m3db/m3aggregator/client"
 	"github.com/m3db/m3cluster/kv"
 	"github.com/m3db/m3cluster/kv/mem"
@@ -84,6 +83,8 @@ type DownsamplerOptions struct {
 	TagEncoderPoolOptions   pool.ObjectPoolOptions
 	TagDecoderPoolOptions   pool.ObjectPoolOptions
 	OpenTimeout             time.Duration
+	LateArrival             *LateArrivalConfiguration
+	Deadband                DeadbandConfiguration
 }
 
 // Validate validates the dynamic downsampling options.
@@ -112,6 +113,14 @@ func (o DownsamplerOptions) validate() error {
 	if o.TagDecoderPoolOptions == nil {
 		return errNoTagDecoderPoolOptions
 	}
+	if o.LateArrival != nil {
+		if err := o.LateArrival.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := o.Deadband.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -120,6 +129,11 @@ type agg struct {
 	clockOpts  clock.Options
 	matcher    matcher.Matcher
 	pools      aggPools
+	health     *healthTracker
+	nameTag    []byte
+
+	lateArrivals *lateArrivalHandler
+	deadband     *deadbandFilter
 }
 
 func (o DownsamplerOptions) newAggregator() (agg, error) {
@@ -144,6 +158,8 @@ func (o DownsamplerOptions) newAggregator() (agg, error) {
 
 	pools := o.newAggregatorPools()
 	ruleSetOpts := o.newAggregatorRulesOptions(pools)
+	health := newHealthTracker(clockOpts.NowFn(),
+		instrumentOpts.MetricsScope().SubScope("downsampler-health"))
 
 	// Use default aggregation types, in future we can provide more configurability
 	var defaultAggregationTypes aggregation.TypesConfiguration
@@ -188,9 +204,31 @@ func (o DownsamplerOptions) newAggregator() (agg, error) {
 		return agg{}, err
 	}
 
+	// Only track flushed windows if late samples are handled and only keep
+	// the flushed aggregates if late samples are applied to them, otherwise
+	// samples are aggregated into the current window regardless of their
+	// timestamp.
+	var (
+		windows *flushedWindows
+		flushed *flushedAggregates
+	)
+	if o.LateArrival != nil {
+		windows = newFlushedWindows(clockOpts.NowFn())
+		if o.LateArrival.Policy == LateArrivalReemit {
+			flushed = newFlushedAggregates(clockOpts.NowFn())
+		}
+	}
+
 	flushManager, flushHandler := o.newAggregatorFlushManagerAndHandler(serviceID,
 		placementManager, flushTimesManager, electionManager, instrumentOpts,
-		storageFlushConcurrency, pools)
+		storageFlushConcurrency, pools, health, windows, flushed)
+
+	var lateArrivals *lateArrivalHandler
+	if o.LateArrival != nil {
+		lateArrivals = newLateArrivalHandler(*o.LateArrival, health, windows,
+			flushed, flushHandler,
+			instrumentOpts.MetricsScope().SubScope("downsampler-late-arrivals"))
+	}
 
 	// Finally construct all options
 	aggregatorOpts := aggregator.NewOptions().
@@ -230,6 +268,12 @@ func (o DownsamplerOptions) newAggregator() (agg, error) {
 		aggregator: aggregatorInstance,
 		matcher:    matcher,
 		pools:      pools,
+		health:     health,
+		nameTag:    o.nameTag(),
+
+		lateArrivals: lateArrivals,
+		deadband: newDeadbandFilter(o.Deadband, clockOpts.NowFn(),
+			instrumentOpts.MetricsScope().SubScope("downsampler-deadband")),
 	}, nil
 }
 
@@ -259,11 +303,15 @@ func (o DownsamplerOptions) newAggregatorPools() aggPools {
 	}
 }
 
-func (o DownsamplerOptions) newAggregatorRulesOptions(pools aggPools) rules.Options {
-	nameTag := defaultMetricNameTagName
+func (o DownsamplerOptions) nameTag() []byte {
 	if o.NameTag != "" {
-		nameTag = []byte(o.NameTag)
+		return []byte(o.NameTag)
 	}
+	return defaultMetricNameTagName
+}
+
+func (o DownsamplerOptions) newAggregatorRulesOptions(pools aggPools) rules.Options {
+	nameTag := o.nameTag()
 
 	sortedTagIteratorFn := func(tagPairs []byte) id.SortedTagIterator {
 		it := pools.encodedTagsIteratorPool.Get()
@@ -398,7 +446,10 @@ func (o DownsamplerOptions) newAggregatorFlushManagerAndHandler(
 	instrumentOpts instrument.Options,
 	storageFlushConcurrency int,
 	pools aggPools,
-) (aggregator.FlushManager, handler.Handler) {
+	health *healthTracker,
+	windows *flushedWindows,
+	flushed *flushedAggregates,
+) (aggregator.FlushManager, *downsamplerFlushHandler) {
 	flushManagerOpts := aggregator.NewFlushManagerOptions().
 		SetPlacementManager(placementManager).
 		SetFlushTimesManager(flushTimesManager).
@@ -409,7 +460,7 @@ func (o DownsamplerOptions) newAggregatorFlushManagerAndHandler(
 	flushWorkers := xsync.NewWorkerPool(storageFlushConcurrency)
 	flushWorkers.Init()
 	handler := newDownsamplerFlushHandler(o.Storage, pools.encodedTagsIteratorPool,
-		flushWorkers, instrumentOpts)
+		flushWorkers, health, windows, flushed, instrumentOpts)
 
 	return flushManager, handler
 }
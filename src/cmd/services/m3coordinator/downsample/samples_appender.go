@@ -21,6 +21,8 @@
 package downsample
 
 import (
+	"time"
+
 	"github.com/m3db/m3aggregator/aggregator"
 	"github.com/m3db/m3metrics/metadata"
 	"github.com/m3db/m3metrics/metric"
@@ -30,6 +32,8 @@ import (
 
 type samplesAppender struct {
 	agg             aggregator.Aggregator
+	lateArrivals    *lateArrivalHandler
+	deadband        *deadbandFilter
 	unownedID       []byte
 	stagedMetadatas metadata.StagedMetadatas
 }
@@ -44,6 +48,10 @@ func (a samplesAppender) AppendCounterSample(value int64) error {
 }
 
 func (a samplesAppender) AppendGaugeSample(value float64) error {
+	a, ok := a.withDeadband(time.Time{}, value)
+	if !ok {
+		return nil
+	}
 	sample := unaggregated.MetricUnion{
 		Type:     metric.GaugeType,
 		ID:       a.unownedID,
@@ -52,6 +60,70 @@ func (a samplesAppender) AppendGaugeSample(value float64) error {
 	return a.agg.AddUntimed(sample, a.stagedMetadatas)
 }
 
+func (a samplesAppender) AppendCounterTimedSample(t time.Time, value int64) error {
+	sample := unaggregated.MetricUnion{
+		Type:       metric.CounterType,
+		ID:         a.unownedID,
+		CounterVal: value,
+	}
+	return a.addTimed(t, sample)
+}
+
+func (a samplesAppender) AppendGaugeTimedSample(t time.Time, value float64) error {
+	a, ok := a.withDeadband(t, value)
+	if !ok {
+		return nil
+	}
+	sample := unaggregated.MetricUnion{
+		Type:     metric.GaugeType,
+		ID:       a.unownedID,
+		GaugeVal: value,
+	}
+	return a.addTimed(t, sample)
+}
+
+// withDeadband returns the appender without the storage policies the gauge
+// sample falls within the deadband of, returning false if none are left.
+// Untimed samples are observed at the current time.
+func (a samplesAppender) withDeadband(
+	t time.Time,
+	value float64,
+) (samplesAppender, bool) {
+	if a.deadband == nil {
+		return a, true
+	}
+	if t.IsZero() {
+		t = a.deadband.nowFn()
+	}
+	a.stagedMetadatas = a.deadband.filter(a.unownedID, t, value,
+		a.stagedMetadatas)
+	return a, len(a.stagedMetadatas) != 0
+}
+
+// addTimed adds the sample to the windows that are still open and applies
+// the late arrival policy for the storage policies already flushed.
+func (a samplesAppender) addTimed(
+	t time.Time,
+	sample unaggregated.MetricUnion,
+) error {
+	if a.lateArrivals == nil {
+		return a.agg.AddUntimed(sample, a.stagedMetadatas)
+	}
+
+	late := a.lateArrivals.lateStoragePolicies(a.unownedID, t, a.stagedMetadatas)
+	if len(late) == 0 {
+		return a.agg.AddUntimed(sample, a.stagedMetadatas)
+	}
+
+	var multiErr xerrors.MultiError
+	onTime := withoutStoragePolicies(a.stagedMetadatas, late)
+	if len(onTime) != 0 {
+		multiErr = multiErr.Add(a.agg.AddUntimed(sample, onTime))
+	}
+	multiErr = multiErr.Add(a.lateArrivals.handle(a.unownedID, t, sample, late))
+	return multiErr.FinalError()
+}
+
 // Ensure multiSamplesAppender implements SamplesAppender
 var _ SamplesAppender = (*multiSamplesAppender)(nil)
 
@@ -89,3 +161,19 @@ func (a *multiSamplesAppender) AppendGaugeSample(value float64) error {
 	}
 	return multiErr.FinalError()
 }
+
+func (a *multiSamplesAppender) AppendCounterTimedSample(t time.Time, value int64) error {
+	var multiErr xerrors.MultiError
+	for _, appender := range a.appenders {
+		multiErr = multiErr.Add(appender.AppendCounterTimedSample(t, value))
+	}
+	return multiErr.FinalError()
+}
+
+func (a *multiSamplesAppender) AppendGaugeTimedSample(t time.Time, value float64) error {
+	var multiErr xerrors.MultiError
+	for _, appender := range a.appenders {
+		multiErr = multiErr.Add(appender.AppendGaugeTimedSample(t, value))
+	}
+	return multiErr.FinalError()
+}
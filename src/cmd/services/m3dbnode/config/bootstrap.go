@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
@@ -57,6 +58,22 @@ type BootstrapConfiguration struct {
 	// CacheSeriesMetadata determines whether individual bootstrappers cache
 	// series metadata across all calls (namespaces / shards / blocks).
 	CacheSeriesMetadata *bool `yaml:"cacheSeriesMetadata"`
+
+	// ReadWhileBootstrapping determines whether reads are served for shards
+	// that are still bootstrapping using the data loaded so far.
+	ReadWhileBootstrapping bool `yaml:"readWhileBootstrapping"`
+
+	// WriteBufferSize is the max number of writes per shard to buffer while
+	// the shard is bootstrapping and apply once bootstrapped, writes wait for
+	// capacity when the buffer is full. If zero writes are applied directly.
+	WriteBufferSize int `yaml:"writeBufferSize" validate:"min=0"`
+
+	// InitialLookback limits the initial bootstrap to the most recent blocks
+	// within this duration so a node that lost its data can take writes
+	// quickly, if zero the full retention period is bootstrapped. It must be
+	// at least the block size plus buffer past of every namespace, older
+	// blocks are never flushed and only serve data still on disk.
+	InitialLookback time.Duration `yaml:"initialLookback" validate:"min=0"`
 }
 
 func (bsc BootstrapConfiguration) fsNumProcessors() int {
@@ -108,7 +125,8 @@ func (bsc BootstrapConfiguration) New(
 		SetInstrumentOptions(opts.InstrumentOptions()).
 		SetDatabaseBlockOptions(opts.DatabaseBlockOptions()).
 		SetSeriesCachePolicy(opts.SeriesCachePolicy()).
-		SetIndexMutableSegmentAllocator(mutableSegmentAllocator)
+		SetIndexMutableSegmentAllocator(mutableSegmentAllocator).
+		SetEncodingCodecRegistry(opts.EncodingCodecRegistry())
 
 	fsOpts := opts.CommitLogOptions().FilesystemOptions()
 
@@ -167,6 +185,9 @@ func (bsc BootstrapConfiguration) New(
 	if bsc.CacheSeriesMetadata != nil {
 		providerOpts = providerOpts.SetCacheSeriesMetadata(*bsc.CacheSeriesMetadata)
 	}
+	if bsc.InitialLookback > 0 {
+		providerOpts = providerOpts.SetInitialLookback(bsc.InitialLookback)
+	}
 	return bootstrap.NewProcessProvider(bs, providerOpts, rsOpts), nil
 }
 
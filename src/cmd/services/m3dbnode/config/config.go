@@ -26,12 +26,21 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/gctuner"
+	"github.com/m3db/m3/src/m3ninx/analysis"
+	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3x/config/hostid"
 	"github.com/m3db/m3x/instrument"
 	xlog "github.com/m3db/m3x/log"
@@ -46,6 +55,8 @@ const (
 	defaultEtcdListenHost = "http://0.0.0.0"
 	defaultEtcdClientPort = 2379
 	defaultEtcdServerPort = 2380
+
+	defaultSelfTelemetryReportInterval = 10 * time.Second
 )
 
 // Configuration is the top level configuration that includes both a DB
@@ -93,6 +104,11 @@ type DBConfiguration struct {
 	// The initial garbage collection target percentage.
 	GCPercentage int `yaml:"gcPercentage" validate:"max=100"`
 
+	// GCTuning is the configuration for tuning the garbage collection target
+	// percentage and a heap ballast at runtime from a memory budget, it
+	// overrides the initial garbage collection target percentage (optional).
+	GCTuning *gctuner.Configuration `yaml:"gcTuning"`
+
 	// Write new series limit per second to limit overwhelming during new ID bursts.
 	WriteNewSeriesLimitPerSecond int `yaml:"writeNewSeriesLimitPerSecond"`
 
@@ -120,6 +136,9 @@ type DBConfiguration struct {
 	// The repair policy for repairing in-memory data.
 	Repair RepairPolicy `yaml:"repair"`
 
+	// The peer streaming configuration for bootstrapping and repairing.
+	PeerStreaming *PeerStreamingConfiguration `yaml:"peerStreaming"`
+
 	// The pooling policy.
 	PoolingPolicy PoolingPolicy `yaml:"pooling"`
 
@@ -131,6 +150,114 @@ type DBConfiguration struct {
 
 	// Write new series asynchronously for fast ingestion of new ID bursts.
 	WriteNewSeriesAsync bool `yaml:"writeNewSeriesAsync"`
+
+	// Faults is the fault injection configuration, only intended for testing
+	// failure handling and should be omitted in production.
+	Faults *fault.Configuration `yaml:"faults"`
+
+	// CPUPinning is the configuration for pinning worker pools to CPUs on
+	// large hosts (optional).
+	CPUPinning *CPUPinningConfiguration `yaml:"cpuPinning"`
+
+	// UnexpectedShardPolicy is the action taken at startup for fileset shard
+	// directories on disk for shards not assigned to the node, one of
+	// ignore, quarantine or read_only (optional, defaults to ignore). Shards
+	// are never deleted at startup since the placement seen at startup may
+	// be stale.
+	UnexpectedShardPolicy *storage.UnexpectedShardPolicy `yaml:"unexpectedShardPolicy"`
+
+	// WriteTimestamps is the configuration for validating the timestamps of
+	// writes too far in the future (optional).
+	WriteTimestamps *WriteTimestampConfiguration `yaml:"writeTimestamps"`
+
+	// RPCConcurrencyLimits are the concurrency limits of each class of node
+	// RPC request, requests are not limited if not set (optional).
+	RPCConcurrencyLimits *RPCConcurrencyLimitsConfiguration `yaml:"rpcConcurrencyLimits"`
+
+	// SelfTelemetry is the configuration for writing the metrics of the node
+	// to one of its own namespaces (optional).
+	SelfTelemetry *SelfTelemetryConfiguration `yaml:"selfTelemetry"`
+}
+
+// SelfTelemetryConfiguration is the configuration for writing the metrics of
+// the node to a namespace reserved for them.
+type SelfTelemetryConfiguration struct {
+	// Namespace is the namespace the metrics are written to.
+	Namespace string `yaml:"namespace" validate:"nonzero"`
+
+	// Prefix is prepended to the name of each metric written.
+	Prefix string `yaml:"prefix"`
+
+	// ReportInterval is how often metrics are written, defaults to 10s.
+	ReportInterval time.Duration `yaml:"reportInterval" validate:"min=0"`
+}
+
+// ReportIntervalOrDefault returns the report interval or the default.
+func (c SelfTelemetryConfiguration) ReportIntervalOrDefault() time.Duration {
+	if c.ReportInterval <= 0 {
+		return defaultSelfTelemetryReportInterval
+	}
+	return c.ReportInterval
+}
+
+// RPCConcurrencyLimitsConfiguration is the configuration of the concurrency
+// limits of each class of node RPC request, each class is queued separately
+// and queued requests are served in priority order of writes, reads then
+// streams so that peer bootstrap and repair streams cannot starve writes.
+type RPCConcurrencyLimitsConfiguration struct {
+	// MaxConcurrent is the max number of requests of all classes served
+	// concurrently, unlimited if zero.
+	MaxConcurrent int `yaml:"maxConcurrent" validate:"min=0"`
+
+	// Write is the limit for write requests.
+	Write RPCConcurrencyLimitConfiguration `yaml:"write"`
+
+	// Read is the limit for fetch and query requests.
+	Read RPCConcurrencyLimitConfiguration `yaml:"read"`
+
+	// Stream is the limit for the block and metadata streaming requests of
+	// peer bootstrap and repair.
+	Stream RPCConcurrencyLimitConfiguration `yaml:"stream"`
+}
+
+// RPCConcurrencyLimitConfiguration is the concurrency limit configuration of
+// a class of node RPC request.
+type RPCConcurrencyLimitConfiguration struct {
+	// MaxConcurrent is the max number of requests served concurrently,
+	// unlimited if zero.
+	MaxConcurrent int `yaml:"maxConcurrent" validate:"min=0"`
+
+	// MaxQueued is the max number of requests waiting for one of the
+	// concurrent slots, requests beyond this are rejected.
+	MaxQueued int `yaml:"maxQueued" validate:"min=0"`
+}
+
+// WriteTimestampConfiguration is the configuration for handling writes with
+// timestamps beyond the buffer future of a namespace.
+type WriteTimestampConfiguration struct {
+	// FuturePolicy is the action taken for writes beyond the buffer future
+	// plus the max clock skew, one of reject or clamp.
+	FuturePolicy storage.FutureWritePolicy `yaml:"futurePolicy"`
+
+	// MaxClockSkew is the tolerated clock skew between clients and the node
+	// beyond the buffer future before writes are rejected.
+	MaxClockSkew time.Duration `yaml:"maxClockSkew" validate:"min=0"`
+}
+
+// CPUPinningConfiguration is the configuration for pinning the major worker
+// pools to CPUs, e.g. to keep the memory of each worker local to its NUMA
+// node on multi-socket hosts.
+type CPUPinningConfiguration struct {
+	// Write pins the commit log writer, which is a single worker so is
+	// pinned to the union of the CPU sets, and the shard insert queues which
+	// are each pinned to one of the CPU sets in turn.
+	Write *cpuset.Configuration `yaml:"write"`
+
+	// Read pins the block retriever fetch loops.
+	Read *cpuset.Configuration `yaml:"read"`
+
+	// IndexQuery pins the index query workers.
+	IndexQuery *cpuset.Configuration `yaml:"indexQuery"`
 }
 
 // IndexConfiguration contains index-specific configuration.
@@ -140,6 +267,193 @@ type IndexConfiguration struct {
 	// important to prevent index queries from overloading the database entirely
 	// as they are very CPU-intensive (regex and FST matching.)
 	MaxQueryIDsConcurrency int `yaml:"maxQueryIDsConcurrency" validate:"min=0"`
+
+	// NumericTagNames are the tag names whose values are indexed as numbers,
+	// only these tags can be used in numeric range queries (e.g. status_code >= 500).
+	NumericTagNames []string `yaml:"numericTagNames"`
+
+	// IdentifierLeakDetection tracks the allocation sites of pooled IDs used
+	// by the index and reports IDs that are leaked or finalized twice, this is
+	// expensive and should only be enabled for debugging.
+	IdentifierLeakDetection bool `yaml:"identifierLeakDetection"`
+
+	// CardinalityReportInterval is how often the cardinality of each tag name
+	// of each index block is computed in the background and made available
+	// at /debug/index/cardinality, disabled if zero.
+	CardinalityReportInterval time.Duration `yaml:"cardinalityReportInterval" validate:"min=0"`
+
+	// TagDictionaryMaxEntries is the maximum number of tag names and values
+	// held by each namespace's shared tag dictionary, in memory series tags
+	// and documents inserted into the index reference the dictionary instead
+	// of holding their own copies of frequent names and values, disabled if
+	// zero. Once full, entries not used since the last index tick are evicted.
+	TagDictionaryMaxEntries int `yaml:"tagDictionaryMaxEntries" validate:"min=0"`
+
+	// SlowQueryThreshold is the duration after which index queries are logged
+	// along with the request metadata sent by the client, disabled if zero.
+	SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold" validate:"min=0"`
+
+	// FetchTaggedStreamBatchSize is the number of series fetch tagged requests
+	// take from the index at a time so that the tags of every matching series
+	// are not held in memory at once, disabled if zero.
+	FetchTaggedStreamBatchSize int `yaml:"fetchTaggedStreamBatchSize" validate:"min=0"`
+
+	// FetchTaggedStreamMaxBytes is the size of the IDs, tags and data a
+	// streamed fetch tagged response holds before it stops taking series from
+	// the index and is returned as not exhaustive, unlimited if zero.
+	FetchTaggedStreamMaxBytes int `yaml:"fetchTaggedStreamMaxBytes" validate:"min=0"`
+
+	// Warmup warms the index in the background after bootstrap (optional).
+	Warmup *IndexWarmupConfiguration `yaml:"warmup"`
+
+	// ReservedFieldNames are tag names series are not allowed to use, writes
+	// of new series using them are rejected.
+	ReservedFieldNames []string `yaml:"reservedFieldNames"`
+
+	// FieldPatterns are regular expressions the values of tags must match
+	// by tag name, writes of new series with other values are rejected.
+	FieldPatterns map[string]string `yaml:"fieldPatterns"`
+
+	// Analyzer indexes tag values by additional terms so they can be searched
+	// by more than exact matching (optional).
+	Analyzer *IndexAnalyzerConfiguration `yaml:"analyzer"`
+
+	// FullScanMaxSeries is the number of series below which a namespace with
+	// indexing disabled can be queried by tags by scanning its series, the
+	// results are not exhaustive once series may have been evicted from
+	// memory, disabled if zero.
+	FullScanMaxSeries int `yaml:"fullScanMaxSeries" validate:"min=0"`
+
+	// QueryCache caches the results of repeated index queries (optional).
+	QueryCache *IndexQueryCacheConfiguration `yaml:"queryCache"`
+
+	// PostingsListCache caches the postings lists matched by the terms and
+	// regexps of flushed index segments, such as those of the warmup queries
+	// (optional).
+	PostingsListCache *IndexPostingsListCacheConfiguration `yaml:"postingsListCache"`
+
+	// RecentlyIndexedWindow is how long IDs are remembered as indexed for a
+	// block start so that repeated inserts skip the insert queue, disabled
+	// if zero.
+	RecentlyIndexedWindow time.Duration `yaml:"recentlyIndexedWindow" validate:"min=0"`
+
+	// InsertSyncTimeout is how long a write of a new series waits for the
+	// series to be indexed when new series are indexed synchronously, the
+	// index default is used if zero.
+	InsertSyncTimeout time.Duration `yaml:"insertSyncTimeout" validate:"min=0"`
+
+	// SegmentMergePolicy determines which segments of each index block are
+	// searched by index queries, defaults to searching all segments.
+	SegmentMergePolicy *index.SegmentMergePolicy `yaml:"segmentMergePolicy"`
+
+	// MaxQueryResults is the maximum number of series returned by an index
+	// query, once reached the results so far are returned with a warning,
+	// unlimited if zero.
+	MaxQueryResults int `yaml:"maxQueryResults" validate:"min=0"`
+
+	// MaxQuerySeriesMatched is the maximum number of series matched across
+	// all blocks searched by an index query, once exceeded the results so far
+	// are returned with a warning, unlimited if zero.
+	MaxQuerySeriesMatched int `yaml:"maxQuerySeriesMatched" validate:"min=0"`
+}
+
+// IndexQueryCacheConfiguration is the configuration for caching the IDs
+// matched by index queries by query and range of index blocks.
+type IndexQueryCacheConfiguration struct {
+	// Size is the maximum number of query results cached per namespace.
+	Size int `yaml:"size" validate:"min=1"`
+
+	// TTL is how long query results are cached, series indexed since are
+	// only returned once the cached results expire.
+	TTL time.Duration `yaml:"ttl" validate:"min=0"`
+}
+
+// IndexPostingsListCacheConfiguration is the configuration for caching the
+// postings lists matched by flushed index segments.
+type IndexPostingsListCacheConfiguration struct {
+	// Size is the maximum number of postings lists cached across namespaces.
+	Size int `yaml:"size" validate:"min=1"`
+}
+
+// IndexAnalyzerConfiguration is the configuration for the analyzer that tag
+// values are indexed by in addition to their exact value.
+type IndexAnalyzerConfiguration struct {
+	// Type is the type of analyzer, one of lowercase or ngram. Queries are
+	// rewritten to search the analyzed terms, case insensitive term queries
+	// with lowercase and regexp queries containing literals with ngram.
+	Type string `yaml:"type" validate:"nonzero"`
+
+	// NGramSize is the size in bytes of the n-grams of the ngram analyzer.
+	NGramSize int `yaml:"ngramSize" validate:"min=0"`
+}
+
+// NewAnalyzer returns the analyzer of the configuration.
+func (c IndexAnalyzerConfiguration) NewAnalyzer() (analysis.Analyzer, error) {
+	switch c.Type {
+	case "lowercase":
+		return analysis.NewLowercaseAnalyzer(), nil
+	case "ngram":
+		return analysis.NewNGramAnalyzer(c.NGramSize)
+	default:
+		return nil, fmt.Errorf("unknown index analyzer type: %s", c.Type)
+	}
+}
+
+// FieldValidators returns the validators of the field patterns, each pattern
+// must match the whole value of the field.
+func (c IndexConfiguration) FieldValidators() (map[string]index.FieldValidator, error) {
+	if len(c.FieldPatterns) == 0 {
+		return nil, nil
+	}
+	validators := make(map[string]index.FieldValidator, len(c.FieldPatterns))
+	for name, pattern := range c.FieldPatterns {
+		pattern := pattern
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for field %s: %v", name, err)
+		}
+		validators[name] = func(value []byte) error {
+			if !re.Match(value) {
+				return fmt.Errorf("does not match pattern %s", pattern)
+			}
+			return nil
+		}
+	}
+	return validators, nil
+}
+
+// IndexWarmupConfiguration is the configuration for warming the index after
+// bootstrap, the segments of each index block are read so their pages are
+// resident and then the configured queries are run, priming the postings
+// list cache with the postings lists they match if it is configured.
+type IndexWarmupConfiguration struct {
+	// Queries are the queries to run, each query matches the series with all
+	// of the given tag names and values, e.g. {"__name__": "up"}.
+	Queries []map[string]string `yaml:"queries"`
+}
+
+// IndexQueries returns the warm up queries as index queries.
+func (c IndexWarmupConfiguration) IndexQueries() ([]idx.Query, error) {
+	queries := make([]idx.Query, 0, len(c.Queries))
+	for i, tags := range c.Queries {
+		if len(tags) == 0 {
+			return nil, fmt.Errorf("index warmup query %d has no tags", i)
+		}
+
+		// NB: sort the tag names so the query is deterministic.
+		names := make([]string, 0, len(tags))
+		for name := range tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		terms := make([]idx.Query, 0, len(names))
+		for _, name := range names {
+			terms = append(terms, idx.NewTermQuery([]byte(name), []byte(tags[name])))
+		}
+		queries = append(queries, idx.NewConjunctionQuery(terms...))
+	}
+	return queries, nil
 }
 
 // TickConfiguration is the tick configuration for background processing of
@@ -183,6 +497,14 @@ type CommitLogPolicy struct {
 
 	// The commit log block size.
 	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+
+	// The size in bytes after which a commit log file is rotated within a
+	// block, zero disables size based rotation.
+	MaxSegmentSize int64 `yaml:"maxSegmentSize" validate:"min=0"`
+
+	// The total size in bytes of commit log files to retain, the oldest files
+	// are cleaned up even if not yet flushed once exceeded, zero disables the bound.
+	MaxRetainedBytes int64 `yaml:"maxRetainedBytes" validate:"min=0"`
 }
 
 // CalculationType is a type of configuration parameter.
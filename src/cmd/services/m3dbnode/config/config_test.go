@@ -302,6 +302,23 @@ db:
 	expected := `db:
   index:
     maxQueryIDsConcurrency: 0
+    numericTagNames: []
+    identifierLeakDetection: false
+    cardinalityReportInterval: 0s
+    tagDictionaryMaxEntries: 0
+    slowQueryThreshold: 0s
+    fetchTaggedStreamBatchSize: 0
+    warmup: null
+    reservedFieldNames: []
+    fieldPatterns: {}
+    analyzer: null
+    fullScanMaxSeries: 0
+    queryCache: null
+    recentlyIndexedWindow: 0s
+    insertSyncTimeout: 0s
+    segmentMergePolicy: null
+    maxQueryResults: 0
+    maxQuerySeriesMatched: 0
   logging:
     file: /var/log/m3dbnode.log
     level: info
@@ -359,7 +376,10 @@ db:
     backgroundHealthCheckFailThrottleFactor: 0.5
     hashing:
       seed: 42
+    asyncWrite: null
+    sortTaggedIDsTags: false
   gcPercentage: 100
+  gcTuning: null
   writeNewSeriesLimitPerSecond: 1048576
   writeNewSeriesBackoffDuration: 2ms
   tick: null
@@ -372,20 +392,26 @@ db:
       numProcessorsPerCPU: 0.125
     peers: null
     cacheSeriesMetadata: null
+    readWhileBootstrapping: false
+    writeBufferSize: 0
+    initialLookback: 0s
   blockRetrieve: null
   cache:
     series: null
   fs:
     filePathPrefix: /var/lib/m3db
+    warmTier: null
     writeBufferSize: 65536
     dataReadBufferSize: 65536
     infoReadBufferSize: 128
     seekReadBufferSize: 4096
     throughputLimitMbps: 100
     throughputCheckEvery: 128
+    adaptiveThrottle: null
     newFileMode: null
     newDirectoryMode: null
     mmap: null
+    directIOWrites: false
   commitlog:
     flushMaxBytes: 524288
     flushEvery: 1s
@@ -394,6 +420,8 @@ db:
       size: 2097152
     retentionPeriod: 24h0m0s
     blockSize: 10m0s
+    maxSegmentSize: 0
+    maxRetainedBytes: 0
   repair:
     enabled: false
     interval: 2h0m0s
@@ -401,6 +429,7 @@ db:
     jitter: 1h0m0s
     throttle: 2m0s
     checkInterval: 1m0s
+  peerStreaming: null
   pooling:
     blockAllocSize: 16
     type: simple
@@ -526,6 +555,7 @@ db:
       size: 8192
       lowWatermark: 0.01
       highWatermark: 0.02
+    autoSize: null
   config:
     service:
       zone: embedded
@@ -584,6 +614,10 @@ db:
   hashing:
     seed: 42
   writeNewSeriesAsync: true
+  faults: null
+  cpuPinning: null
+  unexpectedShardPolicy: null
+  writeTimestamps: null
 coordinator: null
 `
 
@@ -684,3 +718,20 @@ func TestIsSeedNode(t *testing.T) {
 	res = IsSeedNode(seedNodes, "host4")
 	assert.Equal(t, false, res)
 }
+
+func TestIndexFieldValidators(t *testing.T) {
+	cfg := IndexConfiguration{
+		FieldPatterns: map[string]string{"env": "prod|dev"},
+	}
+	validators, err := cfg.FieldValidators()
+	require.NoError(t, err)
+
+	validate := validators["env"]
+	require.NotNil(t, validate)
+	assert.NoError(t, validate([]byte("prod")))
+	assert.Error(t, validate([]byte("production")))
+
+	cfg.FieldPatterns["app"] = "("
+	_, err = cfg.FieldValidators()
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 const (
@@ -47,6 +48,10 @@ type FilesystemConfiguration struct {
 	// File path prefix for reading/writing TSDB files
 	FilePathPrefix string `yaml:"filePathPrefix" validate:"nonzero"`
 
+	// WarmTier moves older data filesets to a separate, typically slower
+	// and larger, file path prefix
+	WarmTier *WarmTierConfiguration `yaml:"warmTier"`
+
 	// Write buffer size
 	WriteBufferSize int `yaml:"writeBufferSize" validate:"min=1"`
 
@@ -65,6 +70,10 @@ type FilesystemConfiguration struct {
 	// Disk flush throughput check interval
 	ThroughputCheckEvery int `yaml:"throughputCheckEvery" validate:"nonzero"`
 
+	// AdaptiveThrottle lowers the disk flush throughput limit, which becomes
+	// a ceiling, while reads are starved by the disk being saturated
+	AdaptiveThrottle *AdaptiveThrottleConfiguration `yaml:"adaptiveThrottle"`
+
 	// NewFileMode is the new file permissions mode to use when
 	// creating files - specify as three digits, e.g. 666.
 	NewFileMode *string `yaml:"newFileMode"`
@@ -75,6 +84,33 @@ type FilesystemConfiguration struct {
 
 	// Mmap is the mmap options which features are primarily platform dependent
 	Mmap *MmapConfiguration `yaml:"mmap"`
+
+	// DirectIOWrites writes data filesets with direct I/O to avoid filling
+	// the page cache during flushes, currently only supported on linux
+	DirectIOWrites bool `yaml:"directIOWrites"`
+}
+
+// AdaptiveThrottleConfiguration is the adaptive flush throttle configuration.
+type AdaptiveThrottleConfiguration struct {
+	// MinThroughputLimitMbps is the floor of the disk flush throughput limit
+	MinThroughputLimitMbps float64 `yaml:"minThroughputLimitMbps" validate:"min=0.0"`
+
+	// MaxDiskUtilization is the disk utilization, between 0 and 1, above
+	// which reads are considered starved
+	MaxDiskUtilization float64 `yaml:"maxDiskUtilization" validate:"min=0.0,max=1.0"`
+
+	// MaxReadLatency is the average read latency above which reads are
+	// considered starved, zero disables the latency check
+	MaxReadLatency time.Duration `yaml:"maxReadLatency"`
+}
+
+// WarmTierConfiguration is the warm storage tier configuration.
+type WarmTierConfiguration struct {
+	// FilePathPrefix is the file path prefix that older data filesets are moved to
+	FilePathPrefix string `yaml:"filePathPrefix" validate:"nonzero"`
+
+	// After is how long after a block ends its data fileset is moved
+	After time.Duration `yaml:"after" validate:"nonzero"`
 }
 
 // MmapConfiguration is the mmap configuration.
@@ -0,0 +1,100 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+)
+
+const (
+	defaultPeerStreamingThroughputCheckEvery = 1
+)
+
+// PeerStreamingConfiguration is the configuration for streaming blocks from
+// peers when bootstrapping and repairing.
+type PeerStreamingConfiguration struct {
+	// ThroughputLimitMbps is the streaming throughput limit in Mb/s, zero
+	// disables the limit.
+	ThroughputLimitMbps float64 `yaml:"throughputLimitMbps" validate:"min=0.0"`
+
+	// ThroughputCheckEvery is the number of streamed batches between
+	// throughput checks, defaults to every batch.
+	ThroughputCheckEvery int `yaml:"throughputCheckEvery" validate:"min=0"`
+
+	// Windows are the daily windows of time streaming is allowed in of the
+	// form "HH:MM-HH:MM", e.g. "22:00-06:00", always allowed if empty.
+	Windows []string `yaml:"windows"`
+
+	// TimeZone is the time zone of the windows, defaults to UTC.
+	TimeZone string `yaml:"timeZone"`
+}
+
+// RateLimitOptions returns the peer streaming rate limit options.
+func (c PeerStreamingConfiguration) RateLimitOptions() ratelimit.Options {
+	checkEvery := defaultPeerStreamingThroughputCheckEvery
+	if c.ThroughputCheckEvery > 0 {
+		checkEvery = c.ThroughputCheckEvery
+	}
+	return ratelimit.NewOptions().
+		SetLimitEnabled(c.ThroughputLimitMbps > 0).
+		SetLimitMbps(c.ThroughputLimitMbps).
+		SetLimitCheckEvery(checkEvery)
+}
+
+// Location returns the time zone of the windows.
+func (c PeerStreamingConfiguration) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer streaming time zone: %v", err)
+	}
+	return loc, nil
+}
+
+// TimeOfDayWindows returns the daily windows of time streaming is allowed in.
+func (c PeerStreamingConfiguration) TimeOfDayWindows() (ratelimit.TimeOfDayWindows, error) {
+	return ParsePeerStreamingWindows(c.Windows, c.TimeZone)
+}
+
+// ParsePeerStreamingWindows parses peer streaming windows in the given time
+// zone, an empty time zone defaults to UTC.
+func ParsePeerStreamingWindows(
+	values []string,
+	timeZone string,
+) (ratelimit.TimeOfDayWindows, error) {
+	loc, err := PeerStreamingConfiguration{TimeZone: timeZone}.Location()
+	if err != nil {
+		return nil, err
+	}
+	windows := make(ratelimit.TimeOfDayWindows, 0, len(values))
+	for _, value := range values {
+		window, err := ratelimit.ParseTimeOfDayWindow(value, loc)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
@@ -35,6 +35,16 @@ const (
 
 const (
 	defaultMaxFinalizerCapacity = 4
+
+	defaultRequestBudgetFraction = 0.1
+	autoSizeRequestsPerShard     = 128
+
+	// The estimated bytes retained by pooled objects per series, i.e. a series,
+	// block, encoder, identifier and tags, and per request slot, i.e. a context,
+	// closers, segment reader and iterator. These exclude bytes from the bytes
+	// pool which is sized by its buckets.
+	estimatedPerSeriesBytes  = 1024
+	estimatedPerRequestBytes = 1024
 )
 
 // PoolingPolicy specifies the pooling policy.
@@ -107,6 +117,67 @@ type PoolingPolicy struct {
 
 	// The policy for the TagDecoderPool
 	TagDecoderPool PoolPolicy `yaml:"tagDecoderPool"`
+
+	// The policy for automatically sizing the major pools, if set the sizes
+	// of the series, block, encoder, identifier, tags, context, closers,
+	// segment reader and iterator pools are derived from a memory budget.
+	AutoSize *AutoSizePoolPolicy `yaml:"autoSize"`
+}
+
+// AutoSizePoolPolicy specifies how to size the major pools from a memory
+// budget and the number of series and shards observed at startup.
+type AutoSizePoolPolicy struct {
+	// The memory budget in bytes for objects retained by the sized pools.
+	MemoryBudget int64 `yaml:"memoryBudget" validate:"min=0"`
+
+	// The fraction of the budget for pools of objects used per request,
+	// the rest is for pools of objects used per series. Defaults to 0.1.
+	RequestBudgetFraction float64 `yaml:"requestBudgetFraction" validate:"min=0.0,max=1.0"`
+}
+
+// RequestBudgetFractionWithDefault returns the fraction of the budget for
+// per request pools and falls back to the default value if its not set.
+func (p AutoSizePoolPolicy) RequestBudgetFractionWithDefault() float64 {
+	if p.RequestBudgetFraction == 0 {
+		return defaultRequestBudgetFraction
+	}
+	return p.RequestBudgetFraction
+}
+
+// AutoSized returns the pooling policy with the major pools sized from the
+// memory budget, the number of series and shards are those observed at
+// startup and are zero if unknown. Pools are sized to hold an object per
+// series, or per request slot of each shard, unless the budget is exceeded
+// in which case they are scaled down to fit the budget.
+func (p PoolingPolicy) AutoSized(numSeries, numShards int) PoolingPolicy {
+	if p.AutoSize == nil || p.AutoSize.MemoryBudget <= 0 {
+		return p
+	}
+
+	var (
+		budget        = float64(p.AutoSize.MemoryBudget)
+		requestBudget = budget * p.AutoSize.RequestBudgetFractionWithDefault()
+		seriesBudget  = budget - requestBudget
+		seriesSize    = int(seriesBudget / estimatedPerSeriesBytes)
+		requestSize   = int(requestBudget / estimatedPerRequestBytes)
+	)
+	if numSeries > 0 && numSeries < seriesSize {
+		seriesSize = numSeries
+	}
+	if desired := numShards * autoSizeRequestsPerShard; desired > 0 && desired < requestSize {
+		requestSize = desired
+	}
+
+	p.SeriesPool.Size = seriesSize
+	p.BlockPool.Size = seriesSize
+	p.EncoderPool.Size = seriesSize
+	p.IdentifierPool.Size = seriesSize
+	p.TagsPool.Size = seriesSize
+	p.ContextPool.Size = requestSize
+	p.ClosersPool.Size = requestSize
+	p.SegmentReaderPool.Size = requestSize
+	p.IteratorPool.Size = requestSize
+	return p
 }
 
 // PoolPolicy specifies a single pool policy.
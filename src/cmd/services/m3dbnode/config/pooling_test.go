@@ -51,3 +51,38 @@ func TestContextPoolMaxFinalizerCapacityWithDefault(t *testing.T) {
 	cpp.MaxFinalizerCapacity = 10
 	require.Equal(t, 10, cpp.MaxFinalizerCapacityWithDefault())
 }
+
+func TestPoolingPolicyAutoSized(t *testing.T) {
+	policy := PoolingPolicy{
+		SeriesPool:  PoolPolicy{Size: 10, RefillLowWaterMark: 0.1, RefillHighWaterMark: 0.2},
+		ContextPool: ContextPoolPolicy{Size: 10},
+		TagsPool:    MaxCapacityPoolPolicy{Size: 10, Capacity: 8, MaxCapacity: 16},
+	}
+
+	// Not sized without a policy.
+	require.Equal(t, policy, policy.AutoSized(1000, 10))
+
+	// Sized to the observed series and shards when within budget.
+	policy.AutoSize = &AutoSizePoolPolicy{MemoryBudget: 1 << 30}
+	sized := policy.AutoSized(1000, 10)
+	require.Equal(t, 1000, sized.SeriesPool.Size)
+	require.Equal(t, 0.1, sized.SeriesPool.RefillLowWaterMark)
+	require.Equal(t, 0.2, sized.SeriesPool.RefillHighWaterMark)
+	require.Equal(t, 1000, sized.TagsPool.Size)
+	require.Equal(t, 16, sized.TagsPool.MaxCapacity)
+	require.Equal(t, 10*autoSizeRequestsPerShard, sized.ContextPool.Size)
+
+	// Scaled down to fit the budget.
+	policy.AutoSize = &AutoSizePoolPolicy{
+		MemoryBudget:          1000 * estimatedPerSeriesBytes,
+		RequestBudgetFraction: 0.5,
+	}
+	sized = policy.AutoSized(100000, 10)
+	require.Equal(t, 500, sized.SeriesPool.Size)
+	require.Equal(t, 500, sized.EncoderPool.Size)
+	require.Equal(t, 500*estimatedPerSeriesBytes/estimatedPerRequestBytes, sized.IteratorPool.Size)
+
+	// Sized from the budget alone when nothing was observed.
+	sized = policy.AutoSized(0, 0)
+	require.Equal(t, 500, sized.BlockPool.Size)
+}
@@ -23,12 +23,19 @@ package config
 import (
 	"time"
 
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/query/storage/federated"
+	"github.com/m3db/m3/src/query/storage/ingest"
 	"github.com/m3db/m3/src/query/storage/local"
 	etcdclient "github.com/m3db/m3cluster/client/etcd"
 	"github.com/m3db/m3x/config/listenaddress"
 	"github.com/m3db/m3x/instrument"
 )
 
+const (
+	defaultSelfTelemetryReportInterval = 10 * time.Second
+)
+
 // Configuration is the configuration for the query service.
 type Configuration struct {
 	// Metrics configuration.
@@ -58,6 +65,137 @@ type Configuration struct {
 	// DecompressWorkerPoolSize is the size of the worker pool given to each
 	// fetch request.
 	DecompressWorkerPoolSize int `yaml:"workerPoolSize"`
+
+	// RouteLimits is the per route class HTTP server limits configuration.
+	RouteLimits RouteLimitsConfiguration `yaml:"routeLimits"`
+
+	// AccessLog is the configuration for structured access logs of the write
+	// and query routes, requests are not access logged if not set.
+	AccessLog *AccessLogConfiguration `yaml:"accessLog"`
+
+	// SelfTelemetry is the configuration for writing the metrics of the
+	// query service itself to M3 (optional).
+	SelfTelemetry *SelfTelemetryConfiguration `yaml:"selfTelemetry"`
+
+	// WriteMirror is the configuration for mirroring a percentage of writes
+	// to a secondary cluster (optional).
+	WriteMirror *WriteMirrorConfiguration `yaml:"writeMirror"`
+
+	// ReadVerification is the configuration for verifying a percentage of
+	// reads against a secondary cluster (optional).
+	ReadVerification *ReadVerificationConfiguration `yaml:"readVerification"`
+
+	// Graphite is the configuration for the Graphite find and expand endpoints.
+	Graphite GraphiteConfiguration `yaml:"graphite"`
+
+	// Downsample is the configuration for the downsampler used with
+	// aggregated namespaces.
+	Downsample DownsampleConfiguration `yaml:"downsample"`
+
+	// RemoteReads are Prometheus remote read endpoints that reads are fanned
+	// out to alongside local storage (optional).
+	RemoteReads []federated.BackendConfiguration `yaml:"remoteReads"`
+
+	// WriteIdempotency is the configuration for deduplicating remote writes
+	// and RPC write batches retried with the same idempotency key.
+	WriteIdempotency ingest.IdempotencyConfiguration `yaml:"writeIdempotency"`
+
+	// LookbackDuration is how far back each step of a query looks for the
+	// most recent datapoint of a series, datapoints are carried forward
+	// indefinitely if zero.
+	LookbackDuration time.Duration `yaml:"lookbackDuration" validate:"min=0"`
+}
+
+// DownsampleConfiguration is the configuration for the downsampler.
+type DownsampleConfiguration struct {
+	// LateArrival is the policy for samples that arrive after the window of
+	// their series they belong to has been flushed (optional), if not set
+	// samples are aggregated into the current window regardless of their
+	// timestamp.
+	LateArrival *downsample.LateArrivalConfiguration `yaml:"lateArrival"`
+
+	// Deadband drops gauge samples whose value has not changed since the
+	// last sample stored for their series, per storage policy (optional).
+	Deadband downsample.DeadbandConfiguration `yaml:"deadband"`
+}
+
+// SelfTelemetryConfiguration is the configuration for writing the counters
+// and gauges of the query service to a namespace reserved for them.
+type SelfTelemetryConfiguration struct {
+	// Prefix is prepended to the name of each metric written.
+	Prefix string `yaml:"prefix"`
+
+	// ReportInterval is how often metrics are written, defaults to 10s.
+	ReportInterval time.Duration `yaml:"reportInterval" validate:"min=0"`
+
+	// Retention and Resolution select the aggregated namespace reserved for
+	// the metrics, if not set metrics are written to the unaggregated
+	// namespace.
+	Retention  time.Duration `yaml:"retention"`
+	Resolution time.Duration `yaml:"resolution"`
+}
+
+// ReportIntervalOrDefault returns the report interval or the default.
+func (c SelfTelemetryConfiguration) ReportIntervalOrDefault() time.Duration {
+	if c.ReportInterval <= 0 {
+		return defaultSelfTelemetryReportInterval
+	}
+	return c.ReportInterval
+}
+
+// WriteMirrorConfiguration is the configuration for asynchronously mirroring
+// a percentage of writes to a secondary cluster, e.g. to validate a new
+// cluster version with production load.
+type WriteMirrorConfiguration struct {
+	// Clusters is the secondary DB cluster configuration writes are
+	// mirrored to.
+	Clusters local.ClustersStaticConfiguration `yaml:"clusters" validate:"nonzero"`
+
+	// Percent is the percentage of series whose writes are mirrored.
+	Percent float64 `yaml:"percent" validate:"min=0.0,max=100.0"`
+
+	// QueueSize is the number of writes queued for the secondary cluster
+	// before writes are dropped.
+	QueueSize int `yaml:"queueSize" validate:"min=0"`
+
+	// Concurrency is the number of concurrent writes to the secondary cluster.
+	Concurrency int `yaml:"concurrency" validate:"min=0"`
+}
+
+// ReadVerificationConfiguration is the configuration for dual reads that
+// compare the results of a secondary cluster with the primary results, e.g.
+// during a storage migration. Only the primary results are returned.
+type ReadVerificationConfiguration struct {
+	// Clusters is the secondary DB cluster configuration reads are
+	// verified against.
+	Clusters local.ClustersStaticConfiguration `yaml:"clusters" validate:"nonzero"`
+
+	// Percent is the percentage of reads that are verified.
+	Percent float64 `yaml:"percent" validate:"min=0.0,max=100.0"`
+
+	// Tolerance is the relative difference allowed between values.
+	Tolerance float64 `yaml:"tolerance" validate:"min=0.0"`
+
+	// Timeout is the timeout of reads from the secondary cluster.
+	Timeout time.Duration `yaml:"timeout" validate:"min=0"`
+
+	// MaxInFlight is the maximum number of concurrent verifications.
+	MaxInFlight int `yaml:"maxInFlight" validate:"min=0"`
+}
+
+// GraphiteConfiguration is the configuration for mapping Graphite metric
+// paths to tags, each path node is stored as a tag named with the prefix,
+// the index of the node and the suffix, e.g. __g0__.
+type GraphiteConfiguration struct {
+	// PathTagPrefix is the prefix of path node tag names, defaults to "__g".
+	PathTagPrefix string `yaml:"pathTagPrefix"`
+
+	// PathTagSuffix is the suffix of path node tag names, defaults to "__".
+	PathTagSuffix string `yaml:"pathTagSuffix"`
+
+	// FindLimit is the max number of series searched per find or expand
+	// request.
+	FindLimit int `yaml:"findLimit" validate:"min=0"`
 }
 
 // LocalConfiguration is the local embedded configuration if running
@@ -75,6 +213,21 @@ type LocalConfiguration struct {
 type ClusterManagementConfiguration struct {
 	// Etcd is the client configuration for etcd.
 	Etcd etcdclient.Configuration `yaml:"etcd"`
+
+	// NamespaceUsage is the configuration for collecting the usage of
+	// namespaces from the dbnodes for the namespace get endpoint (optional).
+	NamespaceUsage *NamespaceUsageConfiguration `yaml:"namespaceUsage"`
+}
+
+// NamespaceUsageConfiguration is the configuration for collecting the usage
+// of namespaces from the debug endpoints of the dbnodes in the placement.
+type NamespaceUsageConfiguration struct {
+	// DBNodeDebugPort is the port of the debug listen address of the
+	// dbnodes, defaults to 9004.
+	DBNodeDebugPort int `yaml:"dbnodeDebugPort" validate:"min=0"`
+
+	// Timeout is the timeout of the request to each dbnode, defaults to 5s.
+	Timeout time.Duration `yaml:"timeout" validate:"min=0"`
 }
 
 // RPCConfiguration is the RPC configuration for the coordinator for
@@ -90,3 +243,49 @@ type RPCConfiguration struct {
 	// coordinator calls.
 	RemoteListenAddresses []string `yaml:"remoteListenAddresses"`
 }
+
+// RouteLimitsConfiguration is the HTTP server limits configuration for each
+// class of route, so that slow requests of one class such as range queries
+// cannot starve requests of another class such as writes.
+type RouteLimitsConfiguration struct {
+	// Write is the limits for the write routes.
+	Write *RouteLimitConfiguration `yaml:"write"`
+
+	// Query is the limits for the query routes other than range queries.
+	Query *RouteLimitConfiguration `yaml:"query"`
+
+	// RangeQuery is the limits for the range query routes.
+	RangeQuery *RouteLimitConfiguration `yaml:"rangeQuery"`
+
+	// Admin is the limits for the placement, namespace and database routes.
+	Admin *RouteLimitConfiguration `yaml:"admin"`
+}
+
+// AccessLogConfiguration is the configuration for access logs.
+type AccessLogConfiguration struct {
+	// SampleRate is the fraction of successful requests logged, requests that
+	// fail with a server error are always logged.
+	SampleRate float64 `yaml:"sampleRate" validate:"min=0.0,max=1.0"`
+
+	// TenantHeader is the header the tenant of a request is read from, the
+	// tenant is not logged if empty.
+	TenantHeader string `yaml:"tenantHeader"`
+
+	// TagHeaders are the headers whose values are logged as tags of a request.
+	TagHeaders []string `yaml:"tagHeaders"`
+}
+
+// RouteLimitConfiguration is the limits configuration for a class of route.
+type RouteLimitConfiguration struct {
+	// Timeout is the deadline set on the context of each request, including
+	// time spent queued, no deadline is set if zero.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// MaxConcurrent is the max number of requests served concurrently,
+	// unlimited if zero.
+	MaxConcurrent int `yaml:"maxConcurrent" validate:"min=0"`
+
+	// MaxQueued is the max number of requests waiting for one of the
+	// concurrent slots, requests beyond this are rejected.
+	MaxQueued int `yaml:"maxQueued" validate:"min=0"`
+}
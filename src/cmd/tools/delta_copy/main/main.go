@@ -0,0 +1,154 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/client/delta"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	xconfig "github.com/m3db/m3x/config"
+	"github.com/m3db/m3x/instrument"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/pborman/getopt"
+)
+
+// configuration is the configuration of the clients of the clusters.
+type configuration struct {
+	// Source is the client configuration of the cluster to read from.
+	Source client.Configuration `yaml:"source"`
+
+	// Destination is the client configuration of the cluster to write to.
+	Destination client.Configuration `yaml:"destination"`
+}
+
+func main() {
+	var (
+		optConfig     = getopt.StringLong("config", 'f', "", "Config file with the source and destination client configurations")
+		optSourceNs   = getopt.StringLong("source-namespace", 's', "", "Namespace to read from")
+		optDestNs     = getopt.StringLong("destination-namespace", 'd', "", "Namespace to write to, defaults to the source namespace")
+		optTags       = getopt.StringLong("tags", 't', "", "Comma separated tag matchers of the series to copy [e.g. app=foo,host=~web.*]")
+		optStart      = getopt.StringLong("start", 'b', "", "Inclusive start of the time range to copy in RFC3339")
+		optEnd        = getopt.StringLong("end", 'e', "", "Exclusive end of the time range to copy in RFC3339")
+		optWindow     = getopt.StringLong("window", 'w', delta.DefaultWindow.String(), "Size of the time ranges copied and checkpointed at a time")
+		optRateLimit  = getopt.IntLong("rate-limit", 'r', 0, "Max datapoints written per second, 0 is unlimited")
+		optCheckpoint = getopt.StringLong("checkpoint", 'c', "", "File to record progress to and resume from")
+		log           = xlog.NewLogger(os.Stderr)
+	)
+	getopt.Parse()
+
+	if *optConfig == "" || *optSourceNs == "" || *optTags == "" ||
+		*optStart == "" || *optEnd == "" {
+		getopt.Usage()
+		os.Exit(1)
+	}
+	if *optDestNs == "" {
+		*optDestNs = *optSourceNs
+	}
+
+	query, err := parseQuery(*optTags)
+	if err != nil {
+		log.Fatalf("unable to parse tags: %v", err)
+	}
+	start, err := time.Parse(time.RFC3339, *optStart)
+	if err != nil {
+		log.Fatalf("unable to parse start: %v", err)
+	}
+	end, err := time.Parse(time.RFC3339, *optEnd)
+	if err != nil {
+		log.Fatalf("unable to parse end: %v", err)
+	}
+	window, err := time.ParseDuration(*optWindow)
+	if err != nil {
+		log.Fatalf("unable to parse window: %v", err)
+	}
+
+	var cfg configuration
+	if err := xconfig.LoadFile(&cfg, *optConfig, xconfig.Options{}); err != nil {
+		log.Fatalf("unable to load %s: %v", *optConfig, err)
+	}
+	src, err := newSession(cfg.Source)
+	if err != nil {
+		log.Fatalf("unable to connect to source: %v", err)
+	}
+	defer src.Close()
+	dst, err := newSession(cfg.Destination)
+	if err != nil {
+		log.Fatalf("unable to connect to destination: %v", err)
+	}
+	defer dst.Close()
+
+	result, err := delta.Copy(src, dst, delta.Options{
+		SourceNamespace:      *optSourceNs,
+		DestinationNamespace: *optDestNs,
+		Query:                query,
+		Start:                start,
+		End:                  end,
+		Window:               window,
+		RateLimit:            *optRateLimit,
+		CheckpointPath:       *optCheckpoint,
+		Logger:               log,
+	})
+	if err != nil {
+		log.Fatalf("unable to copy: %v", err)
+	}
+	log.Infof("copied %d series and %d datapoints in %d windows",
+		result.Series, result.Datapoints, result.Windows)
+}
+
+func newSession(cfg client.Configuration) (client.Session, error) {
+	c, err := cfg.NewClient(client.ConfigurationParameters{
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.NewSession()
+}
+
+// parseQuery parses comma separated name=value term and name=~regexp
+// matchers into a conjunction.
+func parseQuery(tags string) (index.Query, error) {
+	var queries []idx.Query
+	for _, matcher := range strings.Split(tags, ",") {
+		parts := strings.SplitN(matcher, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return index.Query{}, fmt.Errorf("invalid matcher: %s", matcher)
+		}
+		name, value := parts[0], parts[1]
+		if strings.HasPrefix(value, "~") {
+			q, err := idx.NewRegexpQuery([]byte(name), []byte(value[1:]))
+			if err != nil {
+				return index.Query{}, err
+			}
+			queries = append(queries, q)
+			continue
+		}
+		queries = append(queries, idx.NewTermQuery([]byte(name), []byte(value)))
+	}
+	return index.Query{idx.NewConjunctionQuery(queries...)}, nil
+}
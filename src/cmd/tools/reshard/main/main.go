@@ -0,0 +1,117 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package main
+
+import (
+	"os"
+
+	"github.com/m3db/m3/src/cmd/tools"
+	"github.com/m3db/m3/src/dbnode/persist/fs/reshard"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3cluster/placement"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pborman/getopt"
+)
+
+func main() {
+	var (
+		optSrcPathPrefix  = getopt.StringLong("src-path-prefix", 'p', "", "Source path prefix [e.g. /var/lib/m3db]")
+		optDestPathPrefix = getopt.StringLong("dest-path-prefix", 'd', "", "Destination path prefix [e.g. /tmp/m3db-resharded]")
+		optNamespace      = getopt.StringLong("namespace", 'n', "", "Namespace [e.g. metrics]")
+		optSrcNumShards   = getopt.Uint32Long("src-num-shards", 's', 0, "Source number of shards")
+		optDestNumShards  = getopt.Uint32Long("dest-num-shards", 'r', 0, "Destination number of shards")
+		optPlacementFile  = getopt.StringLong("placement-file", 'f', "", "Resharded placement JSON, writes shards per owning instance (optional)")
+		optRoutingTag     = getopt.StringLong("shard-routing-tag", 't', "", "Tag whose value alone routes series to shards [e.g. region] (optional)")
+		log               = xlog.NewLogger(os.Stderr)
+	)
+	getopt.Parse()
+
+	if *optSrcPathPrefix == "" ||
+		*optDestPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optSrcNumShards == 0 ||
+		*optDestNumShards == 0 {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	pathPrefixesFn := reshard.NewPathPrefixesFn(*optDestPathPrefix)
+	if *optPlacementFile != "" {
+		p, err := readPlacement(*optPlacementFile)
+		if err != nil {
+			log.Fatalf("unable to read placement: %v", err)
+		}
+		if p.NumShards() != int(*optDestNumShards) {
+			log.Fatalf("placement has %d shards, expected %d",
+				p.NumShards(), *optDestNumShards)
+		}
+		pathPrefixesFn = reshard.NewPlacementPathPrefixesFn(*optDestPathPrefix, p)
+	}
+
+	srcShards := make([]uint32, 0, *optSrcNumShards)
+	for shard := uint32(0); shard < *optSrcNumShards; shard++ {
+		srcShards = append(srcShards, shard)
+	}
+
+	bytesPool := tools.NewCheckedBytesPool()
+	bytesPool.Init()
+
+	src := reshard.Source{
+		PathPrefix: *optSrcPathPrefix,
+		Namespace:  *optNamespace,
+		Shards:     srcShards,
+	}
+	dest := reshard.Destination{
+		PathPrefixesFn: pathPrefixesFn,
+		Namespace:      *optNamespace,
+		NumShards:      int(*optDestNumShards),
+	}
+	if *optRoutingTag != "" {
+		dest.ShardRoutingTag = []byte(*optRoutingTag)
+	}
+
+	log.Infof("source: %+v", src)
+	log.Infof("destination: %s with %d shards", *optDestPathPrefix, dest.NumShards)
+
+	resharder := reshard.NewResharder(reshard.NewOptions().SetBytesPool(bytesPool))
+	result, err := resharder.Reshard(src, dest)
+	if err != nil {
+		log.Fatalf("unable to reshard: %v", err)
+	}
+
+	log.Infof("successfully resharded %d series across %d blocks and %d index blocks",
+		result.NumSeries, result.NumBlocks, result.NumIndexBlocks)
+}
+
+func readPlacement(file string) (placement.Placement, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var resp admin.PlacementGetResponse
+	if err := jsonpb.Unmarshal(f, &resp); err != nil {
+		return nil, err
+	}
+	return placement.NewPlacementFromProto(resp.Placement)
+}
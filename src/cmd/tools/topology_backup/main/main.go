@@ -0,0 +1,159 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/m3db/m3/src/dbnode/topology/backup"
+	etcdclient "github.com/m3db/m3cluster/client/etcd"
+	"github.com/m3db/m3cluster/placement"
+	"github.com/m3db/m3cluster/services"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/pborman/getopt"
+)
+
+func main() {
+	var (
+		optEndpoints = getopt.StringLong("etcd-endpoints", 'e', "", "Comma separated etcd endpoints [e.g. etcd1:2379,etcd2:2379]")
+		optEnv       = getopt.StringLong("env", 'v', "default_env", "Environment of the placement and KV store")
+		optZone      = getopt.StringLong("zone", 'z', "embedded", "Zone of the placement and etcd cluster")
+		optService   = getopt.StringLong("service", 's', "m3db", "Service of the placement")
+		optFile      = getopt.StringLong("file", 'f', "", "Backup file to export to or restore from")
+		optForce     = getopt.BoolLong("force", 0, "Restore over an existing placement and namespace registry")
+		log          = xlog.NewLogger(os.Stderr)
+	)
+	getopt.SetParameters("export|restore|diff [file-a file-b]")
+	getopt.Parse()
+
+	args := getopt.Args()
+	if len(args) == 0 {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "diff":
+		if len(args) != 3 {
+			getopt.Usage()
+			os.Exit(1)
+		}
+		a, err := readBackup(args[1])
+		if err != nil {
+			log.Fatalf("unable to read %s: %v", args[1], err)
+		}
+		b, err := readBackup(args[2])
+		if err != nil {
+			log.Fatalf("unable to read %s: %v", args[2], err)
+		}
+		diffs := backup.Diff(a, b)
+		for _, diff := range diffs {
+			fmt.Println(diff)
+		}
+		if len(diffs) > 0 {
+			os.Exit(1)
+		}
+		return
+	case "export", "restore":
+	default:
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	if *optEndpoints == "" || *optFile == "" {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	cfg := etcdclient.Configuration{
+		Zone:    *optZone,
+		Env:     *optEnv,
+		Service: *optService,
+		ETCDClusters: []etcdclient.ClusterConfig{
+			etcdclient.ClusterConfig{
+				Zone:      *optZone,
+				Endpoints: strings.Split(*optEndpoints, ","),
+			},
+		},
+	}
+	client, err := etcdclient.NewConfigServiceClient(cfg.NewOptions())
+	if err != nil {
+		log.Fatalf("unable to create etcd client: %v", err)
+	}
+	store, err := client.KV()
+	if err != nil {
+		log.Fatalf("unable to get KV store: %v", err)
+	}
+	svcs, err := client.Services(services.NewOverrideOptions())
+	if err != nil {
+		log.Fatalf("unable to get services: %v", err)
+	}
+	sid := services.NewServiceID().
+		SetName(*optService).
+		SetEnvironment(*optEnv).
+		SetZone(*optZone)
+	placements, err := svcs.PlacementService(sid,
+		placement.NewOptions().SetValidZone(*optZone))
+	if err != nil {
+		log.Fatalf("unable to get placement service: %v", err)
+	}
+
+	if args[0] == "export" {
+		b, err := backup.Export(placements, store)
+		if err != nil {
+			log.Fatalf("unable to export: %v", err)
+		}
+		f, err := os.Create(*optFile)
+		if err != nil {
+			log.Fatalf("unable to create %s: %v", *optFile, err)
+		}
+		if err := backup.Write(f, b); err != nil {
+			log.Fatalf("unable to write %s: %v", *optFile, err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("unable to close %s: %v", *optFile, err)
+		}
+		log.Infof("exported placement version %d and namespaces version %d to %s",
+			b.PlacementVersion, b.NamespacesVersion, *optFile)
+		return
+	}
+
+	b, err := readBackup(*optFile)
+	if err != nil {
+		log.Fatalf("unable to read %s: %v", *optFile, err)
+	}
+	if err := backup.Restore(b, placements, store, *optForce); err != nil {
+		log.Fatalf("unable to restore: %v", err)
+	}
+	log.Infof("restored placement and namespaces from %s", *optFile)
+}
+
+func readBackup(file string) (backup.Backup, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return backup.Backup{}, err
+	}
+	defer f.Close()
+	return backup.Read(f)
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
+)
+
+// AsyncWriteDropPolicy is the policy used to drop writes when the
+// async write queue is full.
+type AsyncWriteDropPolicy int
+
+const (
+	// AsyncWriteDropNewest drops the write being enqueued when the queue is full.
+	AsyncWriteDropNewest AsyncWriteDropPolicy = iota
+
+	// AsyncWriteDropOldest drops the oldest queued write to make room for
+	// the write being enqueued when the queue is full.
+	AsyncWriteDropOldest
+)
+
+var validAsyncWriteDropPolicies = []AsyncWriteDropPolicy{
+	AsyncWriteDropNewest,
+	AsyncWriteDropOldest,
+}
+
+var (
+	errAsyncWriterClosed           = errors.New("async writer is closed")
+	errAsyncWriteDropPolicyInvalid = errors.New("async write drop policy invalid")
+)
+
+// String returns the async write drop policy as a string.
+func (p AsyncWriteDropPolicy) String() string {
+	switch p {
+	case AsyncWriteDropNewest:
+		return "dropNewest"
+	case AsyncWriteDropOldest:
+		return "dropOldest"
+	}
+	return "unknown"
+}
+
+// ValidateAsyncWriteDropPolicy returns nil when the drop policy is valid,
+// otherwise it returns an error.
+func ValidateAsyncWriteDropPolicy(v AsyncWriteDropPolicy) error {
+	for _, policy := range validAsyncWriteDropPolicies {
+		if policy == v {
+			return nil
+		}
+	}
+	return errAsyncWriteDropPolicyInvalid
+}
+
+// UnmarshalYAML unmarshals an AsyncWriteDropPolicy into a valid type from string.
+func (p *AsyncWriteDropPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*p = AsyncWriteDropNewest
+		return nil
+	}
+	strs := make([]string, 0, len(validAsyncWriteDropPolicies))
+	for _, valid := range validAsyncWriteDropPolicies {
+		if str == valid.String() {
+			*p = valid
+			return nil
+		}
+		strs = append(strs, "'"+valid.String()+"'")
+	}
+	return fmt.Errorf("invalid AsyncWriteDropPolicy '%s' valid types are: %s",
+		str, strings.Join(strs, ", "))
+}
+
+// asyncWrite is a write queued by the async writer, all references are
+// copied so that it is safe to hold beyond the caller's write call.
+type asyncWrite struct {
+	attemptType writeAttemptType
+	namespace   ident.ID
+	id          ident.ID
+	tags        ident.Tags
+	t           time.Time
+	value       float64
+	unit        xtime.Unit
+	annotation  []byte
+
+	requestMetadata string
+}
+
+func newAsyncWrite(
+	attemptType writeAttemptType,
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) (asyncWrite, error) {
+	w := asyncWrite{
+		attemptType: attemptType,
+		namespace:   ident.BytesID(append([]byte(nil), namespace.Bytes()...)),
+		id:          ident.BytesID(append([]byte(nil), id.Bytes()...)),
+		t:           t,
+		value:       value,
+		unit:        unit,
+	}
+	if len(annotation) > 0 {
+		w.annotation = append([]byte(nil), annotation...)
+	}
+	if attemptType == taggedWriteAttemptType {
+		w.tags = ident.NewTags()
+		tagsIter := tags.Duplicate()
+		for tagsIter.Next() {
+			tag := tagsIter.Current()
+			w.tags.Append(ident.Tag{
+				Name:  ident.BytesID(append([]byte(nil), tag.Name.Bytes()...)),
+				Value: ident.BytesID(append([]byte(nil), tag.Value.Bytes()...)),
+			})
+		}
+		err := tagsIter.Err()
+		tagsIter.Close()
+		if err != nil {
+			return asyncWrite{}, err
+		}
+	}
+	return w, nil
+}
+
+type asyncWriteFn func(w asyncWrite) error
+
+type asyncWriterMetrics struct {
+	enqueued      tally.Counter
+	droppedNewest tally.Counter
+	droppedOldest tally.Counter
+	success       tally.Counter
+	errors        tally.Counter
+}
+
+func newAsyncWriterMetrics(scope tally.Scope) asyncWriterMetrics {
+	return asyncWriterMetrics{
+		enqueued: scope.Counter("enqueued"),
+		droppedNewest: scope.Tagged(map[string]string{
+			"policy": AsyncWriteDropNewest.String(),
+		}).Counter("dropped"),
+		droppedOldest: scope.Tagged(map[string]string{
+			"policy": AsyncWriteDropOldest.String(),
+		}).Counter("dropped"),
+		success: scope.Counter("success"),
+		errors:  scope.Counter("errors"),
+	}
+}
+
+// asyncWriter queues writes to a bounded queue that is flushed by background
+// workers, writes are dropped per the drop policy rather than blocking the
+// caller when the queue is full.
+type asyncWriter struct {
+	sync.RWMutex
+
+	queue   chan asyncWrite
+	workers int
+	policy  AsyncWriteDropPolicy
+	writeFn asyncWriteFn
+	closed  bool
+	wg      sync.WaitGroup
+	metrics asyncWriterMetrics
+}
+
+func newAsyncWriter(
+	size int,
+	workers int,
+	policy AsyncWriteDropPolicy,
+	writeFn asyncWriteFn,
+	scope tally.Scope,
+) *asyncWriter {
+	if workers < 1 {
+		workers = 1
+	}
+	return &asyncWriter{
+		queue:   make(chan asyncWrite, size),
+		workers: workers,
+		policy:  policy,
+		writeFn: writeFn,
+		metrics: newAsyncWriterMetrics(scope),
+	}
+}
+
+func (w *asyncWriter) Start() {
+	for i := 0; i < w.workers; i++ {
+		w.wg.Add(1)
+		go w.flushLoop()
+	}
+}
+
+func (w *asyncWriter) flushLoop() {
+	defer w.wg.Done()
+	for write := range w.queue {
+		if err := w.writeFn(write); err != nil {
+			w.metrics.errors.Inc(1)
+			continue
+		}
+		w.metrics.success.Inc(1)
+	}
+}
+
+// Enqueue queues the write without blocking, returning an error only if
+// the writer is closed, writes dropped due to a full queue are only
+// reflected in metrics.
+func (w *asyncWriter) Enqueue(write asyncWrite) error {
+	w.RLock()
+	defer w.RUnlock()
+	if w.closed {
+		return errAsyncWriterClosed
+	}
+
+	select {
+	case w.queue <- write:
+		w.metrics.enqueued.Inc(1)
+		return nil
+	default:
+	}
+
+	if w.policy == AsyncWriteDropOldest {
+		select {
+		case <-w.queue:
+			w.metrics.droppedOldest.Inc(1)
+		default:
+		}
+		select {
+		case w.queue <- write:
+			w.metrics.enqueued.Inc(1)
+			return nil
+		default:
+		}
+	}
+
+	w.metrics.droppedNewest.Inc(1)
+	return nil
+}
+
+// Close stops accepting writes and waits for queued writes to be flushed.
+func (w *asyncWriter) Close() {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.Unlock()
+
+	w.wg.Wait()
+}
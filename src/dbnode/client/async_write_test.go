@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestAsyncWriterFlushesOnClose(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		written []string
+	)
+	writeFn := func(w asyncWrite) error {
+		lock.Lock()
+		written = append(written, w.id.String())
+		lock.Unlock()
+		return nil
+	}
+
+	w := newAsyncWriter(8, 2, AsyncWriteDropNewest, writeFn, tally.NoopScope)
+	w.Start()
+
+	for _, id := range []string{"foo", "bar", "baz"} {
+		write, err := newAsyncWrite(untaggedWriteAttemptType, ident.StringID("ns"),
+			ident.StringID(id), ident.EmptyTagIterator, time.Now(), 1.0, xtime.Second, nil)
+		require.NoError(t, err)
+		require.NoError(t, w.Enqueue(write))
+	}
+
+	w.Close()
+	sort.Strings(written)
+	assert.Equal(t, []string{"bar", "baz", "foo"}, written)
+
+	write, err := newAsyncWrite(untaggedWriteAttemptType, ident.StringID("ns"),
+		ident.StringID("qux"), ident.EmptyTagIterator, time.Now(), 1.0, xtime.Second, nil)
+	require.NoError(t, err)
+	assert.Equal(t, errAsyncWriterClosed, w.Enqueue(write))
+}
+
+func TestAsyncWriterDropPolicy(t *testing.T) {
+	tests := []struct {
+		policy   AsyncWriteDropPolicy
+		expected []string
+	}{
+		{policy: AsyncWriteDropNewest, expected: []string{"foo", "bar"}},
+		{policy: AsyncWriteDropOldest, expected: []string{"bar", "baz"}},
+	}
+
+	for _, test := range tests {
+		var written []string
+		writeFn := func(w asyncWrite) error {
+			written = append(written, w.id.String())
+			return errors.New("an error")
+		}
+
+		// Do not start the writer so that the queue fills up
+		w := newAsyncWriter(2, 1, test.policy, writeFn, tally.NoopScope)
+		for _, id := range []string{"foo", "bar", "baz"} {
+			write, err := newAsyncWrite(untaggedWriteAttemptType, ident.StringID("ns"),
+				ident.StringID(id), ident.EmptyTagIterator, time.Now(), 1.0, xtime.Second, nil)
+			require.NoError(t, err)
+			require.NoError(t, w.Enqueue(write))
+		}
+
+		w.Start()
+		w.Close()
+		assert.Equal(t, test.expected, written, test.policy.String())
+	}
+}
+
+func TestNewAsyncWriteCopiesTags(t *testing.T) {
+	tags := ident.NewTags(ident.StringTag("name", "value"))
+	write, err := newAsyncWrite(taggedWriteAttemptType, ident.StringID("ns"),
+		ident.StringID("foo"), ident.NewTagsIterator(tags), time.Now(), 1.0,
+		xtime.Second, []byte("annotation"))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(write.tags.Values()))
+	assert.Equal(t, "name", write.tags.Values()[0].Name.String())
+	assert.Equal(t, "value", write.tags.Values()[0].Value.String())
+	assert.Equal(t, []byte("annotation"), write.annotation)
+}
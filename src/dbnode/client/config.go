@@ -35,6 +35,10 @@ import (
 	"github.com/m3db/m3x/retry"
 )
 
+const (
+	defaultNamespaceResolutionTimeout = time.Minute
+)
+
 var (
 	errConfigurationMustSupplyConfig = errors.New(
 		"must supply config when no topology initializer parameter supplied")
@@ -79,6 +83,27 @@ type Configuration struct {
 
 	// HashingConfiguration is the configuration for hashing of IDs to shards.
 	HashingConfiguration HashingConfiguration `yaml:"hashing"`
+
+	// AsyncWrite is the configuration for asynchronous best effort writes,
+	// if set writes are queued and return without waiting for completion.
+	AsyncWrite *AsyncWriteConfiguration `yaml:"asyncWrite"`
+
+	// SortTaggedIDsTags returns the tags of the results of index queries
+	// sorted by name with duplicate names removed, a duplicate name with a
+	// different value fails the query.
+	SortTaggedIDsTags bool `yaml:"sortTaggedIDsTags"`
+}
+
+// AsyncWriteConfiguration is the configuration for asynchronous writes.
+type AsyncWriteConfiguration struct {
+	// QueueSize is the max number of writes queued.
+	QueueSize int `yaml:"queueSize" validate:"min=1"`
+
+	// Workers is the number of workers flushing queued writes.
+	Workers int `yaml:"workers" validate:"min=0"`
+
+	// DropPolicy is the policy for dropping writes when the queue is full.
+	DropPolicy AsyncWriteDropPolicy `yaml:"dropPolicy"`
 }
 
 // HashingConfiguration is the configuration for hashing
@@ -154,9 +179,15 @@ func (c Configuration) NewAdminClient(
 	var err error
 	if envCfg.TopologyInitializer == nil {
 		if c.EnvironmentConfig.Service != nil {
+			namespaceResolutionTimeout := c.EnvironmentConfig.NamespaceResolutionTimeout
+			if namespaceResolutionTimeout <= 0 {
+				namespaceResolutionTimeout = defaultNamespaceResolutionTimeout
+			}
+
 			envCfg, err = c.EnvironmentConfig.Configure(environment.ConfigurationParameters{
-				InstrumentOpts: iopts,
-				HashingSeed:    c.HashingConfiguration.Seed,
+				InstrumentOpts:             iopts,
+				HashingSeed:                c.HashingConfiguration.Seed,
+				NamespaceResolutionTimeout: namespaceResolutionTimeout,
 			})
 
 			if err != nil {
@@ -188,7 +219,21 @@ func (c Configuration) NewAdminClient(
 		SetWriteRetrier(c.WriteRetry.NewRetrier(writeRequestScope)).
 		SetFetchRetrier(c.FetchRetry.NewRetrier(fetchRequestScope)).
 		SetChannelOptions(xtchannel.NewDefaultChannelOptions()).
-		SetInstrumentOptions(iopts)
+		SetInstrumentOptions(iopts).
+		SetSortTaggedIDsTags(c.SortTaggedIDsTags)
+
+	if c.AsyncWrite != nil {
+		v = v.SetAsyncWriteQueueSize(c.AsyncWrite.QueueSize).
+			SetAsyncWriteDropPolicy(c.AsyncWrite.DropPolicy)
+		if c.AsyncWrite.Workers > 0 {
+			v = v.SetAsyncWriteWorkers(c.AsyncWrite.Workers)
+		}
+	}
+
+	if envCfg.NamespaceInitializer != nil {
+		// Route series by the shard routing tags of the namespaces.
+		v = v.SetNamespaceInitializer(envCfg.NamespaceInitializer)
+	}
 
 	encodingOpts := params.EncodingOptions
 	if encodingOpts == nil {
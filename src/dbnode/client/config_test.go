@@ -56,6 +56,10 @@ backgroundHealthCheckFailLimit: 4
 backgroundHealthCheckFailThrottleFactor: 0.5
 hashing:
   seed: 42
+asyncWrite:
+  queueSize: 1024
+  workers: 2
+  dropPolicy: dropOldest
 `
 
 	fd, err := ioutil.TempFile("", "config.yaml")
@@ -97,6 +101,11 @@ hashing:
 		HashingConfiguration: HashingConfiguration{
 			Seed: 42,
 		},
+		AsyncWrite: &AsyncWriteConfiguration{
+			QueueSize:  1024,
+			Workers:    2,
+			DropPolicy: AsyncWriteDropOldest,
+		},
 	}
 
 	assert.Equal(t, expected, cfg)
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	nchannel "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node/channel"
 	"github.com/m3db/m3/src/dbnode/topology"
 	xclose "github.com/m3db/m3x/close"
@@ -65,6 +66,8 @@ type connPool struct {
 	sleepHealth        sleepFn
 	sleepHealthRetry   sleepFn
 	status             status
+	apiVersion         int32
+	capabilities       int64
 }
 
 type conn struct {
@@ -85,19 +88,20 @@ func newConnectionPool(host topology.Host, opts Options) connectionPool {
 	seed := int64(murmur3.Sum32([]byte(host.Address())))
 
 	p := &connPool{
-		opts:               opts,
-		host:               host,
-		pool:               make([]conn, 0, opts.MaxConnectionCount()),
-		poolLen:            0,
-		connectRand:        rand.NewSource(seed),
-		healthCheckRand:    rand.NewSource(seed + 1),
-		newConn:            globalNewConn,
-		healthCheckNewConn: healthCheck,
-		healthCheck:        healthCheck,
-		sleepConnect:       time.Sleep,
-		sleepHealth:        time.Sleep,
-		sleepHealthRetry:   time.Sleep,
+		opts:             opts,
+		host:             host,
+		pool:             make([]conn, 0, opts.MaxConnectionCount()),
+		poolLen:          0,
+		connectRand:      rand.NewSource(seed),
+		healthCheckRand:  rand.NewSource(seed + 1),
+		newConn:          globalNewConn,
+		sleepConnect:     time.Sleep,
+		sleepHealth:      time.Sleep,
+		sleepHealthRetry: time.Sleep,
+		capabilities:     int64(tchannelthrift.LegacyCapabilities),
 	}
+	p.healthCheckNewConn = p.healthCheckAndNegotiate
+	p.healthCheck = p.healthCheckAndNegotiate
 
 	return p
 }
@@ -128,6 +132,14 @@ func (p *connPool) ConnectionCount() int {
 	return int(poolLen)
 }
 
+func (p *connPool) APIVersion() int32 {
+	return atomic.LoadInt32(&p.apiVersion)
+}
+
+func (p *connPool) Capabilities() tchannelthrift.Capabilities {
+	return tchannelthrift.Capabilities(atomic.LoadInt64(&p.capabilities))
+}
+
 func (p *connPool) NextClient() (rpc.TChanNode, error) {
 	p.RLock()
 	if p.status != statusOpen {
@@ -306,7 +318,9 @@ func newConn(channelName string, address string, opts Options) (xclose.SimpleClo
 	return channel, client, nil
 }
 
-func healthCheck(client rpc.TChanNode, opts Options) error {
+// healthCheckAndNegotiate health checks a connection and records the API
+// version and capabilities negotiated with the host.
+func (p *connPool) healthCheckAndNegotiate(client rpc.TChanNode, opts Options) error {
 	tctx, _ := thrift.NewContext(opts.HostConnectTimeout())
 	result, err := client.Health(tctx)
 	if err != nil {
@@ -315,6 +329,11 @@ func healthCheck(client rpc.TChanNode, opts Options) error {
 	if !result.Ok {
 		return fmt.Errorf("status not ok: %s", result.Status)
 	}
+
+	apiVersion, capabilities := tchannelthrift.NegotiatedCapabilities(
+		result.ApiVersion, result.Capabilities)
+	atomic.StoreInt32(&p.apiVersion, apiVersion)
+	atomic.StoreInt64(&p.capabilities, int64(capabilities))
 	return nil
 }
 
@@ -0,0 +1,104 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Checkpoint is the progress of a copy, recorded after each window.
+type Checkpoint struct {
+	SourceNamespace      string    `json:"sourceNamespace"`
+	DestinationNamespace string    `json:"destinationNamespace"`
+	Query                string    `json:"query"`
+	Start                time.Time `json:"start"`
+	End                  time.Time `json:"end"`
+	Next                 time.Time `json:"next"`
+	Series               int64     `json:"series"`
+	Datapoints           int64     `json:"datapoints"`
+}
+
+func newCheckpoint(opts Options) Checkpoint {
+	return Checkpoint{
+		SourceNamespace:      opts.SourceNamespace,
+		DestinationNamespace: opts.DestinationNamespace,
+		Query:                queryString(opts),
+		Start:                opts.Start,
+		End:                  opts.End,
+		Next:                 opts.Start,
+	}
+}
+
+// matches returns an error if the checkpoint was recorded for a different copy.
+func (c Checkpoint) matches(other Checkpoint) error {
+	if c.SourceNamespace != other.SourceNamespace ||
+		c.DestinationNamespace != other.DestinationNamespace ||
+		c.Query != other.Query ||
+		!c.Start.Equal(other.Start) ||
+		!c.End.Equal(other.End) {
+		return fmt.Errorf("checkpoint is for a different copy: "+
+			"namespaces %s to %s, query %s, range %s to %s",
+			c.SourceNamespace, c.DestinationNamespace, c.Query,
+			c.Start.Format(time.RFC3339), c.End.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func queryString(opts Options) string {
+	if opts.Query.Query.SearchQuery() == nil {
+		return ""
+	}
+	return opts.Query.String()
+}
+
+// ReadCheckpoint reads a checkpoint, returning false if there is none.
+func ReadCheckpoint(path string) (Checkpoint, bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, false, fmt.Errorf("unable to parse checkpoint %s: %v", path, err)
+	}
+	return cp, true, nil
+}
+
+// WriteCheckpoint writes a checkpoint, replacing any existing checkpoint
+// atomically so an interrupted write does not lose progress.
+func WriteCheckpoint(path string, cp Checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
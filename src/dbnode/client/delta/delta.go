@@ -0,0 +1,263 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package delta copies the series and datapoints matching a query within a
+// time range from one live cluster to another, e.g. for incremental data
+// moves between clusters that can not use fileset level copies.
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
+)
+
+const (
+	// DefaultWindow is the default size of the time ranges copied and
+	// checkpointed at a time.
+	DefaultWindow = time.Hour
+)
+
+var (
+	errNoSourceNamespace      = errors.New("no source namespace set")
+	errNoDestinationNamespace = errors.New("no destination namespace set")
+	errInvalidRange           = errors.New("start must be before end")
+	errNegativeRateLimit      = errors.New("rate limit must not be negative")
+)
+
+// Options are the options for copying data between clusters.
+type Options struct {
+	// SourceNamespace is the namespace to read from.
+	SourceNamespace string
+
+	// DestinationNamespace is the namespace to write to.
+	DestinationNamespace string
+
+	// Query selects the series to copy.
+	Query index.Query
+
+	// Start is the inclusive start of the time range to copy.
+	Start time.Time
+
+	// End is the exclusive end of the time range to copy.
+	End time.Time
+
+	// Window is the size of the time ranges fetched and checkpointed at a
+	// time, defaults to DefaultWindow.
+	Window time.Duration
+
+	// RateLimit is the maximum number of datapoints written per second,
+	// zero disables rate limiting.
+	RateLimit int
+
+	// CheckpointPath is the file progress is recorded to after each window
+	// so an interrupted copy resumes where it left off, progress is not
+	// recorded if empty.
+	CheckpointPath string
+
+	// Logger is the logger used to report progress.
+	Logger xlog.Logger
+}
+
+// Validate validates the options.
+func (o Options) Validate() error {
+	if o.SourceNamespace == "" {
+		return errNoSourceNamespace
+	}
+	if o.DestinationNamespace == "" {
+		return errNoDestinationNamespace
+	}
+	if !o.Start.Before(o.End) {
+		return errInvalidRange
+	}
+	if o.RateLimit < 0 {
+		return errNegativeRateLimit
+	}
+	return nil
+}
+
+// Result is the result of a copy.
+type Result struct {
+	// Series is the number of series copied, series are counted once per
+	// window they have datapoints in.
+	Series int64
+
+	// Datapoints is the number of datapoints copied.
+	Datapoints int64
+
+	// Windows is the number of windows copied, excluding windows skipped
+	// since they were completed by a previous run.
+	Windows int
+}
+
+// Copy copies the series and datapoints matching the query within the time
+// range of the options from the source to the destination session, resuming
+// from the checkpoint if one was recorded by a previous run.
+func Copy(src, dst client.Session, opts Options) (Result, error) {
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.Logger == nil {
+		opts.Logger = xlog.NewLogger(ioutil.Discard)
+	}
+
+	c := &copier{
+		src:      src,
+		dst:      dst,
+		opts:     opts,
+		srcNs:    ident.StringID(opts.SourceNamespace),
+		dstNs:    ident.StringID(opts.DestinationNamespace),
+		throttle: newThrottle(opts.RateLimit),
+	}
+	return c.run()
+}
+
+type copier struct {
+	src      client.Session
+	dst      client.Session
+	opts     Options
+	srcNs    ident.ID
+	dstNs    ident.ID
+	throttle *throttle
+}
+
+func (c *copier) run() (Result, error) {
+	var (
+		opts   = c.opts
+		log    = opts.Logger
+		result Result
+	)
+
+	cp := newCheckpoint(opts)
+	if opts.CheckpointPath != "" {
+		existing, ok, err := ReadCheckpoint(opts.CheckpointPath)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			if err := existing.matches(cp); err != nil {
+				return result, err
+			}
+			cp = existing
+			log.Infof("resuming copy from %s", cp.Next.Format(time.RFC3339))
+		}
+	}
+
+	for cp.Next.Before(opts.End) {
+		start := cp.Next
+		end := start.Add(opts.Window)
+		if end.After(opts.End) {
+			end = opts.End
+		}
+
+		series, datapoints, err := c.copyWindow(start, end)
+		if err != nil {
+			return result, fmt.Errorf("unable to copy %s to %s: %v",
+				start.Format(time.RFC3339), end.Format(time.RFC3339), err)
+		}
+		result.Series += series
+		result.Datapoints += datapoints
+		result.Windows++
+
+		cp.Next = end
+		cp.Series += series
+		cp.Datapoints += datapoints
+		if opts.CheckpointPath != "" {
+			if err := WriteCheckpoint(opts.CheckpointPath, cp); err != nil {
+				return result, err
+			}
+		}
+		log.Infof("copied %d series and %d datapoints from %s to %s",
+			series, datapoints, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	return result, nil
+}
+
+func (c *copier) copyWindow(start, end time.Time) (int64, int64, error) {
+	iters, exhaustive, err := c.src.FetchTagged(c.srcNs, c.opts.Query,
+		index.QueryOptions{StartInclusive: start, EndExclusive: end})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer iters.Close()
+
+	// NB: Copying a partial result would record the window as complete in
+	// the checkpoint so fail instead, a smaller window should be used.
+	if !exhaustive {
+		return 0, 0, fmt.Errorf("results not exhaustive, use a smaller window")
+	}
+
+	var series, datapoints int64
+	for _, iter := range iters.Iters() {
+		n, err := c.copySeries(iter)
+		if err != nil {
+			return series, datapoints, fmt.Errorf("series %s: %v", iter.ID().String(), err)
+		}
+		if n > 0 {
+			series++
+			datapoints += n
+		}
+	}
+	return series, datapoints, nil
+}
+
+func (c *copier) copySeries(iter encoding.SeriesIterator) (int64, error) {
+	tags, err := copyTags(iter.Tags())
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for iter.Next() {
+		dp, unit, annotation := iter.Current()
+		c.throttle.wait()
+		if err := c.dst.WriteTagged(c.dstNs, iter.ID(), ident.NewTagsIterator(tags),
+			dp.Timestamp, dp.Value, unit, annotation); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, iter.Err()
+}
+
+// copyTags copies the tags of a series since the iterator is owned by the
+// series iterator and can only be consumed once.
+func copyTags(iter ident.TagIterator) (ident.Tags, error) {
+	dup := iter.Duplicate()
+	defer dup.Close()
+
+	var tags ident.Tags
+	for dup.Next() {
+		tag := dup.Current()
+		tags.Append(ident.StringTag(tag.Name.String(), tag.Value.String()))
+	}
+	return tags, dup.Err()
+}
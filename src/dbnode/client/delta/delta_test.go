@@ -0,0 +1,157 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package delta
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeriesIters(
+	ctrl *gomock.Controller,
+	id string,
+	datapoints ...ts.Datapoint,
+) encoding.SeriesIterators {
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	iter.EXPECT().ID().Return(ident.StringID(id)).AnyTimes()
+	iter.EXPECT().Tags().Return(ident.NewTagsIterator(
+		ident.NewTags(ident.StringTag("foo", "bar")))).AnyTimes()
+	for _, dp := range datapoints {
+		iter.EXPECT().Next().Return(true)
+		iter.EXPECT().Current().Return(dp, xtime.Second, nil)
+	}
+	iter.EXPECT().Next().Return(false)
+	iter.EXPECT().Err().Return(nil)
+
+	iters := encoding.NewMockSeriesIterators(ctrl)
+	iters.EXPECT().Iters().Return([]encoding.SeriesIterator{iter})
+	iters.EXPECT().Close()
+	return iters
+}
+
+func TestCopyResumesFromCheckpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "delta")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		src   = client.NewMockSession(ctrl)
+		dst   = client.NewMockSession(ctrl)
+		start = time.Unix(1500000000, 0)
+		opts  = Options{
+			SourceNamespace:      "src",
+			DestinationNamespace: "dst",
+			Query:                index.Query{idx.NewTermQuery([]byte("foo"), []byte("bar"))},
+			Start:                start,
+			End:                  start.Add(3 * time.Hour),
+			Window:               2 * time.Hour,
+			CheckpointPath:       filepath.Join(dir, "checkpoint.json"),
+		}
+		dp = ts.Datapoint{Timestamp: start.Add(2*time.Hour + time.Minute), Value: 42}
+	)
+
+	// Record a previous run that copied the first window.
+	cp := newCheckpoint(opts)
+	cp.Next = start.Add(2 * time.Hour)
+	require.NoError(t, WriteCheckpoint(opts.CheckpointPath, cp))
+
+	src.EXPECT().
+		FetchTagged(ident.NewIDMatcher("src"), opts.Query, gomock.Any()).
+		Do(func(_ ident.ID, _ index.Query, queryOpts index.QueryOptions) {
+			assert.True(t, queryOpts.StartInclusive.Equal(start.Add(2*time.Hour)))
+			assert.True(t, queryOpts.EndExclusive.Equal(opts.End))
+		}).
+		Return(newTestSeriesIters(ctrl, "a", dp), true, nil)
+	dst.EXPECT().
+		WriteTagged(ident.NewIDMatcher("dst"), ident.NewIDMatcher("a"), gomock.Any(),
+			dp.Timestamp, dp.Value, xtime.Second, gomock.Any()).
+		Return(nil)
+
+	result, err := Copy(src, dst, opts)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Series: 1, Datapoints: 1, Windows: 1}, result)
+
+	cp, ok, err := ReadCheckpoint(opts.CheckpointPath)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, cp.Next.Equal(opts.End))
+	assert.Equal(t, int64(1), cp.Datapoints)
+
+	// A checkpoint of a different copy is not resumed from.
+	opts.DestinationNamespace = "other"
+	_, err = Copy(src, dst, opts)
+	require.Error(t, err)
+}
+
+func TestCopyNotExhaustive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		src   = client.NewMockSession(ctrl)
+		dst   = client.NewMockSession(ctrl)
+		start = time.Unix(1500000000, 0)
+		iters = encoding.NewMockSeriesIterators(ctrl)
+	)
+	iters.EXPECT().Close()
+	src.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(iters, false, nil)
+
+	_, err := Copy(src, dst, Options{
+		SourceNamespace:      "src",
+		DestinationNamespace: "dst",
+		Start:                start,
+		End:                  start.Add(time.Hour),
+	})
+	require.Error(t, err)
+}
+
+func TestThrottle(t *testing.T) {
+	var (
+		now   = time.Unix(0, 0)
+		slept []time.Duration
+		th    = newThrottle(10)
+	)
+	th.nowFn = func() time.Time { return now }
+	th.sleepFn = func(d time.Duration) { slept = append(slept, d) }
+
+	for i := 0; i < 3; i++ {
+		th.wait()
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, slept)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package delta
+
+import (
+	"time"
+)
+
+type nowFn func() time.Time
+
+type sleepFn func(time.Duration)
+
+// throttle limits the rate of writes by sleeping before each write until the
+// number of writes so far is within the rate limit since the first write.
+type throttle struct {
+	limit   int
+	start   time.Time
+	writes  int64
+	nowFn   nowFn
+	sleepFn sleepFn
+}
+
+func newThrottle(limit int) *throttle {
+	return &throttle{
+		limit:   limit,
+		nowFn:   time.Now,
+		sleepFn: time.Sleep,
+	}
+}
+
+func (t *throttle) wait() {
+	if t.limit <= 0 {
+		return
+	}
+	if t.writes == 0 {
+		t.start = t.nowFn()
+	}
+
+	target := time.Duration(float64(t.writes) / float64(t.limit) * float64(time.Second))
+	if elapsed := t.nowFn().Sub(t.start); elapsed < target {
+		t.sleepFn(target - elapsed)
+	}
+	t.writes++
+}
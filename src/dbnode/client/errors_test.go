@@ -54,3 +54,13 @@ func TestConsistencyResultError(t *testing.T) {
 	assert.Equal(t, 1, NumSuccess(err))
 	assert.Equal(t, 2, NumError(err))
 }
+
+func TestIsResourceExhaustedError(t *testing.T) {
+	err := xerrors.NewRenamedError(&rpc.Error{
+		Type: rpc.ErrorType_RESOURCE_EXHAUSTED,
+	}, fmt.Errorf("renamed"))
+
+	assert.True(t, IsResourceExhaustedError(err))
+	assert.False(t, IsBadRequestError(err))
+	assert.False(t, IsInternalServerError(err))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fake
+
+import (
+	"github.com/m3db/m3/src/dbnode/client"
+)
+
+type fakeClient struct {
+	opts    client.Options
+	session client.Session
+}
+
+// NewClient returns a client whose sessions are all backed by the same
+// in-memory session, so that data written by one session can be read back
+// by any other.
+func NewClient() client.Client {
+	return &fakeClient{
+		opts:    client.NewOptions(),
+		session: NewSession(),
+	}
+}
+
+func (c *fakeClient) Options() client.Options {
+	return c.opts
+}
+
+func (c *fakeClient) NewSession() (client.Session, error) {
+	return c.session, nil
+}
+
+func (c *fakeClient) DefaultSession() (client.Session, error) {
+	return c.session, nil
+}
+
+func (c *fakeClient) DefaultSessionActive() bool {
+	return true
+}
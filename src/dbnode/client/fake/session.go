@@ -0,0 +1,510 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fake provides an in-memory implementation of the client session
+// for unit testing code that uses the client without a running cluster.
+package fake
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+const (
+	// defaultNumShards is the number of shards used to compute shard IDs.
+	defaultNumShards = 64
+)
+
+var (
+	errSessionClosed               = errors.New("session is closed")
+	errIteratorPoolsNotSupported   = errors.New("iterator pools are not supported by the fake session")
+	errSeriesWrittenWithDifferTags = errors.New("series was previously written with different tags")
+)
+
+type datapoint struct {
+	ts.Datapoint
+	unit       xtime.Unit
+	annotation []byte
+}
+
+type series struct {
+	id         ident.ID
+	tags       ident.Tags
+	tagged     bool
+	datapoints []datapoint
+}
+
+func (s *series) write(dp datapoint) {
+	idx := sort.Search(len(s.datapoints), func(i int) bool {
+		return !s.datapoints[i].Timestamp.Before(dp.Timestamp)
+	})
+	if idx < len(s.datapoints) && s.datapoints[idx].Timestamp.Equal(dp.Timestamp) {
+		// Last write wins for the same timestamp
+		s.datapoints[idx] = dp
+		return
+	}
+	s.datapoints = append(s.datapoints, datapoint{})
+	copy(s.datapoints[idx+1:], s.datapoints[idx:])
+	s.datapoints[idx] = dp
+}
+
+func (s *series) hasDataInRange(start, end time.Time) bool {
+	for _, dp := range s.datapoints {
+		if !dp.Timestamp.Before(start) && dp.Timestamp.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+type session struct {
+	sync.RWMutex
+
+	closed  bool
+	shardFn sharding.HashFn
+	data    map[string]map[string]*series
+}
+
+// NewSession returns a new in-memory session. Writes are stored in memory
+// and queries are evaluated naively against every series written with tags.
+func NewSession() client.Session {
+	return &session{
+		shardFn: sharding.DefaultHashFn(defaultNumShards),
+		data:    make(map[string]map[string]*series),
+	}
+}
+
+func (s *session) Write(
+	namespace, id ident.ID,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	return s.write(namespace, id, nil, t, value, unit, annotation)
+}
+
+func (s *session) WriteTagged(
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	copiedTags, err := copyTags(tags)
+	if err != nil {
+		return err
+	}
+	return s.write(namespace, id, &copiedTags, t, value, unit, annotation)
+}
+
+func (s *session) write(
+	namespace, id ident.ID,
+	tags *ident.Tags,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return errSessionClosed
+	}
+
+	nsData, ok := s.data[namespace.String()]
+	if !ok {
+		nsData = make(map[string]*series)
+		s.data[namespace.String()] = nsData
+	}
+
+	entry, ok := nsData[id.String()]
+	if !ok {
+		entry = &series{id: ident.StringID(id.String())}
+		nsData[id.String()] = entry
+	}
+	if tags != nil {
+		if entry.tagged && !tagsEqual(entry.tags, *tags) {
+			return errSeriesWrittenWithDifferTags
+		}
+		entry.tags = *tags
+		entry.tagged = true
+	}
+
+	var copiedAnnotation []byte
+	if len(annotation) > 0 {
+		copiedAnnotation = append([]byte(nil), annotation...)
+	}
+	entry.write(datapoint{
+		Datapoint:  ts.Datapoint{Timestamp: t, Value: value},
+		unit:       unit,
+		annotation: copiedAnnotation,
+	})
+	return nil
+}
+
+func (s *session) Fetch(
+	namespace, id ident.ID,
+	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterator, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil, errSessionClosed
+	}
+	return s.seriesIteratorWithRLock(namespace, id, startInclusive, endExclusive)
+}
+
+func (s *session) FetchIDs(
+	namespace ident.ID,
+	ids ident.Iterator,
+	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterators, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil, errSessionClosed
+	}
+
+	iters := make([]encoding.SeriesIterator, 0, ids.Remaining())
+	for ids.Next() {
+		iter, err := s.seriesIteratorWithRLock(namespace, ids.Current(),
+			startInclusive, endExclusive)
+		if err != nil {
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+	if err := ids.Err(); err != nil {
+		return nil, err
+	}
+	return encoding.NewSeriesIterators(iters, nil), nil
+}
+
+func (s *session) FetchTagged(
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) (encoding.SeriesIterators, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil, false, errSessionClosed
+	}
+
+	matches, exhaustive, err := s.queryWithRLock(namespace, q, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	iters := make([]encoding.SeriesIterator, 0, len(matches))
+	for _, match := range matches {
+		iter, err := s.seriesIteratorWithRLock(namespace, match.id,
+			opts.StartInclusive, opts.EndExclusive)
+		if err != nil {
+			return nil, false, err
+		}
+		iters = append(iters, iter)
+	}
+	return encoding.NewSeriesIterators(iters, nil), exhaustive, nil
+}
+
+func (s *session) FetchTaggedWithMetadata(
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) (encoding.SeriesIterators, client.FetchTaggedResultMetadata, error) {
+	iters, exhaustive, err := s.FetchTagged(namespace, q, opts)
+	return iters, client.FetchTaggedResultMetadata{Exhaustive: exhaustive}, err
+}
+
+func (s *session) FetchTaggedIDs(
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) (client.TaggedIDsIterator, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil, false, errSessionClosed
+	}
+
+	matches, exhaustive, err := s.queryWithRLock(namespace, q, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return newTaggedIDsIterator(ident.StringID(namespace.String()), matches),
+		exhaustive, nil
+}
+
+func (s *session) FetchTaggedCount(
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) (int, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return 0, false, errSessionClosed
+	}
+
+	matches, exhaustive, err := s.queryWithRLock(namespace, q, opts)
+	if err != nil {
+		return 0, false, err
+	}
+	return len(matches), exhaustive, nil
+}
+
+func (s *session) ShardID(namespace, id ident.ID) (uint32, error) {
+	return s.shardFn(id), nil
+}
+
+func (s *session) IteratorPools() (encoding.IteratorPools, error) {
+	return nil, errIteratorPoolsNotSupported
+}
+
+func (s *session) Backpressure() (float64, error) {
+	// NB: the fake session applies operations immediately.
+	return 0, nil
+}
+
+func (s *session) WithRequestMetadata(requestMetadata string) client.Session {
+	// NB: the fake session has no nodes to send request metadata to.
+	return s
+}
+
+func (s *session) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return errSessionClosed
+	}
+	s.closed = true
+	s.data = nil
+	return nil
+}
+
+func (s *session) seriesIteratorWithRLock(
+	namespace, id ident.ID,
+	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterator, error) {
+	var (
+		replicas []encoding.MultiReaderIterator
+		tags     = ident.EmptyTagIterator
+	)
+	if entry, ok := s.data[namespace.String()][id.String()]; ok {
+		if entry.tagged {
+			tags = ident.NewTagsIterator(entry.tags)
+		}
+		replica, err := newReplica(entry.datapoints, startInclusive, endExclusive)
+		if err != nil {
+			return nil, err
+		}
+		if replica != nil {
+			replicas = append(replicas, replica)
+		}
+	}
+	return encoding.NewSeriesIterator(ident.StringID(id.String()),
+		ident.StringID(namespace.String()), tags, startInclusive, endExclusive,
+		replicas, nil), nil
+}
+
+func (s *session) queryWithRLock(
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) ([]*series, bool, error) {
+	seg, err := mem.NewSegment(postings.ID(0), mem.NewOptions())
+	if err != nil {
+		return nil, false, err
+	}
+	defer seg.Close()
+
+	// Insert in ID order so that results, and which results are returned
+	// when limited, are deterministic.
+	nsData := s.data[namespace.String()]
+	ids := make([]string, 0, len(nsData))
+	for id := range nsData {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		entry := nsData[id]
+		if !entry.tagged ||
+			!entry.hasDataInRange(opts.StartInclusive, opts.EndExclusive) {
+			continue
+		}
+		d, err := convert.FromMetric(entry.id, entry.tags)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, err := seg.Insert(d); err != nil {
+			return nil, false, err
+		}
+	}
+
+	reader, err := seg.Reader()
+	if err != nil {
+		return nil, false, err
+	}
+	exec := executor.NewExecutor([]m3ninxindex.Reader{reader})
+	defer exec.Close()
+
+	iter, err := exec.Execute(q.Query.SearchQuery())
+	if err != nil {
+		return nil, false, err
+	}
+	defer iter.Close()
+
+	var matches []*series
+	for iter.Next() {
+		if opts.Limit > 0 && len(matches) >= opts.Limit {
+			return matches, false, nil
+		}
+		matches = append(matches, nsData[string(iter.Current().ID)])
+	}
+	if err := iter.Err(); err != nil {
+		return nil, false, err
+	}
+	return matches, true, nil
+}
+
+func newReplica(
+	datapoints []datapoint,
+	startInclusive, endExclusive time.Time,
+) (encoding.MultiReaderIterator, error) {
+	var (
+		encodingOpts = encoding.NewOptions()
+		encoder      encoding.Encoder
+	)
+	for _, dp := range datapoints {
+		if dp.Timestamp.Before(startInclusive) || !dp.Timestamp.Before(endExclusive) {
+			continue
+		}
+		if encoder == nil {
+			encoder = m3tsz.NewEncoder(dp.Timestamp, nil,
+				m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+		}
+		if err := encoder.Encode(dp.Datapoint, dp.unit, dp.annotation); err != nil {
+			return nil, err
+		}
+	}
+	if encoder == nil {
+		return nil, nil
+	}
+
+	reader := xio.BlockReader{
+		SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+		Start:         startInclusive,
+		BlockSize:     endExclusive.Sub(startInclusive),
+	}
+	iter := encoding.NewMultiReaderIterator(func(r io.Reader) encoding.ReaderIterator {
+		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+	}, nil)
+	iter.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(
+		[][]xio.BlockReader{{reader}}))
+	return iter, nil
+}
+
+func copyTags(tags ident.TagIterator) (ident.Tags, error) {
+	var (
+		dup    = tags.Duplicate()
+		copied = ident.NewTags()
+	)
+	defer dup.Close()
+	for dup.Next() {
+		tag := dup.Current()
+		copied.Append(ident.StringTag(tag.Name.String(), tag.Value.String()))
+	}
+	return copied, dup.Err()
+}
+
+func tagsEqual(a, b ident.Tags) bool {
+	aValues, bValues := a.Values(), b.Values()
+	if len(aValues) != len(bValues) {
+		return false
+	}
+	for i := range aValues {
+		if !aValues[i].Name.Equal(bValues[i].Name) ||
+			!aValues[i].Value.Equal(bValues[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+type taggedIDsIterator struct {
+	namespace ident.ID
+	matches   []*series
+	idx       int
+	tags      ident.TagIterator
+}
+
+func newTaggedIDsIterator(
+	namespace ident.ID,
+	matches []*series,
+) client.TaggedIDsIterator {
+	return &taggedIDsIterator{
+		namespace: namespace,
+		matches:   matches,
+		idx:       -1,
+	}
+}
+
+func (i *taggedIDsIterator) Next() bool {
+	i.idx++
+	if i.idx >= len(i.matches) {
+		return false
+	}
+	i.tags = ident.NewTagsIterator(i.matches[i.idx].tags)
+	return true
+}
+
+func (i *taggedIDsIterator) Current() (ident.ID, ident.ID, ident.TagIterator) {
+	return i.namespace, i.matches[i.idx].id, i.tags
+}
+
+func (i *taggedIDsIterator) Err() error {
+	return nil
+}
+
+func (i *taggedIDsIterator) Finalize() {
+	i.matches = nil
+	i.tags = nil
+}
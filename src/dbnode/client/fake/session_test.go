@@ -0,0 +1,111 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionWriteFetch(t *testing.T) {
+	var (
+		s     = NewSession()
+		ns    = ident.StringID("ns")
+		id    = ident.StringID("foo")
+		start = time.Now().Truncate(time.Hour)
+	)
+	defer s.Close()
+
+	require.NoError(t, s.Write(ns, id, start.Add(time.Second), 2, xtime.Second, nil))
+	require.NoError(t, s.Write(ns, id, start, 1, xtime.Second, nil))
+	// Overwrites the previous value at the same timestamp
+	require.NoError(t, s.Write(ns, id, start.Add(time.Second), 3, xtime.Second, nil))
+
+	iter, err := s.Fetch(ns, id, start, start.Add(time.Minute))
+	require.NoError(t, err)
+	defer iter.Close()
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, []float64{1, 3}, values)
+
+	iter, err = s.Fetch(ns, ident.StringID("unknown"), start, start.Add(time.Minute))
+	require.NoError(t, err)
+	defer iter.Close()
+	require.False(t, iter.Next())
+}
+
+func TestSessionFetchTagged(t *testing.T) {
+	var (
+		s     = NewSession()
+		ns    = ident.StringID("ns")
+		start = time.Now().Truncate(time.Hour)
+		opts  = index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   start.Add(time.Hour),
+		}
+	)
+	defer s.Close()
+
+	for _, id := range []string{"foo", "bar"} {
+		tags := ident.NewTagsIterator(ident.NewTags(
+			ident.StringTag("name", id), ident.StringTag("env", "prod")))
+		require.NoError(t, s.WriteTagged(ns, ident.StringID(id), tags,
+			start, 1, xtime.Second, nil))
+	}
+
+	q := index.Query{Query: idx.NewTermQuery([]byte("env"), []byte("prod"))}
+	iters, exhaustive, err := s.FetchTagged(ns, q, opts)
+	require.NoError(t, err)
+	defer iters.Close()
+	require.True(t, exhaustive)
+	require.Equal(t, 2, iters.Len())
+	require.Equal(t, "bar", iters.Iters()[0].ID().String())
+	require.Equal(t, "foo", iters.Iters()[1].ID().String())
+
+	q = index.Query{Query: idx.NewTermQuery([]byte("name"), []byte("foo"))}
+	idsIter, exhaustive, err := s.FetchTaggedIDs(ns, q, opts)
+	require.NoError(t, err)
+	defer idsIter.Finalize()
+	require.True(t, exhaustive)
+	require.True(t, idsIter.Next())
+	nsID, id, _ := idsIter.Current()
+	require.Equal(t, "ns", nsID.String())
+	require.Equal(t, "foo", id.String())
+	require.False(t, idsIter.Next())
+
+	opts.Limit = 1
+	_, exhaustive, err = s.FetchTaggedIDs(ns,
+		index.Query{Query: idx.NewTermQuery([]byte("env"), []byte("prod"))}, opts)
+	require.NoError(t, err)
+	require.False(t, exhaustive)
+}
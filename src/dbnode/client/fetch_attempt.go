@@ -47,6 +47,8 @@ type fetchAttemptArgs struct {
 	ids       ident.Iterator
 	start     time.Time
 	end       time.Time
+
+	requestMetadata string
 }
 
 func (f *fetchAttempt) reset() {
@@ -56,7 +58,7 @@ func (f *fetchAttempt) reset() {
 
 func (f *fetchAttempt) perform() error {
 	result, err := f.session.fetchIDsAttempt(f.args.namespace,
-		f.args.ids, f.args.start, f.args.end)
+		f.args.ids, f.args.start, f.args.end, f.args.requestMetadata)
 	f.result = result
 
 	if IsBadRequestError(err) {
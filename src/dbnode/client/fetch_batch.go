@@ -31,6 +31,10 @@ type fetchBatchOp struct {
 	request       rpc.FetchBatchRawRequest
 	completionFns []completionFn
 	finalizer     fetchBatchOpFinalizer
+
+	// requestMetadata is sent with the request for nodes to include in
+	// their logs and errors.
+	requestMetadata string
 }
 
 func (f *fetchBatchOp) reset() {
@@ -46,6 +50,7 @@ func (f *fetchBatchOp) reset() {
 		f.completionFns[i] = nil
 	}
 	f.completionFns = f.completionFns[:0]
+	f.requestMetadata = ""
 	f.DecWrites()
 }
 
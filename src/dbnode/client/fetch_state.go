@@ -28,6 +28,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3x/ident"
@@ -89,12 +90,16 @@ func (f *fetchState) Reset(
 	startTime time.Time,
 	endTime time.Time,
 	op *fetchTaggedOp, topoMap topology.Map,
+	shardSet sharding.ShardSet,
 	majority int,
 	consistencyLevel topology.ReadConsistencyLevel,
+	sortTags bool,
 ) {
 	op.incRef() // take a reference to the provided op
 	f.op = op
 	f.tagResultAccumulator.Reset(startTime, endTime, topoMap, majority, consistencyLevel)
+	f.tagResultAccumulator.SetShardSet(shardSet)
+	f.tagResultAccumulator.SetSortTags(sortTags)
 }
 
 func (f *fetchState) completionFn(
@@ -149,20 +154,46 @@ func (f *fetchState) asTaggedIDsIterator(pools fetchTaggedPools) (TaggedIDsItera
 	return f.tagResultAccumulator.AsTaggedIDsIterator(limit, pools)
 }
 
-func (f *fetchState) asEncodingSeriesIterators(pools fetchTaggedPools) (encoding.SeriesIterators, bool, error) {
+func (f *fetchState) asEncodingSeriesIterators(
+	pools fetchTaggedPools,
+) (encoding.SeriesIterators, FetchTaggedResultMetadata, error) {
 	f.Lock()
 	defer f.Unlock()
 
 	if !f.done {
-		return nil, false, errFetchStateStillProcessing
+		return nil, FetchTaggedResultMetadata{}, errFetchStateStillProcessing
 	}
 
 	if err := f.err; err != nil {
-		return nil, false, err
+		return nil, FetchTaggedResultMetadata{}, err
+	}
+
+	limit := f.op.requestLimit(maxInt)
+	iters, exhaustive, err := f.tagResultAccumulator.AsEncodingSeriesIterators(limit, pools)
+	if err != nil {
+		return nil, FetchTaggedResultMetadata{}, err
+	}
+	return iters, FetchTaggedResultMetadata{
+		Exhaustive:          exhaustive,
+		Warnings:            f.tagResultAccumulator.Warnings(),
+		PartialAvailability: f.tagResultAccumulator.PartialAvailability(),
+	}, nil
+}
+
+func (f *fetchState) asCount() (int, bool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.done {
+		return 0, false, errFetchStateStillProcessing
+	}
+
+	if err := f.err; err != nil {
+		return 0, false, err
 	}
 
 	limit := f.op.requestLimit(maxInt)
-	return f.tagResultAccumulator.AsEncodingSeriesIterators(limit, pools)
+	return f.tagResultAccumulator.AsCount(limit)
 }
 
 // NB(prateek): this is backed by the sessionPools struct, but we're restricting it to a narrow
@@ -173,6 +204,7 @@ func (f *fetchState) asEncodingSeriesIterators(pools fetchTaggedPools) (encoding
 type fetchTaggedPools interface {
 	MultiReaderIteratorArray() encoding.MultiReaderIteratorArrayPool
 	MultiReaderIterator() encoding.MultiReaderIteratorPool
+	CodecMultiReaderIterators() encoding.CodecMultiReaderIteratorPools
 	MutableSeriesIterators() encoding.MutableSeriesIteratorsPool
 	SeriesIterator() encoding.SeriesIteratorPool
 	CheckedBytesWrapper() xpool.CheckedBytesWrapperPool
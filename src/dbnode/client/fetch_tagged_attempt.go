@@ -36,12 +36,15 @@ type fetchTaggedAttempt struct {
 	args    fetchTaggedAttemptArgs
 	session *session
 
-	idsAttemptFn         xretry.Fn
-	dataAttemptFn        xretry.Fn
-	idsResultIter        TaggedIDsIterator
-	dataResultIters      encoding.SeriesIterators
-	idsResultExhaustive  bool
-	dataResultExhaustive bool
+	idsAttemptFn          xretry.Fn
+	dataAttemptFn         xretry.Fn
+	countAttemptFn        xretry.Fn
+	idsResultIter         TaggedIDsIterator
+	dataResultIters       encoding.SeriesIterators
+	countResult           int
+	dataResultMetadata    FetchTaggedResultMetadata
+	idsResultExhaustive   bool
+	countResultExhaustive bool
 }
 
 type fetchTaggedAttemptArgs struct {
@@ -56,7 +59,9 @@ func (f *fetchTaggedAttempt) reset() {
 	f.idsResultIter = nil
 	f.idsResultExhaustive = false
 	f.dataResultIters = nil
-	f.dataResultExhaustive = false
+	f.dataResultMetadata = FetchTaggedResultMetadata{}
+	f.countResult = 0
+	f.countResultExhaustive = false
 }
 
 func (f *fetchTaggedAttempt) performIDsAttempt() error {
@@ -68,7 +73,14 @@ func (f *fetchTaggedAttempt) performIDsAttempt() error {
 
 func (f *fetchTaggedAttempt) performDataAttempt() error {
 	var err error
-	f.dataResultIters, f.dataResultExhaustive, err = f.session.fetchTaggedAttempt(
+	f.dataResultIters, f.dataResultMetadata, err = f.session.fetchTaggedAttempt(
+		f.args.ns, f.args.query, f.args.opts)
+	return err
+}
+
+func (f *fetchTaggedAttempt) performCountAttempt() error {
+	var err error
+	f.countResult, f.countResultExhaustive, err = f.session.fetchTaggedCountAttempt(
 		f.args.ns, f.args.query, f.args.opts)
 	return err
 }
@@ -99,6 +111,7 @@ func (p *fetchTaggedAttemptPoolImpl) Init() {
 		// and function method pointer over and over again
 		f.idsAttemptFn = f.performIDsAttempt
 		f.dataAttemptFn = f.performDataAttempt
+		f.countAttemptFn = f.performCountAttempt
 		f.reset()
 		return f
 	})
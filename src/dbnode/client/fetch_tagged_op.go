@@ -34,6 +34,10 @@ type fetchTaggedOp struct {
 	request      rpc.FetchTaggedRequest
 	completionFn completionFn
 
+	// requestMetadata is sent with the request for nodes to include in
+	// their logs and errors.
+	requestMetadata string
+
 	pool fetchTaggedOpPool
 }
 
@@ -55,6 +59,7 @@ func (f *fetchTaggedOp) requestLimit(defaultValue int) int {
 func (f *fetchTaggedOp) close() {
 	f.completionFn = nil
 	f.request = fetchTaggedOpRequestZeroed
+	f.requestMetadata = ""
 	// return to pool
 	if f.pool == nil {
 		return
@@ -28,14 +28,22 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3cluster/shard"
 	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
 )
 
 type fetchTaggedResultAccumulatorOpts struct {
 	host     topology.Host
 	response *rpc.FetchTaggedResult_
+	// responseHeaders are the response headers of the call, they list
+	// the query limits exceeded by the host, if any, and mark results
+	// served from shards still bootstrapping.
+	responseHeaders map[string]string
 }
 
 func newFetchTaggedResultAccumulator() fetchTaggedResultAccumulator {
@@ -55,12 +63,20 @@ type fetchTaggedResultAccumulator struct {
 	errors     xerrors.Errors
 	responses  fetchTaggedIDResults
 	exhaustive bool
+	warnings   []index.QueryLimitExceeded
+	partial    bool
+	// sortTags returns the tags of each result sorted by name with
+	// duplicate names removed.
+	sortTags bool
 
 	startTime        time.Time
 	endTime          time.Time
 	majority         int
 	consistencyLevel topology.ReadConsistencyLevel
 	topoMap          topology.Map
+	// shardSet looks up the shard of a series, it routes series the same
+	// way as the namespace, e.g. by its shard routing tag.
+	shardSet sharding.ShardSet
 }
 
 type fetchTaggedShardConsistencyResult struct {
@@ -68,6 +84,9 @@ type fetchTaggedShardConsistencyResult struct {
 	success  int8
 	errors   int8
 	done     bool
+	// count is the max number of matching series reported for the shard
+	// by any replica, only used by count only requests.
+	count int64
 }
 
 func (rs fetchTaggedShardConsistencyResult) pending() int32 {
@@ -107,6 +126,15 @@ func (accum *fetchTaggedResultAccumulator) Add(
 		for _, elem := range response.Elements {
 			accum.responses = append(accum.responses, elem)
 		}
+		accum.addWarnings(opts.responseHeaders)
+		if tchannelthrift.PartialAvailability(opts.responseHeaders) {
+			accum.partial = true
+		}
+	}
+
+	var shardCounts map[int32]int64
+	if resultErr == nil {
+		shardCounts = accum.shardCounts(response)
 	}
 
 	// FOLLOWUP(prateek): once we transmit the shards successfully satisfied by a response, the
@@ -127,6 +155,9 @@ func (accum *fetchTaggedResultAccumulator) Add(
 			shardResult.errors++
 		} else if resultErr == nil {
 			shardResult.success++
+			if count := shardCounts[int32(shardID)]; count > shardResult.count {
+				shardResult.count = count
+			}
 		} else {
 			shardResult.errors++
 		}
@@ -166,6 +197,56 @@ func (accum *fetchTaggedResultAccumulator) Add(
 	return doneAccumulating, nil
 }
 
+// shardCounts returns the per shard counts of a response, computing them from
+// the returned elements if the host does not support count only requests.
+func (accum *fetchTaggedResultAccumulator) shardCounts(
+	response *rpc.FetchTaggedResult_,
+) map[int32]int64 {
+	if response.ShardCounts != nil || len(response.Elements) == 0 {
+		return response.ShardCounts
+	}
+	counts := make(map[int32]int64)
+	for _, elem := range response.Elements {
+		counts[int32(accum.shardSet.Lookup(ident.BytesID(elem.ID)))]++
+	}
+	return counts
+}
+
+// addWarnings adds the query limits exceeded by a host, a limit exceeded by
+// more than one host is only added once.
+func (accum *fetchTaggedResultAccumulator) addWarnings(headers map[string]string) {
+	exceeded, err := tchannelthrift.QueryLimitsExceeded(headers)
+	if err != nil {
+		// NB: the results of the host are known to be partial even if the
+		// limits it exceeded cannot be parsed.
+		accum.exhaustive = false
+		return
+	}
+	for _, e := range exceeded {
+		found := false
+		for _, w := range accum.warnings {
+			if w == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			accum.warnings = append(accum.warnings, e)
+		}
+	}
+}
+
+// PartialAvailability returns whether any host served results from shards
+// that are still bootstrapping.
+func (accum *fetchTaggedResultAccumulator) PartialAvailability() bool {
+	return accum.partial
+}
+
+// Warnings returns the query limits exceeded by any host.
+func (accum *fetchTaggedResultAccumulator) Warnings() []index.QueryLimitExceeded {
+	return accum.warnings
+}
+
 func (accum *fetchTaggedResultAccumulator) Clear() {
 	for i := range accum.responses {
 		accum.responses[i] = nil
@@ -180,7 +261,11 @@ func (accum *fetchTaggedResultAccumulator) Clear() {
 	accum.majority, accum.numHostsPending, accum.numShardsPending = 0, 0, 0
 	accum.startTime, accum.endTime = time.Time{}, time.Time{}
 	accum.topoMap = nil
+	accum.shardSet = nil
 	accum.exhaustive = true
+	accum.warnings = nil
+	accum.partial = false
+	accum.sortTags = false
 }
 
 func (accum *fetchTaggedResultAccumulator) Reset(
@@ -194,6 +279,7 @@ func (accum *fetchTaggedResultAccumulator) Reset(
 	accum.startTime = startTime
 	accum.endTime = endTime
 	accum.topoMap = topoMap
+	accum.shardSet = topoMap.ShardSet()
 	accum.majority = majority
 	accum.consistencyLevel = consistencyLevel
 	accum.numHostsPending = int32(topoMap.HostsLen())
@@ -212,17 +298,34 @@ func (accum *fetchTaggedResultAccumulator) Reset(
 	}
 }
 
+// SetSortTags sets whether the tags of each result are returned sorted by
+// name with duplicate names removed.
+func (accum *fetchTaggedResultAccumulator) SetSortTags(value bool) {
+	accum.sortTags = value
+}
+
+// SetShardSet sets the shard set used to look up the shard of a series,
+// it must have the same shards as the topology map the accumulator was
+// reset with.
+func (accum *fetchTaggedResultAccumulator) SetShardSet(shardSet sharding.ShardSet) {
+	accum.shardSet = shardSet
+}
+
 func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	pools fetchTaggedPools,
 	elems fetchTaggedIDResults,
-) encoding.SeriesIterator {
+) (encoding.SeriesIterator, error) {
 	numElems := len(elems)
 	iters := pools.MultiReaderIteratorArray().Get(numElems)[:numElems]
 	for idx, elem := range elems {
-		slicesIter := pools.ReaderSliceOfSlicesIterator().Get()
-		slicesIter.Reset(elem.Segments)
-		multiIter := pools.MultiReaderIterator().Get()
-		multiIter.ResetSliceOfSlices(slicesIter)
+		multiIter, err := newMultiReaderIteratorFromSegments(pools, elem.Segments)
+		if err != nil {
+			for _, iter := range iters[:idx] {
+				iter.Close()
+			}
+			pools.MultiReaderIteratorArray().Put(iters)
+			return nil, err
+		}
 		iters[idx] = multiIter
 	}
 
@@ -234,14 +337,26 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	encodedTags := pools.CheckedBytesWrapper().Get(elem.EncodedTags)
 	decoder := pools.TagDecoder().Get()
 	decoder.Reset(encodedTags)
+	var tags ident.TagIterator = decoder
+	if accum.sortTags {
+		sorted, err := newSortedTagIterator(decoder)
+		if err != nil {
+			for _, iter := range iters {
+				iter.Close()
+			}
+			pools.MultiReaderIteratorArray().Put(iters)
+			return nil, err
+		}
+		tags = sorted
+	}
 
 	tsID := pools.CheckedBytesWrapper().Get(elem.ID)
 	nsID := pools.CheckedBytesWrapper().Get(elem.NameSpace)
 	seriesIter := pools.SeriesIterator().Get()
 	seriesIter.Reset(pools.ID().BinaryID(tsID), pools.ID().BinaryID(nsID),
-		decoder, accum.startTime, accum.endTime, iters)
+		tags, accum.startTime, accum.endTime, iters)
 
-	return seriesIter
+	return seriesIter, nil
 }
 
 func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
@@ -259,15 +374,26 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 
 	result := pools.MutableSeriesIterators().Get(numElements)
 	result.Reset(numElements)
-	count := 0
-	moreElems := false
+	var (
+		count     = 0
+		moreElems = false
+		err       error
+	)
 	accum.responses.forEachID(func(elems fetchTaggedIDResults, hasMore bool) bool {
-		seriesIter := accum.sliceResponsesAsSeriesIter(pools, elems)
+		seriesIter, iterErr := accum.sliceResponsesAsSeriesIter(pools, elems)
+		if iterErr != nil {
+			err = iterErr
+			return false
+		}
 		result.SetAt(count, seriesIter)
 		count++
 		moreElems = hasMore
 		return count < limit
 	})
+	if err != nil {
+		result.Close()
+		return nil, false, err
+	}
 
 	exhaustive := accum.exhaustive && count <= limit && !moreElems
 	return result, exhaustive, nil
@@ -293,9 +419,26 @@ func (accum *fetchTaggedResultAccumulator) AsTaggedIDsIterator(
 	})
 
 	exhaustive := accum.exhaustive && count <= limit && !moreElems
+	if accum.sortTags {
+		return newSortedTagsIDsIterator(iter), exhaustive, nil
+	}
 	return iter, exhaustive, nil
 }
 
+// AsCount returns the number of matching series, summing the counts of
+// each shard.
+func (accum *fetchTaggedResultAccumulator) AsCount(limit int) (int, bool, error) {
+	var count int64
+	for _, shardResult := range accum.shardConsistencyResults {
+		count += shardResult.count
+	}
+	exhaustive := accum.exhaustive && count <= int64(limit)
+	if count > int64(limit) {
+		count = int64(limit)
+	}
+	return int(count), exhaustive, nil
+}
+
 type fetchTaggedShardConsistencyResults []fetchTaggedShardConsistencyResult
 
 func (res fetchTaggedShardConsistencyResults) initialize(length int) fetchTaggedShardConsistencyResults {
@@ -319,16 +462,19 @@ type fetchTaggedIDResults []*rpc.FetchTaggedIDResult_
 type forEachFetchTaggedIDFn func(responsesForSingleID fetchTaggedIDResults, hasMore bool) (continueIterating bool)
 
 // forEachID iterates over the provide results, and calls `fn` on each
-// group of responses with the same ID.
+// group of responses with the same namespace and ID, results hold more than
+// one namespace when a query specifies additional namespaces.
 // NB: assumes the results array being operated upon has been sorted
 func (results fetchTaggedIDResults) forEachID(fn forEachFetchTaggedIDFn) {
 	var (
 		startIdx = 0
+		lastNs   []byte
 		lastID   []byte
 	)
 	for i := 0; i < len(results); i++ {
 		elem := results[i]
-		if !bytes.Equal(elem.ID, lastID) {
+		if !bytes.Equal(elem.ID, lastID) || !bytes.Equal(elem.NameSpace, lastNs) {
+			lastNs = elem.NameSpace
 			lastID = elem.ID
 			// We only want to call the the forEachID fn once we have calculated the entire group,
 			// i.e. once we have gone past the last element for a given ID, but the first element
@@ -351,11 +497,14 @@ func (results fetchTaggedIDResults) forEachID(fn forEachFetchTaggedIDFn) {
 }
 
 // fetchTaggedIDResultsSortedByID implements sort.Interface for fetchTaggedIDResults
-// based on the ID field.
+// based on the ID field, and then the NameSpace field.
 type fetchTaggedIDResultsSortedByID fetchTaggedIDResults
 
 func (a fetchTaggedIDResultsSortedByID) Len() int      { return len(a) }
 func (a fetchTaggedIDResultsSortedByID) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a fetchTaggedIDResultsSortedByID) Less(i, j int) bool {
-	return bytes.Compare(a[i].ID, a[j].ID) < 0
+	if c := bytes.Compare(a[i].ID, a[j].ID); c != 0 {
+		return c < 0
+	}
+	return bytes.Compare(a[i].NameSpace, a[j].NameSpace) < 0
 }
@@ -27,7 +27,9 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/topology/testutil"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -180,6 +182,98 @@ func TestFetchTaggedResultsAccumulatorIdsMergeReportsExhaustiveCorrectly(t *test
 	newTestSerieses(1, 15).assertMatchesEncodingIters(t, iters)
 }
 
+func TestFetchTaggedResultsAccumulatorQueryLimitsExceeded(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	limitExceeded := map[string]string{
+		tchannelthrift.QueryLimitExceededHeader: "max-results=5",
+	}
+	th := newTestFetchTaggedHelper(t)
+	workflow := testFetchTaggedWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchTaggedWorklowStep{
+			testFetchTaggedWorklowStep{
+				hostname:        "testhost0",
+				response:        newTestSerieses(1, 5).toRPCResult(th, testStartTime, false),
+				responseHeaders: limitExceeded,
+			},
+			testFetchTaggedWorklowStep{
+				hostname:        "testhost1",
+				response:        newTestSerieses(1, 5).toRPCResult(th, testStartTime, false),
+				responseHeaders: limitExceeded,
+			},
+			testFetchTaggedWorklowStep{
+				hostname:     "testhost2",
+				response:     newTestSerieses(1, 3).toRPCResult(th, testStartTime, true),
+				expectedDone: true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	// a limit exceeded by more than one host is only reported once.
+	require.Equal(t, []index.QueryLimitExceeded{
+		{Limit: index.MaxResultsQueryLimit, Max: 5},
+	}, accum.Warnings())
+
+	_, exhaust, err := accum.AsEncodingSeriesIterators(100, th.pools)
+	require.NoError(t, err)
+	require.False(t, exhaust)
+
+	accum.Clear()
+	require.Nil(t, accum.Warnings())
+}
+
+func TestFetchTaggedResultsAccumulatorPartialAvailability(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	th := newTestFetchTaggedHelper(t)
+	workflow := testFetchTaggedWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchTaggedWorklowStep{
+			testFetchTaggedWorklowStep{
+				hostname: "testhost0",
+				response: newTestSerieses(1, 5).toRPCResult(th, testStartTime, true),
+			},
+			testFetchTaggedWorklowStep{
+				hostname: "testhost1",
+				response: newTestSerieses(1, 3).toRPCResult(th, testStartTime, false),
+				responseHeaders: map[string]string{
+					tchannelthrift.PartialAvailabilityHeader: "true",
+				},
+			},
+			testFetchTaggedWorklowStep{
+				hostname:     "testhost2",
+				response:     newTestSerieses(1, 5).toRPCResult(th, testStartTime, true),
+				expectedDone: true,
+			},
+		},
+	}
+	accum := workflow.run()
+	require.True(t, accum.PartialAvailability())
+
+	accum.Clear()
+	require.False(t, accum.PartialAvailability())
+}
+
 func TestFetchTaggedResultsAccumulatorSeriesItersDatapoints(t *testing.T) {
 	// rf=3, 3 identical hosts, with same shards
 	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
@@ -300,11 +394,12 @@ type testFetchTaggedWorkflow struct {
 }
 
 type testFetchTaggedWorklowStep struct {
-	hostname     string
-	response     *rpc.FetchTaggedResult_
-	err          error
-	expectedDone bool
-	expectedErr  bool
+	hostname        string
+	response        *rpc.FetchTaggedResult_
+	responseHeaders map[string]string
+	err             error
+	expectedDone    bool
+	expectedErr     bool
 }
 
 func (tm testFetchTaggedWorkflow) run() fetchTaggedResultAccumulator {
@@ -315,8 +410,9 @@ func (tm testFetchTaggedWorkflow) run() fetchTaggedResultAccumulator {
 	accum.Reset(tm.startTime, tm.endTime, tm.topoMap, majority, tm.level)
 	for _, s := range tm.steps {
 		opts := fetchTaggedResultAccumulatorOpts{
-			host:     host(tm.t, tm.topoMap, s.hostname),
-			response: s.response,
+			host:            host(tm.t, tm.topoMap, s.hostname),
+			response:        s.response,
+			responseHeaders: s.responseHeaders,
 		}
 		done, err := accum.Add(opts, s.err)
 		assert.Equal(tm.t, s.expectedDone, done, fmt.Sprintf("%+v", s))
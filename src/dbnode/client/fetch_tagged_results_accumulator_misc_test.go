@@ -32,10 +32,17 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/topology"
+	tu "github.com/m3db/m3/src/dbnode/topology/testutil"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3cluster/shard"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
+	xtime "github.com/m3db/m3x/time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -59,6 +66,83 @@ func TestFetchTaggedResultsAccumulatorClearResetsState(t *testing.T) {
 	require.NoError(t, resultsIter.Err())
 }
 
+func TestFetchTaggedResultsAccumulatorAsCountDedupesReplicas(t *testing.T) {
+	// rf=2, 2 shards total; two identical hosts
+	topoMap := tu.MustNewTopologyMap(2, map[string][]shard.Shard{
+		"testhost0": tu.ShardsRange(0, 1, shard.Available),
+		"testhost1": tu.ShardsRange(0, 1, shard.Available),
+	})
+
+	accum := testFetchTaggedWorkflow{
+		t:       t,
+		topoMap: topoMap,
+		level:   topology.ReadConsistencyLevelAll,
+		steps: []testFetchTaggedWorklowStep{
+			testFetchTaggedWorklowStep{
+				hostname: "testhost0",
+				response: &rpc.FetchTaggedResult_{
+					Exhaustive:  true,
+					ShardCounts: map[int32]int64{0: 3, 1: 2},
+				},
+			},
+			testFetchTaggedWorklowStep{
+				hostname: "testhost1",
+				response: &rpc.FetchTaggedResult_{
+					Exhaustive:  true,
+					ShardCounts: map[int32]int64{0: 4, 1: 2},
+				},
+				expectedDone: true,
+			},
+		},
+	}.run()
+
+	count, exhaustive, err := accum.AsCount(100)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, 6, count)
+
+	count, exhaustive, err = accum.AsCount(5)
+	require.NoError(t, err)
+	require.False(t, exhaustive)
+	require.Equal(t, 5, count)
+}
+
+func TestMultiReaderIteratorFromSegmentsUsesSegmentsEncoding(t *testing.T) {
+	pools := newTestFetchTaggedPools()
+	start := time.Now().Truncate(time.Hour)
+	values := []float64{100, 105, 97}
+
+	enc := m3tsz.NewIntDeltaEncoder(start, nil, encoding.NewOptions())
+	for i, v := range values {
+		dp := ts.Datapoint{Timestamp: start.Add(time.Duration(i) * time.Second), Value: v}
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+	converted, err := convert.ToSegments([]xio.BlockReader{{SegmentReader: enc.Stream()}})
+	require.NoError(t, err)
+	encodingName := m3tsz.IntDeltaCodecName
+	converted.Segments.Encoding = &encodingName
+
+	iter, err := newMultiReaderIteratorFromSegments(pools,
+		[]*rpc.Segments{converted.Segments})
+	require.NoError(t, err)
+	require.Equal(t, m3tsz.IntDeltaCodecName, iter.Codec())
+
+	var decoded []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		decoded = append(decoded, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, values, decoded)
+	iter.Close()
+
+	unknown := "unknown"
+	converted.Segments.Encoding = &unknown
+	_, err = newMultiReaderIteratorFromSegments(pools,
+		[]*rpc.Segments{converted.Segments})
+	require.Error(t, err)
+}
+
 func TestFetchTaggedShardConsistencyResultsInitializeLength(t *testing.T) {
 	var results fetchTaggedShardConsistencyResults
 	require.Len(t, results, 0)
@@ -160,6 +244,33 @@ func TestFetchTaggedForEachIDFnEarlyTerminate(t *testing.T) {
 	require.Equal(t, 2, numElements)
 }
 
+func TestFetchTaggedForEachIDFnGroupsByNamespace(t *testing.T) {
+	input := fetchTaggedIDResults{
+		&rpc.FetchTaggedIDResult_{
+			NameSpace: []byte("unaggregated"),
+			ID:        []byte("abc"),
+		},
+		&rpc.FetchTaggedIDResult_{
+			NameSpace: []byte("aggregated"),
+			ID:        []byte("abc"),
+		},
+		&rpc.FetchTaggedIDResult_{
+			NameSpace: []byte("unaggregated"),
+			ID:        []byte("abc"),
+		},
+	}
+	sort.Sort(fetchTaggedIDResultsSortedByID(input))
+	var namespaces []string
+	input.forEachID(func(elems fetchTaggedIDResults, _ bool) bool {
+		for _, elem := range elems {
+			require.Equal(t, elems[0].NameSpace, elem.NameSpace)
+		}
+		namespaces = append(namespaces, string(elems[0].NameSpace))
+		return true
+	})
+	require.Equal(t, []string{"aggregated", "unaggregated"}, namespaces)
+}
+
 func TestFetchTaggedForEachIDFnNumberCalls(t *testing.T) {
 	parameters := gopter.DefaultTestParameters()
 	seed := time.Now().UnixNano()
@@ -272,6 +383,13 @@ func initTestFetchTaggedPools() *testFetchTaggedPools {
 		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
 	})
 
+	codecRegistry, err := m3tsz.NewCodecRegistry()
+	if err != nil {
+		panic(err)
+	}
+	pools.codecMultiReader = encoding.NewCodecMultiReaderIteratorPools(
+		codecRegistry, pools.multiReader, opts, encoding.NewOptions())
+
 	pools.seriesIter = encoding.NewSeriesIteratorPool(opts)
 	pools.seriesIter.Init()
 
@@ -302,6 +420,7 @@ var _ fetchTaggedPools = testFetchTaggedPools{}
 type testFetchTaggedPools struct {
 	readerSlices             *readerSliceOfSlicesIteratorPool
 	multiReader              encoding.MultiReaderIteratorPool
+	codecMultiReader         encoding.CodecMultiReaderIteratorPools
 	seriesIter               encoding.SeriesIteratorPool
 	mutableSeriesIter        encoding.MutableSeriesIteratorsPool
 	multiReaderIteratorArray encoding.MultiReaderIteratorArrayPool
@@ -318,6 +437,10 @@ func (p testFetchTaggedPools) MultiReaderIterator() encoding.MultiReaderIterator
 	return p.multiReader
 }
 
+func (p testFetchTaggedPools) CodecMultiReaderIterators() encoding.CodecMultiReaderIteratorPools {
+	return p.codecMultiReader
+}
+
 func (p testFetchTaggedPools) SeriesIterator() encoding.SeriesIteratorPool {
 	return p.seriesIter
 }
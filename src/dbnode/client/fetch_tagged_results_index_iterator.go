@@ -21,6 +21,10 @@
 package client
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3x/ident"
 )
@@ -37,6 +41,10 @@ type taggedIDsIterator struct {
 		tags serialize.TagDecoder
 	}
 
+	// decoder is reused to decode the tags of each result as they are only
+	// valid until the next call to Next.
+	decoder *reusedTagDecoder
+
 	backing struct {
 		nses [][]byte
 		ids  [][]byte
@@ -65,13 +73,15 @@ func (i *taggedIDsIterator) Next() bool {
 		return false
 	}
 
-	dec := i.pools.TagDecoder().Get()
+	if i.decoder == nil {
+		i.decoder = &reusedTagDecoder{i.pools.TagDecoder().Get()}
+	}
 	wb := i.pools.CheckedBytesWrapper().Get(i.backing.tags[i.currentIdx])
-	dec.Reset(wb)
+	i.decoder.Reset(wb)
 
 	i.current.tsID = i.asIdent(i.backing.ids[i.currentIdx])
 	i.current.nsID = i.asIdent(i.backing.nses[i.currentIdx])
-	i.current.tags = dec
+	i.current.tags = i.decoder
 	return true
 }
 
@@ -88,6 +98,10 @@ func (i *taggedIDsIterator) asIdent(b []byte) ident.ID {
 
 func (i *taggedIDsIterator) Finalize() {
 	i.release()
+	if i.decoder != nil {
+		i.decoder.TagDecoder.Close()
+		i.decoder = nil
+	}
 	i.backing.nses = nil
 	i.backing.ids = nil
 	i.backing.tags = nil
@@ -102,10 +116,8 @@ func (i *taggedIDsIterator) release() {
 		id.Finalize()
 		i.current.tsID = nil
 	}
-	if decoder := i.current.tags; decoder != nil {
-		decoder.Close()
-		i.current.tags = nil
-	}
+	// NB: the decoder is reused so it is only closed on finalize.
+	i.current.tags = nil
 }
 
 func (i *taggedIDsIterator) Current() (ident.ID, ident.ID, ident.TagIterator) {
@@ -115,3 +127,125 @@ func (i *taggedIDsIterator) Current() (ident.ID, ident.ID, ident.TagIterator) {
 func (i *taggedIDsIterator) Err() error {
 	return i.err
 }
+
+// reusedTagDecoder is a tag decoder handed out to callers that ignores
+// Close, as the underlying decoder is reused across results.
+type reusedTagDecoder struct {
+	serialize.TagDecoder
+}
+
+func (d *reusedTagDecoder) Close() {}
+
+// sortedTags copies tags sorted by name with duplicate names removed, a
+// duplicate name with a different value is an error.
+type sortedTags struct {
+	buf     []byte
+	offsets []sortedTagOffsets
+	tags    []ident.Tag
+}
+
+type sortedTagOffsets struct {
+	nameStart  int
+	valueStart int
+	valueEnd   int
+}
+
+// reset copies and sorts the given tags, the tags are copied since decoded
+// bytes are only valid until the decoder moves to the next tag.
+func (t *sortedTags) reset(iter ident.TagIterator) error {
+	t.buf = t.buf[:0]
+	t.offsets = t.offsets[:0]
+	for iter.Next() {
+		tag := iter.Current()
+		nameStart := len(t.buf)
+		t.buf = append(t.buf, tag.Name.Bytes()...)
+		valueStart := len(t.buf)
+		t.buf = append(t.buf, tag.Value.Bytes()...)
+		t.offsets = append(t.offsets, sortedTagOffsets{
+			nameStart:  nameStart,
+			valueStart: valueStart,
+			valueEnd:   len(t.buf),
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	t.tags = t.tags[:0]
+	for _, o := range t.offsets {
+		t.tags = append(t.tags, ident.Tag{
+			Name:  ident.BytesID(t.buf[o.nameStart:o.valueStart]),
+			Value: ident.BytesID(t.buf[o.valueStart:o.valueEnd]),
+		})
+	}
+	sort.SliceStable(t.tags, func(a, b int) bool {
+		return bytes.Compare(t.tags[a].Name.Bytes(), t.tags[b].Name.Bytes()) < 0
+	})
+	deduped := t.tags[:0]
+	for _, tag := range t.tags {
+		last := len(deduped) - 1
+		if last < 0 || !bytes.Equal(deduped[last].Name.Bytes(), tag.Name.Bytes()) {
+			deduped = append(deduped, tag)
+			continue
+		}
+		if !bytes.Equal(deduped[last].Value.Bytes(), tag.Value.Bytes()) {
+			return fmt.Errorf("conflicting values for duplicate tag name: %s",
+				tag.Name.String())
+		}
+	}
+	t.tags = deduped
+	return nil
+}
+
+// newSortedTagIterator returns a copy of the given tags sorted by name with
+// duplicate names removed, the given tags are closed.
+func newSortedTagIterator(iter ident.TagIterator) (ident.TagIterator, error) {
+	var sorted sortedTags
+	err := sorted.reset(iter)
+	iter.Close()
+	if err != nil {
+		return nil, err
+	}
+	return ident.NewTagsIterator(ident.NewTags(sorted.tags...)), nil
+}
+
+// sortedTagsIDsIterator returns the tags of each result sorted by name with
+// duplicate names removed.
+type sortedTagsIDsIterator struct {
+	TaggedIDsIterator
+
+	err     error
+	sorted  sortedTags
+	current ident.TagIterator
+}
+
+func newSortedTagsIDsIterator(iter TaggedIDsIterator) *sortedTagsIDsIterator {
+	return &sortedTagsIDsIterator{TaggedIDsIterator: iter}
+}
+
+func (i *sortedTagsIDsIterator) Next() bool {
+	if i.err != nil || !i.TaggedIDsIterator.Next() {
+		return false
+	}
+
+	_, _, tags := i.TaggedIDsIterator.Current()
+	if err := i.sorted.reset(tags); err != nil {
+		i.err = err
+		return false
+	}
+
+	i.current = ident.NewTagsIterator(ident.NewTags(i.sorted.tags...))
+	return true
+}
+
+func (i *sortedTagsIDsIterator) Current() (ident.ID, ident.ID, ident.TagIterator) {
+	nsID, tsID, _ := i.TaggedIDsIterator.Current()
+	return nsID, tsID, i.current
+}
+
+func (i *sortedTagsIDsIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.TaggedIDsIterator.Err()
+}
@@ -23,6 +23,7 @@ package client
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
@@ -85,3 +86,135 @@ func TestFetchTaggedResultsIndexIterator(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchTaggedResultsIndexIteratorReusesDecoder(t *testing.T) {
+	pools := newTestFetchTaggedPools()
+
+	opts := serialize.NewTagEncoderOptions()
+	popts := pool.NewObjectPoolOptions().SetSize(1)
+	encPool := serialize.NewTagEncoderPool(opts, popts)
+	encPool.Init()
+
+	iter := newTaggedIDsIterator(pools)
+	for _, value := range []string{"tv0", "tv1"} {
+		enc := encPool.Get()
+		require.NoError(t, enc.Encode(ident.NewTagsIterator(ident.NewTags(
+			ident.StringTag("tn", value)))))
+		data, ok := enc.Data()
+		require.True(t, ok)
+		iter.addBacking([]byte("ns"), []byte(value), data.Bytes())
+	}
+
+	var decoders []ident.TagIterator
+	for _, value := range []string{"tv0", "tv1"} {
+		require.True(t, iter.Next())
+		_, _, tags := iter.Current()
+		require.True(t, tags.Next())
+		require.Equal(t, value, tags.Current().Value.String())
+		// Closing the tags must not release the reused decoder.
+		tags.Close()
+		decoders = append(decoders, tags)
+	}
+	require.False(t, iter.Next())
+	require.True(t, decoders[0] == decoders[1])
+	iter.Finalize()
+}
+
+func TestFetchTaggedResultsIndexIteratorSortedTags(t *testing.T) {
+	pools := newTestFetchTaggedPools()
+
+	opts := serialize.NewTagEncoderOptions()
+	popts := pool.NewObjectPoolOptions().SetSize(1)
+	encPool := serialize.NewTagEncoderPool(opts, popts)
+	encPool.Init()
+
+	iter := newTaggedIDsIterator(pools)
+	for _, tags := range []ident.Tags{
+		ident.NewTags(ident.StringTag("c", "3"), ident.StringTag("a", "1"),
+			ident.StringTag("b", "2"), ident.StringTag("a", "1")),
+		ident.NewTags(ident.StringTag("z", "26")),
+	} {
+		enc := encPool.Get()
+		require.NoError(t, enc.Encode(ident.NewTagsIterator(tags)))
+		data, ok := enc.Data()
+		require.True(t, ok)
+		iter.addBacking([]byte("ns"), []byte("id"), data.Bytes())
+	}
+
+	sorted := newSortedTagsIDsIterator(iter)
+	for _, expected := range []ident.Tags{
+		ident.NewTags(ident.StringTag("a", "1"), ident.StringTag("b", "2"),
+			ident.StringTag("c", "3")),
+		ident.NewTags(ident.StringTag("z", "26")),
+	} {
+		require.True(t, sorted.Next())
+		_, _, tags := sorted.Current()
+		require.True(t, ident.NewTagIterMatcher(
+			ident.NewTagsIterator(expected)).Matches(tags))
+	}
+	require.False(t, sorted.Next())
+	require.NoError(t, sorted.Err())
+	sorted.Finalize()
+}
+
+func TestFetchTaggedResultsIndexIteratorSortedTagsConflictingDuplicate(t *testing.T) {
+	pools := newTestFetchTaggedPools()
+
+	opts := serialize.NewTagEncoderOptions()
+	popts := pool.NewObjectPoolOptions().SetSize(1)
+	encPool := serialize.NewTagEncoderPool(opts, popts)
+	encPool.Init()
+
+	enc := encPool.Get()
+	require.NoError(t, enc.Encode(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("a", "1"), ident.StringTag("a", "2")))))
+	data, ok := enc.Data()
+	require.True(t, ok)
+
+	iter := newTaggedIDsIterator(pools)
+	iter.addBacking([]byte("ns"), []byte("id"), data.Bytes())
+
+	sorted := newSortedTagsIDsIterator(iter)
+	require.False(t, sorted.Next())
+	require.Error(t, sorted.Err())
+	sorted.Finalize()
+}
+
+func TestFetchTaggedResultsAccumulatorSortedSeriesIteratorTags(t *testing.T) {
+	pools := newTestFetchTaggedPools()
+
+	opts := serialize.NewTagEncoderOptions()
+	popts := pool.NewObjectPoolOptions().SetSize(1)
+	encPool := serialize.NewTagEncoderPool(opts, popts)
+	encPool.Init()
+
+	encode := func(tags ident.Tags) []byte {
+		enc := encPool.Get()
+		require.NoError(t, enc.Encode(ident.NewTagsIterator(tags)))
+		data, ok := enc.Data()
+		require.True(t, ok)
+		return append([]byte(nil), data.Bytes()...)
+	}
+
+	accum := newFetchTaggedResultAccumulator()
+	accum.SetSortTags(true)
+	accum.responses = append(accum.responses, &rpc.FetchTaggedIDResult_{
+		NameSpace: []byte("ns"),
+		ID:        []byte("id"),
+		EncodedTags: encode(ident.NewTags(ident.StringTag("b", "2"),
+			ident.StringTag("a", "1"), ident.StringTag("b", "2"))),
+	})
+
+	iters, _, err := accum.AsEncodingSeriesIterators(10, pools)
+	require.NoError(t, err)
+	require.Equal(t, 1, iters.Len())
+	require.True(t, ident.NewTagIterMatcher(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("a", "1"), ident.StringTag("b", "2")),
+	)).Matches(iters.Iters()[0].Tags()))
+	iters.Close()
+
+	accum.responses[0].EncodedTags = encode(ident.NewTags(
+		ident.StringTag("a", "1"), ident.StringTag("a", "2")))
+	_, _, err = accum.AsEncodingSeriesIterators(10, pools)
+	require.Error(t, err)
+}
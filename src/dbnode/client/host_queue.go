@@ -23,15 +23,22 @@ package client
 import (
 	"fmt"
 	"math"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
 
+	"github.com/uber-go/tally"
 	"github.com/uber/tchannel-go/thrift"
 )
 
@@ -39,8 +46,16 @@ type queue struct {
 	sync.WaitGroup
 	sync.RWMutex
 
+	// NB: accessed atomically, kept first to keep them 64 bit aligned.
+	pending          int64
+	enqueueFailures  int64
+	lastFlushLatency int64
+
 	opts                                       Options
+	nsOpts                                     namespaceOptionsResolver
 	nowFn                                      clock.NowFn
+	sleepFn                                    clock.SleepFn
+	faultInjector                              fault.Injector
 	host                                       topology.Host
 	connPool                                   connectionPool
 	writeBatchRawRequestPool                   writeBatchRawRequestPool
@@ -52,10 +67,33 @@ type queue struct {
 	opsSumSize                                 int
 	opsLastRotatedAt                           time.Time
 	opsArrayPool                               *opArrayPool
+	tagDecoderPool                             serialize.TagDecoderPool
 	drainIn                                    chan []op
+	capacity                                   int
+	metrics                                    hostQueueMetrics
 	status                                     status
 }
 
+type hostQueueMetrics struct {
+	pending              tally.Gauge
+	backpressure         tally.Gauge
+	enqueueFailures      tally.Counter
+	flushLatency         tally.Timer
+	newSeriesWrites      tally.Counter
+	existingSeriesWrites tally.Counter
+}
+
+func newHostQueueMetrics(scope tally.Scope) hostQueueMetrics {
+	return hostQueueMetrics{
+		pending:              scope.Gauge("pending"),
+		backpressure:         scope.Gauge("backpressure"),
+		enqueueFailures:      scope.Counter("enqueue-failures"),
+		flushLatency:         scope.Timer("flush-latency"),
+		newSeriesWrites:      scope.Counter("new-series-writes"),
+		existingSeriesWrites: scope.Counter("existing-series-writes"),
+	}
+}
+
 func newHostQueue(
 	host topology.Host,
 	hostQueueOpts hostQueueOpts,
@@ -81,19 +119,35 @@ func newHostQueue(
 	opArrayPool := newOpArrayPool(opArrayPoolOpts, opArrayPoolCapacity)
 	opArrayPool.Init()
 
+	// NB: tag decoders are only used to write to hosts that do not support
+	// batched tagged writes, so keep few of them around.
+	tagDecoderPool := serialize.NewTagDecoderPool(opts.TagDecoderOptions(),
+		pool.NewObjectPoolOptions().
+			SetSize(1).
+			SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(
+				scope.SubScope("tag-decoder-pool"),
+			)))
+	tagDecoderPool.Init()
+
 	return &queue{
 		opts:                                       opts,
+		nsOpts:                                     newNamespaceOptionsResolver(opts),
 		nowFn:                                      opts.ClockOptions().NowFn(),
+		sleepFn:                                    opts.ClockOptions().SleepFn(),
+		faultInjector:                              opts.FaultInjector(),
 		host:                                       host,
 		connPool:                                   newConnectionPool(host, opts),
 		writeBatchRawRequestPool:                   hostQueueOpts.writeBatchRawRequestPool,
 		writeBatchRawRequestElementArrayPool:       hostQueueOpts.writeBatchRawRequestElementArrayPool,
 		writeTaggedBatchRawRequestPool:             hostQueueOpts.writeTaggedBatchRawRequestPool,
 		writeTaggedBatchRawRequestElementArrayPool: hostQueueOpts.writeTaggedBatchRawRequestElementArrayPool,
-		size:         size,
-		ops:          opArrayPool.Get(),
-		opsArrayPool: opArrayPool,
-		drainIn:      make(chan []op, opsArraysLen),
+		size:           size,
+		ops:            opArrayPool.Get(),
+		opsArrayPool:   opArrayPool,
+		tagDecoderPool: tagDecoderPool,
+		drainIn:        make(chan []op, opsArraysLen),
+		capacity:       size * opsArraysLen,
+		metrics:        newHostQueueMetrics(scope),
 	}
 }
 
@@ -118,6 +172,60 @@ func (q *queue) Open() {
 		// Continually flush the queue at given interval if set
 		go q.flushEvery(flushInterval)
 	}
+
+	go q.reportEvery(q.opts.InstrumentOptions().ReportInterval())
+}
+
+func (q *queue) reportEvery(interval time.Duration) {
+	for {
+		q.sleepFn(interval)
+
+		q.RLock()
+		open := q.status == statusOpen
+		q.RUnlock()
+		if !open {
+			return
+		}
+
+		stats := q.Stats()
+		q.metrics.pending.Update(float64(stats.Pending))
+		q.metrics.backpressure.Update(stats.Backpressure())
+		if fn := q.opts.HostQueueStatsFn(); fn != nil {
+			fn(stats)
+		}
+	}
+}
+
+func (q *queue) Stats() HostQueueStats {
+	return HostQueueStats{
+		HostID:           q.host.ID(),
+		Pending:          int(atomic.LoadInt64(&q.pending)),
+		Capacity:         q.capacity,
+		EnqueueFailures:  atomic.LoadInt64(&q.enqueueFailures),
+		LastFlushLatency: time.Duration(atomic.LoadInt64(&q.lastFlushLatency)),
+	}
+}
+
+// completed marks operations as no longer pending.
+func (q *queue) completed(n int) {
+	atomic.AddInt64(&q.pending, -int64(n))
+}
+
+func (q *queue) recordFlush(start time.Time) {
+	took := q.nowFn().Sub(start)
+	atomic.StoreInt64(&q.lastFlushLatency, int64(took))
+	q.metrics.flushLatency.Record(took)
+}
+
+// recordWriteResult records how many of a successful batch of writes
+// created new series on the host versus wrote to existing series.
+func (q *queue) recordWriteResult(result *rpc.WriteBatchRawResult_, numWrites int) {
+	var newSeries int
+	if result != nil {
+		newSeries = len(result.NewSeriesIndexes)
+	}
+	q.metrics.newSeriesWrites.Inc(int64(newSeries))
+	q.metrics.existingSeriesWrites.Inc(int64(numWrites - newSeries))
 }
 
 func (q *queue) flushEvery(interval time.Duration) {
@@ -240,6 +348,7 @@ func (q *queue) drain() {
 			default:
 				completionFn := ops[i].CompletionFn()
 				completionFn(nil, errQueueUnknownOperation(q.host.ID()))
+				q.completed(ops[i].Size())
 			}
 		}
 
@@ -291,6 +400,7 @@ func (q *queue) asyncTaggedWrite(
 
 		// NB(r): Defer is slow in the hot path unfortunately
 		cleanup := func() {
+			q.completed(len(ops))
 			q.writeTaggedBatchRawRequestPool.Put(req)
 			q.writeTaggedBatchRawRequestElementArrayPool.Put(elems)
 			q.opsArrayPool.Put(ops)
@@ -308,10 +418,35 @@ func (q *queue) asyncTaggedWrite(
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
-		err = client.WriteTaggedBatchRaw(ctx, req)
+		res := q.faultInjector.Inject(fault.RPCSend)
+		if res.Err != nil && !res.Partial {
+			callAllCompletionFns(ops, q.host, res.Err)
+			cleanup()
+			return
+		}
+
+		if !q.connPool.Capabilities().Has(tchannelthrift.CapabilityWriteTaggedBatchRaw) {
+			// Host predates batched tagged writes, degrade to single writes.
+			q.writeTaggedUnbatched(client, req.NameSpace, ops, elems)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.nsOpts.writeRequestTimeout(req.NameSpace))
+		ctx = tchannelthrift.WithRequestMetadata(ctx, batchRequestMetadata(ops))
+		start := q.nowFn()
+		result, err := client.WriteTaggedBatchRaw(ctx, req)
+		q.recordFlush(start)
+		if res.Err != nil {
+			// NB: the request was sent, fail the writes regardless to simulate
+			// the response being lost after the writes were applied.
+			callAllCompletionFns(ops, q.host, res.Err)
+			cleanup()
+			return
+		}
 		if err == nil {
 			// All succeeded
+			q.recordWriteResult(result, len(ops))
 			callAllCompletionFns(ops, q.host, nil)
 			cleanup()
 			return
@@ -342,6 +477,55 @@ func (q *queue) asyncTaggedWrite(
 	}()
 }
 
+// writeTaggedUnbatched writes tagged write ops one request at a time for
+// hosts that do not support the writeTaggedBatchRaw endpoint.
+func (q *queue) writeTaggedUnbatched(
+	client rpc.TChanNode,
+	namespace []byte,
+	ops []op,
+	elems []*rpc.WriteTaggedBatchRawRequestElement,
+) {
+	timeout := q.nsOpts.writeRequestTimeout(namespace)
+	for i, elem := range elems {
+		req, err := q.newWriteTaggedRequest(namespace, elem)
+		if err == nil {
+			ctx, _ := thrift.NewContext(timeout)
+			ctx = tchannelthrift.WithRequestMetadata(ctx,
+				batchRequestMetadata(ops[i:i+1]))
+			err = client.WriteTagged(ctx, req)
+		}
+		ops[i].CompletionFn()(q.host, err)
+	}
+}
+
+func (q *queue) newWriteTaggedRequest(
+	namespace []byte,
+	elem *rpc.WriteTaggedBatchRawRequestElement,
+) (*rpc.WriteTaggedRequest, error) {
+	decoder := q.tagDecoderPool.Get()
+	defer decoder.Close()
+
+	decoder.Reset(checked.NewBytes(elem.EncodedTags, nil))
+	tags := make([]*rpc.Tag, 0, decoder.Remaining())
+	for decoder.Next() {
+		tag := decoder.Current()
+		tags = append(tags, &rpc.Tag{
+			Name:  tag.Name.String(),
+			Value: tag.Value.String(),
+		})
+	}
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+
+	return &rpc.WriteTaggedRequest{
+		NameSpace: string(namespace),
+		ID:        string(elem.ID),
+		Tags:      tags,
+		Datapoint: elem.Datapoint,
+	}, nil
+}
+
 func (q *queue) asyncWrite(
 	namespace ident.ID,
 	ops []op,
@@ -356,6 +540,7 @@ func (q *queue) asyncWrite(
 
 		// NB(r): Defer is slow in the hot path unfortunately
 		cleanup := func() {
+			q.completed(len(ops))
 			q.writeBatchRawRequestPool.Put(req)
 			q.writeBatchRawRequestElementArrayPool.Put(elems)
 			q.opsArrayPool.Put(ops)
@@ -373,10 +558,28 @@ func (q *queue) asyncWrite(
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
-		err = client.WriteBatchRaw(ctx, req)
+		res := q.faultInjector.Inject(fault.RPCSend)
+		if res.Err != nil && !res.Partial {
+			callAllCompletionFns(ops, q.host, res.Err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.nsOpts.writeRequestTimeout(req.NameSpace))
+		ctx = tchannelthrift.WithRequestMetadata(ctx, batchRequestMetadata(ops))
+		start := q.nowFn()
+		result, err := client.WriteBatchRaw(ctx, req)
+		q.recordFlush(start)
+		if res.Err != nil {
+			// NB: the request was sent, fail the writes regardless to simulate
+			// the response being lost after the writes were applied.
+			callAllCompletionFns(ops, q.host, res.Err)
+			cleanup()
+			return
+		}
 		if err == nil {
 			// All succeeded
+			q.recordWriteResult(result, len(ops))
 			callAllCompletionFns(ops, q.host, nil)
 			cleanup()
 			return
@@ -411,8 +614,10 @@ func (q *queue) asyncFetch(op *fetchBatchOp) {
 	q.Add(1)
 	// TODO(r): Use a worker pool to avoid creating new go routines for async fetches
 	go func() {
+		size := op.Size()
 		// NB(r): Defer is slow in the hot path unfortunately
 		cleanup := func() {
+			q.completed(size)
 			op.DecRef()
 			op.Finalize()
 			q.Done()
@@ -426,8 +631,17 @@ func (q *queue) asyncFetch(op *fetchBatchOp) {
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		if res := q.faultInjector.Inject(fault.RPCSend); res.Err != nil {
+			op.completeAll(nil, res.Err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.nsOpts.fetchRequestTimeout(op.request.NameSpace))
+		ctx = tchannelthrift.WithRequestMetadata(ctx, op.requestMetadata)
+		start := q.nowFn()
 		result, err := client.FetchBatchRaw(ctx, &op.request)
+		q.recordFlush(start)
 		if err != nil {
 			op.completeAll(nil, err)
 			cleanup()
@@ -458,6 +672,7 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 	go func() {
 		// NB(r): Defer is slow in the hot path unfortunately
 		cleanup := func() {
+			q.completed(op.Size())
 			op.decRef()
 			q.Done()
 		}
@@ -470,8 +685,27 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		if !q.connPool.Capabilities().Has(tchannelthrift.CapabilityFetchTaggedTags) {
+			// Host may not return tags with its results, fail the fetch from
+			// this host so that results are only taken from the replicas
+			// that do.
+			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host},
+				errQueueFetchTaggedTagsUnsupported(q.host.ID()))
+			cleanup()
+			return
+		}
+
+		if res := q.faultInjector.Inject(fault.RPCSend); res.Err != nil {
+			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, res.Err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.nsOpts.fetchRequestTimeout(op.request.NameSpace))
+		ctx = tchannelthrift.WithRequestMetadata(ctx, op.requestMetadata)
+		start := q.nowFn()
 		result, err := client.FetchTagged(ctx, &op.request)
+		q.recordFlush(start)
 		if err != nil {
 			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
@@ -479,8 +713,9 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 		}
 
 		op.CompletionFn()(fetchTaggedResultAccumulatorOpts{
-			host:     q.host,
-			response: result,
+			host:            q.host,
+			response:        result,
+			responseHeaders: ctx.ResponseHeaders(),
 		}, err)
 		cleanup()
 	}()
@@ -490,7 +725,10 @@ func (q *queue) asyncTruncate(op *truncateOp) {
 	q.Add(1)
 
 	go func() {
-		cleanup := q.Done
+		cleanup := func() {
+			q.completed(op.Size())
+			q.Done()
+		}
 
 		client, err := q.connPool.NextClient()
 		if err != nil {
@@ -528,14 +766,20 @@ func (q *queue) Enqueue(o op) error {
 		sOp.incRef()
 	}
 
-	var needsDrain []op
+	var (
+		needsDrain []op
+		size       = o.Size()
+	)
 	q.Lock()
 	if q.status != statusOpen {
 		q.Unlock()
+		atomic.AddInt64(&q.enqueueFailures, 1)
+		q.metrics.enqueueFailures.Inc(1)
 		return errQueueNotOpen(q.host.ID())
 	}
 	q.ops = append(q.ops, o)
-	q.opsSumSize += o.Size()
+	q.opsSumSize += size
+	atomic.AddInt64(&q.pending, int64(size))
 	// If queue is full flush
 	if q.opsSumSize >= q.size {
 		needsDrain = q.rotateOpsWithLock()
@@ -602,6 +846,34 @@ func (q *queue) Close() {
 	q.Unlock()
 }
 
+// batchRequestMetadata returns the distinct request metadata of the write
+// ops in a batch joined by commas, writes from different callers are
+// batched together so each caller's metadata is sent.
+func batchRequestMetadata(ops []op) string {
+	var result string
+	for _, op := range ops {
+		var requestMetadata string
+		switch v := op.(type) {
+		case *writeOperation:
+			requestMetadata = v.requestMetadata
+		case *writeTaggedOperation:
+			requestMetadata = v.requestMetadata
+		}
+		if requestMetadata == "" || strings.Contains(","+result+",", ","+requestMetadata+",") {
+			continue
+		}
+		if result != "" {
+			requestMetadata = "," + requestMetadata
+		}
+		if len(result)+len(requestMetadata) > tchannelthrift.MaxRequestMetadataLength {
+			// NB: the node truncates longer metadata anyway.
+			break
+		}
+		result += requestMetadata
+	}
+	return result
+}
+
 // errors
 
 func errQueueNotOpen(hostID string) error {
@@ -616,6 +888,10 @@ func errQueueFetchNoResponse(hostID string) error {
 	return fmt.Errorf("host operation queue did not receive response for given fetch for host: %s", hostID)
 }
 
+func errQueueFetchTaggedTagsUnsupported(hostID string) error {
+	return fmt.Errorf("host does not support returning tags from fetch tagged: %s", hostID)
+}
+
 // ops container types
 
 type namespaceWriteBatchOps struct {
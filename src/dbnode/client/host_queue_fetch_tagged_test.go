@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,8 @@ func TestHostQueueDrainOnCloseFetchTagged(t *testing.T) {
 	opts := newHostQueueTestOptions()
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -157,9 +160,27 @@ func TestHostQueueFetchTaggedErrorOnFetchTaggedError(t *testing.T) {
 	})
 }
 
+func TestHostQueueFetchTaggedErrorOnLegacyHost(t *testing.T) {
+	namespace := "testNs"
+	expectedResults := []hostQueueResult{
+		hostQueueResult{
+			result: fetchTaggedResultAccumulatorOpts{host: h},
+			err:    errQueueFetchTaggedTagsUnsupported(h.ID()),
+		},
+	}
+	capabilities := tchannelthrift.LegacyCapabilities
+	opts := &testHostQueueFetchTaggedOptions{
+		capabilities: &capabilities,
+	}
+	testHostQueueFetchTagged(t, namespace, nil, expectedResults, opts, func(results []hostQueueResult) {
+		assert.Equal(t, expectedResults, results)
+	})
+}
+
 type testHostQueueFetchTaggedOptions struct {
 	nextClientErr  error
 	fetchTaggedErr error
+	capabilities   *tchannelthrift.Capabilities
 }
 
 func testHostQueueFetchTagged(
@@ -180,6 +201,12 @@ func testHostQueueFetchTagged(
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
 
+	capabilities := tchannelthrift.ServerCapabilities
+	if testOpts != nil && testOpts.capabilities != nil {
+		capabilities = *testOpts.capabilities
+	}
+	mockConnPool.EXPECT().Capabilities().Return(capabilities).AnyTimes()
+
 	// Open
 	mockConnPool.EXPECT().Open()
 	queue.Open()
@@ -203,6 +230,8 @@ func testHostQueueFetchTagged(
 	mockClient := rpc.NewMockTChanNode(ctrl)
 	if testOpts != nil && testOpts.nextClientErr != nil {
 		mockConnPool.EXPECT().NextClient().Return(nil, testOpts.nextClientErr)
+	} else if testOpts != nil && testOpts.capabilities != nil {
+		mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 	} else if testOpts != nil && testOpts.fetchTaggedErr != nil {
 		fetchTaggedExec := func(ctx thrift.Context, req *rpc.FetchTaggedRequest) {
 			require.NotNil(t, req)
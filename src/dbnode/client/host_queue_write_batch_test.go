@@ -22,11 +22,13 @@ package client
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3x/ident"
 
 	"github.com/golang/mock/gomock"
@@ -46,6 +48,7 @@ func TestHostQueueWriteErrorAfterClose(t *testing.T) {
 	queue.Open()
 	queue.Close()
 	assert.Error(t, queue.Enqueue(&writeOperation{}))
+	assert.Equal(t, int64(1), queue.Stats().EnqueueFailures)
 }
 
 func TestHostQueueWriteBatches(t *testing.T) {
@@ -88,6 +91,7 @@ func TestHostQueueWriteBatches(t *testing.T) {
 		// Sleep some so that we can ensure flushing is not happening until queue is full
 		time.Sleep(20 * time.Millisecond)
 	}
+	assert.Equal(t, 3, queue.Stats().Pending)
 
 	// Prepare mocks for flush
 	mockClient := rpc.NewMockTChanNode(ctrl)
@@ -97,7 +101,7 @@ func TestHostQueueWriteBatches(t *testing.T) {
 			assert.Equal(t, req.Elements[i].Datapoint, write.request.Datapoint)
 		}
 	}
-	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil)
+	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil)
 
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
@@ -122,6 +126,9 @@ func TestHostQueueWriteBatches(t *testing.T) {
 	})
 	queue.Close()
 	closeWg.Wait()
+
+	// All writes completed
+	assert.Equal(t, 0, queue.Stats().Pending)
 }
 
 func TestHostQueueWriteBatchesDifferentNamespaces(t *testing.T) {
@@ -178,7 +185,7 @@ func TestHostQueueWriteBatchesDifferentNamespaces(t *testing.T) {
 	}
 
 	// Assert the writes will be handled in two batches
-	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil).MinTimes(2).MaxTimes(2)
+	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil).MinTimes(2).MaxTimes(2)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil).MinTimes(2).MaxTimes(2)
 
 	for _, write := range writes {
@@ -296,7 +303,7 @@ func TestHostQueueWriteBatchesPartialBatchErrs(t *testing.T) {
 			Message: writeErr,
 		}},
 	}}
-	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(batchErrs)
+	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, batchErrs)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
 	// Perform writes
@@ -355,7 +362,7 @@ func TestHostQueueWriteBatchesEntireBatchErr(t *testing.T) {
 			assert.Equal(t, req.Elements[i].Datapoint, write.request.Datapoint)
 		}
 	}
-	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(writeErr)
+	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, writeErr)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
 	// Perform writes
@@ -424,7 +431,7 @@ func TestHostQueueDrainOnClose(t *testing.T) {
 			assert.Equal(t, req.Elements[i].Datapoint, write.request.Datapoint)
 		}
 	}
-	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil)
+	mockClient.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil)
 
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
@@ -475,3 +482,18 @@ func testWriteOp(
 	w.completionFn = completionFn
 	return w
 }
+
+func TestHostQueueBatchRequestMetadata(t *testing.T) {
+	ops := []op{
+		&writeOperation{requestMetadata: "a"},
+		&writeTaggedOperation{},
+		&writeTaggedOperation{requestMetadata: "b"},
+		&writeOperation{requestMetadata: "a"},
+	}
+	assert.Equal(t, "a,b", batchRequestMetadata(ops))
+	assert.Equal(t, "", batchRequestMetadata(ops[1:2]))
+
+	long := &writeOperation{requestMetadata: strings.Repeat("c",
+		tchannelthrift.MaxRequestMetadataLength)}
+	assert.Equal(t, "a", batchRequestMetadata([]op{ops[0], long}))
+}
@@ -28,10 +28,14 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3x/ident"
+	"github.com/m3db/m3x/pool"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/tchannel-go/thrift"
 )
 
@@ -59,6 +63,8 @@ func TestHostQueueWriteTaggedBatches(t *testing.T) {
 	opts := newHostQueueTestOptions()
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -112,7 +118,7 @@ func TestHostQueueWriteTaggedBatches(t *testing.T) {
 			assert.Equal(t, req.Elements[i].EncodedTags, write.request.EncodedTags)
 		}
 	}
-	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil)
+	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil)
 
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
@@ -148,6 +154,8 @@ func TestHostQueueWriteTaggedBatchesDifferentNamespaces(t *testing.T) {
 	opts := newHostQueueTestOptions()
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -205,7 +213,7 @@ func TestHostQueueWriteTaggedBatchesDifferentNamespaces(t *testing.T) {
 	}
 
 	// Assert the writes will be handled in two batches
-	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil).MinTimes(2).MaxTimes(2)
+	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil).MinTimes(2).MaxTimes(2)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil).MinTimes(2).MaxTimes(2)
 
 	for _, write := range writes {
@@ -241,6 +249,8 @@ func TestHostQueueWriteTaggedBatchesNoClientAvailable(t *testing.T) {
 	opts = opts.SetHostQueueOpsFlushInterval(time.Millisecond)
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -284,6 +294,8 @@ func TestHostQueueWriteTaggedBatchesPartialBatchErrs(t *testing.T) {
 	opts = opts.SetHostQueueOpsFlushSize(2)
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -328,7 +340,7 @@ func TestHostQueueWriteTaggedBatchesPartialBatchErrs(t *testing.T) {
 			Message: writeErr,
 		}},
 	}}
-	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(batchErrs)
+	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, batchErrs)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
 	// Perform writes
@@ -359,6 +371,8 @@ func TestHostQueueWriteTaggedBatchesEntireBatchErr(t *testing.T) {
 	opts = opts.SetHostQueueOpsFlushSize(2)
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -388,7 +402,7 @@ func TestHostQueueWriteTaggedBatchesEntireBatchErr(t *testing.T) {
 			assert.Equal(t, req.Elements[i].EncodedTags, write.request.EncodedTags)
 		}
 	}
-	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(writeErr)
+	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, writeErr)
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
 	// Perform writes
@@ -418,6 +432,8 @@ func TestHostQueueDrainOnCloseTaggedWrite(t *testing.T) {
 	opts := newHostQueueTestOptions()
 	queue := newTestHostQueue(opts)
 	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.ServerCapabilities).AnyTimes()
 
 	// Open
 	mockConnPool.EXPECT().Open()
@@ -458,7 +474,7 @@ func TestHostQueueDrainOnCloseTaggedWrite(t *testing.T) {
 			assert.Equal(t, req.Elements[i].EncodedTags, write.request.EncodedTags)
 		}
 	}
-	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil)
+	mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).Do(writeBatch).Return(nil, nil)
 
 	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
 
@@ -489,6 +505,81 @@ func TestHostQueueDrainOnCloseTaggedWrite(t *testing.T) {
 	}
 }
 
+func TestHostQueueWriteTaggedUnbatchedWithoutCapability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnPool := NewMockconnectionPool(ctrl)
+
+	opts := newHostQueueTestOptions()
+	opts = opts.SetHostQueueOpsFlushInterval(time.Millisecond)
+	queue := newTestHostQueue(opts)
+	queue.connPool = mockConnPool
+	mockConnPool.EXPECT().Capabilities().
+		Return(tchannelthrift.CapabilityFetchBlocksMetadataRawV2).AnyTimes()
+
+	// Open
+	mockConnPool.EXPECT().Open()
+	queue.Open()
+	assert.Equal(t, statusOpen, queue.status)
+
+	// Prepare callback for writes
+	var (
+		results []hostQueueResult
+		wg      sync.WaitGroup
+	)
+	callback := func(r interface{}, err error) {
+		results = append(results, hostQueueResult{r, err})
+		wg.Done()
+	}
+
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions().SetSize(1))
+	encoderPool.Init()
+	encoder := encoderPool.Get()
+	require.NoError(t, encoder.Encode(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("tag", "value"),
+		ident.StringTag("sup", "holmes"),
+	))))
+	encoded, ok := encoder.Data()
+	require.True(t, ok)
+
+	write := testWriteTaggedOp("testNs", "foo", nil, 1.0, 1000,
+		rpc.TimeType_UNIX_SECONDS, callback)
+	write.request.EncodedTags = encoded.Bytes()
+	wg.Add(1)
+
+	// Prepare mocks for flush
+	mockClient := rpc.NewMockTChanNode(ctrl)
+	mockClient.EXPECT().WriteTagged(gomock.Any(), &rpc.WriteTaggedRequest{
+		NameSpace: "testNs",
+		ID:        "foo",
+		Tags: []*rpc.Tag{
+			{Name: "tag", Value: "value"},
+			{Name: "sup", Value: "holmes"},
+		},
+		Datapoint: write.request.Datapoint,
+	}).Return(nil)
+	mockConnPool.EXPECT().NextClient().Return(mockClient, nil)
+
+	assert.NoError(t, queue.Enqueue(write))
+
+	// Wait for background flush
+	wg.Wait()
+
+	require.Equal(t, 1, len(results))
+	assert.Nil(t, results[0].err)
+
+	// Close
+	var closeWg sync.WaitGroup
+	closeWg.Add(1)
+	mockConnPool.EXPECT().Close().Do(func() {
+		closeWg.Done()
+	})
+	queue.Close()
+	closeWg.Wait()
+}
+
 func testWriteTaggedOp(
 	namespace string,
 	id string,
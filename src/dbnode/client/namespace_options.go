@@ -0,0 +1,148 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
+	xretry "github.com/m3db/m3x/retry"
+)
+
+// NamespaceOptions are per namespace overrides of the session defaults,
+// any value left unset falls back to the session default.
+type NamespaceOptions struct {
+	// WriteConsistencyLevel overrides the write consistency level.
+	WriteConsistencyLevel *topology.ConsistencyLevel
+
+	// ReadConsistencyLevel overrides the read consistency level.
+	ReadConsistencyLevel *topology.ReadConsistencyLevel
+
+	// WriteRequestTimeout overrides the write request timeout.
+	WriteRequestTimeout time.Duration
+
+	// FetchRequestTimeout overrides the fetch request timeout.
+	FetchRequestTimeout time.Duration
+
+	// WriteRetrier overrides the write retrier.
+	WriteRetrier xretry.Retrier
+
+	// FetchRetrier overrides the fetch retrier.
+	FetchRetrier xretry.Retrier
+
+	// TagEncoderOptions overrides the options of the encoder the tags of
+	// tagged writes are encoded with, e.g. to allow longer tags.
+	TagEncoderOptions serialize.TagEncoderOptions
+}
+
+// Validate validates the namespace options.
+func (o NamespaceOptions) Validate() error {
+	if o.WriteConsistencyLevel != nil {
+		if err := topology.ValidateConsistencyLevel(*o.WriteConsistencyLevel); err != nil {
+			return err
+		}
+	}
+	if o.ReadConsistencyLevel != nil {
+		if err := topology.ValidateReadConsistencyLevel(*o.ReadConsistencyLevel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// namespaceOptionsResolver resolves the options to use for a namespace
+// falling back to the defaults when there is no override.
+type namespaceOptionsResolver struct {
+	opts   Options
+	byName map[string]NamespaceOptions
+}
+
+func newNamespaceOptionsResolver(opts Options) namespaceOptionsResolver {
+	return namespaceOptionsResolver{
+		opts:   opts,
+		byName: opts.NamespaceOptions(),
+	}
+}
+
+func (r namespaceOptionsResolver) get(namespace []byte) (NamespaceOptions, bool) {
+	if len(r.byName) == 0 {
+		return NamespaceOptions{}, false
+	}
+	// NB: string conversion in map index does not allocate.
+	o, ok := r.byName[string(namespace)]
+	return o, ok
+}
+
+func (r namespaceOptionsResolver) writeConsistencyLevel(
+	namespace ident.ID,
+	defaultLevel topology.ConsistencyLevel,
+) topology.ConsistencyLevel {
+	if o, ok := r.get(namespace.Bytes()); ok && o.WriteConsistencyLevel != nil {
+		return *o.WriteConsistencyLevel
+	}
+	return defaultLevel
+}
+
+func (r namespaceOptionsResolver) readConsistencyLevel(
+	namespace ident.ID,
+	defaultLevel topology.ReadConsistencyLevel,
+) topology.ReadConsistencyLevel {
+	if o, ok := r.get(namespace.Bytes()); ok && o.ReadConsistencyLevel != nil {
+		return *o.ReadConsistencyLevel
+	}
+	return defaultLevel
+}
+
+func (r namespaceOptionsResolver) writeRequestTimeout(namespace []byte) time.Duration {
+	if o, ok := r.get(namespace); ok && o.WriteRequestTimeout > 0 {
+		return o.WriteRequestTimeout
+	}
+	return r.opts.WriteRequestTimeout()
+}
+
+func (r namespaceOptionsResolver) fetchRequestTimeout(namespace []byte) time.Duration {
+	if o, ok := r.get(namespace); ok && o.FetchRequestTimeout > 0 {
+		return o.FetchRequestTimeout
+	}
+	return r.opts.FetchRequestTimeout()
+}
+
+func (r namespaceOptionsResolver) writeRetrier(
+	namespace ident.ID,
+	defaultRetrier xretry.Retrier,
+) xretry.Retrier {
+	if o, ok := r.get(namespace.Bytes()); ok && o.WriteRetrier != nil {
+		return o.WriteRetrier
+	}
+	return defaultRetrier
+}
+
+func (r namespaceOptionsResolver) fetchRetrier(
+	namespace ident.ID,
+	defaultRetrier xretry.Retrier,
+) xretry.Retrier {
+	if o, ok := r.get(namespace.Bytes()); ok && o.FetchRetrier != nil {
+		return o.FetchRetrier
+	}
+	return defaultRetrier
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
+	xretry "github.com/m3db/m3x/retry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceOptionsResolver(t *testing.T) {
+	writeLevel := topology.ConsistencyLevelAll
+	readLevel := topology.ReadConsistencyLevelOne
+	writeRetrier := xretry.NewRetrier(xretry.NewOptions().SetMaxRetries(5))
+	opts := NewOptions().
+		SetWriteRequestTimeout(time.Second).
+		SetFetchRequestTimeout(2 * time.Second).
+		SetNamespaceOptions(map[string]NamespaceOptions{
+			"metrics": {
+				WriteConsistencyLevel: &writeLevel,
+				ReadConsistencyLevel:  &readLevel,
+				WriteRequestTimeout:   10 * time.Second,
+				WriteRetrier:          writeRetrier,
+			},
+		})
+	require.NoError(t, opts.NamespaceOptions()["metrics"].Validate())
+
+	r := newNamespaceOptionsResolver(opts)
+	defaultRetrier := opts.WriteRetrier()
+
+	metrics := ident.StringID("metrics")
+	assert.Equal(t, writeLevel,
+		r.writeConsistencyLevel(metrics, topology.ConsistencyLevelMajority))
+	assert.Equal(t, readLevel,
+		r.readConsistencyLevel(metrics, topology.ReadConsistencyLevelMajority))
+	assert.Equal(t, 10*time.Second, r.writeRequestTimeout(metrics.Bytes()))
+	assert.Equal(t, 2*time.Second, r.fetchRequestTimeout(metrics.Bytes()))
+	assert.Equal(t, writeRetrier, r.writeRetrier(metrics, defaultRetrier))
+	assert.Equal(t, defaultRetrier, r.fetchRetrier(metrics, defaultRetrier))
+
+	other := ident.StringID("other")
+	assert.Equal(t, topology.ConsistencyLevelMajority,
+		r.writeConsistencyLevel(other, topology.ConsistencyLevelMajority))
+	assert.Equal(t, topology.ReadConsistencyLevelMajority,
+		r.readConsistencyLevel(other, topology.ReadConsistencyLevelMajority))
+	assert.Equal(t, time.Second, r.writeRequestTimeout(other.Bytes()))
+	assert.Equal(t, defaultRetrier, r.writeRetrier(other, defaultRetrier))
+}
+
+func TestNamespaceOptionsValidate(t *testing.T) {
+	invalid := topology.ConsistencyLevel(100)
+	opts := NamespaceOptions{WriteConsistencyLevel: &invalid}
+	assert.Error(t, opts.Validate())
+}
@@ -22,6 +22,7 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"runtime"
@@ -32,7 +33,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/dbnode/x/fault"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -82,6 +85,9 @@ const (
 	// defaultIdentifierPoolSize is the default identifier pool size
 	defaultIdentifierPoolSize = 8192
 
+	// defaultAsyncWriteWorkers is the default number of async write workers
+	defaultAsyncWriteWorkers = 4
+
 	// defaultWriteOpPoolSize is the default write op pool size
 	defaultWriteOpPoolSize = 65536
 
@@ -180,13 +186,16 @@ var (
 
 	errNoTopologyInitializerSet    = errors.New("no topology initializer set")
 	errNoReaderIteratorAllocateSet = errors.New("no reader iterator allocator set, encoding not set")
+	errNoCodecRegistrySet          = errors.New("no encoding codec registry set")
 )
 
 type options struct {
 	runtimeOptsMgr                          m3dbruntime.OptionsManager
+	faultInjector                           fault.Injector
 	clockOpts                               clock.Options
 	instrumentOpts                          instrument.Options
 	topologyInitializer                     topology.Initializer
+	namespaceInitializer                    namespace.Initializer
 	readConsistencyLevel                    topology.ReadConsistencyLevel
 	writeConsistencyLevel                   topology.ConsistencyLevel
 	bootstrapConsistencyLevel               topology.ReadConsistencyLevel
@@ -210,9 +219,15 @@ type options struct {
 	tagDecoderOpts                          serialize.TagDecoderOptions
 	tagDecoderPoolSize                      int
 	writeRetrier                            xretry.Retrier
+	asyncWriteQueueSize                     int
+	asyncWriteWorkers                       int
+	asyncWriteDropPolicy                    AsyncWriteDropPolicy
+	namespaceOpts                           map[string]NamespaceOptions
 	fetchRetrier                            xretry.Retrier
 	streamBlocksRetrier                     xretry.Retrier
 	readerIteratorAllocate                  encoding.ReaderIteratorAllocate
+	codecRegistry                           encoding.CodecRegistry
+	sortTaggedIDsTags                       bool
 	writeOperationPoolSize                  int
 	writeTaggedOperationPoolSize            int
 	fetchBatchOpPoolSize                    int
@@ -222,6 +237,7 @@ type options struct {
 	hostQueueOpsFlushSize                   int
 	hostQueueOpsFlushInterval               time.Duration
 	hostQueueOpsArrayPoolSize               int
+	hostQueueStatsFn                        HostQueueStatsFn
 	seriesIteratorPoolSize                  int
 	seriesIteratorArrayPoolBuckets          []pool.Bucket
 	checkedBytesWrapperPoolSize             int
@@ -266,6 +282,7 @@ func newOptions() *options {
 		SetFinalizerPoolOptions(poolOpts))
 
 	opts := &options{
+		faultInjector:                           fault.NewNoopInjector(),
 		clockOpts:                               clock.NewOptions(),
 		instrumentOpts:                          instrument.NewOptions(),
 		writeConsistencyLevel:                   defaultWriteConsistencyLevel,
@@ -286,6 +303,8 @@ func newOptions() *options {
 		backgroundHealthCheckFailLimit:          defaultBackgroundHealthCheckFailLimit,
 		backgroundHealthCheckFailThrottleFactor: defaultBackgroundHealthCheckFailThrottleFactor,
 		writeRetrier:                            defaultWriteRetrier,
+		asyncWriteWorkers:                       defaultAsyncWriteWorkers,
+		asyncWriteDropPolicy:                    AsyncWriteDropNewest,
 		fetchRetrier:                            defaultFetchRetrier,
 		tagEncoderPoolSize:                      defaultTagEncoderPoolSize,
 		tagEncoderOpts:                          serialize.NewTagEncoderOptions(),
@@ -321,6 +340,9 @@ func (o *options) Validate() error {
 	if o.readerIteratorAllocate == nil {
 		return errNoReaderIteratorAllocateSet
 	}
+	if o.codecRegistry == nil {
+		return errNoCodecRegistrySet
+	}
 	if err := topology.ValidateConsistencyLevel(
 		o.writeConsistencyLevel,
 	); err != nil {
@@ -336,6 +358,14 @@ func (o *options) Validate() error {
 	); err != nil {
 		return err
 	}
+	if err := ValidateAsyncWriteDropPolicy(o.asyncWriteDropPolicy); err != nil {
+		return err
+	}
+	for namespace, nsOpts := range o.namespaceOpts {
+		if err := nsOpts.Validate(); err != nil {
+			return fmt.Errorf("invalid options for namespace %s: %v", namespace, err)
+		}
+	}
 	return topology.ValidateConnectConsistencyLevel(
 		o.clusterConnectConsistencyLevel,
 	)
@@ -346,6 +376,12 @@ func (o *options) SetEncodingM3TSZ() Options {
 	opts.readerIteratorAllocate = func(r io.Reader) encoding.ReaderIterator {
 		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
 	}
+	codecRegistry, err := m3tsz.NewCodecRegistry()
+	if err != nil {
+		// NB: the m3tsz codecs have distinct names so this is an invariant.
+		panic(fmt.Errorf("unable to register m3tsz codecs: %v", err))
+	}
+	opts.codecRegistry = codecRegistry
 	return &opts
 }
 
@@ -359,6 +395,16 @@ func (o *options) RuntimeOptionsManager() m3dbruntime.OptionsManager {
 	return o.runtimeOptsMgr
 }
 
+func (o *options) SetFaultInjector(value fault.Injector) Options {
+	opts := *o
+	opts.faultInjector = value
+	return &opts
+}
+
+func (o *options) FaultInjector() fault.Injector {
+	return o.faultInjector
+}
+
 func (o *options) SetClockOptions(value clock.Options) Options {
 	opts := *o
 	opts.clockOpts = value
@@ -389,6 +435,16 @@ func (o *options) TopologyInitializer() topology.Initializer {
 	return o.topologyInitializer
 }
 
+func (o *options) SetNamespaceInitializer(value namespace.Initializer) Options {
+	opts := *o
+	opts.namespaceInitializer = value
+	return &opts
+}
+
+func (o *options) NamespaceInitializer() namespace.Initializer {
+	return o.namespaceInitializer
+}
+
 func (o *options) SetReadConsistencyLevel(value topology.ReadConsistencyLevel) Options {
 	opts := *o
 	opts.readConsistencyLevel = value
@@ -579,6 +635,49 @@ func (o *options) WriteRetrier() xretry.Retrier {
 	return o.writeRetrier
 }
 
+func (o *options) SetAsyncWriteQueueSize(value int) Options {
+	opts := *o
+	opts.asyncWriteQueueSize = value
+	return &opts
+}
+
+func (o *options) AsyncWriteQueueSize() int {
+	return o.asyncWriteQueueSize
+}
+
+func (o *options) SetAsyncWriteWorkers(value int) Options {
+	opts := *o
+	opts.asyncWriteWorkers = value
+	return &opts
+}
+
+func (o *options) AsyncWriteWorkers() int {
+	return o.asyncWriteWorkers
+}
+
+func (o *options) SetAsyncWriteDropPolicy(value AsyncWriteDropPolicy) Options {
+	opts := *o
+	opts.asyncWriteDropPolicy = value
+	return &opts
+}
+
+func (o *options) AsyncWriteDropPolicy() AsyncWriteDropPolicy {
+	return o.asyncWriteDropPolicy
+}
+
+func (o *options) SetNamespaceOptions(value map[string]NamespaceOptions) Options {
+	opts := *o
+	opts.namespaceOpts = make(map[string]NamespaceOptions, len(value))
+	for k, v := range value {
+		opts.namespaceOpts[k] = v
+	}
+	return &opts
+}
+
+func (o *options) NamespaceOptions() map[string]NamespaceOptions {
+	return o.namespaceOpts
+}
+
 func (o *options) SetFetchRetrier(value xretry.Retrier) Options {
 	opts := *o
 	opts.fetchRetrier = value
@@ -749,6 +848,16 @@ func (o *options) HostQueueOpsArrayPoolSize() int {
 	return o.hostQueueOpsArrayPoolSize
 }
 
+func (o *options) SetHostQueueStatsFn(value HostQueueStatsFn) Options {
+	opts := *o
+	opts.hostQueueStatsFn = value
+	return &opts
+}
+
+func (o *options) HostQueueStatsFn() HostQueueStatsFn {
+	return o.hostQueueStatsFn
+}
+
 func (o *options) SetSeriesIteratorPoolSize(value int) Options {
 	opts := *o
 	opts.seriesIteratorPoolSize = value
@@ -779,6 +888,26 @@ func (o *options) ReaderIteratorAllocate() encoding.ReaderIteratorAllocate {
 	return o.readerIteratorAllocate
 }
 
+func (o *options) SetEncodingCodecRegistry(value encoding.CodecRegistry) Options {
+	opts := *o
+	opts.codecRegistry = value
+	return &opts
+}
+
+func (o *options) EncodingCodecRegistry() encoding.CodecRegistry {
+	return o.codecRegistry
+}
+
+func (o *options) SetSortTaggedIDsTags(value bool) Options {
+	opts := *o
+	opts.sortTaggedIDsTags = value
+	return &opts
+}
+
+func (o *options) SortTaggedIDsTags() bool {
+	return o.sortTaggedIDsTags
+}
+
 func (o *options) SetOrigin(value topology.Host) AdminOptions {
 	opts := *o
 	opts.origin = value
@@ -0,0 +1,145 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+)
+
+const (
+	bytesPerMegabit = 1024 * 1024 / 8
+
+	// peerStreamThrottleWindowCheckInterval is how often to check whether
+	// streaming has entered an allowed window, runtime changes to the
+	// windows are observed at this interval.
+	peerStreamThrottleWindowCheckInterval = 10 * time.Second
+
+	// peerStreamThrottleIdleResetInterval is how long streaming can be idle
+	// before the rate limit measurement is restarted, so that idle time
+	// does not allow a burst above the rate limit.
+	peerStreamThrottleIdleResetInterval = 10 * time.Second
+)
+
+// peerStreamThrottle limits the throughput of blocks streamed from peers
+// and restricts streaming to the configured daily windows of time.
+type peerStreamThrottle struct {
+	sync.Mutex
+
+	nowFn   clock.NowFn
+	sleepFn clock.SleepFn
+
+	rateLimitOpts ratelimit.Options
+	windows       ratelimit.TimeOfDayWindows
+
+	start time.Time
+	last  time.Time
+	bytes int64
+	count int
+}
+
+func newPeerStreamThrottle(opts clock.Options) *peerStreamThrottle {
+	return &peerStreamThrottle{
+		nowFn:         opts.NowFn(),
+		sleepFn:       opts.SleepFn(),
+		rateLimitOpts: ratelimit.NewOptions(),
+	}
+}
+
+func (t *peerStreamThrottle) setOptions(
+	rateLimitOpts ratelimit.Options,
+	windows ratelimit.TimeOfDayWindows,
+) {
+	t.Lock()
+	t.rateLimitOpts = rateLimitOpts
+	t.windows = windows
+	t.resetWithLock()
+	t.Unlock()
+}
+
+func (t *peerStreamThrottle) resetWithLock() {
+	t.start = time.Time{}
+	t.last = time.Time{}
+	t.bytes = 0
+	t.count = 0
+}
+
+// waitForWindow blocks until the current time is within one of the
+// streaming windows, returning how long it waited.
+func (t *peerStreamThrottle) waitForWindow() time.Duration {
+	var waited time.Duration
+	for {
+		t.Lock()
+		inWindow := t.windows.Contains(t.nowFn())
+		if !inWindow {
+			// Restart the rate limit measurement once back in a window.
+			t.resetWithLock()
+		}
+		t.Unlock()
+		if inWindow {
+			return waited
+		}
+		t.sleepFn(peerStreamThrottleWindowCheckInterval)
+		waited += peerStreamThrottleWindowCheckInterval
+	}
+}
+
+// throttle records the bytes streamed and blocks for long enough to keep
+// the throughput under the rate limit, returning how long it blocked.
+func (t *peerStreamThrottle) throttle(bytes int) time.Duration {
+	t.Lock()
+	opts := t.rateLimitOpts
+	limitMbps := opts.LimitMbps()
+	if !opts.LimitEnabled() || limitMbps <= 0 {
+		t.Unlock()
+		return 0
+	}
+
+	now := t.nowFn()
+	if !t.last.IsZero() && now.Sub(t.last) > peerStreamThrottleIdleResetInterval {
+		// Restart the rate limit measurement after being idle.
+		t.resetWithLock()
+	}
+	if t.start.IsZero() {
+		t.start = now
+	}
+	t.bytes += int64(bytes)
+	t.count++
+
+	var wait time.Duration
+	if t.count >= opts.LimitCheckEvery() {
+		target := time.Duration(float64(time.Second) *
+			float64(t.bytes) / (limitMbps * bytesPerMegabit))
+		if elapsed := now.Sub(t.start); elapsed < target {
+			wait = target - elapsed
+		}
+		t.count = 0
+	}
+	t.last = now.Add(wait)
+	t.Unlock()
+
+	if wait > 0 {
+		t.sleepFn(wait)
+	}
+	return wait
+}
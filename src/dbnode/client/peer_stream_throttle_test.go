@@ -0,0 +1,92 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPeerStreamThrottle(start time.Time) (*peerStreamThrottle, *time.Time, *[]time.Duration) {
+	var (
+		now    = start
+		sleeps []time.Duration
+	)
+	opts := clock.NewOptions().
+		SetNowFn(func() time.Time { return now }).
+		SetSleepFn(func(d time.Duration) {
+			sleeps = append(sleeps, d)
+			now = now.Add(d)
+		})
+	return newPeerStreamThrottle(opts), &now, &sleeps
+}
+
+func TestPeerStreamThrottleRateLimit(t *testing.T) {
+	throttle, _, sleeps := newTestPeerStreamThrottle(time.Unix(0, 0))
+
+	// Disabled by default.
+	require.Equal(t, time.Duration(0), throttle.throttle(bytesPerMegabit))
+
+	throttle.setOptions(ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitMbps(1).
+		SetLimitCheckEvery(2), nil)
+
+	require.Equal(t, time.Duration(0), throttle.throttle(bytesPerMegabit))
+	require.Equal(t, 2*time.Second, throttle.throttle(bytesPerMegabit))
+	require.Equal(t, []time.Duration{2 * time.Second}, *sleeps)
+}
+
+func TestPeerStreamThrottleRateLimitResetsAfterIdle(t *testing.T) {
+	throttle, now, sleeps := newTestPeerStreamThrottle(time.Unix(0, 0))
+	throttle.setOptions(ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitMbps(1).
+		SetLimitCheckEvery(1), nil)
+
+	require.Equal(t, time.Second, throttle.throttle(bytesPerMegabit))
+
+	// Idle time must not be credited towards later streaming.
+	*now = now.Add(time.Minute)
+	require.Equal(t, time.Second, throttle.throttle(bytesPerMegabit))
+	require.Equal(t, []time.Duration{time.Second, time.Second}, *sleeps)
+}
+
+func TestPeerStreamThrottleWaitForWindow(t *testing.T) {
+	start := time.Date(2018, time.June, 1, 21, 59, 30, 0, time.UTC)
+	throttle, now, _ := newTestPeerStreamThrottle(start)
+
+	// No windows always allows streaming.
+	require.Equal(t, time.Duration(0), throttle.waitForWindow())
+
+	window, err := ratelimit.ParseTimeOfDayWindow("22:00-06:00", time.UTC)
+	require.NoError(t, err)
+	throttle.setOptions(ratelimit.NewOptions(), ratelimit.TimeOfDayWindows{window})
+
+	waited := throttle.waitForWindow()
+	require.Equal(t, 3*peerStreamThrottleWindowCheckInterval, waited)
+	require.True(t, window.Contains(*now))
+	require.Equal(t, time.Duration(0), throttle.waitForWindow())
+}
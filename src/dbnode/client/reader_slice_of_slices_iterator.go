@@ -23,6 +23,7 @@ package client
 import (
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -185,3 +186,31 @@ func (it *readerSliceOfSlicesIterator) Reset(segments []*rpc.Segments) {
 	it.idx = -1
 	it.closed = false
 }
+
+// segmentsEncoding returns the name of the codec the segments were encoded
+// with, it is empty for the default codec.
+func segmentsEncoding(segments []*rpc.Segments) string {
+	for _, s := range segments {
+		if s != nil && s.Encoding != nil {
+			return *s.Encoding
+		}
+	}
+	return ""
+}
+
+// newMultiReaderIteratorFromSegments returns a multi reader iterator that
+// reads the segments with the codec they were encoded with.
+func newMultiReaderIteratorFromSegments(
+	pools fetchTaggedPools,
+	segments []*rpc.Segments,
+) (encoding.MultiReaderIterator, error) {
+	multiIterPool, err := pools.CodecMultiReaderIterators().Pool(segmentsEncoding(segments))
+	if err != nil {
+		return nil, err
+	}
+	slicesIter := pools.ReaderSliceOfSlicesIterator().Get()
+	slicesIter.Reset(segments)
+	multiIter := multiIterPool.Get()
+	multiIter.ResetSliceOfSlices(slicesIter)
+	return multiIter, nil
+}
@@ -37,8 +37,10 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -151,6 +153,12 @@ type sessionState struct {
 	topoWatch      topology.MapWatch
 	replicas       int
 	majority       int
+
+	nsRegistry namespace.Registry
+	nsWatch    namespace.Watch
+	// shardRoutingTags are the shard routing tags of the namespaces that
+	// route series by the value of a tag rather than their ID.
+	shardRoutingTags map[string][]byte
 }
 
 type session struct {
@@ -175,6 +183,9 @@ type session struct {
 	streamBlocksBatchSize            int
 	streamBlocksMetadataBatchTimeout time.Duration
 	streamBlocksBatchTimeout         time.Duration
+	streamBlocksThrottle             *peerStreamThrottle
+	asyncWriter                      *asyncWriter
+	nsOpts                           namespaceOptionsResolver
 	metrics                          sessionMetrics
 }
 
@@ -225,6 +236,8 @@ type streamFromPeersMetrics struct {
 	fetchBlockRetriesRespError                        tally.Counter
 	fetchBlockRetriesConsistencyLevelNotAchievedError tally.Counter
 	blocksEnqueueChannel                              tally.Gauge
+	throttledOutsideWindow                            tally.Timer
+	throttledRateLimit                                tally.Timer
 }
 
 type hostQueueOpts struct {
@@ -258,12 +271,14 @@ func newSession(opts Options) (clientSession, error) {
 		opts:                 opts,
 		scope:                scope,
 		nowFn:                opts.ClockOptions().NowFn(),
+		streamBlocksThrottle: newPeerStreamThrottle(opts.ClockOptions()),
 		log:                  opts.InstrumentOptions().Logger(),
 		newHostQueueFn:       newHostQueue,
 		fetchBatchSize:       opts.FetchBatchSize(),
 		newPeerBlocksQueueFn: newPeerBlocksQueue,
 		writeRetrier:         opts.WriteRetrier(),
 		fetchRetrier:         opts.FetchRetrier(),
+		nsOpts:               newNamespaceOptionsResolver(opts),
 		pools: sessionPools{
 			context: opts.ContextPool(),
 			id:      opts.IdentifierPool(),
@@ -271,6 +286,11 @@ func newSession(opts Options) (clientSession, error) {
 		metrics: newSessionMetrics(scope),
 	}
 	s.reattemptStreamBlocksFromPeersFn = s.streamBlocksReattemptFromPeers
+	if size := opts.AsyncWriteQueueSize(); size > 0 {
+		s.asyncWriter = newAsyncWriter(size, opts.AsyncWriteWorkers(),
+			opts.AsyncWriteDropPolicy(), s.writeAsyncWrite,
+			scope.SubScope("async-write"))
+	}
 	s.pickBestPeerFn = s.streamBlocksPickBestPeer
 	writeAttemptPoolOpts := pool.NewObjectPoolOptions().
 		SetSize(opts.WriteOpPoolSize()).
@@ -304,6 +324,25 @@ func newSession(opts Options) (clientSession, error) {
 	s.pools.tagEncoder = serialize.NewTagEncoderPool(opts.TagEncoderOptions(), tagEncoderPoolOpts)
 	s.pools.tagEncoder.Init()
 
+	for ns, nsOpts := range opts.NamespaceOptions() {
+		if nsOpts.TagEncoderOptions == nil {
+			continue
+		}
+		if s.pools.namespaceTagEncoders == nil {
+			s.pools.namespaceTagEncoders = make(map[string]serialize.TagEncoderPool)
+		}
+		nsTagEncoderPoolOpts := tagEncoderPoolOpts.
+			SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(
+				scope.SubScope("tag-encoder-pool").Tagged(map[string]string{
+					"namespace": ns,
+				}),
+			))
+		nsTagEncoderPool := serialize.NewTagEncoderPool(nsOpts.TagEncoderOptions,
+			nsTagEncoderPoolOpts)
+		nsTagEncoderPool.Init()
+		s.pools.namespaceTagEncoders[ns] = nsTagEncoderPool
+	}
+
 	tagDecoderPoolOpts := pool.NewObjectPoolOptions().
 		SetSize(opts.TagDecoderPoolSize()).
 		SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(
@@ -344,15 +383,17 @@ func (s *session) SetRuntimeOptions(value runtime.Options) {
 	s.state.readLevel = value.ClientReadConsistencyLevel()
 	s.state.writeLevel = value.ClientWriteConsistencyLevel()
 	s.state.Unlock()
+	s.streamBlocksThrottle.setOptions(value.PeerStreamingRateLimitOptions(),
+		value.PeerStreamingWindows())
 }
 
-func (s *session) ShardID(id ident.ID) (uint32, error) {
+func (s *session) ShardID(namespace, id ident.ID) (uint32, error) {
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
 		return 0, errSessionStatusNotOpen
 	}
-	value := s.state.topoMap.ShardSet().Lookup(id)
+	value := s.lookupShardWithRLock(namespace, id)
 	s.state.RUnlock()
 	return value, nil
 }
@@ -408,6 +449,12 @@ func (s *session) newPeerMetadataStreamingProgressMetrics(
 			"reason": "consistency-level-not-achieved-error",
 		}).Counter("fetch-block-retries"),
 		blocksEnqueueChannel: scope.Gauge("fetch-blocks-enqueue-channel-length"),
+		throttledOutsideWindow: scope.Tagged(map[string]string{
+			"reason": "outside-window",
+		}).Timer("fetch-blocks-throttled"),
+		throttledRateLimit: scope.Tagged(map[string]string{
+			"reason": "rate-limit",
+		}).Timer("fetch-blocks-throttled"),
 	}
 	s.metrics.streamFromPeersMetrics[mKey] = m
 	s.metrics.Unlock()
@@ -449,15 +496,46 @@ func (s *session) nodesRespondingErrorsMetricIndex(respErrs int32) int32 {
 }
 
 func (s *session) Open() error {
+	// NB: wait for the namespaces to be available before acquiring the
+	// state lock since the registry may take some time to resolve.
+	var (
+		nsRegistry namespace.Registry
+		nsWatch    namespace.Watch
+	)
+	if nsInit := s.opts.NamespaceInitializer(); nsInit != nil {
+		registry, err := nsInit.Init()
+		if err != nil {
+			return err
+		}
+		nsWatch, err = registry.Watch()
+		if err != nil {
+			registry.Close()
+			return err
+		}
+
+		// Wait for the namespaces to be available
+		<-nsWatch.C()
+
+		nsRegistry = registry
+	}
+	closeNamespaces := func() {
+		if nsWatch != nil {
+			nsWatch.Close()
+			nsRegistry.Close()
+		}
+	}
+
 	s.state.Lock()
 	if s.state.status != statusNotOpen {
 		s.state.Unlock()
+		closeNamespaces()
 		return errSessionStatusNotInitial
 	}
 
 	watch, err := s.state.topo.Watch()
 	if err != nil {
 		s.state.Unlock()
+		closeNamespaces()
 		return err
 	}
 
@@ -469,11 +547,19 @@ func (s *session) Open() error {
 	queues, replicas, majority, err := s.hostQueues(topoMap, nil)
 	if err != nil {
 		s.state.Unlock()
+		watch.Close()
+		closeNamespaces()
 		return err
 	}
 	s.setTopologyWithLock(topoMap, queues, replicas, majority)
 	s.state.topoWatch = watch
 
+	if nsWatch != nil {
+		s.setShardRoutingTagsWithLock(nsWatch.Get())
+		s.state.nsRegistry = nsRegistry
+		s.state.nsWatch = nsWatch
+	}
+
 	// NB(r): Alloc pools that can take some time in Open, expectation
 	// is already that Open will take some time
 	writeOperationPoolOpts := pool.NewObjectPoolOptions().
@@ -540,6 +626,10 @@ func (s *session) Open() error {
 	s.state.status = statusOpen
 	s.state.Unlock()
 
+	if s.asyncWriter != nil {
+		s.asyncWriter.Start()
+	}
+
 	go func() {
 		for range watch.C() {
 			s.log.Info("received update for topology")
@@ -562,9 +652,64 @@ func (s *session) Open() error {
 		}
 	}()
 
+	if nsWatch != nil {
+		go func() {
+			for range nsWatch.C() {
+				s.log.Info("received update for namespaces")
+				s.state.Lock()
+				s.setShardRoutingTagsWithLock(nsWatch.Get())
+				s.state.Unlock()
+			}
+		}()
+	}
+
 	return nil
 }
 
+// tagEncoderPool returns the pool of the encoders the tags of tagged writes
+// to the namespace are encoded with.
+func (s *session) tagEncoderPool(namespace ident.ID) serialize.TagEncoderPool {
+	// NB: string conversion in map index does not allocate.
+	if p, ok := s.pools.namespaceTagEncoders[string(namespace.Bytes())]; ok {
+		return p
+	}
+	return s.pools.tagEncoder
+}
+
+func (s *session) setShardRoutingTagsWithLock(nsMap namespace.Map) {
+	tags := make(map[string][]byte)
+	if nsMap != nil {
+		for _, md := range nsMap.Metadatas() {
+			if tag := md.Options().ShardRoutingTag(); tag != "" {
+				tags[md.ID().String()] = []byte(tag)
+			}
+		}
+	}
+	s.state.shardRoutingTags = tags
+}
+
+// shardSetWithRLock returns the shard set used to look up the shard of the
+// series of a namespace, series are routed the same way as the nodes route
+// them, e.g. by the shard routing tag of the namespace.
+func (s *session) shardSetWithRLock(namespace ident.ID) sharding.ShardSet {
+	shardSet := s.state.topoMap.ShardSet()
+	// NB: string conversion in map index does not allocate.
+	if tag, ok := s.state.shardRoutingTags[string(namespace.Bytes())]; ok {
+		return sharding.NewTagRoutingShardSet(shardSet, tag)
+	}
+	return shardSet
+}
+
+// lookupShardWithRLock returns the shard of a series of a namespace without
+// allocating a shard set.
+func (s *session) lookupShardWithRLock(namespace, id ident.ID) uint32 {
+	shardSet := s.state.topoMap.ShardSet()
+	if tag, ok := s.state.shardRoutingTags[string(namespace.Bytes())]; ok {
+		return shardSet.Lookup(sharding.RoutingID(id, tag))
+	}
+	return shardSet.Lookup(id)
+}
+
 func (s *session) BorrowConnection(hostID string, fn withConnectionFn) error {
 	s.state.RLock()
 	unlocked := false
@@ -761,6 +906,15 @@ func (s *session) setTopologyWithLock(topoMap topology.Map, queues []hostQueue,
 		s.pools.multiReaderIterator = encoding.NewMultiReaderIteratorPool(poolOpts)
 		s.pools.multiReaderIterator.Init(s.opts.ReaderIteratorAllocate())
 	}
+	if s.pools.codecMultiReaderIterators == nil {
+		poolOpts := pool.NewObjectPoolOptions().
+			SetInstrumentOptions(s.opts.InstrumentOptions().SetMetricsScope(
+				s.scope.SubScope("codec-multi-reader-iterator-pool"),
+			))
+		s.pools.codecMultiReaderIterators = encoding.NewCodecMultiReaderIteratorPools(
+			s.opts.EncodingCodecRegistry(), s.pools.multiReaderIterator,
+			poolOpts, encoding.NewOptions())
+	}
 	if replicas > len(s.metrics.writeNodesRespondingErrors) {
 		curr := len(s.metrics.writeNodesRespondingErrors)
 		for i := curr; i < replicas; i++ {
@@ -863,15 +1017,8 @@ func (s *session) Write(
 	unit xtime.Unit,
 	annotation []byte,
 ) error {
-	w := s.pools.writeAttempt.Get()
-	w.args.attemptType = untaggedWriteAttemptType
-	w.args.namespace, w.args.id = namespace, id
-	w.args.tags = ident.EmptyTagIterator
-	w.args.t, w.args.value, w.args.unit, w.args.annotation =
-		t, value, unit, annotation
-	err := s.writeRetrier.Attempt(w.attemptFn)
-	s.pools.writeAttempt.Put(w)
-	return err
+	return s.write(untaggedWriteAttemptType, namespace, id,
+		ident.EmptyTagIterator, t, value, unit, annotation, "")
 }
 
 func (s *session) WriteTagged(
@@ -881,17 +1028,88 @@ func (s *session) WriteTagged(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
+) error {
+	return s.write(taggedWriteAttemptType, namespace, id,
+		tags, t, value, unit, annotation, "")
+}
+
+func (s *session) write(
+	wType writeAttemptType,
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	requestMetadata string,
+) error {
+	if s.asyncWriter != nil {
+		return s.enqueueAsyncWrite(wType, namespace, id,
+			tags, t, value, unit, annotation, requestMetadata)
+	}
+	return s.writeWithRetry(wType, namespace, id,
+		tags, t, value, unit, annotation, requestMetadata)
+}
+
+func (s *session) writeWithRetry(
+	wType writeAttemptType,
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	requestMetadata string,
 ) error {
 	w := s.pools.writeAttempt.Get()
-	w.args.attemptType = taggedWriteAttemptType
+	w.args.attemptType = wType
 	w.args.namespace, w.args.id, w.args.tags = namespace, id, tags
 	w.args.t, w.args.value, w.args.unit, w.args.annotation =
 		t, value, unit, annotation
-	err := s.writeRetrier.Attempt(w.attemptFn)
+	w.args.requestMetadata = requestMetadata
+	err := s.nsOpts.writeRetrier(namespace, s.writeRetrier).Attempt(w.attemptFn)
 	s.pools.writeAttempt.Put(w)
 	return err
 }
 
+// enqueueAsyncWrite copies and queues the write to be performed in the
+// background, the write is not guaranteed to succeed and failures are
+// only reflected in metrics.
+func (s *session) enqueueAsyncWrite(
+	wType writeAttemptType,
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	requestMetadata string,
+) error {
+	s.state.RLock()
+	status := s.state.status
+	s.state.RUnlock()
+	if status != statusOpen {
+		return errSessionStatusNotOpen
+	}
+
+	write, err := newAsyncWrite(wType, namespace, id, tags, t,
+		value, unit, annotation)
+	if err != nil {
+		return err
+	}
+	write.requestMetadata = requestMetadata
+	return s.asyncWriter.Enqueue(write)
+}
+
+func (s *session) writeAsyncWrite(w asyncWrite) error {
+	var tags ident.TagIterator = ident.EmptyTagIterator
+	if w.attemptType == taggedWriteAttemptType {
+		tags = ident.NewTagsIterator(w.tags)
+	}
+	return s.writeWithRetry(w.attemptType, w.namespace, w.id, tags,
+		w.t, w.value, w.unit, w.annotation, w.requestMetadata)
+}
+
 func (s *session) writeAttempt(
 	wType writeAttemptType,
 	namespace, id ident.ID,
@@ -900,6 +1118,7 @@ func (s *session) writeAttempt(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
+	requestMetadata string,
 ) error {
 	timeType, timeTypeErr := convert.ToTimeType(unit)
 	if timeTypeErr != nil {
@@ -918,7 +1137,8 @@ func (s *session) writeAttempt(
 	}
 
 	state, majority, enqueued, err := s.writeAttemptWithRLock(
-		wType, namespace, id, inputTags, timestamp, value, timeType, annotation)
+		wType, namespace, id, inputTags, timestamp, value, timeType, annotation,
+		requestMetadata)
 	s.state.RUnlock()
 
 	if err != nil {
@@ -953,6 +1173,7 @@ func (s *session) writeAttemptWithRLock(
 	value float64,
 	timeType rpc.TimeType,
 	annotation []byte,
+	requestMetadata string,
 ) (*writeState, int32, int32, error) {
 	var (
 		majority = int32(s.state.majority)
@@ -968,29 +1189,32 @@ func (s *session) writeAttemptWithRLock(
 	tsID := s.pools.id.Clone(id)
 	var tagEncoder serialize.TagEncoder
 	if wType == taggedWriteAttemptType {
-		tagEncoder = s.pools.tagEncoder.Get()
+		tagEncoder = s.tagEncoderPool(namespace).Get()
 		if err := tagEncoder.Encode(inputTags); err != nil {
 			tagEncoder.Finalize()
 			return nil, 0, 0, err
 		}
 	}
 
+	shardID := s.lookupShardWithRLock(namespace, tsID)
+
 	var op writeOp
 	switch wType {
 	case untaggedWriteAttemptType:
 		wop := s.pools.writeOperation.Get()
 		wop.namespace = nsID
-		wop.shardID = s.state.topoMap.ShardSet().Lookup(tsID)
+		wop.shardID = shardID
 		wop.request.ID = tsID.Bytes()
 		wop.request.Datapoint.Value = value
 		wop.request.Datapoint.Timestamp = timestamp
 		wop.request.Datapoint.TimestampTimeType = timeType
 		wop.request.Datapoint.Annotation = annotation
+		wop.requestMetadata = requestMetadata
 		op = wop
 	case taggedWriteAttemptType:
 		wop := s.pools.writeTaggedOperation.Get()
 		wop.namespace = nsID
-		wop.shardID = s.state.topoMap.ShardSet().Lookup(tsID)
+		wop.shardID = shardID
 		wop.request.ID = tsID.Bytes()
 		encodedTagBytes, ok := tagEncoder.Data()
 		if !ok {
@@ -1001,6 +1225,7 @@ func (s *session) writeAttemptWithRLock(
 		wop.request.Datapoint.Timestamp = timestamp
 		wop.request.Datapoint.TimestampTimeType = timeType
 		wop.request.Datapoint.Annotation = annotation
+		wop.requestMetadata = requestMetadata
 		op = wop
 	default:
 		// should never happen
@@ -1008,7 +1233,7 @@ func (s *session) writeAttemptWithRLock(
 	}
 
 	state := s.pools.writeState.Get()
-	state.consistencyLevel = s.state.writeLevel
+	state.consistencyLevel = s.nsOpts.writeConsistencyLevel(namespace, s.state.writeLevel)
 	state.topoMap = s.state.topoMap
 	state.incRef()
 
@@ -1017,7 +1242,7 @@ func (s *session) writeAttemptWithRLock(
 	state.nsID, state.tsID, state.tagEncoder = nsID, tsID, tagEncoder
 	op.SetCompletionFn(state.completionFn)
 
-	if err := s.state.topoMap.RouteForEach(tsID, func(idx int, host topology.Host) {
+	if err := s.state.topoMap.RouteShardForEach(shardID, func(idx int, host topology.Host) {
 		// Count pending write requests before we enqueue the completion fns,
 		// which rely on the count when executing
 		state.pending++
@@ -1051,9 +1276,19 @@ func (s *session) Fetch(
 	namespace ident.ID,
 	id ident.ID,
 	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterator, error) {
+	return s.fetch(namespace, id, startInclusive, endExclusive, "")
+}
+
+func (s *session) fetch(
+	namespace ident.ID,
+	id ident.ID,
+	startInclusive, endExclusive time.Time,
+	requestMetadata string,
 ) (encoding.SeriesIterator, error) {
 	tsIDs := ident.NewIDsIterator(id)
-	results, err := s.FetchIDs(namespace, tsIDs, startInclusive, endExclusive)
+	results, err := s.fetchIDs(namespace, tsIDs, startInclusive, endExclusive,
+		requestMetadata)
 	if err != nil {
 		return nil, err
 	}
@@ -1070,11 +1305,21 @@ func (s *session) FetchIDs(
 	namespace ident.ID,
 	ids ident.Iterator,
 	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterators, error) {
+	return s.fetchIDs(namespace, ids, startInclusive, endExclusive, "")
+}
+
+func (s *session) fetchIDs(
+	namespace ident.ID,
+	ids ident.Iterator,
+	startInclusive, endExclusive time.Time,
+	requestMetadata string,
 ) (encoding.SeriesIterators, error) {
 	f := s.pools.fetchAttempt.Get()
 	f.args.namespace, f.args.ids = namespace, ids
 	f.args.start, f.args.end = startInclusive, endExclusive
-	err := s.fetchRetrier.Attempt(f.attemptFn)
+	f.args.requestMetadata = requestMetadata
+	err := s.nsOpts.fetchRetrier(namespace, s.fetchRetrier).Attempt(f.attemptFn)
 	result := f.result
 	s.pools.fetchAttempt.Put(f)
 	return result, err
@@ -1083,23 +1328,30 @@ func (s *session) FetchIDs(
 func (s *session) FetchTagged(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
 ) (encoding.SeriesIterators, bool, error) {
+	iters, metadata, err := s.FetchTaggedWithMetadata(ns, q, opts)
+	return iters, metadata.Exhaustive, err
+}
+
+func (s *session) FetchTaggedWithMetadata(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (encoding.SeriesIterators, FetchTaggedResultMetadata, error) {
 	f := s.pools.fetchTaggedAttempt.Get()
 	f.args.ns = ns
 	f.args.query = q
 	f.args.opts = opts
-	err := s.fetchRetrier.Attempt(f.dataAttemptFn)
-	iters, exhaustive := f.dataResultIters, f.dataResultExhaustive
+	err := s.nsOpts.fetchRetrier(ns, s.fetchRetrier).Attempt(f.dataAttemptFn)
+	iters, metadata := f.dataResultIters, f.dataResultMetadata
 	s.pools.fetchTaggedAttempt.Put(f)
-	return iters, exhaustive, err
+	return iters, metadata, err
 }
 
 func (s *session) fetchTaggedAttempt(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
-) (encoding.SeriesIterators, bool, error) {
+) (encoding.SeriesIterators, FetchTaggedResultMetadata, error) {
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, FetchTaggedResultMetadata{}, errSessionStatusNotOpen
 	}
 
 	const fetchData = true
@@ -1107,7 +1359,7 @@ func (s *session) fetchTaggedAttempt(
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, FetchTaggedResultMetadata{}, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1117,13 +1369,13 @@ func (s *session) fetchTaggedAttempt(
 	// must Unlock before calling `asEncodingSeriesIterators` as the latter needs to acquire
 	// the fetchState Lock
 	fetchState.Unlock()
-	iters, exhaustive, err := fetchState.asEncodingSeriesIterators(s.pools)
+	iters, metadata, err := fetchState.asEncodingSeriesIterators(s.pools)
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
-	return iters, exhaustive, err
+	return iters, metadata, err
 }
 
 func (s *session) FetchTaggedIDs(
@@ -1133,7 +1385,7 @@ func (s *session) FetchTaggedIDs(
 	f.args.ns = ns
 	f.args.query = q
 	f.args.opts = opts
-	err := s.fetchRetrier.Attempt(f.idsAttemptFn)
+	err := s.nsOpts.fetchRetrier(ns, s.fetchRetrier).Attempt(f.idsAttemptFn)
 	iter, exhaustive := f.idsResultIter, f.idsResultExhaustive
 	s.pools.fetchTaggedAttempt.Put(f)
 	return iter, exhaustive, err
@@ -1172,6 +1424,53 @@ func (s *session) fetchTaggedIDsAttempt(
 	return iter, exhaustive, err
 }
 
+func (s *session) FetchTaggedCount(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (int, bool, error) {
+	opts.CountOnly = true
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	err := s.nsOpts.fetchRetrier(ns, s.fetchRetrier).Attempt(f.countAttemptFn)
+	count, exhaustive := f.countResult, f.countResultExhaustive
+	s.pools.fetchTaggedAttempt.Put(f)
+	return count, exhaustive, err
+}
+
+func (s *session) fetchTaggedCountAttempt(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (int, bool, error) {
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return 0, false, errSessionStatusNotOpen
+	}
+
+	const fetchData = false
+	fetchState, err := s.fetchTaggedAttemptWithRLock(ns, q, opts, fetchData)
+	s.state.RUnlock()
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
+	// returned from fetchTaggedAttemptWithRLock.
+	fetchState.Wait()
+
+	// must Unlock before calling `asCount` as the latter needs to acquire
+	// the fetchState Lock
+	fetchState.Unlock()
+	count, exhaustive, err := fetchState.asCount()
+
+	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
+	// pool if ref count == 0.
+	fetchState.decRef()
+
+	return count, exhaustive, err
+}
+
 // NB(prateek): the returned fetchState, if valid, still holds the lock. Its ownership
 // is transferred to the calling function, and is expected to manage the lifecycle of
 // of the object (including releasing the lock/decRef'ing it).
@@ -1205,8 +1504,14 @@ func (s *session) fetchTaggedAttemptWithRLock(
 	fetchState.incRef()       // indicate current go-routine has a reference to the fetchState
 	op.incRef()               // indicate current go-routine has a reference to the op
 	op.update(req, fetchState.completionFn)
+	op.requestMetadata = opts.RequestMetadata
 
-	fetchState.Reset(opts.StartInclusive, opts.EndExclusive, op, topoMap, s.state.majority, s.state.readLevel)
+	var (
+		readLevel = s.nsOpts.readConsistencyLevel(ns, s.state.readLevel)
+		shardSet  = s.shardSetWithRLock(ns)
+	)
+	fetchState.Reset(opts.StartInclusive, opts.EndExclusive, op, topoMap,
+		shardSet, s.state.majority, readLevel, s.opts.SortTaggedIDsTags())
 	fetchState.Lock()
 	for _, hq := range s.state.queues {
 		// inc to indicate the hostQueue has a reference to `op` which has a ref to the fetchState
@@ -1236,6 +1541,7 @@ func (s *session) fetchIDsAttempt(
 	inputNamespace ident.ID,
 	inputIDs ident.Iterator,
 	startInclusive, endExclusive time.Time,
+	requestMetadata string,
 ) (encoding.SeriesIterators, error) {
 	var (
 		wg                     sync.WaitGroup
@@ -1294,7 +1600,7 @@ func (s *session) fetchIDsAttempt(
 	// while it is filling.
 	fetchBatchOpsByHostIdx = s.pools.fetchBatchOpArrayArray.Get()
 
-	consistencyLevel = s.state.readLevel
+	consistencyLevel = s.nsOpts.readConsistencyLevel(namespace, s.state.readLevel)
 	majority = int32(s.state.majority)
 
 	// NB(prateek): namespaceAccessors tracks the number of pending accessors for nsID.
@@ -1376,7 +1682,14 @@ func (s *session) fetchIDsAttempt(
 			wg.Done()
 		}
 		completionFn := func(result interface{}, err error) {
-			var snapshotSuccess int32
+			var (
+				snapshotSuccess int32
+				multiIter       encoding.MultiReaderIterator
+			)
+			if err == nil {
+				multiIter, err = newMultiReaderIteratorFromSegments(s.pools,
+					result.([]*rpc.Segments))
+			}
 			if err != nil {
 				atomic.AddInt32(&errs, 1)
 				// NB(r): reuse the error lock here as we do not want to create
@@ -1388,10 +1701,6 @@ func (s *session) fetchIDsAttempt(
 				errors = append(errors, err)
 				resultErrLock.Unlock()
 			} else {
-				slicesIter := s.pools.readerSliceOfSlicesIterator.Get()
-				slicesIter.Reset(result.([]*rpc.Segments))
-				multiIter := s.pools.multiReaderIterator.Get()
-				multiIter.ResetSliceOfSlices(slicesIter)
 				// Results is pre-allocated after creating fetch ops for this ID below
 				resultsLock.Lock()
 				results[success] = multiIter
@@ -1404,7 +1713,7 @@ func (s *session) fetchIDsAttempt(
 			// to iter.Reset down below before setting the iterator in the results array,
 			// which would cause a nil pointer exception.
 			remaining := atomic.AddInt32(&pending, -1)
-			shouldTerminate := topology.ReadConsistencyTermination(s.state.readLevel, majority, remaining, snapshotSuccess)
+			shouldTerminate := topology.ReadConsistencyTermination(consistencyLevel, majority, remaining, snapshotSuccess)
 			if shouldTerminate && atomic.CompareAndSwapInt32(&wgIsDone, 0, 1) {
 				allCompletionFn()
 			}
@@ -1420,7 +1729,8 @@ func (s *session) fetchIDsAttempt(
 			}
 		}
 
-		if err := s.state.topoMap.RouteForEach(tsID, func(hostIdx int, host topology.Host) {
+		shardID := s.lookupShardWithRLock(namespace, tsID)
+		if err := s.state.topoMap.RouteShardForEach(shardID, func(hostIdx int, host topology.Host) {
 			// Inc safely as this for each is sequential
 			enqueued++
 			pending++
@@ -1447,6 +1757,7 @@ func (s *session) fetchIDsAttempt(
 				f.request.RangeStart = rangeStart
 				f.request.RangeEnd = rangeEnd
 				f.request.RangeTimeType = rpc.TimeType_UNIX_NANOSECONDS
+				f.requestMetadata = requestMetadata
 			}
 
 			// Append IDWithNamespace to this request
@@ -1535,7 +1846,34 @@ func (s *session) IteratorPools() (encoding.IteratorPools, error) {
 	return s.pools, nil
 }
 
+func (s *session) Backpressure() (float64, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.status != statusOpen {
+		return 0, errSessionStatusNotOpen
+	}
+	var max float64
+	for _, q := range s.state.queues {
+		if value := q.Stats().Backpressure(); value > max {
+			max = value
+		}
+	}
+	return max, nil
+}
+
 func (s *session) Close() error {
+	s.state.RLock()
+	status := s.state.status
+	s.state.RUnlock()
+	if status != statusOpen {
+		return errSessionStatusNotOpen
+	}
+
+	// Flush any queued async writes while the session is still open
+	if s.asyncWriter != nil {
+		s.asyncWriter.Close()
+	}
+
 	s.state.Lock()
 	if s.state.status != statusOpen {
 		s.state.Unlock()
@@ -1545,6 +1883,8 @@ func (s *session) Close() error {
 	queues := s.state.queues
 	topoWatch := s.state.topoWatch
 	topo := s.state.topo
+	nsWatch := s.state.nsWatch
+	nsRegistry := s.state.nsRegistry
 	s.state.Unlock()
 
 	for _, q := range queues {
@@ -1554,6 +1894,11 @@ func (s *session) Close() error {
 	topoWatch.Close()
 	topo.Close()
 
+	if nsWatch != nil {
+		nsWatch.Close()
+		nsRegistry.Close()
+	}
+
 	if closer := s.runtimeOptsListenerCloser; closer != nil {
 		closer.Close()
 	}
@@ -2829,8 +3174,7 @@ func (s *session) streamBlocksBatchFromPeer(
 
 		nowFn              = opts.ClockOptions().NowFn()
 		ropts              = namespaceMetadata.Options().RetentionOptions()
-		retention          = ropts.RetentionPeriod()
-		earliestBlockStart = nowFn().Add(-retention).Truncate(ropts.BlockSize())
+		earliestBlockStart = retention.FlushTimeStart(ropts, nowFn())
 	)
 	req.NameSpace = namespaceMetadata.ID().Bytes()
 	req.Shard = int32(shard)
@@ -2851,6 +3195,11 @@ func (s *session) streamBlocksBatchFromPeer(
 		return
 	}
 
+	// Only stream within the allowed windows of time
+	if waited := s.streamBlocksThrottle.waitForWindow(); waited > 0 {
+		m.throttledOutsideWindow.Record(waited)
+	}
+
 	// Attempt request
 	if err := retrier.Attempt(func() error {
 		var attemptErr error
@@ -2879,6 +3228,11 @@ func (s *session) streamBlocksBatchFromPeer(
 		return
 	}
 
+	// Keep the streaming throughput under the rate limit
+	if throttled := s.streamBlocksThrottle.throttle(fetchBlocksRawResultSize(result)); throttled > 0 {
+		m.throttledRateLimit.Record(throttled)
+	}
+
 	// Parse and act on result
 	tooManyIDsLogged := false
 	for i := range result.Elements {
@@ -2979,6 +3333,24 @@ func (s *session) streamBlocksBatchFromPeer(
 	}
 }
 
+func fetchBlocksRawResultSize(result *rpc.FetchBlocksRawResult_) int {
+	size := 0
+	for _, elem := range result.Elements {
+		for _, block := range elem.Blocks {
+			if block.Segments == nil {
+				continue
+			}
+			if merged := block.Segments.Merged; merged != nil {
+				size += len(merged.Head) + len(merged.Tail)
+			}
+			for _, unmerged := range block.Segments.Unmerged {
+				size += len(unmerged.Head) + len(unmerged.Tail)
+			}
+		}
+	}
+	return size
+}
+
 func (s *session) verifyFetchedBlock(block *rpc.Block) error {
 	if block.Err != nil {
 		return fmt.Errorf("block error from peer: %s %s", block.Err.Type.String(), block.Err.Message)
@@ -3129,6 +3501,15 @@ type baseBlocksResult struct {
 	contextPool             context.Pool
 	encoderPool             encoding.EncoderPool
 	multiReaderIteratorPool encoding.MultiReaderIteratorPool
+	resultOpts              result.Options
+	codecBlockOpts          *codecBlockOptions
+}
+
+// codecBlockOptions caches the block options used to merge segments that
+// were encoded with a codec other than the default.
+type codecBlockOptions struct {
+	sync.Mutex
+	byName map[string]block.Options
 }
 
 func newBaseBlocksResult(
@@ -3142,9 +3523,36 @@ func newBaseBlocksResult(
 		contextPool:             opts.ContextPool(),
 		encoderPool:             blockOpts.EncoderPool(),
 		multiReaderIteratorPool: blockOpts.MultiReaderIteratorPool(),
+		resultOpts:              resultOpts,
+		codecBlockOpts: &codecBlockOptions{
+			byName: make(map[string]block.Options),
+		},
 	}
 }
 
+// pools returns the encoder and multi reader iterator pools that encode and
+// decode with the named codec, the default pools are returned if it is empty.
+func (b *baseBlocksResult) pools(
+	codecName string,
+) (encoding.EncoderPool, encoding.MultiReaderIteratorPool, error) {
+	if codecName == "" {
+		return b.encoderPool, b.multiReaderIteratorPool, nil
+	}
+
+	b.codecBlockOpts.Lock()
+	defer b.codecBlockOpts.Unlock()
+	blockOpts, ok := b.codecBlockOpts.byName[codecName]
+	if !ok {
+		var err error
+		blockOpts, err = result.NamespaceDatabaseBlockOptions(b.resultOpts, codecName)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.codecBlockOpts.byName[codecName] = blockOpts
+	}
+	return blockOpts.EncoderPool(), blockOpts.MultiReaderIteratorPool(), nil
+}
+
 func (b *baseBlocksResult) segmentForBlock(seg *rpc.Segment) ts.Segment {
 	var (
 		bytesPool  = b.blockOpts.BytesPool()
@@ -3165,12 +3573,22 @@ func (b *baseBlocksResult) segmentForBlock(seg *rpc.Segment) ts.Segment {
 	return ts.NewSegment(head, tail, ts.FinalizeHead&ts.FinalizeTail)
 }
 
-func (b *baseBlocksResult) mergeReaders(start time.Time, blockSize time.Duration, readers []xio.SegmentReader) (encoding.Encoder, error) {
-	iter := b.multiReaderIteratorPool.Get()
+func (b *baseBlocksResult) mergeReaders(
+	start time.Time,
+	blockSize time.Duration,
+	codecName string,
+	readers []xio.SegmentReader,
+) (encoding.Encoder, error) {
+	encoderPool, multiReaderIteratorPool, err := b.pools(codecName)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := multiReaderIteratorPool.Get()
 	iter.Reset(readers, start, blockSize)
 	defer iter.Close()
 
-	encoder := b.encoderPool.Get()
+	encoder := encoderPool.Get()
 	encoder.Reset(start, b.blockAllocSize)
 
 	for iter.Next() {
@@ -3188,6 +3606,15 @@ func (b *baseBlocksResult) mergeReaders(start time.Time, blockSize time.Duration
 	return encoder, nil
 }
 
+// blockEncoding returns the name of the codec the block segments were
+// encoded with, it is empty for the default codec.
+func blockEncoding(block *rpc.Block) string {
+	if block.Segments == nil || block.Segments.Encoding == nil {
+		return ""
+	}
+	return *block.Segments.Encoding
+}
+
 func (b *baseBlocksResult) newDatabaseBlock(block *rpc.Block) (block.DatabaseBlock, error) {
 	var (
 		start    = time.Unix(0, block.Start)
@@ -3222,7 +3649,7 @@ func (b *baseBlocksResult) newDatabaseBlock(block *rpc.Block) (block.DatabaseBlo
 				blockSize = bs
 			}
 		}
-		encoder, err := b.mergeReaders(start, blockSize, readers)
+		encoder, err := b.mergeReaders(start, blockSize, blockEncoding(block), readers)
 		for _, reader := range readers {
 			// Close each reader
 			reader.Finalize()
@@ -3437,7 +3864,7 @@ func (r *bulkBlocksResult) addBlockFromPeer(
 		readers := []xio.SegmentReader{currReader.SegmentReader, resultReader.SegmentReader}
 		blockSize := currReader.BlockSize
 
-		encoder, err := r.mergeReaders(start, blockSize, readers)
+		encoder, err := r.mergeReaders(start, blockSize, blockEncoding(block), readers)
 
 		if err != nil {
 			return err
@@ -1813,6 +1813,77 @@ func TestBlocksResultAddBlockFromPeerReadUnmerged(t *testing.T) {
 	assert.NoError(t, iter.Err())
 }
 
+func TestBlocksResultAddBlockFromPeerReadUnmergedCodecEncoding(t *testing.T) {
+	var (
+		opts   = newSessionTestAdminOptions()
+		bopts  = result.NewOptions()
+		codec  = m3tsz.NewIntDeltaCodec()
+		eopts  = encoding.NewOptions()
+		start  = time.Now().Truncate(time.Second)
+		values = []testValue{
+			{1.0, start, xtime.Second, nil},
+			{2.0, start.Add(1 * time.Second), xtime.Second, nil},
+			{3.0, start.Add(2 * time.Second), xtime.Second, nil},
+		}
+	)
+
+	encodingName := codec.Name()
+	bl := &rpc.Block{
+		Start:    start.UnixNano(),
+		Segments: &rpc.Segments{Encoding: &encodingName},
+	}
+	for _, val := range values {
+		encoder := codec.NewEncoder(start, nil, eopts)
+		dp := ts.Datapoint{Timestamp: val.t, Value: val.value}
+		require.NoError(t, encoder.Encode(dp, val.unit, val.annotation))
+		result := encoder.Discard()
+		seg := &rpc.Segment{Head: result.Head.Bytes(), Tail: result.Tail.Bytes()}
+		bl.Segments.Unmerged = append(bl.Segments.Unmerged, seg)
+	}
+
+	r := newBulkBlocksResult(opts, bopts, testTagDecodingPool, testIDPool)
+	require.NoError(t, r.addBlockFromPeer(fooID, fooTags, testHost, bl))
+
+	sl, ok := r.result.AllSeries().Get(fooID)
+	require.True(t, ok)
+	result, ok := sl.Blocks.BlockAt(start)
+	require.True(t, ok)
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	stream, err := result.Stream(ctx)
+	require.NoError(t, err)
+
+	iter := codec.NewReaderIterator(stream, eopts)
+	defer iter.Close()
+	asserted := 0
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		assert.Equal(t, values[asserted].value, dp.Value)
+		assert.True(t, values[asserted].t.Equal(dp.Timestamp))
+		asserted++
+	}
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, len(values), asserted)
+}
+
+func TestBlocksResultAddBlockFromPeerErrorOnUnknownEncoding(t *testing.T) {
+	opts := newSessionTestAdminOptions()
+	bopts := result.NewOptions()
+	r := newBulkBlocksResult(opts, bopts, testTagDecodingPool, testIDPool)
+
+	encodingName := "unknown"
+	bl := &rpc.Block{
+		Start: time.Now().UnixNano(),
+		Segments: &rpc.Segments{
+			Encoding: &encodingName,
+			Unmerged: []*rpc.Segment{&rpc.Segment{}},
+		},
+	}
+	assert.Error(t, r.addBlockFromPeer(fooID, fooTags, testHost, bl))
+}
+
 // TODO: add test TestBlocksResultAddBlockFromPeerMergeExistingResult
 
 func TestBlocksResultAddBlockFromPeerErrorOnNoSegments(t *testing.T) {
@@ -39,9 +39,11 @@ type sessionPools struct {
 	fetchState                  fetchStatePool
 	multiReaderIteratorArray    encoding.MultiReaderIteratorArrayPool
 	tagEncoder                  serialize.TagEncoderPool
+	namespaceTagEncoders        map[string]serialize.TagEncoderPool
 	tagDecoder                  serialize.TagDecoderPool
 	readerSliceOfSlicesIterator *readerSliceOfSlicesIteratorPool
 	multiReaderIterator         encoding.MultiReaderIteratorPool
+	codecMultiReaderIterators   encoding.CodecMultiReaderIteratorPools
 	seriesIterator              encoding.SeriesIteratorPool
 	seriesIterators             encoding.MutableSeriesIteratorsPool
 	writeAttempt                *writeAttemptPool
@@ -82,6 +84,10 @@ func (s sessionPools) MultiReaderIterator() encoding.MultiReaderIteratorPool {
 	return s.multiReaderIterator
 }
 
+func (s sessionPools) CodecMultiReaderIterators() encoding.CodecMultiReaderIteratorPools {
+	return s.codecMultiReaderIterators
+}
+
 func (s sessionPools) CheckedBytesWrapper() xpool.CheckedBytesWrapperPool {
 	return s.checkedBytesWrapper
 }
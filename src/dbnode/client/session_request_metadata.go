@@ -0,0 +1,120 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package client
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+func (s *session) WithRequestMetadata(requestMetadata string) Session {
+	return &requestMetadataSession{session: s, requestMetadata: requestMetadata}
+}
+
+// requestMetadataSession is a view of a session that attaches request
+// metadata to each of its writes and fetches, closing it closes the
+// underlying session.
+type requestMetadataSession struct {
+	*session
+
+	requestMetadata string
+}
+
+func (s *requestMetadataSession) WithRequestMetadata(requestMetadata string) Session {
+	return s.session.WithRequestMetadata(requestMetadata)
+}
+
+func (s *requestMetadataSession) Write(
+	namespace, id ident.ID,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	return s.session.write(untaggedWriteAttemptType, namespace, id,
+		ident.EmptyTagIterator, t, value, unit, annotation, s.requestMetadata)
+}
+
+func (s *requestMetadataSession) WriteTagged(
+	namespace, id ident.ID,
+	tags ident.TagIterator,
+	t time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	return s.session.write(taggedWriteAttemptType, namespace, id,
+		tags, t, value, unit, annotation, s.requestMetadata)
+}
+
+func (s *requestMetadataSession) Fetch(
+	namespace ident.ID,
+	id ident.ID,
+	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterator, error) {
+	return s.session.fetch(namespace, id, startInclusive, endExclusive,
+		s.requestMetadata)
+}
+
+func (s *requestMetadataSession) FetchIDs(
+	namespace ident.ID,
+	ids ident.Iterator,
+	startInclusive, endExclusive time.Time,
+) (encoding.SeriesIterators, error) {
+	return s.session.fetchIDs(namespace, ids, startInclusive, endExclusive,
+		s.requestMetadata)
+}
+
+func (s *requestMetadataSession) FetchTagged(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (encoding.SeriesIterators, bool, error) {
+	return s.session.FetchTagged(ns, q, s.queryOptions(opts))
+}
+
+func (s *requestMetadataSession) FetchTaggedWithMetadata(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (encoding.SeriesIterators, FetchTaggedResultMetadata, error) {
+	return s.session.FetchTaggedWithMetadata(ns, q, s.queryOptions(opts))
+}
+
+func (s *requestMetadataSession) FetchTaggedIDs(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (TaggedIDsIterator, bool, error) {
+	return s.session.FetchTaggedIDs(ns, q, s.queryOptions(opts))
+}
+
+func (s *requestMetadataSession) FetchTaggedCount(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (int, bool, error) {
+	return s.session.FetchTaggedCount(ns, q, s.queryOptions(opts))
+}
+
+// queryOptions returns the query options with the request metadata unless
+// the caller set its own.
+func (s *requestMetadataSession) queryOptions(opts index.QueryOptions) index.QueryOptions {
+	if opts.RequestMetadata == "" {
+		opts.RequestMetadata = s.requestMetadata
+	}
+	return opts
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3cluster/shard"
@@ -136,7 +137,7 @@ func TestSessionShardID(t *testing.T) {
 	s, err := newSession(opts)
 	assert.NoError(t, err)
 
-	_, err = s.ShardID(ident.StringID("foo"))
+	_, err = s.ShardID(ident.StringID("ns"), ident.StringID("foo"))
 	assert.Error(t, err)
 	assert.Equal(t, errSessionStatusNotOpen, err)
 
@@ -145,13 +146,64 @@ func TestSessionShardID(t *testing.T) {
 	require.NoError(t, s.Open())
 
 	// The shard set we create in newSessionTestOptions always hashes to uint32
-	shard, err := s.ShardID(ident.StringID("foo"))
+	shard, err := s.ShardID(ident.StringID("ns"), ident.StringID("foo"))
 	require.NoError(t, err)
 	assert.Equal(t, uint32(0), shard)
 
 	assert.NoError(t, s.Close())
 }
 
+func TestSessionShardRoutingTagsFromNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	md, err := namespace.NewMetadata(ident.StringID("metrics"),
+		namespace.NewOptions().SetShardRoutingTag("region"))
+	require.NoError(t, err)
+
+	opts := newSessionTestOptions().
+		SetNamespaceInitializer(namespace.NewStaticInitializer(
+			[]namespace.Metadata{md}))
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+
+	mockHostQueues(ctrl, s.(*session), sessionTestReplicas, nil)
+
+	require.NoError(t, s.Open())
+
+	session := s.(*session)
+	session.state.RLock()
+	assert.Equal(t, map[string][]byte{"metrics": []byte("region")},
+		session.state.shardRoutingTags)
+	session.state.RUnlock()
+
+	assert.NoError(t, s.Close())
+}
+
+func TestSessionNamespaceTagEncoderOptions(t *testing.T) {
+	limits := serialize.NewTagSerializationLimits().SetMaxTagLiteralLength(4)
+	opts := newSessionTestOptions().
+		SetNamespaceOptions(map[string]NamespaceOptions{
+			"metrics": {
+				TagEncoderOptions: serialize.NewTagEncoderOptions().
+					SetTagSerializationLimits(limits),
+			},
+		})
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	tags := ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value")))
+
+	enc := session.tagEncoderPool(ident.StringID("metrics")).Get()
+	require.Error(t, enc.Encode(tags.Duplicate()))
+	enc.Finalize()
+
+	enc = session.tagEncoderPool(ident.StringID("other")).Get()
+	require.NoError(t, enc.Encode(tags.Duplicate()))
+	enc.Finalize()
+}
+
 func TestSessionClusterConnectConsistencyLevelAll(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
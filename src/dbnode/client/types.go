@@ -26,6 +26,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -33,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/dbnode/x/fault"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -75,21 +77,54 @@ type Session interface {
 	// FetchTagged resolves the provided query to known IDs, and fetches the data for them.
 	FetchTagged(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error)
 
+	// FetchTaggedWithMetadata resolves the provided query to known IDs, and fetches the data
+	// for them, returning metadata describing how complete the results are.
+	FetchTaggedWithMetadata(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, metadata FetchTaggedResultMetadata, err error)
+
 	// FetchTaggedIDs resolves the provided query to known IDs.
 	FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error)
 
-	// ShardID returns the given shard for an ID for callers
+	// FetchTaggedCount resolves the provided query to the number of matching IDs.
+	FetchTaggedCount(namespace ident.ID, q index.Query, opts index.QueryOptions) (count int, exhaustive bool, err error)
+
+	// ShardID returns the given shard for an ID of a namespace for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing
-	ShardID(id ident.ID) (uint32, error)
+	ShardID(namespace, id ident.ID) (uint32, error)
 
 	// IteratorPools exposes the internal iterator pools used by the session to clients
 	IteratorPools() (encoding.IteratorPools, error)
 
+	// Backpressure returns the highest backpressure of the queues to the
+	// hosts, see HostQueueStats.Backpressure, so that callers can shed load
+	// before writes and fetches start timing out.
+	Backpressure() (float64, error)
+
+	// WithRequestMetadata returns a view of the session that sends the opaque
+	// request metadata, such as a request ID, with each write and fetch so
+	// that nodes include it in their slow query logs and errors. Closing the
+	// view closes the session.
+	WithRequestMetadata(requestMetadata string) Session
+
 	// Close the session
 	Close() error
 }
 
+// FetchTaggedResultMetadata describes how complete the results of a fetch
+// tagged request are.
+type FetchTaggedResultMetadata struct {
+	// Exhaustive is true when the results hold all the matching series.
+	Exhaustive bool
+
+	// Warnings holds the query limits exceeded by any node, the results are
+	// partial when there are any.
+	Warnings []index.QueryLimitExceeded
+
+	// PartialAvailability is true when any node served results from shards
+	// that are still bootstrapping, the results may be incomplete.
+	PartialAvailability bool
+}
+
 // TaggedIDsIterator iterates over a collection of IDs with associated tags and namespace.
 type TaggedIDsIterator interface {
 	// Next returns whether there are more items in the collection.
@@ -216,6 +251,12 @@ type Options interface {
 	// RuntimeOptionsManager returns the runtime options manager, it is optional
 	RuntimeOptionsManager() runtime.OptionsManager
 
+	// SetFaultInjector sets the fault injector used when sending requests
+	SetFaultInjector(value fault.Injector) Options
+
+	// FaultInjector returns the fault injector used when sending requests
+	FaultInjector() fault.Injector
+
 	// SetClockOptions sets the clock options
 	SetClockOptions(value clock.Options) Options
 
@@ -234,6 +275,15 @@ type Options interface {
 	// TopologyInitializer returns the TopologyInitializer
 	TopologyInitializer() topology.Initializer
 
+	// SetNamespaceInitializer sets the initializer of the namespace registry
+	// the shard routing tags of namespaces are read from, series are routed
+	// by their ID if not set
+	SetNamespaceInitializer(value namespace.Initializer) Options
+
+	// NamespaceInitializer returns the initializer of the namespace registry
+	// the shard routing tags of namespaces are read from
+	NamespaceInitializer() namespace.Initializer
+
 	// SetReadConsistencyLevel sets the read consistency level
 	SetReadConsistencyLevel(value topology.ReadConsistencyLevel) Options
 
@@ -352,6 +402,38 @@ type Options interface {
 	// a write operation. Only retryable errors are retried.
 	WriteRetrier() xretry.Retrier
 
+	// SetAsyncWriteQueueSize sets the max number of writes queued when writing
+	// asynchronously, if greater than zero writes are enqueued and return
+	// immediately rather than waiting for the write to complete.
+	SetAsyncWriteQueueSize(value int) Options
+
+	// AsyncWriteQueueSize returns the max number of writes queued when writing
+	// asynchronously, if greater than zero writes are enqueued and return
+	// immediately rather than waiting for the write to complete.
+	AsyncWriteQueueSize() int
+
+	// SetAsyncWriteWorkers sets the number of workers flushing async writes.
+	SetAsyncWriteWorkers(value int) Options
+
+	// AsyncWriteWorkers returns the number of workers flushing async writes.
+	AsyncWriteWorkers() int
+
+	// SetAsyncWriteDropPolicy sets the policy for dropping async writes
+	// when the async write queue is full.
+	SetAsyncWriteDropPolicy(value AsyncWriteDropPolicy) Options
+
+	// AsyncWriteDropPolicy returns the policy for dropping async writes
+	// when the async write queue is full.
+	AsyncWriteDropPolicy() AsyncWriteDropPolicy
+
+	// SetNamespaceOptions sets the per namespace overrides of the default
+	// consistency levels, request timeouts and retriers keyed by namespace.
+	SetNamespaceOptions(value map[string]NamespaceOptions) Options
+
+	// NamespaceOptions returns the per namespace overrides of the default
+	// consistency levels, request timeouts and retriers keyed by namespace.
+	NamespaceOptions() map[string]NamespaceOptions
+
 	// SetFetchRetrier sets the fetch retrier when performing a write for
 	// a fetch operation. Only retryable errors are retried.
 	SetFetchRetrier(value xretry.Retrier) Options
@@ -456,6 +538,14 @@ type Options interface {
 	// HostQueueOpsArrayPoolSize returns the hostQueueOpsArrayPoolSize
 	HostQueueOpsArrayPoolSize() int
 
+	// SetHostQueueStatsFn sets the function called with the stats of each
+	// host queue every instrument report interval
+	SetHostQueueStatsFn(value HostQueueStatsFn) Options
+
+	// HostQueueStatsFn returns the function called with the stats of each
+	// host queue every instrument report interval
+	HostQueueStatsFn() HostQueueStatsFn
+
 	// SetSeriesIteratorPoolSize sets the seriesIteratorPoolSize
 	SetSeriesIteratorPoolSize(value int) Options
 
@@ -473,6 +563,24 @@ type Options interface {
 
 	// ReaderIteratorAllocate returns the readerIteratorAllocate
 	ReaderIteratorAllocate() encoding.ReaderIteratorAllocate
+
+	// SetEncodingCodecRegistry sets the registry of codecs used to decode
+	// data encoded with a codec other than the default.
+	SetEncodingCodecRegistry(value encoding.CodecRegistry) Options
+
+	// EncodingCodecRegistry returns the registry of codecs used to decode
+	// data encoded with a codec other than the default.
+	EncodingCodecRegistry() encoding.CodecRegistry
+
+	// SetSortTaggedIDsTags sets whether the tags of the results of FetchTagged
+	// and FetchTaggedIDs are returned sorted by name with duplicate names
+	// removed, a duplicate name with a different value fails the fetch
+	SetSortTaggedIDsTags(value bool) Options
+
+	// SortTaggedIDsTags returns whether the tags of the results of FetchTagged
+	// and FetchTaggedIDs are returned sorted by name with duplicate names
+	// removed, a duplicate name with a different value fails the fetch
+	SortTaggedIDsTags() bool
 }
 
 // AdminOptions is a set of administration client options
@@ -562,10 +670,40 @@ type hostQueue interface {
 	// BorrowConnection will borrow a connection and execute a user function
 	BorrowConnection(fn withConnectionFn) error
 
+	// Stats returns the current stats of the host queue
+	Stats() HostQueueStats
+
 	// Close the host queue, will flush any operations still pending
 	Close()
 }
 
+// HostQueueStats is a snapshot of the queue of operations to a host.
+type HostQueueStats struct {
+	// HostID is the ID of the host.
+	HostID string
+	// Pending is the number of operations enqueued or in flight to the host.
+	Pending int
+	// Capacity is the number of operations the queue buffers, i.e. the flush
+	// size times the number of op arrays.
+	Capacity int
+	// EnqueueFailures is the number of operations that failed to enqueue.
+	EnqueueFailures int64
+	// LastFlushLatency is the latency of the last request sent to the host.
+	LastFlushLatency time.Duration
+}
+
+// Backpressure returns the ratio of pending operations to the capacity of
+// the queue, above one the host is not keeping up with the operations sent.
+func (s HostQueueStats) Backpressure() float64 {
+	if s.Capacity <= 0 {
+		return 0
+	}
+	return float64(s.Pending) / float64(s.Capacity)
+}
+
+// HostQueueStatsFn is called with the stats of a host queue.
+type HostQueueStatsFn func(stats HostQueueStats)
+
 type withConnectionFn func(c rpc.TChanNode)
 
 type connectionPool interface {
@@ -578,6 +716,13 @@ type connectionPool interface {
 	// NextClient gets the next client for use by the connection pool
 	NextClient() (rpc.TChanNode, error)
 
+	// APIVersion returns the node RPC API version negotiated with the host
+	APIVersion() int32
+
+	// Capabilities returns the node RPC API capabilities negotiated with
+	// the host
+	Capabilities() tchannelthrift.Capabilities
+
 	// Close the connection pool
 	Close()
 }
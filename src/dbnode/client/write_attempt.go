@@ -58,6 +58,8 @@ type writeAttemptArgs struct {
 	annotation  []byte
 	unit        xtime.Unit
 	attemptType writeAttemptType
+
+	requestMetadata string
 }
 
 func (w *writeAttempt) reset() {
@@ -67,7 +69,7 @@ func (w *writeAttempt) reset() {
 func (w *writeAttempt) perform() error {
 	err := w.session.writeAttempt(w.args.attemptType,
 		w.args.namespace, w.args.id, w.args.tags, w.args.t,
-		w.args.value, w.args.unit, w.args.annotation)
+		w.args.value, w.args.unit, w.args.annotation, w.args.requestMetadata)
 
 	if IsBadRequestError(err) {
 		// Do not retry bad request errors
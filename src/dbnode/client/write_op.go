@@ -40,6 +40,10 @@ type writeOperation struct {
 	datapoint    rpc.Datapoint
 	completionFn completionFn
 	pool         *writeOperationPool
+
+	// requestMetadata is sent with the request for nodes to include in
+	// their logs and errors.
+	requestMetadata string
 }
 
 func (w *writeOperation) reset() {
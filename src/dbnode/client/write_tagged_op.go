@@ -40,6 +40,10 @@ type writeTaggedOperation struct {
 	datapoint    rpc.Datapoint
 	completionFn completionFn
 	pool         *writeTaggedOperationPool
+
+	// requestMetadata is sent with the request for nodes to include in
+	// their logs and errors.
+	requestMetadata string
 }
 
 func (w *writeTaggedOperation) reset() {
@@ -25,13 +25,19 @@ import (
 )
 
 type options struct {
-	nowFn NowFn
+	nowFn       NowFn
+	sleepFn     SleepFn
+	afterFn     AfterFn
+	newTickerFn NewTickerFn
 }
 
 // NewOptions creates new clock options
 func NewOptions() Options {
 	return &options{
-		nowFn: time.Now,
+		nowFn:       time.Now,
+		sleepFn:     time.Sleep,
+		afterFn:     time.After,
+		newTickerFn: newTicker,
 	}
 }
 
@@ -44,3 +50,45 @@ func (o *options) SetNowFn(value NowFn) Options {
 func (o *options) NowFn() NowFn {
 	return o.nowFn
 }
+
+func (o *options) SetSleepFn(value SleepFn) Options {
+	opts := *o
+	opts.sleepFn = value
+	return &opts
+}
+
+func (o *options) SleepFn() SleepFn {
+	return o.sleepFn
+}
+
+func (o *options) SetAfterFn(value AfterFn) Options {
+	opts := *o
+	opts.afterFn = value
+	return &opts
+}
+
+func (o *options) AfterFn() AfterFn {
+	return o.afterFn
+}
+
+func (o *options) SetNewTickerFn(value NewTickerFn) Options {
+	opts := *o
+	opts.newTickerFn = value
+	return &opts
+}
+
+func (o *options) NewTickerFn() NewTickerFn {
+	return o.newTickerFn
+}
+
+type ticker struct {
+	*time.Ticker
+}
+
+func newTicker(d time.Duration) Ticker {
+	return ticker{Ticker: time.NewTicker(d)}
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -27,6 +27,27 @@ import (
 // NowFn is the function supplied to determine "now"
 type NowFn func() time.Time
 
+// SleepFn is the function supplied to wait for a duration to elapse
+type SleepFn func(d time.Duration)
+
+// AfterFn is the function supplied to receive the current time on the
+// returned channel once a duration has elapsed, it is used in place of
+// timers and tickers.
+type AfterFn func(d time.Duration) <-chan time.Time
+
+// Ticker delivers the current time on its channel at an interval.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on.
+	C() <-chan time.Time
+
+	// Stop stops the ticker, no ticks are delivered after it returns.
+	Stop()
+}
+
+// NewTickerFn is the function supplied to create a ticker that ticks at the
+// interval, it is used in place of tickers for loops that run periodically.
+type NewTickerFn func(d time.Duration) Ticker
+
 // Options represents the options for the clock
 type Options interface {
 	// SetNowFn sets the nowFn
@@ -34,4 +55,22 @@ type Options interface {
 
 	// NowFn returns the nowFn
 	NowFn() NowFn
+
+	// SetSleepFn sets the sleepFn
+	SetSleepFn(value SleepFn) Options
+
+	// SleepFn returns the sleepFn
+	SleepFn() SleepFn
+
+	// SetAfterFn sets the afterFn
+	SetAfterFn(value AfterFn) Options
+
+	// AfterFn returns the afterFn
+	AfterFn() AfterFn
+
+	// SetNewTickerFn sets the newTickerFn
+	SetNewTickerFn(value NewTickerFn) Options
+
+	// NewTickerFn returns the newTickerFn
+	NewTickerFn() NewTickerFn
 }
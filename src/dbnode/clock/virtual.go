@@ -0,0 +1,245 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// VirtualClockMode describes how a virtual clock handles sleeps.
+type VirtualClockMode int
+
+const (
+	// VirtualClockManual blocks sleepers until the clock is advanced past
+	// their deadline by a call to Advance or Set.
+	VirtualClockManual VirtualClockMode = iota
+
+	// VirtualClockAutoAdvance advances the clock by the sleep duration and
+	// returns immediately, useful for running simulations faster than real time.
+	// Channels returned by After are not advanced to, they fire once sleeps
+	// or calls to Advance or Set move the clock past their deadline.
+	VirtualClockAutoAdvance
+)
+
+// VirtualClock is a deterministic clock that only moves forward when
+// explicitly advanced, for use when running the database in simulation mode.
+type VirtualClock interface {
+	// Now returns the current virtual time.
+	Now() time.Time
+
+	// Sleep waits for the virtual clock to move forward by the duration.
+	Sleep(d time.Duration)
+
+	// After returns a channel that receives the virtual time once the
+	// virtual clock has moved forward by the duration.
+	After(d time.Duration) <-chan time.Time
+
+	// Advance moves the virtual clock forward by the duration.
+	Advance(d time.Duration)
+
+	// Set sets the virtual time, it is a no-op if the time is in the past.
+	Set(t time.Time)
+
+	// NewTicker returns a ticker that ticks each time the virtual clock
+	// moves forward by the interval.
+	NewTicker(d time.Duration) Ticker
+
+	// NumSleepers returns the number of callers blocked in Sleep, of
+	// channels returned by After that have not fired and of tickers that
+	// have not been stopped.
+	NumSleepers() int
+
+	// Options returns clock options backed by the virtual clock.
+	Options() Options
+}
+
+type sleeper struct {
+	deadline time.Time
+	doneCh   chan time.Time
+}
+
+type virtualClock struct {
+	sync.Mutex
+
+	mode     VirtualClockMode
+	now      time.Time
+	sleepers []sleeper
+	tickers  []*virtualTicker
+}
+
+type virtualTicker struct {
+	clock    *virtualClock
+	interval time.Duration
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *virtualTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *virtualTicker) Stop() {
+	c := t.clock
+	c.Lock()
+	defer c.Unlock()
+	for i, ticker := range c.tickers {
+		if ticker != t {
+			continue
+		}
+		last := len(c.tickers) - 1
+		c.tickers[i] = c.tickers[last]
+		c.tickers[last] = nil
+		c.tickers = c.tickers[:last]
+		return
+	}
+}
+
+// NewVirtualClock creates a new virtual clock starting at the given time.
+func NewVirtualClock(start time.Time, mode VirtualClockMode) VirtualClock {
+	return &virtualClock{
+		mode: mode,
+		now:  start,
+	}
+}
+
+func (c *virtualClock) Now() time.Time {
+	c.Lock()
+	now := c.now
+	c.Unlock()
+	return now
+}
+
+func (c *virtualClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	c.Lock()
+	if c.mode == VirtualClockAutoAdvance {
+		c.setWithLock(c.now.Add(d))
+		c.Unlock()
+		return
+	}
+
+	doneCh := c.afterWithLock(d)
+	c.Unlock()
+
+	<-doneCh
+}
+
+func (c *virtualClock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+	if d <= 0 {
+		doneCh := make(chan time.Time, 1)
+		doneCh <- c.now
+		return doneCh
+	}
+	return c.afterWithLock(d)
+}
+
+func (c *virtualClock) afterWithLock(d time.Duration) <-chan time.Time {
+	doneCh := make(chan time.Time, 1)
+	c.sleepers = append(c.sleepers, sleeper{
+		deadline: c.now.Add(d),
+		doneCh:   doneCh,
+	})
+	return doneCh
+}
+
+func (c *virtualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	t := &virtualTicker{
+		clock:    c,
+		interval: d,
+		deadline: c.now.Add(d),
+		// NB: buffer a single tick and drop ticks for slow receivers as
+		// tickers do.
+		ch: make(chan time.Time, 1),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+func (c *virtualClock) Advance(d time.Duration) {
+	c.Lock()
+	c.setWithLock(c.now.Add(d))
+	c.Unlock()
+}
+
+func (c *virtualClock) Set(t time.Time) {
+	c.Lock()
+	c.setWithLock(t)
+	c.Unlock()
+}
+
+func (c *virtualClock) setWithLock(t time.Time) {
+	if t.After(c.now) {
+		c.now = t
+	}
+
+	remaining := c.sleepers[:0]
+	for _, s := range c.sleepers {
+		if s.deadline.After(c.now) {
+			remaining = append(remaining, s)
+			continue
+		}
+		s.doneCh <- c.now
+	}
+	for i := len(remaining); i < len(c.sleepers); i++ {
+		c.sleepers[i] = sleeper{}
+	}
+	c.sleepers = remaining
+
+	for _, t := range c.tickers {
+		if t.deadline.After(c.now) {
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+		for !t.deadline.After(c.now) {
+			t.deadline = t.deadline.Add(t.interval)
+		}
+	}
+}
+
+func (c *virtualClock) NumSleepers() int {
+	c.Lock()
+	n := len(c.sleepers) + len(c.tickers)
+	c.Unlock()
+	return n
+}
+
+func (c *virtualClock) Options() Options {
+	return NewOptions().
+		SetNowFn(c.Now).
+		SetSleepFn(c.Sleep).
+		SetAfterFn(c.After).
+		SetNewTickerFn(c.NewTicker)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClockAutoAdvance(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewVirtualClock(start, VirtualClockAutoAdvance)
+	opts := c.Options()
+
+	require.Equal(t, start, opts.NowFn()())
+	opts.SleepFn()(time.Minute)
+	require.Equal(t, start.Add(time.Minute), opts.NowFn()())
+
+	c.Set(start)
+	require.Equal(t, start.Add(time.Minute), c.Now())
+}
+
+func TestVirtualClockManualWakesSleepersOnAdvance(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewVirtualClock(start, VirtualClockManual)
+
+	doneCh := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(doneCh)
+	}()
+
+	for c.NumSleepers() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-doneCh:
+		require.FailNow(t, "sleeper woke before deadline")
+	default:
+	}
+	require.Equal(t, 1, c.NumSleepers())
+
+	c.Advance(30 * time.Second)
+	<-doneCh
+	require.Equal(t, 0, c.NumSleepers())
+	require.Equal(t, start.Add(time.Minute), c.Now())
+}
+
+func TestVirtualClockAfter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewVirtualClock(start, VirtualClockAutoAdvance)
+	afterFn := c.Options().AfterFn()
+
+	ch := afterFn(time.Minute)
+	require.Equal(t, 1, c.NumSleepers())
+
+	// After does not advance the clock even when auto advancing.
+	select {
+	case <-ch:
+		require.FailNow(t, "after fired before deadline")
+	default:
+	}
+
+	c.Sleep(time.Minute)
+	require.Equal(t, start.Add(time.Minute), <-ch)
+	require.Equal(t, 0, c.NumSleepers())
+
+	require.Equal(t, start.Add(time.Minute), <-afterFn(0))
+}
+
+func TestVirtualClockTicker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewVirtualClock(start, VirtualClockManual)
+	ticker := c.Options().NewTickerFn()(time.Minute)
+	require.Equal(t, 1, c.NumSleepers())
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		require.FailNow(t, "ticker fired before interval")
+	default:
+	}
+
+	c.Advance(30 * time.Second)
+	require.Equal(t, start.Add(time.Minute), <-ticker.C())
+
+	// Ticks are dropped for slow receivers and the ticker keeps its cadence.
+	c.Advance(time.Minute)
+	c.Advance(90 * time.Second)
+	require.Equal(t, start.Add(2*time.Minute), <-ticker.C())
+	c.Advance(30 * time.Second)
+	require.Equal(t, start.Add(4*time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	require.Equal(t, 0, c.NumSleepers())
+	c.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		require.FailNow(t, "ticker fired after stop")
+	default:
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"bufio"
 	"io"
 	"os"
+
+	"github.com/m3db/m3/src/dbnode/x/directio"
 )
 
 // FdWithDigestWriter provides a buffered writer for writing to the underlying file.
@@ -32,16 +34,40 @@ type FdWithDigestWriter interface {
 	io.Writer
 }
 
+type bufferedFdWriter interface {
+	io.Writer
+
+	Flush() error
+	Reset(fd *os.File)
+}
+
+type bufioFdWriter struct {
+	*bufio.Writer
+}
+
+func (w bufioFdWriter) Reset(fd *os.File) {
+	w.Writer.Reset(fd)
+}
+
 type fdWithDigestWriter struct {
 	FdWithDigest
-	writer *bufio.Writer
+	writer bufferedFdWriter
 }
 
 // NewFdWithDigestWriter creates a new FdWithDigestWriter.
 func NewFdWithDigestWriter(bufferSize int) FdWithDigestWriter {
 	return &fdWithDigestWriter{
 		FdWithDigest: newFdWithDigest(),
-		writer:       bufio.NewWriterSize(nil, bufferSize),
+		writer:       bufioFdWriter{bufio.NewWriterSize(nil, bufferSize)},
+	}
+}
+
+// NewFdWithDigestDirectWriter creates a new FdWithDigestWriter for files
+// opened with direct I/O, see directio.OpenFile.
+func NewFdWithDigestDirectWriter(bufferSize int) FdWithDigestWriter {
+	return &fdWithDigestWriter{
+		FdWithDigest: newFdWithDigest(),
+		writer:       directio.NewWriter(bufferSize),
 	}
 }
 
@@ -93,6 +119,15 @@ func NewFdWithDigestContentsWriter(bufferSize int) FdWithDigestContentsWriter {
 	}
 }
 
+// NewFdWithDigestContentsDirectWriter creates a new FdWithDigestContentsWriter
+// for files opened with direct I/O, see directio.OpenFile.
+func NewFdWithDigestContentsDirectWriter(bufferSize int) FdWithDigestContentsWriter {
+	return &fdWithDigestContentsWriter{
+		FdWithDigestWriter: NewFdWithDigestDirectWriter(bufferSize),
+		digestBuf:          NewBuffer(),
+	}
+}
+
 func (w *fdWithDigestContentsWriter) WriteDigests(digests ...uint32) error {
 	for _, digest := range digests {
 		w.digestBuf.WriteDigest(digest)
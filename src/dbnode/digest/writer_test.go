@@ -38,7 +38,7 @@ func createTestFdWithDigestWriter(t *testing.T) (*fdWithDigestWriter, *os.File,
 	fd, md := createTestFdWithDigest(t)
 	writer := NewFdWithDigestWriter(testWriterBufferSize).(*fdWithDigestWriter)
 	writer.FdWithDigest.(*fdWithDigest).digest = md
-	writer.writer = bufio.NewWriterSize(nil, 2)
+	writer.writer = bufioFdWriter{bufio.NewWriterSize(nil, 2)}
 	writer.Reset(fd)
 	return writer, fd, md
 }
@@ -136,3 +136,22 @@ func TestFdWithDigestWriteDigestsSuccess(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, b)
 }
+
+func TestFdWithDigestDirectWriterClose(t *testing.T) {
+	fd, err := ioutil.TempFile("", "testfile")
+	require.NoError(t, err)
+	defer os.Remove(fd.Name())
+
+	writer := NewFdWithDigestDirectWriter(testWriterBufferSize)
+	writer.Reset(fd)
+
+	data := []byte{0x1, 0x2, 0x3}
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.Equal(t, Checksum(data), writer.Digest().Sum32())
+	require.NoError(t, writer.Close())
+
+	b, err := ioutil.ReadFile(fd.Name())
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+}
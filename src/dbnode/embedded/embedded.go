@@ -0,0 +1,299 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package embedded
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper"
+	bcl "github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/commitlog"
+	bfs "github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3cluster/shard"
+	"github.com/m3db/m3x/ident"
+)
+
+// fsBootstrapProcessorsPerCPU is the number of filesystem bootstrap
+// processors per CPU, matching the default of the database node.
+const fsBootstrapProcessorsPerCPU = 0.5
+
+type embeddedDB struct {
+	db          storage.Database
+	opts        Options
+	storageOpts storage.Options
+	unit        time.Duration
+}
+
+// Open opens an embedded database storing its data in dir, the data in dir
+// from a previous run of the database is bootstrapped before Open returns.
+func Open(dir string, opts Options) (DB, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	unit, err := opts.WriteTimeUnit().Value()
+	if err != nil {
+		return nil, err
+	}
+
+	storageOpts, err := newStorageOptions(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	shardSet, err := newShardSet(opts.NumShards())
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := storage.NewDatabase(shardSet, storageOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Open(); err != nil {
+		return nil, err
+	}
+	if err := db.Bootstrap(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to bootstrap embedded database: %v", err)
+	}
+
+	return &embeddedDB{
+		db:          db,
+		opts:        opts,
+		storageOpts: storageOpts,
+		unit:        unit,
+	}, nil
+}
+
+func newStorageOptions(dir string, opts Options) (storage.Options, error) {
+	storageOpts := opts.StorageOptions()
+	fsOpts := storageOpts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir)
+
+	storageOpts = storageOpts.
+		SetCommitLogOptions(storageOpts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts)).
+		SetNamespaceInitializer(namespace.NewStaticInitializer(opts.Namespaces())).
+		// NB: there are no peers to repair from.
+		SetRepairEnabled(false)
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	if err != nil {
+		return nil, err
+	}
+	storageOpts = storageOpts.SetPersistManager(pm)
+
+	if storageOpts.SeriesCachePolicy() != series.CacheAll {
+		retrieverOpts := fs.NewBlockRetrieverOptions().
+			SetBytesPool(storageOpts.BytesPool()).
+			SetSegmentReaderPool(storageOpts.SegmentReaderPool()).
+			SetIdentifierPool(storageOpts.IdentifierPool())
+		storageOpts = storageOpts.SetDatabaseBlockRetrieverManager(
+			block.NewDatabaseBlockRetrieverManager(
+				func(md namespace.Metadata) (block.DatabaseBlockRetriever, error) {
+					retriever := fs.NewBlockRetriever(retrieverOpts, fsOpts)
+					if err := retriever.Open(md); err != nil {
+						return nil, err
+					}
+					return retriever, nil
+				}))
+	}
+
+	bs, err := newBootstrapProcessProvider(storageOpts)
+	if err != nil {
+		return nil, err
+	}
+	return storageOpts.SetBootstrapProcessProvider(bs), nil
+}
+
+// newBootstrapProcessProvider returns a bootstrap process that bootstraps
+// from the filesets and then the commit log in the directory of the database.
+func newBootstrapProcessProvider(opts storage.Options) (bootstrap.ProcessProvider, error) {
+	var (
+		fsOpts = opts.CommitLogOptions().FilesystemOptions()
+		rsOpts = result.NewOptions().
+			SetInstrumentOptions(opts.InstrumentOptions()).
+			SetDatabaseBlockOptions(opts.DatabaseBlockOptions()).
+			SetSeriesCachePolicy(opts.SeriesCachePolicy()).
+			SetIndexMutableSegmentAllocator(
+				index.NewBootstrapResultMutableSegmentAllocator(opts.IndexOptions()))
+		bs = bootstrapper.NewNoOpAllBootstrapperProvider()
+	)
+
+	inspection, err := fs.InspectFilesystem(fsOpts)
+	if err != nil {
+		return nil, err
+	}
+	clOpts := bcl.NewOptions().
+		SetResultOptions(rsOpts).
+		SetCommitLogOptions(opts.CommitLogOptions())
+	bs, err = bcl.NewCommitLogBootstrapperProvider(clOpts, inspection, bs)
+	if err != nil {
+		return nil, err
+	}
+
+	numProcessors := int(math.Ceil(float64(runtime.NumCPU()) * fsBootstrapProcessorsPerCPU))
+	bfsOpts := bfs.NewOptions().
+		SetInstrumentOptions(opts.InstrumentOptions()).
+		SetResultOptions(rsOpts).
+		SetFilesystemOptions(fsOpts).
+		SetPersistManager(opts.PersistManager()).
+		SetBoostrapDataNumProcessors(numProcessors).
+		SetDatabaseBlockRetrieverManager(opts.DatabaseBlockRetrieverManager()).
+		SetRuntimeOptionsManager(opts.RuntimeOptionsManager()).
+		SetIdentifierPool(opts.IdentifierPool())
+	bs, err = bfs.NewFileSystemBootstrapperProvider(bfsOpts, bs)
+	if err != nil {
+		return nil, err
+	}
+
+	return bootstrap.NewProcessProvider(bs, bootstrap.NewProcessOptions(), rsOpts), nil
+}
+
+func newShardSet(numShards int) (sharding.ShardSet, error) {
+	ids := make([]uint32, 0, numShards)
+	for i := 0; i < numShards; i++ {
+		ids = append(ids, uint32(i))
+	}
+	shards := sharding.NewShards(ids, shard.Available)
+	return sharding.NewShardSet(shards, sharding.DefaultHashFn(numShards))
+}
+
+func (d *embeddedDB) Write(
+	namespace string,
+	id string,
+	tags map[string]string,
+	timestamp time.Time,
+	value float64,
+) error {
+	ctx := d.storageOpts.ContextPool().Get()
+	defer ctx.Close()
+
+	var (
+		nsID     = ident.StringID(namespace)
+		seriesID = ident.StringID(id)
+		unit     = d.opts.WriteTimeUnit()
+		err      error
+	)
+	timestamp = timestamp.Truncate(d.unit)
+	if len(tags) == 0 {
+		_, err = d.db.Write(ctx, nsID, seriesID, timestamp, value, unit, nil)
+		return err
+	}
+
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	identTags := make([]ident.Tag, 0, len(names))
+	for _, name := range names {
+		identTags = append(identTags, ident.StringTag(name, tags[name]))
+	}
+	iter := ident.NewTagsIterator(ident.NewTags(identTags...))
+	_, err = d.db.WriteTagged(ctx, nsID, seriesID, iter, timestamp, value, unit, nil)
+	return err
+}
+
+func (d *embeddedDB) Read(
+	namespace string,
+	id string,
+	start, end time.Time,
+) ([]ts.Datapoint, error) {
+	ctx := d.storageOpts.ContextPool().Get()
+	defer ctx.Close()
+
+	encoded, err := d.db.ReadEncoded(ctx, ident.StringID(namespace),
+		ident.StringID(id), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := d.storageOpts.MultiReaderIteratorPool().Get()
+	iter.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(encoded))
+	defer iter.Close()
+
+	var datapoints []ts.Datapoint
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		datapoints = append(datapoints, dp)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return datapoints, nil
+}
+
+func (d *embeddedDB) Query(
+	namespace string,
+	query index.Query,
+	opts index.QueryOptions,
+) ([]Series, bool, error) {
+	ctx := d.storageOpts.ContextPool().Get()
+	defer ctx.Close()
+
+	res, err := d.db.QueryIDs(ctx, ident.StringID(namespace), query, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// NB: the results are released when the context is closed so are copied.
+	entries := res.Results.Map().Iter()
+	results := make([]Series, 0, len(entries))
+	for _, entry := range entries {
+		tags := entry.Value().Values()
+		s := Series{
+			ID:   entry.Key().String(),
+			Tags: make(map[string]string, len(tags)),
+		}
+		for _, tag := range tags {
+			s.Tags[tag.Name.String()] = tag.Value.String()
+		}
+		results = append(results, s)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	return results, res.Exhaustive, nil
+}
+
+func (d *embeddedDB) Database() storage.Database {
+	return d.db
+}
+
+func (d *embeddedDB) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package embedded
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOptions(t *testing.T) Options {
+	md, err := namespace.NewMetadata(ident.StringID("metrics"), namespace.NewOptions().
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true)))
+	require.NoError(t, err)
+
+	storageOpts := storage.NewOptions()
+	storageOpts = storageOpts.SetIndexOptions(storageOpts.IndexOptions().
+		SetInsertMode(index.InsertSync))
+	return NewOptions().
+		SetStorageOptions(storageOpts).
+		SetNamespaces([]namespace.Metadata{md}).
+		SetNumShards(2)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.Error(t, NewOptions().Validate())
+
+	opts := newTestOptions(t)
+	require.NoError(t, opts.Validate())
+	require.Error(t, opts.SetNumShards(0).Validate())
+}
+
+func TestEmbeddedWriteReadQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "embedded")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Open(dir, newTestOptions(t))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	now := time.Now().Truncate(time.Millisecond)
+	require.NoError(t, db.Write("metrics", "foo", map[string]string{"city": "nyc"}, now, 1))
+	require.NoError(t, db.Write("metrics", "foo", map[string]string{"city": "nyc"}, now.Add(time.Second), 2))
+	require.NoError(t, db.Write("metrics", "bar", nil, now, 3))
+
+	datapoints, err := db.Read("metrics", "foo", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, datapoints, 2)
+	require.True(t, now.Equal(datapoints[0].Timestamp))
+	require.Equal(t, 1.0, datapoints[0].Value)
+	require.Equal(t, 2.0, datapoints[1].Value)
+
+	series, exhaustive, err := db.Query("metrics", index.Query{
+		Query: idx.NewTermQuery([]byte("city"), []byte("nyc")),
+	}, index.QueryOptions{
+		StartInclusive: now.Add(-time.Hour),
+		EndExclusive:   now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	require.Equal(t, []Series{
+		{ID: "foo", Tags: map[string]string{"city": "nyc"}},
+	}, series)
+}
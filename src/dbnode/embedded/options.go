@@ -0,0 +1,108 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package embedded
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	xtime "github.com/m3db/m3x/time"
+)
+
+const (
+	defaultNumShards     = 16
+	defaultWriteTimeUnit = xtime.Millisecond
+)
+
+var (
+	errNoNamespaces     = errors.New("at least one namespace is required")
+	errNumShardsInvalid = errors.New("number of shards must be positive")
+)
+
+type options struct {
+	storageOpts storage.Options
+	namespaces  []namespace.Metadata
+	numShards   int
+	writeUnit   xtime.Unit
+}
+
+// NewOptions returns new embedded database options.
+func NewOptions() Options {
+	return &options{
+		storageOpts: storage.NewOptions(),
+		numShards:   defaultNumShards,
+		writeUnit:   defaultWriteTimeUnit,
+	}
+}
+
+func (o *options) Validate() error {
+	if len(o.namespaces) == 0 {
+		return errNoNamespaces
+	}
+	if o.numShards <= 0 {
+		return errNumShardsInvalid
+	}
+	if _, err := o.writeUnit.Value(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *options) SetStorageOptions(value storage.Options) Options {
+	opts := *o
+	opts.storageOpts = value
+	return &opts
+}
+
+func (o *options) StorageOptions() storage.Options {
+	return o.storageOpts
+}
+
+func (o *options) SetNamespaces(value []namespace.Metadata) Options {
+	opts := *o
+	opts.namespaces = value
+	return &opts
+}
+
+func (o *options) Namespaces() []namespace.Metadata {
+	return o.namespaces
+}
+
+func (o *options) SetNumShards(value int) Options {
+	opts := *o
+	opts.numShards = value
+	return &opts
+}
+
+func (o *options) NumShards() int {
+	return o.numShards
+}
+
+func (o *options) SetWriteTimeUnit(value xtime.Unit) Options {
+	opts := *o
+	opts.writeUnit = value
+	return &opts
+}
+
+func (o *options) WriteTimeUnit() xtime.Unit {
+	return o.writeUnit
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package embedded provides a single node M3DB storage engine that can be
+// embedded in an application, storing its data in a local directory without
+// requiring etcd or serving RPCs.
+package embedded
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3x/time"
+)
+
+// DB is an embedded database.
+type DB interface {
+	// Write writes a datapoint to a series, the series is indexed by its tags
+	// if any are provided and the namespace has indexing enabled.
+	Write(
+		namespace string,
+		id string,
+		tags map[string]string,
+		timestamp time.Time,
+		value float64,
+	) error
+
+	// Read returns the datapoints of a series between start inclusive and
+	// end exclusive.
+	Read(namespace string, id string, start, end time.Time) ([]ts.Datapoint, error)
+
+	// Query returns the series of a namespace matching a query, along with
+	// whether the results are exhaustive.
+	Query(namespace string, query index.Query, opts index.QueryOptions) ([]Series, bool, error)
+
+	// Database returns the underlying storage database for uses not covered
+	// by the methods of the embedded database.
+	Database() storage.Database
+
+	// Close flushes the commit log and closes the database.
+	Close() error
+}
+
+// Series is a series matched by a query.
+type Series struct {
+	ID   string
+	Tags map[string]string
+}
+
+// Options are the options of an embedded database.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetStorageOptions sets the storage options, the filesystem path prefix
+	// of the storage options is overridden by the directory of the database.
+	SetStorageOptions(value storage.Options) Options
+
+	// StorageOptions returns the storage options.
+	StorageOptions() storage.Options
+
+	// SetNamespaces sets the namespaces of the database.
+	SetNamespaces(value []namespace.Metadata) Options
+
+	// Namespaces returns the namespaces of the database.
+	Namespaces() []namespace.Metadata
+
+	// SetNumShards sets the number of shards series are distributed across.
+	SetNumShards(value int) Options
+
+	// NumShards returns the number of shards series are distributed across.
+	NumShards() int
+
+	// SetWriteTimeUnit sets the time unit of the timestamps of writes.
+	SetWriteTimeUnit(value xtime.Unit) Options
+
+	// WriteTimeUnit returns the time unit of the timestamps of writes.
+	WriteTimeUnit() xtime.Unit
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/pool"
+)
+
+var (
+	errCodecNameEmpty = errors.New("codec name must not be empty")
+)
+
+// Codec is a named encoding scheme that can be selected per namespace.
+type Codec interface {
+	// Name returns the name the codec is registered with.
+	Name() string
+
+	// NewEncoder returns a new encoder for the codec.
+	NewEncoder(start time.Time, bytes checked.Bytes, opts Options) Encoder
+
+	// NewReaderIterator returns a new reader iterator for the codec.
+	NewReaderIterator(reader io.Reader, opts Options) ReaderIterator
+}
+
+// CodecRegistry is a registry of codecs keyed by name.
+type CodecRegistry interface {
+	// Register registers a codec, it is an error to register a name twice.
+	Register(codec Codec) error
+
+	// Codec returns the codec registered with the name.
+	Codec(name string) (Codec, error)
+
+	// Names returns the sorted names of the registered codecs.
+	Names() []string
+}
+
+type codecRegistry struct {
+	sync.RWMutex
+	codecs map[string]Codec
+}
+
+// NewCodecRegistry returns a new empty codec registry.
+func NewCodecRegistry() CodecRegistry {
+	return &codecRegistry{codecs: make(map[string]Codec)}
+}
+
+func (r *codecRegistry) Register(codec Codec) error {
+	name := codec.Name()
+	if name == "" {
+		return errCodecNameEmpty
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.codecs[name]; ok {
+		return fmt.Errorf("codec already registered: %s", name)
+	}
+	r.codecs[name] = codec
+	return nil
+}
+
+func (r *codecRegistry) Codec(name string) (Codec, error) {
+	r.RLock()
+	codec, ok := r.codecs[name]
+	r.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("codec not registered: %s", name)
+	}
+	return codec, nil
+}
+
+func (r *codecRegistry) Names() []string {
+	r.RLock()
+	names := make([]string, 0, len(r.codecs))
+	for name := range r.codecs {
+		names = append(names, name)
+	}
+	r.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// CodecMultiReaderIteratorPools returns multi reader iterator pools that
+// decode with a registered codec, creating the pool for a codec on first use.
+type CodecMultiReaderIteratorPools interface {
+	// Pool returns the pool for the named codec, the default pool is
+	// returned if the name is empty.
+	Pool(codecName string) (MultiReaderIteratorPool, error)
+}
+
+type codecMultiReaderIteratorPools struct {
+	sync.RWMutex
+	registry     CodecRegistry
+	defaultPool  MultiReaderIteratorPool
+	poolOpts     pool.ObjectPoolOptions
+	encodingOpts Options
+	pools        map[string]MultiReaderIteratorPool
+}
+
+// NewCodecMultiReaderIteratorPools returns new codec multi reader iterator
+// pools, the default pool is used for data with no codec set.
+func NewCodecMultiReaderIteratorPools(
+	registry CodecRegistry,
+	defaultPool MultiReaderIteratorPool,
+	poolOpts pool.ObjectPoolOptions,
+	encodingOpts Options,
+) CodecMultiReaderIteratorPools {
+	return &codecMultiReaderIteratorPools{
+		registry:     registry,
+		defaultPool:  defaultPool,
+		poolOpts:     poolOpts,
+		encodingOpts: encodingOpts,
+		pools:        make(map[string]MultiReaderIteratorPool),
+	}
+}
+
+func (p *codecMultiReaderIteratorPools) Pool(
+	codecName string,
+) (MultiReaderIteratorPool, error) {
+	if codecName == "" {
+		return p.defaultPool, nil
+	}
+
+	p.RLock()
+	result, ok := p.pools[codecName]
+	p.RUnlock()
+	if ok {
+		return result, nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if result, ok := p.pools[codecName]; ok {
+		return result, nil
+	}
+
+	codec, err := p.registry.Codec(codecName)
+	if err != nil {
+		return nil, err
+	}
+
+	encodingOpts := p.encodingOpts
+	alloc := func(r io.Reader) ReaderIterator {
+		return codec.NewReaderIterator(r, encodingOpts)
+	}
+	codecPool := &multiReaderIteratorPool{pool: pool.NewObjectPool(p.poolOpts)}
+	codecPool.pool.Init(func() interface{} {
+		it := NewMultiReaderIterator(alloc, codecPool).(*multiReaderIterator)
+		it.codec = codecName
+		return it
+	})
+	p.pools[codecName] = codecPool
+	return codecPool, nil
+}
+
+// CodecReaderIteratorAllocate returns the reader iterator allocate function
+// for the named codec, the default is returned if the name is empty.
+func CodecReaderIteratorAllocate(
+	registry CodecRegistry,
+	codecName string,
+	defaultAlloc ReaderIteratorAllocate,
+	opts Options,
+) (ReaderIteratorAllocate, error) {
+	if codecName == "" {
+		return defaultAlloc, nil
+	}
+	if registry == nil {
+		return nil, fmt.Errorf("codec not registered: %s", codecName)
+	}
+	codec, err := registry.Codec(codecName)
+	if err != nil {
+		return nil, err
+	}
+	return func(r io.Reader) ReaderIterator {
+		return codec.NewReaderIterator(r, opts)
+	}, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encoding
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/pool"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCodec struct {
+	name string
+}
+
+func (c testCodec) Name() string { return c.name }
+
+func (c testCodec) NewEncoder(time.Time, checked.Bytes, Options) Encoder { return nil }
+
+func (c testCodec) NewReaderIterator(io.Reader, Options) ReaderIterator {
+	return &testCodecReaderIterator{codec: c.name}
+}
+
+type testCodecReaderIterator struct {
+	ReaderIterator
+	codec string
+}
+
+func TestCodecRegistry(t *testing.T) {
+	r := NewCodecRegistry()
+	require.NoError(t, r.Register(testCodec{name: "b"}))
+	require.NoError(t, r.Register(testCodec{name: "a"}))
+	require.Error(t, r.Register(testCodec{name: "a"}))
+	require.Error(t, r.Register(testCodec{}))
+
+	codec, err := r.Codec("a")
+	require.NoError(t, err)
+	require.Equal(t, "a", codec.Name())
+
+	_, err = r.Codec("c")
+	require.Error(t, err)
+
+	require.Equal(t, []string{"a", "b"}, r.Names())
+}
+
+func TestCodecMultiReaderIteratorPools(t *testing.T) {
+	r := NewCodecRegistry()
+	require.NoError(t, r.Register(testCodec{name: "a"}))
+
+	defaultPool := NewMultiReaderIteratorPool(nil)
+	defaultPool.Init(func(io.Reader) ReaderIterator { return nil })
+	pools := NewCodecMultiReaderIteratorPools(r, defaultPool,
+		pool.NewObjectPoolOptions().SetSize(1), NewOptions())
+
+	p, err := pools.Pool("")
+	require.NoError(t, err)
+	require.True(t, p == defaultPool)
+
+	p, err = pools.Pool("a")
+	require.NoError(t, err)
+	require.False(t, p == defaultPool)
+
+	again, err := pools.Pool("a")
+	require.NoError(t, err)
+	require.True(t, p == again)
+
+	iter := p.Get()
+	require.Equal(t, "a", iter.Codec())
+	it := iter.(*multiReaderIterator).iteratorAlloc(nil)
+	require.Equal(t, "a", it.(*testCodecReaderIterator).codec)
+
+	_, err = pools.Pool("b")
+	require.Error(t, err)
+}
+
+func TestCodecReaderIteratorAllocate(t *testing.T) {
+	r := NewCodecRegistry()
+	require.NoError(t, r.Register(testCodec{name: "a"}))
+
+	defaultAlloc := func(io.Reader) ReaderIterator { return nil }
+	alloc, err := CodecReaderIteratorAllocate(r, "", defaultAlloc, NewOptions())
+	require.NoError(t, err)
+	require.Nil(t, alloc(nil))
+
+	alloc, err = CodecReaderIteratorAllocate(r, "a", defaultAlloc, NewOptions())
+	require.NoError(t, err)
+	require.Equal(t, "a", alloc(nil).(*testCodecReaderIterator).codec)
+
+	_, err = CodecReaderIteratorAllocate(r, "b", defaultAlloc, NewOptions())
+	require.Error(t, err)
+}
@@ -156,6 +156,10 @@ func (it *testMultiIterator) Readers() xio.ReaderSliceOfSlicesIterator {
 	return nil
 }
 
+func (it *testMultiIterator) Codec() string {
+	return ""
+}
+
 type testReaderSliceOfSlicesIterator struct {
 	blocks [][]xio.BlockReader
 	idx    int
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3tsz
+
+import (
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3x/checked"
+)
+
+const (
+	// CodecName is the name of the m3tsz codec.
+	CodecName = "m3tsz"
+)
+
+type codec struct {
+	intOptimized bool
+}
+
+// NewCodec returns the m3tsz codec.
+func NewCodec(intOptimized bool) encoding.Codec {
+	return codec{intOptimized: intOptimized}
+}
+
+func (c codec) Name() string {
+	return CodecName
+}
+
+func (c codec) NewEncoder(
+	start time.Time,
+	bytes checked.Bytes,
+	opts encoding.Options,
+) encoding.Encoder {
+	return NewEncoder(start, bytes, c.intOptimized, opts)
+}
+
+func (c codec) NewReaderIterator(
+	reader io.Reader,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	return NewReaderIterator(reader, c.intOptimized, opts)
+}
+
+// NewCodecRegistry returns a codec registry with the m3tsz and int delta
+// codecs registered.
+func NewCodecRegistry() (encoding.CodecRegistry, error) {
+	registry := encoding.NewCodecRegistry()
+	if err := registry.Register(NewCodec(DefaultIntOptimizationEnabled)); err != nil {
+		return nil, err
+	}
+	if err := registry.Register(NewIntDeltaCodec()); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
@@ -58,6 +58,8 @@ type encoder struct {
 	intVal             float64    // current int val
 	tu                 xtime.Unit // current time unit
 	intOptimized       bool       // whether the encoding scheme is optimized for ints
+	intDelta           bool       // whether ints are encoded as deltas by the int delta codec
+	intDeltaVal        int64      // current int delta val
 	isFloat            bool       // whether we are encoding ints/floats
 	hasStartedWriting  bool       // whether any datapoints have been written yet
 	maxMult            uint8      // current max multiplier for int vals
@@ -261,6 +263,10 @@ func (enc *encoder) writeDeltaOfDeltaTimeUnitUnchanged(prevDelta, curDelta time.
 }
 
 func (enc *encoder) writeFirstValue(v float64) error {
+	if enc.intDelta {
+		enc.writeFirstIntDeltaValue(v)
+		return nil
+	}
 	if !enc.intOptimized {
 		enc.writeFullFloatVal(math.Float64bits(v))
 		return nil
@@ -297,6 +303,10 @@ func (enc *encoder) writeFirstValue(v float64) error {
 }
 
 func (enc *encoder) writeNextValue(v float64) error {
+	if enc.intDelta {
+		enc.writeNextIntDeltaValue(v)
+		return nil
+	}
 	if !enc.intOptimized {
 		enc.writeFloatXOR(math.Float64bits(v))
 		return nil
@@ -508,6 +518,7 @@ func (enc *encoder) reset(start time.Time, bytes checked.Bytes) {
 	enc.vb = 0
 	enc.xor = 0
 	enc.intVal = 0
+	enc.intDeltaVal = 0
 	enc.isFloat = false
 	enc.maxMult = 0
 	enc.numSig = 0
@@ -0,0 +1,199 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3tsz
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3x/checked"
+)
+
+const (
+	// IntDeltaCodecName is the name of the int delta codec.
+	IntDeltaCodecName = "intdelta"
+
+	// maxIntDeltaValue is the largest magnitude of an integer value encoded
+	// as an integer, beyond it float64 can no longer represent every integer.
+	maxIntDeltaValue = 1 << 53
+
+	opcodeIntDeltaRepeat      = 0x0
+	opcodeIntDeltaNoRepeat    = 0x1
+	opcodeIntDeltaValue       = 0x0
+	opcodeIntDeltaToFloatMode = 0x1
+
+	numIntDeltaSizeBits = 2
+)
+
+// intDeltaSizes are the number of bits a zigzag encoded delta is written with,
+// selected by a size prefix of numIntDeltaSizeBits.
+var intDeltaSizes = [1 << numIntDeltaSizeBits]int{8, 16, 32, 64}
+
+type intDeltaCodec struct{}
+
+// NewIntDeltaCodec returns the int delta codec, which encodes timestamps the
+// same as m3tsz but encodes integer values as the delta from the previous
+// value. Namespaces of integer valued series select the codec by name with
+// their encoding, the value type is not declared per series nor recorded in
+// the metadata of persisted blocks. Instead whether a block holds integer or
+// float values is written in the stream at the start of the block from its
+// first value, and a block of integers switches to float values for the rest
+// of the block if a non-integer value is written, so each block decodes
+// correctly given only the codec it was encoded with.
+func NewIntDeltaCodec() encoding.Codec {
+	return intDeltaCodec{}
+}
+
+func (c intDeltaCodec) Name() string {
+	return IntDeltaCodecName
+}
+
+func (c intDeltaCodec) NewEncoder(
+	start time.Time,
+	bytes checked.Bytes,
+	opts encoding.Options,
+) encoding.Encoder {
+	return NewIntDeltaEncoder(start, bytes, opts)
+}
+
+func (c intDeltaCodec) NewReaderIterator(
+	reader io.Reader,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	return NewIntDeltaReaderIterator(reader, opts)
+}
+
+// NewIntDeltaEncoder creates a new int delta encoder.
+func NewIntDeltaEncoder(
+	start time.Time,
+	bytes checked.Bytes,
+	opts encoding.Options,
+) encoding.Encoder {
+	enc := NewEncoder(start, bytes, false, opts).(*encoder)
+	enc.intDelta = true
+	return enc
+}
+
+// NewIntDeltaReaderIterator returns a new iterator for a stream written by
+// an int delta encoder.
+func NewIntDeltaReaderIterator(
+	reader io.Reader,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	it := NewReaderIterator(reader, false, opts).(*readerIterator)
+	it.intDelta = true
+	return it
+}
+
+// isIntDeltaValue returns whether the value can be encoded as an integer.
+func isIntDeltaValue(v float64) bool {
+	if v != math.Trunc(v) || math.Abs(v) >= maxIntDeltaValue {
+		// NB: also excludes NaN and infinities.
+		return false
+	}
+	// Negative zero must be encoded as a float to be preserved.
+	return v != 0 || !math.Signbit(v)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (enc *encoder) writeFirstIntDeltaValue(v float64) {
+	if !isIntDeltaValue(v) {
+		enc.os.WriteBit(opcodeFloatMode)
+		enc.writeFullFloatVal(math.Float64bits(v))
+		enc.isFloat = true
+		return
+	}
+	enc.os.WriteBit(opcodeIntMode)
+	enc.writeIntDelta(int64(v))
+}
+
+func (enc *encoder) writeNextIntDeltaValue(v float64) {
+	if enc.isFloat {
+		enc.writeFloatXOR(math.Float64bits(v))
+		return
+	}
+	if !isIntDeltaValue(v) {
+		enc.os.WriteBit(opcodeIntDeltaNoRepeat)
+		enc.os.WriteBit(opcodeIntDeltaToFloatMode)
+		enc.writeFullFloatVal(math.Float64bits(v))
+		enc.isFloat = true
+		return
+	}
+	val := int64(v)
+	if val == enc.intDeltaVal {
+		enc.os.WriteBit(opcodeIntDeltaRepeat)
+		return
+	}
+	enc.os.WriteBit(opcodeIntDeltaNoRepeat)
+	enc.os.WriteBit(opcodeIntDeltaValue)
+	enc.writeIntDelta(val)
+}
+
+func (enc *encoder) writeIntDelta(val int64) {
+	delta := zigzag(val - enc.intDeltaVal)
+	for i, size := range intDeltaSizes {
+		if size == 64 || delta < 1<<uint(size) {
+			enc.os.WriteBits(uint64(i), numIntDeltaSizeBits)
+			enc.os.WriteBits(delta, size)
+			break
+		}
+	}
+	enc.intDeltaVal = val
+}
+
+func (it *readerIterator) readFirstIntDeltaValue() {
+	if it.readBits(1) == opcodeFloatMode {
+		it.readFullFloatVal()
+		it.isFloat = true
+		return
+	}
+	it.readIntDelta()
+}
+
+func (it *readerIterator) readNextIntDeltaValue() {
+	if it.isFloat {
+		it.readFloatXOR()
+		return
+	}
+	if it.readBits(1) == opcodeIntDeltaRepeat {
+		return
+	}
+	if it.readBits(1) == opcodeIntDeltaToFloatMode {
+		it.readFullFloatVal()
+		it.isFloat = true
+		return
+	}
+	it.readIntDelta()
+}
+
+func (it *readerIterator) readIntDelta() {
+	size := intDeltaSizes[it.readBits(numIntDeltaSizeBits)]
+	it.intDeltaVal += unzigzag(it.readBits(size))
+}
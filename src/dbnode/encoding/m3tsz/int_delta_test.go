@@ -0,0 +1,129 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3tsz
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntDeltaRoundTrip(t *testing.T) {
+	for _, input := range [][]ts.Datapoint{
+		generateCounterDatapoints(1000, time.Second),
+		generateMixSignIntDatapoints(1000, time.Second),
+		generateMixedDatapoints(1000, time.Second),
+		generatePreciseFloatDatapoints(1000, time.Second),
+		generateOverflowDatapoints(),
+	} {
+		validateIntDeltaRoundTrip(t, input)
+	}
+}
+
+func TestIntDeltaSpecialValuesRoundTrip(t *testing.T) {
+	staleNaN := math.Float64frombits(0x7ff0000000000002)
+	for _, vals := range [][]float64{
+		{1, 1, 2, -300, 1 << 40, math.MaxInt32, math.MinInt32},
+		{1, 2, math.Copysign(0, -1), 3},
+		{1, 2, staleNaN, 3, math.Inf(1)},
+		{0.5, 1, 2},
+		{1 << 53, 1, 2},
+	} {
+		input := make([]ts.Datapoint, 0, len(vals))
+		for i, v := range vals {
+			input = append(input, ts.Datapoint{
+				Timestamp: testStartTime.Add(time.Duration(i) * time.Second),
+				Value:     v,
+			})
+		}
+		validateIntDeltaRoundTrip(t, input)
+	}
+}
+
+func TestIntDeltaCodec(t *testing.T) {
+	codec := NewIntDeltaCodec()
+	require.Equal(t, IntDeltaCodecName, codec.Name())
+
+	opts := encoding.NewOptions()
+	enc := codec.NewEncoder(testStartTime, nil, opts)
+	for i := 0; i < 10; i++ {
+		dp := ts.Datapoint{
+			Timestamp: testStartTime.Add(time.Duration(i) * time.Second),
+			Value:     float64(i * 10),
+		}
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+
+	stream := enc.Stream()
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(stream)
+	require.NoError(t, err)
+
+	it := codec.NewReaderIterator(bytes.NewReader(buf.Bytes()), opts)
+	var n int
+	for it.Next() {
+		dp, _, _ := it.Current()
+		require.Equal(t, float64(n*10), dp.Value)
+		n++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 10, n)
+}
+
+func validateIntDeltaRoundTrip(t *testing.T, input []ts.Datapoint) {
+	encoder := NewIntDeltaEncoder(testStartTime, nil, nil)
+	for j, v := range input {
+		var err error
+		if j == 0 {
+			err = encoder.Encode(v, xtime.Millisecond, proto.EncodeVarint(10))
+		} else {
+			err = encoder.Encode(v, xtime.Second, nil)
+		}
+		require.NoError(t, err)
+	}
+
+	it := NewIntDeltaReaderIterator(encoder.Stream(), encoding.NewOptions())
+	defer it.Close()
+	var decompressed []ts.Datapoint
+	for it.Next() {
+		v, _, a := it.Current()
+		if len(decompressed) == 0 {
+			s, _ := proto.DecodeVarint(a)
+			require.Equal(t, uint64(10), s)
+		}
+		decompressed = append(decompressed, v)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, len(input), len(decompressed))
+	for i := range input {
+		require.Equal(t, input[i].Timestamp, decompressed[i].Timestamp)
+		require.Equal(t, math.Float64bits(input[i].Value),
+			math.Float64bits(decompressed[i].Value))
+	}
+}
@@ -55,8 +55,10 @@ type readerIterator struct {
 	mult uint8 // current int multiplier
 	sig  uint8 // current number of significant bits for int diff
 
-	intOptimized bool // whether encoding scheme is optimized for ints
-	isFloat      bool // whether encoding is in int or float
+	intOptimized bool  // whether encoding scheme is optimized for ints
+	intDelta     bool  // whether ints are encoded as deltas by the int delta codec
+	intDeltaVal  int64 // current int delta value
+	isFloat      bool  // whether encoding is in int or float
 
 	tuChanged bool // whether we have a new time unit
 	done      bool // has reached the end
@@ -180,6 +182,10 @@ func (it *readerIterator) readDeltaOfDelta(tes encoding.TimeEncodingScheme) (d t
 }
 
 func (it *readerIterator) readFirstValue() {
+	if it.intDelta {
+		it.readFirstIntDeltaValue()
+		return
+	}
 	if !it.intOptimized {
 		it.readFullFloatVal()
 		return
@@ -196,6 +202,10 @@ func (it *readerIterator) readFirstValue() {
 }
 
 func (it *readerIterator) readNextValue() {
+	if it.intDelta {
+		it.readNextIntDeltaValue()
+		return
+	}
 	if !it.intOptimized {
 		it.readFloatXOR()
 		return
@@ -350,6 +360,12 @@ func (it *readerIterator) timeUnit() time.Duration {
 // Users should not hold on to the returned Annotation object as it may get invalidated when
 // the iterator calls Next().
 func (it *readerIterator) Current() (ts.Datapoint, xtime.Unit, ts.Annotation) {
+	if it.intDelta && !it.isFloat {
+		return ts.Datapoint{
+			Timestamp: it.t,
+			Value:     float64(it.intDeltaVal),
+		}, it.tu, it.ant
+	}
 	if !it.intOptimized || it.isFloat {
 		return ts.Datapoint{
 			Timestamp: it.t,
@@ -395,6 +411,7 @@ func (it *readerIterator) Reset(reader io.Reader) {
 	it.ant = nil
 	it.isFloat = false
 	it.intVal = 0.0
+	it.intDeltaVal = 0
 	it.mult = 0
 	it.sig = 0
 	it.tu = xtime.None
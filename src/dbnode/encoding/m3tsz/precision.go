@@ -0,0 +1,77 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package m3tsz
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	// MaxFloatPrecisionDigits is the number of significant decimal digits
+	// that round trip any float64, rounding to this many digits leaves a value
+	// unchanged.
+	MaxFloatPrecisionDigits = 17
+
+	// maxExactPow10 is the largest power of ten exactly representable by a
+	// float64.
+	maxExactPow10 = 22
+)
+
+// TruncateFloatPrecision rounds v to the given number of significant decimal
+// digits, returning the float64 nearest the rounded decimal so that values
+// with few digits after the decimal point are encoded as scaled ints rather
+// than as XORs of floats. Values are returned unchanged if digits is not
+// within (0, MaxFloatPrecisionDigits) or v is zero or not finite.
+func TruncateFloatPrecision(v float64, digits int) float64 {
+	if digits <= 0 || digits >= MaxFloatPrecisionDigits ||
+		v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+
+	// NB: the logarithm can be off by one next to powers of ten.
+	abs := math.Abs(v)
+	exp := int(math.Floor(math.Log10(abs)))
+	if abs < math.Pow10(exp) {
+		exp--
+	} else if abs >= math.Pow10(exp+1) {
+		exp++
+	}
+
+	// Rounding to an integer that is exact as a float64 and scaling it by an
+	// exact power of ten yields the float64 nearest the rounded decimal.
+	shift := digits - 1 - exp
+	switch {
+	case shift >= 0 && shift <= maxExactPow10:
+		scale := math.Pow10(shift)
+		if scaled := v * scale; math.Abs(scaled) < 1<<53 {
+			return math.Round(scaled) / scale
+		}
+	case shift < 0 && -shift <= maxExactPow10:
+		scale := math.Pow10(-shift)
+		return math.Round(v/scale) * scale
+	}
+
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', digits, 64), 64)
+	if err != nil {
+		return v
+	}
+	return rounded
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package m3tsz
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateFloatPrecision(t *testing.T) {
+	inputs := []struct {
+		value    float64
+		digits   int
+		expected float64
+	}{
+		{value: 42.0, digits: 4, expected: 42.0},
+		{value: 1.23456, digits: 4, expected: 1.235},
+		{value: -1.23456, digits: 4, expected: -1.235},
+		{value: 0.000123456, digits: 3, expected: 0.000123},
+		{value: 123456.789, digits: 2, expected: 120000},
+		{value: 99.99, digits: 3, expected: 100},
+		{value: 0.1 + 0.2, digits: 15, expected: 0.3},
+		{value: 1e-300 / 3, digits: 2, expected: 3.3e-301},
+		{value: 0.1, digits: 0, expected: 0.1},
+		{value: 0.1 + 0.2, digits: MaxFloatPrecisionDigits, expected: 0.1 + 0.2},
+		{value: 0, digits: 4, expected: 0},
+	}
+	for _, input := range inputs {
+		require.Equal(t, input.expected, TruncateFloatPrecision(input.value, input.digits))
+	}
+
+	require.True(t, math.IsNaN(TruncateFloatPrecision(math.NaN(), 4)))
+	require.True(t, math.IsInf(TruncateFloatPrecision(math.Inf(1), 4), 1))
+	require.Equal(t, math.MaxFloat64, TruncateFloatPrecision(math.MaxFloat64, MaxFloatPrecisionDigits))
+}
+
+func TestTruncateFloatPrecisionCompressesAsInts(t *testing.T) {
+	// NB: a gauge reported with many decimal digits of noise.
+	var values []float64
+	for i := 0; i < 720; i++ {
+		values = append(values, 50+10*math.Sin(float64(i)/30)+math.Cos(float64(i))/7)
+	}
+
+	encodedLen := func(digits int) int {
+		enc := NewEncoder(testStartTime, nil, true, nil)
+		for i, v := range values {
+			dp := ts.Datapoint{
+				Timestamp: testStartTime.Add(time.Duration(i) * 10 * time.Second),
+				Value:     TruncateFloatPrecision(v, digits),
+			}
+			require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+		}
+		return enc.Len()
+	}
+
+	// values rounded to decimal digits take the int optimization and encode
+	// far smaller than the full precision values.
+	full, rounded := encodedLen(0), encodedLen(4)
+	require.True(t, rounded < full/2, "rounded %d, full %d", rounded, full)
+
+	for _, v := range values {
+		_, mult, isFloat, err := convertToIntFloat(TruncateFloatPrecision(v, 4), 0)
+		require.NoError(t, err)
+		require.False(t, isFloat)
+		require.True(t, mult <= 2)
+	}
+}
@@ -101,6 +101,34 @@ func TestIntOverflow(t *testing.T) {
 	testRoundTrip(t, generateOverflowDatapoints())
 }
 
+func TestStaleNaNRoundTrip(t *testing.T) {
+	// NB: Prometheus staleness markers are a NaN with a specific payload
+	// which must survive encoding for queries to drop stale series.
+	staleNaN := math.Float64frombits(0x7ff0000000000002)
+	vals := []float64{1, 2, staleNaN, 3, 4.5, staleNaN, staleNaN, 6}
+	for _, intOpt := range []bool{true, false} {
+		encoder := NewEncoder(testStartTime, nil, intOpt, nil)
+		for i, v := range vals {
+			dp := ts.Datapoint{Timestamp: testStartTime.Add(time.Duration(i) * time.Second), Value: v}
+			require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		}
+
+		it := NewDecoder(intOpt, nil).Decode(encoder.Stream())
+		var decoded []uint64
+		for it.Next() {
+			dp, _, _ := it.Current()
+			decoded = append(decoded, math.Float64bits(dp.Value))
+		}
+		require.NoError(t, it.Err())
+		it.Close()
+
+		require.Equal(t, len(vals), len(decoded))
+		for i, v := range vals {
+			require.Equal(t, math.Float64bits(v), decoded[i])
+		}
+	}
+}
+
 func testRoundTrip(t *testing.T, input []ts.Datapoint) {
 	validateRoundTrip(t, input, true)
 	validateRoundTrip(t, input, false)
@@ -39,6 +39,7 @@ type multiReaderIterator struct {
 	iters            iterators
 	slicesIter       xio.ReaderSliceOfSlicesIterator
 	iteratorAlloc    ReaderIteratorAllocate
+	codec            string
 	singleSlicesIter singleSlicesOfSlicesIterator
 	pool             MultiReaderIteratorPool
 	err              error
@@ -159,6 +160,10 @@ func (it *multiReaderIterator) Readers() xio.ReaderSliceOfSlicesIterator {
 	return it.slicesIter
 }
 
+func (it *multiReaderIterator) Codec() string {
+	return it.codec
+}
+
 func (it *multiReaderIterator) Reset(blocks []xio.SegmentReader, start time.Time, blockSize time.Duration) {
 	it.singleSlicesIter.readers = blocks
 	it.singleSlicesIter.firstNext = true
@@ -144,6 +144,10 @@ type MultiReaderIterator interface {
 
 	// Readers exposes the underlying ReaderSliceOfSlicesIterator for this MultiReaderIterator
 	Readers() xio.ReaderSliceOfSlicesIterator
+
+	// Codec returns the name of the codec the iterator decodes with, it is
+	// empty for the default codec.
+	Codec() string
 }
 
 // SeriesIterator is an iterator that iterates over a set of iterators from different replicas
@@ -39,6 +39,8 @@ import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
 
+import binary "encoding/binary"
+
 import io "io"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -53,12 +55,13 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type RetentionOptions struct {
-	RetentionPeriodNanos                     int64 `protobuf:"varint,1,opt,name=retentionPeriodNanos,proto3" json:"retentionPeriodNanos,omitempty"`
-	BlockSizeNanos                           int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
-	BufferFutureNanos                        int64 `protobuf:"varint,3,opt,name=bufferFutureNanos,proto3" json:"bufferFutureNanos,omitempty"`
-	BufferPastNanos                          int64 `protobuf:"varint,4,opt,name=bufferPastNanos,proto3" json:"bufferPastNanos,omitempty"`
-	BlockDataExpiry                          bool  `protobuf:"varint,5,opt,name=blockDataExpiry,proto3" json:"blockDataExpiry,omitempty"`
-	BlockDataExpiryAfterNotAccessPeriodNanos int64 `protobuf:"varint,6,opt,name=blockDataExpiryAfterNotAccessPeriodNanos,proto3" json:"blockDataExpiryAfterNotAccessPeriodNanos,omitempty"`
+	RetentionPeriodNanos                     int64  `protobuf:"varint,1,opt,name=retentionPeriodNanos,proto3" json:"retentionPeriodNanos,omitempty"`
+	BlockSizeNanos                           int64  `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	BufferFutureNanos                        int64  `protobuf:"varint,3,opt,name=bufferFutureNanos,proto3" json:"bufferFutureNanos,omitempty"`
+	BufferPastNanos                          int64  `protobuf:"varint,4,opt,name=bufferPastNanos,proto3" json:"bufferPastNanos,omitempty"`
+	BlockDataExpiry                          bool   `protobuf:"varint,5,opt,name=blockDataExpiry,proto3" json:"blockDataExpiry,omitempty"`
+	BlockDataExpiryAfterNotAccessPeriodNanos int64  `protobuf:"varint,6,opt,name=blockDataExpiryAfterNotAccessPeriodNanos,proto3" json:"blockDataExpiryAfterNotAccessPeriodNanos,omitempty"`
+	TimeZone                                 string `protobuf:"bytes,7,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
 }
 
 func (m *RetentionOptions) Reset()                    { *m = RetentionOptions{} }
@@ -108,9 +111,17 @@ func (m *RetentionOptions) GetBlockDataExpiryAfterNotAccessPeriodNanos() int64 {
 	return 0
 }
 
+func (m *RetentionOptions) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
 type IndexOptions struct {
 	Enabled        bool  `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
 	BlockSizeNanos int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	InsertMode     int32 `protobuf:"varint,3,opt,name=insertMode,proto3" json:"insertMode,omitempty"`
 }
 
 func (m *IndexOptions) Reset()                    { *m = IndexOptions{} }
@@ -132,15 +143,27 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 	return 0
 }
 
+func (m *IndexOptions) GetInsertMode() int32 {
+	if m != nil {
+		return m.InsertMode
+	}
+	return 0
+}
+
 type NamespaceOptions struct {
-	BootstrapEnabled  bool              `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
-	FlushEnabled      bool              `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
-	WritesToCommitLog bool              `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
-	CleanupEnabled    bool              `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
-	RepairEnabled     bool              `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
-	RetentionOptions  *RetentionOptions `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
-	SnapshotEnabled   bool              `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
-	IndexOptions      *IndexOptions     `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	BootstrapEnabled      bool              `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
+	FlushEnabled          bool              `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
+	WritesToCommitLog     bool              `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
+	CleanupEnabled        bool              `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
+	RepairEnabled         bool              `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
+	RetentionOptions      *RetentionOptions `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
+	SnapshotEnabled       bool              `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
+	IndexOptions          *IndexOptions     `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	ShardRoutingTag       string            `protobuf:"bytes,9,opt,name=shardRoutingTag,proto3" json:"shardRoutingTag,omitempty"`
+	FloatPrecisionDigits  int32             `protobuf:"varint,10,opt,name=floatPrecisionDigits,proto3" json:"floatPrecisionDigits,omitempty"`
+	IndexSummariesPercent float64           `protobuf:"fixed64,11,opt,name=indexSummariesPercent,proto3" json:"indexSummariesPercent,omitempty"`
+	Priority              int32             `protobuf:"varint,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	Encoding              string            `protobuf:"bytes,13,opt,name=encoding,proto3" json:"encoding,omitempty"`
 }
 
 func (m *NamespaceOptions) Reset()                    { *m = NamespaceOptions{} }
@@ -204,6 +227,41 @@ func (m *NamespaceOptions) GetIndexOptions() *IndexOptions {
 	return nil
 }
 
+func (m *NamespaceOptions) GetShardRoutingTag() string {
+	if m != nil {
+		return m.ShardRoutingTag
+	}
+	return ""
+}
+
+func (m *NamespaceOptions) GetFloatPrecisionDigits() int32 {
+	if m != nil {
+		return m.FloatPrecisionDigits
+	}
+	return 0
+}
+
+func (m *NamespaceOptions) GetIndexSummariesPercent() float64 {
+	if m != nil {
+		return m.IndexSummariesPercent
+	}
+	return 0
+}
+
+func (m *NamespaceOptions) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *NamespaceOptions) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
+
 type Registry struct {
 	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
 }
@@ -276,6 +334,12 @@ func (m *RetentionOptions) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockDataExpiryAfterNotAccessPeriodNanos))
 	}
+	if len(m.TimeZone) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.TimeZone)))
+		i += copy(dAtA[i:], m.TimeZone)
+	}
 	return i, nil
 }
 
@@ -309,6 +373,11 @@ func (m *IndexOptions) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
 	}
+	if m.InsertMode != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.InsertMode))
+	}
 	return i, nil
 }
 
@@ -407,6 +476,34 @@ func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if len(m.ShardRoutingTag) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.ShardRoutingTag)))
+		i += copy(dAtA[i:], m.ShardRoutingTag)
+	}
+	if m.FloatPrecisionDigits != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.FloatPrecisionDigits))
+	}
+	if m.IndexSummariesPercent != 0 {
+		dAtA[i] = 0x59
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.IndexSummariesPercent))))
+		i += 8
+	}
+	if m.Priority != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.Priority))
+	}
+	if len(m.Encoding) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Encoding)))
+		i += copy(dAtA[i:], m.Encoding)
+	}
 	return i, nil
 }
 
@@ -486,6 +583,10 @@ func (m *RetentionOptions) Size() (n int) {
 	if m.BlockDataExpiryAfterNotAccessPeriodNanos != 0 {
 		n += 1 + sovNamespace(uint64(m.BlockDataExpiryAfterNotAccessPeriodNanos))
 	}
+	l = len(m.TimeZone)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
 	return n
 }
 
@@ -498,6 +599,9 @@ func (m *IndexOptions) Size() (n int) {
 	if m.BlockSizeNanos != 0 {
 		n += 1 + sovNamespace(uint64(m.BlockSizeNanos))
 	}
+	if m.InsertMode != 0 {
+		n += 1 + sovNamespace(uint64(m.InsertMode))
+	}
 	return n
 }
 
@@ -530,6 +634,23 @@ func (m *NamespaceOptions) Size() (n int) {
 		l = m.IndexOptions.Size()
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	l = len(m.ShardRoutingTag)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.FloatPrecisionDigits != 0 {
+		n += 1 + sovNamespace(uint64(m.FloatPrecisionDigits))
+	}
+	if m.IndexSummariesPercent != 0 {
+		n += 9
+	}
+	if m.Priority != 0 {
+		n += 1 + sovNamespace(uint64(m.Priority))
+	}
+	l = len(m.Encoding)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
 	return n
 }
 
@@ -709,6 +830,35 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeZone", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TimeZone = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -798,6 +948,25 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InsertMode", wireType)
+			}
+			m.InsertMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InsertMode |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -1034,6 +1203,113 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardRoutingTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ShardRoutingTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FloatPrecisionDigits", wireType)
+			}
+			m.FloatPrecisionDigits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FloatPrecisionDigits |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexSummariesPercent", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.IndexSummariesPercent = float64(math.Float64frombits(v))
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			m.Priority = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Priority |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -1338,38 +1614,44 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdf, 0x6e, 0xd3, 0x30,
-	0x14, 0xc6, 0x49, 0xbb, 0x3f, 0xdd, 0x59, 0x61, 0xc1, 0x42, 0xa2, 0x02, 0xa9, 0x9a, 0x0a, 0x42,
-	0x15, 0x42, 0x8d, 0x68, 0x6f, 0x10, 0x5c, 0x8d, 0x51, 0x26, 0x24, 0x54, 0x2a, 0xc3, 0xd5, 0xee,
-	0x9c, 0xe4, 0xb4, 0xb5, 0xd6, 0xd8, 0x91, 0xed, 0xc0, 0xca, 0x43, 0x20, 0xde, 0x83, 0x17, 0xe1,
-	0x82, 0x0b, 0x1e, 0x01, 0x95, 0x17, 0x41, 0x71, 0x48, 0x97, 0xb8, 0xbb, 0xd8, 0x4d, 0xe5, 0x7e,
-	0xe7, 0x67, 0x1f, 0xe7, 0x7c, 0x5f, 0x02, 0x67, 0x73, 0x6e, 0x16, 0x59, 0x38, 0x88, 0x64, 0x12,
-	0x24, 0xa3, 0x38, 0x0c, 0x92, 0x51, 0xa0, 0x55, 0x14, 0xc4, 0xa1, 0x90, 0x31, 0x06, 0x73, 0x14,
-	0xa8, 0x98, 0xc1, 0x38, 0x48, 0x95, 0x34, 0x32, 0x10, 0x2c, 0x41, 0x9d, 0xb2, 0x08, 0xaf, 0x56,
-	0x03, 0x5b, 0x21, 0x07, 0x1b, 0xa1, 0xf7, 0xab, 0x01, 0x3e, 0x45, 0x83, 0xc2, 0x70, 0x29, 0x3e,
-	0xa4, 0xf9, 0xaf, 0x26, 0x43, 0xb8, 0xa7, 0x4a, 0x6d, 0x8a, 0x8a, 0xcb, 0x78, 0xc2, 0x84, 0xd4,
-	0x1d, 0xef, 0xd8, 0xeb, 0x37, 0xe9, 0xb5, 0x35, 0xf2, 0x04, 0xee, 0x84, 0x4b, 0x19, 0x5d, 0x7c,
-	0xe4, 0x5f, 0xb1, 0xa0, 0x1b, 0x96, 0x76, 0x54, 0xf2, 0x0c, 0xee, 0x86, 0xd9, 0x6c, 0x86, 0xea,
-	0x6d, 0x66, 0x32, 0xf5, 0x1f, 0x6d, 0x5a, 0x74, 0xbb, 0x40, 0xfa, 0x70, 0x54, 0x88, 0x53, 0xa6,
-	0x4d, 0xc1, 0xee, 0x58, 0xd6, 0x95, 0x2d, 0x99, 0x77, 0x7a, 0xc3, 0x0c, 0x1b, 0x5f, 0xa6, 0x5c,
-	0xad, 0x3a, 0xbb, 0xc7, 0x5e, 0xbf, 0x45, 0x5d, 0x99, 0x9c, 0x43, 0xdf, 0x91, 0x4e, 0x66, 0x06,
-	0xd5, 0x44, 0x9a, 0x93, 0x28, 0x42, 0xad, 0xab, 0x4f, 0xbc, 0x67, 0x9b, 0xdd, 0x98, 0xef, 0x4d,
-	0xa1, 0xfd, 0x4e, 0xc4, 0x78, 0x59, 0x4e, 0xb2, 0x03, 0xfb, 0x28, 0x58, 0xb8, 0xc4, 0xd8, 0x0e,
-	0xaf, 0x45, 0xcb, 0xbf, 0x37, 0x9d, 0x57, 0xef, 0x5b, 0x13, 0xfc, 0x49, 0x69, 0x57, 0x79, 0xec,
-	0x53, 0xf0, 0x43, 0x29, 0x8d, 0x36, 0x8a, 0xa5, 0xe3, 0xda, 0xf9, 0x5b, 0x3a, 0xe9, 0x41, 0x7b,
-	0xb6, 0xcc, 0xf4, 0xa2, 0xe4, 0x1a, 0x96, 0xab, 0x69, 0xb9, 0x29, 0x5f, 0x14, 0x37, 0xa8, 0x3f,
-	0xc9, 0x53, 0x99, 0x24, 0xdc, 0xbc, 0x97, 0x73, 0x6b, 0x4a, 0x8b, 0x6e, 0x17, 0xf2, 0xab, 0x47,
-	0x4b, 0x64, 0x22, 0xdb, 0xf4, 0xde, 0xb1, 0xa8, 0xa3, 0x92, 0xc7, 0x70, 0x5b, 0x61, 0xca, 0xb8,
-	0x2a, 0xb1, 0xc2, 0x90, 0xba, 0x48, 0xce, 0xc0, 0x57, 0x4e, 0x00, 0xed, 0xd8, 0x0f, 0x87, 0x0f,
-	0x07, 0x57, 0xc1, 0x75, 0x33, 0x4a, 0xb7, 0x36, 0xe5, 0x09, 0xd0, 0x82, 0xa5, 0x7a, 0x21, 0x4d,
-	0xd9, 0x70, 0xbf, 0x48, 0x80, 0x23, 0x93, 0x57, 0xd0, 0xe6, 0x15, 0x97, 0x3a, 0x2d, 0xdb, 0xee,
-	0x7e, 0xa5, 0x5d, 0xd5, 0x44, 0x5a, 0x83, 0x7b, 0x3f, 0x3c, 0x68, 0x51, 0x9c, 0x73, 0x6d, 0xd4,
-	0x8a, 0x9c, 0x02, 0x6c, 0x36, 0xe5, 0xef, 0x47, 0xb3, 0x7f, 0x38, 0x7c, 0x54, 0xbb, 0x76, 0x01,
-	0x0e, 0x36, 0x16, 0xea, 0xb1, 0x30, 0x6a, 0x45, 0x2b, 0xdb, 0x1e, 0x9c, 0xc3, 0x91, 0x53, 0x26,
-	0x3e, 0x34, 0x2f, 0x70, 0x65, 0x3d, 0x3d, 0xa0, 0xf9, 0x92, 0x3c, 0x87, 0xdd, 0xcf, 0x6c, 0x99,
-	0xa1, 0xf5, 0xaf, 0x3e, 0x1b, 0x37, 0x1e, 0xb4, 0x20, 0x5f, 0x36, 0x5e, 0x78, 0xaf, 0xfd, 0x9f,
-	0xeb, 0xae, 0xf7, 0x7b, 0xdd, 0xf5, 0xfe, 0xac, 0xbb, 0xde, 0xf7, 0xbf, 0xdd, 0x5b, 0xe1, 0x9e,
-	0xfd, 0x06, 0x8c, 0xfe, 0x05, 0x00, 0x00, 0xff, 0xff, 0x30, 0x42, 0xc6, 0x09, 0x4e, 0x04, 0x00,
-	0x00,
+	// 622 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x14, 0x24, 0x49, 0xd3, 0x24, 0xaf, 0x29, 0x35, 0x2b, 0x10, 0x56, 0x91, 0x22, 0x14, 0x10, 0x8a,
+	0x10, 0x4a, 0x44, 0xcb, 0x01, 0xc1, 0xa9, 0xb4, 0xa5, 0x42, 0x82, 0x50, 0x6d, 0x7b, 0xea, 0x6d,
+	0x6d, 0xbf, 0x24, 0xab, 0xc6, 0xbb, 0xd6, 0xee, 0x1a, 0x1a, 0xbe, 0x82, 0xff, 0xe0, 0x47, 0x38,
+	0x72, 0xe6, 0x84, 0xe0, 0xc2, 0x67, 0xb0, 0x5e, 0xe3, 0x34, 0x71, 0x72, 0xe8, 0xc1, 0x96, 0xdf,
+	0xbc, 0x59, 0x8f, 0xf7, 0xcd, 0x78, 0xe1, 0x64, 0xcc, 0xcd, 0x24, 0x0d, 0xfa, 0xa1, 0x8c, 0x07,
+	0xf1, 0x7e, 0x14, 0xd8, 0xdb, 0x40, 0xab, 0x70, 0x10, 0x05, 0x42, 0x46, 0x38, 0x18, 0xa3, 0x40,
+	0xc5, 0x0c, 0x46, 0x83, 0x44, 0x49, 0x23, 0x07, 0x82, 0xc5, 0xa8, 0x13, 0x16, 0xe2, 0xf5, 0x53,
+	0xdf, 0x75, 0x48, 0x6b, 0x0e, 0x74, 0xff, 0x56, 0xc1, 0xa3, 0x68, 0x50, 0x18, 0x2e, 0xc5, 0xc7,
+	0x24, 0xbb, 0x6b, 0xb2, 0x07, 0x77, 0x55, 0x81, 0x9d, 0xa2, 0xe2, 0x32, 0x1a, 0x32, 0x21, 0xb5,
+	0x5f, 0x79, 0x58, 0xe9, 0xd5, 0xe8, 0xda, 0x1e, 0x79, 0x02, 0xb7, 0x83, 0xa9, 0x0c, 0x2f, 0xcf,
+	0xf8, 0x17, 0xcc, 0xd9, 0x55, 0xc7, 0x2e, 0xa1, 0xe4, 0x19, 0xdc, 0x09, 0xd2, 0xd1, 0x08, 0xd5,
+	0xdb, 0xd4, 0xa4, 0xea, 0x3f, 0xb5, 0xe6, 0xa8, 0xab, 0x0d, 0xd2, 0x83, 0x9d, 0x1c, 0x3c, 0x65,
+	0xda, 0xe4, 0xdc, 0x0d, 0xc7, 0x2d, 0xc3, 0x8e, 0x99, 0x29, 0x1d, 0x31, 0xc3, 0x8e, 0xaf, 0x12,
+	0xae, 0x66, 0x7e, 0xdd, 0x32, 0x9b, 0xb4, 0x0c, 0x93, 0x0b, 0xe8, 0x95, 0xa0, 0x83, 0x91, 0x41,
+	0x35, 0x94, 0xe6, 0x20, 0x0c, 0x51, 0xeb, 0xc5, 0x1d, 0x6f, 0x3a, 0xb1, 0x1b, 0xf3, 0xc9, 0x2e,
+	0x34, 0x0d, 0x8f, 0xf1, 0x42, 0x0a, 0xf4, 0x1b, 0x76, 0x6d, 0x8b, 0xce, 0xeb, 0x6e, 0x02, 0xed,
+	0x77, 0x22, 0xc2, 0xab, 0x62, 0xca, 0x3e, 0x34, 0x50, 0xb0, 0x60, 0x8a, 0x91, 0x1b, 0x6c, 0x93,
+	0x16, 0xe5, 0x8d, 0x67, 0xd9, 0x01, 0xe0, 0x42, 0xa3, 0x32, 0x1f, 0xac, 0xff, 0x6e, 0x88, 0x75,
+	0xba, 0x80, 0x74, 0x7f, 0x6e, 0x80, 0x37, 0x2c, 0xac, 0x2e, 0x64, 0x9f, 0x82, 0x17, 0x48, 0x69,
+	0xb4, 0x51, 0x2c, 0x39, 0x5e, 0xd2, 0x5f, 0xc1, 0x49, 0x17, 0xda, 0xa3, 0x69, 0xaa, 0x27, 0x05,
+	0xaf, 0xea, 0x78, 0x4b, 0x58, 0x66, 0xe8, 0x67, 0xc5, 0x0d, 0xea, 0x73, 0x79, 0x28, 0xe3, 0x98,
+	0x9b, 0xf7, 0x72, 0xec, 0xbe, 0xa5, 0x49, 0x57, 0x1b, 0xd9, 0xd6, 0xc2, 0x29, 0x32, 0x91, 0xce,
+	0xb5, 0x37, 0x1c, 0xb5, 0x84, 0x92, 0xc7, 0xb0, 0xad, 0x30, 0x61, 0x5c, 0x15, 0xb4, 0xdc, 0xcc,
+	0x65, 0x90, 0x9c, 0x80, 0xa7, 0x4a, 0xe1, 0x75, 0x96, 0x6d, 0xed, 0x3d, 0xe8, 0x5f, 0x87, 0xbe,
+	0x9c, 0x6f, 0xba, 0xb2, 0x28, 0x4b, 0x8f, 0x16, 0x2c, 0xd1, 0x13, 0x69, 0x0a, 0xc1, 0x46, 0x9e,
+	0x9e, 0x12, 0x4c, 0x5e, 0x43, 0x9b, 0x2f, 0xb8, 0xe8, 0x37, 0x9d, 0xdc, 0xfd, 0x05, 0xb9, 0x45,
+	0x93, 0xe9, 0x12, 0xd9, 0xc9, 0x4c, 0x98, 0x8a, 0xa8, 0x4c, 0x0d, 0x17, 0xe3, 0x73, 0x36, 0xf6,
+	0x5b, 0x2e, 0x25, 0x65, 0x38, 0xfb, 0x05, 0x47, 0x53, 0xc9, 0xcc, 0xa9, 0xc2, 0x90, 0x6b, 0xbb,
+	0xf8, 0x88, 0xdb, 0xbf, 0x5f, 0xfb, 0xe0, 0x4c, 0x5e, 0xdb, 0x23, 0x2f, 0xe0, 0x9e, 0x53, 0x3b,
+	0x4b, 0xe3, 0x98, 0x29, 0x8e, 0x59, 0x32, 0x43, 0xbb, 0x4f, 0x7f, 0xcb, 0x2e, 0xaa, 0xd0, 0xf5,
+	0xcd, 0x2c, 0xb2, 0x89, 0x0d, 0xb0, 0x75, 0x6a, 0xe6, 0xb7, 0xdd, 0xdb, 0xe7, 0x75, 0xd6, 0x43,
+	0x11, 0xca, 0xc8, 0x7e, 0x94, 0xbf, 0x9d, 0xc7, 0xb9, 0xa8, 0xbb, 0xdf, 0x2a, 0xd0, 0xa4, 0x38,
+	0xe6, 0x36, 0x30, 0x33, 0x72, 0x08, 0x30, 0x1f, 0x40, 0x76, 0x4e, 0xd4, 0xec, 0x4c, 0x1e, 0x2d,
+	0x59, 0x90, 0x13, 0xfb, 0xf3, 0x38, 0xea, 0x63, 0x61, 0x6b, 0xba, 0xb0, 0x6c, 0xf7, 0x02, 0x76,
+	0x4a, 0x6d, 0xe2, 0x41, 0xed, 0x12, 0x67, 0x2e, 0x9f, 0x2d, 0x9a, 0x3d, 0x92, 0xe7, 0x50, 0xff,
+	0xc4, 0xa6, 0x29, 0xba, 0x2c, 0x2e, 0xfb, 0x5c, 0x8e, 0x3a, 0xcd, 0x99, 0xaf, 0xaa, 0x2f, 0x2b,
+	0x6f, 0xbc, 0xef, 0xbf, 0x3b, 0x95, 0x1f, 0xf6, 0xfa, 0x65, 0xaf, 0xaf, 0x7f, 0x3a, 0xb7, 0x82,
+	0x4d, 0x77, 0x16, 0xee, 0xff, 0x03, 0xcc, 0xfc, 0x52, 0x43, 0x56, 0x05, 0x00, 0x00,
 }
@@ -29,7 +29,8 @@ enum TimeType {
 
 enum ErrorType {
 	INTERNAL_ERROR,
-	BAD_REQUEST
+	BAD_REQUEST,
+	RESOURCE_EXHAUSTED
 }
 
 exception Error {
@@ -56,8 +57,8 @@ service Node {
 	// TODO(rartoul): Delete this once we delete the V1 code path
 	FetchBlocksMetadataRawResult fetchBlocksMetadataRaw(1: FetchBlocksMetadataRawRequest req) throws (1: Error err)
 	FetchBlocksMetadataRawV2Result fetchBlocksMetadataRawV2(1: FetchBlocksMetadataRawV2Request req) throws (1: Error err)
-	void writeBatchRaw(1: WriteBatchRawRequest req) throws (1: WriteBatchRawErrors err)
-	void writeTaggedBatchRaw(1: WriteTaggedBatchRawRequest req) throws (1: WriteBatchRawErrors err)
+	WriteBatchRawResult writeBatchRaw(1: WriteBatchRawRequest req) throws (1: WriteBatchRawErrors err)
+	WriteBatchRawResult writeTaggedBatchRaw(1: WriteTaggedBatchRawRequest req) throws (1: WriteBatchRawErrors err)
 	void repair() throws (1: Error err)
 	TruncateResult truncate(1: TruncateRequest req) throws (1: Error err)
 
@@ -126,6 +127,7 @@ struct FetchRawResult {
 struct Segments {
 	1: optional Segment merged
 	2: optional list<Segment> unmerged
+	3: optional string encoding
 }
 
 struct Segment {
@@ -143,11 +145,14 @@ struct FetchTaggedRequest {
 	5: required bool fetchData
 	6: optional i64 limit
 	7: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	8: optional bool countOnly
+	9: optional list<binary> additionalNameSpaces
 }
 
 struct FetchTaggedResult {
 	1: required list<FetchTaggedIDResult> elements
 	2: required bool exhaustive
+	3: optional map<i32,i64> shardCounts
 }
 
 struct FetchTaggedIDResult {
@@ -279,6 +284,10 @@ struct WriteBatchRawError {
 	2: required Error err
 }
 
+struct WriteBatchRawResult {
+	1: optional list<i64> newSeriesIndexes
+}
+
 struct TruncateRequest {
 	1: required binary nameSpace
 }
@@ -291,6 +300,8 @@ struct NodeHealthResult {
 	1: required bool ok
 	2: required string status
 	3: required bool bootstrapped
+	4: optional i32 apiVersion
+	5: optional i64 capabilities
 }
 
 struct NodePersistRateLimitResult {
@@ -107,8 +107,9 @@ func (p *TimeType) Value() (driver.Value, error) {
 type ErrorType int64
 
 const (
-	ErrorType_INTERNAL_ERROR ErrorType = 0
-	ErrorType_BAD_REQUEST    ErrorType = 1
+	ErrorType_INTERNAL_ERROR     ErrorType = 0
+	ErrorType_BAD_REQUEST        ErrorType = 1
+	ErrorType_RESOURCE_EXHAUSTED ErrorType = 2
 )
 
 func (p ErrorType) String() string {
@@ -117,6 +118,8 @@ func (p ErrorType) String() string {
 		return "INTERNAL_ERROR"
 	case ErrorType_BAD_REQUEST:
 		return "BAD_REQUEST"
+	case ErrorType_RESOURCE_EXHAUSTED:
+		return "RESOURCE_EXHAUSTED"
 	}
 	return "<UNSET>"
 }
@@ -127,6 +130,8 @@ func ErrorTypeFromString(s string) (ErrorType, error) {
 		return ErrorType_INTERNAL_ERROR, nil
 	case "BAD_REQUEST":
 		return ErrorType_BAD_REQUEST, nil
+	case "RESOURCE_EXHAUSTED":
+		return ErrorType_RESOURCE_EXHAUSTED, nil
 	}
 	return ErrorType(0), fmt.Errorf("not a valid ErrorType string")
 }
@@ -2098,9 +2103,11 @@ func (p *FetchRawResult_) String() string {
 // Attributes:
 //  - Merged
 //  - Unmerged
+//  - Encoding
 type Segments struct {
 	Merged   *Segment   `thrift:"merged,1" db:"merged" json:"merged,omitempty"`
 	Unmerged []*Segment `thrift:"unmerged,2" db:"unmerged" json:"unmerged,omitempty"`
+	Encoding *string    `thrift:"encoding,3" db:"encoding" json:"encoding,omitempty"`
 }
 
 func NewSegments() *Segments {
@@ -2121,6 +2128,15 @@ var Segments_Unmerged_DEFAULT []*Segment
 func (p *Segments) GetUnmerged() []*Segment {
 	return p.Unmerged
 }
+
+var Segments_Encoding_DEFAULT string
+
+func (p *Segments) GetEncoding() string {
+	if !p.IsSetEncoding() {
+		return Segments_Encoding_DEFAULT
+	}
+	return *p.Encoding
+}
 func (p *Segments) IsSetMerged() bool {
 	return p.Merged != nil
 }
@@ -2129,6 +2145,10 @@ func (p *Segments) IsSetUnmerged() bool {
 	return p.Unmerged != nil
 }
 
+func (p *Segments) IsSetEncoding() bool {
+	return p.Encoding != nil
+}
+
 func (p *Segments) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2151,6 +2171,10 @@ func (p *Segments) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField2(iprot); err != nil {
 				return err
 			}
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -2194,6 +2218,15 @@ func (p *Segments) ReadField2(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *Segments) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.Encoding = &v
+	}
+	return nil
+}
+
 func (p *Segments) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("Segments"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -2205,6 +2238,9 @@ func (p *Segments) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField2(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -2253,6 +2289,21 @@ func (p *Segments) writeField2(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *Segments) writeField3(oprot thrift.TProtocol) (err error) {
+	if p.IsSetEncoding() {
+		if err := oprot.WriteFieldBegin("encoding", thrift.STRING, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:encoding: ", p), err)
+		}
+		if err := oprot.WriteString(string(*p.Encoding)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.encoding (3) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:encoding: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *Segments) String() string {
 	if p == nil {
 		return "<nil>"
@@ -2499,14 +2550,18 @@ func (p *Segment) String() string {
 //  - FetchData
 //  - Limit
 //  - RangeTimeType
+//  - CountOnly
+//  - AdditionalNameSpaces
 type FetchTaggedRequest struct {
-	NameSpace     []byte   `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
-	Query         []byte   `thrift:"query,2,required" db:"query" json:"query"`
-	RangeStart    int64    `thrift:"rangeStart,3,required" db:"rangeStart" json:"rangeStart"`
-	RangeEnd      int64    `thrift:"rangeEnd,4,required" db:"rangeEnd" json:"rangeEnd"`
-	FetchData     bool     `thrift:"fetchData,5,required" db:"fetchData" json:"fetchData"`
-	Limit         *int64   `thrift:"limit,6" db:"limit" json:"limit,omitempty"`
-	RangeTimeType TimeType `thrift:"rangeTimeType,7" db:"rangeTimeType" json:"rangeTimeType,omitempty"`
+	NameSpace            []byte   `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	Query                []byte   `thrift:"query,2,required" db:"query" json:"query"`
+	RangeStart           int64    `thrift:"rangeStart,3,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd             int64    `thrift:"rangeEnd,4,required" db:"rangeEnd" json:"rangeEnd"`
+	FetchData            bool     `thrift:"fetchData,5,required" db:"fetchData" json:"fetchData"`
+	Limit                *int64   `thrift:"limit,6" db:"limit" json:"limit,omitempty"`
+	RangeTimeType        TimeType `thrift:"rangeTimeType,7" db:"rangeTimeType" json:"rangeTimeType,omitempty"`
+	CountOnly            *bool    `thrift:"countOnly,8" db:"countOnly" json:"countOnly,omitempty"`
+	AdditionalNameSpaces [][]byte `thrift:"additionalNameSpaces,9" db:"additionalNameSpaces" json:"additionalNameSpaces,omitempty"`
 }
 
 func NewFetchTaggedRequest() *FetchTaggedRequest {
@@ -2549,6 +2604,21 @@ var FetchTaggedRequest_RangeTimeType_DEFAULT TimeType = 0
 func (p *FetchTaggedRequest) GetRangeTimeType() TimeType {
 	return p.RangeTimeType
 }
+
+var FetchTaggedRequest_CountOnly_DEFAULT bool
+
+func (p *FetchTaggedRequest) GetCountOnly() bool {
+	if !p.IsSetCountOnly() {
+		return FetchTaggedRequest_CountOnly_DEFAULT
+	}
+	return *p.CountOnly
+}
+
+var FetchTaggedRequest_AdditionalNameSpaces_DEFAULT [][]byte
+
+func (p *FetchTaggedRequest) GetAdditionalNameSpaces() [][]byte {
+	return p.AdditionalNameSpaces
+}
 func (p *FetchTaggedRequest) IsSetLimit() bool {
 	return p.Limit != nil
 }
@@ -2557,6 +2627,14 @@ func (p *FetchTaggedRequest) IsSetRangeTimeType() bool {
 	return p.RangeTimeType != FetchTaggedRequest_RangeTimeType_DEFAULT
 }
 
+func (p *FetchTaggedRequest) IsSetCountOnly() bool {
+	return p.CountOnly != nil
+}
+
+func (p *FetchTaggedRequest) IsSetAdditionalNameSpaces() bool {
+	return p.AdditionalNameSpaces != nil
+}
+
 func (p *FetchTaggedRequest) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2610,6 +2688,14 @@ func (p *FetchTaggedRequest) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField7(iprot); err != nil {
 				return err
 			}
+		case 8:
+			if err := p.ReadField8(iprot); err != nil {
+				return err
+			}
+		case 9:
+			if err := p.ReadField9(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -2704,6 +2790,37 @@ func (p *FetchTaggedRequest) ReadField7(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchTaggedRequest) ReadField8(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.CountOnly = &v
+	}
+	return nil
+}
+
+func (p *FetchTaggedRequest) ReadField9(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([][]byte, 0, size)
+	p.AdditionalNameSpaces = tSlice
+	for i := 0; i < size; i++ {
+		var _elem23 []byte
+		if v, err := iprot.ReadBinary(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem23 = v
+		}
+		p.AdditionalNameSpaces = append(p.AdditionalNameSpaces, _elem23)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
 func (p *FetchTaggedRequest) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchTaggedRequest"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -2730,6 +2847,12 @@ func (p *FetchTaggedRequest) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField7(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField8(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -2835,6 +2958,44 @@ func (p *FetchTaggedRequest) writeField7(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchTaggedRequest) writeField8(oprot thrift.TProtocol) (err error) {
+	if p.IsSetCountOnly() {
+		if err := oprot.WriteFieldBegin("countOnly", thrift.BOOL, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:countOnly: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.CountOnly)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.countOnly (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:countOnly: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FetchTaggedRequest) writeField9(oprot thrift.TProtocol) (err error) {
+	if p.IsSetAdditionalNameSpaces() {
+		if err := oprot.WriteFieldBegin("additionalNameSpaces", thrift.LIST, 9); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:additionalNameSpaces: ", p), err)
+		}
+		if err := oprot.WriteListBegin(thrift.STRING, len(p.AdditionalNameSpaces)); err != nil {
+			return thrift.PrependError("error writing list begin: ", err)
+		}
+		for _, v := range p.AdditionalNameSpaces {
+			if err := oprot.WriteBinary(v); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+			}
+		}
+		if err := oprot.WriteListEnd(); err != nil {
+			return thrift.PrependError("error writing list end: ", err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 9:additionalNameSpaces: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchTaggedRequest) String() string {
 	if p == nil {
 		return "<nil>"
@@ -2845,9 +3006,11 @@ func (p *FetchTaggedRequest) String() string {
 // Attributes:
 //  - Elements
 //  - Exhaustive
+//  - ShardCounts
 type FetchTaggedResult_ struct {
-	Elements   []*FetchTaggedIDResult_ `thrift:"elements,1,required" db:"elements" json:"elements"`
-	Exhaustive bool                    `thrift:"exhaustive,2,required" db:"exhaustive" json:"exhaustive"`
+	Elements    []*FetchTaggedIDResult_ `thrift:"elements,1,required" db:"elements" json:"elements"`
+	Exhaustive  bool                    `thrift:"exhaustive,2,required" db:"exhaustive" json:"exhaustive"`
+	ShardCounts map[int32]int64         `thrift:"shardCounts,3" db:"shardCounts" json:"shardCounts,omitempty"`
 }
 
 func NewFetchTaggedResult_() *FetchTaggedResult_ {
@@ -2861,6 +3024,15 @@ func (p *FetchTaggedResult_) GetElements() []*FetchTaggedIDResult_ {
 func (p *FetchTaggedResult_) GetExhaustive() bool {
 	return p.Exhaustive
 }
+
+var FetchTaggedResult__ShardCounts_DEFAULT map[int32]int64
+
+func (p *FetchTaggedResult_) GetShardCounts() map[int32]int64 {
+	return p.ShardCounts
+}
+func (p *FetchTaggedResult_) IsSetShardCounts() bool {
+	return p.ShardCounts != nil
+}
 func (p *FetchTaggedResult_) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2888,6 +3060,10 @@ func (p *FetchTaggedResult_) Read(iprot thrift.TProtocol) error {
 				return err
 			}
 			issetExhaustive = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -2938,6 +3114,34 @@ func (p *FetchTaggedResult_) ReadField2(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchTaggedResult_) ReadField3(iprot thrift.TProtocol) error {
+	_, _, size, err := iprot.ReadMapBegin()
+	if err != nil {
+		return thrift.PrependError("error reading map begin: ", err)
+	}
+	tMap := make(map[int32]int64, size)
+	p.ShardCounts = tMap
+	for i := 0; i < size; i++ {
+		var _key8 int32
+		if v, err := iprot.ReadI32(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_key8 = v
+		}
+		var _val9 int64
+		if v, err := iprot.ReadI64(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_val9 = v
+		}
+		p.ShardCounts[_key8] = _val9
+	}
+	if err := iprot.ReadMapEnd(); err != nil {
+		return thrift.PrependError("error reading map end: ", err)
+	}
+	return nil
+}
+
 func (p *FetchTaggedResult_) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchTaggedResult"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -2949,6 +3153,9 @@ func (p *FetchTaggedResult_) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField2(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -2993,6 +3200,32 @@ func (p *FetchTaggedResult_) writeField2(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchTaggedResult_) writeField3(oprot thrift.TProtocol) (err error) {
+	if p.IsSetShardCounts() {
+		if err := oprot.WriteFieldBegin("shardCounts", thrift.MAP, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:shardCounts: ", p), err)
+		}
+		if err := oprot.WriteMapBegin(thrift.I32, thrift.I64, len(p.ShardCounts)); err != nil {
+			return thrift.PrependError("error writing map begin: ", err)
+		}
+		for k, v := range p.ShardCounts {
+			if err := oprot.WriteI32(int32(k)); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+			}
+			if err := oprot.WriteI64(int64(v)); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+			}
+		}
+		if err := oprot.WriteMapEnd(); err != nil {
+			return thrift.PrependError("error writing map end: ", err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:shardCounts: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchTaggedResult_) String() string {
 	if p == nil {
 		return "<nil>"
@@ -7214,6 +7447,128 @@ func (p *WriteBatchRawError) String() string {
 	return fmt.Sprintf("WriteBatchRawError(%+v)", *p)
 }
 
+// Attributes:
+//  - NewSeriesIndexes
+type WriteBatchRawResult_ struct {
+	NewSeriesIndexes []int64 `thrift:"newSeriesIndexes,1" db:"newSeriesIndexes" json:"newSeriesIndexes,omitempty"`
+}
+
+func NewWriteBatchRawResult_() *WriteBatchRawResult_ {
+	return &WriteBatchRawResult_{}
+}
+
+var WriteBatchRawResult__NewSeriesIndexes_DEFAULT []int64
+
+func (p *WriteBatchRawResult_) GetNewSeriesIndexes() []int64 {
+	return p.NewSeriesIndexes
+}
+func (p *WriteBatchRawResult_) IsSetNewSeriesIndexes() bool {
+	return p.NewSeriesIndexes != nil
+}
+
+func (p *WriteBatchRawResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *WriteBatchRawResult_) ReadField1(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]int64, 0, size)
+	p.NewSeriesIndexes = tSlice
+	for i := 0; i < size; i++ {
+		var _elem19 int64
+		if v, err := iprot.ReadI64(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem19 = v
+		}
+		p.NewSeriesIndexes = append(p.NewSeriesIndexes, _elem19)
+	}
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *WriteBatchRawResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("WriteBatchRawResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *WriteBatchRawResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetNewSeriesIndexes() {
+		if err := oprot.WriteFieldBegin("newSeriesIndexes", thrift.LIST, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:newSeriesIndexes: ", p), err)
+		}
+		if err := oprot.WriteListBegin(thrift.I64, len(p.NewSeriesIndexes)); err != nil {
+			return thrift.PrependError("error writing list begin: ", err)
+		}
+		for _, v := range p.NewSeriesIndexes {
+			if err := oprot.WriteI64(int64(v)); err != nil {
+				return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+			}
+		}
+		if err := oprot.WriteListEnd(); err != nil {
+			return thrift.PrependError("error writing list end: ", err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:newSeriesIndexes: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *WriteBatchRawResult_) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("WriteBatchRawResult_(%+v)", *p)
+}
+
 // Attributes:
 //  - NameSpace
 type TruncateRequest struct {
@@ -7416,10 +7771,14 @@ func (p *TruncateResult_) String() string {
 //  - Ok
 //  - Status
 //  - Bootstrapped
+//  - ApiVersion
+//  - Capabilities
 type NodeHealthResult_ struct {
 	Ok           bool   `thrift:"ok,1,required" db:"ok" json:"ok"`
 	Status       string `thrift:"status,2,required" db:"status" json:"status"`
 	Bootstrapped bool   `thrift:"bootstrapped,3,required" db:"bootstrapped" json:"bootstrapped"`
+	ApiVersion   *int32 `thrift:"apiVersion,4" db:"apiVersion" json:"apiVersion,omitempty"`
+	Capabilities *int64 `thrift:"capabilities,5" db:"capabilities" json:"capabilities,omitempty"`
 }
 
 func NewNodeHealthResult_() *NodeHealthResult_ {
@@ -7437,6 +7796,31 @@ func (p *NodeHealthResult_) GetStatus() string {
 func (p *NodeHealthResult_) GetBootstrapped() bool {
 	return p.Bootstrapped
 }
+
+var NodeHealthResult__ApiVersion_DEFAULT int32
+
+func (p *NodeHealthResult_) GetApiVersion() int32 {
+	if !p.IsSetApiVersion() {
+		return NodeHealthResult__ApiVersion_DEFAULT
+	}
+	return *p.ApiVersion
+}
+
+var NodeHealthResult__Capabilities_DEFAULT int64
+
+func (p *NodeHealthResult_) GetCapabilities() int64 {
+	if !p.IsSetCapabilities() {
+		return NodeHealthResult__Capabilities_DEFAULT
+	}
+	return *p.Capabilities
+}
+func (p *NodeHealthResult_) IsSetApiVersion() bool {
+	return p.ApiVersion != nil
+}
+
+func (p *NodeHealthResult_) IsSetCapabilities() bool {
+	return p.Capabilities != nil
+}
 func (p *NodeHealthResult_) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -7470,6 +7854,14 @@ func (p *NodeHealthResult_) Read(iprot thrift.TProtocol) error {
 				return err
 			}
 			issetBootstrapped = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -7521,6 +7913,24 @@ func (p *NodeHealthResult_) ReadField3(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *NodeHealthResult_) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.ApiVersion = &v
+	}
+	return nil
+}
+
+func (p *NodeHealthResult_) ReadField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.Capabilities = &v
+	}
+	return nil
+}
+
 func (p *NodeHealthResult_) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("NodeHealthResult"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -7535,6 +7945,12 @@ func (p *NodeHealthResult_) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField3(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -7584,6 +8000,36 @@ func (p *NodeHealthResult_) writeField3(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *NodeHealthResult_) writeField4(oprot thrift.TProtocol) (err error) {
+	if p.IsSetApiVersion() {
+		if err := oprot.WriteFieldBegin("apiVersion", thrift.I32, 4); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:apiVersion: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(*p.ApiVersion)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.apiVersion (4) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 4:apiVersion: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeHealthResult_) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetCapabilities() {
+		if err := oprot.WriteFieldBegin("capabilities", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:capabilities: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.Capabilities)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.capabilities (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:capabilities: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *NodeHealthResult_) String() string {
 	if p == nil {
 		return "<nil>"
@@ -10508,10 +10954,10 @@ type Node interface {
 	FetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (r *FetchBlocksMetadataRawV2Result_, err error)
 	// Parameters:
 	//  - Req
-	WriteBatchRaw(req *WriteBatchRawRequest) (err error)
+	WriteBatchRaw(req *WriteBatchRawRequest) (r *WriteBatchRawResult_, err error)
 	// Parameters:
 	//  - Req
-	WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error)
+	WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (r *WriteBatchRawResult_, err error)
 	Repair() (err error)
 	// Parameters:
 	//  - Req
@@ -11290,7 +11736,7 @@ func (p *NodeClient) recvFetchBlocksMetadataRawV2() (value *FetchBlocksMetadataR
 
 // Parameters:
 //  - Req
-func (p *NodeClient) WriteBatchRaw(req *WriteBatchRawRequest) (err error) {
+func (p *NodeClient) WriteBatchRaw(req *WriteBatchRawRequest) (r *WriteBatchRawResult_, err error) {
 	if err = p.sendWriteBatchRaw(req); err != nil {
 		return
 	}
@@ -11319,7 +11765,7 @@ func (p *NodeClient) sendWriteBatchRaw(req *WriteBatchRawRequest) (err error) {
 	return oprot.Flush()
 }
 
-func (p *NodeClient) recvWriteBatchRaw() (err error) {
+func (p *NodeClient) recvWriteBatchRaw() (value *WriteBatchRawResult_, err error) {
 	iprot := p.InputProtocol
 	if iprot == nil {
 		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
@@ -11365,12 +11811,13 @@ func (p *NodeClient) recvWriteBatchRaw() (err error) {
 		err = result.Err
 		return
 	}
+	value = result.GetSuccess()
 	return
 }
 
 // Parameters:
 //  - Req
-func (p *NodeClient) WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error) {
+func (p *NodeClient) WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (r *WriteBatchRawResult_, err error) {
 	if err = p.sendWriteTaggedBatchRaw(req); err != nil {
 		return
 	}
@@ -11399,7 +11846,7 @@ func (p *NodeClient) sendWriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (e
 	return oprot.Flush()
 }
 
-func (p *NodeClient) recvWriteTaggedBatchRaw() (err error) {
+func (p *NodeClient) recvWriteTaggedBatchRaw() (value *WriteBatchRawResult_, err error) {
 	iprot := p.InputProtocol
 	if iprot == nil {
 		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
@@ -11445,6 +11892,7 @@ func (p *NodeClient) recvWriteTaggedBatchRaw() (err error) {
 		err = result.Err
 		return
 	}
+	value = result.GetSuccess()
 	return
 }
 
@@ -12868,8 +13316,9 @@ func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 
 	iprot.ReadMessageEnd()
 	result := NodeWriteBatchRawResult{}
+	var retval *WriteBatchRawResult_
 	var err2 error
-	if err2 = p.handler.WriteBatchRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.WriteBatchRaw(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *WriteBatchRawErrors:
 			result.Err = v
@@ -12881,6 +13330,8 @@ func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
 	if err2 = oprot.WriteMessageBegin("writeBatchRaw", thrift.REPLY, seqId); err2 != nil {
 		err = err2
@@ -12918,8 +13369,9 @@ func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thr
 
 	iprot.ReadMessageEnd()
 	result := NodeWriteTaggedBatchRawResult{}
+	var retval *WriteBatchRawResult_
 	var err2 error
-	if err2 = p.handler.WriteTaggedBatchRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.WriteTaggedBatchRaw(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *WriteBatchRawErrors:
 			result.Err = v
@@ -12931,6 +13383,8 @@ func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thr
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
 	if err2 = oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.REPLY, seqId); err2 != nil {
 		err = err2
@@ -15815,15 +16269,26 @@ func (p *NodeWriteBatchRawArgs) String() string {
 }
 
 // Attributes:
+//  - Success
 //  - Err
 type NodeWriteBatchRawResult struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+	Success *WriteBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *WriteBatchRawErrors  `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
 func NewNodeWriteBatchRawResult() *NodeWriteBatchRawResult {
 	return &NodeWriteBatchRawResult{}
 }
 
+var NodeWriteBatchRawResult_Success_DEFAULT *WriteBatchRawResult_
+
+func (p *NodeWriteBatchRawResult) GetSuccess() *WriteBatchRawResult_ {
+	if !p.IsSetSuccess() {
+		return NodeWriteBatchRawResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
 var NodeWriteBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
 
 func (p *NodeWriteBatchRawResult) GetErr() *WriteBatchRawErrors {
@@ -15832,6 +16297,10 @@ func (p *NodeWriteBatchRawResult) GetErr() *WriteBatchRawErrors {
 	}
 	return p.Err
 }
+func (p *NodeWriteBatchRawResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
 func (p *NodeWriteBatchRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
@@ -15850,6 +16319,10 @@ func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -15869,6 +16342,14 @@ func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *NodeWriteBatchRawResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &WriteBatchRawResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
 func (p *NodeWriteBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
@@ -15882,6 +16363,9 @@ func (p *NodeWriteBatchRawResult) Write(oprot thrift.TProtocol) error {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -15895,6 +16379,21 @@ func (p *NodeWriteBatchRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *NodeWriteBatchRawResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *NodeWriteBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
@@ -16019,15 +16518,26 @@ func (p *NodeWriteTaggedBatchRawArgs) String() string {
 }
 
 // Attributes:
+//  - Success
 //  - Err
 type NodeWriteTaggedBatchRawResult struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+	Success *WriteBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *WriteBatchRawErrors  `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
 func NewNodeWriteTaggedBatchRawResult() *NodeWriteTaggedBatchRawResult {
 	return &NodeWriteTaggedBatchRawResult{}
 }
 
+var NodeWriteTaggedBatchRawResult_Success_DEFAULT *WriteBatchRawResult_
+
+func (p *NodeWriteTaggedBatchRawResult) GetSuccess() *WriteBatchRawResult_ {
+	if !p.IsSetSuccess() {
+		return NodeWriteTaggedBatchRawResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
 var NodeWriteTaggedBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
 
 func (p *NodeWriteTaggedBatchRawResult) GetErr() *WriteBatchRawErrors {
@@ -16036,6 +16546,10 @@ func (p *NodeWriteTaggedBatchRawResult) GetErr() *WriteBatchRawErrors {
 	}
 	return p.Err
 }
+func (p *NodeWriteTaggedBatchRawResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
 func (p *NodeWriteTaggedBatchRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
@@ -16054,6 +16568,10 @@ func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -16073,6 +16591,14 @@ func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *NodeWriteTaggedBatchRawResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &WriteBatchRawResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
 func (p *NodeWriteTaggedBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
@@ -16086,6 +16612,9 @@ func (p *NodeWriteTaggedBatchRawResult) Write(oprot thrift.TProtocol) error {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -16099,6 +16628,21 @@ func (p *NodeWriteTaggedBatchRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *NodeWriteTaggedBatchRawResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *NodeWriteTaggedBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
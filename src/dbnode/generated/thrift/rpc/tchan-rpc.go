@@ -64,9 +64,9 @@ type TChanNode interface {
 	SetWriteNewSeriesLimitPerShardPerSecond(ctx thrift.Context, req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (*NodeWriteNewSeriesLimitPerShardPerSecondResult_, error)
 	Truncate(ctx thrift.Context, req *TruncateRequest) (*TruncateResult_, error)
 	Write(ctx thrift.Context, req *WriteRequest) error
-	WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) error
+	WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) (*WriteBatchRawResult_, error)
 	WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error
-	WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) error
+	WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) (*WriteBatchRawResult_, error)
 }
 
 // Implementation of a client and service handler.
@@ -803,7 +803,7 @@ func (c *tchanNodeClient) Write(ctx thrift.Context, req *WriteRequest) error {
 	return err
 }
 
-func (c *tchanNodeClient) WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) error {
+func (c *tchanNodeClient) WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) (*WriteBatchRawResult_, error) {
 	var resp NodeWriteBatchRawResult
 	args := NodeWriteBatchRawArgs{
 		Req: req,
@@ -818,7 +818,7 @@ func (c *tchanNodeClient) WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRe
 		}
 	}
 
-	return err
+	return resp.GetSuccess(), err
 }
 
 func (c *tchanNodeClient) WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error {
@@ -839,7 +839,7 @@ func (c *tchanNodeClient) WriteTagged(ctx thrift.Context, req *WriteTaggedReques
 	return err
 }
 
-func (c *tchanNodeClient) WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) error {
+func (c *tchanNodeClient) WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) (*WriteBatchRawResult_, error) {
 	var resp NodeWriteTaggedBatchRawResult
 	args := NodeWriteTaggedBatchRawArgs{
 		Req: req,
@@ -854,7 +854,7 @@ func (c *tchanNodeClient) WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTagg
 		}
 	}
 
-	return err
+	return resp.GetSuccess(), err
 }
 
 type tchanNodeServer struct {
@@ -1490,7 +1490,7 @@ func (s *tchanNodeServer) handleWriteBatchRaw(ctx thrift.Context, protocol athri
 		return false, nil, err
 	}
 
-	err :=
+	r, err :=
 		s.handler.WriteBatchRaw(ctx, req.Req)
 
 	if err != nil {
@@ -1504,6 +1504,7 @@ func (s *tchanNodeServer) handleWriteBatchRaw(ctx thrift.Context, protocol athri
 			return false, nil, err
 		}
 	} else {
+		res.Success = r
 	}
 
 	return err == nil, &res, nil
@@ -1544,7 +1545,7 @@ func (s *tchanNodeServer) handleWriteTaggedBatchRaw(ctx thrift.Context, protocol
 		return false, nil, err
 	}
 
-	err :=
+	r, err :=
 		s.handler.WriteTaggedBatchRaw(ctx, req.Req)
 
 	if err != nil {
@@ -1558,6 +1559,7 @@ func (s *tchanNodeServer) handleWriteTaggedBatchRaw(ctx thrift.Context, protocol
 			return false, nil, err
 		}
 	} else {
+		res.Success = r
 	}
 
 	return err == nil, &res, nil
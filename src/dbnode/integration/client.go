@@ -75,7 +75,8 @@ func tchannelClientWriteBatch(client rpc.TChanNode, timeout time.Duration, names
 		NameSpace: namespace.Bytes(),
 		Elements:  elems,
 	}
-	return client.WriteBatchRaw(ctx, batchReq)
+	_, err := client.WriteBatchRaw(ctx, batchReq)
+	return err
 }
 
 // tchannelClientFetch fulfills a fetch request using a tchannel client.
@@ -294,8 +294,9 @@ func startAndWriteTagged(
 	defer ctx.BlockingClose()
 	for _, n := range nodes {
 		require.NoError(t, n.startServer())
-		require.NoError(t, n.db.WriteTagged(ctx, testNamespaces[0], ident.StringID("quorumTest"),
+		_, err := n.db.WriteTagged(ctx, testNamespaces[0], ident.StringID("quorumTest"),
 			ident.NewTagsIterator(ident.NewTags(ident.StringTag("foo", "bar"), ident.StringTag("boo", "baz"))),
-			n.getNowFn(), 42, xtime.Second, nil))
+			n.getNowFn(), 42, xtime.Second, nil)
+		require.NoError(t, err)
 	}
 }
@@ -112,7 +112,8 @@ func testMixedModeReadWrite(t *testing.T, snapshotEnabled bool) {
 	for _, dp := range datapoints {
 		ts := dp.time
 		setup.setNowFn(ts)
-		require.NoError(t, db.Write(ctx, nsID, dp.series, ts, dp.value, xtime.Second, nil))
+		_, err = db.Write(ctx, nsID, dp.series, ts, dp.value, xtime.Second, nil)
+		require.NoError(t, err)
 	}
 	log.Infof("wrote datapoints")
 
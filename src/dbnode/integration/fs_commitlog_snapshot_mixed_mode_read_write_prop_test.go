@@ -187,7 +187,7 @@ func TestFsCommitLogMixedModeReadWriteProp(t *testing.T) {
 
 						setup.setNowFn(ts)
 
-						err := setup.db.Write(ctx, nsID, dp.series, ts, dp.value, xtime.Second, nil)
+						_, err := setup.db.Write(ctx, nsID, dp.series, ts, dp.value, xtime.Second, nil)
 						if err != nil {
 							return false, err
 						}
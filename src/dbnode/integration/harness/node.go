@@ -0,0 +1,340 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/cluster"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3cluster/shard"
+)
+
+const (
+	waitUntilSleep = 10 * time.Millisecond
+)
+
+var (
+	errNodeAlreadyStarted = errors.New("node already started")
+	errNodeNotStarted     = errors.New("node not started")
+	errNodeClosed         = errors.New("node closed")
+	errNodeStartTimedOut  = errors.New("node took too long to start")
+)
+
+type node struct {
+	sync.Mutex
+
+	opts             Options
+	storageOpts      storage.Options
+	topoInit         topology.Initializer
+	adminClient      client.AdminClient
+	tchannelNodeAddr string
+	filePathPrefix   string
+	removeOnClose    bool
+
+	nowLock sync.RWMutex
+	now     time.Time
+
+	db       cluster.Database
+	closeFn  func()
+	started  bool
+	isClosed bool
+}
+
+// NewNode creates a new in-process node, the node is not started until
+// Start is called.
+func NewNode(opts Options) (Node, error) {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := &node{opts: opts}
+
+	filePathPrefix := opts.FilePathPrefix()
+	if filePathPrefix == "" {
+		dir, err := ioutil.TempDir("", "m3dbnode-harness")
+		if err != nil {
+			return nil, err
+		}
+		filePathPrefix = dir
+		n.removeOnClose = true
+	}
+	n.filePathPrefix = filePathPrefix
+
+	tchannelNodeAddr := opts.TChannelNodeAddr()
+	if tchannelNodeAddr == "" {
+		addr, err := freeLocalAddr()
+		if err != nil {
+			return nil, err
+		}
+		tchannelNodeAddr = addr
+	}
+	n.tchannelNodeAddr = tchannelNodeAddr
+
+	topoInit := opts.TopologyInitializer()
+	if topoInit == nil {
+		var err error
+		topoInit, err = newSingleNodeTopologyInitializer(opts.HostID(),
+			tchannelNodeAddr, opts.NumShards())
+		if err != nil {
+			return nil, err
+		}
+	}
+	n.topoInit = topoInit
+
+	n.now = opts.StartTime()
+	if n.now.IsZero() {
+		n.now = time.Now().Truncate(minBlockSize(opts.Namespaces()))
+	}
+
+	storageOpts := opts.StorageOptions()
+	storageOpts = storageOpts.
+		SetNamespaceInitializer(namespace.NewStaticInitializer(opts.Namespaces())).
+		SetClockOptions(storageOpts.ClockOptions().SetNowFn(n.Now))
+
+	fsOpts := storageOpts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(filePathPrefix)
+	storageOpts = storageOpts.SetCommitLogOptions(
+		storageOpts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	if err != nil {
+		return nil, err
+	}
+	storageOpts = storageOpts.SetPersistManager(pm)
+
+	clientOpts, ok := opts.ClientOptions().
+		SetTopologyInitializer(topoInit).(client.AdminOptions)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast to admin options")
+	}
+	clientOpts = clientOpts.SetClockOptions(
+		clientOpts.ClockOptions().SetNowFn(n.Now)).(client.AdminOptions)
+	adminClient, err := client.NewAdminClient(clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	n.adminClient = adminClient
+
+	// Allow the storage layer to repair using the local node's client.
+	n.storageOpts = storageOpts.SetRepairOptions(
+		storageOpts.RepairOptions().SetAdminClient(adminClient))
+
+	return n, nil
+}
+
+func (n *node) Start() error {
+	n.Lock()
+	defer n.Unlock()
+	if n.isClosed {
+		return errNodeClosed
+	}
+	if n.started {
+		return errNodeAlreadyStarted
+	}
+
+	db, err := cluster.NewDatabase(n.opts.HostID(), n.topoInit, n.storageOpts)
+	if err != nil {
+		return err
+	}
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("could not open database: %v", err)
+	}
+
+	ttopts := tchannelthrift.NewOptions()
+	contextPool := n.storageOpts.ContextPool()
+	nodeClose, err := ttnode.NewServer(db, n.tchannelNodeAddr, contextPool,
+		nil, ttopts).ListenAndServe()
+	if err != nil {
+		db.Terminate()
+		return fmt.Errorf("could not open tchannelthrift interface %s: %v",
+			n.tchannelNodeAddr, err)
+	}
+
+	bootstrapErrCh := make(chan error, 1)
+	go func() {
+		bootstrapErrCh <- db.Bootstrap()
+	}()
+
+	deadline := time.Now().Add(n.opts.ServerStateChangeTimeout())
+	for !db.IsBootstrapped() {
+		select {
+		case err := <-bootstrapErrCh:
+			if err != nil {
+				nodeClose()
+				db.Terminate()
+				return fmt.Errorf("bootstrapping database encountered error: %v", err)
+			}
+		default:
+		}
+		if time.Now().After(deadline) {
+			nodeClose()
+			db.Terminate()
+			return errNodeStartTimedOut
+		}
+		time.Sleep(waitUntilSleep)
+	}
+
+	n.db = db
+	n.closeFn = nodeClose
+	n.started = true
+	return nil
+}
+
+func (n *node) Stop() error {
+	n.Lock()
+	defer n.Unlock()
+	return n.stopWithLock()
+}
+
+func (n *node) stopWithLock() error {
+	if !n.started {
+		return errNodeNotStarted
+	}
+	n.started = false
+	n.closeFn()
+	n.closeFn = nil
+	err := n.db.Terminate()
+	n.db = nil
+	return err
+}
+
+func (n *node) Close() error {
+	n.Lock()
+	defer n.Unlock()
+	if n.isClosed {
+		return errNodeClosed
+	}
+	n.isClosed = true
+
+	var err error
+	if n.started {
+		err = n.stopWithLock()
+	}
+	if n.removeOnClose {
+		if rmErr := os.RemoveAll(n.filePathPrefix); err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
+
+func (n *node) Client() client.Client {
+	return n.adminClient
+}
+
+func (n *node) AdminClient() client.AdminClient {
+	return n.adminClient
+}
+
+func (n *node) DB() storage.Database {
+	n.Lock()
+	defer n.Unlock()
+	return n.db
+}
+
+func (n *node) Now() time.Time {
+	n.nowLock.RLock()
+	now := n.now
+	n.nowLock.RUnlock()
+	return now
+}
+
+func (n *node) SetNow(t time.Time) {
+	n.nowLock.Lock()
+	n.now = t
+	n.nowLock.Unlock()
+}
+
+func (n *node) FilePathPrefix() string {
+	return n.filePathPrefix
+}
+
+func newSingleNodeTopologyInitializer(
+	hostID string,
+	tchannelNodeAddr string,
+	numShards int,
+) (topology.Initializer, error) {
+	ids := make([]uint32, 0, numShards)
+	for i := uint32(0); i < uint32(numShards); i++ {
+		ids = append(ids, i)
+	}
+
+	shards := sharding.NewShards(ids, shard.Available)
+	shardSet, err := sharding.NewShardSet(shards, sharding.DefaultHashFn(numShards))
+	if err != nil {
+		return nil, err
+	}
+
+	hostShardSet := topology.NewHostShardSet(
+		topology.NewHost(hostID, tchannelNodeAddr), shardSet)
+	staticOptions := topology.NewStaticOptions().
+		SetShardSet(shardSet).
+		SetReplicas(1).
+		SetHostShardSets([]topology.HostShardSet{hostShardSet})
+
+	return topology.NewStaticInitializer(staticOptions), nil
+}
+
+// freeLocalAddr returns a local address with a port that is free at the
+// time of the call.
+func freeLocalAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := l.Addr().String()
+	if err := l.Close(); err != nil {
+		return "", err
+	}
+	return addr, nil
+}
+
+// minBlockSize returns the smallest block size across the namespaces.
+func minBlockSize(mds []namespace.Metadata) time.Duration {
+	var min time.Duration
+	for _, md := range mds {
+		blockSize := md.Options().RetentionOptions().BlockSize()
+		if min == 0 || blockSize < min {
+			min = blockSize
+		}
+	}
+	if min == 0 {
+		return time.Hour
+	}
+	return min
+}
@@ -0,0 +1,66 @@
+// +build integration
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package harness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeWriteRead(t *testing.T) {
+	n, err := NewNode(NewOptions().SetNumShards(4))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, n.Close())
+	}()
+
+	require.NoError(t, n.Start())
+
+	session, err := n.Client().DefaultSession()
+	require.NoError(t, err)
+
+	var (
+		now = n.Now()
+		id  = ident.StringID("foo")
+	)
+	require.NoError(t, session.Write(DefaultNamespaceID, id, now, 42.0, xtime.Second, nil))
+
+	iter, err := session.Fetch(DefaultNamespaceID, id, now.Add(-time.Minute), now.Add(time.Minute))
+	require.NoError(t, err)
+	defer iter.Close()
+
+	require.True(t, iter.Next())
+	dp, _, _ := iter.Current()
+	require.True(t, now.Equal(dp.Timestamp))
+	require.Equal(t, 42.0, dp.Value)
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+
+	require.NoError(t, n.Stop())
+	require.Error(t, n.Stop())
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package harness
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
+)
+
+const (
+	// defaultHostID is the default host ID of the node.
+	defaultHostID = "harness"
+
+	// defaultNumShards is the default number of shards.
+	defaultNumShards = 12
+
+	// defaultServerStateChangeTimeout is the default time to wait for the
+	// node to start or stop.
+	defaultServerStateChangeTimeout = 5 * time.Minute
+)
+
+var (
+	// DefaultNamespaceID is the ID of the namespace created when no
+	// namespaces are specified.
+	DefaultNamespaceID = ident.StringID("default")
+
+	errNoNamespaces         = errors.New("no namespaces specified")
+	errInvalidNumShards     = errors.New("number of shards must be positive")
+	errInvalidChangeTimeout = errors.New("server state change timeout must be positive")
+)
+
+type options struct {
+	hostID                   string
+	namespaces               []namespace.Metadata
+	numShards                int
+	topoInit                 topology.Initializer
+	tchannelNodeAddr         string
+	filePathPrefix           string
+	startTime                time.Time
+	serverStateChangeTimeout time.Duration
+	storageOpts              storage.Options
+	clientOpts               client.AdminOptions
+}
+
+// NewOptions creates a new set of harness options with a single default
+// namespace.
+func NewOptions() Options {
+	md, err := namespace.NewMetadata(DefaultNamespaceID, namespace.NewOptions())
+	if err != nil { // should never happen
+		panic(err)
+	}
+	return &options{
+		hostID:                   defaultHostID,
+		namespaces:               []namespace.Metadata{md},
+		numShards:                defaultNumShards,
+		serverStateChangeTimeout: defaultServerStateChangeTimeout,
+		storageOpts:              storage.NewOptions(),
+		clientOpts:               client.NewAdminOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if len(o.namespaces) == 0 {
+		return errNoNamespaces
+	}
+	if o.topoInit == nil && o.numShards <= 0 {
+		return errInvalidNumShards
+	}
+	if o.serverStateChangeTimeout <= 0 {
+		return errInvalidChangeTimeout
+	}
+	return nil
+}
+
+func (o *options) SetHostID(value string) Options {
+	opts := *o
+	opts.hostID = value
+	return &opts
+}
+
+func (o *options) HostID() string {
+	return o.hostID
+}
+
+func (o *options) SetNamespaces(value []namespace.Metadata) Options {
+	opts := *o
+	opts.namespaces = value
+	return &opts
+}
+
+func (o *options) Namespaces() []namespace.Metadata {
+	return o.namespaces
+}
+
+func (o *options) SetNumShards(value int) Options {
+	opts := *o
+	opts.numShards = value
+	return &opts
+}
+
+func (o *options) NumShards() int {
+	return o.numShards
+}
+
+func (o *options) SetTopologyInitializer(value topology.Initializer) Options {
+	opts := *o
+	opts.topoInit = value
+	return &opts
+}
+
+func (o *options) TopologyInitializer() topology.Initializer {
+	return o.topoInit
+}
+
+func (o *options) SetTChannelNodeAddr(value string) Options {
+	opts := *o
+	opts.tchannelNodeAddr = value
+	return &opts
+}
+
+func (o *options) TChannelNodeAddr() string {
+	return o.tchannelNodeAddr
+}
+
+func (o *options) SetFilePathPrefix(value string) Options {
+	opts := *o
+	opts.filePathPrefix = value
+	return &opts
+}
+
+func (o *options) FilePathPrefix() string {
+	return o.filePathPrefix
+}
+
+func (o *options) SetStartTime(value time.Time) Options {
+	opts := *o
+	opts.startTime = value
+	return &opts
+}
+
+func (o *options) StartTime() time.Time {
+	return o.startTime
+}
+
+func (o *options) SetServerStateChangeTimeout(value time.Duration) Options {
+	opts := *o
+	opts.serverStateChangeTimeout = value
+	return &opts
+}
+
+func (o *options) ServerStateChangeTimeout() time.Duration {
+	return o.serverStateChangeTimeout
+}
+
+func (o *options) SetStorageOptions(value storage.Options) Options {
+	opts := *o
+	opts.storageOpts = value
+	return &opts
+}
+
+func (o *options) StorageOptions() storage.Options {
+	return o.storageOpts
+}
+
+func (o *options) SetClientOptions(value client.AdminOptions) Options {
+	opts := *o
+	opts.clientOpts = value
+	return &opts
+}
+
+func (o *options) ClientOptions() client.AdminOptions {
+	return o.clientOpts
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package harness provides an in-process dbnode for use in tests of
+// applications that embed the client, without requiring docker or any
+// external processes.
+package harness
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/topology"
+)
+
+// Node is an in-process dbnode backed by temporary directories and a fake
+// clock that is controlled by the test.
+type Node interface {
+	// Start opens and bootstraps the node, returning once it is serving.
+	Start() error
+
+	// Stop stops the node, the data directories are retained so that the
+	// node may be started again.
+	Stop() error
+
+	// Close stops the node if required and removes any temporary directories.
+	Close() error
+
+	// Client returns a client connected to the node.
+	Client() client.Client
+
+	// AdminClient returns an admin client connected to the node.
+	AdminClient() client.AdminClient
+
+	// DB returns the database backing the node, only valid once started.
+	DB() storage.Database
+
+	// Now returns the current time of the node's fake clock.
+	Now() time.Time
+
+	// SetNow sets the current time of the node's fake clock.
+	SetNow(t time.Time)
+
+	// FilePathPrefix returns the directory the node persists data to.
+	FilePathPrefix() string
+}
+
+// Options are the options for an in-process node.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetHostID sets the host ID of the node.
+	SetHostID(value string) Options
+
+	// HostID returns the host ID of the node.
+	HostID() string
+
+	// SetNamespaces sets the namespaces of the node.
+	SetNamespaces(value []namespace.Metadata) Options
+
+	// Namespaces returns the namespaces of the node.
+	Namespaces() []namespace.Metadata
+
+	// SetNumShards sets the number of shards used when no topology
+	// initializer is set.
+	SetNumShards(value int) Options
+
+	// NumShards returns the number of shards used when no topology
+	// initializer is set.
+	NumShards() int
+
+	// SetTopologyInitializer sets a programmable topology initializer, if
+	// not set a single node topology owning all shards is used.
+	SetTopologyInitializer(value topology.Initializer) Options
+
+	// TopologyInitializer returns the topology initializer.
+	TopologyInitializer() topology.Initializer
+
+	// SetTChannelNodeAddr sets the tchannel node address, if not set a
+	// free local port is used.
+	SetTChannelNodeAddr(value string) Options
+
+	// TChannelNodeAddr returns the tchannel node address.
+	TChannelNodeAddr() string
+
+	// SetFilePathPrefix sets the file path prefix, if not set a temporary
+	// directory is created and removed when the node is closed.
+	SetFilePathPrefix(value string) Options
+
+	// FilePathPrefix returns the file path prefix.
+	FilePathPrefix() string
+
+	// SetStartTime sets the initial time of the node's fake clock, if
+	// not set the current time truncated to the namespace block size is used.
+	SetStartTime(value time.Time) Options
+
+	// StartTime returns the initial time of the node's fake clock.
+	StartTime() time.Time
+
+	// SetServerStateChangeTimeout sets the time to wait for the node to
+	// start or stop.
+	SetServerStateChangeTimeout(value time.Duration) Options
+
+	// ServerStateChangeTimeout returns the time to wait for the node to
+	// start or stop.
+	ServerStateChangeTimeout() time.Duration
+
+	// SetStorageOptions sets the base storage options for the node.
+	SetStorageOptions(value storage.Options) Options
+
+	// StorageOptions returns the base storage options for the node.
+	StorageOptions() storage.Options
+
+	// SetClientOptions sets the base client options for the node's clients.
+	SetClientOptions(value client.AdminOptions) Options
+
+	// ClientOptions returns the base client options for the node's clients.
+	ClientOptions() client.AdminOptions
+}
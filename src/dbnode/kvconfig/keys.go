@@ -44,4 +44,14 @@ const (
 	// ClientWriteConsistencyLevel is the KV config key for the runtime
 	// configuration specifying the client write consistency level
 	ClientWriteConsistencyLevel = "m3db.client.write-consistency-level"
+
+	// PeerStreamingThroughputLimitMbpsKey is the KV config key for the runtime
+	// configuration specifying the peer streaming throughput limit in Mb/s,
+	// a value of zero disables the limit
+	PeerStreamingThroughputLimitMbpsKey = "m3db.node.peer-streaming-throughput-limit-mbps"
+
+	// PeerStreamingWindowsKey is the KV config key for the runtime
+	// configuration specifying the comma separated daily windows of time
+	// peer streaming is allowed in, e.g. "22:00-06:00"
+	PeerStreamingWindowsKey = "m3db.node.peer-streaming-windows"
 )
@@ -0,0 +1,83 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tchannelthrift
+
+// APIVersion is the version of the node RPC API served by this build, it is
+// incremented when fields or endpoints that clients may depend on are added.
+const APIVersion int32 = 1
+
+// Capabilities is a set of optional node RPC API features, it is exchanged
+// in health check responses so that clients only use features a node
+// supports during rolling upgrades.
+type Capabilities int64
+
+const (
+	// CapabilityWriteTaggedBatchRaw is support for the writeTaggedBatchRaw
+	// endpoint.
+	CapabilityWriteTaggedBatchRaw Capabilities = 1 << iota
+	// CapabilityFetchBlocksMetadataRawV2 is support for the
+	// fetchBlocksMetadataRawV2 endpoint.
+	CapabilityFetchBlocksMetadataRawV2
+	// CapabilityFetchTaggedTags is support for returning encoded tags in
+	// fetchTagged responses.
+	CapabilityFetchTaggedTags
+)
+
+const (
+	// ServerCapabilities are the capabilities served and understood by this
+	// build.
+	ServerCapabilities = CapabilityWriteTaggedBatchRaw |
+		CapabilityFetchBlocksMetadataRawV2 |
+		CapabilityFetchTaggedTags
+
+	// LegacyCapabilities are the capabilities assumed for nodes that do not
+	// report an API version, i.e. nodes that predate capability negotiation.
+	// Only the metadata endpoint required by peer bootstrapping is assumed,
+	// tagged writes are sent unbatched and fetchTagged responses are not
+	// relied upon for tags until the node reports otherwise.
+	LegacyCapabilities = CapabilityFetchBlocksMetadataRawV2
+)
+
+// Has returns whether all of the given capabilities are in the set.
+func (c Capabilities) Has(capabilities Capabilities) bool {
+	return c&capabilities == capabilities
+}
+
+// NegotiatedCapabilities returns the API version and capabilities to use
+// with a node given its reported API version and capabilities, unknown
+// capabilities reported by newer nodes are ignored.
+func NegotiatedCapabilities(
+	apiVersion *int32,
+	capabilities *int64,
+) (int32, Capabilities) {
+	if apiVersion == nil {
+		return 0, LegacyCapabilities
+	}
+	version := *apiVersion
+	if version > APIVersion {
+		version = APIVersion
+	}
+	var result Capabilities
+	if capabilities != nil {
+		result = Capabilities(*capabilities)
+	}
+	return version, result & ServerCapabilities
+}
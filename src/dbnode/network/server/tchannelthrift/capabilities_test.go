@@ -0,0 +1,60 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tchannelthrift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiatedCapabilitiesLegacyNode(t *testing.T) {
+	version, capabilities := NegotiatedCapabilities(nil, nil)
+	assert.Equal(t, int32(0), version)
+	assert.Equal(t, LegacyCapabilities, capabilities)
+	assert.NotEqual(t, ServerCapabilities, capabilities)
+	assert.True(t, capabilities.Has(CapabilityFetchBlocksMetadataRawV2))
+	assert.False(t, capabilities.Has(CapabilityWriteTaggedBatchRaw))
+	assert.False(t, capabilities.Has(CapabilityFetchTaggedTags))
+}
+
+func TestNegotiatedCapabilities(t *testing.T) {
+	var (
+		apiVersion   = int32(1)
+		capabilities = int64(CapabilityFetchBlocksMetadataRawV2)
+	)
+	version, result := NegotiatedCapabilities(&apiVersion, &capabilities)
+	assert.Equal(t, int32(1), version)
+	assert.True(t, result.Has(CapabilityFetchBlocksMetadataRawV2))
+	assert.False(t, result.Has(CapabilityWriteTaggedBatchRaw))
+	assert.False(t, result.Has(CapabilityWriteTaggedBatchRaw|
+		CapabilityFetchBlocksMetadataRawV2))
+}
+
+func TestNegotiatedCapabilitiesNewerNode(t *testing.T) {
+	var (
+		apiVersion   = APIVersion + 1
+		capabilities = int64(ServerCapabilities) | 1<<62
+	)
+	version, result := NegotiatedCapabilities(&apiVersion, &capabilities)
+	assert.Equal(t, APIVersion, version)
+	assert.Equal(t, ServerCapabilities, result)
+}
@@ -223,22 +223,36 @@ func FromRPCFetchTaggedRequest(
 	if l := req.Limit; l != nil {
 		opts.Limit = int(*l)
 	}
+	if c := req.CountOnly; c != nil {
+		opts.CountOnly = *c
+	}
 
 	q, err := idx.Unmarshal(req.Query)
 	if err != nil {
 		return nil, index.Query{}, index.QueryOptions{}, false, err
 	}
 
-	var ns ident.ID
-	if pools != nil {
-		nsBytes := pools.CheckedBytesWrapper().Get(req.NameSpace)
-		ns = pools.ID().BinaryID(nsBytes)
-	} else {
-		ns = ident.StringID(string(req.NameSpace))
+	ns := fetchTaggedNamespaceID(req.NameSpace, pools)
+	if len(req.AdditionalNameSpaces) > 0 {
+		opts.AdditionalNamespaces = make([]ident.ID, 0, len(req.AdditionalNameSpaces))
+		for _, nsBytes := range req.AdditionalNameSpaces {
+			opts.AdditionalNamespaces = append(opts.AdditionalNamespaces,
+				fetchTaggedNamespaceID(nsBytes, pools))
+		}
 	}
 	return ns, index.Query{Query: q}, opts, req.FetchData, nil
 }
 
+func fetchTaggedNamespaceID(
+	nsBytes []byte,
+	pools FetchTaggedConversionPools,
+) ident.ID {
+	if pools == nil {
+		return ident.StringID(string(nsBytes))
+	}
+	return pools.ID().BinaryID(pools.CheckedBytesWrapper().Get(nsBytes))
+}
+
 // ToRPCFetchTaggedRequest converts the Go `client/` types into rpc request type for FetchTaggedRequest.
 func ToRPCFetchTaggedRequest(
 	ns ident.ID,
@@ -273,6 +287,14 @@ func ToRPCFetchTaggedRequest(
 		l := int64(opts.Limit)
 		request.Limit = &l
 	}
+	if opts.CountOnly {
+		countOnly := true
+		request.CountOnly = &countOnly
+	}
+	for _, additionalNs := range opts.AdditionalNamespaces {
+		request.AdditionalNameSpaces = append(request.AdditionalNameSpaces,
+			additionalNs.Bytes())
+	}
 
 	return request, nil
 }
@@ -150,6 +150,26 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertFetchTaggedRequestAdditionalNamespaces(t *testing.T) {
+	q, _ := termQueryTestCase(t)
+	opts := index.QueryOptions{
+		StartInclusive:       time.Now().Add(-time.Hour),
+		EndExclusive:         time.Now(),
+		AdditionalNamespaces: []ident.ID{ident.StringID("def")},
+	}
+	req, err := convert.ToRPCFetchTaggedRequest(ident.StringID("abc"),
+		index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("def")}, req.AdditionalNameSpaces)
+
+	for _, pools := range []convert.FetchTaggedConversionPools{nil, newTestPools()} {
+		_, _, observedOpts, _, err := convert.FromRPCFetchTaggedRequest(&req, pools)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(observedOpts.AdditionalNamespaces))
+		require.Equal(t, "def", observedOpts.AdditionalNamespaces[0].String())
+	}
+}
+
 type testPools struct {
 	id      ident.Pool
 	wrapper xpool.CheckedBytesWrapperPool
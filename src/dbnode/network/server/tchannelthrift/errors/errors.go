@@ -43,6 +43,12 @@ func IsBadRequestError(err *rpc.Error) bool {
 	return err != nil && err.Type == rpc.ErrorType_BAD_REQUEST
 }
 
+// IsResourceExhaustedError returns whether the error is a resource exhausted
+// error
+func IsResourceExhaustedError(err *rpc.Error) bool {
+	return err != nil && err.Type == rpc.ErrorType_RESOURCE_EXHAUSTED
+}
+
 // NewInternalError creates a new internal error
 func NewInternalError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_INTERNAL_ERROR, err)
@@ -53,6 +59,12 @@ func NewBadRequestError(err error) *rpc.Error {
 	return newError(rpc.ErrorType_BAD_REQUEST, err)
 }
 
+// NewResourceExhaustedError creates a new resource exhausted error, the
+// request was rejected due to load and may be retried
+func NewResourceExhaustedError(err error) *rpc.Error {
+	return newError(rpc.ErrorType_RESOURCE_EXHAUSTED, err)
+}
+
 // NewWriteBatchRawError creates a new write batch error
 func NewWriteBatchRawError(index int, err error) *rpc.WriteBatchRawError {
 	batchErr := rpc.NewWriteBatchRawError()
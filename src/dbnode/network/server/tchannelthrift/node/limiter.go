@@ -0,0 +1,237 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+var (
+	errRequestQueueFull    = errors.New("too many requests queued for request class")
+	errRequestQueueTimeout = errors.New("request cancelled waiting to be served")
+)
+
+// requestClass is the class of a request, each class has its own limits and
+// classes are served in priority order, lowest first.
+type requestClass int
+
+const (
+	writeRequestClass requestClass = iota
+	readRequestClass
+	streamRequestClass
+
+	numRequestClasses = iota
+)
+
+func (c requestClass) String() string {
+	switch c {
+	case writeRequestClass:
+		return "write"
+	case readRequestClass:
+		return "read"
+	case streamRequestClass:
+		return "stream"
+	}
+	return "unknown"
+}
+
+// requestLimiter limits the concurrency of each class of request and the
+// total concurrency of all requests, queued requests are served in priority
+// order of their class so that peer bootstrap streams cannot starve writes.
+type requestLimiter struct {
+	sync.Mutex
+
+	// maxRunning is the max number of requests of all classes running,
+	// unlimited if zero.
+	maxRunning int
+	running    int
+	classes    [numRequestClasses]*classLimiter
+}
+
+func newRequestLimiter(
+	limits tchannelthrift.ConcurrencyLimits,
+	scope tally.Scope,
+) *requestLimiter {
+	l := &requestLimiter{maxRunning: limits.MaxConcurrent}
+	l.classes[writeRequestClass] = newClassLimiter(limits.Write, scope, writeRequestClass)
+	l.classes[readRequestClass] = newClassLimiter(limits.Read, scope, readRequestClass)
+	l.classes[streamRequestClass] = newClassLimiter(limits.Stream, scope, streamRequestClass)
+	return l
+}
+
+// acquire waits for a slot to serve a request of the class, release must be
+// called once the request is served if no error is returned.
+func (l *requestLimiter) acquire(tctx thrift.Context, class requestClass) error {
+	c := l.classes[class]
+	if l.unlimited(c) {
+		return nil
+	}
+
+	l.Lock()
+	if l.canRunWithLock(class) {
+		l.runWithLock(c)
+		l.Unlock()
+		return nil
+	}
+	if c.maxRunning > 0 && len(c.queue) >= c.maxQueued {
+		l.Unlock()
+		c.metrics.rejected.Inc(1)
+		return tterrors.NewResourceExhaustedError(errRequestQueueFull)
+	}
+	w := &limiterWaiter{ready: make(chan struct{})}
+	c.queue = append(c.queue, w)
+	l.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-tctx.Done():
+	}
+
+	l.Lock()
+	if w.admitted {
+		// NB: admitted concurrently with the cancellation, give up the slot.
+		l.releaseWithLock(c)
+	} else {
+		c.remove(w)
+	}
+	l.Unlock()
+	c.metrics.queueTimeouts.Inc(1)
+	return tterrors.NewResourceExhaustedError(errRequestQueueTimeout)
+}
+
+func (l *requestLimiter) release(class requestClass) {
+	c := l.classes[class]
+	if l.unlimited(c) {
+		return
+	}
+
+	l.Lock()
+	l.releaseWithLock(c)
+	l.Unlock()
+}
+
+func (l *requestLimiter) unlimited(c *classLimiter) bool {
+	return l.maxRunning <= 0 && c.maxRunning <= 0
+}
+
+// canRunWithLock returns whether a request of the class can run without
+// waiting, requests queued of the class or of a higher priority class that
+// is only waiting on the total limit run first.
+func (l *requestLimiter) canRunWithLock(class requestClass) bool {
+	if l.maxRunning > 0 && l.running >= l.maxRunning {
+		return false
+	}
+	c := l.classes[class]
+	if !c.hasCapacity() || len(c.queue) > 0 {
+		return false
+	}
+	for _, higher := range l.classes[:class] {
+		if len(higher.queue) > 0 && higher.hasCapacity() {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *requestLimiter) runWithLock(c *classLimiter) {
+	c.running++
+	l.running++
+}
+
+func (l *requestLimiter) releaseWithLock(c *classLimiter) {
+	c.running--
+	l.running--
+
+	// Admit queued requests in priority order of their class while there
+	// are slots free.
+	for _, queued := range l.classes {
+		for len(queued.queue) > 0 && queued.hasCapacity() {
+			if l.maxRunning > 0 && l.running >= l.maxRunning {
+				return
+			}
+			w := queued.queue[0]
+			queued.queue[0] = nil
+			queued.queue = queued.queue[1:]
+			w.admitted = true
+			l.runWithLock(queued)
+			close(w.ready)
+		}
+	}
+}
+
+type limiterWaiter struct {
+	ready    chan struct{}
+	admitted bool
+}
+
+type classLimiterMetrics struct {
+	rejected      tally.Counter
+	queueTimeouts tally.Counter
+}
+
+type classLimiter struct {
+	// maxRunning bounds the requests of the class running and maxQueued the
+	// requests waiting, both are unlimited if maxRunning is zero.
+	maxRunning int
+	maxQueued  int
+	running    int
+	queue      []*limiterWaiter
+	metrics    classLimiterMetrics
+}
+
+func newClassLimiter(
+	limit tchannelthrift.ConcurrencyLimit,
+	scope tally.Scope,
+	class requestClass,
+) *classLimiter {
+	scope = scope.Tagged(map[string]string{"request-class": class.String()})
+	return &classLimiter{
+		maxRunning: limit.MaxConcurrent,
+		maxQueued:  limit.MaxQueued,
+		metrics: classLimiterMetrics{
+			rejected:      scope.Counter("concurrency-limit.rejected"),
+			queueTimeouts: scope.Counter("concurrency-limit.queue-timeouts"),
+		},
+	}
+}
+
+func (c *classLimiter) hasCapacity() bool {
+	return c.maxRunning <= 0 || c.running < c.maxRunning
+}
+
+func (c *classLimiter) remove(w *limiterWaiter) {
+	for i, queued := range c.queue {
+		if queued == w {
+			copy(c.queue[i:], c.queue[i+1:])
+			c.queue[len(c.queue)-1] = nil
+			c.queue = c.queue[:len(c.queue)-1]
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+func TestRequestLimiterClassesLimitedSeparately(t *testing.T) {
+	limiter := newRequestLimiter(tchannelthrift.ConcurrencyLimits{
+		Stream: tchannelthrift.ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 1},
+	}, tally.NoopScope)
+
+	tctx, cancel := thrift.NewContext(time.Minute)
+	defer cancel()
+
+	// Streams saturate their own limit.
+	require.NoError(t, limiter.acquire(tctx, streamRequestClass))
+
+	queueCtx, queueCancel := thrift.NewContext(time.Millisecond)
+	defer queueCancel()
+	require.Error(t, limiter.acquire(queueCtx, streamRequestClass))
+
+	// Writes are unlimited so are not starved by the streams.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.acquire(tctx, writeRequestClass))
+	}
+	for i := 0; i < 10; i++ {
+		limiter.release(writeRequestClass)
+	}
+
+	// Another stream is queued while one is running and further streams are
+	// rejected until the queue frees up.
+	queued := make(chan error)
+	go func() {
+		queued <- limiter.acquire(tctx, streamRequestClass)
+	}()
+	waitQueued(limiter, streamRequestClass, 1)
+	err := limiter.acquire(tctx, streamRequestClass)
+	require.Error(t, err)
+	require.True(t, tterrors.IsResourceExhaustedError(err.(*rpc.Error)))
+
+	limiter.release(streamRequestClass)
+	require.NoError(t, <-queued)
+	limiter.release(streamRequestClass)
+}
+
+func TestRequestLimiterServesQueuedInPriorityOrder(t *testing.T) {
+	limiter := newRequestLimiter(tchannelthrift.ConcurrencyLimits{
+		MaxConcurrent: 1,
+	}, tally.NoopScope)
+
+	tctx, cancel := thrift.NewContext(time.Minute)
+	defer cancel()
+
+	require.NoError(t, limiter.acquire(tctx, readRequestClass))
+
+	// A stream is queued before a write but the write is served first.
+	served := make(chan requestClass, 2)
+	for _, class := range []requestClass{streamRequestClass, writeRequestClass} {
+		class := class
+		go func() {
+			require.NoError(t, limiter.acquire(tctx, class))
+			served <- class
+		}()
+		waitQueued(limiter, class, 1)
+	}
+
+	limiter.release(readRequestClass)
+	require.Equal(t, writeRequestClass, <-served)
+	limiter.release(writeRequestClass)
+	require.Equal(t, streamRequestClass, <-served)
+	limiter.release(streamRequestClass)
+}
+
+func TestRequestLimiterQueueTimeout(t *testing.T) {
+	limiter := newRequestLimiter(tchannelthrift.ConcurrencyLimits{
+		MaxConcurrent: 1,
+	}, tally.NoopScope)
+
+	tctx, cancel := thrift.NewContext(time.Minute)
+	defer cancel()
+	require.NoError(t, limiter.acquire(tctx, writeRequestClass))
+
+	queueCtx, queueCancel := thrift.NewContext(time.Millisecond)
+	defer queueCancel()
+	err := limiter.acquire(queueCtx, readRequestClass)
+	require.Error(t, err)
+	require.True(t, tterrors.IsResourceExhaustedError(err.(*rpc.Error)))
+	waitQueued(limiter, readRequestClass, 0)
+
+	limiter.release(writeRequestClass)
+	require.NoError(t, limiter.acquire(tctx, readRequestClass))
+	limiter.release(readRequestClass)
+}
+
+func waitQueued(limiter *requestLimiter, class requestClass, n int) {
+	for {
+		limiter.Lock()
+		queued := len(limiter.classes[class].queue)
+		limiter.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -28,11 +28,13 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -74,37 +76,53 @@ var (
 
 	// errRequiresDatapoint raised when a datapoint is not provided
 	errRequiresDatapoint = fmt.Errorf("requires datapoint")
+
+	// errMultiNamespaceCountOnly raised when a count only query specifies
+	// additional namespaces, counts are only returned for a single namespace
+	errMultiNamespaceCountOnly = errors.New("count only queries must specify a single namespace")
+
+	// errFetchTaggedResponseFull raised by the stream function of a fetch
+	// tagged request once its response holds the max streamed bytes
+	errFetchTaggedResponseFull = errors.New("fetch tagged response is full")
 )
 
 type serviceMetrics struct {
-	fetch               instrument.MethodMetrics
-	fetchTagged         instrument.MethodMetrics
-	write               instrument.MethodMetrics
-	writeTagged         instrument.MethodMetrics
-	fetchBlocks         instrument.MethodMetrics
-	fetchBlocksMetadata instrument.MethodMetrics
-	repair              instrument.MethodMetrics
-	truncate            instrument.MethodMetrics
-	fetchBatchRaw       instrument.BatchMethodMetrics
-	writeBatchRaw       instrument.BatchMethodMetrics
-	writeTaggedBatchRaw instrument.BatchMethodMetrics
-	overloadRejected    tally.Counter
+	fetch                   instrument.MethodMetrics
+	fetchTagged             instrument.MethodMetrics
+	write                   instrument.MethodMetrics
+	writeTagged             instrument.MethodMetrics
+	fetchBlocks             instrument.MethodMetrics
+	fetchBlocksMetadata     instrument.MethodMetrics
+	repair                  instrument.MethodMetrics
+	truncate                instrument.MethodMetrics
+	fetchBatchRaw           instrument.BatchMethodMetrics
+	writeBatchRaw           instrument.BatchMethodMetrics
+	writeTaggedBatchRaw     instrument.BatchMethodMetrics
+	overloadRejected        tally.Counter
+	partialReads            tally.Counter
+	newSeriesWrites         tally.Counter
+	existingSeriesWrites    tally.Counter
+	fetchTaggedResponseFull tally.Counter
 }
 
 func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 	return serviceMetrics{
-		fetch:               instrument.NewMethodMetrics(scope, "fetch", samplingRate),
-		fetchTagged:         instrument.NewMethodMetrics(scope, "fetchTagged", samplingRate),
-		write:               instrument.NewMethodMetrics(scope, "write", samplingRate),
-		writeTagged:         instrument.NewMethodMetrics(scope, "writeTagged", samplingRate),
-		fetchBlocks:         instrument.NewMethodMetrics(scope, "fetchBlocks", samplingRate),
-		fetchBlocksMetadata: instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
-		repair:              instrument.NewMethodMetrics(scope, "repair", samplingRate),
-		truncate:            instrument.NewMethodMetrics(scope, "truncate", samplingRate),
-		fetchBatchRaw:       instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", samplingRate),
-		writeBatchRaw:       instrument.NewBatchMethodMetrics(scope, "writeBatchRaw", samplingRate),
-		writeTaggedBatchRaw: instrument.NewBatchMethodMetrics(scope, "writeTaggedBatchRaw", samplingRate),
-		overloadRejected:    scope.Counter("overload-rejected"),
+		fetch:                   instrument.NewMethodMetrics(scope, "fetch", samplingRate),
+		fetchTagged:             instrument.NewMethodMetrics(scope, "fetchTagged", samplingRate),
+		write:                   instrument.NewMethodMetrics(scope, "write", samplingRate),
+		writeTagged:             instrument.NewMethodMetrics(scope, "writeTagged", samplingRate),
+		fetchBlocks:             instrument.NewMethodMetrics(scope, "fetchBlocks", samplingRate),
+		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
+		repair:                  instrument.NewMethodMetrics(scope, "repair", samplingRate),
+		truncate:                instrument.NewMethodMetrics(scope, "truncate", samplingRate),
+		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", samplingRate),
+		writeBatchRaw:           instrument.NewBatchMethodMetrics(scope, "writeBatchRaw", samplingRate),
+		writeTaggedBatchRaw:     instrument.NewBatchMethodMetrics(scope, "writeTaggedBatchRaw", samplingRate),
+		overloadRejected:        scope.Counter("overload-rejected"),
+		partialReads:            scope.Counter("partial-reads"),
+		newSeriesWrites:         scope.Counter("new-series-writes"),
+		existingSeriesWrites:    scope.Counter("existing-series-writes"),
+		fetchTaggedResponseFull: scope.Counter("fetch-tagged-response-full"),
 	}
 }
 
@@ -119,6 +137,7 @@ type service struct {
 	pools   pools
 	metrics serviceMetrics
 	health  *rpc.NodeHealthResult_
+	limiter *requestLimiter
 }
 
 type pools struct {
@@ -134,6 +153,7 @@ type pools struct {
 	blockMetadataV2Slice    tchannelthrift.BlockMetadataV2SlicePool
 	blocksMetadata          tchannelthrift.BlocksMetadataPool
 	blocksMetadataSlice     tchannelthrift.BlocksMetadataSlicePool
+	multiReaderIterator     encoding.CodecMultiReaderIteratorPools
 }
 
 // ensure `pools` matches a required conversion interface
@@ -179,12 +199,28 @@ func NewService(db storage.Database, opts tchannelthrift.Options) rpc.TChanNode
 	writeBatchPooledReqPool := newWriteBatchPooledReqPool(iopts)
 	writeBatchPooledReqPool.Init(opts.TagDecoderPool())
 
+	dbOpts := db.Options()
+	multiReaderIteratorPools := encoding.NewCodecMultiReaderIteratorPools(
+		dbOpts.EncodingCodecRegistry(),
+		dbOpts.MultiReaderIteratorPool(),
+		pool.NewObjectPoolOptions().
+			SetInstrumentOptions(iopts.SetMetricsScope(
+				scope.SubScope("multi-reader-iterator-pool"))),
+		encoding.NewOptions().
+			SetBytesPool(dbOpts.BytesPool()).
+			SetSegmentReaderPool(dbOpts.SegmentReaderPool()))
+
+	var (
+		apiVersion   = tchannelthrift.APIVersion
+		capabilities = int64(tchannelthrift.ServerCapabilities)
+	)
 	s := &service{
 		db:      db,
 		logger:  iopts.Logger(),
 		opts:    opts,
 		nowFn:   db.Options().ClockOptions().NowFn(),
 		metrics: newServiceMetrics(scope, iopts.MetricsSamplingRate()),
+		limiter: newRequestLimiter(opts.ConcurrencyLimits(), scope),
 		pools: pools{
 			checkedBytesWrapper:     wrapperPool,
 			tagEncoder:              opts.TagEncoderPool(),
@@ -198,11 +234,14 @@ func NewService(db storage.Database, opts tchannelthrift.Options) rpc.TChanNode
 			blockMetadataV2Slice:    opts.BlockMetadataV2SlicePool(),
 			blocksMetadata:          opts.BlocksMetadataPool(),
 			blocksMetadataSlice:     opts.BlocksMetadataSlicePool(),
+			multiReaderIterator:     multiReaderIteratorPools,
 		},
 		health: &rpc.NodeHealthResult_{
 			Ok:           true,
 			Status:       "up",
 			Bootstrapped: false,
+			ApiVersion:   &apiVersion,
+			Capabilities: &capabilities,
 		},
 	}
 
@@ -239,6 +278,11 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, readRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(readRequestClass)
+
 	ctx := tchannelthrift.Context(tctx)
 
 	start, rangeStartErr := convert.ToTime(req.RangeStart, req.RangeType)
@@ -254,9 +298,13 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 	}
 
 	nsID := s.pools.id.GetStringID(ctx, req.NameSpace)
+	limits := s.opts.QueryLimits()
 	opts := index.QueryOptions{
-		StartInclusive: start,
-		EndExclusive:   end,
+		StartInclusive:     start,
+		EndExclusive:       end,
+		SegmentMergePolicy: s.opts.SegmentMergePolicy(),
+		MaxResults:         limits.MaxResults,
+		MaxSeriesMatched:   limits.MaxSeriesMatched,
 	}
 	if l := req.Limit; l != nil {
 		opts.Limit = int(*l)
@@ -265,6 +313,7 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 	if err != nil {
 		return nil, convert.ToRPCError(err)
 	}
+	tchannelthrift.SetQueryLimitsExceeded(tctx, queryResult.Warnings)
 
 	result := &rpc.QueryResult_{
 		Results:    make([]*rpc.QueryResultElement, 0, queryResult.Results.Map().Len()),
@@ -274,6 +323,10 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 	if req.NoData != nil && *req.NoData {
 		fetchData = false
 	}
+	var encodingName string
+	if fetchData {
+		encodingName = s.namespaceEncoding(nsID)
+	}
 	for _, entry := range queryResult.Results.Map().Iter() {
 		elem := &rpc.QueryResultElement{
 			ID:   entry.Key().String(),
@@ -291,7 +344,7 @@ func (s *service) Query(tctx thrift.Context, req *rpc.QueryRequest) (*rpc.QueryR
 		}
 		tsID := entry.Key()
 		datapoints, err := s.readDatapoints(ctx, nsID, tsID, start, end,
-			req.ResultTimeType)
+			encodingName, req.ResultTimeType)
 		if err != nil {
 			return nil, convert.ToRPCError(err)
 		}
@@ -307,15 +360,22 @@ func (s *service) Fetch(tctx thrift.Context, req *rpc.FetchRequest) (*rpc.FetchR
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, readRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(readRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	start, rangeStartErr := convert.ToTime(req.RangeStart, req.RangeType)
 	end, rangeEndErr := convert.ToTime(req.RangeEnd, req.RangeType)
 
 	if rangeStartErr != nil || rangeEndErr != nil {
 		s.metrics.fetch.ReportError(s.nowFn().Sub(callStart))
-		return nil, tterrors.NewBadRequestError(xerrors.FirstError(rangeStartErr, rangeEndErr))
+		return nil, tterrors.NewBadRequestError(withRequestMetadata(
+			xerrors.FirstError(rangeStartErr, rangeEndErr), requestMetadata))
 	}
 
 	tsID := s.pools.id.GetStringID(ctx, req.ID)
@@ -323,13 +383,16 @@ func (s *service) Fetch(tctx thrift.Context, req *rpc.FetchRequest) (*rpc.FetchR
 
 	// Make datapoints an initialized empty array for JSON serialization as empty array than null
 	datapoints, err := s.readDatapoints(ctx, nsID, tsID, start, end,
-		req.ResultTimeType)
+		s.namespaceEncoding(nsID), req.ResultTimeType)
 	if err != nil {
 		s.metrics.fetch.ReportError(s.nowFn().Sub(callStart))
-		return nil, convert.ToRPCError(err)
+		return nil, convert.ToRPCError(withRequestMetadata(err, requestMetadata))
 	}
 
-	s.metrics.fetch.ReportSuccess(s.nowFn().Sub(callStart))
+	s.markPartialAvailability(tctx, nsID, [][]byte{tsID.Bytes()})
+	took := s.nowFn().Sub(callStart)
+	s.logSlowFetch("fetch", nsID, 1, start, end, took, requestMetadata)
+	s.metrics.fetch.ReportSuccess(took)
 	return &rpc.FetchResult_{Datapoints: datapoints}, nil
 }
 
@@ -337,6 +400,7 @@ func (s *service) readDatapoints(
 	ctx context.Context,
 	nsID, tsID ident.ID,
 	start, end time.Time,
+	encodingName string,
 	timeType rpc.TimeType,
 ) ([]*rpc.Datapoint, error) {
 	encoded, err := s.db.ReadEncoded(ctx, nsID, tsID, start, end)
@@ -344,10 +408,15 @@ func (s *service) readDatapoints(
 		return nil, err
 	}
 
+	multiItPool, err := s.pools.multiReaderIterator.Pool(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Make datapoints an initialized empty array for JSON serialization as empty array than null
 	datapoints := make([]*rpc.Datapoint, 0)
 
-	multiIt := s.db.Options().MultiReaderIteratorPool().Get()
+	multiIt := multiItPool.Get()
 	multiIt.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(encoded))
 	defer multiIt.Close()
 
@@ -380,24 +449,210 @@ func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest)
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, readRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(readRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 	ns, query, opts, fetchData, err := convert.FromRPCFetchTaggedRequest(req, s.pools)
 	if err != nil {
 		s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
-		return nil, tterrors.NewBadRequestError(err)
+		return nil, tterrors.NewBadRequestError(withRequestMetadata(err, requestMetadata))
+	}
+	opts.RequestMetadata = requestMetadata
+	opts.SegmentMergePolicy = s.opts.SegmentMergePolicy()
+	limits := s.opts.QueryLimits()
+	opts.MaxResults = limits.MaxResults
+	opts.MaxSeriesMatched = limits.MaxSeriesMatched
+
+	if len(opts.AdditionalNamespaces) > 0 {
+		if opts.CountOnly {
+			s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
+			return nil, tterrors.NewBadRequestError(
+				withRequestMetadata(errMultiNamespaceCountOnly, requestMetadata))
+		}
+		namespaces := make([]ident.ID, 0, 1+len(opts.AdditionalNamespaces))
+		namespaces = append(namespaces, ns)
+		namespaces = append(namespaces, opts.AdditionalNamespaces...)
+		opts.AdditionalNamespaces = nil
+		response, err := s.fetchTaggedMultiNamespace(tctx, namespaces,
+			query, opts, fetchData)
+		if err != nil {
+			s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
+			return nil, tterrors.NewInternalError(withRequestMetadata(err, requestMetadata))
+		}
+		took := s.nowFn().Sub(callStart)
+		s.metrics.fetchTagged.ReportSuccess(took)
+		s.logSlowQuery("fetchTagged", ns, query, opts, len(response.Elements), took)
+		return response, nil
+	}
+
+	var encodingName string
+	if fetchData {
+		encodingName = s.namespaceEncoding(ns)
+	}
+	response := &rpc.FetchTaggedResult_{}
+
+	// NB: when streaming, the elements of each batch of series are built as
+	// the index is searched so that the tags of all matching series are not
+	// held at once, count only queries do not take the tags of series so
+	// are never streamed. Since the response is only sent once complete it
+	// stops taking series once it holds the max streamed bytes.
+	var (
+		streamed      = false
+		streamedBytes = 0
+		maxBytes      = s.opts.FetchTaggedStreamMaxBytes()
+	)
+	if opts.CountOnly {
+		opts.ShardFn = s.namespaceShardSet(ns).Lookup
+	}
+	if batchSize := s.opts.FetchTaggedStreamBatchSize(); batchSize > 0 && !opts.CountOnly {
+		streamed = true
+		nsBytes := append([]byte(nil), ns.Bytes()...)
+		opts.StreamBatchSize = batchSize
+		opts.StreamFn = func(batch index.Results) error {
+			numElems := len(response.Elements)
+			if err := s.appendFetchTaggedElements(ctx, response, nsBytes, batch,
+				opts.StartInclusive, opts.EndExclusive, fetchData, encodingName, true); err != nil {
+				return err
+			}
+			streamedBytes += fetchTaggedElementsSize(response.Elements[numElems:])
+			if maxBytes > 0 && streamedBytes >= maxBytes {
+				return errFetchTaggedResponseFull
+			}
+			return nil
+		}
 	}
 
 	queryResult, err := s.db.QueryIDs(ctx, ns, query, opts)
+	if err == errFetchTaggedResponseFull {
+		s.metrics.fetchTaggedResponseFull.Inc(1)
+		queryResult, err = index.QueryResults{Exhaustive: false}, nil
+	}
 	if err != nil {
 		s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
-		return nil, tterrors.NewInternalError(err)
+		return nil, tterrors.NewInternalError(withRequestMetadata(err, requestMetadata))
 	}
 
-	response := &rpc.FetchTaggedResult_{
-		Exhaustive: queryResult.Exhaustive,
-	}
+	response.Exhaustive = queryResult.Exhaustive
 	results := queryResult.Results
+	tchannelthrift.SetQueryLimitsExceeded(tctx, queryResult.Warnings)
+	if opts.CountOnly {
+		// Counts are returned per shard so clients can dedupe across replicas.
+		response.ShardCounts = make(map[int32]int64)
+		if counts, ok := results.(index.CountResults); ok {
+			for shard, count := range counts.ShardCounts() {
+				response.ShardCounts[int32(shard)] = count
+			}
+		}
+		if s.markPartialAvailability(tctx, ns, nil) {
+			response.Exhaustive = false
+		}
+		took := s.nowFn().Sub(callStart)
+		s.metrics.fetchTagged.ReportSuccess(took)
+		s.logSlowQuery("fetchTagged", ns, query, opts, results.Size(), took)
+		return response, nil
+	}
+
+	if !streamed {
+		nsBytes := results.Namespace().Bytes()
+		if err := s.appendFetchTaggedElements(ctx, response, nsBytes, results,
+			opts.StartInclusive, opts.EndExclusive, fetchData, encodingName, false); err != nil {
+			s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
+			return nil, tterrors.NewInternalError(withRequestMetadata(err, requestMetadata))
+		}
+	}
+
+	if s.markPartialAvailability(tctx, ns, nil) {
+		response.Exhaustive = false
+	}
+	took := s.nowFn().Sub(callStart)
+	s.metrics.fetchTagged.ReportSuccess(took)
+	s.logSlowQuery("fetchTagged", ns, query, opts, len(response.Elements), took)
+	return response, nil
+}
+
+// fetchTaggedMultiNamespace executes the query against each of the
+// namespaces and merges their results into a single response, each element
+// carries the namespace of its series. The limit of the query applies to
+// the merged response.
+func (s *service) fetchTaggedMultiNamespace(
+	tctx thrift.Context,
+	namespaces []ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+	fetchData bool,
+) (*rpc.FetchTaggedResult_, error) {
+	ctx := tchannelthrift.Context(tctx)
+	queryResults, err := s.db.QueryIDsMultiNamespace(ctx, namespaces, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tchannelthrift.SetQueryLimitsExceeded(tctx, queryResults.Warnings)
+	var (
+		response = &rpc.FetchTaggedResult_{Exhaustive: queryResults.Exhaustive}
+		// elemNamespaces holds the index of the namespace of each element.
+		elemNamespaces []int
+	)
+	for i, results := range queryResults.Results {
+		nsBytes := results.Namespace().Bytes()
+		if err := s.appendFetchTaggedElements(ctx, response, nsBytes, results,
+			opts.StartInclusive, opts.EndExclusive, false, "", false); err != nil {
+			return nil, err
+		}
+		for len(elemNamespaces) < len(response.Elements) {
+			elemNamespaces = append(elemNamespaces, i)
+		}
+		if s.markPartialAvailability(tctx, namespaces[i], nil) {
+			response.Exhaustive = false
+		}
+	}
+
+	// NB: apply the limit before any data is read so that series beyond the
+	// limit are never read.
+	if opts.Limit > 0 && len(response.Elements) > opts.Limit {
+		response.Elements = response.Elements[:opts.Limit]
+		response.Exhaustive = false
+	}
+	if !fetchData {
+		return response, nil
+	}
+
+	encodingNames := make([]string, len(namespaces))
+	for i, nsID := range namespaces {
+		encodingNames[i] = s.namespaceEncoding(nsID)
+	}
+	for i, elem := range response.Elements {
+		nsIdx := elemNamespaces[i]
+		segments, rpcErr := s.readEncoded(ctx, namespaces[nsIdx],
+			ident.BytesID(elem.ID), opts.StartInclusive, opts.EndExclusive,
+			encodingNames[nsIdx])
+		if rpcErr != nil {
+			elem.Err = rpcErr
+			continue
+		}
+		elem.Segments = segments
+	}
+	return response, nil
+}
+
+// appendFetchTaggedElements appends an element for each series of the
+// results to the response, the IDs of the series are copied if the results
+// are reset before the response is sent.
+func (s *service) appendFetchTaggedElements(
+	ctx context.Context,
+	response *rpc.FetchTaggedResult_,
+	nsBytes []byte,
+	results index.Results,
+	start, end time.Time,
+	fetchData bool,
+	encodingName string,
+	copyIDs bool,
+) error {
 	nsID := results.Namespace()
 	tagsIter := ident.NewTagsIterator(ident.Tags{})
 	for _, entry := range results.Map().Iter() {
@@ -408,29 +663,120 @@ func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest)
 		tagsIter.Reset(tags)
 		encodedTags, err := s.encodeTags(enc, tagsIter)
 		if err != nil { // This is an invariant, should never happen
-			s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
-			return nil, tterrors.NewInternalError(err)
+			return err
 		}
 
+		idBytes := tsID.Bytes()
+		if copyIDs {
+			idBytes = append([]byte(nil), idBytes...)
+		}
 		elem := &rpc.FetchTaggedIDResult_{
-			NameSpace:   nsID.Bytes(),
-			ID:          tsID.Bytes(),
+			NameSpace:   nsBytes,
+			ID:          idBytes,
 			EncodedTags: encodedTags.Bytes(),
 		}
 		response.Elements = append(response.Elements, elem)
 		if !fetchData {
 			continue
 		}
-		segments, rpcErr := s.readEncoded(ctx, nsID, tsID, opts.StartInclusive, opts.EndExclusive)
+		segments, rpcErr := s.readEncoded(ctx, nsID, tsID, start, end, encodingName)
 		if rpcErr != nil {
 			elem.Err = rpcErr
 			continue
 		}
 		elem.Segments = segments
 	}
+	return nil
+}
 
-	s.metrics.fetchTagged.ReportSuccess(s.nowFn().Sub(callStart))
-	return response, nil
+// fetchTaggedElementsSize returns the size of the IDs, tags and data held by
+// the elements of a fetch tagged response.
+func fetchTaggedElementsSize(elems []*rpc.FetchTaggedIDResult_) int {
+	size := 0
+	for _, elem := range elems {
+		size += len(elem.ID) + len(elem.EncodedTags)
+		for _, segments := range elem.Segments {
+			if segments.Merged != nil {
+				size += len(segments.Merged.Head) + len(segments.Merged.Tail)
+			}
+			for _, segment := range segments.Unmerged {
+				size += len(segment.Head) + len(segment.Tail)
+			}
+		}
+	}
+	return size
+}
+
+// logSlowQuery logs the query if it took longer than the slow query
+// threshold, including the request metadata so it can be correlated with
+// the caller.
+func (s *service) logSlowQuery(
+	method string,
+	ns ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+	numResults int,
+	took time.Duration,
+) {
+	threshold := s.opts.SlowQueryThreshold()
+	if threshold <= 0 || took < threshold {
+		return
+	}
+	s.logger.WithFields(
+		log.NewField("method", method),
+		log.NewField("namespace", ns.String()),
+		log.NewField("query", query.String()),
+		log.NewField("start", opts.StartInclusive),
+		log.NewField("end", opts.EndExclusive),
+		log.NewField("results", numResults),
+		log.NewField("took", took),
+		log.NewField("requestMetadata", opts.RequestMetadata),
+	).Warn("slow query")
+}
+
+// logSlowFetch logs the fetch of series by ID if it took longer than the
+// slow query threshold, including the request metadata so it can be
+// correlated with the caller.
+func (s *service) logSlowFetch(
+	method string,
+	ns ident.ID,
+	numIDs int,
+	start, end time.Time,
+	took time.Duration,
+	requestMetadata string,
+) {
+	threshold := s.opts.SlowQueryThreshold()
+	if threshold <= 0 || took < threshold {
+		return
+	}
+	s.logger.WithFields(
+		log.NewField("method", method),
+		log.NewField("namespace", ns.String()),
+		log.NewField("ids", numIDs),
+		log.NewField("start", start),
+		log.NewField("end", end),
+		log.NewField("took", took),
+		log.NewField("requestMetadata", requestMetadata),
+	).Warn("slow fetch")
+}
+
+// withRequestMetadata returns the error with the request metadata, if any,
+// appended to its message.
+func withRequestMetadata(err error, requestMetadata string) error {
+	if requestMetadata == "" {
+		return err
+	}
+	return xerrors.NewRenamedError(err,
+		errors.New(appendRequestMetadata(err.Error(), requestMetadata)))
+}
+
+// appendRequestMetadata returns the message with the request metadata, if
+// any, appended to it.
+func appendRequestMetadata(message string, requestMetadata string) string {
+	if requestMetadata == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (request metadata: %s)", message, requestMetadata)
 }
 
 func (s *service) encodeTags(
@@ -461,8 +807,14 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, readRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(readRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	start, rangeStartErr := convert.ToTime(req.RangeStart, req.RangeTimeType)
 	end, rangeEndErr := convert.ToTime(req.RangeEnd, req.RangeTimeType)
@@ -470,10 +822,12 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 	if rangeStartErr != nil || rangeEndErr != nil {
 		s.metrics.fetchBatchRaw.ReportNonRetryableErrors(len(req.Ids))
 		s.metrics.fetchBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
-		return nil, tterrors.NewBadRequestError(xerrors.FirstError(rangeStartErr, rangeEndErr))
+		return nil, tterrors.NewBadRequestError(withRequestMetadata(
+			xerrors.FirstError(rangeStartErr, rangeEndErr), requestMetadata))
 	}
 
 	nsID := s.newID(ctx, req.NameSpace)
+	encodingName := s.namespaceEncoding(nsID)
 
 	result := rpc.NewFetchBatchRawResult_()
 
@@ -488,8 +842,9 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 		result.Elements = append(result.Elements, rawResult)
 
 		tsID := s.newID(ctx, req.Ids[i])
-		segments, rpcErr := s.readEncoded(ctx, nsID, tsID, start, end)
+		segments, rpcErr := s.readEncoded(ctx, nsID, tsID, start, end, encodingName)
 		if rpcErr != nil {
+			rpcErr.Message = appendRequestMetadata(rpcErr.Message, requestMetadata)
 			rawResult.Err = rpcErr
 			if tterrors.IsBadRequestError(rawResult.Err) {
 				nonRetryableErrors++
@@ -503,11 +858,14 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 		rawResult.Segments = segments
 	}
 
+	took := s.nowFn().Sub(callStart)
+	s.logSlowFetch("fetchBatchRaw", nsID, len(req.Ids), start, end, took, requestMetadata)
 	s.metrics.fetchBatchRaw.ReportSuccess(success)
 	s.metrics.fetchBatchRaw.ReportRetryableErrors(retryableErrors)
 	s.metrics.fetchBatchRaw.ReportNonRetryableErrors(nonRetryableErrors)
-	s.metrics.fetchBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+	s.metrics.fetchBatchRaw.ReportLatency(took)
 
+	s.markPartialAvailability(tctx, nsID, req.Ids)
 	return result, nil
 }
 
@@ -517,6 +875,11 @@ func (s *service) FetchBlocksRaw(tctx thrift.Context, req *rpc.FetchBlocksRawReq
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, streamRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(streamRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
@@ -571,6 +934,9 @@ func (s *service) FetchBlocksRaw(tctx thrift.Context, req *rpc.FetchBlocksRawReq
 				}
 				block.Segments = converted.Segments
 				block.Checksum = converted.Checksum
+				if encodingName := nsMetadata.Options().Encoding(); encodingName != "" {
+					block.Segments.Encoding = &encodingName
+				}
 			}
 
 			blocks.Blocks = append(blocks.Blocks, block)
@@ -590,6 +956,11 @@ func (s *service) FetchBlocksMetadataRaw(tctx thrift.Context, req *rpc.FetchBloc
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, streamRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(streamRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
@@ -694,6 +1065,11 @@ func (s *service) FetchBlocksMetadataRawV2(tctx thrift.Context, req *rpc.FetchBl
 		return nil, tterrors.NewInternalError(errServerIsOverloaded)
 	}
 
+	if err := s.limiter.acquire(tctx, streamRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(streamRequestClass)
+
 	var err error
 	callStart := s.nowFn()
 	defer func() {
@@ -824,12 +1200,18 @@ func (s *service) getBlocksMetadataV2FromResult(
 }
 
 func (s *service) Write(tctx thrift.Context, req *rpc.WriteRequest) error {
+	if err := s.limiter.acquire(tctx, writeRequestClass); err != nil {
+		return err
+	}
+	defer s.limiter.release(writeRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	if req.Datapoint == nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(errRequiresDatapoint)
+		return tterrors.NewBadRequestError(withRequestMetadata(errRequiresDatapoint, requestMetadata))
 	}
 
 	dp := req.Datapoint
@@ -837,40 +1219,49 @@ func (s *service) Write(tctx thrift.Context, req *rpc.WriteRequest) error {
 
 	if unitErr != nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(unitErr)
+		return tterrors.NewBadRequestError(withRequestMetadata(unitErr, requestMetadata))
 	}
 
 	d, err := unit.Value()
 	if err != nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(err)
+		return tterrors.NewBadRequestError(withRequestMetadata(err, requestMetadata))
 	}
 
-	if err = s.db.Write(
+	newSeries, err := s.db.Write(
 		ctx, s.pools.id.GetStringID(ctx, req.NameSpace), s.pools.id.GetStringID(ctx, req.ID),
 		xtime.FromNormalizedTime(dp.Timestamp, d), dp.Value, unit, dp.Annotation,
-	); err != nil {
+	)
+	if err != nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
-		return convert.ToRPCError(err)
+		return convert.ToRPCError(withRequestMetadata(err, requestMetadata))
 	}
 
+	s.recordSeriesWrite(newSeries)
+
 	s.metrics.write.ReportSuccess(s.nowFn().Sub(callStart))
 
 	return nil
 }
 
 func (s *service) WriteTagged(tctx thrift.Context, req *rpc.WriteTaggedRequest) error {
+	if err := s.limiter.acquire(tctx, writeRequestClass); err != nil {
+		return err
+	}
+	defer s.limiter.release(writeRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	if req.Datapoint == nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(errRequiresDatapoint)
+		return tterrors.NewBadRequestError(withRequestMetadata(errRequiresDatapoint, requestMetadata))
 	}
 
 	if req.Tags == nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(errIllegalTagValues)
+		return tterrors.NewBadRequestError(withRequestMetadata(errIllegalTagValues, requestMetadata))
 	}
 
 	dp := req.Datapoint
@@ -878,38 +1269,50 @@ func (s *service) WriteTagged(tctx thrift.Context, req *rpc.WriteTaggedRequest)
 
 	if unitErr != nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(unitErr)
+		return tterrors.NewBadRequestError(withRequestMetadata(unitErr, requestMetadata))
 	}
 
 	d, err := unit.Value()
 	if err != nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(err)
+		return tterrors.NewBadRequestError(withRequestMetadata(err, requestMetadata))
 	}
 
 	iter, err := convert.ToTagsIter(req)
 	if err != nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return tterrors.NewBadRequestError(err)
+		return tterrors.NewBadRequestError(withRequestMetadata(err, requestMetadata))
 	}
 
-	if err = s.db.WriteTagged(ctx,
+	newSeries, err := s.db.WriteTagged(ctx,
 		s.pools.id.GetStringID(ctx, req.NameSpace),
 		s.pools.id.GetStringID(ctx, req.ID),
 		iter, xtime.FromNormalizedTime(dp.Timestamp, d),
-		dp.Value, unit, dp.Annotation); err != nil {
+		dp.Value, unit, dp.Annotation)
+	if err != nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
-		return convert.ToRPCError(err)
+		return convert.ToRPCError(withRequestMetadata(err, requestMetadata))
 	}
 
+	s.recordSeriesWrite(newSeries)
+
 	s.metrics.writeTagged.ReportSuccess(s.nowFn().Sub(callStart))
 
 	return nil
 }
 
-func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawRequest) error {
+func (s *service) WriteBatchRaw(
+	tctx thrift.Context,
+	req *rpc.WriteBatchRawRequest,
+) (*rpc.WriteBatchRawResult_, error) {
+	if err := s.limiter.acquire(tctx, writeRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(writeRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	// NB(r): Use the pooled request tracking to return thrift alloc'd bytes
 	// to the thrift bytes pool and to return ident.ID wrappers to a pool for
@@ -923,6 +1326,7 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 
 	var (
 		errs               []*rpc.WriteBatchRawError
+		newSeriesIndexes   []int64
 		success            int
 		retryableErrors    int
 		nonRetryableErrors int
@@ -931,30 +1335,39 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 		unit, unitErr := convert.ToUnit(elem.Datapoint.TimestampTimeType)
 		if unitErr != nil {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, unitErr))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(unitErr, requestMetadata)))
 			continue
 		}
 
 		d, err := unit.Value()
 		if err != nil {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 			continue
 		}
 
 		seriesID := s.newPooledID(ctx, elem.ID, pooledReq)
-		if err = s.db.Write(
+		newSeries, err := s.db.Write(
 			ctx, nsID, seriesID,
 			xtime.FromNormalizedTime(elem.Datapoint.Timestamp, d),
 			elem.Datapoint.Value, unit, elem.Datapoint.Annotation,
-		); err != nil && xerrors.IsInvalidParams(err) {
+		)
+		if err != nil && xerrors.IsInvalidParams(err) {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 		} else if err != nil {
 			retryableErrors++
-			errs = append(errs, tterrors.NewWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 		} else {
 			success++
+			s.recordSeriesWrite(newSeries)
+			if newSeries {
+				newSeriesIndexes = append(newSeriesIndexes, int64(i))
+			}
 		}
 	}
 
@@ -966,15 +1379,26 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 	if len(errs) > 0 {
 		batchErrs := rpc.NewWriteBatchRawErrors()
 		batchErrs.Errors = errs
-		return batchErrs
+		return nil, batchErrs
 	}
 
-	return nil
+	result := rpc.NewWriteBatchRawResult_()
+	result.NewSeriesIndexes = newSeriesIndexes
+	return result, nil
 }
 
-func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedBatchRawRequest) error {
+func (s *service) WriteTaggedBatchRaw(
+	tctx thrift.Context,
+	req *rpc.WriteTaggedBatchRawRequest,
+) (*rpc.WriteBatchRawResult_, error) {
+	if err := s.limiter.acquire(tctx, writeRequestClass); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release(writeRequestClass)
+
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
+	requestMetadata := tchannelthrift.RequestMetadata(tctx)
 
 	// NB(r): Use the pooled request tracking to return thrift alloc'd bytes
 	// to the thrift bytes pool and to return ident.ID wrappers to a pool for
@@ -988,6 +1412,7 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 
 	var (
 		errs               []*rpc.WriteBatchRawError
+		newSeriesIndexes   []int64
 		success            int
 		retryableErrors    int
 		nonRetryableErrors int
@@ -996,37 +1421,47 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 		unit, unitErr := convert.ToUnit(elem.Datapoint.TimestampTimeType)
 		if unitErr != nil {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, unitErr))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(unitErr, requestMetadata)))
 			continue
 		}
 
 		d, err := unit.Value()
 		if err != nil {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 			continue
 		}
 
 		dec, err := s.newPooledTagsDecoder(ctx, elem.EncodedTags, pooledReq)
 		if err != nil {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 			continue
 		}
 
 		seriesID := s.newPooledID(ctx, elem.ID, pooledReq)
-		if err = s.db.WriteTagged(
+		newSeries, err := s.db.WriteTagged(
 			ctx, nsID, seriesID, dec,
 			xtime.FromNormalizedTime(elem.Datapoint.Timestamp, d),
 			elem.Datapoint.Value, unit, elem.Datapoint.Annotation,
-		); err != nil && xerrors.IsInvalidParams(err) {
+		)
+		if err != nil && xerrors.IsInvalidParams(err) {
 			nonRetryableErrors++
-			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewBadRequestWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 		} else if err != nil {
 			retryableErrors++
-			errs = append(errs, tterrors.NewWriteBatchRawError(i, err))
+			errs = append(errs, tterrors.NewWriteBatchRawError(i,
+				withRequestMetadata(err, requestMetadata)))
 		} else {
 			success++
+			s.recordSeriesWrite(newSeries)
+			if newSeries {
+				newSeriesIndexes = append(newSeriesIndexes, int64(i))
+			}
 		}
 	}
 
@@ -1038,10 +1473,20 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 	if len(errs) > 0 {
 		batchErrs := rpc.NewWriteBatchRawErrors()
 		batchErrs.Errors = errs
-		return batchErrs
+		return nil, batchErrs
 	}
 
-	return nil
+	result := rpc.NewWriteBatchRawResult_()
+	result.NewSeriesIndexes = newSeriesIndexes
+	return result, nil
+}
+
+func (s *service) recordSeriesWrite(newSeries bool) {
+	if newSeries {
+		s.metrics.newSeriesWrites.Inc(1)
+		return
+	}
+	s.metrics.existingSeriesWrites.Inc(1)
 }
 
 func (s *service) Repair(tctx thrift.Context) error {
@@ -1205,6 +1650,56 @@ func (s *service) isOverloaded() bool {
 	return s.db.IsOverloaded()
 }
 
+// markPartialAvailability sets the partial availability response header if
+// reads are being served from shards of the namespace that are still
+// bootstrapping and returns whether the header was set. Only the shards of
+// the IDs are considered, or all shards of the namespace if there are none.
+func (s *service) markPartialAvailability(
+	tctx thrift.Context,
+	nsID ident.ID,
+	ids [][]byte,
+) bool {
+	if !s.db.Options().ReadWhileBootstrapping() {
+		return false
+	}
+	ns, ok := s.db.Namespace(nsID)
+	if !ok {
+		return false
+	}
+
+	var bootstrapping map[uint32]struct{}
+	for _, shard := range ns.Shards() {
+		if shard.IsBootstrapped() {
+			continue
+		}
+		if bootstrapping == nil {
+			bootstrapping = make(map[uint32]struct{})
+		}
+		bootstrapping[shard.ID()] = struct{}{}
+	}
+	if len(bootstrapping) == 0 {
+		return false
+	}
+
+	partial := len(ids) == 0
+	if !partial {
+		shardSet := s.namespaceShardSet(nsID)
+		for _, id := range ids {
+			if _, ok := bootstrapping[shardSet.Lookup(ident.BytesID(id))]; ok {
+				partial = true
+				break
+			}
+		}
+	}
+	if !partial {
+		return false
+	}
+
+	s.metrics.partialReads.Inc(1)
+	tchannelthrift.SetPartialAvailability(tctx)
+	return true
+}
+
 func (s *service) newID(ctx context.Context, id []byte) ident.ID {
 	checkedBytes := s.pools.checkedBytesWrapper.Get(id)
 	return s.pools.id.GetBinaryID(ctx, checkedBytes)
@@ -1225,6 +1720,7 @@ func (s *service) readEncoded(
 	ctx context.Context,
 	nsID, tsID ident.ID,
 	start, end time.Time,
+	encodingName string,
 ) ([]*rpc.Segments, *rpc.Error) {
 	encoded, err := s.db.ReadEncoded(ctx, nsID, tsID, start, end)
 	if err != nil {
@@ -1246,12 +1742,39 @@ func (s *service) readEncoded(
 		if converted.Segments == nil {
 			continue
 		}
+		if encodingName != "" {
+			converted.Segments.Encoding = &encodingName
+		}
 		segments = append(segments, converted.Segments)
 	}
 
 	return segments, nil
 }
 
+// namespaceEncoding returns the name of the codec the namespace encodes
+// data with, it is empty for the default codec.
+func (s *service) namespaceEncoding(nsID ident.ID) string {
+	md, ok := s.db.Namespace(nsID)
+	if !ok {
+		return ""
+	}
+	return md.Options().Encoding()
+}
+
+// namespaceShardSet returns the shard set used to look up the shard of a
+// series in a namespace, taking into account its shard routing tag.
+func (s *service) namespaceShardSet(nsID ident.ID) sharding.ShardSet {
+	shardSet := s.db.ShardSet()
+	md, ok := s.db.Namespace(nsID)
+	if !ok {
+		return shardSet
+	}
+	if tag := md.Options().ShardRoutingTag(); tag != "" {
+		return sharding.NewTagRoutingShardSet(shardSet, []byte(tag))
+	}
+	return shardSet
+}
+
 func (s *service) newTagsDecoder(ctx context.Context, encodedTags []byte) (serialize.TagDecoder, error) {
 	checkedBytes := s.pools.checkedBytesWrapper.Get(encodedTags)
 	dec := s.pools.tagDecoder.Get()
@@ -28,12 +28,15 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -41,7 +44,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3cluster/shard"
 	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
@@ -82,6 +87,8 @@ func TestServiceHealth(t *testing.T) {
 	assert.Equal(t, true, result.Ok)
 	assert.Equal(t, "up", result.Status)
 	assert.Equal(t, false, result.Bootstrapped)
+	assert.Equal(t, tchannelthrift.APIVersion, result.GetApiVersion())
+	assert.Equal(t, int64(tchannelthrift.ServerCapabilities), result.GetCapabilities())
 
 	// Assert bootstrapped true
 	mockDB.EXPECT().IsBootstrapped().Return(true)
@@ -101,6 +108,7 @@ func TestServiceQuery(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, nil).(*service)
@@ -275,6 +283,7 @@ func TestServiceFetch(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, nil).(*service)
@@ -335,6 +344,127 @@ func TestServiceFetch(t *testing.T) {
 	}
 }
 
+func TestServiceMarkPartialAvailability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1}, shard.Available),
+		func(id ident.ID) uint32 {
+			if id.String() == "bootstrapping" {
+				return 1
+			}
+			return 0
+		})
+	require.NoError(t, err)
+
+	bootstrapped := storage.NewMockShard(ctrl)
+	bootstrapped.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	bootstrapping := storage.NewMockShard(ctrl)
+	bootstrapping.EXPECT().IsBootstrapped().Return(false).AnyTimes()
+	bootstrapping.EXPECT().ID().Return(uint32(1)).AnyTimes()
+
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Options().Return(namespace.NewOptions()).AnyTimes()
+	mockNs.EXPECT().Shards().
+		Return([]storage.Shard{bootstrapped, bootstrapping}).AnyTimes()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().
+		Return(testStorageOpts.SetReadWhileBootstrapping(true)).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(mockNs, true).AnyTimes()
+	mockDB.EXPECT().ShardSet().Return(shardSet).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+	nsID := ident.StringID("metrics")
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	require.False(t, service.markPartialAvailability(tctx, nsID,
+		[][]byte{[]byte("foo")}))
+	require.False(t, tchannelthrift.PartialAvailability(tctx.ResponseHeaders()))
+
+	// Reads of a series of a bootstrapping shard are partial.
+	require.True(t, service.markPartialAvailability(tctx, nsID,
+		[][]byte{[]byte("foo"), []byte("bootstrapping")}))
+	require.True(t, tchannelthrift.PartialAvailability(tctx.ResponseHeaders()))
+
+	// Reads of all shards are partial if any shard is bootstrapping.
+	tctx, _ = tchannelthrift.NewContext(time.Minute)
+	require.True(t, service.markPartialAvailability(tctx, nsID, nil))
+	require.True(t, tchannelthrift.PartialAvailability(tctx.ResponseHeaders()))
+}
+func TestServiceFetchNamespaceEncoding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nsID := "metrics"
+	md, err := namespace.NewMetadata(ident.StringID(nsID),
+		namespace.NewOptions().SetEncoding(m3tsz.IntDeltaCodecName))
+	require.NoError(t, err)
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID)).Return(md, true).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+
+	values := []struct {
+		t time.Time
+		v float64
+	}{
+		{start.Add(10 * time.Second), 100.0},
+		{start.Add(20 * time.Second), 105.0},
+		{start.Add(30 * time.Second), 97.0},
+	}
+	enc := m3tsz.NewIntDeltaEncoder(start, nil, encoding.NewOptions())
+	for _, v := range values {
+		dp := ts.Datapoint{Timestamp: v.t, Value: v.v}
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+
+	for i := 0; i < 2; i++ {
+		mockDB.EXPECT().
+			ReadEncoded(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher("foo"), start, end).
+			Return([][]xio.BlockReader{{{SegmentReader: enc.Stream()}}}, nil)
+	}
+
+	r, err := service.Fetch(tctx, &rpc.FetchRequest{
+		RangeStart:     start.Unix(),
+		RangeEnd:       end.Unix(),
+		RangeType:      rpc.TimeType_UNIX_SECONDS,
+		NameSpace:      nsID,
+		ID:             "foo",
+		ResultTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, len(values), len(r.Datapoints))
+	for i, v := range values {
+		assert.Equal(t, v.t, time.Unix(r.Datapoints[i].Timestamp, 0))
+		assert.Equal(t, v.v, r.Datapoints[i].Value)
+	}
+
+	raw, err := service.FetchBatchRaw(tctx, &rpc.FetchBatchRawRequest{
+		RangeStart:    start.Unix(),
+		RangeEnd:      end.Unix(),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+		NameSpace:     []byte(nsID),
+		Ids:           [][]byte{[]byte("foo")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(raw.Elements))
+	require.Equal(t, 1, len(raw.Elements[0].Segments))
+	assert.Equal(t, m3tsz.IntDeltaCodecName, raw.Elements[0].Segments[0].GetEncoding())
+}
+
 func TestServiceFetchIsOverloaded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -374,6 +504,7 @@ func TestServiceFetchBatchRaw(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, nil).(*service)
@@ -990,6 +1121,7 @@ func TestServiceFetchTagged(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, nil).(*service)
@@ -1239,6 +1371,302 @@ func TestServiceFetchTaggedNoData(t *testing.T) {
 	}
 }
 
+func TestServiceFetchTaggedStreamed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	opts := tchannelthrift.NewOptions().SetFetchTaggedStreamBatchSize(1)
+	service := NewService(mockDB, opts).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+
+	mockDB.EXPECT().QueryIDs(ctx, ident.NewIDMatcher(nsID), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			_ ident.ID,
+			_ index.Query,
+			opts index.QueryOptions,
+		) (index.QueryResults, error) {
+			require.NotNil(t, opts.StreamFn)
+			require.Equal(t, 1, opts.StreamBatchSize)
+
+			// each batch is reset once delivered, as the index does.
+			batch := index.NewResults(index.NewOptions())
+			for _, id := range []string{"foo", "bar"} {
+				batch.Reset(ident.StringID(nsID))
+				batch.Map().Set(ident.StringID(id), ident.Tags{})
+				require.NoError(t, opts.StreamFn(batch))
+			}
+			batch.Reset(ident.StringID(nsID))
+			return index.QueryResults{Results: batch, Exhaustive: true}, nil
+		})
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	r, err := service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		FetchData:  false,
+	})
+	require.NoError(t, err)
+	require.True(t, r.Exhaustive)
+
+	ids := [][]byte{[]byte("foo"), []byte("bar")}
+	require.Equal(t, len(ids), len(r.Elements))
+	for i, id := range ids {
+		elem := r.Elements[i]
+		require.Nil(t, elem.Err)
+		require.Equal(t, id, elem.ID)
+		require.Equal(t, []byte(nsID), elem.NameSpace)
+	}
+}
+
+func TestServiceFetchTaggedStreamedMaxBytes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	opts := tchannelthrift.NewOptions().
+		SetFetchTaggedStreamBatchSize(1).
+		SetFetchTaggedStreamMaxBytes(1)
+	service := NewService(mockDB, opts).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+
+	mockDB.EXPECT().QueryIDs(ctx, ident.NewIDMatcher(nsID), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			_ ident.ID,
+			_ index.Query,
+			opts index.QueryOptions,
+		) (index.QueryResults, error) {
+			// the index stops searching once the stream function errors.
+			batch := index.NewResults(index.NewOptions())
+			batch.Reset(ident.StringID(nsID))
+			batch.Map().Set(ident.StringID("foo"), ident.Tags{})
+			if err := opts.StreamFn(batch); err != nil {
+				return index.QueryResults{}, err
+			}
+			require.FailNow(t, "expected the response to be full")
+			return index.QueryResults{}, nil
+		})
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	r, err := service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		FetchData:  false,
+	})
+	require.NoError(t, err)
+	require.False(t, r.Exhaustive)
+	require.Len(t, r.Elements, 1)
+	require.Equal(t, []byte("foo"), r.Elements[0].ID)
+}
+
+func TestServiceFetchTaggedQueryLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	limits := tchannelthrift.QueryLimits{MaxResults: 1, MaxSeriesMatched: 10}
+	opts := tchannelthrift.NewOptions().SetQueryLimits(limits)
+	service := NewService(mockDB, opts).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	exceeded := index.QueryLimitExceeded{Limit: index.MaxResultsQueryLimit, Max: 1}
+	resMap := index.NewResults(index.NewOptions())
+	resMap.Reset(ident.StringID(nsID))
+	resMap.Map().Set(ident.StringID("foo"), ident.Tags{})
+	mockDB.EXPECT().QueryIDs(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		index.QueryOptions{
+			StartInclusive:   start,
+			EndExclusive:     end,
+			MaxResults:       1,
+			MaxSeriesMatched: 10,
+		}).Return(index.QueryResults{
+		Results:  resMap,
+		Warnings: []index.QueryLimitExceeded{exceeded},
+	}, nil)
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	r, err := service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		FetchData:  false,
+	})
+	require.NoError(t, err)
+	require.False(t, r.Exhaustive)
+	require.Equal(t, 1, len(r.Elements))
+
+	warnings, err := tchannelthrift.QueryLimitsExceeded(tctx.ResponseHeaders())
+	require.NoError(t, err)
+	require.Equal(t, []index.QueryLimitExceeded{exceeded}, warnings)
+}
+
+func TestServiceFetchTaggedMultiNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	unaggregated := index.NewResults(index.NewOptions())
+	unaggregated.Reset(ident.StringID("unaggregated"))
+	unaggregated.Map().Set(ident.StringID("foo"), ident.Tags{})
+	unaggregated.Map().Set(ident.StringID("bar"), ident.Tags{})
+	aggregated := index.NewResults(index.NewOptions())
+	aggregated.Reset(ident.StringID("aggregated"))
+	aggregated.Map().Set(ident.StringID("foo"), ident.Tags{})
+
+	mockDB.EXPECT().QueryIDsMultiNamespace(
+		ctx,
+		gomock.Any(),
+		index.NewQueryMatcher(qry),
+		index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			Limit:          2,
+		}).
+		Do(func(_ context.Context, namespaces []ident.ID, _ index.Query, _ index.QueryOptions) {
+			require.Equal(t, 2, len(namespaces))
+			require.Equal(t, "unaggregated", namespaces[0].String())
+			require.Equal(t, "aggregated", namespaces[1].String())
+		}).
+		Return(index.MultiNamespaceQueryResults{
+			Results:    []index.Results{unaggregated, aggregated},
+			Exhaustive: true,
+		}, nil)
+
+	// NB: only the series within the limit are read.
+	for _, id := range []string{"foo", "bar"} {
+		mockDB.EXPECT().
+			ReadEncoded(ctx, ident.NewIDMatcher("unaggregated"), ident.NewIDMatcher(id), start, end).
+			Return(nil, nil)
+	}
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	limit := int64(2)
+	r, err := service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:            []byte("unaggregated"),
+		AdditionalNameSpaces: [][]byte{[]byte("aggregated")},
+		Query:                data,
+		RangeStart:           startNanos,
+		RangeEnd:             endNanos,
+		FetchData:            true,
+		Limit:                &limit,
+	})
+	require.NoError(t, err)
+
+	// The limit applies to the merged results of all namespaces.
+	require.False(t, r.Exhaustive)
+	require.Equal(t, 2, len(r.Elements))
+	for _, elem := range r.Elements {
+		require.Equal(t, "unaggregated", string(elem.NameSpace))
+	}
+
+	countOnly := true
+	_, err = service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:            []byte("unaggregated"),
+		AdditionalNameSpaces: [][]byte{[]byte("aggregated")},
+		Query:                data,
+		RangeStart:           startNanos,
+		RangeEnd:             endNanos,
+		CountOnly:            &countOnly,
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+}
+
 func TestServiceFetchTaggedErrs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1313,7 +1741,7 @@ func TestServiceWrite(t *testing.T) {
 
 	mockDB.EXPECT().
 		Write(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher(id), at, value, xtime.Second, nil).
-		Return(nil)
+		Return(true, nil)
 
 	err := service.Write(tctx, &rpc.WriteRequest{
 		NameSpace: nsID,
@@ -1327,6 +1755,45 @@ func TestServiceWrite(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServiceWriteErrorRequestMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+
+	service := NewService(mockDB, nil).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	tctx = tchannelthrift.WithRequestMetadata(tctx, "rqID=abc")
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	at := time.Now().Truncate(time.Second)
+	mockDB.EXPECT().
+		Write(ctx, ident.NewIDMatcher("metrics"), ident.NewIDMatcher("foo"),
+			at, 42.42, xtime.Second, nil).
+		Return(false, fmt.Errorf("write failed"))
+
+	err := service.Write(tctx, &rpc.WriteRequest{
+		NameSpace: "metrics",
+		ID:        "foo",
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         at.Unix(),
+			TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			Value:             42.42,
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "write failed (request metadata: rqID=abc)")
+
+	// Bad requests also include the request metadata.
+	err = service.Write(tctx, &rpc.WriteRequest{NameSpace: "metrics", ID: "foo"})
+	require.Error(t, err)
+	assert.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+	assert.Contains(t, err.Error(), "(request metadata: rqID=abc)")
+}
+
 func TestServiceWriteTagged(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1354,7 +1821,7 @@ func TestServiceWriteTagged(t *testing.T) {
 		ident.NewIDMatcher(id),
 		gomock.Any(),
 		at, value, xtime.Second, nil,
-	).Return(nil)
+	).Return(true, nil)
 
 	request := &rpc.WriteTaggedRequest{
 		NameSpace: nsID,
@@ -1393,17 +1860,18 @@ func TestServiceWriteBatchRaw(t *testing.T) {
 	nsID := "metrics"
 
 	values := []struct {
-		id string
-		t  time.Time
-		v  float64
+		id        string
+		t         time.Time
+		v         float64
+		newSeries bool
 	}{
-		{"foo", time.Now().Truncate(time.Second), 12.34},
-		{"bar", time.Now().Truncate(time.Second), 42.42},
+		{"foo", time.Now().Truncate(time.Second), 12.34, false},
+		{"bar", time.Now().Truncate(time.Second), 42.42, true},
 	}
 	for _, w := range values {
 		mockDB.EXPECT().
 			Write(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher(w.id), w.t, w.v, xtime.Second, nil).
-			Return(nil)
+			Return(w.newSeries, nil)
 	}
 
 	var elements []*rpc.WriteBatchRawRequestElement
@@ -1419,11 +1887,12 @@ func TestServiceWriteBatchRaw(t *testing.T) {
 		elements = append(elements, elem)
 	}
 
-	err := service.WriteBatchRaw(tctx, &rpc.WriteBatchRawRequest{
+	result, err := service.WriteBatchRaw(tctx, &rpc.WriteBatchRawRequest{
 		NameSpace: []byte(nsID),
 		Elements:  elements,
 	})
 	require.NoError(t, err)
+	require.Equal(t, []int64{1}, result.NewSeriesIndexes)
 }
 
 func TestServiceWriteTaggedBatchRaw(t *testing.T) {
@@ -1456,16 +1925,17 @@ func TestServiceWriteTaggedBatchRaw(t *testing.T) {
 		tagEncode string
 		t         time.Time
 		v         float64
+		newSeries bool
 	}{
-		{"foo", "a|b", time.Now().Truncate(time.Second), 12.34},
-		{"bar", "c|dd", time.Now().Truncate(time.Second), 42.42},
+		{"foo", "a|b", time.Now().Truncate(time.Second), 12.34, true},
+		{"bar", "c|dd", time.Now().Truncate(time.Second), 42.42, false},
 	}
 	for _, w := range values {
 		mockDB.EXPECT().
 			WriteTagged(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher(w.id),
 				mockDecoder,
 				w.t, w.v, xtime.Second, nil).
-			Return(nil)
+			Return(w.newSeries, nil)
 	}
 
 	var elements []*rpc.WriteTaggedBatchRawRequestElement
@@ -1482,11 +1952,12 @@ func TestServiceWriteTaggedBatchRaw(t *testing.T) {
 		elements = append(elements, elem)
 	}
 
-	err := service.WriteTaggedBatchRaw(tctx, &rpc.WriteTaggedBatchRawRequest{
+	result, err := service.WriteTaggedBatchRaw(tctx, &rpc.WriteTaggedBatchRawRequest{
 		NameSpace: []byte(nsID),
 		Elements:  elements,
 	})
 	require.NoError(t, err)
+	require.Equal(t, []int64{0}, result.NewSeriesIndexes)
 }
 func TestServiceRepair(t *testing.T) {
 	ctrl := gomock.NewController(t)
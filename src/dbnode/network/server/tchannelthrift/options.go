@@ -21,21 +21,30 @@
 package tchannelthrift
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 )
 
 type options struct {
-	instrumentOpts           instrument.Options
-	blockMetadataPool        BlockMetadataPool
-	blockMetadataV2Pool      BlockMetadataV2Pool
-	blockMetadataSlicePool   BlockMetadataSlicePool
-	blockMetadataV2SlicePool BlockMetadataV2SlicePool
-	blocksMetadataPool       BlocksMetadataPool
-	blocksMetadataSlicePool  BlocksMetadataSlicePool
-	tagEncoderPool           serialize.TagEncoderPool
-	tagDecoderPool           serialize.TagDecoderPool
+	instrumentOpts             instrument.Options
+	blockMetadataPool          BlockMetadataPool
+	blockMetadataV2Pool        BlockMetadataV2Pool
+	blockMetadataSlicePool     BlockMetadataSlicePool
+	blockMetadataV2SlicePool   BlockMetadataV2SlicePool
+	blocksMetadataPool         BlocksMetadataPool
+	blocksMetadataSlicePool    BlocksMetadataSlicePool
+	tagEncoderPool             serialize.TagEncoderPool
+	tagDecoderPool             serialize.TagDecoderPool
+	slowQueryThreshold         time.Duration
+	fetchTaggedStreamBatchSize int
+	fetchTaggedStreamMaxBytes  int
+	segmentMergePolicy         index.SegmentMergePolicy
+	queryLimits                QueryLimits
+	concurrencyLimits          ConcurrencyLimits
 }
 
 // NewOptions creates new options
@@ -60,6 +69,7 @@ func NewOptions() Options {
 		blocksMetadataSlicePool:  NewBlocksMetadataSlicePool(nil, 0),
 		tagEncoderPool:           tagEncoderPool,
 		tagDecoderPool:           tagDecoderPool,
+		segmentMergePolicy:       index.DefaultSegmentMergePolicy,
 	}
 }
 
@@ -152,3 +162,63 @@ func (o *options) SetTagDecoderPool(value serialize.TagDecoderPool) Options {
 func (o *options) TagDecoderPool() serialize.TagDecoderPool {
 	return o.tagDecoderPool
 }
+
+func (o *options) SetSlowQueryThreshold(value time.Duration) Options {
+	opts := *o
+	opts.slowQueryThreshold = value
+	return &opts
+}
+
+func (o *options) SlowQueryThreshold() time.Duration {
+	return o.slowQueryThreshold
+}
+
+func (o *options) SetFetchTaggedStreamBatchSize(value int) Options {
+	opts := *o
+	opts.fetchTaggedStreamBatchSize = value
+	return &opts
+}
+
+func (o *options) FetchTaggedStreamBatchSize() int {
+	return o.fetchTaggedStreamBatchSize
+}
+
+func (o *options) SetFetchTaggedStreamMaxBytes(value int) Options {
+	opts := *o
+	opts.fetchTaggedStreamMaxBytes = value
+	return &opts
+}
+
+func (o *options) FetchTaggedStreamMaxBytes() int {
+	return o.fetchTaggedStreamMaxBytes
+}
+
+func (o *options) SetSegmentMergePolicy(value index.SegmentMergePolicy) Options {
+	opts := *o
+	opts.segmentMergePolicy = value
+	return &opts
+}
+
+func (o *options) SegmentMergePolicy() index.SegmentMergePolicy {
+	return o.segmentMergePolicy
+}
+
+func (o *options) SetQueryLimits(value QueryLimits) Options {
+	opts := *o
+	opts.queryLimits = value
+	return &opts
+}
+
+func (o *options) QueryLimits() QueryLimits {
+	return o.queryLimits
+}
+
+func (o *options) SetConcurrencyLimits(value ConcurrencyLimits) Options {
+	opts := *o
+	opts.concurrencyLimits = value
+	return &opts
+}
+
+func (o *options) ConcurrencyLimits() ConcurrencyLimits {
+	return o.concurrencyLimits
+}
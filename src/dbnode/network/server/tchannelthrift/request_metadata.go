@@ -0,0 +1,56 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tchannelthrift
+
+import (
+	"github.com/uber/tchannel-go/thrift"
+)
+
+const (
+	// RequestMetadataHeader is the request header carrying the opaque
+	// metadata, such as a request ID, attached by a client to a call so the
+	// node can include it in its logs and errors.
+	RequestMetadataHeader = "m3db-request-metadata"
+
+	// MaxRequestMetadataLength is the maximum length of request metadata,
+	// longer metadata is truncated.
+	MaxRequestMetadataLength = 256
+)
+
+// WithRequestMetadata returns a context that attaches the request metadata
+// to the call, the context is returned unchanged if the metadata is empty.
+func WithRequestMetadata(ctx thrift.Context, metadata string) thrift.Context {
+	if metadata == "" {
+		return ctx
+	}
+	return thrift.WithHeaders(ctx, map[string]string{
+		RequestMetadataHeader: metadata,
+	})
+}
+
+// RequestMetadata returns the request metadata attached to a call, if any.
+func RequestMetadata(ctx thrift.Context) string {
+	metadata := ctx.Headers()[RequestMetadataHeader]
+	if len(metadata) > MaxRequestMetadataLength {
+		metadata = metadata[:MaxRequestMetadataLength]
+	}
+	return metadata
+}
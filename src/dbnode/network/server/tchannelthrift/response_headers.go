@@ -0,0 +1,103 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tchannelthrift
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+
+	"github.com/uber/tchannel-go/thrift"
+)
+
+const (
+	// QueryLimitExceededHeader is the response header listing the query
+	// limits exceeded by a call, the results of the call are partial.
+	QueryLimitExceededHeader = "m3db-query-limit-exceeded"
+
+	// PartialAvailabilityHeader is the response header set on reads served
+	// from shards that are still bootstrapping, results may be incomplete.
+	PartialAvailabilityHeader = "m3db-partial-availability"
+)
+
+// SetResponseHeader sets a response header of a call, keeping the response
+// headers already set.
+func SetResponseHeader(ctx thrift.Context, key, value string) {
+	existing := ctx.ResponseHeaders()
+	headers := make(map[string]string, len(existing)+1)
+	for k, v := range existing {
+		headers[k] = v
+	}
+	headers[key] = value
+	ctx.SetResponseHeaders(headers)
+}
+
+// SetQueryLimitsExceeded sets the query limit exceeded response header of a
+// call to the limits exceeded, the header is not set if there are none.
+func SetQueryLimitsExceeded(ctx thrift.Context, exceeded []index.QueryLimitExceeded) {
+	if len(exceeded) == 0 {
+		return
+	}
+	values := make([]string, 0, len(exceeded))
+	for _, e := range exceeded {
+		values = append(values, fmt.Sprintf("%s=%d", e.Limit, e.Max))
+	}
+	SetResponseHeader(ctx, QueryLimitExceededHeader, strings.Join(values, ","))
+}
+
+// QueryLimitsExceeded returns the query limits exceeded listed by the
+// response headers of a call, if any.
+func QueryLimitsExceeded(headers map[string]string) ([]index.QueryLimitExceeded, error) {
+	value := headers[QueryLimitExceededHeader]
+	if value == "" {
+		return nil, nil
+	}
+	var exceeded []index.QueryLimitExceeded
+	for _, v := range strings.Split(value, ",") {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid query limit exceeded: %s", v)
+		}
+		max, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query limit exceeded: %s", v)
+		}
+		exceeded = append(exceeded, index.QueryLimitExceeded{
+			Limit: index.QueryLimit(parts[0]),
+			Max:   max,
+		})
+	}
+	return exceeded, nil
+}
+
+// SetPartialAvailability sets the partial availability response header of
+// a call.
+func SetPartialAvailability(ctx thrift.Context) {
+	SetResponseHeader(ctx, PartialAvailabilityHeader, "true")
+}
+
+// PartialAvailability returns whether the response headers of a call mark
+// the results as served from shards that are still bootstrapping.
+func PartialAvailability(headers map[string]string) bool {
+	return headers[PartialAvailabilityHeader] == "true"
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tchannelthrift
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+func TestQueryLimitsExceeded(t *testing.T) {
+	ctx, cancel := thrift.NewContext(time.Second)
+	defer cancel()
+
+	SetQueryLimitsExceeded(ctx, nil)
+	require.Empty(t, ctx.ResponseHeaders())
+
+	exceeded := []index.QueryLimitExceeded{
+		{Limit: index.MaxResultsQueryLimit, Max: 100},
+		{Limit: index.MaxSeriesMatchedQueryLimit, Max: 1000},
+	}
+	SetResponseHeader(ctx, "other", "value")
+	SetQueryLimitsExceeded(ctx, exceeded)
+	require.Equal(t, "value", ctx.ResponseHeaders()["other"])
+
+	result, err := QueryLimitsExceeded(ctx.ResponseHeaders())
+	require.NoError(t, err)
+	require.Equal(t, exceeded, result)
+
+	_, err = QueryLimitsExceeded(map[string]string{
+		QueryLimitExceededHeader: "max-results",
+	})
+	require.Error(t, err)
+}
+
+func TestPartialAvailability(t *testing.T) {
+	ctx, cancel := thrift.NewContext(time.Second)
+	defer cancel()
+
+	require.False(t, PartialAvailability(ctx.ResponseHeaders()))
+
+	SetQueryLimitsExceeded(ctx, []index.QueryLimitExceeded{
+		{Limit: index.MaxResultsQueryLimit, Max: 100},
+	})
+	SetPartialAvailability(ctx)
+	require.True(t, PartialAvailability(ctx.ResponseHeaders()))
+	require.Contains(t, ctx.ResponseHeaders(), QueryLimitExceededHeader)
+}
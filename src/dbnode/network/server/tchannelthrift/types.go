@@ -21,7 +21,10 @@
 package tchannelthrift
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3x/instrument"
 )
 
@@ -80,4 +83,96 @@ type Options interface {
 
 	// TagDecoderPool returns the tag encoder pool
 	TagDecoderPool() serialize.TagDecoderPool
+
+	// SetSlowQueryThreshold sets the duration after which queries are logged
+	// as slow, zero disables logging slow queries.
+	SetSlowQueryThreshold(value time.Duration) Options
+
+	// SlowQueryThreshold returns the duration after which queries are logged
+	// as slow.
+	SlowQueryThreshold() time.Duration
+
+	// SetFetchTaggedStreamBatchSize sets the number of series fetch tagged
+	// requests take from the index at a time, zero disables streaming and
+	// all matching series are taken from the index at once.
+	SetFetchTaggedStreamBatchSize(value int) Options
+
+	// FetchTaggedStreamBatchSize returns the number of series fetch tagged
+	// requests take from the index at a time.
+	FetchTaggedStreamBatchSize() int
+
+	// SetFetchTaggedStreamMaxBytes sets the size of the IDs, tags and data a
+	// streamed fetch tagged response holds before it is returned as not
+	// exhaustive, zero leaves the response unbounded.
+	SetFetchTaggedStreamMaxBytes(value int) Options
+
+	// FetchTaggedStreamMaxBytes returns the size of the IDs, tags and data a
+	// streamed fetch tagged response holds before it is returned as not
+	// exhaustive.
+	FetchTaggedStreamMaxBytes() int
+
+	// SetSegmentMergePolicy sets the policy determining which segments of
+	// each index block are searched by index queries.
+	SetSegmentMergePolicy(value index.SegmentMergePolicy) Options
+
+	// SegmentMergePolicy returns the policy determining which segments of
+	// each index block are searched by index queries.
+	SegmentMergePolicy() index.SegmentMergePolicy
+
+	// SetQueryLimits sets the limits applied to the index queries of fetch
+	// tagged and query requests, zero limits are unlimited.
+	SetQueryLimits(value QueryLimits) Options
+
+	// QueryLimits returns the limits applied to the index queries of fetch
+	// tagged and query requests.
+	QueryLimits() QueryLimits
+
+	// SetConcurrencyLimits sets the concurrency limits of each class of request.
+	SetConcurrencyLimits(value ConcurrencyLimits) Options
+
+	// ConcurrencyLimits returns the concurrency limits of each class of request.
+	ConcurrencyLimits() ConcurrencyLimits
+}
+
+// ConcurrencyLimits are the limits on the requests served concurrently for
+// each class of request, each class is queued separately and queued requests
+// are served in priority order of writes, reads then streams so that one
+// class such as peer bootstrap streams cannot starve another such as writes.
+type ConcurrencyLimits struct {
+	// MaxConcurrent is the max number of requests of all classes served
+	// concurrently, unlimited if zero.
+	MaxConcurrent int
+
+	// Write is the limit for write requests.
+	Write ConcurrencyLimit
+
+	// Read is the limit for fetch and query requests.
+	Read ConcurrencyLimit
+
+	// Stream is the limit for the block and metadata streaming requests of
+	// peer bootstrap and repair.
+	Stream ConcurrencyLimit
+}
+
+// ConcurrencyLimit is the concurrency limit of a class of request.
+type ConcurrencyLimit struct {
+	// MaxConcurrent is the max number of requests served concurrently,
+	// unlimited if zero.
+	MaxConcurrent int
+
+	// MaxQueued is the max number of requests waiting for one of the
+	// concurrent slots, requests beyond this are rejected.
+	MaxQueued int
+}
+
+// QueryLimits are the limits applied to index queries, once a limit is
+// exceeded the results so far are returned with a query limit exceeded
+// warning, zero limits are unlimited.
+type QueryLimits struct {
+	// MaxResults is the maximum number of series returned by a query.
+	MaxResults int
+
+	// MaxSeriesMatched is the maximum number of series matched across all
+	// blocks searched by a query.
+	MaxSeriesMatched int
 }
@@ -27,6 +27,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3x/context"
 	xlog "github.com/m3db/m3x/log"
 	xtime "github.com/m3db/m3x/time"
@@ -62,8 +63,9 @@ type completionFn func(err error)
 
 type commitLog struct {
 	sync.RWMutex
-	opts  Options
-	nowFn clock.NowFn
+	opts    Options
+	nowFn   clock.NowFn
+	sleepFn clock.SleepFn
 
 	log xlog.Logger
 
@@ -95,6 +97,8 @@ type commitLogMetrics struct {
 	closeErrors tally.Counter
 	flushErrors tally.Counter
 	flushDone   tally.Counter
+
+	sizeRotations tally.Counter
 }
 
 type valueType int
@@ -127,6 +131,7 @@ func NewCommitLog(opts Options) (CommitLog, error) {
 	commitLog := &commitLog{
 		opts:                 opts,
 		nowFn:                opts.ClockOptions().NowFn(),
+		sleepFn:              opts.ClockOptions().SleepFn(),
 		log:                  iopts.Logger(),
 		newCommitLogWriterFn: newCommitLogWriter,
 		writes:               make(chan commitLogWrite, opts.BacklogQueueSize()),
@@ -139,6 +144,8 @@ func NewCommitLog(opts Options) (CommitLog, error) {
 			closeErrors: scope.Counter("writes.close-errors"),
 			flushErrors: scope.Counter("writes.flush-errors"),
 			flushDone:   scope.Counter("writes.flush-done"),
+
+			sizeRotations: scope.Counter("writes.size-rotations"),
 		},
 	}
 
@@ -197,7 +204,7 @@ func (l *commitLog) flushEvery(interval time.Duration) {
 			sleepForOverride = 0
 		}
 
-		time.Sleep(sleepFor)
+		l.sleepFn(sleepFor)
 
 		l.flushMutex.RLock()
 		lastFlushAt := l.lastFlushAt
@@ -222,6 +229,12 @@ func (l *commitLog) flushEvery(interval time.Duration) {
 }
 
 func (l *commitLog) write() {
+	if set := l.opts.WriteCPUSet(); len(set) > 0 {
+		if err := cpuset.LockAndPin(set); err != nil {
+			l.log.Warnf("could not pin commit log writer to cpus %s: %v", set, err)
+		}
+	}
+
 	for write := range l.writes {
 		// For writes requiring acks add to pending acks
 		if write.completionFn != nil {
@@ -233,17 +246,10 @@ func (l *commitLog) write() {
 			continue
 		}
 
-		if now := l.nowFn(); !now.Before(l.writerExpireAt) {
+		now := l.nowFn()
+		if !now.Before(l.writerExpireAt) {
 			if err := l.openWriter(now); err != nil {
-
-				l.metrics.errors.Inc(1)
-				l.metrics.openErrors.Inc(1)
-				l.log.Errorf("failed to open commit log: %v", err)
-
-				if l.commitLogFailFn != nil {
-					l.commitLogFailFn(err)
-				}
-
+				l.onOpenError(err)
 				continue
 			}
 		}
@@ -262,6 +268,16 @@ func (l *commitLog) write() {
 			continue
 		}
 		l.metrics.success.Inc(1)
+
+		// Rotate to a new commit log file within the same block once the
+		// active file grows past the max segment size
+		maxSegmentSize := l.opts.MaxSegmentSize()
+		if maxSegmentSize > 0 && l.writer.Size() >= maxSegmentSize {
+			l.metrics.sizeRotations.Inc(1)
+			if err := l.openWriter(now); err != nil {
+				l.onOpenError(err)
+			}
+		}
 	}
 
 	l.Lock()
@@ -272,6 +288,16 @@ func (l *commitLog) write() {
 	l.closeErr <- writer.Close()
 }
 
+func (l *commitLog) onOpenError(err error) {
+	l.metrics.errors.Inc(1)
+	l.metrics.openErrors.Inc(1)
+	l.log.Errorf("failed to open commit log: %v", err)
+
+	if l.commitLogFailFn != nil {
+		l.commitLogFailFn(err)
+	}
+}
+
 func (l *commitLog) onFlush(err error) {
 	l.flushMutex.Lock()
 	l.lastFlushAt = l.nowFn()
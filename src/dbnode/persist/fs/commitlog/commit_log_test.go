@@ -157,6 +157,7 @@ type mockCommitLogWriter struct {
 	writeFn func(Series, ts.Datapoint, xtime.Unit, ts.Annotation) error
 	flushFn func() error
 	closeFn func() error
+	sizeFn  func() int64
 }
 
 func newMockCommitLogWriter() *mockCommitLogWriter {
@@ -173,6 +174,9 @@ func newMockCommitLogWriter() *mockCommitLogWriter {
 		closeFn: func() error {
 			return nil
 		},
+		sizeFn: func() int64 {
+			return 0
+		},
 	}
 }
 
@@ -193,6 +197,10 @@ func (w *mockCommitLogWriter) Flush() error {
 	return w.flushFn()
 }
 
+func (w *mockCommitLogWriter) Size() int64 {
+	return w.sizeFn()
+}
+
 func (w *mockCommitLogWriter) Close() error {
 	return w.closeFn()
 }
@@ -435,6 +443,44 @@ func TestReadCommitLogMissingMetadata(t *testing.T) {
 	require.NoError(t, commitLog.Close())
 }
 
+func TestCommitLogRotatesByMaxSegmentSize(t *testing.T) {
+	// Make sure we're not leaking goroutines
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteWait,
+	})
+	opts = opts.SetMaxSegmentSize(1)
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+
+	writes := []testWrite{
+		{testSeries(0, "foo.bar", testTags1, 127), time.Now(), 123.456, xtime.Second, nil, nil},
+		{testSeries(1, "foo.baz", testTags2, 150), time.Now(), 456.789, xtime.Second, nil, nil},
+		{testSeries(0, "foo.bar", testTags1, 127), time.Now(), 789.123, xtime.Second, nil, nil},
+	}
+
+	// Call write sync
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+
+	// Close the commit log and consequently flush
+	require.NoError(t, commitLog.Close())
+
+	// Each write rotates to a new file
+	rotations, ok := snapshotCounterValue(scope, "commitlog.writes.size-rotations")
+	require.True(t, ok)
+	require.Equal(t, int64(len(writes)), rotations.Value())
+
+	fsopts := opts.FilesystemOptions()
+	files, err := fs.SortedCommitLogFiles(fs.CommitLogsDirPath(fsopts.FilePathPrefix()))
+	require.NoError(t, err)
+	require.Equal(t, len(writes)+1, len(files))
+
+	// Assert writes are readable across the rotated files
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
 func TestCommitLogReaderIsNotReusable(t *testing.T) {
 	// Make sure we're not leaking goroutines
 	defer leaktest.CheckTimeout(t, time.Second)()
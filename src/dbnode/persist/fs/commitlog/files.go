@@ -36,6 +36,7 @@ type File struct {
 	Start    time.Time
 	Duration time.Duration
 	Index    int64
+	Size     int64
 }
 
 // ReadLogInfo reads the commit log info out of a commitlog file
@@ -95,15 +96,24 @@ func Files(opts Options) ([]File, error) {
 			return nil, err
 		}
 
+		stat, err := os.Stat(filePath)
+		if err != nil {
+			return nil, err
+		}
+
 		commitLogFiles = append(commitLogFiles, File{
 			FilePath: filePath,
 			Start:    start,
 			Duration: duration,
 			Index:    index,
+			Size:     stat.Size(),
 		})
 	}
 
 	sort.Slice(commitLogFiles, func(i, j int) bool {
+		if commitLogFiles[i].Start.Equal(commitLogFiles[j].Start) {
+			return commitLogFiles[i].Index < commitLogFiles[j].Index
+		}
 		return commitLogFiles[i].Start.Before(commitLogFiles[j].Start)
 	})
 
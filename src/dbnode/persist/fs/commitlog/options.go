@@ -27,6 +27,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
@@ -63,6 +64,8 @@ var (
 	errRetentionPeriodPositive        = errors.New("retention period must be a positive duration")
 	errRetentionGreaterEqualBlockSize = errors.New("retention period must be >= block size")
 	errReadConcurrencyPositive        = errors.New("read concurrency must be a positive integer")
+	errMaxSegmentSizeNonNegative      = errors.New("max segment size must be non-negative")
+	errMaxRetainedBytesNonNegative    = errors.New("max retained bytes must be non-negative")
 )
 
 type options struct {
@@ -70,6 +73,8 @@ type options struct {
 	instrumentOpts   instrument.Options
 	retentionPeriod  time.Duration
 	blockSize        time.Duration
+	maxSegmentSize   int64
+	maxRetainedBytes int64
 	fsOpts           fs.Options
 	strategy         Strategy
 	flushSize        int
@@ -78,6 +83,7 @@ type options struct {
 	bytesPool        pool.CheckedBytesPool
 	identPool        ident.Pool
 	readConcurrency  int
+	writeCPUSet      cpuset.CPUSet
 }
 
 // NewOptions creates new commit log options
@@ -118,6 +124,12 @@ func (o *options) Validate() error {
 	if o.ReadConcurrency() <= 0 {
 		return errReadConcurrencyPositive
 	}
+	if o.MaxSegmentSize() < 0 {
+		return errMaxSegmentSizeNonNegative
+	}
+	if o.MaxRetainedBytes() < 0 {
+		return errMaxRetainedBytesNonNegative
+	}
 	return nil
 }
 
@@ -161,6 +173,26 @@ func (o *options) BlockSize() time.Duration {
 	return o.blockSize
 }
 
+func (o *options) SetMaxSegmentSize(value int64) Options {
+	opts := *o
+	opts.maxSegmentSize = value
+	return &opts
+}
+
+func (o *options) MaxSegmentSize() int64 {
+	return o.maxSegmentSize
+}
+
+func (o *options) SetMaxRetainedBytes(value int64) Options {
+	opts := *o
+	opts.maxRetainedBytes = value
+	return &opts
+}
+
+func (o *options) MaxRetainedBytes() int64 {
+	return o.maxRetainedBytes
+}
+
 func (o *options) SetFilesystemOptions(value fs.Options) Options {
 	opts := *o
 	opts.fsOpts = value
@@ -240,3 +272,13 @@ func (o *options) SetIdentifierPool(value ident.Pool) Options {
 func (o *options) IdentifierPool() ident.Pool {
 	return o.identPool
 }
+
+func (o *options) SetWriteCPUSet(value cpuset.CPUSet) Options {
+	opts := *o
+	opts.writeCPUSet = value
+	return &opts
+}
+
+func (o *options) WriteCPUSet() cpuset.CPUSet {
+	return o.writeCPUSet
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -135,6 +136,22 @@ type Options interface {
 	// BlockSize returns the block size
 	BlockSize() time.Duration
 
+	// SetMaxSegmentSize sets the size in bytes after which the active commit
+	// log file is rotated, zero disables size based rotation
+	SetMaxSegmentSize(value int64) Options
+
+	// MaxSegmentSize returns the size in bytes after which the active commit
+	// log file is rotated, zero disables size based rotation
+	MaxSegmentSize() int64
+
+	// SetMaxRetainedBytes sets the total size in bytes of commit log files to
+	// retain before the oldest are forcibly cleaned up, zero disables the bound
+	SetMaxRetainedBytes(value int64) Options
+
+	// MaxRetainedBytes returns the total size in bytes of commit log files to
+	// retain before the oldest are forcibly cleaned up, zero disables the bound
+	MaxRetainedBytes() int64
+
 	// SetFilesystemOptions sets the filesystem options
 	SetFilesystemOptions(value fs.Options) Options
 
@@ -182,6 +199,13 @@ type Options interface {
 
 	// IdentifierPool returns the IdentifierPool to use for pooling identifiers.
 	IdentifierPool() ident.Pool
+
+	// SetWriteCPUSet sets the CPU set the writer is pinned to, the writer is
+	// not pinned if empty.
+	SetWriteCPUSet(value cpuset.CPUSet) Options
+
+	// WriteCPUSet returns the CPU set the writer is pinned to.
+	WriteCPUSet() cpuset.CPUSet
 }
 
 // FileFilterPredicate is a predicate that allows the caller to determine
@@ -35,6 +35,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 )
@@ -76,6 +78,10 @@ type commitLogWriter interface {
 	// Flush will flush the contents to the disk, useful when first testing if first commit log is writable
 	Flush() error
 
+	// Size returns the number of bytes written to the open commit log,
+	// including bytes buffered but not yet flushed
+	Size() int64
+
 	// Close the reader
 	Close() error
 }
@@ -89,6 +95,7 @@ type writer struct {
 	nowFn              clock.NowFn
 	start              time.Time
 	duration           time.Duration
+	size               int64
 	chunkWriter        *chunkWriter
 	chunkReserveHeader []byte
 	buffer             *bufio.Writer
@@ -98,20 +105,23 @@ type writer struct {
 	metadataEncoder    *msgpack.Encoder
 	tagEncoder         serialize.TagEncoder
 	tagSliceIter       ident.TagsIterator
+	faultInjector      fault.Injector
 }
 
 func newCommitLogWriter(
 	flushFn flushFn,
 	opts Options,
 ) commitLogWriter {
-	shouldFsync := opts.Strategy() == StrategyWriteWait
-
+	var (
+		shouldFsync      = opts.Strategy() == StrategyWriteWait
+		writeAmpRecorder = opts.FilesystemOptions().WriteAmplificationRecorder()
+	)
 	return &writer{
 		filePathPrefix:     opts.FilesystemOptions().FilePathPrefix(),
 		newFileMode:        opts.FilesystemOptions().NewFileMode(),
 		newDirectoryMode:   opts.FilesystemOptions().NewDirectoryMode(),
 		nowFn:              opts.ClockOptions().NowFn(),
-		chunkWriter:        newChunkWriter(flushFn, shouldFsync),
+		chunkWriter:        newChunkWriter(flushFn, shouldFsync, writeAmpRecorder),
 		chunkReserveHeader: make([]byte, chunkHeaderLen),
 		buffer:             bufio.NewWriterSize(nil, opts.FlushSize()),
 		sizeBuffer:         make([]byte, binary.MaxVarintLen64),
@@ -120,6 +130,7 @@ func newCommitLogWriter(
 		metadataEncoder:    msgpack.NewEncoder(),
 		tagEncoder:         opts.FilesystemOptions().TagEncoderPool().Get(),
 		tagSliceIter:       ident.NewTagsIterator(ident.Tags{}),
+		faultInjector:      opts.FilesystemOptions().FaultInjector(),
 	}
 }
 
@@ -153,6 +164,7 @@ func (w *writer) Open(start time.Time, duration time.Duration) error {
 
 	w.chunkWriter.fd = fd
 	w.buffer.Reset(w.chunkWriter)
+	w.size = 0
 	if err := w.write(w.logEncoder.Bytes()); err != nil {
 		w.Close()
 		return err
@@ -222,9 +234,22 @@ func (w *writer) Write(
 	if err := w.logEncoder.EncodeLogEntry(logEntry); err != nil {
 		return err
 	}
-	if err := w.write(w.logEncoder.Bytes()); err != nil {
+
+	data := w.logEncoder.Bytes()
+	res := w.faultInjector.Inject(fault.CommitLogWrite)
+	if res.Err != nil {
+		if !res.Partial {
+			return res.Err
+		}
+		// NB: only write out half the entry to simulate a torn write.
+		data = data[:len(data)/2]
+	}
+	if err := w.write(data); err != nil {
 		return err
 	}
+	if res.Err != nil {
+		return res.Err
+	}
 
 	if !seen {
 		// Record we have written this series and metadata to this commit log
@@ -237,6 +262,10 @@ func (w *writer) Flush() error {
 	return w.buffer.Flush()
 }
 
+func (w *writer) Size() int64 {
+	return w.size
+}
+
 func (w *writer) Close() error {
 	if !w.isOpen() {
 		return nil
@@ -252,6 +281,7 @@ func (w *writer) Close() error {
 	w.chunkWriter.fd = nil
 	w.start = timeZero
 	w.duration = 0
+	w.size = 0
 	w.seen.ClearAll()
 	return nil
 }
@@ -273,22 +303,31 @@ func (w *writer) write(data []byte) error {
 	if _, err := w.buffer.Write(w.sizeBuffer[:sizeLen]); err != nil {
 		return err
 	}
-	_, err := w.buffer.Write(data)
-	return err
+	if _, err := w.buffer.Write(data); err != nil {
+		return err
+	}
+	w.size += int64(totalLen)
+	return nil
 }
 
 type chunkWriter struct {
-	fd      *os.File
-	flushFn flushFn
-	buff    []byte
-	fsync   bool
+	fd               *os.File
+	flushFn          flushFn
+	buff             []byte
+	fsync            bool
+	writeAmpRecorder writeamp.Recorder
 }
 
-func newChunkWriter(flushFn flushFn, fsync bool) *chunkWriter {
+func newChunkWriter(
+	flushFn flushFn,
+	fsync bool,
+	writeAmpRecorder writeamp.Recorder,
+) *chunkWriter {
 	return &chunkWriter{
-		flushFn: flushFn,
-		buff:    make([]byte, chunkHeaderLen),
-		fsync:   fsync,
+		flushFn:          flushFn,
+		buff:             make([]byte, chunkHeaderLen),
+		fsync:            fsync,
+		writeAmpRecorder: writeAmpRecorder,
 	}
 }
 
@@ -322,6 +361,7 @@ func (w *chunkWriter) Write(p []byte) (int, error) {
 
 	// Write contents to file descriptor
 	n, err := w.fd.Write(w.buff)
+	w.writeAmpRecorder.RecordWritten(writeamp.CommitLog, int64(n))
 	if err != nil {
 		w.flushFn(err)
 		return n, err
@@ -49,6 +49,7 @@ const (
 	indexDirName      = "index"
 	snapshotDirName   = "snapshots"
 	commitLogsDirName = "commitlogs"
+	quarantineDirName = "quarantine"
 
 	commitLogComponentPosition    = 2
 	indexFileSetComponentPosition = 2
@@ -1026,6 +1027,12 @@ func ShardSnapshotsDirPath(prefix string, namespace ident.ID, shard uint32) stri
 	return path.Join(namespacePath, strconv.Itoa(int(shard)))
 }
 
+// QuarantineDirPath returns the path to the directory files are moved to
+// when they are set aside rather than deleted.
+func QuarantineDirPath(prefix string) string {
+	return path.Join(prefix, quarantineDirName)
+}
+
 // CommitLogsDirPath returns the path to commit logs.
 func CommitLogsDirPath(prefix string) string {
 	return path.Join(prefix, commitLogsDirName)
@@ -1145,6 +1152,18 @@ func OpenWritable(filePath string, perm os.FileMode) (*os.File, error) {
 	return os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
 }
 
+// filesSize returns the total size of the files at the given paths, files
+// that cannot be stat'd are skipped.
+func filesSize(filePaths []string) int64 {
+	var size int64
+	for _, filePath := range filePaths {
+		if info, err := os.Stat(filePath); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
 func filesetFileForTime(t time.Time, suffix string) string {
 	return fmt.Sprintf("%s%s%d%s%s%s", filesetFilePrefix, separator, t.UnixNano(), separator, suffix, fileSuffix)
 }
@@ -24,13 +24,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	xmsgpack "github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/x/mmap"
 	"github.com/m3db/m3x/ident"
-
-	"gopkg.in/vmihailenco/msgpack.v2"
 )
 
 var errCloneShouldNotBeCloned = errors.New("clones should not be cloned")
@@ -39,24 +38,23 @@ var errCloneShouldNotBeCloned = errors.New("clones should not be cloned")
 // ID in the index file. It is not safe for concurrent use
 type nearestIndexOffsetLookup struct {
 	summaryIDsOffsets []xmsgpack.IndexSummaryToken
+	// index file offsets of the summaries decoded up front so that lookups
+	// do not need to decode the summaries
+	indexOffsets []int64
 	// bytes from file mmap'd into anonymous region
 	summariesMmap []byte
-	// reusable decoder stream
-	decoderStream  xmsgpack.DecoderStream
-	msgpackDecoder *msgpack.Decoder
-	isClone        bool
+	isClone       bool
 }
 
 func newNearestIndexOffsetLookup(
 	summaryIDsOffsets []xmsgpack.IndexSummaryToken,
+	indexOffsets []int64,
 	summariesMmap []byte,
-	decoderStream xmsgpack.DecoderStream,
 ) *nearestIndexOffsetLookup {
 	return &nearestIndexOffsetLookup{
 		summaryIDsOffsets: summaryIDsOffsets,
+		indexOffsets:      indexOffsets,
 		summariesMmap:     summariesMmap,
-		decoderStream:     decoderStream,
-		msgpackDecoder:    msgpack.NewDecoder(nil),
 		isClone:           false,
 	}
 }
@@ -68,81 +66,39 @@ func (il *nearestIndexOffsetLookup) concurrentClone() (*nearestIndexOffsetLookup
 
 	return &nearestIndexOffsetLookup{
 		summaryIDsOffsets: il.summaryIDsOffsets,
+		indexOffsets:      il.indexOffsets,
 		summariesMmap:     il.summariesMmap,
-		decoderStream:     xmsgpack.NewDecoderStream(nil),
-		msgpackDecoder:    msgpack.NewDecoder(nil),
 		isClone:           true,
 	}, nil
 }
 
-// getNearestIndexFileOffset returns either:
-//     1. The offset in the index file for the specified series
-//     2. The offset in the index file for the the series in the summaries file
-//        that satisfies the following two constraints:
-//            1. Is closest to the desired series in the index file
-//            2. Is BEFORE the desired series in the index file (because we
-//               we scan the index file sequentially in a forward-moving manner)
-// In other words, the returned offset can always be used as a starting point to
-// begin scanning the index file for the desired series.
-func (il *nearestIndexOffsetLookup) getNearestIndexFileOffset(id ident.ID) (int64, error) {
+// getNearestIndexFileRange returns the range of the index file that contains
+// the specified series if it exists. The range starts at the offset of the
+// nearest series in the summaries file that is at or BEFORE the desired series
+// and ends at the offset of the following series in the summaries file, or is
+// -1 if the range extends to the end of the index file. Since the index is
+// sorted by ID the desired series does not exist if it is not in the range.
+func (il *nearestIndexOffsetLookup) getNearestIndexFileRange(id ident.ID) (int64, int64) {
 	idBytes := id.Bytes()
 
-	min := 0
-	max := len(il.summaryIDsOffsets) - 1
-
 	// The summaries file only contains a fraction of the series that are in
-	// the index file itself. Because of that, the binary search that we're
-	// performing is "optimistic". We're trying to find either an exact match,
-	// OR the nearest match that is to the left of the series we're searching
-	// for (so we keep track of it everytime we move right). We start with an
-	// assumption that the best match so far is at index 0, because in the worst
-	// case scenario if we don't find a single "match", then the caller should
-	// start at index 0 and scan until they encounter an entry that tells them
-	// that the ID they're looking for does not exist (because the IDs in the
-	// index are sorted).
-	bestMatchSoFar := int64(0)
-
-	for {
-		if min > max {
-			return bestMatchSoFar, nil
-		}
-
-		idx := (max + min) / 2
-		summaryBytesMetadata := il.summaryIDsOffsets[idx]
-		compBytes := summaryBytesMetadata.ID(il.summariesMmap)
-		comparison := bytes.Compare(idBytes, compBytes)
-
-		// Found it
-		if comparison == 0 {
-			indexOffset, err := summaryBytesMetadata.IndexOffset(
-				il.summariesMmap, il.decoderStream, il.msgpackDecoder)
-			// Should never happen, either something is really wrong with the code or
-			// the file on disk was corrupted
-			if err != nil {
-				return -1, err
-			}
-			return indexOffset, nil
-		}
-
-		// idBytes is smaller than compBytes, go left
-		if comparison == -1 {
-			max = idx - 1
-			continue
-		}
-
-		// idBytes is larger than compBytes, go right
-		if comparison == 1 {
-			min = idx + 1
-			indexOffset, err := summaryBytesMetadata.IndexOffset(
-				il.summariesMmap, il.decoderStream, il.msgpackDecoder)
-			if err != nil {
-				return -1, err
-			}
-			// update the bestMatchSoFar everytime we move right
-			bestMatchSoFar = indexOffset
-			continue
-		}
+	// the index file itself, so search for the first summary that is after the
+	// series we're looking for, the summary before it is the nearest match.
+	// If there is no match the caller should start at offset 0 and scan until
+	// they encounter an entry that tells them the ID does not exist.
+	next := sort.Search(len(il.summaryIDsOffsets), func(i int) bool {
+		compBytes := il.summaryIDsOffsets[i].ID(il.summariesMmap)
+		return bytes.Compare(idBytes, compBytes) < 0
+	})
+
+	start, end := int64(0), int64(-1)
+	if next > 0 {
+		start = il.indexOffsets[next-1]
+	}
+	if next < len(il.indexOffsets) {
+		end = il.indexOffsets[next]
 	}
+	return start, end
 }
 
 func (il *nearestIndexOffsetLookup) close() error {
@@ -155,8 +111,9 @@ func (il *nearestIndexOffsetLookup) close() error {
 
 // newNearestIndexOffsetLookupFromSummariesFile creates an nearestIndexOffsetLookup
 // from an index summaries file by reading the summaries file into an anonymous
-// mmap'd region, and also creating the slice of summaries offsets which is
-// required to binary search the data structure. It will also make sure that
+// mmap'd region, and also creating the slices of summaries offsets and index
+// offsets which are required to binary search the data structure without
+// decoding the summaries on each lookup. It will also make sure that
 // the summaries file is sorted (which it always should be).
 func newNearestIndexOffsetLookupFromSummariesFile(
 	summariesFdWithDigest digest.FdWithDigestReader,
@@ -194,12 +151,12 @@ func newNearestIndexOffsetLookupFromSummariesFile(
 	var (
 		decoderStream = xmsgpack.NewDecoderStream(summariesMmap)
 		summaryTokens = make([]xmsgpack.IndexSummaryToken, 0, numEntries)
+		indexOffsets  = make([]int64, 0, numEntries)
 		lastReadID    []byte
 	)
 	decoder.Reset(decoderStream)
 
 	for read := 0; read < numEntries; read++ {
-		// We ignore the entry itself because we don't need any information from it
 		entry, summaryToken, err := decoder.DecodeIndexSummary()
 		if err != nil {
 			mmap.Munmap(summariesMmap)
@@ -213,8 +170,9 @@ func newNearestIndexOffsetLookupFromSummariesFile(
 			return nil, fmt.Errorf("summaries file is not sorted: %s", summariesFd.Name())
 		}
 		summaryTokens = append(summaryTokens, summaryToken)
+		indexOffsets = append(indexOffsets, entry.IndexEntryOffset)
 		lastReadID = entry.ID
 	}
 
-	return newNearestIndexOffsetLookup(summaryTokens, summariesMmap, decoderStream), nil
+	return newNearestIndexOffsetLookup(summaryTokens, indexOffsets, summariesMmap), nil
 }
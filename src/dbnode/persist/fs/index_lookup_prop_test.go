@@ -125,10 +125,7 @@ func TestIndexLookupWriteRead(t *testing.T) {
 
 		// Make sure it returns the correct index offset for every ID
 		for id, expectedOffset := range expectedIndexFileOffsets {
-			foundOffset, err := indexLookup.getNearestIndexFileOffset(ident.StringID(id))
-			if err != nil {
-				return false, fmt.Errorf("err locating index file offset for: %s, err: %v", id, err)
-			}
+			foundOffset, _ := indexLookup.getNearestIndexFileRange(ident.StringID(id))
 			if expectedOffset != foundOffset {
 				return false, fmt.Errorf(
 					"offsets for: %s do not match, expected: %d, got: %d",
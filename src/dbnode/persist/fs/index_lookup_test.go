@@ -116,14 +116,55 @@ func TestClosingCloneDoesNotAffectParent(t *testing.T) {
 	for _, summary := range indexSummaries {
 		id := ident.StringID(string(summary.ID))
 		require.NoError(t, err)
-		offset, err := clone.getNearestIndexFileOffset(id)
-		require.NoError(t, err)
+		offset, _ := clone.getNearestIndexFileRange(id)
 		require.Equal(t, summary.IndexEntryOffset, offset)
 		id.Finalize()
 	}
 	require.NoError(t, indexLookup.close())
 }
 
+func TestNearestIndexFileRange(t *testing.T) {
+	indexSummaries := []schema.IndexSummary{
+		schema.IndexSummary{
+			Index:            0,
+			ID:               []byte("b"),
+			IndexEntryOffset: 0,
+		},
+		schema.IndexSummary{
+			Index:            10,
+			ID:               []byte("d"),
+			IndexEntryOffset: 100,
+		},
+		schema.IndexSummary{
+			Index:            20,
+			ID:               []byte("f"),
+			IndexEntryOffset: 200,
+		},
+	}
+
+	indexLookup := newIndexLookupWithSummaries(t, indexSummaries)
+	defer indexLookup.close()
+
+	tests := []struct {
+		id    string
+		start int64
+		end   int64
+	}{
+		{id: "a", start: 0, end: 0},
+		{id: "b", start: 0, end: 100},
+		{id: "c", start: 0, end: 100},
+		{id: "d", start: 100, end: 200},
+		{id: "e", start: 100, end: 200},
+		{id: "f", start: 200, end: -1},
+		{id: "g", start: 200, end: -1},
+	}
+	for _, test := range tests {
+		start, end := indexLookup.getNearestIndexFileRange(ident.StringID(test.id))
+		require.Equal(t, test.start, start, test.id)
+		require.Equal(t, test.end, end, test.id)
+	}
+}
+
 func TestParentAndClonesSafeForConcurrentUse(t *testing.T) {
 	numSummaries := 1000
 	numClones := 10
@@ -161,8 +202,7 @@ func TestParentAndClonesSafeForConcurrentUse(t *testing.T) {
 		startWg.Wait()
 		for _, summary := range indexSummaries {
 			id := ident.StringID(string(summary.ID))
-			offset, err := clone.getNearestIndexFileOffset(id)
-			require.NoError(t, err)
+			offset, _ := clone.getNearestIndexFileRange(id)
 			require.Equal(t, summary.IndexEntryOffset, offset)
 			id.Finalize()
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/generated/proto/index"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
 	xerrors "github.com/m3db/m3x/errors"
 )
@@ -52,6 +53,7 @@ type indexWriter struct {
 	newFileMode      os.FileMode
 	newDirectoryMode os.FileMode
 	fdWithDigest     digest.FdWithDigestWriter
+	writeAmpRecorder writeamp.Recorder
 
 	err          error
 	blockSize    time.Duration
@@ -61,6 +63,7 @@ type indexWriter struct {
 	volumeIndex  int
 	shards       map[uint32]struct{}
 	segments     []writtenIndexSegment
+	filePaths    []string
 
 	namespaceDir       string
 	checkpointFilePath string
@@ -93,6 +96,7 @@ func NewIndexWriter(opts Options) (IndexFileSetWriter, error) {
 		newFileMode:      opts.NewFileMode(),
 		newDirectoryMode: opts.NewDirectoryMode(),
 		fdWithDigest:     digest.NewFdWithDigestWriter(bufferSize),
+		writeAmpRecorder: opts.WriteAmplificationRecorder(),
 	}, nil
 }
 
@@ -113,6 +117,7 @@ func (w *indexWriter) Open(opts IndexWriterOpenOptions) error {
 	w.shards = opts.Shards
 	w.snapshotTime = opts.Snapshot.SnapshotTime
 	w.segments = nil
+	w.filePaths = w.filePaths[:0]
 
 	switch opts.FileSetType {
 	case persist.FileSetSnapshotType:
@@ -200,6 +205,7 @@ func (w *indexWriter) WriteSegmentFileSet(
 			return w.markSegmentWriteError(segType, segFileType, err)
 		}
 
+		w.filePaths = append(w.filePaths, filePath)
 		seg.files = append(seg.files, writtenIndexSegmentFile{
 			segmentFileType: segFileType,
 			digest:          digest.Sum32(),
@@ -300,5 +306,13 @@ func (w *indexWriter) Close() error {
 	// Write checkpoint file
 	digestBuffer := digest.NewBuffer()
 	digestBuffer.WriteDigest(digest.Checksum(digestsFileData))
-	return ioutil.WriteFile(w.checkpointFilePath, digestBuffer, w.newFileMode)
+	err = ioutil.WriteFile(w.checkpointFilePath, digestBuffer, w.newFileMode)
+	if err != nil {
+		return err
+	}
+
+	written := filesSize(w.filePaths) + int64(len(infoFileData)) +
+		int64(len(digestsFileData)) + int64(len(digestBuffer))
+	w.writeAmpRecorder.RecordWritten(writeamp.Index, written)
+	return nil
 }
@@ -24,11 +24,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/x/directio"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
@@ -67,19 +71,26 @@ var (
 
 	errTagEncoderPoolNotSet = errors.New("tag encoder pool is not set")
 	errTagDecoderPoolNotSet = errors.New("tag decoder pool is not set")
+
+	errWarmTierAfterNotPositive = errors.New("warm tier after must be positive when warm file path prefix is set")
 )
 
 type options struct {
 	clockOpts                            clock.Options
 	instrumentOpts                       instrument.Options
 	runtimeOptsMgr                       runtime.OptionsManager
+	faultInjector                        fault.Injector
+	writeAmpRecorder                     writeamp.Recorder
 	decodingOpts                         msgpack.DecodingOptions
 	filePathPrefix                       string
+	warmFilePathPrefix                   string
+	warmTierAfter                        time.Duration
 	newFileMode                          os.FileMode
 	newDirectoryMode                     os.FileMode
 	indexSummariesPercent                float64
 	indexBloomFilterFalsePositivePercent float64
 	writerBufferSize                     int
+	directIOWritesEnabled                bool
 	dataReaderBufferSize                 int
 	infoReaderBufferSize                 int
 	seekReaderBufferSize                 int
@@ -104,6 +115,8 @@ func NewOptions() Options {
 		clockOpts:                            clock.NewOptions(),
 		instrumentOpts:                       instrument.NewOptions(),
 		runtimeOptsMgr:                       runtime.NewOptionsManager(),
+		faultInjector:                        fault.NewNoopInjector(),
+		writeAmpRecorder:                     writeamp.NewNoopRecorder(),
 		decodingOpts:                         msgpack.NewDecodingOptions(),
 		filePathPrefix:                       defaultFilePathPrefix,
 		newFileMode:                          defaultNewFileMode,
@@ -139,6 +152,12 @@ func (o *options) Validate() error {
 	if o.tagDecoderPool == nil {
 		return errTagDecoderPoolNotSet
 	}
+	if o.warmFilePathPrefix != "" && o.warmTierAfter <= 0 {
+		return errWarmTierAfterNotPositive
+	}
+	if o.directIOWritesEnabled && !directio.Supported {
+		return directio.ErrNotSupported
+	}
 	return nil
 }
 
@@ -172,6 +191,26 @@ func (o *options) RuntimeOptionsManager() runtime.OptionsManager {
 	return o.runtimeOptsMgr
 }
 
+func (o *options) SetFaultInjector(value fault.Injector) Options {
+	opts := *o
+	opts.faultInjector = value
+	return &opts
+}
+
+func (o *options) FaultInjector() fault.Injector {
+	return o.faultInjector
+}
+
+func (o *options) SetWriteAmplificationRecorder(value writeamp.Recorder) Options {
+	opts := *o
+	opts.writeAmpRecorder = value
+	return &opts
+}
+
+func (o *options) WriteAmplificationRecorder() writeamp.Recorder {
+	return o.writeAmpRecorder
+}
+
 func (o *options) SetDecodingOptions(value msgpack.DecodingOptions) Options {
 	opts := *o
 	opts.decodingOpts = value
@@ -192,6 +231,26 @@ func (o *options) FilePathPrefix() string {
 	return o.filePathPrefix
 }
 
+func (o *options) SetWarmFilePathPrefix(value string) Options {
+	opts := *o
+	opts.warmFilePathPrefix = value
+	return &opts
+}
+
+func (o *options) WarmFilePathPrefix() string {
+	return o.warmFilePathPrefix
+}
+
+func (o *options) SetWarmTierAfter(value time.Duration) Options {
+	opts := *o
+	opts.warmTierAfter = value
+	return &opts
+}
+
+func (o *options) WarmTierAfter() time.Duration {
+	return o.warmTierAfter
+}
+
 func (o *options) SetNewFileMode(value os.FileMode) Options {
 	opts := *o
 	opts.newFileMode = value
@@ -242,6 +301,16 @@ func (o *options) WriterBufferSize() int {
 	return o.writerBufferSize
 }
 
+func (o *options) SetDirectIOWritesEnabled(value bool) Options {
+	opts := *o
+	opts.directIOWritesEnabled = value
+	return &opts
+}
+
+func (o *options) DirectIOWritesEnabled() bool {
+	return o.directIOWritesEnabled
+}
+
 func (o *options) SetDataReaderBufferSize(value int) Options {
 	opts := *o
 	opts.dataReaderBufferSize = value
@@ -79,12 +79,16 @@ type persistManager struct {
 
 	status            persistManagerStatus
 	currRateLimitOpts ratelimit.Options
+	adaptiveLimiter   *ratelimit.AdaptiveLimiter
+	rateLimitScale    float64
 
-	start        time.Time
-	count        int
-	bytesWritten int64
-	worked       time.Duration
-	slept        time.Duration
+	start          time.Time
+	count          int
+	bytesWritten   int64
+	bytesThrottled int64
+	throttleTarget time.Duration
+	worked         time.Duration
+	slept          time.Duration
 
 	metrics persistManagerMetrics
 }
@@ -125,12 +129,14 @@ type newPersistentSegmentFn func(
 type persistManagerMetrics struct {
 	writeDurationMs    tally.Gauge
 	throttleDurationMs tally.Gauge
+	throttleLimitMbps  tally.Gauge
 }
 
 func newPersistManagerMetrics(scope tally.Scope) persistManagerMetrics {
 	return persistManagerMetrics{
 		writeDurationMs:    scope.Gauge("write-duration-ms"),
 		throttleDurationMs: scope.Gauge("throttle-duration-ms"),
+		throttleLimitMbps:  scope.Gauge("throttle-limit-mbps"),
 	}
 }
 
@@ -154,6 +160,17 @@ func NewPersistManager(opts Options) (persist.Manager, error) {
 		return nil, err
 	}
 
+	diskStatsFn, err := ratelimit.NewDiskStatsFn(filePathPrefix)
+	if err != nil {
+		// NB: the adaptive limit stays at the ceiling if disk stats are
+		// not available, i.e. behaves as the fixed limit.
+		opts.InstrumentOptions().Logger().Warnf(
+			"disk stats not available for adaptive persist rate limit: %v", err)
+		diskStatsFn = func() (ratelimit.DiskStats, error) {
+			return ratelimit.DiskStats{}, err
+		}
+	}
+
 	pm := &persistManager{
 		opts:           opts,
 		filePathPrefix: filePathPrefix,
@@ -167,8 +184,9 @@ func NewPersistManager(opts Options) (persist.Manager, error) {
 			writer:        idxWriter,
 			segmentWriter: segmentWriter,
 		},
-		status:  persistManagerIdle,
-		metrics: newPersistManagerMetrics(scope),
+		status:          persistManagerIdle,
+		adaptiveLimiter: ratelimit.NewAdaptiveLimiter(diskStatsFn),
+		metrics:         newPersistManagerMetrics(scope),
 	}
 	pm.indexPM.newReaderFn = NewIndexReader
 	pm.indexPM.newPersistentSegmentFn = m3ninxpersist.NewSegment
@@ -181,6 +199,8 @@ func (pm *persistManager) reset() {
 	pm.start = timeZero
 	pm.count = 0
 	pm.bytesWritten = 0
+	pm.bytesThrottled = 0
+	pm.throttleTarget = 0
 	pm.worked = 0
 	pm.slept = 0
 	pm.indexPM.segmentWriter.Reset(nil)
@@ -423,7 +443,8 @@ func (pm *persistManager) PrepareData(opts persist.DataPrepareOptions) (persist.
 
 	blockSize := nsMetadata.Options().RetentionOptions().BlockSize()
 	dataWriterOpts := DataWriterOpenOptions{
-		BlockSize: blockSize,
+		BlockSize:        blockSize,
+		SummariesPercent: nsMetadata.Options().IndexSummariesPercent(),
 		Snapshot: DataWriterSnapshotOptions{
 			SnapshotTime: snapshotTime,
 		},
@@ -439,6 +460,11 @@ func (pm *persistManager) PrepareData(opts persist.DataPrepareOptions) (persist.
 		return prepared, err
 	}
 
+	// NB: the rate limit is scaled by the priority of the namespace being
+	// persisted so lower priority namespaces leave more of the disk to
+	// higher priority namespaces.
+	pm.rateLimitScale = nsMetadata.Options().Priority().PersistRateLimitScale()
+
 	prepared.Persist = pm.persist
 	prepared.Close = pm.closeData
 
@@ -460,14 +486,21 @@ func (pm *persistManager) persist(
 		start = pm.nowFn()
 		slept time.Duration
 	)
-	rateLimitMbps := opts.LimitMbps()
-	if opts.LimitEnabled() && rateLimitMbps > 0.0 {
+	if opts.LimitEnabled() && opts.LimitMbps() > 0.0 && pm.rateLimitScale > 0.0 {
 		if pm.start.IsZero() {
 			pm.start = start
 		} else if pm.count >= opts.LimitCheckEvery() {
-			target := time.Duration(float64(time.Second) * float64(pm.bytesWritten) / (rateLimitMbps * bytesPerMegabit))
-			if elapsed := start.Sub(pm.start); elapsed < target {
-				pm.sleepFn(target - elapsed)
+			// NB: the target is accumulated at the limit in effect for the bytes
+			// written since the last check since an adaptive limit changes
+			// during a flush, for a fixed limit this is the same as the time
+			// to write all the bytes at the limit.
+			rateLimitMbps := pm.adaptiveLimiter.LimitMbps(opts, start) * pm.rateLimitScale
+			pm.metrics.throttleLimitMbps.Update(rateLimitMbps)
+			pm.throttleTarget += time.Duration(float64(time.Second) *
+				float64(pm.bytesWritten-pm.bytesThrottled) / (rateLimitMbps * bytesPerMegabit))
+			pm.bytesThrottled = pm.bytesWritten
+			if elapsed := start.Sub(pm.start); elapsed < pm.throttleTarget {
+				pm.sleepFn(pm.throttleTarget - elapsed)
 				// Recapture start for precise timing, might take some time to "wakeup"
 				now := pm.nowFn()
 				slept = now.Sub(start)
@@ -527,7 +560,8 @@ func (pm *persistManager) dataFilesetExistsAt(prepareOpts persist.DataPrepareOpt
 		// already exist doesn't make much sense
 		return false, nil
 	case persist.FileSetFlushType:
-		return DataFileSetExistsAt(pm.filePathPrefix, nsID, shard, blockStart)
+		return TieredDataFileSetExistsAt(pm.filePathPrefix, pm.opts.WarmFilePathPrefix(),
+			nsID, shard, blockStart)
 	default:
 		return false, fmt.Errorf(
 			"unable to determine if fileset exists in persist manager for fileset type: %s",
@@ -29,6 +29,8 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	m3ninxfs "github.com/m3db/m3/src/m3ninx/index/segment/fst"
@@ -553,6 +555,135 @@ func TestPersistenceManagerWithRateLimit(t *testing.T) {
 	}
 }
 
+func TestPersistenceManagerWithAdaptiveRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pm, writer, _ := testDataPersistManager(t, ctrl)
+	defer os.RemoveAll(pm.filePathPrefix)
+
+	var (
+		now      time.Time
+		slept    time.Duration
+		stats    ratelimit.DiskStats
+		id       = ident.StringID("foo")
+		head     = checked.NewBytes([]byte{0x1, 0x2}, nil)
+		tail     = checked.NewBytes([]byte{0x3}, nil)
+		segment  = ts.NewSegment(head, tail, ts.FinalizeNone)
+		checksum = digest.SegmentChecksum(segment)
+	)
+
+	pm.nowFn = func() time.Time { return now }
+	pm.sleepFn = func(d time.Duration) { slept += d }
+	pm.adaptiveLimiter = ratelimit.NewAdaptiveLimiter(func() (ratelimit.DiskStats, error) {
+		return stats, nil
+	})
+	pm.currRateLimitOpts = ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitCheckEvery(2).
+		SetLimitMbps(16.0).
+		SetAdaptiveEnabled(true).
+		SetAdaptiveMinLimitMbps(4.0)
+
+	writer.EXPECT().Open(gomock.Any()).Return(nil)
+	writer.EXPECT().WriteAll(id, ident.Tags{}, pm.dataPM.segmentHolder, checksum).Return(nil).AnyTimes()
+	writer.EXPECT().Close()
+
+	flush, err := pm.StartDataPersist()
+	require.NoError(t, err)
+	prepared, err := flush.PrepareData(persist.DataPrepareOptions{
+		NamespaceMetadata: testNs1Metadata(t),
+		Shard:             0,
+		BlockStart:        time.Unix(1000, 0),
+	})
+	require.NoError(t, err)
+
+	// The first check samples the disk and throttles at the ceiling.
+	now = time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, prepared.Persist(id, ident.Tags{}, segment, checksum))
+	}
+	require.Equal(t, time.Duration(2861), pm.throttleTarget)
+
+	// Reads starved by a saturated disk halve the limit for the next bytes.
+	stats.ReadsCompleted += 10
+	stats.IOTime += time.Second
+	now = now.Add(time.Second)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, prepared.Persist(id, ident.Tags{}, segment, checksum))
+	}
+	require.Equal(t, time.Duration(2861+5722), pm.throttleTarget)
+	require.Equal(t, time.Duration(0), slept)
+
+	require.NoError(t, prepared.Close())
+	require.NoError(t, flush.DoneData())
+}
+
+func TestPersistenceManagerRateLimitByNamespacePriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pm, writer, _ := testDataPersistManager(t, ctrl)
+	defer os.RemoveAll(pm.filePathPrefix)
+
+	var (
+		now      = time.Now()
+		slept    time.Duration
+		id       = ident.StringID("foo")
+		head     = checked.NewBytes([]byte{0x1, 0x2}, nil)
+		tail     = checked.NewBytes([]byte{0x3}, nil)
+		segment  = ts.NewSegment(head, tail, ts.FinalizeNone)
+		checksum = digest.SegmentChecksum(segment)
+	)
+
+	pm.nowFn = func() time.Time { return now }
+	pm.sleepFn = func(d time.Duration) { slept += d }
+	pm.currRateLimitOpts = ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitCheckEvery(2).
+		SetLimitMbps(16.0)
+
+	writer.EXPECT().Open(gomock.Any()).Return(nil).Times(3)
+	writer.EXPECT().WriteAll(id, ident.Tags{}, pm.dataPM.segmentHolder, checksum).Return(nil).AnyTimes()
+	writer.EXPECT().Close().Times(3)
+
+	tests := []struct {
+		priority namespace.Priority
+		expected time.Duration
+	}{
+		{priority: namespace.PriorityCritical, expected: 1430},
+		{priority: namespace.PriorityNormal, expected: 2861},
+		{priority: namespace.PriorityBackground, expected: 5722},
+	}
+	for _, test := range tests {
+		slept = 0
+
+		md, err := namespace.NewMetadata(testNs1ID, testNs1Metadata(t).Options().
+			SetPriority(test.priority))
+		require.NoError(t, err)
+
+		flush, err := pm.StartDataPersist()
+		require.NoError(t, err)
+		prepared, err := flush.PrepareData(persist.DataPrepareOptions{
+			NamespaceMetadata: md,
+			Shard:             0,
+			BlockStart:        time.Unix(1000, 0),
+		})
+		require.NoError(t, err)
+
+		// Critical namespaces are throttled at twice the limit while
+		// background namespaces are throttled at half the limit.
+		for i := 0; i < 3; i++ {
+			require.NoError(t, prepared.Persist(id, ident.Tags{}, segment, checksum))
+		}
+		require.Equal(t, test.expected, pm.throttleTarget, test.priority.String())
+		require.Equal(t, test.expected, slept, test.priority.String())
+
+		require.NoError(t, prepared.Close())
+		require.NoError(t, flush.DoneData())
+	}
+}
+
 func TestPersistenceManagerNamespaceSwitch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
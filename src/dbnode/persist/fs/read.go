@@ -152,7 +152,13 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		indexFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, snapshotIndex, indexFileSuffix)
 		dataFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, snapshotIndex, dataFileSuffix)
 	case persist.FileSetFlushType:
-		shardDir = ShardDataDirPath(r.filePathPrefix, namespace, shard)
+		var filePathPrefix string
+		filePathPrefix, err = DataFileSetPathPrefix(r.filePathPrefix,
+			r.opts.WarmFilePathPrefix(), namespace, shard, blockStart)
+		if err != nil {
+			return err
+		}
+		shardDir = ShardDataDirPath(filePathPrefix, namespace, shard)
 		checkpointFilepath = filesetPathFromTime(shardDir, blockStart, checkpointFileSuffix)
 		infoFilepath = filesetPathFromTime(shardDir, blockStart, infoFileSuffix)
 		digestFilepath = filesetPathFromTime(shardDir, blockStart, digestFileSuffix)
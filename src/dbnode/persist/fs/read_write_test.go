@@ -32,8 +32,11 @@ import (
 	"github.com/m3db/bloom"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/x/directio"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/ident"
+	"github.com/m3db/m3x/instrument"
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/stretchr/testify/assert"
@@ -455,3 +458,74 @@ func TestWriterOnlyWritesNonNilBytes(t *testing.T) {
 		{"foo", nil, []byte{1, 2, 3, 4, 5, 6}},
 	})
 }
+
+func TestWriterRecordsWriteAmplification(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	recorder := writeamp.NewRecorder(instrument.NewOptions())
+	w, err := NewWriter(testDefaultOpts.
+		SetFilePathPrefix(filePathPrefix).
+		SetWriterBufferSize(testWriterBufferSize).
+		SetWriteAmplificationRecorder(recorder))
+	require.NoError(t, err)
+
+	entries := []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+	}
+	writeTestData(t, w, 0, testWriterStart, entries, persist.FileSetFlushType)
+
+	var size int64
+	shardDir := ShardDataDirPath(filePathPrefix, testNs1ID, 0)
+	require.NoError(t, filepath.Walk(shardDir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return err
+	}))
+	require.True(t, size > 0)
+
+	report := recorder.Report()
+	assert.Equal(t, size, report.WrittenBytes)
+	for _, source := range report.Sources {
+		if source.Source == writeamp.Flush {
+			assert.Equal(t, size, source.WrittenBytes)
+		}
+	}
+}
+
+func TestSimpleReadWriteDirectIO(t *testing.T) {
+	if !directio.Supported {
+		t.Skip("direct I/O not supported on this platform")
+	}
+
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	fd, err := directio.OpenFile(filepath.Join(dir, "probe"),
+		os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultNewFileMode)
+	if err != nil {
+		t.Skipf("direct I/O not supported by the temp dir filesystem: %v", err)
+	}
+	require.NoError(t, fd.Close())
+
+	entries := []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+		{"baz", nil, make([]byte, 65536)},
+		{"cat", nil, make([]byte, 100000)},
+	}
+
+	w, err := NewWriter(testDefaultOpts.
+		SetFilePathPrefix(filePathPrefix).
+		SetWriterBufferSize(testWriterBufferSize).
+		SetDirectIOWritesEnabled(true))
+	require.NoError(t, err)
+	writeTestData(t, w, 0, testWriterStart, entries, persist.FileSetFlushType)
+
+	r := newTestReader(t, filePathPrefix)
+	readTestData(t, r, 0, testWriterStart, entries)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package reshard
+
+import (
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
+	"github.com/m3db/m3x/pool"
+)
+
+const (
+	defaultBufferSize = 65536
+	defaultFileMode   = os.FileMode(0666)
+	defaultDirMode    = os.ModeDir | os.FileMode(0755)
+)
+
+type options struct {
+	bytesPool    pool.CheckedBytesPool
+	decodingOpts msgpack.DecodingOptions
+	bufferSize   int
+	fileMode     os.FileMode
+	dirMode      os.FileMode
+}
+
+// NewOptions returns new reshard options
+func NewOptions() Options {
+	return &options{
+		decodingOpts: msgpack.NewDecodingOptions(),
+		bufferSize:   defaultBufferSize,
+		fileMode:     defaultFileMode,
+		dirMode:      defaultDirMode,
+	}
+}
+
+func (o *options) SetBytesPool(value pool.CheckedBytesPool) Options {
+	opts := *o
+	opts.bytesPool = value
+	return &opts
+}
+
+func (o *options) BytesPool() pool.CheckedBytesPool {
+	return o.bytesPool
+}
+
+func (o *options) SetDecodingOptions(value msgpack.DecodingOptions) Options {
+	opts := *o
+	opts.decodingOpts = value
+	return &opts
+}
+
+func (o *options) DecodingOptions() msgpack.DecodingOptions {
+	return o.decodingOpts
+}
+
+func (o *options) SetBufferSize(value int) Options {
+	opts := *o
+	opts.bufferSize = value
+	return &opts
+}
+
+func (o *options) BufferSize() int {
+	return o.bufferSize
+}
+
+func (o *options) SetFileMode(value os.FileMode) Options {
+	opts := *o
+	opts.fileMode = value
+	return &opts
+}
+
+func (o *options) FileMode() os.FileMode {
+	return o.fileMode
+}
+
+func (o *options) SetDirMode(value os.FileMode) Options {
+	opts := *o
+	opts.dirMode = value
+	return &opts
+}
+
+func (o *options) DirMode() os.FileMode {
+	return o.dirMode
+}
@@ -0,0 +1,521 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package reshard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	m3ninxpersist "github.com/m3db/m3/src/m3ninx/persist"
+	"github.com/m3db/m3cluster/placement"
+	"github.com/m3db/m3x/ident"
+	"github.com/m3db/m3x/ident/testutil"
+	xtime "github.com/m3db/m3x/time"
+)
+
+var (
+	errNoSourceShards        = errors.New("no source shards specified")
+	errInvalidNumShards      = errors.New("destination number of shards must be positive")
+	errNoPathPrefixesFn      = errors.New("no destination path prefixes fn specified")
+	errNoDestinationPrefixes = errors.New("no destination path prefixes for shard")
+)
+
+type resharder struct {
+	opts   Options
+	fsOpts fs.Options
+}
+
+// NewResharder creates a new fileset resharder
+func NewResharder(opts Options) Resharder {
+	fsOpts := fs.NewOptions().
+		SetDataReaderBufferSize(opts.BufferSize()).
+		SetInfoReaderBufferSize(opts.BufferSize()).
+		SetWriterBufferSize(opts.BufferSize()).
+		SetDecodingOptions(opts.DecodingOptions()).
+		SetNewFileMode(opts.FileMode()).
+		SetNewDirectoryMode(opts.DirMode())
+	return &resharder{
+		opts:   opts,
+		fsOpts: fsOpts,
+	}
+}
+
+// NewPathPrefixesFn returns a path prefixes fn that writes every shard to
+// the given path prefix
+func NewPathPrefixesFn(pathPrefix string) PathPrefixesFn {
+	pathPrefixes := []string{pathPrefix}
+	return func(shard uint32) []string {
+		return pathPrefixes
+	}
+}
+
+// NewPlacementPathPrefixesFn returns a path prefixes fn that writes every
+// shard to a directory per instance that owns it in the given placement,
+// i.e. <pathPrefix>/<instanceID>, so the directories can be copied as is
+// to the hosts of the resharded placement
+func NewPlacementPathPrefixesFn(
+	pathPrefix string,
+	p placement.Placement,
+) PathPrefixesFn {
+	return func(shard uint32) []string {
+		instances := p.InstancesForShard(shard)
+		pathPrefixes := make([]string, 0, len(instances))
+		for _, instance := range instances {
+			pathPrefixes = append(pathPrefixes, path.Join(pathPrefix, instance.ID()))
+		}
+		return pathPrefixes
+	}
+}
+
+func (r *resharder) Reshard(src Source, dest Destination) (Result, error) {
+	if len(src.Shards) == 0 {
+		return Result{}, errNoSourceShards
+	}
+	if dest.NumShards <= 0 {
+		return Result{}, errInvalidNumShards
+	}
+	if dest.PathPrefixesFn == nil {
+		return Result{}, errNoPathPrefixesFn
+	}
+
+	blockStarts, blockSize, err := r.blockStarts(src)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{SeriesByShard: make(map[uint32]int)}
+	for _, blockStart := range blockStarts {
+		if err := r.reshardBlock(src, dest, blockStart, blockSize, &result); err != nil {
+			return Result{}, fmt.Errorf("unable to reshard block %v: %v", blockStart, err)
+		}
+		result.NumBlocks++
+	}
+
+	// NB: the index filesets of the source hold the series of every shard of
+	// the source node, so they are rebuilt from the resharded series rather
+	// than copied, otherwise nodes would bootstrap the index from them with
+	// the source shards.
+	indexBlocks, err := r.indexBlocks(src)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, indexBlock := range indexBlocks {
+		if err := r.reshardIndexBlock(src, dest, indexBlock, blockStarts, blockSize); err != nil {
+			return Result{}, fmt.Errorf("unable to reshard index block %v: %v",
+				indexBlock.start, err)
+		}
+		result.NumIndexBlocks++
+	}
+	return result, nil
+}
+
+func destHashFn(dest Destination) sharding.HashFn {
+	hashFn := sharding.DefaultHashFn(dest.NumShards)
+	if len(dest.ShardRoutingTag) > 0 {
+		hashFn = sharding.NewTagRoutingHashFn(dest.ShardRoutingTag, hashFn)
+	}
+	return hashFn
+}
+
+func (r *resharder) blockStarts(src Source) ([]time.Time, time.Duration, error) {
+	var (
+		namespace   = ident.StringID(src.Namespace)
+		blockSize   time.Duration
+		blockStarts = make(map[xtime.UnixNano]struct{})
+	)
+	for _, shard := range src.Shards {
+		results := fs.ReadInfoFiles(src.PathPrefix, namespace, shard,
+			r.opts.BufferSize(), r.opts.DecodingOptions())
+		for _, result := range results {
+			if err := result.Err.Error(); err != nil {
+				return nil, 0, fmt.Errorf("unable to read info file %s: %v",
+					result.Err.Filepath(), err)
+			}
+			size := time.Duration(result.Info.BlockSize)
+			if blockSize == 0 {
+				blockSize = size
+			} else if blockSize != size {
+				return nil, 0, fmt.Errorf("mismatched block sizes: %v and %v",
+					blockSize, size)
+			}
+			blockStarts[xtime.UnixNano(result.Info.BlockStart)] = struct{}{}
+		}
+	}
+
+	sorted := make([]time.Time, 0, len(blockStarts))
+	for blockStart := range blockStarts {
+		sorted = append(sorted, blockStart.ToTime())
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Before(sorted[j])
+	})
+	return sorted, blockSize, nil
+}
+
+func (r *resharder) reshardBlock(
+	src Source,
+	dest Destination,
+	blockStart time.Time,
+	blockSize time.Duration,
+	result *Result,
+) error {
+	reader, err := fs.NewReader(r.opts.BytesPool(),
+		r.fsOpts.SetFilePathPrefix(src.PathPrefix))
+	if err != nil {
+		return fmt.Errorf("unable to create fileset reader: %v", err)
+	}
+
+	var (
+		srcNamespace = ident.StringID(src.Namespace)
+		hashFn       = destHashFn(dest)
+		writers      = make(map[uint32][]fs.DataFileSetWriter)
+	)
+	for _, shard := range src.Shards {
+		exists, err := fs.DataFileSetExistsAt(src.PathPrefix, srcNamespace,
+			shard, blockStart)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		openOpts := fs.DataReaderOpenOptions{
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  srcNamespace,
+				Shard:      shard,
+				BlockStart: blockStart,
+			},
+			FileSetType: persist.FileSetFlushType,
+		}
+		if err := reader.Open(openOpts); err != nil {
+			return fmt.Errorf("unable to read source fileset for shard %d: %v",
+				shard, err)
+		}
+
+		for {
+			id, tagsIter, data, checksum, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("unexpected error while reading data: %v", err)
+			}
+
+			tags, err := testutil.NewTagsFromTagIterator(tagsIter)
+			if err != nil {
+				return err
+			}
+
+			destShard := hashFn(id)
+			shardWriters, ok := writers[destShard]
+			if !ok {
+				shardWriters, err = r.openWriters(dest, destShard, blockStart, blockSize)
+				if err != nil {
+					return err
+				}
+				writers[destShard] = shardWriters
+			}
+
+			data.IncRef()
+			for _, writer := range shardWriters {
+				if err := writer.Write(id, tags, data, checksum); err != nil {
+					data.DecRef()
+					return fmt.Errorf("unexpected error while writing data: %v", err)
+				}
+			}
+			data.DecRef()
+			data.Finalize()
+
+			result.NumSeries++
+			result.SeriesByShard[destShard]++
+		}
+
+		if err := reader.Close(); err != nil {
+			return fmt.Errorf("unable to finalize reader: %v", err)
+		}
+	}
+
+	for _, shardWriters := range writers {
+		for _, writer := range shardWriters {
+			if err := writer.Close(); err != nil {
+				return fmt.Errorf("unable to finalize writer: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *resharder) openWriters(
+	dest Destination,
+	shard uint32,
+	blockStart time.Time,
+	blockSize time.Duration,
+) ([]fs.DataFileSetWriter, error) {
+	pathPrefixes := dest.PathPrefixesFn(shard)
+	if len(pathPrefixes) == 0 {
+		return nil, fmt.Errorf("%v: %d", errNoDestinationPrefixes, shard)
+	}
+
+	writers := make([]fs.DataFileSetWriter, 0, len(pathPrefixes))
+	for _, pathPrefix := range pathPrefixes {
+		writer, err := fs.NewWriter(r.fsOpts.SetFilePathPrefix(pathPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create fileset writer: %v", err)
+		}
+		writerOpts := fs.DataWriterOpenOptions{
+			BlockSize: blockSize,
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  ident.StringID(dest.Namespace),
+				Shard:      shard,
+				BlockStart: blockStart,
+			},
+		}
+		if err := writer.Open(writerOpts); err != nil {
+			return nil, fmt.Errorf("unable to open fileset writer: %v", err)
+		}
+		writers = append(writers, writer)
+	}
+	return writers, nil
+}
+
+type indexBlock struct {
+	start time.Time
+	size  time.Duration
+}
+
+func (r *resharder) indexBlocks(src Source) ([]indexBlock, error) {
+	var (
+		namespace = ident.StringID(src.Namespace)
+		blocks    = make(map[xtime.UnixNano]time.Duration)
+	)
+	results := fs.ReadIndexInfoFiles(src.PathPrefix, namespace, r.opts.BufferSize())
+	for _, result := range results {
+		if err := result.Err.Error(); err != nil {
+			return nil, fmt.Errorf("unable to read index info file %s: %v",
+				result.Err.Filepath(), err)
+		}
+		blocks[xtime.UnixNano(result.Info.BlockStart)] = time.Duration(result.Info.BlockSize)
+	}
+
+	sorted := make([]indexBlock, 0, len(blocks))
+	for start, size := range blocks {
+		sorted = append(sorted, indexBlock{start: start.ToTime(), size: size})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start.Before(sorted[j].start)
+	})
+	return sorted, nil
+}
+
+// indexSegmentBuilder builds the index segment of the series written to the
+// shards of a destination path prefix.
+type indexSegmentBuilder struct {
+	segment segment.MutableSegment
+	shards  map[uint32]struct{}
+}
+
+func (r *resharder) reshardIndexBlock(
+	src Source,
+	dest Destination,
+	block indexBlock,
+	blockStarts []time.Time,
+	blockSize time.Duration,
+) error {
+	reader, err := fs.NewReader(r.opts.BytesPool(),
+		r.fsOpts.SetFilePathPrefix(src.PathPrefix))
+	if err != nil {
+		return fmt.Errorf("unable to create fileset reader: %v", err)
+	}
+
+	var (
+		srcNamespace = ident.StringID(src.Namespace)
+		hashFn       = destHashFn(dest)
+		builders     = make(map[string]*indexSegmentBuilder)
+		blockEnd     = block.start.Add(block.size)
+	)
+	for _, blockStart := range blockStarts {
+		// the index block holds the series of every data block it overlaps.
+		if !blockStart.Before(blockEnd) || !blockStart.Add(blockSize).After(block.start) {
+			continue
+		}
+		for _, shard := range src.Shards {
+			exists, err := fs.DataFileSetExistsAt(src.PathPrefix, srcNamespace,
+				shard, blockStart)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+
+			openOpts := fs.DataReaderOpenOptions{
+				Identifier: fs.FileSetFileIdentifier{
+					Namespace:  srcNamespace,
+					Shard:      shard,
+					BlockStart: blockStart,
+				},
+				FileSetType: persist.FileSetFlushType,
+			}
+			if err := reader.Open(openOpts); err != nil {
+				return fmt.Errorf("unable to read source fileset for shard %d: %v",
+					shard, err)
+			}
+
+			for {
+				id, tagsIter, _, _, err := reader.ReadMetadata()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return fmt.Errorf("unexpected error while reading metadata: %v", err)
+				}
+
+				err = r.indexSeries(dest, hashFn(id), id, tagsIter, builders)
+				id.Finalize()
+				tagsIter.Close()
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := reader.Close(); err != nil {
+				return fmt.Errorf("unable to finalize reader: %v", err)
+			}
+		}
+	}
+
+	for pathPrefix, builder := range builders {
+		if err := r.writeIndexSegment(pathPrefix, dest, block, builder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *resharder) indexSeries(
+	dest Destination,
+	shard uint32,
+	id ident.ID,
+	tagsIter ident.TagIterator,
+	builders map[string]*indexSegmentBuilder,
+) error {
+	pathPrefixes := dest.PathPrefixesFn(shard)
+	if len(pathPrefixes) == 0 {
+		return fmt.Errorf("%v: %d", errNoDestinationPrefixes, shard)
+	}
+	var d *doc.Document
+	for _, pathPrefix := range pathPrefixes {
+		builder, ok := builders[pathPrefix]
+		if !ok {
+			seg, err := mem.NewSegment(0, mem.NewOptions())
+			if err != nil {
+				return fmt.Errorf("unable to create index segment: %v", err)
+			}
+			builder = &indexSegmentBuilder{
+				segment: seg,
+				shards:  make(map[uint32]struct{}),
+			}
+			builders[pathPrefix] = builder
+		}
+		builder.shards[shard] = struct{}{}
+
+		exists, err := builder.segment.ContainsID(id.Bytes())
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if d == nil {
+			// NB: the series is only converted once as the tags iterator is
+			// consumed, the document is shared by the segments of replicas.
+			converted, err := convert.FromMetricIter(id, tagsIter)
+			if err != nil {
+				return fmt.Errorf("unable to convert series to document: %v", err)
+			}
+			d = &converted
+		}
+		if _, err := builder.segment.Insert(*d); err != nil {
+			return fmt.Errorf("unable to index series: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *resharder) writeIndexSegment(
+	pathPrefix string,
+	dest Destination,
+	block indexBlock,
+	builder *indexSegmentBuilder,
+) error {
+	namespace := ident.StringID(dest.Namespace)
+	volumeIndex, err := fs.NextIndexFileSetVolumeIndex(pathPrefix, namespace, block.start)
+	if err != nil {
+		return err
+	}
+
+	writer, err := fs.NewIndexWriter(r.fsOpts.SetFilePathPrefix(pathPrefix))
+	if err != nil {
+		return fmt.Errorf("unable to create index fileset writer: %v", err)
+	}
+	if err := writer.Open(fs.IndexWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          namespace,
+			BlockStart:         block.start,
+			VolumeIndex:        volumeIndex,
+		},
+		BlockSize:   block.size,
+		FileSetType: persist.FileSetFlushType,
+		Shards:      builder.shards,
+	}); err != nil {
+		return fmt.Errorf("unable to open index fileset writer: %v", err)
+	}
+
+	if _, err := builder.segment.Seal(); err != nil {
+		return fmt.Errorf("unable to seal index segment: %v", err)
+	}
+	segmentWriter, err := m3ninxpersist.NewMutableSegmentFileSetWriter()
+	if err != nil {
+		return err
+	}
+	if err := segmentWriter.Reset(builder.segment); err != nil {
+		return fmt.Errorf("unable to reset index segment writer: %v", err)
+	}
+	if err := writer.WriteSegmentFileSet(segmentWriter); err != nil {
+		return fmt.Errorf("unable to write index segment: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("unable to finalize index fileset writer: %v", err)
+	}
+	return builder.segment.Close()
+}
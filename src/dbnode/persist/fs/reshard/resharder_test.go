@@ -0,0 +1,277 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package reshard
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	numTestSeriesPerShard = 100
+	testNamespace         = "testns"
+)
+
+func TestResharder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reshard")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	opts := NewOptions()
+
+	// generate some fake source data across two shards and blocks
+	var (
+		blockSize   = time.Hour
+		blockStart  = time.Now().Truncate(blockSize)
+		srcShards   = []uint32{0, 1}
+		srcData     = path.Join(dir, "src")
+		destData    = path.Join(dir, "dest")
+		srcHashFn   = sharding.DefaultHashFn(len(srcShards))
+		numWritten  = 0
+		srcSeriesID = 0
+	)
+	for _, shard := range srcShards {
+		var ids []ident.ID
+		for len(ids) < numTestSeriesPerShard {
+			id := ident.StringID(fmt.Sprintf("test-series.%d", srcSeriesID))
+			srcSeriesID++
+			if srcHashFn(id) == shard {
+				ids = append(ids, id)
+			}
+		}
+		writeTestData(t, opts, srcData, shard, blockStart, blockSize, ids)
+		writeTestData(t, opts, srcData, shard, blockStart.Add(blockSize), blockSize, ids[:10])
+		numWritten += len(ids) + 10
+	}
+
+	// reshard it
+	destNumShards := 4
+	resharder := NewResharder(opts)
+	result, err := resharder.Reshard(Source{
+		PathPrefix: srcData,
+		Namespace:  testNamespace,
+		Shards:     srcShards,
+	}, Destination{
+		PathPrefixesFn: NewPathPrefixesFn(destData),
+		Namespace:      testNamespace,
+		NumShards:      destNumShards,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.NumBlocks)
+	require.Equal(t, numWritten, result.NumSeries)
+
+	// verify every series was written to the shard it hashes to
+	destHashFn := sharding.DefaultHashFn(destNumShards)
+	numRead := 0
+	for shard := uint32(0); shard < uint32(destNumShards); shard++ {
+		for _, bs := range []time.Time{blockStart, blockStart.Add(blockSize)} {
+			exists, err := fs.DataFileSetExistsAt(destData,
+				ident.StringID(testNamespace), shard, bs)
+			require.NoError(t, err)
+			if !exists {
+				continue
+			}
+
+			reader, err := fs.NewReader(nil, fs.NewOptions().
+				SetFilePathPrefix(destData))
+			require.NoError(t, err)
+			require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+				Identifier: fs.FileSetFileIdentifier{
+					Namespace:  ident.StringID(testNamespace),
+					Shard:      shard,
+					BlockStart: bs,
+				},
+			}))
+			for {
+				id, _, data, _, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				require.Equal(t, shard, destHashFn(id))
+				data.IncRef()
+				require.Equal(t, []byte(id.String()), data.Bytes())
+				data.DecRef()
+				numRead++
+			}
+			require.NoError(t, reader.Close())
+		}
+	}
+	require.Equal(t, numWritten, numRead)
+}
+
+func TestResharderIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reshard")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	opts := NewOptions()
+
+	var (
+		blockSize = time.Hour
+		// the index block covers both data blocks.
+		indexBlockSize = 2 * blockSize
+		blockStart     = time.Now().Truncate(indexBlockSize)
+		srcData        = path.Join(dir, "src")
+		destData       = path.Join(dir, "dest")
+		ids            []ident.ID
+	)
+	for i := 0; i < numTestSeriesPerShard; i++ {
+		ids = append(ids, ident.StringID(fmt.Sprintf("test-series.%d", i)))
+	}
+	writeTestData(t, opts, srcData, 0, blockStart, blockSize, ids[:50])
+	writeTestData(t, opts, srcData, 0, blockStart.Add(blockSize), blockSize, ids[40:])
+	writeTestIndex(t, srcData, blockStart, indexBlockSize, []uint32{0})
+
+	destNumShards := 4
+	result, err := NewResharder(opts).Reshard(Source{
+		PathPrefix: srcData,
+		Namespace:  testNamespace,
+		Shards:     []uint32{0},
+	}, Destination{
+		PathPrefixesFn: NewPathPrefixesFn(destData),
+		Namespace:      testNamespace,
+		NumShards:      destNumShards,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.NumIndexBlocks)
+
+	// verify the index fileset holds every series with the destination shards
+	infoFiles := fs.ReadIndexInfoFiles(destData, ident.StringID(testNamespace),
+		opts.BufferSize())
+	require.Equal(t, 1, len(infoFiles))
+	require.NoError(t, infoFiles[0].Err.Error())
+	require.Equal(t, blockStart.UnixNano(), infoFiles[0].Info.BlockStart)
+	require.Equal(t, int64(indexBlockSize), infoFiles[0].Info.BlockSize)
+
+	destHashFn := sharding.DefaultHashFn(destNumShards)
+	expectedShards := make(map[uint32]struct{})
+	for _, id := range ids {
+		expectedShards[destHashFn(id)] = struct{}{}
+	}
+	shards := make(map[uint32]struct{})
+	for _, shard := range infoFiles[0].Info.Shards {
+		shards[shard] = struct{}{}
+	}
+	require.Equal(t, expectedShards, shards)
+
+	segments, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+		ReaderOptions: fs.IndexReaderOpenOptions{
+			Identifier:  infoFiles[0].ID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: fs.NewOptions().SetFilePathPrefix(destData),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(segments))
+	defer segments[0].Close()
+	require.Equal(t, int64(len(ids)), segments[0].Size())
+	for _, id := range ids {
+		exists, err := segments[0].ContainsID(id.Bytes())
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+}
+
+func TestResharderInvalidDestination(t *testing.T) {
+	resharder := NewResharder(NewOptions())
+	src := Source{PathPrefix: "/tmp", Namespace: testNamespace, Shards: []uint32{0}}
+
+	_, err := resharder.Reshard(src, Destination{
+		PathPrefixesFn: NewPathPrefixesFn("/tmp"),
+		Namespace:      testNamespace,
+	})
+	require.Equal(t, errInvalidNumShards, err)
+
+	_, err = resharder.Reshard(src, Destination{
+		Namespace: testNamespace,
+		NumShards: 4,
+	})
+	require.Equal(t, errNoPathPrefixesFn, err)
+}
+
+func writeTestData(
+	t *testing.T,
+	opts Options,
+	pathPrefix string,
+	shard uint32,
+	blockStart time.Time,
+	blockSize time.Duration,
+	ids []ident.ID,
+) {
+	w, err := fs.NewWriter(fs.NewOptions().
+		SetFilePathPrefix(pathPrefix).
+		SetWriterBufferSize(opts.BufferSize()).
+		SetNewFileMode(opts.FileMode()).
+		SetNewDirectoryMode(opts.DirMode()))
+	require.NoError(t, err)
+	require.NoError(t, w.Open(fs.DataWriterOpenOptions{
+		BlockSize: blockSize,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  ident.StringID(testNamespace),
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+	}))
+	for _, id := range ids {
+		data := checked.NewBytes([]byte(id.String()), nil)
+		data.IncRef()
+		require.NoError(t, w.Write(id, ident.Tags{}, data, 1234))
+		data.DecRef()
+	}
+	require.NoError(t, w.Close())
+}
+
+func writeTestIndex(
+	t *testing.T,
+	pathPrefix string,
+	blockStart time.Time,
+	blockSize time.Duration,
+	shards []uint32,
+) {
+	w, err := fs.NewIndexWriter(fs.NewOptions().SetFilePathPrefix(pathPrefix))
+	require.NoError(t, err)
+	shardSet := make(map[uint32]struct{}, len(shards))
+	for _, shard := range shards {
+		shardSet[shard] = struct{}{}
+	}
+	require.NoError(t, w.Open(fs.IndexWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          ident.StringID(testNamespace),
+			BlockStart:         blockStart,
+		},
+		BlockSize:   blockSize,
+		FileSetType: persist.FileSetFlushType,
+		Shards:      shardSet,
+	}))
+	require.NoError(t, w.Close())
+}
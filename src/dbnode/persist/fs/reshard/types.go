@@ -0,0 +1,100 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package reshard
+
+import (
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
+	"github.com/m3db/m3x/pool"
+)
+
+// Source identifies the filesets of a namespace to be resharded
+type Source struct {
+	PathPrefix string
+	Namespace  string
+	Shards     []uint32
+}
+
+// PathPrefixesFn returns the path prefixes the filesets of a shard are
+// written to, a shard is written once per path prefix
+type PathPrefixesFn func(shard uint32) []string
+
+// Destination describes where and how resharded filesets are written
+type Destination struct {
+	PathPrefixesFn PathPrefixesFn
+	Namespace      string
+	NumShards      int
+	// ShardRoutingTag, if set, hashes series by the value of this tag only,
+	// it must match the shard routing tag of the destination namespace.
+	ShardRoutingTag []byte
+}
+
+// Result is the result of a reshard
+type Result struct {
+	NumBlocks      int
+	NumIndexBlocks int
+	NumSeries      int
+	SeriesByShard  map[uint32]int
+}
+
+// Resharder re-hashes the series of a namespace's filesets into a
+// different number of shards
+type Resharder interface {
+	// Reshard reads every flushed fileset of the source shards and writes
+	// each series to the destination shard it hashes to, the index filesets
+	// of the source are rebuilt for the shards of each destination path
+	// prefix, if an error is returned the destination filesets are
+	// incomplete and must be discarded
+	Reshard(src Source, dest Destination) (Result, error)
+}
+
+// Options represents the knobs available while resharding
+type Options interface {
+	// SetBytesPool sets the bytesPool
+	SetBytesPool(value pool.CheckedBytesPool) Options
+
+	// BytesPool returns the bytesPool
+	BytesPool() pool.CheckedBytesPool
+
+	// SetDecodingOptions sets the decoding options
+	SetDecodingOptions(value msgpack.DecodingOptions) Options
+
+	// DecodingOptions returns the decoding options
+	DecodingOptions() msgpack.DecodingOptions
+
+	// SetBufferSize sets the buffer size
+	SetBufferSize(value int) Options
+
+	// BufferSize returns the buffer size
+	BufferSize() int
+
+	// SetFileMode sets the fileMode used for file creation
+	SetFileMode(value os.FileMode) Options
+
+	// FileMode returns the fileMode used for file creation
+	FileMode() os.FileMode
+
+	// SetDirMode sets the file mode used for dir creation
+	SetDirMode(value os.FileMode) Options
+
+	// DirMode returns the file mode used for dir creation
+	DirMode() os.FileMode
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/context"
@@ -145,7 +146,7 @@ func (r *blockRetriever) Open(ns namespace.Metadata) error {
 	r.blockSize = ns.Options().RetentionOptions().BlockSize()
 
 	for i := 0; i < r.opts.FetchConcurrency(); i++ {
-		go r.fetchLoop(seekerMgr)
+		go r.fetchLoop(seekerMgr, i)
 	}
 	return nil
 }
@@ -160,7 +161,14 @@ func (r *blockRetriever) CacheShardIndices(shards []uint32) error {
 	return r.seekerMgr.CacheShardIndices(shards)
 }
 
-func (r *blockRetriever) fetchLoop(seekerMgr DataFileSetSeekerManager) {
+func (r *blockRetriever) fetchLoop(seekerMgr DataFileSetSeekerManager, i int) {
+	if sets := r.opts.FetchCPUSets(); len(sets) > 0 {
+		set := sets[i%len(sets)]
+		if err := cpuset.LockAndPin(set); err != nil {
+			r.logger.Warnf("could not pin block retriever fetch loop to cpus %s: %v", set, err)
+		}
+	}
+
 	var (
 		inFlight      []*retrieveRequest
 		currBatchReqs []*retrieveRequest
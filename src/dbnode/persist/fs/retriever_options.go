@@ -21,6 +21,7 @@
 package fs
 
 import (
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
@@ -37,6 +38,7 @@ type blockRetrieverOptions struct {
 	segmentReaderPool xio.SegmentReaderPool
 	fetchConcurrency  int
 	identifierPool    ident.Pool
+	fetchCPUSets      []cpuset.CPUSet
 }
 
 // NewBlockRetrieverOptions creates a new set of block retriever options
@@ -108,3 +110,13 @@ func (o *blockRetrieverOptions) SetIdentifierPool(value ident.Pool) BlockRetriev
 func (o *blockRetrieverOptions) IdentifierPool() ident.Pool {
 	return o.identifierPool
 }
+
+func (o *blockRetrieverOptions) SetFetchCPUSets(value []cpuset.CPUSet) BlockRetrieverOptions {
+	opts := *o
+	opts.fetchCPUSets = value
+	return &opts
+}
+
+func (o *blockRetrieverOptions) FetchCPUSets() []cpuset.CPUSet {
+	return o.fetchCPUSets
+}
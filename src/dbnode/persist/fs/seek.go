@@ -36,6 +36,8 @@ import (
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
 	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -82,9 +84,25 @@ type seeker struct {
 
 	keepUnreadBuf bool
 
+	metrics seekerMetrics
+
 	isClone bool
 }
 
+type seekerMetrics struct {
+	indexSeeks          tally.Counter
+	indexEntriesScanned tally.Counter
+	indexBytesScanned   tally.Counter
+}
+
+func newSeekerMetrics(scope tally.Scope) seekerMetrics {
+	return seekerMetrics{
+		indexSeeks:          scope.Counter("index-seeks"),
+		indexEntriesScanned: scope.Counter("index-entries-scanned"),
+		indexBytesScanned:   scope.Counter("index-bytes-scanned"),
+	}
+}
+
 // IndexEntry is an entry from the index file which can be passed to
 // SeekUsingIndexEntry to seek to the data for that entry
 type IndexEntry struct {
@@ -142,6 +160,7 @@ type fileSetSeeker interface {
 }
 
 func newSeeker(opts seekerOpts) fileSetSeeker {
+	scope := opts.opts.InstrumentOptions().MetricsScope().SubScope("seek")
 	return &seeker{
 		filePathPrefix: opts.filePathPrefix,
 		keepUnreadBuf:  opts.keepUnreadBuf,
@@ -149,6 +168,7 @@ func newSeeker(opts seekerOpts) fileSetSeeker {
 		decoder:        msgpack.NewDecoder(opts.decodingOpts),
 		decodingOpts:   opts.decodingOpts,
 		opts:           opts,
+		metrics:        newSeekerMetrics(scope),
 	}
 }
 
@@ -161,7 +181,12 @@ func (s *seeker) Open(namespace ident.ID, shard uint32, blockStart time.Time) er
 		return errClonesShouldNotBeOpened
 	}
 
-	shardDir := ShardDataDirPath(s.filePathPrefix, namespace, shard)
+	filePathPrefix, err := DataFileSetPathPrefix(s.filePathPrefix,
+		s.opts.opts.WarmFilePathPrefix(), namespace, shard, blockStart)
+	if err != nil {
+		return err
+	}
+	shardDir := ShardDataDirPath(filePathPrefix, namespace, shard)
 	var infoFd, indexFd, dataFd, digestFd, bloomFilterFd, summariesFd *os.File
 
 	// Open necessary files
@@ -382,19 +407,28 @@ func (s *seeker) SeekByIndexEntry(entry IndexEntry) (checked.Bytes, error) {
 }
 
 func (s *seeker) SeekIndexEntry(id ident.ID) (IndexEntry, error) {
-	offset, err := s.indexLookup.getNearestIndexFileOffset(id)
-	// Should never happen, either something is really wrong with the code or
-	// the file on disk was corrupted
-	if err != nil {
-		return IndexEntry{}, err
+	// The series can only be between the nearest summaries before and after
+	// it so only that range of the index file is scanned.
+	start, end := s.indexLookup.getNearestIndexFileRange(id)
+	indexBytes := s.indexMmap[start:]
+	if end >= 0 {
+		indexBytes = s.indexMmap[start:end]
 	}
 
-	stream := msgpack.NewDecoderStream(s.indexMmap[offset:])
+	stream := msgpack.NewDecoderStream(indexBytes)
 	s.decoder.Reset(stream)
 
+	var scanned int64
+	s.metrics.indexSeeks.Inc(1)
+	defer func() {
+		s.metrics.indexEntriesScanned.Inc(scanned)
+		s.metrics.indexBytesScanned.Inc(int64(len(indexBytes)) - stream.Remaining())
+	}()
+
 	idBytes := id.Bytes()
 	// Prevent panic's when we're scanning to the end of the buffer
 	for stream.Remaining() != 0 {
+		scanned++
 		entry, err := s.decoder.DecodeIndexEntry()
 		// Should never happen, either something is really wrong with the code or
 		// the file on disk was corrupted
@@ -476,6 +510,7 @@ func (s *seeker) ConcurrentClone() (ConcurrentDataFileSetSeeker, error) {
 		// bloomFilter is concurrency safe
 		bloomFilter: s.bloomFilter,
 		indexLookup: indexLookupClone,
+		metrics:     s.metrics,
 		isClone:     true,
 	}, nil
 }
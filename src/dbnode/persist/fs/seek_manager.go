@@ -360,7 +360,8 @@ func (m *seekerManager) newOpenSeeker(
 	shard uint32,
 	blockStart time.Time,
 ) (DataFileSetSeeker, error) {
-	exists, err := DataFileSetExistsAt(m.filePathPrefix, m.namespace, shard, blockStart)
+	exists, err := TieredDataFileSetExistsAt(m.filePathPrefix,
+		m.opts.WarmFilePathPrefix(), m.namespace, shard, blockStart)
 	if err != nil {
 		return nil, err
 	}
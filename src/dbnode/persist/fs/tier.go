@@ -0,0 +1,253 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+)
+
+// DataFileSetPathPrefix returns the file path prefix that holds the complete
+// data fileset for the given namespace, shard and block start. Filesets are
+// resolved from the hot prefix first and then the warm prefix, if neither
+// holds a complete fileset the hot prefix is returned.
+func DataFileSetPathPrefix(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+) (string, error) {
+	if warmFilePathPrefix == "" {
+		return hotFilePathPrefix, nil
+	}
+	exists, err := DataFileSetExistsAt(hotFilePathPrefix, namespace, shard, blockStart)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return hotFilePathPrefix, nil
+	}
+	exists, err = DataFileSetExistsAt(warmFilePathPrefix, namespace, shard, blockStart)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return warmFilePathPrefix, nil
+	}
+	return hotFilePathPrefix, nil
+}
+
+// TieredDataFileSetExistsAt determines whether data fileset files exist for the
+// given namespace, shard, and block start in either the hot or warm prefix.
+func TieredDataFileSetExistsAt(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+) (bool, error) {
+	exists, err := DataFileSetExistsAt(hotFilePathPrefix, namespace, shard, blockStart)
+	if err != nil || exists || warmFilePathPrefix == "" {
+		return exists, err
+	}
+	return DataFileSetExistsAt(warmFilePathPrefix, namespace, shard, blockStart)
+}
+
+// ReadTieredInfoFiles reads all the valid info entries across the hot and warm
+// file path prefixes, entries in the hot prefix take precedence over entries
+// for the same block start in the warm prefix.
+func ReadTieredInfoFiles(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	readerBufferSize int,
+	decodingOpts msgpack.DecodingOptions,
+) []ReadInfoFileResult {
+	results := ReadInfoFiles(hotFilePathPrefix, namespace, shard,
+		readerBufferSize, decodingOpts)
+	if warmFilePathPrefix == "" {
+		return results
+	}
+
+	hotBlockStarts := make(map[int64]struct{}, len(results))
+	for _, result := range results {
+		if result.Err.Error() == nil {
+			hotBlockStarts[result.Info.BlockStart] = struct{}{}
+		}
+	}
+
+	warmResults := ReadInfoFiles(warmFilePathPrefix, namespace, shard,
+		readerBufferSize, decodingOpts)
+	for _, result := range warmResults {
+		if result.Err.Error() == nil {
+			if _, ok := hotBlockStarts[result.Info.BlockStart]; ok {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// MoveDataFileSetToWarm moves the complete data fileset for the given namespace,
+// shard and block start from the hot file path prefix to the warm file path
+// prefix. Files are copied rather than renamed as the prefixes are expected to
+// reside on different devices, the checkpoint file is copied last and removed
+// from the hot prefix first so that a complete fileset is always visible in at
+// least one of the prefixes.
+func MoveDataFileSetToWarm(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error {
+	fileset, ok, err := FileSetAt(hotFilePathPrefix, namespace, shard, blockStart)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("fileset for blockStart: %d does not exist", blockStart.Unix())
+	}
+	return moveDataFileSetToWarm(hotFilePathPrefix, warmFilePathPrefix,
+		fileset, newFileMode, newDirectoryMode)
+}
+
+// MoveDataFileSetsToWarmBefore moves all the complete data filesets for the
+// given namespace and shard whose block start is earlier than the given time
+// from the hot file path prefix to the warm file path prefix, returning the
+// number of filesets moved.
+func MoveDataFileSetsToWarmBefore(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	t time.Time,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) (int, error) {
+	filesets, err := filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetDataContentType,
+		filePathPrefix: hotFilePathPrefix,
+		namespace:      namespace,
+		shard:          shard,
+		pattern:        filesetFilePattern,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		moved    int
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, fileset := range filesets {
+		if !fileset.ID.BlockStart.Before(t) || !fileset.HasCheckpointFile() {
+			continue
+		}
+		if err := moveDataFileSetToWarm(hotFilePathPrefix, warmFilePathPrefix,
+			fileset, newFileMode, newDirectoryMode); err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		moved++
+	}
+	return moved, multiErr.FinalError()
+}
+
+func moveDataFileSetToWarm(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	fileset FileSetFile,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error {
+	var (
+		namespace  = fileset.ID.Namespace
+		shard      = fileset.ID.Shard
+		blockStart = fileset.ID.BlockStart
+	)
+	warmShardDir := ShardDataDirPath(warmFilePathPrefix, namespace, shard)
+	if err := os.MkdirAll(warmShardDir, newDirectoryMode); err != nil {
+		return err
+	}
+
+	var (
+		hotCheckpointPath = filesetPathFromTime(
+			ShardDataDirPath(hotFilePathPrefix, namespace, shard),
+			blockStart, checkpointFileSuffix)
+		remaining = make([]string, 0, len(fileset.AbsoluteFilepaths))
+	)
+	for _, filePath := range fileset.AbsoluteFilepaths {
+		if filePath == hotCheckpointPath {
+			continue
+		}
+		remaining = append(remaining, filePath)
+		dst := filepath.Join(warmShardDir, filepath.Base(filePath))
+		if err := copyFileSync(filePath, dst, newFileMode); err != nil {
+			return err
+		}
+	}
+
+	warmCheckpointPath := filepath.Join(warmShardDir, filepath.Base(hotCheckpointPath))
+	if err := copyFileSync(hotCheckpointPath, warmCheckpointPath, newFileMode); err != nil {
+		return err
+	}
+	if err := os.Remove(hotCheckpointPath); err != nil {
+		return err
+	}
+	return DeleteFiles(remaining)
+}
+
+// copyFileSync copies the file at src to dst and syncs dst before returning.
+func copyFileSync(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := OpenWritable(dst, perm)
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	if _, err := io.Copy(out, in); err != nil {
+		multiErr = multiErr.Add(err)
+	} else if err := out.Sync(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	multiErr = multiErr.Add(out.Close())
+	return multiErr.FinalError()
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveDataFileSetToWarmAndRead(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		hotFilePathPrefix  = filepath.Join(dir, "hot")
+		warmFilePathPrefix = filepath.Join(dir, "warm")
+		warmBlockStart     = testWriterStart
+		hotBlockStart      = testWriterStart.Add(testBlockSize)
+		entries            = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", nil, []byte{4, 5, 6}},
+		}
+	)
+
+	w := newTestWriter(t, hotFilePathPrefix)
+	writeTestData(t, w, 0, warmBlockStart, entries, persist.FileSetFlushType)
+	writeTestData(t, w, 0, hotBlockStart, entries, persist.FileSetFlushType)
+
+	moved, err := MoveDataFileSetsToWarmBefore(hotFilePathPrefix, warmFilePathPrefix,
+		testNs1ID, 0, hotBlockStart, defaultNewFileMode, defaultNewDirectoryMode)
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+
+	exists, err := DataFileSetExistsAt(hotFilePathPrefix, testNs1ID, 0, warmBlockStart)
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = DataFileSetExistsAt(warmFilePathPrefix, testNs1ID, 0, warmBlockStart)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	prefix, err := DataFileSetPathPrefix(hotFilePathPrefix, warmFilePathPrefix,
+		testNs1ID, 0, warmBlockStart)
+	require.NoError(t, err)
+	require.Equal(t, warmFilePathPrefix, prefix)
+	prefix, err = DataFileSetPathPrefix(hotFilePathPrefix, warmFilePathPrefix,
+		testNs1ID, 0, hotBlockStart)
+	require.NoError(t, err)
+	require.Equal(t, hotFilePathPrefix, prefix)
+
+	results := ReadTieredInfoFiles(hotFilePathPrefix, warmFilePathPrefix,
+		testNs1ID, 0, testReaderBufferSize, testDefaultOpts.DecodingOptions())
+	require.Equal(t, 2, len(results))
+	for _, result := range results {
+		require.NoError(t, result.Err.Error())
+	}
+
+	reader, err := NewReader(testBytesPool, testDefaultOpts.
+		SetFilePathPrefix(hotFilePathPrefix).
+		SetWarmFilePathPrefix(warmFilePathPrefix).
+		SetInfoReaderBufferSize(testReaderBufferSize).
+		SetDataReaderBufferSize(testReaderBufferSize))
+	require.NoError(t, err)
+	readTestData(t, reader, 0, warmBlockStart, entries)
+	readTestData(t, reader, 0, hotBlockStart, entries)
+}
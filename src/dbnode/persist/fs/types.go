@@ -32,6 +32,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/serialize"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
@@ -59,6 +62,9 @@ type DataWriterOpenOptions struct {
 	FileSetContentType persist.FileSetContentType
 	Identifier         FileSetFileIdentifier
 	BlockSize          time.Duration
+	// SummariesPercent overrides the percent of index entries written to the
+	// summaries file when greater than zero
+	SummariesPercent float64
 	// Only used when writing snapshot files
 	Snapshot DataWriterSnapshotOptions
 }
@@ -335,6 +341,18 @@ type Options interface {
 	// RuntimeOptionsManager returns the runtime options manager
 	RuntimeOptionsManager() runtime.OptionsManager
 
+	// SetFaultInjector sets the fault injector used when writing files
+	SetFaultInjector(value fault.Injector) Options
+
+	// FaultInjector returns the fault injector used when writing files
+	FaultInjector() fault.Injector
+
+	// SetWriteAmplificationRecorder sets the recorder of bytes written to disk
+	SetWriteAmplificationRecorder(value writeamp.Recorder) Options
+
+	// WriteAmplificationRecorder returns the recorder of bytes written to disk
+	WriteAmplificationRecorder() writeamp.Recorder
+
 	// SetDecodingOptions sets the decoding options
 	SetDecodingOptions(value msgpack.DecodingOptions) Options
 
@@ -347,6 +365,22 @@ type Options interface {
 	// FilePathPrefix returns the file path prefix for sharded TSDB files
 	FilePathPrefix() string
 
+	// SetWarmFilePathPrefix sets the file path prefix that older data filesets
+	// are moved to, an empty prefix disables the warm tier
+	SetWarmFilePathPrefix(value string) Options
+
+	// WarmFilePathPrefix returns the file path prefix that older data filesets
+	// are moved to, an empty prefix disables the warm tier
+	WarmFilePathPrefix() string
+
+	// SetWarmTierAfter sets how long after a block ends its data fileset
+	// is moved to the warm tier
+	SetWarmTierAfter(value time.Duration) Options
+
+	// WarmTierAfter returns how long after a block ends its data fileset
+	// is moved to the warm tier
+	WarmTierAfter() time.Duration
+
 	// SetNewFileMode sets the new file mode
 	SetNewFileMode(value os.FileMode) Options
 
@@ -379,6 +413,14 @@ type Options interface {
 	// WriterBufferSize returns the buffer size for writing TSDB files
 	WriterBufferSize() int
 
+	// SetDirectIOWritesEnabled sets whether data filesets are written with
+	// direct I/O, bypassing the page cache
+	SetDirectIOWritesEnabled(value bool) Options
+
+	// DirectIOWritesEnabled returns whether data filesets are written with
+	// direct I/O, bypassing the page cache
+	DirectIOWritesEnabled() bool
+
 	// SetInfoReaderBufferSize sets the buffer size for reading TSDB info, digest and checkpoint files
 	SetInfoReaderBufferSize(value int) Options
 
@@ -459,4 +501,11 @@ type BlockRetrieverOptions interface {
 
 	// IdentifierPool returns the identifierPool
 	IdentifierPool() ident.Pool
+
+	// SetFetchCPUSets sets the CPU sets the fetch loops are pinned to in
+	// turn, fetch loops are not pinned if empty
+	SetFetchCPUSets(value []cpuset.CPUSet) BlockRetrieverOptions
+
+	// FetchCPUSets returns the CPU sets the fetch loops are pinned to in turn
+	FetchCPUSets() []cpuset.CPUSet
 }
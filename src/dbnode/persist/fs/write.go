@@ -35,6 +35,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/dbnode/serialize"
+	"github.com/m3db/m3/src/dbnode/x/directio"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3x/checked"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
@@ -50,7 +53,9 @@ type writer struct {
 	filePathPrefix   string
 	newFileMode      os.FileMode
 	newDirectoryMode os.FileMode
+	directIO         bool
 
+	defaultSummariesPercent         float64
 	summariesPercent                float64
 	bloomFilterFalsePositivePercent float64
 
@@ -61,6 +66,7 @@ type writer struct {
 	dataFdWithDigest           digest.FdWithDigestWriter
 	digestFdWithDigestContents digest.FdWithDigestContentsWriter
 	checkpointFilePath         string
+	filePaths                  []string
 	indexEntries               indexEntries
 
 	start              time.Time
@@ -71,6 +77,9 @@ type writer struct {
 	digestBuf          digest.Buffer
 	singleCheckedBytes []checked.Bytes
 	tagEncoderPool     serialize.TagEncoderPool
+	faultInjector      fault.Injector
+	writeAmpRecorder   writeamp.Recorder
+	writeAmpSource     writeamp.Source
 	err                error
 }
 
@@ -109,23 +118,35 @@ func NewWriter(opts Options) (DataFileSetWriter, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
-	bufferSize := opts.WriterBufferSize()
+	var (
+		bufferSize              = opts.WriterBufferSize()
+		directIO                = opts.DirectIOWritesEnabled()
+		newFdWithDigest         = digest.NewFdWithDigestWriter
+		newFdWithDigestContents = digest.NewFdWithDigestContentsWriter
+	)
+	if directIO {
+		newFdWithDigest = digest.NewFdWithDigestDirectWriter
+		newFdWithDigestContents = digest.NewFdWithDigestContentsDirectWriter
+	}
 	return &writer{
 		filePathPrefix:                  opts.FilePathPrefix(),
 		newFileMode:                     opts.NewFileMode(),
 		newDirectoryMode:                opts.NewDirectoryMode(),
-		summariesPercent:                opts.IndexSummariesPercent(),
+		directIO:                        directIO,
+		defaultSummariesPercent:         opts.IndexSummariesPercent(),
 		bloomFilterFalsePositivePercent: opts.IndexBloomFilterFalsePositivePercent(),
-		infoFdWithDigest:                digest.NewFdWithDigestWriter(bufferSize),
-		indexFdWithDigest:               digest.NewFdWithDigestWriter(bufferSize),
-		summariesFdWithDigest:           digest.NewFdWithDigestWriter(bufferSize),
-		bloomFilterFdWithDigest:         digest.NewFdWithDigestWriter(bufferSize),
-		dataFdWithDigest:                digest.NewFdWithDigestWriter(bufferSize),
-		digestFdWithDigestContents:      digest.NewFdWithDigestContentsWriter(bufferSize),
+		infoFdWithDigest:                newFdWithDigest(bufferSize),
+		indexFdWithDigest:               newFdWithDigest(bufferSize),
+		summariesFdWithDigest:           newFdWithDigest(bufferSize),
+		bloomFilterFdWithDigest:         newFdWithDigest(bufferSize),
+		dataFdWithDigest:                newFdWithDigest(bufferSize),
+		digestFdWithDigestContents:      newFdWithDigestContents(bufferSize),
 		encoder:                         msgpack.NewEncoder(),
 		digestBuf:                       digest.NewBuffer(),
 		singleCheckedBytes:              make([]checked.Bytes, 1),
 		tagEncoderPool:                  opts.TagEncoderPool(),
+		faultInjector:                   opts.FaultInjector(),
+		writeAmpRecorder:                opts.WriteAmplificationRecorder(),
 	}, nil
 }
 
@@ -144,6 +165,10 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 	w.blockSize = opts.BlockSize
 	w.start = blockStart
 	w.snapshotTime = opts.Snapshot.SnapshotTime
+	w.summariesPercent = w.defaultSummariesPercent
+	if opts.SummariesPercent > 0 {
+		w.summariesPercent = opts.SummariesPercent
+	}
 	w.currIdx = 0
 	w.currOffset = 0
 	w.err = nil
@@ -174,6 +199,7 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 		bloomFilterFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, nextSnapshotIndex, bloomFilterFileSuffix)
 		dataFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, nextSnapshotIndex, dataFileSuffix)
 		digestFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, nextSnapshotIndex, digestFileSuffix)
+		w.writeAmpSource = writeamp.Snapshot
 	case persist.FileSetFlushType:
 		shardDir = ShardDataDirPath(w.filePathPrefix, namespace, shard)
 		if err := os.MkdirAll(shardDir, w.newDirectoryMode); err != nil {
@@ -187,12 +213,13 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 		bloomFilterFilepath = filesetPathFromTime(shardDir, blockStart, bloomFilterFileSuffix)
 		dataFilepath = filesetPathFromTime(shardDir, blockStart, dataFileSuffix)
 		digestFilepath = filesetPathFromTime(shardDir, blockStart, digestFileSuffix)
+		w.writeAmpSource = writeamp.Flush
 	default:
 		return fmt.Errorf("unable to open reader with fileset type: %s", opts.FileSetType)
 	}
 
 	var infoFd, indexFd, summariesFd, bloomFilterFd, dataFd, digestFd *os.File
-	err = openFiles(w.openWritable,
+	err = openFiles(w.openFileSetWritable,
 		map[string]**os.File{
 			infoFilepath:        &infoFd,
 			indexFilepath:       &indexFd,
@@ -206,6 +233,10 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 		return err
 	}
 
+	w.filePaths = append(w.filePaths[:0], infoFilepath, indexFilepath,
+		summariesFilepath, bloomFilterFilepath, dataFilepath, digestFilepath,
+		w.checkpointFilePath)
+
 	w.infoFdWithDigest.Reset(infoFd)
 	w.indexFdWithDigest.Reset(indexFd)
 	w.summariesFdWithDigest.Reset(summariesFd)
@@ -296,6 +327,13 @@ func (w *writer) writeAll(
 }
 
 func (w *writer) Close() error {
+	res := w.faultInjector.Inject(fault.FilesetFlush)
+	if res.Err != nil && !res.Partial {
+		w.abort()
+		w.err = res.Err
+		return res.Err
+	}
+
 	err := w.close()
 	if w.err != nil {
 		return w.err
@@ -304,12 +342,19 @@ func (w *writer) Close() error {
 		w.err = err
 		return err
 	}
+	if res.Err != nil {
+		// NB: the data and index files have been written but the checkpoint
+		// file is not to leave behind an incomplete fileset.
+		w.err = res.Err
+		return res.Err
+	}
 	// NB(xichen): only write out the checkpoint file if there are no errors
 	// encountered between calling writer.Open() and writer.Close().
 	if err := w.writeCheckpointFile(); err != nil {
 		w.err = err
 		return err
 	}
+	w.writeAmpRecorder.RecordWritten(w.writeAmpSource, filesSize(w.filePaths))
 	return nil
 }
 
@@ -338,6 +383,20 @@ func (w *writer) close() error {
 	)
 }
 
+// abort closes the files without writing out the index related files.
+func (w *writer) abort() {
+	w.indexEntries.releaseRefs()
+	w.indexEntries = w.indexEntries[:0]
+	closeAll(
+		w.infoFdWithDigest,
+		w.indexFdWithDigest,
+		w.summariesFdWithDigest,
+		w.bloomFilterFdWithDigest,
+		w.dataFdWithDigest,
+		w.digestFdWithDigestContents,
+	)
+}
+
 func (w *writer) writeCheckpointFile() error {
 	fd, err := w.openWritable(w.checkpointFilePath)
 	if err != nil {
@@ -357,6 +416,17 @@ func (w *writer) openWritable(filePath string) (*os.File, error) {
 	return OpenWritable(filePath, w.newFileMode)
 }
 
+// openFileSetWritable opens the files written through the digest writers,
+// with direct I/O if enabled. The checkpoint file is always written with
+// buffered I/O since it is written directly rather than by a digest writer.
+func (w *writer) openFileSetWritable(filePath string) (*os.File, error) {
+	if w.directIO {
+		return directio.OpenFile(filePath,
+			os.O_WRONLY|os.O_CREATE|os.O_TRUNC, w.newFileMode)
+	}
+	return w.openWritable(filePath)
+}
+
 func (w *writer) writeIndexRelatedFiles() error {
 	summariesApprox := float64(len(w.indexEntries)) * w.summariesPercent
 	summaryEvery := 0
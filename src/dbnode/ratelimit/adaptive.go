@@ -0,0 +1,126 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// adaptiveSampleInterval is how often the disk is sampled to adapt the
+	// limit, shorter intervals make the average latency noisy.
+	adaptiveSampleInterval = time.Second
+
+	// adaptiveIncreaseFraction is the fraction of the ceiling the limit is
+	// raised by every sample that reads are not starved.
+	adaptiveIncreaseFraction = 0.1
+)
+
+// DiskStats are the cumulative IO statistics of a disk.
+type DiskStats struct {
+	ReadsCompleted uint64
+	ReadTime       time.Duration
+	IOTime         time.Duration
+}
+
+// DiskStatsFn returns the current cumulative IO statistics of a disk.
+type DiskStatsFn func() (DiskStats, error)
+
+// AdaptiveLimiter adapts a throughput limit to disk feedback, it halves the
+// limit, down to a floor, whenever reads are starved by the disk being too
+// busy or reads being too slow and otherwise raises it back towards the
+// ceiling, i.e. the configured limit. It is not thread-safe.
+type AdaptiveLimiter struct {
+	statsFn DiskStatsFn
+
+	limitMbps  float64
+	lastSample time.Time
+	lastStats  DiskStats
+	hasStats   bool
+}
+
+// NewAdaptiveLimiter returns a new adaptive limiter sampling the disk with
+// the given function.
+func NewAdaptiveLimiter(statsFn DiskStatsFn) *AdaptiveLimiter {
+	return &AdaptiveLimiter{statsFn: statsFn}
+}
+
+// LimitMbps returns the limit to apply at the given time, sampling the disk
+// if the sample interval has elapsed since the last sample.
+func (l *AdaptiveLimiter) LimitMbps(opts Options, now time.Time) float64 {
+	ceiling := opts.LimitMbps()
+	if !opts.AdaptiveEnabled() {
+		return ceiling
+	}
+	floor := math.Min(opts.AdaptiveMinLimitMbps(), ceiling)
+	if l.limitMbps == 0 {
+		l.limitMbps = ceiling
+	}
+
+	if now.Sub(l.lastSample) >= adaptiveSampleInterval {
+		l.sample(opts, now, ceiling)
+	}
+
+	l.limitMbps = math.Max(floor, math.Min(l.limitMbps, ceiling))
+	return l.limitMbps
+}
+
+func (l *AdaptiveLimiter) sample(opts Options, now time.Time, ceiling float64) {
+	stats, err := l.statsFn()
+	if err != nil {
+		// Keep the current limit if the disk cannot be sampled.
+		return
+	}
+
+	prevSample, prevStats, hasStats := l.lastSample, l.lastStats, l.hasStats
+	l.lastSample, l.lastStats, l.hasStats = now, stats, true
+	if !hasStats || stats.ReadsCompleted < prevStats.ReadsCompleted {
+		return
+	}
+
+	reads := stats.ReadsCompleted - prevStats.ReadsCompleted
+	if reads > 0 && l.starved(opts, stats, prevStats, reads, now.Sub(prevSample)) {
+		l.limitMbps /= 2
+		return
+	}
+	l.limitMbps += ceiling * adaptiveIncreaseFraction
+}
+
+func (l *AdaptiveLimiter) starved(
+	opts Options,
+	stats, prevStats DiskStats,
+	reads uint64,
+	elapsed time.Duration,
+) bool {
+	if elapsed <= 0 {
+		return false
+	}
+	utilization := float64(stats.IOTime-prevStats.IOTime) / float64(elapsed)
+	if utilization >= opts.AdaptiveMaxDiskUtilization() {
+		return true
+	}
+	maxLatency := opts.AdaptiveMaxReadLatency()
+	if maxLatency <= 0 {
+		return false
+	}
+	latency := (stats.ReadTime - prevStats.ReadTime) / time.Duration(reads)
+	return latency >= maxLatency
+}
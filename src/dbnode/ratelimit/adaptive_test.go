@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveLimiterDisabled(t *testing.T) {
+	l := NewAdaptiveLimiter(func() (DiskStats, error) {
+		t.Fatal("should not sample the disk")
+		return DiskStats{}, nil
+	})
+	opts := NewOptions().SetLimitMbps(100)
+	assert.Equal(t, 100.0, l.LimitMbps(opts, time.Now()))
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	var stats DiskStats
+	l := NewAdaptiveLimiter(func() (DiskStats, error) { return stats, nil })
+	opts := NewOptions().
+		SetLimitMbps(100).
+		SetAdaptiveEnabled(true).
+		SetAdaptiveMinLimitMbps(20).
+		SetAdaptiveMaxDiskUtilization(0.9).
+		SetAdaptiveMaxReadLatency(10 * time.Millisecond)
+
+	now := time.Now()
+	assert.Equal(t, 100.0, l.LimitMbps(opts, now))
+
+	// Reads starved by latency halve the limit.
+	stats.ReadsCompleted += 10
+	stats.ReadTime += 200 * time.Millisecond
+	stats.IOTime += 500 * time.Millisecond
+	now = now.Add(time.Second)
+	assert.Equal(t, 50.0, l.LimitMbps(opts, now))
+
+	// Not sampled again until the sample interval has elapsed.
+	stats.ReadsCompleted += 10
+	stats.IOTime += time.Second
+	assert.Equal(t, 50.0, l.LimitMbps(opts, now.Add(time.Millisecond)))
+
+	// Reads starved by utilization halve the limit down to the floor.
+	now = now.Add(time.Second)
+	assert.Equal(t, 25.0, l.LimitMbps(opts, now))
+	stats.ReadsCompleted += 10
+	stats.IOTime += time.Second
+	now = now.Add(time.Second)
+	assert.Equal(t, 20.0, l.LimitMbps(opts, now))
+
+	// No reads, or fast reads on an idle disk, raise the limit.
+	stats.IOTime += time.Second
+	now = now.Add(time.Second)
+	assert.Equal(t, 30.0, l.LimitMbps(opts, now))
+	stats.ReadsCompleted += 10
+	stats.ReadTime += 10 * time.Millisecond
+	stats.IOTime += 100 * time.Millisecond
+	now = now.Add(time.Second)
+	assert.Equal(t, 40.0, l.LimitMbps(opts, now))
+
+	// The limit never exceeds the ceiling.
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Second)
+		l.LimitMbps(opts, now)
+	}
+	assert.Equal(t, 100.0, l.LimitMbps(opts, now))
+}
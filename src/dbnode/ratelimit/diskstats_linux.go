@@ -0,0 +1,102 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	procDiskStatsPath = "/proc/diskstats"
+
+	// Fields of /proc/diskstats, see the kernel's iostats documentation.
+	diskStatsMajorField          = 0
+	diskStatsMinorField          = 1
+	diskStatsReadsCompletedField = 3
+	diskStatsReadTimeMsField     = 6
+	diskStatsIOTimeMsField       = 12
+	diskStatsMinFields           = 14
+)
+
+// NewDiskStatsFn returns a function that reads the IO statistics of the
+// disk, or partition, the given path resides on from /proc/diskstats.
+func NewDiskStatsFn(path string) (DiskStatsFn, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return nil, err
+	}
+	dev := uint64(st.Dev)
+	major := (dev>>8)&0xfff | (dev>>32)&^0xfff
+	minor := dev&0xff | (dev>>12)&^0xff
+	return func() (DiskStats, error) {
+		return readDiskStats(procDiskStatsPath, major, minor)
+	}, nil
+}
+
+func readDiskStats(path string, major, minor uint64) (DiskStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return DiskStats{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < diskStatsMinFields {
+			continue
+		}
+		if fields[diskStatsMajorField] != strconv.FormatUint(major, 10) ||
+			fields[diskStatsMinorField] != strconv.FormatUint(minor, 10) {
+			continue
+		}
+		return parseDiskStats(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		return DiskStats{}, err
+	}
+	return DiskStats{}, fmt.Errorf("no disk stats for device %d:%d", major, minor)
+}
+
+func parseDiskStats(fields []string) (DiskStats, error) {
+	var values [3]uint64
+	for i, field := range []int{
+		diskStatsReadsCompletedField,
+		diskStatsReadTimeMsField,
+		diskStatsIOTimeMsField,
+	} {
+		v, err := strconv.ParseUint(fields[field], 10, 64)
+		if err != nil {
+			return DiskStats{}, fmt.Errorf("invalid disk stats field %d: %v", field, err)
+		}
+		values[i] = v
+	}
+	return DiskStats{
+		ReadsCompleted: values[0],
+		ReadTime:       time.Duration(values[1]) * time.Millisecond,
+		IOTime:         time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
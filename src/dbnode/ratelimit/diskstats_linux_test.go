@@ -0,0 +1,57 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDiskStats(t *testing.T) {
+	f, err := ioutil.TempFile("", "diskstats")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(
+		"   8       0 sda 1000 10 8000 4000 2000 20 16000 6000 0 9000 10000\n" +
+			"   8       1 sda1 500 5 4000 2500 1000 10 8000 3000 0 7000 5500 0 0 0 0\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	stats, err := readDiskStats(f.Name(), 8, 0)
+	require.NoError(t, err)
+	assert.Equal(t, DiskStats{
+		ReadsCompleted: 1000,
+		ReadTime:       4 * time.Second,
+		IOTime:         9 * time.Second,
+	}, stats)
+
+	stats, err = readDiskStats(f.Name(), 8, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(500), stats.ReadsCompleted)
+
+	_, err = readDiskStats(f.Name(), 8, 2)
+	require.Error(t, err)
+}
@@ -20,6 +20,10 @@
 
 package ratelimit
 
+import (
+	"time"
+)
+
 const (
 	// defaultLimitEnabled determines whether rate limiting is enabled
 	defaultLimitEnabled = false
@@ -30,12 +34,27 @@ const (
 
 	// defaultLimitMbps is the default limit in Mb/s
 	defaultLimitMbps = 100.0
+
+	// defaultAdaptiveMinLimitMbps is the default floor of the adaptive limit
+	defaultAdaptiveMinLimitMbps = 10.0
+
+	// defaultAdaptiveMaxDiskUtilization is the default disk utilization
+	// above which reads are considered starved
+	defaultAdaptiveMaxDiskUtilization = 0.9
+
+	// defaultAdaptiveMaxReadLatency is the default average read latency
+	// above which reads are considered starved
+	defaultAdaptiveMaxReadLatency = 20 * time.Millisecond
 )
 
 type options struct {
-	limitEnabled    bool
-	limitMbps       float64
-	limitCheckEvery int
+	limitEnabled               bool
+	limitMbps                  float64
+	limitCheckEvery            int
+	adaptiveEnabled            bool
+	adaptiveMinLimitMbps       float64
+	adaptiveMaxDiskUtilization float64
+	adaptiveMaxReadLatency     time.Duration
 }
 
 // NewOptions creates a new rate limit options
@@ -44,6 +63,10 @@ func NewOptions() Options {
 		limitEnabled:    defaultLimitEnabled,
 		limitMbps:       defaultLimitMbps,
 		limitCheckEvery: defaultLimitCheckEvery,
+
+		adaptiveMinLimitMbps:       defaultAdaptiveMinLimitMbps,
+		adaptiveMaxDiskUtilization: defaultAdaptiveMaxDiskUtilization,
+		adaptiveMaxReadLatency:     defaultAdaptiveMaxReadLatency,
 	}
 }
 
@@ -76,3 +99,43 @@ func (o *options) SetLimitCheckEvery(value int) Options {
 func (o *options) LimitCheckEvery() int {
 	return o.limitCheckEvery
 }
+
+func (o *options) SetAdaptiveEnabled(value bool) Options {
+	opts := *o
+	opts.adaptiveEnabled = value
+	return &opts
+}
+
+func (o *options) AdaptiveEnabled() bool {
+	return o.adaptiveEnabled
+}
+
+func (o *options) SetAdaptiveMinLimitMbps(value float64) Options {
+	opts := *o
+	opts.adaptiveMinLimitMbps = value
+	return &opts
+}
+
+func (o *options) AdaptiveMinLimitMbps() float64 {
+	return o.adaptiveMinLimitMbps
+}
+
+func (o *options) SetAdaptiveMaxDiskUtilization(value float64) Options {
+	opts := *o
+	opts.adaptiveMaxDiskUtilization = value
+	return &opts
+}
+
+func (o *options) AdaptiveMaxDiskUtilization() float64 {
+	return o.adaptiveMaxDiskUtilization
+}
+
+func (o *options) SetAdaptiveMaxReadLatency(value time.Duration) Options {
+	opts := *o
+	opts.adaptiveMaxReadLatency = value
+	return &opts
+}
+
+func (o *options) AdaptiveMaxReadLatency() time.Duration {
+	return o.adaptiveMaxReadLatency
+}
@@ -20,6 +20,10 @@
 
 package ratelimit
 
+import (
+	"time"
+)
+
 // Options provides options for rate limiting
 type Options interface {
 	// SetLimitEnabled determines whether rate limiting is enabled
@@ -39,4 +43,34 @@ type Options interface {
 
 	// LimitCheckEvery returns the limit check frequency
 	LimitCheckEvery() int
+
+	// SetAdaptiveEnabled determines whether the limit adapts to disk
+	// utilization and read latency, in which case the limit is a ceiling
+	SetAdaptiveEnabled(value bool) Options
+
+	// AdaptiveEnabled returns whether the limit adapts to disk utilization
+	// and read latency
+	AdaptiveEnabled() bool
+
+	// SetAdaptiveMinLimitMbps sets the floor of the adaptive limit
+	SetAdaptiveMinLimitMbps(value float64) Options
+
+	// AdaptiveMinLimitMbps returns the floor of the adaptive limit
+	AdaptiveMinLimitMbps() float64
+
+	// SetAdaptiveMaxDiskUtilization sets the disk utilization, between 0
+	// and 1, above which reads are considered starved
+	SetAdaptiveMaxDiskUtilization(value float64) Options
+
+	// AdaptiveMaxDiskUtilization returns the disk utilization above which
+	// reads are considered starved
+	AdaptiveMaxDiskUtilization() float64
+
+	// SetAdaptiveMaxReadLatency sets the average read latency above which
+	// reads are considered starved, zero disables the latency check
+	SetAdaptiveMaxReadLatency(value time.Duration) Options
+
+	// AdaptiveMaxReadLatency returns the average read latency above which
+	// reads are considered starved
+	AdaptiveMaxReadLatency() time.Duration
 }
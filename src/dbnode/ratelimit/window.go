@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	day             = 24 * time.Hour
+	timeOfDayLayout = "15:04"
+)
+
+var (
+	errWindowOutOfRange = errors.New("window start and end must be within a day")
+	errWindowEmpty      = errors.New("window start and end must differ")
+)
+
+// TimeOfDayWindow is a daily window of time, the start and end are offsets
+// from midnight in the window's location, a window whose end is before its
+// start wraps past midnight.
+type TimeOfDayWindow struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// ParseTimeOfDayWindow parses a window of the form "HH:MM-HH:MM", e.g.
+// "22:00-06:00" for a window from 10pm to 6am, in the given location.
+func ParseTimeOfDayWindow(value string, loc *time.Location) (TimeOfDayWindow, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 {
+		return TimeOfDayWindow{}, fmt.Errorf(
+			"invalid window %s: expected format HH:MM-HH:MM", value)
+	}
+
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse(timeOfDayLayout, strings.TrimSpace(part))
+		if err != nil {
+			return TimeOfDayWindow{}, fmt.Errorf("invalid window %s: %v", value, err)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour +
+			time.Duration(t.Minute())*time.Minute
+	}
+
+	w := TimeOfDayWindow{Start: offsets[0], End: offsets[1], Location: loc}
+	if err := w.Validate(); err != nil {
+		return TimeOfDayWindow{}, err
+	}
+	return w, nil
+}
+
+// Validate validates the window.
+func (w TimeOfDayWindow) Validate() error {
+	if w.Start < 0 || w.Start >= day || w.End < 0 || w.End >= day {
+		return errWindowOutOfRange
+	}
+	if w.Start == w.End {
+		return errWindowEmpty
+	}
+	return nil
+}
+
+// Contains returns whether the time falls within the window.
+func (w TimeOfDayWindow) Contains(t time.Time) bool {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	offset := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	// Window wraps past midnight.
+	return offset >= w.Start || offset < w.End
+}
+
+func (w TimeOfDayWindow) String() string {
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return midnight.Add(w.Start).Format(timeOfDayLayout) + "-" +
+		midnight.Add(w.End).Format(timeOfDayLayout)
+}
+
+// TimeOfDayWindows is a set of daily windows of time.
+type TimeOfDayWindows []TimeOfDayWindow
+
+// Contains returns whether the time falls within any of the windows, an
+// empty set of windows contains all times.
+func (w TimeOfDayWindows) Contains(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, window := range w {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates each of the windows.
+func (w TimeOfDayWindows) Validate() error {
+	for _, window := range w {
+		if err := window.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeOfDayWindow(t *testing.T) {
+	w, err := ParseTimeOfDayWindow("22:00-06:30", time.UTC)
+	require.NoError(t, err)
+	require.Equal(t, 22*time.Hour, w.Start)
+	require.Equal(t, 6*time.Hour+30*time.Minute, w.End)
+	require.Equal(t, "22:00-06:30", w.String())
+
+	for _, invalid := range []string{"", "22:00", "22:00-22:00", "25:00-01:00", "a-b"} {
+		_, err := ParseTimeOfDayWindow(invalid, time.UTC)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestTimeOfDayWindowContains(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2018, time.June, 1, hour, min, 0, 0, time.UTC)
+	}
+
+	business, err := ParseTimeOfDayWindow("09:00-17:00", time.UTC)
+	require.NoError(t, err)
+	require.True(t, business.Contains(at(9, 0)))
+	require.True(t, business.Contains(at(16, 59)))
+	require.False(t, business.Contains(at(17, 0)))
+	require.False(t, business.Contains(at(3, 0)))
+
+	overnight, err := ParseTimeOfDayWindow("22:00-06:00", time.UTC)
+	require.NoError(t, err)
+	require.True(t, overnight.Contains(at(23, 0)))
+	require.True(t, overnight.Contains(at(5, 59)))
+	require.False(t, overnight.Contains(at(12, 0)))
+
+	est := time.FixedZone("EST", -5*3600)
+	local, err := ParseTimeOfDayWindow("22:00-06:00", est)
+	require.NoError(t, err)
+	require.True(t, local.Contains(at(3, 0)))
+	require.False(t, local.Contains(at(23, 0)))
+
+	require.True(t, TimeOfDayWindows(nil).Contains(at(12, 0)))
+	require.True(t, TimeOfDayWindows{business, overnight}.Contains(at(23, 0)))
+	require.False(t, TimeOfDayWindows{business, overnight}.Contains(at(20, 0)))
+}
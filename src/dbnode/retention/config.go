@@ -21,6 +21,7 @@
 package retention
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -32,10 +33,20 @@ type Configuration struct {
 	BufferPast                            time.Duration  `yaml:"bufferPast" validate:"nonzero"`
 	BlockDataExpiry                       *bool          `yaml:"blockDataExpiry"`
 	BlockDataExpiryAfterNotAccessedPeriod *time.Duration `yaml:"blockDataExpiryAfterNotAccessedPeriod"`
+	TimeZone                              string         `yaml:"timeZone"`
 }
 
-// Options returns `Options` corresponding to the provided struct values
-func (c *Configuration) Options() Options {
+// Location returns the time zone location, nil if no time zone is set
+func (c *Configuration) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(c.TimeZone)
+}
+
+// Options returns `Options` corresponding to the provided struct values,
+// returning an error if the time zone cannot be loaded
+func (c *Configuration) Options() (Options, error) {
 	opts := NewOptions().
 		SetRetentionPeriod(c.RetentionPeriod).
 		SetBlockSize(c.BlockSize).
@@ -47,5 +58,12 @@ func (c *Configuration) Options() Options {
 	if v := c.BlockDataExpiryAfterNotAccessedPeriod; v != nil {
 		opts = opts.SetBlockDataExpiryAfterNotAccessedPeriod(*v)
 	}
-	return opts
+	tz, err := c.Location()
+	if err != nil {
+		return nil, fmt.Errorf("invalid retention time zone %q: %v", c.TimeZone, err)
+	}
+	if tz != nil {
+		opts = opts.SetTimeZone(tz)
+	}
+	return opts, nil
 }
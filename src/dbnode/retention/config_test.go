@@ -45,7 +45,8 @@ func TestConfigurationAssignment(t *testing.T) {
 		}
 	)
 
-	opts := config.Options()
+	opts, err := config.Options()
+	require.NoError(t, err)
 	require.Equal(t, retentionPeriod, opts.RetentionPeriod())
 	require.Equal(t, blockSize, opts.BlockSize())
 	require.Equal(t, bufferFuture, opts.BufferFuture())
@@ -53,3 +54,21 @@ func TestConfigurationAssignment(t *testing.T) {
 	require.Equal(t, blockDataExpiry, opts.BlockDataExpiry())
 	require.Equal(t, blockDataExpiryAfterNotAccessedPeriod, opts.BlockDataExpiryAfterNotAccessedPeriod())
 }
+
+func TestConfigurationTimeZone(t *testing.T) {
+	config := &Configuration{
+		RetentionPeriod: 48 * time.Hour,
+		BlockSize:       24 * time.Hour,
+		BufferFuture:    time.Minute,
+		BufferPast:      time.Minute,
+		TimeZone:        "UTC",
+	}
+
+	opts, err := config.Options()
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, opts.TimeZone())
+
+	config.TimeZone = "Not/AZone"
+	_, err = config.Options()
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package retention
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type dynamicOptions struct {
+	sync.RWMutex
+	opts Options
+}
+
+// NewDynamicOptions returns a new set of dynamic retention options wrapping
+// the given options, setters return static copies of the current options.
+func NewDynamicOptions(opts Options) DynamicOptions {
+	return &dynamicOptions{opts: opts}
+}
+
+func (o *dynamicOptions) current() Options {
+	o.RLock()
+	opts := o.opts
+	o.RUnlock()
+	return opts
+}
+
+func (o *dynamicOptions) ExtendRetentionPeriod(value time.Duration) error {
+	o.Lock()
+	defer o.Unlock()
+	if curr := o.opts.RetentionPeriod(); value < curr {
+		return fmt.Errorf(
+			"cannot shorten retention period at runtime: current=%v, new=%v",
+			curr, value)
+	}
+	opts := o.opts.SetRetentionPeriod(value)
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	o.opts = opts
+	return nil
+}
+
+func (o *dynamicOptions) Validate() error {
+	return o.current().Validate()
+}
+
+func (o *dynamicOptions) Equal(value Options) bool {
+	return o.current().Equal(value)
+}
+
+func (o *dynamicOptions) SetRetentionPeriod(value time.Duration) Options {
+	return o.current().SetRetentionPeriod(value)
+}
+
+func (o *dynamicOptions) RetentionPeriod() time.Duration {
+	return o.current().RetentionPeriod()
+}
+
+func (o *dynamicOptions) SetBlockSize(value time.Duration) Options {
+	return o.current().SetBlockSize(value)
+}
+
+func (o *dynamicOptions) BlockSize() time.Duration {
+	return o.current().BlockSize()
+}
+
+func (o *dynamicOptions) SetBufferFuture(value time.Duration) Options {
+	return o.current().SetBufferFuture(value)
+}
+
+func (o *dynamicOptions) BufferFuture() time.Duration {
+	return o.current().BufferFuture()
+}
+
+func (o *dynamicOptions) SetBufferPast(value time.Duration) Options {
+	return o.current().SetBufferPast(value)
+}
+
+func (o *dynamicOptions) BufferPast() time.Duration {
+	return o.current().BufferPast()
+}
+
+func (o *dynamicOptions) SetBlockDataExpiry(value bool) Options {
+	return o.current().SetBlockDataExpiry(value)
+}
+
+func (o *dynamicOptions) BlockDataExpiry() bool {
+	return o.current().BlockDataExpiry()
+}
+
+func (o *dynamicOptions) SetBlockDataExpiryAfterNotAccessedPeriod(value time.Duration) Options {
+	return o.current().SetBlockDataExpiryAfterNotAccessedPeriod(value)
+}
+
+func (o *dynamicOptions) BlockDataExpiryAfterNotAccessedPeriod() time.Duration {
+	return o.current().BlockDataExpiryAfterNotAccessedPeriod()
+}
+
+func (o *dynamicOptions) SetTimeZone(value *time.Location) Options {
+	return o.current().SetTimeZone(value)
+}
+
+func (o *dynamicOptions) TimeZone() *time.Location {
+	return o.current().TimeZone()
+}
@@ -22,6 +22,7 @@ package retention
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -52,6 +53,7 @@ var (
 	errBufferFutureTooLarge    = errors.New("buffer future must be smaller than block size")
 	errBufferPastTooLarge      = errors.New("buffer past must be smaller than block size")
 	errRetentionPeriodTooSmall = errors.New("retention period must not be smaller than block size")
+	errBlockSizeNotDayAligned  = errors.New("block size must evenly divide a day when a time zone is set")
 )
 
 type options struct {
@@ -61,6 +63,7 @@ type options struct {
 	bufferPast                       time.Duration
 	dataExpiry                       bool
 	dataExpiryAfterNotAccessedPeriod time.Duration
+	timeZone                         *time.Location
 }
 
 // NewOptions creates new retention options
@@ -94,6 +97,35 @@ func (o *options) Validate() error {
 	if o.retentionPeriod < o.blockSize {
 		return errRetentionPeriodTooSmall
 	}
+	if o.timeZone != nil {
+		return o.validateTimeZone()
+	}
+	return nil
+}
+
+// validateTimeZone ensures that block boundaries, which are aligned to UTC,
+// also fall on the time zone's midnight for each offset the zone has across
+// the retention period. Blocks of a whole day cannot align with a zone offset
+// from UTC so expiry is at the start of the block containing midnight.
+func (o *options) validateTimeZone() error {
+	if (24*time.Hour)%o.blockSize != 0 {
+		return errBlockSizeNotDayAligned
+	}
+	if o.blockSize == 24*time.Hour {
+		return nil
+	}
+	// NB: offsets change at most a few times a year so checking each day
+	// from the retention start until the end of the next block is enough
+	// to observe each offset in effect, including past zone rule changes.
+	end := time.Now().Add(o.blockSize)
+	for t := end.Add(-o.retentionPeriod - o.blockSize); !t.After(end); t = t.Add(24 * time.Hour) {
+		_, offset := t.In(o.timeZone).Zone()
+		if (time.Duration(offset)*time.Second)%o.blockSize != 0 {
+			return fmt.Errorf(
+				"block size %v does not align with time zone %s offset %v at %v",
+				o.blockSize, o.timeZone, time.Duration(offset)*time.Second, t)
+		}
+	}
 	return nil
 }
 
@@ -103,7 +135,15 @@ func (o *options) Equal(value Options) bool {
 		o.bufferFuture == value.BufferFuture() &&
 		o.bufferPast == value.BufferPast() &&
 		o.dataExpiry == value.BlockDataExpiry() &&
-		o.dataExpiryAfterNotAccessedPeriod == value.BlockDataExpiryAfterNotAccessedPeriod()
+		o.dataExpiryAfterNotAccessedPeriod == value.BlockDataExpiryAfterNotAccessedPeriod() &&
+		timeZoneEqual(o.timeZone, value.TimeZone())
+}
+
+func timeZoneEqual(a, b *time.Location) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
 }
 
 func (o *options) SetRetentionPeriod(value time.Duration) Options {
@@ -165,3 +205,13 @@ func (o *options) SetBlockDataExpiryAfterNotAccessedPeriod(value time.Duration)
 func (o *options) BlockDataExpiryAfterNotAccessedPeriod() time.Duration {
 	return o.dataExpiryAfterNotAccessedPeriod
 }
+
+func (o *options) SetTimeZone(value *time.Location) Options {
+	opts := *o
+	opts.timeZone = value
+	return &opts
+}
+
+func (o *options) TimeZone() *time.Location {
+	return o.timeZone
+}
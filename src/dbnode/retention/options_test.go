@@ -42,3 +42,85 @@ func TestEqualsFalse(t *testing.T) {
 	require.False(t, opts.Equal(otherOpts))
 	require.False(t, otherOpts.Equal(opts))
 }
+
+func TestValidateTimeZone(t *testing.T) {
+	est := time.FixedZone("EST", -5*3600)
+	ist := time.FixedZone("IST", 5*3600+1800)
+
+	opts := NewOptions().SetBlockSize(time.Hour).SetTimeZone(est)
+	require.NoError(t, opts.Validate())
+	require.False(t, opts.Equal(NewOptions().SetBlockSize(time.Hour)))
+
+	require.Error(t, opts.SetTimeZone(ist).Validate())
+	require.NoError(t, opts.SetTimeZone(ist).SetBlockSize(30*time.Minute).Validate())
+	require.Error(t, opts.SetBlockSize(7*time.Hour).SetRetentionPeriod(48*time.Hour).Validate())
+
+	// Whole day blocks cannot align with a zone offset from UTC.
+	require.NoError(t, opts.SetBlockSize(24*time.Hour).SetRetentionPeriod(48*time.Hour).Validate())
+}
+
+func TestValidateTimeZoneOffsetsAcrossRetention(t *testing.T) {
+	// Lord Howe Island is UTC+10:30 in winter and UTC+11 in summer, a year of
+	// retention spans both whatever the current time is.
+	lordHowe, err := time.LoadLocation("Australia/Lord_Howe")
+	require.NoError(t, err)
+
+	opts := NewOptions().SetTimeZone(lordHowe).SetRetentionPeriod(365 * 24 * time.Hour)
+	require.Error(t, opts.SetBlockSize(time.Hour).Validate())
+	require.NoError(t, opts.SetBlockSize(30*time.Minute).Validate())
+}
+
+func TestFlushTimeStartTimeZone(t *testing.T) {
+	est := time.FixedZone("EST", -5*3600)
+	opts := NewOptions().
+		SetBlockSize(time.Hour).
+		SetRetentionPeriod(48 * time.Hour).
+		SetTimeZone(est)
+
+	now := time.Date(2018, time.March, 10, 3, 30, 0, 0, time.UTC)
+	expected := time.Date(2018, time.March, 7, 0, 0, 0, 0, est)
+	require.True(t, expected.Equal(FlushTimeStart(opts, now)))
+
+	expected = time.Date(2018, time.March, 8, 3, 0, 0, 0, time.UTC)
+	require.True(t, expected.Equal(FlushTimeStart(opts.SetTimeZone(nil), now)))
+
+	// Start of day in EST is 05:00 UTC, the whole day block containing it
+	// starts at midnight UTC so that it is retained.
+	expected = time.Date(2018, time.March, 7, 0, 0, 0, 0, time.UTC)
+	require.True(t, expected.Equal(FlushTimeStart(opts.SetBlockSize(24*time.Hour), now)))
+}
+
+func TestFlushTimeStartForBlockSizeTimeZone(t *testing.T) {
+	est := time.FixedZone("EST", -5*3600)
+	opts := NewOptions().
+		SetBlockSize(time.Hour).
+		SetRetentionPeriod(48 * time.Hour).
+		SetTimeZone(est)
+
+	// Start of day in EST is 05:00 UTC, the index block containing it starts
+	// at midnight UTC so that it is retained.
+	now := time.Date(2018, time.March, 10, 3, 30, 0, 0, time.UTC)
+	expected := time.Date(2018, time.March, 7, 0, 0, 0, 0, time.UTC)
+	require.True(t, expected.Equal(FlushTimeStartForBlockSize(opts, 24*time.Hour, now)))
+
+	expected = time.Date(2018, time.March, 8, 0, 0, 0, 0, time.UTC)
+	require.True(t, expected.Equal(FlushTimeStartForBlockSize(opts.SetTimeZone(nil), 24*time.Hour, now)))
+}
+
+func TestDynamicOptionsExtendRetentionPeriod(t *testing.T) {
+	opts := NewOptions().SetRetentionPeriod(24 * time.Hour)
+	dynamic := NewDynamicOptions(opts)
+	require.True(t, dynamic.Equal(opts))
+
+	require.Error(t, dynamic.ExtendRetentionPeriod(12*time.Hour))
+	require.Equal(t, 24*time.Hour, dynamic.RetentionPeriod())
+
+	require.NoError(t, dynamic.ExtendRetentionPeriod(48*time.Hour))
+	require.Equal(t, 48*time.Hour, dynamic.RetentionPeriod())
+	require.True(t, opts.SetRetentionPeriod(48*time.Hour).Equal(dynamic))
+	require.Equal(t, 24*time.Hour, opts.RetentionPeriod())
+
+	static := dynamic.SetBlockSize(time.Hour)
+	require.NoError(t, dynamic.ExtendRetentionPeriod(72*time.Hour))
+	require.Equal(t, 48*time.Hour, static.RetentionPeriod())
+}
@@ -22,11 +22,35 @@ package retention
 
 import "time"
 
-// FlushTimeStart is the earliest flushable time
+// FlushTimeStart is the earliest flushable time, if a time zone is set it is
+// the start of the block containing the start of the calendar day in that
+// time zone the retention period ends in
 func FlushTimeStart(opts Options, t time.Time) time.Time {
+	if tz := opts.TimeZone(); tz != nil {
+		// NB: truncate as a whole day block does not align with the start of
+		// day of a zone offset from UTC, the block containing it is kept.
+		return StartOfDay(t.Add(-opts.RetentionPeriod()), tz).Truncate(opts.BlockSize())
+	}
 	return FlushTimeStartForRetentionPeriod(opts.RetentionPeriod(), opts.BlockSize(), t)
 }
 
+// FlushTimeStartForBlockSize is the earliest flushable time for blocks of the
+// provided size, e.g. index blocks, that cover data governed by the retention
+// options; with a time zone set it is the block containing the start of day
+func FlushTimeStartForBlockSize(opts Options, blockSize time.Duration, t time.Time) time.Time {
+	if tz := opts.TimeZone(); tz != nil {
+		return StartOfDay(t.Add(-opts.RetentionPeriod()), tz).Truncate(blockSize)
+	}
+	return FlushTimeStartForRetentionPeriod(opts.RetentionPeriod(), blockSize, t)
+}
+
+// StartOfDay returns the start of the calendar day in the time zone that t is in.
+func StartOfDay(t time.Time, tz *time.Location) time.Time {
+	local := t.In(tz)
+	year, month, day := local.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, tz).In(t.Location())
+}
+
 // FlushTimeStartForRetentionPeriod is the earliest flushable time
 func FlushTimeStartForRetentionPeriod(retentionPeriod time.Duration, blockSize time.Duration, t time.Time) time.Time {
 	return t.Add(-retentionPeriod).Truncate(blockSize)
@@ -69,4 +69,22 @@ type Options interface {
 	// BlockDataExpiryAfterNotAccessedPeriod returns the period that blocks data should
 	// be expired after not being accessed for a given duration
 	BlockDataExpiryAfterNotAccessedPeriod() time.Duration
+
+	// SetTimeZone sets the time zone whose calendar days retention expiry is
+	// aligned to, if nil retention expiry is aligned to the block size
+	SetTimeZone(value *time.Location) Options
+
+	// TimeZone returns the time zone whose calendar days retention expiry is
+	// aligned to, if nil retention expiry is aligned to the block size
+	TimeZone() *time.Location
+}
+
+// DynamicOptions is a set of retention options whose retention period can be
+// extended at runtime, readers always observe the latest retention period
+type DynamicOptions interface {
+	Options
+
+	// ExtendRetentionPeriod increases the retention period in place, returning
+	// an error if the value would shorten the current retention period
+	ExtendRetentionPeriod(value time.Duration) error
 }
@@ -65,6 +65,8 @@ var (
 
 type options struct {
 	persistRateLimitOpts                 ratelimit.Options
+	peerStreamingRateLimitOpts           ratelimit.Options
+	peerStreamingWindows                 ratelimit.TimeOfDayWindows
 	writeNewSeriesAsync                  bool
 	writeNewSeriesBackoffDuration        time.Duration
 	writeNewSeriesLimitPerShardPerSecond int
@@ -82,6 +84,7 @@ type options struct {
 func NewOptions() Options {
 	return &options{
 		persistRateLimitOpts:                 ratelimit.NewOptions(),
+		peerStreamingRateLimitOpts:           ratelimit.NewOptions(),
 		writeNewSeriesAsync:                  defaultWriteNewSeriesAsync,
 		writeNewSeriesBackoffDuration:        defaultWriteNewSeriesBackoffDuration,
 		writeNewSeriesLimitPerShardPerSecond: defaultWriteNewSeriesLimitPerShardPerSecond,
@@ -118,6 +121,10 @@ func (o *options) Validate() error {
 
 	// tickMinimumInterval can be zero if user desires
 
+	if err := o.peerStreamingWindows.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -131,6 +138,26 @@ func (o *options) PersistRateLimitOptions() ratelimit.Options {
 	return o.persistRateLimitOpts
 }
 
+func (o *options) SetPeerStreamingRateLimitOptions(value ratelimit.Options) Options {
+	opts := *o
+	opts.peerStreamingRateLimitOpts = value
+	return &opts
+}
+
+func (o *options) PeerStreamingRateLimitOptions() ratelimit.Options {
+	return o.peerStreamingRateLimitOpts
+}
+
+func (o *options) SetPeerStreamingWindows(value ratelimit.TimeOfDayWindows) Options {
+	opts := *o
+	opts.peerStreamingWindows = value
+	return &opts
+}
+
+func (o *options) PeerStreamingWindows() ratelimit.TimeOfDayWindows {
+	return o.peerStreamingWindows
+}
+
 func (o *options) SetWriteNewSeriesAsync(value bool) Options {
 	opts := *o
 	opts.writeNewSeriesAsync = value
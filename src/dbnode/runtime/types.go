@@ -39,6 +39,22 @@ type Options interface {
 	// PersistRateLimitOptions returns the persist rate limit options
 	PersistRateLimitOptions() ratelimit.Options
 
+	// SetPeerStreamingRateLimitOptions sets the rate limit options applied
+	// to blocks streamed from peers when bootstrapping and repairing.
+	SetPeerStreamingRateLimitOptions(value ratelimit.Options) Options
+
+	// PeerStreamingRateLimitOptions returns the rate limit options applied
+	// to blocks streamed from peers when bootstrapping and repairing.
+	PeerStreamingRateLimitOptions() ratelimit.Options
+
+	// SetPeerStreamingWindows sets the daily windows of time that blocks can
+	// be streamed from peers in, streaming is always allowed if empty.
+	SetPeerStreamingWindows(value ratelimit.TimeOfDayWindows) Options
+
+	// PeerStreamingWindows returns the daily windows of time that blocks can
+	// be streamed from peers in, streaming is always allowed if empty.
+	PeerStreamingWindows() ratelimit.TimeOfDayWindows
+
 	// SetWriteNewSeriesAsync sets whether to write new series asynchronously or not,
 	// when true this essentially makes writes for new series eventually consistent
 	// as after a write is finished you are not guaranteed to read it back immediately
@@ -0,0 +1,91 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+)
+
+const (
+	// backgroundSchedulesHandlerPath is the path of the background process
+	// schedules.
+	backgroundSchedulesHandlerPath = "/debug/schedules"
+
+	backgroundSchedulesActionPause  = "pause"
+	backgroundSchedulesActionResume = "resume"
+	backgroundSchedulesActionAdjust = "adjust"
+)
+
+// backgroundSchedulesHandler serves the schedules of the tick, flush and
+// cleanup background processes and pauses, resumes or adjusts them, e.g.
+// GET /debug/schedules
+// POST /debug/schedules?process=flush&action=pause&duration=30m
+// POST /debug/schedules?process=flush&action=resume
+// POST /debug/schedules?process=flush&action=adjust&minInterval=10m
+type backgroundSchedulesHandler struct {
+	schedules storage.BackgroundSchedules
+}
+
+func (h backgroundSchedulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := h.update(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.schedules.Schedules())
+}
+
+func (h backgroundSchedulesHandler) update(r *http.Request) error {
+	var (
+		query   = r.URL.Query()
+		process = storage.BackgroundProcess(query.Get("process"))
+	)
+	switch action := query.Get("action"); action {
+	case backgroundSchedulesActionPause:
+		d, err := time.ParseDuration(query.Get("duration"))
+		if err != nil {
+			return fmt.Errorf("invalid duration: %v", err)
+		}
+		return h.schedules.Pause(process, d)
+	case backgroundSchedulesActionResume:
+		return h.schedules.Resume(process)
+	case backgroundSchedulesActionAdjust:
+		d, err := time.ParseDuration(query.Get("minInterval"))
+		if err != nil {
+			return fmt.Errorf("invalid minInterval: %v", err)
+		}
+		return h.schedules.Adjust(process, d)
+	default:
+		return fmt.Errorf("invalid action: %q", action)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3x/ident"
+)
+
+// consistencyCheckHandlerPath is the path of the index consistency check.
+const consistencyCheckHandlerPath = "/debug/consistency"
+
+// consistencyCheckHandler compares the series with data in the index block
+// of a namespace containing the block start, a unix seconds time defaulting
+// to now, with the series in the index. An optional sample rate checks a
+// fraction of the series and repair, which requires a POST, reindexes the
+// series missing from the index, e.g.
+// POST /debug/consistency?namespace=default&blockStart=1530000000&sampleRate=0.1&repair=true
+type consistencyCheckHandler struct {
+	db storage.Database
+}
+
+type consistencyCheckResponse struct {
+	storage.ConsistencyReport
+	Consistent bool `json:"consistent"`
+}
+
+func (h consistencyCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	namespace := query.Get("namespace")
+	if namespace == "" {
+		http.Error(w, "namespace is required", http.StatusBadRequest)
+		return
+	}
+	blockStart, err := unixTimeQueryParam(query.Get("blockStart"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if blockStart.IsZero() {
+		blockStart = h.db.Options().ClockOptions().NowFn()()
+	}
+
+	var opts storage.ConsistencyCheckOptions
+	if v := query.Get("sampleRate"); v != "" {
+		if opts.SampleRate, err = strconv.ParseFloat(v, 64); err != nil {
+			http.Error(w, fmt.Sprintf("invalid sample rate: %s", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("maxIDs"); v != "" {
+		if opts.MaxIDs, err = strconv.Atoi(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid max IDs: %s", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("repair"); v != "" {
+		if opts.Repair, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid repair: %s", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if opts.Repair && r.Method != http.MethodPost {
+		http.Error(w, "repair requires a POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.db.ConsistencyCheck(ident.StringID(namespace), blockStart, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(consistencyCheckResponse{
+		ConsistencyReport: report,
+		Consistent:        report.Consistent(),
+	})
+}
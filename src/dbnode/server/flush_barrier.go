@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3x/ident"
+)
+
+// flushBarrierHandlerPath is the path of the flush barrier.
+const flushBarrierHandlerPath = "/debug/flush-barrier"
+
+// flushBarrierHandler flushes, snapshots and flushes the index of the given
+// namespaces, or all namespaces if none are given, at a single point in
+// time and serves the fileset volumes to copy for a consistent backup, e.g.
+// POST /debug/flush-barrier?namespace=metrics&namespace=metrics_agg
+// Cleanups are held until the barrier is released once the volumes are
+// copied, e.g. DELETE /debug/flush-barrier?token=1541030400000000000
+type flushBarrierHandler struct {
+	db storage.Database
+}
+
+func (h flushBarrierHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		if err := h.db.ReleaseFlushBarrier(r.URL.Query().Get("token")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		}
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var namespaces []ident.ID
+	for _, namespace := range r.URL.Query()["namespace"] {
+		namespaces = append(namespaces, ident.StringID(namespace))
+	}
+
+	barrier, err := h.db.FlushBarrier(namespaces)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(barrier)
+}
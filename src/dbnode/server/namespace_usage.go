@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3x/ident"
+)
+
+// namespaceUsageHandlerPath is the path of the namespace usage report.
+const namespaceUsageHandlerPath = "/debug/namespace/usage"
+
+// namespaceUsageHandler serves the utilization of each namespace on the node,
+// or of a single namespace if set, e.g.
+// GET /debug/namespace/usage?namespace=default
+type namespaceUsageHandler struct {
+	db storage.Database
+}
+
+type namespaceUsageResponse struct {
+	Namespaces []storage.NamespaceUsage `json:"namespaces"`
+}
+
+func (h namespaceUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespaces := h.db.Namespaces()
+	if name := r.URL.Query().Get("namespace"); name != "" {
+		ns, ok := h.db.Namespace(ident.StringID(name))
+		if !ok {
+			http.Error(w, "namespace not found", http.StatusNotFound)
+			return
+		}
+		namespaces = []storage.Namespace{ns}
+	}
+
+	resp := namespaceUsageResponse{
+		Namespaces: make([]storage.NamespaceUsage, 0, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		usage, err := ns.Usage()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Namespaces = append(resp.Namespaces, usage)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
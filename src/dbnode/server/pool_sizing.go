@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"io/ioutil"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3x/ident"
+)
+
+// observedSeriesAndShards returns the number of series and distinct shards
+// in the data filesets on disk, the number of series of each shard is the
+// number of entries in its most recent data fileset.
+func observedSeriesAndShards(fsopts fs.Options) (int, int, error) {
+	prefix := fsopts.FilePathPrefix()
+	namespaceDirs, err := ioutil.ReadDir(fs.DataDirPath(prefix))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		numSeries int
+		shards    = make(map[uint32]struct{})
+	)
+	for _, namespaceDir := range namespaceDirs {
+		if !namespaceDir.IsDir() {
+			continue
+		}
+		namespace := ident.StringID(namespaceDir.Name())
+		shardDirs, err := ioutil.ReadDir(fs.NamespaceDataDirPath(prefix, namespace))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, shardDir := range shardDirs {
+			shard, err := strconv.ParseUint(shardDir.Name(), 10, 32)
+			if !shardDir.IsDir() || err != nil {
+				continue
+			}
+			shards[uint32(shard)] = struct{}{}
+
+			var latest int64
+			entries := 0
+			infoFiles := fs.ReadInfoFiles(prefix, namespace, uint32(shard),
+				fsopts.InfoReaderBufferSize(), fsopts.DecodingOptions())
+			for _, result := range infoFiles {
+				if result.Err.Error() != nil {
+					continue
+				}
+				if result.Info.BlockStart >= latest {
+					latest = result.Info.BlockStart
+					entries = int(result.Info.Entries)
+				}
+			}
+			numSeries += entries
+		}
+	}
+	return numSeries, len(shards), nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/pool"
+)
+
+// The pools below record their gets so their hit rate can be tracked, all
+// other methods are forwarded to the wrapped pool.
+
+type statsCheckedBytesPool struct {
+	pool.CheckedBytesPool
+
+	recordGet func()
+}
+
+func (p statsCheckedBytesPool) Get(capacity int) checked.Bytes {
+	p.recordGet()
+	return p.CheckedBytesPool.Get(capacity)
+}
+
+type statsEncoderPool struct {
+	encoding.EncoderPool
+
+	recordGet func()
+}
+
+func (p statsEncoderPool) Get() encoding.Encoder {
+	p.recordGet()
+	return p.EncoderPool.Get()
+}
+
+type statsSeriesPool struct {
+	series.DatabaseSeriesPool
+
+	recordGet func()
+}
+
+func (p statsSeriesPool) Get() series.DatabaseSeries {
+	p.recordGet()
+	return p.DatabaseSeriesPool.Get()
+}
+
+type statsContextPool struct {
+	context.Pool
+
+	recordGet func()
+}
+
+func (p statsContextPool) Get() context.Context {
+	p.recordGet()
+	return p.Pool.Get()
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package server
+
+import (
+	stdcontext "context"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	querystorage "github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3x/context"
+	xerrors "github.com/m3db/m3x/errors"
+	"github.com/m3db/m3x/ident"
+)
+
+// selfTelemetryAppender writes the self telemetry of the node directly to
+// one of its own namespaces rather than through a client.
+type selfTelemetryAppender struct {
+	db          storage.Database
+	namespace   ident.ID
+	contextPool context.Pool
+}
+
+func (a selfTelemetryAppender) Write(
+	_ stdcontext.Context,
+	query *querystorage.WriteQuery,
+) error {
+	ctx := a.contextPool.Get()
+	defer ctx.BlockingClose()
+
+	var (
+		id       = ident.StringID(query.Tags.ID())
+		tags     = querystorage.TagsToIdentTagIterator(query.Tags)
+		multiErr xerrors.MultiError
+	)
+	defer tags.Close()
+
+	for _, dp := range query.Datapoints {
+		_, err := a.db.WriteTagged(ctx, a.namespace, id, tags.Duplicate(),
+			dp.Timestamp, dp.Value, query.Unit, nil)
+		multiErr = multiErr.Add(err)
+	}
+	return multiErr.FinalError()
+}
@@ -29,6 +29,8 @@ import (
 	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -58,9 +60,17 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
+	"github.com/m3db/m3/src/dbnode/x/fault"
+	"github.com/m3db/m3/src/dbnode/x/gctuner"
+	"github.com/m3db/m3/src/dbnode/x/identcheck"
 	"github.com/m3db/m3/src/dbnode/x/mmap"
+	"github.com/m3db/m3/src/dbnode/x/poolstats"
 	"github.com/m3db/m3/src/dbnode/x/tchannel"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	querystorage "github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/selftelemetry"
 	clusterclient "github.com/m3db/m3cluster/client"
 	"github.com/m3db/m3cluster/client/etcd"
 	"github.com/m3db/m3cluster/generated/proto/commonpb"
@@ -142,6 +152,19 @@ func Run(runOpts RunOptions) {
 		logger.Fatalf("could not connect to metrics: %v", err)
 	}
 
+	var selfTelemetry selftelemetry.Reporter
+	if selfCfg := cfg.SelfTelemetry; selfCfg != nil {
+		logger.Infof("writing self telemetry to namespace %s with prefix %s",
+			selfCfg.Namespace, selfCfg.Prefix)
+
+		selfTelemetry = selftelemetry.NewReporter(selfCfg.Prefix,
+			querystorage.Attributes{MetricsType: querystorage.UnaggregatedMetricsType})
+		var selfTelemetryCloser io.Closer
+		scope, selfTelemetryCloser = selftelemetry.NewScope(scope, selfTelemetry,
+			selfCfg.ReportIntervalOrDefault())
+		defer selfTelemetryCloser.Close()
+	}
+
 	hostID, err := cfg.HostID.Resolve()
 	if err != nil {
 		logger.Fatalf("could not resolve local host ID: %v", err)
@@ -199,7 +222,20 @@ func Run(runOpts RunOptions) {
 		SetMetricsSamplingRate(cfg.Metrics.SampleRate())
 	opts = opts.SetInstrumentOptions(iopts)
 
-	if cfg.Index.MaxQueryIDsConcurrency != 0 {
+	var cpuPinning config.CPUPinningConfiguration
+	if cfg.CPUPinning != nil {
+		cpuPinning = *cfg.CPUPinning
+	}
+
+	if sets := resolveCPUSets(logger, "index query", cpuPinning.IndexQuery); len(sets) > 0 {
+		concurrency := cfg.Index.MaxQueryIDsConcurrency
+		if concurrency == 0 {
+			concurrency = len(cpuset.Union(sets...))
+		}
+		queryIDsWorkerPool := cpuset.NewWorkerPool(concurrency, sets, iopts)
+		queryIDsWorkerPool.Init()
+		opts = opts.SetQueryIDsWorkerPool(queryIDsWorkerPool)
+	} else if cfg.Index.MaxQueryIDsConcurrency != 0 {
 		queryIDsWorkerPool := xsync.NewWorkerPool(cfg.Index.MaxQueryIDsConcurrency)
 		queryIDsWorkerPool.Init()
 		opts = opts.SetQueryIDsWorkerPool(queryIDsWorkerPool)
@@ -213,11 +249,34 @@ func Run(runOpts RunOptions) {
 	}
 	defer buildReporter.Stop()
 
+	if cfg.GCTuning != nil {
+		gcTuner, err := cfg.GCTuning.NewTuner(iopts.SetMetricsScope(scope.SubScope("gc-tuner")))
+		if err != nil {
+			logger.Fatalf("could not create gc tuner: %v", err)
+		}
+		gcTuner.Start()
+		defer gcTuner.Stop()
+	}
+
+	persistRateLimitOpts := ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitMbps(cfg.Filesystem.ThroughputLimitMbps).
+		SetLimitCheckEvery(cfg.Filesystem.ThroughputCheckEvery)
+	if throttleCfg := cfg.Filesystem.AdaptiveThrottle; throttleCfg != nil {
+		persistRateLimitOpts = persistRateLimitOpts.
+			SetAdaptiveEnabled(true).
+			SetAdaptiveMaxReadLatency(throttleCfg.MaxReadLatency)
+		// NB: zero values would throttle to a standstill, keep the defaults.
+		if v := throttleCfg.MinThroughputLimitMbps; v > 0 {
+			persistRateLimitOpts = persistRateLimitOpts.SetAdaptiveMinLimitMbps(v)
+		}
+		if v := throttleCfg.MaxDiskUtilization; v > 0 {
+			persistRateLimitOpts = persistRateLimitOpts.SetAdaptiveMaxDiskUtilization(v)
+		}
+	}
+
 	runtimeOpts := m3dbruntime.NewOptions().
-		SetPersistRateLimitOptions(ratelimit.NewOptions().
-			SetLimitEnabled(true).
-			SetLimitMbps(cfg.Filesystem.ThroughputLimitMbps).
-			SetLimitCheckEvery(cfg.Filesystem.ThroughputCheckEvery)).
+		SetPersistRateLimitOptions(persistRateLimitOpts).
 		SetWriteNewSeriesAsync(cfg.WriteNewSeriesAsync).
 		SetWriteNewSeriesBackoffDuration(cfg.WriteNewSeriesBackoffDuration)
 	if lruCfg := cfg.Cache.SeriesConfiguration().LRU; lruCfg != nil {
@@ -231,7 +290,64 @@ func Run(runOpts RunOptions) {
 		insertMode = index.InsertAsync
 	}
 	opts = opts.SetIndexOptions(
-		indexOpts.SetInsertMode(insertMode))
+		indexOpts.
+			SetInsertMode(insertMode).
+			SetNumericTagNames(cfg.Index.NumericTagNames).
+			SetCardinalityReportInterval(cfg.Index.CardinalityReportInterval).
+			SetTagDictionaryMaxEntries(cfg.Index.TagDictionaryMaxEntries).
+			SetReservedFieldNames(cfg.Index.ReservedFieldNames).
+			SetFullScanMaxSeries(cfg.Index.FullScanMaxSeries).
+			SetRecentlyIndexedWindow(cfg.Index.RecentlyIndexedWindow))
+
+	fieldValidators, err := cfg.Index.FieldValidators()
+	if err != nil {
+		logger.Fatalf("could not parse index field patterns: %v", err)
+	}
+	opts = opts.SetIndexOptions(opts.IndexOptions().
+		SetFieldValidators(fieldValidators))
+
+	if analyzerCfg := cfg.Index.Analyzer; analyzerCfg != nil {
+		analyzer, err := analyzerCfg.NewAnalyzer()
+		if err != nil {
+			logger.Fatalf("could not create index analyzer: %v", err)
+		}
+		opts = opts.SetIndexOptions(opts.IndexOptions().
+			SetAnalyzer(analyzer))
+	}
+
+	if warmupCfg := cfg.Index.Warmup; warmupCfg != nil {
+		queries, err := warmupCfg.IndexQueries()
+		if err != nil {
+			logger.Fatalf("could not parse index warmup queries: %v", err)
+		}
+		warmupQueries := make([]index.Query, 0, len(queries))
+		for _, q := range queries {
+			warmupQueries = append(warmupQueries, index.Query{Query: q})
+		}
+		opts = opts.SetIndexOptions(opts.IndexOptions().
+			SetWarmupEnabled(true).
+			SetWarmupQueries(warmupQueries))
+	}
+
+	if cfg.Index.InsertSyncTimeout > 0 {
+		opts = opts.SetIndexOptions(opts.IndexOptions().
+			SetInsertSyncTimeout(cfg.Index.InsertSyncTimeout))
+	}
+
+	if queryCacheCfg := cfg.Index.QueryCache; queryCacheCfg != nil {
+		indexOpts := opts.IndexOptions().SetQueryCacheSize(queryCacheCfg.Size)
+		if queryCacheCfg.TTL > 0 {
+			indexOpts = indexOpts.SetQueryCacheTTL(queryCacheCfg.TTL)
+		}
+		opts = opts.SetIndexOptions(indexOpts)
+	}
+
+	if postingsCfg := cfg.Index.PostingsListCache; postingsCfg != nil {
+		postingsListCache := index.NewPostingsListCache(postingsCfg.Size,
+			scope.SubScope("postings-list-cache"))
+		opts = opts.SetIndexOptions(opts.IndexOptions().
+			SetPostingsListCache(postingsListCache))
+	}
 
 	if tick := cfg.Tick; tick != nil {
 		runtimeOpts = runtimeOpts.
@@ -240,6 +356,18 @@ func Run(runOpts RunOptions) {
 			SetTickMinimumInterval(tick.MinimumInterval)
 	}
 
+	peerStreamingCfg := config.PeerStreamingConfiguration{}
+	if cfg.PeerStreaming != nil {
+		peerStreamingCfg = *cfg.PeerStreaming
+	}
+	peerStreamingWindows, err := peerStreamingCfg.TimeOfDayWindows()
+	if err != nil {
+		logger.Fatalf("could not parse peer streaming windows: %v", err)
+	}
+	runtimeOpts = runtimeOpts.
+		SetPeerStreamingRateLimitOptions(peerStreamingCfg.RateLimitOptions()).
+		SetPeerStreamingWindows(peerStreamingWindows)
+
 	runtimeOptsMgr := m3dbruntime.NewOptionsManager()
 	if err := runtimeOptsMgr.Update(runtimeOpts); err != nil {
 		logger.Fatalf("could not set initial runtime options: %v", err)
@@ -272,16 +400,44 @@ func Run(runOpts RunOptions) {
 		}
 	}
 
+	poolStats := poolstats.NewRegistry(scope)
+	// NB: served by the debug server if a debug listen address is set.
+	http.Handle(poolstats.HandlerPath, poolstats.NewHandler(poolStats))
+	go func() {
+		ticker := time.NewTicker(opts.InstrumentOptions().ReportInterval())
+		defer ticker.Stop()
+		for range ticker.C {
+			poolStats.ReportMetrics()
+		}
+	}()
+
 	policy := cfg.PoolingPolicy
+	poolScope := poolStats.Scope()
 	tagEncoderPool := serialize.NewTagEncoderPool(
 		serialize.NewTagEncoderOptions(),
-		poolOptions(policy.TagEncoderPool, scope.SubScope("tag-encoder-pool")))
+		poolOptions(policy.TagEncoderPool, poolScope.SubScope("tag-encoder-pool")))
 	tagEncoderPool.Init()
 	tagDecoderPool := serialize.NewTagDecoderPool(
 		serialize.NewTagDecoderOptions(),
-		poolOptions(policy.TagDecoderPool, scope.SubScope("tag-decoder-pool")))
+		poolOptions(policy.TagDecoderPool, poolScope.SubScope("tag-decoder-pool")))
 	tagDecoderPool.Init()
 
+	faultInjector := fault.NewNoopInjector()
+	if cfg.Faults != nil {
+		if err := cfg.Faults.Validate(); err != nil {
+			logger.Fatalf("could not validate fault injection config: %v", err)
+		}
+		logger.Warnf("fault injection enabled, this should not be used in production")
+		faultInjector = cfg.Faults.NewInjector()
+		// NB: served by the debug server if a debug listen address is set.
+		http.Handle(fault.HandlerPath, fault.NewHandler(faultInjector))
+	}
+
+	writeAmpRecorder := writeamp.NewRecorder(opts.InstrumentOptions().
+		SetMetricsScope(scope.SubScope("write-amplification")))
+	// NB: served by the debug server if a debug listen address is set.
+	http.Handle(writeamp.HandlerPath, writeamp.NewHandler(writeAmpRecorder))
+
 	fsopts := fs.NewOptions().
 		SetClockOptions(opts.ClockOptions()).
 		SetInstrumentOptions(opts.InstrumentOptions().
@@ -290,14 +446,22 @@ func Run(runOpts RunOptions) {
 		SetNewFileMode(newFileMode).
 		SetNewDirectoryMode(newDirectoryMode).
 		SetWriterBufferSize(cfg.Filesystem.WriteBufferSize).
+		SetDirectIOWritesEnabled(cfg.Filesystem.DirectIOWrites).
 		SetDataReaderBufferSize(cfg.Filesystem.DataReadBufferSize).
 		SetInfoReaderBufferSize(cfg.Filesystem.InfoReadBufferSize).
 		SetSeekReaderBufferSize(cfg.Filesystem.SeekReadBufferSize).
 		SetMmapEnableHugeTLB(shouldUseHugeTLB).
 		SetMmapHugeTLBThreshold(mmapCfg.HugeTLB.Threshold).
 		SetRuntimeOptionsManager(runtimeOptsMgr).
+		SetFaultInjector(faultInjector).
+		SetWriteAmplificationRecorder(writeAmpRecorder).
 		SetTagEncoderPool(tagEncoderPool).
 		SetTagDecoderPool(tagDecoderPool)
+	if warmTierCfg := cfg.Filesystem.WarmTier; warmTierCfg != nil {
+		fsopts = fsopts.
+			SetWarmFilePathPrefix(warmTierCfg.FilePathPrefix).
+			SetWarmTierAfter(warmTierCfg.After)
+	}
 
 	var commitLogQueueSize int
 	specified := cfg.CommitLog.Queue.Size
@@ -311,6 +475,10 @@ func Run(runOpts RunOptions) {
 			cfg.CommitLog.Queue.CalculationType)
 	}
 
+	// NB: the commit log writer is a single worker so is pinned to the union
+	// of the write CPU sets, the shard insert queues are spread across them.
+	writeCPUSets := resolveCPUSets(logger, "write", cpuPinning.Write)
+	opts = opts.SetWriteCPUSets(writeCPUSets)
 	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
 		SetInstrumentOptions(opts.InstrumentOptions()).
 		SetFilesystemOptions(fsopts).
@@ -319,14 +487,39 @@ func Run(runOpts RunOptions) {
 		SetFlushInterval(cfg.CommitLog.FlushEvery).
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetRetentionPeriod(cfg.CommitLog.RetentionPeriod).
-		SetBlockSize(cfg.CommitLog.BlockSize))
+		SetBlockSize(cfg.CommitLog.BlockSize).
+		SetMaxSegmentSize(cfg.CommitLog.MaxSegmentSize).
+		SetMaxRetainedBytes(cfg.CommitLog.MaxRetainedBytes).
+		SetWriteCPUSet(cpuset.Union(writeCPUSets...)))
 
 	// Set the series cache policy
 	seriesCachePolicy := cfg.Cache.SeriesConfiguration().Policy
 	opts = opts.SetSeriesCachePolicy(seriesCachePolicy)
 
+	if policy := cfg.UnexpectedShardPolicy; policy != nil {
+		opts = opts.SetUnexpectedShardPolicy(*policy)
+	}
+
+	if wt := cfg.WriteTimestamps; wt != nil {
+		opts = opts.
+			SetFutureWritePolicy(wt.FuturePolicy).
+			SetMaxClockSkew(wt.MaxClockSkew)
+	}
+
+	if cfg.PoolingPolicy.AutoSize != nil {
+		numSeries, numShards, err := observedSeriesAndShards(fsopts)
+		if err != nil && !os.IsNotExist(err) {
+			logger.Warnf("could not observe series and shards to size pools: %v", err)
+		}
+		policy = policy.AutoSized(numSeries, numShards)
+		logger.Infof("pools sized from memory budget %d: series=%d, shards=%d, "+
+			"seriesPoolSize=%d, contextPoolSize=%d", policy.AutoSize.MemoryBudget,
+			numSeries, numShards, policy.SeriesPool.Size, policy.ContextPool.Size)
+	}
+
 	// Apply pooling options
-	opts = withEncodingAndPoolingOptions(logger, opts, cfg.PoolingPolicy)
+	opts = withEncodingAndPoolingOptions(logger, opts, policy,
+		poolStats, cfg.Index.IdentifierLeakDetection)
 
 	// Setup the block retriever
 	switch seriesCachePolicy {
@@ -343,6 +536,8 @@ func Run(runOpts RunOptions) {
 			retrieverOpts = retrieverOpts.
 				SetFetchConcurrency(blockRetrieveCfg.FetchConcurrency)
 		}
+		retrieverOpts = retrieverOpts.
+			SetFetchCPUSets(resolveCPUSets(logger, "read", cpuPinning.Read))
 		blockRetrieverMgr := block.NewDatabaseBlockRetrieverManager(
 			func(md namespace.Metadata) (block.DatabaseBlockRetriever, error) {
 				retriever := fs.NewBlockRetriever(retrieverOpts, fsopts)
@@ -418,6 +613,9 @@ func Run(runOpts RunOptions) {
 		func(opts client.AdminOptions) client.AdminOptions {
 			return opts.SetRuntimeOptionsManager(runtimeOptsMgr).(client.AdminOptions)
 		},
+		func(opts client.AdminOptions) client.AdminOptions {
+			return opts.SetFaultInjector(faultInjector).(client.AdminOptions)
+		},
 		func(opts client.AdminOptions) client.AdminOptions {
 			return opts.SetContextPool(opts.ContextPool()).(client.AdminOptions)
 		},
@@ -436,6 +634,8 @@ func Run(runOpts RunOptions) {
 	clientAdminOpts := m3dbClient.Options().(client.AdminOptions)
 	kvWatchClientConsistencyLevels(envCfg.KVStore, logger,
 		clientAdminOpts, runtimeOptsMgr)
+	kvWatchPeerStreaming(envCfg.KVStore, logger,
+		peerStreamingCfg, runtimeOptsMgr)
 
 	// Set bootstrap options
 	bs, err := cfg.Bootstrap.New(opts, m3dbClient)
@@ -443,7 +643,9 @@ func Run(runOpts RunOptions) {
 		logger.Fatalf("could not create bootstrap process: %v", err)
 	}
 
-	opts = opts.SetBootstrapProcessProvider(bs)
+	opts = opts.SetBootstrapProcessProvider(bs).
+		SetReadWhileBootstrapping(cfg.Bootstrap.ReadWhileBootstrapping).
+		SetBootstrapWriteBufferSize(cfg.Bootstrap.WriteBufferSize)
 
 	timeout := bootstrapConfigInitTimeout
 	kvWatchBootstrappers(envCfg.KVStore, logger, timeout, cfg.Bootstrap.Bootstrappers,
@@ -466,7 +668,7 @@ func Run(runOpts RunOptions) {
 	// Set repair options
 	hostBlockMetadataSlicePool := repair.NewHostBlockMetadataSlicePool(
 		capacityPoolOptions(policy.HostBlockMetadataSlicePool,
-			scope.SubScope("host-block-metadata-slice-pool")),
+			poolScope.SubScope("host-block-metadata-slice-pool")),
 		policy.HostBlockMetadataSlicePool.Capacity)
 
 	opts = opts.
@@ -482,14 +684,14 @@ func Run(runOpts RunOptions) {
 
 	// Set tchannelthrift options
 	blockMetadataPool := tchannelthrift.NewBlockMetadataPool(
-		poolOptions(policy.BlockMetadataPool, scope.SubScope("block-metadata-pool")))
+		poolOptions(policy.BlockMetadataPool, poolScope.SubScope("block-metadata-pool")))
 	blockMetadataSlicePool := tchannelthrift.NewBlockMetadataSlicePool(
-		capacityPoolOptions(policy.BlockMetadataSlicePool, scope.SubScope("block-metadata-slice-pool")),
+		capacityPoolOptions(policy.BlockMetadataSlicePool, poolScope.SubScope("block-metadata-slice-pool")),
 		policy.BlockMetadataSlicePool.Capacity)
 	blocksMetadataPool := tchannelthrift.NewBlocksMetadataPool(
-		poolOptions(policy.BlocksMetadataPool, scope.SubScope("blocks-metadata-pool")))
+		poolOptions(policy.BlocksMetadataPool, poolScope.SubScope("blocks-metadata-pool")))
 	blocksMetadataSlicePool := tchannelthrift.NewBlocksMetadataSlicePool(
-		capacityPoolOptions(policy.BlocksMetadataSlicePool, scope.SubScope("blocks-metadata-slice-pool")),
+		capacityPoolOptions(policy.BlocksMetadataSlicePool, poolScope.SubScope("blocks-metadata-slice-pool")),
 		policy.BlocksMetadataSlicePool.Capacity)
 
 	ttopts := tchannelthrift.NewOptions().
@@ -499,7 +701,25 @@ func Run(runOpts RunOptions) {
 		SetBlocksMetadataPool(blocksMetadataPool).
 		SetBlocksMetadataSlicePool(blocksMetadataSlicePool).
 		SetTagEncoderPool(tagEncoderPool).
-		SetTagDecoderPool(tagDecoderPool)
+		SetTagDecoderPool(tagDecoderPool).
+		SetSlowQueryThreshold(cfg.Index.SlowQueryThreshold).
+		SetFetchTaggedStreamBatchSize(cfg.Index.FetchTaggedStreamBatchSize).
+		SetFetchTaggedStreamMaxBytes(cfg.Index.FetchTaggedStreamMaxBytes).
+		SetQueryLimits(tchannelthrift.QueryLimits{
+			MaxResults:       cfg.Index.MaxQueryResults,
+			MaxSeriesMatched: cfg.Index.MaxQuerySeriesMatched,
+		})
+	if policy := cfg.Index.SegmentMergePolicy; policy != nil {
+		ttopts = ttopts.SetSegmentMergePolicy(*policy)
+	}
+	if limitsCfg := cfg.RPCConcurrencyLimits; limitsCfg != nil {
+		ttopts = ttopts.SetConcurrencyLimits(tchannelthrift.ConcurrencyLimits{
+			MaxConcurrent: limitsCfg.MaxConcurrent,
+			Write:         tchannelthrift.ConcurrencyLimit(limitsCfg.Write),
+			Read:          tchannelthrift.ConcurrencyLimit(limitsCfg.Read),
+			Stream:        tchannelthrift.ConcurrencyLimit(limitsCfg.Stream),
+		})
+	}
 
 	db, err := cluster.NewDatabase(hostID, envCfg.TopologyInitializer, opts)
 	if err != nil {
@@ -509,8 +729,28 @@ func Run(runOpts RunOptions) {
 		logger.Fatalf("could not open database: %v", err)
 	}
 
+	// NB: served by the debug server if a debug listen address is set.
+	http.Handle(indexCardinalityHandlerPath, indexCardinalityHandler{db: db})
+	http.Handle(integrityReportHandlerPath, integrityReportHandler{db: db})
+	http.Handle(consistencyCheckHandlerPath, consistencyCheckHandler{db: db})
+	http.Handle(namespaceUsageHandlerPath, namespaceUsageHandler{db: db})
+	http.Handle(flushBarrierHandlerPath, flushBarrierHandler{db: db})
+	http.Handle(backgroundSchedulesHandlerPath, backgroundSchedulesHandler{
+		schedules: opts.BackgroundSchedules(),
+	})
+
 	contextPool := opts.ContextPool()
 
+	if selfTelemetry != nil {
+		// NB: metrics reported before the database is open are accumulated
+		// and written once the appender is set.
+		selfTelemetry.SetAppender(selfTelemetryAppender{
+			db:          db,
+			namespace:   ident.StringID(cfg.SelfTelemetry.Namespace),
+			contextPool: contextPool,
+		})
+	}
+
 	tchannelOpts := xtchannel.NewDefaultChannelOptions()
 	tchannelthriftNodeClose, err := ttnode.NewServer(db,
 		cfg.ListenAddress, contextPool, tchannelOpts, ttopts).ListenAndServe()
@@ -612,6 +852,22 @@ func Run(runOpts RunOptions) {
 	}
 }
 
+func resolveCPUSets(
+	logger xlog.Logger,
+	name string,
+	cfg *cpuset.Configuration,
+) []cpuset.CPUSet {
+	if cfg == nil {
+		return nil
+	}
+	sets, err := cfg.CPUSets()
+	if err != nil {
+		logger.Fatalf("could not resolve %s cpu pinning: %v", name, err)
+	}
+	logger.Infof("pinning %s workers to cpus %v", name, sets)
+	return sets
+}
+
 func interrupt() <-chan os.Signal {
 	c := make(chan os.Signal)
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
@@ -747,6 +1003,55 @@ func kvWatchClientConsistencyLevels(
 		})
 }
 
+func kvWatchPeerStreaming(
+	store kv.Store,
+	logger xlog.Logger,
+	cfg config.PeerStreamingConfiguration,
+	runtimeOptsMgr m3dbruntime.OptionsManager,
+) {
+	kvWatchStringValue(store, logger,
+		kvconfig.PeerStreamingThroughputLimitMbpsKey,
+		func(value string) error {
+			limitMbps, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return err
+			}
+			peerStreamingCfg := cfg
+			peerStreamingCfg.ThroughputLimitMbps = limitMbps
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetPeerStreamingRateLimitOptions(peerStreamingCfg.RateLimitOptions()))
+		},
+		func() error {
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetPeerStreamingRateLimitOptions(cfg.RateLimitOptions()))
+		})
+
+	kvWatchStringValue(store, logger,
+		kvconfig.PeerStreamingWindowsKey,
+		func(value string) error {
+			var values []string
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			windows, err := config.ParsePeerStreamingWindows(values, cfg.TimeZone)
+			if err != nil {
+				return err
+			}
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetPeerStreamingWindows(windows))
+		},
+		func() error {
+			windows, err := cfg.TimeOfDayWindows()
+			if err != nil {
+				return err
+			}
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetPeerStreamingWindows(windows))
+		})
+}
+
 func kvWatchStringValue(
 	store kv.Store,
 	logger xlog.Logger,
@@ -885,9 +1190,11 @@ func withEncodingAndPoolingOptions(
 	logger xlog.Logger,
 	opts storage.Options,
 	policy config.PoolingPolicy,
+	poolStats poolstats.Registry,
+	indexIdentifierLeakDetection bool,
 ) storage.Options {
 	iopts := opts.InstrumentOptions()
-	scope := opts.InstrumentOptions().MetricsScope()
+	scope := poolStats.Scope()
 
 	bytesPoolOpts := pool.NewObjectPoolOptions().
 		SetInstrumentOptions(iopts.SetMetricsScope(scope.SubScope("bytes-pool")))
@@ -930,18 +1237,28 @@ func withEncodingAndPoolingOptions(
 
 	logger.Infof("bytes pool %s init", policy.Type)
 	bytesPool.Init()
+	bytesPool = statsCheckedBytesPool{
+		CheckedBytesPool: bytesPool,
+		recordGet:        poolStats.GetRecorder("checked-bytes-pool"),
+	}
 
 	segmentReaderPool := xio.NewSegmentReaderPool(
 		poolOptions(policy.SegmentReaderPool, scope.SubScope("segment-reader-pool")))
 	segmentReaderPool.Init()
-	encoderPool := encoding.NewEncoderPool(
-		poolOptions(policy.EncoderPool, scope.SubScope("encoder-pool")))
+	encoderPool := encoding.EncoderPool(statsEncoderPool{
+		EncoderPool: encoding.NewEncoderPool(
+			poolOptions(policy.EncoderPool, scope.SubScope("encoder-pool"))),
+		recordGet: poolStats.GetRecorder("encoder-pool"),
+	})
 	closersPoolOpts := poolOptions(policy.ClosersPool, scope.SubScope("closers-pool"))
 	contextPoolOpts := poolOptions(policy.ContextPool.PoolPolicy(), scope.SubScope("context-pool"))
-	contextPool := context.NewPool(context.NewOptions().
-		SetContextPoolOptions(contextPoolOpts).
-		SetFinalizerPoolOptions(closersPoolOpts).
-		SetMaxPooledFinalizerCapacity(policy.ContextPool.MaxFinalizerCapacityWithDefault()))
+	contextPool := context.Pool(statsContextPool{
+		Pool: context.NewPool(context.NewOptions().
+			SetContextPoolOptions(contextPoolOpts).
+			SetFinalizerPoolOptions(closersPoolOpts).
+			SetMaxPooledFinalizerCapacity(policy.ContextPool.MaxFinalizerCapacityWithDefault())),
+		recordGet: poolStats.GetRecorder("context-pool"),
+	})
 	iteratorPool := encoding.NewReaderIteratorPool(
 		poolOptions(policy.IteratorPool, scope.SubScope("iterator-pool")))
 	multiIteratorPool := encoding.NewMultiReaderIteratorPool(
@@ -1019,8 +1336,11 @@ func withEncodingAndPoolingOptions(
 	retentionOpts := retention.NewOptions()
 	seriesOpts := storage.NewSeriesOptionsFromOptions(opts, retentionOpts).
 		SetFetchBlockMetadataResultsPool(opts.FetchBlockMetadataResultsPool())
-	seriesPool := series.NewDatabaseSeriesPool(
-		poolOptions(policy.SeriesPool, scope.SubScope("series-pool")))
+	seriesPool := series.DatabaseSeriesPool(statsSeriesPool{
+		DatabaseSeriesPool: series.NewDatabaseSeriesPool(
+			poolOptions(policy.SeriesPool, scope.SubScope("series-pool"))),
+		recordGet: poolStats.GetRecorder("series-pool"),
+	})
 
 	opts = opts.
 		SetSeriesOptions(seriesOpts).
@@ -1029,13 +1349,20 @@ func withEncodingAndPoolingOptions(
 		SetBytesPool(bytesPool).
 		SetIdentifierPool(identifierPool))
 
+	indexIdentifierPool := identifierPool
+	if indexIdentifierLeakDetection {
+		logger.Warnf("index identifier leak detection enabled, this should not be used in production")
+		indexIdentifierPool = identcheck.NewPool(identifierPool, identcheck.NewLoggingReportFn(
+			iopts.SetMetricsScope(iopts.MetricsScope().SubScope("index-identifier-leak-detection"))))
+	}
+
 	resultsPool := index.NewResultsPool(poolOptions(policy.IndexResultsPool,
 		scope.SubScope("index-results-pool")))
 	indexOpts := opts.IndexOptions().
 		SetInstrumentOptions(iopts).
 		SetMemSegmentOptions(
 			opts.IndexOptions().MemSegmentOptions().SetInstrumentOptions(iopts)).
-		SetIdentifierPool(identifierPool).
+		SetIdentifierPool(indexIdentifierPool).
 		SetCheckedBytesPool(bytesPool).
 		SetResultsPool(resultsPool)
 	resultsPool.Init(func() index.Results { return index.NewResults(indexOpts) })
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3x/ident"
+)
+
+var (
+	errRoutingTagNotInID = errors.New("series ID does not encode the value of its shard routing tag")
+)
+
+const (
+	tagPairSeparator  = byte(',')
+	tagValueSeparator = byte('=')
+)
+
+// RoutingID returns the ID that is hashed to pick the shard of a series when
+// routing by the given tag. Series IDs are expected to be in the coordinator's
+// tag ID format, i.e. "name=value,name=value,", and series without the tag
+// are routed by their ID. The format has no escaping so writes of series
+// whose ID does not yield the value of their tag must be rejected with
+// ValidateRoutingID, otherwise reads by ID are routed to a different shard.
+func RoutingID(id ident.ID, tagName []byte) ident.ID {
+	if len(tagName) == 0 {
+		return id
+	}
+	value, ok := tagValueFromID(id.Bytes(), tagName)
+	if !ok {
+		return id
+	}
+	return ident.BytesID(value)
+}
+
+// NewTagRoutingHashFn returns a HashFn which hashes only the value of the
+// given tag with the provided HashFn, so all series with the same tag value
+// are assigned the same shard.
+func NewTagRoutingHashFn(tagName []byte, fn HashFn) HashFn {
+	return func(id ident.ID) uint32 {
+		return fn(RoutingID(id, tagName))
+	}
+}
+
+// NewTagRoutingShardSet returns a shard set with the same shards as the
+// provided shard set which routes series by the value of the given tag.
+func NewTagRoutingShardSet(shardSet ShardSet, tagName []byte) ShardSet {
+	return newValidatedShardSet(shardSet.All(),
+		NewTagRoutingHashFn(tagName, shardSet.HashFn()))
+}
+
+// ValidateRoutingID returns an error if the ID of a series does not route it
+// by the value of the given tag in its tags, e.g. as the ID is a hash of the
+// tags or the tag value contains the separators of the tag ID format.
+func ValidateRoutingID(id ident.ID, tagName []byte, tags ident.TagIterator) error {
+	if len(tagName) == 0 {
+		return nil
+	}
+
+	var (
+		iter     = tags.Duplicate()
+		tagValue []byte
+		hasTag   bool
+	)
+	defer iter.Close()
+	for iter.Next() {
+		tag := iter.Current()
+		if bytes.Equal(tag.Name.Bytes(), tagName) {
+			tagValue, hasTag = tag.Value.Bytes(), true
+			break
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	idValue, idHasTag := tagValueFromID(id.Bytes(), tagName)
+	if hasTag != idHasTag || !bytes.Equal(tagValue, idValue) {
+		return fmt.Errorf("%v: id=%s, tag=%s", errRoutingTagNotInID,
+			id.String(), tagName)
+	}
+	return nil
+}
+
+func tagValueFromID(id, tagName []byte) ([]byte, bool) {
+	for len(id) > 0 {
+		var pair []byte
+		if idx := bytes.IndexByte(id, tagPairSeparator); idx >= 0 {
+			pair, id = id[:idx], id[idx+1:]
+		} else {
+			pair, id = id, nil
+		}
+
+		idx := bytes.IndexByte(pair, tagValueSeparator)
+		if idx < 0 {
+			continue
+		}
+		if bytes.Equal(pair[:idx], tagName) {
+			return pair[idx+1:], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"testing"
+
+	"github.com/m3db/m3cluster/shard"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutingID(t *testing.T) {
+	tenant := []byte("tenant")
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{id: "__name__=cpu,host=a,tenant=foo,", expected: "foo"},
+		{id: "tenant=bar,", expected: "bar"},
+		{id: "tenant=baz", expected: "baz"},
+		{id: "tenant=,host=a,", expected: ""},
+		{id: "subtenant=foo,host=a,", expected: "subtenant=foo,host=a,"},
+		{id: "opaque-id", expected: "opaque-id"},
+	}
+	for _, test := range tests {
+		actual := RoutingID(ident.StringID(test.id), tenant)
+		require.Equal(t, test.expected, actual.String(), test.id)
+	}
+
+	id := ident.StringID("tenant=foo,")
+	require.Equal(t, id, RoutingID(id, nil))
+}
+
+func TestValidateRoutingID(t *testing.T) {
+	tenant := []byte("tenant")
+	tests := []struct {
+		id    string
+		tags  ident.Tags
+		valid bool
+	}{
+		{
+			id:    "__name__=cpu,tenant=foo,",
+			tags:  ident.NewTags(ident.StringTag("__name__", "cpu"), ident.StringTag("tenant", "foo")),
+			valid: true,
+		},
+		{
+			id:    "__name__=cpu,",
+			tags:  ident.NewTags(ident.StringTag("__name__", "cpu")),
+			valid: true,
+		},
+		{
+			// hashed IDs do not encode the tag.
+			id:   "7f1a2b3c4d5e6f708192a3b4c5d6e7f8",
+			tags: ident.NewTags(ident.StringTag("tenant", "foo")),
+		},
+		{
+			// the tag value contains the pair separator.
+			id:   "host=a,tenant=foo,bar,",
+			tags: ident.NewTags(ident.StringTag("host", "a"), ident.StringTag("tenant", "foo,bar")),
+		},
+		{
+			// another tag value contains the tag.
+			id:   "a=x,tenant=evil,tenant=foo,",
+			tags: ident.NewTags(ident.StringTag("a", "x,tenant=evil"), ident.StringTag("tenant", "foo")),
+		},
+	}
+	for _, test := range tests {
+		tags := ident.NewTagsIterator(test.tags)
+		err := ValidateRoutingID(ident.StringID(test.id), tenant, tags)
+		require.Equal(t, test.valid, err == nil, test.id)
+		// the tags are not consumed.
+		require.Equal(t, len(test.tags.Values()), tags.Remaining())
+	}
+
+	require.NoError(t, ValidateRoutingID(ident.StringID("opaque"), nil,
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("tenant", "foo")))))
+}
+
+func TestTagRoutingShardSet(t *testing.T) {
+	shards := NewShards([]uint32{0, 1, 2, 3, 4, 5, 6, 7}, shard.Available)
+	ss, err := NewShardSet(shards, DefaultHashFn(len(shards)))
+	require.NoError(t, err)
+
+	routed := NewTagRoutingShardSet(ss, []byte("tenant"))
+	require.Equal(t, ss.AllIDs(), routed.AllIDs())
+
+	expected := ss.Lookup(ident.StringID("foo"))
+	for _, id := range []string{
+		"__name__=cpu,tenant=foo,",
+		"__name__=mem,host=a,tenant=foo,",
+		"__name__=disk,host=b,tenant=foo,zone=c,",
+	} {
+		require.Equal(t, expected, routed.Lookup(ident.StringID(id)))
+	}
+
+	noTag := ident.StringID("__name__=cpu,host=a,")
+	require.Equal(t, ss.Lookup(noTag), routed.Lookup(noTag))
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3x/ident"
+)
+
+const (
+	// BackgroundProcessTick is the tick of all namespaces.
+	BackgroundProcessTick BackgroundProcess = "tick"
+	// BackgroundProcessFlush is the flush and snapshot of all namespaces.
+	BackgroundProcessFlush BackgroundProcess = "flush"
+	// BackgroundProcessCleanup is the cleanup of expired files.
+	BackgroundProcessCleanup BackgroundProcess = "cleanup"
+
+	// maxBackgroundProcessPause bounds how long a background process can be
+	// paused so that a forgotten pause cannot stop flushing indefinitely.
+	maxBackgroundProcessPause = 24 * time.Hour
+
+	// maxBackgroundProcessMinInterval bounds the minimum interval between the
+	// runs of a background process for the same reason.
+	maxBackgroundProcessMinInterval = 24 * time.Hour
+)
+
+var (
+	backgroundProcesses = []BackgroundProcess{
+		BackgroundProcessTick,
+		BackgroundProcessFlush,
+		BackgroundProcessCleanup,
+	}
+
+	errInvalidPauseDuration = fmt.Errorf(
+		"pause duration must be positive and at most %v", maxBackgroundProcessPause)
+	errInvalidMinInterval = fmt.Errorf(
+		"minimum interval must not be negative and at most %v", maxBackgroundProcessMinInterval)
+	errUnknownBackgroundProcess = errors.New("unknown background process")
+)
+
+// BackgroundProcess is a background process of the database.
+type BackgroundProcess string
+
+// BackgroundProcessRun is the outcome of a run of a background process.
+type BackgroundProcessRun struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// BackgroundProcessSchedule is the schedule of a background process.
+type BackgroundProcessSchedule struct {
+	Process BackgroundProcess `json:"process"`
+	// LastRun is the last completed run, zero if not run yet.
+	LastRun BackgroundProcessRun `json:"lastRun"`
+	// NextRun is the estimated start of the next run from the interval
+	// between the last two runs, zero if not known yet.
+	NextRun time.Time `json:"nextRun"`
+	// Paused is whether the process is paused until PausedUntil.
+	Paused      bool      `json:"paused"`
+	PausedUntil time.Time `json:"pausedUntil"`
+	// MinInterval is the minimum interval between the starts of runs the
+	// schedule was adjusted to, zero if the process runs as often as it can.
+	MinInterval time.Duration `json:"minInterval,omitempty"`
+	// Namespaces is the last run of each namespace, for the processes that
+	// run per namespace.
+	Namespaces map[string]BackgroundProcessRun `json:"namespaces,omitempty"`
+}
+
+type backgroundProcessState struct {
+	lastRun     BackgroundProcessRun
+	interval    time.Duration
+	minInterval time.Duration
+	pausedUntil time.Time
+	namespaces  map[string]BackgroundProcessRun
+}
+
+type backgroundSchedules struct {
+	sync.RWMutex
+
+	nowFn  clock.NowFn
+	states map[BackgroundProcess]*backgroundProcessState
+}
+
+// NewBackgroundSchedules returns a new tracker of background process
+// schedules.
+func NewBackgroundSchedules(opts clock.Options) BackgroundSchedules {
+	states := make(map[BackgroundProcess]*backgroundProcessState, len(backgroundProcesses))
+	for _, p := range backgroundProcesses {
+		states[p] = &backgroundProcessState{
+			namespaces: make(map[string]BackgroundProcessRun),
+		}
+	}
+	return &backgroundSchedules{
+		nowFn:  opts.NowFn(),
+		states: states,
+	}
+}
+
+func (s *backgroundSchedules) Schedules() []BackgroundProcessSchedule {
+	now := s.nowFn()
+
+	s.RLock()
+	defer s.RUnlock()
+
+	schedules := make([]BackgroundProcessSchedule, 0, len(backgroundProcesses))
+	for _, p := range backgroundProcesses {
+		state := s.states[p]
+		schedule := BackgroundProcessSchedule{
+			Process:     p,
+			LastRun:     state.lastRun,
+			MinInterval: state.minInterval,
+		}
+		if interval := state.interval; interval > 0 || state.minInterval > 0 {
+			if interval < state.minInterval {
+				interval = state.minInterval
+			}
+			schedule.NextRun = state.lastRun.Start.Add(interval)
+		}
+		if now.Before(state.pausedUntil) {
+			schedule.Paused = true
+			schedule.PausedUntil = state.pausedUntil
+			if schedule.NextRun.Before(state.pausedUntil) {
+				schedule.NextRun = state.pausedUntil
+			}
+		}
+		if len(state.namespaces) > 0 {
+			schedule.Namespaces = make(map[string]BackgroundProcessRun, len(state.namespaces))
+			for ns, run := range state.namespaces {
+				schedule.Namespaces[ns] = run
+			}
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules
+}
+
+func (s *backgroundSchedules) Pause(process BackgroundProcess, d time.Duration) error {
+	if d <= 0 || d > maxBackgroundProcessPause {
+		return errInvalidPauseDuration
+	}
+	until := s.nowFn().Add(d)
+
+	s.Lock()
+	defer s.Unlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return errUnknownBackgroundProcess
+	}
+	state.pausedUntil = until
+	return nil
+}
+
+func (s *backgroundSchedules) Resume(process BackgroundProcess) error {
+	s.Lock()
+	defer s.Unlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return errUnknownBackgroundProcess
+	}
+	state.pausedUntil = time.Time{}
+	return nil
+}
+
+func (s *backgroundSchedules) Adjust(process BackgroundProcess, minInterval time.Duration) error {
+	if minInterval < 0 || minInterval > maxBackgroundProcessMinInterval {
+		return errInvalidMinInterval
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return errUnknownBackgroundProcess
+	}
+	state.minInterval = minInterval
+	return nil
+}
+
+func (s *backgroundSchedules) ShouldRun(process BackgroundProcess) bool {
+	now := s.nowFn()
+
+	s.RLock()
+	defer s.RUnlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return true
+	}
+	if now.Before(state.pausedUntil) {
+		return false
+	}
+	lastStart := state.lastRun.Start
+	return state.minInterval == 0 || lastStart.IsZero() ||
+		!now.Before(lastStart.Add(state.minInterval))
+}
+
+func (s *backgroundSchedules) IsPaused(process BackgroundProcess) bool {
+	now := s.nowFn()
+
+	s.RLock()
+	defer s.RUnlock()
+
+	state, ok := s.states[process]
+	return ok && now.Before(state.pausedUntil)
+}
+
+func (s *backgroundSchedules) RecordRun(process BackgroundProcess, start time.Time, err error) {
+	run := s.newRun(start, err)
+
+	s.Lock()
+	defer s.Unlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return
+	}
+	if prev := state.lastRun.Start; !prev.IsZero() {
+		state.interval = start.Sub(prev)
+	}
+	state.lastRun = run
+}
+
+func (s *backgroundSchedules) RecordNamespaceRun(
+	process BackgroundProcess,
+	namespace ident.ID,
+	start time.Time,
+	err error,
+) {
+	run := s.newRun(start, err)
+
+	s.Lock()
+	defer s.Unlock()
+
+	state, ok := s.states[process]
+	if !ok {
+		return
+	}
+	state.namespaces[namespace.String()] = run
+}
+
+func (s *backgroundSchedules) newRun(start time.Time, err error) BackgroundProcessRun {
+	run := BackgroundProcessRun{
+		Start:    start,
+		Duration: s.nowFn().Sub(start),
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	return run
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackgroundSchedulesRecordRun(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewBackgroundSchedules(clock.NewOptions().SetNowFn(func() time.Time { return now }))
+
+	start := now
+	now = now.Add(time.Second)
+	s.RecordRun(BackgroundProcessFlush, start, nil)
+	s.RecordNamespaceRun(BackgroundProcessFlush, ident.StringID("ns"), start, errTickCancelled)
+
+	now = now.Add(time.Minute)
+	s.RecordRun(BackgroundProcessFlush, start.Add(time.Minute), nil)
+
+	schedules := s.Schedules()
+	require.Len(t, schedules, len(backgroundProcesses))
+	flush := schedules[1]
+	assert.Equal(t, BackgroundProcessFlush, flush.Process)
+	assert.Equal(t, start.Add(time.Minute), flush.LastRun.Start)
+	assert.Equal(t, time.Second, flush.LastRun.Duration)
+	assert.Equal(t, start.Add(2*time.Minute), flush.NextRun)
+	assert.False(t, flush.Paused)
+	assert.Equal(t, errTickCancelled.Error(), flush.Namespaces["ns"].Error)
+}
+
+func TestBackgroundSchedulesPauseResume(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewBackgroundSchedules(clock.NewOptions().SetNowFn(func() time.Time { return now }))
+
+	assert.Equal(t, errInvalidPauseDuration, s.Pause(BackgroundProcessTick, 0))
+	assert.Equal(t, errInvalidPauseDuration, s.Pause(BackgroundProcessTick, 48*time.Hour))
+	assert.Equal(t, errUnknownBackgroundProcess, s.Pause("unknown", time.Minute))
+
+	require.NoError(t, s.Pause(BackgroundProcessTick, time.Minute))
+	assert.True(t, s.IsPaused(BackgroundProcessTick))
+	assert.False(t, s.IsPaused(BackgroundProcessFlush))
+	tick := s.Schedules()[0]
+	assert.True(t, tick.Paused)
+	assert.Equal(t, now.Add(time.Minute), tick.NextRun)
+
+	// Pauses expire.
+	now = now.Add(time.Minute)
+	assert.False(t, s.IsPaused(BackgroundProcessTick))
+
+	require.NoError(t, s.Pause(BackgroundProcessTick, time.Minute))
+	require.NoError(t, s.Resume(BackgroundProcessTick))
+	assert.False(t, s.IsPaused(BackgroundProcessTick))
+}
+
+func TestBackgroundSchedulesAdjust(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewBackgroundSchedules(clock.NewOptions().SetNowFn(func() time.Time { return now }))
+
+	assert.Equal(t, errInvalidMinInterval, s.Adjust(BackgroundProcessFlush, -time.Minute))
+	assert.Equal(t, errInvalidMinInterval, s.Adjust(BackgroundProcessFlush, 48*time.Hour))
+	assert.Equal(t, errUnknownBackgroundProcess, s.Adjust("unknown", time.Minute))
+
+	require.NoError(t, s.Adjust(BackgroundProcessFlush, 10*time.Minute))
+	assert.True(t, s.ShouldRun(BackgroundProcessFlush))
+
+	s.RecordRun(BackgroundProcessFlush, now, nil)
+	assert.False(t, s.ShouldRun(BackgroundProcessFlush))
+	assert.True(t, s.ShouldRun(BackgroundProcessCleanup))
+	flush := s.Schedules()[1]
+	assert.Equal(t, 10*time.Minute, flush.MinInterval)
+	assert.Equal(t, now.Add(10*time.Minute), flush.NextRun)
+
+	now = now.Add(10 * time.Minute)
+	assert.True(t, s.ShouldRun(BackgroundProcessFlush))
+
+	// pauses take precedence over the minimum interval.
+	require.NoError(t, s.Pause(BackgroundProcessFlush, time.Minute))
+	assert.False(t, s.ShouldRun(BackgroundProcessFlush))
+
+	require.NoError(t, s.Resume(BackgroundProcessFlush))
+	require.NoError(t, s.Adjust(BackgroundProcessFlush, 0))
+	s.RecordRun(BackgroundProcessFlush, now, nil)
+	assert.True(t, s.ShouldRun(BackgroundProcessFlush))
+}
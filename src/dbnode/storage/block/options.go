@@ -105,6 +105,39 @@ func NewOptions() Options {
 	return o
 }
 
+// NewCodecOptions returns a copy of the options with encoder and reader
+// iterator pools that encode and decode with the given codec.
+func NewCodecOptions(
+	opts Options,
+	codec encoding.Codec,
+	poolOpts pool.ObjectPoolOptions,
+) Options {
+	encoderPool := encoding.NewEncoderPool(poolOpts)
+	readerIteratorPool := encoding.NewReaderIteratorPool(poolOpts)
+	multiReaderIteratorPool := encoding.NewMultiReaderIteratorPool(poolOpts)
+
+	encodingOpts := encoding.NewOptions().
+		SetBytesPool(opts.BytesPool()).
+		SetEncoderPool(encoderPool).
+		SetReaderIteratorPool(readerIteratorPool).
+		SetSegmentReaderPool(opts.SegmentReaderPool())
+
+	encoderPool.Init(func() encoding.Encoder {
+		return codec.NewEncoder(timeZero, nil, encodingOpts)
+	})
+	readerIteratorPool.Init(func(r io.Reader) encoding.ReaderIterator {
+		return codec.NewReaderIterator(r, encodingOpts)
+	})
+	multiReaderIteratorPool.Init(func(r io.Reader) encoding.ReaderIterator {
+		return codec.NewReaderIterator(r, encodingOpts)
+	})
+
+	return opts.
+		SetEncoderPool(encoderPool).
+		SetReaderIteratorPool(readerIteratorPool).
+		SetMultiReaderIteratorPool(multiReaderIteratorPool)
+}
+
 func (o *options) SetClockOptions(value clock.Options) Options {
 	opts := *o
 	opts.clockOpts = value
@@ -175,6 +175,7 @@ func (m *bootstrapManager) bootstrap() error {
 	if err != nil {
 		return err
 	}
+	sortNamespacesByPriority(namespaces)
 
 	startBootstrap := m.nowFn()
 	for _, namespace := range namespaces {
@@ -195,11 +195,14 @@ func (s *commitLogSource) ReadData(
 		return nil, err
 	}
 
-	var (
-		bOpts     = s.opts.ResultOptions()
-		blOpts    = bOpts.DatabaseBlockOptions()
-		blockSize = ns.Options().RetentionOptions().BlockSize()
-	)
+	// Encode with the codec the namespace is configured to encode with.
+	blOpts, err := result.NamespaceDatabaseBlockOptions(
+		s.opts.ResultOptions(), ns.Options().Encoding())
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize := ns.Options().RetentionOptions().BlockSize()
 
 	// Determine the minimum number of commit logs files that we
 	// must read based on the available snapshot files.
@@ -326,6 +329,7 @@ func (s *commitLogSource) ReadData(
 		int(numShards),
 		blockSize,
 		shardDataByShard,
+		blOpts,
 	)
 	if err != nil {
 		return nil, err
@@ -955,6 +959,7 @@ func (s *commitLogSource) mergeAllShardsCommitLogEncodersAndSnapshots(
 	numShards int,
 	blockSize time.Duration,
 	unmerged []shardData,
+	blOpts block.Options,
 ) (result.DataBootstrapResult, error) {
 	var (
 		shardErrs       = make([]int, numShards)
@@ -1001,7 +1006,7 @@ func (s *commitLogSource) mergeAllShardsCommitLogEncodersAndSnapshots(
 		mergeShardFunc := func() {
 			var shardResult result.ShardResult
 			shardResult, shardEmptyErrs[shard], shardErrs[shard] = s.mergeShardCommitLogEncodersAndSnapshots(
-				shard, snapshotData, unmergedShard, blockSize)
+				shard, snapshotData, unmergedShard, blockSize, blOpts)
 
 			if shardResult != nil && shardResult.NumSeries() > 0 {
 				// Prevent race conditions while updating bootstrapResult from multiple go-routines
@@ -1031,10 +1036,9 @@ func (s *commitLogSource) mergeShardCommitLogEncodersAndSnapshots(
 	snapshotData result.ShardResult,
 	unmergedShard shardData,
 	blockSize time.Duration,
+	blOpts block.Options,
 ) (result.ShardResult, int, int) {
 	var (
-		bOpts                   = s.opts.ResultOptions()
-		blOpts                  = bOpts.DatabaseBlockOptions()
 		blocksPool              = blOpts.DatabaseBlockPool()
 		multiReaderIteratorPool = blOpts.MultiReaderIteratorPool()
 		segmentReaderPool       = blOpts.SegmentReaderPool()
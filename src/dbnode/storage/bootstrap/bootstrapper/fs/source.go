@@ -180,8 +180,8 @@ func (s *fileSystemSource) shardAvailability(
 		return xtime.Ranges{}
 	}
 
-	readInfoFilesResults := fs.ReadInfoFiles(s.fsopts.FilePathPrefix(),
-		namespace, shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.fsopts.FilePathPrefix(),
+		s.fsopts.WarmFilePathPrefix(), namespace, shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
 
 	var tr xtime.Ranges
 	for i := 0; i < len(readInfoFilesResults); i++ {
@@ -296,8 +296,8 @@ func (s *fileSystemSource) newShardReaders(
 	shard uint32,
 	tr xtime.Ranges,
 ) shardReaders {
-	readInfoFilesResults := fs.ReadInfoFiles(s.fsopts.FilePathPrefix(),
-		ns.ID(), shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.fsopts.FilePathPrefix(),
+		s.fsopts.WarmFilePathPrefix(), ns.ID(), shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
 	if len(readInfoFilesResults) == 0 {
 		return shardReaders{} // No readers
 	}
@@ -21,6 +21,7 @@
 package bootstrap
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -103,6 +104,10 @@ func (b bootstrapProcess) Run(
 	namespace namespace.Metadata,
 	shards []uint32,
 ) (ProcessResult, error) {
+	if err := b.validateInitialLookback(namespace); err != nil {
+		return ProcessResult{}, err
+	}
+
 	dataResult, err := b.bootstrapData(start, namespace, shards)
 	if err != nil {
 		return ProcessResult{}, err
@@ -114,11 +119,50 @@ func (b bootstrapProcess) Run(
 	}
 
 	return ProcessResult{
-		DataResult:  dataResult,
-		IndexResult: indexResult,
+		DataResult:   dataResult,
+		IndexResult:  indexResult,
+		SkippedRange: b.skippedRange(start, namespace.Options().RetentionOptions()),
 	}, nil
 }
 
+// validateInitialLookback ensures the initial lookback covers at least the
+// blocks that can still receive writes, otherwise those blocks would neither
+// be bootstrapped nor be safe to consider flushed.
+func (b bootstrapProcess) validateInitialLookback(namespace namespace.Metadata) error {
+	initial := b.processOpts.InitialLookback()
+	if initial <= 0 {
+		return nil
+	}
+	var (
+		ropts     = namespace.Options().RetentionOptions()
+		idxopts   = namespace.Options().IndexOptions()
+		blockSize = ropts.BlockSize()
+	)
+	if idxopts.Enabled() && idxopts.BlockSize() > blockSize {
+		blockSize = idxopts.BlockSize()
+	}
+	if min := blockSize + ropts.BufferPast(); initial < min {
+		return fmt.Errorf(
+			"bootstrap initial lookback %v for namespace %s is less than block size plus buffer past %v",
+			initial, namespace.ID().String(), min)
+	}
+	return nil
+}
+
+// skippedRange returns the range of data blocks within retention that are
+// older than the initial lookback and are therefore not bootstrapped.
+func (b bootstrapProcess) skippedRange(at time.Time, ropts retention.Options) xtime.Range {
+	var (
+		start  = at.Add(-ropts.RetentionPeriod()).Truncate(ropts.BlockSize())
+		end    = b.targetRangesForData(at, ropts)[0].Range.Start
+		result xtime.Range
+	)
+	if start.Before(end) {
+		result = xtime.Range{Start: start, End: end}
+	}
+	return result
+}
+
 func (b bootstrapProcess) bootstrapData(
 	at time.Time,
 	namespace namespace.Metadata,
@@ -269,7 +313,14 @@ func (b bootstrapProcess) targetRanges(
 	at time.Time,
 	opts targetRangesOptions,
 ) []TargetRange {
-	start := at.Add(-opts.retentionPeriod).
+	lookback := opts.retentionPeriod
+	if initial := b.processOpts.InitialLookback(); initial > 0 && initial < lookback {
+		// NB: Only bootstrap the most recent blocks so a node that has lost
+		// all of its data can start serving writes quickly, older blocks
+		// are returned as skipped so they're never flushed empty.
+		lookback = initial
+	}
+	start := at.Add(-lookback).
 		Truncate(opts.blockSize)
 	midPoint := at.
 		Add(-opts.blockSize).
@@ -278,6 +329,9 @@ func (b bootstrapProcess) targetRanges(
 		// NB(r): Since "end" is exclusive we need to add a
 		// an extra block size when specifying the end time.
 		Add(opts.blockSize)
+	if start.After(midPoint) {
+		start = midPoint
+	}
 	cutover := at.Add(opts.bufferFuture).
 		Truncate(opts.blockSize).
 		Add(opts.blockSize)
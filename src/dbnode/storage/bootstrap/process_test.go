@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bootstrap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetRangesInitialLookback(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		at        = time.Unix(0, 0).Add(30 * 24 * time.Hour).Add(30 * time.Minute)
+		opts      = targetRangesOptions{
+			retentionPeriod: 48 * time.Hour,
+			blockSize:       blockSize,
+			bufferPast:      10 * time.Minute,
+			bufferFuture:    2 * time.Minute,
+		}
+	)
+
+	process := bootstrapProcess{processOpts: NewProcessOptions()}
+	ranges := process.targetRanges(at, opts)
+	require.Equal(t, 2, len(ranges))
+	require.Equal(t, at.Add(-48*time.Hour).Truncate(blockSize), ranges[0].Range.Start)
+
+	process.processOpts = NewProcessOptions().SetInitialLookback(6 * time.Hour)
+	ranges = process.targetRanges(at, opts)
+	require.Equal(t, 2, len(ranges))
+	require.Equal(t, at.Add(-6*time.Hour).Truncate(blockSize), ranges[0].Range.Start)
+	require.Equal(t, ranges[0].Range.End, ranges[1].Range.Start)
+
+	// A lookback longer than retention is bounded by retention.
+	process.processOpts = NewProcessOptions().SetInitialLookback(96 * time.Hour)
+	ranges = process.targetRanges(at, opts)
+	require.Equal(t, at.Add(-48*time.Hour).Truncate(blockSize), ranges[0].Range.Start)
+}
+
+func TestSkippedRangeInitialLookback(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		at        = time.Unix(0, 0).Add(30 * 24 * time.Hour).Add(30 * time.Minute)
+		ropts     = retention.NewOptions().
+				SetRetentionPeriod(48 * time.Hour).
+				SetBlockSize(blockSize).
+				SetBufferPast(10 * time.Minute).
+				SetBufferFuture(2 * time.Minute)
+	)
+
+	process := bootstrapProcess{processOpts: NewProcessOptions()}
+	require.True(t, process.skippedRange(at, ropts).IsEmpty())
+
+	process.processOpts = NewProcessOptions().SetInitialLookback(6 * time.Hour)
+	skipped := process.skippedRange(at, ropts)
+	require.Equal(t, at.Add(-48*time.Hour).Truncate(blockSize), skipped.Start)
+	require.Equal(t, at.Add(-6*time.Hour).Truncate(blockSize), skipped.End)
+}
+
+func TestValidateInitialLookback(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("ns"), namespace.NewOptions().
+		SetRetentionOptions(retention.NewOptions().
+			SetBlockSize(2*time.Hour).
+			SetBufferPast(10*time.Minute)).
+		SetIndexOptions(namespace.NewIndexOptions().
+			SetEnabled(true).
+			SetBlockSize(4*time.Hour)))
+	require.NoError(t, err)
+
+	process := bootstrapProcess{processOpts: NewProcessOptions()}
+	require.NoError(t, process.validateInitialLookback(md))
+
+	// Must cover the index block size plus buffer past.
+	process.processOpts = NewProcessOptions().SetInitialLookback(4 * time.Hour)
+	require.Error(t, process.validateInitialLookback(md))
+
+	process.processOpts = NewProcessOptions().SetInitialLookback(4*time.Hour + 10*time.Minute)
+	require.NoError(t, process.validateInitialLookback(md))
+}
@@ -20,6 +20,10 @@
 
 package bootstrap
 
+import (
+	"time"
+)
+
 const (
 	// defaultCacheSeriesMetadata declares that by default bootstrap providers should
 	// cache series metadata between runs.
@@ -28,6 +32,7 @@ const (
 
 type processOptions struct {
 	cacheSeriesMetadata bool
+	initialLookback     time.Duration
 }
 
 // NewProcessOptions creates new bootstrap run options
@@ -46,3 +51,13 @@ func (o *processOptions) SetCacheSeriesMetadata(value bool) ProcessOptions {
 func (o *processOptions) CacheSeriesMetadata() bool {
 	return o.cacheSeriesMetadata
 }
+
+func (o *processOptions) SetInitialLookback(value time.Duration) ProcessOptions {
+	opts := *o
+	opts.initialLookback = value
+	return &opts
+}
+
+func (o *processOptions) InitialLookback() time.Duration {
+	return o.initialLookback
+}
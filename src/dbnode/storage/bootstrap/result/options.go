@@ -21,7 +21,12 @@
 package result
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3x/instrument"
@@ -31,6 +36,10 @@ const (
 	defaultNewBlocksLen = 2
 )
 
+var (
+	errCodecRegistryNotSet = errors.New("encoding codec registry is not set")
+)
+
 type options struct {
 	clockOpts               clock.Options
 	instrumentOpts          instrument.Options
@@ -38,10 +47,16 @@ type options struct {
 	newBlocksLen            int
 	seriesCachePolicy       series.CachePolicy
 	mutableSegmentAllocator MutableSegmentAllocator
+	codecRegistry           encoding.CodecRegistry
 }
 
 // NewOptions creates new bootstrap options
 func NewOptions() Options {
+	codecRegistry, err := m3tsz.NewCodecRegistry()
+	if err != nil {
+		// NB: the default codecs have distinct names so this is an invariant.
+		panic(fmt.Errorf("unable to register default codecs: %v", err))
+	}
 	return &options{
 		clockOpts:               clock.NewOptions(),
 		instrumentOpts:          instrument.NewOptions(),
@@ -49,6 +64,7 @@ func NewOptions() Options {
 		newBlocksLen:            defaultNewBlocksLen,
 		seriesCachePolicy:       series.DefaultCachePolicy,
 		mutableSegmentAllocator: NewDefaultMutableSegmentAllocator(),
+		codecRegistry:           codecRegistry,
 	}
 }
 
@@ -111,3 +127,35 @@ func (o *options) SetIndexMutableSegmentAllocator(value MutableSegmentAllocator)
 func (o *options) IndexMutableSegmentAllocator() MutableSegmentAllocator {
 	return o.mutableSegmentAllocator
 }
+
+func (o *options) SetEncodingCodecRegistry(value encoding.CodecRegistry) Options {
+	opts := *o
+	opts.codecRegistry = value
+	return &opts
+}
+
+func (o *options) EncodingCodecRegistry() encoding.CodecRegistry {
+	return o.codecRegistry
+}
+
+// NamespaceDatabaseBlockOptions returns the database block options to use for
+// a namespace that encodes with the named codec, the default database block
+// options are returned if the name is empty.
+func NamespaceDatabaseBlockOptions(
+	opts Options,
+	codecName string,
+) (block.Options, error) {
+	blockOpts := opts.DatabaseBlockOptions()
+	if codecName == "" {
+		return blockOpts, nil
+	}
+	registry := opts.EncodingCodecRegistry()
+	if registry == nil {
+		return nil, errCodecRegistryNotSet
+	}
+	codec, err := registry.Codec(codecName)
+	if err != nil {
+		return nil, err
+	}
+	return block.NewCodecOptions(blockOpts, codec, nil), nil
+}
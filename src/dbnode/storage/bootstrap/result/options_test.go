@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package result
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceDatabaseBlockOptions(t *testing.T) {
+	opts := NewOptions()
+
+	blOpts, err := NamespaceDatabaseBlockOptions(opts, "")
+	require.NoError(t, err)
+	require.Equal(t, opts.DatabaseBlockOptions(), blOpts)
+
+	_, err = NamespaceDatabaseBlockOptions(opts, "unknown")
+	require.Error(t, err)
+
+	_, err = NamespaceDatabaseBlockOptions(
+		opts.SetEncodingCodecRegistry(nil), m3tsz.IntDeltaCodecName)
+	require.Error(t, err)
+
+	blOpts, err = NamespaceDatabaseBlockOptions(opts, m3tsz.IntDeltaCodecName)
+	require.NoError(t, err)
+
+	start := time.Now().Truncate(time.Hour)
+	encode := func(pool encoding.EncoderPool) []byte {
+		enc := pool.Get()
+		enc.Reset(start, 0)
+		for i := 0; i < 10; i++ {
+			dp := ts.Datapoint{
+				Timestamp: start.Add(time.Duration(i) * time.Second),
+				Value:     float64(i),
+			}
+			require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+		}
+		b, err := ioutil.ReadAll(enc.Stream())
+		require.NoError(t, err)
+		return b
+	}
+
+	encoded := encode(blOpts.EncoderPool())
+	require.NotEqual(t, encode(opts.DatabaseBlockOptions().EncoderPool()), encoded)
+
+	iter := blOpts.ReaderIteratorPool().Get()
+	iter.Reset(bytes.NewReader(encoded))
+	var n int
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		require.Equal(t, float64(n), dp.Value)
+		n++
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, 10, n)
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
@@ -160,4 +161,12 @@ type Options interface {
 
 	// IndexMutableSegmentAllocator returns the index mutable segment allocator.
 	IndexMutableSegmentAllocator() MutableSegmentAllocator
+
+	// SetEncodingCodecRegistry sets the registry of codecs namespaces can
+	// encode with.
+	SetEncodingCodecRegistry(value encoding.CodecRegistry) Options
+
+	// EncodingCodecRegistry returns the registry of codecs namespaces can
+	// encode with.
+	EncodingCodecRegistry() encoding.CodecRegistry
 }
@@ -55,6 +55,10 @@ type Process interface {
 type ProcessResult struct {
 	DataResult  result.DataBootstrapResult
 	IndexResult result.IndexBootstrapResult
+	// SkippedRange is the range of data blocks within retention older than
+	// the initial lookback that were not bootstrapped, empty if the entire
+	// retention period was bootstrapped.
+	SkippedRange xtime.Range
 }
 
 // TargetRange is a bootstrap target range.
@@ -75,6 +79,17 @@ type ProcessOptions interface {
 	// CacheSeriesMetadata returns whether bootstrappers created by this
 	// provider should cache series metadata between runs.
 	CacheSeriesMetadata() bool
+
+	// SetInitialLookback sets how far back from the bootstrap time data and
+	// index blocks are bootstrapped, zero means the full retention period.
+	// It must be at least the block size plus buffer past of each namespace.
+	// Older blocks are marked as flushed so they are never flushed empty,
+	// only data still on disk for them is served.
+	SetInitialLookback(value time.Duration) ProcessOptions
+
+	// InitialLookback returns how far back from the bootstrap time data and
+	// index blocks are bootstrapped, zero means the full retention period.
+	InitialLookback() time.Duration
 }
 
 // RunOptions is a set of options for a bootstrap run.
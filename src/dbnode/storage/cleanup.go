@@ -22,6 +22,7 @@ package storage
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -41,6 +42,16 @@ type deleteFilesFn func(files []string) error
 
 type deleteInactiveDirectoriesFn func(parentDirPath string, activeDirNames []string) error
 
+type moveDataFileSetsToWarmFn func(
+	hotFilePathPrefix string,
+	warmFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	t time.Time,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) (int, error)
+
 type cleanupManager struct {
 	sync.RWMutex
 
@@ -52,8 +63,11 @@ type cleanupManager struct {
 	commitLogFilesFn            commitLogFilesFn
 	deleteFilesFn               deleteFilesFn
 	deleteInactiveDirectoriesFn deleteInactiveDirectoriesFn
+	moveDataFileSetsToWarmFn    moveDataFileSetsToWarmFn
 	cleanupInProgress           bool
 	status                      tally.Gauge
+	warmTierMoved               tally.Counter
+	commitLogForcedCleanups     tally.Counter
 }
 
 func newCleanupManager(database database, scope tally.Scope) databaseCleanupManager {
@@ -70,7 +84,10 @@ func newCleanupManager(database database, scope tally.Scope) databaseCleanupMana
 		commitLogFilesFn:            commitlog.Files,
 		deleteFilesFn:               fs.DeleteFiles,
 		deleteInactiveDirectoriesFn: fs.DeleteInactiveDirectories,
-		status: scope.Gauge("cleanup"),
+		moveDataFileSetsToWarmFn:    fs.MoveDataFileSetsToWarmBefore,
+		status:                      scope.Gauge("cleanup"),
+		warmTierMoved:               scope.Counter("warm-tier-moved"),
+		commitLogForcedCleanups:     scope.Counter("commitlog-forced-cleanups"),
 	}
 }
 
@@ -91,6 +108,11 @@ func (m *cleanupManager) Cleanup(t time.Time) error {
 			"encountered errors when cleaning up data files for %v: %v", t, err))
 	}
 
+	if err := m.moveDataFilesToWarmTier(t); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when moving data files to warm tier for %v: %v", t, err))
+	}
+
 	if err := m.cleanupExpiredIndexFiles(t); err != nil {
 		multiErr = multiErr.Add(fmt.Errorf(
 			"encountered errors when cleaning up index files for %v: %v", t, err))
@@ -146,8 +168,6 @@ func (m *cleanupManager) Report() {
 
 func (m *cleanupManager) deleteInactiveNamespaceFiles() error {
 	var namespaceDirNames []string
-	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	dataDirPath := fs.DataDirPath(filePathPrefix)
 	namespaces, err := m.database.GetOwnedNamespaces()
 	if err != nil {
 		return err
@@ -157,29 +177,54 @@ func (m *cleanupManager) deleteInactiveNamespaceFiles() error {
 		namespaceDirNames = append(namespaceDirNames, n.ID().String())
 	}
 
-	return m.deleteInactiveDirectoriesFn(dataDirPath, namespaceDirNames)
+	multiErr := xerrors.NewMultiError()
+	for _, filePathPrefix := range m.dataFilePathPrefixes() {
+		dataDirPath := fs.DataDirPath(filePathPrefix)
+		multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(dataDirPath, namespaceDirNames))
+	}
+	return multiErr.FinalError()
 }
 
 // deleteInactiveDataFiles will delete data files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath)
+	multiErr := xerrors.NewMultiError()
+	for _, filePathPrefix := range m.dataFilePathPrefixes() {
+		multiErr = multiErr.Add(m.deleteInactiveDataFileSetFiles(filePathPrefix,
+			fs.NamespaceDataDirPath))
+	}
+	return multiErr.FinalError()
 }
 
 // deleteInactiveDataSnapshotFiles will delete snapshot files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataSnapshotFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath)
+	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	return m.deleteInactiveDataFileSetFiles(filePathPrefix, fs.NamespaceSnapshotsDirPath)
 }
 
-func (m *cleanupManager) deleteInactiveDataFileSetFiles(filesetFilesDirPathFn func(string, ident.ID) string) error {
+// dataFilePathPrefixes returns the file path prefixes that may hold data filesets,
+// the hot prefix followed by the warm prefix if the warm tier is enabled.
+func (m *cleanupManager) dataFilePathPrefixes() []string {
+	fsOpts := m.database.Options().CommitLogOptions().FilesystemOptions()
+	filePathPrefixes := []string{fsOpts.FilePathPrefix()}
+	if warmFilePathPrefix := fsOpts.WarmFilePathPrefix(); warmFilePathPrefix != "" {
+		filePathPrefixes = append(filePathPrefixes, warmFilePathPrefix)
+	}
+	return filePathPrefixes
+}
+
+func (m *cleanupManager) deleteInactiveDataFileSetFiles(
+	filePathPrefix string,
+	filesetFilesDirPathFn func(string, ident.ID) string,
+) error {
 	multiErr := xerrors.NewMultiError()
-	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	namespaces, err := m.database.GetOwnedNamespaces()
 	if err != nil {
 		return err
 	}
 	for _, n := range namespaces {
+		// NB: read only shards are owned so their files are kept.
 		var activeShards []string
 		namespaceDirPath := filesetFilesDirPathFn(filePathPrefix, n.ID())
 		for _, s := range n.GetOwnedShards() {
@@ -203,12 +248,42 @@ func (m *cleanupManager) cleanupExpiredDataFiles(t time.Time) error {
 			continue
 		}
 		earliestToRetain := retention.FlushTimeStart(n.Options().RetentionOptions(), t)
-		shards := n.GetOwnedShards()
+		// NB: read only shards are not assigned to the node, keep their
+		// files as they were found on disk.
+		shards := n.GetWritableShards()
 		multiErr = multiErr.Add(m.cleanupExpiredNamespaceDataFiles(earliestToRetain, shards))
 	}
 	return multiErr.FinalError()
 }
 
+// moveDataFilesToWarmTier moves the data filesets of blocks that ended at least
+// the configured warm tier duration ago from the hot prefix to the warm prefix.
+func (m *cleanupManager) moveDataFilesToWarmTier(t time.Time) error {
+	fsOpts := m.database.Options().CommitLogOptions().FilesystemOptions()
+	warmFilePathPrefix := fsOpts.WarmFilePathPrefix()
+	if warmFilePathPrefix == "" {
+		return nil
+	}
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		blockSize := n.Options().RetentionOptions().BlockSize()
+		moveBefore := t.Add(-fsOpts.WarmTierAfter()).Add(-blockSize)
+		for _, shard := range n.GetWritableShards() {
+			moved, err := m.moveDataFileSetsToWarmFn(fsOpts.FilePathPrefix(),
+				warmFilePathPrefix, n.ID(), shard.ID(), moveBefore,
+				fsOpts.NewFileMode(), fsOpts.NewDirectoryMode())
+			m.warmTierMoved.Inc(int64(moved))
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(t time.Time) error {
 	namespaces, err := m.database.GetOwnedNamespaces()
 	if err != nil {
@@ -237,7 +312,7 @@ func (m *cleanupManager) cleanupDataSnapshotFiles(t time.Time) error {
 	}
 	for _, n := range namespaces {
 		earliestToRetain := retention.FlushTimeStart(n.Options().RetentionOptions(), t)
-		shards := n.GetOwnedShards()
+		shards := n.GetWritableShards()
 		if n.Options().CleanupEnabled() {
 			multiErr = multiErr.Add(m.cleanupNamespaceSnapshotFiles(earliestToRetain, shards))
 		}
@@ -270,7 +345,9 @@ func (m *cleanupManager) cleanupNamespaceSnapshotFiles(earliestToRetain time.Tim
 // NB(xichen): since each commit log contains data needed for bootstrapping not only
 // its own block size period but also its left and right block neighbors due to past
 // writes and future writes, we need to shift flush time range by block size as the
-// time range for commit log files we need to check.
+// time range for commit log files we need to check. Commit log files are keyed by
+// system time and retained by the commit log's own retention period rather than a
+// namespace's, so namespace retention time zones intentionally do not apply here.
 func (m *cleanupManager) commitLogTimeRange(t time.Time) (time.Time, time.Time) {
 	var (
 		copts      = m.opts.CommitLogOptions()
@@ -352,7 +429,58 @@ func (m *cleanupManager) commitLogTimes(t time.Time) ([]commitlog.File, error) {
 		return true, nil
 	}
 
-	return filterCommitLogFiles(files, shouldCleanupFile)
+	filesToCleanup, err := filterCommitLogFiles(files, shouldCleanupFile)
+	if err != nil {
+		return nil, err
+	}
+	return m.boundCommitLogFiles(files, filesToCleanup), nil
+}
+
+// boundCommitLogFiles adds the oldest commit log files to the files to clean up
+// until the files retained fit within the commit log max retained bytes, even if
+// the data they contain has not been flushed or captured by a snapshot. The most
+// recent file is never selected as it is the one being actively written to.
+func (m *cleanupManager) boundCommitLogFiles(
+	files []commitlog.File,
+	filesToCleanup []commitlog.File,
+) []commitlog.File {
+	maxRetainedBytes := m.opts.CommitLogOptions().MaxRetainedBytes()
+	if maxRetainedBytes <= 0 || len(files) == 0 {
+		return filesToCleanup
+	}
+
+	cleanup := make(map[string]struct{}, len(filesToCleanup))
+	for _, f := range filesToCleanup {
+		cleanup[f.FilePath] = struct{}{}
+	}
+
+	var retainedBytes int64
+	for _, f := range files {
+		if _, ok := cleanup[f.FilePath]; !ok {
+			retainedBytes += f.Size
+		}
+	}
+
+	var forced int64
+	for _, f := range files[:len(files)-1] {
+		if retainedBytes <= maxRetainedBytes {
+			break
+		}
+		if _, ok := cleanup[f.FilePath]; ok {
+			continue
+		}
+		filesToCleanup = append(filesToCleanup, f)
+		retainedBytes -= f.Size
+		forced++
+	}
+
+	if forced > 0 {
+		m.commitLogForcedCleanups.Inc(forced)
+		m.opts.InstrumentOptions().Logger().Warnf(
+			"forcibly cleaning up %d commit log files that may contain unflushed data "+
+				"to retain at most %d bytes of commit logs", forced, maxRetainedBytes)
+	}
+	return filesToCleanup
 }
 
 // commitLogNamespaceBlockTimes returns the range of namespace block starts for which the
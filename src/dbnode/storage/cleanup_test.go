@@ -23,6 +23,7 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -69,6 +70,7 @@ func TestCleanupManagerCleanup(t *testing.T) {
 		ns.EXPECT().Options().Return(nsOpts).AnyTimes()
 		ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
 		ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
+		ns.EXPECT().GetWritableShards().Return(nil).AnyTimes()
 		namespaces = append(namespaces, ns)
 	}
 	db := newMockdatabase(ctrl, namespaces...)
@@ -114,6 +116,7 @@ func TestCleanupManagerNamespaceCleanup(t *testing.T) {
 	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
 	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
+	ns.EXPECT().GetWritableShards().Return(nil).AnyTimes()
 
 	idx := NewMocknamespaceIndex(ctrl)
 	ns.EXPECT().GetIndex().Return(idx, nil)
@@ -176,6 +179,7 @@ func TestCleanupDataAndSnapshotFileSetFiles(t *testing.T) {
 	shard.EXPECT().CleanupSnapshots(expectedEarliestToRetain)
 	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
 	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	ns.EXPECT().GetWritableShards().Return([]databaseShard{shard}).AnyTimes()
 	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
 	namespaces := []databaseNamespace{ns}
@@ -205,6 +209,7 @@ func TestDeleteInactiveDataAndSnapshotFileSetFiles(t *testing.T) {
 	shard := NewMockdatabaseShard(ctrl)
 	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
 	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	ns.EXPECT().GetWritableShards().Return([]databaseShard{shard}).AnyTimes()
 	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
 	namespaces := []databaseNamespace{ns}
@@ -252,6 +257,56 @@ func TestDeleteInactiveDataAndSnapshotFileSetFiles(t *testing.T) {
 	}
 }
 
+func TestCleanupManagerMovesDataFilesToWarmTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := timeFor(36000)
+	rOpts := retention.NewOptions().SetBlockSize(time.Hour)
+	nsOpts := namespace.NewOptions().SetRetentionOptions(rOpts)
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(3)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	ns.EXPECT().GetWritableShards().Return([]databaseShard{shard}).AnyTimes()
+
+	opts := testDatabaseOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix("hot").
+		SetWarmFilePathPrefix("warm").
+		SetWarmTierAfter(2 * time.Hour)
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil).AnyTimes()
+	mgr := newCleanupManager(db, tally.NoopScope).(*cleanupManager)
+
+	var moveBefore []time.Time
+	mgr.moveDataFileSetsToWarmFn = func(
+		hotFilePathPrefix string,
+		warmFilePathPrefix string,
+		namespace ident.ID,
+		shard uint32,
+		t time.Time,
+		_ os.FileMode,
+		_ os.FileMode,
+	) (int, error) {
+		require.Equal(t, "hot", hotFilePathPrefix)
+		require.Equal(t, "warm", warmFilePathPrefix)
+		require.Equal(t, "nsID", namespace.String())
+		require.Equal(t, uint32(3), shard)
+		moveBefore = append(moveBefore, t)
+		return 1, nil
+	}
+
+	require.NoError(t, mgr.moveDataFilesToWarmTier(ts))
+	require.Equal(t, []time.Time{ts.Add(-3 * time.Hour)}, moveBefore)
+}
+
 func TestCleanupManagerPropagatesGetOwnedNamespacesError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -614,6 +669,33 @@ func TestCleanupManagerCommitLogTimesAllPendingFlush(t *testing.T) {
 	require.Equal(t, 0, len(filesToCleanup))
 }
 
+func TestCleanupManagerCommitLogTimesExceedsMaxRetainedBytes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns, mgr := newCleanupManagerCommitLogTimesTest(t, ctrl)
+	mgr.opts = mgr.opts.SetCommitLogOptions(
+		mgr.opts.CommitLogOptions().SetMaxRetainedBytes(250))
+	mgr.commitLogFilesFn = func(_ commitlog.Options) ([]commitlog.File, error) {
+		return []commitlog.File{
+			commitlog.File{FilePath: "a", Start: time10, Duration: commitLogBlockSize, Size: 100},
+			commitlog.File{FilePath: "b", Start: time20, Duration: commitLogBlockSize, Size: 100},
+			commitlog.File{FilePath: "c", Start: time20, Duration: commitLogBlockSize, Index: 1, Size: 100},
+			commitlog.File{FilePath: "d", Start: time30, Duration: commitLogBlockSize, Size: 100},
+		}, nil
+	}
+
+	ns.EXPECT().IsCapturedBySnapshot(
+		gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
+
+	filesToCleanup, err := mgr.commitLogTimes(currentTime)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(filesToCleanup))
+	require.Equal(t, "a", filesToCleanup[0].FilePath)
+	require.Equal(t, "b", filesToCleanup[1].FilePath)
+}
+
 func timeFor(s int64) time.Time {
 	return time.Unix(s, 0)
 }
@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/topology"
@@ -66,11 +67,12 @@ func newDatabaseMetrics(scope tally.Scope) databaseMetrics {
 type clusterDB struct {
 	storage.Database
 
-	log     xlog.Logger
-	metrics databaseMetrics
-	hostID  string
-	topo    topology.Topology
-	watch   topology.MapWatch
+	log       xlog.Logger
+	newTicker clock.NewTickerFn
+	metrics   databaseMetrics
+	hostID    string
+	topo      topology.Topology
+	watch     topology.MapWatch
 
 	watchMutex sync.Mutex
 	watching   bool
@@ -79,6 +81,10 @@ type clusterDB struct {
 
 	initializing   map[uint32]shard.Shard
 	bootstrapCount map[uint32]int
+
+	// readOnlyShards are the shards found on disk at startup that are not
+	// assigned to the host and are opened to serve reads.
+	readOnlyShards []uint32
 }
 
 // NewDatabase creates a new clustered time series database
@@ -108,6 +114,7 @@ func NewDatabase(
 
 	d := &clusterDB{
 		log:            log,
+		newTicker:      opts.ClockOptions().NewTickerFn(),
 		metrics:        m,
 		hostID:         hostID,
 		topo:           topo,
@@ -116,7 +123,24 @@ func NewDatabase(
 		bootstrapCount: make(map[uint32]int),
 	}
 
-	shardSet := d.hostOrEmptyShardSet(watch.Get())
+	topoMap := watch.Get()
+	hostShardSet, ok := topoMap.LookupHostShardSet(hostID)
+	if !ok {
+		hostShardSet = nil
+	}
+	verifiedOpts, err := verifyShardOwnership(hostShardSet, opts, log)
+	switch {
+	case err == errShardOwnershipHostNotInPlacement:
+		log.Warnf("skipping shard ownership verification for host ID %s: %v", hostID, err)
+	case err != nil:
+		return nil, err
+	default:
+		opts = verifiedOpts
+	}
+	d.readOnlyShards = opts.ReadOnlyShards()
+
+	shardSet := d.hostOrEmptyShardSet(topoMap)
+
 	db, err := newStorageDatabase(shardSet, opts)
 	if err != nil {
 		return nil, err
@@ -189,10 +213,10 @@ func (d *clusterDB) activeTopologyWatch() {
 	reportClosingCh := make(chan struct{}, 1)
 	reportClosedCh := make(chan struct{}, 1)
 	go func() {
-		ticker := time.NewTicker(time.Second)
+		ticker := d.newTicker(time.Second)
 		for {
 			select {
-			case <-ticker.C:
+			case <-ticker.C():
 				d.analyzeAndReportShardStates()
 			case <-reportClosingCh:
 				ticker.Stop()
@@ -344,9 +368,38 @@ func (d *clusterDB) resetBootstrapCount() {
 // found the shard set for the host the second parameter returns true,
 // otherwise false.
 func (d *clusterDB) hostOrEmptyShardSet(m topology.Map) sharding.ShardSet {
-	if hostShardSet, ok := m.LookupHostShardSet(d.hostID); ok {
-		return hostShardSet.ShardSet()
+	hostShardSet, ok := m.LookupHostShardSet(d.hostID)
+	if !ok {
+		d.log.Warnf("topology has no shard set for host ID: %s", d.hostID)
+		return d.withReadOnlyShards(sharding.NewEmptyShardSet(m.ShardSet().HashFn()))
+	}
+	return d.withReadOnlyShards(hostShardSet.ShardSet())
+}
+
+// withReadOnlyShards returns the shard set with the read only shards not
+// assigned to the host added in the read only state so that they are opened
+// to serve reads, once assigned a shard takes its state from the placement.
+func (d *clusterDB) withReadOnlyShards(shardSet sharding.ShardSet) sharding.ShardSet {
+	if len(d.readOnlyShards) == 0 {
+		return shardSet
+	}
+	shards := shardSet.All()
+	assigned := make(map[uint32]struct{}, len(shards))
+	for _, s := range shards {
+		assigned[s.ID()] = struct{}{}
+	}
+	merged := append([]shard.Shard(nil), shards...)
+	for _, id := range d.readOnlyShards {
+		if _, ok := assigned[id]; ok {
+			continue
+		}
+		merged = append(merged, shard.NewShard(id).SetState(storage.ReadOnlyShardState))
+	}
+	result, err := sharding.NewShardSet(merged, shardSet.HashFn())
+	if err != nil {
+		// should never happen, the shards are unique
+		d.log.Errorf("unable to add read only shards to shard set: %v", err)
+		return shardSet
 	}
-	d.log.Warnf("topology has no shard set for host ID: %s", d.hostID)
-	return sharding.NewEmptyShardSet(m.ShardSet().HashFn())
+	return result
 }
@@ -413,3 +413,25 @@ func TestDatabaseCloseCanRetry(t *testing.T) {
 	err = db.Close()
 	require.NoError(t, err)
 }
+
+func TestDatabaseShardSetIncludesReadOnlyShards(t *testing.T) {
+	d := &clusterDB{
+		log:            storage.NewOptions().InstrumentOptions().Logger(),
+		readOnlyShards: []uint32{1, 3},
+	}
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1}, shard.Initializing), sharding.DefaultHashFn(4))
+	require.NoError(t, err)
+
+	result := d.withReadOnlyShards(shardSet)
+	require.Equal(t, []uint32{0, 1, 3}, result.AllIDs())
+
+	// Assigned shards keep their state.
+	state, err := result.LookupStateByID(1)
+	require.NoError(t, err)
+	require.Equal(t, shard.Initializing, state)
+	state, err = result.LookupStateByID(3)
+	require.NoError(t, err)
+	require.Equal(t, storage.ReadOnlyShardState, state)
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3cluster/shard"
+	xerrors "github.com/m3db/m3x/errors"
+	xlog "github.com/m3db/m3x/log"
+)
+
+var (
+	errShardOwnershipHostNotInPlacement = errors.New(
+		"unable to verify shard ownership, host has no shard set in the placement")
+)
+
+// unexpectedShard is a fileset shard directory on disk for a shard that
+// is not assigned to the host.
+type unexpectedShard struct {
+	filePathPrefix string
+	// relPath is the path of the directory relative to the file path
+	// prefix, e.g. data/metrics/12.
+	relPath string
+	shard   uint32
+}
+
+// findUnexpectedShards returns the data and snapshot fileset shard
+// directories under the file path prefixes for shards not owned by the host,
+// initializing and leaving shards are owned since their data is either being
+// streamed to or from the host.
+func findUnexpectedShards(
+	filePathPrefixes []string,
+	shardSet sharding.ShardSet,
+) ([]unexpectedShard, error) {
+	assigned := make(map[uint32]struct{}, len(shardSet.All()))
+	for _, s := range shardSet.All() {
+		switch s.State() {
+		case shard.Initializing, shard.Available, shard.Leaving:
+			assigned[s.ID()] = struct{}{}
+		}
+	}
+
+	var unexpected []unexpectedShard
+	for _, filePathPrefix := range filePathPrefixes {
+		for _, dirName := range []string{
+			path.Base(fs.DataDirPath(filePathPrefix)),
+			path.Base(fs.SnapshotDirPath(filePathPrefix)),
+		} {
+			namespaceDirs, err := readSubDirs(path.Join(filePathPrefix, dirName))
+			if err != nil {
+				return nil, err
+			}
+			for _, namespace := range namespaceDirs {
+				shardDirs, err := readSubDirs(path.Join(filePathPrefix, dirName, namespace))
+				if err != nil {
+					return nil, err
+				}
+				for _, shardDir := range shardDirs {
+					id, err := strconv.ParseUint(shardDir, 10, 32)
+					if err != nil {
+						// NB: not a shard directory.
+						continue
+					}
+					if _, ok := assigned[uint32(id)]; ok {
+						continue
+					}
+					unexpected = append(unexpected, unexpectedShard{
+						filePathPrefix: filePathPrefix,
+						relPath:        path.Join(dirName, namespace, shardDir),
+						shard:          uint32(id),
+					})
+				}
+			}
+		}
+	}
+	return unexpected, nil
+}
+
+// unexpectedShardIDs returns the sorted unique IDs of the unexpected shards.
+func unexpectedShardIDs(unexpected []unexpectedShard) []uint32 {
+	seen := make(map[uint32]struct{}, len(unexpected))
+	ids := make([]uint32, 0, len(unexpected))
+	for _, s := range unexpected {
+		if _, ok := seen[s.shard]; ok {
+			continue
+		}
+		seen[s.shard] = struct{}{}
+		ids = append(ids, s.shard)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// readSubDirs returns the names of the directories in a directory, which
+// may not exist.
+func readSubDirs(dirPath string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dirPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+// verifyShardOwnership applies the unexpected shard policy at startup to
+// the fileset shard directories on disk for shards not assigned to the host,
+// and returns the options to create the database with, which include the
+// unexpected shards as read only shards for the read only policy. Nothing is
+// changed on disk if the host has no shard set, which is expected while a
+// node replacement is in progress or the placement is still propagating.
+// Shards are never deleted at startup since the placement seen at startup
+// may be stale.
+func verifyShardOwnership(
+	hostShardSet topology.HostShardSet,
+	opts storage.Options,
+	log xlog.Logger,
+) (storage.Options, error) {
+	policy := opts.UnexpectedShardPolicy()
+	if policy == storage.UnexpectedShardIgnore {
+		return opts, nil
+	}
+	if hostShardSet == nil {
+		return nil, errShardOwnershipHostNotInPlacement
+	}
+	shardSet := hostShardSet.ShardSet()
+
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	filePathPrefixes := []string{fsOpts.FilePathPrefix()}
+	if warmFilePathPrefix := fsOpts.WarmFilePathPrefix(); warmFilePathPrefix != "" {
+		filePathPrefixes = append(filePathPrefixes, warmFilePathPrefix)
+	}
+
+	unexpected, err := findUnexpectedShards(filePathPrefixes, shardSet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify shard ownership: %v", err)
+	}
+	if len(unexpected) == 0 {
+		return opts, nil
+	}
+
+	var (
+		nowFn    = opts.ClockOptions().NowFn()
+		multiErr xerrors.MultiError
+	)
+	for _, s := range unexpected {
+		dirPath := path.Join(s.filePathPrefix, s.relPath)
+		log.Warnf("found fileset shard directory for shard not assigned to host: %s, policy: %s",
+			dirPath, policy.String())
+
+		switch policy {
+		case storage.UnexpectedShardQuarantine:
+			quarantinePath := path.Join(fs.QuarantineDirPath(s.filePathPrefix), s.relPath)
+			if _, err := os.Stat(quarantinePath); err == nil {
+				quarantinePath = fmt.Sprintf("%s.%d", quarantinePath, nowFn().UnixNano())
+			}
+			if err := os.MkdirAll(path.Dir(quarantinePath), os.ModePerm); err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			multiErr = multiErr.Add(os.Rename(dirPath, quarantinePath))
+		}
+	}
+	if err := multiErr.FinalError(); err != nil {
+		return nil, fmt.Errorf("unable to apply unexpected shard policy %s: %v",
+			policy.String(), err)
+	}
+
+	if policy == storage.UnexpectedShardReadOnly {
+		readOnlyShards := unexpectedShardIDs(unexpected)
+		log.Warnf("serving shards not assigned to host read only: %v", readOnlyShards)
+		opts = opts.SetReadOnlyShards(readOnlyShards)
+	}
+	return opts, nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3cluster/shard"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShardOwnershipOptions(
+	t *testing.T,
+	policy storage.UnexpectedShardPolicy,
+) (storage.Options, string) {
+	dir, err := ioutil.TempDir("", "shard-ownership")
+	require.NoError(t, err)
+	for _, p := range []string{"data/metrics/0", "data/metrics/1", "snapshots/metrics/2"} {
+		require.NoError(t, os.MkdirAll(path.Join(dir, p), os.ModePerm))
+	}
+
+	opts := storage.NewOptions().SetUnexpectedShardPolicy(policy)
+	clOpts := opts.CommitLogOptions()
+	opts = opts.SetCommitLogOptions(clOpts.SetFilesystemOptions(
+		clOpts.FilesystemOptions().SetFilePathPrefix(dir)))
+	return opts, dir
+}
+
+func testHostShardSet(shardSet sharding.ShardSet) topology.HostShardSet {
+	return topology.NewHostShardSet(topology.NewHost("testhost", "testhost:9000"), shardSet)
+}
+
+func TestVerifyShardOwnershipQuarantine(t *testing.T) {
+	opts, dir := newTestShardOwnershipOptions(t, storage.UnexpectedShardQuarantine)
+	defer os.RemoveAll(dir)
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0}, shard.Available), sharding.DefaultHashFn(3))
+	require.NoError(t, err)
+
+	result, err := verifyShardOwnership(testHostShardSet(shardSet), opts, opts.InstrumentOptions().Logger())
+	require.NoError(t, err)
+	require.Empty(t, result.ReadOnlyShards())
+
+	for p, exists := range map[string]bool{
+		"data/metrics/0":      true,
+		"data/metrics/1":      false,
+		"snapshots/metrics/2": false,
+		path.Join(path.Base(fs.QuarantineDirPath(dir)), "data/metrics/1"):      true,
+		path.Join(path.Base(fs.QuarantineDirPath(dir)), "snapshots/metrics/2"): true,
+	} {
+		_, err := os.Stat(path.Join(dir, p))
+		require.Equal(t, exists, err == nil, p)
+	}
+}
+
+func TestVerifyShardOwnershipReadOnly(t *testing.T) {
+	opts, dir := newTestShardOwnershipOptions(t, storage.UnexpectedShardReadOnly)
+	defer os.RemoveAll(dir)
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1, 2}, shard.Available), sharding.DefaultHashFn(3))
+	require.NoError(t, err)
+
+	// All shards on disk are assigned.
+	result, err := verifyShardOwnership(testHostShardSet(shardSet), opts, opts.InstrumentOptions().Logger())
+	require.NoError(t, err)
+	require.Empty(t, result.ReadOnlyShards())
+
+	shardSet, err = sharding.NewShardSet(
+		sharding.NewShards([]uint32{0}, shard.Available), sharding.DefaultHashFn(3))
+	require.NoError(t, err)
+
+	result, err = verifyShardOwnership(testHostShardSet(shardSet), opts, opts.InstrumentOptions().Logger())
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 2}, result.ReadOnlyShards())
+	_, err = os.Stat(path.Join(dir, "data/metrics/1"))
+	require.NoError(t, err)
+}
+
+func TestVerifyShardOwnershipHostNotInPlacement(t *testing.T) {
+	opts, dir := newTestShardOwnershipOptions(t, storage.UnexpectedShardQuarantine)
+	defer os.RemoveAll(dir)
+
+	_, err := verifyShardOwnership(nil, opts, opts.InstrumentOptions().Logger())
+	require.Equal(t, errShardOwnershipHostNotInPlacement, err)
+
+	for _, p := range []string{"data/metrics/0", "data/metrics/1", "snapshots/metrics/2"} {
+		_, err := os.Stat(path.Join(dir, p))
+		require.NoError(t, err, p)
+	}
+}
+
+func TestVerifyShardOwnershipQuarantineInitializingAndLeavingOwned(t *testing.T) {
+	opts, dir := newTestShardOwnershipOptions(t, storage.UnexpectedShardQuarantine)
+	defer os.RemoveAll(dir)
+
+	shards := append(sharding.NewShards([]uint32{0}, shard.Initializing),
+		sharding.NewShards([]uint32{1}, shard.Leaving)...)
+	shardSet, err := sharding.NewShardSet(shards, sharding.DefaultHashFn(3))
+	require.NoError(t, err)
+
+	_, err = verifyShardOwnership(testHostShardSet(shardSet), opts,
+		opts.InstrumentOptions().Logger())
+	require.NoError(t, err)
+
+	for p, exists := range map[string]bool{
+		"data/metrics/0":      true,
+		"data/metrics/1":      true,
+		"snapshots/metrics/2": false,
+		path.Join(path.Base(fs.QuarantineDirPath(dir)), "snapshots/metrics/2"): true,
+	} {
+		_, err := os.Stat(path.Join(dir, p))
+		require.Equal(t, exists, err == nil, p)
+	}
+}
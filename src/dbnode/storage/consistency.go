@@ -0,0 +1,354 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+const (
+	// defaultConsistencyCheckMaxIDs is the default maximum number of IDs of
+	// each kind of discrepancy included in a consistency report.
+	defaultConsistencyCheckMaxIDs = 100
+
+	// consistencyCheckRepairTimeout is how long a consistency check waits for
+	// the series it resubmitted to the index to be indexed.
+	consistencyCheckRepairTimeout = time.Minute
+
+	// consistencyCheckSampleBuckets is the number of buckets series IDs are
+	// hashed into when sampling series.
+	consistencyCheckSampleBuckets = 1 << 20
+)
+
+var (
+	errConsistencyCheckSampleRate    = errors.New("consistency check sample rate must be between 0 and 1")
+	errConsistencyCheckNotExhaustive = errors.New("consistency check index query is not exhaustive, the max query limit is too low")
+	errConsistencyCheckRepairTimeout = errors.New("consistency check timed out waiting for series to be reindexed")
+
+	// consistencyCheckAllQuery matches every series of an index block since
+	// no series has the field.
+	consistencyCheckAllQuery = index.Query{
+		Query: idx.NewNegationQuery(idx.NewTermQuery([]byte("__consistency_check__"), nil)),
+	}
+)
+
+// ConsistencyCheckOptions are the options of a consistency check between
+// the data and the index of a namespace.
+type ConsistencyCheckOptions struct {
+	// SampleRate is the fraction of series checked, series are sampled by
+	// the hash of their ID so the same series are checked on both sides.
+	// Zero checks all series.
+	SampleRate float64
+
+	// Repair reindexes the series with data that are missing from the index.
+	Repair bool
+
+	// MaxIDs is the maximum number of IDs of each kind of discrepancy
+	// included in the report, zero uses the default.
+	MaxIDs int
+}
+
+// ConsistencyReport is the result of comparing the series with data in an
+// index block of a namespace to the series in the index for the block.
+type ConsistencyReport struct {
+	Namespace  string    `json:"namespace"`
+	BlockStart time.Time `json:"blockStart"`
+	BlockEnd   time.Time `json:"blockEnd"`
+	SampleRate float64   `json:"sampleRate"`
+
+	// DataSeries is the number of sampled series with data in the block.
+	DataSeries int `json:"dataSeries"`
+
+	// IndexSeries is the number of sampled series in the index block.
+	IndexSeries int `json:"indexSeries"`
+
+	// MissingFromIndex is the number of series with data that are not
+	// queryable via the index.
+	MissingFromIndex    int      `json:"missingFromIndex"`
+	MissingFromIndexIDs []string `json:"missingFromIndexIDs,omitempty"`
+
+	// MissingFromData is the number of series in the index without data.
+	MissingFromData    int      `json:"missingFromData"`
+	MissingFromDataIDs []string `json:"missingFromDataIDs,omitempty"`
+
+	// Reindexed is the number of series missing from the index that were
+	// resubmitted to the index, the index may still reject writes for
+	// blocks that no longer accept writes.
+	Reindexed int `json:"reindexed"`
+}
+
+// Consistent returns whether the data and the index match.
+func (r ConsistencyReport) Consistent() bool {
+	return r.MissingFromIndex == 0 && r.MissingFromData == 0
+}
+
+func (o ConsistencyCheckOptions) validate() error {
+	if o.SampleRate < 0 || o.SampleRate > 1 || math.IsNaN(o.SampleRate) {
+		return errConsistencyCheckSampleRate
+	}
+	return nil
+}
+
+func (d *db) ConsistencyCheck(
+	namespace ident.ID,
+	blockStart time.Time,
+	opts ConsistencyCheckOptions,
+) (ConsistencyReport, error) {
+	if err := opts.validate(); err != nil {
+		return ConsistencyReport{}, err
+	}
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return ConsistencyReport{}, err
+	}
+	return n.ConsistencyCheck(blockStart, opts)
+}
+
+func (n *dbNamespace) ConsistencyCheck(
+	blockStart time.Time,
+	opts ConsistencyCheckOptions,
+) (ConsistencyReport, error) {
+	if n.reverseIndex == nil {
+		return ConsistencyReport{}, errNamespaceIndexingDisabled
+	}
+
+	var (
+		blockSize = n.nopts.IndexOptions().BlockSize()
+		start     = blockStart.Truncate(blockSize)
+		end       = start.Add(blockSize)
+		c         = newConsistencyChecker(n.ID().String(), start, end, opts)
+		ctx       = context.NewContext()
+	)
+	defer ctx.BlockingClose()
+
+	// NB: stream the series of the index block so they are sampled as they
+	// are matched rather than materializing every series of the block.
+	indexResults, err := n.reverseIndex.Query(ctx, consistencyCheckAllQuery,
+		index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			StreamFn: func(batch index.Results) error {
+				for _, entry := range batch.Map().Iter() {
+					c.addIndexSeries(entry.Key())
+				}
+				return nil
+			},
+		})
+	if err != nil {
+		return ConsistencyReport{}, err
+	}
+	if !indexResults.Exhaustive {
+		// Series beyond the limit would be falsely reported as missing.
+		return ConsistencyReport{}, errConsistencyCheckNotExhaustive
+	}
+
+	var (
+		batch   *index.WriteBatch
+		reindex = &consistencyCheckOnIndexSeries{}
+	)
+	if opts.Repair {
+		batch = index.NewWriteBatch(index.WriteBatchOptions{IndexBlockSize: blockSize})
+	}
+	for _, shard := range n.GetOwnedShards() {
+		var (
+			first     = true
+			pageToken PageToken
+		)
+		for first || pageToken != nil {
+			first = false
+
+			var (
+				shardCtx = context.NewContext()
+				results  block.FetchBlocksMetadataResults
+			)
+			results, pageToken, err = shard.FetchBlocksMetadataV2(shardCtx, start, end,
+				defaultFlushReadDataBlocksBatchSize, pageToken, block.FetchBlocksMetadataOptions{})
+			if err != nil {
+				shardCtx.BlockingClose()
+				return ConsistencyReport{}, err
+			}
+			for _, result := range results.Results() {
+				if c.addDataSeries(result.ID) || batch == nil {
+					continue
+				}
+				d, err := convert.FromMetricIter(result.ID, result.Tags)
+				if err != nil {
+					results.Close()
+					shardCtx.BlockingClose()
+					return ConsistencyReport{}, err
+				}
+				reindex.wg.Add(1)
+				batch.Append(index.WriteBatchEntry{
+					Timestamp:     start,
+					OnIndexSeries: reindex,
+					EnqueuedAt:    n.nowFn(),
+					Repair:        true,
+				}, d)
+			}
+			results.Close()
+			shardCtx.BlockingClose()
+		}
+	}
+
+	if batch != nil && batch.Len() > 0 {
+		// NB: the entries are indexed asynchronously in async insert mode so
+		// wait for each entry to be finalized before counting the successes,
+		// errors for individual entries are reported by the count.
+		if err := n.reverseIndex.WriteBatch(batch); err != nil && batch.NumErrs() == 0 {
+			return ConsistencyReport{}, err
+		}
+		if !reindex.waitTimeout(consistencyCheckRepairTimeout) {
+			return ConsistencyReport{}, errConsistencyCheckRepairTimeout
+		}
+		c.report.Reindexed = int(reindex.numIndexed())
+	}
+	return c.finish(), nil
+}
+
+// consistencyChecker compares the sampled series of an index block with
+// the sampled series with data in the block.
+type consistencyChecker struct {
+	report  ConsistencyReport
+	buckets uint64
+	maxIDs  int
+	index   map[string]struct{}
+}
+
+func newConsistencyChecker(
+	namespace string,
+	start, end time.Time,
+	opts ConsistencyCheckOptions,
+) *consistencyChecker {
+	sampleRate := opts.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+	maxIDs := opts.MaxIDs
+	if maxIDs <= 0 {
+		maxIDs = defaultConsistencyCheckMaxIDs
+	}
+	return &consistencyChecker{
+		report: ConsistencyReport{
+			Namespace:  namespace,
+			BlockStart: start,
+			BlockEnd:   end,
+			SampleRate: sampleRate,
+		},
+		buckets: uint64(sampleRate * consistencyCheckSampleBuckets),
+		maxIDs:  maxIDs,
+		index:   make(map[string]struct{}),
+	}
+}
+
+func (c *consistencyChecker) sampled(id ident.ID) bool {
+	h := fnv.New64a()
+	h.Write(id.Bytes())
+	return h.Sum64()%consistencyCheckSampleBuckets < c.buckets
+}
+
+// addIndexSeries records a series in the index block.
+func (c *consistencyChecker) addIndexSeries(id ident.ID) {
+	if !c.sampled(id) {
+		return
+	}
+	c.index[id.String()] = struct{}{}
+	c.report.IndexSeries = len(c.index)
+}
+
+// addDataSeries records a series with data in the block and returns whether
+// it is queryable via the index or was not sampled.
+func (c *consistencyChecker) addDataSeries(id ident.ID) bool {
+	if !c.sampled(id) {
+		return true
+	}
+	c.report.DataSeries++
+	key := id.String()
+	if _, ok := c.index[key]; ok {
+		delete(c.index, key)
+		return true
+	}
+	c.report.MissingFromIndex++
+	if len(c.report.MissingFromIndexIDs) < c.maxIDs {
+		c.report.MissingFromIndexIDs = append(c.report.MissingFromIndexIDs, key)
+	}
+	return false
+}
+
+// finish returns the report, the series left in the index after all series
+// with data were added have no data.
+func (c *consistencyChecker) finish() ConsistencyReport {
+	c.report.MissingFromData = len(c.index)
+	for id := range c.index {
+		if len(c.report.MissingFromDataIDs) >= c.maxIDs {
+			break
+		}
+		c.report.MissingFromDataIDs = append(c.report.MissingFromDataIDs, id)
+	}
+	return c.report
+}
+
+// consistencyCheckOnIndexSeries is the index callback for series reindexed
+// by a consistency check which are not held by a shard, it counts the series
+// indexed successfully once each of them is finalized.
+type consistencyCheckOnIndexSeries struct {
+	wg      sync.WaitGroup
+	indexed int64
+}
+
+func (s *consistencyCheckOnIndexSeries) OnIndexSuccess(blockStart xtime.UnixNano) {
+	atomic.AddInt64(&s.indexed, 1)
+}
+
+func (s *consistencyCheckOnIndexSeries) OnIndexFinalize(blockStart xtime.UnixNano) {
+	s.wg.Done()
+}
+
+func (s *consistencyCheckOnIndexSeries) numIndexed() int64 {
+	return atomic.LoadInt64(&s.indexed)
+}
+
+// waitTimeout waits for every series to be finalized and returns false if
+// they are not finalized within the timeout.
+func (s *consistencyCheckOnIndexSeries) waitTimeout(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
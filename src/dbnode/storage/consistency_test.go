@@ -0,0 +1,119 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistencyChecker(t *testing.T) {
+	var (
+		start = time.Unix(0, 0).Add(24 * time.Hour)
+		end   = start.Add(24 * time.Hour)
+		c     = newConsistencyChecker("testns", start, end, ConsistencyCheckOptions{})
+	)
+
+	for _, id := range []string{"a", "b", "c"} {
+		c.addIndexSeries(ident.StringID(id))
+	}
+	assert.True(t, c.addDataSeries(ident.StringID("a")))
+	assert.True(t, c.addDataSeries(ident.StringID("b")))
+	assert.False(t, c.addDataSeries(ident.StringID("d")))
+
+	report := c.finish()
+	assert.Equal(t, ConsistencyReport{
+		Namespace:           "testns",
+		BlockStart:          start,
+		BlockEnd:            end,
+		SampleRate:          1,
+		DataSeries:          3,
+		IndexSeries:         3,
+		MissingFromIndex:    1,
+		MissingFromIndexIDs: []string{"d"},
+		MissingFromData:     1,
+		MissingFromDataIDs:  []string{"c"},
+	}, report)
+	assert.False(t, report.Consistent())
+}
+
+func TestConsistencyCheckerSampled(t *testing.T) {
+	var (
+		start = time.Unix(0, 0)
+		c     = newConsistencyChecker("testns", start, start.Add(time.Hour),
+			ConsistencyCheckOptions{SampleRate: 0.5, MaxIDs: 2})
+	)
+
+	// Series are only in the index, both sides sample the same series.
+	numSeries := 1000
+	for i := 0; i < numSeries; i++ {
+		c.addIndexSeries(ident.StringID(fmt.Sprintf("series-%d", i)))
+	}
+	sampled := c.report.IndexSeries
+	assert.True(t, sampled > numSeries/4 && sampled < 3*numSeries/4)
+	for i := 0; i < numSeries; i++ {
+		require.True(t, c.addDataSeries(ident.StringID(fmt.Sprintf("series-%d", i))))
+	}
+
+	report := c.finish()
+	assert.Equal(t, sampled, report.DataSeries)
+	assert.True(t, report.Consistent())
+
+	// Only the configured number of IDs are included.
+	for i := 0; i < numSeries; i++ {
+		c.addIndexSeries(ident.StringID(fmt.Sprintf("missing-%d", i)))
+	}
+	report = c.finish()
+	assert.True(t, report.MissingFromData > 2)
+	assert.Len(t, report.MissingFromDataIDs, 2)
+}
+
+func TestConsistencyCheckOnIndexSeries(t *testing.T) {
+	var (
+		reindex = &consistencyCheckOnIndexSeries{}
+		batch   = index.NewWriteBatch(index.WriteBatchOptions{IndexBlockSize: time.Hour})
+	)
+	for i := 0; i < 3; i++ {
+		reindex.wg.Add(1)
+		batch.Append(index.WriteBatchEntry{
+			Timestamp:     time.Now(),
+			OnIndexSeries: reindex,
+			Repair:        true,
+		}, doc.Document{ID: []byte(fmt.Sprintf("foo%d", i))})
+	}
+
+	// Series are counted once finalized, as they are in async insert mode
+	require.False(t, reindex.waitTimeout(time.Millisecond))
+
+	batch.MarkUnmarkedEntrySuccess(0)
+	batch.MarkUnmarkedEntryError(errors.New("an error"), 1)
+	batch.MarkUnmarkedEntrySuccess(2)
+	require.True(t, reindex.waitTimeout(time.Second))
+	assert.Equal(t, int64(2), reindex.numIndexed())
+}
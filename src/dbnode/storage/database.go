@@ -34,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/dbnode/x/writeamp"
 	"github.com/m3db/m3/src/dbnode/x/xcounter"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/context"
@@ -45,6 +46,12 @@ import (
 	"github.com/uber-go/tally"
 )
 
+const (
+	// ingestedDatapointBytes is the logical size of a datapoint's timestamp
+	// and value when accounting for write amplification.
+	ingestedDatapointBytes = 16
+)
+
 var (
 	// errDatabaseAlreadyOpen raised when trying to open a database that is already open
 	errDatabaseAlreadyOpen = errors.New("database is already open")
@@ -95,6 +102,10 @@ type db struct {
 	errors       xcounter.FrequencyCounter
 	errWindow    time.Duration
 	errThreshold int64
+
+	writeAmpRecorder writeamp.Recorder
+
+	flushBarrierLock sync.Mutex
 }
 
 type databaseMetrics struct {
@@ -156,6 +167,9 @@ func NewDatabase(
 		errors:       xcounter.NewFrequencyCounter(opts.ErrorCounterOptions()),
 		errWindow:    opts.ErrorWindowForLoad(),
 		errThreshold: opts.ErrorThresholdForLoad(),
+
+		writeAmpRecorder: opts.CommitLogOptions().FilesystemOptions().
+			WriteAmplificationRecorder(),
 	}
 
 	databaseIOpts := iopts.SetMetricsScope(scope)
@@ -196,6 +210,28 @@ func NewDatabase(
 }
 
 func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
+	extensions, err := d.updateOwnedNamespaces(newNamespaces)
+	if err != nil {
+		return err
+	}
+
+	// NB: extending the retention of a namespace reads the flush states and
+	// index filesets of the extended blocks from disk so it is applied
+	// outside of the database lock.
+	if err := d.extendNamespaceRetentions(extensions); err != nil {
+		enrichedErr := fmt.Errorf("unable to extend namespace retentions: %v", err)
+		d.log.Errorf("%v", enrichedErr)
+		return enrichedErr
+	}
+	return nil
+}
+
+// updateOwnedNamespaces applies the namespace updates that are applied with
+// the database lock held and returns the updates that extend the retention
+// period of a namespace.
+func (d *db) updateOwnedNamespaces(
+	newNamespaces namespace.Map,
+) ([]namespaceRetentionExtension, error) {
 	d.Lock()
 	defer d.Unlock()
 
@@ -203,17 +239,28 @@ func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
 	if err := d.logNamespaceUpdate(removes, adds, updates); err != nil {
 		enrichedErr := fmt.Errorf("unable to log namespace updates: %v", err)
 		d.log.Errorf("%v", enrichedErr)
-		return enrichedErr
+		return nil, enrichedErr
 	}
 
 	// add any namespaces marked for addition
 	if err := d.addNamespacesWithLock(adds); err != nil {
 		enrichedErr := fmt.Errorf("unable to add namespaces: %v", err)
 		d.log.Errorf("%v", enrichedErr)
-		return err
+		return nil, enrichedErr
+	}
+
+	// collect any updates that only extend the retention period
+	extensions, updates := d.namespaceRetentionExtensionsWithLock(updates)
+
+	// apply any updates that only change the index insert mode
+	updates, err := d.setNamespaceIndexInsertModesWithLock(updates)
+	if err != nil {
+		enrichedErr := fmt.Errorf("unable to set namespace index insert modes: %v", err)
+		d.log.Errorf("%v", enrichedErr)
+		return nil, enrichedErr
 	}
 
-	// log that updates and removals are skipped
+	// log that remaining updates and removals are skipped
 	if len(removes) > 0 || len(updates) > 0 {
 		d.log.Warnf("skipping namespace removals and updates, restart process if you want changes to take effect.")
 	}
@@ -223,7 +270,7 @@ func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
 		d.queueBootstrapWithLock()
 	}
 
-	return nil
+	return extensions, nil
 }
 
 func (d *db) namespaceDeltaWithLock(newNamespaces namespace.Map) ([]ident.ID, []namespace.Metadata, []namespace.Metadata) {
@@ -268,6 +315,108 @@ func (d *db) namespaceDeltaWithLock(newNamespaces namespace.Map) ([]ident.ID, []
 	return removes, adds, updates
 }
 
+// namespaceRetentionExtension is an update that only extends the retention
+// period of a namespace.
+type namespaceRetentionExtension struct {
+	namespace       databaseNamespace
+	retentionPeriod time.Duration
+}
+
+// namespaceRetentionExtensionsWithLock returns the updates that only extend
+// the retention period of a namespace and the updates that were skipped.
+func (d *db) namespaceRetentionExtensionsWithLock(
+	updates []namespace.Metadata,
+) ([]namespaceRetentionExtension, []namespace.Metadata) {
+	var (
+		extensions []namespaceRetentionExtension
+		skipped    []namespace.Metadata
+	)
+	for _, newMd := range updates {
+		ns, ok := d.namespaces.Get(newMd.ID())
+		if !ok || !isRetentionExtension(ns.Options(), newMd.Options()) {
+			skipped = append(skipped, newMd)
+			continue
+		}
+		extensions = append(extensions, namespaceRetentionExtension{
+			namespace:       ns,
+			retentionPeriod: newMd.Options().RetentionOptions().RetentionPeriod(),
+		})
+	}
+	return extensions, skipped
+}
+
+// extendNamespaceRetentions extends the retention periods of namespaces.
+func (d *db) extendNamespaceRetentions(
+	extensions []namespaceRetentionExtension,
+) error {
+	var multiErr xerrors.MultiError
+	for _, extension := range extensions {
+		ns := extension.namespace
+		if err := ns.ExtendRetentionPeriod(extension.retentionPeriod); err != nil {
+			multiErr = multiErr.Add(fmt.Errorf("namespace %s: %v", ns.ID().String(), err))
+			continue
+		}
+		d.log.WithFields(
+			xlog.NewField("namespace", ns.ID().String()),
+			xlog.NewField("retentionPeriod", extension.retentionPeriod.String()),
+		).Infof("extended namespace retention period")
+	}
+	return multiErr.FinalError()
+}
+
+// isRetentionExtension returns whether the only difference between the
+// existing and new namespace options is a longer retention period.
+func isRetentionExtension(existing, updated namespace.Options) bool {
+	var (
+		existingRetention = existing.RetentionOptions()
+		updatedPeriod     = updated.RetentionOptions().RetentionPeriod()
+	)
+	if updatedPeriod <= existingRetention.RetentionPeriod() {
+		return false
+	}
+	extended := existing.SetRetentionOptions(
+		existingRetention.SetRetentionPeriod(updatedPeriod))
+	return extended.Equal(updated)
+}
+
+// setNamespaceIndexInsertModesWithLock applies the updates that only change
+// the index insert mode of a namespace and returns the updates that were skipped.
+func (d *db) setNamespaceIndexInsertModesWithLock(
+	updates []namespace.Metadata,
+) ([]namespace.Metadata, error) {
+	var skipped []namespace.Metadata
+	for _, newMd := range updates {
+		ns, ok := d.namespaces.Get(newMd.ID())
+		if !ok || !isIndexInsertModeChange(ns.Options(), newMd.Options()) {
+			skipped = append(skipped, newMd)
+			continue
+		}
+
+		insertMode := newMd.Options().IndexOptions().InsertMode()
+		if err := ns.SetIndexInsertMode(insertMode); err != nil {
+			return nil, err
+		}
+		d.log.WithFields(
+			xlog.NewField("namespace", newMd.ID().String()),
+			xlog.NewField("indexInsertMode", insertMode.String()),
+		).Infof("set namespace index insert mode")
+	}
+	return skipped, nil
+}
+
+// isIndexInsertModeChange returns whether the only difference between the
+// existing and new namespace options is the index insert mode.
+func isIndexInsertModeChange(existing, updated namespace.Options) bool {
+	existingIndex := existing.IndexOptions()
+	updatedMode := updated.IndexOptions().InsertMode()
+	if updatedMode == existingIndex.InsertMode() {
+		return false
+	}
+	changed := existing.SetIndexOptions(
+		existingIndex.SetInsertMode(updatedMode))
+	return changed.Equal(updated)
+}
+
 func (d *db) logNamespaceUpdate(removes []ident.ID, adds, updates []namespace.Metadata) error {
 	removalString, err := tsIDs(removes).String()
 	if err != nil {
@@ -292,7 +441,8 @@ func (d *db) logNamespaceUpdate(removes []ident.ID, adds, updates []namespace.Me
 	).Infof("updating database namespaces")
 
 	// NB(prateek): as noted in `UpdateOwnedNamespaces()` above, the current implementation
-	// does not apply updates, and removals until the m3dbnode process is restarted.
+	// does not apply updates (other than retention extensions), and removals until the
+	// m3dbnode process is restarted.
 
 	return nil
 }
@@ -480,18 +630,21 @@ func (d *db) Write(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
 	n, err := d.namespaceFor(namespace)
 	if err != nil {
 		d.metrics.unknownNamespaceWrite.Inc(1)
-		return err
+		return false, err
 	}
 
-	err = n.Write(ctx, id, timestamp, value, unit, annotation)
+	newSeries, err := n.Write(ctx, id, timestamp, value, unit, annotation)
 	if err == commitlog.ErrCommitLogQueueFull {
 		d.errors.Record(1)
 	}
-	return err
+	if err == nil {
+		d.recordIngested(id, annotation)
+	}
+	return newSeries, err
 }
 
 func (d *db) WriteTagged(
@@ -503,18 +656,28 @@ func (d *db) WriteTagged(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
 	n, err := d.namespaceFor(namespace)
 	if err != nil {
 		d.metrics.unknownNamespaceWriteTagged.Inc(1)
-		return err
+		return false, err
 	}
 
-	err = n.WriteTagged(ctx, id, tags, timestamp, value, unit, annotation)
+	newSeries, err := n.WriteTagged(ctx, id, tags, timestamp, value, unit, annotation)
 	if err == commitlog.ErrCommitLogQueueFull {
 		d.errors.Record(1)
 	}
-	return err
+	if err == nil {
+		d.recordIngested(id, annotation)
+	}
+	return newSeries, err
+}
+
+// recordIngested records the logical size of a write, i.e. the series ID,
+// the timestamp and value of the datapoint and its annotation.
+func (d *db) recordIngested(id ident.ID, annotation []byte) {
+	d.writeAmpRecorder.RecordIngested(
+		len(id.Bytes()) + ingestedDatapointBytes + len(annotation))
 }
 
 func (d *db) QueryIDs(
@@ -542,6 +705,85 @@ func (d *db) QueryIDs(
 	return queryResults, err
 }
 
+func (d *db) IndexCardinalityReport(
+	namespace ident.ID,
+) (index.CardinalityReport, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return index.CardinalityReport{}, err
+	}
+
+	idx, err := n.GetIndex()
+	if err != nil {
+		return index.CardinalityReport{}, err
+	}
+	return idx.CardinalityReport()
+}
+
+func (d *db) QueryIDsMultiNamespace(
+	ctx context.Context,
+	namespaces []ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+) (index.MultiNamespaceQueryResults, error) {
+	nses := make([]databaseNamespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		n, err := d.namespaceFor(namespace)
+		if err != nil {
+			d.metrics.unknownNamespaceQueryIDs.Inc(1)
+			return index.MultiNamespaceQueryResults{}, err
+		}
+		nses = append(nses, n)
+	}
+
+	var (
+		wg           = sync.WaitGroup{}
+		queryResults = make([]index.QueryResults, len(nses))
+		queryErrs    = make([]error, len(nses))
+	)
+	for i, n := range nses {
+		i, n := i, n
+		wg.Add(1)
+		d.opts.QueryIDsWorkerPool().Go(func() {
+			queryResults[i], queryErrs[i] = n.QueryIDs(ctx, query, opts)
+			wg.Done()
+		})
+	}
+	wg.Wait()
+
+	var multiErr xerrors.MultiError
+	for _, err := range queryErrs {
+		multiErr = multiErr.Add(err)
+	}
+	if err := multiErr.FinalError(); err != nil {
+		return index.MultiNamespaceQueryResults{}, err
+	}
+
+	results := index.MultiNamespaceQueryResults{
+		Results:    make([]index.Results, 0, len(queryResults)),
+		Exhaustive: true,
+	}
+	for _, r := range queryResults {
+		results.Results = append(results.Results, r.Results)
+		results.Exhaustive = results.Exhaustive && r.Exhaustive
+		for _, w := range r.Warnings {
+			// NB: a limit exceeded in more than one namespace is only
+			// reported once.
+			found := false
+			for _, existing := range results.Warnings {
+				if existing == w {
+					found = true
+					break
+				}
+			}
+			if !found {
+				results.Warnings = append(results.Warnings, w)
+			}
+		}
+	}
+	return results, nil
+}
+
 func (d *db) ReadEncoded(
 	ctx context.Context,
 	namespace ident.ID,
@@ -23,6 +23,7 @@ package storage
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -613,13 +614,13 @@ func TestDatabaseAddNamespace(t *testing.T) {
 	require.Len(t, nses, 3)
 	ns1, ok := d.Namespace(defaultTestNs1ID)
 	require.True(t, ok)
-	require.Equal(t, md1.Options(), ns1.Options())
+	require.True(t, md1.Options().Equal(ns1.Options()))
 	ns2, ok := d.Namespace(defaultTestNs2ID)
 	require.True(t, ok)
-	require.Equal(t, md2.Options(), ns2.Options())
+	require.True(t, md2.Options().Equal(ns2.Options()))
 	ns3, ok := d.Namespace(ident.StringID("and1"))
 	require.True(t, ok)
-	require.Equal(t, md1.Options(), ns3.Options())
+	require.True(t, md1.Options().Equal(ns3.Options()))
 }
 
 func TestDatabaseUpdateNamespace(t *testing.T) {
@@ -642,7 +643,7 @@ func TestDatabaseUpdateNamespace(t *testing.T) {
 	require.Len(t, nses, 2)
 
 	// construct new namespace Map
-	ropts := defaultTestNs1Opts.RetentionOptions().SetRetentionPeriod(2000 * time.Hour)
+	ropts := defaultTestNs1Opts.RetentionOptions().SetRetentionPeriod(24 * time.Hour)
 	md1, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.SetRetentionOptions(ropts))
 	require.NoError(t, err)
 	md2, err := namespace.NewMetadata(defaultTestNs2ID, defaultTestNs2Opts)
@@ -663,10 +664,105 @@ func TestDatabaseUpdateNamespace(t *testing.T) {
 	require.Len(t, nses, 2)
 	ns1, ok := d.Namespace(defaultTestNs1ID)
 	require.True(t, ok)
-	require.Equal(t, defaultTestNs1Opts, ns1.Options())
+	require.True(t, defaultTestNs1Opts.Equal(ns1.Options()))
+	ns2, ok := d.Namespace(defaultTestNs2ID)
+	require.True(t, ok)
+	require.True(t, defaultTestNs2Opts.Equal(ns2.Options()))
+}
+
+func TestDatabaseUpdateNamespaceExtendRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, Bootstrapped)
+	require.NoError(t, d.Open())
+	defer func() {
+		close(mapCh)
+		require.NoError(t, d.Close())
+		leaktest.CheckTimeout(t, time.Second)()
+	}()
+
+	// retrieve the update channel to track propatation
+	updateCh := d.opts.NamespaceInitializer().(*mockNsInitializer).updateCh
+
+	// check initial namespaces
+	nses := d.Namespaces()
+	require.Len(t, nses, 2)
+
+	// construct new namespace Map
+	ropts := defaultTestNs1Opts.RetentionOptions().SetRetentionPeriod(2000 * time.Hour)
+	md1, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.SetRetentionOptions(ropts))
+	require.NoError(t, err)
+	md2, err := namespace.NewMetadata(defaultTestNs2ID, defaultTestNs2Opts)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md1, md2})
+	require.NoError(t, err)
+
+	// update the database watch with new Map
+	mapCh <- nsMap
+
+	// wait till the update has propagated
+	<-updateCh
+	<-updateCh
+
+	// ensure the retention period was extended in place
+	ns1, ok := d.Namespace(defaultTestNs1ID)
+	require.True(t, ok)
+	require.True(t, xclock.WaitUntil(func() bool {
+		return ns1.Options().RetentionOptions().RetentionPeriod() == 2000*time.Hour
+	}, 2*time.Second))
+	require.True(t, md1.Options().Equal(ns1.Options()))
+
+	// ensure the other namespaces are unchanged
+	nses = d.Namespaces()
+	require.Len(t, nses, 2)
 	ns2, ok := d.Namespace(defaultTestNs2ID)
 	require.True(t, ok)
-	require.Equal(t, defaultTestNs2Opts, ns2.Options())
+	require.True(t, defaultTestNs2Opts.Equal(ns2.Options()))
+}
+
+func TestDatabaseUpdateNamespaceIndexInsertMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, Bootstrapped)
+	require.NoError(t, d.Open())
+	defer func() {
+		close(mapCh)
+		require.NoError(t, d.Close())
+		leaktest.CheckTimeout(t, time.Second)()
+	}()
+
+	// retrieve the update channel to track propatation
+	updateCh := d.opts.NamespaceInitializer().(*mockNsInitializer).updateCh
+
+	// construct new namespace Map
+	iopts := defaultTestNs1Opts.IndexOptions().SetInsertMode(namespace.IndexInsertModeSync)
+	md1, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.SetIndexOptions(iopts))
+	require.NoError(t, err)
+	md2, err := namespace.NewMetadata(defaultTestNs2ID, defaultTestNs2Opts)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md1, md2})
+	require.NoError(t, err)
+
+	// update the database watch with new Map
+	mapCh <- nsMap
+
+	// wait till the update has propagated
+	<-updateCh
+	<-updateCh
+
+	// ensure the index insert mode was changed in place
+	ns1, ok := d.Namespace(defaultTestNs1ID)
+	require.True(t, ok)
+	require.True(t, xclock.WaitUntil(func() bool {
+		return ns1.Options().IndexOptions().InsertMode() == namespace.IndexInsertModeSync
+	}, 2*time.Second))
+	require.True(t, md1.Options().Equal(ns1.Options()))
+
+	ns2, ok := d.Namespace(defaultTestNs2ID)
+	require.True(t, ok)
+	require.True(t, defaultTestNs2Opts.Equal(ns2.Options()))
 }
 
 func TestDatabaseNamespaceIndexFunctions(t *testing.T) {
@@ -686,16 +782,18 @@ func TestDatabaseNamespaceIndexFunctions(t *testing.T) {
 
 	ctx := context.NewContext()
 	ns.EXPECT().WriteTagged(ctx, ident.NewIDMatcher("foo"), gomock.Any(),
-		time.Time{}, 1.0, xtime.Second, nil).Return(nil)
-	require.NoError(t, d.WriteTagged(ctx, ident.StringID("testns"),
+		time.Time{}, 1.0, xtime.Second, nil).Return(true, nil)
+	_, err := d.WriteTagged(ctx, ident.StringID("testns"),
 		ident.StringID("foo"), ident.EmptyTagIterator, time.Time{},
-		1.0, xtime.Second, nil))
+		1.0, xtime.Second, nil)
+	require.NoError(t, err)
 
 	ns.EXPECT().WriteTagged(ctx, ident.NewIDMatcher("foo"), gomock.Any(),
-		time.Time{}, 1.0, xtime.Second, nil).Return(fmt.Errorf("random err"))
-	require.Error(t, d.WriteTagged(ctx, ident.StringID("testns"),
+		time.Time{}, 1.0, xtime.Second, nil).Return(false, fmt.Errorf("random err"))
+	_, err = d.WriteTagged(ctx, ident.StringID("testns"),
 		ident.StringID("foo"), ident.EmptyTagIterator, time.Time{},
-		1.0, xtime.Second, nil))
+		1.0, xtime.Second, nil)
+	require.Error(t, err)
 
 	var (
 		q    = index.Query{}
@@ -716,6 +814,52 @@ func TestDatabaseNamespaceIndexFunctions(t *testing.T) {
 	require.NoError(t, d.Close())
 }
 
+func TestDatabaseQueryIDsMultiNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, BootstrapNotStarted)
+	defer func() {
+		close(mapCh)
+	}()
+
+	ns1 := dbAddNewMockNamespace(ctrl, d, "testns1")
+	ns2 := dbAddNewMockNamespace(ctrl, d, "testns2")
+
+	var (
+		ctx  = context.NewContext()
+		q    = index.Query{}
+		opts = index.QueryOptions{}
+		res1 = index.NewMockResults(ctrl)
+		res2 = index.NewMockResults(ctrl)
+		nses = []ident.ID{ident.StringID("testns1"), ident.StringID("testns2")}
+	)
+
+	exceeded := index.QueryLimitExceeded{Limit: index.MaxResultsQueryLimit, Max: 10}
+	ns1.EXPECT().QueryIDs(ctx, q, opts).
+		Return(index.QueryResults{Results: res1, Exhaustive: true,
+			Warnings: []index.QueryLimitExceeded{exceeded}}, nil)
+	ns2.EXPECT().QueryIDs(ctx, q, opts).
+		Return(index.QueryResults{Results: res2, Exhaustive: false,
+			Warnings: []index.QueryLimitExceeded{exceeded}}, nil)
+	results, err := d.QueryIDsMultiNamespace(ctx, nses, q, opts)
+	require.NoError(t, err)
+	require.Equal(t, []index.Results{res1, res2}, results.Results)
+	require.False(t, results.Exhaustive)
+	require.Equal(t, []index.QueryLimitExceeded{exceeded}, results.Warnings)
+
+	ns1.EXPECT().QueryIDs(ctx, q, opts).
+		Return(index.QueryResults{Results: res1, Exhaustive: true}, nil)
+	ns2.EXPECT().QueryIDs(ctx, q, opts).
+		Return(index.QueryResults{}, fmt.Errorf("random err"))
+	_, err = d.QueryIDsMultiNamespace(ctx, nses, q, opts)
+	require.Error(t, err)
+
+	_, err = d.QueryIDsMultiNamespace(ctx,
+		[]ident.ID{ident.StringID("testns1"), ident.StringID("unknown")}, q, opts)
+	require.Error(t, err)
+}
+
 func TestDatabaseBootstrapState(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -746,3 +890,62 @@ func TestDatabaseBootstrapState(t *testing.T) {
 		},
 	}, dbBootstrapState)
 }
+
+func TestDatabaseFlushBarrier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, Bootstrapped)
+	defer func() {
+		close(mapCh)
+	}()
+
+	now := time.Now()
+	d.nowFn = func() time.Time { return now }
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shard.EXPECT().FlushState(gomock.Any()).Return(fileOpState{}).AnyTimes()
+
+	ns1 := dbAddNewMockNamespace(ctrl, d, "testns1")
+	ns1.EXPECT().BootstrapState().Return(ShardBootstrapStates{0: Bootstrapped})
+	ns1.EXPECT().Options().Return(defaultTestNs1Opts).AnyTimes()
+	ns1.EXPECT().GetOwnedShards().Return([]databaseShard{shard})
+	dbAddNewMockNamespace(ctrl, d, "testns2").EXPECT().
+		BootstrapState().Return(ShardBootstrapStates{})
+
+	var (
+		tickStart = now.Add(-time.Minute)
+		token     = strconv.FormatInt(now.UnixNano(), 10)
+		expiresAt = now.Add(maxFlushBarrierHold)
+	)
+	mediator := NewMockdatabaseMediator(ctrl)
+	mediator.EXPECT().IsBootstrapped().Return(true).Times(2)
+	mediator.EXPECT().LastTickStart().Return(tickStart)
+	gomock.InOrder(
+		mediator.EXPECT().DisableFileOps(),
+		mediator.EXPECT().
+			FlushNamespaces(tickStart, gomock.Any(), []databaseNamespace{ns1}).
+			Return(nil),
+		mediator.EXPECT().HoldCleanup(token, expiresAt),
+		mediator.EXPECT().EnableFileOps(),
+	)
+	d.mediator = mediator
+
+	_, err := d.FlushBarrier([]ident.ID{ident.StringID("unknown")})
+	require.Error(t, err)
+
+	barrier, err := d.FlushBarrier([]ident.ID{ident.StringID("testns1")})
+	require.NoError(t, err)
+	require.Equal(t, token, barrier.Token)
+	require.True(t, barrier.BarrierTime.Equal(tickStart))
+	require.True(t, barrier.ExpiresAt.Equal(expiresAt))
+	require.Equal(t, []FlushBarrierNamespace{
+		{Namespace: "testns1"},
+	}, barrier.Namespaces)
+
+	mediator.EXPECT().ReleaseCleanup(token).Return(true)
+	require.NoError(t, d.ReleaseFlushBarrier(token))
+	mediator.EXPECT().ReleaseCleanup(token).Return(false)
+	require.Equal(t, errFlushBarrierUnknownToken, d.ReleaseFlushBarrier(token))
+}
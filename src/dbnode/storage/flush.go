@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/retention"
 	xerrors "github.com/m3db/m3x/errors"
@@ -52,9 +53,11 @@ const (
 type flushManager struct {
 	sync.RWMutex
 
-	database database
-	opts     Options
-	pm       persist.Manager
+	database  database
+	opts      Options
+	pm        persist.Manager
+	schedules BackgroundSchedules
+	nowFn     clock.NowFn
 	// isFlushingOrSnapshotting is used to protect the flush manager against
 	// concurrent use, while flushInProgress and snapshotInProgress are more
 	// granular and are used for emitting granular gauges.
@@ -70,6 +73,8 @@ func newFlushManager(database database, scope tally.Scope) databaseFlushManager
 		database:        database,
 		opts:            opts,
 		pm:              opts.PersistManager(),
+		schedules:       opts.BackgroundSchedules(),
+		nowFn:           opts.ClockOptions().NowFn(),
 		isFlushing:      scope.Gauge("flush"),
 		isSnapshotting:  scope.Gauge("snapshot"),
 		isIndexFlushing: scope.Gauge("index-flush"),
@@ -79,6 +84,18 @@ func newFlushManager(database database, scope tally.Scope) databaseFlushManager
 func (m *flushManager) Flush(
 	tickStart time.Time,
 	dbBootstrapStateAtTickStart DatabaseBootstrapState,
+) error {
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+	return m.FlushNamespaces(tickStart, dbBootstrapStateAtTickStart, namespaces)
+}
+
+func (m *flushManager) FlushNamespaces(
+	tickStart time.Time,
+	dbBootstrapStateAtTickStart DatabaseBootstrapState,
+	namespaces []databaseNamespace,
 ) error {
 	// ensure only a single flush is happening at a time
 	m.Lock()
@@ -91,13 +108,36 @@ func (m *flushManager) Flush(
 
 	defer m.setState(flushManagerIdle)
 
-	// create flush-er
-	flush, err := m.pm.StartDataPersist()
-	if err != nil {
-		return err
+	// Flush, snapshot and flush the index of each priority class in turn so
+	// that critical namespaces have their data persisted and index segments
+	// compacted before lower priority namespaces compete for the disk. Lower
+	// priority classes defer their index flush to the next flush if a higher
+	// priority class failed to flush so they don't compete with it while it
+	// catches up.
+	var (
+		multiErr        = xerrors.NewMultiError()
+		deferIndexFlush bool
+	)
+	for _, group := range groupNamespacesByPriority(namespaces) {
+		err := m.flushNamespacesWithPriority(tickStart,
+			dbBootstrapStateAtTickStart, group, deferIndexFlush)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			deferIndexFlush = true
+		}
 	}
 
-	namespaces, err := m.database.GetOwnedNamespaces()
+	return multiErr.FinalError()
+}
+
+func (m *flushManager) flushNamespacesWithPriority(
+	tickStart time.Time,
+	dbBootstrapStateAtTickStart DatabaseBootstrapState,
+	namespaces []databaseNamespace,
+	deferIndexFlush bool,
+) error {
+	// create flush-er
+	flush, err := m.pm.StartDataPersist()
 	if err != nil {
 		return err
 	}
@@ -114,7 +154,10 @@ func (m *flushManager) Flush(
 				"tried to flush ns: %s, but did not have shard bootstrap times", ns.ID().String()))
 			continue
 		}
-		multiErr = multiErr.Add(m.flushNamespaceWithTimes(ns, shardBootstrapTimes, flushTimes, flush))
+		nsStart := m.nowFn()
+		err := m.flushNamespaceWithTimes(ns, shardBootstrapTimes, flushTimes, flush)
+		m.schedules.RecordNamespaceRun(BackgroundProcessFlush, ns.ID(), nsStart, err)
+		multiErr = multiErr.Add(err)
 	}
 
 	// Perform two separate loops through all the namespaces so that we can emit better
@@ -143,6 +186,10 @@ func (m *flushManager) Flush(
 	// mark data flush finished
 	multiErr = multiErr.Add(flush.DoneData())
 
+	if deferIndexFlush {
+		return multiErr.FinalError()
+	}
+
 	// flush index data
 	// create index-flusher
 	indexFlush, err := m.pm.StartIndexPersist()
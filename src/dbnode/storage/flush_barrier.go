@@ -0,0 +1,225 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3x/ident"
+)
+
+const (
+	// maxFlushBarrierHold bounds how long a flush barrier holds cleanups so
+	// that a barrier that is never released cannot stop cleanups forever.
+	maxFlushBarrierHold = 24 * time.Hour
+)
+
+var (
+	errFlushBarrierNotBootstrapped = errors.New("flush barrier requires a bootstrapped database")
+	errFlushBarrierNoTick          = errors.New("flush barrier requires a complete tick")
+	errFlushBarrierUnknownToken    = errors.New("flush barrier token is unknown or expired")
+)
+
+// FlushBarrier is the set of fileset volumes of the namespaces persisted by
+// a flush barrier, together they represent the data of the namespaces at
+// the barrier time and can be copied by external backup tooling. Cleanups
+// are held until the barrier is released with its token so that the
+// volumes are not removed while they are copied.
+type FlushBarrier struct {
+	// Token identifies the barrier, it is the time the barrier was taken in
+	// unix nanoseconds.
+	Token string `json:"token"`
+	// BarrierTime is the start of the last complete tick before the barrier,
+	// it is the time the namespaces were flushed at and the snapshot time of
+	// the barrier snapshots.
+	BarrierTime time.Time               `json:"barrierTime"`
+	Namespaces  []FlushBarrierNamespace `json:"namespaces"`
+	// ExpiresAt is when cleanups are no longer held if the barrier is not
+	// released.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FlushBarrierNamespace is the fileset volumes of a namespace persisted by
+// a flush barrier.
+type FlushBarrierNamespace struct {
+	Namespace string `json:"namespace"`
+
+	// Data is the flushed data filesets of the shards owned by the node.
+	Data []FlushBarrierFileSet `json:"data"`
+
+	// Snapshots is the data snapshot filesets taken at the barrier time.
+	Snapshots []FlushBarrierFileSet `json:"snapshots"`
+
+	// Index is every complete index fileset volume of the namespace.
+	Index []FlushBarrierFileSet `json:"index"`
+}
+
+// FlushBarrierFileSet is a fileset volume and the files that make it up.
+type FlushBarrierFileSet struct {
+	Shard       uint32    `json:"shard"`
+	BlockStart  time.Time `json:"blockStart"`
+	VolumeIndex int       `json:"volumeIndex"`
+	Files       []string  `json:"files"`
+}
+
+func newFlushBarrierFileSet(f fs.FileSetFile) FlushBarrierFileSet {
+	return FlushBarrierFileSet{
+		Shard:       f.ID.Shard,
+		BlockStart:  f.ID.BlockStart,
+		VolumeIndex: f.ID.VolumeIndex,
+		Files:       f.AbsoluteFilepaths,
+	}
+}
+
+func (d *db) FlushBarrier(namespaces []ident.ID) (FlushBarrier, error) {
+	if !d.IsBootstrapped() {
+		return FlushBarrier{}, errFlushBarrierNotBootstrapped
+	}
+
+	var barrierNamespaces []databaseNamespace
+	if len(namespaces) == 0 {
+		owned, err := d.GetOwnedNamespaces()
+		if err != nil {
+			return FlushBarrier{}, err
+		}
+		barrierNamespaces = owned
+	}
+	for _, namespace := range namespaces {
+		n, err := d.namespaceFor(namespace)
+		if err != nil {
+			return FlushBarrier{}, err
+		}
+		barrierNamespaces = append(barrierNamespaces, n)
+	}
+
+	// NB: barriers are serialized so that one barrier does not enable file
+	// operations while another is still flushing.
+	d.flushBarrierLock.Lock()
+	defer d.flushBarrierLock.Unlock()
+
+	// NB: the namespaces are flushed at the start of the last complete tick,
+	// as a background flush is, since only blocks that a complete tick has
+	// seen since they stopped receiving writes may be flushed.
+	barrierTime := d.mediator.LastTickStart()
+	if barrierTime.IsZero() {
+		return FlushBarrier{}, errFlushBarrierNoTick
+	}
+
+	// NB: file operations are disabled for the duration of the barrier so
+	// neither a background flush nor a cleanup runs between flushing the
+	// namespaces and listing their filesets.
+	d.mediator.DisableFileOps()
+	defer d.mediator.EnableFileOps()
+
+	err := d.mediator.FlushNamespaces(barrierTime, d.BootstrapState(),
+		barrierNamespaces)
+	if err != nil {
+		return FlushBarrier{}, err
+	}
+
+	now := d.nowFn()
+	result := FlushBarrier{
+		Token:       strconv.FormatInt(now.UnixNano(), 10),
+		BarrierTime: barrierTime,
+		ExpiresAt:   now.Add(maxFlushBarrierHold),
+	}
+	for _, n := range barrierNamespaces {
+		ns, err := d.flushBarrierNamespace(n, barrierTime)
+		if err != nil {
+			return FlushBarrier{}, err
+		}
+		result.Namespaces = append(result.Namespaces, ns)
+	}
+
+	// hold cleanups before file operations are enabled again so that the
+	// returned volumes are not removed before they are copied.
+	d.mediator.HoldCleanup(result.Token, result.ExpiresAt)
+	return result, nil
+}
+
+func (d *db) ReleaseFlushBarrier(token string) error {
+	if !d.mediator.ReleaseCleanup(token) {
+		return errFlushBarrierUnknownToken
+	}
+	return nil
+}
+
+func (d *db) flushBarrierNamespace(
+	n databaseNamespace,
+	barrierTime time.Time,
+) (FlushBarrierNamespace, error) {
+	var (
+		id             = n.ID()
+		ropts          = n.Options().RetentionOptions()
+		start          = barrierTime.Add(-ropts.RetentionPeriod())
+		blockSize      = ropts.BlockSize()
+		filePathPrefix = d.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+		result         = FlushBarrierNamespace{Namespace: id.String()}
+	)
+	for _, shard := range n.GetOwnedShards() {
+		for t := start.Truncate(blockSize); t.Before(barrierTime); t = t.Add(blockSize) {
+			if shard.FlushState(t).Status != fileOpSuccess {
+				continue
+			}
+			fileset, ok, err := fs.FileSetAt(filePathPrefix, id, shard.ID(), t)
+			if err != nil {
+				return FlushBarrierNamespace{}, err
+			}
+			if ok {
+				result.Data = append(result.Data, newFlushBarrierFileSet(fileset))
+			}
+		}
+
+		snapshots, err := fs.SnapshotFiles(filePathPrefix, id, shard.ID())
+		if err != nil {
+			return FlushBarrierNamespace{}, err
+		}
+		for _, f := range snapshots {
+			if !f.HasCheckpointFile() {
+				continue
+			}
+			snapshotTime, err := f.SnapshotTime()
+			if err != nil {
+				return FlushBarrierNamespace{}, err
+			}
+			if snapshotTime.Equal(barrierTime) {
+				result.Snapshots = append(result.Snapshots, newFlushBarrierFileSet(f))
+			}
+		}
+	}
+
+	if !n.Options().IndexOptions().Enabled() {
+		return result, nil
+	}
+	indexBlockSize := n.Options().IndexOptions().BlockSize()
+	for t := start.Truncate(indexBlockSize); t.Before(barrierTime); t = t.Add(indexBlockSize) {
+		filesets, err := fs.IndexFileSetsAt(filePathPrefix, id, t)
+		if err != nil {
+			return FlushBarrierNamespace{}, err
+		}
+		for _, f := range filesets {
+			result.Index = append(result.Index, newFlushBarrierFileSet(f))
+		}
+	}
+	return result, nil
+}
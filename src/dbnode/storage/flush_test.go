@@ -237,6 +237,65 @@ func TestFlushManagerNamespaceIndexingEnabled(t *testing.T) {
 	require.NoError(t, fm.Flush(now, bootstrapStates))
 }
 
+func TestFlushManagerFlushByNamespacePriority(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	newNamespace := func(id string, priority namespace.Priority) *MockdatabaseNamespace {
+		nsOpts := defaultTestNs1Opts.
+			SetPriority(priority).
+			SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true))
+		ns := NewMockdatabaseNamespace(ctrl)
+		ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+		ns.EXPECT().ID().Return(ident.StringID(id)).AnyTimes()
+		ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
+		return ns
+	}
+
+	var (
+		fakeErr    = errors.New("fake error while flushing index")
+		critical   = newNamespace("critical", namespace.PriorityCritical)
+		background = newNamespace("background", namespace.PriorityBackground)
+
+		criticalFlusher      = persist.NewMockDataFlush(ctrl)
+		criticalIndexFlusher = persist.NewMockIndexFlush(ctrl)
+		backgroundFlusher    = persist.NewMockDataFlush(ctrl)
+		mockPersistManager   = persist.NewMockManager(ctrl)
+	)
+
+	// The critical namespace is flushed and has its index flushed with its
+	// own persist before the background namespace is flushed, the background
+	// namespace defers its index flush since the critical namespace failed.
+	gomock.InOrder(
+		mockPersistManager.EXPECT().StartDataPersist().Return(criticalFlusher, nil),
+		critical.EXPECT().Flush(gomock.Any(), gomock.Any(), criticalFlusher).Return(nil).MinTimes(1),
+		criticalFlusher.EXPECT().DoneData().Return(nil),
+		mockPersistManager.EXPECT().StartIndexPersist().Return(criticalIndexFlusher, nil),
+		critical.EXPECT().FlushIndex(criticalIndexFlusher).Return(fakeErr),
+		criticalIndexFlusher.EXPECT().DoneIndex().Return(nil),
+		mockPersistManager.EXPECT().StartDataPersist().Return(backgroundFlusher, nil),
+		background.EXPECT().Flush(gomock.Any(), gomock.Any(), backgroundFlusher).Return(nil).MinTimes(1),
+		backgroundFlusher.EXPECT().DoneData().Return(nil),
+	)
+
+	testOpts := testDatabaseOptions().SetPersistManager(mockPersistManager)
+	db := newMockdatabase(ctrl)
+	db.EXPECT().Options().Return(testOpts).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{background, critical}, nil)
+
+	fm := newFlushManager(db, tally.NoopScope).(*flushManager)
+	fm.pm = mockPersistManager
+
+	now := time.Unix(0, 0)
+	bootstrapStates := DatabaseBootstrapState{
+		NamespaceBootstrapStates: map[string]ShardBootstrapStates{
+			"critical":   ShardBootstrapStates{},
+			"background": ShardBootstrapStates{},
+		},
+	}
+	require.Equal(t, fakeErr, fm.Flush(now, bootstrapStates))
+}
+
 func TestFlushManagerFlushTimeStart(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
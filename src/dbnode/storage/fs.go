@@ -24,6 +24,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	xlog "github.com/m3db/m3x/log"
 )
 
@@ -60,11 +61,16 @@ type fileSystemManager struct {
 	databaseCleanupManager
 	sync.RWMutex
 
-	log      xlog.Logger
-	database database
-	opts     Options
-	status   fileOpStatus
-	enabled  bool
+	log       xlog.Logger
+	database  database
+	opts      Options
+	schedules BackgroundSchedules
+	nowFn     clock.NowFn
+	status    fileOpStatus
+	enabled   bool
+
+	// cleanupHolds is the time until which each hold defers cleanups.
+	cleanupHolds map[string]time.Time
 }
 
 func newFileSystemManager(
@@ -80,11 +86,42 @@ func newFileSystemManager(
 		databaseFlushManager:   fm,
 		databaseCleanupManager: cm,
 		log:      instrumentOpts.Logger(),
-		database: database,
-		opts:     opts,
-		status:   fileOpNotStarted,
-		enabled:  true,
+		database:  database,
+		opts:      opts,
+		schedules: opts.BackgroundSchedules(),
+		nowFn:     opts.ClockOptions().NowFn(),
+		status:    fileOpNotStarted,
+		enabled:   true,
+
+		cleanupHolds: make(map[string]time.Time),
+	}
+}
+
+func (m *fileSystemManager) HoldCleanup(token string, until time.Time) {
+	m.Lock()
+	m.cleanupHolds[token] = until
+	m.Unlock()
+}
+
+func (m *fileSystemManager) ReleaseCleanup(token string) bool {
+	m.Lock()
+	_, ok := m.cleanupHolds[token]
+	delete(m.cleanupHolds, token)
+	m.Unlock()
+	return ok
+}
+
+func (m *fileSystemManager) isCleanupHeld() bool {
+	now := m.nowFn()
+
+	m.Lock()
+	defer m.Unlock()
+	for token, until := range m.cleanupHolds {
+		if !now.Before(until) {
+			delete(m.cleanupHolds, token)
+		}
 	}
+	return len(m.cleanupHolds) > 0
 }
 
 func (m *fileSystemManager) Disable() fileOpStatus {
@@ -126,11 +163,21 @@ func (m *fileSystemManager) Run(
 
 	// NB(xichen): perform data cleanup and flushing sequentially to minimize the impact of disk seeks.
 	flushFn := func() {
-		if err := m.Cleanup(t); err != nil {
-			m.log.Errorf("error when cleaning up data for time %v: %v", t, err)
+		if m.schedules.ShouldRun(BackgroundProcessCleanup) && !m.isCleanupHeld() {
+			start := m.nowFn()
+			err := m.Cleanup(t)
+			m.schedules.RecordRun(BackgroundProcessCleanup, start, err)
+			if err != nil {
+				m.log.Errorf("error when cleaning up data for time %v: %v", t, err)
+			}
 		}
-		if err := m.Flush(t, dbBootstrapStates); err != nil {
-			m.log.Errorf("error when flushing data for time %v: %v", t, err)
+		if m.schedules.ShouldRun(BackgroundProcessFlush) {
+			start := m.nowFn()
+			err := m.Flush(t, dbBootstrapStates)
+			m.schedules.RecordRun(BackgroundProcessFlush, start, err)
+			if err != nil {
+				m.log.Errorf("error when flushing data for time %v: %v", t, err)
+			}
 		}
 		m.Lock()
 		m.status = fileOpNotStarted
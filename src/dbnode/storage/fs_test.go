@@ -92,3 +92,37 @@ func TestFileSystemManagerRun(t *testing.T) {
 	mgr.Run(ts, DatabaseBootstrapState{}, syncRun, noForce)
 	require.Equal(t, fileOpNotStarted, mgr.status)
 }
+
+func TestFileSystemManagerRunCleanupHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	database := newMockdatabase(ctrl)
+	database.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+
+	fm := NewMockdatabaseFlushManager(ctrl)
+	cm := NewMockdatabaseCleanupManager(ctrl)
+	fsm := newFileSystemManager(database, testDatabaseOptions())
+	mgr := fsm.(*fileSystemManager)
+	mgr.databaseFlushManager = fm
+	mgr.databaseCleanupManager = cm
+
+	ts := time.Now()
+	mgr.nowFn = func() time.Time { return ts }
+
+	// cleanups are skipped while held, flushes are not.
+	mgr.HoldCleanup("foo", ts.Add(time.Hour))
+	fm.EXPECT().Flush(ts, DatabaseBootstrapState{}).Return(nil)
+	mgr.Run(ts, DatabaseBootstrapState{}, syncRun, noForce)
+
+	require.True(t, mgr.ReleaseCleanup("foo"))
+	require.False(t, mgr.ReleaseCleanup("foo"))
+
+	// expired holds no longer defer cleanups.
+	mgr.HoldCleanup("bar", ts)
+	gomock.InOrder(
+		cm.EXPECT().Cleanup(ts).Return(nil),
+		fm.EXPECT().Flush(ts, DatabaseBootstrapState{}).Return(nil),
+	)
+	mgr.Run(ts, DatabaseBootstrapState{}, syncRun, noForce)
+	require.False(t, mgr.ReleaseCleanup("bar"))
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	m3dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
+	xtime "github.com/m3db/m3x/time"
+)
+
+var (
+	errFutureWritePolicyUnspecified = errors.New("future write policy unspecified")
+)
+
+// FutureWritePolicy is the action taken for datapoints with timestamps
+// beyond the buffer future of their namespace plus the max clock skew.
+type FutureWritePolicy uint
+
+const (
+	// FutureWriteReject rejects the datapoints.
+	FutureWriteReject FutureWritePolicy = iota
+	// FutureWriteClamp writes the datapoints at the latest time accepted
+	// by the buffer instead.
+	FutureWriteClamp
+
+	// DefaultFutureWritePolicy is the default future write policy.
+	DefaultFutureWritePolicy = FutureWriteReject
+)
+
+// ValidFutureWritePolicies returns the valid future write policies.
+func ValidFutureWritePolicies() []FutureWritePolicy {
+	return []FutureWritePolicy{FutureWriteReject, FutureWriteClamp}
+}
+
+func (p FutureWritePolicy) String() string {
+	switch p {
+	case FutureWriteReject:
+		return "reject"
+	case FutureWriteClamp:
+		return "clamp"
+	}
+	return "unknown"
+}
+
+// ParseFutureWritePolicy parses a FutureWritePolicy from a string.
+func ParseFutureWritePolicy(str string) (FutureWritePolicy, error) {
+	var r FutureWritePolicy
+	if str == "" {
+		return r, errFutureWritePolicyUnspecified
+	}
+	for _, valid := range ValidFutureWritePolicies() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid FutureWritePolicy '%s' valid types are: %v",
+		str, ValidFutureWritePolicies())
+}
+
+// UnmarshalYAML unmarshals a FutureWritePolicy into a valid type from string.
+func (p *FutureWritePolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseFutureWritePolicy(str)
+	if err != nil {
+		return err
+	}
+	*p = r
+	return nil
+}
+
+// futureWriteTimestamp returns the timestamp to write a datapoint at, the
+// buffer only accepts datapoints before now plus the buffer future so
+// datapoints at or after are clamped to the latest time it accepts if
+// within the max clock skew or the policy is to clamp, otherwise rejected.
+func futureWriteTimestamp(
+	now time.Time,
+	timestamp time.Time,
+	unit xtime.Unit,
+	bufferFuture time.Duration,
+	maxClockSkew time.Duration,
+	policy FutureWritePolicy,
+) (time.Time, bool, error) {
+	futureLimit := now.Add(bufferFuture)
+	if timestamp.Before(futureLimit) {
+		return timestamp, false, nil
+	}
+	if policy == FutureWriteReject && !timestamp.Before(futureLimit.Add(maxClockSkew)) {
+		return timestamp, false, m3dberrors.ErrTooFuture
+	}
+
+	// NB: truncate to the unit so the clamped timestamp can be encoded.
+	unitDuration, err := unit.Value()
+	if err != nil {
+		return timestamp, false, err
+	}
+	clamped := futureLimit.Add(-1).Truncate(unitDuration)
+	if !clamped.After(now) {
+		// NB: the buffer future is less than the unit, nothing after now
+		// can be written at this unit.
+		return timestamp, false, m3dberrors.ErrTooFuture
+	}
+	return clamped, true, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	m3dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFutureWriteTimestamp(t *testing.T) {
+	var (
+		now          = time.Unix(1000, 0)
+		bufferFuture = 2 * time.Minute
+		maxSkew      = time.Minute
+		limit        = now.Add(bufferFuture)
+		latest       = limit.Add(-time.Second)
+	)
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		policy    FutureWritePolicy
+		expected  time.Time
+		clamped   bool
+		err       error
+	}{
+		{
+			name:      "within buffer future",
+			timestamp: now.Add(time.Minute),
+			policy:    FutureWriteReject,
+			expected:  now.Add(time.Minute),
+		},
+		{
+			name:      "within max skew",
+			timestamp: limit.Add(30 * time.Second),
+			policy:    FutureWriteReject,
+			expected:  latest,
+			clamped:   true,
+		},
+		{
+			name:      "beyond max skew rejected",
+			timestamp: limit.Add(maxSkew),
+			policy:    FutureWriteReject,
+			err:       m3dberrors.ErrTooFuture,
+		},
+		{
+			name:      "beyond max skew clamped",
+			timestamp: limit.Add(time.Hour),
+			policy:    FutureWriteClamp,
+			expected:  latest,
+			clamped:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, clamped, err := futureWriteTimestamp(now, test.timestamp,
+				xtime.Second, bufferFuture, maxSkew, test.policy)
+			if test.err != nil {
+				require.Equal(t, test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, test.expected.Equal(result))
+			require.Equal(t, test.clamped, clamped)
+		})
+	}
+}
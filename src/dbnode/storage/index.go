@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
@@ -60,8 +61,10 @@ var (
 	errDbIndexUnableToQueryClosed         = errors.New("unable to query database index, already closed")
 	errDbIndexUnableToFlushClosed         = errors.New("unable to flush database index, already closed")
 	errDbIndexUnableToCleanupClosed       = errors.New("unable to cleanup database index, already closed")
+	errDbIndexUnableToReloadClosed        = errors.New("unable to reload database index blocks, already closed")
 	errDbIndexTerminatingTickCancellation = errors.New("terminating tick early due to cancellation")
 	errDbIndexIsBootstrapping             = errors.New("index is already bootstrapping")
+	errDbIndexCardinalityReportDisabled   = errors.New("index cardinality reporting is disabled")
 )
 
 const (
@@ -74,16 +77,31 @@ type nsIndex struct {
 
 	// all the vars below this line are not modified past the ctor
 	// and don't require a lock when being accessed.
-	nowFn           clock.NowFn
-	blockSize       time.Duration
-	retentionPeriod time.Duration
-	bufferPast      time.Duration
-	bufferFuture    time.Duration
+	nowFn         clock.NowFn
+	blockSize     time.Duration
+	retentionOpts retention.Options
+	bufferPast    time.Duration
+	bufferFuture  time.Duration
 
 	indexFilesetsBeforeFn indexFilesetsBeforeFn
 	deleteFilesFn         deleteFilesFn
 
-	newBlockFn          newBlockFn
+	// recentlyIndexed is nil if repeated inserts are not skipped.
+	recentlyIndexed *recentlyIndexedIDs
+
+	// cardinalityReporter is nil if cardinality reporting is disabled.
+	cardinalityReporter *indexCardinalityReporter
+
+	// warmer is nil if warming the index after bootstrap is disabled.
+	warmer *indexWarmer
+
+	// tagDictionary is nil if the shared tag dictionary is disabled.
+	tagDictionary convert.Dictionary
+
+	// queryCache is nil if query results are not cached.
+	queryCache *indexQueryCache
+
+	newBlockFn          index.NewBlockFn
 	logger              xlog.Logger
 	opts                Options
 	nsMetadata          namespace.Metadata
@@ -120,12 +138,11 @@ type nsIndexState struct {
 // under the same nsIndex mutex.
 type nsIndexRuntimeOptions struct {
 	insertMode            index.InsertMode
+	insertSyncTimeout     time.Duration
 	maxQueryLimit         int64
 	flushBlockNumSegments uint
 }
 
-type newBlockFn func(time.Time, namespace.Metadata, index.Options) (index.Block, error)
-
 // NB(prateek): the returned filesets are strictly before the given time, i.e. they
 // live in the period (-infinity, exclusiveTime).
 type indexFilesetsBeforeFn func(dir string,
@@ -137,7 +154,7 @@ type newNamespaceIndexOpts struct {
 	md              namespace.Metadata
 	opts            Options
 	newIndexQueueFn newNamespaceIndexInsertQueueFn
-	newBlockFn      newBlockFn
+	newBlockFn      index.NewBlockFn
 }
 
 // newNamespaceIndex returns a new namespaceIndex for the provided namespace.
@@ -149,7 +166,7 @@ func newNamespaceIndex(
 		md:              nsMD,
 		opts:            opts,
 		newIndexQueueFn: newNamespaceIndexInsertQueue,
-		newBlockFn:      index.NewBlock,
+		newBlockFn:      opts.IndexOptions().NewBlockFn(),
 	})
 }
 
@@ -163,14 +180,14 @@ func newNamespaceIndexWithInsertQueueFn(
 		md:              nsMD,
 		opts:            opts,
 		newIndexQueueFn: newIndexQueueFn,
-		newBlockFn:      index.NewBlock,
+		newBlockFn:      opts.IndexOptions().NewBlockFn(),
 	})
 }
 
 // newNamespaceIndexWithNewBlockFn is a ctor used in tests to inject blocks.
 func newNamespaceIndexWithNewBlockFn(
 	nsMD namespace.Metadata,
-	newBlockFn newBlockFn,
+	newBlockFn index.NewBlockFn,
 	opts Options,
 ) (namespaceIndex, error) {
 	return newNamespaceIndexWithOptions(newNamespaceIndexOpts{
@@ -209,21 +226,24 @@ func newNamespaceIndexWithOptions(
 	idx := &nsIndex{
 		state: nsIndexState{
 			runtimeOpts: nsIndexRuntimeOptions{
-				insertMode:            indexOpts.InsertMode(), // FOLLOWUP(prateek): wire to allow this to be tweaked at runtime
+				insertMode:            indexInsertMode(nsMD.Options().IndexOptions().InsertMode(), indexOpts.InsertMode()),
+				insertSyncTimeout:     indexOpts.InsertSyncTimeout(),
 				flushBlockNumSegments: runtime.DefaultFlushIndexBlockNumSegments,
 			},
 			blocksByTime: make(map[xtime.UnixNano]index.Block),
 		},
 
-		nowFn:           nowFn,
-		blockSize:       nsMD.Options().IndexOptions().BlockSize(),
-		retentionPeriod: nsMD.Options().RetentionOptions().RetentionPeriod(),
-		bufferPast:      nsMD.Options().RetentionOptions().BufferPast(),
-		bufferFuture:    nsMD.Options().RetentionOptions().BufferFuture(),
+		nowFn:         nowFn,
+		blockSize:     nsMD.Options().IndexOptions().BlockSize(),
+		retentionOpts: nsMD.Options().RetentionOptions(),
+		bufferPast:    nsMD.Options().RetentionOptions().BufferPast(),
+		bufferFuture:  nsMD.Options().RetentionOptions().BufferFuture(),
 
 		indexFilesetsBeforeFn: fs.IndexFileSetsBefore,
 		deleteFilesFn:         fs.DeleteFiles,
 
+		tagDictionary: indexOpts.TagDictionary(),
+
 		newBlockFn: newBlockFn,
 		opts:       newIndexOpts.opts,
 		logger:     indexOpts.InstrumentOptions().Logger(),
@@ -231,6 +251,13 @@ func newNamespaceIndexWithOptions(
 
 		metrics: newNamespaceIndexMetrics(instrumentOpts),
 	}
+	if window := indexOpts.RecentlyIndexedWindow(); window > 0 {
+		idx.recentlyIndexed = newRecentlyIndexedIDs(window, nowFn())
+	}
+	if size := indexOpts.QueryCacheSize(); size > 0 {
+		idx.queryCache = newIndexQueryCache(size, indexOpts.QueryCacheTTL(),
+			nowFn, scope.SubScope("query-cache"))
+	}
 	if runtimeOptsMgr != nil {
 		idx.runtimeOptsListener = runtimeOptsMgr.RegisterListener(idx)
 	}
@@ -250,6 +277,19 @@ func newNamespaceIndexWithOptions(
 		return nil, err
 	}
 
+	if interval := indexOpts.CardinalityReportInterval(); interval > 0 {
+		idx.cardinalityReporter = newIndexCardinalityReporter(interval,
+			indexOpts.CardinalityReportMaxValues(), idx.blocksDescOrder, nowFn,
+			indexOpts.ClockOptions().NewTickerFn(), idx.logger, scope)
+		idx.cardinalityReporter.Start()
+	}
+
+	if indexOpts.WarmupEnabled() {
+		idx.warmer = newIndexWarmer(indexOpts.WarmupQueries(),
+			idx.blocksDescOrder, idx.Query, idx.warmupQueryOptions, nowFn,
+			idx.logger, scope)
+	}
+
 	return idx, nil
 }
 
@@ -259,6 +299,27 @@ func (i *nsIndex) SetRuntimeOptions(value runtime.Options) {
 	i.state.Unlock()
 }
 
+func (i *nsIndex) SetInsertMode(value index.InsertMode) {
+	i.state.Lock()
+	i.state.runtimeOpts.insertMode = value
+	i.state.Unlock()
+}
+
+// indexInsertMode returns the insert mode the namespace mode resolves to,
+// falling back to the node's insert mode for the default namespace mode.
+func indexInsertMode(
+	mode namespace.IndexInsertMode,
+	defaultMode index.InsertMode,
+) index.InsertMode {
+	switch mode {
+	case namespace.IndexInsertModeSync:
+		return index.InsertSync
+	case namespace.IndexInsertModeAsync:
+		return index.InsertAsync
+	}
+	return defaultMode
+}
+
 func (i *nsIndex) BlockStartForWriteTime(writeTime time.Time) xtime.UnixNano {
 	return xtime.ToUnixNano(writeTime.Truncate(i.blockSize))
 }
@@ -270,6 +331,7 @@ func (i *nsIndex) BlockStartForWriteTime(writeTime time.Time) xtime.UnixNano {
 //       => shard.insertSeriesAsyncBatched()
 //       => shardInsertQueue.Insert()
 //       => shard.writeBatch()
+//       => index.WriteSeriesBatch()
 //       => index.WriteBatch()
 //       => indexQueue.Insert()
 //       => index.writeBatch()
@@ -280,7 +342,8 @@ func (i *nsIndex) BlockStartForWriteTime(writeTime time.Time) xtime.UnixNano {
 //        => shard.insertSeriesForIndexingAsyncBatched()
 //        => shardInsertQueue.Insert()
 //        => shard.writeBatch()
-//        => index.Write()
+//        => index.WriteSeriesBatch()
+//        => index.WriteBatch()
 //        => indexQueue.Insert()
 //      	=> index.writeBatch()
 
@@ -296,9 +359,16 @@ func (i *nsIndex) WriteBatch(
 		return err
 	}
 
+	// Skip the inserts of IDs already indexed for their block start.
+	if i.skipAlreadyIndexedWithRLock(batch) {
+		i.state.RUnlock()
+		return nil
+	}
+
 	// NB(prateek): retrieving insertMode here while we have the RLock.
 	insertMode := i.state.runtimeOpts.insertMode
-	wg, err := i.state.insertQueue.InsertBatch(batch)
+	insertSyncTimeout := i.state.runtimeOpts.insertSyncTimeout
+	future, err := i.state.insertQueue.InsertBatch(batch)
 
 	// release the lock because we don't need it past this point.
 	i.state.RUnlock()
@@ -313,7 +383,12 @@ func (i *nsIndex) WriteBatch(
 
 	// wait/terminate depending on if we are indexing synchronously or not.
 	if insertMode != index.InsertAsync {
-		wg.Wait()
+		if err := future.WaitTimeout(insertSyncTimeout); err != nil {
+			if err == errIndexInsertTimeout {
+				i.metrics.InsertSyncTimeout.Inc(1)
+			}
+			return err
+		}
 
 		// Re-sort the batch by initial enqueue order
 		if numErrs := batch.NumErrs(); numErrs > 0 {
@@ -326,10 +401,63 @@ func (i *nsIndex) WriteBatch(
 	return nil
 }
 
+func (i *nsIndex) WriteSeriesBatch(
+	series []index.WriteBatchSeries,
+) error {
+	return i.WriteBatch(index.NewSeriesWriteBatch(index.WriteBatchOptions{
+		IndexBlockSize: i.blockSize,
+	}, series))
+}
+
+// skipAlreadyIndexedWithRLock marks the entries of IDs recently indexed for
+// their block start, or already contained by the latest block, as successful
+// and returns whether no entries are pending.
+func (i *nsIndex) skipAlreadyIndexedWithRLock(batch *index.WriteBatch) bool {
+	var (
+		now             = i.nowFn()
+		latestBlock     = i.state.latestBlock
+		recentlySkipped int64
+		indexedSkipped  int64
+	)
+	batch.ForEach(func(idx int, entry index.WriteBatchEntry,
+		d doc.Document, _ index.WriteBatchEntryResult) {
+		if entry.OnIndexSeries == nil {
+			return
+		}
+		blockStart := entry.Timestamp.Truncate(i.blockSize)
+		blockStartNanos := xtime.ToUnixNano(blockStart)
+		if i.recentlyIndexed != nil &&
+			i.recentlyIndexed.Contains(now, blockStartNanos, d.ID) {
+			batch.MarkUnmarkedEntrySuccess(idx)
+			recentlySkipped++
+			return
+		}
+		if latestBlock == nil || !latestBlock.StartTime().Equal(blockStart) {
+			return
+		}
+		// NB: errors checking the block are ignored, the insert is queued
+		// and the block write surfaces any error.
+		if ok, err := latestBlock.ContainsID(d.ID); err != nil || !ok {
+			return
+		}
+		batch.MarkUnmarkedEntrySuccess(idx)
+		if i.recentlyIndexed != nil {
+			i.recentlyIndexed.Add(now, blockStartNanos, d.ID)
+		}
+		indexedSkipped++
+	})
+	if recentlySkipped == 0 && indexedSkipped == 0 {
+		return false
+	}
+	i.metrics.InsertRecentlyIndexedSkipped.Inc(recentlySkipped)
+	i.metrics.InsertAlreadyIndexedSkipped.Inc(indexedSkipped)
+	return len(batch.PendingEntries()) == 0
+}
+
 // WriteBatches is called by the indexInsertQueue.
 func (i *nsIndex) writeBatches(
 	batches []*index.WriteBatch,
-) {
+) error {
 	// NB(prateek): we use a read lock to guard against mutation of the
 	// indexBlocks, mutations within the underlying blocks are guarded
 	// by primitives internal to it.
@@ -338,7 +466,7 @@ func (i *nsIndex) writeBatches(
 	if !i.isOpenWithRLock() {
 		// NB(prateek): deliberately skip calling any of the `OnIndexFinalize` methods
 		// on the provided inserts to terminate quicker during shutdown.
-		return
+		return errDbIndexUnableToWriteClosed
 	}
 
 	now := i.nowFn()
@@ -350,6 +478,9 @@ func (i *nsIndex) writeBatches(
 		// doc is valid.
 		batch.ForEach(func(idx int, entry index.WriteBatchEntry,
 			d doc.Document, _ index.WriteBatchEntryResult) {
+			if entry.Repair {
+				return
+			}
 			if !futureLimit.After(entry.Timestamp) {
 				batch.MarkUnmarkedEntryError(m3dberrors.ErrTooFuture, idx)
 				return
@@ -366,6 +497,8 @@ func (i *nsIndex) writeBatches(
 		// with a result.
 		batch.ForEachUnmarkedBatchByBlockStart(writeBatchFn)
 	}
+
+	return nil
 }
 
 func (i *nsIndex) writeBatchForBlockStartWithRLock(
@@ -403,6 +536,16 @@ func (i *nsIndex) writeBatchForBlockStartWithRLock(
 		i.metrics.AsyncInsertErrors.Inc(numErr)
 	}
 
+	if i.recentlyIndexed != nil {
+		indexBlockStart := xtime.ToUnixNano(blockStart)
+		batch.ForEach(func(_ int, entry index.WriteBatchEntry,
+			d doc.Document, result index.WriteBatchEntryResult) {
+			if entry.OnIndexSeries == nil && result.Err == nil {
+				i.recentlyIndexed.Add(now, indexBlockStart, d.ID)
+			}
+		})
+	}
+
 	if err != nil {
 		// NB: dropping duplicate id error messages from logs as they're expected when we see
 		// repeated inserts. as long as a block has an ID, it's not an error so we don't need
@@ -434,6 +577,9 @@ func (i *nsIndex) Bootstrap(
 		i.state.Lock()
 		i.state.bootstrapState = Bootstrapped
 		i.state.Unlock()
+		if i.warmer != nil {
+			i.warmer.Start()
+		}
 	}()
 
 	var multiErr xerrors.MultiError
@@ -447,6 +593,7 @@ func (i *nsIndex) Bootstrap(
 			multiErr = multiErr.Add(err)
 		}
 	}
+	i.invalidateQueryCache()
 
 	return multiErr.FinalError()
 }
@@ -454,7 +601,7 @@ func (i *nsIndex) Bootstrap(
 func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceIndexTickResult, error) {
 	var (
 		result                     = namespaceIndexTickResult{}
-		earliestBlockStartToRetain = retention.FlushTimeStartForRetentionPeriod(i.retentionPeriod, i.blockSize, tickStart)
+		earliestBlockStartToRetain = i.earliestBlockStartToRetain(tickStart)
 		lastSealableBlockStart     = retention.FlushTimeEndForBlockSize(i.blockSize, tickStart.Add(-i.bufferPast))
 	)
 
@@ -495,9 +642,107 @@ func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceInd
 		}
 	}
 
+	if result.NumBlocksSealed > 0 || result.NumBlocksEvicted > 0 {
+		i.invalidateQueryCache()
+	}
+
+	if i.tagDictionary != nil {
+		// NB: the dictionary is shared by the namespace so evicting once per
+		// index tick evicts the entries not interned since the last tick.
+		i.tagDictionary.Evict()
+		i.metrics.TagDictionary.report(i.tagDictionary.Stats())
+	}
+
 	return result, multiErr.FinalError()
 }
 
+// ReloadEvictedBlocks loads the flushed blocks within retention that are not
+// in the index from disk, such as blocks evicted by a tick before the
+// retention period of the namespace was extended.
+func (i *nsIndex) ReloadEvictedBlocks() error {
+	var (
+		fsOpts    = i.opts.CommitLogOptions().FilesystemOptions()
+		earliest  = i.earliestBlockStartToRetain(i.nowFn())
+		reloaded  = make(result.IndexResults)
+		infoFiles = fs.ReadIndexInfoFiles(fsOpts.FilePathPrefix(),
+			i.nsMetadata.ID(), fsOpts.InfoReaderBufferSize())
+		multiErr xerrors.MultiError
+	)
+	for _, infoFile := range infoFiles {
+		if err := infoFile.Err.Error(); err != nil {
+			multiErr = multiErr.Add(fmt.Errorf("unable to read index info file %s: %v",
+				infoFile.Err.Filepath(), err))
+			continue
+		}
+
+		blockStart := xtime.UnixNano(infoFile.Info.BlockStart)
+		if blockStart.ToTime().Before(earliest) {
+			continue
+		}
+		i.state.RLock()
+		_, ok := i.state.blocksByTime[blockStart]
+		i.state.RUnlock()
+		if ok {
+			// Not evicted.
+			continue
+		}
+
+		segments, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+			ReaderOptions: fs.IndexReaderOpenOptions{
+				Identifier:  infoFile.ID,
+				FileSetType: persist.FileSetFlushType,
+			},
+			FilesystemOptions: fsOpts,
+		})
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		blockRange := xtime.Range{
+			Start: blockStart.ToTime(),
+			End:   blockStart.ToTime().Add(i.blockSize),
+		}
+		fulfilled := make(result.ShardTimeRanges, len(infoFile.Info.Shards))
+		for _, shard := range infoFile.Info.Shards {
+			fulfilled[shard] = xtime.NewRanges(blockRange)
+		}
+		reloaded.Add(result.NewIndexBlock(blockRange.Start, segments, fulfilled))
+	}
+	if len(reloaded) == 0 {
+		return multiErr.FinalError()
+	}
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		for _, block := range reloaded {
+			for _, seg := range block.Segments() {
+				seg.Close()
+			}
+		}
+		return errDbIndexUnableToReloadClosed
+	}
+	for blockStart, blockResults := range reloaded {
+		block, err := i.ensureBlockPresentWithRLock(blockStart.ToTime())
+		if err != nil { // should never happen
+			multiErr = multiErr.Add(i.unableToAllocBlockInvariantError(err))
+			continue
+		}
+		multiErr = multiErr.Add(block.AddResults(blockResults))
+	}
+	i.state.RUnlock()
+	i.invalidateQueryCache()
+
+	return multiErr.FinalError()
+}
+
+// earliestBlockStartToRetain returns the start of the earliest block within
+// retention at the provided time, earlier blocks are evicted on tick.
+func (i *nsIndex) earliestBlockStartToRetain(t time.Time) time.Time {
+	return retention.FlushTimeStartForBlockSize(i.retentionOpts, i.blockSize, t)
+}
+
 func (i *nsIndex) Flush(
 	flush persist.IndexFlush,
 	shards []databaseShard,
@@ -523,6 +768,7 @@ func (i *nsIndex) Flush(
 		if err := block.AddResults(results); err != nil {
 			return err
 		}
+		i.invalidateQueryCache()
 		// It's now safe to remove the mutable segments as anything the block
 		// held is covered by the owned shards we just read
 		evictResult, err := block.EvictMutableSegments()
@@ -687,7 +933,8 @@ func (i *nsIndex) flushBlockSegment(
 					continue
 				}
 
-				doc, err := convert.FromMetricIter(result.ID, result.Tags)
+				doc, err := convert.FromMetricIterWithDictionary(result.ID,
+					result.Tags, i.tagDictionary)
 				if err != nil {
 					return err
 				}
@@ -721,6 +968,18 @@ func (i *nsIndex) Query(
 		return index.QueryResults{}, errDbIndexUnableToQueryClosed
 	}
 
+	numericTagNames := i.opts.IndexOptions().NumericTagNames()
+	if err := index.ValidateNumericRangeQueries(query, numericTagNames); err != nil {
+		return index.QueryResults{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	// search the terms indexed by the analyzer where it can narrow the query.
+	rewritten, err := analysis.RewriteQuery(i.opts.IndexOptions().Analyzer(), query.Query)
+	if err != nil {
+		return index.QueryResults{}, xerrors.NewInvalidParamsError(err)
+	}
+	query.Query = rewritten
+
 	// override query response limit if needed.
 	if i.state.runtimeOpts.maxQueryLimit > 0 && (opts.Limit == 0 ||
 		int64(opts.Limit) > i.state.runtimeOpts.maxQueryLimit) {
@@ -731,25 +990,80 @@ func (i *nsIndex) Query(
 
 	var (
 		exhaustive = true
-		results    = i.opts.IndexOptions().ResultsPool().Get()
-		err        error
+		results    = newQueryResults(i.nsMetadata.ID(), opts, i.opts.IndexOptions())
 	)
-	results.Reset(i.nsMetadata.ID())
 	ctx.RegisterFinalizer(results)
 
+	// streamed, limited and count only queries are never cached since their
+	// results are not retained or may be partial.
+	var (
+		cacheable = i.queryCache != nil && opts.StreamFn == nil &&
+			!opts.CountOnly && opts.MaxResults == 0 && opts.MaxSeriesMatched == 0
+		cacheKey        indexQueryCacheKey
+		cacheGeneration uint64
+	)
+	if cacheable {
+		cacheKey = newIndexQueryCacheKey(query, opts, i.blockSize)
+		cacheGeneration = i.queryCache.Generation()
+		if entry, ok := i.queryCache.Get(cacheKey); ok {
+			for _, d := range entry.docs {
+				if _, _, err := results.Add(d); err != nil {
+					return index.QueryResults{}, err
+				}
+			}
+			return index.QueryResults{
+				Exhaustive: entry.exhaustive,
+				Results:    results,
+			}, nil
+		}
+	}
+
+	// when streaming, series are handed to the stream function in batches as
+	// the blocks are searched and the returned results are left empty.
+	var (
+		streaming    index.StreamingResults
+		queryResults = results
+	)
+	if opts.StreamFn != nil {
+		streaming = index.NewStreamingResults(results, i.nsMetadata.ID(),
+			opts.StreamBatchSize, opts.StreamFn)
+		queryResults = streaming
+	}
+
+	// limits are applied to the series added by all blocks so that a query
+	// matching too many series returns partial results rather than erroring.
+	var limited index.LimitedResults
+	if opts.MaxResults > 0 || opts.MaxSeriesMatched > 0 {
+		limited = index.NewLimitedResults(queryResults, opts.MaxResults,
+			opts.MaxSeriesMatched)
+		queryResults = limited
+	}
+
 	// Chunk the query request into bounds based on applicable blocks and
 	// execute the requests to each of them; and merge results.
 	queryRange := xtime.NewRanges(xtime.Range{
 		Start: opts.StartInclusive, End: opts.EndExclusive})
+	earliestBlockStartToRetain := i.earliestBlockStartToRetain(i.nowFn())
 
 	// iterate known blocks in a defined order of time (newest first) to enforce
 	// some determinism about the results returned.
+	var unlockedToStream bool
 	for _, start := range i.state.blockStartsDescOrder {
 		block, ok := i.state.blocksByTime[start]
+		if !ok && unlockedToStream {
+			// the block was evicted while a streamed batch was delivered.
+			continue
+		}
 		if !ok { // should never happen
 			return index.QueryResults{}, i.missingBlockInvariantError(start)
 		}
 
+		// skip blocks that have fallen out of retention but are yet to be
+		// evicted by the next tick.
+		if start.ToTime().Before(earliestBlockStartToRetain) {
+			continue
+		}
+
 		// ensure the block has data requested by the query
 		blockRange := xtime.Range{Start: block.StartTime(), End: block.EndTime()}
 		if !queryRange.Overlaps(blockRange) {
@@ -757,16 +1071,35 @@ func (i *nsIndex) Query(
 		}
 
 		// terminate early if we know we don't need any more results
-		if opts.Limit > 0 && results.Size() >= opts.Limit {
+		if opts.Limit > 0 && queryResults.Size() >= opts.Limit {
 			exhaustive = false
 			break
 		}
 
-		exhaustive, err = block.Query(query, opts, results)
+		exhaustive, err = block.Query(query, opts, queryResults)
+		if err != nil && limited != nil && limited.LimitExceeded() != nil {
+			exhaustive = false
+			break
+		}
 		if err != nil {
 			return index.QueryResults{}, err
 		}
 
+		// NB: deliver streamed batches between blocks without holding the
+		// index lock since the stream function may read series from disk.
+		if streaming != nil && streaming.Full() {
+			unlockedToStream = true
+			i.state.RUnlock()
+			err := streaming.Flush()
+			i.state.RLock()
+			if err != nil {
+				return index.QueryResults{}, err
+			}
+			if !i.isOpenWithRLock() {
+				return index.QueryResults{}, errDbIndexUnableToQueryClosed
+			}
+		}
+
 		if !exhaustive {
 			// i.e. block had more data but we stopped early, we know
 			// we have hit the limit and don't need to query any more.
@@ -780,15 +1113,54 @@ func (i *nsIndex) Query(
 		}
 	}
 
+	if streaming != nil {
+		i.state.RUnlock()
+		err := streaming.Flush()
+		i.state.RLock()
+		if err != nil {
+			return index.QueryResults{}, err
+		}
+	}
+
 	// FOLLOWUP(prateek): do the above operation with controllable parallelism to optimize
 	// for latency at the cost of higher mem-usage.
 
+	var warnings []index.QueryLimitExceeded
+	if limited != nil {
+		if exceeded := limited.LimitExceeded(); exceeded != nil {
+			i.metrics.QueryLimitExceeded.Inc(1)
+			warnings = append(warnings, *exceeded)
+		}
+	}
+
+	if cacheable {
+		if err := i.queryCache.Put(cacheKey, cacheGeneration, results, exhaustive); err != nil {
+			i.logger.Warnf("unable to cache query results: %v", err)
+		}
+	}
+
 	return index.QueryResults{
 		Exhaustive: exhaustive,
 		Results:    results,
+		Warnings:   warnings,
 	}, nil
 }
 
+// newQueryResults returns the results the series matched by a query are
+// added to, count only queries only count the series matched.
+func newQueryResults(
+	nsID ident.ID,
+	opts index.QueryOptions,
+	indexOpts index.Options,
+) index.Results {
+	if opts.CountOnly {
+		return index.NewCountResults(nsID, opts.ShardFn, indexOpts)
+	}
+	results := indexOpts.ResultsPool().Get()
+	results.Reset(nsID)
+	return results
+}
+
 // ensureBlockPresentWithRLock guarantees an index.Block exists for the specified
 // blockStart, allocating one if it does not. It returns the desired block, or
 // error if it's unable to do so.
@@ -880,7 +1252,7 @@ func (i *nsIndex) CleanupExpiredFileSets(t time.Time) error {
 	}
 
 	// earliest block to retain based on retention period
-	earliestBlockStartToRetain := retention.FlushTimeStartForRetentionPeriod(i.retentionPeriod, i.blockSize, t)
+	earliestBlockStartToRetain := i.earliestBlockStartToRetain(t)
 
 	// now we loop through the blocks we hold, to ensure we don't delete any data for them.
 	for t := range i.state.blocksByTime {
@@ -903,7 +1275,55 @@ func (i *nsIndex) CleanupExpiredFileSets(t time.Time) error {
 	return i.deleteFilesFn(filesets)
 }
 
+// blocksDescOrder returns the blocks of the index in reverse chronological order.
+func (i *nsIndex) blocksDescOrder() []index.Block {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if !i.isOpenWithRLock() {
+		return nil
+	}
+
+	blocks := make([]index.Block, 0, len(i.state.blockStartsDescOrder))
+	for _, start := range i.state.blockStartsDescOrder {
+		if block, ok := i.state.blocksByTime[start]; ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// warmupQueryOptions returns the options for warm up queries, which span
+// the retention period of the namespace.
+func (i *nsIndex) warmupQueryOptions() index.QueryOptions {
+	now := i.nowFn()
+	return index.QueryOptions{
+		StartInclusive: now.Add(-i.retentionOpts.RetentionPeriod()),
+		EndExclusive:   now.Add(i.bufferFuture),
+	}
+}
+
+func (i *nsIndex) CardinalityReport() (index.CardinalityReport, error) {
+	if i.cardinalityReporter == nil {
+		return index.CardinalityReport{}, errDbIndexCardinalityReportDisabled
+	}
+
+	blocks, computedAt := i.cardinalityReporter.Report()
+	return index.CardinalityReport{
+		ComputedAt: computedAt,
+		Blocks:     blocks,
+	}, nil
+}
+
 func (i *nsIndex) Close() error {
+	// NB: the reporter and warmer must be stopped before taking the lock
+	// as they acquire the lock to list the blocks.
+	if i.cardinalityReporter != nil {
+		i.cardinalityReporter.Close()
+	}
+	if i.warmer != nil {
+		i.warmer.Close()
+	}
+
 	i.state.Lock()
 	defer i.state.Unlock()
 	if !i.isOpenWithRLock() {
@@ -931,6 +1351,14 @@ func (i *nsIndex) Close() error {
 	return multiErr.FinalError()
 }
 
+// invalidateQueryCache removes the cached query results once blocks are
+// sealed, evicted or given new segments.
+func (i *nsIndex) invalidateQueryCache() {
+	if i.queryCache != nil {
+		i.queryCache.Invalidate()
+	}
+}
+
 func (i *nsIndex) missingBlockInvariantError(t xtime.UnixNano) error {
 	err := fmt.Errorf("index query did not find block %d despite seeing it in slice", t)
 	instrument.EmitInvariantViolationAndGetLogger(i.opts.InstrumentOptions()).Errorf(err.Error())
@@ -944,11 +1372,47 @@ func (i *nsIndex) unableToAllocBlockInvariantError(err error) error {
 }
 
 type nsIndexMetrics struct {
-	AsyncInsertErrors           tally.Counter
-	InsertAfterClose            tally.Counter
-	QueryAfterClose             tally.Counter
-	InsertEndToEndLatency       tally.Timer
-	FlushEvictedMutableSegments tally.Counter
+	AsyncInsertErrors            tally.Counter
+	InsertAfterClose             tally.Counter
+	InsertSyncTimeout            tally.Counter
+	QueryAfterClose              tally.Counter
+	InsertEndToEndLatency        tally.Timer
+	FlushEvictedMutableSegments  tally.Counter
+	InsertRecentlyIndexedSkipped tally.Counter
+	InsertAlreadyIndexedSkipped  tally.Counter
+	QueryLimitExceeded           tally.Counter
+	TagDictionary                tagDictionaryMetrics
+}
+
+type tagDictionaryMetrics struct {
+	entries   tally.Gauge
+	bytes     tally.Gauge
+	hits      tally.Counter
+	misses    tally.Counter
+	evictions tally.Counter
+
+	// last is the last reported stats, the cumulative dictionary stats are
+	// reported as counters by their change since.
+	last convert.DictionaryStats
+}
+
+func newTagDictionaryMetrics(scope tally.Scope) tagDictionaryMetrics {
+	return tagDictionaryMetrics{
+		entries:   scope.Gauge("entries"),
+		bytes:     scope.Gauge("bytes"),
+		hits:      scope.Counter("hits"),
+		misses:    scope.Counter("misses"),
+		evictions: scope.Counter("evictions"),
+	}
+}
+
+func (m *tagDictionaryMetrics) report(stats convert.DictionaryStats) {
+	m.entries.Update(float64(stats.Entries))
+	m.bytes.Update(float64(stats.Bytes))
+	m.hits.Inc(stats.Hits - m.last.Hits)
+	m.misses.Inc(stats.Misses - m.last.Misses)
+	m.evictions.Inc(stats.Evictions - m.last.Evictions)
+	m.last = stats
 }
 
 func newNamespaceIndexMetrics(
@@ -962,13 +1426,20 @@ func newNamespaceIndexMetrics(
 		InsertAfterClose: scope.Tagged(map[string]string{
 			"error_type": "insert-closed",
 		}).Counter("insert-after-close"),
+		InsertSyncTimeout: scope.Tagged(map[string]string{
+			"error_type": "insert-sync-timeout",
+		}).Counter("index-error"),
 		QueryAfterClose: scope.Tagged(map[string]string{
 			"error_type": "query-closed",
 		}).Counter("query-after-error"),
 		InsertEndToEndLatency: instrument.MustCreateSampledTimer(
 			scope.Timer("insert-end-to-end-latency"),
 			iopts.MetricsSamplingRate()),
-		FlushEvictedMutableSegments: scope.Counter("mutable-segment-evicted"),
+		FlushEvictedMutableSegments:  scope.Counter("mutable-segment-evicted"),
+		InsertRecentlyIndexedSkipped: scope.Counter("insert-recently-indexed-skipped"),
+		InsertAlreadyIndexedSkipped:  scope.Counter("insert-already-indexed-skipped"),
+		QueryLimitExceeded:           scope.Counter("query-limit-exceeded"),
+		TagDictionary:                newTagDictionaryMetrics(scope.SubScope("tag-dictionary")),
 	}
 }
 
@@ -60,7 +60,7 @@ const (
 	blockStateSealed
 )
 
-type newExecutorFn func() (search.Executor, error)
+type newExecutorFn func(policy SegmentMergePolicy) (search.Executor, error)
 
 type block struct {
 	sync.RWMutex
@@ -68,6 +68,10 @@ type block struct {
 	activeSegment       segment.MutableSegment
 	shardRangesSegments []blockShardRangesSegments
 
+	// segmentsInUse tracks scans of the segments made without the lock held,
+	// segments are only closed once there are none.
+	segmentsInUse sync.WaitGroup
+
 	newExecutorFn newExecutorFn
 	startTime     time.Time
 	endTime       time.Time
@@ -180,17 +184,23 @@ func (b *block) WriteBatch(inserts *WriteBatch) (WriteBatchResult, error) {
 	}, partialErr
 }
 
-func (b *block) executorWithRLock() (search.Executor, error) {
-	var expectedReaders int
+func (b *block) executorWithRLock(policy SegmentMergePolicy) (search.Executor, error) {
+	// start with the segment that's being actively written to (if we have one)
+	// followed by the segments associated to shard time ranges.
+	var segments []segment.Segment
 	if b.activeSegment != nil {
-		expectedReaders++
+		segments = append(segments, b.activeSegment)
 	}
 	for _, group := range b.shardRangesSegments {
-		expectedReaders += len(group.segments)
+		segments = append(segments, group.segments...)
+	}
+	if policy != MergeAllSegmentsPolicy {
+		segments = policy.selectSegments(segments, b.IsSealedWithRLock(),
+			b.flushedSegmentsCoverBlockWithRLock())
 	}
 
 	var (
-		readers = make([]m3ninxindex.Reader, 0, expectedReaders)
+		readers = make([]m3ninxindex.Reader, 0, len(segments))
 		success = false
 	)
 
@@ -203,28 +213,55 @@ func (b *block) executorWithRLock() (search.Executor, error) {
 		}
 	}()
 
-	// start with the segment that's being actively written to (if we have one)
-	if b.activeSegment != nil {
-		reader, err := b.activeSegment.Reader()
+	cache := b.opts.PostingsListCache()
+	for _, seg := range segments {
+		reader, err := seg.Reader()
 		if err != nil {
 			return nil, err
 		}
+		if _, ok := seg.(segment.MutableSegment); !ok && cache != nil {
+			reader = newCachedPostingsListReader(reader, seg, cache)
+		}
 		readers = append(readers, reader)
 	}
 
-	// loop over the segments associated to shard time ranges
+	success = true
+	return executor.NewExecutor(readers), nil
+}
+
+// flushedSegmentsCoverBlockWithRLock returns whether the groups of segments
+// holding only flushed segments cover every shard with segments in the block
+// for the entire range of the block, i.e. whether every series in the mutable
+// segments of the block is also in a flushed segment. The shards of the active
+// segment are not tracked, an index flush covers every shard of the node so
+// the shards of the block's groups are taken to be those of the node.
+func (b *block) flushedSegmentsCoverBlockWithRLock() bool {
+	var (
+		flushed = make(result.ShardTimeRanges)
+		shards  []uint32
+	)
 	for _, group := range b.shardRangesSegments {
+		for shard := range group.shardTimeRanges {
+			shards = append(shards, shard)
+		}
+		onlyFlushed := len(group.segments) > 0
 		for _, seg := range group.segments {
-			reader, err := seg.Reader()
-			if err != nil {
-				return nil, err
+			if _, ok := seg.(segment.MutableSegment); ok {
+				onlyFlushed = false
+				break
 			}
-			readers = append(readers, reader)
+		}
+		if onlyFlushed {
+			flushed.AddRanges(group.shardTimeRanges)
 		}
 	}
+	if flushed.IsEmpty() {
+		return false
+	}
 
-	success = true
-	return executor.NewExecutor(readers), nil
+	uncovered := result.NewShardTimeRanges(b.startTime, b.endTime, shards...)
+	uncovered.Subtract(flushed)
+	return uncovered.IsEmpty()
 }
 
 func (b *block) Query(
@@ -238,7 +275,11 @@ func (b *block) Query(
 		return false, errUnableToQueryBlockClosed
 	}
 
-	exec, err := b.newExecutorFn()
+	if err := ValidateSegmentMergePolicy(opts.SegmentMergePolicy); err != nil {
+		return false, err
+	}
+
+	exec, err := b.newExecutorFn(opts.SegmentMergePolicy)
 	if err != nil {
 		return false, err
 	}
@@ -292,6 +333,102 @@ func (b *block) Query(
 	return exhaustive, nil
 }
 
+func (b *block) ContainsID(id []byte) (bool, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return false, errUnableToQueryBlockClosed
+	}
+
+	if b.activeSegment != nil {
+		ok, err := b.activeSegment.ContainsID(id)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	for _, group := range b.shardRangesSegments {
+		for _, seg := range group.segments {
+			ok, err := seg.ContainsID(id)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+	return false, nil
+}
+
+func (b *block) Cardinality(maxValues int) (BlockCardinality, error) {
+	// NB: the segments are scanned without the lock held so that writes are
+	// not blocked for the duration of the scan, closing the segments waits
+	// for the scan to complete instead.
+	b.RLock()
+	if b.state == blockStateClosed {
+		b.RUnlock()
+		return BlockCardinality{}, errUnableToQueryBlockClosed
+	}
+
+	var segments []segment.Segment
+	if b.activeSegment != nil {
+		segments = append(segments, b.activeSegment)
+	}
+	for _, group := range b.shardRangesSegments {
+		segments = append(segments, group.segments...)
+	}
+	b.segmentsInUse.Add(1)
+	b.RUnlock()
+	defer b.segmentsInUse.Done()
+
+	var numDocs int64
+	for _, seg := range segments {
+		numDocs += seg.Size()
+	}
+
+	tags, err := segmentsCardinality(segments, maxValues)
+	if err != nil {
+		return BlockCardinality{}, err
+	}
+	return BlockCardinality{
+		BlockStart: b.startTime,
+		NumDocs:    numDocs,
+		Tags:       tags,
+	}, nil
+}
+
+func (b *block) Warm() (WarmResult, error) {
+	// NB: like Cardinality, the segments are read without the lock held so
+	// that writes are not blocked, closing the segments waits instead.
+	b.RLock()
+	if b.state == blockStateClosed {
+		b.RUnlock()
+		return WarmResult{}, errUnableToQueryBlockClosed
+	}
+
+	var segments []segment.Segment
+	for _, group := range b.shardRangesSegments {
+		for _, seg := range group.segments {
+			if _, ok := seg.(segment.MutableSegment); ok {
+				// NB: mutable segments are held in memory so need no warming.
+				continue
+			}
+			segments = append(segments, seg)
+		}
+	}
+	b.segmentsInUse.Add(1)
+	b.RUnlock()
+	defer b.segmentsInUse.Done()
+
+	var result WarmResult
+	for _, seg := range segments {
+		numTerms, err := warmSegment(seg)
+		result.NumTerms += numTerms
+		if err != nil {
+			return result, err
+		}
+		result.NumSegments++
+	}
+	return result, nil
+}
+
 func (b *block) AddResults(
 	results result.IndexBlock,
 ) error {
@@ -357,10 +494,11 @@ func (b *block) AddResults(
 
 	// This is the case where the new segments can wholly replace the
 	// current set of blocks since unfullfilled by the new segments is zero
+	b.segmentsInUse.Wait()
 	for i, group := range b.shardRangesSegments {
 		for _, seg := range group.segments {
 			// Make sure to close the existing segments
-			multiErr = multiErr.Add(seg.Close())
+			multiErr = multiErr.Add(b.closeSegmentWithLock(seg))
 		}
 		b.shardRangesSegments[i] = blockShardRangesSegments{}
 	}
@@ -464,6 +602,7 @@ func (b *block) EvictMutableSegments() (EvictMutableSegmentResults, error) {
 		return results, fmt.Errorf("unable to evict mutable segments, block must be sealed, found: %v", b.state)
 	}
 	var multiErr xerrors.MultiError
+	b.segmentsInUse.Wait()
 
 	// close active segment.
 	if b.activeSegment != nil {
@@ -501,6 +640,7 @@ func (b *block) Close() error {
 	b.state = blockStateClosed
 
 	var multiErr xerrors.MultiError
+	b.segmentsInUse.Wait()
 
 	// close active segment.
 	if b.activeSegment != nil {
@@ -511,7 +651,7 @@ func (b *block) Close() error {
 	// close any other added segments too.
 	for _, group := range b.shardRangesSegments {
 		for _, seg := range group.segments {
-			multiErr = multiErr.Add(seg.Close())
+			multiErr = multiErr.Add(b.closeSegmentWithLock(seg))
 		}
 	}
 	b.shardRangesSegments = nil
@@ -519,6 +659,15 @@ func (b *block) Close() error {
 	return multiErr.FinalError()
 }
 
+// closeSegmentWithLock closes a segment added to the block, removing the
+// postings lists cached for it.
+func (b *block) closeSegmentWithLock(seg segment.Segment) error {
+	if cache := b.opts.PostingsListCache(); cache != nil {
+		cache.PurgeSegment(seg)
+	}
+	return seg.Close()
+}
+
 func (b *block) writeBatchErrorInvalidState(state blockState) error {
 	switch state {
 	case blockStateClosed:
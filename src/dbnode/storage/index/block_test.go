@@ -442,7 +442,7 @@ func TestBlockQueryExecutorError(t *testing.T) {
 	b, ok := blk.(*block)
 	require.True(t, ok)
 
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		b.RLock() // ensures we call newExecutorFn with RLock, or this would deadlock
 		defer b.RUnlock()
 		return nil, fmt.Errorf("random-err")
@@ -522,7 +522,7 @@ func TestBlockMockQueryExecutorExecError(t *testing.T) {
 
 	// dIter:= doc.NewMockIterator(ctrl)
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 	gomock.InOrder(
@@ -546,7 +546,7 @@ func TestBlockMockQueryExecutorExecIterErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -577,7 +577,7 @@ func TestBlockMockQueryExecutorExecLimit(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -617,7 +617,7 @@ func TestBlockMockQueryExecutorExecIterCloseErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -647,7 +647,7 @@ func TestBlockMockQueryExecutorExecIterExecCloseErr(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -677,7 +677,7 @@ func TestBlockMockQueryLimit(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -705,6 +705,44 @@ func TestBlockMockQueryLimit(t *testing.T) {
 		ident.NewTagsIterator(t1)))
 }
 
+func TestBlockMockQueryCountOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
+		return exec, nil
+	}
+
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	shardFn := func(id ident.ID) uint32 { return 3 }
+	results := NewCountResults(ident.StringID("ns"), shardFn, testOpts)
+	exhaustive, err := b.Query(Query{}, QueryOptions{CountOnly: true}, results)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+
+	require.Equal(t, 1, results.Size())
+	require.Equal(t, 0, results.Map().Len())
+	require.Equal(t, map[uint32]int64{3: 1}, results.ShardCounts())
+}
+
 func TestBlockMockQueryLimitExhaustive(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -718,7 +756,7 @@ func TestBlockMockQueryLimitExhaustive(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -760,7 +798,7 @@ func TestBlockMockQueryMergeResultsMapLimit(t *testing.T) {
 	require.NoError(t, b.Seal())
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -802,7 +840,7 @@ func TestBlockMockQueryMergeResultsDupeID(t *testing.T) {
 	require.True(t, ok)
 
 	exec := search.NewMockExecutor(ctrl)
-	b.newExecutorFn = func() (search.Executor, error) {
+	b.newExecutorFn = func(SegmentMergePolicy) (search.Executor, error) {
 		return exec, nil
 	}
 
@@ -861,6 +899,45 @@ func TestBlockAddResultsAddsSegment(t *testing.T) {
 	require.Equal(t, seg1, b.shardRangesSegments[0].segments[0])
 }
 
+func TestBlockFlushedSegmentsCoverBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	var (
+		mutable = segment.NewMockMutableSegment(ctrl)
+		flushed = segment.NewMockSegment(ctrl)
+		end     = b.endTime
+	)
+	b.shardRangesSegments = []blockShardRangesSegments{
+		{
+			shardTimeRanges: result.NewShardTimeRanges(start, end, 1, 2, 3),
+			segments:        []segment.Segment{mutable},
+		},
+	}
+	require.False(t, b.flushedSegmentsCoverBlockWithRLock())
+
+	// Flushed segments that do not cover every shard do not cover the block.
+	b.shardRangesSegments = append(b.shardRangesSegments, blockShardRangesSegments{
+		shardTimeRanges: result.NewShardTimeRanges(start, end, 1, 2),
+		segments:        []segment.Segment{flushed},
+	})
+	require.False(t, b.flushedSegmentsCoverBlockWithRLock())
+
+	b.shardRangesSegments = append(b.shardRangesSegments, blockShardRangesSegments{
+		shardTimeRanges: result.NewShardTimeRanges(start, end, 3),
+		segments:        []segment.Segment{flushed},
+	})
+	require.True(t, b.flushedSegmentsCoverBlockWithRLock())
+}
+
 func TestBlockAddResultsAfterCloseFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
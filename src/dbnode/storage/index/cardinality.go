@@ -0,0 +1,290 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package index
+
+import (
+	"bytes"
+	"container/heap"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/analysis"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+)
+
+// TagValueCardinality is the number of series with a tag value.
+type TagValueCardinality struct {
+	Value     string `json:"value"`
+	NumSeries int64  `json:"numSeries"`
+}
+
+// TagCardinality is the cardinality contribution of a tag name.
+type TagCardinality struct {
+	Name string `json:"name"`
+	// NumValues is the number of distinct values of the tag name.
+	NumValues int64 `json:"numValues"`
+	// NumSeries is the number of series with the tag name.
+	NumSeries int64 `json:"numSeries"`
+	// TopValues is the values with the most series, sorted descending.
+	TopValues []TagValueCardinality `json:"topValues"`
+}
+
+// BlockCardinality is the cardinality of each tag name of an index block.
+type BlockCardinality struct {
+	BlockStart time.Time `json:"blockStart"`
+	NumDocs    int64     `json:"numDocs"`
+	// Tags is sorted by number of values descending.
+	Tags []TagCardinality `json:"tags"`
+}
+
+// CardinalityReport is the cardinality of each index block of a namespace.
+type CardinalityReport struct {
+	ComputedAt time.Time `json:"computedAt"`
+	// Blocks is sorted by block start descending.
+	Blocks []BlockCardinality `json:"blocks"`
+}
+
+// Top returns the report limited to the given number of tag names and
+// values per tag name of each block, non-positive values do not limit.
+func (r CardinalityReport) Top(names, values int) CardinalityReport {
+	result := CardinalityReport{
+		ComputedAt: r.ComputedAt,
+		Blocks:     make([]BlockCardinality, 0, len(r.Blocks)),
+	}
+	for _, block := range r.Blocks {
+		result.Blocks = append(result.Blocks, block.Top(names, values))
+	}
+	return result
+}
+
+// Top returns the block cardinality limited to the given number of tag
+// names and values per tag name, non-positive values do not limit.
+func (c BlockCardinality) Top(names, values int) BlockCardinality {
+	tags := c.Tags
+	if names > 0 && len(tags) > names {
+		tags = tags[:names]
+	}
+	result := c
+	result.Tags = make([]TagCardinality, 0, len(tags))
+	for _, tag := range tags {
+		if values > 0 && len(tag.TopValues) > values {
+			tag.TopValues = tag.TopValues[:values]
+		}
+		result.Tags = append(result.Tags, tag)
+	}
+	return result
+}
+
+type tagCardinalityAccumulator struct {
+	numSeries int64
+	values    map[string]int64
+}
+
+// segmentsCardinality returns the cardinality of each tag name across the
+// segments, keeping the top maxValues values of each tag name. The values of
+// each tag name are merged across segments so that a value present in more
+// than one segment is counted once and ranked by its total number of series.
+func segmentsCardinality(
+	segments []segment.Segment,
+	maxValues int,
+) ([]TagCardinality, error) {
+	accums := make(map[string]*tagCardinalityAccumulator)
+	for _, seg := range segments {
+		var err error
+		if mutable, ok := seg.(segment.MutableSegment); ok && !mutable.IsSealed() {
+			err = addUnsealedSegmentCardinality(seg, accums)
+		} else {
+			err = addSegmentCardinality(seg, accums)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags := make([]TagCardinality, 0, len(accums))
+	for name, accum := range accums {
+		tags = append(tags, TagCardinality{
+			Name:      name,
+			NumValues: int64(len(accum.values)),
+			NumSeries: accum.numSeries,
+			TopValues: topValues(accum.values, maxValues),
+		})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].NumValues == tags[j].NumValues {
+			return tags[i].Name < tags[j].Name
+		}
+		return tags[i].NumValues > tags[j].NumValues
+	})
+	return tags, nil
+}
+
+// topValues returns the maxValues values with the most series, sorted
+// descending.
+func topValues(values map[string]int64, maxValues int) []TagValueCardinality {
+	top := make(topValuesHeap, 0, maxValues)
+	if maxValues <= 0 {
+		return top
+	}
+	for value, numSeries := range values {
+		v := TagValueCardinality{Value: value, NumSeries: numSeries}
+		if len(top) < maxValues {
+			heap.Push(&top, v)
+		} else if numSeries > top[0].NumSeries ||
+			(numSeries == top[0].NumSeries && value < top[0].Value) {
+			top[0] = v
+			heap.Fix(&top, 0)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].NumSeries == top[j].NumSeries {
+			return top[i].Value < top[j].Value
+		}
+		return top[i].NumSeries > top[j].NumSeries
+	})
+	return top
+}
+
+func accumulatorFor(
+	accums map[string]*tagCardinalityAccumulator,
+	field []byte,
+) (*tagCardinalityAccumulator, bool) {
+	if bytes.Equal(field, doc.IDReservedFieldName) ||
+		analysis.IsAnalyzedFieldName(field) {
+		return nil, false
+	}
+	accum, ok := accums[string(field)]
+	if !ok {
+		accum = &tagCardinalityAccumulator{values: make(map[string]int64)}
+		accums[string(field)] = accum
+	}
+	return accum, true
+}
+
+func addSegmentCardinality(
+	seg segment.Segment,
+	accums map[string]*tagCardinalityAccumulator,
+) error {
+	reader, err := seg.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	fields, err := seg.Fields()
+	if err != nil {
+		return err
+	}
+	defer fields.Close()
+
+	for fields.Next() {
+		field := fields.Current()
+		accum, ok := accumulatorFor(accums, field)
+		if !ok {
+			continue
+		}
+
+		terms, err := seg.Terms(field)
+		if err != nil {
+			return err
+		}
+		for terms.Next() {
+			term := terms.Current()
+			pl, err := reader.MatchTerm(field, term)
+			if err != nil {
+				terms.Close()
+				return err
+			}
+			numSeries := int64(pl.Len())
+			accum.numSeries += numSeries
+			accum.values[string(term)] += numSeries
+		}
+		if err := terms.Err(); err != nil {
+			terms.Close()
+			return err
+		}
+		if err := terms.Close(); err != nil {
+			return err
+		}
+	}
+
+	return fields.Err()
+}
+
+// addUnsealedSegmentCardinality adds the cardinality of an unsealed mutable
+// segment, the terms of which cannot be iterated, from its documents.
+func addUnsealedSegmentCardinality(
+	seg segment.Segment,
+	accums map[string]*tagCardinalityAccumulator,
+) error {
+	reader, err := seg.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	docs, err := reader.AllDocs()
+	if err != nil {
+		return err
+	}
+	for docs.Next() {
+		for _, field := range docs.Current().Fields {
+			accum, ok := accumulatorFor(accums, field.Name)
+			if !ok {
+				continue
+			}
+			accum.numSeries++
+			accum.values[string(field.Value)]++
+		}
+	}
+	if err := docs.Err(); err != nil {
+		docs.Close()
+		return err
+	}
+	return docs.Close()
+}
+
+// topValuesHeap is a min heap of values by number of series, values with
+// the same number of series are ordered descending so that the top values
+// are deterministic.
+type topValuesHeap []TagValueCardinality
+
+func (h topValuesHeap) Len() int { return len(h) }
+func (h topValuesHeap) Less(i, j int) bool {
+	if h[i].NumSeries == h[j].NumSeries {
+		return h[i].Value > h[j].Value
+	}
+	return h[i].NumSeries < h[j].NumSeries
+}
+
+func (h topValuesHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *topValuesHeap) Push(x interface{}) {
+	*h = append(*h, x.(TagValueCardinality))
+}
+
+func (h *topValuesHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package index
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCardinalitySegment(t *testing.T, docs []doc.Document) segment.Segment {
+	seg, err := mem.NewSegment(0, testOpts.MemSegmentOptions())
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := seg.Insert(d)
+		require.NoError(t, err)
+	}
+	sealed, err := seg.Seal()
+	require.NoError(t, err)
+	return sealed
+}
+
+func newTestCardinalityDoc(id string, fields ...string) doc.Document {
+	d := doc.Document{ID: []byte(id)}
+	for i := 0; i < len(fields); i += 2 {
+		d.Fields = append(d.Fields, doc.Field{
+			Name:  []byte(fields[i]),
+			Value: []byte(fields[i+1]),
+		})
+	}
+	return d
+}
+
+func TestSegmentsCardinality(t *testing.T) {
+	var docs []doc.Document
+	for i := 0; i < 5; i++ {
+		docs = append(docs, newTestCardinalityDoc(fmt.Sprintf("a%d", i),
+			"app", "a", "host", fmt.Sprintf("host%d", i)))
+	}
+	docs = append(docs, newTestCardinalityDoc("b0", "app", "b", "host", "host0"))
+	seg0 := newTestCardinalitySegment(t, docs)
+	seg1 := newTestCardinalitySegment(t, []doc.Document{
+		newTestCardinalityDoc("c0", "app", "b", "dc", "east"),
+	})
+
+	tags, err := segmentsCardinality([]segment.Segment{seg0, seg1}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []TagCardinality{
+		{
+			Name:      "host",
+			NumValues: 5,
+			NumSeries: 6,
+			TopValues: []TagValueCardinality{{Value: "host0", NumSeries: 2}},
+		},
+		{
+			Name:      "app",
+			NumValues: 2,
+			NumSeries: 7,
+			TopValues: []TagValueCardinality{{Value: "a", NumSeries: 5}},
+		},
+		{
+			Name:      "dc",
+			NumValues: 1,
+			NumSeries: 1,
+			TopValues: []TagValueCardinality{{Value: "east", NumSeries: 1}},
+		},
+	}, tags)
+
+	report := CardinalityReport{Blocks: []BlockCardinality{{Tags: tags}}}
+	top := report.Top(1, 0)
+	require.Len(t, top.Blocks, 1)
+	require.Len(t, top.Blocks[0].Tags, 1)
+	require.Equal(t, "host", top.Blocks[0].Tags[0].Name)
+}
+
+func TestSegmentsCardinalityMergesValuesAcrossSegments(t *testing.T) {
+	// the top value of each segment is not the top value of the block.
+	seg0 := newTestCardinalitySegment(t, []doc.Document{
+		newTestCardinalityDoc("a0", "app", "x"),
+		newTestCardinalityDoc("a1", "app", "x"),
+		newTestCardinalityDoc("a2", "app", "x"),
+		newTestCardinalityDoc("a3", "app", "y"),
+		newTestCardinalityDoc("a4", "app", "y"),
+	})
+	seg1 := newTestCardinalitySegment(t, []doc.Document{
+		newTestCardinalityDoc("b0", "app", "z"),
+		newTestCardinalityDoc("b1", "app", "z"),
+		newTestCardinalityDoc("b2", "app", "z"),
+		newTestCardinalityDoc("b3", "app", "y"),
+		newTestCardinalityDoc("b4", "app", "y"),
+	})
+
+	// unsealed segments are included from their documents.
+	unsealed, err := mem.NewSegment(0, testOpts.MemSegmentOptions())
+	require.NoError(t, err)
+	_, err = unsealed.Insert(newTestCardinalityDoc("c0", "app", "z"))
+	require.NoError(t, err)
+
+	tags, err := segmentsCardinality([]segment.Segment{seg0, seg1, unsealed}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []TagCardinality{
+		{
+			Name:      "app",
+			NumValues: 3,
+			NumSeries: 11,
+			TopValues: []TagValueCardinality{
+				{Value: "y", NumSeries: 4},
+				{Value: "z", NumSeries: 4},
+			},
+		},
+	}, tags)
+}
@@ -114,6 +114,17 @@ func FromMetricNoClone(id ident.ID, tags ident.Tags) (doc.Document, error) {
 // FOLLOWUP(r): Rename FromMetric to FromSeries (metric terminiology
 // is not common in the codebase)
 func FromMetricIter(id ident.ID, tags ident.TagIterator) (doc.Document, error) {
+	return FromMetricIterWithDictionary(id, tags, nil)
+}
+
+// FromMetricIterWithDictionary converts the provided metric id+tags into a
+// document, tag names and values not contained in the ID reference the
+// dictionary bytes where possible rather than being copied.
+func FromMetricIterWithDictionary(
+	id ident.ID,
+	tags ident.TagIterator,
+	dict Dictionary,
+) (doc.Document, error) {
 	clonedID := clone(id)
 	fields := make([]doc.Field, 0, tags.Remaining())
 	for tags.Next() {
@@ -122,23 +133,9 @@ func FromMetricIter(id ident.ID, tags ident.TagIterator) (doc.Document, error) {
 			return doc.Document{}, ErrUsingReservedFieldName
 		}
 
-		nameBytes, valueBytes := tag.Name.Bytes(), tag.Value.Bytes()
-
-		var clonedName, clonedValue []byte
-		if idx := bytes.Index(clonedID, nameBytes); idx != -1 {
-			clonedName = clonedID[idx : idx+len(nameBytes)]
-		} else {
-			clonedName = append([]byte(nil), nameBytes...)
-		}
-		if idx := bytes.Index(clonedID, valueBytes); idx != -1 {
-			clonedValue = clonedID[idx : idx+len(valueBytes)]
-		} else {
-			clonedValue = append([]byte(nil), valueBytes...)
-		}
-
 		fields = append(fields, doc.Field{
-			Name:  clonedName,
-			Value: clonedValue,
+			Name:  cloneWithRef(clonedID, tag.Name.Bytes(), dict),
+			Value: cloneWithRef(clonedID, tag.Value.Bytes(), dict),
 		})
 	}
 	if err := tags.Err(); err != nil {
@@ -182,6 +179,18 @@ func TagsFromTagsIter(
 	seriesID ident.ID,
 	iter ident.TagIterator,
 	idPool ident.Pool,
+) (ident.Tags, error) {
+	return TagsFromTagsIterWithDictionary(seriesID, iter, idPool, nil)
+}
+
+// TagsFromTagsIterWithDictionary returns an ident.Tags from a TagIterator
+// the same as TagsFromTagsIter, except that tag names and values not present
+// in the seriesID reference the dictionary bytes where possible.
+func TagsFromTagsIterWithDictionary(
+	seriesID ident.ID,
+	iter ident.TagIterator,
+	idPool ident.Pool,
+	dict Dictionary,
 ) (ident.Tags, error) {
 	var (
 		seriesIDBytes = ident.BytesID(seriesID.Bytes())
@@ -199,12 +208,18 @@ func TagsFromTagsIter(
 		if idx := bytes.Index(seriesIDBytes, nameBytes); idx != -1 {
 			tag.Name = seriesIDBytes[idx : idx+len(nameBytes)]
 			idRef = true
+		} else if ref, ok := internOrFalse(dict, nameBytes); ok {
+			tag.Name = ident.BytesID(ref)
+			idRef = true
 		} else {
 			tag.Name = idPool.Clone(curr.Name)
 		}
 		if idx := bytes.Index(seriesIDBytes, valueBytes); idx != -1 {
 			tag.Value = seriesIDBytes[idx : idx+len(valueBytes)]
 			idRef = true
+		} else if ref, ok := internOrFalse(dict, valueBytes); ok {
+			tag.Value = ident.BytesID(ref)
+			idRef = true
 		} else {
 			tag.Value = idPool.Clone(curr.Value)
 		}
@@ -222,6 +237,25 @@ func TagsFromTagsIter(
 	return tags, nil
 }
 
+// cloneWithRef returns a reference to the bytes within the ID if present,
+// otherwise a reference to the dictionary bytes if possible, otherwise a copy.
+func cloneWithRef(id []byte, b []byte, dict Dictionary) []byte {
+	if idx := bytes.Index(id, b); idx != -1 {
+		return id[idx : idx+len(b)]
+	}
+	if ref, ok := internOrFalse(dict, b); ok {
+		return ref
+	}
+	return append([]byte(nil), b...)
+}
+
+func internOrFalse(dict Dictionary, b []byte) ([]byte, bool) {
+	if dict == nil {
+		return nil, false
+	}
+	return dict.Intern(b)
+}
+
 // NB(prateek): we take an independent copy of the bytes underlying
 // any ids provided, as we need to maintain the lifecycle of the indexed
 // bytes separately from the rest of the storage subsystem.
@@ -0,0 +1,198 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package convert
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// defaultDictionaryMaxValueLength is the longest tag name or value that
+	// is added to a dictionary by default.
+	defaultDictionaryMaxValueLength = 256
+)
+
+// Dictionary is a shared set of tag names and values that documents and
+// series tags reference instead of each holding their own copy of the bytes.
+// Bytes returned by a dictionary are never finalized and must not be mutated.
+type Dictionary interface {
+	// Intern returns the dictionary bytes equal to the given bytes, adding a
+	// copy if not present, returns false if the dictionary is full or the
+	// bytes are too long to be added.
+	Intern(b []byte) ([]byte, bool)
+
+	// Evict removes the entries not interned since the last eviction if the
+	// dictionary is full and returns the number of entries removed, bytes
+	// already returned by the dictionary remain valid.
+	Evict() int
+
+	// Stats returns the current dictionary stats.
+	Stats() DictionaryStats
+}
+
+// DictionaryStats are stats about a dictionary, hits, misses and evictions
+// are cumulative since the dictionary was created.
+type DictionaryStats struct {
+	Entries   int64
+	Bytes     int64
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// DictionaryOptions are options for a dictionary.
+type DictionaryOptions struct {
+	// MaxEntries is the maximum number of entries held by the dictionary.
+	MaxEntries int
+	// MaxValueLength is the longest tag name or value that is added to the
+	// dictionary, defaults to 256 bytes if zero.
+	MaxValueLength int
+}
+
+type dictionaryEntry struct {
+	value []byte
+	// referenced is set when the entry is interned and cleared on eviction
+	// so entries still in use survive an eviction.
+	referenced uint32
+}
+
+type dictionary struct {
+	sync.RWMutex
+
+	hits      int64
+	misses    int64
+	evictions int64
+
+	maxEntries     int
+	maxValueLength int
+	// values is keyed by hash to avoid holding a second copy of each value
+	// as a string key, values with colliding hashes are not added.
+	values map[uint64]*dictionaryEntry
+	bytes  int64
+}
+
+// NewDictionary returns a new dictionary.
+func NewDictionary(opts DictionaryOptions) Dictionary {
+	maxValueLength := opts.MaxValueLength
+	if maxValueLength <= 0 {
+		maxValueLength = defaultDictionaryMaxValueLength
+	}
+	return &dictionary{
+		maxEntries:     opts.MaxEntries,
+		maxValueLength: maxValueLength,
+		values:         make(map[uint64]*dictionaryEntry),
+	}
+}
+
+func (d *dictionary) Intern(b []byte) ([]byte, bool) {
+	if len(b) > d.maxValueLength {
+		atomic.AddInt64(&d.misses, 1)
+		return nil, false
+	}
+
+	hash := xxhash.Sum64(b)
+	d.RLock()
+	entry, ok := d.values[hash]
+	full := len(d.values) >= d.maxEntries
+	d.RUnlock()
+	if ok {
+		return d.hitOrMiss(entry, b)
+	}
+	if full {
+		// NB: avoid taking the exclusive lock for every miss once full,
+		// space is only made by the next eviction.
+		atomic.AddInt64(&d.misses, 1)
+		return nil, false
+	}
+
+	d.Lock()
+	entry, ok = d.values[hash]
+	if ok {
+		d.Unlock()
+		return d.hitOrMiss(entry, b)
+	}
+	if len(d.values) >= d.maxEntries {
+		d.Unlock()
+		atomic.AddInt64(&d.misses, 1)
+		return nil, false
+	}
+	entry = &dictionaryEntry{
+		value:      append([]byte(nil), b...),
+		referenced: 1,
+	}
+	d.values[hash] = entry
+	d.bytes += int64(len(entry.value))
+	d.Unlock()
+
+	return entry.value, true
+}
+
+func (d *dictionary) hitOrMiss(entry *dictionaryEntry, b []byte) ([]byte, bool) {
+	if !bytes.Equal(entry.value, b) {
+		atomic.AddInt64(&d.misses, 1)
+		return nil, false
+	}
+	if atomic.LoadUint32(&entry.referenced) == 0 {
+		atomic.StoreUint32(&entry.referenced, 1)
+	}
+	atomic.AddInt64(&d.hits, 1)
+	return entry.value, true
+}
+
+func (d *dictionary) Evict() int {
+	d.RLock()
+	full := len(d.values) >= d.maxEntries
+	d.RUnlock()
+	if !full {
+		return 0
+	}
+
+	evicted := 0
+	d.Lock()
+	for hash, entry := range d.values {
+		if atomic.SwapUint32(&entry.referenced, 0) == 1 {
+			continue
+		}
+		delete(d.values, hash)
+		d.bytes -= int64(len(entry.value))
+		evicted++
+	}
+	d.Unlock()
+
+	atomic.AddInt64(&d.evictions, int64(evicted))
+	return evicted
+}
+
+func (d *dictionary) Stats() DictionaryStats {
+	d.RLock()
+	entries, size := int64(len(d.values)), d.bytes
+	d.RUnlock()
+	return DictionaryStats{
+		Entries:   entries,
+		Bytes:     size,
+		Hits:      atomic.LoadInt64(&d.hits),
+		Misses:    atomic.LoadInt64(&d.misses),
+		Evictions: atomic.LoadInt64(&d.evictions),
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package convert_test
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictionaryIntern(t *testing.T) {
+	dict := convert.NewDictionary(convert.DictionaryOptions{
+		MaxEntries:     2,
+		MaxValueLength: 4,
+	})
+
+	foo, ok := dict.Intern([]byte("foo"))
+	require.True(t, ok)
+	again, ok := dict.Intern([]byte("foo"))
+	require.True(t, ok)
+	assert.Equal(t, &foo[0], &again[0])
+
+	_, ok = dict.Intern([]byte("toolong"))
+	assert.False(t, ok)
+
+	_, ok = dict.Intern([]byte("bar"))
+	require.True(t, ok)
+	_, ok = dict.Intern([]byte("baz"))
+	assert.False(t, ok)
+
+	assert.Equal(t, convert.DictionaryStats{
+		Entries: 2,
+		Bytes:   6,
+		Hits:    1,
+		Misses:  2,
+	}, dict.Stats())
+}
+
+func TestDictionaryEvict(t *testing.T) {
+	dict := convert.NewDictionary(convert.DictionaryOptions{MaxEntries: 2})
+
+	_, ok := dict.Intern([]byte("foo"))
+	require.True(t, ok)
+	assert.Equal(t, 0, dict.Evict())
+
+	bar, ok := dict.Intern([]byte("bar"))
+	require.True(t, ok)
+
+	// The first eviction only clears the references of the entries interned.
+	assert.Equal(t, 0, dict.Evict())
+	again, ok := dict.Intern([]byte("bar"))
+	require.True(t, ok)
+	assert.Equal(t, &bar[0], &again[0])
+
+	// Entries not interned since the last eviction are removed.
+	assert.Equal(t, 1, dict.Evict())
+	_, ok = dict.Intern([]byte("baz"))
+	require.True(t, ok)
+
+	stats := dict.Stats()
+	assert.Equal(t, int64(2), stats.Entries)
+	assert.Equal(t, int64(6), stats.Bytes)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
+
+func TestFromMetricIterWithDictionary(t *testing.T) {
+	dict := convert.NewDictionary(convert.DictionaryOptions{MaxEntries: 10})
+	id := ident.StringID("foo")
+	tags := ident.NewTags(
+		ident.StringTag("name", "value"),
+	)
+
+	d1, err := convert.FromMetricIterWithDictionary(id,
+		ident.NewTagsIterator(tags), dict)
+	require.NoError(t, err)
+	d2, err := convert.FromMetricIterWithDictionary(id,
+		ident.NewTagsIterator(tags), dict)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("name"), d1.Fields[0].Name)
+	assert.Equal(t, []byte("value"), d1.Fields[0].Value)
+	assert.Equal(t, &d1.Fields[0].Value[0], &d2.Fields[0].Value[0])
+	assert.Equal(t, int64(2), dict.Stats().Entries)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/cespare/xxhash"
+)
+
+type countResults struct {
+	nsID    ident.ID
+	shardFn ShardFn
+	seen    map[uint64]struct{}
+	counts  map[uint32]int64
+	empty   *ResultsMap
+}
+
+// NewCountResults returns results that count the series added to them per
+// shard. Series matched in more than one block are deduped by the hash of
+// their ID so that neither their IDs nor their tags are held, a collision of
+// hashes undercounts by a series.
+func NewCountResults(nsID ident.ID, shardFn ShardFn, opts Options) CountResults {
+	return &countResults{
+		nsID:    nsID,
+		shardFn: shardFn,
+		seen:    make(map[uint64]struct{}),
+		counts:  make(map[uint32]int64),
+		empty:   newResultsMap(opts.IdentifierPool()),
+	}
+}
+
+func (r *countResults) Add(d doc.Document) (bool, int, error) {
+	if len(d.ID) == 0 {
+		return false, len(r.seen), errUnableToAddDocMissingID
+	}
+	hash := xxhash.Sum64(d.ID)
+	if _, ok := r.seen[hash]; ok {
+		return false, len(r.seen), nil
+	}
+	r.seen[hash] = struct{}{}
+
+	var shard uint32
+	if r.shardFn != nil {
+		shard = r.shardFn(ident.BytesID(d.ID))
+	}
+	r.counts[shard]++
+	return true, len(r.seen), nil
+}
+
+func (r *countResults) Namespace() ident.ID {
+	return r.nsID
+}
+
+// Map returns an empty map since the series counted are not retained.
+func (r *countResults) Map() *ResultsMap {
+	return r.empty
+}
+
+func (r *countResults) Size() int {
+	return len(r.seen)
+}
+
+func (r *countResults) ShardCounts() map[uint32]int64 {
+	return r.counts
+}
+
+func (r *countResults) Reset(nsID ident.ID) {
+	r.nsID = nsID
+	r.seen = make(map[uint64]struct{})
+	r.counts = make(map[uint32]int64)
+}
+
+func (r *countResults) Finalize() {
+	r.Reset(nil)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountResults(t *testing.T) {
+	shardFn := func(id ident.ID) uint32 {
+		return uint32(len(id.Bytes()))
+	}
+	res := NewCountResults(ident.StringID("ns"), shardFn, testOpts)
+
+	for _, id := range []string{"a", "b", "cc", "a"} {
+		_, _, err := res.Add(doc.Document{
+			ID:     []byte(id),
+			Fields: doc.Fields{{Name: []byte("foo"), Value: []byte("bar")}},
+		})
+		require.NoError(t, err)
+	}
+	_, _, err := res.Add(doc.Document{})
+	require.Error(t, err)
+
+	require.Equal(t, "ns", res.Namespace().String())
+	require.Equal(t, 3, res.Size())
+	require.Equal(t, 0, res.Map().Len())
+	require.Equal(t, map[uint32]int64{1: 2, 2: 1}, res.ShardCounts())
+
+	res.Reset(ident.StringID("other"))
+	require.Equal(t, 0, res.Size())
+	require.Equal(t, 0, len(res.ShardCounts()))
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -134,3 +135,52 @@ func TestWriteBatchForEachUnmarkedBatchByBlockStartMore(t *testing.T) {
 	})
 	require.Equal(t, 2, numCalls)
 }
+
+func TestNewSeriesWriteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	series := []WriteBatchSeries{
+		{
+			Entry: WriteBatchEntry{
+				Timestamp:     now,
+				OnIndexSeries: NewMockOnIndexSeries(ctrl),
+			},
+			ID:   ident.StringID("foo"),
+			Tags: ident.NewTags(ident.StringTag("name", "foo"), ident.StringTag("a", "b")),
+		},
+		{
+			Entry: WriteBatchEntry{
+				Timestamp:     now,
+				OnIndexSeries: NewMockOnIndexSeries(ctrl),
+			},
+			ID:   ident.StringID("bar"),
+			Tags: ident.NewTags(ident.StringTag("name", "bar")),
+		},
+	}
+
+	batch := NewSeriesWriteBatch(WriteBatchOptions{}, series)
+	require.Equal(t, 2, batch.Len())
+	require.Equal(t, []doc.Document{
+		{
+			ID: []byte("foo"),
+			Fields: doc.Fields{
+				{Name: []byte("name"), Value: []byte("foo")},
+				{Name: []byte("a"), Value: []byte("b")},
+			},
+		},
+		{
+			ID:     []byte("bar"),
+			Fields: doc.Fields{{Name: []byte("name"), Value: []byte("bar")}},
+		},
+	}, batch.PendingDocs())
+	for i, entry := range batch.PendingEntries() {
+		require.True(t, entry.OnIndexSeries == series[i].Entry.OnIndexSeries)
+	}
+
+	// Appending to the fields of one document must not overwrite the next.
+	docs := batch.PendingDocs()
+	_ = append(docs[0].Fields, doc.Field{Name: []byte("c"), Value: []byte("d")})
+	require.Equal(t, "name", string(docs[1].Fields[0].Name))
+}
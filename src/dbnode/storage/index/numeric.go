@@ -0,0 +1,73 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+)
+
+// ValidateNumericRangeQueries returns an error if the query contains a numeric
+// range filter on a tag name which is not indexed as a number.
+func ValidateNumericRangeQueries(q Query, numericTagNames []string) error {
+	sq := q.SearchQuery()
+	if sq == nil {
+		return nil
+	}
+	return validateNumericRangeQueries(sq.ToProto(), numericTagNames)
+}
+
+func validateNumericRangeQueries(q *querypb.Query, numericTagNames []string) error {
+	if q == nil {
+		return nil
+	}
+
+	switch inner := q.Query.(type) {
+	case *querypb.Query_NumericRange:
+		field := string(inner.NumericRange.Field)
+		for _, name := range numericTagNames {
+			if name == field {
+				return nil
+			}
+		}
+		return fmt.Errorf("numeric range query on tag %s which is not indexed as a number", field)
+
+	case *querypb.Query_Negation:
+		return validateNumericRangeQueries(inner.Negation.Query, numericTagNames)
+
+	case *querypb.Query_Conjunction:
+		for _, q := range inner.Conjunction.Queries {
+			if err := validateNumericRangeQueries(q, numericTagNames); err != nil {
+				return err
+			}
+		}
+
+	case *querypb.Query_Disjunction:
+		for _, q := range inner.Disjunction.Queries {
+			if err := validateNumericRangeQueries(q, numericTagNames); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/m3ninx/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNumericRangeQueries(t *testing.T) {
+	var (
+		numericTags = []string{"status_code"}
+		rng         = index.NewNumericRange().WithMin(500, true)
+	)
+
+	q := Query{idx.NewConjunctionQuery(
+		idx.NewTermQuery([]byte("service"), []byte("api")),
+		idx.NewNumericRangeQuery([]byte("status_code"), rng),
+	)}
+	require.NoError(t, ValidateNumericRangeQueries(q, numericTags))
+
+	q = Query{idx.NewDisjunctionQuery(
+		idx.NewTermQuery([]byte("service"), []byte("api")),
+		idx.NewNegationQuery(idx.NewNumericRangeQuery([]byte("latency"), rng)),
+	)}
+	require.Error(t, ValidateNumericRangeQueries(q, numericTags))
+	require.Error(t, ValidateNumericRangeQueries(q, nil))
+
+	q = Query{idx.NewTermQuery([]byte("service"), []byte("api"))}
+	require.NoError(t, ValidateNumericRangeQueries(q, nil))
+}
@@ -22,8 +22,11 @@ package index
 
 import (
 	"errors"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -33,23 +36,65 @@ import (
 const (
 	// defaultIndexInsertMode sets the default indexing mode to synchronous.
 	defaultIndexInsertMode = InsertSync
+
+	// defaultRecentlyIndexedWindow is how long IDs are remembered as indexed
+	// for a block start by default, zero so that skipping repeated inserts
+	// is opt-in.
+	defaultRecentlyIndexedWindow = 0
+
+	// defaultInsertSyncTimeout is how long a synchronous insert waits for
+	// its batch to be indexed by default, so that writes are not blocked
+	// indefinitely when indexing stalls.
+	defaultInsertSyncTimeout = 30 * time.Second
+
+	// defaultQueryCacheTTL is how long query results are cached by default
+	// when the query cache is enabled.
+	defaultQueryCacheTTL = 10 * time.Second
+
+	// defaultCardinalityReportMaxValues is the number of top values retained
+	// for each tag name by default when computing cardinality.
+	defaultCardinalityReportMaxValues = 100
 )
 
 var (
 	errOptionsIdentifierPoolUnspecified = errors.New("identifier pool is unset")
 	errOptionsBytesPoolUnspecified      = errors.New("checkedbytes pool is unset")
 	errOptionsResultsPoolUnspecified    = errors.New("results pool is unset")
+	errOptionsNewBlockFnUnspecified     = errors.New("new block fn is unset")
 	errIDGenerationDisabled             = errors.New("id generation is disabled")
 )
 
 type opts struct {
 	insertMode     InsertMode
+	insertTimeout  time.Duration
 	clockOpts      clock.Options
 	instrumentOpts instrument.Options
 	memOpts        mem.Options
 	idPool         ident.Pool
 	bytesPool      pool.CheckedBytesPool
 	resultsPool    ResultsPool
+	newBlockFn     NewBlockFn
+
+	recentlyIndexedWindow time.Duration
+
+	cardinalityReportInterval  time.Duration
+	cardinalityReportMaxValues int
+
+	tagDictionaryMaxEntries int
+	tagDictionary           convert.Dictionary
+
+	warmupEnabled bool
+	warmupQueries []Query
+
+	reservedFieldNames []string
+	fieldValidators    map[string]FieldValidator
+
+	fullScanMaxSeries int
+
+	queryCacheSize int
+	queryCacheTTL  time.Duration
+
+	postingsListCache *PostingsListCache
 }
 
 var undefinedUUIDFn = func() ([]byte, error) { return nil, errIDGenerationDisabled }
@@ -64,12 +109,19 @@ func NewOptions() Options {
 	idPool := ident.NewPool(bytesPool, ident.PoolOptions{})
 	opts := &opts{
 		insertMode:     defaultIndexInsertMode,
+		insertTimeout:  defaultInsertSyncTimeout,
 		clockOpts:      clock.NewOptions(),
 		instrumentOpts: instrument.NewOptions(),
 		memOpts:        mem.NewOptions().SetNewUUIDFn(undefinedUUIDFn),
 		bytesPool:      bytesPool,
 		idPool:         idPool,
 		resultsPool:    resultsPool,
+		newBlockFn:     NewBlock,
+
+		recentlyIndexedWindow: defaultRecentlyIndexedWindow,
+		queryCacheTTL:         defaultQueryCacheTTL,
+
+		cardinalityReportMaxValues: defaultCardinalityReportMaxValues,
 	}
 	resultsPool.Init(func() Results { return NewResults(opts) })
 	return opts
@@ -85,6 +137,9 @@ func (o *opts) Validate() error {
 	if o.resultsPool == nil {
 		return errOptionsResultsPoolUnspecified
 	}
+	if o.newBlockFn == nil {
+		return errOptionsNewBlockFnUnspecified
+	}
 	return nil
 }
 
@@ -98,6 +153,16 @@ func (o *opts) InsertMode() InsertMode {
 	return o.insertMode
 }
 
+func (o *opts) SetInsertSyncTimeout(value time.Duration) Options {
+	opts := *o
+	opts.insertTimeout = value
+	return &opts
+}
+
+func (o *opts) InsertSyncTimeout() time.Duration {
+	return o.insertTimeout
+}
+
 func (o *opts) SetClockOptions(value clock.Options) Options {
 	opts := *o
 	opts.clockOpts = value
@@ -159,3 +224,163 @@ func (o *opts) SetResultsPool(value ResultsPool) Options {
 func (o *opts) ResultsPool() ResultsPool {
 	return o.resultsPool
 }
+
+func (o *opts) SetNewBlockFn(value NewBlockFn) Options {
+	opts := *o
+	opts.newBlockFn = value
+	return &opts
+}
+
+func (o *opts) NewBlockFn() NewBlockFn {
+	return o.newBlockFn
+}
+
+func (o *opts) SetNumericTagNames(value []string) Options {
+	opts := *o
+	opts.memOpts = opts.MemSegmentOptions().SetNumericFields(value)
+	return &opts
+}
+
+func (o *opts) NumericTagNames() []string {
+	return o.memOpts.NumericFields()
+}
+
+func (o *opts) SetRecentlyIndexedWindow(value time.Duration) Options {
+	opts := *o
+	opts.recentlyIndexedWindow = value
+	return &opts
+}
+
+func (o *opts) RecentlyIndexedWindow() time.Duration {
+	return o.recentlyIndexedWindow
+}
+
+func (o *opts) SetCardinalityReportInterval(value time.Duration) Options {
+	opts := *o
+	opts.cardinalityReportInterval = value
+	return &opts
+}
+
+func (o *opts) CardinalityReportInterval() time.Duration {
+	return o.cardinalityReportInterval
+}
+
+func (o *opts) SetCardinalityReportMaxValues(value int) Options {
+	opts := *o
+	opts.cardinalityReportMaxValues = value
+	return &opts
+}
+
+func (o *opts) CardinalityReportMaxValues() int {
+	return o.cardinalityReportMaxValues
+}
+
+func (o *opts) SetTagDictionaryMaxEntries(value int) Options {
+	opts := *o
+	opts.tagDictionaryMaxEntries = value
+	return &opts
+}
+
+func (o *opts) TagDictionaryMaxEntries() int {
+	return o.tagDictionaryMaxEntries
+}
+
+func (o *opts) SetTagDictionary(value convert.Dictionary) Options {
+	opts := *o
+	opts.tagDictionary = value
+	return &opts
+}
+
+func (o *opts) TagDictionary() convert.Dictionary {
+	return o.tagDictionary
+}
+
+func (o *opts) SetWarmupEnabled(value bool) Options {
+	opts := *o
+	opts.warmupEnabled = value
+	return &opts
+}
+
+func (o *opts) WarmupEnabled() bool {
+	return o.warmupEnabled
+}
+
+func (o *opts) SetWarmupQueries(value []Query) Options {
+	opts := *o
+	opts.warmupQueries = value
+	return &opts
+}
+
+func (o *opts) WarmupQueries() []Query {
+	return o.warmupQueries
+}
+
+func (o *opts) SetReservedFieldNames(value []string) Options {
+	opts := *o
+	opts.reservedFieldNames = value
+	return &opts
+}
+
+func (o *opts) ReservedFieldNames() []string {
+	return o.reservedFieldNames
+}
+
+func (o *opts) SetFieldValidators(value map[string]FieldValidator) Options {
+	opts := *o
+	opts.fieldValidators = value
+	return &opts
+}
+
+func (o *opts) FieldValidators() map[string]FieldValidator {
+	return o.fieldValidators
+}
+
+func (o *opts) SetAnalyzer(value analysis.Analyzer) Options {
+	opts := *o
+	opts.memOpts = opts.MemSegmentOptions().SetAnalyzer(value)
+	return &opts
+}
+
+func (o *opts) Analyzer() analysis.Analyzer {
+	return o.memOpts.Analyzer()
+}
+
+func (o *opts) SetFullScanMaxSeries(value int) Options {
+	opts := *o
+	opts.fullScanMaxSeries = value
+	return &opts
+}
+
+func (o *opts) FullScanMaxSeries() int {
+	return o.fullScanMaxSeries
+}
+
+func (o *opts) SetQueryCacheSize(value int) Options {
+	opts := *o
+	opts.queryCacheSize = value
+	return &opts
+}
+
+func (o *opts) QueryCacheSize() int {
+	return o.queryCacheSize
+}
+
+func (o *opts) SetQueryCacheTTL(value time.Duration) Options {
+	opts := *o
+	opts.queryCacheTTL = value
+	return &opts
+}
+
+func (o *opts) QueryCacheTTL() time.Duration {
+	return o.queryCacheTTL
+}
+
+func (o *opts) SetPostingsListCache(value *PostingsListCache) Options {
+	opts := *o
+	opts.postingsListCache = value
+	return &opts
+}
+
+func (o *opts) PostingsListCache() *PostingsListCache {
+	return o.postingsListCache
+}
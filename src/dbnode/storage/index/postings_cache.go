@@ -0,0 +1,201 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"container/list"
+	"sync"
+
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
+
+	"github.com/uber-go/tally"
+)
+
+type postingsListCacheKey struct {
+	segment segment.Segment
+	field   string
+	pattern string
+	regexp  bool
+}
+
+type postingsListCacheEntry struct {
+	key          postingsListCacheKey
+	postingsList postings.List
+}
+
+type postingsListCacheMetrics struct {
+	hits      tally.Counter
+	misses    tally.Counter
+	evictions tally.Counter
+	purges    tally.Counter
+	size      tally.Gauge
+}
+
+func newPostingsListCacheMetrics(scope tally.Scope) postingsListCacheMetrics {
+	return postingsListCacheMetrics{
+		hits:      scope.Counter("hits"),
+		misses:    scope.Counter("misses"),
+		evictions: scope.Counter("evictions"),
+		purges:    scope.Counter("purges"),
+		size:      scope.Gauge("size"),
+	}
+}
+
+// PostingsListCache is an LRU cache of the postings lists matched by the
+// terms and regexps of immutable segments, it is shared by the index blocks
+// of every namespace. Immutable segments never change so entries are only
+// removed when evicted or when their segment is closed.
+type PostingsListCache struct {
+	sync.Mutex
+
+	size    int
+	entries map[postingsListCacheKey]*list.Element
+	lru     *list.List
+	metrics postingsListCacheMetrics
+}
+
+// NewPostingsListCache returns a new postings list cache holding at most size
+// postings lists.
+func NewPostingsListCache(size int, scope tally.Scope) *PostingsListCache {
+	return &PostingsListCache{
+		size:    size,
+		entries: make(map[postingsListCacheKey]*list.Element, size),
+		lru:     list.New(),
+		metrics: newPostingsListCacheMetrics(scope),
+	}
+}
+
+func (c *PostingsListCache) get(key postingsListCacheKey) (postings.List, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.metrics.misses.Inc(1)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.metrics.hits.Inc(1)
+	return elem.Value.(postingsListCacheEntry).postingsList, true
+}
+
+func (c *PostingsListCache) put(key postingsListCacheKey, pl postings.List) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(postingsListCacheEntry{
+		key:          key,
+		postingsList: pl,
+	})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(postingsListCacheEntry).key)
+		c.metrics.evictions.Inc(1)
+	}
+	c.metrics.size.Update(float64(c.lru.Len()))
+}
+
+// PurgeSegment removes the postings lists of a segment that is being closed.
+func (c *PostingsListCache) PurgeSegment(seg segment.Segment) {
+	c.Lock()
+	defer c.Unlock()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(postingsListCacheEntry)
+		if entry.key.segment == seg {
+			c.lru.Remove(elem)
+			delete(c.entries, entry.key)
+			c.metrics.purges.Inc(1)
+		}
+		elem = next
+	}
+	c.metrics.size.Update(float64(c.lru.Len()))
+}
+
+// cachedPostingsListReader is a reader of an immutable segment that matches
+// terms and regexps from the postings list cache.
+type cachedPostingsListReader struct {
+	m3ninxindex.Reader
+
+	segment segment.Segment
+	cache   *PostingsListCache
+}
+
+func newCachedPostingsListReader(
+	reader m3ninxindex.Reader,
+	seg segment.Segment,
+	cache *PostingsListCache,
+) m3ninxindex.Reader {
+	return &cachedPostingsListReader{
+		Reader:  reader,
+		segment: seg,
+		cache:   cache,
+	}
+}
+
+func (r *cachedPostingsListReader) MatchTerm(
+	field, term []byte,
+) (postings.List, error) {
+	key := postingsListCacheKey{
+		segment: r.segment,
+		field:   string(field),
+		pattern: string(term),
+	}
+	if pl, ok := r.cache.get(key); ok {
+		return pl, nil
+	}
+	pl, err := r.Reader.MatchTerm(field, term)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.put(key, pl)
+	return pl, nil
+}
+
+func (r *cachedPostingsListReader) MatchRegexp(
+	field, regexp []byte,
+	compiled m3ninxindex.CompiledRegex,
+) (postings.List, error) {
+	key := postingsListCacheKey{
+		segment: r.segment,
+		field:   string(field),
+		pattern: string(regexp),
+		regexp:  true,
+	}
+	if pl, ok := r.cache.get(key); ok {
+		return pl, nil
+	}
+	pl, err := r.Reader.MatchRegexp(field, regexp, compiled)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.put(key, pl)
+	return pl, nil
+}
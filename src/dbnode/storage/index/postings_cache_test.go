@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestPostingsListCacheReaderMatchesFromCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		cache  = NewPostingsListCache(2, tally.NoopScope)
+		seg    = segment.NewMockSegment(ctrl)
+		reader = m3ninxindex.NewMockReader(ctrl)
+		pl     = roaring.NewPostingsList()
+		field  = []byte("app")
+	)
+	require.NoError(t, pl.Insert(1))
+
+	// NB: each term and regexp is only matched by the segment once.
+	reader.EXPECT().MatchTerm(field, []byte("a")).Return(pl, nil)
+	reader.EXPECT().MatchRegexp(field, []byte("a.*"), gomock.Any()).Return(pl, nil)
+	cached := newCachedPostingsListReader(reader, seg, cache)
+	for i := 0; i < 2; i++ {
+		matched, err := cached.MatchTerm(field, []byte("a"))
+		require.NoError(t, err)
+		require.True(t, matched.Equal(pl))
+
+		matched, err = cached.MatchRegexp(field, []byte("a.*"), m3ninxindex.CompiledRegex{})
+		require.NoError(t, err)
+		require.True(t, matched.Equal(pl))
+	}
+
+	// the least recently used postings list is evicted once full.
+	reader.EXPECT().MatchTerm(field, []byte("b")).Return(pl, nil)
+	_, err := cached.MatchTerm(field, []byte("b"))
+	require.NoError(t, err)
+	_, ok := cache.get(postingsListCacheKey{segment: seg, field: "app", pattern: "a"})
+	require.False(t, ok)
+	_, ok = cache.get(postingsListCacheKey{segment: seg, field: "app", pattern: "a.*", regexp: true})
+	require.True(t, ok)
+
+	// postings lists of closed segments are purged.
+	other := segment.NewMockSegment(ctrl)
+	cache.put(postingsListCacheKey{segment: other, field: "app", pattern: "c"}, pl)
+	cache.PurgeSegment(seg)
+	require.Equal(t, 1, cache.lru.Len())
+	_, ok = cache.get(postingsListCacheKey{segment: other, field: "app", pattern: "c"})
+	require.True(t, ok)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+)
+
+// QueryLimit names a limit that bounds the cost of a query.
+type QueryLimit string
+
+const (
+	// MaxResultsQueryLimit bounds the number of series returned by a query.
+	MaxResultsQueryLimit QueryLimit = "max-results"
+
+	// MaxSeriesMatchedQueryLimit bounds the number of series matched across
+	// all blocks searched by a query.
+	MaxSeriesMatchedQueryLimit QueryLimit = "max-series-matched"
+)
+
+// QueryLimitExceeded is a warning returned with the partial results of a
+// query that was stopped early because it exceeded a limit.
+type QueryLimitExceeded struct {
+	Limit QueryLimit
+	Max   int
+}
+
+func (e QueryLimitExceeded) Error() string {
+	return fmt.Sprintf("query exceeded limit %s=%d, results are partial", e.Limit, e.Max)
+}
+
+// LimitedResults are results that stop accepting series once a query limit
+// is exceeded.
+type LimitedResults interface {
+	Results
+
+	// LimitExceeded returns the limit that was exceeded, if any.
+	LimitExceeded() *QueryLimitExceeded
+}
+
+type limitedResults struct {
+	Results
+
+	maxResults       int
+	maxSeriesMatched int
+	matched          int
+	added            map[string]struct{}
+	exceeded         *QueryLimitExceeded
+}
+
+// NewLimitedResults returns results that return a QueryLimitExceeded error
+// from Add once either more than maxSeriesMatched series have been matched or
+// maxResults series have been added, a limit of zero is unlimited. A series
+// already in the results does not count towards maxResults.
+func NewLimitedResults(
+	results Results,
+	maxResults int,
+	maxSeriesMatched int,
+) LimitedResults {
+	r := &limitedResults{
+		Results:          results,
+		maxResults:       maxResults,
+		maxSeriesMatched: maxSeriesMatched,
+	}
+	if maxResults > 0 {
+		// NB: the IDs added are tracked here rather than looked up in the
+		// wrapped results since streamed results only hold the current batch.
+		r.added = make(map[string]struct{}, maxResults)
+	}
+	return r
+}
+
+func (r *limitedResults) Add(d doc.Document) (bool, int, error) {
+	if r.exceeded != nil {
+		return false, r.Size(), *r.exceeded
+	}
+
+	r.matched++
+	if r.maxSeriesMatched > 0 && r.matched > r.maxSeriesMatched {
+		return false, r.Size(), r.exceed(MaxSeriesMatchedQueryLimit, r.maxSeriesMatched)
+	}
+	if r.maxResults == 0 {
+		return r.Results.Add(d)
+	}
+	if _, ok := r.added[string(d.ID)]; ok {
+		return false, r.Size(), nil
+	}
+	if len(r.added) >= r.maxResults {
+		return false, r.Size(), r.exceed(MaxResultsQueryLimit, r.maxResults)
+	}
+	added, size, err := r.Results.Add(d)
+	if added {
+		r.added[string(d.ID)] = struct{}{}
+	}
+	return added, size, err
+}
+
+func (r *limitedResults) Reset(nsID ident.ID) {
+	r.Results.Reset(nsID)
+	r.matched = 0
+	r.exceeded = nil
+	for id := range r.added {
+		delete(r.added, id)
+	}
+}
+
+func (r *limitedResults) exceed(limit QueryLimit, max int) error {
+	r.exceeded = &QueryLimitExceeded{Limit: limit, Max: max}
+	return *r.exceeded
+}
+
+func (r *limitedResults) LimitExceeded() *QueryLimitExceeded {
+	return r.exceeded
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitedResultsMaxResults(t *testing.T) {
+	res := NewResults(testOpts)
+	res.Reset(ident.StringID("ns"))
+	limited := NewLimitedResults(res, 2, 0)
+
+	for _, id := range []string{"foo", "bar"} {
+		added, _, err := limited.Add(doc.Document{ID: []byte(id)})
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.Nil(t, limited.LimitExceeded())
+
+	// a series already in the results is not rejected once the limit is reached.
+	added, _, err := limited.Add(doc.Document{ID: []byte("foo")})
+	require.NoError(t, err)
+	require.False(t, added)
+	require.Nil(t, limited.LimitExceeded())
+
+	_, size, err := limited.Add(doc.Document{ID: []byte("baz")})
+	require.Equal(t, QueryLimitExceeded{Limit: MaxResultsQueryLimit, Max: 2}, err)
+	require.Equal(t, 2, size)
+	require.Equal(t, &QueryLimitExceeded{Limit: MaxResultsQueryLimit, Max: 2},
+		limited.LimitExceeded())
+	require.Equal(t, 2, res.Size())
+}
+
+func TestLimitedResultsMaxSeriesMatched(t *testing.T) {
+	res := NewResults(testOpts)
+	res.Reset(ident.StringID("ns"))
+	limited := NewLimitedResults(res, 0, 2)
+
+	// matching the same series twice counts towards the limit.
+	for i := 0; i < 2; i++ {
+		_, _, err := limited.Add(doc.Document{ID: []byte("foo")})
+		require.NoError(t, err)
+	}
+
+	_, _, err := limited.Add(doc.Document{ID: []byte("bar")})
+	require.Equal(t, QueryLimitExceeded{Limit: MaxSeriesMatchedQueryLimit, Max: 2}, err)
+	require.Equal(t, 1, res.Size())
+}
@@ -28,6 +28,16 @@ import (
 	"github.com/m3db/m3x/pool"
 )
 
+const (
+	// tagsChunkSize is the number of tags allocated at once for the tags of
+	// the documents added to results.
+	tagsChunkSize = 256
+
+	// maxRetainedTagsChunks is the number of tags chunks retained on reset
+	// for reuse, so results that once held many documents do not keep them.
+	maxRetainedTagsChunks = 64
+)
+
 var (
 	errUnableToAddDocMissingID = errors.New("corrupt data, unable to extract id")
 )
@@ -40,6 +50,12 @@ type results struct {
 	idPool    ident.Pool
 	bytesPool pool.CheckedBytesPool
 
+	// tagsChunks back the tags of added documents, they are sized from the
+	// number of fields of each document and reused across resets to avoid
+	// allocating and growing a tags slice per document.
+	tagsChunks [][]ident.Tag
+	tagsChunk  int
+
 	pool ResultsPool
 }
 
@@ -82,14 +98,38 @@ func (r *results) Add(d doc.Document) (added bool, size int, err error) {
 }
 
 func (r *results) tags(fields doc.Fields) ident.Tags {
-	tags := r.idPool.Tags()
-	for _, f := range fields {
-		tags.Append(ident.Tag{
+	values := r.tagValues(len(fields))
+	for i, f := range fields {
+		values[i] = ident.Tag{
 			Name:  r.copyBytes(f.Name),
 			Value: r.copyBytes(f.Value),
-		})
+		}
 	}
-	return tags
+	// NB: the tags are not associated with the tags pool as their values
+	// are owned by the results and are released on reset.
+	return ident.NewTags(values...)
+}
+
+// tagValues returns a slice of n tags carved from the tags chunks, capped so
+// that appends to it never overwrite the tags of other documents.
+func (r *results) tagValues(n int) []ident.Tag {
+	for ; r.tagsChunk < len(r.tagsChunks); r.tagsChunk++ {
+		chunk := r.tagsChunks[r.tagsChunk]
+		if start := len(chunk); cap(chunk)-start >= n {
+			chunk = chunk[:start+n]
+			r.tagsChunks[r.tagsChunk] = chunk
+			return chunk[start : start+n : start+n]
+		}
+	}
+
+	size := tagsChunkSize
+	if n > size {
+		size = n
+	}
+	chunk := make([]ident.Tag, n, size)
+	r.tagsChunks = append(r.tagsChunks, chunk)
+	r.tagsChunk = len(r.tagsChunks) - 1
+	return chunk[:n:n]
 }
 
 // copyBytes copies the provided bytes into an ident.ID backed by pooled types.
@@ -136,6 +176,21 @@ func (r *results) Reset(nsID ident.ID) {
 	r.resultsMap.Reset()
 	r.size = 0
 
+	// release references held by the tags chunks so they can be reused
+	for i, chunk := range r.tagsChunks {
+		for j := range chunk {
+			chunk[j] = ident.Tag{}
+		}
+		r.tagsChunks[i] = chunk[:0]
+	}
+	if len(r.tagsChunks) > maxRetainedTagsChunks {
+		for i := maxRetainedTagsChunks; i < len(r.tagsChunks); i++ {
+			r.tagsChunks[i] = nil
+		}
+		r.tagsChunks = r.tagsChunks[:maxRetainedTagsChunks]
+	}
+	r.tagsChunk = 0
+
 	// NB: could do keys+value in one step but I'm trying to avoid
 	// using an internal method of a code-gen'd type.
 }
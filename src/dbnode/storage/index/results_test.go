@@ -21,6 +21,7 @@
 package index
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -135,6 +136,45 @@ func TestResultsReset(t *testing.T) {
 	require.Equal(t, 0, res.Size())
 }
 
+func TestResultsTagsChunksReused(t *testing.T) {
+	res := NewResults(testOpts).(*results)
+	add := func(id string, numFields int) {
+		d := doc.Document{ID: []byte(id)}
+		for i := 0; i < numFields; i++ {
+			d.Fields = append(d.Fields, doc.Field{
+				Name:  []byte(fmt.Sprintf("name%d", i)),
+				Value: []byte(fmt.Sprintf("%s%d", id, i)),
+			})
+		}
+		added, _, err := res.Add(d)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	verify := func(id string, numFields int) {
+		tags, ok := res.Map().Get(ident.StringID(id))
+		require.True(t, ok)
+		require.Equal(t, numFields, len(tags.Values()))
+		for i, tag := range tags.Values() {
+			require.Equal(t, fmt.Sprintf("name%d", i), tag.Name.String())
+			require.Equal(t, fmt.Sprintf("%s%d", id, i), tag.Value.String())
+		}
+	}
+
+	add("a", 3)
+	add("b", 2)
+	add("c", tagsChunkSize)
+	verify("a", 3)
+	verify("b", 2)
+	verify("c", tagsChunkSize)
+	require.Equal(t, 2, len(res.tagsChunks))
+
+	res.Reset(nil)
+	add("d", 4)
+	verify("d", 4)
+	require.Equal(t, 2, len(res.tagsChunks))
+	require.Equal(t, 4, len(res.tagsChunks[0]))
+}
+
 func TestResultsResetNamespaceClones(t *testing.T) {
 	res := NewResults(testOpts)
 	require.Equal(t, nil, res.Namespace())
@@ -0,0 +1,143 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+)
+
+// SegmentMergePolicy determines which of the segments of a block are
+// searched and merged when the block is queried.
+type SegmentMergePolicy uint
+
+const (
+	// MergeAllSegmentsPolicy searches and merges all the segments of a block.
+	MergeAllSegmentsPolicy SegmentMergePolicy = iota
+	// PreferFlushedSegmentsPolicy searches only the flushed segments of a
+	// block when they cover every shard of the block for its entire range,
+	// falling back to all of its segments otherwise, series written since
+	// the block was flushed are not returned.
+	PreferFlushedSegmentsPolicy
+	// SkipMutableSegmentsForHistoricalPolicy skips the mutable segments of
+	// sealed blocks, i.e. blocks that no longer receive writes, once their
+	// flushed segments cover every shard of the block for its entire range.
+	SkipMutableSegmentsForHistoricalPolicy
+
+	// DefaultSegmentMergePolicy is the default segment merge policy.
+	DefaultSegmentMergePolicy = MergeAllSegmentsPolicy
+)
+
+var validSegmentMergePolicies = []SegmentMergePolicy{
+	MergeAllSegmentsPolicy,
+	PreferFlushedSegmentsPolicy,
+	SkipMutableSegmentsForHistoricalPolicy,
+}
+
+func (p SegmentMergePolicy) String() string {
+	switch p {
+	case MergeAllSegmentsPolicy:
+		return "merge_all"
+	case PreferFlushedSegmentsPolicy:
+		return "prefer_flushed"
+	case SkipMutableSegmentsForHistoricalPolicy:
+		return "skip_mutable_historical"
+	}
+	return "unknown"
+}
+
+// ValidSegmentMergePolicies returns the valid segment merge policies.
+func ValidSegmentMergePolicies() []SegmentMergePolicy {
+	src := validSegmentMergePolicies
+	dst := make([]SegmentMergePolicy, 0, len(src))
+	return append(dst, src...)
+}
+
+// ValidateSegmentMergePolicy returns an error if the policy is invalid.
+func ValidateSegmentMergePolicy(p SegmentMergePolicy) error {
+	for _, valid := range validSegmentMergePolicies {
+		if valid == p {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid segment merge policy '%d': should be one of %v",
+		p, validSegmentMergePolicies)
+}
+
+// ParseSegmentMergePolicy parses a SegmentMergePolicy from a string.
+func ParseSegmentMergePolicy(str string) (SegmentMergePolicy, error) {
+	for _, valid := range validSegmentMergePolicies {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return 0, fmt.Errorf("unrecognized segment merge policy: %v", str)
+}
+
+// UnmarshalYAML unmarshals a segment merge policy.
+func (p *SegmentMergePolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*p = DefaultSegmentMergePolicy
+		return nil
+	}
+	parsed, err := ParseSegmentMergePolicy(str)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// selectSegments returns the segments to search for the policy given the
+// segments of a block, whether the block is sealed and whether its flushed
+// segments cover every shard of the block for its entire range. Mutable
+// segments are never skipped unless flushed segments cover the block since
+// they may hold the only copy of series that are yet to be flushed.
+func (p SegmentMergePolicy) selectSegments(
+	segments []segment.Segment,
+	sealed bool,
+	flushedCoversBlock bool,
+) []segment.Segment {
+	var skipMutable bool
+	switch p {
+	case PreferFlushedSegmentsPolicy:
+		skipMutable = flushedCoversBlock
+	case SkipMutableSegmentsForHistoricalPolicy:
+		skipMutable = sealed && flushedCoversBlock
+	}
+	if !skipMutable {
+		return segments
+	}
+
+	selected := make([]segment.Segment, 0, len(segments))
+	for _, seg := range segments {
+		if _, ok := seg.(segment.MutableSegment); ok {
+			continue
+		}
+		selected = append(selected, seg)
+	}
+	return selected
+}
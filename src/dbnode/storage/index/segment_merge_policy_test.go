@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentMergePolicySelectSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mutable  = segment.NewMockMutableSegment(ctrl)
+		flushed  = segment.NewMockSegment(ctrl)
+		both     = []segment.Segment{mutable, flushed}
+		onlyMut  = []segment.Segment{mutable}
+		expected = []segment.Segment{flushed}
+	)
+
+	require.Equal(t, both, MergeAllSegmentsPolicy.selectSegments(both, true, true))
+
+	require.Equal(t, expected, PreferFlushedSegmentsPolicy.selectSegments(both, false, true))
+	require.Equal(t, both, PreferFlushedSegmentsPolicy.selectSegments(both, false, false))
+	require.Equal(t, onlyMut, PreferFlushedSegmentsPolicy.selectSegments(onlyMut, false, false))
+
+	require.Equal(t, both, SkipMutableSegmentsForHistoricalPolicy.selectSegments(both, false, true))
+	require.Equal(t, both, SkipMutableSegmentsForHistoricalPolicy.selectSegments(both, true, false))
+	require.Equal(t, expected, SkipMutableSegmentsForHistoricalPolicy.selectSegments(both, true, true))
+}
+
+func TestParseSegmentMergePolicy(t *testing.T) {
+	for _, p := range ValidSegmentMergePolicies() {
+		parsed, err := ParseSegmentMergePolicy(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, parsed)
+	}
+
+	_, err := ParseSegmentMergePolicy("unknown")
+	require.Error(t, err)
+	require.Error(t, ValidateSegmentMergePolicy(SegmentMergePolicy(100)))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// DefaultQueryStreamBatchSize is the default number of series that are
+	// buffered before a batch of streamed query results is delivered.
+	DefaultQueryStreamBatchSize = 1024
+
+	// maxStreamingResultsSeen is the maximum number of series the streaming
+	// results remember to dedupe series matched in more than one block.
+	maxStreamingResultsSeen = 1 << 20
+)
+
+type streamingResults struct {
+	Results
+
+	nsID      ident.ID
+	fn        QueryStreamFn
+	batchSize int
+	size      int
+	seen      map[uint64]struct{}
+}
+
+// NewStreamingResults returns results that accumulate the series added to
+// them until Flush hands them to the provided function and resets the
+// wrapped results to nsID, so that only a single batch is held in memory at
+// once. Adding series never delivers a batch so that the function is not
+// called while an index block is being searched, callers flush once Full
+// returns true after a block is searched and once all series are added.
+// The hashes of the IDs of delivered series are retained, up to a bound, so
+// that a series matched in more than one block is only delivered and counted
+// once.
+func NewStreamingResults(
+	results Results,
+	nsID ident.ID,
+	batchSize int,
+	fn QueryStreamFn,
+) StreamingResults {
+	if batchSize <= 0 {
+		batchSize = DefaultQueryStreamBatchSize
+	}
+	return &streamingResults{
+		Results:   results,
+		nsID:      nsID,
+		fn:        fn,
+		batchSize: batchSize,
+		seen:      make(map[uint64]struct{}),
+	}
+}
+
+func (r *streamingResults) Add(d doc.Document) (bool, int, error) {
+	// NB: the series may have been delivered in an earlier batch, in which
+	// case it is no longer held by the wrapped results.
+	hash := xxhash.Sum64(d.ID)
+	if _, ok := r.seen[hash]; ok {
+		return false, r.size, nil
+	}
+	added, _, err := r.Results.Add(d)
+	if err != nil {
+		return false, r.size, err
+	}
+	if added {
+		if len(r.seen) < maxStreamingResultsSeen {
+			r.seen[hash] = struct{}{}
+		}
+		r.size++
+	}
+	return added, r.size, nil
+}
+
+func (r *streamingResults) Size() int {
+	return r.size
+}
+
+func (r *streamingResults) Reset(nsID ident.ID) {
+	r.Results.Reset(nsID)
+	r.nsID = nsID
+	r.size = 0
+	for hash := range r.seen {
+		delete(r.seen, hash)
+	}
+}
+
+func (r *streamingResults) Full() bool {
+	return r.Results.Size() >= r.batchSize
+}
+
+func (r *streamingResults) Flush() error {
+	if r.Results.Size() == 0 {
+		return nil
+	}
+	err := r.fn(r.Results)
+	r.Results.Reset(r.nsID)
+	return err
+}
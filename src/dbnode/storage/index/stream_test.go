@@ -0,0 +1,121 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamingResultsBatches(t *testing.T) {
+	var (
+		nsID    = ident.StringID("ns")
+		batches [][]string
+	)
+	res := NewResults(testOpts)
+	res.Reset(nsID)
+	streaming := NewStreamingResults(res, nsID, 2, func(batch Results) error {
+		require.Equal(t, "ns", batch.Namespace().String())
+		var ids []string
+		for _, entry := range batch.Map().Iter() {
+			ids = append(ids, entry.Key().String())
+		}
+		batches = append(batches, ids)
+		return nil
+	})
+
+	// batches are only delivered once flushed, callers flush once full.
+	for i := 0; i < 5; i++ {
+		added, size, err := streaming.Add(doc.Document{ID: []byte(fmt.Sprintf("id%d", i))})
+		require.NoError(t, err)
+		require.True(t, added)
+		require.Equal(t, i+1, size)
+		require.Equal(t, i+1 >= 2, streaming.Full())
+	}
+	require.Len(t, batches, 0)
+	require.NoError(t, streaming.Flush())
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 5)
+	require.Equal(t, 0, res.Size())
+	require.False(t, streaming.Full())
+
+	_, _, err := streaming.Add(doc.Document{ID: []byte("id5")})
+	require.NoError(t, err)
+	require.NoError(t, streaming.Flush())
+	require.Len(t, batches, 2)
+	require.Equal(t, []string{"id5"}, batches[1])
+	require.Equal(t, 6, streaming.Size())
+
+	// flushing with nothing buffered does not deliver an empty batch.
+	require.NoError(t, streaming.Flush())
+	require.Len(t, batches, 2)
+}
+
+func TestStreamingResultsStreamFnError(t *testing.T) {
+	res := NewResults(testOpts)
+	streamErr := errors.New("stream error")
+	streaming := NewStreamingResults(res, nil, 1, func(batch Results) error {
+		return streamErr
+	})
+
+	_, _, err := streaming.Add(doc.Document{ID: []byte("foo")})
+	require.NoError(t, err)
+	require.Equal(t, streamErr, streaming.Flush())
+	require.Equal(t, 0, res.Size())
+}
+
+func TestStreamingResultsDedupesAcrossBatches(t *testing.T) {
+	var (
+		nsID      = ident.StringID("ns")
+		delivered []string
+	)
+	res := NewResults(testOpts)
+	res.Reset(nsID)
+	streaming := NewStreamingResults(res, nsID, 1, func(batch Results) error {
+		for _, entry := range batch.Map().Iter() {
+			delivered = append(delivered, entry.Key().String())
+		}
+		return nil
+	})
+
+	// series matched again in a later block are neither delivered nor
+	// counted a second time.
+	for _, id := range []string{"foo", "bar", "foo", "bar", "baz"} {
+		_, _, err := streaming.Add(doc.Document{ID: []byte(id)})
+		require.NoError(t, err)
+		require.NoError(t, streaming.Flush())
+	}
+	require.NoError(t, streaming.Flush())
+	require.Equal(t, []string{"foo", "bar", "baz"}, delivered)
+	require.Equal(t, 3, streaming.Size())
+
+	streaming.Reset(nsID)
+	added, size, err := streaming.Add(doc.Document{ID: []byte("foo")})
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, 1, size)
+}
@@ -27,6 +27,9 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
@@ -62,12 +65,63 @@ type QueryOptions struct {
 	StartInclusive time.Time
 	EndExclusive   time.Time
 	Limit          int
-}
+	// CountOnly counts the matching series per shard rather than returning
+	// them, the results of the query are then CountResults.
+	CountOnly bool
+	// ShardFn returns the shard of a series, it is used to count the series
+	// matched by count only queries per shard.
+	ShardFn ShardFn
+	// RequestMetadata is opaque metadata, such as a request ID, sent by the
+	// client with the query so nodes include it in their logs and errors.
+	RequestMetadata string
+	// StreamFn, when set, delivers the results of the query incrementally in
+	// batches as blocks are searched rather than accumulating all of them in
+	// the returned results.
+	StreamFn QueryStreamFn
+	// StreamBatchSize is the number of series buffered before a streamed
+	// batch is delivered, a batch may be larger since it is only delivered
+	// once a block has been searched. DefaultQueryStreamBatchSize is used
+	// when it is not set.
+	StreamBatchSize int
+	// SegmentMergePolicy determines which segments of each block are searched,
+	// allowing freshness to be traded for speed on historical queries.
+	SegmentMergePolicy SegmentMergePolicy
+	// MaxResults is the maximum number of series returned, once reached the
+	// results so far are returned as non-exhaustive with a warning.
+	MaxResults int
+	// MaxSeriesMatched is the maximum number of series matched across all
+	// blocks searched, counting a series once for each block it matches in.
+	MaxSeriesMatched int
+	// AdditionalNamespaces are queried along with the namespace of the query,
+	// the results of each carry the namespace they were matched in.
+	AdditionalNamespaces []ident.ID
+}
+
+// QueryStreamFn receives a batch of streamed query results once the blocks
+// that matched them have been searched, it is not called while the index is
+// locked. The batch is reset once the function returns so it must not be
+// retained. Each series is delivered once even when it is matched in more
+// than one block, unless more than a million series are streamed in which
+// case later series matched in more than one block may be delivered again.
+type QueryStreamFn func(batch Results) error
 
 // QueryResults is the collection of results for a query.
 type QueryResults struct {
 	Results    Results
 	Exhaustive bool
+	// Warnings holds the limits exceeded by the query, if any.
+	Warnings []QueryLimitExceeded
+}
+
+// MultiNamespaceQueryResults is the collection of results for a query
+// executed against multiple namespaces.
+type MultiNamespaceQueryResults struct {
+	// Results holds the results for each namespace in the order the
+	// namespaces were requested.
+	Results    []Results
+	Exhaustive bool
+	// Warnings holds the limits exceeded by the query in any namespace.
+	Warnings []QueryLimitExceeded
 }
 
 // Results is a collection of results for a query.
@@ -96,6 +150,31 @@ type Results interface {
 	Add(d doc.Document) (added bool, size int, err error)
 }
 
+// StreamingResults are results that deliver the series added to them in
+// batches rather than accumulating them.
+type StreamingResults interface {
+	Results
+
+	// Full returns whether enough series were added since the last batch was
+	// delivered to deliver a batch.
+	Full() bool
+
+	// Flush delivers any series added since the last batch was delivered.
+	Flush() error
+}
+
+// CountResults are the results of a count only query, they count the series
+// added to them per shard without retaining their IDs or tags.
+type CountResults interface {
+	Results
+
+	// ShardCounts returns the number of series added per shard.
+	ShardCounts() map[uint32]int64
+}
+
+// ShardFn returns the shard a series ID belongs to.
+type ShardFn func(id ident.ID) uint32
+
 // ResultsAllocator allocates Results types.
 type ResultsAllocator func() Results
 
@@ -128,6 +207,13 @@ type OnIndexSeries interface {
 
 // Block represents a collection of segments. Each `Block` is a complete reverse
 // index for a period of time defined by [StartTime, EndTime).
+//
+// Block is also the extension point for alternative index backends, e.g. an
+// external tag index or a trigram index, which can be plugged in by setting
+// a NewBlockFn on the index Options. Implementations must be safe for
+// concurrent use, must only return IDs for documents written to or added to
+// the block and must not retain references to the WriteBatch after WriteBatch
+// returns.
 type Block interface {
 	// StartTime returns the start time of the period this Block indexes.
 	StartTime() time.Time
@@ -148,6 +234,18 @@ type Block interface {
 	// AddResults adds bootstrap results to the block, if c.
 	AddResults(results result.IndexBlock) error
 
+	// ContainsID returns whether the block has indexed the given series ID.
+	ContainsID(id []byte) (bool, error)
+
+	// Cardinality returns the cardinality of each tag name of the block,
+	// keeping the top maxValues values of each tag name.
+	Cardinality(maxValues int) (BlockCardinality, error)
+
+	// Warm reads every term and postings list of the block's bootstrapped
+	// and flushed segments so that their pages are resident, without
+	// blocking writes to the block.
+	Warm() (WarmResult, error)
+
 	// Tick does internal house keeping operations.
 	Tick(c context.Cancellable, tickStart time.Time) (BlockTickResult, error)
 
@@ -174,6 +272,14 @@ type Block interface {
 	Close() error
 }
 
+// NewBlockFn returns a new Block for the period starting at blockStart, the
+// default implementation is NewBlock which is backed by in memory and FST segments.
+type NewBlockFn func(
+	blockStart time.Time,
+	md namespace.Metadata,
+	opts Options,
+) (Block, error)
+
 // EvictMutableSegmentResults returns statistics about the EvictMutableSegments execution.
 type EvictMutableSegmentResults struct {
 	NumMutableSegments int64
@@ -246,6 +352,49 @@ func (b *WriteBatch) Append(
 	b.docs = append(b.docs, doc)
 }
 
+// WriteBatchSeries is a series to be indexed along with the entry tracking
+// its indexing, its document is converted from its ID and tags.
+type WriteBatchSeries struct {
+	Entry WriteBatchEntry
+	// ID and Tags are referenced by the converted document rather than
+	// copied, so they must remain valid until the entry is finalized.
+	ID   ident.ID
+	Tags ident.Tags
+}
+
+// NewSeriesWriteBatch creates a new write batch with the documents converted
+// from the provided series, allocating the fields of every document at once
+// rather than once per series.
+func NewSeriesWriteBatch(
+	opts WriteBatchOptions,
+	series []WriteBatchSeries,
+) *WriteBatch {
+	if opts.InitialCapacity < len(series) {
+		opts.InitialCapacity = len(series)
+	}
+	b := NewWriteBatch(opts)
+
+	var numFields int
+	for i := range series {
+		numFields += len(series[i].Tags.Values())
+	}
+	fields := make(doc.Fields, 0, numFields)
+	for i := range series {
+		start := len(fields)
+		for _, tag := range series[i].Tags.Values() {
+			fields = append(fields, doc.Field{
+				Name:  tag.Name.Bytes(),
+				Value: tag.Value.Bytes(),
+			})
+		}
+		b.Append(series[i].Entry, doc.Document{
+			ID:     series[i].ID.Bytes(),
+			Fields: fields[start:len(fields):len(fields)],
+		})
+	}
+	return b
+}
+
 // ForEachWriteBatchEntryFn allows a caller to perform an operation for each
 // batch entry.
 type ForEachWriteBatchEntryFn func(
@@ -413,6 +562,17 @@ func (b *WriteBatch) MarkUnmarkedEntriesSuccess() {
 	}
 }
 
+// MarkUnmarkedEntrySuccess marks an unmarked entry at index as success.
+func (b *WriteBatch) MarkUnmarkedEntrySuccess(idx int) {
+	if b.entries[idx].OnIndexSeries != nil {
+		blockStart := b.entries[idx].indexBlockStart(b.opts.IndexBlockSize)
+		b.entries[idx].OnIndexSeries.OnIndexSuccess(blockStart)
+		b.entries[idx].OnIndexSeries.OnIndexFinalize(blockStart)
+		b.entries[idx].OnIndexSeries = nil
+		b.entries[idx].result = WriteBatchEntryResult{Err: nil}
+	}
+}
+
 // MarkUnmarkedEntriesError marks all unmarked entries as error.
 func (b *WriteBatch) MarkUnmarkedEntriesError(err error) {
 	for idx := range b.entries {
@@ -483,6 +643,10 @@ type WriteBatchEntry struct {
 	// EnqueuedAt is the timestamp that this entry was enqueued for indexing
 	// so that we can calculate the latency it takes to index the entry
 	EnqueuedAt time.Time
+	// Repair is set when the entry reindexes a series with data that is
+	// missing from the index, the timestamp is not checked against the
+	// buffer past and future since it is not the time of a write
+	Repair bool
 	// enqueuedIdx is the idx of the entry when originally enqueued by the call
 	// to append on the write batch
 	enqueuedIdx int
@@ -517,6 +681,14 @@ type Options interface {
 	// IndexInsertMode returns the index's insert mode (sync/async).
 	InsertMode() InsertMode
 
+	// SetInsertSyncTimeout sets how long a synchronous insert waits for its
+	// batch to be indexed before giving up, zero waits indefinitely.
+	SetInsertSyncTimeout(value time.Duration) Options
+
+	// InsertSyncTimeout returns how long a synchronous insert waits for its
+	// batch to be indexed before giving up, zero waits indefinitely.
+	InsertSyncTimeout() time.Duration
+
 	// SetClockOptions sets the clock options.
 	SetClockOptions(value clock.Options) Options
 
@@ -552,4 +724,130 @@ type Options interface {
 
 	// ResultsPool returns the results pool.
 	ResultsPool() ResultsPool
+
+	// SetNewBlockFn sets the function used to create new index blocks.
+	SetNewBlockFn(value NewBlockFn) Options
+
+	// NewBlockFn returns the function used to create new index blocks.
+	NewBlockFn() NewBlockFn
+
+	// SetNumericTagNames sets the tag names whose values are indexed as numbers
+	// and which support numeric range queries.
+	SetNumericTagNames(value []string) Options
+
+	// NumericTagNames returns the tag names whose values are indexed as numbers
+	// and which support numeric range queries.
+	NumericTagNames() []string
+
+	// SetRecentlyIndexedWindow sets how long IDs are remembered as indexed
+	// for a block start so repeated inserts skip the insert queue, zero
+	// disables skipping repeated inserts.
+	SetRecentlyIndexedWindow(value time.Duration) Options
+
+	// RecentlyIndexedWindow returns how long IDs are remembered as indexed
+	// for a block start so repeated inserts skip the insert queue.
+	RecentlyIndexedWindow() time.Duration
+
+	// SetCardinalityReportInterval sets how often the cardinality of each tag
+	// name is computed for each block in the background, zero disables it.
+	SetCardinalityReportInterval(value time.Duration) Options
+
+	// CardinalityReportInterval returns how often the cardinality of each tag
+	// name is computed for each block in the background.
+	CardinalityReportInterval() time.Duration
+
+	// SetCardinalityReportMaxValues sets the number of top values retained
+	// for each tag name when computing cardinality.
+	SetCardinalityReportMaxValues(value int) Options
+
+	// CardinalityReportMaxValues returns the number of top values retained
+	// for each tag name when computing cardinality.
+	CardinalityReportMaxValues() int
+
+	// SetTagDictionaryMaxEntries sets the maximum number of tag names and
+	// values held by each namespace's shared tag dictionary, zero disables it.
+	SetTagDictionaryMaxEntries(value int) Options
+
+	// TagDictionaryMaxEntries returns the maximum number of tag names and
+	// values held by each namespace's shared tag dictionary.
+	TagDictionaryMaxEntries() int
+
+	// SetTagDictionary sets the shared tag dictionary of the namespace the
+	// options belong to, set by the namespace when the dictionary is enabled.
+	SetTagDictionary(value convert.Dictionary) Options
+
+	// TagDictionary returns the shared tag dictionary of the namespace the
+	// options belong to, nil if disabled.
+	TagDictionary() convert.Dictionary
+
+	// SetWarmupEnabled sets whether the index blocks are warmed in the
+	// background once the index has bootstrapped.
+	SetWarmupEnabled(value bool) Options
+
+	// WarmupEnabled returns whether the index blocks are warmed in the
+	// background once the index has bootstrapped.
+	WarmupEnabled() bool
+
+	// SetWarmupQueries sets the queries run when warming the index, such as
+	// the most common dashboard queries.
+	SetWarmupQueries(value []Query) Options
+
+	// WarmupQueries returns the queries run when warming the index.
+	WarmupQueries() []Query
+
+	// SetReservedFieldNames sets additional field names series are not
+	// allowed to use, beyond the field name reserved for the series ID.
+	SetReservedFieldNames(value []string) Options
+
+	// ReservedFieldNames returns the additional field names series are not
+	// allowed to use.
+	ReservedFieldNames() []string
+
+	// SetFieldValidators sets the validators of the values of fields by
+	// field name, series with a value rejected by a validator are not indexed.
+	SetFieldValidators(value map[string]FieldValidator) Options
+
+	// FieldValidators returns the validators of the values of fields by
+	// field name.
+	FieldValidators() map[string]FieldValidator
+
+	// SetAnalyzer sets the analyzer that derives additional terms to index
+	// tag values by, if nil tag values are only indexed exactly.
+	SetAnalyzer(value analysis.Analyzer) Options
+
+	// Analyzer returns the analyzer that derives additional terms to index
+	// tag values by, if nil tag values are only indexed exactly.
+	Analyzer() analysis.Analyzer
+
+	// SetFullScanMaxSeries sets the number of series below which a namespace
+	// with indexing disabled is queried by scanning its series, disabled if
+	// zero.
+	SetFullScanMaxSeries(value int) Options
+
+	// FullScanMaxSeries returns the number of series below which a namespace
+	// with indexing disabled is queried by scanning its series, disabled if
+	// zero.
+	FullScanMaxSeries() int
+
+	// SetQueryCacheSize sets the maximum number of query results cached by
+	// each namespace's index, zero disables the query cache.
+	SetQueryCacheSize(value int) Options
+
+	// QueryCacheSize returns the maximum number of query results cached by
+	// each namespace's index.
+	QueryCacheSize() int
+
+	// SetQueryCacheTTL sets how long query results are cached.
+	SetQueryCacheTTL(value time.Duration) Options
+
+	// QueryCacheTTL returns how long query results are cached.
+	QueryCacheTTL() time.Duration
+
+	// SetPostingsListCache sets the cache of the postings lists matched by
+	// the immutable segments of index blocks, nil disables the cache.
+	SetPostingsListCache(value *PostingsListCache) Options
+
+	// PostingsListCache returns the cache of the postings lists matched by
+	// the immutable segments of index blocks, nil if disabled.
+	PostingsListCache() *PostingsListCache
 }
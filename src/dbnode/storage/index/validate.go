@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3x/ident"
+)
+
+// FieldValidator validates the value of a field of a document, returning an
+// error if the value is not allowed.
+type FieldValidator func(value []byte) error
+
+// ReservedFieldError is the error returned when a series uses a field name
+// reserved by the index options.
+type ReservedFieldError struct {
+	Name string
+}
+
+func (e ReservedFieldError) Error() string {
+	return fmt.Sprintf("unable to index series using reserved field name: %s", e.Name)
+}
+
+// InvalidFieldError is the error returned when the value of a field of a
+// series is rejected by the field's validator.
+type InvalidFieldError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e InvalidFieldError) Error() string {
+	return fmt.Sprintf("unable to index series with invalid value for field %s: %s: %v",
+		e.Name, e.Value, e.Err)
+}
+
+// ValidateFields validates the tags of a series against the reserved field
+// names and field validators of the index options, returning either a
+// ReservedFieldError or an InvalidFieldError. The tag iterator is consumed.
+func ValidateFields(opts Options, tags ident.TagIterator) error {
+	var (
+		reserved   = opts.ReservedFieldNames()
+		validators = opts.FieldValidators()
+	)
+	if len(reserved) == 0 && len(validators) == 0 {
+		return nil
+	}
+
+	for tags.Next() {
+		tag := tags.Current()
+		name := tag.Name.Bytes()
+		for _, r := range reserved {
+			if r == string(name) {
+				return ReservedFieldError{Name: r}
+			}
+		}
+		validator, ok := validators[string(name)]
+		if !ok {
+			continue
+		}
+		value := tag.Value.Bytes()
+		if err := validator(value); err != nil {
+			return InvalidFieldError{Name: string(name), Value: string(value), Err: err}
+		}
+	}
+	return tags.Err()
+}
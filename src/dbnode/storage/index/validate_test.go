@@ -0,0 +1,62 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFields(t *testing.T) {
+	opts := NewOptions().
+		SetReservedFieldNames([]string{"__internal__"}).
+		SetFieldValidators(map[string]FieldValidator{
+			"env": func(value []byte) error {
+				if string(value) != "prod" && string(value) != "dev" {
+					return errors.New("unknown env")
+				}
+				return nil
+			},
+		})
+
+	tags := ident.NewTags(ident.StringTag("env", "prod"), ident.StringTag("app", "foo"))
+	require.NoError(t, ValidateFields(opts, ident.NewTagsIterator(tags)))
+
+	tags = ident.NewTags(ident.StringTag("__internal__", "x"))
+	err := ValidateFields(opts, ident.NewTagsIterator(tags))
+	require.Error(t, err)
+	assert.Equal(t, ReservedFieldError{Name: "__internal__"}, err)
+
+	tags = ident.NewTags(ident.StringTag("app", "foo"), ident.StringTag("env", "staging"))
+	err = ValidateFields(opts, ident.NewTagsIterator(tags))
+	require.Error(t, err)
+	invalid, ok := err.(InvalidFieldError)
+	require.True(t, ok)
+	assert.Equal(t, "env", invalid.Name)
+	assert.Equal(t, "staging", invalid.Value)
+
+	require.NoError(t, ValidateFields(NewOptions(), ident.NewTagsIterator(tags)))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"github.com/m3db/m3/src/m3ninx/analysis"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+)
+
+// WarmResult is the result of warming the segments of a block.
+type WarmResult struct {
+	NumSegments int64
+	NumTerms    int64
+}
+
+// warmSegment reads every field, term and postings list of a segment so
+// the pages backing an mmap'd segment are resident before it is queried.
+func warmSegment(seg segment.Segment) (int64, error) {
+	reader, err := seg.Reader()
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	segFields, err := seg.Fields()
+	if err != nil {
+		return 0, err
+	}
+	// NB: only the fields documents were indexed with are warmed, the n-grams
+	// of analyzed fields far outnumber them.
+	fields := analysis.NewFieldsIterator(segFields)
+	defer fields.Close()
+
+	var numTerms int64
+	for fields.Next() {
+		field := fields.Current()
+		terms, err := seg.Terms(field)
+		if err != nil {
+			return numTerms, err
+		}
+
+		for terms.Next() {
+			pl, err := reader.MatchTerm(field, terms.Current())
+			if err != nil {
+				terms.Close()
+				return numTerms, err
+			}
+			// NB: the length forces the postings list to be read in full.
+			pl.Len()
+			numTerms++
+		}
+		if err := terms.Err(); err != nil {
+			terms.Close()
+			return numTerms, err
+		}
+		if err := terms.Close(); err != nil {
+			return numTerms, err
+		}
+	}
+
+	return numTerms, fields.Err()
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmSegment(t *testing.T) {
+	seg, err := mem.NewSegment(0, testOpts.MemSegmentOptions())
+	require.NoError(t, err)
+	for _, d := range []doc.Document{
+		newTestCardinalityDoc("a0", "app", "a", "host", "host0"),
+		newTestCardinalityDoc("a1", "app", "a", "host", "host1"),
+		newTestCardinalityDoc("b0", "app", "b", "host", "host2"),
+	} {
+		_, err := seg.Insert(d)
+		require.NoError(t, err)
+	}
+	sealed, err := seg.Seal()
+	require.NoError(t, err)
+	defer sealed.Close()
+
+	// NB: the ID field is indexed as well, so 2 apps, 3 hosts and 3 IDs.
+	numTerms, err := warmSegment(sealed)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), numTerms)
+}
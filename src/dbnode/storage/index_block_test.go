@@ -134,6 +134,29 @@ func TestNamespaceIndexNewBlockFn(t *testing.T) {
 	require.Equal(t, mockBlock, blk)
 }
 
+func TestNamespaceIndexNewBlockFnFromOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+	now := time.Now().Truncate(blockSize).Add(2 * time.Minute)
+	nowFn := func() time.Time { return now }
+	opts := testDatabaseOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+
+	mockBlock := index.NewMockBlock(ctrl)
+	newBlockFn := func(ts time.Time, md namespace.Metadata, io index.Options) (index.Block, error) {
+		require.Equal(t, now.Truncate(blockSize), ts)
+		return mockBlock, nil
+	}
+	opts = opts.SetIndexOptions(opts.IndexOptions().SetNewBlockFn(newBlockFn))
+
+	md := testNamespaceMetadata(blockSize, 4*time.Hour)
+	idx, err := newNamespaceIndex(md, opts)
+	require.NoError(t, err)
+	require.Equal(t, mockBlock, idx.(*nsIndex).state.latestBlock)
+}
+
 func TestNamespaceIndexNewBlockFnRandomErr(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -176,6 +199,7 @@ func TestNamespaceIndexWrite(t *testing.T) {
 	tag := ident.StringTag("name", "value")
 	tags := ident.NewTags(tag)
 	lifecycle := index.NewMockOnIndexSeries(ctrl)
+	mockBlock.EXPECT().ContainsID(id.Bytes()).Return(false, nil)
 	mockBlock.EXPECT().
 		WriteBatch(gomock.Any()).
 		Return(index.WriteBatchResult{}, nil).
@@ -198,6 +222,44 @@ func TestNamespaceIndexWrite(t *testing.T) {
 	require.NoError(t, idx.WriteBatch(batch))
 }
 
+func TestNamespaceIndexWriteSkipsAlreadyIndexed(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+	now := time.Now().Truncate(blockSize).Add(2 * time.Minute)
+	blockStart := now.Truncate(blockSize)
+	nowFn := func() time.Time { return now }
+	opts := testDatabaseOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+
+	mockBlock := index.NewMockBlock(ctrl)
+	mockBlock.EXPECT().StartTime().Return(blockStart).AnyTimes()
+	newBlockFn := func(ts time.Time, md namespace.Metadata, io index.Options) (index.Block, error) {
+		return mockBlock, nil
+	}
+	md := testNamespaceMetadata(blockSize, 4*time.Hour)
+	idx, err := newNamespaceIndexWithNewBlockFn(md, newBlockFn, opts)
+	require.NoError(t, err)
+
+	id := ident.StringID("foo")
+	tags := ident.NewTags(ident.StringTag("name", "value"))
+
+	// The block already contains the ID so the insert is never queued, and
+	// the following insert is skipped without checking the block again.
+	mockBlock.EXPECT().ContainsID(id.Bytes()).Return(true, nil).Times(1)
+	for n := 0; n < 2; n++ {
+		lifecycle := index.NewMockOnIndexSeries(ctrl)
+		lifecycle.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		lifecycle.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+
+		entry, doc := testWriteBatchEntry(id, tags, now, lifecycle)
+		batch := testWriteBatch(entry, doc, testWriteBatchBlockSizeOption(blockSize))
+		require.NoError(t, idx.WriteBatch(batch))
+		require.Equal(t, 0, len(batch.PendingEntries()))
+	}
+}
+
 func TestNamespaceIndexWriteCreatesBlock(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -236,6 +298,7 @@ func TestNamespaceIndexWriteCreatesBlock(t *testing.T) {
 	tag := ident.StringTag("name", "value")
 	tags := ident.NewTags(tag)
 	lifecycle := index.NewMockOnIndexSeries(ctrl)
+	b1.EXPECT().ContainsID(id.Bytes()).Return(false, nil).AnyTimes()
 	b1.EXPECT().
 		WriteBatch(gomock.Any()).
 		Return(index.WriteBatchResult{}, nil).
@@ -509,4 +572,16 @@ func TestNamespaceIndexBlockQuery(t *testing.T) {
 	b0.EXPECT().Query(q, qOpts, gomock.Any()).Return(false, nil)
 	_, err = idx.Query(ctx, q, qOpts)
 	require.NoError(t, err)
+
+	// skips blocks out of retention that are yet to be evicted
+	nowLock.Lock()
+	now = now.Add(3 * blockSize)
+	nowLock.Unlock()
+	qOpts = index.QueryOptions{
+		StartInclusive: t0,
+		EndExclusive:   t2.Add(time.Minute),
+	}
+	b1.EXPECT().Query(q, qOpts, gomock.Any()).Return(true, nil)
+	_, err = idx.Query(ctx, q, qOpts)
+	require.NoError(t, err)
 }
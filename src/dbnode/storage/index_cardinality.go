@@ -0,0 +1,153 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/uber-go/tally"
+)
+
+// indexCardinalityReporter computes the cardinality of each tag name of
+// each index block in the background, so reports can be served without
+// scanning the index on request.
+type indexCardinalityReporter struct {
+	sync.RWMutex
+
+	interval  time.Duration
+	maxValues int
+	blocksFn  func() []index.Block
+	nowFn     func() time.Time
+	newTicker clock.NewTickerFn
+	logger    xlog.Logger
+	metrics   indexCardinalityReporterMetrics
+
+	report    []index.BlockCardinality
+	reportAt  time.Time
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+}
+
+type indexCardinalityReporterMetrics struct {
+	reports tally.Counter
+	errors  tally.Counter
+	latency tally.Timer
+}
+
+func newIndexCardinalityReporter(
+	interval time.Duration,
+	maxValues int,
+	blocksFn func() []index.Block,
+	nowFn func() time.Time,
+	newTicker clock.NewTickerFn,
+	logger xlog.Logger,
+	scope tally.Scope,
+) *indexCardinalityReporter {
+	scope = scope.SubScope("cardinality-report")
+	return &indexCardinalityReporter{
+		interval:  interval,
+		maxValues: maxValues,
+		blocksFn:  blocksFn,
+		nowFn:     nowFn,
+		newTicker: newTicker,
+		logger:    logger,
+		metrics: indexCardinalityReporterMetrics{
+			reports: scope.Counter("reports"),
+			errors:  scope.Counter("errors"),
+			latency: scope.Timer("latency"),
+		},
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+func (r *indexCardinalityReporter) Start() {
+	go r.run()
+}
+
+func (r *indexCardinalityReporter) run() {
+	defer close(r.doneCh)
+
+	ticker := r.newTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closeCh:
+			return
+		case <-ticker.C():
+			r.compute()
+		}
+	}
+}
+
+// compute computes the cardinality of every block, in reverse chronological
+// order, and replaces the current report.
+func (r *indexCardinalityReporter) compute() {
+	start := r.nowFn()
+	blocks := r.blocksFn()
+	report := make([]index.BlockCardinality, 0, len(blocks))
+	for _, block := range blocks {
+		select {
+		case <-r.closeCh:
+			return
+		default:
+		}
+
+		blockReport, err := block.Cardinality(r.maxValues)
+		if err != nil {
+			// NB: the block may have been closed since taking the snapshot.
+			r.metrics.errors.Inc(1)
+			r.logger.Errorf("unable to compute index block cardinality for block %v: %v",
+				block.StartTime(), err)
+			continue
+		}
+		report = append(report, blockReport)
+	}
+
+	now := r.nowFn()
+	r.Lock()
+	r.report = report
+	r.reportAt = now
+	r.Unlock()
+
+	r.metrics.reports.Inc(1)
+	r.metrics.latency.Record(now.Sub(start))
+}
+
+// Report returns the last computed report and when it was computed.
+func (r *indexCardinalityReporter) Report() ([]index.BlockCardinality, time.Time) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.report, r.reportAt
+}
+
+func (r *indexCardinalityReporter) Close() {
+	r.closeOnce.Do(func() {
+		close(r.closeCh)
+	})
+	<-r.doneCh
+}
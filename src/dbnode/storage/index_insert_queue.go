@@ -35,6 +35,7 @@ var (
 	errIndexInsertQueueNotOpen             = errors.New("index insert queue is not open")
 	errIndexInsertQueueAlreadyOpenOrClosed = errors.New("index insert queue already open or is closed")
 	errNewSeriesIndexRateLimitExceeded     = errors.New("indexing new series exceeds rate limit")
+	errIndexInsertTimeout                  = errors.New("timed out waiting for index insert to complete")
 )
 
 type nsIndexInsertQueueState int
@@ -137,10 +138,31 @@ func (q *nsIndexInsertQueue) insertLoop() {
 			q.Unlock()
 		}
 
+		var err error
 		if len(batch.inserts) > 0 {
-			q.indexBatchFn(batch.inserts)
+			start := q.nowFn()
+			err = q.indexBatchFn(batch.inserts)
+			end := q.nowFn()
+
+			q.metrics.batchSize.RecordValue(float64(batch.numEntries))
+			q.metrics.flushDuration.RecordDuration(end.Sub(start))
+			for _, enqueuedAt := range batch.enqueuedAt {
+				q.metrics.enqueueLatency.RecordDuration(end.Sub(enqueuedAt))
+			}
+			if err != nil {
+				// The whole batch failed without marking its entries.
+				q.metrics.numDropped.Inc(int64(batch.numEntries))
+			} else {
+				var numErrs int
+				for _, inserts := range batch.inserts {
+					numErrs += inserts.NumErrs()
+				}
+				if numErrs > 0 {
+					q.metrics.numDropped.Inc(int64(numErrs))
+				}
+			}
 		}
-		batch.wg.Done()
+		batch.future.complete(err)
 
 		// Set the free batch
 		batch.Reset()
@@ -156,12 +178,15 @@ func (q *nsIndexInsertQueue) insertLoop() {
 
 func (q *nsIndexInsertQueue) InsertBatch(
 	batch *index.WriteBatch,
-) (*sync.WaitGroup, error) {
-	windowNanos := q.nowFn().Truncate(time.Second).UnixNano()
+) (*indexInsertFuture, error) {
+	now := q.nowFn()
+	windowNanos := now.Truncate(time.Second).UnixNano()
+	batchLen := batch.Len()
 
 	q.Lock()
 	if q.state != nsIndexInsertQueueStateOpen {
 		q.Unlock()
+		q.metrics.numRejectedNotOpen.Inc(int64(batchLen))
 		return nil, errIndexInsertQueueNotOpen
 	}
 	if limit := q.indexPerSecondLimit; limit > 0 {
@@ -173,12 +198,14 @@ func (q *nsIndexInsertQueue) InsertBatch(
 		q.indexPerSecondLimitWindowValues++
 		if q.indexPerSecondLimitWindowValues > limit {
 			q.Unlock()
+			q.metrics.numRejectedRateLimit.Inc(int64(batchLen))
 			return nil, errNewSeriesIndexRateLimitExceeded
 		}
 	}
-	batchLen := batch.Len()
 	q.currBatch.inserts = append(q.currBatch.inserts, batch)
-	wg := q.currBatch.wg
+	q.currBatch.enqueuedAt = append(q.currBatch.enqueuedAt, now)
+	q.currBatch.numEntries += batchLen
+	future := q.currBatch.future
 	q.Unlock()
 
 	// Notify insert loop
@@ -189,7 +216,7 @@ func (q *nsIndexInsertQueue) InsertBatch(
 	}
 
 	q.metrics.numPending.Inc(int64(batchLen))
-	return wg, nil
+	return future, nil
 }
 
 func (q *nsIndexInsertQueue) Start() error {
@@ -229,33 +256,104 @@ func (q *nsIndexInsertQueue) Stop() error {
 	return nil
 }
 
-type nsIndexInsertBatchFn func(inserts []*index.WriteBatch)
+type nsIndexInsertBatchFn func(inserts []*index.WriteBatch) error
 
 type nsIndexInsertBatch struct {
-	wg      *sync.WaitGroup
-	inserts []*index.WriteBatch
+	future     *indexInsertFuture
+	inserts    []*index.WriteBatch
+	enqueuedAt []time.Time
+	numEntries int
 }
 
 func (b *nsIndexInsertBatch) Reset() {
-	b.wg = &sync.WaitGroup{}
 	// We always expect to be waiting for an index
-	b.wg.Add(1)
+	b.future = newIndexInsertFuture()
 	for i := range b.inserts {
 		// TODO(prateek): if we start pooling `[]index.WriteBatchEntry`, then we could return to the pool here.
 		b.inserts[i] = nil
 	}
 	b.inserts = b.inserts[:0]
+	b.enqueuedAt = b.enqueuedAt[:0]
+	b.numEntries = 0
+}
+
+// indexInsertFuture completes once the batch of inserts it was handed out
+// for has been indexed.
+type indexInsertFuture struct {
+	doneCh chan struct{}
+	err    error
+}
+
+func newIndexInsertFuture() *indexInsertFuture {
+	return &indexInsertFuture{doneCh: make(chan struct{})}
+}
+
+func (f *indexInsertFuture) complete(err error) {
+	f.err = err
+	close(f.doneCh)
+}
+
+// Done returns a channel that is closed once the batch has been indexed.
+func (f *indexInsertFuture) Done() <-chan struct{} {
+	return f.doneCh
+}
+
+// Err returns the error indexing the batch, only valid once Done is closed.
+func (f *indexInsertFuture) Err() error {
+	return f.err
+}
+
+// Wait blocks until the batch has been indexed and returns the batch error.
+func (f *indexInsertFuture) Wait() error {
+	<-f.doneCh
+	return f.err
+}
+
+// WaitTimeout is the same as Wait but gives up after the timeout elapses,
+// a non-positive timeout waits indefinitely.
+func (f *indexInsertFuture) WaitTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return f.Wait()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-f.doneCh:
+		return f.err
+	case <-timer.C:
+		return errIndexInsertTimeout
+	}
 }
 
 type nsIndexInsertQueueMetrics struct {
-	numPending tally.Counter
+	numPending           tally.Counter
+	numDropped           tally.Counter
+	numRejectedNotOpen   tally.Counter
+	numRejectedRateLimit tally.Counter
+	batchSize            tally.Histogram
+	enqueueLatency       tally.Histogram
+	flushDuration        tally.Histogram
 }
 
 func newNamespaceIndexInsertQueueMetrics(
 	scope tally.Scope,
 ) nsIndexInsertQueueMetrics {
 	subScope := scope.SubScope("index-queue")
+	durationBuckets := tally.MustMakeExponentialDurationBuckets(100*time.Microsecond, 2, 16)
 	return nsIndexInsertQueueMetrics{
 		numPending: subScope.Counter("num-pending"),
+		numDropped: subScope.Counter("num-dropped"),
+		numRejectedNotOpen: subScope.Tagged(map[string]string{
+			"reason": "not-open",
+		}).Counter("num-rejected"),
+		numRejectedRateLimit: subScope.Tagged(map[string]string{
+			"reason": "rate-limit",
+		}).Counter("num-rejected"),
+		batchSize: subScope.Histogram("batch-size",
+			tally.MustMakeExponentialValueBuckets(1, 2, 16)),
+		enqueueLatency: subScope.Histogram("enqueue-latency", durationBuckets),
+		flushDuration:  subScope.Histogram("flush-duration", durationBuckets),
 	}
 }
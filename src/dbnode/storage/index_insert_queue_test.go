@@ -21,12 +21,14 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	m3dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3x/ident"
 
@@ -39,7 +41,7 @@ import (
 
 func newTestIndexInsertQueue() *nsIndexInsertQueue {
 	var (
-		nsIndexInsertBatchFn = func(inserts []*index.WriteBatch) {}
+		nsIndexInsertBatchFn = func(inserts []*index.WriteBatch) error { return nil }
 		nowFn                = time.Now
 		scope                = tally.NoopScope
 	)
@@ -86,10 +88,11 @@ func TestIndexInsertQueueCallback(t *testing.T) {
 		insertedBatches []*index.WriteBatch
 		callback        = index.NewMockOnIndexSeries(ctrl)
 	)
-	q.indexBatchFn = func(inserts []*index.WriteBatch) {
+	q.indexBatchFn = func(inserts []*index.WriteBatch) error {
 		insertLock.Lock()
 		insertedBatches = append(insertedBatches, inserts...)
 		insertLock.Unlock()
+		return nil
 	}
 
 	assert.NoError(t, q.Start())
@@ -98,9 +101,9 @@ func TestIndexInsertQueueCallback(t *testing.T) {
 	now := time.Now()
 	batch := index.NewWriteBatch(index.WriteBatchOptions{})
 	batch.Append(testWriteBatchEntry(testID(1), testTags(1), now, callback))
-	wg, err := q.InsertBatch(batch)
+	future, err := q.InsertBatch(batch)
 	assert.NoError(t, err)
-	wg.Wait()
+	assert.NoError(t, future.Wait())
 
 	insertLock.Lock()
 	defer insertLock.Unlock()
@@ -213,10 +216,11 @@ func TestIndexInsertQueueBatchBackoff(t *testing.T) {
 		defer timeLock.Unlock()
 		return currTime
 	}
-	q.indexBatchFn = func(value []*index.WriteBatch) {
+	q.indexBatchFn = func(value []*index.WriteBatch) error {
 		inserts = append(inserts, value)
 		insertWgs[len(inserts)-1].Done()
 		insertProgressWgs[len(inserts)-1].Wait()
+		return nil
 	}
 
 	q.indexBatchBackoff = backoff
@@ -291,8 +295,9 @@ func TestIndexInsertQueueFlushedOnClose(t *testing.T) {
 		currTime          = time.Now().Truncate(time.Second)
 	)
 
-	q := newNamespaceIndexInsertQueue(func(value []*index.WriteBatch) {
+	q := newNamespaceIndexInsertQueue(func(value []*index.WriteBatch) error {
 		atomic.AddInt64(&numInsertObserved, int64(len(value)))
+		return nil
 	}, func() time.Time { return currTime }, tally.NoopScope)
 
 	require.NoError(t, q.Start())
@@ -306,3 +311,112 @@ func TestIndexInsertQueueFlushedOnClose(t *testing.T) {
 	require.NoError(t, q.Stop())
 	require.Equal(t, int64(numInsertExpected), atomic.LoadInt64(&numInsertObserved))
 }
+
+func TestIndexInsertQueueFutureReturnsBatchError(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	var (
+		q        = newTestIndexInsertQueue()
+		batchErr = errors.New("batch error")
+	)
+	q.indexBatchFn = func(inserts []*index.WriteBatch) error {
+		return batchErr
+	}
+
+	require.NoError(t, q.Start())
+	defer q.Stop()
+
+	future, err := q.InsertBatch(testWriteBatch(testWriteBatchEntry(testID(1),
+		testTags(1), time.Now(), nil)))
+	require.NoError(t, err)
+
+	select {
+	case <-future.Done():
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for future")
+	}
+	require.Equal(t, batchErr, future.Err())
+	require.Equal(t, batchErr, future.Wait())
+}
+
+func TestIndexInsertQueueFutureWaitTimeout(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	var (
+		q       = newTestIndexInsertQueue()
+		release = make(chan struct{})
+	)
+	q.indexBatchFn = func(inserts []*index.WriteBatch) error {
+		<-release
+		return nil
+	}
+
+	require.NoError(t, q.Start())
+	defer q.Stop()
+
+	future, err := q.InsertBatch(testWriteBatch(testWriteBatchEntry(testID(1),
+		testTags(1), time.Now(), nil)))
+	require.NoError(t, err)
+	require.Equal(t, errIndexInsertTimeout, future.WaitTimeout(10*time.Millisecond))
+
+	close(release)
+	require.NoError(t, future.WaitTimeout(time.Second))
+}
+
+func TestIndexInsertQueueMetricsDroppedAndRejected(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	scope := tally.NewTestScope("", nil)
+	q := newNamespaceIndexInsertQueue(func(inserts []*index.WriteBatch) error {
+		return errors.New("batch error")
+	}, time.Now, scope).(*nsIndexInsertQueue)
+
+	_, err := q.InsertBatch(testWriteBatch(testWriteBatchEntry(testID(1),
+		testTags(1), time.Now(), nil)))
+	require.Equal(t, errIndexInsertQueueNotOpen, err)
+
+	require.NoError(t, q.Start())
+	batch := testWriteBatch(testWriteBatchEntry(testID(1),
+		testTags(1), time.Now(), nil))
+	batch.Append(testWriteBatchEntry(testID(2), testTags(2), time.Now(), nil))
+	future, err := q.InsertBatch(batch)
+	require.NoError(t, err)
+	require.Error(t, future.Wait())
+	require.NoError(t, q.Stop())
+
+	counters := scope.Snapshot().Counters()
+	rejected := counters["insert-queue.index-queue.num-rejected+reason=not-open"]
+	require.NotNil(t, rejected)
+	assert.Equal(t, int64(1), rejected.Value())
+	dropped := counters["insert-queue.index-queue.num-dropped+"]
+	require.NotNil(t, dropped)
+	assert.Equal(t, int64(2), dropped.Value())
+}
+
+func TestIndexInsertQueueMetricsDroppedEntries(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scope := tally.NewTestScope("", nil)
+	q := newNamespaceIndexInsertQueue(func(inserts []*index.WriteBatch) error {
+		inserts[0].MarkUnmarkedEntryError(m3dberrors.ErrTooPast, 0)
+		return nil
+	}, time.Now, scope).(*nsIndexInsertQueue)
+	require.NoError(t, q.Start())
+
+	lifecycle := index.NewMockOnIndexSeries(ctrl)
+	lifecycle.EXPECT().OnIndexFinalize(gomock.Any())
+	batch := testWriteBatch(testWriteBatchEntry(testID(1),
+		testTags(1), time.Now(), lifecycle))
+	batch.Append(testWriteBatchEntry(testID(2), testTags(2), time.Now(), nil))
+	future, err := q.InsertBatch(batch)
+	require.NoError(t, err)
+	require.NoError(t, future.Wait())
+	require.NoError(t, q.Stop())
+
+	dropped := scope.Snapshot().Counters()["insert-queue.index-queue.num-dropped+"]
+	require.NotNil(t, dropped)
+	assert.Equal(t, int64(1), dropped.Value())
+}
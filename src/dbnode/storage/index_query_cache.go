@@ -0,0 +1,226 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
+)
+
+// indexQueryCacheKey identifies the results of a query over a range of
+// index blocks, the results do not depend on the exact query time range
+// since blocks are queried as a whole. Every query option that changes the
+// results must be part of the key.
+type indexQueryCacheKey struct {
+	query              string
+	blockStart         xtime.UnixNano
+	blockEnd           xtime.UnixNano
+	limit              int
+	countOnly          bool
+	segmentMergePolicy index.SegmentMergePolicy
+}
+
+func newIndexQueryCacheKey(
+	query index.Query,
+	opts index.QueryOptions,
+	blockSize time.Duration,
+) indexQueryCacheKey {
+	blockEnd := opts.EndExclusive.Truncate(blockSize)
+	if blockEnd.Before(opts.EndExclusive) {
+		blockEnd = blockEnd.Add(blockSize)
+	}
+	return indexQueryCacheKey{
+		query:      query.String(),
+		blockStart: xtime.ToUnixNano(opts.StartInclusive.Truncate(blockSize)),
+		blockEnd:   xtime.ToUnixNano(blockEnd),
+		limit:      opts.Limit,
+		countOnly:  opts.CountOnly,
+
+		segmentMergePolicy: opts.SegmentMergePolicy,
+	}
+}
+
+type indexQueryCacheEntry struct {
+	key        indexQueryCacheKey
+	docs       []doc.Document
+	exhaustive bool
+	expiresAt  time.Time
+}
+
+type indexQueryCacheMetrics struct {
+	hits          tally.Counter
+	misses        tally.Counter
+	evictions     tally.Counter
+	invalidations tally.Counter
+	size          tally.Gauge
+}
+
+func newIndexQueryCacheMetrics(scope tally.Scope) indexQueryCacheMetrics {
+	return indexQueryCacheMetrics{
+		hits:          scope.Counter("hits"),
+		misses:        scope.Counter("misses"),
+		evictions:     scope.Counter("evictions"),
+		invalidations: scope.Counter("invalidations"),
+		size:          scope.Gauge("size"),
+	}
+}
+
+// indexQueryCache is an LRU cache of the IDs and tags matched by queries of
+// a namespace's index. Entries expire after the TTL and all entries are
+// invalidated whenever blocks are sealed, evicted or given new segments,
+// series indexed into the active block are only visible once cached results
+// expire. Each invalidation starts a new generation, results of queries that
+// started in an earlier generation are not cached since they may predate the
+// invalidation.
+type indexQueryCache struct {
+	sync.Mutex
+
+	size       int
+	ttl        time.Duration
+	nowFn      clock.NowFn
+	entries    map[indexQueryCacheKey]*list.Element
+	lru        *list.List
+	generation uint64
+	metrics    indexQueryCacheMetrics
+}
+
+func newIndexQueryCache(
+	size int,
+	ttl time.Duration,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *indexQueryCache {
+	return &indexQueryCache{
+		size:    size,
+		ttl:     ttl,
+		nowFn:   nowFn,
+		entries: make(map[indexQueryCacheKey]*list.Element, size),
+		lru:     list.New(),
+		metrics: newIndexQueryCacheMetrics(scope),
+	}
+}
+
+// Get returns the cached results of the query, the returned documents must
+// not be modified.
+func (c *indexQueryCache) Get(key indexQueryCacheKey) (indexQueryCacheEntry, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.metrics.misses.Inc(1)
+		return indexQueryCacheEntry{}, false
+	}
+	entry := elem.Value.(indexQueryCacheEntry)
+	if !c.nowFn().Before(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		c.metrics.misses.Inc(1)
+		return indexQueryCacheEntry{}, false
+	}
+	c.lru.MoveToFront(elem)
+	c.metrics.hits.Inc(1)
+	return entry, true
+}
+
+// Generation returns the current generation of the cache, it must be read
+// before a query starts and passed to Put with the query's results.
+func (c *indexQueryCache) Generation() uint64 {
+	c.Lock()
+	generation := c.generation
+	c.Unlock()
+	return generation
+}
+
+// Put caches the IDs and tags of the query results if the cache has not been
+// invalidated since the generation the query started in.
+func (c *indexQueryCache) Put(
+	key indexQueryCacheKey,
+	generation uint64,
+	results index.Results,
+	exhaustive bool,
+) error {
+	if c.Generation() != generation {
+		return nil
+	}
+
+	docs := make([]doc.Document, 0, results.Size())
+	for _, entry := range results.Map().Iter() {
+		d, err := convert.FromMetric(entry.Key(), entry.Value())
+		if err != nil {
+			return err
+		}
+		docs = append(docs, d)
+	}
+
+	entry := indexQueryCacheEntry{
+		key:        key,
+		docs:       docs,
+		exhaustive: exhaustive,
+		expiresAt:  c.nowFn().Add(c.ttl),
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.generation != generation {
+		return nil
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(indexQueryCacheEntry).key)
+		c.metrics.evictions.Inc(1)
+	}
+	c.metrics.size.Update(float64(c.lru.Len()))
+	return nil
+}
+
+// Invalidate removes every cached query result.
+func (c *indexQueryCache) Invalidate() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.generation++
+	if c.lru.Len() == 0 {
+		return
+	}
+	c.entries = make(map[indexQueryCacheKey]*list.Element, c.size)
+	c.lru.Init()
+	c.metrics.invalidations.Inc(1)
+	c.metrics.size.Update(0)
+}
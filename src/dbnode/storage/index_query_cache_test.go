@@ -0,0 +1,116 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestIndexQueryCacheKeyBlockRange(t *testing.T) {
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Now().Truncate(blockSize)
+		query      = index.Query{Query: idx.NewTermQuery([]byte("foo"), []byte("bar"))}
+	)
+	key := newIndexQueryCacheKey(query, index.QueryOptions{
+		StartInclusive: blockStart.Add(time.Minute),
+		EndExclusive:   blockStart.Add(time.Hour),
+	}, blockSize)
+	sameBlocks := newIndexQueryCacheKey(query, index.QueryOptions{
+		StartInclusive: blockStart,
+		EndExclusive:   blockStart.Add(blockSize),
+	}, blockSize)
+	nextBlock := newIndexQueryCacheKey(query, index.QueryOptions{
+		StartInclusive: blockStart,
+		EndExclusive:   blockStart.Add(blockSize + time.Minute),
+	}, blockSize)
+	require.Equal(t, key, sameBlocks)
+	require.NotEqual(t, key, nextBlock)
+
+	// Count only queries do not return tags so must not share results.
+	countOnly := newIndexQueryCacheKey(query, index.QueryOptions{
+		StartInclusive: blockStart.Add(time.Minute),
+		EndExclusive:   blockStart.Add(time.Hour),
+		CountOnly:      true,
+	}, blockSize)
+	require.NotEqual(t, key, countOnly)
+}
+
+func TestIndexQueryCache(t *testing.T) {
+	var (
+		now   = time.Now()
+		nowFn = func() time.Time { return now }
+		cache = newIndexQueryCache(2, time.Minute, nowFn, tally.NoopScope)
+		keyA  = indexQueryCacheKey{query: "a"}
+		keyB  = indexQueryCacheKey{query: "b"}
+		keyC  = indexQueryCacheKey{query: "c"}
+	)
+
+	results := index.NewResults(index.NewOptions())
+	_, _, err := results.Add(doc.Document{
+		ID:     []byte("foo"),
+		Fields: doc.Fields{{Name: []byte("name"), Value: []byte("value")}},
+	})
+	require.NoError(t, err)
+
+	_, ok := cache.Get(keyA)
+	require.False(t, ok)
+
+	require.NoError(t, cache.Put(keyA, 0, results, true))
+	entry, ok := cache.Get(keyA)
+	require.True(t, ok)
+	require.True(t, entry.exhaustive)
+	require.Equal(t, 1, len(entry.docs))
+	require.Equal(t, []byte("foo"), entry.docs[0].ID)
+
+	// The least recently used result is evicted.
+	require.NoError(t, cache.Put(keyB, 0, results, true))
+	_, ok = cache.Get(keyA)
+	require.True(t, ok)
+	require.NoError(t, cache.Put(keyC, 0, results, true))
+	_, ok = cache.Get(keyB)
+	require.False(t, ok)
+	_, ok = cache.Get(keyA)
+	require.True(t, ok)
+
+	// Results expire after the TTL.
+	now = now.Add(time.Minute)
+	_, ok = cache.Get(keyA)
+	require.False(t, ok)
+
+	generation := cache.Generation()
+	require.NoError(t, cache.Put(keyA, generation, results, false))
+	cache.Invalidate()
+	_, ok = cache.Get(keyA)
+	require.False(t, ok)
+
+	// Results of queries started before an invalidation are not cached.
+	require.NoError(t, cache.Put(keyA, generation, results, false))
+	_, ok = cache.Get(keyA)
+	require.False(t, ok)
+}
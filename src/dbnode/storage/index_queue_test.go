@@ -245,6 +245,46 @@ func TestNamespaceIndexInsertRetentionPeriod(t *testing.T) {
 	assert.NoError(t, dbIdx.Close())
 }
 
+func TestNamespaceIndexInsertRepairSkipsRetentionPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	md, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts)
+	require.NoError(t, err)
+
+	opts := testNamespaceIndexOptions().SetInsertMode(index.InsertSync)
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+	dbIdx, err := newNamespaceIndex(md, testDatabaseOptions().SetIndexOptions(opts))
+	require.NoError(t, err)
+
+	idx, ok := dbIdx.(*nsIndex)
+	require.True(t, ok)
+
+	var (
+		id   = ident.StringID("foo")
+		tags = ident.NewTags(
+			ident.StringTag("name", "value"),
+		)
+		lifecycle = index.NewMockOnIndexSeries(ctrl)
+	)
+
+	// Repairs are indexed for the block of their timestamp even if it is
+	// older than the buffer past
+	tooOld := now.Add(-1 * idx.bufferPast).Add(-1 * time.Second)
+	blockStart := xtime.ToUnixNano(tooOld.Truncate(idx.blockSize))
+	lifecycle.EXPECT().OnIndexSuccess(blockStart)
+	lifecycle.EXPECT().OnIndexFinalize(blockStart)
+	entry, document := testWriteBatchEntry(id, tags, tooOld, lifecycle)
+	entry.Repair = true
+	batch := testWriteBatch(entry, document, testWriteBatchBlockSizeOption(idx.blockSize))
+	require.NoError(t, idx.WriteBatch(batch))
+
+	assert.NoError(t, dbIdx.Close())
+}
+
 func TestNamespaceIndexInsertQueueInteraction(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -262,9 +302,10 @@ func TestNamespaceIndexInsertQueueInteraction(t *testing.T) {
 
 	now := time.Now()
 
-	var wg sync.WaitGroup
+	future := newIndexInsertFuture()
+	future.complete(nil)
 	lifecycle := index.NewMockOnIndexSeries(ctrl)
-	q.EXPECT().InsertBatch(gomock.Any()).Return(&wg, nil)
+	q.EXPECT().InsertBatch(gomock.Any()).Return(future, nil)
 	assert.NoError(t, idx.WriteBatch(testWriteBatch(testWriteBatchEntry(id,
 		tags, now, lifecycle))))
 }
@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync"
+	"time"
+
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// recentlyIndexedNumShards is the number of shards the recently indexed
+	// IDs are split into by the hash of the ID, each with its own lock, so
+	// that concurrent writes do not contend on a single lock.
+	recentlyIndexedNumShards = 64
+)
+
+// recentlyIndexedIDs is a set of the IDs recently indexed for each block
+// start, checked before enqueueing inserts so that repeated inserts of an
+// ID, e.g. writes of a series not yet marked indexed or a re-index on
+// bootstrap, do not load the insert queue. IDs are remembered for between
+// one and two windows, each shard of the set is split into two generations
+// that are rotated every window.
+type recentlyIndexedIDs struct {
+	shards [recentlyIndexedNumShards]recentlyIndexedShard
+}
+
+type recentlyIndexedShard struct {
+	sync.RWMutex
+
+	window    time.Duration
+	rotatedAt time.Time
+	current   recentlyIndexedSet
+	previous  recentlyIndexedSet
+}
+
+// recentlyIndexedSet is keyed by block start then ID, IDs are keyed by string
+// so that lookups with a converted byte slice do not allocate.
+type recentlyIndexedSet map[xtime.UnixNano]map[string]struct{}
+
+func (s recentlyIndexedSet) contains(blockStart xtime.UnixNano, id []byte) bool {
+	ids, ok := s[blockStart]
+	if !ok {
+		return false
+	}
+	// NB: string conversion in map index does not allocate.
+	_, ok = ids[string(id)]
+	return ok
+}
+
+func newRecentlyIndexedIDs(window time.Duration, now time.Time) *recentlyIndexedIDs {
+	r := &recentlyIndexedIDs{}
+	for i := range r.shards {
+		r.shards[i] = recentlyIndexedShard{
+			window:    window,
+			rotatedAt: now,
+			current:   make(recentlyIndexedSet),
+			previous:  make(recentlyIndexedSet),
+		}
+	}
+	return r
+}
+
+func (r *recentlyIndexedIDs) shard(id []byte) *recentlyIndexedShard {
+	return &r.shards[xxhash.Sum64(id)%recentlyIndexedNumShards]
+}
+
+// Contains returns whether the ID was recently indexed for the block start.
+func (r *recentlyIndexedIDs) Contains(
+	now time.Time,
+	blockStart xtime.UnixNano,
+	id []byte,
+) bool {
+	return r.shard(id).contains(now, blockStart, id)
+}
+
+// Add marks the ID as indexed for the block start.
+func (r *recentlyIndexedIDs) Add(
+	now time.Time,
+	blockStart xtime.UnixNano,
+	id []byte,
+) {
+	r.shard(id).add(now, blockStart, id)
+}
+
+func (s *recentlyIndexedShard) contains(
+	now time.Time,
+	blockStart xtime.UnixNano,
+	id []byte,
+) bool {
+	s.maybeRotate(now)
+	s.RLock()
+	ok := s.current.contains(blockStart, id) || s.previous.contains(blockStart, id)
+	s.RUnlock()
+	return ok
+}
+
+func (s *recentlyIndexedShard) add(
+	now time.Time,
+	blockStart xtime.UnixNano,
+	id []byte,
+) {
+	s.maybeRotate(now)
+	s.Lock()
+	ids, ok := s.current[blockStart]
+	if !ok {
+		ids = make(map[string]struct{})
+		s.current[blockStart] = ids
+	}
+	if _, ok := ids[string(id)]; !ok {
+		ids[string(id)] = struct{}{}
+	}
+	s.Unlock()
+}
+
+func (s *recentlyIndexedShard) maybeRotate(now time.Time) {
+	s.RLock()
+	rotate := now.Sub(s.rotatedAt) >= s.window
+	s.RUnlock()
+	if !rotate {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	elapsed := now.Sub(s.rotatedAt)
+	if elapsed < s.window {
+		// Rotated while waiting for the lock.
+		return
+	}
+
+	s.previous = s.current
+	if elapsed >= 2*s.window {
+		// Nothing in the current generation is recent enough to keep.
+		s.previous = make(recentlyIndexedSet)
+	}
+	s.current = make(recentlyIndexedSet)
+	s.rotatedAt = now
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentlyIndexedIDs(t *testing.T) {
+	var (
+		now        = time.Now()
+		window     = time.Minute
+		blockStart = xtime.ToUnixNano(now.Truncate(2 * time.Hour))
+		otherStart = xtime.ToUnixNano(now.Truncate(2 * time.Hour).Add(2 * time.Hour))
+		recent     = newRecentlyIndexedIDs(window, now)
+	)
+
+	require.False(t, recent.Contains(now, blockStart, []byte("foo")))
+	recent.Add(now, blockStart, []byte("foo"))
+	require.True(t, recent.Contains(now, blockStart, []byte("foo")))
+	require.False(t, recent.Contains(now, otherStart, []byte("foo")))
+	require.False(t, recent.Contains(now, blockStart, []byte("bar")))
+
+	// Still contained after a single rotation.
+	now = now.Add(window)
+	require.True(t, recent.Contains(now, blockStart, []byte("foo")))
+
+	// Expired after a second rotation.
+	now = now.Add(window)
+	require.False(t, recent.Contains(now, blockStart, []byte("foo")))
+
+	// Expired if not looked up for two windows.
+	recent.Add(now, blockStart, []byte("bar"))
+	now = now.Add(2 * window)
+	require.False(t, recent.Contains(now, blockStart, []byte("bar")))
+}
+
+func TestRecentlyIndexedIDsConcurrent(t *testing.T) {
+	var (
+		now        = time.Now()
+		blockStart = xtime.ToUnixNano(now.Truncate(2 * time.Hour))
+		recent     = newRecentlyIndexedIDs(time.Minute, now)
+		wg         sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := []byte(fmt.Sprintf("id-%d-%d", i, j))
+				recent.Add(now, blockStart, id)
+				require.True(t, recent.Contains(now, blockStart, id))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var numShards int
+	for i := range recent.shards {
+		if len(recent.shards[i].current[blockStart]) > 0 {
+			numShards++
+		}
+	}
+	require.True(t, numShards > 1)
+}
@@ -22,15 +22,20 @@ package storage
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	"github.com/m3db/m3x/ident"
 	xtest "github.com/m3db/m3x/test"
 	xtime "github.com/m3db/m3x/time"
@@ -83,6 +88,81 @@ func TestNamespaceIndexCleanupExpiredFilesetsWithBlocks(t *testing.T) {
 	require.NoError(t, idx.CleanupExpiredFileSets(now))
 }
 
+func TestNamespaceIndexReloadEvictedBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	indexBlockSize := 2 * time.Hour
+	md := testNamespaceMetadata(indexBlockSize, 8*time.Hour)
+	opts := testDatabaseOptions()
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
+		SetFilesystemOptions(opts.CommitLogOptions().FilesystemOptions().
+			SetFilePathPrefix(dir)))
+
+	now := time.Now().Truncate(indexBlockSize)
+	retained := now.Add(-2 * indexBlockSize)
+	expired := now.Add(-6 * indexBlockSize)
+	for _, blockStart := range []time.Time{retained, expired} {
+		writeTestIndexFileSet(t, opts, md, blockStart)
+	}
+
+	nsIdx, err := newNamespaceIndex(md, opts)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, nsIdx.Close())
+	}()
+
+	idx := nsIdx.(*nsIndex)
+	idx.nowFn = func() time.Time { return now }
+	require.NoError(t, idx.ReloadEvictedBlocks())
+
+	_, ok := idx.state.blocksByTime[xtime.ToUnixNano(retained)]
+	require.True(t, ok)
+	_, ok = idx.state.blocksByTime[xtime.ToUnixNano(expired)]
+	require.False(t, ok)
+
+	// Blocks already held in memory are not reloaded again.
+	numBlocks := len(idx.state.blocksByTime)
+	require.NoError(t, idx.ReloadEvictedBlocks())
+	require.Equal(t, numBlocks, len(idx.state.blocksByTime))
+}
+
+func writeTestIndexFileSet(
+	t *testing.T,
+	opts Options,
+	md namespace.Metadata,
+	blockStart time.Time,
+) {
+	seg, err := mem.NewSegment(0, mem.NewOptions())
+	require.NoError(t, err)
+	_, err = seg.Insert(doc.Document{ID: []byte("foo")})
+	require.NoError(t, err)
+	_, err = seg.Seal()
+	require.NoError(t, err)
+
+	pm, err := fs.NewPersistManager(opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, err)
+	flush, err := pm.StartIndexPersist()
+	require.NoError(t, err)
+
+	prepared, err := flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: md,
+		BlockStart:        blockStart,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            map[uint32]struct{}{0: struct{}{}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, prepared.Persist(seg))
+
+	segments, err := prepared.Close()
+	require.NoError(t, err)
+	for _, s := range segments {
+		require.NoError(t, s.Close())
+	}
+	require.NoError(t, flush.DoneIndex())
+}
+
 func TestNamespaceIndexFlushSuccess(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -0,0 +1,184 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3x/context"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/uber-go/tally"
+)
+
+type indexQueryFn func(
+	ctx context.Context,
+	query index.Query,
+	opts index.QueryOptions,
+) (index.QueryResults, error)
+
+// indexWarmer warms the index once in the background after bootstrap by
+// reading the segments of each block, so their mmap'd pages are resident,
+// and running a set of common queries, priming the postings list cache, so
+// the first queries served after a restart do not pay for the page faults
+// and postings list decoding.
+type indexWarmer struct {
+	sync.Mutex
+
+	queries   []index.Query
+	blocksFn  func() []index.Block
+	queryFn   indexQueryFn
+	queryOpts func() index.QueryOptions
+	nowFn     func() time.Time
+	logger    xlog.Logger
+	metrics   indexWarmerMetrics
+
+	started bool
+	closed  bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+type indexWarmerMetrics struct {
+	blocks  tally.Counter
+	terms   tally.Counter
+	queries tally.Counter
+	errors  tally.Counter
+	latency tally.Timer
+}
+
+func newIndexWarmer(
+	queries []index.Query,
+	blocksFn func() []index.Block,
+	queryFn indexQueryFn,
+	queryOpts func() index.QueryOptions,
+	nowFn func() time.Time,
+	logger xlog.Logger,
+	scope tally.Scope,
+) *indexWarmer {
+	scope = scope.SubScope("warmup")
+	return &indexWarmer{
+		queries:   queries,
+		blocksFn:  blocksFn,
+		queryFn:   queryFn,
+		queryOpts: queryOpts,
+		nowFn:     nowFn,
+		logger:    logger,
+		metrics: indexWarmerMetrics{
+			blocks:  scope.Counter("blocks"),
+			terms:   scope.Counter("terms"),
+			queries: scope.Counter("queries"),
+			errors:  scope.Counter("errors"),
+			latency: scope.Timer("latency"),
+		},
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start warms the index in the background, only the first call after the
+// index has bootstrapped has any effect.
+func (w *indexWarmer) Start() {
+	w.Lock()
+	defer w.Unlock()
+	if w.started || w.closed {
+		return
+	}
+	w.started = true
+	go w.run()
+}
+
+func (w *indexWarmer) run() {
+	defer close(w.doneCh)
+
+	var (
+		start      = w.nowFn()
+		numBlocks  int64
+		numTerms   int64
+		numQueries int64
+	)
+	for _, block := range w.blocksFn() {
+		if w.isClosed() {
+			return
+		}
+
+		result, err := block.Warm()
+		numTerms += result.NumTerms
+		if err != nil {
+			// NB: the block may have been closed since taking the snapshot.
+			w.metrics.errors.Inc(1)
+			w.logger.Errorf("unable to warm index block %v: %v", block.StartTime(), err)
+			continue
+		}
+		numBlocks++
+	}
+
+	for _, query := range w.queries {
+		if w.isClosed() {
+			return
+		}
+
+		// NB: the results are discarded, only the reads matter.
+		ctx := context.NewContext()
+		_, err := w.queryFn(ctx, query, w.queryOpts())
+		ctx.BlockingClose()
+		if err != nil {
+			w.metrics.errors.Inc(1)
+			w.logger.Errorf("unable to run index warm up query %s: %v", query.String(), err)
+			continue
+		}
+		numQueries++
+	}
+
+	took := w.nowFn().Sub(start)
+	w.metrics.blocks.Inc(numBlocks)
+	w.metrics.terms.Inc(numTerms)
+	w.metrics.queries.Inc(numQueries)
+	w.metrics.latency.Record(took)
+	w.logger.Infof("index warm up complete: blocks=%d, terms=%d, queries=%d, took=%v",
+		numBlocks, numTerms, numQueries, took)
+}
+
+func (w *indexWarmer) isClosed() bool {
+	select {
+	case <-w.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops warming the index and waits for the warm up to return.
+func (w *indexWarmer) Close() {
+	w.Lock()
+	started := w.started
+	if !w.closed {
+		w.closed = true
+		close(w.closeCh)
+	}
+	w.Unlock()
+
+	if started {
+		<-w.doneCh
+	}
+}
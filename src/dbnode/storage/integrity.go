@@ -0,0 +1,180 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+// IntegrityReport is the durability of each block of a namespace across
+// the shards owned by the node.
+type IntegrityReport struct {
+	Namespace string           `json:"namespace"`
+	Blocks    []BlockIntegrity `json:"blocks"`
+}
+
+// BlockIntegrity is the number of shards of a block by how durably the
+// data of the block is persisted.
+type BlockIntegrity struct {
+	BlockStart time.Time `json:"blockStart"`
+
+	// Flushed is the number of shards with a flushed fileset for the block.
+	Flushed int `json:"flushed"`
+
+	// SnapshotOnly is the number of shards not flushed that have a
+	// snapshot covering the block.
+	SnapshotOnly int `json:"snapshotOnly"`
+
+	// CommitLogOnly is the number of shards neither flushed nor snapshotted
+	// whose writes for the block may only be in the commit log.
+	CommitLogOnly int `json:"commitLogOnly"`
+
+	// Missing is the number of shards without any durable data for the block.
+	Missing int `json:"missing"`
+}
+
+// Durable returns whether every shard of every block is flushed.
+func (r IntegrityReport) Durable() bool {
+	for _, b := range r.Blocks {
+		if b.SnapshotOnly > 0 || b.CommitLogOnly > 0 || b.Missing > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *db) IntegrityReport(
+	namespace ident.ID,
+	start, end time.Time,
+) (IntegrityReport, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return IntegrityReport{}, err
+	}
+
+	var (
+		ropts  = n.Options().RetentionOptions()
+		now    = d.nowFn()
+		clOpts = d.opts.CommitLogOptions()
+	)
+	if start.IsZero() {
+		start = now.Add(-ropts.RetentionPeriod())
+	}
+	if end.IsZero() {
+		end = now
+	}
+
+	commitLogFiles, err := commitlog.Files(clOpts)
+	if err != nil {
+		return IntegrityReport{}, err
+	}
+
+	b := newIntegrityReportBuilder(namespace.String(), start, end,
+		ropts.BlockSize(), ropts.BufferPast(), ropts.BufferFuture(), commitLogFiles)
+	filePathPrefix := clOpts.FilesystemOptions().FilePathPrefix()
+	for _, shard := range n.GetOwnedShards() {
+		snapshots, err := fs.SnapshotFiles(filePathPrefix, namespace, shard.ID())
+		if err != nil {
+			return IntegrityReport{}, err
+		}
+		snapshotted := make(map[xtime.UnixNano]struct{}, len(snapshots))
+		for _, f := range snapshots {
+			if f.HasCheckpointFile() {
+				snapshotted[xtime.ToUnixNano(f.ID.BlockStart)] = struct{}{}
+			}
+		}
+
+		b.addShard(func(blockStart time.Time) bool {
+			return shard.FlushState(blockStart).Status == fileOpSuccess
+		}, snapshotted)
+	}
+	return b.report, nil
+}
+
+// integrityReportBuilder classifies the shards of each block of a namespace.
+type integrityReportBuilder struct {
+	report         IntegrityReport
+	blockSize      time.Duration
+	bufferPast     time.Duration
+	bufferFuture   time.Duration
+	commitLogFiles []commitlog.File
+}
+
+func newIntegrityReportBuilder(
+	namespace string,
+	start, end time.Time,
+	blockSize, bufferPast, bufferFuture time.Duration,
+	commitLogFiles []commitlog.File,
+) *integrityReportBuilder {
+	b := &integrityReportBuilder{
+		report:         IntegrityReport{Namespace: namespace},
+		blockSize:      blockSize,
+		bufferPast:     bufferPast,
+		bufferFuture:   bufferFuture,
+		commitLogFiles: commitLogFiles,
+	}
+	for t := start.Truncate(blockSize); t.Before(end); t = t.Add(blockSize) {
+		b.report.Blocks = append(b.report.Blocks, BlockIntegrity{BlockStart: t})
+	}
+	return b
+}
+
+// addShard records the durability of each block of a shard given whether
+// each block is flushed and the block starts that have a snapshot.
+func (b *integrityReportBuilder) addShard(
+	flushed func(blockStart time.Time) bool,
+	snapshotted map[xtime.UnixNano]struct{},
+) {
+	for i := range b.report.Blocks {
+		block := &b.report.Blocks[i]
+		if flushed(block.BlockStart) {
+			block.Flushed++
+			continue
+		}
+		if _, ok := snapshotted[xtime.ToUnixNano(block.BlockStart)]; ok {
+			block.SnapshotOnly++
+			continue
+		}
+		if b.inCommitLog(block.BlockStart) {
+			block.CommitLogOnly++
+			continue
+		}
+		block.Missing++
+	}
+}
+
+// inCommitLog returns whether any commit log file on disk spans the period
+// writes for the block could have been accepted.
+func (b *integrityReportBuilder) inCommitLog(blockStart time.Time) bool {
+	writesStart := blockStart.Add(-b.bufferFuture)
+	writesEnd := blockStart.Add(b.blockSize).Add(b.bufferPast)
+	for _, f := range b.commitLogFiles {
+		if f.Start.Before(writesEnd) && f.Start.Add(f.Duration).After(writesStart) {
+			return true
+		}
+	}
+	return false
+}
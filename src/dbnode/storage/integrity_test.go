@@ -0,0 +1,73 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrityReportBuilder(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		blocks    = []time.Time{start, start.Add(blockSize), start.Add(2 * blockSize), start.Add(3 * blockSize)}
+	)
+
+	// Only the last block has writes in the commit log.
+	commitLogFiles := []commitlog.File{
+		{Start: blocks[3].Add(30 * time.Minute), Duration: 10 * time.Minute},
+	}
+	b := newIntegrityReportBuilder("testns", start, blocks[3].Add(time.Minute),
+		blockSize, 10*time.Minute, 2*time.Minute, commitLogFiles)
+	require.Len(t, b.report.Blocks, 4)
+
+	// Shard flushed the first block and snapshotted the second.
+	b.addShard(func(blockStart time.Time) bool {
+		return blockStart.Equal(blocks[0])
+	}, map[xtime.UnixNano]struct{}{
+		xtime.ToUnixNano(blocks[1]): {},
+	})
+	// Shard flushed the first two blocks.
+	b.addShard(func(blockStart time.Time) bool {
+		return blockStart.Before(blocks[2])
+	}, nil)
+
+	assert.Equal(t, IntegrityReport{
+		Namespace: "testns",
+		Blocks: []BlockIntegrity{
+			{BlockStart: blocks[0], Flushed: 2},
+			{BlockStart: blocks[1], Flushed: 1, SnapshotOnly: 1},
+			{BlockStart: blocks[2], Missing: 2},
+			{BlockStart: blocks[3], CommitLogOnly: 2},
+		},
+	}, b.report)
+	assert.False(t, b.report.Durable())
+
+	b.report.Blocks = b.report.Blocks[:1]
+	assert.True(t, b.report.Durable())
+}
@@ -71,24 +71,29 @@ type mediator struct {
 	databaseTickManager
 	databaseRepairer
 
-	opts     Options
-	nowFn    clock.NowFn
-	sleepFn  sleepFn
-	metrics  mediatorMetrics
-	state    mediatorState
-	closedCh chan struct{}
+	opts      Options
+	nowFn     clock.NowFn
+	sleepFn   sleepFn
+	newTicker clock.NewTickerFn
+	metrics   mediatorMetrics
+	state     mediatorState
+	closedCh  chan struct{}
+
+	lastTickStart          time.Time
+	lastTickBootstrapState DatabaseBootstrapState
 }
 
 func newMediator(database database, opts Options) (databaseMediator, error) {
 	scope := opts.InstrumentOptions().MetricsScope()
 	d := &mediator{
-		database: database,
-		opts:     opts,
-		nowFn:    opts.ClockOptions().NowFn(),
-		sleepFn:  time.Sleep,
-		metrics:  newMediatorMetrics(scope),
-		state:    mediatorNotOpen,
-		closedCh: make(chan struct{}),
+		database:  database,
+		opts:      opts,
+		nowFn:     opts.ClockOptions().NowFn(),
+		sleepFn:   opts.ClockOptions().SleepFn(),
+		newTicker: opts.ClockOptions().NewTickerFn(),
+		metrics:   newMediatorMetrics(scope),
+		state:     mediatorNotOpen,
+		closedCh:  make(chan struct{}),
 	}
 
 	fsm := newFileSystemManager(database, opts)
@@ -161,6 +166,11 @@ func (m *mediator) Tick(runType runType, forceType forceType) error {
 		return err
 	}
 
+	m.Lock()
+	m.lastTickStart = tickStart
+	m.lastTickBootstrapState = dbBootstrapStateAtTickStart
+	m.Unlock()
+
 	// NB(r): Cleanup and/or flush if required to cleanup files and/or
 	// flush blocks to disk. Note this has to run after the tick as
 	// blocks may only have just become available during a tick beginning
@@ -169,6 +179,12 @@ func (m *mediator) Tick(runType runType, forceType forceType) error {
 	return nil
 }
 
+func (m *mediator) LastTickStart() time.Time {
+	m.RLock()
+	defer m.RUnlock()
+	return m.lastTickStart
+}
+
 func (m *mediator) Report() {
 	m.databaseBootstrapManager.Report()
 	m.databaseRepairer.Report()
@@ -196,6 +212,13 @@ func (m *mediator) ongoingTick() {
 		case <-m.closedCh:
 			return
 		default:
+			if !m.opts.BackgroundSchedules().ShouldRun(BackgroundProcessTick) {
+				// NB: flushes and cleanups keep running while ticks are paused,
+				// as of the last tick since they may only act on what it saw.
+				m.runFileOpsAtLastTick()
+				m.sleepFn(tickCheckInterval)
+				continue
+			}
 			// NB(xichen): if we attempt to tick while another tick
 			// is in progress, throttle a little to avoid constantly
 			// checking whether the ongoing tick is finished
@@ -210,13 +233,23 @@ func (m *mediator) ongoingTick() {
 	}
 }
 
+func (m *mediator) runFileOpsAtLastTick() {
+	m.RLock()
+	tickStart, dbBootstrapState := m.lastTickStart, m.lastTickBootstrapState
+	m.RUnlock()
+	if tickStart.IsZero() {
+		return
+	}
+	m.databaseFileSystemManager.Run(tickStart, dbBootstrapState, syncRun, noForce)
+}
+
 func (m *mediator) reportLoop() {
 	interval := m.opts.InstrumentOptions().ReportInterval()
-	t := time.NewTicker(interval)
+	t := m.newTicker(interval)
 
 	for {
 		select {
-		case <-t.C:
+		case <-t.C():
 			m.Report()
 		case <-m.closedCh:
 			t.Stop()
@@ -89,3 +89,34 @@ func TestDatabaseMediatorDisableFileOps(t *testing.T) {
 	m.DisableFileOps()
 	require.Equal(t, 3, len(slept))
 }
+
+func TestDatabaseMediatorRunFileOpsAtLastTick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testDatabaseOptions().SetRepairEnabled(false)
+	opts = opts.SetBootstrapProcessProvider(nil)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	med, err := newMediator(db, opts)
+	require.NoError(t, err)
+
+	m := med.(*mediator)
+	fsm := NewMockdatabaseFileSystemManager(ctrl)
+	m.databaseFileSystemManager = fsm
+
+	// no file ops run before the first tick.
+	m.runFileOpsAtLastTick()
+
+	tickStart := time.Now()
+	state := DatabaseBootstrapState{
+		NamespaceBootstrapStates: NamespaceBootstrapStates{
+			"ns": ShardBootstrapStates{0: Bootstrapped},
+		},
+	}
+	m.lastTickStart = tickStart
+	m.lastTickBootstrapState = state
+	fsm.EXPECT().Run(tickStart, state, syncRun, noForce).Return(true)
+	m.runFileOpsAtLastTick()
+}
@@ -26,17 +26,21 @@ import (
 	"math"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -98,6 +102,10 @@ var commitLogWriteNoOp = commitLogWriter(commitLogWriterFn(func(
 type dbNamespace struct {
 	sync.RWMutex
 
+	// NB: usageCounters is accessed atomically so is kept first for 64 bit
+	// alignment on 32 bit platforms.
+	usageCounters namespaceUsageCounters
+
 	closed             bool
 	shutdownCh         chan struct{}
 	id                 ident.ID
@@ -107,8 +115,12 @@ type dbNamespace struct {
 	opts               Options
 	metadata           namespace.Metadata
 	nopts              namespace.Options
+	retentionOpts      retention.DynamicOptions
+	nsIndexOpts        namespace.DynamicIndexOptions
 	seriesOpts         series.Options
 	nowFn              clock.NowFn
+	sleepFn            clock.SleepFn
+	newTicker          clock.NewTickerFn
 	snapshotFilesFn    snapshotFilesFn
 	log                xlog.Logger
 	bootstrapState     BootstrapState
@@ -117,6 +129,13 @@ type dbNamespace struct {
 	// entry will be nil when this shard does not belong to current database
 	shards []databaseShard
 
+	// readOnlyShards are the shards in the shard set that serve reads of the
+	// data on disk but are not assigned to the node so reject writes.
+	readOnlyShards map[uint32]struct{}
+
+	// shardRoutingTag is the tag series are routed to shards by, if any.
+	shardRoutingTag []byte
+
 	increasingIndex increasingIndex
 	commitLogWriter commitLogWriter
 	reverseIndex    namespaceIndex
@@ -133,6 +152,7 @@ type databaseNamespaceStatsLastTick struct {
 	activeSeries int64
 	activeBlocks int64
 	index        databaseNamespaceIndexStatsLastTick
+	usage        namespaceUsageRatesLastTick
 }
 
 type databaseNamespaceIndexStatsLastTick struct {
@@ -158,6 +178,13 @@ type databaseNamespaceMetrics struct {
 	shards              databaseNamespaceShardMetrics
 	tick                databaseNamespaceTickMetrics
 	status              databaseNamespaceStatusMetrics
+	futureWrites        databaseNamespaceFutureWriteMetrics
+}
+
+type databaseNamespaceFutureWriteMetrics struct {
+	skew     tally.Timer
+	clamped  tally.Counter
+	rejected tally.Counter
 }
 
 type databaseNamespaceShardMetrics struct {
@@ -210,6 +237,7 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 	tickScope := scope.SubScope("tick")
 	indexTickScope := tickScope.SubScope("index")
 	statusScope := scope.SubScope("status")
+	futureWritesScope := scope.SubScope("future-writes")
 	indexStatusScope := statusScope.SubScope("index")
 	return databaseNamespaceMetrics{
 		bootstrap:           instrument.NewMethodMetrics(scope, "bootstrap", samplingRate),
@@ -250,6 +278,11 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 				numBlocksEvicted: indexTickScope.Counter("num-blocks-evicted"),
 			},
 		},
+		futureWrites: databaseNamespaceFutureWriteMetrics{
+			skew:     futureWritesScope.Timer("skew"),
+			clamped:  futureWritesScope.Counter("clamped"),
+			rejected: futureWritesScope.Counter("rejected"),
+		},
 		status: databaseNamespaceStatusMetrics{
 			activeSeries: statusScope.Gauge("active-series"),
 			activeBlocks: statusScope.Gauge("active-blocks"),
@@ -278,6 +311,20 @@ func newDatabaseNamespace(
 		commitLogWriter = commitLogWriteNoOp
 	}
 
+	// Wrap the retention options so the retention period can be extended
+	// at runtime and observed by the series, shards and index.
+	retentionOpts := retention.NewDynamicOptions(nopts.RetentionOptions())
+	nopts = nopts.SetRetentionOptions(retentionOpts)
+
+	// Similarly wrap the index options so the index insert mode can be
+	// changed at runtime.
+	nsIndexOpts := namespace.NewDynamicIndexOptions(nopts.IndexOptions())
+	nopts = nopts.SetIndexOptions(nsIndexOpts)
+	metadata, err := namespace.NewMetadata(id, nopts)
+	if err != nil {
+		return nil, err
+	}
+
 	iops := opts.InstrumentOptions()
 	logger := iops.Logger().WithFields(xlog.NewField("namespace", id.String()))
 	iops = iops.SetLogger(logger)
@@ -292,7 +339,25 @@ func newDatabaseNamespace(
 	tickWorkers := xsync.NewWorkerPool(tickWorkersConcurrency)
 	tickWorkers.Init()
 
-	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions()).
+	if codecName := nopts.Encoding(); codecName != "" {
+		opts, err = newNamespaceEncodingOptions(opts, codecName)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unable to create namespace %v, invalid encoding: %v",
+				metadata.ID().String(), err)
+		}
+	}
+
+	if maxEntries := opts.IndexOptions().TagDictionaryMaxEntries(); maxEntries > 0 {
+		// NB: each namespace has its own tag dictionary shared by its
+		// shards and index.
+		dict := convert.NewDictionary(convert.DictionaryOptions{
+			MaxEntries: maxEntries,
+		})
+		opts = opts.SetIndexOptions(opts.IndexOptions().SetTagDictionary(dict))
+	}
+
+	seriesOpts := NewSeriesOptionsFromOptions(opts, retentionOpts).
 		SetStats(series.NewStats(scope))
 	if err := seriesOpts.Validate(); err != nil {
 		return nil, fmt.Errorf(
@@ -300,10 +365,7 @@ func newDatabaseNamespace(
 			metadata.ID().String(), err)
 	}
 
-	var (
-		index namespaceIndex
-		err   error
-	)
+	var index namespaceIndex
 	if metadata.Options().IndexOptions().Enabled() {
 		index, err = newNamespaceIndex(metadata, opts)
 		if err != nil {
@@ -314,14 +376,20 @@ func newDatabaseNamespace(
 	n := &dbNamespace{
 		id:                     id,
 		shutdownCh:             make(chan struct{}),
-		shardSet:               shardSet,
+		shardSet:               namespaceShardSet(nopts, shardSet),
+		readOnlyShards:         readOnlyShardIDs(shardSet),
+		shardRoutingTag:        []byte(nopts.ShardRoutingTag()),
 		blockRetriever:         blockRetriever,
 		namespaceReaderMgr:     newNamespaceReaderManager(metadata, scope, opts),
 		opts:                   opts,
 		metadata:               metadata,
 		nopts:                  nopts,
+		retentionOpts:          retentionOpts,
+		nsIndexOpts:            nsIndexOpts,
 		seriesOpts:             seriesOpts,
 		nowFn:                  opts.ClockOptions().NowFn(),
+		sleepFn:                opts.ClockOptions().SleepFn(),
+		newTicker:              opts.ClockOptions().NewTickerFn(),
 		snapshotFilesFn:        fs.SnapshotFiles,
 		log:                    logger,
 		increasingIndex:        increasingIndex,
@@ -340,13 +408,13 @@ func newDatabaseNamespace(
 
 func (n *dbNamespace) reportStatusLoop() {
 	reportInterval := n.opts.InstrumentOptions().ReportInterval()
-	ticker := time.NewTicker(reportInterval)
+	ticker := n.newTicker(reportInterval)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-n.shutdownCh:
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			n.statsLastTick.RLock()
 			n.metrics.status.activeSeries.Update(float64(n.statsLastTick.activeSeries))
 			n.metrics.status.activeBlocks.Update(float64(n.statsLastTick.activeBlocks))
@@ -385,6 +453,33 @@ func (n *dbNamespace) Shards() []Shard {
 	return databaseShards
 }
 
+// namespaceShardSet returns the shard set used to look up the shard of a
+// series, if the namespace has a shard routing tag series are routed by the
+// value of that tag rather than by their ID.
+func namespaceShardSet(nopts namespace.Options, shardSet sharding.ShardSet) sharding.ShardSet {
+	if tag := nopts.ShardRoutingTag(); tag != "" {
+		return sharding.NewTagRoutingShardSet(shardSet, []byte(tag))
+	}
+	return shardSet
+}
+
+// readOnlyShardIDs returns the shards in the shard set that are not assigned
+// to the node by the placement, once the placement assigns such a shard it
+// takes the assigned state and becomes writable.
+func readOnlyShardIDs(shardSet sharding.ShardSet) map[uint32]struct{} {
+	var readOnly map[uint32]struct{}
+	for _, s := range shardSet.All() {
+		if s.State() != ReadOnlyShardState {
+			continue
+		}
+		if readOnly == nil {
+			readOnly = make(map[uint32]struct{})
+		}
+		readOnly[s.ID()] = struct{}{}
+	}
+	return readOnly
+}
+
 func (n *dbNamespace) AssignShardSet(shardSet sharding.ShardSet) {
 	var (
 		incoming = make(map[uint32]struct{}, len(shardSet.All()))
@@ -405,7 +500,8 @@ func (n *dbNamespace) AssignShardSet(shardSet sharding.ShardSet) {
 			closing = append(closing, shard)
 		}
 	}
-	n.shardSet = shardSet
+	n.shardSet = namespaceShardSet(n.nopts, shardSet)
+	n.readOnlyShards = readOnlyShardIDs(shardSet)
 	n.shards = make([]databaseShard, n.shardSet.Max()+1)
 	for _, shard := range n.shardSet.AllIDs() {
 		if int(shard) < len(existing) && existing[shard] != nil {
@@ -521,6 +617,7 @@ func (n *dbNamespace) Tick(c context.Cancellable, tickStart time.Time) error {
 		numBlocks:   indexTickResults.NumBlocks,
 		numSegments: indexTickResults.NumSegments,
 	}
+	n.updateUsageRatesWithLock(tickStart)
 	n.statsLastTick.Unlock()
 
 	n.metrics.tick.activeSeries.Update(float64(r.activeSeries))
@@ -550,16 +647,23 @@ func (n *dbNamespace) Write(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
 	callStart := n.nowFn()
 	shard, err := n.shardFor(id)
 	if err != nil {
 		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
-		return err
+		return false, err
+	}
+	timestamp, err = n.writeTimestamp(timestamp, unit)
+	if err != nil {
+		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
+		return false, err
 	}
-	err = shard.Write(ctx, id, timestamp, value, unit, annotation)
+	value = m3tsz.TruncateFloatPrecision(value, n.nopts.FloatPrecisionDigits())
+	newSeries, err := shard.Write(ctx, id, timestamp, value, unit, annotation)
+	atomic.AddUint64(&n.usageCounters.writes, 1)
 	n.metrics.write.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
-	return err
+	return newSeries, err
 }
 
 func (n *dbNamespace) WriteTagged(
@@ -570,20 +674,57 @@ func (n *dbNamespace) WriteTagged(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
 	callStart := n.nowFn()
 	if n.reverseIndex == nil { // only happens if indexing is enabled.
 		n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
-		return errNamespaceIndexingDisabled
+		return false, errNamespaceIndexingDisabled
+	}
+	if len(n.shardRoutingTag) > 0 {
+		if err := sharding.ValidateRoutingID(id, n.shardRoutingTag, tags); err != nil {
+			n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
+			return false, xerrors.NewInvalidParamsError(err)
+		}
 	}
 	shard, err := n.shardFor(id)
 	if err != nil {
 		n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
-		return err
+		return false, err
+	}
+	timestamp, err = n.writeTimestamp(timestamp, unit)
+	if err != nil {
+		n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
+		return false, err
 	}
-	err = shard.WriteTagged(ctx, id, tags, timestamp, value, unit, annotation)
+	value = m3tsz.TruncateFloatPrecision(value, n.nopts.FloatPrecisionDigits())
+	newSeries, err := shard.WriteTagged(ctx, id, tags, timestamp, value, unit, annotation)
+	atomic.AddUint64(&n.usageCounters.writes, 1)
 	n.metrics.writeTagged.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
-	return err
+	return newSeries, err
+}
+
+// writeTimestamp returns the timestamp to write a datapoint at, recording
+// how far ahead of the node's clock datapoints are written.
+func (n *dbNamespace) writeTimestamp(
+	timestamp time.Time,
+	unit xtime.Unit,
+) (time.Time, error) {
+	now := n.nowFn()
+	if !timestamp.After(now) {
+		return timestamp, nil
+	}
+
+	n.metrics.futureWrites.skew.Record(timestamp.Sub(now))
+	result, clamped, err := futureWriteTimestamp(now, timestamp, unit,
+		n.retentionOpts.BufferFuture(), n.opts.MaxClockSkew(), n.opts.FutureWritePolicy())
+	if err != nil {
+		n.metrics.futureWrites.rejected.Inc(1)
+		return timestamp, err
+	}
+	if clamped {
+		n.metrics.futureWrites.clamped.Inc(1)
+	}
+	return result, nil
 }
 
 func (n *dbNamespace) QueryIDs(
@@ -593,6 +734,11 @@ func (n *dbNamespace) QueryIDs(
 ) (index.QueryResults, error) {
 	callStart := n.nowFn()
 	if n.reverseIndex == nil { // only happens if indexing is enabled.
+		if n.shouldQueryByFullScan() {
+			res, err := n.queryIDsByFullScan(ctx, query, opts)
+			n.metrics.queryIDs.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+			return res, err
+		}
 		n.metrics.queryIDs.ReportError(n.nowFn().Sub(callStart))
 		return index.QueryResults{}, errNamespaceIndexingDisabled
 	}
@@ -607,12 +753,13 @@ func (n *dbNamespace) ReadEncoded(
 	start, end time.Time,
 ) ([][]xio.BlockReader, error) {
 	callStart := n.nowFn()
-	shard, err := n.readableShardFor(id)
+	shard, err := n.queryableShardFor(id)
 	if err != nil {
 		n.metrics.read.ReportError(n.nowFn().Sub(callStart))
 		return nil, err
 	}
 	res, err := shard.ReadEncoded(ctx, id, start, end)
+	atomic.AddUint64(&n.usageCounters.reads, 1)
 	n.metrics.read.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
 	return res, err
 }
@@ -776,6 +923,15 @@ func (n *dbNamespace) Bootstrap(start time.Time, process bootstrap.Process) erro
 
 	wg.Wait()
 
+	if skipped := bootstrapResult.SkippedRange; !skipped.IsEmpty() {
+		// NB: blocks older than the initial bootstrap lookback were not
+		// bootstrapped, mark them as flushed so flushing them does not
+		// write empty filesets over them.
+		for _, shard := range shards {
+			shard.MarkSkippedBlocksFlushed(skipped)
+		}
+	}
+
 	if n.reverseIndex != nil {
 		err := n.reverseIndex.Bootstrap(bootstrapResult.IndexResult.IndexResults())
 		multiErr = multiErr.Add(err)
@@ -1097,7 +1253,7 @@ func (n *dbNamespace) Repair(
 			mutex.Unlock()
 
 			if throttlePerShard > 0 {
-				time.Sleep(throttlePerShard)
+				n.sleepFn(throttlePerShard)
 			}
 		})
 	}
@@ -1130,6 +1286,49 @@ func (n *dbNamespace) GetOwnedShards() []databaseShard {
 	return databaseShards
 }
 
+func (n *dbNamespace) GetWritableShards() []databaseShard {
+	n.RLock()
+	shards := n.shardSet.AllIDs()
+	databaseShards := make([]databaseShard, 0, len(shards))
+	for _, shard := range shards {
+		if _, ok := n.readOnlyShards[shard]; ok {
+			continue
+		}
+		databaseShards = append(databaseShards, n.shards[shard])
+	}
+	n.RUnlock()
+	return databaseShards
+}
+
+func (n *dbNamespace) ExtendRetentionPeriod(value time.Duration) error {
+	if err := n.retentionOpts.ExtendRetentionPeriod(value); err != nil {
+		return err
+	}
+
+	// Blocks that fell out of the previous retention period may have had
+	// their flush states removed and their index blocks evicted while their
+	// filesets are yet to be cleaned up, mark them as flushed again and
+	// reload their index blocks so they can be read from disk.
+	for _, shard := range n.GetOwnedShards() {
+		shard.LoadFlushStates()
+	}
+	if n.reverseIndex != nil {
+		return n.reverseIndex.ReloadEvictedBlocks()
+	}
+	return nil
+}
+
+func (n *dbNamespace) SetIndexInsertMode(value namespace.IndexInsertMode) error {
+	if err := n.nsIndexOpts.UpdateInsertMode(value); err != nil {
+		return err
+	}
+	if n.reverseIndex != nil {
+		n.reverseIndex.SetInsertMode(indexInsertMode(value,
+			n.opts.IndexOptions().InsertMode()))
+	}
+	return nil
+}
+
 func (n *dbNamespace) GetIndex() (namespaceIndex, error) {
 	n.RLock()
 	defer n.RUnlock()
@@ -1139,18 +1338,37 @@ func (n *dbNamespace) GetIndex() (namespaceIndex, error) {
 	return n.reverseIndex, nil
 }
 
+// shardFor returns the shard to write the given ID to.
 func (n *dbNamespace) shardFor(id ident.ID) (databaseShard, error) {
 	n.RLock()
 	shardID := n.shardSet.Lookup(id)
 	shard, err := n.shardAtWithRLock(shardID)
+	_, readOnly := n.readOnlyShards[shardID]
 	n.RUnlock()
-	return shard, err
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		return nil, fmt.Errorf("shard %d is read only as it is not assigned to the node", shardID)
+	}
+	return shard, nil
 }
 
-func (n *dbNamespace) readableShardFor(id ident.ID) (databaseShard, error) {
+// queryableShardFor returns the shard to serve a read for the given ID, if
+// reading while bootstrapping is enabled this will return shards that have
+// not yet finished bootstrapping and only serve the data loaded so far.
+func (n *dbNamespace) queryableShardFor(id ident.ID) (databaseShard, error) {
 	n.RLock()
 	shardID := n.shardSet.Lookup(id)
-	shard, err := n.readableShardAtWithRLock(shardID)
+	var (
+		shard databaseShard
+		err   error
+	)
+	if n.opts.ReadWhileBootstrapping() {
+		shard, err = n.shardAtWithRLock(shardID)
+	} else {
+		shard, err = n.readableShardAtWithRLock(shardID)
+	}
 	n.RUnlock()
 	return shard, err
 }
@@ -1211,6 +1429,7 @@ func (n *dbNamespace) Close() error {
 	shards := n.shards
 	n.shards = shards[:0]
 	n.shardSet = sharding.NewEmptyShardSet(sharding.DefaultHashFn(1))
+	n.readOnlyShards = nil
 	n.Unlock()
 	n.namespaceReaderMgr.close()
 	n.closeShards(shards, true)
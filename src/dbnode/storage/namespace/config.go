@@ -48,20 +48,28 @@ func (m *MapConfiguration) Map() (Map, error) {
 
 // MetadataConfiguration is the configuration for a single namespace
 type MetadataConfiguration struct {
-	ID                string                  `yaml:"id" validate:"nonzero"`
-	BootstrapEnabled  *bool                   `yaml:"bootstrapEnabled"`
-	FlushEnabled      *bool                   `yaml:"flushEnabled"`
-	WritesToCommitLog *bool                   `yaml:"writesToCommitLog"`
-	CleanupEnabled    *bool                   `yaml:"cleanupEnabled"`
-	RepairEnabled     *bool                   `yaml:"repairEnabled"`
-	Retention         retention.Configuration `yaml:"retention" validate:"nonzero"`
-	Index             IndexConfiguration      `yaml:"index"`
+	ID                    string                  `yaml:"id" validate:"nonzero"`
+	BootstrapEnabled      *bool                   `yaml:"bootstrapEnabled"`
+	FlushEnabled          *bool                   `yaml:"flushEnabled"`
+	WritesToCommitLog     *bool                   `yaml:"writesToCommitLog"`
+	CleanupEnabled        *bool                   `yaml:"cleanupEnabled"`
+	RepairEnabled         *bool                   `yaml:"repairEnabled"`
+	Retention             retention.Configuration `yaml:"retention" validate:"nonzero"`
+	Index                 IndexConfiguration      `yaml:"index"`
+	Encoding              string                  `yaml:"encoding"`
+	ShardRoutingTag       string                  `yaml:"shardRoutingTag"`
+	FloatPrecisionDigits  int                     `yaml:"floatPrecisionDigits" validate:"min=0,max=17"`
+	IndexSummariesPercent float64                 `yaml:"indexSummariesPercent" validate:"min=0,max=1"`
+	Priority              *Priority               `yaml:"priority"`
 }
 
 // Metadata returns a Metadata corresponding to the receiver struct
 func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
+	ropts, err := mc.Retention.Options()
+	if err != nil {
+		return nil, err
+	}
 	iopts := mc.Index.Options()
-	ropts := mc.Retention.Options()
 	opts := NewOptions().
 		SetRetentionOptions(ropts).
 		SetIndexOptions(iopts)
@@ -80,18 +88,35 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.RepairEnabled; v != nil {
 		opts = opts.SetRepairEnabled(*v)
 	}
+	if v := mc.Encoding; v != "" {
+		opts = opts.SetEncoding(v)
+	}
+	if v := mc.ShardRoutingTag; v != "" {
+		opts = opts.SetShardRoutingTag(v)
+	}
+	if v := mc.FloatPrecisionDigits; v != 0 {
+		opts = opts.SetFloatPrecisionDigits(v)
+	}
+	if v := mc.IndexSummariesPercent; v != 0 {
+		opts = opts.SetIndexSummariesPercent(v)
+	}
+	if v := mc.Priority; v != nil {
+		opts = opts.SetPriority(*v)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
 // IndexConfiguration controls the knobs to tweak indexing configuration.
 type IndexConfiguration struct {
-	Enabled   bool          `yaml:"enabled" validate:"nonzero"`
-	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+	Enabled    bool            `yaml:"enabled" validate:"nonzero"`
+	BlockSize  time.Duration   `yaml:"blockSize" validate:"nonzero"`
+	InsertMode IndexInsertMode `yaml:"insertMode"`
 }
 
 // Options returns the IndexOptions corresponding to the receiver struct.
 func (ic *IndexConfiguration) Options() IndexOptions {
 	return NewIndexOptions().
 		SetEnabled(ic.Enabled).
-		SetBlockSize(ic.BlockSize)
+		SetBlockSize(ic.BlockSize).
+		SetInsertMode(ic.InsertMode)
 }
@@ -22,6 +22,7 @@ package namespace
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
@@ -56,6 +57,14 @@ func ToRetention(
 		SetBlockDataExpiryAfterNotAccessedPeriod(
 			fromNanos(ro.BlockDataExpiryAfterNotAccessPeriodNanos))
 
+	if ro.TimeZone != "" {
+		tz, err := time.LoadLocation(ro.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention time zone %q: %v", ro.TimeZone, err)
+		}
+		ropts = ropts.SetTimeZone(tz)
+	}
+
 	if err := ropts.Validate(); err != nil {
 		return nil, err
 	}
@@ -73,7 +82,8 @@ func ToIndexOptions(
 	}
 
 	iopts = iopts.SetEnabled(io.Enabled).
-		SetBlockSize(fromNanos(io.BlockSizeNanos))
+		SetBlockSize(fromNanos(io.BlockSizeNanos)).
+		SetInsertMode(IndexInsertMode(io.InsertMode))
 
 	return iopts, nil
 }
@@ -105,7 +115,12 @@ func ToMetadata(
 		SetWritesToCommitLog(opts.WritesToCommitLog).
 		SetSnapshotEnabled(opts.SnapshotEnabled).
 		SetRetentionOptions(ropts).
-		SetIndexOptions(iopts)
+		SetIndexOptions(iopts).
+		SetShardRoutingTag(opts.ShardRoutingTag).
+		SetFloatPrecisionDigits(int(opts.FloatPrecisionDigits)).
+		SetIndexSummariesPercent(opts.IndexSummariesPercent).
+		SetPriority(Priority(opts.Priority)).
+		SetEncoding(opts.Encoding)
 
 	return NewMetadata(ident.StringID(id), mopts)
 }
@@ -155,10 +170,24 @@ func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 			BufferPastNanos:                          ropts.BufferPast().Nanoseconds(),
 			BlockDataExpiry:                          ropts.BlockDataExpiry(),
 			BlockDataExpiryAfterNotAccessPeriodNanos: ropts.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds(),
+			TimeZone:                                 timeZoneName(ropts.TimeZone()),
 		},
 		IndexOptions: &nsproto.IndexOptions{
 			Enabled:        iopts.Enabled(),
 			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
+			InsertMode:     int32(iopts.InsertMode()),
 		},
+		ShardRoutingTag:       opts.ShardRoutingTag(),
+		FloatPrecisionDigits:  int32(opts.FloatPrecisionDigits()),
+		IndexSummariesPercent: opts.IndexSummariesPercent(),
+		Priority:              int32(opts.Priority()),
+		Encoding:              opts.Encoding(),
+	}
+}
+
+func timeZoneName(tz *time.Location) string {
+	if tz == nil {
+		return ""
 	}
+	return tz.String()
 }
@@ -101,6 +101,22 @@ func TestNamespaceToRetentionValid(t *testing.T) {
 	assertEqualRetentions(t, validOpts, ropts)
 }
 
+func TestNamespaceToRetentionTimeZone(t *testing.T) {
+	opts := validRetentionOpts
+	opts.BlockSizeNanos = toNanos(240) // 4h
+	opts.TimeZone = "Asia/Dubai"
+	ropts, err := namespace.ToRetention(&opts)
+	require.NoError(t, err)
+	assertEqualRetentions(t, opts, ropts)
+
+	nsOpts := namespace.NewOptions().SetRetentionOptions(ropts)
+	require.Equal(t, "Asia/Dubai", namespace.OptionsToProto(nsOpts).RetentionOptions.TimeZone)
+
+	opts.TimeZone = "Not/AZone"
+	_, err = namespace.ToRetention(&opts)
+	require.Error(t, err)
+}
+
 func TestNamespaceToRetentionInvalid(t *testing.T) {
 	for _, opts := range invalidRetentionOpts {
 		_, err := namespace.ToRetention(&opts)
@@ -217,6 +233,68 @@ func TestFromProtoSnapshotEnabled(t *testing.T) {
 	assert.Equal(t, !namespace.NewOptions().SnapshotEnabled(), md.Options().SnapshotEnabled())
 }
 
+func TestShardRoutingTagRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().SetShardRoutingTag("tenant"),
+	)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Equal(t, "tenant", reg.Namespaces["ns1"].ShardRoutingTag)
+
+	fromProto, err := namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = fromProto.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.Equal(t, "tenant", md.Options().ShardRoutingTag())
+}
+
+func TestFloatPrecisionDigitsRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().SetFloatPrecisionDigits(12),
+	)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Equal(t, int32(12), reg.Namespaces["ns1"].FloatPrecisionDigits)
+
+	fromProto, err := namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = fromProto.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.Equal(t, 12, md.Options().FloatPrecisionDigits())
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().SetEncoding("intdelta"),
+	)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Equal(t, "intdelta", reg.Namespaces["ns1"].Encoding)
+
+	data, err := reg.Marshal()
+	require.NoError(t, err)
+	var unmarshalled nsproto.Registry
+	require.NoError(t, unmarshalled.Unmarshal(data))
+
+	fromProto, err := namespace.FromProto(unmarshalled)
+	require.NoError(t, err)
+	md, err = fromProto.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.Equal(t, "intdelta", md.Options().Encoding())
+}
+
 func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOptions, observed namespace.Metadata) {
 	require.Equal(t, name, observed.ID().String())
 	opts := observed.Options()
@@ -238,4 +316,9 @@ func assertEqualRetentions(t *testing.T, expected nsproto.RetentionOptions, obse
 	require.Equal(t, expected.BlockDataExpiry, observed.BlockDataExpiry())
 	require.Equal(t, expected.BlockDataExpiryAfterNotAccessPeriodNanos,
 		observed.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds())
+	if expected.TimeZone == "" {
+		require.Nil(t, observed.TimeZone())
+	} else {
+		require.Equal(t, expected.TimeZone, observed.TimeZone().String())
+	}
 }
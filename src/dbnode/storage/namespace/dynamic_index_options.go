@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"sync"
+	"time"
+)
+
+type dynamicIndexOpts struct {
+	sync.RWMutex
+	opts IndexOptions
+}
+
+// NewDynamicIndexOptions returns a new set of dynamic index options wrapping
+// the given options, setters return static copies of the current options.
+func NewDynamicIndexOptions(opts IndexOptions) DynamicIndexOptions {
+	return &dynamicIndexOpts{opts: opts}
+}
+
+func (o *dynamicIndexOpts) current() IndexOptions {
+	o.RLock()
+	opts := o.opts
+	o.RUnlock()
+	return opts
+}
+
+func (o *dynamicIndexOpts) UpdateInsertMode(value IndexInsertMode) error {
+	if err := ValidateIndexInsertMode(value); err != nil {
+		return err
+	}
+	o.Lock()
+	o.opts = o.opts.SetInsertMode(value)
+	o.Unlock()
+	return nil
+}
+
+func (o *dynamicIndexOpts) Equal(value IndexOptions) bool {
+	return o.current().Equal(value)
+}
+
+func (o *dynamicIndexOpts) SetEnabled(value bool) IndexOptions {
+	return o.current().SetEnabled(value)
+}
+
+func (o *dynamicIndexOpts) Enabled() bool {
+	return o.current().Enabled()
+}
+
+func (o *dynamicIndexOpts) SetBlockSize(value time.Duration) IndexOptions {
+	return o.current().SetBlockSize(value)
+}
+
+func (o *dynamicIndexOpts) BlockSize() time.Duration {
+	return o.current().BlockSize()
+}
+
+func (o *dynamicIndexOpts) SetInsertMode(value IndexInsertMode) IndexOptions {
+	return o.current().SetInsertMode(value)
+}
+
+func (o *dynamicIndexOpts) InsertMode() IndexInsertMode {
+	return o.current().InsertMode()
+}
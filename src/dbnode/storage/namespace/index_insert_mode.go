@@ -0,0 +1,102 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"fmt"
+)
+
+// IndexInsertMode is the mode new series of a namespace are indexed with.
+type IndexInsertMode uint
+
+const (
+	// IndexInsertModeDefault specifies new series are indexed with the insert
+	// mode the node is configured with.
+	IndexInsertModeDefault IndexInsertMode = iota
+	// IndexInsertModeSync specifies writes of new series wait until the series
+	// are indexed, making them immediately queryable.
+	IndexInsertModeSync
+	// IndexInsertModeAsync specifies writes of new series return once the
+	// series are queued for indexing.
+	IndexInsertModeAsync
+)
+
+// ValidIndexInsertModes returns the valid index insert modes.
+func ValidIndexInsertModes() []IndexInsertMode {
+	return []IndexInsertMode{
+		IndexInsertModeDefault,
+		IndexInsertModeSync,
+		IndexInsertModeAsync,
+	}
+}
+
+func (m IndexInsertMode) String() string {
+	switch m {
+	case IndexInsertModeDefault:
+		return "default"
+	case IndexInsertModeSync:
+		return "sync"
+	case IndexInsertModeAsync:
+		return "async"
+	}
+	return "unknown"
+}
+
+// ValidateIndexInsertMode validates an index insert mode.
+func ValidateIndexInsertMode(v IndexInsertMode) error {
+	for _, valid := range ValidIndexInsertModes() {
+		if valid == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid namespace IndexInsertMode '%d' valid types are: %v",
+		uint(v), ValidIndexInsertModes())
+}
+
+// ParseIndexInsertMode parses an IndexInsertMode from a string.
+func ParseIndexInsertMode(str string) (IndexInsertMode, error) {
+	var r IndexInsertMode
+	if str == "" {
+		return IndexInsertModeDefault, nil
+	}
+	for _, valid := range ValidIndexInsertModes() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid namespace IndexInsertMode '%s' valid types are: %v",
+		str, ValidIndexInsertModes())
+}
+
+// UnmarshalYAML unmarshals an IndexInsertMode into a valid type from string.
+func (m *IndexInsertMode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseIndexInsertMode(str)
+	if err != nil {
+		return err
+	}
+	*m = r
+	return nil
+}
@@ -33,8 +33,9 @@ var (
 )
 
 type indexOpts struct {
-	enabled   bool
-	blockSize time.Duration
+	enabled    bool
+	blockSize  time.Duration
+	insertMode IndexInsertMode
 }
 
 // NewIndexOptions returns a new IndexOptions.
@@ -47,7 +48,8 @@ func NewIndexOptions() IndexOptions {
 
 func (i *indexOpts) Equal(value IndexOptions) bool {
 	return i.Enabled() == value.Enabled() &&
-		i.BlockSize() == value.BlockSize()
+		i.BlockSize() == value.BlockSize() &&
+		i.InsertMode() == value.InsertMode()
 }
 
 func (i *indexOpts) SetEnabled(value bool) IndexOptions {
@@ -69,3 +71,13 @@ func (i *indexOpts) SetBlockSize(value time.Duration) IndexOptions {
 func (i *indexOpts) BlockSize() time.Duration {
 	return i.blockSize
 }
+
+func (i *indexOpts) SetInsertMode(value IndexInsertMode) IndexOptions {
+	io := *i
+	io.insertMode = value
+	return &io
+}
+
+func (i *indexOpts) InsertMode() IndexInsertMode {
+	return i.insertMode
+}
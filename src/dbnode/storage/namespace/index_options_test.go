@@ -45,3 +45,30 @@ func TestIndexOptionsBlockSize(t *testing.T) {
 	opts := NewIndexOptions()
 	require.Equal(t, time.Hour, opts.SetBlockSize(time.Hour).BlockSize())
 }
+
+func TestIndexOptionsInsertMode(t *testing.T) {
+	opts := NewIndexOptions()
+	require.Equal(t, IndexInsertModeDefault, opts.InsertMode())
+	require.Equal(t, IndexInsertModeAsync,
+		opts.SetInsertMode(IndexInsertModeAsync).InsertMode())
+	require.False(t, opts.Equal(opts.SetInsertMode(IndexInsertModeSync)))
+}
+
+func TestDynamicIndexOptionsUpdateInsertMode(t *testing.T) {
+	opts := NewDynamicIndexOptions(NewIndexOptions().SetBlockSize(time.Hour))
+	require.NoError(t, opts.UpdateInsertMode(IndexInsertModeSync))
+	require.Equal(t, IndexInsertModeSync, opts.InsertMode())
+	require.Equal(t, time.Hour, opts.BlockSize())
+	require.Error(t, opts.UpdateInsertMode(IndexInsertMode(10)))
+	require.Equal(t, IndexInsertModeSync, opts.InsertMode())
+}
+
+func TestParseIndexInsertMode(t *testing.T) {
+	for _, mode := range ValidIndexInsertModes() {
+		parsed, err := ParseIndexInsertMode(mode.String())
+		require.NoError(t, err)
+		require.Equal(t, mode, parsed)
+	}
+	_, err := ParseIndexInsertMode("eventually")
+	require.Error(t, err)
+}
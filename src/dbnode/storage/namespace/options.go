@@ -23,6 +23,7 @@ package namespace
 import (
 	"errors"
 
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/retention"
 )
 
@@ -50,17 +51,24 @@ var (
 	errIndexBlockSizePositive                       = errors.New("index block size must positive")
 	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
+	errFloatPrecisionDigitsInvalid                  = errors.New("float precision digits must be between 0 and 17")
+	errIndexSummariesPercentInvalid                 = errors.New("index summaries percent must be between 0 and 1")
 )
 
 type options struct {
-	bootstrapEnabled  bool
-	flushEnabled      bool
-	snapshotEnabled   bool
-	writesToCommitLog bool
-	cleanupEnabled    bool
-	repairEnabled     bool
-	retentionOpts     retention.Options
-	indexOpts         IndexOptions
+	bootstrapEnabled     bool
+	flushEnabled         bool
+	snapshotEnabled      bool
+	writesToCommitLog    bool
+	cleanupEnabled       bool
+	repairEnabled        bool
+	retentionOpts        retention.Options
+	indexOpts            IndexOptions
+	encoding             string
+	shardRoutingTag      string
+	floatPrecisionDigits int
+	summariesPercent     float64
+	priority             Priority
 }
 
 // NewOptions creates a new namespace options
@@ -74,6 +82,7 @@ func NewOptions() Options {
 		repairEnabled:     defaultRepairEnabled,
 		retentionOpts:     retention.NewOptions(),
 		indexOpts:         NewIndexOptions(),
+		priority:          DefaultPriority,
 	}
 }
 
@@ -81,6 +90,18 @@ func (o *options) Validate() error {
 	if err := o.retentionOpts.Validate(); err != nil {
 		return err
 	}
+	if o.floatPrecisionDigits < 0 || o.floatPrecisionDigits > m3tsz.MaxFloatPrecisionDigits {
+		return errFloatPrecisionDigitsInvalid
+	}
+	if o.summariesPercent < 0 || o.summariesPercent > 1 {
+		return errIndexSummariesPercentInvalid
+	}
+	if err := ValidatePriority(o.priority); err != nil {
+		return err
+	}
+	if err := ValidateIndexInsertMode(o.indexOpts.InsertMode()); err != nil {
+		return err
+	}
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -109,7 +130,12 @@ func (o *options) Equal(value Options) bool {
 		o.cleanupEnabled == value.CleanupEnabled() &&
 		o.repairEnabled == value.RepairEnabled() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
-		o.indexOpts.Equal(value.IndexOptions())
+		o.indexOpts.Equal(value.IndexOptions()) &&
+		o.encoding == value.Encoding() &&
+		o.shardRoutingTag == value.ShardRoutingTag() &&
+		o.floatPrecisionDigits == value.FloatPrecisionDigits() &&
+		o.summariesPercent == value.IndexSummariesPercent() &&
+		o.priority == value.Priority()
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -191,3 +217,53 @@ func (o *options) SetIndexOptions(value IndexOptions) Options {
 func (o *options) IndexOptions() IndexOptions {
 	return o.indexOpts
 }
+
+func (o *options) SetEncoding(value string) Options {
+	opts := *o
+	opts.encoding = value
+	return &opts
+}
+
+func (o *options) Encoding() string {
+	return o.encoding
+}
+
+func (o *options) SetShardRoutingTag(value string) Options {
+	opts := *o
+	opts.shardRoutingTag = value
+	return &opts
+}
+
+func (o *options) ShardRoutingTag() string {
+	return o.shardRoutingTag
+}
+
+func (o *options) SetFloatPrecisionDigits(value int) Options {
+	opts := *o
+	opts.floatPrecisionDigits = value
+	return &opts
+}
+
+func (o *options) FloatPrecisionDigits() int {
+	return o.floatPrecisionDigits
+}
+
+func (o *options) SetIndexSummariesPercent(value float64) Options {
+	opts := *o
+	opts.summariesPercent = value
+	return &opts
+}
+
+func (o *options) IndexSummariesPercent() float64 {
+	return o.summariesPercent
+}
+
+func (o *options) SetPriority(value Priority) Options {
+	opts := *o
+	opts.priority = value
+	return &opts
+}
+
+func (o *options) Priority() Priority {
+	return o.priority
+}
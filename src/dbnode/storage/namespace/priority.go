@@ -0,0 +1,137 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package namespace
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errPriorityUnspecified = errors.New("namespace priority unspecified")
+)
+
+// Priority is the priority class of a namespace, it controls the order in
+// which namespaces are bootstrapped, flushed, snapshotted and have their
+// index compacted and flushed, and how much of the persist rate limit they
+// are allowed to use when they compete for the same resources.
+type Priority uint
+
+const (
+	// PriorityNormal specifies the namespace is processed after critical
+	// namespaces and before background namespaces.
+	PriorityNormal Priority = iota
+	// PriorityCritical specifies the namespace is processed before all
+	// other namespaces.
+	PriorityCritical
+	// PriorityBackground specifies the namespace is processed after all
+	// other namespaces.
+	PriorityBackground
+
+	// DefaultPriority is the default namespace priority.
+	DefaultPriority = PriorityNormal
+)
+
+// ValidPriorities returns the valid namespace priorities.
+func ValidPriorities() []Priority {
+	return []Priority{PriorityCritical, PriorityNormal, PriorityBackground}
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityNormal:
+		return "normal"
+	case PriorityBackground:
+		return "background"
+	}
+	return "unknown"
+}
+
+// Before returns whether the priority is processed before another priority.
+func (p Priority) Before(other Priority) bool {
+	return p.rank() < other.rank()
+}
+
+// PersistRateLimitScale returns the scale applied to the persist rate limit
+// when flushing and snapshotting namespaces of the priority, critical
+// namespaces persist faster than the limit but remain rate limited so they
+// cannot saturate the disk.
+func (p Priority) PersistRateLimitScale() float64 {
+	switch p {
+	case PriorityCritical:
+		return 2
+	case PriorityNormal:
+		return 1
+	}
+	return 0.5
+}
+
+func (p Priority) rank() int {
+	switch p {
+	case PriorityCritical:
+		return 0
+	case PriorityNormal:
+		return 1
+	}
+	return 2
+}
+
+// ValidatePriority validates a namespace priority.
+func ValidatePriority(v Priority) error {
+	for _, valid := range ValidPriorities() {
+		if valid == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid namespace Priority '%d' valid types are: %v",
+		uint(v), ValidPriorities())
+}
+
+// ParsePriority parses a Priority from a string.
+func ParsePriority(str string) (Priority, error) {
+	var r Priority
+	if str == "" {
+		return r, errPriorityUnspecified
+	}
+	for _, valid := range ValidPriorities() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid namespace Priority '%s' valid types are: %v",
+		str, ValidPriorities())
+}
+
+// UnmarshalYAML unmarshals a Priority into a valid type from string.
+func (p *Priority) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParsePriority(str)
+	if err != nil {
+		return err
+	}
+	*p = r
+	return nil
+}
@@ -84,6 +84,46 @@ type Options interface {
 
 	// IndexOptions returns the IndexOptions.
 	IndexOptions() IndexOptions
+
+	// SetEncoding sets the name of the registered codec used to encode data
+	// for this namespace, the database default is used if empty. The codec
+	// is not recorded in the metadata of persisted blocks so it must not be
+	// changed once the namespace holds data.
+	SetEncoding(value string) Options
+
+	// Encoding returns the name of the registered codec used to encode data
+	// for this namespace, the database default is used if empty.
+	Encoding() string
+
+	// SetShardRoutingTag sets the tag whose value alone is hashed to pick the
+	// shard of a series, series without the tag are hashed by their ID.
+	SetShardRoutingTag(value string) Options
+
+	// ShardRoutingTag returns the tag whose value alone is hashed to pick the
+	// shard of a series, series without the tag are hashed by their ID.
+	ShardRoutingTag() string
+
+	// SetFloatPrecisionDigits sets the number of significant decimal digits float
+	// values are rounded to before encoding, zero keeps full precision.
+	SetFloatPrecisionDigits(value int) Options
+
+	// FloatPrecisionDigits returns the number of significant decimal digits float
+	// values are rounded to before encoding, zero keeps full precision.
+	FloatPrecisionDigits() int
+
+	// SetIndexSummariesPercent sets the percent of fileset index entries
+	// sampled into the index summaries, zero uses the filesystem default.
+	SetIndexSummariesPercent(value float64) Options
+
+	// IndexSummariesPercent returns the percent of fileset index entries
+	// sampled into the index summaries, zero uses the filesystem default.
+	IndexSummariesPercent() float64
+
+	// SetPriority sets the priority class of the namespace.
+	SetPriority(value Priority) Options
+
+	// Priority returns the priority class of the namespace.
+	Priority() Priority
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -102,6 +142,21 @@ type IndexOptions interface {
 
 	// BlockSize returns the block size.
 	BlockSize() time.Duration
+
+	// SetInsertMode sets the mode new series are indexed with.
+	SetInsertMode(value IndexInsertMode) IndexOptions
+
+	// InsertMode returns the mode new series are indexed with.
+	InsertMode() IndexInsertMode
+}
+
+// DynamicIndexOptions is a set of index options whose insert mode can be
+// changed at runtime, readers always observe the latest insert mode.
+type DynamicIndexOptions interface {
+	IndexOptions
+
+	// UpdateInsertMode changes the insert mode in place.
+	UpdateInsertMode(value IndexInsertMode) error
 }
 
 // Metadata represents namespace metadata information
@@ -0,0 +1,207 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3x/context"
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+// shouldQueryByFullScan returns whether the namespace, which has indexing
+// disabled, has few enough series to be queried by scanning the series of
+// its shards.
+func (n *dbNamespace) shouldQueryByFullScan() bool {
+	maxSeries := n.opts.IndexOptions().FullScanMaxSeries()
+	if maxSeries <= 0 {
+		return false
+	}
+
+	var numSeries int64
+	for _, shard := range n.GetOwnedShards() {
+		numSeries += shard.NumSeries()
+		if numSeries >= int64(maxSeries) {
+			return false
+		}
+	}
+	return true
+}
+
+// fullScanMayMissSeries returns whether a full scan may not match every
+// series with data in the query range, which is the case while shards are
+// bootstrapping or once blocks in the range are flushed since series whose
+// blocks are all flushed are evicted from the shards.
+func (n *dbNamespace) fullScanMayMissSeries(opts index.QueryOptions) bool {
+	var (
+		ropts     = n.nopts.RetentionOptions()
+		blockSize = ropts.BlockSize()
+		start     = opts.StartInclusive.Truncate(blockSize)
+	)
+	if earliest := retention.FlushTimeStart(ropts, n.nowFn()); start.Before(earliest) {
+		start = earliest
+	}
+	for _, shard := range n.GetOwnedShards() {
+		if !shard.IsBootstrapped() {
+			return true
+		}
+		for t := start; t.Before(opts.EndExclusive); t = t.Add(blockSize) {
+			if shard.FlushState(t).Status != fileOpNotStarted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queryIDsByFullScan resolves the query against a temporary in-memory segment
+// built from the series of the owned shards. Series are matched by their tags
+// regardless of the query time range, much like the index matches series
+// by the blocks they were written to, and the results are only exhaustive if
+// no series with data in the range may have been evicted from the shards.
+func (n *dbNamespace) queryIDsByFullScan(
+	ctx context.Context,
+	query index.Query,
+	opts index.QueryOptions,
+) (index.QueryResults, error) {
+	indexOpts := n.opts.IndexOptions()
+	seg, err := mem.NewSegment(0, indexOpts.MemSegmentOptions())
+	if err != nil {
+		return index.QueryResults{}, err
+	}
+
+	mayMissSeries := n.fullScanMayMissSeries(opts)
+	for _, shard := range n.GetOwnedShards() {
+		var insertErr error
+		err := shard.ForEachSeriesDocument(func(d doc.Document) bool {
+			_, insertErr = seg.Insert(d)
+			return insertErr == nil
+		})
+		if err == nil {
+			err = insertErr
+		}
+		if err != nil {
+			seg.Close()
+			return index.QueryResults{}, err
+		}
+	}
+
+	reader, err := seg.Reader()
+	if err != nil {
+		seg.Close()
+		return index.QueryResults{}, err
+	}
+
+	results := newQueryResults(n.ID(), opts, indexOpts)
+
+	// NB: streaming and the query limits are applied the same way as the
+	// index applies them.
+	var (
+		streaming    index.StreamingResults
+		limited      index.LimitedResults
+		queryResults = results
+	)
+	if opts.StreamFn != nil {
+		streaming = index.NewStreamingResults(results, n.ID(),
+			opts.StreamBatchSize, opts.StreamFn)
+		queryResults = streaming
+	}
+	if opts.MaxResults > 0 || opts.MaxSeriesMatched > 0 {
+		limited = index.NewLimitedResults(queryResults, opts.MaxResults,
+			opts.MaxSeriesMatched)
+		queryResults = limited
+	}
+
+	exec := executor.NewExecutor([]m3ninxindex.Reader{reader})
+	exhaustive, err := n.executeFullScan(exec, query, opts, queryResults, streaming)
+	if err != nil && limited != nil && limited.LimitExceeded() != nil {
+		exhaustive, err = false, nil
+	}
+	if err == nil && streaming != nil {
+		err = streaming.Flush()
+	}
+
+	multiErr := xerrors.NewMultiError()
+	multiErr = multiErr.Add(err)
+	multiErr = multiErr.Add(exec.Close())
+	multiErr = multiErr.Add(seg.Close())
+	if err := multiErr.FinalError(); err != nil {
+		results.Finalize()
+		return index.QueryResults{}, err
+	}
+
+	var warnings []index.QueryLimitExceeded
+	if limited != nil {
+		if exceeded := limited.LimitExceeded(); exceeded != nil {
+			warnings = append(warnings, *exceeded)
+		}
+	}
+
+	ctx.RegisterFinalizer(results)
+	return index.QueryResults{
+		Results:    results,
+		Exhaustive: exhaustive && !mayMissSeries,
+		Warnings:   warnings,
+	}, nil
+}
+
+func (n *dbNamespace) executeFullScan(
+	exec search.Executor,
+	query index.Query,
+	opts index.QueryOptions,
+	results index.Results,
+	streaming index.StreamingResults,
+) (bool, error) {
+	iter, err := exec.Execute(query.Query.SearchQuery())
+	if err != nil {
+		return false, err
+	}
+
+	exhaustive := true
+	for iter.Next() {
+		if opts.Limit > 0 && results.Size() >= opts.Limit {
+			exhaustive = false
+			break
+		}
+		d := iter.Current()
+		if _, _, err := results.Add(d); err != nil {
+			iter.Close()
+			return false, err
+		}
+		if streaming != nil && streaming.Full() {
+			if err := streaming.Flush(); err != nil {
+				iter.Close()
+				return false, err
+			}
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return false, err
+	}
+	return exhaustive, iter.Close()
+}
@@ -164,7 +164,13 @@ func (m *namespaceReaderManager) filesetExistsAt(
 	shard uint32,
 	blockStart time.Time,
 ) (bool, error) {
-	return m.filesetExistsAtFn(m.fsOpts.FilePathPrefix(),
+	exists, err := m.filesetExistsAtFn(m.fsOpts.FilePathPrefix(),
+		m.namespace.ID(), shard, blockStart)
+	warmFilePathPrefix := m.fsOpts.WarmFilePathPrefix()
+	if err != nil || exists || warmFilePathPrefix == "" {
+		return exists, err
+	}
+	return m.filesetExistsAtFn(warmFilePathPrefix,
 		m.namespace.ID(), shard, blockStart)
 }
 
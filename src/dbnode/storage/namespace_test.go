@@ -38,6 +38,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/x/metrics"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3cluster/shard"
 	"github.com/m3db/m3x/context"
 	xerrors "github.com/m3db/m3x/errors"
@@ -93,6 +95,83 @@ func TestNamespaceName(t *testing.T) {
 	require.True(t, defaultTestNs1ID.Equal(ns.ID()))
 }
 
+func TestNamespaceShardRoutingTag(t *testing.T) {
+	opts := defaultTestNs1Opts.SetShardRoutingTag("tenant")
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, opts)
+	require.NoError(t, err)
+	hashFn := func(identifier ident.ID) uint32 {
+		if identifier.String() == "foo" {
+			return testShardIDs[1].ID()
+		}
+		return testShardIDs[0].ID()
+	}
+	shardSet, err := sharding.NewShardSet(testShardIDs, hashFn)
+	require.NoError(t, err)
+	dopts := testDatabaseOptions().SetRuntimeOptionsManager(runtime.NewOptionsManager())
+	defer dopts.RuntimeOptionsManager().Close()
+	dbNs, err := newDatabaseNamespace(metadata, shardSet, nil, nil, nil, dopts)
+	require.NoError(t, err)
+	ns := dbNs.(*dbNamespace)
+
+	shard, err := ns.shardFor(ident.StringID("__name__=cpu,tenant=foo,"))
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[1].ID(), shard.ID())
+
+	shard, err = ns.shardFor(ident.StringID("__name__=cpu,tenant=bar,"))
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[0].ID(), shard.ID())
+
+	// Routing is preserved when a new shard set is assigned.
+	ns.AssignShardSet(shardSet)
+	shard, err = ns.shardFor(ident.StringID("__name__=mem,tenant=foo,"))
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[1].ID(), shard.ID())
+}
+
+func TestNamespaceShardForReadOnlyShard(t *testing.T) {
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts)
+	require.NoError(t, err)
+	hashFn := func(identifier ident.ID) uint32 {
+		if identifier.String() == "foo" {
+			return testShardIDs[1].ID()
+		}
+		return testShardIDs[0].ID()
+	}
+	readOnlyShards := []shard.Shard{
+		testShardIDs[0],
+		shard.NewShard(testShardIDs[1].ID()).SetState(ReadOnlyShardState),
+	}
+	shardSet, err := sharding.NewShardSet(readOnlyShards, hashFn)
+	require.NoError(t, err)
+	dopts := testDatabaseOptions().SetRuntimeOptionsManager(runtime.NewOptionsManager())
+	defer dopts.RuntimeOptionsManager().Close()
+	dbNs, err := newDatabaseNamespace(metadata, shardSet, nil, nil, nil, dopts)
+	require.NoError(t, err)
+	ns := dbNs.(*dbNamespace)
+
+	shard, err := ns.shardFor(ident.StringID("bar"))
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[0].ID(), shard.ID())
+
+	// The read only shard is open for reads but rejects writes.
+	_, err = ns.shardFor(ident.StringID("foo"))
+	require.Error(t, err)
+	shard, err = ns.shardAtWithRLock(testShardIDs[1].ID())
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[1].ID(), shard.ID())
+	require.Len(t, ns.GetOwnedShards(), 2)
+	require.Len(t, ns.GetWritableShards(), 1)
+
+	// Once the placement assigns the shard it becomes writable.
+	shardSet, err = sharding.NewShardSet(testShardIDs, hashFn)
+	require.NoError(t, err)
+	ns.AssignShardSet(shardSet)
+	shard, err = ns.shardFor(ident.StringID("foo"))
+	require.NoError(t, err)
+	require.Equal(t, testShardIDs[1].ID(), shard.ID())
+	require.Len(t, ns.GetWritableShards(), 2)
+}
+
 func TestNamespaceTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -141,7 +220,7 @@ func TestNamespaceWriteShardNotOwned(t *testing.T) {
 	for i := range ns.shards {
 		ns.shards[i] = nil
 	}
-	err := ns.Write(ctx, ident.StringID("foo"), time.Now(), 0.0, xtime.Second, nil)
+	_, err := ns.Write(ctx, ident.StringID("foo"), time.Now(), 0.0, xtime.Second, nil)
 	require.Error(t, err)
 	require.True(t, xerrors.IsRetryableError(err))
 	require.Equal(t, "not responsible for shard 0", err.Error())
@@ -163,10 +242,33 @@ func TestNamespaceWriteShardOwned(t *testing.T) {
 	ns, closer := newTestNamespace(t)
 	defer closer()
 	shard := NewMockdatabaseShard(ctrl)
-	shard.EXPECT().Write(ctx, id, ts, val, unit, ant).Return(nil)
+	shard.EXPECT().Write(ctx, id, ts, val, unit, ant).Return(true, nil)
 	ns.shards[testShardIDs[0].ID()] = shard
 
-	require.NoError(t, ns.Write(ctx, id, ts, val, unit, ant))
+	_, err := ns.Write(ctx, id, ts, val, unit, ant)
+	require.NoError(t, err)
+}
+
+func TestNamespaceWriteTruncatesFloatPrecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	id := ident.StringID("foo")
+	ts := time.Now()
+	unit := xtime.Second
+
+	ns, closer := newTestNamespaceWithIDOpts(t, defaultTestNs1ID,
+		defaultTestNs1Opts.SetFloatPrecisionDigits(4))
+	defer closer()
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().Write(ctx, id, ts, 1.235, unit, nil).Return(true, nil)
+	ns.shards[testShardIDs[0].ID()] = shard
+
+	_, err := ns.Write(ctx, id, ts, 1.23456, unit, nil)
+	require.NoError(t, err)
 }
 
 func TestNamespaceReadEncodedShardNotOwned(t *testing.T) {
@@ -212,6 +314,30 @@ func TestNamespaceReadEncodedShardOwned(t *testing.T) {
 	require.Equal(t, errShardNotBootstrappedToRead, xerrors.GetInnerRetryableError(err))
 }
 
+func TestNamespaceReadEncodedWhileBootstrapping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	id := ident.StringID("foo")
+	start := time.Now()
+	end := time.Now().Add(time.Second)
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+	ns.opts = ns.opts.SetReadWhileBootstrapping(true)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().IsBootstrapped().Return(false).AnyTimes()
+	shard.EXPECT().ReadEncoded(ctx, id, start, end).Return(nil, nil)
+	ns.shards[testShardIDs[0].ID()] = shard
+
+	_, err := ns.ReadEncoded(ctx, id, start, end)
+	require.NoError(t, err)
+}
+
 func TestNamespaceFetchBlocksShardNotOwned(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
@@ -1094,10 +1220,10 @@ func TestNamespaceIndexInsert(t *testing.T) {
 
 	shard := NewMockdatabaseShard(ctrl)
 	shard.EXPECT().WriteTagged(ctx, ident.NewIDMatcher("a"), ident.EmptyTagIterator,
-		ts, 1.0, xtime.Second, nil).Return(nil)
+		ts, 1.0, xtime.Second, nil).Return(true, nil)
 	ns.shards[testShardIDs[0].ID()] = shard
 
-	err := ns.WriteTagged(ctx, ident.StringID("a"),
+	_, err := ns.WriteTagged(ctx, ident.StringID("a"),
 		ident.EmptyTagIterator, ts, 1.0, xtime.Second, nil)
 	require.NoError(t, err)
 
@@ -1106,6 +1232,26 @@ func TestNamespaceIndexInsert(t *testing.T) {
 	require.NoError(t, ns.Close())
 }
 
+func TestNamespaceWriteTaggedRejectsUnroutableID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idx := NewMocknamespaceIndex(ctrl)
+	ns, closer := newTestNamespaceWithIndex(t, idx)
+	defer closer()
+	ns.shardRoutingTag = []byte("tenant")
+
+	ctx := context.NewContext()
+	tags := ident.NewTagsIterator(ident.NewTags(ident.StringTag("tenant", "foo")))
+	_, err := ns.WriteTagged(ctx, ident.StringID("7f1a2b3c4d5e6f70"),
+		tags, time.Now(), 1.0, xtime.Second, nil)
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	idx.EXPECT().Close().Return(nil)
+	require.NoError(t, ns.Close())
+}
+
 func TestNamespaceIndexQuery(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1154,6 +1300,103 @@ func TestNamespaceIndexDisabledQuery(t *testing.T) {
 	require.NoError(t, ns.Close())
 }
 
+func TestNamespaceIndexDisabledQueryByFullScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+	ns.opts = ns.opts.SetIndexOptions(ns.opts.IndexOptions().
+		SetFullScanMaxSeries(10))
+
+	for i := range testShardIDs {
+		d := doc.Document{
+			ID: []byte(fmt.Sprintf("foo%d", i)),
+			Fields: []doc.Field{
+				{Name: []byte("shard"), Value: []byte(fmt.Sprintf("%d", i))},
+			},
+		}
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().NumSeries().Return(int64(1))
+		shard.EXPECT().IsBootstrapped().Return(true)
+		shard.EXPECT().ForEachSeriesDocument(gomock.Any()).DoAndReturn(
+			func(fn func(doc.Document) bool) error {
+				fn(d)
+				return nil
+			})
+		ns.shards[testShardIDs[i].ID()] = shard
+	}
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	query := index.Query{Query: idx.NewTermQuery([]byte("shard"), []byte("1"))}
+	res, err := ns.QueryIDs(ctx, query, index.QueryOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Exhaustive)
+	require.Equal(t, 1, res.Results.Size())
+	_, ok := res.Results.Map().Get(ident.StringID("foo1"))
+	require.True(t, ok)
+}
+
+func TestNamespaceIndexDisabledQueryByFullScanStreamedNotExhaustive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+	ns.opts = ns.opts.SetIndexOptions(ns.opts.IndexOptions().
+		SetFullScanMaxSeries(10))
+
+	var (
+		blockSize = ns.nopts.RetentionOptions().BlockSize()
+		end       = time.Now().Truncate(blockSize)
+		start     = end.Add(-blockSize)
+	)
+	for i := range testShardIDs {
+		d := doc.Document{
+			ID:     []byte(fmt.Sprintf("foo%d", i)),
+			Fields: []doc.Field{{Name: []byte("name"), Value: []byte("foo")}},
+		}
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().NumSeries().Return(int64(1))
+		shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+		shard.EXPECT().ForEachSeriesDocument(gomock.Any()).DoAndReturn(
+			func(fn func(doc.Document) bool) error {
+				fn(d)
+				return nil
+			})
+		// series of a flushed block may have been evicted.
+		status := fileOpNotStarted
+		if i == 0 {
+			status = fileOpSuccess
+		}
+		shard.EXPECT().FlushState(start).Return(fileOpState{Status: status}).AnyTimes()
+		ns.shards[testShardIDs[i].ID()] = shard
+	}
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	var streamed []string
+	query := index.Query{Query: idx.NewTermQuery([]byte("name"), []byte("foo"))}
+	res, err := ns.QueryIDs(ctx, query, index.QueryOptions{
+		StartInclusive:  start,
+		EndExclusive:    end,
+		StreamBatchSize: 1,
+		StreamFn: func(batch index.Results) error {
+			for _, entry := range batch.Map().Iter() {
+				streamed = append(streamed, entry.Key().String())
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, res.Exhaustive)
+	require.Equal(t, 0, res.Results.Map().Len())
+	require.Len(t, streamed, len(testShardIDs))
+}
+
 func TestNamespaceBootstrapState(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
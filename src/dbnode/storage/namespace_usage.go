@@ -0,0 +1,127 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+)
+
+// NamespaceUsage is the utilization of a namespace on a node.
+type NamespaceUsage struct {
+	Namespace        string  `json:"namespace"`
+	NumSeries        int64   `json:"numSeries"`
+	DiskBytes        int64   `json:"diskBytes"`
+	WritesPerSecond  float64 `json:"writesPerSecond"`
+	ReadsPerSecond   float64 `json:"readsPerSecond"`
+	IndexNumDocs     int64   `json:"indexNumDocs"`
+	IndexNumSegments int64   `json:"indexNumSegments"`
+}
+
+// namespaceUsageCounters counts the writes and reads received by a
+// namespace, the rates are computed from the counters each tick.
+type namespaceUsageCounters struct {
+	writes uint64
+	reads  uint64
+}
+
+type namespaceUsageRatesLastTick struct {
+	at              time.Time
+	writes          uint64
+	reads           uint64
+	writesPerSecond float64
+	readsPerSecond  float64
+}
+
+func (n *dbNamespace) Usage() (NamespaceUsage, error) {
+	n.statsLastTick.RLock()
+	usage := NamespaceUsage{
+		Namespace:        n.id.String(),
+		WritesPerSecond:  n.statsLastTick.usage.writesPerSecond,
+		ReadsPerSecond:   n.statsLastTick.usage.readsPerSecond,
+		IndexNumDocs:     n.statsLastTick.index.numDocs,
+		IndexNumSegments: n.statsLastTick.index.numSegments,
+	}
+	n.statsLastTick.RUnlock()
+
+	usage.NumSeries = n.NumSeries()
+
+	fsOpts := n.opts.CommitLogOptions().FilesystemOptions()
+	dirs := []string{
+		fs.NamespaceDataDirPath(fsOpts.FilePathPrefix(), n.id),
+		fs.NamespaceSnapshotsDirPath(fsOpts.FilePathPrefix(), n.id),
+		fs.NamespaceIndexDataDirPath(fsOpts.FilePathPrefix(), n.id),
+		fs.NamespaceIndexSnapshotDirPath(fsOpts.FilePathPrefix(), n.id),
+	}
+	if warmFilePathPrefix := fsOpts.WarmFilePathPrefix(); warmFilePathPrefix != "" {
+		dirs = append(dirs, fs.NamespaceDataDirPath(warmFilePathPrefix, n.id))
+	}
+	for _, dir := range dirs {
+		size, err := dirSize(dir)
+		if err != nil {
+			return usage, err
+		}
+		usage.DiskBytes += size
+	}
+	return usage, nil
+}
+
+// updateUsageRatesWithLock updates the write and read rates since the last
+// tick, the stats last tick lock must be held.
+func (n *dbNamespace) updateUsageRatesWithLock(now time.Time) {
+	var (
+		last   = &n.statsLastTick.usage
+		writes = atomic.LoadUint64(&n.usageCounters.writes)
+		reads  = atomic.LoadUint64(&n.usageCounters.reads)
+	)
+	if !last.at.IsZero() {
+		if elapsed := now.Sub(last.at).Seconds(); elapsed > 0 {
+			last.writesPerSecond = float64(writes-last.writes) / elapsed
+			last.readsPerSecond = float64(reads-last.reads) / elapsed
+		}
+	}
+	last.at = now
+	last.writes = writes
+	last.reads = reads
+}
+
+// dirSize returns the total size of the files in a directory, zero if the
+// directory does not exist.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
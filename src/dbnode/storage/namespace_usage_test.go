@@ -0,0 +1,67 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceUsageRates(t *testing.T) {
+	var (
+		n   dbNamespace
+		now = time.Unix(1500000000, 0)
+	)
+
+	n.usageCounters.writes = 100
+	n.updateUsageRatesWithLock(now)
+	assert.Equal(t, 0.0, n.statsLastTick.usage.writesPerSecond)
+
+	n.usageCounters.writes = 300
+	n.usageCounters.reads = 10
+	n.updateUsageRatesWithLock(now.Add(10 * time.Second))
+	assert.Equal(t, 20.0, n.statsLastTick.usage.writesPerSecond)
+	assert.Equal(t, 1.0, n.statsLastTick.usage.readsPerSecond)
+}
+
+func TestDirSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dir-size")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a", "1"), make([]byte, 10), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "2"), make([]byte, 5), 0644))
+
+	size, err := dirSize(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), size)
+
+	size, err = dirSize(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3/src/dbnode/x/xcounter"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/context"
@@ -88,6 +89,8 @@ var (
 	errRepairOptionsNotSet        = errors.New("repair enabled but repair options are not set")
 	errIndexOptionsNotSet         = errors.New("index enabled but index options are not set")
 	errPersistManagerNotSet       = errors.New("persist manager is not set")
+	errBootstrapWriteBufferSize   = errors.New("bootstrap write buffer size must be non-negative")
+	errCodecRegistryNotSet        = errors.New("encoding codec registry is not set")
 )
 
 // NewSeriesOptionsFromOptions creates a new set of database series options from provided options.
@@ -120,9 +123,12 @@ type options struct {
 	errThresholdForLoad            int64
 	indexingEnabled                bool
 	repairEnabled                  bool
+	readWhileBootstrapping         bool
+	bootstrapWriteBufferSize       int
 	indexOpts                      index.Options
 	repairOpts                     repair.Options
 	newEncoderFn                   encoding.NewEncoderFn
+	codecRegistry                  encoding.CodecRegistry
 	newDecoderFn                   encoding.NewDecoderFn
 	bootstrapProcessProvider       bootstrap.ProcessProvider
 	persistManager                 persist.Manager
@@ -142,6 +148,12 @@ type options struct {
 	fetchBlockMetadataResultsPool  block.FetchBlockMetadataResultsPool
 	fetchBlocksMetadataResultsPool block.FetchBlocksMetadataResultsPool
 	queryIDsWorkerPool             xsync.WorkerPool
+	writeCPUSets                   []cpuset.CPUSet
+	backgroundSchedules            BackgroundSchedules
+	unexpectedShardPolicy          UnexpectedShardPolicy
+	readOnlyShards                 []uint32
+	futureWritePolicy              FutureWritePolicy
+	maxClockSkew                   time.Duration
 }
 
 // NewOptions creates a new set of storage options with defaults
@@ -156,6 +168,12 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 	bytesPool.Init()
 	seriesOpts := series.NewOptions()
 
+	codecRegistry, err := m3tsz.NewCodecRegistry()
+	if err != nil {
+		// NB: the default codecs have distinct names so this is an invariant.
+		panic(fmt.Errorf("unable to register default codecs: %v", err))
+	}
+
 	// Default to using half of the available cores for querying IDs
 	queryIDsWorkerPool := xsync.NewWorkerPool(int(math.Ceil(float64(runtime.NumCPU()) / 2)))
 	queryIDsWorkerPool.Init()
@@ -187,6 +205,7 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		segmentReaderPool:       xio.NewSegmentReaderPool(poolOpts),
 		readerIteratorPool:      encoding.NewReaderIteratorPool(poolOpts),
 		multiReaderIteratorPool: encoding.NewMultiReaderIteratorPool(poolOpts),
+		codecRegistry:           codecRegistry,
 		identifierPool: ident.NewPool(bytesPool, ident.PoolOptions{
 			IDPoolOptions:           poolOpts,
 			TagsPoolOptions:         poolOpts,
@@ -195,6 +214,9 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		fetchBlockMetadataResultsPool:  block.NewFetchBlockMetadataResultsPool(poolOpts, 0),
 		fetchBlocksMetadataResultsPool: block.NewFetchBlocksMetadataResultsPool(poolOpts, 0),
 		queryIDsWorkerPool:             queryIDsWorkerPool,
+		backgroundSchedules:            NewBackgroundSchedules(clock.NewOptions()),
+		unexpectedShardPolicy:          DefaultUnexpectedShardPolicy,
+		futureWritePolicy:              DefaultFutureWritePolicy,
 	}
 	return o.SetEncodingM3TSZPooled()
 }
@@ -223,6 +245,14 @@ func (o *options) Validate() error {
 		}
 	}
 
+	if o.BootstrapWriteBufferSize() < 0 {
+		return errBootstrapWriteBufferSize
+	}
+
+	if o.EncodingCodecRegistry() == nil {
+		return errCodecRegistryNotSet
+	}
+
 	// validate indexing options
 	iOpts := o.IndexOptions()
 	if iOpts == nil {
@@ -249,6 +279,8 @@ func (o *options) SetClockOptions(value clock.Options) Options {
 	opts.commitLogOpts = opts.commitLogOpts.SetClockOptions(value)
 	opts.indexOpts = opts.indexOpts.SetClockOptions(value)
 	opts.seriesOpts = NewSeriesOptionsFromOptions(&opts, nil)
+	// NB: the schedules only hold state recorded once the database is open.
+	opts.backgroundSchedules = NewBackgroundSchedules(value)
 	return &opts
 }
 
@@ -360,6 +392,26 @@ func (o *options) RepairEnabled() bool {
 	return o.repairEnabled
 }
 
+func (o *options) SetReadWhileBootstrapping(value bool) Options {
+	opts := *o
+	opts.readWhileBootstrapping = value
+	return &opts
+}
+
+func (o *options) ReadWhileBootstrapping() bool {
+	return o.readWhileBootstrapping
+}
+
+func (o *options) SetBootstrapWriteBufferSize(value int) Options {
+	opts := *o
+	opts.bootstrapWriteBufferSize = value
+	return &opts
+}
+
+func (o *options) BootstrapWriteBufferSize() int {
+	return o.bootstrapWriteBufferSize
+}
+
 func (o *options) SetRepairOptions(value repair.Options) Options {
 	opts := *o
 	opts.repairOpts = value
@@ -572,6 +624,41 @@ func (o *options) MultiReaderIteratorPool() encoding.MultiReaderIteratorPool {
 	return o.multiReaderIteratorPool
 }
 
+func (o *options) SetEncodingCodecRegistry(value encoding.CodecRegistry) Options {
+	opts := *o
+	opts.codecRegistry = value
+	return &opts
+}
+
+func (o *options) EncodingCodecRegistry() encoding.CodecRegistry {
+	return o.codecRegistry
+}
+
+// newNamespaceEncodingOptions returns options with encoder and reader iterator
+// pools that use the named codec from the codec registry.
+func newNamespaceEncodingOptions(opts Options, codecName string) (Options, error) {
+	codec, err := opts.EncodingCodecRegistry().Codec(codecName)
+	if err != nil {
+		return nil, err
+	}
+
+	iopts := opts.InstrumentOptions()
+	poolOpts := pool.NewObjectPoolOptions().
+		SetInstrumentOptions(iopts.SetMetricsScope(
+			iopts.MetricsScope().SubScope("encoding-pool").
+				Tagged(map[string]string{"codec": codecName})))
+	blockOpts := opts.DatabaseBlockOptions().
+		SetBytesPool(opts.BytesPool()).
+		SetSegmentReaderPool(opts.SegmentReaderPool())
+	blockOpts = block.NewCodecOptions(blockOpts, codec, poolOpts)
+
+	return opts.
+		SetEncoderPool(blockOpts.EncoderPool()).
+		SetReaderIteratorPool(blockOpts.ReaderIteratorPool()).
+		SetMultiReaderIteratorPool(blockOpts.MultiReaderIteratorPool()).
+		SetDatabaseBlockOptions(blockOpts), nil
+}
+
 func (o *options) SetIdentifierPool(value ident.Pool) Options {
 	opts := *o
 	opts.indexOpts = opts.indexOpts.SetIdentifierPool(value)
@@ -622,3 +709,63 @@ func (o *options) SetQueryIDsWorkerPool(value xsync.WorkerPool) Options {
 func (o *options) QueryIDsWorkerPool() xsync.WorkerPool {
 	return o.queryIDsWorkerPool
 }
+
+func (o *options) SetWriteCPUSets(value []cpuset.CPUSet) Options {
+	opts := *o
+	opts.writeCPUSets = value
+	return &opts
+}
+
+func (o *options) WriteCPUSets() []cpuset.CPUSet {
+	return o.writeCPUSets
+}
+
+func (o *options) SetBackgroundSchedules(value BackgroundSchedules) Options {
+	opts := *o
+	opts.backgroundSchedules = value
+	return &opts
+}
+
+func (o *options) BackgroundSchedules() BackgroundSchedules {
+	return o.backgroundSchedules
+}
+
+func (o *options) SetUnexpectedShardPolicy(value UnexpectedShardPolicy) Options {
+	opts := *o
+	opts.unexpectedShardPolicy = value
+	return &opts
+}
+
+func (o *options) UnexpectedShardPolicy() UnexpectedShardPolicy {
+	return o.unexpectedShardPolicy
+}
+
+func (o *options) SetReadOnlyShards(value []uint32) Options {
+	opts := *o
+	opts.readOnlyShards = value
+	return &opts
+}
+
+func (o *options) ReadOnlyShards() []uint32 {
+	return o.readOnlyShards
+}
+
+func (o *options) SetFutureWritePolicy(value FutureWritePolicy) Options {
+	opts := *o
+	opts.futureWritePolicy = value
+	return &opts
+}
+
+func (o *options) FutureWritePolicy() FutureWritePolicy {
+	return o.futureWritePolicy
+}
+
+func (o *options) SetMaxClockSkew(value time.Duration) Options {
+	opts := *o
+	opts.maxClockSkew = value
+	return &opts
+}
+
+func (o *options) MaxClockSkew() time.Duration {
+	return o.maxClockSkew
+}
@@ -32,6 +32,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
@@ -274,7 +275,7 @@ func newDatabaseRepairer(database database, opts Options) (databaseRepairer, err
 		ropts:               ropts,
 		shardRepairer:       shardRepairer,
 		repairStatesByNs:    newRepairStates(),
-		sleepFn:             time.Sleep,
+		sleepFn:             opts.ClockOptions().SleepFn(),
 		nowFn:               nowFn,
 		logger:              opts.InstrumentOptions().Logger(),
 		repairInterval:      ropts.RepairInterval(),
@@ -329,7 +330,7 @@ func (r *dbRepairer) namespaceRepairTimeRanges(ns databaseNamespace) xtime.Range
 		now       = r.nowFn()
 		rtopts    = ns.Options().RetentionOptions()
 		blockSize = rtopts.BlockSize()
-		start     = now.Add(-rtopts.RetentionPeriod()).Truncate(blockSize)
+		start     = retention.FlushTimeStart(rtopts, now)
 		end       = now.Add(-rtopts.BufferPast()).Truncate(blockSize)
 	)
 
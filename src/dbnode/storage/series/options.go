@@ -21,6 +21,8 @@
 package series
 
 import (
+	"errors"
+
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -31,6 +33,23 @@ import (
 	"github.com/m3db/m3x/pool"
 )
 
+const (
+	// defaultRetrievePrefetchBlocks is the default max number of blocks
+	// retrieved ahead of the block being read by a range read, prefetching
+	// is off by default since the block retriever already retrieves the
+	// blocks of a range asynchronously and in batches.
+	defaultRetrievePrefetchBlocks = 0
+
+	// defaultRetrievePrefetchBytes is the default max estimated bytes of
+	// blocks retrieved ahead of the block being read by a range read.
+	defaultRetrievePrefetchBytes = 64 * 1024 * 1024
+)
+
+var (
+	errRetrievePrefetchBlocksNegative = errors.New("retrieve prefetch blocks is negative")
+	errRetrievePrefetchBytesNegative  = errors.New("retrieve prefetch bytes is negative")
+)
+
 type options struct {
 	clockOpts                     clock.Options
 	instrumentOpts                instrument.Options
@@ -43,6 +62,8 @@ type options struct {
 	fetchBlockMetadataResultsPool block.FetchBlockMetadataResultsPool
 	identifierPool                ident.Pool
 	stats                         Stats
+	retrievePrefetchBlocks        int
+	retrievePrefetchBytes         int
 }
 
 // NewOptions creates new database series options
@@ -66,6 +87,8 @@ func NewOptions() Options {
 		fetchBlockMetadataResultsPool: block.NewFetchBlockMetadataResultsPool(nil, 0),
 		identifierPool:                ident.NewPool(bytesPool, ident.PoolOptions{}),
 		stats:                         NewStats(iopts.MetricsScope()),
+		retrievePrefetchBlocks:        defaultRetrievePrefetchBlocks,
+		retrievePrefetchBytes:         defaultRetrievePrefetchBytes,
 	}
 }
 
@@ -73,6 +96,12 @@ func (o *options) Validate() error {
 	if err := o.retentionOpts.Validate(); err != nil {
 		return err
 	}
+	if o.retrievePrefetchBlocks < 0 {
+		return errRetrievePrefetchBlocksNegative
+	}
+	if o.retrievePrefetchBytes < 0 {
+		return errRetrievePrefetchBytesNegative
+	}
 	return ValidateCachePolicy(o.cachePolicy)
 }
 
@@ -185,3 +214,23 @@ func (o *options) SetStats(value Stats) Options {
 func (o *options) Stats() Stats {
 	return o.stats
 }
+
+func (o *options) SetRetrievePrefetchBlocks(value int) Options {
+	opts := *o
+	opts.retrievePrefetchBlocks = value
+	return &opts
+}
+
+func (o *options) RetrievePrefetchBlocks() int {
+	return o.retrievePrefetchBlocks
+}
+
+func (o *options) SetRetrievePrefetchBytes(value int) Options {
+	opts := *o
+	opts.retrievePrefetchBytes = value
+	return &opts
+}
+
+func (o *options) RetrievePrefetchBytes() int {
+	return o.retrievePrefetchBytes
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package series
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/ident"
+)
+
+// blockPrefetcher retrieves the blocks of a range read from the block
+// retriever a window ahead of the block being read, so that subsequent
+// blocks are read from disk while earlier blocks are decoded without
+// holding every block of a long range in memory at once.
+type blockPrefetcher struct {
+	sync.Mutex
+
+	ctx        context.Context
+	id         ident.ID
+	retriever  QueryableBlockRetriever
+	onRetrieve block.OnRetrieveBlock
+	maxBlocks  int
+	maxBytes   int
+
+	readers        []*prefetchSegmentReader
+	retrieved      int
+	consumedBlocks int
+	consumedBytes  int
+}
+
+func newBlockPrefetcher(
+	ctx context.Context,
+	id ident.ID,
+	retriever QueryableBlockRetriever,
+	onRetrieve block.OnRetrieveBlock,
+	opts Options,
+) *blockPrefetcher {
+	return &blockPrefetcher{
+		ctx: ctx,
+		// NB: Copy the ID as blocks may be retrieved after the series
+		// has been closed.
+		id:         ident.BytesID(append([]byte(nil), id.Bytes()...)),
+		retriever:  retriever,
+		onRetrieve: onRetrieve,
+		maxBlocks:  opts.RetrievePrefetchBlocks(),
+		maxBytes:   opts.RetrievePrefetchBytes(),
+	}
+}
+
+// add adds a block to be retrieved, blocks must be added in the order that
+// they are read.
+func (p *blockPrefetcher) add(blockStart time.Time, blockSize time.Duration) xio.BlockReader {
+	reader := &prefetchSegmentReader{
+		prefetcher: p,
+		idx:        len(p.readers),
+		start:      blockStart,
+	}
+	p.readers = append(p.readers, reader)
+	return xio.BlockReader{
+		SegmentReader: reader,
+		Start:         blockStart,
+		BlockSize:     blockSize,
+	}
+}
+
+// start retrieves the first window of blocks.
+func (p *blockPrefetcher) start() error {
+	p.Lock()
+	defer p.Unlock()
+	return p.retrieveWithLock(p.aheadWithLock())
+}
+
+// retrieveWithLock retrieves the blocks up to but excluding the index.
+func (p *blockPrefetcher) retrieveWithLock(until int) error {
+	if until > len(p.readers) {
+		until = len(p.readers)
+	}
+	for ; p.retrieved < until; p.retrieved++ {
+		reader := p.readers[p.retrieved]
+		if reader.finalized {
+			// Finalized before being read, no need to retrieve it.
+			continue
+		}
+		reader.block, reader.err = p.retriever.Stream(p.ctx, p.id,
+			reader.start, p.onRetrieve)
+		if reader.err != nil {
+			p.retrieved++
+			return reader.err
+		}
+	}
+	return nil
+}
+
+// aheadWithLock returns the number of blocks to retrieve ahead of the block
+// being read, limited by the average size of the blocks read so far.
+func (p *blockPrefetcher) aheadWithLock() int {
+	ahead := p.maxBlocks
+	if p.maxBytes > 0 && p.consumedBlocks > 0 && p.consumedBytes > 0 {
+		avgBytes := p.consumedBytes / p.consumedBlocks
+		if byBytes := p.maxBytes / avgBytes; byBytes < ahead {
+			ahead = byBytes
+		}
+	}
+	if ahead < 1 {
+		ahead = 1
+	}
+	return ahead
+}
+
+// await retrieves the block at the index if not already retrieved and
+// prefetches the blocks after it, returning the segment reader of the block.
+func (p *blockPrefetcher) await(idx int) (xio.SegmentReader, error) {
+	p.Lock()
+	reader := p.readers[idx]
+	first := !reader.awaited
+	reader.awaited = true
+	// NB: Errors are kept by the reader of the block that failed to be
+	// retrieved and returned when that block is read.
+	_ = p.retrieveWithLock(idx + 1 + p.aheadWithLock())
+	blockReader, err := reader.block, reader.err
+	p.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return blockReader.SegmentReader, nil
+	}
+
+	// Record the size of the block to limit prefetching by size.
+	segment, err := blockReader.Segment()
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	p.consumedBlocks++
+	p.consumedBytes += segment.Len()
+	p.Unlock()
+
+	return blockReader.SegmentReader, nil
+}
+
+// prefetchSegmentReader is the segment reader of a block retrieved by a
+// block prefetcher, reading waits for the block to be retrieved.
+type prefetchSegmentReader struct {
+	prefetcher *blockPrefetcher
+	idx        int
+	start      time.Time
+
+	// Guarded by the prefetcher lock.
+	block     xio.BlockReader
+	err       error
+	awaited   bool
+	finalized bool
+}
+
+func (r *prefetchSegmentReader) Read(b []byte) (int, error) {
+	reader, err := r.prefetcher.await(r.idx)
+	if err != nil {
+		return 0, err
+	}
+	return reader.Read(b)
+}
+
+func (r *prefetchSegmentReader) Segment() (ts.Segment, error) {
+	reader, err := r.prefetcher.await(r.idx)
+	if err != nil {
+		return ts.Segment{}, err
+	}
+	return reader.Segment()
+}
+
+func (r *prefetchSegmentReader) Reset(segment ts.Segment) {
+	reader, err := r.prefetcher.await(r.idx)
+	if err != nil {
+		return
+	}
+	reader.Reset(segment)
+}
+
+func (r *prefetchSegmentReader) Clone() (xio.SegmentReader, error) {
+	reader, err := r.prefetcher.await(r.idx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.Clone()
+}
+
+func (r *prefetchSegmentReader) Finalize() {
+	p := r.prefetcher
+	p.Lock()
+	if r.finalized {
+		p.Unlock()
+		return
+	}
+	r.finalized = true
+	retrieved := r.idx < p.retrieved && r.err == nil
+	block := r.block
+	p.Unlock()
+
+	if retrieved && block.SegmentReader != nil {
+		block.SegmentReader.Finalize()
+	}
+}
@@ -115,6 +115,12 @@ func (r Reader) readersWithBlocksMapAndBuffer(
 		alignedEnd = latest
 	}
 
+	var prefetcher *blockPrefetcher
+	if r.retriever != nil && r.opts.RetrievePrefetchBlocks() > 0 {
+		prefetcher = newBlockPrefetcher(ctx, r.id, r.retriever,
+			r.onRetrieve, r.opts)
+	}
+
 	first, last := alignedStart, alignedEnd
 	for blockAt := first; !blockAt.After(last); blockAt = blockAt.Add(size) {
 		if seriesBlocks != nil {
@@ -145,6 +151,13 @@ func (r Reader) readersWithBlocksMapAndBuffer(
 		case r.retriever != nil:
 			// Try to stream from disk
 			if r.retriever.IsBlockRetrievable(blockAt) {
+				if prefetcher != nil {
+					// Retrieved in order as the blocks are read
+					results = append(results, []xio.BlockReader{
+						prefetcher.add(blockAt, size),
+					})
+					continue
+				}
 				streamedBlock, err := r.retriever.Stream(ctx, r.id, blockAt, r.onRetrieve)
 				if err != nil {
 					return nil, err
@@ -156,6 +169,12 @@ func (r Reader) readersWithBlocksMapAndBuffer(
 		}
 	}
 
+	if prefetcher != nil {
+		if err := prefetcher.start(); err != nil {
+			return nil, err
+		}
+	}
+
 	if seriesBuffer != nil {
 		bufferResults := seriesBuffer.ReadEncoded(ctx, start, end)
 		if len(bufferResults) > 0 {
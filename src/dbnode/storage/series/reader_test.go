@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/ident"
 
@@ -37,7 +38,7 @@ func TestReaderUsingRetrieverReadEncoded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	opts := newSeriesTestOptions()
+	opts := newSeriesTestOptions().SetRetrievePrefetchBlocks(0)
 	ropts := opts.RetentionOptions()
 
 	end := opts.ClockOptions().NowFn()().Truncate(ropts.BlockSize())
@@ -82,6 +83,104 @@ func TestReaderUsingRetrieverReadEncoded(t *testing.T) {
 	}
 }
 
+func TestReaderUsingRetrieverReadEncodedPrefetch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSeriesTestOptions().SetRetrievePrefetchBlocks(1)
+	ropts := opts.RetentionOptions()
+
+	end := opts.ClockOptions().NowFn()().Truncate(ropts.BlockSize())
+	start := end.Add(-4 * ropts.BlockSize())
+
+	retriever := NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(gomock.Any()).Return(true).Times(4)
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	var segmentReaders []*xio.MockSegmentReader
+	for i := 0; i < 4; i++ {
+		segmentReaders = append(segmentReaders, xio.NewMockSegmentReader(ctrl))
+	}
+	expectStream := func(i int) {
+		retriever.EXPECT().
+			Stream(ctx, ident.NewIDMatcher("foo"),
+				start.Add(time.Duration(i)*ropts.BlockSize()), nil).
+			Return(xio.BlockReader{SegmentReader: segmentReaders[i]}, nil)
+	}
+
+	// Only the first block is retrieved before reading.
+	expectStream(0)
+
+	reader := NewReaderUsingRetriever(
+		ident.StringID("foo"), retriever, nil, nil, opts)
+	r, err := reader.ReadEncoded(ctx, start, end)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(r))
+	for i, readers := range r {
+		require.Equal(t, 1, len(readers))
+		assert.Equal(t, start.Add(time.Duration(i)*ropts.BlockSize()), readers[0].Start)
+	}
+
+	// Reading the first block retrieves the next block.
+	expectStream(1)
+	segmentReaders[0].EXPECT().Segment().Return(ts.Segment{}, nil).Times(2)
+	_, err = r[0][0].Segment()
+	require.NoError(t, err)
+
+	// Reading the third block retrieves the skipped block and the fourth.
+	expectStream(2)
+	expectStream(3)
+	segmentReaders[2].EXPECT().Segment().Return(ts.Segment{}, nil)
+	segmentReaders[2].EXPECT().Read(gomock.Any()).Return(0, nil)
+	_, err = r[2][0].Read(nil)
+	require.NoError(t, err)
+
+	// Finalizing the readers finalizes the retrieved block readers once.
+	for _, segmentReader := range segmentReaders {
+		segmentReader.EXPECT().Finalize()
+	}
+	for _, readers := range r {
+		readers[0].Finalize()
+		readers[0].Finalize()
+	}
+}
+
+func TestReaderUsingRetrieverReadEncodedPrefetchFinalizeBeforeRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSeriesTestOptions().SetRetrievePrefetchBlocks(1)
+	ropts := opts.RetentionOptions()
+
+	end := opts.ClockOptions().NowFn()().Truncate(ropts.BlockSize())
+	start := end.Add(-2 * ropts.BlockSize())
+
+	retriever := NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(gomock.Any()).Return(true).Times(2)
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	// Only the first block is retrieved, the second is finalized before
+	// being read so it is never retrieved.
+	segmentReader := xio.NewMockSegmentReader(ctrl)
+	retriever.EXPECT().
+		Stream(ctx, ident.NewIDMatcher("foo"), start, nil).
+		Return(xio.BlockReader{SegmentReader: segmentReader}, nil)
+
+	reader := NewReaderUsingRetriever(
+		ident.StringID("foo"), retriever, nil, nil, opts)
+	r, err := reader.ReadEncoded(ctx, start, end)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(r))
+
+	segmentReader.EXPECT().Finalize()
+	r[0][0].Finalize()
+	r[1][0].Finalize()
+}
+
 func TestReaderUsingRetrieverFetchBlocks(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
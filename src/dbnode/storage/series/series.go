@@ -28,6 +28,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -156,7 +157,7 @@ func (s *dbSeries) updateBlocksWithLock() (updateBlocksResult, error) {
 		ropts        = s.opts.RetentionOptions()
 		retriever    = s.blockRetriever
 		cachePolicy  = s.opts.CachePolicy()
-		expireCutoff = now.Add(-ropts.RetentionPeriod()).Truncate(ropts.BlockSize())
+		expireCutoff = retention.FlushTimeStart(ropts, now)
 		wiredTimeout = ropts.BlockDataExpiryAfterNotAccessedPeriod()
 	)
 	for startNano, currBlock := range s.blocks.AllBlocks() {
@@ -261,6 +261,24 @@ type Options interface {
 
 	// Stats returns the configured Stats.
 	Stats() Stats
+
+	// SetRetrievePrefetchBlocks sets the max number of blocks retrieved from
+	// disk ahead of the block being read by a range read, zero retrieves
+	// all blocks of the range at once.
+	SetRetrievePrefetchBlocks(value int) Options
+
+	// RetrievePrefetchBlocks returns the max number of blocks retrieved from
+	// disk ahead of the block being read by a range read.
+	RetrievePrefetchBlocks() int
+
+	// SetRetrievePrefetchBytes sets the max estimated bytes of blocks
+	// retrieved from disk ahead of the block being read by a range read,
+	// zero does not limit prefetching by size.
+	SetRetrievePrefetchBytes(value int) Options
+
+	// RetrievePrefetchBytes returns the max estimated bytes of blocks
+	// retrieved from disk ahead of the block being read by a range read.
+	RetrievePrefetchBytes() int
 }
 
 // Stats is passed down from namespace/shard to avoid allocations per series.
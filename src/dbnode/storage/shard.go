@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/proto/pagetoken"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
@@ -149,6 +150,7 @@ type dbShard struct {
 	commitLogWriter          commitLogWriter
 	reverseIndex             namespaceIndex
 	insertQueue              *dbShardInsertQueue
+	bootstrapWriteBuffer     *dbShardBootstrapWriteBuffer
 	lookup                   *shardMap
 	list                     *list.List
 	bootstrapState           BootstrapState
@@ -157,6 +159,7 @@ type dbShard struct {
 	snapshotFilesFn          snapshotFilesFn
 	sleepFn                  func(time.Duration)
 	identifierPool           ident.Pool
+	tagDictionary            convert.Dictionary
 	contextPool              context.Pool
 	flushState               shardFlushState
 	snapshotState            shardSnapshotState
@@ -191,10 +194,13 @@ type dbShardMetrics struct {
 	insertAsyncWriteErrors        tally.Counter
 	seriesBootstrapBlocksToBuffer tally.Counter
 	seriesBootstrapBlocksMerged   tally.Counter
+	bootstrapWritesBuffered       tally.Counter
+	bootstrapWritesApplyErrors    tally.Counter
 }
 
 func newDatabaseShardMetrics(scope tally.Scope) dbShardMetrics {
 	seriesBootstrapScope := scope.SubScope("series-bootstrap")
+	bootstrapWriteBufferScope := scope.SubScope("bootstrap-write-buffer")
 	return dbShardMetrics{
 		create:       scope.Counter("create"),
 		close:        scope.Counter("close"),
@@ -211,6 +217,8 @@ func newDatabaseShardMetrics(scope tally.Scope) dbShardMetrics {
 		}).Counter("insert-async.errors"),
 		seriesBootstrapBlocksToBuffer: seriesBootstrapScope.Counter("blocks-to-buffer"),
 		seriesBootstrapBlocksMerged:   seriesBootstrapScope.Counter("blocks-merged"),
+		bootstrapWritesBuffered:       bootstrapWriteBufferScope.Counter("buffered"),
+		bootstrapWritesApplyErrors:    bootstrapWriteBufferScope.Counter("apply-errors"),
 	}
 }
 
@@ -269,8 +277,9 @@ func newDatabaseShard(
 		filesetBeforeFn:    fs.DataFileSetsBefore,
 		deleteFilesFn:      fs.DeleteFiles,
 		snapshotFilesFn:    fs.SnapshotFiles,
-		sleepFn:            time.Sleep,
+		sleepFn:            opts.ClockOptions().SleepFn(),
 		identifierPool:     opts.IdentifierPool(),
+		tagDictionary:      opts.IndexOptions().TagDictionary(),
 		contextPool:        opts.ContextPool(),
 		flushState:         newShardFlushState(),
 		tickWg:             &sync.WaitGroup{},
@@ -279,6 +288,12 @@ func newDatabaseShard(
 	}
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
 		s.nowFn, scope)
+	if sets := opts.WriteCPUSets(); len(sets) > 0 {
+		// NB: the shards are spread across the sets, e.g. a set per NUMA
+		// node, so that new series are inserted by every node.
+		s.insertQueue.cpuSet = sets[int(shard)%len(sets)]
+		s.insertQueue.logger = s.logger
+	}
 
 	registerRuntimeOptionsListener := func(listener runtime.OptionsListener) {
 		elem := opts.RuntimeOptionsManager().RegisterListener(listener)
@@ -294,6 +309,8 @@ func newDatabaseShard(
 	if !needsBootstrap {
 		s.bootstrapState = Bootstrapped
 		s.newSeriesBootstrapped = true
+	} else if size := opts.BootstrapWriteBufferSize(); size > 0 {
+		s.bootstrapWriteBuffer = newDatabaseShardBootstrapWriteBuffer(size)
 	}
 
 	if blockRetriever != nil {
@@ -335,6 +352,22 @@ func (s *dbShard) NumSeries() int64 {
 	return int64(n)
 }
 
+func (s *dbShard) ForEachSeriesDocument(fn func(d doc.Document) bool) error {
+	var convertErr error
+	err := s.forEachShardEntry(func(entry *lookup.Entry) bool {
+		d, err := convert.FromMetric(entry.Series.ID(), entry.Series.Tags())
+		if err != nil {
+			convertErr = err
+			return false
+		}
+		return fn(d)
+	})
+	if err != nil {
+		return err
+	}
+	return convertErr
+}
+
 // Stream implements series.QueryableBlockRetriever
 func (s *dbShard) Stream(
 	ctx context.Context,
@@ -753,7 +786,16 @@ func (s *dbShard) WriteTagged(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
+	// NB: fields are validated for tagged writes only, series that already
+	// exist on disk are still loaded if their tags are no longer valid.
+	tagsIter := tags.Duplicate()
+	err := index.ValidateFields(s.opts.IndexOptions(), tagsIter)
+	tagsIter.Close()
+	if err != nil {
+		return false, xerrors.NewInvalidParamsError(err)
+	}
+
 	return s.writeAndIndex(ctx, id, tags, timestamp,
 		value, unit, annotation, true)
 }
@@ -765,7 +807,7 @@ func (s *dbShard) Write(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
-) error {
+) (bool, error) {
 	return s.writeAndIndex(ctx, id, ident.EmptyTagIterator, timestamp,
 		value, unit, annotation, false)
 }
@@ -779,14 +821,43 @@ func (s *dbShard) writeAndIndex(
 	unit xtime.Unit,
 	annotation []byte,
 	shouldReverseIndex bool,
-) error {
+) (bool, error) {
+	return s.writeAndIndexWithCommitLog(ctx, id, tags, timestamp, value,
+		unit, annotation, shouldReverseIndex, true)
+}
+
+// writeAndIndexWithCommitLog writes and indexes the datapoint, writing it
+// to the commit log unless it was already written when it was buffered.
+func (s *dbShard) writeAndIndexWithCommitLog(
+	ctx context.Context,
+	id ident.ID,
+	tags ident.TagIterator,
+	timestamp time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	shouldReverseIndex bool,
+	writeCommitLog bool,
+) (bool, error) {
+	// NB: writes keep being buffered until the buffer is drained, even once
+	// bootstrapped, so they are applied after the writes buffered before them.
+	if writeCommitLog && s.bootstrapWriteBuffer != nil &&
+		!s.bootstrapWriteBuffer.IsDrained() {
+		buffered, err := s.bufferBootstrapWrite(ctx, id, tags, timestamp,
+			value, unit, annotation, shouldReverseIndex)
+		if err != nil || buffered {
+			return false, err
+		}
+	}
+
 	// Prepare write
 	entry, opts, err := s.tryRetrieveWritableSeries(id)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	writable := entry != nil
+	newSeries := !writable
 
 	// If no entry and we are not writing new series asynchronously
 	if !writable && !opts.writeNewSeriesAsync {
@@ -799,7 +870,7 @@ func (s *dbShard) writeAndIndex(
 			},
 		})
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		// Wait for the insert to be batched together and inserted
@@ -808,7 +879,7 @@ func (s *dbShard) writeAndIndex(
 		// Retrieve the inserted entry
 		entry, err = s.writableSeries(id, tags)
 		if err != nil {
-			return err
+			return false, err
 		}
 		writable = true
 
@@ -842,7 +913,7 @@ func (s *dbShard) writeAndIndex(
 		// release the reference we got on entry from `writableSeries`
 		entry.DecrementReaderWriterCount()
 		if err != nil {
-			return err
+			return false, err
 		}
 	} else {
 		// This is an asynchronous insert and write
@@ -861,7 +932,7 @@ func (s *dbShard) writeAndIndex(
 			},
 		})
 		if err != nil {
-			return err
+			return false, err
 		}
 		// NB(r): Make sure to use the copied ID which will eventually
 		// be set to the newly series inserted ID.
@@ -873,6 +944,10 @@ func (s *dbShard) writeAndIndex(
 		commitLogSeriesUniqueIndex = result.entry.Index
 	}
 
+	if !writeCommitLog {
+		return newSeries, nil
+	}
+
 	// Write commit log
 	series := commitlog.Series{
 		UniqueIndex: commitLogSeriesUniqueIndex,
@@ -887,14 +962,216 @@ func (s *dbShard) writeAndIndex(
 		Value:     value,
 	}
 
-	return s.commitLogWriter.Write(ctx, series, datapoint,
-		unit, annotation)
+	if err := s.commitLogWriter.Write(ctx, series, datapoint,
+		unit, annotation); err != nil {
+		return false, err
+	}
+	return newSeries, nil
+}
+
+func (s *dbShard) bufferBootstrapWrite(
+	ctx context.Context,
+	id ident.ID,
+	tags ident.TagIterator,
+	timestamp time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	shouldReverseIndex bool,
+) (bool, error) {
+	write := dbShardBufferedWrite{
+		id:                 ident.BytesID(append([]byte(nil), id.Bytes()...)),
+		timestamp:          timestamp,
+		value:              value,
+		unit:               unit,
+		shouldReverseIndex: shouldReverseIndex,
+	}
+	if len(annotation) > 0 {
+		write.annotation = append([]byte(nil), annotation...)
+	}
+	if shouldReverseIndex {
+		// NB: Take a duplicate so that we don't consume the tag iterator
+		// passed to this method.
+		tagsIter := tags.Duplicate()
+		copied, err := copyBufferedWriteTags(tagsIter)
+		tagsIter.Close()
+		if err != nil {
+			return false, err
+		}
+		write.tags = copied
+	}
+
+	// NB: the write is acknowledged once buffered so it must be written to
+	// the commit log first to survive a crash before it is applied, it is
+	// not written to the commit log again when applied.
+	// NB: this waits for capacity if the buffer is full to apply backpressure
+	// to writers rather than rejecting the write.
+	uniqueIndex, ok := s.bootstrapWriteBuffer.Reserve(write.id.Bytes(),
+		s.increasingIndex.nextIndex)
+	if !ok {
+		return false, nil
+	}
+	series := commitlog.Series{
+		UniqueIndex: uniqueIndex,
+		Namespace:   s.namespace.ID(),
+		ID:          write.id,
+		Tags:        write.tags,
+		Shard:       s.shard,
+	}
+	datapoint := ts.Datapoint{
+		Timestamp: timestamp,
+		Value:     value,
+	}
+	if err := s.commitLogWriter.Write(ctx, series, datapoint,
+		unit, write.annotation); err != nil {
+		s.bootstrapWriteBuffer.Unreserve()
+		return false, err
+	}
+
+	if !s.bootstrapWriteBuffer.Enqueue(write) {
+		// NB: drained since reserving, the write is applied directly and
+		// so is written to the commit log a second time which is harmless.
+		return false, nil
+	}
+	s.metrics.bootstrapWritesBuffered.Inc(1)
+	return true, nil
+}
+
+// applyBootstrapBufferedWrites applies the writes accepted while the shard
+// was bootstrapping, must be called once the shard is bootstrapped. Writes
+// accepted while applying are buffered and applied in turn until the buffer
+// is empty, so writes are applied in the order they were accepted and a
+// buffered write never takes precedence over a later write.
+func (s *dbShard) applyBootstrapBufferedWrites() {
+	if s.bootstrapWriteBuffer == nil {
+		return
+	}
+
+	ctx := s.contextPool.Get()
+	defer ctx.Close()
+
+	var numWrites, errs int
+	for {
+		writes, ok := s.bootstrapWriteBuffer.Take()
+		if !ok {
+			break
+		}
+		numWrites += len(writes)
+		for _, w := range writes {
+			var tags ident.TagIterator = ident.EmptyTagIterator
+			if w.shouldReverseIndex {
+				tags = ident.NewTagsIterator(w.tags)
+			}
+			_, err := s.writeAndIndexWithCommitLog(ctx, w.id, tags, w.timestamp,
+				w.value, w.unit, w.annotation, w.shouldReverseIndex, false)
+			if err != nil {
+				errs++
+			}
+		}
+	}
+
+	if errs > 0 {
+		s.metrics.bootstrapWritesApplyErrors.Inc(int64(errs))
+		s.logger.WithFields(
+			xlog.NewField("shard", s.ID()),
+			xlog.NewField("namespace", s.namespace.ID()),
+			xlog.NewField("numWrites", numWrites),
+			xlog.NewField("numErrors", errs),
+		).Error("unable to apply writes buffered while bootstrapping")
+	}
 }
 
 func (s *dbShard) ReadEncoded(
 	ctx context.Context,
 	id ident.ID,
 	start, end time.Time,
+) ([][]xio.BlockReader, error) {
+	results, err := s.readEncoded(ctx, id, start, end)
+	if err != nil || s.bootstrapWriteBuffer == nil {
+		return results, err
+	}
+	return s.appendBootstrapBufferedReaders(ctx, id, start, end, results)
+}
+
+// appendBootstrapBufferedReaders adds readers of the writes of the series
+// that are buffered while bootstrapping to the block readers so that the
+// writes can be read before they are applied.
+func (s *dbShard) appendBootstrapBufferedReaders(
+	ctx context.Context,
+	id ident.ID,
+	start, end time.Time,
+	results [][]xio.BlockReader,
+) ([][]xio.BlockReader, error) {
+	writes := s.bootstrapWriteBuffer.SeriesWrites(id.Bytes(), start, end)
+	if len(writes) == 0 {
+		return results, nil
+	}
+
+	var (
+		blockSize   = s.namespace.Options().RetentionOptions().BlockSize()
+		encoderPool = s.seriesOpts.EncoderPool()
+		encoder     encoding.Encoder
+		blockStart  time.Time
+	)
+	appendEncoder := func() {
+		if encoder == nil {
+			return
+		}
+		reader := xio.NewSegmentReader(encoder.Discard())
+		ctx.RegisterFinalizer(reader)
+		results = appendBlockReader(results, xio.BlockReader{
+			SegmentReader: reader,
+			Start:         blockStart,
+			BlockSize:     blockSize,
+		})
+		encoder = nil
+	}
+	for i, w := range writes {
+		if i > 0 && writes[i-1].timestamp.Equal(w.timestamp) {
+			// The first write accepted for a timestamp wins as it does when
+			// the writes are applied to the series.
+			continue
+		}
+		if writeBlockStart := w.timestamp.Truncate(blockSize); encoder == nil ||
+			!writeBlockStart.Equal(blockStart) {
+			appendEncoder()
+			blockStart = writeBlockStart
+			encoder = encoderPool.Get()
+			encoder.Reset(blockStart, 0)
+		}
+		dp := ts.Datapoint{Timestamp: w.timestamp, Value: w.value}
+		if err := encoder.Encode(dp, w.unit, w.annotation); err != nil {
+			encoder.Close()
+			return nil, err
+		}
+	}
+	appendEncoder()
+	return results, nil
+}
+
+// appendBlockReader adds the reader to the readers of its block, keeping
+// the blocks in order of their start.
+func appendBlockReader(
+	results [][]xio.BlockReader,
+	reader xio.BlockReader,
+) [][]xio.BlockReader {
+	idx := sort.Search(len(results), func(i int) bool {
+		return len(results[i]) > 0 && !results[i][0].Start.Before(reader.Start)
+	})
+	if idx < len(results) && results[idx][0].Start.Equal(reader.Start) {
+		results[idx] = append(results[idx], reader)
+		return results
+	}
+	results = append(results, nil)
+	copy(results[idx+1:], results[idx:])
+	results[idx] = []xio.BlockReader{reader}
+	return results
+}
+
+func (s *dbShard) readEncoded(
+	ctx context.Context,
+	id ident.ID,
+	start, end time.Time,
 ) ([][]xio.BlockReader, error) {
 	s.RLock()
 	entry, _, err := s.lookupEntryWithLock(id)
@@ -1026,8 +1303,8 @@ func (s *dbShard) newShardEntry(
 		if tagsIter.CurrentIndex() != 0 {
 			return nil, errNewShardEntryTagsIterNotAtIndexZero
 		}
-		seriesTags, err = convert.TagsFromTagsIter(
-			seriesID, tagsIter, s.identifierPool)
+		seriesTags, err = convert.TagsFromTagsIterWithDictionary(
+			seriesID, tagsIter, s.identifierPool, s.tagDictionary)
 		tagsIter.Close()
 		if err != nil {
 			return nil, err
@@ -1280,11 +1557,7 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 	// Perform any indexing, pending writes or pending retrieved blocks outside of lock
 	ctx := s.contextPool.Get()
 	// TODO(prateek): pool this type
-	indexBlockSize := s.namespace.Options().IndexOptions().BlockSize()
-	indexBatch := index.NewWriteBatch(index.WriteBatchOptions{
-		InitialCapacity: numPendingIndexing,
-		IndexBlockSize:  indexBlockSize,
-	})
+	indexSeries := make([]index.WriteBatchSeries, 0, numPendingIndexing)
 	for i := range inserts {
 		var (
 			entry           = inserts[i].entry
@@ -1306,23 +1579,16 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 			// this method (insertSeriesBatch) via `entryRefCountIncremented` mechanism.
 			entry.OnIndexPrepare()
 
-			id := entry.Series.ID()
-			tags := entry.Series.Tags().Values()
-
-			var d doc.Document
-			d.ID = id.Bytes() // IDs from shard entries are always set NoFinalize
-			d.Fields = make(doc.Fields, 0, len(tags))
-			for _, tag := range tags {
-				d.Fields = append(d.Fields, doc.Field{
-					Name:  tag.Name.Bytes(),  // Tags from shard entries are always set NoFinalize
-					Value: tag.Value.Bytes(), // Tags from shard entries are always set NoFinalize
-				})
-			}
-			indexBatch.Append(index.WriteBatchEntry{
-				Timestamp:     pendingIndex.timestamp,
-				OnIndexSeries: entry,
-				EnqueuedAt:    pendingIndex.enqueuedAt,
-			}, d)
+			indexSeries = append(indexSeries, index.WriteBatchSeries{
+				Entry: index.WriteBatchEntry{
+					Timestamp:     pendingIndex.timestamp,
+					OnIndexSeries: entry,
+					EnqueuedAt:    pendingIndex.enqueuedAt,
+				},
+				// IDs and tags from shard entries are always set NoFinalize
+				ID:   entry.Series.ID(),
+				Tags: entry.Series.Tags(),
+			})
 		}
 
 		if inserts[i].opts.hasPendingRetrievedBlock {
@@ -1337,8 +1603,8 @@ func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
 
 	var err error
 	// index all requested entries in batch.
-	if indexBatch.Len() > 0 {
-		err = s.reverseIndex.WriteBatch(indexBatch)
+	if len(indexSeries) > 0 {
+		err = s.reverseIndex.WriteSeriesBatch(indexSeries)
 	}
 
 	// Avoid goroutine spinning up to close this context
@@ -1787,8 +2053,22 @@ func (s *dbShard) Bootstrap(
 
 	// Now iterate flushed time ranges to determine which blocks are
 	// retrievable before servicing reads
+	s.LoadFlushStates()
+
+	s.Lock()
+	s.bootstrapState = Bootstrapped
+	s.Unlock()
+
+	// Apply any writes that were accepted while bootstrapping
+	s.applyBootstrapBufferedWrites()
+
+	return multiErr.FinalError()
+}
+
+func (s *dbShard) LoadFlushStates() {
 	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
-	readInfoFilesResults := fs.ReadInfoFiles(fsOpts.FilePathPrefix(), s.namespace.ID(), s.shard,
+	readInfoFilesResults := fs.ReadTieredInfoFiles(fsOpts.FilePathPrefix(),
+		fsOpts.WarmFilePathPrefix(), s.namespace.ID(), s.shard,
 		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
 
 	for _, result := range readInfoFilesResults {
@@ -1798,7 +2078,7 @@ func (s *dbShard) Bootstrap(
 				xlog.NewField("namespace", s.namespace.ID()),
 				xlog.NewField("error", result.Err.Error()),
 				xlog.NewField("filepath", result.Err.Filepath()),
-			).Error("unable to read info files in shard bootstrap")
+			).Error("unable to read info files when loading shard flush states")
 			continue
 		}
 		info := result.Info
@@ -1809,12 +2089,16 @@ func (s *dbShard) Bootstrap(
 		}
 		s.markFlushStateSuccess(at)
 	}
+}
 
-	s.Lock()
-	s.bootstrapState = Bootstrapped
-	s.Unlock()
-
-	return multiErr.FinalError()
+func (s *dbShard) MarkSkippedBlocksFlushed(skipped xtime.Range) {
+	blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+	for t := skipped.Start.Truncate(blockSize); t.Before(skipped.End); t = t.Add(blockSize) {
+		if s.FlushState(t).Status != fileOpNotStarted {
+			continue // Already recorded progress
+		}
+		s.markFlushStateSuccess(t)
+	}
 }
 
 func (s *dbShard) Flush(
@@ -2072,17 +2356,24 @@ func (s *dbShard) CleanupSnapshots(earliestToRetain time.Time) error {
 }
 
 func (s *dbShard) CleanupExpiredFileSets(earliestToRetain time.Time) error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	multiErr := xerrors.NewMultiError()
-	expired, err := s.filesetBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
-	if err != nil {
-		detailedErr :=
-			fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
-				filePathPrefix, s.namespace.ID(), s.ID(), err)
-		multiErr = multiErr.Add(detailedErr)
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	filePathPrefixes := []string{fsOpts.FilePathPrefix()}
+	if warmFilePathPrefix := fsOpts.WarmFilePathPrefix(); warmFilePathPrefix != "" {
+		filePathPrefixes = append(filePathPrefixes, warmFilePathPrefix)
 	}
-	if err := s.deleteFilesFn(expired); err != nil {
-		multiErr = multiErr.Add(err)
+
+	multiErr := xerrors.NewMultiError()
+	for _, filePathPrefix := range filePathPrefixes {
+		expired, err := s.filesetBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
+		if err != nil {
+			detailedErr :=
+				fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+					filePathPrefix, s.namespace.ID(), s.ID(), err)
+			multiErr = multiErr.Add(detailedErr)
+		}
+		if err := s.deleteFilesFn(expired); err != nil {
+			multiErr = multiErr.Add(err)
+		}
 	}
 	return multiErr.FinalError()
 }
@@ -0,0 +1,226 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+// dbShardBufferedWrite is a write accepted by a shard while bootstrapping
+// which is applied once the shard finishes bootstrapping, all references
+// are copied so the write is safe to hold beyond the request context.
+type dbShardBufferedWrite struct {
+	id                 ident.ID
+	tags               ident.Tags
+	timestamp          time.Time
+	value              float64
+	unit               xtime.Unit
+	annotation         []byte
+	shouldReverseIndex bool
+}
+
+// copyBufferedWriteTags copies the tags onto the heap rather than taking
+// them from the identifier pool, they are referenced by the commit log
+// after the write is applied so they are never returned to the pool.
+func copyBufferedWriteTags(iter ident.TagIterator) (ident.Tags, error) {
+	tags := make([]ident.Tag, 0, iter.Remaining())
+	for iter.Next() {
+		tag := iter.Current()
+		tags = append(tags, ident.Tag{
+			Name:  ident.BytesID(append([]byte(nil), tag.Name.Bytes()...)),
+			Value: ident.BytesID(append([]byte(nil), tag.Value.Bytes()...)),
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return ident.Tags{}, err
+	}
+	return ident.NewTags(tags...), nil
+}
+
+// sortBufferedWrites sorts the writes by timestamp, writes with the same
+// timestamp keep the order they were accepted in so the first one wins.
+func sortBufferedWrites(writes []dbShardBufferedWrite) {
+	sort.SliceStable(writes, func(i, j int) bool {
+		return writes[i].timestamp.Before(writes[j].timestamp)
+	})
+}
+
+// dbShardBufferedSeries is the buffered writes of a single series.
+type dbShardBufferedSeries struct {
+	// uniqueIndex is the commit log unique index of the series, so repeated
+	// writes of a series share the series metadata.
+	uniqueIndex uint64
+	writes      []dbShardBufferedWrite
+}
+
+// dbShardBootstrapWriteBuffer is a bounded buffer of writes accepted while
+// a shard is bootstrapping, writers wait for capacity when it is full. Once
+// drained it no longer accepts writes and callers should write directly to
+// the shard.
+type dbShardBootstrapWriteBuffer struct {
+	sync.Mutex
+	notFull *sync.Cond
+
+	series  map[string]*dbShardBufferedSeries
+	size    int
+	maxSize int
+	drained int32
+}
+
+func newDatabaseShardBootstrapWriteBuffer(maxSize int) *dbShardBootstrapWriteBuffer {
+	b := &dbShardBootstrapWriteBuffer{
+		series:  make(map[string]*dbShardBufferedSeries),
+		maxSize: maxSize,
+	}
+	b.notFull = sync.NewCond(&b.Mutex)
+	return b
+}
+
+// IsDrained returns whether the buffer has been drained.
+func (b *dbShardBootstrapWriteBuffer) IsDrained() bool {
+	return atomic.LoadInt32(&b.drained) == 1
+}
+
+// Reserve reserves capacity for a write of the series, waiting for capacity
+// if the buffer is full, and returns the commit log unique index to write
+// it to the commit log with before it is enqueued. It returns false if the
+// buffer has been drained and the write should be applied directly.
+func (b *dbShardBootstrapWriteBuffer) Reserve(
+	id []byte,
+	nextIndex func() uint64,
+) (uint64, bool) {
+	if b.IsDrained() {
+		return 0, false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	for b.size >= b.maxSize && !b.IsDrained() {
+		b.notFull.Wait()
+	}
+	if b.IsDrained() {
+		return 0, false
+	}
+	b.size++
+	series, ok := b.series[string(id)]
+	if !ok {
+		series = &dbShardBufferedSeries{uniqueIndex: nextIndex()}
+		b.series[string(id)] = series
+	}
+	return series.uniqueIndex, true
+}
+
+// Unreserve releases the capacity reserved for a write that was not
+// enqueued.
+func (b *dbShardBootstrapWriteBuffer) Unreserve() {
+	b.Lock()
+	if b.size > 0 {
+		b.size--
+	}
+	b.Unlock()
+	b.notFull.Signal()
+}
+
+// Enqueue buffers a reserved write and returns true, or returns false if
+// the buffer has been drained since the write was reserved and the write
+// should be applied directly.
+func (b *dbShardBootstrapWriteBuffer) Enqueue(write dbShardBufferedWrite) bool {
+	b.Lock()
+	defer b.Unlock()
+	if b.IsDrained() {
+		return false
+	}
+	series, ok := b.series[string(write.id.Bytes())]
+	if !ok {
+		// NB: the writes of the series were taken since being reserved.
+		series = &dbShardBufferedSeries{}
+		b.series[string(write.id.Bytes())] = series
+	}
+	series.writes = append(series.writes, write)
+	return true
+}
+
+// Take returns the buffered writes of each series sorted by timestamp and
+// frees their capacity, writes buffered after this are returned by the next
+// call. If there are no buffered writes the buffer is drained and false is
+// returned.
+func (b *dbShardBootstrapWriteBuffer) Take() ([]dbShardBufferedWrite, bool) {
+	b.Lock()
+	var writes []dbShardBufferedWrite
+	for _, series := range b.series {
+		if len(series.writes) == 0 {
+			continue
+		}
+		sortBufferedWrites(series.writes)
+		writes = append(writes, series.writes...)
+		b.size -= len(series.writes)
+		// NB: keep the series so later writes share its unique index.
+		series.writes = nil
+	}
+	if len(writes) == 0 {
+		b.series = nil
+		b.size = 0
+		atomic.StoreInt32(&b.drained, 1)
+	}
+	b.Unlock()
+	b.notFull.Broadcast()
+	return writes, len(writes) > 0
+}
+
+// SeriesWrites returns a copy of the buffered writes of the series between
+// start inclusive and end exclusive sorted by timestamp.
+func (b *dbShardBootstrapWriteBuffer) SeriesWrites(
+	id []byte,
+	start, end time.Time,
+) []dbShardBufferedWrite {
+	if b.IsDrained() {
+		return nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	series, ok := b.series[string(id)]
+	if !ok {
+		return nil
+	}
+	var writes []dbShardBufferedWrite
+	for _, w := range series.writes {
+		if !w.timestamp.Before(start) && w.timestamp.Before(end) {
+			writes = append(writes, w)
+		}
+	}
+	sortBufferedWrites(writes)
+	return writes
+}
+
+// Len returns the number of buffered writes.
+func (b *dbShardBootstrapWriteBuffer) Len() int {
+	b.Lock()
+	n := b.size
+	b.Unlock()
+	return n
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/m3ninx/doc"
 	xclock "github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/context"
+	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
@@ -58,8 +59,9 @@ func TestShardInsertNamespaceIndex(t *testing.T) {
 	defer ctrl.Finish()
 	idx := NewMocknamespaceIndex(ctrl)
 	idx.EXPECT().BlockStartForWriteTime(gomock.Any()).Return(blockStart).AnyTimes()
-	idx.EXPECT().WriteBatch(gomock.Any()).Do(
-		func(batch *index.WriteBatch) {
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).Do(
+		func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 
 			lock.Lock()
 			indexWrites = append(indexWrites, batch.PendingDocs()...)
@@ -78,18 +80,18 @@ func TestShardInsertNamespaceIndex(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	require.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			now, 1.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		now, 1.0, xtime.Second, nil)
+	require.NoError(t, err)
 
-	require.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			now, 2.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		now, 2.0, xtime.Second, nil)
+	require.NoError(t, err)
 
-	require.NoError(t,
-		shard.Write(ctx, ident.StringID("baz"), now, 1.0, xtime.Second, nil))
+	_, err = shard.Write(ctx, ident.StringID("baz"), now, 1.0, xtime.Second, nil)
+	require.NoError(t, err)
 
 	lock.Lock()
 	defer lock.Unlock()
@@ -110,8 +112,9 @@ func TestShardAsyncInsertNamespaceIndex(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	idx := NewMocknamespaceIndex(ctrl)
-	idx.EXPECT().WriteBatch(gomock.Any()).Do(
-		func(batch *index.WriteBatch) {
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).Do(
+		func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 			lock.Lock()
 			indexWrites = append(indexWrites, batch.PendingDocs()...)
 			lock.Unlock()
@@ -124,21 +127,21 @@ func TestShardAsyncInsertNamespaceIndex(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			time.Now(), 1.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		time.Now(), 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("bar"), time.Now(), 1.0, xtime.Second, nil))
+	_, err = shard.Write(ctx, ident.StringID("bar"), time.Now(), 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("baz"),
-			ident.NewTagsIterator(ident.NewTags(
-				ident.StringTag("all", "tags"),
-				ident.StringTag("should", "be-present"),
-			)),
-			time.Now(), 1.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("baz"),
+		ident.NewTagsIterator(ident.NewTags(
+			ident.StringTag("all", "tags"),
+			ident.StringTag("should", "be-present"),
+		)),
+		time.Now(), 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	for {
 		lock.RLock()
@@ -186,8 +189,9 @@ func TestShardAsyncIndexOnlyWhenNotIndexed(t *testing.T) {
 			return xtime.ToUnixNano(t.Truncate(blockSize))
 		}).
 		AnyTimes()
-	idx.EXPECT().WriteBatch(gomock.Any()).Do(
-		func(batch *index.WriteBatch) {
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).Do(
+		func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 			if batch.Len() == 0 {
 				panic(fmt.Errorf("expected batch of len 1")) // panic to avoid goroutine exit from require
 			}
@@ -207,10 +211,10 @@ func TestShardAsyncIndexOnlyWhenNotIndexed(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			now, 1.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		now, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	for {
 		if l := atomic.LoadInt32(&numCalls); l == 1 {
@@ -220,10 +224,10 @@ func TestShardAsyncIndexOnlyWhenNotIndexed(t *testing.T) {
 	}
 
 	// ensure we don't index once we have already indexed
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			now.Add(time.Second), 2.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		now.Add(time.Second), 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	l := atomic.LoadInt32(&numCalls)
 	assert.Equal(t, int32(1), l)
@@ -250,9 +254,10 @@ func TestShardAsyncIndexIfExpired(t *testing.T) {
 			return xtime.ToUnixNano(t.Truncate(blockSize))
 		}).
 		AnyTimes()
-	idx.EXPECT().WriteBatch(gomock.Any()).
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).
 		Return(nil).
-		Do(func(batch *index.WriteBatch) {
+		Do(func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 			for _, b := range batch.PendingEntries() {
 				blockStart := b.Timestamp.Truncate(blockSize)
 				b.OnIndexSeries.OnIndexSuccess(xtime.ToUnixNano(blockStart))
@@ -270,10 +275,10 @@ func TestShardAsyncIndexIfExpired(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			now, 1.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		now, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	// wait till we're done indexing.
 	indexed := xclock.WaitUntil(func() bool {
@@ -283,10 +288,10 @@ func TestShardAsyncIndexIfExpired(t *testing.T) {
 
 	// ensure we index because it's expired
 	nextWriteTime := now.Add(blockSize)
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"),
-			ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
-			nextWriteTime, 2.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "value"))),
+		nextWriteTime, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	// wait till we're done indexing.
 	reIndexed := xclock.WaitUntil(func() bool {
@@ -309,3 +314,27 @@ type testIndexWrite struct {
 	tags ident.Tags
 	ts   time.Time
 }
+
+func TestShardWriteTaggedValidatesFields(t *testing.T) {
+	opts := testDatabaseOptions()
+	opts = opts.SetIndexOptions(opts.IndexOptions().
+		SetReservedFieldNames([]string{"__internal__"}))
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	tags := ident.NewTags(ident.StringTag("__internal__", "x"))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"),
+		ident.NewTagsIterator(tags), time.Now(), 1.0, xtime.Second, nil)
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	// Series that already exist, such as those bootstrapped or retrieved
+	// from disk, are not validated so that they are not dropped
+	entry, err := shard.newShardEntry(ident.StringID("foo"),
+		newTagsIterArg(ident.NewTagsIterator(tags)))
+	require.NoError(t, err)
+	require.Equal(t, "foo", entry.Series.ID().String())
+}
@@ -29,7 +29,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3x/ident"
+	xlog "github.com/m3db/m3x/log"
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/uber-go/tally"
@@ -68,6 +70,10 @@ type dbShardInsertQueue struct {
 	notifyInsert chan struct{}
 	closeCh      chan struct{}
 
+	// cpuSet is the CPU set the insert loop is pinned to, if any.
+	cpuSet cpuset.CPUSet
+	logger xlog.Logger
+
 	metrics dbShardInsertQueueMetrics
 }
 
@@ -197,6 +203,12 @@ func (q *dbShardInsertQueue) insertLoop() {
 		close(q.closeCh)
 	}()
 
+	if len(q.cpuSet) > 0 {
+		if err := cpuset.LockAndPin(q.cpuSet); err != nil {
+			q.logger.Warnf("could not pin shard insert queue to cpus %s: %v", q.cpuSet, err)
+		}
+	}
+
 	var lastInsert time.Time
 	freeBatch := &dbShardInsertBatch{}
 	freeBatch.reset()
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errUnexpectedShardPolicyUnspecified = errors.New("unexpected shard policy unspecified")
+)
+
+// UnexpectedShardPolicy is the action taken at startup for fileset shard
+// directories found on disk for shards not assigned to the node, which
+// usually means a node was replaced with the wrong host ID or disk.
+type UnexpectedShardPolicy uint
+
+const (
+	// UnexpectedShardIgnore leaves the shards to be deleted by the cleanup
+	// of shards the node no longer owns.
+	UnexpectedShardIgnore UnexpectedShardPolicy = iota
+	// UnexpectedShardQuarantine moves the shards into the quarantine
+	// directory so they can be inspected or restored.
+	UnexpectedShardQuarantine
+	// UnexpectedShardReadOnly leaves the shards in place and serves reads
+	// for them while rejecting writes to them, until the placement assigns
+	// them to the node.
+	UnexpectedShardReadOnly
+
+	// DefaultUnexpectedShardPolicy is the default unexpected shard policy.
+	DefaultUnexpectedShardPolicy = UnexpectedShardIgnore
+)
+
+// ValidUnexpectedShardPolicies returns the valid unexpected shard policies.
+func ValidUnexpectedShardPolicies() []UnexpectedShardPolicy {
+	return []UnexpectedShardPolicy{UnexpectedShardIgnore, UnexpectedShardQuarantine,
+		UnexpectedShardReadOnly}
+}
+
+func (p UnexpectedShardPolicy) String() string {
+	switch p {
+	case UnexpectedShardIgnore:
+		return "ignore"
+	case UnexpectedShardQuarantine:
+		return "quarantine"
+	case UnexpectedShardReadOnly:
+		return "read_only"
+	}
+	return "unknown"
+}
+
+// ParseUnexpectedShardPolicy parses an UnexpectedShardPolicy from a string.
+func ParseUnexpectedShardPolicy(str string) (UnexpectedShardPolicy, error) {
+	var r UnexpectedShardPolicy
+	if str == "" {
+		return r, errUnexpectedShardPolicyUnspecified
+	}
+	for _, valid := range ValidUnexpectedShardPolicies() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid UnexpectedShardPolicy '%s' valid types are: %v",
+		str, ValidUnexpectedShardPolicies())
+}
+
+// UnmarshalYAML unmarshals an UnexpectedShardPolicy into a valid type from string.
+func (p *UnexpectedShardPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseUnexpectedShardPolicy(str)
+	if err != nil {
+		return err
+	}
+	*p = r
+	return nil
+}
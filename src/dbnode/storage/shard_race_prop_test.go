@@ -161,7 +161,8 @@ func TestShardTickWriteRace(t *testing.T) {
 			<-barrier
 			ctx := context.NewContext()
 			now := time.Now()
-			require.NoError(t, shard.Write(ctx, id, now, 1.0, xtime.Second, nil))
+			_, err := shard.Write(ctx, id, now, 1.0, xtime.Second, nil)
+			require.NoError(t, err)
 			ctx.BlockingClose()
 			wg.Done()
 		}()
@@ -70,12 +70,11 @@ func TestShardWriteSyncRefCount(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("foo"), now, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("bar"), now, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("baz"), now, 3.0, xtime.Second, nil))
+	for _, id := range []string{"foo", "bar", "baz"} {
+		newSeries, err := shard.Write(ctx, ident.StringID(id), now, 1.0, xtime.Second, nil)
+		assert.NoError(t, err)
+		assert.True(t, newSeries, id)
+	}
 
 	// ensure all entries have no references left
 	for _, id := range []string{"foo", "bar", "baz"} {
@@ -88,12 +87,11 @@ func TestShardWriteSyncRefCount(t *testing.T) {
 
 	// write already inserted series'
 	next := now.Add(time.Minute)
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("foo"), next, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("bar"), next, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("baz"), next, 3.0, xtime.Second, nil))
+	for _, id := range []string{"foo", "bar", "baz"} {
+		newSeries, err := shard.Write(ctx, ident.StringID(id), next, 1.0, xtime.Second, nil)
+		assert.NoError(t, err)
+		assert.False(t, newSeries, id)
+	}
 
 	written := xclock.WaitUntil(func() bool {
 		return atomic.LoadInt32(&numCommitLogWrites) == 6
@@ -122,9 +120,10 @@ func TestShardWriteTaggedSyncRefCountMockIndex(t *testing.T) {
 			return xtime.ToUnixNano(t.Truncate(blockSize))
 		}).
 		AnyTimes()
-	idx.EXPECT().WriteBatch(gomock.Any()).
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).
 		Return(nil).
-		Do(func(batch *index.WriteBatch) {
+		Do(func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 			if batch.Len() != 1 {
 				// require.Equal(...) silently kills goroutines
 				panic(fmt.Sprintf("expected batch len 1: len=%d", batch.Len()))
@@ -186,12 +185,12 @@ func testShardWriteTaggedSyncRefCount(t *testing.T, idx namespaceIndex) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, now, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, now, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, now, 3.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, now, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, now, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, now, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	// ensure all entries have no references left
 	for _, id := range []string{"foo", "bar", "baz"} {
@@ -204,12 +203,12 @@ func testShardWriteTaggedSyncRefCount(t *testing.T, idx namespaceIndex) {
 
 	// write already inserted series'
 	next := now.Add(time.Minute)
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, next, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, next, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, next, 3.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, next, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, next, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, next, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	written := xclock.WaitUntil(func() bool {
 		return atomic.LoadInt32(&numCommitLogWrites) == 6
@@ -261,12 +260,12 @@ func TestShardWriteAsyncRefCount(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("foo"), now, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("bar"), now, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("baz"), now, 3.0, xtime.Second, nil))
+	_, err := shard.Write(ctx, ident.StringID("foo"), now, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.Write(ctx, ident.StringID("bar"), now, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.Write(ctx, ident.StringID("baz"), now, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	inserted := xclock.WaitUntil(func() bool {
 		counter, ok := testReporter.Counters()["dbshard.insert-queue.inserts"]
@@ -285,12 +284,12 @@ func TestShardWriteAsyncRefCount(t *testing.T) {
 
 	// write already inserted series'
 	next := now.Add(time.Minute)
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("foo"), next, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("bar"), next, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.Write(ctx, ident.StringID("baz"), next, 3.0, xtime.Second, nil))
+	_, err = shard.Write(ctx, ident.StringID("foo"), next, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.Write(ctx, ident.StringID("bar"), next, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.Write(ctx, ident.StringID("baz"), next, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	written := xclock.WaitUntil(func() bool {
 		return atomic.LoadInt32(&numCommitLogWrites) == 6
@@ -319,9 +318,10 @@ func TestShardWriteTaggedAsyncRefCountMockIndex(t *testing.T) {
 			return xtime.ToUnixNano(t.Truncate(blockSize))
 		}).
 		AnyTimes()
-	idx.EXPECT().WriteBatch(gomock.Any()).
+	idx.EXPECT().WriteSeriesBatch(gomock.Any()).
 		Return(nil).
-		Do(func(batch *index.WriteBatch) {
+		Do(func(series []index.WriteBatchSeries) {
+			batch := index.NewSeriesWriteBatch(index.WriteBatchOptions{}, series)
 			for _, entry := range batch.PendingEntries() {
 				blockStart := xtime.ToUnixNano(entry.Timestamp.Truncate(blockSize))
 				onIdx := entry.OnIndexSeries
@@ -389,12 +389,12 @@ func testShardWriteTaggedAsyncRefCount(t *testing.T, idx namespaceIndex) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, now, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, now, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, now, 3.0, xtime.Second, nil))
+	_, err := shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, now, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, now, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, now, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	inserted := xclock.WaitUntil(func() bool {
 		counter, ok := testReporter.Counters()["dbshard.insert-queue.inserts"]
@@ -413,12 +413,12 @@ func testShardWriteTaggedAsyncRefCount(t *testing.T, idx namespaceIndex) {
 
 	// write already inserted series'
 	next := now.Add(time.Minute)
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, next, 1.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, next, 2.0, xtime.Second, nil))
-	assert.NoError(t,
-		shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, next, 3.0, xtime.Second, nil))
+	_, err = shard.WriteTagged(ctx, ident.StringID("foo"), ident.EmptyTagIterator, next, 1.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("bar"), ident.EmptyTagIterator, next, 2.0, xtime.Second, nil)
+	assert.NoError(t, err)
+	_, err = shard.WriteTagged(ctx, ident.StringID("baz"), ident.EmptyTagIterator, next, 3.0, xtime.Second, nil)
+	assert.NoError(t, err)
 
 	written := xclock.WaitUntil(func() bool {
 		return atomic.LoadInt32(&numCommitLogWrites) == 6
@@ -23,6 +23,7 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -30,8 +31,11 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -40,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3x/checked"
@@ -104,6 +109,19 @@ func TestShardDontNeedBootstrap(t *testing.T) {
 	require.True(t, shard.newSeriesBootstrapped)
 }
 
+func TestShardInsertQueueWriteCPUSet(t *testing.T) {
+	opts := testDatabaseOptions().
+		SetWriteCPUSets([]cpuset.CPUSet{{0}, {1}})
+	testNs, closer := newTestNamespace(t)
+	defer closer()
+	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
+	shard := newDatabaseShard(testNs.metadata, 3, nil, nil,
+		&testIncreasingIndex{}, commitLogWriteNoOp, nil, false, opts, seriesOpts).(*dbShard)
+	defer shard.Close()
+
+	require.Equal(t, cpuset.CPUSet{1}, shard.insertQueue.cpuSet)
+}
+
 func TestShardBootstrapState(t *testing.T) {
 	opts := testDatabaseOptions()
 	testNs, closer := newTestNamespace(t)
@@ -138,6 +156,28 @@ func TestShardFlushStateNotStarted(t *testing.T) {
 	}
 }
 
+func TestShardMarkSkippedBlocksFlushed(t *testing.T) {
+	opts := testDatabaseOptions()
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	var (
+		blockSize = s.namespace.Options().RetentionOptions().BlockSize()
+		start     = time.Now().Truncate(blockSize).Add(-4 * blockSize)
+		failed    = start.Add(blockSize)
+		end       = start.Add(3 * blockSize)
+	)
+	s.markFlushStateFail(failed)
+
+	s.MarkSkippedBlocksFlushed(xtime.Range{Start: start, End: end})
+
+	success := fileOpState{Status: fileOpSuccess}
+	assert.Equal(t, success, s.FlushState(start))
+	assert.Equal(t, fileOpFailed, s.FlushState(failed).Status)
+	assert.Equal(t, success, s.FlushState(start.Add(2*blockSize)))
+	assert.Equal(t, fileOpNotStarted, s.FlushState(end).Status)
+}
+
 func TestShardBootstrapWithError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -471,6 +511,122 @@ func TestShardTick(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestShardBootstrapWriteBuffer(t *testing.T) {
+	opts := testDatabaseOptions().SetBootstrapWriteBufferSize(2)
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	var (
+		commitLogLock   sync.Mutex
+		commitLogWrites []string
+	)
+	s.commitLogWriter = commitLogWriterFn(func(
+		ctx context.Context,
+		series commitlog.Series,
+		datapoint ts.Datapoint,
+		unit xtime.Unit,
+		annotation ts.Annotation,
+	) error {
+		commitLogLock.Lock()
+		commitLogWrites = append(commitLogWrites, series.ID.String())
+		commitLogLock.Unlock()
+		return nil
+	})
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	now := time.Now().Truncate(time.Second)
+	_, err := s.Write(ctx, ident.StringID("foo"), now.Add(time.Second), 1.0, xtime.Second, nil)
+	require.NoError(t, err)
+	_, err = s.Write(ctx, ident.StringID("foo"), now, 2.0, xtime.Second, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, s.bootstrapWriteBuffer.Len())
+
+	// Buffered writes are written to the commit log before being acknowledged
+	require.Equal(t, []string{"foo", "foo"}, commitLogWrites)
+
+	// Write should not be applied until bootstrapped
+	s.RLock()
+	_, _, err = s.lookupEntryWithLock(ident.StringID("foo"))
+	s.RUnlock()
+	require.Equal(t, errShardEntryNotFound, err)
+
+	// Buffered writes are visible to reads in timestamp order
+	requireShardReadValues(t, s, "foo", now, []float64{2.0, 1.0})
+
+	// Buffer is full, the write waits for capacity rather than being rejected
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx := context.NewContext()
+		defer ctx.Close()
+		_, err := s.Write(ctx, ident.StringID("foo"), now, 3.0, xtime.Second, nil)
+		assert.NoError(t, err)
+	}()
+
+	require.NoError(t, s.Bootstrap(result.NewMap(result.MapOptions{})))
+	wg.Wait()
+	require.Equal(t, 0, s.bootstrapWriteBuffer.Len())
+	require.True(t, s.bootstrapWriteBuffer.IsDrained())
+
+	// Applying the buffered writes does not write them to the commit log
+	// again, and they are applied in the order they were accepted so the
+	// first write of a timestamp wins
+	require.Equal(t, []string{"foo", "foo", "foo"}, commitLogWrites)
+	requireShardReadValues(t, s, "foo", now, []float64{2.0, 1.0})
+
+	// Once drained writes are applied directly
+	_, err = s.Write(ctx, ident.StringID("bar"), now, 2.0, xtime.Second, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, s.bootstrapWriteBuffer.Len())
+}
+
+func requireShardReadValues(
+	t *testing.T,
+	s *dbShard,
+	id string,
+	start time.Time,
+	expected []float64,
+) {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	readers, err := s.ReadEncoded(ctx, ident.StringID(id), start, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	iter := encoding.NewMultiReaderIterator(func(r io.Reader) encoding.ReaderIterator {
+		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled,
+			encoding.NewOptions())
+	}, nil)
+	defer iter.Close()
+	iter.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(readers))
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, expected, values)
+}
+
+func TestCopyBufferedWriteTags(t *testing.T) {
+	name, value := []byte("name"), []byte("value")
+	iter := ident.NewTagsIterator(ident.NewTags(
+		ident.Tag{Name: ident.BytesID(name), Value: ident.BytesID(value)}))
+
+	tags, err := copyBufferedWriteTags(iter)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(tags.Values()))
+
+	// Copied tags must not reference the bytes of the source tags
+	name[0], value[0] = 'x', 'x'
+	assert.Equal(t, "name", tags.Values()[0].Name.String())
+	assert.Equal(t, "value", tags.Values()[0].Value.String())
+}
+
 func TestShardWriteAsync(t *testing.T) {
 	testReporter := xmetrics.NewTestStatsReporter(xmetrics.NewTestStatsReporterOptions())
 	scope, closer := tally.NewRootScope(tally.ScopeOptions{
@@ -63,14 +63,16 @@ func newTickManagerMetrics(scope tally.Scope) tickManagerMetrics {
 }
 
 type tickManager struct {
-	database database
-	opts     Options
-	nowFn    clock.NowFn
-	sleepFn  sleepFn
+	database  database
+	opts      Options
+	nowFn     clock.NowFn
+	sleepFn   sleepFn
+	newTicker clock.NewTickerFn
 
-	metrics tickManagerMetrics
-	c       context.Cancellable
-	tokenCh chan struct{}
+	metrics   tickManagerMetrics
+	schedules BackgroundSchedules
+	c         context.Cancellable
+	tokenCh   chan struct{}
 
 	runtimeOpts tickManagerRuntimeOptions
 }
@@ -103,13 +105,15 @@ func newTickManager(database database, opts Options) databaseTickManager {
 	tokenCh <- struct{}{}
 
 	mgr := &tickManager{
-		database: database,
-		opts:     opts,
-		nowFn:    opts.ClockOptions().NowFn(),
-		sleepFn:  time.Sleep,
-		metrics:  newTickManagerMetrics(scope),
-		c:        context.NewCancellable(),
-		tokenCh:  tokenCh,
+		database:  database,
+		opts:      opts,
+		nowFn:     opts.ClockOptions().NowFn(),
+		sleepFn:   opts.ClockOptions().SleepFn(),
+		newTicker: opts.ClockOptions().NewTickerFn(),
+		metrics:   newTickManagerMetrics(scope),
+		schedules: opts.BackgroundSchedules(),
+		c:         context.NewCancellable(),
+		tokenCh:   tokenCh,
 	}
 
 	runtimeOptsMgr := opts.RuntimeOptionsManager()
@@ -126,14 +130,14 @@ func (mgr *tickManager) SetRuntimeOptions(opts runtime.Options) {
 func (mgr *tickManager) Tick(forceType forceType, tickStart time.Time) error {
 	if forceType == force {
 		acquired := false
-		waiter := time.NewTicker(tokenCheckInterval)
+		waiter := mgr.newTicker(tokenCheckInterval)
 		// NB(xichen): cancellation is done in a loop so if there are multiple
 		// forced ticks, their cancellations don't get reset when token is acquired.
 		for !acquired {
 			select {
 			case <-mgr.tokenCh:
 				acquired = true
-			case <-waiter.C:
+			case <-waiter.C():
 				mgr.c.Cancel()
 			}
 		}
@@ -151,6 +155,13 @@ func (mgr *tickManager) Tick(forceType forceType, tickStart time.Time) error {
 
 	// Now we acquired the token, reset the cancellable
 	mgr.c.Reset()
+	start := mgr.nowFn()
+	err := mgr.tick(start, tickStart)
+	mgr.schedules.RecordRun(BackgroundProcessTick, start, err)
+	return err
+}
+
+func (mgr *tickManager) tick(start, tickStart time.Time) error {
 	namespaces, err := mgr.database.GetOwnedNamespaces()
 	if err != nil {
 		return err
@@ -160,12 +171,12 @@ func (mgr *tickManager) Tick(forceType forceType, tickStart time.Time) error {
 	}
 
 	// Begin ticking
-	var (
-		start    = mgr.nowFn()
-		multiErr xerrors.MultiError
-	)
+	var multiErr xerrors.MultiError
 	for _, n := range namespaces {
-		multiErr = multiErr.Add(n.Tick(mgr.c, tickStart))
+		nsStart := mgr.nowFn()
+		err := n.Tick(mgr.c, tickStart)
+		mgr.schedules.RecordNamespaceRun(BackgroundProcessTick, n.ID(), nsStart, err)
+		multiErr = multiErr.Add(err)
 	}
 
 	// NB(r): Always sleep for some constant period since ticking
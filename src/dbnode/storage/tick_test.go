@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/ident"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,7 @@ func TestTickManagerTickNormalFlow(t *testing.T) {
 	c := context.NewCancellable()
 
 	namespace := NewMockdatabaseNamespace(ctrl)
+	namespace.EXPECT().ID().Return(ident.StringID("testns")).AnyTimes()
 	namespace.EXPECT().Tick(c, gomock.Any())
 	db := newMockdatabase(ctrl, namespace)
 
@@ -62,6 +64,7 @@ func TestTickManagerTickCancelled(t *testing.T) {
 	c := context.NewCancellable()
 
 	namespace := NewMockdatabaseNamespace(ctrl)
+	namespace.EXPECT().ID().Return(ident.StringID("testns")).AnyTimes()
 	namespace.EXPECT().Tick(c, gomock.Any()).Do(func(context.Cancellable, time.Time) {
 		ch1 <- struct{}{}
 		<-ch2
@@ -96,6 +99,7 @@ func TestTickManagerTickErrorFlow(t *testing.T) {
 
 	fakeErr := errors.New("fake error")
 	namespace := NewMockdatabaseNamespace(ctrl)
+	namespace.EXPECT().ID().Return(ident.StringID("testns")).AnyTimes()
 	namespace.EXPECT().Tick(c, gomock.Any()).Return(fakeErr)
 	db := newMockdatabase(ctrl, namespace)
 
@@ -107,6 +111,11 @@ func TestTickManagerTickErrorFlow(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, fakeErr.Error(), err.Error())
 	require.Equal(t, 1, len(tm.tokenCh))
+
+	schedule := opts.BackgroundSchedules().Schedules()[0]
+	require.Equal(t, BackgroundProcessTick, schedule.Process)
+	require.Equal(t, fakeErr.Error(), schedule.LastRun.Error)
+	require.Equal(t, fakeErr.Error(), schedule.Namespaces["testns"].Error)
 }
 
 func TestTickManagerNonForcedTickDuringOngoingTick(t *testing.T) {
@@ -120,6 +129,7 @@ func TestTickManagerNonForcedTickDuringOngoingTick(t *testing.T) {
 	c := context.NewCancellable()
 
 	namespace := NewMockdatabaseNamespace(ctrl)
+	namespace.EXPECT().ID().Return(ident.StringID("testns")).AnyTimes()
 	namespace.EXPECT().Tick(c, gomock.Any()).Do(func(context.Cancellable, time.Time) {
 		ch1 <- struct{}{}
 		<-ch2
@@ -158,6 +168,7 @@ func TestTickManagerForcedTickDuringOngoingTick(t *testing.T) {
 	c := context.NewCancellable()
 
 	namespace := NewMockdatabaseNamespace(ctrl)
+	namespace.EXPECT().ID().Return(ident.StringID("testns")).AnyTimes()
 	gomock.InOrder(
 		namespace.EXPECT().Tick(c, gomock.Any()).Do(func(context.Cancellable, time.Time) {
 			ch1 <- struct{}{}
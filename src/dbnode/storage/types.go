@@ -22,7 +22,6 @@ package storage
 
 import (
 	"bytes"
-	"sync"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
@@ -38,8 +37,11 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/x/cpuset"
 	"github.com/m3db/m3/src/dbnode/x/xcounter"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3cluster/shard"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -48,6 +50,11 @@ import (
 	xtime "github.com/m3db/m3x/time"
 )
 
+// ReadOnlyShardState is the state of shards in an assigned shard set that
+// are not assigned to the node by the placement and are only opened to
+// serve reads of the data on disk.
+const ReadOnlyShardState = shard.Unknown
+
 // PageToken is an opaque paging token.
 type PageToken []byte
 
@@ -80,7 +87,8 @@ type Database interface {
 	// the GC to do so.
 	Terminate() error
 
-	// Write value to the database for an ID
+	// Write value to the database for an ID, returns whether the write
+	// created a new series rather than writing to an existing one.
 	Write(
 		ctx context.Context,
 		namespace ident.ID,
@@ -89,9 +97,10 @@ type Database interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
-	// WriteTagged values to the database for an ID
+	// WriteTagged values to the database for an ID, returns whether the
+	// write created a new series rather than writing to an existing one.
 	WriteTagged(
 		ctx context.Context,
 		namespace ident.ID,
@@ -101,7 +110,7 @@ type Database interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
 	// QueryIDs resolves the given query into known IDs.
 	QueryIDs(
@@ -111,6 +120,35 @@ type Database interface {
 		opts index.QueryOptions,
 	) (index.QueryResults, error)
 
+	// QueryIDsMultiNamespace resolves the given query into known IDs across
+	// each of the given namespaces, executing the query against each
+	// namespace concurrently and merging the exhaustiveness and warnings of
+	// the results. Series are not deduplicated across namespaces since a
+	// series of the same ID in each namespace holds distinct datapoints.
+	QueryIDsMultiNamespace(
+		ctx context.Context,
+		namespaces []ident.ID,
+		query index.Query,
+		opts index.QueryOptions,
+	) (index.MultiNamespaceQueryResults, error)
+
+	// IndexCardinalityReport returns the last computed cardinality of each
+	// tag name of each index block of a namespace.
+	IndexCardinalityReport(namespace ident.ID) (index.CardinalityReport, error)
+
+	// IntegrityReport returns how durably each block of a namespace within
+	// the time range is persisted across the shards owned by the node, a
+	// zero start or end defaults to the retention period or now.
+	IntegrityReport(namespace ident.ID, start, end time.Time) (IntegrityReport, error)
+
+	// ConsistencyCheck compares the series with data in the index block of
+	// a namespace containing the block start with the series in the index.
+	ConsistencyCheck(
+		namespace ident.ID,
+		blockStart time.Time,
+		opts ConsistencyCheckOptions,
+	) (ConsistencyReport, error)
+
 	// ReadEncoded retrieves encoded segments for an ID
 	ReadEncoded(
 		ctx context.Context,
@@ -171,6 +209,17 @@ type Database interface {
 
 	// BootstrapState captures and returns a snapshot of the databases' bootstrap state.
 	BootstrapState() DatabaseBootstrapState
+
+	// FlushBarrier flushes, snapshots and flushes the index of the given
+	// namespaces, or of all namespaces if none are given, at a single point
+	// in time and returns the fileset volumes a backup should copy. Cleanups
+	// are held, so that the volumes are not removed, until the barrier is
+	// released or it expires.
+	FlushBarrier(namespaces []ident.ID) (FlushBarrier, error)
+
+	// ReleaseFlushBarrier releases the hold on cleanups of the flush barrier
+	// with the token once its volumes have been copied.
+	ReleaseFlushBarrier(token string) error
 }
 
 // database is the internal database interface
@@ -197,6 +246,9 @@ type Namespace interface {
 
 	// Shards returns the shard description
 	Shards() []Shard
+
+	// Usage returns the utilization of the namespace on the node.
+	Usage() (NamespaceUsage, error)
 }
 
 // NamespacesByID is a sortable slice of namespaces by ID
@@ -217,16 +269,36 @@ type databaseNamespace interface {
 	// AssignShardSet sets the shard set assignment and returns immediately
 	AssignShardSet(shardSet sharding.ShardSet)
 
+	// ExtendRetentionPeriod extends the retention period of the namespace in
+	// place and makes any filesets still on disk within it retrievable again
+	ExtendRetentionPeriod(value time.Duration) error
+
+	// SetIndexInsertMode sets the mode new series of the namespace are
+	// indexed with in place.
+	SetIndexInsertMode(value namespace.IndexInsertMode) error
+
 	// GetOwnedShards returns the database shards
 	GetOwnedShards() []databaseShard
 
+	// GetWritableShards returns the database shards excluding those that
+	// are only served read only
+	GetWritableShards() []databaseShard
+
+	// ConsistencyCheck compares the series with data in the index block
+	// containing the block start with the series in the index.
+	ConsistencyCheck(
+		blockStart time.Time,
+		opts ConsistencyCheckOptions,
+	) (ConsistencyReport, error)
+
 	// GetIndex returns the reverse index backing the namespace, if it exists.
 	GetIndex() (namespaceIndex, error)
 
 	// Tick performs any regular maintenance operations
 	Tick(c context.Cancellable, tickStart time.Time) error
 
-	// Write writes a data point
+	// Write writes a data point, returns whether the write created a new
+	// series.
 	Write(
 		ctx context.Context,
 		id ident.ID,
@@ -234,9 +306,10 @@ type databaseNamespace interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
-	// WriteTagged values to the namespace for an ID
+	// WriteTagged values to the namespace for an ID, returns whether the
+	// write created a new series.
 	WriteTagged(
 		ctx context.Context,
 		id ident.ID,
@@ -245,7 +318,7 @@ type databaseNamespace interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
 	// QueryIDs resolves the given query into known IDs.
 	QueryIDs(
@@ -361,6 +434,8 @@ type databaseShard interface {
 	// Tick performs any updates to ensure series drain their buffers and blocks are flushed, etc
 	Tick(c context.Cancellable, tickStart time.Time) (tickResult, error)
 
+	// Write writes a data point, returns whether the write created a new
+	// series.
 	Write(
 		ctx context.Context,
 		id ident.ID,
@@ -368,9 +443,10 @@ type databaseShard interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
-	// WriteTagged values to the shard for an ID
+	// WriteTagged values to the shard for an ID, returns whether the write
+	// created a new series.
 	WriteTagged(
 		ctx context.Context,
 		id ident.ID,
@@ -379,7 +455,7 @@ type databaseShard interface {
 		value float64,
 		unit xtime.Unit,
 		annotation []byte,
-	) error
+	) (bool, error)
 
 	ReadEncoded(
 		ctx context.Context,
@@ -429,6 +505,15 @@ type databaseShard interface {
 	// FlushState returns the flush state for this shard at block start.
 	FlushState(blockStart time.Time) fileOpState
 
+	// LoadFlushStates marks any blocks with filesets on disk as flushed so
+	// they are retrievable, blocks with recorded progress are left as is.
+	LoadFlushStates()
+
+	// MarkSkippedBlocksFlushed marks the blocks within a time range that have
+	// no recorded progress as flushed, used for blocks the bootstrap skipped
+	// so they are never flushed empty.
+	MarkSkippedBlocksFlushed(skipped xtime.Range)
+
 	// SnapshotState returns the snapshot state for this shard.
 	SnapshotState() (isSnapshotting bool, lastSuccessfulSnapshot time.Time)
 
@@ -444,6 +529,10 @@ type databaseShard interface {
 		tr xtime.Range,
 		repairer databaseShardRepairer,
 	) (repair.MetadataComparisonResult, error)
+
+	// ForEachSeriesDocument calls fn with the index document of each series
+	// in the shard until fn returns false.
+	ForEachSeriesDocument(fn func(d doc.Document) bool) error
 }
 
 // namespaceIndex indexes namespace writes.
@@ -459,6 +548,12 @@ type namespaceIndex interface {
 		batch *index.WriteBatch,
 	) error
 
+	// WriteSeriesBatch converts the provided series to documents and indexes
+	// them as a single batch.
+	WriteSeriesBatch(
+		series []index.WriteBatchSeries,
+	) error
+
 	// Query resolves the given query into known IDs.
 	Query(
 		ctx context.Context,
@@ -486,6 +581,17 @@ type namespaceIndex interface {
 		shards []databaseShard,
 	) error
 
+	// CardinalityReport returns the last computed cardinality of each tag
+	// name of each block.
+	CardinalityReport() (index.CardinalityReport, error)
+
+	// ReloadEvictedBlocks loads the flushed blocks within retention that are
+	// not in the index from disk.
+	ReloadEvictedBlocks() error
+
+	// SetInsertMode sets the mode new series are indexed with.
+	SetInsertMode(value index.InsertMode)
+
 	// Close will release the index resources and close the index.
 	Close() error
 }
@@ -511,9 +617,9 @@ type namespaceIndexInsertQueue interface {
 
 	// InsertBatch inserts the provided documents to the index queue which processes
 	// inserts to the index asynchronously. It executes the provided callbacks
-	// based on the result of the execution. The returned wait group can be used
+	// based on the result of the execution. The returned future can be used
 	// if the insert is required to be synchronous.
-	InsertBatch(batch *index.WriteBatch) (*sync.WaitGroup, error)
+	InsertBatch(batch *index.WriteBatch) (*indexInsertFuture, error)
 }
 
 // databaseBootstrapManager manages the bootstrap process.
@@ -533,6 +639,14 @@ type databaseFlushManager interface {
 	// Flush flushes in-memory data to persistent storage.
 	Flush(tickStart time.Time, dbBootstrapStateAtTickStart DatabaseBootstrapState) error
 
+	// FlushNamespaces flushes, snapshots and flushes the index of the
+	// in-memory data of the given namespaces to persistent storage.
+	FlushNamespaces(
+		tickStart time.Time,
+		dbBootstrapStateAtTickStart DatabaseBootstrapState,
+		namespaces []databaseNamespace,
+	) error
+
 	// Report reports runtime information
 	Report()
 }
@@ -554,6 +668,14 @@ type databaseFileSystemManager interface {
 	// Flush flushes in-memory data to persistent storage.
 	Flush(t time.Time, dbBootstrapStateAtTickStart DatabaseBootstrapState) error
 
+	// FlushNamespaces flushes, snapshots and flushes the index of the
+	// in-memory data of the given namespaces to persistent storage.
+	FlushNamespaces(
+		t time.Time,
+		dbBootstrapStateAtTickStart DatabaseBootstrapState,
+		namespaces []databaseNamespace,
+	) error
+
 	// Disable disables the filesystem manager and prevents it from
 	// performing file operations, returns the current file operation status
 	Disable() fileOpStatus
@@ -564,6 +686,14 @@ type databaseFileSystemManager interface {
 	// Status returns the file operation status
 	Status() fileOpStatus
 
+	// HoldCleanup defers cleanups until the hold with the token is released
+	// or the given time passes.
+	HoldCleanup(token string, until time.Time)
+
+	// ReleaseCleanup releases the hold on cleanups with the token, returning
+	// whether the hold existed.
+	ReleaseCleanup(token string) bool
+
 	// Run attempts to perform all filesystem-related operations,
 	// returning true if those operations are performed, and false otherwise
 	Run(
@@ -631,6 +761,27 @@ type databaseMediator interface {
 	// EnableFileOps enables file operations
 	EnableFileOps()
 
+	// FlushNamespaces flushes, snapshots and flushes the index of the
+	// given namespaces at the given time, file operations should be
+	// disabled to prevent a concurrent flush.
+	FlushNamespaces(
+		t time.Time,
+		dbBootstrapStateAtTickStart DatabaseBootstrapState,
+		namespaces []databaseNamespace,
+	) error
+
+	// LastTickStart returns the start of the last complete tick, zero if
+	// no tick has completed yet.
+	LastTickStart() time.Time
+
+	// HoldCleanup defers cleanups until the hold with the token is released
+	// or the given time passes.
+	HoldCleanup(token string, until time.Time)
+
+	// ReleaseCleanup releases the hold on cleanups with the token, returning
+	// whether the hold existed.
+	ReleaseCleanup(token string) bool
+
 	// Tick performs a tick
 	Tick(runType runType, forceType forceType) error
 
@@ -730,6 +881,22 @@ type Options interface {
 	// RepairEnabled returns whether the repair is enabled.
 	RepairEnabled() bool
 
+	// SetReadWhileBootstrapping sets whether reads are served for shards
+	// that have not yet finished bootstrapping.
+	SetReadWhileBootstrapping(value bool) Options
+
+	// ReadWhileBootstrapping returns whether reads are served for shards
+	// that have not yet finished bootstrapping.
+	ReadWhileBootstrapping() bool
+
+	// SetBootstrapWriteBufferSize sets the max number of writes per shard
+	// buffered while the shard is bootstrapping, zero disables buffering.
+	SetBootstrapWriteBufferSize(value int) Options
+
+	// BootstrapWriteBufferSize returns the max number of writes per shard
+	// buffered while the shard is bootstrapping, zero disables buffering.
+	BootstrapWriteBufferSize() int
+
 	// SetRepairOptions sets the repair options.
 	SetRepairOptions(value repair.Options) Options
 
@@ -825,6 +992,12 @@ type Options interface {
 	// MultiReaderIteratorPool returns the multiReaderIteratorPool.
 	MultiReaderIteratorPool() encoding.MultiReaderIteratorPool
 
+	// SetEncodingCodecRegistry sets the registry of codecs namespaces can select.
+	SetEncodingCodecRegistry(value encoding.CodecRegistry) Options
+
+	// EncodingCodecRegistry returns the registry of codecs namespaces can select.
+	EncodingCodecRegistry() encoding.CodecRegistry
+
 	// SetIDPool sets the ID pool.
 	SetIdentifierPool(value ident.Pool) Options
 
@@ -848,6 +1021,87 @@ type Options interface {
 
 	// QueryIDsWorkerPool returns the QueryIDs worker pool.
 	QueryIDsWorkerPool() xsync.WorkerPool
+
+	// SetWriteCPUSets sets the CPU sets the insert queues of the shards are
+	// pinned to, the queue of each shard is pinned to one of the sets in turn.
+	SetWriteCPUSets(value []cpuset.CPUSet) Options
+
+	// WriteCPUSets returns the CPU sets the insert queues of the shards are
+	// pinned to, the queue of each shard is pinned to one of the sets in turn.
+	WriteCPUSets() []cpuset.CPUSet
+
+	// SetBackgroundSchedules sets the background process schedules.
+	SetBackgroundSchedules(value BackgroundSchedules) Options
+
+	// BackgroundSchedules returns the background process schedules.
+	BackgroundSchedules() BackgroundSchedules
+
+	// SetUnexpectedShardPolicy sets the action taken at startup for shards
+	// found on disk that are not assigned to the node.
+	SetUnexpectedShardPolicy(value UnexpectedShardPolicy) Options
+
+	// UnexpectedShardPolicy returns the action taken at startup for shards
+	// found on disk that are not assigned to the node.
+	UnexpectedShardPolicy() UnexpectedShardPolicy
+
+	// SetReadOnlyShards sets the shards found on disk at startup that are not
+	// assigned to the node, they are opened to serve reads of the data on disk
+	// until the placement assigns them to the node.
+	SetReadOnlyShards(value []uint32) Options
+
+	// ReadOnlyShards returns the shards found on disk at startup that are not
+	// assigned to the node, they are opened to serve reads of the data on disk
+	// until the placement assigns them to the node.
+	ReadOnlyShards() []uint32
+
+	// SetFutureWritePolicy sets the action taken for datapoints with
+	// timestamps beyond the buffer future plus the max clock skew.
+	SetFutureWritePolicy(value FutureWritePolicy) Options
+
+	// FutureWritePolicy returns the action taken for datapoints with
+	// timestamps beyond the buffer future plus the max clock skew.
+	FutureWritePolicy() FutureWritePolicy
+
+	// SetMaxClockSkew sets how far beyond the buffer future datapoints are
+	// accepted, by clamping them, to tolerate clients with skewed clocks.
+	SetMaxClockSkew(value time.Duration) Options
+
+	// MaxClockSkew returns how far beyond the buffer future datapoints are
+	// accepted, by clamping them, to tolerate clients with skewed clocks.
+	MaxClockSkew() time.Duration
+}
+
+// BackgroundSchedules tracks the runs of the background processes of the
+// database and allows pausing them temporarily, e.g. during incidents, or
+// adjusting how often they run.
+type BackgroundSchedules interface {
+	// Schedules returns the schedule of each background process.
+	Schedules() []BackgroundProcessSchedule
+
+	// Pause pauses a background process for the duration, a run already
+	// in progress is not interrupted.
+	Pause(process BackgroundProcess, d time.Duration) error
+
+	// Resume resumes a paused background process.
+	Resume(process BackgroundProcess) error
+
+	// Adjust sets the minimum interval between the starts of the runs of a
+	// background process, zero lets the process run as often as it can.
+	Adjust(process BackgroundProcess, minInterval time.Duration) error
+
+	// ShouldRun returns whether a background process should start a run, i.e.
+	// it is not paused and its minimum interval has passed since its last run.
+	ShouldRun(process BackgroundProcess) bool
+
+	// IsPaused returns whether a background process is paused.
+	IsPaused(process BackgroundProcess) bool
+
+	// RecordRun records a completed run of a background process.
+	RecordRun(process BackgroundProcess, start time.Time, err error)
+
+	// RecordNamespaceRun records a completed run of a background process
+	// for a namespace.
+	RecordNamespaceRun(process BackgroundProcess, namespace ident.ID, start time.Time, err error)
 }
 
 // DatabaseBootstrapState stores a snapshot of the bootstrap state for all shards across all
@@ -21,6 +21,7 @@
 package storage
 
 import (
+	"sort"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
@@ -86,3 +87,27 @@ func filterTimes(times []time.Time, predicate func(t time.Time) bool) []time.Tim
 
 	return filtered
 }
+
+// sortNamespacesByPriority sorts namespaces by their priority class so that
+// higher priority namespaces are processed first when namespaces compete for
+// resources, namespaces of the same priority keep their order.
+func sortNamespacesByPriority(namespaces []databaseNamespace) {
+	sort.SliceStable(namespaces, func(i, j int) bool {
+		return namespaces[i].Options().Priority().Before(namespaces[j].Options().Priority())
+	})
+}
+
+// groupNamespacesByPriority sorts namespaces by their priority class and
+// returns the namespaces of each priority class, highest priority first.
+func groupNamespacesByPriority(namespaces []databaseNamespace) [][]databaseNamespace {
+	sortNamespacesByPriority(namespaces)
+
+	var groups [][]databaseNamespace
+	for i, ns := range namespaces {
+		if i == 0 || ns.Options().Priority() != namespaces[i-1].Options().Priority() {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], ns)
+	}
+	return groups
+}
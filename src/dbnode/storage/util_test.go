@@ -24,6 +24,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/storage/namespace"
+
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -105,3 +108,30 @@ func TestTimesInRange(t *testing.T) {
 	// [1, 100] with a gap of 1 ==> [100, 99, ..., 1]
 	require.Equal(t, timesForN(1, 100), timesInRange(timeFor(1), timeFor(100), w))
 }
+
+func TestSortNamespacesByPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newNamespace := func(priority namespace.Priority) *MockdatabaseNamespace {
+		ns := NewMockdatabaseNamespace(ctrl)
+		ns.EXPECT().Options().
+			Return(namespace.NewOptions().SetPriority(priority)).AnyTimes()
+		return ns
+	}
+
+	var (
+		background = newNamespace(namespace.PriorityBackground)
+		normal1    = newNamespace(namespace.PriorityNormal)
+		critical   = newNamespace(namespace.PriorityCritical)
+		normal2    = newNamespace(namespace.PriorityNormal)
+	)
+	namespaces := []databaseNamespace{background, normal1, critical, normal2}
+	sortNamespacesByPriority(namespaces)
+
+	// Compare by identity since the mocks are otherwise deeply equal.
+	expected := []databaseNamespace{critical, normal1, normal2, background}
+	for i := range expected {
+		require.True(t, expected[i] == namespaces[i], "namespace %d out of order", i)
+	}
+}
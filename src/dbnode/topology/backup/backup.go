@@ -0,0 +1,210 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package backup exports the placement and namespace registry of a cluster
+// to files, diffs exports and restores them, e.g. for disaster recovery
+// drills or to version the topology of a cluster alongside its config.
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/kvconfig"
+	"github.com/m3db/m3cluster/generated/proto/placementpb"
+	"github.com/m3db/m3cluster/kv"
+	"github.com/m3db/m3cluster/placement"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+)
+
+var (
+	errEmptyBackup = errors.New("backup has neither a placement nor namespaces")
+)
+
+// PlacementStore is the subset of a placement service used to export and
+// restore placements.
+type PlacementStore interface {
+	// Placement returns the placement and its version.
+	Placement() (placement.Placement, int, error)
+
+	// Set sets the placement.
+	Set(p placement.Placement) error
+
+	// SetIfNotExist sets the placement if there is none.
+	SetIfNotExist(p placement.Placement) error
+}
+
+// Backup is an export of the placement and namespace registry of a cluster,
+// each is nil if not set when exported.
+type Backup struct {
+	PlacementVersion  int
+	Placement         *placementpb.Placement
+	NamespacesVersion int
+	Namespaces        *nsproto.Registry
+}
+
+// Export exports the current placement and namespace registry.
+func Export(placements PlacementStore, store kv.Store) (Backup, error) {
+	var backup Backup
+
+	p, version, err := placements.Placement()
+	if err != nil && err != kv.ErrNotFound {
+		return Backup{}, err
+	}
+	if err == nil {
+		pb, err := p.Proto()
+		if err != nil {
+			return Backup{}, err
+		}
+		backup.Placement = pb
+		backup.PlacementVersion = version
+	}
+
+	value, err := store.Get(kvconfig.NamespacesKey)
+	if err != nil && err != kv.ErrNotFound {
+		return Backup{}, err
+	}
+	if err == nil {
+		var registry nsproto.Registry
+		if err := value.Unmarshal(&registry); err != nil {
+			return Backup{}, err
+		}
+		backup.Namespaces = &registry
+		backup.NamespacesVersion = value.Version()
+	}
+
+	return backup, nil
+}
+
+// Restore restores the placement and namespace registry of the backup, if
+// force is not set the restore fails if either is already set. The versions
+// of the restored placement and namespace registry start anew and do not
+// match the versions of the backup.
+func Restore(
+	b Backup,
+	placements PlacementStore,
+	store kv.Store,
+	force bool,
+) error {
+	if b.Placement == nil && b.Namespaces == nil {
+		return errEmptyBackup
+	}
+
+	if b.Placement != nil {
+		p, err := placement.NewPlacementFromProto(b.Placement)
+		if err != nil {
+			return err
+		}
+		if force {
+			err = placements.Set(p)
+		} else {
+			err = placements.SetIfNotExist(p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if b.Namespaces != nil {
+		var err error
+		if force {
+			_, err = store.Set(kvconfig.NamespacesKey, b.Namespaces)
+		} else {
+			_, err = store.SetIfNotExists(kvconfig.NamespacesKey, b.Namespaces)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backupJSON is the file format of a backup, the placement and namespace
+// registry are stored as their JSON protobuf representation.
+type backupJSON struct {
+	PlacementVersion  int             `json:"placementVersion"`
+	Placement         json.RawMessage `json:"placement,omitempty"`
+	NamespacesVersion int             `json:"namespacesVersion"`
+	Namespaces        json.RawMessage `json:"namespaces,omitempty"`
+}
+
+// Write writes the backup as JSON.
+func Write(w io.Writer, b Backup) error {
+	out := backupJSON{
+		PlacementVersion:  b.PlacementVersion,
+		NamespacesVersion: b.NamespacesVersion,
+	}
+	var err error
+	if b.Placement != nil {
+		if out.Placement, err = marshalProto(b.Placement); err != nil {
+			return err
+		}
+	}
+	if b.Namespaces != nil {
+		if out.Namespaces, err = marshalProto(b.Namespaces); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// Read reads a backup written as JSON.
+func Read(r io.Reader) (Backup, error) {
+	var in backupJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return Backup{}, err
+	}
+
+	b := Backup{
+		PlacementVersion:  in.PlacementVersion,
+		NamespacesVersion: in.NamespacesVersion,
+	}
+	if len(in.Placement) > 0 {
+		b.Placement = &placementpb.Placement{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(in.Placement), b.Placement); err != nil {
+			return Backup{}, err
+		}
+	}
+	if len(in.Namespaces) > 0 {
+		b.Namespaces = &nsproto.Registry{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(in.Namespaces), b.Namespaces); err != nil {
+			return Backup{}, err
+		}
+	}
+	return b, nil
+}
+
+func marshalProto(m proto.Message) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	marshaler := jsonpb.Marshaler{EmitDefaults: true}
+	if err := marshaler.Marshal(&buf, m); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
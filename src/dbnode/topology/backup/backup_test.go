@@ -0,0 +1,157 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/kvconfig"
+	"github.com/m3db/m3cluster/generated/proto/placementpb"
+	"github.com/m3db/m3cluster/kv"
+	"github.com/m3db/m3cluster/kv/mem"
+	"github.com/m3db/m3cluster/placement"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePlacementStore struct {
+	placement placement.Placement
+	version   int
+}
+
+func (s *fakePlacementStore) Placement() (placement.Placement, int, error) {
+	if s.placement == nil {
+		return nil, 0, kv.ErrNotFound
+	}
+	return s.placement, s.version, nil
+}
+
+func (s *fakePlacementStore) Set(p placement.Placement) error {
+	s.placement = p
+	s.version++
+	return nil
+}
+
+func (s *fakePlacementStore) SetIfNotExist(p placement.Placement) error {
+	if s.placement != nil {
+		return kv.ErrAlreadyExists
+	}
+	return s.Set(p)
+}
+
+func testPlacementProto() *placementpb.Placement {
+	return &placementpb.Placement{
+		Instances: map[string]*placementpb.Instance{
+			"host1": &placementpb.Instance{
+				Id:             "host1",
+				IsolationGroup: "rack1",
+				Zone:           "embedded",
+				Weight:         1,
+				Endpoint:       "host1:9000",
+				Hostname:       "host1",
+				Port:           9000,
+				Shards: []*placementpb.Shard{
+					&placementpb.Shard{Id: 0, State: placementpb.ShardState_AVAILABLE},
+					&placementpb.Shard{Id: 1, State: placementpb.ShardState_AVAILABLE},
+				},
+			},
+		},
+		ReplicaFactor: 1,
+		NumShards:     2,
+		IsSharded:     true,
+	}
+}
+
+func testNamespaces() *nsproto.Registry {
+	return &nsproto.Registry{
+		Namespaces: map[string]*nsproto.NamespaceOptions{
+			"metrics": &nsproto.NamespaceOptions{BootstrapEnabled: true},
+		},
+	}
+}
+
+func TestExportWriteReadRestore(t *testing.T) {
+	p, err := placement.NewPlacementFromProto(testPlacementProto())
+	require.NoError(t, err)
+	placements := &fakePlacementStore{placement: p, version: 3}
+	store := mem.NewStore()
+	_, err = store.Set(kvconfig.NamespacesKey, testNamespaces())
+	require.NoError(t, err)
+
+	exported, err := Export(placements, store)
+	require.NoError(t, err)
+	require.Equal(t, 3, exported.PlacementVersion)
+	require.NotNil(t, exported.Placement)
+	require.NotNil(t, exported.Namespaces)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, exported))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, exported.PlacementVersion, read.PlacementVersion)
+	assert.Equal(t, exported.NamespacesVersion, read.NamespacesVersion)
+	assert.Empty(t, Diff(exported, read))
+
+	// Restore into an empty cluster.
+	emptyPlacements := &fakePlacementStore{}
+	emptyStore := mem.NewStore()
+	require.NoError(t, Restore(read, emptyPlacements, emptyStore, false))
+	restored, err := Export(emptyPlacements, emptyStore)
+	require.NoError(t, err)
+	assert.Empty(t, Diff(exported, restored))
+
+	// Restoring over an existing topology requires force.
+	require.Error(t, Restore(read, emptyPlacements, emptyStore, false))
+	require.NoError(t, Restore(read, emptyPlacements, emptyStore, true))
+}
+
+func TestExportEmpty(t *testing.T) {
+	exported, err := Export(&fakePlacementStore{}, mem.NewStore())
+	require.NoError(t, err)
+	assert.Nil(t, exported.Placement)
+	assert.Nil(t, exported.Namespaces)
+	assert.Error(t, Restore(exported, &fakePlacementStore{}, mem.NewStore(), false))
+}
+
+func TestDiff(t *testing.T) {
+	a := Backup{Placement: testPlacementProto(), Namespaces: testNamespaces()}
+	b := Backup{Placement: testPlacementProto(), Namespaces: testNamespaces()}
+
+	b.Placement.Instances["host1"].Endpoint = "host1:9001"
+	b.Placement.Instances["host1"].Shards[1].State = placementpb.ShardState_LEAVING
+	b.Placement.Instances["host2"] = &placementpb.Instance{Id: "host2"}
+	b.Namespaces.Namespaces["other"] = &nsproto.NamespaceOptions{}
+	delete(b.Namespaces.Namespaces, "metrics")
+
+	assert.Equal(t, []string{
+		"placement: instance host1 endpoint changed from host1:9000 to host1:9001",
+		"placement: instance host1 shard 1 changed from AVAILABLE to LEAVING",
+		"placement: instance host2 added",
+		"namespaces: metrics removed",
+		"namespaces: other added",
+	}, Diff(a, b))
+
+	assert.Equal(t, []string{"placement: removed"},
+		Diff(a, Backup{Namespaces: testNamespaces()}))
+}
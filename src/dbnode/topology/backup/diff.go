@@ -0,0 +1,191 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"sort"
+
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3cluster/generated/proto/placementpb"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// Diff returns the differences from backup a to backup b, one per line,
+// versions are not compared.
+func Diff(a, b Backup) []string {
+	var diffs []string
+	diffs = append(diffs, diffPlacements(a.Placement, b.Placement)...)
+	diffs = append(diffs, diffNamespaces(a.Namespaces, b.Namespaces)...)
+	return diffs
+}
+
+func diffPlacements(a, b *placementpb.Placement) []string {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return []string{"placement: added"}
+	case b == nil:
+		return []string{"placement: removed"}
+	}
+
+	var diffs []string
+	field := func(name string, from, to interface{}) {
+		if from != to {
+			diffs = append(diffs, fmt.Sprintf("placement: %s changed from %v to %v", name, from, to))
+		}
+	}
+	field("numShards", a.NumShards, b.NumShards)
+	field("replicaFactor", a.ReplicaFactor, b.ReplicaFactor)
+	field("isSharded", a.IsSharded, b.IsSharded)
+	field("isMirrored", a.IsMirrored, b.IsMirrored)
+
+	for _, id := range unionKeys(instanceIDs(a), instanceIDs(b)) {
+		from, inA := a.Instances[id]
+		to, inB := b.Instances[id]
+		switch {
+		case !inB:
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s removed", id))
+		case !inA:
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s added", id))
+		default:
+			diffs = append(diffs, diffInstances(id, from, to)...)
+		}
+	}
+	return diffs
+}
+
+func diffInstances(id string, a, b *placementpb.Instance) []string {
+	var diffs []string
+	field := func(name string, from, to interface{}) {
+		if from != to {
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s %s changed from %v to %v",
+				id, name, from, to))
+		}
+	}
+	field("endpoint", a.Endpoint, b.Endpoint)
+	field("hostname", a.Hostname, b.Hostname)
+	field("port", a.Port, b.Port)
+	field("isolationGroup", a.IsolationGroup, b.IsolationGroup)
+	field("zone", a.Zone, b.Zone)
+	field("weight", a.Weight, b.Weight)
+	field("shardSetID", a.ShardSetId, b.ShardSetId)
+
+	shardsA, shardsB := shardsByID(a.Shards), shardsByID(b.Shards)
+	for _, shard := range unionShardIDs(shardsA, shardsB) {
+		from, inA := shardsA[shard]
+		to, inB := shardsB[shard]
+		switch {
+		case !inB:
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s shard %d removed", id, shard))
+		case !inA:
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s shard %d added", id, shard))
+		case !proto.Equal(from, to):
+			diffs = append(diffs, fmt.Sprintf("placement: instance %s shard %d changed from %v to %v",
+				id, shard, from.State, to.State))
+		}
+	}
+	return diffs
+}
+
+func diffNamespaces(a, b *nsproto.Registry) []string {
+	var namespacesA, namespacesB map[string]*nsproto.NamespaceOptions
+	if a != nil {
+		namespacesA = a.Namespaces
+	}
+	if b != nil {
+		namespacesB = b.Namespaces
+	}
+
+	var diffs []string
+	for _, name := range unionKeys(nsKeys(namespacesA), nsKeys(namespacesB)) {
+		from, inA := namespacesA[name]
+		to, inB := namespacesB[name]
+		switch {
+		case !inB:
+			diffs = append(diffs, fmt.Sprintf("namespaces: %s removed", name))
+		case !inA:
+			diffs = append(diffs, fmt.Sprintf("namespaces: %s added", name))
+		case !proto.Equal(from, to):
+			diffs = append(diffs, fmt.Sprintf("namespaces: %s options changed from {%v} to {%v}",
+				name, from, to))
+		}
+	}
+	return diffs
+}
+
+func instanceIDs(p *placementpb.Placement) []string {
+	ids := make([]string, 0, len(p.Instances))
+	for id := range p.Instances {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func shardsByID(shards []*placementpb.Shard) map[uint32]*placementpb.Shard {
+	result := make(map[uint32]*placementpb.Shard, len(shards))
+	for _, shard := range shards {
+		result[shard.Id] = shard
+	}
+	return result
+}
+
+// unionShardIDs returns the sorted union of the shard IDs.
+func unionShardIDs(a, b map[uint32]*placementpb.Shard) []uint32 {
+	var result []uint32
+	for id := range a {
+		result = append(result, id)
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func nsKeys(m map[string]*nsproto.NamespaceOptions) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// unionKeys returns the sorted union of the keys.
+func unionKeys(a, b []string) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	var result []string
+	for _, keys := range [][]string{a, b} {
+		for _, k := range keys {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cpuset
+
+import "errors"
+
+var (
+	errNothingToPin   = errors.New("cpu pinning requires cpus or per NUMA node to be set")
+	errNoCPUsSelected = errors.New("cpu pinning selects no cpus of any NUMA node")
+)
+
+// Configuration is the configuration for pinning a worker pool to CPUs.
+type Configuration struct {
+	// CPUs is the CPU list the workers are pinned to in the kernel format,
+	// e.g. "0-15,32-47", defaults to all CPUs if per NUMA node is set.
+	CPUs string `yaml:"cpus"`
+
+	// PerNUMANode splits the workers evenly across the NUMA nodes, pinning
+	// each worker to the CPUs of its node, so that the memory a worker
+	// touches stays local to its node.
+	PerNUMANode bool `yaml:"perNUMANode"`
+}
+
+// Validate validates the configuration.
+func (c Configuration) Validate() error {
+	if !Supported {
+		return errNotSupported
+	}
+	if c.CPUs == "" && !c.PerNUMANode {
+		return errNothingToPin
+	}
+	if c.CPUs != "" {
+		if _, err := Parse(c.CPUs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CPUSets returns the CPU sets workers are pinned to in turn.
+func (c Configuration) CPUSets() ([]CPUSet, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var cpus CPUSet
+	if c.CPUs != "" {
+		// NB: validated above.
+		cpus, _ = Parse(c.CPUs)
+	}
+	if !c.PerNUMANode {
+		return []CPUSet{cpus}, nil
+	}
+
+	nodes, err := NUMANodes()
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]CPUSet, 0, len(nodes))
+	for _, node := range nodes {
+		set := node.CPUs
+		if cpus != nil {
+			set = set.Intersect(cpus)
+		}
+		if len(set) > 0 {
+			sets = append(sets, set)
+		}
+	}
+	if len(sets) == 0 {
+		return nil, errNoCPUsSelected
+	}
+	return sets, nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package cpuset pins goroutines to sets of CPUs and discovers the NUMA
+// nodes of the host, so that worker pools can keep their work local to a
+// socket on large hosts.
+package cpuset
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNotSupported = errors.New("cpu pinning is not supported on this platform")
+	errEmptyCPUList = errors.New("empty cpu list")
+)
+
+// CPUSet is a sorted set of CPU IDs.
+type CPUSet []int
+
+// Node is a NUMA node and the CPUs local to it.
+type Node struct {
+	ID   int
+	CPUs CPUSet
+}
+
+// Parse parses a CPU list in the kernel format, e.g. "0-3,8,10-11".
+func Parse(s string) (CPUSet, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errEmptyCPUList
+	}
+
+	seen := make(map[int]struct{})
+	for _, part := range strings.Split(s, ",") {
+		var (
+			bounds   = strings.SplitN(strings.TrimSpace(part), "-", 2)
+			from, to int
+			err      error
+		)
+		if from, err = strconv.Atoi(bounds[0]); err != nil {
+			return nil, fmt.Errorf("invalid cpu list %q: %v", s, err)
+		}
+		to = from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid cpu list %q: %v", s, err)
+			}
+		}
+		if from < 0 || to < from {
+			return nil, fmt.Errorf("invalid cpu list %q: bad range %q", s, part)
+		}
+		for cpu := from; cpu <= to; cpu++ {
+			seen[cpu] = struct{}{}
+		}
+	}
+
+	set := make(CPUSet, 0, len(seen))
+	for cpu := range seen {
+		set = append(set, cpu)
+	}
+	sort.Ints(set)
+	return set, nil
+}
+
+// Intersect returns the CPUs in both sets.
+func (s CPUSet) Intersect(other CPUSet) CPUSet {
+	var result CPUSet
+	for _, cpu := range s {
+		if other.Contains(cpu) {
+			result = append(result, cpu)
+		}
+	}
+	return result
+}
+
+// Contains returns whether the set contains the CPU.
+func (s CPUSet) Contains(cpu int) bool {
+	i := sort.SearchInts(s, cpu)
+	return i < len(s) && s[i] == cpu
+}
+
+// String returns the set in the kernel CPU list format.
+func (s CPUSet) String() string {
+	var parts []string
+	for i := 0; i < len(s); {
+		j := i
+		for j+1 < len(s) && s[j+1] == s[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(s[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", s[i], s[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Union returns the CPUs in any of the sets.
+func Union(sets ...CPUSet) CPUSet {
+	var all []string
+	for _, set := range sets {
+		if len(set) > 0 {
+			all = append(all, set.String())
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	// NB: the strings were formatted from valid sets so cannot fail to parse.
+	result, _ := Parse(strings.Join(all, ","))
+	return result
+}
+
+// LockAndPin locks the calling goroutine to its OS thread and restricts the
+// thread to the CPUs in the set. The goroutine should not unlock the thread,
+// so that when the goroutine exits the pinned thread exits with it rather
+// than being reused by the scheduler.
+func LockAndPin(set CPUSet) error {
+	runtime.LockOSThread()
+	if err := setAffinity(set); err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	return nil
+}
+
+func allCPUsNode() []Node {
+	cpus := make(CPUSet, 0, runtime.NumCPU())
+	for i := 0; i < runtime.NumCPU(); i++ {
+		cpus = append(cpus, i)
+	}
+	return []Node{{ID: 0, CPUs: cpus}}
+}
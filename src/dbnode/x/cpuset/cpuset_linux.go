@@ -0,0 +1,103 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cpuset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// Supported is whether CPU pinning is supported on the platform.
+	Supported = true
+
+	// cpuSetSize is the number of CPUs an affinity mask can represent,
+	// matches CPU_SETSIZE.
+	cpuSetSize = 1024
+
+	sysfsNodesDir = "/sys/devices/system/node"
+)
+
+func setAffinity(set CPUSet) error {
+	if len(set) == 0 {
+		return errEmptyCPUList
+	}
+	var mask [cpuSetSize / 64]uint64
+	for _, cpu := range set {
+		if cpu < 0 || cpu >= cpuSetSize {
+			return fmt.Errorf("cpu %d exceeds max cpu %d", cpu, cpuSetSize-1)
+		}
+		mask[cpu/64] |= 1 << uint(cpu%64)
+	}
+	// NB: a pid of zero sets the affinity of the calling thread.
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0,
+		uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// NUMANodes returns the NUMA nodes of the host, a single node with all
+// CPUs is returned if the host does not expose NUMA topology.
+func NUMANodes() ([]Node, error) {
+	return numaNodes(sysfsNodesDir)
+}
+
+func numaNodes(dir string) ([]Node, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return allCPUsNode(), nil
+	}
+
+	nodes := make([]Node, 0, len(paths))
+	for _, path := range paths {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "node"))
+		if err != nil {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(path, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			// Memory only nodes have no CPUs.
+			continue
+		}
+		cpus, err := Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %v", id, err)
+		}
+		nodes = append(nodes, Node{ID: id, CPUs: cpus})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cpuset
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNUMANodesFromSysfs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpuset")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for node, cpus := range map[string]string{
+		"node0": "0-3,8-11\n",
+		"node1": "4-7,12-15\n",
+		"node2": "\n",
+	} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, node), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, node, "cpulist"), []byte(cpus), 0644))
+	}
+
+	nodes, err := numaNodes(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Node{
+		{ID: 0, CPUs: CPUSet{0, 1, 2, 3, 8, 9, 10, 11}},
+		{ID: 1, CPUs: CPUSet{4, 5, 6, 7, 12, 13, 14, 15}},
+	}, nodes)
+
+	nodes, err = numaNodes(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+}
+
+func TestLockAndPin(t *testing.T) {
+	doneCh := make(chan error)
+	go func() {
+		doneCh <- LockAndPin(CPUSet{0})
+	}()
+	require.NoError(t, <-doneCh)
+}
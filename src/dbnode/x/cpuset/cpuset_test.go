@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cpuset
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	set, err := Parse("8,0-3, 2-4,10-11\n")
+	require.NoError(t, err)
+	assert.Equal(t, CPUSet{0, 1, 2, 3, 4, 8, 10, 11}, set)
+	assert.Equal(t, "0-4,8,10-11", set.String())
+
+	for _, invalid := range []string{"", "a", "3-1", "-1", "1-b"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCPUSetIntersectAndUnion(t *testing.T) {
+	a := CPUSet{0, 1, 2, 3}
+	b := CPUSet{2, 3, 4, 5}
+	assert.Equal(t, CPUSet{2, 3}, a.Intersect(b))
+	assert.Nil(t, a.Intersect(CPUSet{8}))
+	assert.Equal(t, CPUSet{0, 1, 2, 3, 4, 5}, Union(a, b))
+	assert.Nil(t, Union())
+}
+
+func TestConfigurationValidate(t *testing.T) {
+	if !Supported {
+		assert.Equal(t, errNotSupported, Configuration{CPUs: "0"}.Validate())
+		return
+	}
+	assert.Equal(t, errNothingToPin, Configuration{}.Validate())
+	assert.Error(t, Configuration{CPUs: "x"}.Validate())
+	assert.NoError(t, Configuration{CPUs: "0-1"}.Validate())
+
+	sets, err := Configuration{CPUs: "0-1"}.CPUSets()
+	require.NoError(t, err)
+	assert.Equal(t, []CPUSet{{0, 1}}, sets)
+}
+
+func TestPinnedWorkerPool(t *testing.T) {
+	pool := NewWorkerPool(2, nil, instrument.NewOptions())
+	pool.Init()
+
+	var (
+		wg      sync.WaitGroup
+		blockCh = make(chan struct{})
+	)
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		pool.Go(func() {
+			wg.Done()
+			<-blockCh
+		})
+	}
+	wg.Wait()
+
+	// Both workers are busy.
+	assert.False(t, pool.GoIfAvailable(func() {}))
+	assert.False(t, pool.GoWithTimeout(func() {}, time.Millisecond))
+
+	close(blockCh)
+	doneCh := make(chan struct{})
+	assert.True(t, pool.GoWithTimeout(func() { close(doneCh) }, time.Minute))
+	<-doneCh
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cpuset
+
+import (
+	"time"
+
+	"github.com/m3db/m3x/instrument"
+	xsync "github.com/m3db/m3x/sync"
+)
+
+type pinnedWorkerPool struct {
+	size   int
+	sets   []CPUSet
+	workCh chan xsync.Work
+	iopts  instrument.Options
+}
+
+// NewWorkerPool returns a worker pool whose workers are each pinned to one
+// of the CPU sets in turn, e.g. with a CPU set per NUMA node the workers are
+// split evenly across the nodes. Unlike goroutine based pools the workers are
+// long lived so that they stay on their pinned threads.
+func NewWorkerPool(size int, sets []CPUSet, iopts instrument.Options) xsync.WorkerPool {
+	return &pinnedWorkerPool{
+		size:   size,
+		sets:   sets,
+		workCh: make(chan xsync.Work),
+		iopts:  iopts,
+	}
+}
+
+func (p *pinnedWorkerPool) Init() {
+	for i := 0; i < p.size; i++ {
+		go p.work(i)
+	}
+}
+
+func (p *pinnedWorkerPool) work(i int) {
+	if len(p.sets) > 0 {
+		set := p.sets[i%len(p.sets)]
+		if err := LockAndPin(set); err != nil {
+			p.iopts.Logger().Warnf("could not pin worker to cpus %s: %v", set, err)
+		}
+	}
+	for work := range p.workCh {
+		work()
+	}
+}
+
+func (p *pinnedWorkerPool) Go(work xsync.Work) {
+	p.workCh <- work
+}
+
+func (p *pinnedWorkerPool) GoIfAvailable(work xsync.Work) bool {
+	select {
+	case p.workCh <- work:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *pinnedWorkerPool) GoWithTimeout(work xsync.Work, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case p.workCh <- work:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
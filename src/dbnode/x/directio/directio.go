@@ -0,0 +1,139 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package directio provides writing files with direct I/O, bypassing the
+// page cache, using block aligned buffers.
+package directio
+
+import (
+	"errors"
+	"os"
+	"unsafe"
+)
+
+const (
+	// BlockSize is the alignment of buffers, file offsets and lengths of
+	// writes required by direct I/O.
+	BlockSize = 4096
+)
+
+var (
+	// ErrNotSupported is returned when direct I/O is not supported on
+	// the platform.
+	ErrNotSupported = errors.New("direct I/O is not supported on this platform")
+
+	errWriterFlushed = errors.New("direct I/O writer already flushed")
+)
+
+// AlignedBlock returns a buffer of the given size, rounded up to a multiple
+// of the block size, whose address is aligned to the block size.
+func AlignedBlock(size int) []byte {
+	size = alignUp(size)
+	block := make([]byte, size+BlockSize)
+	offset := 0
+	if rem := int(uintptr(unsafe.Pointer(&block[0])) & (BlockSize - 1)); rem != 0 {
+		offset = BlockSize - rem
+	}
+	return block[offset : offset+size : offset+size]
+}
+
+func alignUp(size int) int {
+	if size <= 0 {
+		return BlockSize
+	}
+	return (size + BlockSize - 1) &^ (BlockSize - 1)
+}
+
+// Writer is a buffered writer for files opened with OpenFile, it only issues
+// writes of whole blocks from a block aligned buffer.
+type Writer struct {
+	fd      *os.File
+	buf     []byte
+	n       int
+	written int64
+	flushed bool
+}
+
+// NewWriter returns a new writer with a buffer of the given size, rounded up
+// to a multiple of the block size.
+func NewWriter(bufferSize int) *Writer {
+	return &Writer{buf: AlignedBlock(bufferSize)}
+}
+
+// Reset resets the writer to write to the given file.
+func (w *Writer) Reset(fd *os.File) {
+	w.fd = fd
+	w.n = 0
+	w.written = 0
+	w.flushed = false
+}
+
+// Write buffers the bytes and writes out the buffer whenever it is full.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.flushed {
+		return 0, errWriterFlushed
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[w.n:], p)
+		w.n += n
+		written += n
+		p = p[n:]
+		if w.n == len(w.buf) {
+			if err := w.writeBuffer(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Flush writes out the remaining buffered bytes, padding the last block with
+// zeroes and then truncating the file to the bytes written. Since the file
+// can no longer be written to at aligned offsets it must only be called once
+// all the data has been written.
+func (w *Writer) Flush() error {
+	if w.flushed || w.fd == nil {
+		return nil
+	}
+	w.flushed = true
+	if w.n == 0 {
+		return nil
+	}
+	size := w.written + int64(w.n)
+	padded := alignUp(w.n)
+	for i := w.n; i < padded; i++ {
+		w.buf[i] = 0
+	}
+	w.n = padded
+	if err := w.writeBuffer(); err != nil {
+		return err
+	}
+	return w.fd.Truncate(size)
+}
+
+func (w *Writer) writeBuffer() error {
+	n, err := w.fd.Write(w.buf[:w.n])
+	w.written += int64(n)
+	if err != nil {
+		return err
+	}
+	w.n = 0
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package directio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignedBlock(t *testing.T) {
+	for _, size := range []int{0, 1, BlockSize, BlockSize + 1} {
+		block := AlignedBlock(size)
+		assert.Equal(t, 0, len(block)%BlockSize)
+		assert.True(t, len(block) >= size)
+		assert.Equal(t, uintptr(0), uintptr(unsafe.Pointer(&block[0]))%BlockSize)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "directio")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w := NewWriter(2 * BlockSize)
+	for _, size := range []int{0, 1, BlockSize, 3*BlockSize + 7} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+
+		filePath := filepath.Join(dir, "data")
+		fd, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+		require.NoError(t, err)
+
+		w.Reset(fd)
+		// Write in uneven chunks to exercise partially filling the buffer.
+		for remaining := data; len(remaining) > 0; {
+			n := 1000
+			if n > len(remaining) {
+				n = len(remaining)
+			}
+			written, err := w.Write(remaining[:n])
+			require.NoError(t, err)
+			require.Equal(t, n, written)
+			remaining = remaining[n:]
+		}
+		require.NoError(t, w.Flush())
+		require.NoError(t, fd.Close())
+
+		_, err = w.Write([]byte{1})
+		require.Error(t, err)
+
+		contents, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(data, contents), "size %d", size)
+	}
+}
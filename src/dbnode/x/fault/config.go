@@ -0,0 +1,49 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fault
+
+// Configuration is the configuration for fault injection.
+type Configuration struct {
+	// Seed is the seed used to decide whether probabilistic faults are injected.
+	Seed int64 `yaml:"seed"`
+
+	// Faults are the faults to inject at startup keyed by point.
+	Faults map[Point]Fault `yaml:"faults"`
+}
+
+// Validate validates the configuration.
+func (c Configuration) Validate() error {
+	for point := range c.Faults {
+		if err := validatePoint(point); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewInjector returns a new fault injector with the configured faults set.
+func (c Configuration) NewInjector() Injector {
+	injector := NewInjector(c.Seed)
+	for point, fault := range c.Faults {
+		injector.Set(point, fault)
+	}
+	return injector
+}
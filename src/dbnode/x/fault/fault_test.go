@@ -0,0 +1,91 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fault
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectorInject(t *testing.T) {
+	inj := NewInjector(0).(*injector)
+	var slept time.Duration
+	inj.sleepFn = func(d time.Duration) { slept += d }
+
+	require.Equal(t, Result{}, inj.Inject(CommitLogWrite))
+
+	inj.Set(CommitLogWrite, Fault{Error: "boom", Latency: time.Second, Partial: true})
+	res := inj.Inject(CommitLogWrite)
+	require.Error(t, res.Err)
+	assert.Equal(t, "boom", res.Err.Error())
+	assert.True(t, res.Partial)
+	assert.Equal(t, time.Second, slept)
+
+	inj.Set(RPCSend, Fault{Partial: true})
+	assert.Equal(t, Result{}, inj.Inject(RPCSend))
+
+	inj.Clear(CommitLogWrite)
+	assert.Equal(t, Result{}, inj.Inject(CommitLogWrite))
+	assert.Len(t, inj.Faults(), 1)
+
+	inj.ClearAll()
+	assert.Len(t, inj.Faults(), 0)
+}
+
+func TestInjectorInjectProbability(t *testing.T) {
+	inj := NewInjector(0)
+	inj.Set(FilesetFlush, Fault{Error: "boom", Probability: 0.5})
+
+	var injected int
+	for i := 0; i < 1000; i++ {
+		if inj.Inject(FilesetFlush).Err != nil {
+			injected++
+		}
+	}
+	assert.True(t, injected > 400 && injected < 600)
+}
+
+func TestHandler(t *testing.T) {
+	inj := NewInjector(0)
+	h := NewHandler(inj)
+
+	body := []byte(`{"point":"rpc-send","fault":{"error":"boom"}}`)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, HandlerPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[Point]Fault{RPCSend: {Error: "boom"}}, inj.Faults())
+
+	body = []byte(`{"point":"unknown","fault":{"error":"boom"}}`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, HandlerPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, HandlerPath+"?point=rpc-send", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, inj.Faults(), 0)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// HandlerPath is the path the admin handler is conventionally registered at.
+	HandlerPath = "/debug/faults"
+)
+
+type setFaultRequest struct {
+	Point Point `json:"point"`
+	Fault Fault `json:"fault"`
+}
+
+type handler struct {
+	injector Injector
+}
+
+// NewHandler returns a HTTP handler to inspect and set faults, it returns
+// the current faults on GET, sets a fault on POST and clears the fault at
+// the point given by the "point" query parameter, or all faults, on DELETE.
+func NewHandler(injector Injector) http.Handler {
+	return &handler{injector: injector}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req setFaultRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validatePoint(req.Point); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Fault.Probability < 0 || req.Fault.Probability > 1 {
+			http.Error(w, "probability must be between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		h.injector.Set(req.Point, req.Fault)
+	case http.MethodDelete:
+		if point := r.URL.Query().Get("point"); point != "" {
+			h.injector.Clear(Point(point))
+		} else {
+			h.injector.ClearAll()
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.injector.Faults())
+}
+
+func validatePoint(point Point) error {
+	for _, p := range Points() {
+		if p == point {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown fault point: %s", point)
+}
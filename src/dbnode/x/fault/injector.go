@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fault
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type injector struct {
+	sync.RWMutex
+
+	faults  map[Point]Fault
+	errs    map[Point]error
+	randMu  sync.Mutex
+	rand    *rand.Rand
+	sleepFn func(time.Duration)
+}
+
+// NewInjector returns a new fault injector with no faults set, the seed
+// is used to decide whether faults with a probability are injected.
+func NewInjector(seed int64) Injector {
+	return &injector{
+		faults:  make(map[Point]Fault),
+		errs:    make(map[Point]error),
+		rand:    rand.New(rand.NewSource(seed)),
+		sleepFn: time.Sleep,
+	}
+}
+
+func (i *injector) Inject(point Point) Result {
+	i.RLock()
+	fault, ok := i.faults[point]
+	err := i.errs[point]
+	i.RUnlock()
+	if !ok {
+		return Result{}
+	}
+
+	if fault.Probability > 0 && fault.Probability < 1 {
+		i.randMu.Lock()
+		skip := i.rand.Float64() >= fault.Probability
+		i.randMu.Unlock()
+		if skip {
+			return Result{}
+		}
+	}
+
+	if fault.Latency > 0 {
+		i.sleepFn(fault.Latency)
+	}
+	return Result{
+		Err:     err,
+		Partial: err != nil && fault.Partial,
+	}
+}
+
+func (i *injector) Set(point Point, fault Fault) {
+	i.Lock()
+	i.faults[point] = fault
+	if fault.Error != "" {
+		i.errs[point] = errors.New(fault.Error)
+	} else {
+		delete(i.errs, point)
+	}
+	i.Unlock()
+}
+
+func (i *injector) Clear(point Point) {
+	i.Lock()
+	delete(i.faults, point)
+	delete(i.errs, point)
+	i.Unlock()
+}
+
+func (i *injector) ClearAll() {
+	i.Lock()
+	i.faults = make(map[Point]Fault)
+	i.errs = make(map[Point]error)
+	i.Unlock()
+}
+
+func (i *injector) Faults() map[Point]Fault {
+	i.RLock()
+	faults := make(map[Point]Fault, len(i.faults))
+	for point, fault := range i.faults {
+		faults[point] = fault
+	}
+	i.RUnlock()
+	return faults
+}
+
+type noopInjector struct{}
+
+// NewNoopInjector returns a fault injector that never injects faults.
+func NewNoopInjector() Injector {
+	return noopInjector{}
+}
+
+func (noopInjector) Inject(point Point) Result    { return Result{} }
+func (noopInjector) Set(point Point, fault Fault) {}
+func (noopInjector) Clear(point Point)            {}
+func (noopInjector) ClearAll()                    {}
+func (noopInjector) Faults() map[Point]Fault      { return nil }
@@ -0,0 +1,91 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fault provides hooks to inject errors, latency and partial writes
+// at key points in the storage and client layers to test failure handling.
+package fault
+
+import (
+	"time"
+)
+
+// Point is a named location at which faults can be injected.
+type Point string
+
+const (
+	// CommitLogWrite is the point at which commit log entries are written.
+	CommitLogWrite Point = "commitlog-write"
+
+	// FilesetFlush is the point at which a fileset is closed and checkpointed.
+	FilesetFlush Point = "fileset-flush"
+
+	// RPCSend is the point at which the client sends a request to a host.
+	RPCSend Point = "rpc-send"
+)
+
+// Points returns all the known fault injection points.
+func Points() []Point {
+	return []Point{CommitLogWrite, FilesetFlush, RPCSend}
+}
+
+// Fault describes a fault to inject at a point.
+type Fault struct {
+	// Error is the message of the error to return, no error is returned if empty.
+	Error string `json:"error" yaml:"error"`
+
+	// Latency is the latency to inject before returning.
+	Latency time.Duration `json:"latency" yaml:"latency"`
+
+	// Probability is the probability the fault is injected, defaults to always.
+	Probability float64 `json:"probability" yaml:"probability" validate:"min=0.0,max=1.0"`
+
+	// Partial is whether the operation is partially applied before the
+	// error is returned, it has no effect if no error is set.
+	Partial bool `json:"partial" yaml:"partial"`
+}
+
+// Result is the outcome of injecting faults at a point.
+type Result struct {
+	// Err is the injected error, nil if no error was injected.
+	Err error
+
+	// Partial is whether the operation should be partially applied before
+	// returning the injected error.
+	Partial bool
+}
+
+// Injector injects faults at points, it is safe for concurrent use.
+type Injector interface {
+	// Inject applies the fault set at the point, if any, sleeping for any
+	// injected latency before returning the result.
+	Inject(point Point) Result
+
+	// Set sets the fault to inject at a point.
+	Set(point Point, fault Fault)
+
+	// Clear clears the fault set at a point.
+	Clear(point Point)
+
+	// ClearAll clears all faults.
+	ClearAll()
+
+	// Faults returns the faults currently set.
+	Faults() map[Point]Fault
+}
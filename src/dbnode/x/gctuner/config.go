@@ -0,0 +1,110 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package gctuner
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultMinGCPercent = 25
+	defaultMaxGCPercent = 400
+	defaultInterval     = 5 * time.Second
+)
+
+var (
+	errMemoryBudgetRequired = errors.New("gc tuning memory budget must be set")
+	errInvalidGCPercentages = errors.New("gc tuning min gc percent must not exceed max gc percent")
+	errBallastExceedsBudget = errors.New("gc tuning ballast must be less than half the memory budget")
+)
+
+// Configuration is the configuration for tuning the garbage collector.
+type Configuration struct {
+	// MemoryBudget is the heap size in bytes the garbage collection target
+	// percentage is tuned to stay within.
+	MemoryBudget int64 `yaml:"memoryBudget" validate:"min=0"`
+
+	// MinGCPercent is the lowest garbage collection target percentage set,
+	// bounding the CPU spent collecting when the live heap nears the budget.
+	// Defaults to 25.
+	MinGCPercent int `yaml:"minGCPercent" validate:"min=0"`
+
+	// MaxGCPercent is the highest garbage collection target percentage set,
+	// bounding the heap growth between collections. Defaults to 400.
+	MaxGCPercent int `yaml:"maxGCPercent" validate:"min=0"`
+
+	// BallastBytes is the size of a heap ballast allocated while the live
+	// heap is small, so that small heaps are not collected too frequently.
+	// It is released once the live heap reaches half the budget.
+	BallastBytes int64 `yaml:"ballastBytes" validate:"min=0"`
+
+	// Interval is how often the garbage collector is tuned, defaults to 5s.
+	Interval time.Duration `yaml:"interval" validate:"min=0"`
+}
+
+// Validate validates the configuration.
+func (c Configuration) Validate() error {
+	if c.MemoryBudget <= 0 {
+		return errMemoryBudgetRequired
+	}
+	if c.minGCPercent() > c.maxGCPercent() {
+		return errInvalidGCPercentages
+	}
+	if c.BallastBytes >= c.MemoryBudget/2 {
+		return errBallastExceedsBudget
+	}
+	return nil
+}
+
+// NewTuner returns a new tuner for the configuration.
+func (c Configuration) NewTuner(iopts instrument.Options) (Tuner, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return newTuner(tunerOptions{
+		memoryBudget: c.MemoryBudget,
+		minGCPercent: c.minGCPercent(),
+		maxGCPercent: c.maxGCPercent(),
+		ballastBytes: c.BallastBytes,
+		interval:     interval,
+		iopts:        iopts,
+	}), nil
+}
+
+func (c Configuration) minGCPercent() int {
+	if c.MinGCPercent <= 0 {
+		return defaultMinGCPercent
+	}
+	return c.MinGCPercent
+}
+
+func (c Configuration) maxGCPercent() int {
+	if c.MaxGCPercent <= 0 {
+		return defaultMaxGCPercent
+	}
+	return c.MaxGCPercent
+}
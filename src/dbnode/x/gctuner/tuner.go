@@ -0,0 +1,248 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package gctuner
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+type tunerOptions struct {
+	memoryBudget int64
+	minGCPercent int
+	maxGCPercent int
+	ballastBytes int64
+	interval     time.Duration
+	iopts        instrument.Options
+}
+
+type readMemStatsFn func(ms *runtime.MemStats)
+
+type setGCPercentFn func(percent int) int
+
+type tuner struct {
+	sync.RWMutex
+
+	opts           tunerOptions
+	readMemStatsFn readMemStatsFn
+	setGCPercentFn setGCPercentFn
+	metrics        tunerMetrics
+
+	// NB: the ballast is never read or written so its pages are never
+	// touched and do not add to the resident memory of the process.
+	ballast          []byte
+	state            State
+	lastNumGC        uint32
+	lastPauseTotalNs uint64
+
+	started   bool
+	startOnce sync.Once
+	stopOnce  sync.Once
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+}
+
+type tunerMetrics struct {
+	gcPercent     tally.Gauge
+	liveHeap      tally.Gauge
+	ballast       tally.Gauge
+	gcCPUFraction tally.Gauge
+	collections   tally.Counter
+	pauseTotal    tally.Counter
+	pause         tally.Timer
+}
+
+func newTunerMetrics(scope tally.Scope) tunerMetrics {
+	return tunerMetrics{
+		gcPercent:     scope.Gauge("gc-percent"),
+		liveHeap:      scope.Gauge("live-heap-bytes"),
+		ballast:       scope.Gauge("ballast-bytes"),
+		gcCPUFraction: scope.Gauge("gc-cpu-fraction"),
+		collections:   scope.Counter("collections"),
+		pauseTotal:    scope.Counter("pause-total-ns"),
+		pause:         scope.Timer("pause"),
+	}
+}
+
+func newTuner(opts tunerOptions) *tuner {
+	return &tuner{
+		opts:           opts,
+		readMemStatsFn: runtime.ReadMemStats,
+		setGCPercentFn: debug.SetGCPercent,
+		metrics:        newTunerMetrics(opts.iopts.MetricsScope()),
+		closeCh:        make(chan struct{}),
+		doneCh:         make(chan struct{}),
+	}
+}
+
+func (t *tuner) Start() {
+	t.startOnce.Do(func() {
+		t.init()
+		go t.run()
+	})
+}
+
+// init reads the current garbage collection target percentage and the
+// collections so far, so that only pauses after starting are recorded.
+func (t *tuner) init() {
+	current := t.setGCPercentFn(100)
+	t.setGCPercentFn(current)
+
+	var ms runtime.MemStats
+	t.readMemStatsFn(&ms)
+
+	t.Lock()
+	t.started = true
+	t.state.GCPercent = current
+	t.lastNumGC = ms.NumGC
+	t.lastPauseTotalNs = ms.PauseTotalNs
+	t.Unlock()
+}
+
+func (t *tuner) run() {
+	defer close(t.doneCh)
+
+	ticker := time.NewTicker(t.opts.interval)
+	defer ticker.Stop()
+
+	t.tune()
+	for {
+		select {
+		case <-t.closeCh:
+			return
+		case <-ticker.C:
+			t.tune()
+		}
+	}
+}
+
+func (t *tuner) tune() {
+	var ms runtime.MemStats
+	t.readMemStatsFn(&ms)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.recordPausesWithLock(&ms)
+
+	var (
+		budget  = t.opts.memoryBudget
+		ballast = int64(len(t.ballast))
+		heap    = int64(ms.HeapAlloc)
+	)
+	if percent := t.state.GCPercent; percent > 0 && ms.NextGC > 0 {
+		// The heap live after the last collection is the next collection
+		// target divided by the growth the target percentage allows.
+		heap = int64(ms.NextGC) * 100 / int64(100+percent)
+	}
+	live := heap - ballast
+	if live < 0 {
+		live = 0
+	}
+
+	// Release the ballast once the live heap is large enough to not be
+	// collected too frequently, and only allocate it again once the live
+	// heap has shrunk well below that so it is not churned.
+	switch {
+	case ballast > 0 && live+ballast >= budget/2:
+		t.ballast = nil
+	case ballast == 0 && t.opts.ballastBytes > 0 && live+t.opts.ballastBytes < budget/4:
+		t.ballast = make([]byte, t.opts.ballastBytes)
+	}
+	ballast = int64(len(t.ballast))
+	heap = live + ballast
+
+	// Set the target percentage so the heap at the next collection is
+	// within the budget.
+	gcPercent := t.opts.maxGCPercent
+	if heap > 0 && heap < budget {
+		gcPercent = int((budget - heap) * 100 / heap)
+	} else if heap >= budget {
+		gcPercent = t.opts.minGCPercent
+	}
+	if gcPercent < t.opts.minGCPercent {
+		gcPercent = t.opts.minGCPercent
+	}
+	if gcPercent > t.opts.maxGCPercent {
+		gcPercent = t.opts.maxGCPercent
+	}
+	if gcPercent != t.state.GCPercent {
+		t.setGCPercentFn(gcPercent)
+	}
+
+	t.state = State{
+		LiveHeapBytes: live,
+		BallastBytes:  ballast,
+		GCPercent:     gcPercent,
+	}
+	t.metrics.gcPercent.Update(float64(gcPercent))
+	t.metrics.liveHeap.Update(float64(live))
+	t.metrics.ballast.Update(float64(ballast))
+}
+
+// recordPausesWithLock records the pauses of the collections since the
+// last tuning, i.e. the pause contributed by each collection.
+func (t *tuner) recordPausesWithLock(ms *runtime.MemStats) {
+	numGC := ms.NumGC - t.lastNumGC
+	pauses := numGC
+	if n := uint32(len(ms.PauseNs)); pauses > n {
+		pauses = n
+	}
+	for i := uint32(0); i < pauses; i++ {
+		// PauseNs is a circular buffer of the most recent pauses.
+		idx := (ms.NumGC - 1 - i) % uint32(len(ms.PauseNs))
+		t.metrics.pause.Record(time.Duration(ms.PauseNs[idx]))
+	}
+	t.metrics.collections.Inc(int64(numGC))
+	t.metrics.pauseTotal.Inc(int64(ms.PauseTotalNs - t.lastPauseTotalNs))
+	t.metrics.gcCPUFraction.Update(ms.GCCPUFraction)
+	t.lastNumGC = ms.NumGC
+	t.lastPauseTotalNs = ms.PauseTotalNs
+}
+
+func (t *tuner) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.closeCh)
+	})
+
+	t.RLock()
+	started := t.started
+	t.RUnlock()
+	if started {
+		<-t.doneCh
+	}
+
+	t.Lock()
+	t.ballast = nil
+	t.state.BallastBytes = 0
+	t.Unlock()
+}
+
+func (t *tuner) State() State {
+	t.RLock()
+	defer t.RUnlock()
+	return t.state
+}
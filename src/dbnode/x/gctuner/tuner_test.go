@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package gctuner
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+const kb = 1024
+
+func newTestTuner(t *testing.T) (*tuner, tally.TestScope, *runtime.MemStats, *[]int) {
+	scope := tally.NewTestScope("", nil)
+	cfg := Configuration{MemoryBudget: 1000 * kb, BallastBytes: 100 * kb}
+	tnr, err := cfg.NewTuner(instrument.NewOptions().SetMetricsScope(scope))
+	require.NoError(t, err)
+
+	var (
+		tr      = tnr.(*tuner)
+		ms      runtime.MemStats
+		percent = 100
+		set     []int
+	)
+	tr.readMemStatsFn = func(out *runtime.MemStats) { *out = ms }
+	tr.setGCPercentFn = func(p int) int {
+		prev := percent
+		percent = p
+		set = append(set, p)
+		return prev
+	}
+	return tr, scope, &ms, &set
+}
+
+func TestTunerTune(t *testing.T) {
+	tr, scope, ms, set := newTestTuner(t)
+	tr.init()
+	*set = nil
+
+	// Small live heap allocates the ballast and uses the max percent.
+	ms.NextGC = 20 * kb
+	ms.NumGC = 2
+	ms.PauseNs[0], ms.PauseNs[1] = 10, 20
+	ms.PauseTotalNs = 30
+	tr.tune()
+	assert.Equal(t, State{LiveHeapBytes: 10 * kb, BallastBytes: 100 * kb, GCPercent: 400}, tr.State())
+
+	// Live heap past half the budget releases the ballast.
+	ms.NextGC = 5 * 600 * kb
+	tr.tune()
+	assert.Equal(t, State{LiveHeapBytes: 500 * kb, GCPercent: 100}, tr.State())
+
+	// Live heap near the budget uses the min percent.
+	ms.NextGC = 2 * 900 * kb
+	tr.tune()
+	assert.Equal(t, State{LiveHeapBytes: 900 * kb, GCPercent: 25}, tr.State())
+	assert.Equal(t, []int{400, 100, 25}, *set)
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(2), counters["collections+"].Value())
+	assert.Equal(t, int64(30), counters["pause-total-ns+"].Value())
+}
+
+func TestConfigurationValidate(t *testing.T) {
+	assert.Equal(t, errMemoryBudgetRequired, Configuration{}.Validate())
+	assert.Equal(t, errInvalidGCPercentages,
+		Configuration{MemoryBudget: kb, MinGCPercent: 200, MaxGCPercent: 100}.Validate())
+	assert.Equal(t, errBallastExceedsBudget,
+		Configuration{MemoryBudget: kb, BallastBytes: kb / 2}.Validate())
+	assert.NoError(t, Configuration{MemoryBudget: kb}.Validate())
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package gctuner adjusts the garbage collection target percentage and an
+// optional heap ballast at runtime to keep the heap within a memory budget.
+package gctuner
+
+// Tuner periodically tunes the garbage collector from the live heap.
+type Tuner interface {
+	// Start starts tuning in the background.
+	Start()
+
+	// Stop stops tuning and releases the ballast, the last garbage
+	// collection target percentage set is kept.
+	Stop()
+
+	// State returns the state as of the last tuning.
+	State() State
+}
+
+// State is the state of the garbage collector as of a tuning.
+type State struct {
+	// LiveHeapBytes is the estimated heap live after the last garbage
+	// collection, excluding the ballast.
+	LiveHeapBytes int64 `json:"liveHeapBytes"`
+	// BallastBytes is the size of the ballast currently allocated.
+	BallastBytes int64 `json:"ballastBytes"`
+	// GCPercent is the garbage collection target percentage set.
+	GCPercent int `json:"gcPercent"`
+}
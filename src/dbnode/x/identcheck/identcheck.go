@@ -0,0 +1,285 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package identcheck provides a debug mode identifier pool that tracks the
+// allocation sites of pooled IDs and reports IDs that are leaked, that is
+// garbage collected without ever being finalized, or finalized twice.
+package identcheck
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/m3db/m3x/checked"
+	"github.com/m3db/m3x/context"
+	"github.com/m3db/m3x/ident"
+	"github.com/m3db/m3x/instrument"
+)
+
+const maxStackDepth = 32
+
+// ReportType is the type of a reported misuse of a pooled ID.
+type ReportType int
+
+const (
+	// LeakReportType is reported when an ID is garbage collected without
+	// having been finalized.
+	LeakReportType ReportType = iota
+
+	// DoubleFinalizeReportType is reported when an ID is finalized more
+	// than once.
+	DoubleFinalizeReportType
+)
+
+func (t ReportType) String() string {
+	switch t {
+	case LeakReportType:
+		return "leak"
+	case DoubleFinalizeReportType:
+		return "double-finalize"
+	}
+	return "unknown"
+}
+
+// Report describes a misuse of a pooled ID.
+type Report struct {
+	Type ReportType
+	ID   string
+
+	// AllocStack is the stack the ID was allocated from.
+	AllocStack string
+
+	// FinalizeStack is the stack of the first finalize, only set for
+	// double finalize reports.
+	FinalizeStack string
+
+	// Stack is the stack of the second finalize, only set for double
+	// finalize reports.
+	Stack string
+}
+
+// ReportFn is called with each misuse of a pooled ID.
+type ReportFn func(r Report)
+
+// NewLoggingReportFn returns a report function that logs each report
+// with its stack traces and counts reports by type.
+func NewLoggingReportFn(iopts instrument.Options) ReportFn {
+	var (
+		logger          = iopts.Logger()
+		scope           = iopts.MetricsScope()
+		leaks           = scope.Counter("leaks")
+		doubleFinalizes = scope.Counter("double-finalizes")
+	)
+	return func(r Report) {
+		switch r.Type {
+		case LeakReportType:
+			leaks.Inc(1)
+			logger.Errorf("pooled id %q leaked, allocated at:\n%s",
+				r.ID, r.AllocStack)
+		case DoubleFinalizeReportType:
+			doubleFinalizes.Inc(1)
+			logger.Errorf("pooled id %q finalized twice, allocated at:\n%s\n"+
+				"first finalized at:\n%s\nfinalized again at:\n%s",
+				r.ID, r.AllocStack, r.FinalizeStack, r.Stack)
+		}
+	}
+}
+
+type pool struct {
+	ident.Pool
+
+	reportFn ReportFn
+}
+
+// NewPool returns an identifier pool that wraps the given pool and tracks
+// the IDs it allocates, reporting leaks and double finalizes to the report
+// function. Tags are tracked through the IDs of their names and values, which
+// are allocated from the wrapping pool.
+// Tracking allocation sites is expensive and should only be used for debugging.
+func NewPool(p ident.Pool, reportFn ReportFn) ident.Pool {
+	return &pool{Pool: p, reportFn: reportFn}
+}
+
+func (p *pool) GetBinaryID(ctx context.Context, data checked.Bytes) ident.ID {
+	id := p.BinaryID(data)
+	ctx.RegisterFinalizer(id)
+	return id
+}
+
+func (p *pool) BinaryID(data checked.Bytes) ident.ID {
+	return p.track(p.Pool.BinaryID(data))
+}
+
+func (p *pool) GetStringID(ctx context.Context, v string) ident.ID {
+	id := p.StringID(v)
+	ctx.RegisterFinalizer(id)
+	return id
+}
+
+func (p *pool) StringID(v string) ident.ID {
+	return p.track(p.Pool.StringID(v))
+}
+
+func (p *pool) Clone(id ident.ID) ident.ID {
+	return p.track(p.Pool.Clone(id))
+}
+
+func (p *pool) GetBinaryTag(ctx context.Context, name, value checked.Bytes) ident.Tag {
+	tag := p.BinaryTag(name, value)
+	ctx.RegisterFinalizer(tag.Name)
+	ctx.RegisterFinalizer(tag.Value)
+	return tag
+}
+
+func (p *pool) BinaryTag(name, value checked.Bytes) ident.Tag {
+	return ident.Tag{Name: p.BinaryID(name), Value: p.BinaryID(value)}
+}
+
+func (p *pool) GetStringTag(ctx context.Context, name, value string) ident.Tag {
+	tag := p.StringTag(name, value)
+	ctx.RegisterFinalizer(tag.Name)
+	ctx.RegisterFinalizer(tag.Value)
+	return tag
+}
+
+func (p *pool) StringTag(name, value string) ident.Tag {
+	return ident.Tag{Name: p.StringID(name), Value: p.StringID(value)}
+}
+
+func (p *pool) CloneTag(tag ident.Tag) ident.Tag {
+	return ident.Tag{Name: p.Clone(tag.Name), Value: p.Clone(tag.Value)}
+}
+
+func (p *pool) CloneTags(tags ident.Tags) ident.Tags {
+	clone := p.Tags()
+	for _, tag := range tags.Values() {
+		clone.Append(p.CloneTag(tag))
+	}
+	return clone
+}
+
+func (p *pool) PutTag(tag ident.Tag) {
+	p.Put(tag.Name)
+	p.Put(tag.Value)
+}
+
+func (p *pool) Put(id ident.ID) {
+	if tracked, ok := id.(*trackedID); ok {
+		tracked.Finalize()
+		return
+	}
+	p.Pool.Put(id)
+}
+
+func (p *pool) track(id ident.ID) ident.ID {
+	tracked := &trackedID{
+		ID:         id,
+		reportFn:   p.reportFn,
+		allocStack: callers(),
+	}
+	runtime.SetFinalizer(tracked, finalizeTracked)
+	return tracked
+}
+
+// finalizeTracked is run by the garbage collector once a tracked ID
+// is unreachable.
+func finalizeTracked(id *trackedID) {
+	id.Lock()
+	leaked := !id.finalized && !id.noFinalize
+	id.Unlock()
+	if leaked {
+		id.reportFn(Report{
+			Type:       LeakReportType,
+			ID:         id.String(),
+			AllocStack: formatStack(id.allocStack),
+		})
+	}
+}
+
+type trackedID struct {
+	sync.Mutex
+	ident.ID
+
+	reportFn      ReportFn
+	allocStack    []uintptr
+	finalizeStack []uintptr
+	finalized     bool
+	noFinalize    bool
+
+	finalizedValue string
+}
+
+func (id *trackedID) NoFinalize() {
+	id.Lock()
+	id.noFinalize = true
+	id.Unlock()
+	id.ID.NoFinalize()
+}
+
+func (id *trackedID) Finalize() {
+	id.Lock()
+	if id.noFinalize {
+		id.Unlock()
+		return
+	}
+	if id.finalized {
+		id.Unlock()
+		id.reportFn(Report{
+			Type:          DoubleFinalizeReportType,
+			ID:            id.finalizedValue,
+			AllocStack:    formatStack(id.allocStack),
+			FinalizeStack: formatStack(id.finalizeStack),
+			Stack:         formatStack(callers()),
+		})
+		// NB: do not finalize the underlying ID again, it may already
+		// have been handed out by the pool to another caller.
+		return
+	}
+	id.finalized = true
+	id.finalizeStack = callers()
+	// Keep the value for reporting as the underlying ID may be reused
+	// once returned to the pool.
+	id.finalizedValue = id.ID.String()
+	id.Unlock()
+	id.ID.Finalize()
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, callers and the tracking method itself.
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+func formatStack(pcs []uintptr) string {
+	var (
+		buf    bytes.Buffer
+		frames = runtime.CallersFrames(pcs)
+	)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&buf, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
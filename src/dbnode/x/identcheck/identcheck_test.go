@@ -0,0 +1,113 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package identcheck
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPool() (ident.Pool, chan Report) {
+	reports := make(chan Report, 16)
+	p := NewPool(ident.NewPool(nil, ident.PoolOptions{}), func(r Report) {
+		reports <- r
+	})
+	return p, reports
+}
+
+func TestPoolReportsDoubleFinalize(t *testing.T) {
+	p, reports := newTestPool()
+
+	id := p.StringID("foo")
+	assert.Equal(t, "foo", id.String())
+	id.Finalize()
+	require.Equal(t, 0, len(reports))
+
+	id.Finalize()
+	require.Equal(t, 1, len(reports))
+	r := <-reports
+	assert.Equal(t, DoubleFinalizeReportType, r.Type)
+	assert.Equal(t, "foo", r.ID)
+	assert.Contains(t, r.AllocStack, "TestPoolReportsDoubleFinalize")
+	assert.Contains(t, r.FinalizeStack, "TestPoolReportsDoubleFinalize")
+	assert.Contains(t, r.Stack, "TestPoolReportsDoubleFinalize")
+}
+
+func TestPoolNoFinalizeIsNotReported(t *testing.T) {
+	p, reports := newTestPool()
+
+	id := p.Clone(ident.StringID("foo"))
+	id.NoFinalize()
+	id.Finalize()
+	id.Finalize()
+	require.Equal(t, 0, len(reports))
+}
+
+func TestPoolReportsLeak(t *testing.T) {
+	p, reports := newTestPool()
+
+	func() {
+		p.StringID("leaked")
+		p.StringID("finalized").Finalize()
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(reports) == 0 && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.True(t, len(reports) > 0)
+	r := <-reports
+	assert.Equal(t, LeakReportType, r.Type)
+	assert.Equal(t, "leaked", r.ID)
+	assert.Contains(t, r.AllocStack, "TestPoolReportsLeak")
+}
+
+func TestPoolTracksTags(t *testing.T) {
+	p, reports := newTestPool()
+
+	tag := p.StringTag("foo", "bar")
+	p.PutTag(tag)
+	require.Equal(t, 0, len(reports))
+	tag.Value.Finalize()
+	require.Equal(t, 1, len(reports))
+	r := <-reports
+	assert.Equal(t, DoubleFinalizeReportType, r.Type)
+	assert.Equal(t, "bar", r.ID)
+	assert.Contains(t, r.AllocStack, "TestPoolTracksTags")
+
+	tags := p.CloneTags(ident.NewTags(ident.StringTag("baz", "qux")))
+	require.Equal(t, 1, len(tags.Values()))
+	clone := tags.Values()[0]
+	assert.Equal(t, "baz", clone.Name.String())
+	clone.Name.Finalize()
+	clone.Name.Finalize()
+	require.Equal(t, 1, len(reports))
+	r = <-reports
+	assert.Equal(t, "baz", r.ID)
+	assert.Contains(t, r.AllocStack, "TestPoolTracksTags")
+}
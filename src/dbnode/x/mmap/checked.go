@@ -0,0 +1,139 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package mmap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrUnmapped is raised when a checked region is accessed after Munmap.
+	ErrUnmapped = errors.New("mmap region accessed after munmap")
+
+	// ErrOutstandingRefs is raised when a checked region is unmapped while
+	// references to it are still held, the region is left mapped.
+	ErrOutstandingRefs = errors.New("mmap region munmap with outstanding refs")
+
+	// ErrNoRefs is raised when a checked region is accessed or released
+	// without holding a reference.
+	ErrNoRefs = errors.New("mmap region accessed without a ref")
+)
+
+// Package-level global for easy mocking
+var munmapFn = Munmap
+
+// InvalidAccessPolicy determines how invalid accesses of checked bytes,
+// such as an access after munmap, are surfaced.
+type InvalidAccessPolicy int
+
+const (
+	// PanicOnInvalidAccess panics on invalid access.
+	PanicOnInvalidAccess InvalidAccessPolicy = iota
+
+	// ErrorOnInvalidAccess returns an error on invalid access.
+	ErrorOnInvalidAccess
+)
+
+// CheckedBytes wraps a mmap'd region and guards against use after munmap
+// by tracking the references held to it. Callers must hold a reference
+// while accessing the bytes and the region may only be unmapped once all
+// references have been released.
+type CheckedBytes struct {
+	sync.Mutex
+
+	bytes    []byte
+	refs     int32
+	unmapped int32
+	policy   InvalidAccessPolicy
+}
+
+// NewCheckedBytes returns new checked bytes wrapping the mmap'd bytes.
+func NewCheckedBytes(bytes []byte, policy InvalidAccessPolicy) *CheckedBytes {
+	return &CheckedBytes{bytes: bytes, policy: policy}
+}
+
+// IncRef increments the reference count, it is invalid to take a
+// reference after the region has been unmapped.
+func (b *CheckedBytes) IncRef() error {
+	b.Lock()
+	defer b.Unlock()
+	if atomic.LoadInt32(&b.unmapped) == 1 {
+		return b.invalidAccess(ErrUnmapped)
+	}
+	atomic.AddInt32(&b.refs, 1)
+	return nil
+}
+
+// DecRef decrements the reference count.
+func (b *CheckedBytes) DecRef() error {
+	b.Lock()
+	defer b.Unlock()
+	if atomic.LoadInt32(&b.refs) <= 0 {
+		return b.invalidAccess(ErrNoRefs)
+	}
+	atomic.AddInt32(&b.refs, -1)
+	return nil
+}
+
+// NumRef returns the reference count.
+func (b *CheckedBytes) NumRef() int {
+	return int(atomic.LoadInt32(&b.refs))
+}
+
+// Bytes returns the mmap'd bytes, a reference must be held while
+// accessing the returned bytes.
+func (b *CheckedBytes) Bytes() ([]byte, error) {
+	if atomic.LoadInt32(&b.unmapped) == 1 {
+		return nil, b.invalidAccess(ErrUnmapped)
+	}
+	if atomic.LoadInt32(&b.refs) <= 0 {
+		return nil, b.invalidAccess(ErrNoRefs)
+	}
+	return b.bytes, nil
+}
+
+// Unmapped returns whether the region has been unmapped.
+func (b *CheckedBytes) Unmapped() bool {
+	return atomic.LoadInt32(&b.unmapped) == 1
+}
+
+// Munmap unmaps the region, it is invalid to unmap the region while
+// references are held or to unmap it more than once.
+func (b *CheckedBytes) Munmap() error {
+	b.Lock()
+	defer b.Unlock()
+	if atomic.LoadInt32(&b.unmapped) == 1 {
+		return b.invalidAccess(ErrUnmapped)
+	}
+	if atomic.LoadInt32(&b.refs) > 0 {
+		return b.invalidAccess(ErrOutstandingRefs)
+	}
+	atomic.StoreInt32(&b.unmapped, 1)
+	return munmapFn(b.bytes)
+}
+
+func (b *CheckedBytes) invalidAccess(err error) error {
+	if b.policy == PanicOnInvalidAccess {
+		panic(err)
+	}
+	return err
+}
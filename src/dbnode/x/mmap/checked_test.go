@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package mmap
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCheckedBytes(t *testing.T, policy InvalidAccessPolicy) *CheckedBytes {
+	fd, err := ioutil.TempFile("", "checked")
+	require.NoError(t, err)
+	defer func() {
+		fd.Close()
+		os.Remove(fd.Name())
+	}()
+
+	_, err = fd.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	result, err := File(fd, Options{Read: true})
+	require.NoError(t, err)
+	return NewCheckedBytes(result.Result, policy)
+}
+
+func TestCheckedBytesAccessWithRef(t *testing.T) {
+	b := newTestCheckedBytes(t, ErrorOnInvalidAccess)
+
+	require.NoError(t, b.IncRef())
+	bytes, err := b.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, bytes)
+	require.NoError(t, b.DecRef())
+
+	require.NoError(t, b.Munmap())
+	assert.True(t, b.Unmapped())
+}
+
+func TestCheckedBytesErrorOnInvalidAccess(t *testing.T) {
+	b := newTestCheckedBytes(t, ErrorOnInvalidAccess)
+
+	_, err := b.Bytes()
+	assert.Equal(t, ErrNoRefs, err)
+	assert.Equal(t, ErrNoRefs, b.DecRef())
+
+	require.NoError(t, b.IncRef())
+	assert.Equal(t, ErrOutstandingRefs, b.Munmap())
+	assert.False(t, b.Unmapped())
+	require.NoError(t, b.DecRef())
+
+	require.NoError(t, b.Munmap())
+	assert.Equal(t, ErrUnmapped, b.IncRef())
+	_, err = b.Bytes()
+	assert.Equal(t, ErrUnmapped, err)
+	assert.Equal(t, ErrUnmapped, b.Munmap())
+}
+
+func TestCheckedBytesPanicOnInvalidAccess(t *testing.T) {
+	b := newTestCheckedBytes(t, PanicOnInvalidAccess)
+
+	require.NoError(t, b.Munmap())
+	assert.Panics(t, func() { b.IncRef() })
+	assert.Panics(t, func() { b.Bytes() })
+	assert.Panics(t, func() { b.Munmap() })
+}
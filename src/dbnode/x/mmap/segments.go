@@ -0,0 +1,247 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package mmap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+var (
+	// ErrSegmentUnmapped is raised when reading from a segment that has been unmapped.
+	ErrSegmentUnmapped = errors.New("mmap segment is unmapped")
+
+	errSegmentsClosed = errors.New("mmap segments are closed")
+)
+
+// Segments is a file mmap'd as a series of fixed size, page aligned segments
+// which can be unmapped and remapped independently, allowing cold regions of
+// large files to be released back to the O.S without unmapping the whole file.
+// The file must remain open for segments to be remapped, it is not closed by
+// the segments.
+type Segments struct {
+	sync.RWMutex
+
+	// NB: the file rather than its descriptor is held so that remapping after
+	// the file is closed fails rather than mapping whichever file reused the
+	// descriptor.
+	file        *os.File
+	length      int64
+	segmentSize int64
+	opts        Options
+	segments    [][]byte
+	closed      bool
+}
+
+// FileSegments mmap's a file as segments of the given size, which must be a
+// positive multiple of the page size.
+func FileSegments(file *os.File, segmentSize int64, opts Options) (*Segments, error) {
+	pageSize := int64(os.Getpagesize())
+	if segmentSize <= 0 || segmentSize%pageSize != 0 {
+		return nil, fmt.Errorf(
+			"mmap segment size %d must be a positive multiple of the page size %d",
+			segmentSize, pageSize)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("mmap file could not stat %s: %v", file.Name(), err)
+	}
+	if stat.IsDir() {
+		return nil, fmt.Errorf("mmap target is directory: %s", file.Name())
+	}
+
+	length := stat.Size()
+	numSegments := (length + segmentSize - 1) / segmentSize
+	s := &Segments{
+		file:        file,
+		length:      length,
+		segmentSize: segmentSize,
+		opts:        opts,
+		segments:    make([][]byte, numSegments),
+	}
+	for i := range s.segments {
+		if err := s.mapSegment(i); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Len returns the length of the mapped file.
+func (s *Segments) Len() int64 {
+	return s.length
+}
+
+// SegmentSize returns the size of each segment, the last segment may be shorter.
+func (s *Segments) SegmentSize() int64 {
+	return s.segmentSize
+}
+
+// NumSegments returns the number of segments.
+func (s *Segments) NumSegments() int {
+	return len(s.segments)
+}
+
+// Mapped returns whether the segment at the given index is currently mapped.
+func (s *Segments) Mapped(idx int) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.segments[idx] != nil
+}
+
+// ReadAt reads len(p) bytes starting at the given offset across segments,
+// returning ErrSegmentUnmapped if any of the segments read are unmapped.
+func (s *Segments) ReadAt(p []byte, off int64) (int, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return 0, errSegmentsClosed
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("mmap segments negative offset: %d", off)
+	}
+
+	n := 0
+	for n < len(p) && off < s.length {
+		segment := s.segments[off/s.segmentSize]
+		if segment == nil {
+			return n, ErrSegmentUnmapped
+		}
+		copied := copy(p[n:], segment[off%s.segmentSize:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// UnmapRange unmaps all segments wholly contained by the given byte range,
+// segments only partially covered by the range are left mapped.
+func (s *Segments) UnmapRange(off, length int64) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return errSegmentsClosed
+	}
+
+	start, end := s.containedSegments(off, length)
+	multiErr := xerrors.NewMultiError()
+	for i := start; i < end; i++ {
+		multiErr = multiErr.Add(s.unmapSegment(i))
+	}
+	return multiErr.FinalError()
+}
+
+// MapRange maps any unmapped segments overlapping the given byte range.
+func (s *Segments) MapRange(off, length int64) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return errSegmentsClosed
+	}
+
+	start, end := s.overlappingSegments(off, length)
+	for i := start; i < end; i++ {
+		if s.segments[i] != nil {
+			continue
+		}
+		if err := s.mapSegment(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close unmaps all segments, the file descriptor is not closed.
+func (s *Segments) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return errSegmentsClosed
+	}
+	s.closed = true
+
+	multiErr := xerrors.NewMultiError()
+	for i := range s.segments {
+		multiErr = multiErr.Add(s.unmapSegment(i))
+	}
+	return multiErr.FinalError()
+}
+
+func (s *Segments) mapSegment(idx int) error {
+	offset := int64(idx) * s.segmentSize
+	length := s.segmentSize
+	if remaining := s.length - offset; remaining < length {
+		length = remaining
+	}
+	fd := s.file.Fd()
+	if fd == ^uintptr(0) {
+		return fmt.Errorf("mmap segment file is closed: %s", s.file.Name())
+	}
+	result, err := mmapFdFn(int64(fd), offset, length, s.opts)
+	if err != nil {
+		return err
+	}
+	s.segments[idx] = result.Result
+	return nil
+}
+
+func (s *Segments) unmapSegment(idx int) error {
+	segment := s.segments[idx]
+	if segment == nil {
+		return nil
+	}
+	s.segments[idx] = nil
+	return Munmap(segment)
+}
+
+func (s *Segments) containedSegments(off, length int64) (int, int) {
+	end := off + length
+	if end >= s.length {
+		// The last segment may be shorter than the segment size.
+		end = int64(len(s.segments)) * s.segmentSize
+	}
+	return s.clamp((off + s.segmentSize - 1) / s.segmentSize),
+		s.clamp(end / s.segmentSize)
+}
+
+func (s *Segments) overlappingSegments(off, length int64) (int, int) {
+	return s.clamp(off / s.segmentSize),
+		s.clamp((off + length + s.segmentSize - 1) / s.segmentSize)
+}
+
+func (s *Segments) clamp(idx int64) int {
+	if idx < 0 {
+		return 0
+	}
+	if idx > int64(len(s.segments)) {
+		return len(s.segments)
+	}
+	return int(idx)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package mmap
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentsUnmapAndMapRange(t *testing.T) {
+	pageSize := int64(os.Getpagesize())
+	data := make([]byte, 3*pageSize+10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	fd, err := ioutil.TempFile("", "segments")
+	require.NoError(t, err)
+	defer func() {
+		fd.Close()
+		os.Remove(fd.Name())
+	}()
+	_, err = fd.Write(data)
+	require.NoError(t, err)
+
+	s, err := FileSegments(fd, pageSize, Options{Read: true})
+	require.NoError(t, err)
+	require.Equal(t, 4, s.NumSegments())
+	require.Equal(t, int64(len(data)), s.Len())
+
+	read := make([]byte, len(data))
+	n, err := s.ReadAt(read, 0)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	assert.Equal(t, data, read)
+
+	// Only the second segment is wholly contained by the range.
+	require.NoError(t, s.UnmapRange(pageSize/2, 2*pageSize))
+	assert.True(t, s.Mapped(0))
+	assert.False(t, s.Mapped(1))
+	assert.True(t, s.Mapped(2))
+	assert.True(t, s.Mapped(3))
+
+	_, err = s.ReadAt(read[:10], pageSize+1)
+	assert.Equal(t, ErrSegmentUnmapped, err)
+
+	// Reads of mapped segments are unaffected.
+	n, err = s.ReadAt(read[:20], 3*pageSize-10)
+	require.NoError(t, err)
+	assert.Equal(t, data[3*pageSize-10:3*pageSize+10], read[:20])
+
+	// The trailing partial segment is unmapped when covered to the end.
+	require.NoError(t, s.UnmapRange(3*pageSize, pageSize))
+	assert.False(t, s.Mapped(3))
+
+	require.NoError(t, s.MapRange(pageSize+1, 1))
+	assert.True(t, s.Mapped(1))
+	n, err = s.ReadAt(read[:10], pageSize+1)
+	require.NoError(t, err)
+	assert.Equal(t, data[pageSize+1:pageSize+11], read[:10])
+
+	n, err = s.ReadAt(read, 0)
+	assert.Equal(t, ErrSegmentUnmapped, err)
+
+	require.NoError(t, s.MapRange(0, s.Len()))
+	n, err = s.ReadAt(read[:20], int64(len(data))-10)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 10, n)
+
+	require.NoError(t, s.Close())
+}
+
+func TestSegmentsInvalidSegmentSize(t *testing.T) {
+	fd, err := ioutil.TempFile("", "segments")
+	require.NoError(t, err)
+	defer func() {
+		fd.Close()
+		os.Remove(fd.Name())
+	}()
+
+	_, err = FileSegments(fd, int64(os.Getpagesize())+1, Options{Read: true})
+	require.Error(t, err)
+}
+
+func TestSegmentsMapRangeAfterFileClosed(t *testing.T) {
+	pageSize := int64(os.Getpagesize())
+	fd, err := ioutil.TempFile("", "segments")
+	require.NoError(t, err)
+	defer os.Remove(fd.Name())
+	_, err = fd.Write(make([]byte, 2*pageSize))
+	require.NoError(t, err)
+
+	s, err := FileSegments(fd, pageSize, Options{Read: true})
+	require.NoError(t, err)
+	require.NoError(t, s.UnmapRange(0, pageSize))
+
+	// Remapping must not use a descriptor that may have been reused.
+	require.NoError(t, fd.Close())
+	require.Error(t, s.MapRange(0, pageSize))
+	assert.False(t, s.Mapped(0))
+
+	require.NoError(t, s.Close())
+}
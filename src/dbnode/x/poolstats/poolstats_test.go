@@ -0,0 +1,104 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package poolstats
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestRegistryTracksPoolMetrics(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	r := NewRegistry(scope)
+
+	// Emulate the metrics emitted by an object pool.
+	poolScope := r.Scope().SubScope("encoder-pool")
+	poolScope.Gauge("total").Update(10)
+	poolScope.Gauge("free").Update(4)
+	poolScope.Counter("get-on-empty").Inc(2)
+	poolScope.Counter("put-on-full").Inc(1)
+
+	recordGet := r.GetRecorder("encoder-pool")
+	for i := 0; i < 8; i++ {
+		recordGet()
+	}
+
+	// Emulate a bucketized pool, with a scope tagged per bucket.
+	bytesScope := r.Scope().SubScope("bytes-pool")
+	for _, capacity := range []string{"16", "32"} {
+		bucketScope := bytesScope.Tagged(map[string]string{"bucket-capacity": capacity})
+		bucketScope.Gauge("total").Update(5)
+		bucketScope.Gauge("free").Update(3)
+	}
+
+	snapshot := r.Snapshot()
+	require.Equal(t, 2, len(snapshot.Pools))
+
+	bytesPool := snapshot.Pools[0]
+	assert.Equal(t, "bytes-pool", bytesPool.Name)
+	assert.Equal(t, int64(10), bytesPool.Size)
+	assert.Equal(t, int64(6), bytesPool.Free)
+	assert.Nil(t, bytesPool.HitRate)
+
+	encoderPool := snapshot.Pools[1]
+	assert.Equal(t, "encoder-pool", encoderPool.Name)
+	assert.Equal(t, int64(10), encoderPool.Size)
+	assert.Equal(t, int64(4), encoderPool.Free)
+	assert.Equal(t, int64(8), encoderPool.Gets)
+	assert.Equal(t, int64(2), encoderPool.Grows)
+	assert.Equal(t, int64(1), encoderPool.Discards)
+	require.NotNil(t, encoderPool.HitRate)
+	assert.Equal(t, 0.75, *encoderPool.HitRate)
+
+	// Metrics are still forwarded to the wrapped scope.
+	r.ReportMetrics()
+	metrics := scope.Snapshot()
+	assert.Equal(t, int64(2), metrics.Counters()["encoder-pool.get-on-empty+"].Value())
+	assert.Equal(t, int64(8), metrics.Counters()["encoder-pool.gets+"].Value())
+	assert.Equal(t, 0.75, metrics.Gauges()["encoder-pool.hit-rate+"].Value())
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry(tally.NoopScope)
+	r.Scope().SubScope("series-pool").Gauge("total").Update(1024)
+
+	h := NewHandler(r)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HandlerPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var snapshot Snapshot
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	require.Equal(t, 1, len(snapshot.Pools))
+	assert.Equal(t, "series-pool", snapshot.Pools[0].Name)
+	assert.Equal(t, int64(1024), snapshot.Pools[0].Size)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, HandlerPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
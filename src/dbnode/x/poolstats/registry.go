@@ -0,0 +1,250 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package poolstats
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/uber-go/tally"
+)
+
+// The metrics emitted by object pools that the registry tracks.
+const (
+	freeGauge      = "free"
+	totalGauge     = "total"
+	getOnEmptyName = "get-on-empty"
+	putOnFullName  = "put-on-full"
+)
+
+type registry struct {
+	sync.RWMutex
+
+	scope tally.Scope
+	pools map[string]*poolState
+}
+
+// NewRegistry returns a new pool registry forwarding metrics to scope.
+func NewRegistry(scope tally.Scope) Registry {
+	return &registry{
+		scope: scope,
+		pools: make(map[string]*poolState),
+	}
+}
+
+func (r *registry) Scope() tally.Scope {
+	return &rootScope{Scope: r.scope, registry: r}
+}
+
+func (r *registry) GetRecorder(name string) func() {
+	pool := r.pool(name, r.scope.SubScope(name))
+	gets := pool.scope.Counter("gets")
+	return func() {
+		atomic.AddInt64(&pool.gets, 1)
+		gets.Inc(1)
+	}
+}
+
+func (r *registry) ReportMetrics() {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, pool := range r.pools {
+		stats := pool.stats()
+		if stats.HitRate != nil {
+			pool.hitRate.Update(*stats.HitRate)
+		}
+	}
+}
+
+func (r *registry) Snapshot() Snapshot {
+	r.RLock()
+	snapshot := Snapshot{Pools: make([]PoolStats, 0, len(r.pools))}
+	for _, pool := range r.pools {
+		snapshot.Pools = append(snapshot.Pools, pool.stats())
+	}
+	r.RUnlock()
+
+	sort.Slice(snapshot.Pools, func(i, j int) bool {
+		return snapshot.Pools[i].Name < snapshot.Pools[j].Name
+	})
+	return snapshot
+}
+
+func (r *registry) pool(name string, scope tally.Scope) *poolState {
+	r.RLock()
+	pool, ok := r.pools[name]
+	r.RUnlock()
+	if ok {
+		return pool
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if pool, ok := r.pools[name]; ok {
+		return pool
+	}
+	pool = &poolState{
+		name:    name,
+		scope:   scope,
+		hitRate: scope.Gauge("hit-rate"),
+	}
+	r.pools[name] = pool
+	return pool
+}
+
+// poolState is the state of a pool, a pool emits its metrics from a
+// single instance or from an instance per bucket.
+type poolState struct {
+	sync.Mutex
+
+	name      string
+	scope     tally.Scope
+	hitRate   tally.Gauge
+	gets      int64
+	instances []*instanceState
+}
+
+func (p *poolState) newInstance() *instanceState {
+	instance := &instanceState{}
+	p.Lock()
+	p.instances = append(p.instances, instance)
+	p.Unlock()
+	return instance
+}
+
+func (p *poolState) stats() PoolStats {
+	stats := PoolStats{
+		Name: p.name,
+		Gets: atomic.LoadInt64(&p.gets),
+	}
+	p.Lock()
+	for _, instance := range p.instances {
+		stats.Size += int64(math.Float64frombits(atomic.LoadUint64(&instance.total)))
+		stats.Free += int64(math.Float64frombits(atomic.LoadUint64(&instance.free)))
+		stats.Grows += atomic.LoadInt64(&instance.getOnEmpty)
+		stats.Discards += atomic.LoadInt64(&instance.putOnFull)
+	}
+	p.Unlock()
+
+	if stats.Gets > 0 {
+		// NB: gets recorded and grows are read at slightly different times
+		// so clamp the hit rate.
+		hitRate := math.Max(0, 1-float64(stats.Grows)/float64(stats.Gets))
+		stats.HitRate = &hitRate
+	}
+	return stats
+}
+
+type instanceState struct {
+	free       uint64
+	total      uint64
+	getOnEmpty int64
+	putOnFull  int64
+}
+
+// rootScope creates a pool scope for each sub scope.
+type rootScope struct {
+	tally.Scope
+
+	registry *registry
+}
+
+func (s *rootScope) Tagged(tags map[string]string) tally.Scope {
+	return &rootScope{Scope: s.Scope.Tagged(tags), registry: s.registry}
+}
+
+func (s *rootScope) SubScope(name string) tally.Scope {
+	scope := s.Scope.SubScope(name)
+	pool := s.registry.pool(name, scope)
+	return &poolScope{Scope: scope, instance: pool.newInstance(), pool: pool}
+}
+
+// poolScope captures the metrics emitted by a pool.
+type poolScope struct {
+	tally.Scope
+
+	pool     *poolState
+	instance *instanceState
+}
+
+func (s *poolScope) Tagged(tags map[string]string) tally.Scope {
+	// NB: bucketized pools tag the scope of each bucket.
+	return &poolScope{
+		Scope:    s.Scope.Tagged(tags),
+		pool:     s.pool,
+		instance: s.pool.newInstance(),
+	}
+}
+
+func (s *poolScope) SubScope(name string) tally.Scope {
+	return &poolScope{
+		Scope:    s.Scope.SubScope(name),
+		pool:     s.pool,
+		instance: s.pool.newInstance(),
+	}
+}
+
+func (s *poolScope) Counter(name string) tally.Counter {
+	counter := s.Scope.Counter(name)
+	switch name {
+	case getOnEmptyName:
+		return &capturingCounter{Counter: counter, value: &s.instance.getOnEmpty}
+	case putOnFullName:
+		return &capturingCounter{Counter: counter, value: &s.instance.putOnFull}
+	}
+	return counter
+}
+
+func (s *poolScope) Gauge(name string) tally.Gauge {
+	gauge := s.Scope.Gauge(name)
+	switch name {
+	case freeGauge:
+		return &capturingGauge{Gauge: gauge, value: &s.instance.free}
+	case totalGauge:
+		return &capturingGauge{Gauge: gauge, value: &s.instance.total}
+	}
+	return gauge
+}
+
+type capturingCounter struct {
+	tally.Counter
+
+	value *int64
+}
+
+func (c *capturingCounter) Inc(delta int64) {
+	atomic.AddInt64(c.value, delta)
+	c.Counter.Inc(delta)
+}
+
+type capturingGauge struct {
+	tally.Gauge
+
+	value *uint64
+}
+
+func (g *capturingGauge) Update(value float64) {
+	atomic.StoreUint64(g.value, math.Float64bits(value))
+	g.Gauge.Update(value)
+}
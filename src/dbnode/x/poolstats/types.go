@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package poolstats tracks the state of object pools from the metrics they
+// emit, so that pool sizing can be validated from live process state.
+package poolstats
+
+import (
+	"github.com/uber-go/tally"
+)
+
+// Registry tracks the state of the object pools constructed with the
+// sub scopes of its scope.
+type Registry interface {
+	// Scope returns a scope for which each sub scope tracks the pool emitting
+	// its metrics to it, all metrics are also forwarded to the wrapped scope.
+	Scope() tally.Scope
+
+	// GetRecorder returns a function that records a get from the named pool,
+	// pools with gets recorded also have their hit rate tracked.
+	GetRecorder(name string) func()
+
+	// ReportMetrics emits the metrics derived from the state of each pool.
+	ReportMetrics()
+
+	// Snapshot returns the current state of all tracked pools.
+	Snapshot() Snapshot
+}
+
+// Snapshot is the state of all tracked pools.
+type Snapshot struct {
+	Pools []PoolStats `json:"pools"`
+}
+
+// PoolStats is the state of a single pool, summed across the buckets of
+// bucketized pools.
+type PoolStats struct {
+	Name string `json:"name"`
+	// Size is the number of objects the pool retains.
+	Size int64 `json:"size"`
+	// Free is the number of objects in the pool when last sampled.
+	Free int64 `json:"free"`
+	// Gets is the number of gets, only tracked for pools with a get recorder.
+	Gets int64 `json:"gets"`
+	// Grows is the number of gets that found the pool empty and allocated.
+	Grows int64 `json:"grows"`
+	// Discards is the number of puts that found the pool full.
+	Discards int64 `json:"discards"`
+	// HitRate is the fraction of gets served from the pool, only set if
+	// gets are tracked for the pool.
+	HitRate *float64 `json:"hitRate,omitempty"`
+}
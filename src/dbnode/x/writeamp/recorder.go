@@ -0,0 +1,137 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writeamp
+
+import (
+	"sync/atomic"
+
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+type sourceMetrics struct {
+	written       tally.Counter
+	amplification tally.Gauge
+}
+
+type recorder struct {
+	sources  []Source
+	ingested int64
+	written  []int64
+
+	ingestedBytes tally.Counter
+	amplification tally.Gauge
+	bySource      []sourceMetrics
+}
+
+// NewRecorder returns a recorder that emits the bytes ingested and written
+// per source as counters, and the write amplification as gauges.
+func NewRecorder(iopts instrument.Options) Recorder {
+	scope := iopts.MetricsScope()
+	r := &recorder{
+		sources:       Sources(),
+		written:       make([]int64, len(Sources())),
+		ingestedBytes: scope.Counter("ingested-bytes"),
+		amplification: scope.Gauge("amplification"),
+	}
+	for _, source := range r.sources {
+		sourceScope := scope.Tagged(map[string]string{"source": string(source)})
+		r.bySource = append(r.bySource, sourceMetrics{
+			written:       sourceScope.Counter("written-bytes"),
+			amplification: sourceScope.Gauge("amplification"),
+		})
+	}
+	return r
+}
+
+func (r *recorder) RecordIngested(bytes int) {
+	atomic.AddInt64(&r.ingested, int64(bytes))
+	r.ingestedBytes.Inc(int64(bytes))
+}
+
+func (r *recorder) RecordWritten(source Source, bytes int64) {
+	idx := r.sourceIndex(source)
+	if idx < 0 {
+		return
+	}
+	written := atomic.AddInt64(&r.written[idx], bytes)
+	r.bySource[idx].written.Inc(bytes)
+
+	// NB: only update the gauges when bytes are written to disk since that
+	// happens far less often than ingestion, i.e. once per commit log chunk
+	// or fileset rather than once per write.
+	ingested := atomic.LoadInt64(&r.ingested)
+	r.bySource[idx].amplification.Update(ratio(written, ingested))
+	r.amplification.Update(ratio(r.totalWritten(), ingested))
+}
+
+func (r *recorder) Report() Report {
+	report := Report{
+		IngestedBytes: atomic.LoadInt64(&r.ingested),
+		Sources:       make([]SourceReport, 0, len(r.sources)),
+	}
+	for i, source := range r.sources {
+		written := atomic.LoadInt64(&r.written[i])
+		report.WrittenBytes += written
+		report.Sources = append(report.Sources, SourceReport{
+			Source:        source,
+			WrittenBytes:  written,
+			Amplification: ratio(written, report.IngestedBytes),
+		})
+	}
+	report.Amplification = ratio(report.WrittenBytes, report.IngestedBytes)
+	return report
+}
+
+func (r *recorder) sourceIndex(source Source) int {
+	for i, s := range r.sources {
+		if s == source {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *recorder) totalWritten() int64 {
+	var total int64
+	for i := range r.written {
+		total += atomic.LoadInt64(&r.written[i])
+	}
+	return total
+}
+
+func ratio(written, ingested int64) float64 {
+	if ingested == 0 {
+		return 0
+	}
+	return float64(written) / float64(ingested)
+}
+
+type noopRecorder struct{}
+
+// NewNoopRecorder returns a recorder that records nothing.
+func NewNoopRecorder() Recorder {
+	return noopRecorder{}
+}
+
+func (noopRecorder) RecordIngested(bytes int)                 {}
+func (noopRecorder) RecordWritten(source Source, bytes int64) {}
+func (noopRecorder) Report() Report                           { return Report{} }
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package writeamp accounts for the bytes written to disk per logical byte
+// ingested, i.e. the write amplification of the storage engine.
+package writeamp
+
+// Source is a path of the storage engine that writes to disk.
+type Source string
+
+const (
+	// CommitLog is the commit log, written for every datapoint ingested.
+	CommitLog Source = "commitlog"
+	// Snapshot is the data filesets written when snapshotting blocks.
+	Snapshot Source = "snapshot"
+	// Flush is the data filesets written when flushing sealed blocks.
+	Flush Source = "flush"
+	// Index is the index filesets, written when the segments of an index
+	// block are compacted and flushed or snapshotted.
+	Index Source = "index"
+)
+
+// Sources returns all the sources that write to disk.
+func Sources() []Source {
+	return []Source{CommitLog, Snapshot, Flush, Index}
+}
+
+// Recorder records the bytes ingested and written to disk.
+type Recorder interface {
+	// RecordIngested records logical bytes ingested, i.e. the size of the
+	// series ID, datapoint and annotation of a write.
+	RecordIngested(bytes int)
+
+	// RecordWritten records bytes written to disk by a source.
+	RecordWritten(source Source, bytes int64)
+
+	// Report returns the bytes ingested and written since the recorder
+	// was created.
+	Report() Report
+}
+
+// Report is a report of the write amplification since the process started.
+type Report struct {
+	IngestedBytes int64          `json:"ingestedBytes"`
+	WrittenBytes  int64          `json:"writtenBytes"`
+	Amplification float64        `json:"amplification"`
+	Sources       []SourceReport `json:"sources"`
+}
+
+// SourceReport is the write amplification of a single source.
+type SourceReport struct {
+	Source        Source  `json:"source"`
+	WrittenBytes  int64   `json:"writtenBytes"`
+	Amplification float64 `json:"amplification"`
+}
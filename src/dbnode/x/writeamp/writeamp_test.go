@@ -0,0 +1,83 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writeamp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestRecorderReport(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	r := NewRecorder(instrument.NewOptions().SetMetricsScope(scope))
+
+	require.Equal(t, float64(0), r.Report().Amplification)
+
+	r.RecordIngested(100)
+	r.RecordIngested(100)
+	r.RecordWritten(CommitLog, 250)
+	r.RecordWritten(Flush, 100)
+	r.RecordWritten(Index, 50)
+	r.RecordWritten(Source("unknown"), 1000)
+
+	report := r.Report()
+	assert.Equal(t, int64(200), report.IngestedBytes)
+	assert.Equal(t, int64(400), report.WrittenBytes)
+	assert.Equal(t, 2.0, report.Amplification)
+	assert.Equal(t, []SourceReport{
+		{Source: CommitLog, WrittenBytes: 250, Amplification: 1.25},
+		{Source: Snapshot},
+		{Source: Flush, WrittenBytes: 100, Amplification: 0.5},
+		{Source: Index, WrittenBytes: 50, Amplification: 0.25},
+	}, report.Sources)
+
+	snapshot := scope.Snapshot()
+	assert.Equal(t, int64(200), snapshot.Counters()["ingested-bytes+"].Value())
+	assert.Equal(t, 2.0, snapshot.Gauges()["amplification+"].Value())
+	assert.Equal(t, 1.25,
+		snapshot.Gauges()["amplification+source=commitlog"].Value())
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder(instrument.NewOptions())
+	r.RecordIngested(10)
+	r.RecordWritten(Snapshot, 30)
+
+	h := NewHandler(r)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HandlerPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 3.0, report.Amplification)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, HandlerPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package analysis provides analyzers which derive additional terms from
+// field values so that they can be searched by more than exact matching.
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/idx"
+)
+
+const analyzedFieldNamePrefix = "_m3ninx_analyzed_"
+
+// Analyzer derives the terms that a field value is indexed by in addition to
+// the value itself.
+type Analyzer interface {
+	// Name returns the name of the analyzer, the terms it produces are indexed
+	// under field names derived from it.
+	Name() string
+
+	// Terms returns the terms to index a field value by.
+	Terms(value []byte) [][]byte
+
+	// QueryTerms returns the terms a field value must have been indexed by to
+	// match the value searched for, false if the analyzer cannot search for it.
+	QueryTerms(value []byte) ([][]byte, bool)
+
+	// Rewrite returns a query over the terms indexed by the analyzer to run in
+	// place of a term, regexp or prefix query, false if the analyzer cannot
+	// narrow the query.
+	Rewrite(q *querypb.Query) (*querypb.Query, bool)
+}
+
+// FieldName returns the name of the field the terms produced by the analyzer
+// for the field are indexed under.
+func FieldName(a Analyzer, field []byte) []byte {
+	name := make([]byte, 0, len(analyzedFieldNamePrefix)+len(a.Name())+1+len(field))
+	name = append(name, analyzedFieldNamePrefix...)
+	name = append(name, a.Name()...)
+	name = append(name, ':')
+	return append(name, field...)
+}
+
+// NumericFieldName returns the name of the field the numeric terms of the
+// values of the field are indexed under.
+func NumericFieldName(field []byte) []byte {
+	const name = "numeric"
+	numericName := make([]byte, 0, len(analyzedFieldNamePrefix)+len(name)+1+len(field))
+	numericName = append(numericName, analyzedFieldNamePrefix...)
+	numericName = append(numericName, name...)
+	numericName = append(numericName, ':')
+	return append(numericName, field...)
+}
+
+// IsAnalyzedFieldName returns whether the field name is one that analyzed
+// terms are indexed under.
+func IsAnalyzedFieldName(field []byte) bool {
+	return bytes.HasPrefix(field, []byte(analyzedFieldNamePrefix))
+}
+
+// NewQuery returns a query for documents whose field was indexed by the
+// analyzer with all the terms of the value, false if the analyzer cannot
+// search for the value.
+func NewQuery(a Analyzer, field, value []byte) (idx.Query, bool) {
+	terms, ok := a.QueryTerms(value)
+	if !ok || len(terms) == 0 {
+		return idx.Query{}, false
+	}
+	name := FieldName(a, field)
+	if len(terms) == 1 {
+		return idx.NewTermQuery(name, terms[0]), true
+	}
+	queries := make([]idx.Query, 0, len(terms))
+	for _, term := range terms {
+		queries = append(queries, idx.NewTermQuery(name, term))
+	}
+	return idx.NewConjunctionQuery(queries...), true
+}
+
+type lowercaseAnalyzer struct{}
+
+// NewLowercaseAnalyzer returns an analyzer that indexes values with their
+// ASCII letters lowercased, allowing values to be searched for ignoring their
+// case. Case insensitive term queries are rewritten to match the lowercased
+// terms rather than scanning every term of the field.
+func NewLowercaseAnalyzer() Analyzer {
+	return lowercaseAnalyzer{}
+}
+
+func (a lowercaseAnalyzer) Name() string {
+	return "lowercase"
+}
+
+func (a lowercaseAnalyzer) Terms(value []byte) [][]byte {
+	return [][]byte{asciiLower(value)}
+}
+
+func (a lowercaseAnalyzer) QueryTerms(value []byte) ([][]byte, bool) {
+	return a.Terms(value), true
+}
+
+func (a lowercaseAnalyzer) Rewrite(q *querypb.Query) (*querypb.Query, bool) {
+	inner, ok := q.Query.(*querypb.Query_CaseInsensitiveTerm)
+	if !ok {
+		return nil, false
+	}
+	// NB: the lowercased terms only fold ASCII letters, matching the case
+	// insensitive term searcher, so the rewritten query is exact.
+	field, term := inner.CaseInsensitiveTerm.Field, inner.CaseInsensitiveTerm.Term
+	return newTermQueryProto(FieldName(a, field), asciiLower(term)), true
+}
+
+// asciiLower returns a copy of the value with its ASCII letters lowercased.
+func asciiLower(value []byte) []byte {
+	lower := make([]byte, len(value))
+	for i, c := range value {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower[i] = c
+	}
+	return lower
+}
+
+type ngramAnalyzer struct {
+	name string
+	size int
+}
+
+// NewNGramAnalyzer returns an analyzer that indexes values by each of their
+// substrings of size bytes, allowing values to be searched for by substring.
+// NB: a query matches every value containing all the n-grams of the
+// substring, which is a superset of the values containing the substring when
+// it is longer than size, so results may need to be filtered by the caller.
+// Regexp queries are rewritten to require the n-grams of the literals every
+// match must contain alongside the regexp itself, which filters the superset.
+func NewNGramAnalyzer(size int) (Analyzer, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("ngram size must be positive: %d", size)
+	}
+	return ngramAnalyzer{
+		name: fmt.Sprintf("ngram%d", size),
+		size: size,
+	}, nil
+}
+
+func (a ngramAnalyzer) Name() string {
+	return a.name
+}
+
+func (a ngramAnalyzer) Terms(value []byte) [][]byte {
+	if len(value) < a.size {
+		return nil
+	}
+	var (
+		terms = make([][]byte, 0, len(value)-a.size+1)
+		seen  = make(map[string]struct{}, len(value)-a.size+1)
+	)
+	for i := 0; i+a.size <= len(value); i++ {
+		ngram := value[i : i+a.size]
+		if _, ok := seen[string(ngram)]; ok {
+			continue
+		}
+		seen[string(ngram)] = struct{}{}
+		terms = append(terms, append([]byte(nil), ngram...))
+	}
+	return terms
+}
+
+func (a ngramAnalyzer) QueryTerms(value []byte) ([][]byte, bool) {
+	if len(value) < a.size {
+		return nil, false
+	}
+	return a.Terms(value), true
+}
+
+func (a ngramAnalyzer) Rewrite(q *querypb.Query) (*querypb.Query, bool) {
+	inner, ok := q.Query.(*querypb.Query_Regexp)
+	if !ok {
+		return nil, false
+	}
+	re, err := syntax.Parse(string(inner.Regexp.Regexp), syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+
+	var (
+		name  = FieldName(a, inner.Regexp.Field)
+		terms = make([]*querypb.Query, 0, 4)
+		seen  = make(map[string]struct{})
+	)
+	for _, literal := range requiredLiterals(re.Simplify()) {
+		for _, term := range a.Terms(literal) {
+			if _, ok := seen[string(term)]; ok {
+				continue
+			}
+			seen[string(term)] = struct{}{}
+			terms = append(terms, newTermQueryProto(name, term))
+		}
+	}
+	if len(terms) == 0 {
+		return nil, false
+	}
+	return &querypb.Query{
+		Query: &querypb.Query_Conjunction{
+			Conjunction: &querypb.ConjunctionQuery{
+				Queries: append(terms, q),
+			},
+		},
+	}, true
+}
+
+// requiredLiterals returns the case sensitive literals every string matching
+// the regexp must contain.
+func requiredLiterals(re *syntax.Regexp) [][]byte {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil
+		}
+		return [][]byte{[]byte(string(re.Rune))}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min < 1 {
+			return nil
+		}
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpConcat:
+		var literals [][]byte
+		for _, sub := range re.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		return literals
+	}
+	return nil
+}
+
+func newTermQueryProto(field, term []byte) *querypb.Query {
+	return &querypb.Query{
+		Query: &querypb.Query_Term{
+			Term: &querypb.TermQuery{Field: field, Term: term},
+		},
+	}
+}
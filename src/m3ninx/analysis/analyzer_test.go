@@ -0,0 +1,110 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/idx"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLowercaseAnalyzer(t *testing.T) {
+	a := NewLowercaseAnalyzer()
+	require.Equal(t, [][]byte{[]byte("foobar")}, a.Terms([]byte("FooBar")))
+	// only ASCII letters are folded, matching case insensitive term queries.
+	require.Equal(t, [][]byte{[]byte("\u212a")}, a.Terms([]byte("\u212a")))
+
+	terms, ok := a.QueryTerms([]byte("FOOBAR"))
+	require.True(t, ok)
+	require.Equal(t, [][]byte{[]byte("foobar")}, terms)
+}
+
+func TestNGramAnalyzer(t *testing.T) {
+	_, err := NewNGramAnalyzer(0)
+	require.Error(t, err)
+
+	a, err := NewNGramAnalyzer(3)
+	require.NoError(t, err)
+	require.Equal(t, "ngram3", a.Name())
+
+	// repeated n-grams are only indexed once.
+	require.Equal(t, [][]byte{
+		[]byte("aba"), []byte("bab"),
+	}, a.Terms([]byte("ababa")))
+	require.Nil(t, a.Terms([]byte("ab")))
+
+	_, ok := a.QueryTerms([]byte("ab"))
+	require.False(t, ok)
+}
+
+func TestNewQuery(t *testing.T) {
+	a, err := NewNGramAnalyzer(3)
+	require.NoError(t, err)
+
+	field := []byte("service")
+	require.True(t, IsAnalyzedFieldName(FieldName(a, field)))
+	require.False(t, IsAnalyzedFieldName(field))
+
+	q, ok := NewQuery(a, field, []byte("api"))
+	require.True(t, ok)
+	require.Equal(t, "term(_m3ninx_analyzed_ngram3:service, api)", q.String())
+
+	q, ok = NewQuery(a, field, []byte("apis"))
+	require.True(t, ok)
+	require.Equal(t, "conjunction(term(_m3ninx_analyzed_ngram3:service, api), "+
+		"term(_m3ninx_analyzed_ngram3:service, pis))", q.String())
+
+	_, ok = NewQuery(a, field, []byte("ap"))
+	require.False(t, ok)
+}
+
+func TestRewriteQuery(t *testing.T) {
+	lowercase := NewLowercaseAnalyzer()
+	q, err := RewriteQuery(lowercase, idx.NewConjunctionQuery(
+		idx.NewTermQuery([]byte("city"), []byte("NYC")),
+		idx.NewNegationQuery(idx.NewCaseInsensitiveTermQuery([]byte("service"), []byte("API"))),
+	))
+	require.NoError(t, err)
+	require.Equal(t, "conjunction(term(city, NYC), "+
+		"negation(term(_m3ninx_analyzed_lowercase:service, api)))", q.String())
+
+	ngram, err := NewNGramAnalyzer(3)
+	require.NoError(t, err)
+	q, err = RewriteQuery(ngram, idx.MustCreateRegexpQuery([]byte("service"), []byte(".*apis.*")))
+	require.NoError(t, err)
+	require.Equal(t, "conjunction(term(_m3ninx_analyzed_ngram3:service, api), "+
+		"term(_m3ninx_analyzed_ngram3:service, pis), regexp(service, .*apis.*))", q.String())
+
+	// queries the analyzer cannot narrow are left as is.
+	for _, re := range []string{".*ap.*", "api|web", "(?i)api.*", "(api)?.*"} {
+		orig := idx.MustCreateRegexpQuery([]byte("service"), []byte(re))
+		q, err = RewriteQuery(ngram, orig)
+		require.NoError(t, err)
+		require.True(t, q.Equal(orig), re)
+	}
+
+	orig := idx.NewTermQuery([]byte("service"), []byte("api"))
+	q, err = RewriteQuery(nil, orig)
+	require.NoError(t, err)
+	require.True(t, q.Equal(orig))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package analysis
+
+import (
+	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
+)
+
+type fieldsIterator struct {
+	sgmt.FieldsIterator
+}
+
+// NewFieldsIterator returns an iterator over the fields of the iterator that
+// skips the fields analyzed terms are indexed under, for listing the fields
+// documents were indexed with.
+func NewFieldsIterator(iter sgmt.FieldsIterator) sgmt.FieldsIterator {
+	return fieldsIterator{FieldsIterator: iter}
+}
+
+func (it fieldsIterator) Next() bool {
+	for it.FieldsIterator.Next() {
+		if !IsAnalyzedFieldName(it.FieldsIterator.Current()) {
+			return true
+		}
+	}
+	return false
+}
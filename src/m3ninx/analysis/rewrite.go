@@ -0,0 +1,105 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package analysis
+
+import (
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/idx"
+)
+
+// RewriteQuery returns the query with the term, regexp and prefix queries the
+// analyzer can narrow rewritten to search the terms indexed by the analyzer.
+// NB: segments indexed before the analyzer was configured do not have the
+// analyzed terms, so rewritten queries do not match their documents.
+func RewriteQuery(a Analyzer, q idx.Query) (idx.Query, error) {
+	sq := q.SearchQuery()
+	if a == nil || sq == nil {
+		return q, nil
+	}
+	pb, rewritten := rewriteQuery(a, sq.ToProto())
+	if !rewritten {
+		return q, nil
+	}
+	data, err := pb.Marshal()
+	if err != nil {
+		return idx.Query{}, err
+	}
+	return idx.Unmarshal(data)
+}
+
+func rewriteQuery(a Analyzer, q *querypb.Query) (*querypb.Query, bool) {
+	if q == nil {
+		return q, false
+	}
+
+	switch inner := q.Query.(type) {
+	case *querypb.Query_Negation:
+		rq, ok := rewriteQuery(a, inner.Negation.Query)
+		if !ok {
+			return q, false
+		}
+		return &querypb.Query{
+			Query: &querypb.Query_Negation{
+				Negation: &querypb.NegationQuery{Query: rq},
+			},
+		}, true
+
+	case *querypb.Query_Conjunction:
+		qs, ok := rewriteQueries(a, inner.Conjunction.Queries)
+		if !ok {
+			return q, false
+		}
+		return &querypb.Query{
+			Query: &querypb.Query_Conjunction{
+				Conjunction: &querypb.ConjunctionQuery{Queries: qs},
+			},
+		}, true
+
+	case *querypb.Query_Disjunction:
+		qs, ok := rewriteQueries(a, inner.Disjunction.Queries)
+		if !ok {
+			return q, false
+		}
+		return &querypb.Query{
+			Query: &querypb.Query_Disjunction{
+				Disjunction: &querypb.DisjunctionQuery{Queries: qs},
+			},
+		}, true
+	}
+
+	if rq, ok := a.Rewrite(q); ok {
+		return rq, true
+	}
+	return q, false
+}
+
+func rewriteQueries(a Analyzer, queries []*querypb.Query) ([]*querypb.Query, bool) {
+	var (
+		qs        = make([]*querypb.Query, 0, len(queries))
+		rewritten bool
+	)
+	for _, q := range queries {
+		rq, ok := rewriteQuery(a, q)
+		rewritten = rewritten || ok
+		qs = append(qs, rq)
+	}
+	return qs, rewritten
+}
@@ -30,6 +30,9 @@
 	It has these top-level messages:
 		TermQuery
 		RegexpQuery
+		PrefixQuery
+		CaseInsensitiveTermQuery
+		NumericRangeQuery
 		NegationQuery
 		ConjunctionQuery
 		DisjunctionQuery
@@ -102,6 +105,102 @@ func (m *RegexpQuery) GetRegexp() []byte {
 	return nil
 }
 
+type PrefixQuery struct {
+	Field  []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Prefix []byte `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (m *PrefixQuery) Reset()                    { *m = PrefixQuery{} }
+func (m *PrefixQuery) String() string            { return proto.CompactTextString(m) }
+func (*PrefixQuery) ProtoMessage()               {}
+func (*PrefixQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{2} }
+
+func (m *PrefixQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *PrefixQuery) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+type CaseInsensitiveTermQuery struct {
+	Field []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Term  []byte `protobuf:"bytes,2,opt,name=term,proto3" json:"term,omitempty"`
+}
+
+func (m *CaseInsensitiveTermQuery) Reset()                    { *m = CaseInsensitiveTermQuery{} }
+func (m *CaseInsensitiveTermQuery) String() string            { return proto.CompactTextString(m) }
+func (*CaseInsensitiveTermQuery) ProtoMessage()               {}
+func (*CaseInsensitiveTermQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{3} }
+
+func (m *CaseInsensitiveTermQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *CaseInsensitiveTermQuery) GetTerm() []byte {
+	if m != nil {
+		return m.Term
+	}
+	return nil
+}
+
+type NumericRangeQuery struct {
+	Field        []byte  `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Min          float64 `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max          float64 `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	MinInclusive bool    `protobuf:"varint,4,opt,name=min_inclusive,json=minInclusive,proto3" json:"min_inclusive,omitempty"`
+	MaxInclusive bool    `protobuf:"varint,5,opt,name=max_inclusive,json=maxInclusive,proto3" json:"max_inclusive,omitempty"`
+}
+
+func (m *NumericRangeQuery) Reset()                    { *m = NumericRangeQuery{} }
+func (m *NumericRangeQuery) String() string            { return proto.CompactTextString(m) }
+func (*NumericRangeQuery) ProtoMessage()               {}
+func (*NumericRangeQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{4} }
+
+func (m *NumericRangeQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *NumericRangeQuery) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *NumericRangeQuery) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *NumericRangeQuery) GetMinInclusive() bool {
+	if m != nil {
+		return m.MinInclusive
+	}
+	return false
+}
+
+func (m *NumericRangeQuery) GetMaxInclusive() bool {
+	if m != nil {
+		return m.MaxInclusive
+	}
+	return false
+}
+
 type NegationQuery struct {
 	Query *Query `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
 }
@@ -109,7 +208,7 @@ type NegationQuery struct {
 func (m *NegationQuery) Reset()                    { *m = NegationQuery{} }
 func (m *NegationQuery) String() string            { return proto.CompactTextString(m) }
 func (*NegationQuery) ProtoMessage()               {}
-func (*NegationQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{2} }
+func (*NegationQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
 
 func (m *NegationQuery) GetQuery() *Query {
 	if m != nil {
@@ -125,7 +224,7 @@ type ConjunctionQuery struct {
 func (m *ConjunctionQuery) Reset()                    { *m = ConjunctionQuery{} }
 func (m *ConjunctionQuery) String() string            { return proto.CompactTextString(m) }
 func (*ConjunctionQuery) ProtoMessage()               {}
-func (*ConjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{3} }
+func (*ConjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{6} }
 
 func (m *ConjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -141,7 +240,7 @@ type DisjunctionQuery struct {
 func (m *DisjunctionQuery) Reset()                    { *m = DisjunctionQuery{} }
 func (m *DisjunctionQuery) String() string            { return proto.CompactTextString(m) }
 func (*DisjunctionQuery) ProtoMessage()               {}
-func (*DisjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{4} }
+func (*DisjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{7} }
 
 func (m *DisjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -157,13 +256,16 @@ type Query struct {
 	//	*Query_Negation
 	//	*Query_Conjunction
 	//	*Query_Disjunction
+	//	*Query_Prefix
+	//	*Query_CaseInsensitiveTerm
+	//	*Query_NumericRange
 	Query isQuery_Query `protobuf_oneof:"query"`
 }
 
 func (m *Query) Reset()                    { *m = Query{} }
 func (m *Query) String() string            { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()               {}
-func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{8} }
 
 type isQuery_Query interface {
 	isQuery_Query()
@@ -186,12 +288,24 @@ type Query_Conjunction struct {
 type Query_Disjunction struct {
 	Disjunction *DisjunctionQuery `protobuf:"bytes,5,opt,name=disjunction,oneof"`
 }
+type Query_Prefix struct {
+	Prefix *PrefixQuery `protobuf:"bytes,6,opt,name=prefix,oneof"`
+}
+type Query_CaseInsensitiveTerm struct {
+	CaseInsensitiveTerm *CaseInsensitiveTermQuery `protobuf:"bytes,7,opt,name=case_insensitive_term,json=caseInsensitiveTerm,oneof"`
+}
+type Query_NumericRange struct {
+	NumericRange *NumericRangeQuery `protobuf:"bytes,8,opt,name=numeric_range,json=numericRange,oneof"`
+}
 
-func (*Query_Term) isQuery_Query()        {}
-func (*Query_Regexp) isQuery_Query()      {}
-func (*Query_Negation) isQuery_Query()    {}
-func (*Query_Conjunction) isQuery_Query() {}
-func (*Query_Disjunction) isQuery_Query() {}
+func (*Query_Term) isQuery_Query()                {}
+func (*Query_Regexp) isQuery_Query()              {}
+func (*Query_Negation) isQuery_Query()            {}
+func (*Query_Conjunction) isQuery_Query()         {}
+func (*Query_Disjunction) isQuery_Query()         {}
+func (*Query_Prefix) isQuery_Query()              {}
+func (*Query_CaseInsensitiveTerm) isQuery_Query() {}
+func (*Query_NumericRange) isQuery_Query()        {}
 
 func (m *Query) GetQuery() isQuery_Query {
 	if m != nil {
@@ -235,6 +349,27 @@ func (m *Query) GetDisjunction() *DisjunctionQuery {
 	return nil
 }
 
+func (m *Query) GetPrefix() *PrefixQuery {
+	if x, ok := m.GetQuery().(*Query_Prefix); ok {
+		return x.Prefix
+	}
+	return nil
+}
+
+func (m *Query) GetCaseInsensitiveTerm() *CaseInsensitiveTermQuery {
+	if x, ok := m.GetQuery().(*Query_CaseInsensitiveTerm); ok {
+		return x.CaseInsensitiveTerm
+	}
+	return nil
+}
+
+func (m *Query) GetNumericRange() *NumericRangeQuery {
+	if x, ok := m.GetQuery().(*Query_NumericRange); ok {
+		return x.NumericRange
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Query_OneofMarshaler, _Query_OneofUnmarshaler, _Query_OneofSizer, []interface{}{
@@ -243,6 +378,9 @@ func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Query_Negation)(nil),
 		(*Query_Conjunction)(nil),
 		(*Query_Disjunction)(nil),
+		(*Query_Prefix)(nil),
+		(*Query_CaseInsensitiveTerm)(nil),
+		(*Query_NumericRange)(nil),
 	}
 }
 
@@ -275,6 +413,21 @@ func _Query_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Disjunction); err != nil {
 			return err
 		}
+	case *Query_Prefix:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Prefix); err != nil {
+			return err
+		}
+	case *Query_CaseInsensitiveTerm:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CaseInsensitiveTerm); err != nil {
+			return err
+		}
+	case *Query_NumericRange:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.NumericRange); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Query.Query has unexpected type %T", x)
@@ -325,6 +478,30 @@ func _Query_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Query = &Query_Disjunction{msg}
 		return true, err
+	case 6: // query.prefix
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PrefixQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Prefix{msg}
+		return true, err
+	case 7: // query.case_insensitive_term
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(CaseInsensitiveTermQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_CaseInsensitiveTerm{msg}
+		return true, err
+	case 8: // query.numeric_range
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(NumericRangeQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_NumericRange{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -359,6 +536,21 @@ func _Query_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Query_Prefix:
+		s := proto.Size(x.Prefix)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_CaseInsensitiveTerm:
+		s := proto.Size(x.CaseInsensitiveTerm)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_NumericRange:
+		s := proto.Size(x.NumericRange)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -369,6 +561,9 @@ func _Query_OneofSizer(msg proto.Message) (n int) {
 func init() {
 	proto.RegisterType((*TermQuery)(nil), "query.TermQuery")
 	proto.RegisterType((*RegexpQuery)(nil), "query.RegexpQuery")
+	proto.RegisterType((*PrefixQuery)(nil), "query.PrefixQuery")
+	proto.RegisterType((*CaseInsensitiveTermQuery)(nil), "query.CaseInsensitiveTermQuery")
+	proto.RegisterType((*NumericRangeQuery)(nil), "query.NumericRangeQuery")
 	proto.RegisterType((*NegationQuery)(nil), "query.NegationQuery")
 	proto.RegisterType((*ConjunctionQuery)(nil), "query.ConjunctionQuery")
 	proto.RegisterType((*DisjunctionQuery)(nil), "query.DisjunctionQuery")
@@ -434,6 +629,120 @@ func (m *RegexpQuery) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *PrefixQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrefixQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Prefix) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Prefix)))
+		i += copy(dAtA[i:], m.Prefix)
+	}
+	return i, nil
+}
+
+func (m *CaseInsensitiveTermQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CaseInsensitiveTermQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Term) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Term)))
+		i += copy(dAtA[i:], m.Term)
+	}
+	return i, nil
+}
+
+func (m *NumericRangeQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NumericRangeQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if m.Min != 0 {
+		dAtA[i] = 0x11
+		i++
+		i = encodeFixed64Query(dAtA, i, uint64(math.Float64bits(float64(m.Min))))
+	}
+	if m.Max != 0 {
+		dAtA[i] = 0x19
+		i++
+		i = encodeFixed64Query(dAtA, i, uint64(math.Float64bits(float64(m.Max))))
+	}
+	if m.MinInclusive {
+		dAtA[i] = 0x20
+		i++
+		if m.MinInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxInclusive {
+		dAtA[i] = 0x28
+		i++
+		if m.MaxInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
 func (m *NegationQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -617,6 +926,66 @@ func (m *Query_Disjunction) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
+func (m *Query_Prefix) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Prefix != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Prefix.Size()))
+		n8, err := m.Prefix.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+func (m *Query_CaseInsensitiveTerm) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CaseInsensitiveTerm != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.CaseInsensitiveTerm.Size()))
+		n9, err := m.CaseInsensitiveTerm.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	return i, nil
+}
+func (m *Query_NumericRange) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.NumericRange != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.NumericRange.Size()))
+		n10, err := m.NumericRange.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+func encodeFixed64Query(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Query(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
 func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -654,6 +1023,56 @@ func (m *RegexpQuery) Size() (n int) {
 	return n
 }
 
+func (m *PrefixQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *CaseInsensitiveTermQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Term)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *NumericRangeQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Min != 0 {
+		n += 9
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	if m.MinInclusive {
+		n += 2
+	}
+	if m.MaxInclusive {
+		n += 2
+	}
+	return n
+}
+
 func (m *NegationQuery) Size() (n int) {
 	var l int
 	_ = l
@@ -740,23 +1159,274 @@ func (m *Query_Disjunction) Size() (n int) {
 		l = m.Disjunction.Size()
 		n += 1 + l + sovQuery(uint64(l))
 	}
-	return n
-}
+	return n
+}
+func (m *Query_Prefix) Size() (n int) {
+	var l int
+	_ = l
+	if m.Prefix != nil {
+		l = m.Prefix.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_CaseInsensitiveTerm) Size() (n int) {
+	var l int
+	_ = l
+	if m.CaseInsensitiveTerm != nil {
+		l = m.CaseInsensitiveTerm.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_NumericRange) Size() (n int) {
+	var l int
+	_ = l
+	if m.NumericRange != nil {
+		l = m.NumericRange.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *TermQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
+			if m.Term == nil {
+				m.Term = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
+			if m.Regexp == nil {
+				m.Regexp = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func sovQuery(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozQuery(x uint64) (n int) {
-	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *TermQuery) Unmarshal(dAtA []byte) error {
+func (m *PrefixQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -779,10 +1449,10 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: PrefixQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PrefixQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -818,7 +1488,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -842,9 +1512,9 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
-			if m.Term == nil {
-				m.Term = []byte{}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -868,7 +1538,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+func (m *CaseInsensitiveTermQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -891,10 +1561,10 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: CaseInsensitiveTermQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CaseInsensitiveTermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -930,7 +1600,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -954,11 +1624,168 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
-			if m.Regexp == nil {
-				m.Regexp = []byte{}
+			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
+			if m.Term == nil {
+				m.Term = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NumericRangeQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NumericRangeQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NumericRangeQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 8
+			v = uint64(dAtA[iNdEx-8])
+			v |= uint64(dAtA[iNdEx-7]) << 8
+			v |= uint64(dAtA[iNdEx-6]) << 16
+			v |= uint64(dAtA[iNdEx-5]) << 24
+			v |= uint64(dAtA[iNdEx-4]) << 32
+			v |= uint64(dAtA[iNdEx-3]) << 40
+			v |= uint64(dAtA[iNdEx-2]) << 48
+			v |= uint64(dAtA[iNdEx-1]) << 56
+			m.Min = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 8
+			v = uint64(dAtA[iNdEx-8])
+			v |= uint64(dAtA[iNdEx-7]) << 8
+			v |= uint64(dAtA[iNdEx-6]) << 16
+			v |= uint64(dAtA[iNdEx-5]) << 24
+			v |= uint64(dAtA[iNdEx-4]) << 32
+			v |= uint64(dAtA[iNdEx-3]) << 40
+			v |= uint64(dAtA[iNdEx-2]) << 48
+			v |= uint64(dAtA[iNdEx-1]) << 56
+			m.Max = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MinInclusive = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxInclusive = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1414,6 +2241,102 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			m.Query = &Query_Disjunction{v}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &PrefixQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Prefix{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CaseInsensitiveTerm", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CaseInsensitiveTermQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_CaseInsensitiveTerm{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumericRange", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &NumericRangeQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_NumericRange{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1545,27 +2468,37 @@ func init() {
 }
 
 var fileDescriptorQuery = []byte{
-	// 343 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xbd, 0x4e, 0xc3, 0x30,
-	0x14, 0x85, 0xe3, 0xb6, 0x69, 0xe1, 0xa6, 0x48, 0x95, 0x55, 0x81, 0x59, 0xa2, 0x2a, 0x03, 0x62,
-	0x40, 0xb5, 0xd4, 0x88, 0x85, 0x4e, 0x14, 0x86, 0x4c, 0x48, 0x44, 0x4c, 0x6c, 0x4d, 0x62, 0x82,
-	0x11, 0x76, 0x8a, 0x9b, 0x4a, 0xe5, 0x2d, 0x18, 0x78, 0x28, 0x46, 0x1e, 0x01, 0x95, 0x17, 0x41,
-	0xb1, 0xdd, 0x5f, 0x24, 0x06, 0xa6, 0xe4, 0xfa, 0x9e, 0x4f, 0x3a, 0xf9, 0x62, 0xb8, 0xcc, 0x79,
-	0xf9, 0x38, 0x4b, 0xfa, 0x69, 0x21, 0xa8, 0x08, 0xb3, 0x84, 0x8a, 0x90, 0x4e, 0x55, 0x4a, 0x45,
-	0x28, 0xb9, 0x9c, 0xd3, 0x9c, 0x49, 0xa6, 0xc6, 0x25, 0xcb, 0xe8, 0x44, 0x15, 0x65, 0x41, 0x5f,
-	0x66, 0x4c, 0xbd, 0x4e, 0x12, 0xf3, 0xec, 0xeb, 0x33, 0xec, 0xea, 0x21, 0x38, 0x87, 0xfd, 0x3b,
-	0xa6, 0xc4, 0x6d, 0x35, 0xe0, 0x2e, 0xb8, 0x0f, 0x9c, 0x3d, 0x67, 0x04, 0xf5, 0xd0, 0x69, 0x3b,
-	0x36, 0x03, 0xc6, 0xd0, 0x28, 0x99, 0x12, 0xa4, 0xa6, 0x0f, 0xf5, 0x7b, 0x30, 0x04, 0x2f, 0x66,
-	0x39, 0x9b, 0x4f, 0xfe, 0x02, 0x0f, 0xa1, 0xa9, 0x74, 0xc8, 0xa2, 0x76, 0x0a, 0x42, 0x38, 0xb8,
-	0x61, 0xf9, 0xb8, 0xe4, 0x85, 0x34, 0x78, 0x00, 0xa6, 0x8d, 0xc6, 0xbd, 0x41, 0xbb, 0x6f, 0x8a,
-	0xea, 0x65, 0x6c, 0x8b, 0x5e, 0x40, 0xe7, 0xaa, 0x90, 0x4f, 0x33, 0x99, 0xae, 0xb9, 0x13, 0x68,
-	0x55, 0x4b, 0xce, 0xa6, 0x04, 0xf5, 0xea, 0xbf, 0xc8, 0xe5, 0xb2, 0x62, 0xaf, 0xf9, 0xf4, 0x7f,
-	0xec, 0x7b, 0x0d, 0xdc, 0x25, 0x61, 0x3c, 0x98, 0x92, 0x1d, 0x1b, 0x5f, 0xd9, 0x8b, 0x1c, 0xe3,
-	0x06, 0x9f, 0x6d, 0x7d, 0xb6, 0x37, 0xc0, 0x36, 0xb9, 0x21, 0x2c, 0x72, 0x96, 0x32, 0xf0, 0x00,
-	0xf6, 0xa4, 0x95, 0x41, 0xea, 0x3a, 0xdf, 0xb5, 0xf9, 0x2d, 0x47, 0x91, 0x13, 0xaf, 0x72, 0x78,
-	0x08, 0x5e, 0xba, 0x76, 0x41, 0x1a, 0x1a, 0x3b, 0xb2, 0xd8, 0xae, 0xa5, 0xc8, 0x89, 0x37, 0xd3,
-	0x15, 0x9c, 0xad, 0x65, 0x10, 0x77, 0x0b, 0xde, 0xd5, 0x54, 0xc1, 0x1b, 0xe9, 0x51, 0xcb, 0xfe,
-	0xa9, 0xd1, 0xf1, 0xc7, 0xc2, 0x47, 0x9f, 0x0b, 0x1f, 0x7d, 0x2d, 0x7c, 0xf4, 0xf6, 0xed, 0x3b,
-	0xf7, 0x2d, 0x7b, 0xcb, 0x92, 0xa6, 0xbe, 0x60, 0xe1, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x87,
-	0xea, 0x95, 0x8a, 0xa5, 0x02, 0x00, 0x00,
+	// 504 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9d, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xad, 0x49, 0xf3, 0xc1, 0x38, 0x91, 0xc2, 0xb6, 0x80, 0xb9, 0x14, 0x64, 0x24, 0xc4, 0x01,
+	0xc5, 0x92, 0xa3, 0x5e, 0xe0, 0x80, 0x68, 0x7b, 0xa0, 0x97, 0x0a, 0x2c, 0xb8, 0x70, 0x89, 0x1c,
+	0x67, 0x6b, 0xb6, 0xaa, 0xd7, 0x61, 0x6d, 0x23, 0xf3, 0x2f, 0xb8, 0xf1, 0x97, 0x38, 0x72, 0xe6,
+	0x84, 0xe0, 0x8f, 0x30, 0x3b, 0xbb, 0x4e, 0x9c, 0x54, 0x14, 0xa9, 0x87, 0xb5, 0x77, 0x66, 0xde,
+	0x5b, 0xcd, 0xbe, 0x79, 0x5a, 0x78, 0x95, 0x8a, 0xf2, 0x63, 0x35, 0x9f, 0x24, 0x79, 0x16, 0x64,
+	0xd3, 0xc5, 0x1c, 0x3f, 0x41, 0xa1, 0x12, 0xfc, 0x49, 0x21, 0xeb, 0x20, 0xe5, 0x92, 0xab, 0xb8,
+	0xe4, 0x8b, 0x60, 0xa9, 0xf2, 0x32, 0x0f, 0x3e, 0x55, 0x5c, 0x7d, 0x59, 0xce, 0xcd, 0x7f, 0x42,
+	0x39, 0xd6, 0xa5, 0xc0, 0x3f, 0x84, 0xdb, 0xef, 0xb8, 0xca, 0xde, 0xea, 0x80, 0xed, 0x43, 0xf7,
+	0x5c, 0xf0, 0xcb, 0x85, 0xe7, 0x3c, 0x72, 0x9e, 0x0e, 0x23, 0x13, 0x30, 0x06, 0xbb, 0x25, 0x42,
+	0xbc, 0x5b, 0x94, 0xa4, 0xbd, 0xff, 0x02, 0xdc, 0x88, 0xa7, 0xbc, 0x5e, 0x5e, 0x47, 0xbc, 0x07,
+	0x3d, 0x45, 0x20, 0x4b, 0xb5, 0x91, 0x26, 0xbf, 0x51, 0xfc, 0x5c, 0xd4, 0xff, 0x21, 0x2f, 0x09,
+	0xd4, 0x90, 0x4d, 0xe4, 0x9f, 0x80, 0x77, 0x1c, 0x17, 0xfc, 0x54, 0x16, 0x5c, 0x16, 0xa2, 0x14,
+	0x9f, 0xf9, 0x4d, 0xfa, 0xff, 0xe6, 0xc0, 0x9d, 0xb3, 0x2a, 0xe3, 0x4a, 0x24, 0x51, 0x2c, 0x53,
+	0x7e, 0x1d, 0x7f, 0x0c, 0x9d, 0x4c, 0x48, 0xa2, 0x3b, 0x91, 0xde, 0x52, 0x26, 0xae, 0xbd, 0x8e,
+	0xcd, 0xc4, 0x35, 0x7b, 0x0c, 0x23, 0x2c, 0xcc, 0x84, 0x4c, 0x2e, 0xab, 0x02, 0x7b, 0xf2, 0x76,
+	0xb1, 0x36, 0x88, 0x86, 0x98, 0x3c, 0x6d, 0x72, 0x04, 0x8a, 0xeb, 0x16, 0xa8, 0x6b, 0x41, 0x71,
+	0xbd, 0x02, 0xf9, 0x53, 0x18, 0x9d, 0xf1, 0x34, 0x2e, 0x45, 0x2e, 0x4d, 0x53, 0x3e, 0x98, 0x51,
+	0x51, 0x53, 0x6e, 0x38, 0x9c, 0x98, 0x29, 0x52, 0x31, 0xb2, 0x53, 0x7c, 0x0e, 0xe3, 0xe3, 0x5c,
+	0x5e, 0x54, 0x32, 0x59, 0xf3, 0x9e, 0x40, 0x5f, 0x17, 0x05, 0x2f, 0x90, 0xd9, 0xb9, 0xc2, 0x6c,
+	0x8a, 0x9a, 0x7b, 0x22, 0x8a, 0x9b, 0x71, 0x7f, 0x76, 0xa0, 0xdb, 0x30, 0x8c, 0xc8, 0xa6, 0xc9,
+	0xb1, 0x85, 0xaf, 0x46, 0xf3, 0x7a, 0xc7, 0x08, 0xcf, 0x9e, 0x6d, 0x78, 0xc2, 0x0d, 0x99, 0x45,
+	0xb6, 0xdc, 0x84, 0x58, 0x8b, 0x61, 0x21, 0x0c, 0xa4, 0x15, 0x83, 0xd4, 0x76, 0xc3, 0x7d, 0x8b,
+	0xdf, 0xd0, 0x08, 0x19, 0x2b, 0x1c, 0x43, 0x77, 0x25, 0x6b, 0x2d, 0x68, 0x10, 0x6e, 0x78, 0xdf,
+	0xd2, 0xb6, 0x55, 0x42, 0x66, 0x1b, 0xad, 0xc9, 0x8b, 0xb5, 0x18, 0x34, 0xa0, 0x35, 0x79, 0x5b,
+	0x26, 0x4d, 0x6e, 0xa1, 0xf5, 0xdd, 0xac, 0x65, 0x7b, 0x1b, 0x77, 0x6b, 0x99, 0x5d, 0xdf, 0xcd,
+	0x60, 0xd8, 0x7b, 0xb8, 0x9b, 0xa0, 0x91, 0xd1, 0x0e, 0x2b, 0x27, 0xcf, 0x48, 0xc2, 0x3e, 0x91,
+	0x1f, 0x36, 0x1d, 0xff, 0xc3, 0xec, 0x78, 0xd2, 0x5e, 0x72, 0xb5, 0xc6, 0x5e, 0xc2, 0x48, 0x1a,
+	0x63, 0xcf, 0x94, 0x76, 0xb6, 0x37, 0xa0, 0xe3, 0xbc, 0x46, 0xb7, 0x6d, 0xd3, 0xe3, 0x39, 0x43,
+	0xd9, 0x4a, 0x1e, 0xf5, 0xad, 0xdf, 0x8e, 0x1e, 0x7c, 0xff, 0x7d, 0xe0, 0xfc, 0xc0, 0xf5, 0x0b,
+	0xd7, 0xd7, 0x3f, 0x07, 0x3b, 0x1f, 0xfa, 0xf6, 0x21, 0x99, 0xf7, 0xe8, 0x0d, 0x99, 0xfe, 0x05,
+	0xac, 0xe3, 0xb1, 0x9a, 0x88, 0x04, 0x00, 0x00,
 }
@@ -21,6 +21,7 @@
 package idx
 
 import (
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/m3ninx/search/query"
 )
@@ -63,6 +64,30 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term for the
+// given field beginning with the prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewCaseInsensitiveTermQuery returns a new query for finding documents which match a
+// term ignoring the case of ASCII letters.
+func NewCaseInsensitiveTermQuery(field, term []byte) Query {
+	return Query{
+		query: query.NewCaseInsensitiveTermQuery(field, term),
+	}
+}
+
+// NewNumericRangeQuery returns a new query for finding documents which have a term for
+// the given field that is a number within the range.
+func NewNumericRangeQuery(field []byte, r index.NumericRange) Query {
+	return Query{
+		query: query.NewNumericRangeQuery(field, r),
+	}
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
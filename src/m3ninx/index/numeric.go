@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// NumericRange is a range of numeric values which the terms of a field can be
+// matched against. Unbounded ends of the range are represented by infinities.
+type NumericRange struct {
+	Min          float64
+	Max          float64
+	MinInclusive bool
+	MaxInclusive bool
+}
+
+// NewNumericRange returns an unbounded NumericRange, use the With methods to
+// restrict it.
+func NewNumericRange() NumericRange {
+	return NumericRange{
+		Min: math.Inf(-1),
+		Max: math.Inf(1),
+	}
+}
+
+// WithMin returns a copy of the range with the given lower bound.
+func (r NumericRange) WithMin(min float64, inclusive bool) NumericRange {
+	r.Min = min
+	r.MinInclusive = inclusive
+	return r
+}
+
+// WithMax returns a copy of the range with the given upper bound.
+func (r NumericRange) WithMax(max float64, inclusive bool) NumericRange {
+	r.Max = max
+	r.MaxInclusive = inclusive
+	return r
+}
+
+// Contains returns whether the value lies within the range.
+func (r NumericRange) Contains(v float64) bool {
+	if v < r.Min || (v == r.Min && !r.MinInclusive) {
+		return false
+	}
+	if v > r.Max || (v == r.Max && !r.MaxInclusive) {
+		return false
+	}
+	return true
+}
+
+// TermRange returns the range of terms encoded by EncodeNumericTerm which hold
+// numbers within the range, start is inclusive and end is exclusive.
+func (r NumericRange) TermRange() (start, end []byte) {
+	start = EncodeNumericTerm(r.Min)
+	if !r.MinInclusive {
+		// NB: encoded terms are all the same length so the successor of a term
+		// is the term with a zero byte appended.
+		start = append(start, 0)
+	}
+	end = EncodeNumericTerm(r.Max)
+	if r.MaxInclusive {
+		end = append(end, 0)
+	}
+	return start, end
+}
+
+// String returns the range in interval notation, e.g. [500, +Inf).
+func (r NumericRange) String() string {
+	lower, upper := "(", ")"
+	if r.MinInclusive {
+		lower = "["
+	}
+	if r.MaxInclusive {
+		upper = "]"
+	}
+	return lower + formatNumeric(r.Min) + ", " + formatNumeric(r.Max) + upper
+}
+
+// ParseNumericTerm parses a term as a number, returning false if the term is
+// not a number.
+func ParseNumericTerm(term []byte) (float64, bool) {
+	if len(term) == 0 {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(string(term), 64)
+	if err != nil || math.IsNaN(v) {
+		return 0, false
+	}
+	return v, true
+}
+
+// EncodeNumericTerm returns the term a number is indexed by, the terms of
+// numbers sort in the same order as the numbers themselves so that a range of
+// numbers can be matched by iterating over a range of terms.
+func EncodeNumericTerm(v float64) []byte {
+	if v == 0 {
+		// Negative zero is indexed as zero.
+		v = 0
+	}
+	bits := math.Float64bits(v)
+	if bits&(1<<63) == 0 {
+		bits |= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	term := make([]byte, 8)
+	binary.BigEndian.PutUint64(term, bits)
+	return term
+}
+
+func formatNumeric(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericRangeContains(t *testing.T) {
+	r := NewNumericRange().WithMin(500, true).WithMax(600, false)
+	require.False(t, r.Contains(499.9))
+	require.True(t, r.Contains(500))
+	require.True(t, r.Contains(599))
+	require.False(t, r.Contains(600))
+
+	unbounded := NewNumericRange()
+	require.True(t, unbounded.Contains(-math.MaxFloat64))
+	require.True(t, unbounded.Contains(math.MaxFloat64))
+}
+
+func TestEncodeNumericTermOrder(t *testing.T) {
+	values := []float64{math.Inf(-1), -math.MaxFloat64, -1e3, -1, -0.5,
+		math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 0.5, 1, 1e3,
+		math.MaxFloat64, math.Inf(1)}
+	for i := 1; i < len(values); i++ {
+		cmp := bytes.Compare(EncodeNumericTerm(values[i-1]), EncodeNumericTerm(values[i]))
+		if values[i-1] == values[i] {
+			require.Equal(t, 0, cmp, "%v = %v", values[i-1], values[i])
+			continue
+		}
+		require.Equal(t, -1, cmp, "%v < %v", values[i-1], values[i])
+	}
+}
+
+func TestNumericRangeTermRange(t *testing.T) {
+	r := NewNumericRange().WithMin(500, true).WithMax(600, false)
+	start, end := r.TermRange()
+	for _, v := range []float64{-1, 499.9, 500, 599, 600, 1e3} {
+		term := EncodeNumericTerm(v)
+		contains := bytes.Compare(term, start) >= 0 && bytes.Compare(term, end) < 0
+		require.Equal(t, r.Contains(v), contains, "%v", v)
+	}
+
+	r = NewNumericRange().WithMin(500, false).WithMax(600, true)
+	start, end = r.TermRange()
+	for _, v := range []float64{499.9, 500, 599, 600, 600.1} {
+		term := EncodeNumericTerm(v)
+		contains := bytes.Compare(term, start) >= 0 && bytes.Compare(term, end) < 0
+		require.Equal(t, r.Contains(v), contains, "%v", v)
+	}
+}
+
+func TestNumericRangeString(t *testing.T) {
+	require.Equal(t, "[500, +Inf)", NewNumericRange().WithMin(500, true).String())
+	require.Equal(t, "(-Inf, 1.5]", NewNumericRange().WithMax(1.5, true).String())
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fst
+
+import (
+	"github.com/m3db/m3/src/m3ninx/index"
+
+	"github.com/couchbase/vellum"
+)
+
+// prefixSuccessor returns the smallest key greater than every key beginning
+// with the prefix, or nil if there is no such key.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			end := make([]byte, i+1)
+			copy(end, prefix)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
+
+const caseInsensitiveDeadState = 0
+
+// caseInsensitiveAutomaton is a vellum automaton which matches a term
+// ignoring the case of ASCII letters. State n+1 represents having matched
+// the first n bytes of the term and state zero is the dead state.
+type caseInsensitiveAutomaton struct {
+	term []byte
+}
+
+var _ vellum.Automaton = caseInsensitiveAutomaton{}
+
+func newCaseInsensitiveAutomaton(term []byte) caseInsensitiveAutomaton {
+	return caseInsensitiveAutomaton{term: term}
+}
+
+func (a caseInsensitiveAutomaton) Start() int {
+	return 1
+}
+
+func (a caseInsensitiveAutomaton) IsMatch(state int) bool {
+	return state == len(a.term)+1
+}
+
+func (a caseInsensitiveAutomaton) CanMatch(state int) bool {
+	return state != caseInsensitiveDeadState
+}
+
+func (a caseInsensitiveAutomaton) WillAlwaysMatch(int) bool {
+	return false
+}
+
+func (a caseInsensitiveAutomaton) Accept(state int, b byte) int {
+	if state == caseInsensitiveDeadState || state > len(a.term) {
+		return caseInsensitiveDeadState
+	}
+	if index.ToLowerASCII(b) != index.ToLowerASCII(a.term[state-1]) {
+		return caseInsensitiveDeadState
+	}
+	return state + 1
+}
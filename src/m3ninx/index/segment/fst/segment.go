@@ -21,11 +21,13 @@
 package fst
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"sync"
 
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/generated/proto/fswriter"
 	"github.com/m3db/m3/src/m3ninx/index"
@@ -327,16 +329,145 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled index.Comp
 		return r.opts.PostingsListPool().Get(), nil
 	}
 
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	iter, iterErr := termsFST.Search(re, minByteKey, nil)
+	pl, err := r.unionPostingsListsWithRLock(iter, iterErr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+func (r *fsSegment) MatchPrefix(field []byte, prefix []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	// NB: The terms FST is ordered so only the range of terms beginning with
+	// the prefix needs to be visited.
+	start := prefix
+	if len(start) == 0 {
+		start = minByteKey
+	}
+	iter, iterErr := termsFST.Iterator(start, prefixSuccessor(prefix))
+	pl, err := r.unionPostingsListsWithRLock(iter, iterErr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+func (r *fsSegment) MatchTermCaseInsensitive(field []byte, term []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	iter, iterErr := termsFST.Search(newCaseInsensitiveAutomaton(term), minByteKey, nil)
+	pl, err := r.unionPostingsListsWithRLock(iter, iterErr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+func (r *fsSegment) MatchNumericRange(field []byte, nr index.NumericRange) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	start, end := nr.TermRange()
+	if bytes.Compare(start, end) >= 0 {
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(analysis.NumericFieldName(field))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	// NB: numeric terms sort in the same order as the numbers they encode so
+	// only the terms within the range are visited.
+	iter, iterErr := termsFST.Iterator(start, end)
+	pl, err := r.unionPostingsListsWithRLock(iter, iterErr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+// unionPostingsListsWithRLock returns the union of the postings lists of the
+// terms visited by the iterator, closing the iterator once done.
+func (r *fsSegment) unionPostingsListsWithRLock(
+	iter *vellum.FSTIterator,
+	iterErr error,
+) (postings.List, error) {
 	var (
-		fstCloser     = x.NewSafeCloser(termsFST)
-		pl            = r.opts.PostingsListPool().Get()
-		iter, iterErr = termsFST.Search(re, minByteKey, nil)
-		iterCloser    = x.NewSafeCloser(iter)
+		pl         = r.opts.PostingsListPool().Get()
+		iterCloser = x.NewSafeCloser(iter)
 	)
-	defer func() {
-		iterCloser.Close()
-		fstCloser.Close()
-	}()
+	defer iterCloser.Close()
 
 	for {
 		if iterErr == vellum.ErrIteratorDone {
@@ -363,10 +494,6 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled index.Comp
 		return nil, err
 	}
 
-	if err := fstCloser.Close(); err != nil {
-		return nil, err
-	}
-
 	return pl, nil
 }
 
@@ -526,6 +653,33 @@ func (sr *fsSegmentReader) MatchRegexp(field []byte, regexp []byte, compiled ind
 	return sr.fsSegment.MatchRegexp(field, regexp, compiled)
 }
 
+func (sr *fsSegmentReader) MatchPrefix(field []byte, prefix []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.MatchPrefix(field, prefix)
+}
+
+func (sr *fsSegmentReader) MatchTermCaseInsensitive(field []byte, term []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.MatchTermCaseInsensitive(field, term)
+}
+
+func (sr *fsSegmentReader) MatchNumericRange(field []byte, nr index.NumericRange) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.MatchNumericRange(field, nr)
+}
+
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	sr.RLock()
 	defer sr.RUnlock()
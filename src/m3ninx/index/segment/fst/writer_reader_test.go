@@ -256,6 +256,154 @@ func TestPostingsListEqualForMatchTerm(t *testing.T) {
 	}
 }
 
+func TestPostingsListEqualForMatchPrefixAndCaseInsensitiveTerm(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			memFieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			memFields := toSlice(t, memFieldsIter)
+
+			for _, f := range memFields {
+				memTermsIter, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				memTerms := toSlice(t, memTermsIter)
+
+				for _, term := range memTerms {
+					prefix := term[:len(term)/2]
+					memPl, err := memReader.MatchPrefix(f, prefix)
+					require.NoError(t, err)
+					fstPl, err := fstReader.MatchPrefix(f, prefix)
+					require.NoError(t, err)
+					require.True(t, memPl.Equal(fstPl),
+						fmt.Sprintf("%s:%s* - [%v] != [%v]", string(f), string(prefix), pprintIter(memPl), pprintIter(fstPl)))
+
+					upper := bytes.ToUpper(term)
+					memPl, err = memReader.MatchTermCaseInsensitive(f, upper)
+					require.NoError(t, err)
+					fstPl, err = fstReader.MatchTermCaseInsensitive(f, upper)
+					require.NoError(t, err)
+					require.True(t, memPl.Equal(fstPl),
+						fmt.Sprintf("%s:~%s - [%v] != [%v]", string(f), string(upper), pprintIter(memPl), pprintIter(fstPl)))
+
+					termPl, err := memReader.MatchTerm(f, term)
+					require.NoError(t, err)
+					require.True(t, termPl.Len() <= memPl.Len())
+				}
+			}
+		})
+	}
+}
+
+func TestMatchPrefixAndCaseInsensitiveTerm(t *testing.T) {
+	_, fstSeg := newTestSegments(t, fewTestDocuments)
+	reader, err := fstSeg.Reader()
+	require.NoError(t, err)
+
+	pl, err := reader.MatchPrefix([]byte("fruit"), []byte("app"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	require.True(t, pl.Contains(1))
+
+	pl, err = reader.MatchPrefix([]byte("color"), nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, pl.Len())
+
+	pl, err = reader.MatchTermCaseInsensitive([]byte("fruit"), []byte("PineApple"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	require.True(t, pl.Contains(2))
+
+	pl, err = reader.MatchTermCaseInsensitive([]byte("fruit"), []byte("appl"))
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
+func TestMatchNumericRange(t *testing.T) {
+	var docs []doc.Document
+	for _, code := range []string{"200", "404", "500", "503", "5e2", "unknown", "-1"} {
+		docs = append(docs, doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("status_code"),
+					Value: []byte(code),
+				},
+			},
+		})
+	}
+	opts := mem.NewOptions().SetNumericFields([]string{"status_code"})
+	memSeg, err := mem.NewSegment(postings.ID(0), opts)
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	fstSeg := newFSTSegment(t, memSeg)
+
+	tests := []struct {
+		name     string
+		rng      index.NumericRange
+		expected []postings.ID
+	}{
+		{
+			name:     "min inclusive",
+			rng:      index.NewNumericRange().WithMin(500, true),
+			expected: []postings.ID{2, 3, 4},
+		},
+		{
+			name:     "min exclusive",
+			rng:      index.NewNumericRange().WithMin(500, false),
+			expected: []postings.ID{3},
+		},
+		{
+			name:     "bounded",
+			rng:      index.NewNumericRange().WithMin(200, false).WithMax(500, false),
+			expected: []postings.ID{1},
+		},
+		{
+			name:     "max inclusive",
+			rng:      index.NewNumericRange().WithMax(200, true),
+			expected: []postings.ID{0, 6},
+		},
+		{
+			name:     "empty",
+			rng:      index.NewNumericRange().WithMin(500, false).WithMax(500, false),
+			expected: nil,
+		},
+		{
+			name:     "unbounded",
+			rng:      index.NewNumericRange(),
+			expected: []postings.ID{0, 1, 2, 3, 4, 6},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, seg := range []sgmt.Segment{memSeg, fstSeg} {
+				reader, err := seg.Reader()
+				require.NoError(t, err)
+
+				pl, err := reader.MatchNumericRange([]byte("status_code"), test.rng)
+				require.NoError(t, err)
+				require.Equal(t, len(test.expected), pl.Len(), pprintIter(pl))
+				for _, id := range test.expected {
+					require.True(t, pl.Contains(id))
+				}
+
+				pl, err = reader.MatchNumericRange([]byte("unknown_field"), test.rng)
+				require.NoError(t, err)
+				require.True(t, pl.IsEmpty())
+				require.NoError(t, reader.Close())
+			}
+		})
+	}
+}
+
 func TestPostingsListContainsID(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
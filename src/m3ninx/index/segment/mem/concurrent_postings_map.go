@@ -21,9 +21,11 @@
 package mem
 
 import (
+	"bytes"
 	"regexp"
 	"sync"
 
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
 
@@ -102,14 +104,42 @@ func (m *concurrentPostingsMap) Get(key []byte) (postings.List, bool) {
 // GetRegex returns the union of the postings lists whose keys match the
 // provided regexp.
 func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool) {
+	// TODO: Evaluate if performing a prefix match would speed up the common case.
+	return m.getMatching(re.Match)
+}
+
+// GetPrefix returns the union of the postings lists whose keys begin with the
+// provided prefix.
+func (m *concurrentPostingsMap) GetPrefix(prefix []byte) (postings.List, bool) {
+	return m.getMatching(func(key []byte) bool {
+		return bytes.HasPrefix(key, prefix)
+	})
+}
+
+// GetCaseInsensitive returns the union of the postings lists whose keys are
+// equal to the provided key ignoring the case of ASCII letters.
+func (m *concurrentPostingsMap) GetCaseInsensitive(key []byte) (postings.List, bool) {
+	return m.getMatching(func(k []byte) bool {
+		return index.EqualFoldASCII(k, key)
+	})
+}
+
+// GetRange returns the union of the postings lists whose keys are within the
+// provided range, start is inclusive and end is exclusive.
+func (m *concurrentPostingsMap) GetRange(start, end []byte) (postings.List, bool) {
+	return m.getMatching(func(key []byte) bool {
+		return bytes.Compare(key, start) >= 0 && bytes.Compare(key, end) < 0
+	})
+}
+
+func (m *concurrentPostingsMap) getMatching(match func(key []byte) bool) (postings.List, bool) {
 	var pl postings.MutableList
 
 	m.RLock()
 	for _, mapEntry := range m.postingsMap.Iter() {
 		// TODO: Evaluate lock contention caused by holding on to the read lock while
 		// evaluating this predicate.
-		// TODO: Evaluate if performing a prefix match would speed up the common case.
-		if re.Match(mapEntry.Key()) {
+		if match(mapEntry.Key()) {
 			if pl == nil {
 				pl = mapEntry.Value().Clone()
 			} else {
@@ -21,6 +21,7 @@
 package mem
 
 import (
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/index/util"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
@@ -66,6 +67,22 @@ type Options interface {
 
 	// NewUUIDFn returns the function used to generate new UUIDs.
 	NewUUIDFn() util.NewUUIDFn
+
+	// SetAnalyzer sets the analyzer that derives additional terms to index
+	// field values by, if nil values are only indexed exactly.
+	SetAnalyzer(value analysis.Analyzer) Options
+
+	// Analyzer returns the analyzer that derives additional terms to index
+	// field values by, if nil values are only indexed exactly.
+	Analyzer() analysis.Analyzer
+
+	// SetNumericFields sets the fields whose values are also indexed as
+	// numbers, allowing them to be matched by numeric range queries.
+	SetNumericFields(value []string) Options
+
+	// NumericFields returns the fields whose values are also indexed as
+	// numbers, allowing them to be matched by numeric range queries.
+	NumericFields() []string
 }
 
 type opts struct {
@@ -74,6 +91,8 @@ type opts struct {
 	postingsPool      postings.Pool
 	initialCapacity   int
 	newUUIDFn         util.NewUUIDFn
+	analyzer          analysis.Analyzer
+	numericFields     []string
 }
 
 // NewOptions returns new options.
@@ -141,3 +160,23 @@ func (o *opts) SetNewUUIDFn(v util.NewUUIDFn) Options {
 func (o *opts) NewUUIDFn() util.NewUUIDFn {
 	return o.newUUIDFn
 }
+
+func (o *opts) SetAnalyzer(v analysis.Analyzer) Options {
+	opts := *o
+	opts.analyzer = v
+	return &opts
+}
+
+func (o *opts) Analyzer() analysis.Analyzer {
+	return o.analyzer
+}
+
+func (o *opts) SetNumericFields(v []string) Options {
+	opts := *o
+	opts.numericFields = v
+	return &opts
+}
+
+func (o *opts) NumericFields() []string {
+	return o.numericFields
+}
@@ -87,6 +87,42 @@ func (r *reader) MatchRegexp(field, regexp []byte, compiled index.CompiledRegex)
 	return pl, err
 }
 
+func (r *reader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// NB: As with MatchTerm the postings list can contain IDs greater than the
+	// reader's limit, these are filtered out when fetching the documents.
+	return r.segment.matchPrefix(field, prefix)
+}
+
+func (r *reader) MatchTermCaseInsensitive(field, term []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// NB: As with MatchTerm the postings list can contain IDs greater than the
+	// reader's limit, these are filtered out when fetching the documents.
+	return r.segment.matchTermCaseInsensitive(field, term)
+}
+
+func (r *reader) MatchNumericRange(field []byte, nr index.NumericRange) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// NB: As with MatchTerm the postings list can contain IDs greater than the
+	// reader's limit, these are filtered out when fetching the documents.
+	return r.segment.matchNumericRange(field, nr)
+}
+
 func (r *reader) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -25,6 +25,7 @@ import (
 	re "regexp"
 	"sync"
 
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
@@ -42,6 +43,9 @@ type segment struct {
 	offset    int
 	plPool    postings.Pool
 	newUUIDFn util.NewUUIDFn
+	analyzer  analysis.Analyzer
+	// Set of the fields whose values are also indexed as numbers.
+	numericFields map[string]struct{}
 
 	state struct {
 		sync.RWMutex
@@ -73,10 +77,18 @@ func NewSegment(offset postings.ID, opts Options) (sgmt.MutableSegment, error) {
 		offset:    int(offset),
 		plPool:    opts.PostingsListPool(),
 		newUUIDFn: opts.NewUUIDFn(),
+		analyzer:  opts.Analyzer(),
 		termsDict: newTermsDict(opts),
 		readerID:  postings.NewAtomicID(offset),
 	}
 
+	if fields := opts.NumericFields(); len(fields) > 0 {
+		s.numericFields = make(map[string]struct{}, len(fields))
+		for _, field := range fields {
+			s.numericFields[field] = struct{}{}
+		}
+	}
+
 	s.docs.data = make([]doc.Document, opts.InitialCapacity())
 
 	s.writer.idSet = newIDsMap(256)
@@ -251,6 +263,25 @@ func (s *segment) insertDocWithLocks(d doc.Document) {
 func (s *segment) indexDocWithStateLock(id postings.ID, d doc.Document) error {
 	for _, f := range d.Fields {
 		s.termsDict.Insert(f, id)
+		if _, ok := s.numericFields[string(f.Name)]; ok {
+			if v, ok := index.ParseNumericTerm(f.Value); ok {
+				s.termsDict.Insert(doc.Field{
+					Name:  analysis.NumericFieldName(f.Name),
+					Value: index.EncodeNumericTerm(v),
+				}, id)
+			}
+		}
+		if s.analyzer == nil {
+			continue
+		}
+		terms := s.analyzer.Terms(f.Value)
+		if len(terms) == 0 {
+			continue
+		}
+		name := analysis.FieldName(s.analyzer, f.Name)
+		for _, term := range terms {
+			s.termsDict.Insert(doc.Field{Name: name, Value: term}, id)
+		}
 	}
 	s.termsDict.Insert(doc.Field{
 		Name:  doc.IDReservedFieldName,
@@ -340,6 +371,36 @@ func (s *segment) matchRegexp(name, regexp []byte, compiled *re.Regexp) (posting
 	return s.termsDict.MatchRegexp(name, regexp, compiled), nil
 }
 
+func (s *segment) matchPrefix(field, prefix []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchPrefix(field, prefix), nil
+}
+
+func (s *segment) matchTermCaseInsensitive(field, term []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchTermCaseInsensitive(field, term), nil
+}
+
+func (s *segment) matchNumericRange(field []byte, r index.NumericRange) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchNumericRange(field, r), nil
+}
+
 func (s *segment) getDoc(id postings.ID) (doc.Document, error) {
 	s.state.RLock()
 	defer s.state.RUnlock()
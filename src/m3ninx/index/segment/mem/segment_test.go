@@ -24,6 +24,7 @@ import (
 	re "regexp"
 	"testing"
 
+	"github.com/m3db/m3/src/m3ninx/analysis"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
 
@@ -857,3 +858,38 @@ func compareDocs(expected, actual doc.Document) bool {
 	}
 	return expected.Equal(actual)
 }
+
+func TestSegmentAnalyzer(t *testing.T) {
+	analyzer, err := analysis.NewNGramAnalyzer(3)
+	require.NoError(t, err)
+
+	segment, err := NewSegment(0, testOptions.SetAnalyzer(analyzer))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err = segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	_, err = segment.Seal()
+	require.NoError(t, err)
+
+	r, err := segment.Reader()
+	require.NoError(t, err)
+
+	field := analysis.FieldName(analyzer, []byte("fruit"))
+	pl, err := r.MatchTerm(field, []byte("nan"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	// the stored documents are not modified by the analyzer.
+	iter, err := r.Docs(pl)
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	require.True(t, compareDocs(testDocuments[0], iter.Current()))
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Close())
+
+	require.NoError(t, r.Close())
+	require.NoError(t, segment.Close())
+}
@@ -24,6 +24,7 @@ import (
 	re "regexp"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
@@ -45,6 +46,18 @@ type termsDictionary interface {
 	// given egular expression.
 	MatchRegexp(field, regexp []byte, compiled *re.Regexp) postings.List
 
+	// MatchPrefix returns the postings list corresponding to documents with a term
+	// for the given field which begins with the prefix.
+	MatchPrefix(field, prefix []byte) postings.List
+
+	// MatchTermCaseInsensitive returns the postings list corresponding to documents
+	// which match the given field term ignoring the case of ASCII letters.
+	MatchTermCaseInsensitive(field, term []byte) postings.List
+
+	// MatchNumericRange returns the postings list corresponding to documents with a
+	// term for the given field which is a number within the range.
+	MatchNumericRange(field []byte, r index.NumericRange) postings.List
+
 	// Fields returns the known fields.
 	Fields() sgmt.FieldsIterator
 
@@ -65,6 +78,17 @@ type ReadableSegment interface {
 	// matchRegexp returns the postings list of documents which match the given regular expression.
 	matchRegexp(name, regexp []byte, compiled *re.Regexp) (postings.List, error)
 
+	// matchPrefix returns the postings list of documents with a term beginning with the prefix.
+	matchPrefix(field, prefix []byte) (postings.List, error)
+
+	// matchTermCaseInsensitive returns the postings list of documents which match the given
+	// term ignoring the case of ASCII letters.
+	matchTermCaseInsensitive(field, term []byte) (postings.List, error)
+
+	// matchNumericRange returns the postings list of documents with a term which is a
+	// number within the range.
+	matchNumericRange(field []byte, r index.NumericRange) (postings.List, error)
+
 	// getDoc returns the document associated with the given ID.
 	getDoc(id postings.ID) (doc.Document, error)
 }
@@ -70,6 +70,18 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field, regexp []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchPrefix returns a postings list over all documents with a term for the given
+	// field which begins with the prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
+	// MatchTermCaseInsensitive returns a postings list over all documents which match
+	// the given term ignoring the case of ASCII letters.
+	MatchTermCaseInsensitive(field, term []byte) (postings.List, error)
+
+	// MatchNumericRange returns a postings list over all documents with a term for the
+	// given field which is a number within the range.
+	MatchNumericRange(field []byte, r NumericRange) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// CaseInsensitiveTermQuery finds documents which match the given term ignoring the
+// case of ASCII letters.
+type CaseInsensitiveTermQuery struct {
+	field []byte
+	term  []byte
+}
+
+// NewCaseInsensitiveTermQuery constructs a new CaseInsensitiveTermQuery for the given field and term.
+func NewCaseInsensitiveTermQuery(field, term []byte) search.Query {
+	return &CaseInsensitiveTermQuery{
+		field: field,
+		term:  term,
+	}
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *CaseInsensitiveTermQuery) Searcher(rs index.Readers) (search.Searcher, error) {
+	return searcher.NewCaseInsensitiveTermSearcher(rs, q.field, q.term), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *CaseInsensitiveTermQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*CaseInsensitiveTermQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && bytes.Equal(q.term, inner.term)
+}
+
+// ToProto returns the Protobuf query struct corresponding to the case-insensitive term query.
+func (q *CaseInsensitiveTermQuery) ToProto() *querypb.Query {
+	term := querypb.CaseInsensitiveTermQuery{
+		Field: q.field,
+		Term:  q.term,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_CaseInsensitiveTerm{CaseInsensitiveTerm: &term},
+	}
+}
+
+func (q *CaseInsensitiveTermQuery) String() string {
+	return fmt.Sprintf("caseInsensitiveTerm(%s, %s)", q.field, q.term)
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 
 	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 )
 
@@ -57,6 +58,20 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_CaseInsensitiveTerm:
+		return NewCaseInsensitiveTermQuery(q.CaseInsensitiveTerm.Field, q.CaseInsensitiveTerm.Term), nil
+
+	case *querypb.Query_NumericRange:
+		return NewNumericRangeQuery(q.NumericRange.Field, index.NumericRange{
+			Min:          q.NumericRange.Min,
+			Max:          q.NumericRange.Max,
+			MinInclusive: q.NumericRange.MinInclusive,
+			MaxInclusive: q.NumericRange.MaxInclusive,
+		}), nil
+
 	case *querypb.Query_Negation:
 		inner, err := unmarshal(q.Negation.Query)
 		if err != nil {
@@ -23,6 +23,7 @@ package query
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +41,19 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "case insensitive term query",
+			query: NewCaseInsensitiveTermQuery([]byte("fruit"), []byte("Apple")),
+		},
+		{
+			name: "numeric range query",
+			query: NewNumericRangeQuery([]byte("status_code"),
+				index.NewNumericRange().WithMin(500, true)),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
@@ -45,6 +45,7 @@ func NewConjunctionQuery(queries []search.Query) search.Query {
 		case *ConjuctionQuery:
 			// Merge conjunction queries into slice of top-level queries.
 			qs = append(qs, query.queries...)
+			ns = append(ns, query.negations...)
 			continue
 		case *NegationQuery:
 			ns = append(ns, query.query)
@@ -152,5 +153,10 @@ func (q *ConjuctionQuery) ToProto() *querypb.Query {
 }
 
 func (q *ConjuctionQuery) String() string {
-	return fmt.Sprintf("conjunction(%s)", join(q.queries))
+	qs := make([]search.Query, 0, len(q.queries)+len(q.negations))
+	qs = append(qs, q.queries...)
+	for _, n := range q.negations {
+		qs = append(qs, NewNegationQuery(n))
+	}
+	return fmt.Sprintf("conjunction(%s)", join(qs))
 }
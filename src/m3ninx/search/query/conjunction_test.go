@@ -128,3 +128,16 @@ func TestConjunctionQueryEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestConjunctionQueryNestedNegations(t *testing.T) {
+	inner := NewConjunctionQuery([]search.Query{
+		NewTermQuery([]byte("fruit"), []byte("apple")),
+		NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("banana"))),
+	})
+	q := NewConjunctionQuery([]search.Query{
+		NewTermQuery([]byte("vegetable"), []byte("carrot")),
+		inner,
+	})
+	require.Equal(t, "conjunction(term(vegetable, carrot), term(fruit, apple), "+
+		"negation(term(fruit, banana)))", q.String())
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// NumericRangeQuery finds documents which have a term for the given field that
+// is a number within the range.
+type NumericRangeQuery struct {
+	field []byte
+	rng   index.NumericRange
+}
+
+// NewNumericRangeQuery constructs a new NumericRangeQuery for the given field and range.
+func NewNumericRangeQuery(field []byte, r index.NumericRange) search.Query {
+	return &NumericRangeQuery{
+		field: field,
+		rng:   r,
+	}
+}
+
+// Field returns the field the query matches against.
+func (q *NumericRangeQuery) Field() []byte {
+	return q.field
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *NumericRangeQuery) Searcher(rs index.Readers) (search.Searcher, error) {
+	return searcher.NewNumericRangeSearcher(rs, q.field, q.rng), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *NumericRangeQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*NumericRangeQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && q.rng == inner.rng
+}
+
+// ToProto returns the Protobuf query struct corresponding to the numeric range query.
+func (q *NumericRangeQuery) ToProto() *querypb.Query {
+	numericRange := querypb.NumericRangeQuery{
+		Field:        q.field,
+		Min:          q.rng.Min,
+		Max:          q.rng.Max,
+		MinInclusive: q.rng.MinInclusive,
+		MaxInclusive: q.rng.MaxInclusive,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_NumericRange{NumericRange: &numericRange},
+	}
+}
+
+func (q *NumericRangeQuery) String() string {
+	return fmt.Sprintf("numericRange(%s, %s)", q.field, q.rng)
+}
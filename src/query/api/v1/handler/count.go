@@ -0,0 +1,123 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SeriesCountURL is the url to count the series matching a query
+	SeriesCountURL = RoutePrefixV1 + "/series/count"
+
+	// SeriesCountHTTPMethod is the HTTP method used with this resource.
+	SeriesCountHTTPMethod = http.MethodPost
+)
+
+// SeriesCountHandler represents a handler for the series count endpoint
+type SeriesCountHandler struct {
+	store storage.Storage
+}
+
+// SeriesCountResponse is the response of the series count endpoint
+type SeriesCountResponse struct {
+	Count      int  `json:"count"`
+	Exhaustive bool `json:"exhaustive"`
+}
+
+// NewSeriesCountHandler returns a new instance of handler
+func NewSeriesCountHandler(storage storage.Storage) http.Handler {
+	return &SeriesCountHandler{store: storage}
+}
+
+func (h *SeriesCountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query, rErr := h.parseBody(r)
+	if rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	opts, rErr := h.parseURLParams(r)
+	if rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	result, err := h.count(r.Context(), query, opts)
+	if err != nil {
+		logger.Error("unable to count series", zap.Any("error", err))
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	WriteJSONResponse(w, SeriesCountResponse{
+		Count:      result.Count,
+		Exhaustive: result.Exhaustive,
+	}, logger)
+}
+
+func (h *SeriesCountHandler) parseBody(r *http.Request) (*storage.FetchQuery, *ParseError) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, NewParseError(err, http.StatusBadRequest)
+	}
+	defer r.Body.Close()
+
+	var fetchQuery storage.FetchQuery
+	if err := json.Unmarshal(body, &fetchQuery); err != nil {
+		return nil, NewParseError(err, http.StatusBadRequest)
+	}
+
+	return &fetchQuery, nil
+}
+
+// parseURLParams parses the optional limit, counts are not limited by default.
+func (h *SeriesCountHandler) parseURLParams(r *http.Request) (*storage.FetchOptions, *ParseError) {
+	var limit int
+	if limitRaw := r.URL.Query().Get("limit"); limitRaw != "" {
+		var err error
+		limit, err = strconv.Atoi(limitRaw)
+		if err != nil || limit < 0 {
+			return nil, NewParseError(fmt.Errorf("invalid limit: %s", limitRaw),
+				http.StatusBadRequest)
+		}
+	}
+
+	fetchOptions := newFetchOptions(limit)
+	return &fetchOptions, nil
+}
+
+func (h *SeriesCountHandler) count(ctx context.Context, query *storage.FetchQuery, opts *storage.FetchOptions) (*storage.CountResult, error) {
+	return h.store.FetchCount(ctx, query, opts)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/test/local"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesCountEndpoint(t *testing.T) {
+	logging.InitWithCores(nil)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage, session := local.NewStorageAndSession(t, ctrl)
+	session.EXPECT().FetchTaggedCount(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ ident.ID, _ index.Query, opts index.QueryOptions) (int, bool, error) {
+			assert.Equal(t, 90, opts.Limit)
+			return 42, true, nil
+		})
+
+	server := httptest.NewServer(NewSeriesCountHandler(storage))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s%s", server.URL, "?limit=90")
+	req, err := http.NewRequest(SeriesCountHTTPMethod, url, generateSearchBody(t))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result SeriesCountResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, SeriesCountResponse{Count: 42, Exhaustive: true}, result)
+}
+
+func TestSeriesCountEndpointInvalidLimit(t *testing.T) {
+	logging.InitWithCores(nil)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage, _ := local.NewStorageAndSession(t, ctrl)
+	server := httptest.NewServer(NewSeriesCountHandler(storage))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s%s", server.URL, "?limit=-1")
+	req, err := http.NewRequest(SeriesCountHTTPMethod, url, generateSearchBody(t))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
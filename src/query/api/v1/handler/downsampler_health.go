@@ -0,0 +1,53 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/query/util/logging"
+)
+
+const (
+	// DownsamplerHealthURL is the url for the downsampler health handler
+	DownsamplerHealthURL = RoutePrefixV1 + "/downsampler/health"
+
+	// DownsamplerHealthHTTPMethod is the HTTP method used with this resource.
+	DownsamplerHealthHTTPMethod = http.MethodGet
+)
+
+// DownsamplerHealthHandler represents a handler for the downsampler health
+// endpoint, reporting the open aggregation windows, flush lag and dropped
+// samples by resolution and the estimated memory held for each rule.
+type DownsamplerHealthHandler struct {
+	downsampler downsample.Downsampler
+}
+
+// NewDownsamplerHealthHandler returns a new instance of handler
+func NewDownsamplerHealthHandler(downsampler downsample.Downsampler) http.Handler {
+	return &DownsamplerHealthHandler{downsampler: downsampler}
+}
+
+func (h *DownsamplerHealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	WriteJSONResponse(w, h.downsampler.Health(), logger)
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/graphite"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// FindURL is the url for the graphite metrics find handler
+	FindURL = handler.RoutePrefixV1 + "/graphite/metrics/find"
+
+	// ExpandURL is the url for the graphite metrics expand handler
+	ExpandURL = handler.RoutePrefixV1 + "/graphite/metrics/expand"
+
+	// FindHTTPMethod is the HTTP method used with the find resource.
+	FindHTTPMethod = http.MethodGet
+
+	// ExpandHTTPMethod is the HTTP method used with the expand resource.
+	ExpandHTTPMethod = http.MethodGet
+
+	queryParam      = "query"
+	fromParam       = "from"
+	untilParam      = "until"
+	leavesOnlyParam = "leavesOnly"
+
+	// DefaultLimit is the default max number of series searched per request.
+	DefaultLimit = 10000
+)
+
+// FindNode is a node of the metrics tree returned by the find endpoint,
+// it matches the graphite-web treejson format.
+type FindNode struct {
+	Text          string `json:"text"`
+	ID            string `json:"id"`
+	Leaf          int    `json:"leaf"`
+	Expandable    int    `json:"expandable"`
+	AllowChildren int    `json:"allowChildren"`
+}
+
+// ExpandResponse is the response of the expand endpoint.
+type ExpandResponse struct {
+	Results []string `json:"results"`
+	// Truncated is true if the series searched were limited, in which case
+	// the results may be missing paths
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// FindHandler represents a handler for the graphite metrics find endpoint.
+type FindHandler struct {
+	store   storage.Storage
+	mapping graphite.PathMapping
+	limit   int
+}
+
+// ExpandHandler represents a handler for the graphite metrics expand endpoint.
+type ExpandHandler struct {
+	store   storage.Storage
+	mapping graphite.PathMapping
+	limit   int
+}
+
+// NewFindHandler returns a new instance of the find handler.
+func NewFindHandler(
+	store storage.Storage,
+	mapping graphite.PathMapping,
+	limit int,
+) http.Handler {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &FindHandler{store: store, mapping: mapping, limit: limit}
+}
+
+// NewExpandHandler returns a new instance of the expand handler.
+func NewExpandHandler(
+	store storage.Storage,
+	mapping graphite.PathMapping,
+	limit int,
+) http.Handler {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &ExpandHandler{store: store, mapping: mapping, limit: limit}
+}
+
+// pathNode is a distinct path matching a query, a path can be both a leaf
+// and a branch if series exist both at and below it.
+type pathNode struct {
+	leaf   bool
+	branch bool
+}
+
+func (h *FindHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query, rErr := parseQuery(r)
+	if rErr != nil {
+		handler.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	paths, truncated, err := findPaths(r.Context(), h.store, h.mapping, query, h.limit)
+	if err != nil {
+		logger.Error("unable to find metrics", zap.Any("error", err))
+		handler.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if truncated {
+		logger.Warn("find results truncated by limit",
+			zap.String("query", query.pattern), zap.Int("limit", h.limit))
+		w.Header().Set(handler.TruncatedHeader, "true")
+	}
+
+	ids := sortedPaths(paths)
+	nodes := make([]FindNode, 0, len(ids))
+	for _, id := range ids {
+		text := id[strings.LastIndexByte(id, '.')+1:]
+		p := paths[id]
+		if p.branch {
+			nodes = append(nodes, FindNode{
+				Text:          text,
+				ID:            id,
+				Expandable:    1,
+				AllowChildren: 1,
+			})
+		}
+		if p.leaf {
+			nodes = append(nodes, FindNode{
+				Text: text,
+				ID:   id,
+				Leaf: 1,
+			})
+		}
+	}
+
+	handler.WriteJSONResponse(w, nodes, logger)
+}
+
+func (h *ExpandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query, rErr := parseQuery(r)
+	if rErr != nil {
+		handler.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	paths, truncated, err := findPaths(r.Context(), h.store, h.mapping, query, h.limit)
+	if err != nil {
+		logger.Error("unable to expand metrics", zap.Any("error", err))
+		handler.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if truncated {
+		logger.Warn("expand results truncated by limit",
+			zap.String("query", query.pattern), zap.Int("limit", h.limit))
+		w.Header().Set(handler.TruncatedHeader, "true")
+	}
+
+	leavesOnly := r.URL.Query().Get(leavesOnlyParam) == "1"
+	results := make([]string, 0, len(paths))
+	for _, id := range sortedPaths(paths) {
+		if leavesOnly && !paths[id].leaf {
+			continue
+		}
+		results = append(results, id)
+	}
+
+	handler.WriteJSONResponse(w, ExpandResponse{
+		Results:   results,
+		Truncated: truncated,
+	}, logger)
+}
+
+// findPaths returns the distinct paths matching the query, truncated is true
+// if the series searched hit the limit and so paths may be missing.
+func findPaths(
+	ctx context.Context,
+	store storage.Storage,
+	mapping graphite.PathMapping,
+	query *findQuery,
+	limit int,
+) (map[string]pathNode, bool, error) {
+	matchers, err := mapping.Matchers(query.pattern)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result, err := store.FetchTags(ctx, &storage.FetchQuery{
+		Raw:         query.pattern,
+		TagMatchers: matchers,
+		Start:       query.from,
+		End:         query.until,
+	}, &storage.FetchOptions{Limit: limit})
+	if err != nil {
+		return nil, false, err
+	}
+
+	depth := len(matchers)
+	paths := make(map[string]pathNode)
+	for _, metric := range result.Metrics {
+		path, ok := mapping.Path(metric.Tags, depth)
+		if !ok {
+			continue
+		}
+		p := paths[path]
+		if mapping.HasNode(metric.Tags, depth) {
+			p.branch = true
+		} else {
+			p.leaf = true
+		}
+		paths[path] = p
+	}
+	return paths, len(result.Metrics) >= limit, nil
+}
+
+func sortedPaths(paths map[string]pathNode) []string {
+	ids := make([]string, 0, len(paths))
+	for id := range paths {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+type findQuery struct {
+	pattern string
+	from    time.Time
+	until   time.Time
+}
+
+// parseQuery parses the query and the optional from and until unix
+// timestamps, by default all series indexed until now are searched.
+func parseQuery(r *http.Request) (*findQuery, *handler.ParseError) {
+	values := r.URL.Query()
+	query := &findQuery{
+		pattern: values.Get(queryParam),
+		from:    time.Unix(0, 0),
+		until:   time.Now(),
+	}
+	if query.pattern == "" {
+		return nil, handler.NewParseError(
+			fmt.Errorf("missing %s param", queryParam), http.StatusBadRequest)
+	}
+
+	var err error
+	if str := values.Get(fromParam); str != "" {
+		if query.from, err = parseUnixTime(str); err != nil {
+			return nil, handler.NewParseError(
+				fmt.Errorf("unable to parse %s: %v", fromParam, err), http.StatusBadRequest)
+		}
+	}
+	if str := values.Get(untilParam); str != "" {
+		if query.until, err = parseUnixTime(str); err != nil {
+			return nil, handler.NewParseError(
+				fmt.Errorf("unable to parse %s: %v", untilParam, err), http.StatusBadRequest)
+		}
+	}
+	return query, nil
+}
+
+func parseUnixTime(str string) (time.Time, error) {
+	secs, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0), nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/graphite"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage() mock.Storage {
+	store := mock.NewMockStorage()
+	store.SetFetchTagsResult(&storage.SearchResults{
+		Metrics: models.Metrics{
+			{Tags: models.Tags{"__g0__": "servers", "__g1__": "host01", "__g2__": "cpu"}},
+			{Tags: models.Tags{"__g0__": "servers", "__g1__": "host02", "__g2__": "cpu"}},
+			{Tags: models.Tags{"__g0__": "servers", "__g1__": "host02"}},
+		},
+	}, nil)
+	return store
+}
+
+func TestFind(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	h := NewFindHandler(newTestStorage(), graphite.NewPathMapping("", ""), 0)
+	req := httptest.NewRequest(FindHTTPMethod, FindURL+"?query=servers.*", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var nodes []FindNode
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &nodes))
+	assert.Equal(t, []FindNode{
+		{Text: "host01", ID: "servers.host01", Expandable: 1, AllowChildren: 1},
+		{Text: "host02", ID: "servers.host02", Expandable: 1, AllowChildren: 1},
+		{Text: "host02", ID: "servers.host02", Leaf: 1},
+	}, nodes)
+}
+
+func TestExpand(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	h := NewExpandHandler(newTestStorage(), graphite.NewPathMapping("", ""), 0)
+	req := httptest.NewRequest(ExpandHTTPMethod, ExpandURL+"?query=servers.*&leavesOnly=1", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp ExpandResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"servers.host02"}, resp.Results)
+}
+
+func TestFindRequiresQuery(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	h := NewFindHandler(newTestStorage(), graphite.NewPathMapping("", ""), 0)
+	req := httptest.NewRequest(FindHTTPMethod, FindURL, nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestFindTruncated(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	h := NewFindHandler(newTestStorage(), graphite.NewPathMapping("", ""), 3)
+	req := httptest.NewRequest(FindHTTPMethod, FindURL+"?query=servers.*", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "true", recorder.Header().Get(handler.TruncatedHeader))
+
+	h = NewFindHandler(newTestStorage(), graphite.NewPathMapping("", ""), 4)
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get(handler.TruncatedHeader))
+}
+
+func TestExpandTruncated(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	h := NewExpandHandler(newTestStorage(), graphite.NewPathMapping("", ""), 3)
+	req := httptest.NewRequest(ExpandHTTPMethod, ExpandURL+"?query=servers.*", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "true", recorder.Header().Get(handler.TruncatedHeader))
+
+	var resp ExpandResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.True(t, resp.Truncated)
+}
@@ -32,4 +32,10 @@ const (
 
 	// DeprecatedHeader is the M3 deprecated header
 	DeprecatedHeader = "M3-Deprecated"
+
+	// TruncatedHeader is set when results were limited or sampled
+	TruncatedHeader = "M3-Results-Truncated"
+
+	// MatchedSeriesHeader is the number of series matched before any limit
+	MatchedSeriesHeader = "M3-Matched-Series"
 )
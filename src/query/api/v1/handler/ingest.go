@@ -0,0 +1,122 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/storage/ingest"
+	"github.com/m3db/m3/src/query/util/logging"
+)
+
+const (
+	// IngestStatsURL is the url for the per metric name ingest stats handler
+	IngestStatsURL = RoutePrefixV1 + "/ingest/stats"
+
+	// IngestStatsHTTPMethod is the HTTP method used with this resource.
+	IngestStatsHTTPMethod = http.MethodGet
+
+	// IngestSkewURL is the url for the per writer clock skew handler
+	IngestSkewURL = RoutePrefixV1 + "/ingest/skew"
+
+	// IngestSkewHTTPMethod is the HTTP method used with this resource.
+	IngestSkewHTTPMethod = http.MethodGet
+
+	ingestStatsLimitParam   = "limit"
+	defaultIngestStatsLimit = 10
+)
+
+// IngestStatsHandler represents a handler for the ingest stats endpoint
+type IngestStatsHandler struct {
+	tracker ingest.Tracker
+}
+
+// IngestStatsResponse is the response of the ingest stats endpoint, the
+// stats are approximate and may overcount metrics with few datapoints.
+type IngestStatsResponse struct {
+	Window  string               `json:"window"`
+	Metrics []ingest.MetricStats `json:"metrics"`
+}
+
+// NewIngestStatsHandler returns a new instance of handler
+func NewIngestStatsHandler(tracker ingest.Tracker) http.Handler {
+	return &IngestStatsHandler{tracker: tracker}
+}
+
+func (h *IngestStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	limit := defaultIngestStatsLimit
+	if str := r.URL.Query().Get(ingestStatsLimitParam); str != "" {
+		var err error
+		limit, err = strconv.Atoi(str)
+		if err != nil || limit <= 0 {
+			Error(w, fmt.Errorf("invalid limit: %s", str), http.StatusBadRequest)
+			return
+		}
+	}
+
+	metrics := h.tracker.Top(limit)
+	if metrics == nil {
+		metrics = []ingest.MetricStats{}
+	}
+	WriteJSONResponse(w, IngestStatsResponse{
+		Window:  h.tracker.Window().String(),
+		Metrics: metrics,
+	}, logger)
+}
+
+// IngestSkewHandler represents a handler for the ingest clock skew endpoint
+type IngestSkewHandler struct {
+	tracker ingest.SkewTracker
+}
+
+// IngestSkewResponse is the response of the ingest clock skew endpoint.
+type IngestSkewResponse struct {
+	Writers []ingest.WriterSkew `json:"writers"`
+}
+
+// NewIngestSkewHandler returns a new instance of handler
+func NewIngestSkewHandler(tracker ingest.SkewTracker) http.Handler {
+	return &IngestSkewHandler{tracker: tracker}
+}
+
+func (h *IngestSkewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	limit := defaultIngestStatsLimit
+	if str := r.URL.Query().Get(ingestStatsLimitParam); str != "" {
+		var err error
+		limit, err = strconv.Atoi(str)
+		if err != nil || limit <= 0 {
+			Error(w, fmt.Errorf("invalid limit: %s", str), http.StatusBadRequest)
+			return
+		}
+	}
+
+	writers := h.tracker.Report()
+	if len(writers) > limit {
+		writers = writers[:limit]
+	}
+	WriteJSONResponse(w, IngestSkewResponse{Writers: writers}, logger)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ReadJSONURL is the url for the read json handler
+	ReadJSONURL = handler.RoutePrefixV1 + "/json/read"
+
+	// JSONReadHTTPMethod is the HTTP method used with this resource.
+	JSONReadHTTPMethod = http.MethodPost
+
+	// annotationsParam is the opt-in URL param to include annotations.
+	annotationsParam = "annotations"
+)
+
+var (
+	errEmptyBody = errors.New("empty request body")
+)
+
+// ReadJSONHandler represents a handler for the read json endpoint which
+// returns the raw datapoints of the series matching a fetch query
+type ReadJSONHandler struct {
+	store storage.Storage
+}
+
+// NewReadJSONHandler returns a new instance of handler.
+func NewReadJSONHandler(store storage.Storage) http.Handler {
+	return &ReadJSONHandler{
+		store: store,
+	}
+}
+
+// ReadResult is the response of the read json endpoint
+type ReadResult struct {
+	Series []ReadSeries `json:"series"`
+}
+
+// ReadSeries is a single series in the read response
+type ReadSeries struct {
+	ID         string            `json:"id"`
+	Tags       map[string]string `json:"tags"`
+	Datapoints []ReadDatapoint   `json:"datapoints"`
+}
+
+// ReadDatapoint is a single datapoint in the read response, the annotation
+// is only included if requested with the annotations URL param
+type ReadDatapoint struct {
+	Timestamp  int64   `json:"timestamp"`
+	Value      float64 `json:"value"`
+	Annotation *string `json:"annotation,omitempty"`
+}
+
+func (h *ReadJSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query, rErr := h.parseRequest(r)
+	if rErr != nil {
+		handler.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	includeAnnotations := false
+	if v := r.URL.Query().Get(annotationsParam); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			handler.Error(w, err, http.StatusBadRequest)
+			return
+		}
+		includeAnnotations = parsed
+	}
+
+	result, err := h.store.Fetch(r.Context(), query, &storage.FetchOptions{})
+	if err != nil {
+		logger.Error("unable to fetch data", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	handler.WriteJSONResponse(w, newReadResult(result.SeriesList, includeAnnotations), logger)
+}
+
+func newReadResult(seriesList ts.SeriesList, includeAnnotations bool) ReadResult {
+	result := ReadResult{Series: make([]ReadSeries, 0, len(seriesList))}
+	for _, s := range seriesList {
+		vals := s.Values()
+		datapoints := make([]ReadDatapoint, 0, vals.Len())
+		for i := 0; i < vals.Len(); i++ {
+			dp := vals.DatapointAt(i)
+			readDatapoint := ReadDatapoint{
+				Timestamp: dp.Timestamp.Unix(),
+				Value:     dp.Value,
+			}
+			if includeAnnotations {
+				annotation := string(dp.Annotation)
+				readDatapoint.Annotation = &annotation
+			}
+			datapoints = append(datapoints, readDatapoint)
+		}
+		result.Series = append(result.Series, ReadSeries{
+			ID:         s.Name(),
+			Tags:       s.Tags,
+			Datapoints: datapoints,
+		})
+	}
+	return result
+}
+
+func (h *ReadJSONHandler) parseRequest(r *http.Request) (*storage.FetchQuery, *handler.ParseError) {
+	if r.Body == nil {
+		return nil, handler.NewParseError(errEmptyBody, http.StatusBadRequest)
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, handler.NewParseError(err, http.StatusInternalServerError)
+	}
+
+	var query storage.FetchQuery
+	if err := json.Unmarshal(body, &query); err != nil {
+		return nil, handler.NewParseError(err, http.StatusBadRequest)
+	}
+	if filter := query.Filter; filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, handler.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	return &query, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadResultAnnotations(t *testing.T) {
+	now := time.Unix(1534952005, 0)
+	seriesList := ts.SeriesList{
+		ts.NewSeries("foo", ts.Datapoints{
+			{Timestamp: now, Value: 1, Annotation: []byte("deploy")},
+			{Timestamp: now.Add(time.Second), Value: 2},
+		}, models.Tags{"a": "b"}),
+	}
+
+	result := newReadResult(seriesList, false)
+	require.Len(t, result.Series, 1)
+	require.Equal(t, "foo", result.Series[0].ID)
+	require.Equal(t, map[string]string{"a": "b"}, result.Series[0].Tags)
+	require.Equal(t, []ReadDatapoint{
+		{Timestamp: now.Unix(), Value: 1},
+		{Timestamp: now.Unix() + 1, Value: 2},
+	}, result.Series[0].Datapoints)
+
+	result = newReadResult(seriesList, true)
+	datapoints := result.Series[0].Datapoints
+	require.Len(t, datapoints, 2)
+	require.Equal(t, "deploy", *datapoints[0].Annotation)
+	require.Equal(t, "", *datapoints[1].Annotation)
+}
@@ -59,9 +59,11 @@ func NewWriteJSONHandler(store storage.Storage) http.Handler {
 // WriteQuery represents the write request from the user
 // NB(braskin): support only writing one datapoint for now
 type WriteQuery struct {
-	Tags      map[string]string `json:"tags" validate:"nonzero"`
-	Timestamp string            `json:"timestamp" validate:"nonzero"`
-	Value     float64           `json:"value" validate:"nonzero"`
+	Tags       map[string]string `json:"tags" validate:"nonzero"`
+	Timestamp  string            `json:"timestamp" validate:"nonzero"`
+	Value      float64           `json:"value" validate:"nonzero"`
+	Annotation string            `json:"annotation"`
+	ID         string            `json:"id"`
 }
 
 func (h *WriteJSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -89,6 +91,11 @@ func newStorageWriteQuery(req *WriteQuery) (*storage.WriteQuery, error) {
 		return nil, err
 	}
 
+	var annotation []byte
+	if req.Annotation != "" {
+		annotation = []byte(req.Annotation)
+	}
+
 	return &storage.WriteQuery{
 		Tags: req.Tags,
 		Datapoints: ts.Datapoints{
@@ -98,7 +105,8 @@ func newStorageWriteQuery(req *WriteQuery) (*storage.WriteQuery, error) {
 			},
 		},
 		Unit:       xtime.Millisecond,
-		Annotation: nil,
+		Annotation: annotation,
+		ID:         req.ID,
 	}, nil
 }
 
@@ -55,6 +55,27 @@ func TestJSONWriteParsing(t *testing.T) {
 	require.Equal(t, map[string]string{"tag_one": "val_one", "tag_two": "val_two"}, r.Tags)
 }
 
+func TestJSONWriteAnnotation(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	jsonWrite := &WriteJSONHandler{store: nil}
+
+	jsonReq := `{
+		"tags": { "tag_one": "val_one" },
+		"timestamp": "1534952005",
+		"value": 10.0,
+		"annotation": "deploy"
+	}`
+	req, _ := http.NewRequest("POST", WriteJSONURL, strings.NewReader(jsonReq))
+
+	r, rErr := jsonWrite.parseRequest(req)
+	require.Nil(t, rErr, "unable to parse request")
+
+	writeQuery, err := newStorageWriteQuery(r)
+	require.NoError(t, err)
+	require.Equal(t, []byte("deploy"), writeQuery.Annotation)
+}
+
 func TestJSONWrite(t *testing.T) {
 	logging.InitWithCores(nil)
 
@@ -21,16 +21,21 @@
 package namespace
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/placement"
 	"github.com/m3db/m3/src/query/generated/proto/admin"
 	"github.com/m3db/m3/src/query/util/logging"
 	clusterclient "github.com/m3db/m3cluster/client"
 	"github.com/m3db/m3cluster/kv"
 
+	"github.com/golang/protobuf/jsonpb"
 	"go.uber.org/zap"
 )
 
@@ -40,14 +45,35 @@ const (
 
 	// GetHTTPMethod is the HTTP method used with this resource.
 	GetHTTPMethod = http.MethodGet
+
+	// usageParam is the query param to include the usage of the namespaces
+	// collected from the dbnodes, e.g. GET /api/v1/namespace?usage=true
+	usageParam = "usage"
 )
 
 // GetHandler is the handler for namespace gets.
-type GetHandler Handler
+type GetHandler struct {
+	Handler
+
+	usage usageCollector
+}
+
+// getWithUsageResponse is the response of a namespace get with usage.
+type getWithUsageResponse struct {
+	Registry json.RawMessage `json:"registry"`
+	Usage    UsageReport     `json:"usage"`
+}
 
 // NewGetHandler returns a new instance of GetHandler.
-func NewGetHandler(client clusterclient.Client) *GetHandler {
-	return &GetHandler{client: client}
+func NewGetHandler(client clusterclient.Client, cfg config.Configuration) *GetHandler {
+	var usageCfg *config.NamespaceUsageConfiguration
+	if cfg.ClusterManagement != nil {
+		usageCfg = cfg.ClusterManagement.NamespaceUsage
+	}
+	return &GetHandler{
+		Handler: Handler{client: client},
+		usage:   newUsageCollector(usageCfg),
+	}
 }
 
 func (h *GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -65,7 +91,35 @@ func (h *GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Registry: &nsRegistry,
 	}
 
-	handler.WriteProtoMsgJSONResponse(w, resp, logger)
+	if r.URL.Query().Get(usageParam) != "true" {
+		handler.WriteProtoMsgJSONResponse(w, resp, logger)
+		return
+	}
+
+	service, err := placement.Service(h.client, r.Header)
+	if err != nil {
+		logger.Error("unable to get placement service", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	p, _, err := service.Placement()
+	if err != nil {
+		logger.Error("unable to get placement", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	var registry bytes.Buffer
+	if err := (&jsonpb.Marshaler{EmitDefaults: true}).Marshal(&registry, &nsRegistry); err != nil {
+		logger.Error("unable to marshal namespaces", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	handler.WriteJSONResponse(w, getWithUsageResponse{
+		Registry: registry.Bytes(),
+		Usage:    h.usage.collect(p),
+	}, logger)
 }
 
 // Get gets the namespaces.
@@ -26,6 +26,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3cluster/client"
@@ -55,7 +56,7 @@ func SetupNamespaceTest(t *testing.T) (*client.MockClient, *kv.MockStore, *gomoc
 
 func TestNamespaceGetHandler(t *testing.T) {
 	mockClient, mockKV, ctrl := SetupNamespaceTest(t)
-	getHandler := NewGetHandler(mockClient)
+	getHandler := NewGetHandler(mockClient, config.Configuration{})
 
 	// Test no namespace
 	w := httptest.NewRecorder()
@@ -0,0 +1,165 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3cluster/placement"
+)
+
+const (
+	// dbnodeNamespaceUsagePath is the path of the namespace usage debug
+	// endpoint of the dbnodes.
+	dbnodeNamespaceUsagePath = "/debug/namespace/usage"
+
+	defaultDBNodeDebugPort = 9004
+	defaultUsageTimeout    = 5 * time.Second
+)
+
+// Usage is the utilization of a namespace summed across the dbnodes of the
+// placement, the number of series and disk bytes include all replicas.
+type Usage struct {
+	NumSeries        int64   `json:"numSeries"`
+	DiskBytes        int64   `json:"diskBytes"`
+	WritesPerSecond  float64 `json:"writesPerSecond"`
+	ReadsPerSecond   float64 `json:"readsPerSecond"`
+	IndexNumDocs     int64   `json:"indexNumDocs"`
+	IndexNumSegments int64   `json:"indexNumSegments"`
+}
+
+func (u *Usage) add(other Usage) {
+	u.NumSeries += other.NumSeries
+	u.DiskBytes += other.DiskBytes
+	u.WritesPerSecond += other.WritesPerSecond
+	u.ReadsPerSecond += other.ReadsPerSecond
+	u.IndexNumDocs += other.IndexNumDocs
+	u.IndexNumSegments += other.IndexNumSegments
+}
+
+// UsageReport is the usage of each namespace collected from the dbnodes,
+// instances that could not be reached are reported as errors.
+type UsageReport struct {
+	Namespaces map[string]Usage `json:"namespaces"`
+	Instances  int              `json:"instances"`
+	Errors     []string         `json:"errors,omitempty"`
+}
+
+type dbnodeNamespaceUsage struct {
+	Usage
+	Namespace string `json:"namespace"`
+}
+
+type dbnodeNamespaceUsageResponse struct {
+	Namespaces []dbnodeNamespaceUsage `json:"namespaces"`
+}
+
+// usageCollector collects the usage of namespaces from the debug endpoints
+// of the dbnodes of a placement.
+type usageCollector struct {
+	client *http.Client
+	port   int
+}
+
+func newUsageCollector(cfg *config.NamespaceUsageConfiguration) usageCollector {
+	var (
+		port    = defaultDBNodeDebugPort
+		timeout = defaultUsageTimeout
+	)
+	if cfg != nil {
+		if cfg.DBNodeDebugPort > 0 {
+			port = cfg.DBNodeDebugPort
+		}
+		if cfg.Timeout > 0 {
+			timeout = cfg.Timeout
+		}
+	}
+	return usageCollector{
+		client: &http.Client{Timeout: timeout},
+		port:   port,
+	}
+}
+
+func (c usageCollector) collect(p placement.Placement) UsageReport {
+	var (
+		instances = p.Instances()
+		report    = UsageReport{
+			Namespaces: make(map[string]Usage),
+			Instances:  len(instances),
+		}
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, instance := range instances {
+		instance := instance
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			usages, err := c.fetch(instance.Endpoint())
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Errors = append(report.Errors,
+					fmt.Sprintf("instance %s: %v", instance.ID(), err))
+				return
+			}
+			for _, usage := range usages {
+				total := report.Namespaces[usage.Namespace]
+				total.add(usage.Usage)
+				report.Namespaces[usage.Namespace] = total
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (c usageCollector) fetch(endpoint string) ([]dbnodeNamespaceUsage, error) {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("http://%s%s",
+		net.JoinHostPort(host, strconv.Itoa(c.port)), dbnodeNamespaceUsagePath)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	var result dbnodeNamespaceUsageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Namespaces, nil
+}
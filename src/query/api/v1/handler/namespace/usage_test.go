@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3cluster/placement"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageCollectorCollect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, dbnodeNamespaceUsagePath, r.URL.Path)
+		fmt.Fprint(w, `{"namespaces":[`+
+			`{"namespace":"default","numSeries":10,"diskBytes":100,"writesPerSecond":1.5},`+
+			`{"namespace":"agg","numSeries":2,"indexNumDocs":2}]}`)
+	}))
+	defer server.Close()
+
+	_, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	collector := newUsageCollector(&config.NamespaceUsageConfiguration{
+		DBNodeDebugPort: port,
+	})
+	p := placement.NewPlacement().SetInstances([]placement.Instance{
+		placement.NewInstance().SetID("a").SetEndpoint("127.0.0.1:9000"),
+		placement.NewInstance().SetID("b").SetEndpoint("127.0.0.1:9000"),
+		placement.NewInstance().SetID("c").SetEndpoint("invalid"),
+	})
+
+	report := collector.collect(p)
+	assert.Equal(t, 3, report.Instances)
+	assert.Equal(t, map[string]Usage{
+		"default": Usage{NumSeries: 20, DiskBytes: 200, WritesPerSecond: 3},
+		"agg":     Usage{NumSeries: 4, IndexNumDocs: 4},
+	}, report.Namespaces)
+	require.Equal(t, 1, len(report.Errors))
+	assert.Contains(t, report.Errors[0], "instance c")
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package placement
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/util/logging"
+	clusterclient "github.com/m3db/m3cluster/client"
+	"github.com/m3db/m3cluster/placement"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// AuditURL is the url for the placement audit handler (with the GET method).
+	AuditURL = handler.RoutePrefixV1 + "/placement/audit"
+
+	// AuditHTTPMethod is the HTTP method used with this resource.
+	AuditHTTPMethod = http.MethodGet
+)
+
+// AuditHandler is the handler for placement audits.
+type AuditHandler Handler
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler(client clusterclient.Client, cfg config.Configuration) *AuditHandler {
+	return &AuditHandler{client: client, cfg: cfg}
+}
+
+// AuditResponse is the response of a placement audit.
+type AuditResponse struct {
+	Version         int                            `json:"version"`
+	Violations      []IsolationGroupViolation      `json:"violations"`
+	IsolationGroups map[string]IsolationGroupUsage `json:"isolationGroups"`
+}
+
+// IsolationGroupUsage is the number of instances and shard replicas placed
+// in an isolation group, used to spot skew between isolation groups.
+type IsolationGroupUsage struct {
+	Instances int `json:"instances"`
+	Shards    int `json:"shards"`
+}
+
+func (h *AuditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	service, err := Service(h.client, r.Header)
+	if err != nil {
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	placement, version, err := service.Placement()
+	if err != nil {
+		logger.Error("unable to get placement", zap.Any("error", err))
+		handler.Error(w, err, http.StatusNotFound)
+		return
+	}
+
+	handler.WriteJSONResponse(w, newAuditResponse(placement, version), logger)
+}
+
+func newAuditResponse(p placement.Placement, version int) AuditResponse {
+	resp := AuditResponse{
+		Version:         version,
+		Violations:      IsolationGroupViolations(p),
+		IsolationGroups: make(map[string]IsolationGroupUsage),
+	}
+	if resp.Violations == nil {
+		resp.Violations = []IsolationGroupViolation{}
+	}
+	for _, instance := range p.Instances() {
+		usage := resp.IsolationGroups[instance.IsolationGroup()]
+		usage.Instances++
+		usage.Shards += instance.Shards().NumShards()
+		resp.IsolationGroups[instance.IsolationGroup()] = usage
+	}
+	return resp
+}
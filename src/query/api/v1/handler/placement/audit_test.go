@@ -0,0 +1,106 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package placement
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3cluster/generated/proto/placementpb"
+	"github.com/m3db/m3cluster/placement"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuditPlacement(t *testing.T) placement.Placement {
+	newInstance := func(id, isolationGroup string, shards ...*placementpb.Shard) *placementpb.Instance {
+		return &placementpb.Instance{
+			Id:             id,
+			IsolationGroup: isolationGroup,
+			Zone:           "test",
+			Weight:         1,
+			Endpoint:       "http://" + id + ":1234",
+			Hostname:       id,
+			Port:           1234,
+			Shards:         shards,
+		}
+	}
+	available := func(id uint32) *placementpb.Shard {
+		return &placementpb.Shard{Id: id, State: placementpb.ShardState_AVAILABLE}
+	}
+	leaving := func(id uint32) *placementpb.Shard {
+		return &placementpb.Shard{Id: id, State: placementpb.ShardState_LEAVING}
+	}
+
+	placementProto := &placementpb.Placement{
+		Instances: map[string]*placementpb.Instance{
+			"host1": newInstance("host1", "rack1", available(0), available(1)),
+			"host2": newInstance("host2", "rack1", available(1), leaving(2)),
+			"host3": newInstance("host3", "rack2", available(0), available(2)),
+			"host4": newInstance("host4", "rack1", available(2)),
+		},
+		ReplicaFactor: 2,
+		NumShards:     3,
+		IsSharded:     true,
+	}
+	p, err := placement.NewPlacementFromProto(placementProto)
+	require.NoError(t, err)
+	return p
+}
+
+func TestIsolationGroupViolations(t *testing.T) {
+	p := newTestAuditPlacement(t)
+
+	violations := IsolationGroupViolations(p)
+	require.Equal(t, []IsolationGroupViolation{
+		{Shard: 1, IsolationGroup: "rack1", Instances: []string{"host1", "host2"}},
+	}, violations)
+	require.Error(t, ValidateIsolationGroups(p))
+
+	require.NoError(t, ValidateIsolationGroups(placement.NewPlacement()))
+}
+
+func TestPlacementAuditHandler(t *testing.T) {
+	mockClient, mockPlacementService := SetupPlacementTest(t)
+	handler := NewAuditHandler(mockClient, config.Configuration{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", AuditURL, nil)
+	require.NotNil(t, req)
+
+	mockPlacementService.EXPECT().Placement().Return(newTestAuditPlacement(t), 3, nil)
+	handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var audit AuditResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&audit))
+	assert.Equal(t, 3, audit.Version)
+	assert.Len(t, audit.Violations, 1)
+	assert.Equal(t, map[string]IsolationGroupUsage{
+		"rack1": {Instances: 3, Shards: 5},
+		"rack2": {Instances: 1, Shards: 2},
+	}, audit.IsolationGroups)
+}
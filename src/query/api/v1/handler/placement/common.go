@@ -65,6 +65,24 @@ func Service(clusterClient clusterclient.Client, headers http.Header) (placement
 		return nil, err
 	}
 
+	sid := ServiceID(headers)
+
+	// Reject any placement change that would place more than one replica of
+	// a shard in the same isolation group.
+	opts := placement.NewOptions().
+		SetValidZone(sid.Zone()).
+		SetValidateFnBeforeUpdate(ValidateIsolationGroups)
+	ps, err := cs.PlacementService(sid, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+// ServiceID returns the ID of the service selected by the headers, the
+// default service ID is used for any part not specified.
+func ServiceID(headers http.Header) services.ServiceID {
 	serviceName := DefaultServiceName
 	if v := strings.TrimSpace(headers.Get(HeaderClusterServiceName)); v != "" {
 		serviceName = v
@@ -80,17 +98,10 @@ func Service(clusterClient clusterclient.Client, headers http.Header) (placement
 		serviceZone = v
 	}
 
-	sid := services.NewServiceID().
+	return services.NewServiceID().
 		SetName(serviceName).
 		SetEnvironment(serviceEnvironment).
 		SetZone(serviceZone)
-
-	ps, err := cs.PlacementService(sid, placement.NewOptions().SetValidZone(serviceZone))
-	if err != nil {
-		return nil, err
-	}
-
-	return ps, nil
 }
 
 // ConvertInstancesProto converts a slice of protobuf `Instance`s to `placement.Instance`s
@@ -126,6 +137,7 @@ func RegisterRoutes(r *mux.Router, client clusterclient.Client, cfg config.Confi
 
 	r.HandleFunc(InitURL, logged(NewInitHandler(client, cfg)).ServeHTTP).Methods(InitHTTPMethod)
 	r.HandleFunc(GetURL, logged(NewGetHandler(client, cfg)).ServeHTTP).Methods(GetHTTPMethod)
+	r.HandleFunc(AuditURL, logged(NewAuditHandler(client, cfg)).ServeHTTP).Methods(AuditHTTPMethod)
 	r.HandleFunc(DeleteAllURL, logged(NewDeleteAllHandler(client, cfg)).ServeHTTP).Methods(DeleteAllHTTPMethod)
 	r.HandleFunc(AddURL, logged(NewAddHandler(client, cfg)).ServeHTTP).Methods(AddHTTPMethod)
 	r.HandleFunc(DeleteURL, logged(NewDeleteHandler(client, cfg)).ServeHTTP).Methods(DeleteHTTPMethod)
@@ -0,0 +1,95 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package placement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/m3db/m3cluster/placement"
+	"github.com/m3db/m3cluster/shard"
+)
+
+// IsolationGroupViolation is a shard with more than one replica placed in
+// the same isolation group.
+type IsolationGroupViolation struct {
+	Shard          uint32   `json:"shard"`
+	IsolationGroup string   `json:"isolationGroup"`
+	Instances      []string `json:"instances"`
+}
+
+func (v IsolationGroupViolation) String() string {
+	return fmt.Sprintf("shard %d has replicas on instances [%s] in isolation group %s",
+		v.Shard, strings.Join(v.Instances, ", "), v.IsolationGroup)
+}
+
+// IsolationGroupViolations returns every shard with more than one replica
+// placed in the same isolation group, leaving shards are not counted as they
+// are being moved to another instance.
+func IsolationGroupViolations(p placement.Placement) []IsolationGroupViolation {
+	type shardGroup struct {
+		shard          uint32
+		isolationGroup string
+	}
+
+	instancesByShardGroup := make(map[shardGroup][]string)
+	for _, instance := range p.Instances() {
+		for _, s := range instance.Shards().All() {
+			if s.State() == shard.Leaving {
+				continue
+			}
+			key := shardGroup{shard: s.ID(), isolationGroup: instance.IsolationGroup()}
+			instancesByShardGroup[key] = append(instancesByShardGroup[key], instance.ID())
+		}
+	}
+
+	var violations []IsolationGroupViolation
+	for key, instances := range instancesByShardGroup {
+		if len(instances) < 2 {
+			continue
+		}
+		sort.Strings(instances)
+		violations = append(violations, IsolationGroupViolation{
+			Shard:          key.shard,
+			IsolationGroup: key.isolationGroup,
+			Instances:      instances,
+		})
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Shard != violations[j].Shard {
+			return violations[i].Shard < violations[j].Shard
+		}
+		return violations[i].IsolationGroup < violations[j].IsolationGroup
+	})
+	return violations
+}
+
+// ValidateIsolationGroups returns an error if any shard of the placement has
+// more than one replica placed in the same isolation group.
+func ValidateIsolationGroups(p placement.Placement) error {
+	violations := IsolationGroupViolations(p)
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("placement has %d isolation group violations, first: %s",
+		len(violations), violations[0].String())
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/errors"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -46,6 +47,9 @@ const (
 	stepParam         = "step"
 	debugParam        = "debug"
 	endExclusiveParam = "end-exclusive"
+	streamParam       = "stream"
+	limitParam        = "limit"
+	sampleParam       = "sampleOnLimit"
 
 	formatErrStr = "error parsing param: %s, error: %v"
 )
@@ -103,6 +107,24 @@ func parseParams(r *http.Request) (models.RequestParams, *handler.ParseError) {
 	}
 	params.Target = target
 
+	if str := r.FormValue(limitParam); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil || limit < 0 {
+			return params, handler.NewParseError(
+				fmt.Errorf(formatErrStr, limitParam, str), http.StatusBadRequest)
+		}
+		params.Limit = limit
+	}
+
+	if str := r.FormValue(sampleParam); str != "" {
+		sample, err := strconv.ParseBool(str)
+		if err != nil {
+			return params, handler.NewParseError(
+				fmt.Errorf(formatErrStr, sampleParam, err), http.StatusBadRequest)
+		}
+		params.SampleOnLimit = sample
+	}
+
 	// Skip debug if unable to parse debug param
 	debugVal := r.FormValue(debugParam)
 	if debugVal != "" {
@@ -128,6 +150,22 @@ func parseParams(r *http.Request) (models.RequestParams, *handler.ParseError) {
 	return params, nil
 }
 
+// parseStream parses whether results should be streamed, defaulting to
+// buffering the results if unable to parse the param.
+func parseStream(r *http.Request) bool {
+	streamVal := r.FormValue(streamParam)
+	if streamVal == "" {
+		return false
+	}
+
+	stream, err := strconv.ParseBool(streamVal)
+	if err != nil {
+		logging.WithContext(r.Context()).Warn("unable to parse stream flag", zap.Any("error", err))
+	}
+
+	return stream
+}
+
 func parseTarget(r *http.Request) (string, error) {
 	targetQueries, ok := r.URL.Query()[targetParam]
 	if !ok || len(targetQueries) == 0 || targetQueries[0] == "" {
@@ -143,50 +181,75 @@ func parseTarget(r *http.Request) (string, error) {
 }
 
 func renderResultsJSON(w io.Writer, series []*ts.Series, params models.RequestParams) {
-	startIdx := 0
 	jw := json.NewWriter(w)
 	jw.BeginArray()
 	for _, s := range series {
-		jw.BeginObject()
-		jw.BeginObjectField("target")
-		jw.WriteString(s.Name())
-
-		jw.BeginObjectField("tags")
-		jw.BeginObject()
-		for k, v := range s.Tags {
-			jw.BeginObjectField(k)
-			jw.WriteString(v)
-		}
-		jw.EndObject()
+		renderSeriesJSON(jw, s, params)
+	}
 
-		jw.BeginObjectField("datapoints")
-		jw.BeginArray()
-		vals := s.Values()
-		for i := startIdx; i < s.Len(); i++ {
-			dp := vals.DatapointAt(i)
-			// Skip points before the query boundary. Ideal place to adjust these would be at the result node but that would make it inefficient
-			// since we would need to create another block just for the sake of restricting the bounds.
-			// Each series have the same start time so we just need to calculate the correct startIdx once
-			if dp.Timestamp.Before(params.Start) {
-				startIdx = i + 1
-				continue
-			}
-
-			jw.BeginArray()
-			jw.WriteFloat64(dp.Value)
-			jw.WriteInt(int(dp.Timestamp.Unix()))
-			jw.EndArray()
+	jw.EndArray()
+	jw.Close()
+}
+
+// renderBlockStreamJSON renders the series of a block as each series is
+// built, flushing the response after every series so that the results are
+// sent chunked rather than buffered in memory.
+func renderBlockStreamJSON(w http.ResponseWriter, jw *json.Writer, b block.Block, params models.RequestParams) error {
+	iter, err := newSortedBlocksSeriesIter([]blockWithMeta{{block: b}})
+	if err != nil {
+		return err
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for iter.Next() {
+		renderSeriesJSON(jw, iter.Current(), params)
+		if err := jw.Flush(); err != nil {
+			return err
 		}
-		jw.EndArray()
 
-		fixedStep, ok := s.Values().(ts.FixedResolutionMutableValues)
-		if ok {
-			jw.BeginObjectField("step_size_ms")
-			jw.WriteInt(int(util.DurationToMS(fixedStep.MillisPerStep())))
-			jw.EndObject()
+		if flusher != nil {
+			flusher.Flush()
 		}
 	}
 
+	return iter.Err()
+}
+
+func renderSeriesJSON(jw *json.Writer, s *ts.Series, params models.RequestParams) {
+	jw.BeginObject()
+	jw.BeginObjectField("target")
+	jw.WriteString(s.Name())
+
+	jw.BeginObjectField("tags")
+	jw.BeginObject()
+	for k, v := range s.Tags {
+		jw.BeginObjectField(k)
+		jw.WriteString(v)
+	}
+	jw.EndObject()
+
+	jw.BeginObjectField("datapoints")
+	jw.BeginArray()
+	vals := s.Values()
+	for i := 0; i < s.Len(); i++ {
+		dp := vals.DatapointAt(i)
+		// Skip points before the query boundary. Ideal place to adjust these would be at the result node but that would make it inefficient
+		// since we would need to create another block just for the sake of restricting the bounds.
+		if dp.Timestamp.Before(params.Start) {
+			continue
+		}
+
+		jw.BeginArray()
+		jw.WriteFloat64(dp.Value)
+		jw.WriteInt(int(dp.Timestamp.Unix()))
+		jw.EndArray()
+	}
 	jw.EndArray()
-	jw.Close()
+
+	fixedStep, ok := s.Values().(ts.FixedResolutionMutableValues)
+	if ok {
+		jw.BeginObjectField("step_size_ms")
+		jw.WriteInt(int(util.DurationToMS(fixedStep.MillisPerStep())))
+	}
+	jw.EndObject()
 }
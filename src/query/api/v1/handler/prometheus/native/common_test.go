@@ -53,6 +53,25 @@ func TestParamParsing(t *testing.T) {
 	require.Equal(t, promQuery, r.Target)
 }
 
+func TestParamParsingLimit(t *testing.T) {
+	req, _ := http.NewRequest("GET", PromReadURL, nil)
+	vals := defaultParams()
+	vals.Add(limitParam, "10")
+	vals.Add(sampleParam, "true")
+	req.URL.RawQuery = vals.Encode()
+
+	r, err := parseParams(req)
+	require.Nil(t, err, "unable to parse request")
+	require.Equal(t, 10, r.Limit)
+	require.True(t, r.SampleOnLimit)
+
+	vals.Set(limitParam, "-1")
+	req.URL.RawQuery = vals.Encode()
+	_, err = parseParams(req)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code())
+}
+
 func TestInvalidStart(t *testing.T) {
 	req, _ := http.NewRequest("GET", PromReadURL, nil)
 	vals := defaultParams()
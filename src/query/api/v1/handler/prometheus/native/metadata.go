@@ -0,0 +1,107 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/storage/metadata"
+	"github.com/m3db/m3/src/query/util/logging"
+)
+
+const (
+	// PromMetadataURL is the url for the prom metric metadata handler
+	PromMetadataURL = handler.RoutePrefixV1 + "/metadata"
+
+	// PromMetadataHTTPMethod is the HTTP method used with this resource.
+	PromMetadataHTTPMethod = http.MethodGet
+
+	metadataMetricParam         = "metric"
+	metadataLimitParam          = "limit"
+	metadataLimitPerMetricParam = "limit_per_metric"
+
+	statusSuccess = "success"
+)
+
+// PromMetadataHandler represents a handler for the prometheus metric
+// metadata endpoint.
+type PromMetadataHandler struct {
+	store metadata.Store
+}
+
+// MetadataResponse is the response that gets returned to the user, it
+// matches the prometheus metric metadata API response.
+type MetadataResponse struct {
+	Status string                         `json:"status"`
+	Data   map[string][]metadata.Metadata `json:"data"`
+}
+
+// NewPromMetadataHandler returns a new instance of handler.
+func NewPromMetadataHandler(store metadata.Store) http.Handler {
+	return &PromMetadataHandler{store: store}
+}
+
+func (h *PromMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query, rErr := parseMetadataQuery(r)
+	if rErr != nil {
+		handler.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	handler.WriteJSONResponse(w, MetadataResponse{
+		Status: statusSuccess,
+		Data:   h.store.Query(query),
+	}, logger)
+}
+
+func parseMetadataQuery(r *http.Request) (metadata.Query, *handler.ParseError) {
+	values := r.URL.Query()
+	query := metadata.Query{
+		Metric: values.Get(metadataMetricParam),
+	}
+
+	var err *handler.ParseError
+	if query.Limit, err = parseMetadataLimit(r, metadataLimitParam); err != nil {
+		return metadata.Query{}, err
+	}
+	if query.LimitPerMetric, err = parseMetadataLimit(r, metadataLimitPerMetricParam); err != nil {
+		return metadata.Query{}, err
+	}
+	return query, nil
+}
+
+func parseMetadataLimit(r *http.Request, param string) (int, *handler.ParseError) {
+	str := r.URL.Query().Get(param)
+	if str == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, handler.NewParseError(
+			fmt.Errorf("unable to parse %s: %v", param, err), http.StatusBadRequest)
+	}
+	return limit, nil
+}
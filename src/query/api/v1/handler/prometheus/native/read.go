@@ -31,8 +31,10 @@ import (
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
 	"github.com/m3db/m3/src/query/parser/promql"
 	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/json"
 	"github.com/m3db/m3/src/query/util/logging"
 
 	"go.uber.org/zap"
@@ -55,7 +57,8 @@ var (
 
 // PromReadHandler represents a handler for prometheus read endpoint.
 type PromReadHandler struct {
-	engine *executor.Engine
+	engine     *executor.Engine
+	parseCache promql.ParseCache
 }
 
 // ReadResponse is the response that gets returned to the user
@@ -69,8 +72,14 @@ type blockWithMeta struct {
 }
 
 // NewPromReadHandler returns a new instance of handler.
-func NewPromReadHandler(engine *executor.Engine) http.Handler {
-	return &PromReadHandler{engine: engine}
+func NewPromReadHandler(
+	engine *executor.Engine,
+	parseCache promql.ParseCache,
+) http.Handler {
+	return &PromReadHandler{
+		engine:     engine,
+		parseCache: parseCache,
+	}
 }
 
 func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -87,6 +96,11 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Info("Request params", zap.Any("params", params))
 	}
 
+	if parseStream(r) {
+		h.serveStream(ctx, w, params)
+		return
+	}
+
 	result, err := h.read(ctx, w, params)
 	if err != nil {
 		logger.Error("unable to fetch data", zap.Any("error", err))
@@ -94,12 +108,127 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	logging.RecordSeries(ctx, len(result))
+
 	// TODO: Support multiple result types
 	w.Header().Set("Content-Type", "application/json")
 	renderResultsJSON(w, result, params)
 }
 
+// serveStream streams the series of each result block as chunked JSON as
+// soon as the block is produced, only holding a single block and series in
+// memory rather than every block of the result. A series spanning several
+// blocks is rendered once for each block with the datapoints of that block.
+func (h *PromReadHandler) serveStream(ctx context.Context, w http.ResponseWriter, params models.RequestParams) {
+	logger := logging.WithContext(ctx)
+
+	var jw *json.Writer
+	beginResponse := func() {
+		w.Header().Set("Content-Type", "application/json")
+		jw = json.NewWriter(w)
+		jw.BeginArray()
+	}
+
+	err := h.forEachBlock(ctx, w, params, func(b block.Block) error {
+		defer b.Close()
+		if jw == nil {
+			beginResponse()
+		}
+		return renderBlockStreamJSON(w, jw, b, params)
+	})
+	if err != nil {
+		if jw == nil {
+			logger.Error("unable to fetch data", zap.Any("error", err))
+			handler.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+		// NB: Response has already been partially written so only log, the
+		// array is left open so that clients fail to parse the truncated
+		// response since the status code has already been written.
+		logger.Error("unable to stream data", zap.Any("error", err))
+		return
+	}
+
+	if jw == nil {
+		beginResponse()
+	}
+	jw.EndArray()
+	if err := jw.Close(); err != nil {
+		logger.Error("unable to stream data", zap.Any("error", err))
+	}
+}
+
+// parse parses the query with the parse cache if one is set.
+func (h *PromReadHandler) parse(q string) (parser.Parser, error) {
+	if h.parseCache != nil {
+		return h.parseCache.Parse(q)
+	}
+	return promql.Parse(q)
+}
+
 func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, params models.RequestParams) ([]*ts.Series, error) {
+	sortedBlockList, err := h.readBlocks(reqCtx, w, params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBlocks(sortedBlockList)
+	return sortedBlocksToSeriesList(sortedBlockList)
+}
+
+// readBlocks executes the query and returns the result blocks sorted by start
+// time, the caller is responsible for closing the blocks.
+func (h *PromReadHandler) readBlocks(reqCtx context.Context, w http.ResponseWriter, params models.RequestParams) ([]blockWithMeta, error) {
+	// Block slices are sorted by start time
+	// TODO: Pooling
+	var (
+		sortedBlockList     = make([]blockWithMeta, 0, initialBlockAlloc)
+		numSteps, numSeries int
+	)
+	err := h.forEachBlock(reqCtx, w, params, func(b block.Block) error {
+		if len(sortedBlockList) == 0 {
+			firstStepIter, err := b.StepIter()
+			if err != nil {
+				b.Close()
+				return err
+			}
+
+			firstSeriesIter, err := b.SeriesIter()
+			if err != nil {
+				b.Close()
+				return err
+			}
+
+			numSteps = firstStepIter.StepCount()
+			numSeries = firstSeriesIter.SeriesCount()
+		}
+
+		// Insert blocks sorted by start time
+		blockList, err := insertSortedBlock(b, sortedBlockList, numSteps, numSeries)
+		if err != nil {
+			b.Close()
+			return err
+		}
+		sortedBlockList = blockList
+		return nil
+	})
+	if err != nil {
+		// Ensure that the blocks are closed
+		closeBlocks(sortedBlockList)
+		return nil, err
+	}
+
+	return sortedBlockList, nil
+}
+
+// forEachBlock executes the query and calls fn with each result block as it
+// is produced, fn takes ownership of the block.
+func (h *PromReadHandler) forEachBlock(
+	reqCtx context.Context,
+	w http.ResponseWriter,
+	params models.RequestParams,
+	fn func(b block.Block) error,
+) error {
 	ctx, cancel := context.WithTimeout(reqCtx, params.Timeout)
 	defer cancel()
 
@@ -109,18 +238,15 @@ func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, pa
 	opts.AbortCh = abortCh
 
 	// TODO: Capture timing
-	parser, err := promql.Parse(params.Target)
+	parser, err := h.parse(params.Target)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Results is closed by execute
 	results := make(chan executor.Query)
 	go h.engine.ExecuteExpr(ctx, parser, opts, params, results)
 
-	// Block slices are sorted by start time
-	// TODO: Pooling
-	sortedBlockList := make([]blockWithMeta, 0, initialBlockAlloc)
 	var processErr error
 	for result := range results {
 		if result.Err != nil {
@@ -129,57 +255,40 @@ func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, pa
 		}
 
 		resultChan := result.Result.ResultChan()
-		firstElement := false
-		var numSteps, numSeries int
-		// TODO(nikunj): Stream blocks to client
 		for blkResult := range resultChan {
 			if blkResult.Err != nil {
 				processErr = blkResult.Err
 				break
 			}
 
-			b := blkResult.Block
-			if !firstElement {
-				firstElement = true
-				firstStepIter, err := b.StepIter()
-				if err != nil {
-					processErr = err
-					break
-				}
-
-				firstSeriesIter, err := b.SeriesIter()
-				if err != nil {
-					processErr = err
-					break
-				}
-
-				numSteps = firstStepIter.StepCount()
-				numSeries = firstSeriesIter.SeriesCount()
-			}
-
-			// Insert blocks sorted by start time
-			sortedBlockList, err = insertSortedBlock(b, sortedBlockList, numSteps, numSeries)
-			if err != nil {
+			if err := fn(blkResult.Block); err != nil {
 				processErr = err
 				break
 			}
 		}
-	}
 
-	// Ensure that the blocks are closed. Can't do this above since sortedBlockList might change
-	defer func() {
-		for _, b := range sortedBlockList {
-			b.block.Close()
+		if processErr != nil {
+			// Drain the remaining blocks of the result
+			for range resultChan {
+				// drain out
+			}
+			break
 		}
-	}()
+	}
 
 	if processErr != nil {
 		// Drain anything remaining
 		drainResultChan(results)
-		return nil, processErr
+		return processErr
 	}
 
-	return sortedBlocksToSeriesList(sortedBlockList)
+	return nil
+}
+
+func closeBlocks(blockList []blockWithMeta) {
+	for _, b := range blockList {
+		b.block.Close()
+	}
 }
 
 func drainResultChan(resultsChan chan executor.Query) {
@@ -200,6 +309,42 @@ func sortedBlocksToSeriesList(blockList []blockWithMeta) ([]*ts.Series, error) {
 		return emptySeriesList, nil
 	}
 
+	iter, err := newSortedBlocksSeriesIter(blockList)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesList := make([]*ts.Series, 0, iter.SeriesCount())
+	for iter.Next() {
+		seriesList = append(seriesList, iter.Current())
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return seriesList, nil
+}
+
+// sortedBlocksSeriesIter iterates over the series of a block list sorted by
+// start time, building one series at a time so that callers streaming the
+// results only hold a single series in memory.
+type sortedBlocksSeriesIter struct {
+	seriesIters []block.SeriesIter
+	seriesMeta  []block.SeriesMeta
+	bounds      block.Bounds
+	numValues   int
+	idx         int
+	curr        *ts.Series
+	err         error
+}
+
+func newSortedBlocksSeriesIter(blockList []blockWithMeta) (*sortedBlocksSeriesIter, error) {
+	iter := &sortedBlocksSeriesIter{idx: -1}
+	if len(blockList) == 0 {
+		return iter, nil
+	}
+
 	firstBlock := blockList[0].block
 	firstStepIter, err := firstBlock.StepIter()
 	if err != nil {
@@ -211,12 +356,10 @@ func sortedBlocksToSeriesList(blockList []blockWithMeta) ([]*ts.Series, error) {
 		return nil, err
 	}
 
-	numSeries := firstSeriesIter.SeriesCount()
-	seriesMeta := firstSeriesIter.SeriesMeta()
-	bounds := firstSeriesIter.Meta().Bounds
-
-	seriesList := make([]*ts.Series, numSeries)
-	seriesIters := make([]block.SeriesIter, len(blockList))
+	iter.seriesMeta = firstSeriesIter.SeriesMeta()
+	iter.bounds = firstSeriesIter.Meta().Bounds
+	iter.numValues = firstStepIter.StepCount() * len(blockList)
+	iter.seriesIters = make([]block.SeriesIter, len(blockList))
 	// To create individual series, we iterate over seriesIterators for each block in the block list.
 	// For each iterator, the nth current() will be combined to give the nth series
 	for i, b := range blockList {
@@ -225,33 +368,57 @@ func sortedBlocksToSeriesList(blockList []blockWithMeta) ([]*ts.Series, error) {
 			return nil, err
 		}
 
-		seriesIters[i] = seriesIter
+		iter.seriesIters[i] = seriesIter
 	}
 
-	numValues := firstStepIter.StepCount() * len(blockList)
-	for i := 0; i < numSeries; i++ {
-		values := ts.NewFixedStepValues(bounds.StepSize, numValues, math.NaN(), bounds.Start)
-		valIdx := 0
-		for idx, iter := range seriesIters {
-			if !iter.Next() {
-				return nil, fmt.Errorf("invalid number of datapoints for series: %d, block: %d", i, idx)
-			}
+	return iter, nil
+}
 
-			blockSeries, err := iter.Current()
-			if err != nil {
-				return nil, err
-			}
+// SeriesCount returns the number of series.
+func (it *sortedBlocksSeriesIter) SeriesCount() int {
+	return len(it.seriesMeta)
+}
 
-			for i := 0; i < blockSeries.Len(); i++ {
-				values.SetValueAt(valIdx, blockSeries.ValueAtStep(i))
-				valIdx++
-			}
+// Next moves to the next series.
+func (it *sortedBlocksSeriesIter) Next() bool {
+	if it.err != nil || it.idx+1 >= len(it.seriesMeta) {
+		return false
+	}
+
+	it.idx++
+	values := ts.NewFixedStepValues(it.bounds.StepSize, it.numValues, math.NaN(), it.bounds.Start)
+	valIdx := 0
+	for idx, iter := range it.seriesIters {
+		if !iter.Next() {
+			it.err = fmt.Errorf("invalid number of datapoints for series: %d, block: %d", it.idx, idx)
+			return false
+		}
+
+		blockSeries, err := iter.Current()
+		if err != nil {
+			it.err = err
+			return false
 		}
 
-		seriesList[i] = ts.NewSeries(seriesMeta[i].Name, values, seriesMeta[i].Tags)
+		for i := 0; i < blockSeries.Len(); i++ {
+			values.SetValueAt(valIdx, blockSeries.ValueAtStep(i))
+			valIdx++
+		}
 	}
 
-	return seriesList, nil
+	meta := it.seriesMeta[it.idx]
+	it.curr = ts.NewSeries(meta.Name, values, meta.Tags)
+	return true
+}
+
+// Current returns the current series.
+func (it *sortedBlocksSeriesIter) Current() *ts.Series {
+	return it.curr
+}
+
+// Err returns any error encountered during iteration.
+func (it *sortedBlocksSeriesIter) Err() error {
+	return it.err
 }
 
 func insertSortedBlock(b block.Block, blockList []blockWithMeta, stepCount, seriesCount int) ([]blockWithMeta, error) {
@@ -22,8 +22,10 @@ package native
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/m3db/m3/src/query/block"
@@ -45,7 +47,7 @@ func TestPromRead(t *testing.T) {
 	mockStorage := mock.NewMockStorage()
 	mockStorage.SetFetchBlocksResult(block.Result{Blocks: []block.Block{b}}, nil)
 
-	promRead := &PromReadHandler{engine: executor.NewEngine(mockStorage)}
+	promRead := &PromReadHandler{engine: executor.NewEngine(mockStorage, 0)}
 	req, _ := http.NewRequest("GET", PromReadURL, nil)
 	req.URL.RawQuery = defaultParams().Encode()
 
@@ -61,3 +63,30 @@ func TestPromRead(t *testing.T) {
 		assert.Equal(t, float64(i), s.Values().ValueAt(i))
 	}
 }
+
+func TestPromReadStream(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	values, bounds := test.GenerateValuesAndBounds(nil, nil)
+	mockStorage := mock.NewMockStorage()
+	promRead := &PromReadHandler{engine: executor.NewEngine(mockStorage, 0)}
+
+	serve := func(stream bool) []byte {
+		b := test.NewBlockFromValues(bounds, values)
+		mockStorage.SetFetchBlocksResult(block.Result{Blocks: []block.Block{b}}, nil)
+
+		params := defaultParams()
+		params.Add(streamParam, strconv.FormatBool(stream))
+		req := httptest.NewRequest("GET", PromReadURL+"?"+params.Encode(), nil)
+		recorder := httptest.NewRecorder()
+		promRead.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder.Body.Bytes()
+	}
+
+	var streamed, buffered []map[string]interface{}
+	require.NoError(t, json.Unmarshal(serve(true), &streamed))
+	require.NoError(t, json.Unmarshal(serve(false), &buffered))
+	require.Len(t, streamed, 2)
+	assert.Equal(t, buffered, streamed)
+}
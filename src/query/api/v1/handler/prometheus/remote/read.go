@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/m3db/m3/src/query/api/v1/handler"
@@ -45,6 +46,9 @@ const (
 
 	// PromReadHTTPMethod is the HTTP method used with this resource.
 	PromReadHTTPMethod = http.MethodPost
+
+	limitParam         = "limit"
+	sampleOnLimitParam = "sampleOnLimit"
 )
 
 // PromReadHandler represents a handler for prometheus read endpoint.
@@ -93,7 +97,14 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.read(ctx, w, req, timeout)
+	opts, rErr := parseEngineOptions(r)
+	if rErr != nil {
+		h.promReadMetrics.fetchErrorsClient.Inc(1)
+		handler.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	result, err := h.read(ctx, w, req, opts, timeout)
 	if err != nil {
 		h.promReadMetrics.fetchErrorsServer.Inc(1)
 		logger.Error("unable to fetch data", zap.Any("error", err))
@@ -101,6 +112,10 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, res := range result {
+		logging.RecordSeries(ctx, len(res.Timeseries))
+	}
+
 	resp := &prompb.ReadResponse{
 		Results: result,
 	}
@@ -141,7 +156,38 @@ func (h *PromReadHandler) parseRequest(r *http.Request) (*prompb.ReadRequest, *h
 	return &req, nil
 }
 
-func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, r *prompb.ReadRequest, timeout time.Duration) ([]*prompb.QueryResult, error) {
+// parseEngineOptions parses the optional series limit and whether to sample
+// series rather than truncate them when the limit is exceeded.
+func parseEngineOptions(r *http.Request) (*executor.EngineOptions, *handler.ParseError) {
+	opts := &executor.EngineOptions{}
+	if str := r.URL.Query().Get(limitParam); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil || limit < 0 {
+			return nil, handler.NewParseError(
+				fmt.Errorf("invalid limit: %s", str), http.StatusBadRequest)
+		}
+		opts.Limit = limit
+	}
+
+	if str := r.URL.Query().Get(sampleOnLimitParam); str != "" {
+		sample, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, handler.NewParseError(
+				fmt.Errorf("invalid sampleOnLimit: %s", str), http.StatusBadRequest)
+		}
+		opts.SampleOnLimit = sample
+	}
+
+	return opts, nil
+}
+
+func (h *PromReadHandler) read(
+	reqCtx context.Context,
+	w http.ResponseWriter,
+	r *prompb.ReadRequest,
+	opts *executor.EngineOptions,
+	timeout time.Duration,
+) ([]*prompb.QueryResult, error) {
 	// TODO: Handle multi query use case
 	if len(r.Queries) != 1 {
 		return nil, fmt.Errorf("prometheus read endpoint currently only supports one query at a time")
@@ -158,7 +204,6 @@ func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, r
 	// Results is closed by execute
 	results := make(chan *storage.QueryResult)
 
-	opts := &executor.EngineOptions{}
 	// Detect clients closing connections
 	abortCh, closingCh := handler.CloseWatcher(ctx, w)
 	opts.AbortCh = abortCh
@@ -171,6 +216,12 @@ func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, r
 			return nil, result.Err
 		}
 
+		if result.FetchResult.Truncated {
+			w.Header().Set(handler.TruncatedHeader, "true")
+			w.Header().Set(handler.MatchedSeriesHeader,
+				strconv.Itoa(result.FetchResult.MatchedSeries))
+		}
+
 		promRes := storage.FetchResultToPromResult(result.FetchResult)
 		promResults = append(promResults, promRes)
 	}
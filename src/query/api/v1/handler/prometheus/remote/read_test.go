@@ -54,7 +54,7 @@ func setupServer(t *testing.T) *httptest.Server {
 	lstore, session := local.NewStorageAndSession(t, ctrl)
 	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, fmt.Errorf("not initialized"))
 	storage := test.NewSlowStorage(lstore, 10*time.Millisecond)
-	engine := executor.NewEngine(storage)
+	engine := executor.NewEngine(storage, 0)
 	promRead := &PromReadHandler{engine: engine, promReadMetrics: promReadTestMetrics}
 	server := httptest.NewServer(test.NewSlowHandler(promRead, 10*time.Millisecond))
 	return server
@@ -64,7 +64,7 @@ func TestPromReadParsing(t *testing.T) {
 	logging.InitWithCores(nil)
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
-	promRead := &PromReadHandler{engine: executor.NewEngine(storage), promReadMetrics: promReadTestMetrics}
+	promRead := &PromReadHandler{engine: executor.NewEngine(storage, 0), promReadMetrics: promReadTestMetrics}
 	req, _ := http.NewRequest("POST", PromReadURL, test.GeneratePromReadBody(t))
 
 	r, err := promRead.parseRequest(req)
@@ -76,7 +76,7 @@ func TestPromReadParsingBad(t *testing.T) {
 	logging.InitWithCores(nil)
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
-	promRead := &PromReadHandler{engine: executor.NewEngine(storage), promReadMetrics: promReadTestMetrics}
+	promRead := &PromReadHandler{engine: executor.NewEngine(storage, 0), promReadMetrics: promReadTestMetrics}
 	req, _ := http.NewRequest("POST", PromReadURL, strings.NewReader("bad body"))
 	_, err := promRead.parseRequest(req)
 	require.NotNil(t, err, "unable to parse request")
@@ -87,9 +87,10 @@ func TestPromReadStorageWithFetchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, session := local.NewStorageAndSession(t, ctrl)
 	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, true, fmt.Errorf("unable to get data"))
-	promRead := &PromReadHandler{engine: executor.NewEngine(storage), promReadMetrics: promReadTestMetrics}
+	promRead := &PromReadHandler{engine: executor.NewEngine(storage, 0), promReadMetrics: promReadTestMetrics}
 	req := test.GeneratePromReadRequest()
-	_, err := promRead.read(context.TODO(), httptest.NewRecorder(), req, time.Hour)
+	_, err := promRead.read(context.TODO(), httptest.NewRecorder(), req,
+		&executor.EngineOptions{}, time.Hour)
 	require.NotNil(t, err, "unable to read from storage")
 }
 
@@ -141,7 +142,7 @@ func TestReadErrorMetricsCount(t *testing.T) {
 	defer closer.Close()
 	readMetrics := newPromReadMetrics(scope)
 
-	promRead := &PromReadHandler{engine: executor.NewEngine(storage), promReadMetrics: readMetrics}
+	promRead := &PromReadHandler{engine: executor.NewEngine(storage, 0), promReadMetrics: readMetrics}
 	req, _ := http.NewRequest("POST", PromReadURL, test.GeneratePromReadBody(t))
 	promRead.ServeHTTP(httptest.NewRecorder(), req)
 
@@ -151,3 +152,21 @@ func TestReadErrorMetricsCount(t *testing.T) {
 	}, 5*time.Second)
 	require.True(t, foundMetric)
 }
+
+func TestPromReadParseEngineOptions(t *testing.T) {
+	req := httptest.NewRequest("POST", PromReadURL+"?limit=100&sampleOnLimit=true", nil)
+	opts, err := parseEngineOptions(req)
+	require.Nil(t, err)
+	assert.Equal(t, 100, opts.Limit)
+	assert.True(t, opts.SampleOnLimit)
+
+	req = httptest.NewRequest("POST", PromReadURL, nil)
+	opts, err = parseEngineOptions(req)
+	require.Nil(t, err)
+	assert.Equal(t, 0, opts.Limit)
+	assert.False(t, opts.SampleOnLimit)
+
+	req = httptest.NewRequest("POST", PromReadURL+"?limit=-1", nil)
+	_, err = parseEngineOptions(req)
+	require.NotNil(t, err)
+}
@@ -23,14 +23,23 @@ package remote
 import (
 	"context"
 	"errors"
+	"fmt"
+	"mime"
+	"net"
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb/writev2"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingest"
+	"github.com/m3db/m3/src/query/storage/metadata"
+	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	xerrors "github.com/m3db/m3x/errors"
 
@@ -45,6 +54,33 @@ const (
 
 	// PromWriteHTTPMethod is the HTTP method used with this resource.
 	PromWriteHTTPMethod = http.MethodPost
+
+	// PromWriteV1ProtoMessage is the protobuf message of remote write 1.0.
+	PromWriteV1ProtoMessage = "prometheus.WriteRequest"
+
+	// PromWriteV2ProtoMessage is the protobuf message of remote write 2.0.
+	PromWriteV2ProtoMessage = "io.prometheus.write.v2.Request"
+
+	// PromWriteSamplesWrittenHeader is the remote write 2.0 response header
+	// for the number of samples written.
+	PromWriteSamplesWrittenHeader = "X-Prometheus-Remote-Write-Samples-Written"
+
+	// PromWriteHistogramsWrittenHeader is the remote write 2.0 response header
+	// for the number of histograms written.
+	PromWriteHistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+
+	// PromWriteExemplarsWrittenHeader is the remote write 2.0 response header
+	// for the number of exemplars written.
+	PromWriteExemplarsWrittenHeader = "X-Prometheus-Remote-Write-Exemplars-Written"
+
+	// PromWriteWriterHeader is an optional request header identifying the
+	// writer for clock skew reports, the remote address is used if not set.
+	PromWriteWriterHeader = "M3-Writer"
+
+	// PromWriteIdempotencyKeyHeader is an optional client generated request
+	// header identifying the write, requests with a key already applied
+	// within the dedup window are acknowledged without being written again.
+	PromWriteIdempotencyKeyHeader = "M3-Idempotency-Key"
 )
 
 var (
@@ -55,13 +91,25 @@ var (
 type PromWriteHandler struct {
 	store            storage.Storage
 	downsampler      downsample.Downsampler
+	metadataStore    metadata.Store
+	ingestStats      ingest.Tracker
+	skewTracker      ingest.SkewTracker
+	idempotency      ingest.IdempotencyCache
 	promWriteMetrics promWriteMetrics
 }
 
-// NewPromWriteHandler returns a new instance of handler.
+// NewPromWriteHandler returns a new instance of handler, metric metadata
+// received via remote write 2.0 is recorded to the metadata store if set
+// and datapoints received are recorded to the ingest stats tracker if set.
+// The latest datapoint of each request is recorded to the skew tracker if set
+// and requests with an idempotency key are deduplicated if the cache is set.
 func NewPromWriteHandler(
 	store storage.Storage,
 	downsampler downsample.Downsampler,
+	metadataStore metadata.Store,
+	ingestStats ingest.Tracker,
+	skewTracker ingest.SkewTracker,
+	idempotency ingest.IdempotencyCache,
 	scope tally.Scope,
 ) (http.Handler, error) {
 	if store == nil && downsampler == nil {
@@ -70,6 +118,10 @@ func NewPromWriteHandler(
 	return &PromWriteHandler{
 		store:            store,
 		downsampler:      downsampler,
+		metadataStore:    metadataStore,
+		ingestStats:      ingestStats,
+		skewTracker:      skewTracker,
+		idempotency:      idempotency,
 		promWriteMetrics: newPromWriteMetrics(scope),
 	}, nil
 }
@@ -78,6 +130,11 @@ type promWriteMetrics struct {
 	writeSuccess      tally.Counter
 	writeErrorsServer tally.Counter
 	writeErrorsClient tally.Counter
+	writeV2Requests   tally.Counter
+	writeDeduplicated tally.Counter
+	histogramsDropped tally.Counter
+	exemplarsDropped  tally.Counter
+	metadataDropped   tally.Counter
 }
 
 func newPromWriteMetrics(scope tally.Scope) promWriteMetrics {
@@ -85,51 +142,329 @@ func newPromWriteMetrics(scope tally.Scope) promWriteMetrics {
 		writeSuccess:      scope.Counter("write.success"),
 		writeErrorsServer: scope.Tagged(map[string]string{"code": "5XX"}).Counter("write.errors"),
 		writeErrorsClient: scope.Tagged(map[string]string{"code": "4XX"}).Counter("write.errors"),
+		writeV2Requests:   scope.Counter("write.v2-requests"),
+		writeDeduplicated: scope.Counter("write.deduplicated"),
+		histogramsDropped: scope.Counter("write.histograms-dropped"),
+		exemplarsDropped:  scope.Counter("write.exemplars-dropped"),
+		metadataDropped:   scope.Counter("write.metadata-dropped"),
 	}
 }
 
+// promWriteStats are the stats of a parsed write request.
+type promWriteStats struct {
+	v2         bool
+	samples    int
+	histograms int
+	exemplars  int
+}
+
 func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	req, rErr := h.parseRequest(r)
+	req, stats, rErr := h.parseRequest(r)
 	if rErr != nil {
 		h.promWriteMetrics.writeErrorsClient.Inc(1)
 		handler.Error(w, rErr.Inner(), rErr.Code())
 		return
 	}
-	if err := h.write(r.Context(), req); err != nil {
+	if stats.v2 {
+		h.promWriteMetrics.writeV2Requests.Inc(1)
+		h.promWriteMetrics.histogramsDropped.Inc(int64(stats.histograms))
+		h.promWriteMetrics.exemplarsDropped.Inc(int64(stats.exemplars))
+	}
+
+	// NB: the key is reserved before writing so that concurrent retries of
+	// the same request wait for the write in flight rather than writing too.
+	var (
+		idempotencyKey = r.Header.Get(PromWriteIdempotencyKeyHeader)
+		applied        ingest.AppliedWrites
+		reserved       bool
+	)
+	if h.idempotency != nil && idempotencyKey != "" {
+		var err error
+		applied, reserved, err = h.idempotency.Reserve(r.Context(), idempotencyKey)
+		if err != nil {
+			h.promWriteMetrics.writeErrorsServer.Inc(1)
+			handler.Error(w, err, http.StatusServiceUnavailable)
+			return
+		}
+		if !reserved {
+			// Already applied, acknowledge the retry without writing it again.
+			h.promWriteMetrics.writeDeduplicated.Inc(1)
+			h.writeSuccess(w, stats)
+			return
+		}
+		if applied == nil {
+			applied = make(ingest.AppliedWrites)
+		}
+	}
+
+	h.recordIngestStats(req)
+	h.recordSkew(r, req)
+	logging.RecordSeries(r.Context(), len(req.Timeseries))
+	if err := h.write(r.Context(), req, applied); err != nil {
+		if reserved {
+			// NB: the writes that were applied are remembered so that the
+			// retry only writes the series that failed.
+			h.idempotency.Release(idempotencyKey, applied)
+		}
 		h.promWriteMetrics.writeErrorsServer.Inc(1)
 		logging.WithContext(r.Context()).Error("Write error", zap.Any("err", err))
 		handler.Error(w, err, http.StatusInternalServerError)
 		return
 	}
+	if reserved {
+		h.idempotency.Record(idempotencyKey)
+	}
+	h.writeSuccess(w, stats)
+}
 
+func (h *PromWriteHandler) writeSuccess(w http.ResponseWriter, stats promWriteStats) {
+	if stats.v2 {
+		// NB: Native histograms and exemplars are not stored so are
+		// reported as not written.
+		w.Header().Set(PromWriteSamplesWrittenHeader, strconv.Itoa(stats.samples))
+		w.Header().Set(PromWriteHistogramsWrittenHeader, "0")
+		w.Header().Set(PromWriteExemplarsWrittenHeader, "0")
+	}
 	h.promWriteMetrics.writeSuccess.Inc(1)
 }
 
-func (h *PromWriteHandler) parseRequest(r *http.Request) (*prompb.WriteRequest, *handler.ParseError) {
+func (h *PromWriteHandler) parseRequest(
+	r *http.Request,
+) (*prompb.WriteRequest, promWriteStats, *handler.ParseError) {
+	v2, err := isPromWriteV2Request(r)
+	if err != nil {
+		return nil, promWriteStats{}, err
+	}
+
 	reqBuf, err := prometheus.ParsePromCompressedRequest(r)
 	if err != nil {
-		return nil, err
+		return nil, promWriteStats{}, err
+	}
+
+	if v2 {
+		var v2Req writev2.Request
+		if err := v2Req.Unmarshal(reqBuf); err != nil {
+			return nil, promWriteStats{}, handler.NewParseError(err, http.StatusBadRequest)
+		}
+		req, stats, err := promWriteV2ToV1(&v2Req)
+		if err != nil {
+			return nil, promWriteStats{}, handler.NewParseError(err, http.StatusBadRequest)
+		}
+		if err := h.recordMetadata(&v2Req); err != nil {
+			return nil, promWriteStats{}, handler.NewParseError(err, http.StatusBadRequest)
+		}
+		return req, stats, nil
 	}
 
 	var req prompb.WriteRequest
 	if err := proto.Unmarshal(reqBuf, &req); err != nil {
-		return nil, handler.NewParseError(err, http.StatusBadRequest)
+		return nil, promWriteStats{}, handler.NewParseError(err, http.StatusBadRequest)
+	}
+
+	return &req, promWriteStats{}, nil
+}
+
+// isPromWriteV2Request negotiates the remote write protocol version using
+// the proto parameter of the content type, defaulting to remote write 1.0.
+func isPromWriteV2Request(r *http.Request) (bool, *handler.ParseError) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return false, nil
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false, handler.NewParseError(err, http.StatusBadRequest)
+	}
+
+	switch msg := params["proto"]; msg {
+	case "", PromWriteV1ProtoMessage:
+		return false, nil
+	case PromWriteV2ProtoMessage:
+		return true, nil
+	default:
+		err := fmt.Errorf("unsupported remote write protobuf message: %s", msg)
+		return false, handler.NewParseError(err, http.StatusUnsupportedMediaType)
+	}
+}
+
+// promWriteV2ToV1 converts a remote write 2.0 request to the remote write 1.0
+// request that is ingested, resolving the label symbol references.
+func promWriteV2ToV1(r *writev2.Request) (*prompb.WriteRequest, promWriteStats, error) {
+	var (
+		stats = promWriteStats{v2: true}
+		req   = &prompb.WriteRequest{
+			Timeseries: make([]*prompb.TimeSeries, 0, len(r.Timeseries)),
+		}
+	)
+	for i := range r.Timeseries {
+		ts := &r.Timeseries[i]
+		series := &prompb.TimeSeries{
+			Labels:  make([]*prompb.Label, 0, len(ts.LabelsRefs)/2),
+			Samples: make([]*prompb.Sample, 0, len(ts.Samples)+1),
+		}
+		err := writeV2Labels(r, ts.LabelsRefs, func(name, value string) {
+			series.Labels = append(series.Labels, &prompb.Label{
+				Name:  name,
+				Value: value,
+			})
+		})
+		if err != nil {
+			return nil, stats, err
+		}
+		if s, ok := writeV2CreatedTimestampSample(ts); ok {
+			series.Samples = append(series.Samples, s)
+		}
+		for _, s := range ts.Samples {
+			series.Samples = append(series.Samples, &prompb.Sample{
+				Value:     s.Value,
+				Timestamp: s.Timestamp,
+			})
+		}
+
+		stats.samples += len(ts.Samples)
+		stats.histograms += len(ts.Histograms)
+		stats.exemplars += len(ts.Exemplars)
+		req.Timeseries = append(req.Timeseries, series)
+	}
+	return req, stats, nil
+}
+
+// recordMetadata records the metadata sent with remote write 2.0 series
+// to the metadata store.
+func (h *PromWriteHandler) recordMetadata(r *writev2.Request) error {
+	if h.metadataStore == nil {
+		return nil
+	}
+	for i := range r.Timeseries {
+		ts := &r.Timeseries[i]
+		md := ts.Metadata
+		if md.Type == writev2.Metadata_METRIC_TYPE_UNSPECIFIED &&
+			md.HelpRef == 0 && md.UnitRef == 0 {
+			continue
+		}
+
+		var name string
+		err := writeV2Labels(r, ts.LabelsRefs, func(n, v string) {
+			if n == models.MetricName {
+				name = v
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+
+		help, err := writeV2Symbol(r, md.HelpRef)
+		if err != nil {
+			return err
+		}
+		unit, err := writeV2Symbol(r, md.UnitRef)
+		if err != nil {
+			return err
+		}
+
+		if !h.metadataStore.Add(name, metadata.Metadata{
+			Type: writeV2MetricTypeName(md.Type),
+			Help: help,
+			Unit: unit,
+		}) {
+			h.promWriteMetrics.metadataDropped.Inc(1)
+		}
+	}
+	return nil
+}
+
+func (h *PromWriteHandler) recordIngestStats(r *prompb.WriteRequest) {
+	if h.ingestStats == nil {
+		return
+	}
+
+	// Aggregate per request to avoid contention on the tracker since
+	// requests usually contain many series of the same metric.
+	datapoints := make(map[string]int)
+	for _, ts := range r.Timeseries {
+		for _, l := range ts.Labels {
+			if l.Name == models.MetricName {
+				datapoints[l.Value] += len(ts.Samples)
+				break
+			}
+		}
+	}
+	for name, n := range datapoints {
+		h.ingestStats.Record(name, n)
+	}
+}
+
+func (h *PromWriteHandler) recordSkew(
+	httpReq *http.Request,
+	r *prompb.WriteRequest,
+) {
+	if h.skewTracker == nil {
+		return
+	}
+
+	var latest int64
+	for _, ts := range r.Timeseries {
+		for _, s := range ts.Samples {
+			if s.Timestamp > latest {
+				latest = s.Timestamp
+			}
+		}
+	}
+	if latest == 0 {
+		return
 	}
 
-	return &req, nil
+	writer := httpReq.Header.Get(PromWriteWriterHeader)
+	if writer == "" {
+		writer = httpReq.RemoteAddr
+		if host, _, err := net.SplitHostPort(writer); err == nil {
+			writer = host
+		}
+	}
+	h.skewTracker.Record(writer, storage.TimestampToTime(latest))
 }
 
-func (h *PromWriteHandler) write(ctx context.Context, r *prompb.WriteRequest) error {
+// write writes the series of the request, skipping the writes in applied
+// and adding the writes that succeed to it if applied is not nil.
+func (h *PromWriteHandler) write(
+	ctx context.Context,
+	r *prompb.WriteRequest,
+	applied ingest.AppliedWrites,
+) error {
 	var (
 		wg            sync.WaitGroup
+		appliedLock   sync.Mutex
 		writeUnaggErr error
 		writeAggErr   error
 	)
+	markApplied := func(w ingest.AppliedWrite) {
+		if applied == nil {
+			return
+		}
+		appliedLock.Lock()
+		applied[w] = struct{}{}
+		appliedLock.Unlock()
+	}
+	// NB: the writes to skip are resolved up front as applied is added to
+	// concurrently by the writes.
+	var (
+		skipUnagg = make([]bool, len(r.Timeseries))
+		skipAgg   = make([]bool, len(r.Timeseries))
+	)
+	for i := range r.Timeseries {
+		skipUnagg[i] = applied.Contains(ingest.AppliedWrite{Series: i})
+		skipAgg[i] = applied.Contains(ingest.AppliedWrite{Series: i, Aggregated: true})
+	}
+
 	if h.downsampler != nil {
 		// If writing downsampled aggregations, write them async
 		wg.Add(1)
 		go func() {
-			writeAggErr = h.writeAggregated(ctx, r)
+			writeAggErr = h.writeAggregated(ctx, r, skipAgg, markApplied)
 			wg.Done()
 		}()
 	}
@@ -137,7 +472,7 @@ func (h *PromWriteHandler) write(ctx context.Context, r *prompb.WriteRequest) er
 	if h.store != nil {
 		// Write the unaggregated points out, don't spawn goroutine
 		// so we reduce number of goroutines just a fraction
-		writeUnaggErr = h.writeUnaggregated(ctx, r)
+		writeUnaggErr = h.writeUnaggregated(ctx, r, skipUnagg, markApplied)
 	}
 
 	if h.downsampler != nil {
@@ -155,14 +490,19 @@ func (h *PromWriteHandler) write(ctx context.Context, r *prompb.WriteRequest) er
 func (h *PromWriteHandler) writeUnaggregated(
 	ctx context.Context,
 	r *prompb.WriteRequest,
+	skip []bool,
+	markApplied func(ingest.AppliedWrite),
 ) error {
 	var (
 		wg       sync.WaitGroup
 		errLock  sync.Mutex
 		multiErr xerrors.MultiError
 	)
-	for _, t := range r.Timeseries {
-		t := t // Capture for goroutine
+	for i, t := range r.Timeseries {
+		if skip[i] {
+			continue
+		}
+		i, t := i, t // Capture for goroutine
 
 		// TODO(r): Consider adding a worker pool to limit write
 		// request concurrency, instead of using the batch size
@@ -178,6 +518,8 @@ func (h *PromWriteHandler) writeUnaggregated(
 				errLock.Lock()
 				multiErr = multiErr.Add(err)
 				errLock.Unlock()
+			} else {
+				markApplied(ingest.AppliedWrite{Series: i})
 			}
 
 			wg.Done()
@@ -192,14 +534,19 @@ func (h *PromWriteHandler) writeUnaggregated(
 func (h *PromWriteHandler) writeAggregated(
 	_ context.Context,
 	r *prompb.WriteRequest,
+	skip []bool,
+	markApplied func(ingest.AppliedWrite),
 ) error {
 	var (
 		metricsAppender = h.downsampler.NewMetricsAppender()
 		multiErr        xerrors.MultiError
 	)
-	for _, ts := range r.Timeseries {
+	for i, t := range r.Timeseries {
+		if skip[i] {
+			continue
+		}
 		metricsAppender.Reset()
-		for _, label := range ts.Labels {
+		for _, label := range t.Labels {
 			metricsAppender.AddTag(label.Name, label.Value)
 		}
 
@@ -209,12 +556,23 @@ func (h *PromWriteHandler) writeAggregated(
 			continue
 		}
 
-		for _, elem := range ts.Samples {
-			err := samplesAppender.AppendGaugeSample(elem.Value)
+		seriesErr := false
+		for _, elem := range t.Samples {
+			if ts.IsStaleNaN(elem.Value) {
+				// NB: Staleness markers are only meaningful for the raw
+				// series, aggregating them would poison the aggregate.
+				continue
+			}
+			err := samplesAppender.AppendGaugeTimedSample(
+				storage.TimestampToTime(elem.Timestamp), elem.Value)
 			if err != nil {
 				multiErr = multiErr.Add(err)
+				seriesErr = true
 			}
 		}
+		if !seriesErr {
+			markApplied(ingest.AppliedWrite{Series: i, Aggregated: true})
+		}
 	}
 
 	metricsAppender.Finalize()
@@ -21,19 +21,25 @@
 package remote
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/x/metrics"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test/remote"
+	"github.com/m3db/m3/src/query/storage/ingest"
+	"github.com/m3db/m3/src/query/storage/metadata"
 	"github.com/m3db/m3/src/query/test/local"
 	"github.com/m3db/m3/src/query/util/logging"
 	xclock "github.com/m3db/m3x/clock"
 
 	"github.com/golang/mock/gomock"
+	"github.com/golang/snappy"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
 )
@@ -49,7 +55,7 @@ func TestPromWriteParsing(t *testing.T) {
 	promReqBody := remote.GeneratePromWriteRequestBody(t, promReq)
 	req, _ := http.NewRequest("POST", PromWriteURL, promReqBody)
 
-	r, err := promWrite.parseRequest(req)
+	r, _, err := promWrite.parseRequest(req)
 	require.Nil(t, err, "unable to parse request")
 	require.Equal(t, len(r.Timeseries), 2)
 }
@@ -67,13 +73,162 @@ func TestPromWrite(t *testing.T) {
 	promReqBody := remote.GeneratePromWriteRequestBody(t, promReq)
 	req, _ := http.NewRequest("POST", PromWriteURL, promReqBody)
 
-	r, err := promWrite.parseRequest(req)
+	r, _, err := promWrite.parseRequest(req)
 	require.Nil(t, err, "unable to parse request")
 
-	writeErr := promWrite.write(context.TODO(), r)
+	writeErr := promWrite.write(context.TODO(), r, nil)
 	require.NoError(t, writeErr)
 }
 
+func TestPromWriteRecordsIngestStats(t *testing.T) {
+	tracker := ingest.NewTracker(ingest.TrackerOptions{})
+	promWrite := &PromWriteHandler{ingestStats: tracker}
+
+	promReq := remote.GeneratePromWriteRequest()
+	promReq.Timeseries[1].Samples = append(promReq.Timeseries[1].Samples,
+		promReq.Timeseries[1].Samples[0])
+	promWrite.recordIngestStats(promReq)
+
+	top := tracker.Top(10)
+	require.Len(t, top, 2)
+	require.Equal(t, "second", top[0].Name)
+	require.Equal(t, int64(3), top[0].Datapoints)
+	require.Equal(t, "first", top[1].Name)
+	require.Equal(t, int64(2), top[1].Datapoints)
+}
+
+func TestPromWriteV2(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	storage, session := local.NewStorageAndSession(t, ctrl)
+	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	metadataStore := metadata.NewStore(10, 10)
+	promWrite := &PromWriteHandler{
+		store:            storage,
+		metadataStore:    metadataStore,
+		promWriteMetrics: newPromWriteMetrics(tally.NoopScope),
+	}
+
+	body := snappy.Encode(nil, newTestWriteV2Request())
+	req, _ := http.NewRequest("POST", PromWriteURL, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf;proto="+PromWriteV2ProtoMessage)
+
+	r, stats, err := promWrite.parseRequest(req)
+	require.Nil(t, err, "unable to parse request")
+	require.True(t, stats.v2)
+	require.Equal(t, 1, stats.samples)
+	require.Equal(t, 1, stats.histograms)
+	require.Equal(t, 1, stats.exemplars)
+	require.Equal(t, 1, len(r.Timeseries))
+	require.Equal(t, 2, len(r.Timeseries[0].Labels))
+	require.Equal(t, "http_requests_total", r.Timeseries[0].Labels[0].Value)
+	require.Equal(t, 2, len(r.Timeseries[0].Samples))
+	require.Equal(t, map[string][]metadata.Metadata{
+		"http_requests_total": {{Type: "counter", Help: "Total HTTP requests", Unit: "requests"}},
+	}, metadataStore.Query(metadata.Query{}))
+
+	req, _ = http.NewRequest("POST", PromWriteURL, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf;proto="+PromWriteV2ProtoMessage)
+	recorder := httptest.NewRecorder()
+	promWrite.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get(PromWriteSamplesWrittenHeader))
+	require.Equal(t, "0", recorder.Header().Get(PromWriteExemplarsWrittenHeader))
+}
+
+func TestPromWriteDeduplicatesIdempotencyKey(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	storage, session := local.NewStorageAndSession(t, ctrl)
+	var numWrites int
+	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_, _, _, _, _, _, _ interface{}) { numWrites++ }).AnyTimes()
+
+	promWrite := &PromWriteHandler{
+		store:            storage,
+		idempotency:      ingest.NewIdempotencyCache(time.Minute, 0, nil),
+		promWriteMetrics: newPromWriteMetrics(tally.NoopScope),
+	}
+
+	serve := func() {
+		promReq := remote.GeneratePromWriteRequest()
+		req, _ := http.NewRequest("POST", PromWriteURL, remote.GeneratePromWriteRequestBody(t, promReq))
+		req.Header.Set(PromWriteIdempotencyKeyHeader, "batch-1")
+		recorder := httptest.NewRecorder()
+		promWrite.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	serve()
+	written := numWrites
+	require.True(t, written > 0)
+
+	serve()
+	require.Equal(t, written, numWrites)
+}
+
+func TestPromWriteRetriesIdempotencyKeyOfFailedWrite(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	storage, session := local.NewStorageAndSession(t, ctrl)
+	var (
+		numWritesLock sync.Mutex
+		numWrites     int
+	)
+	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("write error"))
+	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_, _, _, _, _, _, _ interface{}) {
+			numWritesLock.Lock()
+			numWrites++
+			numWritesLock.Unlock()
+		}).Return(nil).AnyTimes()
+
+	promWrite := &PromWriteHandler{
+		store:            storage,
+		idempotency:      ingest.NewIdempotencyCache(time.Minute, 0, nil),
+		promWriteMetrics: newPromWriteMetrics(tally.NoopScope),
+	}
+
+	serve := func() int {
+		promReq := remote.GeneratePromWriteRequest()
+		req, _ := http.NewRequest("POST", PromWriteURL, remote.GeneratePromWriteRequestBody(t, promReq))
+		req.Header.Set(PromWriteIdempotencyKeyHeader, "batch-1")
+		recorder := httptest.NewRecorder()
+		promWrite.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	// The key of the failed write is released so the retry is written, but
+	// only the series that failed are written again.
+	require.Equal(t, http.StatusInternalServerError, serve())
+	numWritesLock.Lock()
+	numWrites = 0
+	numWritesLock.Unlock()
+	require.Equal(t, http.StatusOK, serve())
+	numWritesLock.Lock()
+	require.Equal(t, 2, numWrites)
+	numWritesLock.Unlock()
+
+	// Once applied the retry is not written at all.
+	require.Equal(t, http.StatusOK, serve())
+	numWritesLock.Lock()
+	require.Equal(t, 2, numWrites)
+	numWritesLock.Unlock()
+}
+
+func TestPromWriteUnsupportedProtoMessage(t *testing.T) {
+	req, _ := http.NewRequest("POST", PromWriteURL, nil)
+	req.Header.Set("Content-Type", "application/x-protobuf;proto=unknown.Message")
+	_, err := isPromWriteV2Request(req)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusUnsupportedMediaType, err.Code())
+}
+
 func TestWriteErrorMetricCount(t *testing.T) {
 	logging.InitWithCores(nil)
 
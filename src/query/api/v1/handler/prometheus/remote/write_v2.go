@@ -0,0 +1,103 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb/writev2"
+)
+
+// writeV2MetricTypeName returns the Prometheus exposition name of the
+// remote write 2.0 metric type.
+func writeV2MetricTypeName(t writev2.Metadata_MetricType) string {
+	switch t {
+	case writev2.Metadata_METRIC_TYPE_COUNTER:
+		return "counter"
+	case writev2.Metadata_METRIC_TYPE_GAUGE:
+		return "gauge"
+	case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
+		return "histogram"
+	case writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+		return "gaugehistogram"
+	case writev2.Metadata_METRIC_TYPE_SUMMARY:
+		return "summary"
+	case writev2.Metadata_METRIC_TYPE_INFO:
+		return "info"
+	case writev2.Metadata_METRIC_TYPE_STATESET:
+		return "stateset"
+	}
+	return "unknown"
+}
+
+// writeV2Symbol resolves a symbol reference of a remote write 2.0 request.
+func writeV2Symbol(r *writev2.Request, ref uint32) (string, error) {
+	if int(ref) >= len(r.Symbols) {
+		return "", fmt.Errorf("symbol reference %d out of range of %d symbols",
+			ref, len(r.Symbols))
+	}
+	return r.Symbols[ref], nil
+}
+
+// writeV2Labels resolves label references as name and value pairs.
+func writeV2Labels(
+	r *writev2.Request,
+	refs []uint32,
+	fn func(name, value string),
+) error {
+	if len(refs)%2 != 0 {
+		return fmt.Errorf("odd number of label references: %d", len(refs))
+	}
+	for i := 0; i < len(refs); i += 2 {
+		name, err := writeV2Symbol(r, refs[i])
+		if err != nil {
+			return err
+		}
+		value, err := writeV2Symbol(r, refs[i+1])
+		if err != nil {
+			return err
+		}
+		fn(name, value)
+	}
+	return nil
+}
+
+// writeV2CreatedTimestampSample returns the zero sample written at the
+// created timestamp of a cumulative series so that the reset is not lost,
+// it returns false if the series has no created timestamp or it does not
+// precede the samples of the series.
+func writeV2CreatedTimestampSample(ts *writev2.TimeSeries) (*prompb.Sample, bool) {
+	if ts.CreatedTimestamp == 0 || len(ts.Samples) == 0 {
+		return nil, false
+	}
+	switch ts.Metadata.Type {
+	case writev2.Metadata_METRIC_TYPE_COUNTER,
+		writev2.Metadata_METRIC_TYPE_HISTOGRAM,
+		writev2.Metadata_METRIC_TYPE_SUMMARY:
+	default:
+		return nil, false
+	}
+	if ts.CreatedTimestamp >= ts.Samples[0].Timestamp {
+		return nil, false
+	}
+	return &prompb.Sample{Timestamp: ts.CreatedTimestamp}, true
+}
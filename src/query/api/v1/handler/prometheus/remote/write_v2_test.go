@@ -0,0 +1,127 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb/writev2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWriteV2Request() []byte {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "http_requests_total",
+			"job", "api", "trace_id", "abc", "Total HTTP requests", "requests"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []writev2.Sample{{Value: 42.5, Timestamp: 1000}},
+				Histograms: []writev2.Histogram{{Timestamp: 1000}},
+				Exemplars: []writev2.Exemplar{
+					{LabelsRefs: []uint32{5, 6}, Value: 1.5, Timestamp: 999},
+				},
+				Metadata: writev2.Metadata{
+					Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+					HelpRef: 7,
+					UnitRef: 8,
+				},
+				CreatedTimestamp: 500,
+			},
+		},
+	}
+	buf, err := req.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+func TestPromWriteV2ToV1(t *testing.T) {
+	var req writev2.Request
+	require.NoError(t, req.Unmarshal(newTestWriteV2Request()))
+
+	r, stats, err := promWriteV2ToV1(&req)
+	require.NoError(t, err)
+	assert.Equal(t, promWriteStats{
+		v2:         true,
+		samples:    1,
+		histograms: 1,
+		exemplars:  1,
+	}, stats)
+
+	require.Equal(t, 1, len(r.Timeseries))
+	assert.Equal(t, []*prompb.Label{
+		{Name: "__name__", Value: "http_requests_total"},
+		{Name: "job", Value: "api"},
+	}, r.Timeseries[0].Labels)
+
+	// The created timestamp precedes the samples of the counter so is
+	// written as a zero sample.
+	assert.Equal(t, []*prompb.Sample{
+		{Value: 0, Timestamp: 500},
+		{Value: 42.5, Timestamp: 1000},
+	}, r.Timeseries[0].Samples)
+}
+
+func TestWriteV2CreatedTimestampSample(t *testing.T) {
+	ts := writev2.TimeSeries{
+		Samples:          []writev2.Sample{{Value: 1, Timestamp: 1000}},
+		Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER},
+		CreatedTimestamp: 500,
+	}
+	s, ok := writeV2CreatedTimestampSample(&ts)
+	require.True(t, ok)
+	assert.Equal(t, &prompb.Sample{Timestamp: 500}, s)
+
+	ts.CreatedTimestamp = 1000
+	_, ok = writeV2CreatedTimestampSample(&ts)
+	assert.False(t, ok)
+
+	ts.CreatedTimestamp = 500
+	ts.Metadata.Type = writev2.Metadata_METRIC_TYPE_GAUGE
+	_, ok = writeV2CreatedTimestampSample(&ts)
+	assert.False(t, ok)
+
+	ts.Metadata.Type = writev2.Metadata_METRIC_TYPE_SUMMARY
+	ts.Samples = nil
+	_, ok = writeV2CreatedTimestampSample(&ts)
+	assert.False(t, ok)
+}
+
+func TestWriteV2RequestInvalid(t *testing.T) {
+	buf := newTestWriteV2Request()
+	var req writev2.Request
+	assert.Error(t, req.Unmarshal(buf[:len(buf)-3]))
+
+	req = writev2.Request{}
+	require.NoError(t, req.Unmarshal(buf))
+	noop := func(name, value string) {}
+	assert.Error(t, writeV2Labels(&req, []uint32{1}, noop))
+	assert.Error(t, writeV2Labels(&req, []uint32{1, 100}, noop))
+
+	req.Timeseries[0].LabelsRefs = []uint32{1, 100}
+	_, _, err := promWriteV2ToV1(&req)
+	assert.Error(t, err)
+}
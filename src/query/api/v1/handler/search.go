@@ -85,6 +85,11 @@ func (h *SearchHandler) parseBody(r *http.Request) (*storage.FetchQuery, *ParseE
 	if err := json.Unmarshal(body, &fetchQuery); err != nil {
 		return nil, NewParseError(err, http.StatusBadRequest)
 	}
+	if filter := fetchQuery.Filter; filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, NewParseError(err, http.StatusBadRequest)
+		}
+	}
 
 	return &fetchQuery, nil
 }
@@ -128,3 +128,35 @@ func TestSearchEndpoint(t *testing.T) {
 	defer resp.Body.Close()
 	require.NotNil(t, resp)
 }
+
+func TestSearchParseBodyFilter(t *testing.T) {
+	searchHandler := &SearchHandler{}
+
+	req := generateSearchReq()
+	req.Filter = &storage.Filter{
+		Operator: storage.OrFilter,
+		Filters: []*storage.Filter{
+			{Matcher: &models.Matcher{Type: models.MatchEqual, Name: "foo", Value: "bar"}},
+			{Matcher: &models.Matcher{Type: models.MatchEqual, Name: "foo", Value: "baz"}},
+		},
+	}
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	httpReq := httptest.NewRequest("POST", SearchURL, bytes.NewReader(data))
+	parsed, rErr := searchHandler.parseBody(httpReq)
+	require.Nil(t, rErr)
+	require.NotNil(t, parsed.Filter)
+	assert.Equal(t, storage.OrFilter, parsed.Filter.Operator)
+	require.Len(t, parsed.Filter.Filters, 2)
+
+	// Filters with an operator and a matcher are invalid.
+	req.Filter.Matcher = &models.Matcher{Type: models.MatchEqual, Name: "foo", Value: "bar"}
+	data, err = json.Marshal(req)
+	require.NoError(t, err)
+
+	httpReq = httptest.NewRequest("POST", SearchURL, bytes.NewReader(data))
+	_, rErr = searchHandler.parseBody(httpReq)
+	require.NotNil(t, rErr)
+	assert.Equal(t, http.StatusBadRequest, rErr.Code())
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package topology exposes the versions of the placement and namespace
+// registry, so external tooling can react to topology changes without
+// direct access to etcd.
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/api/v1/handler/placement"
+	"github.com/m3db/m3/src/query/util/logging"
+	clusterclient "github.com/m3db/m3cluster/client"
+	"github.com/m3db/m3cluster/kv"
+	"github.com/m3db/m3cluster/services"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// GetURL is the url for the topology versions handler (with the GET method).
+	GetURL = handler.RoutePrefixV1 + "/topology"
+
+	// WatchURL is the url for the topology watch handler (with the GET method).
+	WatchURL = handler.RoutePrefixV1 + "/topology/watch"
+
+	// GetHTTPMethod is the HTTP method used with these resources.
+	GetHTTPMethod = http.MethodGet
+
+	// PlacementVersionParam is the placement version the caller has seen.
+	PlacementVersionParam = "placementVersion"
+
+	// NamespacesVersionParam is the namespace registry version the caller
+	// has seen.
+	NamespacesVersionParam = "namespacesVersion"
+
+	// TimeoutParam is how long to wait for a change before responding with
+	// the unchanged versions.
+	TimeoutParam = "timeout"
+
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = 5 * time.Minute
+)
+
+var (
+	errTimeoutTooLarge = fmt.Errorf("timeout must not exceed %v", maxWatchTimeout)
+	errTimeoutNegative = errors.New("timeout must not be negative")
+)
+
+// Versions are the versions of the placement and namespace registry, the
+// version is zero if not set.
+type Versions struct {
+	PlacementVersion  int `json:"placementVersion"`
+	NamespacesVersion int `json:"namespacesVersion"`
+}
+
+// changed returns whether the versions differ from the versions seen, a
+// negative version seen matches any version.
+func (v Versions) changed(current Versions) bool {
+	if v.PlacementVersion >= 0 && v.PlacementVersion != current.PlacementVersion {
+		return true
+	}
+	return v.NamespacesVersion >= 0 && v.NamespacesVersion != current.NamespacesVersion
+}
+
+// WatchResponse is the response of a topology watch.
+type WatchResponse struct {
+	Versions
+	Changed bool `json:"changed"`
+}
+
+type versionsFn func(r *http.Request) (Versions, error)
+
+// topologyWatch is notified when the placement or namespace registry may
+// have changed.
+type topologyWatch struct {
+	placement  <-chan struct{}
+	namespaces <-chan struct{}
+	close      func()
+}
+
+type watchFn func(r *http.Request) (topologyWatch, error)
+
+// GetHandler is the handler for topology version gets.
+type GetHandler struct {
+	versionsFn versionsFn
+}
+
+// NewGetHandler returns a new instance of GetHandler.
+func NewGetHandler(client clusterclient.Client) *GetHandler {
+	return &GetHandler{versionsFn: newVersionsFn(client)}
+}
+
+func (h *GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	versions, err := h.versionsFn(r)
+	if err != nil {
+		logger.Error("unable to get topology versions", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	handler.WriteJSONResponse(w, versions, logger)
+}
+
+// WatchHandler is the handler for topology watches, it watches the
+// placement and namespace registry until either version differs from the
+// versions the caller has seen or the timeout elapses.
+type WatchHandler struct {
+	versionsFn versionsFn
+	watchFn    watchFn
+}
+
+// NewWatchHandler returns a new instance of WatchHandler.
+func NewWatchHandler(client clusterclient.Client) *WatchHandler {
+	return &WatchHandler{
+		versionsFn: newVersionsFn(client),
+		watchFn:    newWatchFn(client),
+	}
+}
+
+func (h *WatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	seen, timeout, err := parseWatchParams(r)
+	if err != nil {
+		handler.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// NB: watch before reading the versions so that no change between
+	// reading the versions and waiting for changes is missed.
+	watch, err := h.watchFn(r)
+	if err != nil {
+		logger.Error("unable to watch topology", zap.Any("error", err))
+		handler.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	defer watch.close()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		versions, err := h.versionsFn(r)
+		if err != nil {
+			logger.Error("unable to get topology versions", zap.Any("error", err))
+			handler.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if seen == nil || seen.changed(versions) {
+			handler.WriteJSONResponse(w, WatchResponse{Versions: versions, Changed: true}, logger)
+			return
+		}
+
+		// NB: a closed watch is never notified again rather than notified
+		// continuously.
+		select {
+		case _, ok := <-watch.placement:
+			if !ok {
+				watch.placement = nil
+			}
+		case _, ok := <-watch.namespaces:
+			if !ok {
+				watch.namespaces = nil
+			}
+		case <-deadline.C:
+			handler.WriteJSONResponse(w, WatchResponse{Versions: versions}, logger)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// parseWatchParams returns the versions the caller has seen, nil if not
+// specified, and the watch timeout.
+func parseWatchParams(r *http.Request) (*Versions, time.Duration, error) {
+	var (
+		values  = r.URL.Query()
+		seen    *Versions
+		timeout = defaultWatchTimeout
+	)
+	if v := values.Get(TimeoutParam); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		if parsed < 0 {
+			return nil, 0, errTimeoutNegative
+		}
+		if parsed > maxWatchTimeout {
+			return nil, 0, errTimeoutTooLarge
+		}
+		timeout = parsed
+	}
+
+	placementVersion := values.Get(PlacementVersionParam)
+	namespacesVersion := values.Get(NamespacesVersionParam)
+	if placementVersion == "" && namespacesVersion == "" {
+		return nil, timeout, nil
+	}
+
+	// NB: a version not specified matches any version.
+	seen = &Versions{PlacementVersion: -1, NamespacesVersion: -1}
+	if placementVersion != "" {
+		v, err := strconv.Atoi(placementVersion)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid %s: %v", PlacementVersionParam, err)
+		}
+		seen.PlacementVersion = v
+	}
+	if namespacesVersion != "" {
+		v, err := strconv.Atoi(namespacesVersion)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid %s: %v", NamespacesVersionParam, err)
+		}
+		seen.NamespacesVersion = v
+	}
+	return seen, timeout, nil
+}
+
+func newVersionsFn(client clusterclient.Client) versionsFn {
+	return func(r *http.Request) (Versions, error) {
+		var versions Versions
+
+		service, err := placement.Service(client, r.Header)
+		if err != nil {
+			return Versions{}, err
+		}
+		_, placementVersion, err := service.Placement()
+		if err != nil && err != kv.ErrNotFound {
+			return Versions{}, err
+		}
+		if err == nil {
+			versions.PlacementVersion = placementVersion
+		}
+
+		store, err := client.KV()
+		if err != nil {
+			return Versions{}, err
+		}
+		_, namespacesVersion, err := namespace.Metadata(store)
+		if err != nil {
+			return Versions{}, err
+		}
+		versions.NamespacesVersion = namespacesVersion
+		return versions, nil
+	}
+}
+
+func newWatchFn(client clusterclient.Client) watchFn {
+	return func(r *http.Request) (topologyWatch, error) {
+		cs, err := client.Services(services.NewOverrideOptions())
+		if err != nil {
+			return topologyWatch{}, err
+		}
+		placementWatch, err := cs.Watch(placement.ServiceID(r.Header),
+			services.NewQueryOptions())
+		if err != nil {
+			return topologyWatch{}, err
+		}
+
+		store, err := client.KV()
+		if err != nil {
+			placementWatch.Close()
+			return topologyWatch{}, err
+		}
+		namespacesWatch, err := store.Watch(namespace.M3DBNodeNamespacesKey)
+		if err != nil {
+			placementWatch.Close()
+			return topologyWatch{}, err
+		}
+
+		return topologyWatch{
+			placement:  placementWatch.C(),
+			namespaces: namespacesWatch.C(),
+			close: func() {
+				placementWatch.Close()
+				namespacesWatch.Close()
+			},
+		}, nil
+	}
+}
+
+// RegisterRoutes registers the topology routes.
+func RegisterRoutes(r *mux.Router, client clusterclient.Client) {
+	logged := logging.WithResponseTimeLogging
+
+	r.HandleFunc(GetURL, logged(NewGetHandler(client)).ServeHTTP).Methods(GetHTTPMethod)
+	r.HandleFunc(WatchURL, logged(NewWatchHandler(client)).ServeHTTP).Methods(GetHTTPMethod)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topology
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWatch(t *testing.T, h *WatchHandler, query string) (int, WatchResponse) {
+	req := httptest.NewRequest(GetHTTPMethod, WatchURL+"?"+query, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp WatchResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+func TestWatchHandler(t *testing.T) {
+	var (
+		placementVersion int32 = 3
+		placementCh            = make(chan struct{}, 1)
+		closed           int32
+	)
+	h := &WatchHandler{
+		versionsFn: func(r *http.Request) (Versions, error) {
+			return Versions{
+				PlacementVersion:  int(atomic.LoadInt32(&placementVersion)),
+				NamespacesVersion: 7,
+			}, nil
+		},
+		watchFn: func(r *http.Request) (topologyWatch, error) {
+			return topologyWatch{
+				placement:  placementCh,
+				namespaces: make(chan struct{}),
+				close:      func() { atomic.AddInt32(&closed, 1) },
+			}, nil
+		},
+	}
+
+	// Returns immediately without versions seen.
+	code, resp := testWatch(t, h, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, WatchResponse{
+		Versions: Versions{PlacementVersion: 3, NamespacesVersion: 7},
+		Changed:  true,
+	}, resp)
+
+	// Times out unchanged.
+	code, resp = testWatch(t, h, "placementVersion=3&namespacesVersion=7&timeout=10ms")
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, resp.Changed)
+
+	// A version not specified matches any version.
+	code, resp = testWatch(t, h, "placementVersion=3&timeout=10ms")
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, resp.Changed)
+
+	// Returns once changed.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&placementVersion, 4)
+		placementCh <- struct{}{}
+	}()
+	code, resp = testWatch(t, h, "placementVersion=3&namespacesVersion=7&timeout=1m")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Changed)
+	assert.Equal(t, 4, resp.PlacementVersion)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&closed))
+
+	// Invalid params.
+	code, _ = testWatch(t, h, "placementVersion=foo")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testWatch(t, h, "timeout=1h")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
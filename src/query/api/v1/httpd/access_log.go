@@ -0,0 +1,132 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package httpd
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+// accessLogger logs a sample of the requests to the write and query routes.
+type accessLogger struct {
+	logger       *zap.Logger
+	routes       map[string]string
+	sampleRate   float64
+	tenantHeader string
+	tagHeaders   []string
+	randFn       func() float64
+	nowFn        func() time.Time
+}
+
+func newAccessLogger(cfg *config.AccessLogConfiguration, logger *zap.Logger) *accessLogger {
+	if cfg == nil {
+		return nil
+	}
+
+	routes := make(map[string]string)
+	for _, route := range writeRoutes {
+		routes[route] = "write"
+	}
+	for _, route := range queryRoutes {
+		routes[route] = "query"
+	}
+	for _, route := range rangeQueryRoutes {
+		routes[route] = "range-query"
+	}
+	return &accessLogger{
+		logger:       logger,
+		routes:       routes,
+		sampleRate:   cfg.SampleRate,
+		tenantHeader: cfg.TenantHeader,
+		tagHeaders:   cfg.TagHeaders,
+		randFn:       rand.Float64,
+		nowFn:        time.Now,
+	}
+}
+
+func (l *accessLogger) serve(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	if l == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+	class, ok := l.routes[r.URL.Path]
+	if !ok {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	var (
+		start       = l.nowFn()
+		ctx, record = logging.NewContextWithAccessLogRecord(r.Context())
+		sw          = &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	)
+	next.ServeHTTP(sw, r.WithContext(ctx))
+
+	// NB: always log server errors so that failures are never sampled away.
+	if sw.status < http.StatusInternalServerError && l.randFn() >= l.sampleRate {
+		return
+	}
+
+	fields := make([]zap.Field, 0, 8+len(l.tagHeaders))
+	fields = append(fields,
+		zap.String("method", r.Method),
+		zap.String("route", r.URL.Path),
+		zap.String("routeClass", class),
+		zap.Int("status", sw.status),
+		zap.Duration("latency", l.nowFn().Sub(start)),
+		zap.Int64("series", record.Series()),
+		zap.String("remoteAddr", r.RemoteAddr),
+	)
+	if l.tenantHeader != "" {
+		fields = append(fields, zap.String("tenant", r.Header.Get(l.tenantHeader)))
+	}
+	for _, header := range l.tagHeaders {
+		if value := r.Header.Get(header); value != "" {
+			fields = append(fields, zap.String(header, value))
+		}
+	}
+	l.logger.Info("access", fields...)
+}
+
+// statusResponseWriter records the status code written to a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush flushes the underlying response writer, if supported, so that
+// streamed responses are not buffered by the access log.
+func (w *statusResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package httpd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestAccessLogger(
+	cfg config.AccessLogConfiguration,
+) (*accessLogger, *bytes.Buffer) {
+	var (
+		buf     = new(bytes.Buffer)
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+		logger  = zap.New(zapcore.NewCore(encoder, zapcore.AddSync(buf), zap.InfoLevel))
+	)
+	return newAccessLogger(&cfg, logger), buf
+}
+
+func TestAccessLoggerLogsFields(t *testing.T) {
+	l, buf := newTestAccessLogger(config.AccessLogConfiguration{
+		SampleRate:   1,
+		TenantHeader: "M3-Tenant",
+		TagHeaders:   []string{"M3-Source"},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, remote.PromWriteURL, nil)
+	req.Header.Set("M3-Tenant", "foo")
+	req.Header.Set("M3-Source", "bar")
+	l.serve(httptest.NewRecorder(), req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.RecordSeries(r.Context(), 3)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "access", entry["msg"])
+	assert.Equal(t, remote.PromWriteURL, entry["route"])
+	assert.Equal(t, "write", entry["routeClass"])
+	assert.Equal(t, float64(http.StatusAccepted), entry["status"])
+	assert.Equal(t, float64(3), entry["series"])
+	assert.Equal(t, "foo", entry["tenant"])
+	assert.Equal(t, "bar", entry["M3-Source"])
+}
+
+func TestAccessLoggerSampling(t *testing.T) {
+	l, buf := newTestAccessLogger(config.AccessLogConfiguration{SampleRate: 0.5})
+	l.randFn = func() float64 { return 0.7 }
+
+	serve := func(url string, status int) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		l.serve(httptest.NewRecorder(), req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+	}
+
+	// Successful requests outside the sample are not logged.
+	serve(remote.PromReadURL, http.StatusOK)
+	assert.Equal(t, 0, buf.Len())
+
+	// Server errors are always logged.
+	serve(remote.PromReadURL, http.StatusInternalServerError)
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	// Requests to other routes are never logged.
+	l.randFn = func() float64 { return 0 }
+	serve(healthURL, http.StatusInternalServerError)
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package httpd
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/storage"
+	clusterclient "github.com/m3db/m3cluster/client"
+
+	"github.com/gorilla/mux"
+	"github.com/uber-go/tally"
+)
+
+// CustomHandlerOptions are the query service dependencies made available to
+// custom handlers.
+type CustomHandlerOptions struct {
+	Storage       storage.Storage
+	Engine        *executor.Engine
+	ClusterClient clusterclient.Client
+	Config        config.Configuration
+	Scope         tally.Scope
+}
+
+// CustomHandler registers custom routes with the query service, allowing
+// downstream builds to add endpoints without changing the router setup.
+type CustomHandler interface {
+	// RegisterRoutes registers the routes of the handler with the router.
+	RegisterRoutes(router *mux.Router, opts CustomHandlerOptions) error
+}
+
+// CustomHandlerFn is a function that implements CustomHandler.
+type CustomHandlerFn func(router *mux.Router, opts CustomHandlerOptions) error
+
+// RegisterRoutes registers the routes of the handler with the router.
+func (fn CustomHandlerFn) RegisterRoutes(
+	router *mux.Router,
+	opts CustomHandlerOptions,
+) error {
+	return fn(router, opts)
+}
+
+// Middleware wraps the handler that serves requests routed by the router.
+type Middleware func(next http.Handler) http.Handler
+
+// RegisterCustomHandlers registers the routes of custom handlers, it must be
+// called after RegisterRoutes and routes already registered take precedence.
+func (h *Handler) RegisterCustomHandlers(handlers ...CustomHandler) error {
+	opts := CustomHandlerOptions{
+		Storage:       h.storage,
+		Engine:        h.engine,
+		ClusterClient: h.clusterClient,
+		Config:        h.config,
+		Scope:         h.scope.SubScope("custom"),
+	}
+	for _, handler := range handlers {
+		if err := handler.RegisterRoutes(h.Router, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseMiddleware wraps the router with middleware that runs after route limits
+// are applied, in the order provided and before any middleware used earlier.
+func (h *Handler) UseMiddleware(middleware ...Middleware) {
+	next := h.routed()
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	h.middleware = next
+}
+
+func (h *Handler) routed() http.Handler {
+	if h.middleware != nil {
+		return h.middleware
+	}
+	return h.Router
+}
@@ -33,14 +33,20 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/database"
+	"github.com/m3db/m3/src/query/api/v1/handler/graphite"
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
 	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
 	"github.com/m3db/m3/src/query/api/v1/handler/openapi"
 	"github.com/m3db/m3/src/query/api/v1/handler/placement"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
+	"github.com/m3db/m3/src/query/api/v1/handler/topology"
 	"github.com/m3db/m3/src/query/executor"
+	graphitepath "github.com/m3db/m3/src/query/graphite"
+	"github.com/m3db/m3/src/query/parser/promql"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingest"
+	"github.com/m3db/m3/src/query/storage/metadata"
 	"github.com/m3db/m3/src/query/util/logging"
 	clusterclient "github.com/m3db/m3cluster/client"
 
@@ -69,6 +75,13 @@ type Handler struct {
 	clusterClient clusterclient.Client
 	config        config.Configuration
 	embeddedDbCfg *dbconfig.DBConfiguration
+	metadataStore metadata.Store
+	ingestStats   ingest.Tracker
+	skewTracker   ingest.SkewTracker
+	idempotency   ingest.IdempotencyCache
+	routeLimits   routeLimits
+	accessLog     *accessLogger
+	middleware    http.Handler
 	scope         tally.Scope
 	createdAt     time.Time
 }
@@ -90,6 +103,21 @@ func NewHandler(
 	}
 
 	defer logger.Sync() // flushes buffer, if any
+
+	// NB: metadata is persisted to KV if available so it survives restarts
+	// and is shared across coordinators.
+	metadataStore := metadata.NewStore(metadata.DefaultMaxMetrics, metadata.DefaultMaxPerMetric)
+	if clusterClient != nil {
+		kvStore, err := clusterClient.KV()
+		if err != nil {
+			return nil, err
+		}
+		metadataStore = metadata.NewKVStore(kvStore, metadata.KVStoreOptions{
+			MaxMetrics:   metadata.DefaultMaxMetrics,
+			MaxPerMetric: metadata.DefaultMaxPerMetric,
+		})
+	}
+
 	h := &Handler{
 		CLFLogger:     log.New(os.Stderr, "[httpd] ", 0),
 		Router:        r,
@@ -99,12 +127,26 @@ func NewHandler(
 		clusterClient: clusterClient,
 		config:        cfg,
 		embeddedDbCfg: embeddedDbCfg,
+		metadataStore: metadataStore,
+		ingestStats:   ingest.NewTracker(ingest.TrackerOptions{}),
+		skewTracker:   ingest.NewSkewTracker(0, nil),
+		idempotency:   cfg.WriteIdempotency.NewIdempotencyCache(),
+		routeLimits:   newRouteLimits(cfg.RouteLimits, scope),
+		accessLog:     newAccessLogger(cfg.AccessLog, logger),
 		scope:         scope,
 		createdAt:     time.Now(),
 	}
 	return h, nil
 }
 
+// ServeHTTP serves a request with the router, applying the limits of the
+// class of route the request is for and access logging the request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.accessLog.serve(w, r, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.routeLimits.serve(w, r, h.routed())
+	}))
+}
+
 // RegisterRoutes registers all http routes.
 func (h *Handler) RegisterRoutes() error {
 	logged := logging.WithResponseTimeLogging
@@ -113,20 +155,36 @@ func (h *Handler) RegisterRoutes() error {
 	h.Router.PathPrefix(openapi.StaticURLPrefix).Handler(logged(openapi.StaticHandler()))
 
 	promRemoteReadHandler := remote.NewPromReadHandler(h.engine, h.scope.Tagged(remoteSource))
-	promRemoteWriteHandler, err := remote.NewPromWriteHandler(h.storage, nil, h.scope.Tagged(remoteSource))
+	promRemoteWriteHandler, err := remote.NewPromWriteHandler(h.storage, nil, h.metadataStore, h.ingestStats, h.skewTracker, h.idempotency, h.scope.Tagged(remoteSource))
 	if err != nil {
 		return err
 	}
 
 	h.Router.HandleFunc(remote.PromReadURL, logged(promRemoteReadHandler).ServeHTTP).Methods(remote.PromReadHTTPMethod)
 	h.Router.HandleFunc(remote.PromWriteURL, logged(promRemoteWriteHandler).ServeHTTP).Methods(remote.PromWriteHTTPMethod)
-	h.Router.HandleFunc(native.PromReadURL, logged(native.NewPromReadHandler(h.engine)).ServeHTTP).Methods(native.PromReadHTTPMethod)
+	h.Router.HandleFunc(native.PromReadURL, logged(native.NewPromReadHandler(h.engine, promql.NewParseCache(
+		promql.DefaultParseCacheSize, h.scope.SubScope("promql-parse-cache")))).ServeHTTP).Methods(native.PromReadHTTPMethod)
+	h.Router.HandleFunc(native.PromMetadataURL, logged(native.NewPromMetadataHandler(h.metadataStore)).ServeHTTP).Methods(native.PromMetadataHTTPMethod)
 	h.Router.HandleFunc(handler.SearchURL, logged(handler.NewSearchHandler(h.storage)).ServeHTTP).Methods(handler.SearchHTTPMethod)
+	h.Router.HandleFunc(handler.IngestStatsURL, logged(handler.NewIngestStatsHandler(h.ingestStats)).ServeHTTP).Methods(handler.IngestStatsHTTPMethod)
+	h.Router.HandleFunc(handler.IngestSkewURL, logged(handler.NewIngestSkewHandler(h.skewTracker)).ServeHTTP).Methods(handler.IngestSkewHTTPMethod)
+	h.Router.HandleFunc(handler.SeriesCountURL, logged(handler.NewSeriesCountHandler(h.storage)).ServeHTTP).Methods(handler.SeriesCountHTTPMethod)
+	if h.downsampler != nil {
+		h.Router.HandleFunc(handler.DownsamplerHealthURL, logged(handler.NewDownsamplerHealthHandler(h.downsampler)).ServeHTTP).Methods(handler.DownsamplerHealthHTTPMethod)
+	}
+
+	graphiteCfg := h.config.Graphite
+	graphiteMapping := graphitepath.NewPathMapping(graphiteCfg.PathTagPrefix, graphiteCfg.PathTagSuffix)
+	h.Router.HandleFunc(graphite.FindURL, logged(graphite.NewFindHandler(h.storage, graphiteMapping, graphiteCfg.FindLimit)).ServeHTTP).Methods(graphite.FindHTTPMethod)
+	h.Router.HandleFunc(graphite.ExpandURL, logged(graphite.NewExpandHandler(h.storage, graphiteMapping, graphiteCfg.FindLimit)).ServeHTTP).Methods(graphite.ExpandHTTPMethod)
+
 	h.Router.HandleFunc(m3json.WriteJSONURL, logged(m3json.NewWriteJSONHandler(h.storage)).ServeHTTP).Methods(m3json.JSONWriteHTTPMethod)
+	h.Router.HandleFunc(m3json.ReadJSONURL, logged(m3json.NewReadJSONHandler(h.storage)).ServeHTTP).Methods(m3json.JSONReadHTTPMethod)
 
 	if h.clusterClient != nil {
 		placement.RegisterRoutes(h.Router, h.clusterClient, h.config)
-		namespace.RegisterRoutes(h.Router, h.clusterClient)
+		namespace.RegisterRoutes(h.Router, h.clusterClient, h.config)
+		topology.RegisterRoutes(h.Router, h.clusterClient)
 		database.RegisterRoutes(h.Router, h.clusterClient, h.config, h.embeddedDbCfg)
 	}
 
@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/query/util/logging"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
@@ -49,7 +50,7 @@ func TestPromRemoteReadGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	err = h.RegisterRoutes()
@@ -66,7 +67,7 @@ func TestPromRemoteReadPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	err = h.RegisterRoutes()
@@ -83,7 +84,7 @@ func TestPromNativeReadGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	h.RegisterRoutes()
@@ -99,7 +100,7 @@ func TestPromNativeReadPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	h.RegisterRoutes()
@@ -115,7 +116,7 @@ func TestJSONWritePost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	h.RegisterRoutes()
@@ -131,7 +132,7 @@ func TestRoutesGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	h.RegisterRoutes()
@@ -164,7 +165,7 @@ func TestHealthGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	storage, _ := local.NewStorageAndSession(t, ctrl)
 
-	h, err := NewHandler(storage, nil, executor.NewEngine(storage), nil,
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
 		config.Configuration{}, nil, tally.NewTestScope("", nil))
 	require.NoError(t, err, "unable to setup handler")
 	h.RegisterRoutes()
@@ -185,3 +186,44 @@ func TestHealthGet(t *testing.T) {
 
 	assert.True(t, result > 0)
 }
+
+func TestCustomHandlersAndMiddleware(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	storage, _ := local.NewStorageAndSession(t, ctrl)
+
+	h, err := NewHandler(storage, nil, executor.NewEngine(storage, 0), nil,
+		config.Configuration{}, nil, tally.NewTestScope("", nil))
+	require.NoError(t, err, "unable to setup handler")
+	require.NoError(t, h.RegisterRoutes())
+
+	err = h.RegisterCustomHandlers(CustomHandlerFn(
+		func(router *mux.Router, opts CustomHandlerOptions) error {
+			require.Equal(t, storage, opts.Storage)
+			router.HandleFunc("/custom", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("custom"))
+			}).Methods(http.MethodGet)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	var order []string
+	middleware := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	h.UseMiddleware(middleware("first"), middleware("second"))
+
+	req, _ := http.NewRequest("GET", "/custom", nil)
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, "custom", res.Body.String())
+	require.Equal(t, []string{"first", "second"}, order)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package httpd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/graphite"
+	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errRouteQueueFull    = errors.New("too many requests queued for route")
+	errRouteQueueTimeout = errors.New("timed out waiting to serve request for route")
+
+	writeRoutes = []string{
+		remote.PromWriteURL,
+		m3json.WriteJSONURL,
+	}
+	queryRoutes = []string{
+		remote.PromReadURL,
+		m3json.ReadJSONURL,
+		handler.SearchURL,
+		handler.SeriesCountURL,
+		native.PromMetadataURL,
+		graphite.FindURL,
+		graphite.ExpandURL,
+	}
+	rangeQueryRoutes = []string{
+		native.PromReadURL,
+	}
+	adminRoutePrefixes = []string{
+		handler.RoutePrefixV1 + "/placement",
+		handler.RoutePrefixV1 + "/namespace",
+		handler.RoutePrefixV1 + "/database",
+	}
+)
+
+// routeLimits applies the limits of the class of route a request is for.
+type routeLimits struct {
+	routes map[string]*routeLimiter
+	admin  *routeLimiter
+}
+
+func newRouteLimits(cfg config.RouteLimitsConfiguration, scope tally.Scope) routeLimits {
+	var (
+		write      = newRouteLimiter(cfg.Write, scope, "write")
+		query      = newRouteLimiter(cfg.Query, scope, "query")
+		rangeQuery = newRouteLimiter(cfg.RangeQuery, scope, "range-query")
+		routes     = make(map[string]*routeLimiter)
+	)
+	for _, route := range writeRoutes {
+		routes[route] = write
+	}
+	for _, route := range queryRoutes {
+		routes[route] = query
+	}
+	for _, route := range rangeQueryRoutes {
+		routes[route] = rangeQuery
+	}
+	return routeLimits{
+		routes: routes,
+		admin:  newRouteLimiter(cfg.Admin, scope, "admin"),
+	}
+}
+
+// limiterFor returns the limiter for a request, nil if unlimited.
+func (l routeLimits) limiterFor(r *http.Request) *routeLimiter {
+	if limiter, ok := l.routes[r.URL.Path]; ok {
+		return limiter
+	}
+	for _, prefix := range adminRoutePrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return l.admin
+		}
+	}
+	return nil
+}
+
+func (l routeLimits) serve(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	limiter := l.limiterFor(r)
+	if limiter == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+	limiter.serve(w, r, next)
+}
+
+type routeLimiterMetrics struct {
+	rejected      tally.Counter
+	queueTimeouts tally.Counter
+}
+
+// routeLimiter limits the timeout and concurrency of a class of route.
+type routeLimiter struct {
+	timeout time.Duration
+	// pending bounds the requests running or queued and running bounds the
+	// requests running, both are nil if concurrency is unlimited.
+	pending chan struct{}
+	running chan struct{}
+	metrics routeLimiterMetrics
+}
+
+func newRouteLimiter(
+	cfg *config.RouteLimitConfiguration,
+	scope tally.Scope,
+	class string,
+) *routeLimiter {
+	if cfg == nil {
+		return nil
+	}
+
+	scope = scope.Tagged(map[string]string{"route-class": class})
+	l := &routeLimiter{
+		timeout: cfg.Timeout,
+		metrics: routeLimiterMetrics{
+			rejected:      scope.Counter("route-limit.rejected"),
+			queueTimeouts: scope.Counter("route-limit.queue-timeouts"),
+		},
+	}
+	if cfg.MaxConcurrent > 0 {
+		l.running = make(chan struct{}, cfg.MaxConcurrent)
+		l.pending = make(chan struct{}, cfg.MaxConcurrent+cfg.MaxQueued)
+	}
+	return l
+}
+
+func (l *routeLimiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	ctx := r.Context()
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	if l.running != nil {
+		select {
+		case l.pending <- struct{}{}:
+		default:
+			l.metrics.rejected.Inc(1)
+			handler.Error(w, errRouteQueueFull, http.StatusTooManyRequests)
+			return
+		}
+		defer func() { <-l.pending }()
+
+		select {
+		case l.running <- struct{}{}:
+		case <-ctx.Done():
+			l.metrics.queueTimeouts.Inc(1)
+			handler.Error(w, errRouteQueueTimeout, http.StatusServiceUnavailable)
+			return
+		}
+		defer func() { <-l.running }()
+	}
+
+	next.ServeHTTP(w, r)
+}
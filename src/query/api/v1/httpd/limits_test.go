@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package httpd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestRouteLimitsLimiterFor(t *testing.T) {
+	limits := newRouteLimits(config.RouteLimitsConfiguration{
+		Write:      &config.RouteLimitConfiguration{},
+		RangeQuery: &config.RouteLimitConfiguration{},
+		Admin:      &config.RouteLimitConfiguration{},
+	}, tally.NoopScope)
+
+	limiterFor := func(url string) *routeLimiter {
+		return limits.limiterFor(httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	assert.Equal(t, limits.routes[remote.PromWriteURL], limiterFor(remote.PromWriteURL))
+	assert.NotNil(t, limiterFor(remote.PromWriteURL))
+	assert.NotNil(t, limiterFor(native.PromReadURL))
+	assert.True(t, limiterFor(remote.PromWriteURL) != limiterFor(native.PromReadURL))
+	assert.Equal(t, limits.admin, limiterFor(namespace.GetURL+"/foo"))
+	assert.NotNil(t, limits.admin)
+
+	// Query limits are not configured so are unlimited.
+	assert.Nil(t, limiterFor(remote.PromReadURL))
+	assert.Nil(t, limiterFor(healthURL))
+}
+
+func TestRouteLimiterRejectsWhenQueueFull(t *testing.T) {
+	limiter := newRouteLimiter(&config.RouteLimitConfiguration{
+		MaxConcurrent: 1,
+	}, tally.NoopScope, "write")
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		done    = make(chan struct{})
+	)
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	go func() {
+		defer close(done)
+		limiter.serve(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, remote.PromWriteURL, nil), blocking)
+	}()
+	<-started
+
+	res := httptest.NewRecorder()
+	limiter.serve(res, httptest.NewRequest(http.MethodPost, remote.PromWriteURL, nil),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.FailNow(t, "request should be rejected")
+		}))
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+
+	close(release)
+	<-done
+
+	res = httptest.NewRecorder()
+	limiter.serve(res, httptest.NewRequest(http.MethodPost, remote.PromWriteURL, nil),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestRouteLimiterQueueTimeout(t *testing.T) {
+	limiter := newRouteLimiter(&config.RouteLimitConfiguration{
+		Timeout:       10 * time.Millisecond,
+		MaxConcurrent: 1,
+		MaxQueued:     1,
+	}, tally.NoopScope, "range-query")
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		done    = make(chan struct{})
+	)
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		close(started)
+		<-release
+	})
+	go func() {
+		defer close(done)
+		limiter.serve(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, native.PromReadURL, nil), blocking)
+	}()
+	<-started
+
+	res := httptest.NewRecorder()
+	limiter.serve(res, httptest.NewRequest(http.MethodGet, native.PromReadURL, nil),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.FailNow(t, "request should time out while queued")
+		}))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	close(release)
+	<-done
+}
@@ -24,39 +24,137 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"net"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/uber-go/tally"
+	"github.com/uber-go/tally/prometheus"
+)
+
+const (
+	statsReportInterval = time.Second
+
+	// latencyReservoirSize is the number of latencies sampled between resets
+	// to estimate latency quantiles from, bounding the memory used to hold
+	// them regardless of the write rate.
+	latencyReservoirSize = 10000
 )
 
-var stat = new(stats)
+var stat = newStats()
 
+// stats exposes the benchmark statistics in the Prometheus exposition
+// format so that benchmark runs can be scraped by the monitoring stack.
 type stats struct {
-	Writes    int64 `json:"writes"`
-	RunTimeMs int64 `json:"run_time_ms"`
+	reporter prometheus.Reporter
+	writes   tally.Counter
+	errors   tally.Counter
+	latency  tally.Histogram
+	runTime  tally.Gauge
+
+	numWrites int64
+	numErrors int64
+
+	latenciesLock sync.Mutex
+	latencies     []time.Duration
+	numLatencies  int64
+	rand          *rand.Rand
+}
+
+func newStats() *stats {
+	reporter := prometheus.NewReporter(prometheus.Options{
+		OnRegisterError: func(err error) {
+			log.Printf("unable to register benchmark metric: %v\n", err)
+		},
+	})
+	scope, _ := tally.NewRootScope(tally.ScopeOptions{
+		Prefix:         "benchmark",
+		CachedReporter: reporter,
+		Separator:      prometheus.DefaultSeparator,
+	}, statsReportInterval)
+	return &stats{
+		reporter: reporter,
+		writes:   scope.Counter("writes_total"),
+		errors:   scope.Counter("errors_total"),
+		latency: scope.Histogram("write_latency",
+			tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 16)),
+		runTime: scope.Gauge("run_time_seconds"),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *stats) recordWrite(took time.Duration) {
+	atomic.AddInt64(&s.numWrites, 1)
+	s.writes.Inc(1)
+	s.recordLatency(took)
+}
+
+func (s *stats) recordError(took time.Duration) {
+	atomic.AddInt64(&s.numErrors, 1)
+	s.errors.Inc(1)
+	s.recordLatency(took)
 }
 
-func (s *stats) getWrites() int64 {
-	return atomic.LoadInt64(&s.Writes)
+func (s *stats) recordLatency(took time.Duration) {
+	s.latency.RecordDuration(took)
+	s.latenciesLock.Lock()
+	// NB: each latency recorded replaces a sampled latency with probability
+	// size/n, so the reservoir is a uniform sample of all the latencies.
+	s.numLatencies++
+	if len(s.latencies) < latencyReservoirSize {
+		s.latencies = append(s.latencies, took)
+	} else if i := s.rand.Int63n(s.numLatencies); i < latencyReservoirSize {
+		s.latencies[i] = took
+	}
+	s.latenciesLock.Unlock()
 }
 
-func (s *stats) incWrites() {
-	atomic.AddInt64(&s.Writes, 1)
+// reset returns the writes, errors and a sample of the latencies recorded
+// since the last reset, the exported metrics are cumulative and are not reset.
+func (s *stats) reset() (int64, int64, latencySnapshot) {
+	s.latenciesLock.Lock()
+	latencies := s.latencies
+	s.latencies = nil
+	s.numLatencies = 0
+	s.latenciesLock.Unlock()
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	numWrites := atomic.SwapInt64(&s.numWrites, 0)
+	numErrors := atomic.SwapInt64(&s.numErrors, 0)
+	return numWrites, numErrors, latencySnapshot(latencies)
 }
 
-func (s *stats) getRunTimeMs() int64 {
-	return atomic.LoadInt64(&s.Writes)
+func (s *stats) setRunTime(v time.Duration) {
+	s.runTime.Update(v.Seconds())
 }
 
-func (s *stats) setRunTimeMs(v int64) {
-	atomic.StoreInt64(&s.RunTimeMs, v)
+func (s *stats) handler() http.Handler {
+	return s.reporter.HTTPHandler()
 }
 
-func (s *stats) snapshot() stats {
-	return stats{Writes: s.getWrites(), RunTimeMs: s.getRunTimeMs()}
+// latencySnapshot is a set of latencies sorted in ascending order.
+type latencySnapshot []time.Duration
+
+func (l latencySnapshot) quantile(q float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	idx := int(q*float64(len(l))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(l) {
+		idx = len(l) - 1
+	}
+	return l[idx]
 }
 
 // HTTPClientOptions specify HTTP Client options.
@@ -111,10 +209,7 @@ func (b *benchmarker) serve() {
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(health{Up: true})
 	})
-	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(stat.snapshot())
-	})
+	mux.Handle("/metrics", stat.handler())
 	http.ListenAndServe(b.address, mux)
 	if err := http.ListenAndServe(b.address, mux); err != nil {
 		fmt.Fprintf(os.Stderr, "server could not listen on %s: %v", b.address, err)
@@ -0,0 +1,165 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errScenarioNoPhases      = errors.New("scenario must define at least one phase")
+	errScenarioInvalidQuanta = errors.New("latency quantile must be in (0, 1]")
+)
+
+// scenarioConfiguration describes a benchmark run as a set of phases, each
+// of which is run in order and then validated against the thresholds.
+type scenarioConfiguration struct {
+	// Phases are the phases to run, in order.
+	Phases []phaseConfiguration `yaml:"phases"`
+
+	// Thresholds are the default thresholds applied to every phase.
+	Thresholds thresholdsConfiguration `yaml:"thresholds"`
+}
+
+// phaseConfiguration describes the workload of a single benchmark phase.
+type phaseConfiguration struct {
+	// Name is the name of the phase, used for reporting.
+	Name string `yaml:"name" validate:"nonzero"`
+
+	// Workers is the number of parallel writers, defaults to the workers flag.
+	Workers int `yaml:"workers"`
+
+	// Iterations is the number of passes to make over the input data.
+	Iterations int `yaml:"iterations"`
+
+	// Thresholds override the scenario thresholds for this phase if set.
+	Thresholds *thresholdsConfiguration `yaml:"thresholds"`
+}
+
+// thresholdsConfiguration describes the SLO assertions for a phase, any
+// zero valued threshold is not asserted.
+type thresholdsConfiguration struct {
+	// LatencyQuantile is the latency quantile to assert, defaults to 0.99.
+	LatencyQuantile float64 `yaml:"latencyQuantile"`
+
+	// MaxLatency is the max latency allowed at the latency quantile.
+	MaxLatency time.Duration `yaml:"maxLatency"`
+
+	// MaxErrorRate is the max ratio of failed writes to attempted writes.
+	MaxErrorRate float64 `yaml:"maxErrorRate"`
+
+	// MinWriteRate is the min number of successful writes per second.
+	MinWriteRate float64 `yaml:"minWriteRate"`
+}
+
+const (
+	defaultLatencyQuantile = 0.99
+)
+
+func (c scenarioConfiguration) validate() error {
+	if len(c.Phases) == 0 {
+		return errScenarioNoPhases
+	}
+	if err := c.Thresholds.validate(); err != nil {
+		return err
+	}
+	for _, phase := range c.Phases {
+		if phase.Thresholds == nil {
+			continue
+		}
+		if err := phase.Thresholds.validate(); err != nil {
+			return fmt.Errorf("phase %s: %v", phase.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c scenarioConfiguration) thresholdsFor(phase phaseConfiguration) thresholdsConfiguration {
+	if phase.Thresholds != nil {
+		return *phase.Thresholds
+	}
+	return c.Thresholds
+}
+
+func (c thresholdsConfiguration) validate() error {
+	if c.LatencyQuantile < 0 || c.LatencyQuantile > 1 {
+		return errScenarioInvalidQuanta
+	}
+	return nil
+}
+
+func (c thresholdsConfiguration) latencyQuantile() float64 {
+	if c.LatencyQuantile == 0 {
+		return defaultLatencyQuantile
+	}
+	return c.LatencyQuantile
+}
+
+// phaseResult is the outcome of running a single benchmark phase.
+type phaseResult struct {
+	name      string
+	took      time.Duration
+	numWrites int64
+	numErrors int64
+	latencies latencySnapshot
+}
+
+func (r phaseResult) errorRate() float64 {
+	attempted := r.numWrites + r.numErrors
+	if attempted == 0 {
+		return 0
+	}
+	return float64(r.numErrors) / float64(attempted)
+}
+
+func (r phaseResult) writeRate() float64 {
+	if r.took <= 0 {
+		return 0
+	}
+	return float64(r.numWrites) / r.took.Seconds()
+}
+
+// violations returns a description of each threshold the result violates.
+func (r phaseResult) violations(t thresholdsConfiguration) []string {
+	var violations []string
+	if t.MaxLatency > 0 {
+		q := t.latencyQuantile()
+		if v := r.latencies.quantile(q); v > t.MaxLatency {
+			violations = append(violations, fmt.Sprintf(
+				"p%v latency %v exceeds max %v", q*100, v, t.MaxLatency))
+		}
+	}
+	if t.MaxErrorRate > 0 {
+		if v := r.errorRate(); v > t.MaxErrorRate {
+			violations = append(violations, fmt.Sprintf(
+				"error rate %f exceeds max %f", v, t.MaxErrorRate))
+		}
+	}
+	if t.MinWriteRate > 0 {
+		if v := r.writeRate(); v < t.MinWriteRate {
+			violations = append(violations, fmt.Sprintf(
+				"write rate %f/sec below min %f/sec", v, t.MinWriteRate))
+		}
+	}
+	return violations
+}
@@ -23,7 +23,9 @@ package main
 import (
 	"bytes"
 	"flag"
+	"io/ioutil"
 	"log"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -51,11 +53,14 @@ var (
 
 	writeEndpoint string
 	coordinator   bool
+	scenarioFile  string
 
 	configLoadOpts = xconfig.Options{
 		DisableUnmarshalStrict: false,
 		DisableValidate:        false,
 	}
+
+	serveOnce sync.Once
 )
 
 func init() {
@@ -64,26 +69,82 @@ func init() {
 	flag.IntVar(&workers, "workers", 1, "Number of parallel requests to make.")
 	flag.IntVar(&batch, "batch", 5000, "Batch Size")
 	flag.StringVar(&namespace, "namespace", "metrics", "M3DB namespace where to store result metrics")
-	flag.StringVar(&address, "address", "localhost:8888", "Address to expose benchmarker health and stats")
+	flag.StringVar(&address, "address", "localhost:8888", "Address to expose benchmarker health and Prometheus metrics")
 	flag.StringVar(&benchmarkers, "benchmarkers", "localhost:8888", "Comma separated host:ports addresses of benchmarkers to coordinate")
 	flag.BoolVar(&memprofile, "memprofile", false, "Enable memory profile")
 	flag.BoolVar(&cpuprofile, "cpuprofile", false, "Enable cpu profile")
 	flag.StringVar(&writeEndpoint, "writeEndpoint", "http://localhost:7201/api/v1/prom/remote/write", "Write endpoint for m3coordinator")
 	flag.BoolVar(&coordinator, "coordinator", false, "Benchmark through coordinator rather than m3db directly")
+	flag.StringVar(&scenarioFile, "scenario", "", "Scenario file describing benchmark phases and thresholds, exits non-zero if thresholds are violated")
 	flag.Parse()
 }
 
 func main() {
+	if scenarioFile == "" {
+		runPhase(phaseConfiguration{Name: "default"})
+		return
+	}
+
+	var scenario scenarioConfiguration
+	if err := xconfig.LoadFile(&scenario, scenarioFile, configLoadOpts); err != nil {
+		log.Fatalf("unable to load %s: %v", scenarioFile, err)
+	}
+	if err := scenario.validate(); err != nil {
+		log.Fatalf("invalid scenario %s: %v", scenarioFile, err)
+	}
+
+	failed := false
+	for _, phase := range scenario.Phases {
+		result := runPhase(phase)
+		log.Printf("phase %s: %d writes, %d errors in %fsec (error rate %f, write rate %f/sec)\n",
+			result.name, result.numWrites, result.numErrors, result.took.Seconds(),
+			result.errorRate(), result.writeRate())
+		for _, violation := range result.violations(scenario.thresholdsFor(phase)) {
+			log.Printf("phase %s violated threshold: %s\n", result.name, violation)
+			failed = true
+		}
+	}
+
+	if failed {
+		log.Println("benchmark scenario failed")
+		os.Exit(1)
+	}
+	log.Println("benchmark scenario passed")
+}
+
+func runPhase(phase phaseConfiguration) phaseResult {
+	if phase.Workers > 0 {
+		workers = phase.Workers
+	}
+	iterations := phase.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	// Discard anything recorded before this phase started.
+	stat.reset()
+
+	start := time.Now()
 	if coordinator {
 		log.Println("benchmarking writes on m3coordinator over http endpoint...")
-		benchmarkCoordinator()
+		benchmarkCoordinator(iterations)
 	} else {
 		log.Println("benchmarking writes on m3db...")
-		benchmarkM3DB()
+		benchmarkM3DB(iterations)
+	}
+	took := time.Since(start)
+
+	numWrites, numErrors, latencies := stat.reset()
+	return phaseResult{
+		name:      phase.Name,
+		took:      took,
+		numWrites: numWrites,
+		numErrors: numErrors,
+		latencies: latencies,
 	}
 }
 
-func benchmarkM3DB() {
+func benchmarkM3DB(iterations int) {
 	//Setup
 	metrics := make([]*common.M3Metric, 0, common.MetricsLen)
 	common.ConvertToM3(dataFile, workers, func(m *common.M3Metric) {
@@ -123,9 +184,11 @@ func benchmarkM3DB() {
 
 	appendReadCount := func() int {
 		var items int
-		for _, query := range metrics {
-			ch <- query
-			items++
+		for i := 0; i < iterations; i++ {
+			for _, query := range metrics {
+				ch <- query
+				items++
+			}
 		}
 		close(inputDone)
 		return items
@@ -143,11 +206,15 @@ func benchmarkM3DB() {
 	genericBenchmarker(workerFunction, appendReadCount, cleanup)
 }
 
-func benchmarkCoordinator() {
-	// Setup
-	metrics := make([]*bytes.Reader, 0, common.MetricsLen/batch)
+func benchmarkCoordinator(iterations int) {
+	// Setup, retain the encoded bytes so that they can be resent each iteration
+	metrics := make([][]byte, 0, common.MetricsLen/batch)
 	common.ConvertToProm(dataFile, workers, batch, func(m *bytes.Reader) {
-		metrics = append(metrics, m)
+		b, err := ioutil.ReadAll(m)
+		if err != nil {
+			log.Fatalf("unable to read converted metrics: %v", err)
+		}
+		metrics = append(metrics, b)
 	})
 
 	ch := make(chan *bytes.Reader, workers)
@@ -162,9 +229,11 @@ func benchmarkCoordinator() {
 	inputDone := make(chan struct{})
 	appendReadCount := func() int {
 		var items int
-		for _, query := range metrics {
-			ch <- query
-			items++
+		for i := 0; i < iterations; i++ {
+			for _, query := range metrics {
+				ch <- bytes.NewReader(query)
+				items++
+			}
 		}
 		close(inputDone)
 		return items
@@ -204,7 +273,9 @@ func genericBenchmarker(workerFunction func(), appendReadCount func() int, clean
 	waitForInit.Wait()
 
 	b := &benchmarker{address: address, benchmarkers: benchmarkers}
-	go b.serve()
+	serveOnce.Do(func() {
+		go b.serve()
+	})
 	log.Println("waiting for other benchmarkers to spin up...")
 	b.waitForBenchmarkers()
 
@@ -218,9 +289,9 @@ func genericBenchmarker(workerFunction func(), appendReadCount func() int, clean
 		for {
 			time.Sleep(time.Second)
 			if v := atomic.LoadInt64(&endNanosAtomic); v > 0 {
-				stat.setRunTimeMs(int64(time.Unix(0, v).Sub(start) / time.Millisecond))
+				stat.setRunTime(time.Unix(0, v).Sub(start))
 			} else {
-				stat.setRunTimeMs(int64(time.Since(start) / time.Millisecond))
+				stat.setRunTime(time.Since(start))
 			}
 		}
 	}()
@@ -239,17 +310,22 @@ func genericBenchmarker(workerFunction func(), appendReadCount func() int, clean
 
 func writeToCoordinator(ch <-chan *bytes.Reader) {
 	for query := range ch {
-		if r, err := common.PostEncodedSnappy(writeEndpoint, query); err != nil {
+		start := time.Now()
+		r, err := common.PostEncodedSnappy(writeEndpoint, query)
+		if err != nil {
 			log.Println(err)
-		} else {
-			if r.StatusCode != 200 {
-				b := make([]byte, r.ContentLength)
-				r.Body.Read(b)
-				r.Body.Close()
-				log.Println(string(b))
-			}
-			stat.incWrites()
+			stat.recordError(time.Since(start))
+			continue
+		}
+		if r.StatusCode != 200 {
+			b := make([]byte, r.ContentLength)
+			r.Body.Read(b)
+			r.Body.Close()
+			log.Println(string(b))
+			stat.recordError(time.Since(start))
+			continue
 		}
+		stat.recordWrite(time.Since(start))
 	}
 }
 
@@ -258,10 +334,12 @@ func writeToM3DB(session client.Session, ch <-chan *common.M3Metric) {
 	namespaceID := ident.StringID(namespace)
 	for query := range ch {
 		id := ident.StringID(query.ID)
+		start := time.Now()
 		if err := session.Write(namespaceID, id, query.Time, query.Value, xtime.Millisecond, nil); err != nil {
 			log.Println(err)
+			stat.recordError(time.Since(start))
 		} else {
-			stat.incWrites()
+			stat.recordWrite(time.Since(start))
 		}
 		id.Finalize()
 		if itemsWritten > 0 && itemsWritten%10000 == 0 {
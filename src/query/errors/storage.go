@@ -31,6 +31,9 @@ var (
 	// ErrNotImplemented is returned when the storage endpoint is not implemented
 	ErrNotImplemented = errors.New("not implemented")
 
+	// ErrFilterUnsupported is returned when a fetch query has a filter the storage cannot apply.
+	ErrFilterUnsupported = errors.New("storage does not support filters")
+
 	// ErrInvalidFetchResponse is returned when fetch fails from storage.
 	ErrInvalidFetchResponse = errors.New("invalid response from fetch")
 
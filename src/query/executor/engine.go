@@ -22,6 +22,7 @@ package executor
 
 import (
 	"context"
+	"time"
 
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
@@ -35,15 +36,21 @@ import (
 // Engine executes a Query.
 type Engine struct {
 	// Used for tracking running queries.
-	tracker *Tracker
-	Stats   *QueryStatistics
-	store   storage.Storage
+	tracker  *Tracker
+	Stats    *QueryStatistics
+	store    storage.Storage
+	lookback time.Duration
 }
 
 // EngineOptions can be used to pass custom flags to engine
 type EngineOptions struct {
 	// AbortCh is a channel that signals when results are no longer desired by the caller.
 	AbortCh <-chan bool
+	// Limit is the max number of series fetched, zero means no limit.
+	Limit int
+	// SampleOnLimit returns a deterministic sample of series instead of
+	// the first series found when more than Limit series match.
+	SampleOnLimit bool
 }
 
 // Query is the result after execution
@@ -52,12 +59,15 @@ type Query struct {
 	Result Result
 }
 
-// NewEngine returns a new instance of QueryExecutor.
-func NewEngine(store storage.Storage) *Engine {
+// NewEngine returns a new instance of QueryExecutor, queries look back at
+// most the lookback duration for the most recent datapoint of each step
+// unless it is zero.
+func NewEngine(store storage.Storage, lookback time.Duration) *Engine {
 	return &Engine{
-		tracker: NewTracker(),
-		Stats:   &QueryStatistics{},
-		store:   store,
+		tracker:  NewTracker(),
+		Stats:    &QueryStatistics{},
+		store:    store,
+		lookback: lookback,
 	}
 }
 
@@ -84,7 +94,9 @@ func (e *Engine) Execute(ctx context.Context, query *storage.FetchQuery, opts *E
 	defer e.tracker.DetachQuery(task.qid)
 
 	result, err := e.store.Fetch(ctx, query, &storage.FetchOptions{
-		KillChan: task.closing,
+		Limit:         opts.Limit,
+		SampleOnLimit: opts.SampleOnLimit,
+		KillChan:      task.closing,
 	})
 	if err != nil {
 		results <- &storage.QueryResult{Err: err}
@@ -105,6 +117,14 @@ func (e *Engine) ExecuteExpr(ctx context.Context, parser parser.Parser, opts *En
 		return
 	}
 
+	if params.LookbackDuration == 0 {
+		params.LookbackDuration = e.lookback
+	}
+	if params.Limit == 0 {
+		params.Limit = opts.Limit
+		params.SampleOnLimit = opts.SampleOnLimit
+	}
+
 	lp, err := plan.NewLogicalPlan(nodes, edges)
 	if err != nil {
 		results <- Query{Err: err}
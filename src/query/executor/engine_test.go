@@ -43,7 +43,7 @@ func TestExecute(t *testing.T) {
 	results := make(chan *storage.QueryResult)
 	closing := make(chan bool)
 
-	engine := NewEngine(store)
+	engine := NewEngine(store, 0)
 	go engine.Execute(context.TODO(), &storage.FetchQuery{}, &EngineOptions{}, closing, results)
 	<-results
 	assert.Equal(t, len(engine.tracker.queries), 1)
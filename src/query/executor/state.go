@@ -118,8 +118,11 @@ func GenerateExecutionState(
 	}
 
 	options := transform.Options{
-		TimeSpec: pplan.TimeSpec,
-		Debug:    pplan.Debug,
+		TimeSpec:         pplan.TimeSpec,
+		Debug:            pplan.Debug,
+		LookbackDuration: pplan.LookbackDuration,
+		Limit:            pplan.Limit,
+		SampleOnLimit:    pplan.SampleOnLimit,
 	}
 	controller, err := state.createNode(step, options)
 	if err != nil {
@@ -54,6 +54,9 @@ type FetchNode struct {
 	storage    storage.Storage
 	timespec   transform.TimeSpec
 	debug      bool
+	lookback   time.Duration
+	limit      int
+	sample     bool
 }
 
 // OpType for the operator
@@ -76,7 +79,9 @@ func (o FetchOp) String() string {
 
 // Node creates an execution node
 func (o FetchOp) Node(controller *transform.Controller, storage storage.Storage, options transform.Options) parser.Source {
-	return &FetchNode{op: o, controller: controller, storage: storage, timespec: options.TimeSpec, debug: options.Debug}
+	return &FetchNode{op: o, controller: controller, storage: storage, timespec: options.TimeSpec,
+		debug: options.Debug, lookback: options.LookbackDuration, limit: options.Limit,
+		sample: options.SampleOnLimit}
 }
 
 // Execute runs the fetch node operation
@@ -90,7 +95,11 @@ func (n *FetchNode) Execute(ctx context.Context) error {
 		End:         endTime,
 		TagMatchers: n.op.Matchers,
 		Interval:    timeSpec.Step,
-	}, &storage.FetchOptions{})
+	}, &storage.FetchOptions{
+		Limit:            n.limit,
+		SampleOnLimit:    n.sample,
+		LookbackDuration: n.lookback,
+	})
 	if err != nil {
 		return err
 	}
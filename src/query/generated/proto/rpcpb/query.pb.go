@@ -377,6 +377,7 @@ func (m *Segment) GetBlockSize() int64 {
 type Segments struct {
 	Merged   *Segment   `protobuf:"bytes,1,opt,name=merged" json:"merged,omitempty"`
 	Unmerged []*Segment `protobuf:"bytes,2,rep,name=unmerged" json:"unmerged,omitempty"`
+	Encoding string     `protobuf:"bytes,3,opt,name=encoding,proto3" json:"encoding,omitempty"`
 }
 
 func (m *Segments) Reset()                    { *m = Segments{} }
@@ -398,6 +399,13 @@ func (m *Segments) GetUnmerged() []*Segment {
 	return nil
 }
 
+func (m *Segments) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
+
 type CompressedValuesReplica struct {
 	Segments []*Segments `protobuf:"bytes,1,rep,name=segments" json:"segments,omitempty"`
 }
@@ -1169,6 +1177,12 @@ func (m *Segments) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Encoding) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Encoding)))
+		i += copy(dAtA[i:], m.Encoding)
+	}
 	return i, nil
 }
 
@@ -1541,6 +1555,10 @@ func (m *Segments) Size() (n int) {
 			n += 1 + l + sovQuery(uint64(l))
 		}
 	}
+	l = len(m.Encoding)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
 	return n
 }
 
@@ -3102,6 +3120,35 @@ func (m *Segments) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -3785,56 +3832,56 @@ func init() {
 }
 
 var fileDescriptorQuery = []byte{
-	// 801 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xdb, 0x6e, 0x1c, 0x45,
-	0x10, 0x65, 0x76, 0x3d, 0xeb, 0xdd, 0xf2, 0xe2, 0x38, 0x4d, 0x24, 0x46, 0x21, 0x8c, 0xa2, 0x91,
-	0x08, 0xe6, 0xb6, 0x63, 0xad, 0x91, 0x40, 0x41, 0x02, 0x09, 0x92, 0xf0, 0x14, 0x21, 0xda, 0x2b,
-	0x78, 0x43, 0xea, 0x9d, 0x29, 0xcf, 0x8e, 0xb2, 0x73, 0xa1, 0xbb, 0x17, 0x61, 0xbe, 0x82, 0x4f,
-	0xe0, 0x73, 0x78, 0xcc, 0x07, 0xf0, 0x80, 0xcc, 0x8f, 0xa0, 0xae, 0xee, 0xb9, 0xad, 0x8d, 0xf0,
-	0x5b, 0x75, 0xd5, 0x99, 0xba, 0x9c, 0x3e, 0x5d, 0x03, 0x5f, 0x66, 0xb9, 0xde, 0xec, 0xd6, 0x8b,
-	0xa4, 0x2a, 0xe2, 0xe2, 0x3c, 0x5d, 0xc7, 0xc5, 0x79, 0xac, 0x64, 0x12, 0xff, 0xbc, 0x43, 0x79,
-	0x15, 0x67, 0x58, 0xa2, 0x14, 0x1a, 0xd3, 0xb8, 0x96, 0x95, 0xae, 0x62, 0x59, 0x27, 0xf5, 0xda,
-	0xc6, 0x16, 0xe4, 0x61, 0x3e, 0xb9, 0xa2, 0x4b, 0x98, 0xff, 0x28, 0x73, 0x8d, 0x2f, 0x51, 0x29,
-	0x91, 0x21, 0x7b, 0x1f, 0x7c, 0x42, 0x05, 0xde, 0x63, 0xef, 0xf4, 0x68, 0x79, 0x7f, 0x41, 0xb0,
-	0x05, 0x61, 0xbe, 0x37, 0x01, 0x6e, 0xe3, 0xec, 0x13, 0x38, 0xac, 0x6a, 0x9d, 0x57, 0xa5, 0x0a,
-	0x46, 0x04, 0x7d, 0xab, 0x0f, 0xfd, 0xce, 0x86, 0x78, 0x83, 0x89, 0xfe, 0xf2, 0x00, 0xba, 0x24,
-	0x8c, 0xc1, 0xc1, 0xae, 0xcc, 0x35, 0x55, 0xf1, 0x39, 0xd9, 0x2c, 0x04, 0x10, 0x65, 0x59, 0x69,
-	0x61, 0xbe, 0xa0, 0xa4, 0x73, 0xde, 0xf3, 0xb0, 0x33, 0x80, 0x54, 0x68, 0x51, 0x57, 0x79, 0xa9,
-	0x55, 0x30, 0x7e, 0x3c, 0x3e, 0x3d, 0x5a, 0x9e, 0xb8, 0xa2, 0xcf, 0x9a, 0x00, 0xef, 0x61, 0x58,
-	0x0c, 0x07, 0x5a, 0x64, 0x2a, 0x38, 0x20, 0xec, 0x3b, 0x37, 0x66, 0x59, 0xac, 0x44, 0xa6, 0x9e,
-	0x97, 0x5a, 0x5e, 0x71, 0x02, 0x3e, 0xfc, 0x0c, 0x66, 0xad, 0x8b, 0x9d, 0xc0, 0xf8, 0x15, 0x5a,
-	0x22, 0x66, 0xdc, 0x98, 0xec, 0x01, 0xf8, 0xbf, 0x88, 0xed, 0x0e, 0xa9, 0xb9, 0x19, 0xb7, 0x87,
-	0xa7, 0xa3, 0xcf, 0xbd, 0x28, 0x74, 0x34, 0xba, 0xb9, 0xd9, 0x31, 0x8c, 0xf2, 0xd4, 0x7d, 0x3a,
-	0xca, 0xd3, 0xe8, 0x2b, 0x98, 0xb5, 0x2d, 0xb2, 0x47, 0x30, 0xd3, 0x79, 0x81, 0x4a, 0x8b, 0xa2,
-	0x26, 0xcc, 0x98, 0x77, 0x8e, 0x61, 0x11, 0xcf, 0x15, 0x89, 0x36, 0x00, 0xcf, 0xba, 0xc1, 0x86,
-	0x54, 0x78, 0x77, 0xa0, 0xe2, 0x14, 0xee, 0x5d, 0xe6, 0xbf, 0x62, 0xca, 0x51, 0x55, 0xdb, 0x5d,
-	0xcb, 0xf0, 0x94, 0xef, 0xbb, 0xa3, 0x77, 0xc1, 0x7f, 0x2e, 0x65, 0x25, 0x4d, 0x23, 0x68, 0x0c,
-	0x37, 0x86, 0x3d, 0x18, 0xc1, 0xbc, 0x40, 0x9d, 0x6c, 0xfe, 0x47, 0x30, 0x84, 0xb9, 0x9b, 0x60,
-	0x08, 0x7a, 0x43, 0x30, 0x97, 0x00, 0x5d, 0x0e, 0xd3, 0x8b, 0xd2, 0x42, 0x6a, 0x47, 0x97, 0x3d,
-	0x98, 0x1b, 0xc2, 0x32, 0xa5, 0x74, 0x63, 0x6e, 0x4c, 0x76, 0x06, 0x47, 0x5a, 0x64, 0x2f, 0x85,
-	0x4e, 0x36, 0x28, 0x1b, 0x91, 0x1c, 0xbb, 0x42, 0xce, 0xcd, 0xfb, 0x10, 0x73, 0x73, 0xfd, 0x06,
-	0x6e, 0xdc, 0xdc, 0xb7, 0x70, 0xe8, 0xb0, 0x46, 0xb4, 0xa5, 0x28, 0xd0, 0x05, 0xc9, 0xbe, 0x5d,
-	0x12, 0x06, 0xa9, 0xaf, 0x6a, 0x0c, 0xc6, 0xd4, 0x19, 0xd9, 0xd1, 0xa7, 0x70, 0x44, 0x85, 0x38,
-	0xaa, 0xdd, 0x56, 0xb3, 0xf7, 0x60, 0xa2, 0x50, 0xe6, 0xd8, 0x5c, 0xdf, 0x9b, 0xae, 0xc9, 0x0b,
-	0x72, 0x72, 0x17, 0x8c, 0x0a, 0x38, 0xbc, 0xc0, 0xac, 0xc0, 0x52, 0x9b, 0xa4, 0x1b, 0x14, 0xb6,
-	0xb7, 0x39, 0x27, 0x9b, 0x0a, 0x89, 0x7c, 0xeb, 0x5e, 0x0b, 0xd9, 0x46, 0x5e, 0x44, 0xcf, 0x2a,
-	0x2f, 0x9a, 0x0e, 0x3a, 0x87, 0x89, 0xae, 0xb7, 0x55, 0xf2, 0xea, 0x22, 0xff, 0x0d, 0x83, 0x03,
-	0x1b, 0x6d, 0x1d, 0xd1, 0x4f, 0x30, 0x75, 0xe5, 0x14, 0x7b, 0x02, 0x93, 0x02, 0x65, 0x86, 0xa9,
-	0xbb, 0xda, 0xe3, 0xb6, 0x43, 0x02, 0x70, 0x17, 0x65, 0x1f, 0xc2, 0x74, 0x57, 0x3a, 0xe4, 0x68,
-	0x40, 0x78, 0x83, 0x6c, 0xe3, 0xd1, 0x0b, 0x78, 0xfb, 0x9b, 0xaa, 0xa8, 0x25, 0x2a, 0x85, 0xe9,
-	0x0f, 0x86, 0x2b, 0xc5, 0xb1, 0xde, 0xe6, 0x89, 0x60, 0x1f, 0xc1, 0x54, 0xb9, 0xd2, 0x8e, 0x92,
-	0x7b, 0xc3, 0x34, 0x8a, 0xb7, 0x80, 0xe8, 0xb5, 0x07, 0x0f, 0xba, 0x44, 0xbd, 0x97, 0xf1, 0x08,
-	0x66, 0xe6, 0x5e, 0x54, 0x2d, 0x12, 0x74, 0x4c, 0x75, 0x8e, 0x21, 0x35, 0xa3, 0x7d, 0x6a, 0x02,
-	0x38, 0xc4, 0x32, 0xed, 0xd1, 0xd6, 0x1c, 0xd9, 0x13, 0x38, 0x4e, 0xda, 0x6a, 0x2b, 0xbb, 0x52,
-	0x4c, 0xea, 0x3d, 0x2f, 0x7b, 0x0a, 0x53, 0x69, 0xc7, 0x51, 0x81, 0x4f, 0x33, 0x84, 0x6e, 0x86,
-	0xff, 0x98, 0x9a, 0xb7, 0xf8, 0x28, 0x86, 0xf1, 0x4a, 0x64, 0x03, 0x91, 0xcd, 0x6f, 0x13, 0xd9,
-	0xbc, 0x59, 0x09, 0x7f, 0x78, 0x30, 0xb1, 0x6a, 0xe9, 0x89, 0x76, 0x6e, 0x44, 0xcb, 0x3e, 0x80,
-	0x09, 0x61, 0x9a, 0xa7, 0x76, 0x7f, 0x7f, 0x37, 0x28, 0xee, 0x00, 0x2c, 0x74, 0x3b, 0xd2, 0x3e,
-	0x15, 0x70, 0xc0, 0x95, 0xc8, 0xec, 0x4a, 0x64, 0x5f, 0x00, 0x74, 0x43, 0xd2, 0xd8, 0xdd, 0x26,
-	0xbd, 0xed, 0x06, 0x78, 0x0f, 0xbe, 0xcc, 0xc1, 0xb7, 0xef, 0x77, 0x09, 0x3e, 0x89, 0x9f, 0x0d,
-	0x1e, 0xbd, 0xdb, 0x21, 0x0f, 0x59, 0xdf, 0x69, 0xdf, 0xc7, 0x99, 0xc7, 0x3e, 0x06, 0x9f, 0x76,
-	0x2a, 0x1b, 0xfc, 0x59, 0x9a, 0x6f, 0xe6, 0xce, 0x49, 0xbb, 0xea, 0xd4, 0xfb, 0xfa, 0xe4, 0xcf,
-	0xeb, 0xd0, 0x7b, 0x7d, 0x1d, 0x7a, 0x7f, 0x5f, 0x87, 0xde, 0xef, 0xff, 0x84, 0x6f, 0xac, 0x27,
-	0xf4, 0xa3, 0x3b, 0xff, 0x37, 0x00, 0x00, 0xff, 0xff, 0x25, 0xa1, 0x04, 0x1f, 0x2a, 0x07, 0x00,
-	0x00,
+	// 805 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x55, 0xdb, 0x6e, 0x13, 0x31,
+	0x10, 0x65, 0x93, 0x6e, 0x2e, 0xd3, 0xd0, 0x8b, 0xa9, 0x44, 0x54, 0x20, 0x42, 0x2b, 0x01, 0xe5,
+	0x96, 0xad, 0x5a, 0x24, 0x50, 0x91, 0x40, 0x82, 0xb6, 0x3c, 0x55, 0x08, 0xb7, 0x82, 0x67, 0x67,
+	0xd7, 0x4d, 0x56, 0xcd, 0x5e, 0xd8, 0x75, 0x10, 0xed, 0x57, 0xf0, 0x09, 0x7c, 0x0e, 0x8f, 0xfd,
+	0x00, 0x1e, 0x10, 0xfc, 0x08, 0xf6, 0xd8, 0x7b, 0x4b, 0x8b, 0xe8, 0x43, 0xa2, 0xf1, 0xcc, 0xb1,
+	0x67, 0xe6, 0xf8, 0x78, 0x16, 0x5e, 0x8d, 0x03, 0x31, 0x99, 0x8d, 0x86, 0x5e, 0x1c, 0xba, 0xe1,
+	0xb6, 0x3f, 0x92, 0x7f, 0x6e, 0x96, 0x7a, 0xee, 0xe7, 0x19, 0x4f, 0x4f, 0xdd, 0x31, 0x8f, 0x78,
+	0xca, 0x04, 0xf7, 0xdd, 0x24, 0x8d, 0x45, 0xec, 0xa6, 0x89, 0x97, 0x8c, 0x74, 0x6c, 0x88, 0x1e,
+	0x62, 0xa3, 0xcb, 0x39, 0x86, 0xde, 0xa7, 0x34, 0x10, 0xfc, 0x80, 0x67, 0x19, 0x1b, 0x73, 0xf2,
+	0x00, 0x6c, 0x44, 0xf5, 0xad, 0xbb, 0xd6, 0xc6, 0xe2, 0xd6, 0xea, 0x10, 0x61, 0x43, 0xc4, 0x7c,
+	0x50, 0x01, 0xaa, 0xe3, 0xe4, 0x29, 0xb4, 0xe3, 0x44, 0x04, 0x71, 0x94, 0xf5, 0x1b, 0x08, 0xbd,
+	0x51, 0x85, 0xbe, 0xd7, 0x21, 0x9a, 0x63, 0x9c, 0x9f, 0x16, 0x40, 0x79, 0x08, 0x21, 0xb0, 0x30,
+	0x8b, 0x02, 0x81, 0x59, 0x6c, 0x8a, 0x36, 0x19, 0x00, 0xb0, 0x28, 0x8a, 0x05, 0x53, 0x3b, 0xf0,
+	0xd0, 0x1e, 0xad, 0x78, 0xc8, 0x26, 0x80, 0xcf, 0x04, 0x4b, 0xe2, 0x20, 0x12, 0x59, 0xbf, 0x79,
+	0xb7, 0x29, 0x93, 0xae, 0x98, 0xa4, 0xbb, 0x79, 0x80, 0x56, 0x30, 0xc4, 0x85, 0x05, 0xc1, 0xc6,
+	0x59, 0x7f, 0x01, 0xb1, 0xb7, 0x2e, 0xf4, 0x32, 0x3c, 0x92, 0xd1, 0xbd, 0x48, 0xc8, 0xae, 0x10,
+	0xb8, 0xfe, 0x1c, 0xba, 0x85, 0x8b, 0xac, 0x40, 0xf3, 0x84, 0x6b, 0x22, 0xba, 0x54, 0x99, 0x64,
+	0x0d, 0xec, 0x2f, 0x6c, 0x3a, 0xe3, 0x58, 0x5c, 0x97, 0xea, 0xc5, 0x4e, 0xe3, 0x85, 0xe5, 0x0c,
+	0x0c, 0x8d, 0xa6, 0x6f, 0xb2, 0x04, 0x8d, 0xc0, 0x37, 0x5b, 0xa5, 0xe5, 0xbc, 0x86, 0x6e, 0x51,
+	0x22, 0xb9, 0x0d, 0x5d, 0x11, 0x84, 0x3c, 0x13, 0x2c, 0x4c, 0x10, 0xd3, 0xa4, 0xa5, 0xa3, 0x9e,
+	0xc4, 0x32, 0x49, 0x9c, 0x09, 0xc0, 0x6e, 0xd9, 0x58, 0x9d, 0x0a, 0xeb, 0x0a, 0x54, 0x6c, 0xc0,
+	0xf2, 0x71, 0xf0, 0x95, 0xfb, 0x94, 0x67, 0xf1, 0x74, 0x56, 0x30, 0xdc, 0xa1, 0xf3, 0x6e, 0xe7,
+	0x0e, 0xd8, 0x7b, 0x69, 0x1a, 0xa7, 0xaa, 0x10, 0xae, 0x0c, 0xd3, 0x86, 0x5e, 0x28, 0xc1, 0xec,
+	0x73, 0xe1, 0x4d, 0xfe, 0x23, 0x18, 0xc4, 0x5c, 0x4d, 0x30, 0x08, 0xbd, 0x20, 0x98, 0x63, 0x80,
+	0xf2, 0x0c, 0x55, 0x8b, 0x64, 0x27, 0x15, 0x86, 0x2e, 0xbd, 0x50, 0x37, 0xc4, 0x23, 0x1f, 0x8f,
+	0x6b, 0x52, 0x65, 0x4a, 0x62, 0x16, 0xe5, 0x45, 0x1e, 0x30, 0xb9, 0x91, 0xa7, 0xb9, 0x48, 0x96,
+	0x4c, 0x22, 0xe3, 0xa6, 0x55, 0x88, 0xba, 0xb9, 0x6a, 0x01, 0x17, 0x6e, 0xee, 0x1d, 0xb4, 0x0d,
+	0x56, 0x89, 0x36, 0x62, 0x21, 0x37, 0x41, 0xb4, 0x2f, 0x97, 0x84, 0x42, 0x8a, 0xd3, 0x84, 0xcb,
+	0xfc, 0xaa, 0x32, 0xb4, 0x9d, 0x67, 0xb0, 0x88, 0x89, 0x24, 0xd5, 0xb3, 0xa9, 0x20, 0xf7, 0xa0,
+	0x95, 0xf1, 0x34, 0xe0, 0xf9, 0xf5, 0x5d, 0x37, 0x45, 0x1e, 0xa2, 0x93, 0x9a, 0xa0, 0x13, 0x42,
+	0xfb, 0x90, 0x8f, 0x43, 0x2e, 0x65, 0x23, 0x0f, 0x9d, 0x70, 0xa6, 0x6b, 0xeb, 0x51, 0xb4, 0x31,
+	0x11, 0x0b, 0xa6, 0xe6, 0xb5, 0xa0, 0xad, 0xe4, 0x85, 0xf4, 0x1c, 0x49, 0x49, 0x99, 0x0a, 0x4a,
+	0x87, 0x8a, 0x8e, 0xa6, 0xb1, 0x77, 0x72, 0x18, 0x9c, 0x71, 0xf9, 0x30, 0x30, 0x5a, 0x38, 0x9c,
+	0x33, 0xe8, 0x98, 0x74, 0x19, 0xb9, 0x0f, 0xad, 0x90, 0xa7, 0x63, 0xee, 0x9b, 0xab, 0x5d, 0x2a,
+	0x2a, 0x44, 0x00, 0x35, 0x51, 0xf2, 0x08, 0x3a, 0xb3, 0xc8, 0x20, 0x1b, 0x35, 0xc2, 0x73, 0x64,
+	0x11, 0x27, 0xeb, 0xd0, 0xe1, 0x91, 0x17, 0xfb, 0x41, 0x34, 0xc6, 0xd2, 0xba, 0xb4, 0x58, 0x3b,
+	0xfb, 0x70, 0xf3, 0x6d, 0x1c, 0x26, 0xa9, 0x14, 0x16, 0xf7, 0x3f, 0x2a, 0x1e, 0x33, 0xca, 0x93,
+	0x69, 0xe0, 0x31, 0xf2, 0x18, 0x3a, 0x99, 0x29, 0xcb, 0xd0, 0xb5, 0x5c, 0x4f, 0x91, 0xd1, 0x02,
+	0xe0, 0x9c, 0x5b, 0xb0, 0x56, 0x1e, 0x54, 0x79, 0x35, 0xb2, 0x75, 0x75, 0x67, 0x59, 0xc2, 0x3c,
+	0x6e, 0x58, 0x2c, 0x1d, 0x75, 0xda, 0x1a, 0xf3, 0xb4, 0xf5, 0xa1, 0x2d, 0xf5, 0x55, 0xa1, 0x34,
+	0x5f, 0x4a, 0x9a, 0x96, 0xbc, 0x22, 0xdb, 0x91, 0x1e, 0x37, 0xea, 0xe8, 0x39, 0x2f, 0xd9, 0x81,
+	0x4e, 0xaa, 0xdb, 0xc9, 0xfa, 0x36, 0xf6, 0x30, 0x30, 0x3d, 0xfc, 0xa3, 0x6b, 0x5a, 0xe0, 0x1d,
+	0x17, 0x9a, 0xf2, 0x8c, 0x9a, 0x00, 0x7b, 0x97, 0x09, 0xb0, 0x97, 0x8f, 0x8b, 0xef, 0x16, 0xb4,
+	0xb4, 0x92, 0x2a, 0x82, 0xee, 0x29, 0x41, 0x93, 0x87, 0xd0, 0x42, 0x4c, 0xfe, 0x0c, 0x57, 0xe7,
+	0xe7, 0x86, 0x14, 0x9f, 0x06, 0xc8, 0x89, 0xac, 0xe7, 0xa7, 0x7e, 0x46, 0x60, 0x80, 0xb2, 0x12,
+	0x3d, 0x2e, 0xc9, 0x4b, 0x80, 0xb2, 0x49, 0x6c, 0xbb, 0x9c, 0xb2, 0x97, 0xdd, 0x00, 0xad, 0xc0,
+	0xb7, 0x02, 0xb0, 0xf5, 0xdb, 0xde, 0x02, 0x1b, 0x1f, 0x06, 0xa9, 0x0d, 0x04, 0x33, 0x5f, 0xd6,
+	0x49, 0xd5, 0xa9, 0xdf, 0xce, 0xa6, 0x45, 0x9e, 0x80, 0x8d, 0xf3, 0x96, 0xd4, 0xbe, 0x3a, 0xf9,
+	0x9e, 0x9e, 0x71, 0xe2, 0x1c, 0xdb, 0xb0, 0xde, 0xac, 0xfc, 0xf8, 0x3d, 0xb0, 0xce, 0xe5, 0xef,
+	0x97, 0xfc, 0x7d, 0xfb, 0x33, 0xb8, 0x36, 0x6a, 0xe1, 0x47, 0x70, 0xfb, 0x2f, 0xb6, 0xaa, 0x40,
+	0x7a, 0x46, 0x07, 0x00, 0x00,
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package graphite maps Graphite dot separated metric paths to the tags
+// of series stored in M3, each path node is stored as its own tag.
+package graphite
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	// DefaultPathTagPrefix is the default prefix of path node tag names.
+	DefaultPathTagPrefix = "__g"
+
+	// DefaultPathTagSuffix is the default suffix of path node tag names.
+	DefaultPathTagSuffix = "__"
+)
+
+// PathMapping maps the nodes of a metric path to tag names, e.g. with the
+// default prefix and suffix the path "a.b" is stored as the tags
+// __g0__="a" and __g1__="b".
+type PathMapping struct {
+	TagPrefix string
+	TagSuffix string
+}
+
+// NewPathMapping returns a new path mapping, empty values use defaults.
+func NewPathMapping(tagPrefix, tagSuffix string) PathMapping {
+	if tagPrefix == "" {
+		tagPrefix = DefaultPathTagPrefix
+	}
+	if tagSuffix == "" {
+		tagSuffix = DefaultPathTagSuffix
+	}
+	return PathMapping{TagPrefix: tagPrefix, TagSuffix: tagSuffix}
+}
+
+// TagName returns the tag name of the path node at the index.
+func (m PathMapping) TagName(index int) string {
+	return m.TagPrefix + strconv.Itoa(index) + m.TagSuffix
+}
+
+// Matchers returns the matchers selecting series whose path matches the
+// glob query, a series may have more nodes than the query.
+func (m PathMapping) Matchers(query string) (models.Matchers, error) {
+	if query == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	nodes := strings.Split(query, ".")
+	matchers := make(models.Matchers, 0, len(nodes))
+	for i, node := range nodes {
+		var (
+			matcher *models.Matcher
+			err     error
+		)
+		if isGlob(node) {
+			var re string
+			re, err = GlobToRegex(node)
+			if err != nil {
+				return nil, err
+			}
+			matcher, err = models.NewMatcher(models.MatchRegexp, m.TagName(i), re)
+		} else {
+			matcher, err = models.NewMatcher(models.MatchEqual, m.TagName(i), node)
+		}
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// Path returns the path of the series truncated to the number of nodes,
+// returning false if the series has fewer nodes.
+func (m PathMapping) Path(tags models.Tags, nodes int) (string, bool) {
+	var buf bytes.Buffer
+	for i := 0; i < nodes; i++ {
+		value, ok := tags[m.TagName(i)]
+		if !ok {
+			return "", false
+		}
+		if i > 0 {
+			buf.WriteByte('.')
+		}
+		buf.WriteString(value)
+	}
+	return buf.String(), true
+}
+
+// HasNode returns whether the series has a path node at the index.
+func (m PathMapping) HasNode(tags models.Tags, index int) bool {
+	_, ok := tags[m.TagName(index)]
+	return ok
+}
+
+func isGlob(node string) bool {
+	return strings.ContainsAny(node, "*?[{")
+}
+
+// GlobToRegex converts a Graphite glob path node to a regular expression,
+// supporting the *, ?, [...] and {a,b} wildcards.
+func GlobToRegex(glob string) (string, error) {
+	var (
+		buf     bytes.Buffer
+		inGroup bool
+	)
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			buf.WriteString("[^.]*")
+		case '?':
+			buf.WriteString("[^.]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unbalanced '[' in glob: %s", glob)
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + class + "]")
+			i += end
+		case '{':
+			if inGroup {
+				return "", fmt.Errorf("nested '{' in glob: %s", glob)
+			}
+			inGroup = true
+			buf.WriteString("(?:")
+		case '}':
+			if !inGroup {
+				return "", fmt.Errorf("unbalanced '}' in glob: %s", glob)
+			}
+			inGroup = false
+			buf.WriteString(")")
+		case ',':
+			if inGroup {
+				buf.WriteString("|")
+			} else {
+				buf.WriteByte(c)
+			}
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if inGroup {
+		return "", fmt.Errorf("unbalanced '{' in glob: %s", glob)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob     string
+		expected string
+	}{
+		{"foo", "foo"},
+		{"foo*", "foo[^.]*"},
+		{"f?o", "f[^.]o"},
+		{"[ab]c", "[ab]c"},
+		{"[!ab]c", "[^ab]c"},
+		{"{foo,bar}_total", "(?:foo|bar)_total"},
+		{"a+b", `a\+b`},
+	}
+	for _, test := range tests {
+		re, err := GlobToRegex(test.glob)
+		require.NoError(t, err, test.glob)
+		assert.Equal(t, test.expected, re, test.glob)
+	}
+
+	for _, glob := range []string{"[ab", "{a,b", "a}", "{a,{b}}"} {
+		_, err := GlobToRegex(glob)
+		assert.Error(t, err, glob)
+	}
+}
+
+func TestPathMappingMatchers(t *testing.T) {
+	mapping := NewPathMapping("", "")
+	matchers, err := mapping.Matchers("servers.host*.cpu")
+	require.NoError(t, err)
+	require.Len(t, matchers, 3)
+
+	assert.Equal(t, "__g0__", matchers[0].Name)
+	assert.Equal(t, models.MatchEqual, matchers[0].Type)
+	assert.Equal(t, "__g1__", matchers[1].Name)
+	assert.Equal(t, models.MatchRegexp, matchers[1].Type)
+	assert.True(t, matchers[1].Matches("host01"))
+	assert.False(t, matchers[1].Matches("db01"))
+	assert.Equal(t, "cpu", matchers[2].Value)
+
+	_, err = mapping.Matchers("")
+	assert.Error(t, err)
+}
+
+func TestPathMappingPath(t *testing.T) {
+	mapping := NewPathMapping("_p", "")
+	tags := models.Tags{"_p0__": "a", "_p1__": "b", "_p2__": "c"}
+
+	path, ok := mapping.Path(tags, 2)
+	require.True(t, ok)
+	assert.Equal(t, "a.b", path)
+	assert.True(t, mapping.HasNode(tags, 2))
+	assert.False(t, mapping.HasNode(tags, 3))
+
+	_, ok = mapping.Path(tags, 4)
+	assert.False(t, ok)
+}
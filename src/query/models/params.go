@@ -35,6 +35,14 @@ type RequestParams struct {
 	Target     string
 	Debug      bool
 	IncludeEnd bool
+	// LookbackDuration is how far back a step looks for the most recent
+	// datapoint, datapoints are carried forward indefinitely if zero.
+	LookbackDuration time.Duration
+	// Limit is the max number of series fetched, zero means no limit.
+	Limit int
+	// SampleOnLimit fetches a deterministic sample of series instead of the
+	// first series found when more than Limit series match.
+	SampleOnLimit bool
 }
 
 // ExclusiveEnd returns the end exclusive
@@ -0,0 +1,154 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/m3db/m3/src/query/parser"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	// DefaultParseCacheSize is the default max number of parsed queries
+	// kept by a parse cache.
+	DefaultParseCacheSize = 4096
+)
+
+// ParseCache is an LRU cache of parsed queries keyed by query string, so
+// that queries issued repeatedly skip parsing and building the DAG.
+type ParseCache interface {
+	// Parse returns the cached parsed query, parsing and caching the query
+	// if it is not cached.
+	Parse(q string) (parser.Parser, error)
+}
+
+type parseCacheMetrics struct {
+	hits      tally.Counter
+	misses    tally.Counter
+	evictions tally.Counter
+	size      tally.Gauge
+}
+
+func newParseCacheMetrics(scope tally.Scope) parseCacheMetrics {
+	return parseCacheMetrics{
+		hits:      scope.Counter("hits"),
+		misses:    scope.Counter("misses"),
+		evictions: scope.Counter("evictions"),
+		size:      scope.Gauge("size"),
+	}
+}
+
+type parseCacheEntry struct {
+	query  string
+	parser *cachedParser
+}
+
+type parseCache struct {
+	sync.Mutex
+
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+	metrics parseCacheMetrics
+}
+
+// NewParseCache returns a new parse cache that holds at most size parsed
+// queries, evicting the least recently used query when full.
+func NewParseCache(size int, scope tally.Scope) ParseCache {
+	if size <= 0 {
+		size = DefaultParseCacheSize
+	}
+	return &parseCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		lru:     list.New(),
+		metrics: newParseCacheMetrics(scope),
+	}
+}
+
+func (c *parseCache) Parse(q string) (parser.Parser, error) {
+	c.Lock()
+	if elem, ok := c.entries[q]; ok {
+		c.lru.MoveToFront(elem)
+		c.Unlock()
+		c.metrics.hits.Inc(1)
+		return elem.Value.(parseCacheEntry).parser, nil
+	}
+	c.Unlock()
+	c.metrics.misses.Inc(1)
+
+	// Parse outside of the lock, concurrent misses for the same query
+	// parse it more than once but result in the same entry.
+	p, err := Parse(q)
+	if err != nil {
+		// Do not cache invalid queries, they are usually one off.
+		return nil, err
+	}
+	cached := newCachedParser(p)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[q]; ok {
+		c.lru.MoveToFront(elem)
+		return elem.Value.(parseCacheEntry).parser, nil
+	}
+
+	c.entries[q] = c.lru.PushFront(parseCacheEntry{query: q, parser: cached})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(parseCacheEntry).query)
+		c.metrics.evictions.Inc(1)
+	}
+	c.metrics.size.Update(float64(c.lru.Len()))
+	return cached, nil
+}
+
+// cachedParser is a parser with its DAG built once, the nodes and edges are
+// immutable so they are shared by every query execution.
+type cachedParser struct {
+	str   string
+	nodes parser.Nodes
+	edges parser.Edges
+	err   error
+}
+
+func newCachedParser(p parser.Parser) *cachedParser {
+	nodes, edges, err := p.DAG()
+	return &cachedParser{
+		str:   p.String(),
+		nodes: nodes,
+		edges: edges,
+		err:   err,
+	}
+}
+
+func (p *cachedParser) DAG() (parser.Nodes, parser.Edges, error) {
+	return p.nodes, p.edges, p.err
+}
+
+func (p *cachedParser) String() string {
+	return p.str
+}
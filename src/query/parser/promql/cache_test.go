@@ -0,0 +1,80 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestParseCacheHitsAndEvictions(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	cache := NewParseCache(2, scope)
+
+	q := "sum(http_requests_total{method=\"GET\"}) by (service)"
+	first, err := cache.Parse(q)
+	require.NoError(t, err)
+	second, err := cache.Parse(q)
+	require.NoError(t, err)
+	assert.True(t, first == second, "expected cached parser")
+
+	expected, err := Parse(q)
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), second.String())
+	nodes, edges, err := second.DAG()
+	require.NoError(t, err)
+	expectedNodes, expectedEdges, err := expected.DAG()
+	require.NoError(t, err)
+	assert.Equal(t, expectedNodes, nodes)
+	assert.Equal(t, expectedEdges, edges)
+
+	_, err = cache.Parse("up")
+	require.NoError(t, err)
+	_, err = cache.Parse("foo")
+	require.NoError(t, err)
+
+	// The first query was least recently used so it was evicted.
+	third, err := cache.Parse(q)
+	require.NoError(t, err)
+	assert.False(t, first == third, "expected evicted parser")
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["hits+"].Value())
+	assert.Equal(t, int64(4), counters["misses+"].Value())
+	assert.Equal(t, int64(2), counters["evictions+"].Value())
+}
+
+func TestParseCacheDoesNotCacheErrors(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	cache := NewParseCache(2, scope)
+
+	_, err := cache.Parse("sum(")
+	require.Error(t, err)
+	_, err = cache.Parse("sum(")
+	require.Error(t, err)
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(2), counters["misses+"].Value())
+	assert.Nil(t, counters["hits+"])
+}
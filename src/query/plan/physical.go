@@ -37,6 +37,14 @@ type PhysicalPlan struct {
 	ResultStep ResultOp
 	TimeSpec   transform.TimeSpec
 	Debug      bool
+	// LookbackDuration is how far back a step looks for the most recent
+	// datapoint, datapoints are carried forward indefinitely if zero.
+	LookbackDuration time.Duration
+	// Limit is the max number of series fetched, zero means no limit.
+	Limit int
+	// SampleOnLimit fetches a deterministic sample of series instead of the
+	// first series found when more than Limit series match.
+	SampleOnLimit bool
 }
 
 // ResultOp is resonsible for delivering results to the clients
@@ -59,7 +67,10 @@ func NewPhysicalPlan(lp LogicalPlan, storage storage.Storage, params models.Requ
 			Now:   params.Now,
 			Step:  params.Step,
 		},
-		Debug: params.Debug,
+		Debug:            params.Debug,
+		LookbackDuration: params.LookbackDuration,
+		Limit:            params.Limit,
+		SampleOnLimit:    params.SampleOnLimit,
 	}
 
 	pl, err := p.createResultNode()
@@ -23,6 +23,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"os"
@@ -41,8 +42,12 @@ import (
 	"github.com/m3db/m3/src/query/policy/filter"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/fanout"
+	"github.com/m3db/m3/src/query/storage/ingest"
 	"github.com/m3db/m3/src/query/storage/local"
+	"github.com/m3db/m3/src/query/storage/mirror"
 	"github.com/m3db/m3/src/query/storage/remote"
+	"github.com/m3db/m3/src/query/storage/selftelemetry"
+	"github.com/m3db/m3/src/query/storage/verify"
 	"github.com/m3db/m3/src/query/stores/m3db"
 	tsdbRemote "github.com/m3db/m3/src/query/tsdb/remote"
 	"github.com/m3db/m3/src/query/util/logging"
@@ -93,6 +98,13 @@ type RunOptions struct {
 	// InterruptCh is a programmatic interrupt channel to supply to
 	// interrupt and shutdown the server.
 	InterruptCh <-chan error
+
+	// CustomHandlers are registered with the HTTP router in addition to the
+	// built in handlers.
+	CustomHandlers []httpd.CustomHandler
+
+	// Middleware wraps the HTTP router.
+	Middleware []httpd.Middleware
 }
 
 // Run runs the server programmatically given a filename for the configuration file.
@@ -119,6 +131,28 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("could not connect to metrics", zap.Any("error", err))
 	}
 
+	var selfTelemetry selftelemetry.Reporter
+	if selfCfg := cfg.SelfTelemetry; selfCfg != nil {
+		attrs := storage.Attributes{MetricsType: storage.UnaggregatedMetricsType}
+		if selfCfg.Retention > 0 {
+			attrs = storage.Attributes{
+				MetricsType: storage.AggregatedMetricsType,
+				Retention:   selfCfg.Retention,
+				Resolution:  selfCfg.Resolution,
+			}
+		}
+		logger.Info("writing self telemetry to storage",
+			zap.String("prefix", selfCfg.Prefix),
+			zap.Duration("retention", attrs.Retention),
+			zap.Duration("resolution", attrs.Resolution))
+
+		selfTelemetry = selftelemetry.NewReporter(selfCfg.Prefix, attrs)
+		var selfTelemetryCloser io.Closer
+		scope, selfTelemetryCloser = selftelemetry.NewScope(scope, selfTelemetry,
+			selfCfg.ReportIntervalOrDefault())
+		defer selfTelemetryCloser.Close()
+	}
+
 	var clusterClientCh <-chan clusterclient.Client
 	if runOpts.ClusterClient != nil {
 		clusterClientCh = runOpts.ClusterClient
@@ -154,6 +188,7 @@ func Run(runOpts RunOptions) {
 	if len(cfg.Clusters) > 0 {
 		opts := local.ClustersStaticConfigurationOptions{
 			AsyncSessions: true,
+			MetricsScope:  scope.SubScope("clusters"),
 		}
 		clusters, err = cfg.Clusters.NewClusters(opts)
 		if err != nil {
@@ -211,9 +246,67 @@ func Run(runOpts RunOptions) {
 		return workerPool
 	})
 
-	fanoutStorage, storageCleanup := newStorages(logger, clusters, cfg, objectPool)
+	fanoutStorage, storageCleanup := newStorages(logger, clusters, cfg, objectPool,
+		instrumentOptions)
 	defer storageCleanup()
 
+	if mirrorCfg := cfg.WriteMirror; mirrorCfg != nil {
+		mirrorClusters, err := mirrorCfg.Clusters.NewClusters(
+			local.ClustersStaticConfigurationOptions{AsyncSessions: true})
+		if err != nil {
+			logger.Fatal("unable to connect to write mirror clusters", zap.Any("error", err))
+		}
+
+		logger.Info("mirroring writes to secondary clusters",
+			zap.Float64("percent", mirrorCfg.Percent))
+		mirrorStorage := mirror.NewStorage(fanoutStorage,
+			local.NewStorage(mirrorClusters, objectPool), mirror.Options{
+				Percent:     mirrorCfg.Percent,
+				QueueSize:   mirrorCfg.QueueSize,
+				Concurrency: mirrorCfg.Concurrency,
+				Scope:       scope.SubScope("write-mirror"),
+			})
+		defer func() {
+			if err := mirrorStorage.Close(); err != nil {
+				logger.Error("unable to close write mirror", zap.Any("error", err))
+			}
+			if err := mirrorClusters.Close(); err != nil {
+				logger.Error("unable to close write mirror clusters", zap.Any("error", err))
+			}
+		}()
+		fanoutStorage = mirrorStorage
+	}
+
+	if verifyCfg := cfg.ReadVerification; verifyCfg != nil {
+		verifyClusters, err := verifyCfg.Clusters.NewClusters(
+			local.ClustersStaticConfigurationOptions{AsyncSessions: true})
+		if err != nil {
+			logger.Fatal("unable to connect to read verification clusters", zap.Any("error", err))
+		}
+
+		logger.Info("verifying reads against secondary clusters",
+			zap.Float64("percent", verifyCfg.Percent),
+			zap.Float64("tolerance", verifyCfg.Tolerance))
+		verifyStorage := verify.NewStorage(fanoutStorage,
+			local.NewStorage(verifyClusters, objectPool), verify.Options{
+				Percent:     verifyCfg.Percent,
+				Tolerance:   verifyCfg.Tolerance,
+				Timeout:     verifyCfg.Timeout,
+				MaxInFlight: verifyCfg.MaxInFlight,
+				Scope:       scope.SubScope("read-verification"),
+			})
+		defer func() {
+			if err := verifyClusters.Close(); err != nil {
+				logger.Error("unable to close read verification clusters", zap.Any("error", err))
+			}
+		}()
+		fanoutStorage = verifyStorage
+	}
+
+	if selfTelemetry != nil {
+		selfTelemetry.SetAppender(fanoutStorage)
+	}
+
 	var clusterClient clusterclient.Client
 	if clusterClientCh != nil {
 		// Only use a cluster client if we are going to receive one, that
@@ -231,10 +324,10 @@ func Run(runOpts RunOptions) {
 		logger.Info("configuring downsampler to use with aggregated cluster namespaces",
 			zap.Int("numAggregatedClusterNamespaces", n))
 		downsampler = newDownsampler(logger, clusterManagementClient,
-			fanoutStorage, instrumentOptions)
+			fanoutStorage, cfg.Downsample, instrumentOptions)
 	}
 
-	engine := executor.NewEngine(fanoutStorage)
+	engine := executor.NewEngine(fanoutStorage, cfg.LookbackDuration)
 
 	handler, err := httpd.NewHandler(fanoutStorage, downsampler, engine,
 		clusterClient, cfg, runOpts.DBConfig, scope)
@@ -242,6 +335,10 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("unable to set up handlers", zap.Any("error", err))
 	}
 	handler.RegisterRoutes()
+	if err := handler.RegisterCustomHandlers(runOpts.CustomHandlers...); err != nil {
+		logger.Fatal("unable to register custom handlers", zap.Error(err))
+	}
+	handler.UseMiddleware(runOpts.Middleware...)
 
 	listenAddress, err := cfg.ListenAddress.Resolve()
 	if err != nil {
@@ -250,7 +347,7 @@ func Run(runOpts RunOptions) {
 
 	logger.Info("starting server", zap.String("address", listenAddress))
 	go func() {
-		if err := http.ListenAndServe(listenAddress, handler.Router); err != nil {
+		if err := http.ListenAndServe(listenAddress, handler); err != nil {
 			logger.Fatal("unable to serve on listen address",
 				zap.String("address", listenAddress), zap.Error(err))
 		}
@@ -278,6 +375,7 @@ func newDownsampler(
 	logger *zap.Logger,
 	clusterManagementClient clusterclient.Client,
 	storage storage.Storage,
+	cfg config.DownsampleConfiguration,
 	instrumentOpts instrument.Options,
 ) downsample.Downsampler {
 	if clusterManagementClient == nil {
@@ -311,6 +409,8 @@ func newDownsampler(
 		TagDecoderOptions:     tagDecoderOptions,
 		TagEncoderPoolOptions: tagEncoderPoolOptions,
 		TagDecoderPoolOptions: tagDecoderPoolOptions,
+		LateArrival:           cfg.LateArrival,
+		Deadband:              cfg.Deadband,
 	})
 	if err != nil {
 		logger.Fatal("unable to create downsampler", zap.Any("error", err))
@@ -324,6 +424,7 @@ func newStorages(
 	clusters local.Clusters,
 	cfg config.Configuration,
 	workerPool pool.ObjectPool,
+	instrumentOpts instrument.Options,
 ) (storage.Storage, func()) {
 	cleanup := func() {}
 
@@ -332,7 +433,8 @@ func newStorages(
 	remoteEnabled := false
 	if cfg.RPC != nil && cfg.RPC.Enabled {
 		logger.Info("rpc enabled")
-		server := startGrpcServer(logger, localStorage, cfg.RPC)
+		server := startGrpcServer(logger, localStorage, cfg.RPC,
+			cfg.WriteIdempotency.NewIdempotencyCache())
 		cleanup = func() {
 			server.GracefulStop()
 		}
@@ -348,6 +450,17 @@ func newStorages(
 		}
 	}
 
+	for _, backendCfg := range cfg.RemoteReads {
+		backend, err := backendCfg.NewStorage(instrumentOpts)
+		if err != nil {
+			logger.Fatal("unable to create remote read backend", zap.Any("error", err))
+		}
+
+		logger.Info("remote read backend enabled", zap.String("name", backendCfg.Name))
+		stores = append(stores, backend)
+		remoteEnabled = true
+	}
+
 	readFilter := filter.LocalOnly
 	if remoteEnabled {
 		readFilter = filter.AllowAll
@@ -357,9 +470,14 @@ func newStorages(
 	return fanoutStorage, cleanup
 }
 
-func startGrpcServer(logger *zap.Logger, storage storage.Storage, cfg *config.RPCConfiguration) *grpc.Server {
+func startGrpcServer(
+	logger *zap.Logger,
+	storage storage.Storage,
+	cfg *config.RPCConfiguration,
+	idempotency ingest.IdempotencyCache,
+) *grpc.Server {
 	logger.Info("creating gRPC server")
-	server := tsdbRemote.CreateNewGrpcServer(storage)
+	server := tsdbRemote.CreateNewGrpcServer(storage, idempotency)
 	waitForStart := make(chan struct{})
 	go func() {
 		logger.Info("starting gRPC server on port", zap.Any("rpc", cfg.ListenAddress))
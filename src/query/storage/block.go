@@ -29,8 +29,9 @@ import (
 )
 
 // FetchResultToBlockResult converts a fetch result into coordinator blocks
-func FetchResultToBlockResult(result *FetchResult, query *FetchQuery) (block.Result, error) {
-	alignedSeriesList, err := result.SeriesList.Align(query.Start, query.End, query.Interval)
+func FetchResultToBlockResult(result *FetchResult, query *FetchQuery, options *FetchOptions) (block.Result, error) {
+	alignedSeriesList, err := result.SeriesList.Align(query.Start, query.End,
+		query.Interval, options.LookbackDuration)
 	if err != nil {
 		return block.Result{}, err
 	}
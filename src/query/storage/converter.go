@@ -128,6 +128,41 @@ func PromTypeToM3(labelType prompb.LabelMatcher_Type) (models.MatchType, error)
 	}
 }
 
+// M3MatchersToProm converts m3 matchers to prometheus label matchers
+func M3MatchersToProm(matchers models.Matchers) ([]*prompb.LabelMatcher, error) {
+	result := make([]*prompb.LabelMatcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		matchType, err := M3TypeToProm(matcher.Type)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &prompb.LabelMatcher{
+			Type:  matchType,
+			Name:  matcher.Name,
+			Value: matcher.Value,
+		})
+	}
+
+	return result, nil
+}
+
+// M3TypeToProm converts an m3 matcher type to a prometheus label type
+func M3TypeToProm(matchType models.MatchType) (prompb.LabelMatcher_Type, error) {
+	switch matchType {
+	case models.MatchEqual:
+		return prompb.LabelMatcher_EQ, nil
+	case models.MatchNotEqual:
+		return prompb.LabelMatcher_NEQ, nil
+	case models.MatchRegexp:
+		return prompb.LabelMatcher_RE, nil
+	case models.MatchNotRegexp:
+		return prompb.LabelMatcher_NRE, nil
+
+	default:
+		return 0, fmt.Errorf("unknown match type: %v", matchType)
+	}
+}
+
 // TimestampToTime converts a prometheus timestamp to time.Time
 func TimestampToTime(timestampMS int64) time.Time {
 	return time.Unix(0, timestampMS*int64(time.Millisecond))
@@ -201,8 +236,12 @@ func iteratorToTsSeries(
 
 	datapoints := make(ts.Datapoints, 0, initRawFetchAllocSize)
 	for iter.Next() {
-		dp, _, _ := iter.Current()
-		datapoints = append(datapoints, ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value})
+		dp, _, annotation := iter.Current()
+		datapoint := ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value}
+		if len(annotation) > 0 {
+			datapoint.Annotation = append([]byte(nil), annotation...)
+		}
+		datapoints = append(datapoints, datapoint)
 	}
 
 	return ts.NewSeries(metric.ID, datapoints, metric.Tags), nil
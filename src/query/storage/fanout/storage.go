@@ -58,18 +58,32 @@ func (s *fanoutStorage) Fetch(ctx context.Context, query *storage.FetchQuery, op
 		return nil, err
 	}
 
-	return handleFetchResponses(requests)
+	return handleFetchResponses(ctx, requests)
 }
 
-func handleFetchResponses(requests []execution.Request) (*storage.FetchResult, error) {
-	seriesList := make([]*ts.Series, 0, len(requests))
-	result := &storage.FetchResult{SeriesList: seriesList, LocalOnly: true}
+// handleFetchResponses merges the results of the stores, a series returned
+// by more than one store, such as while series are migrated between them,
+// is only included from the first store that returned it.
+func handleFetchResponses(
+	ctx context.Context,
+	requests []execution.Request,
+) (*storage.FetchResult, error) {
+	var (
+		seriesList = make([]*ts.Series, 0, len(requests))
+		result     = &storage.FetchResult{SeriesList: seriesList, LocalOnly: true}
+		seen       = make(map[string]struct{})
+	)
 	for _, req := range requests {
 		fetchreq, ok := req.(*fetchRequest)
 		if !ok {
 			return nil, errors.ErrFetchRequestType
 		}
 
+		if fetchreq.err != nil {
+			warnStoreError(ctx, "fetch", fetchreq.store, fetchreq.err)
+			continue
+		}
+
 		if fetchreq.result == nil {
 			return nil, errors.ErrInvalidFetchResult
 		}
@@ -78,22 +92,57 @@ func handleFetchResponses(requests []execution.Request) (*storage.FetchResult, e
 			result.LocalOnly = false
 		}
 
-		result.SeriesList = append(result.SeriesList, fetchreq.result.SeriesList...)
+		matched := fetchreq.result.MatchedSeries
+		for _, series := range fetchreq.result.SeriesList {
+			if _, ok := seen[series.Name()]; ok {
+				matched--
+				continue
+			}
+			seen[series.Name()] = struct{}{}
+			result.SeriesList = append(result.SeriesList, series)
+		}
+		result.Truncated = result.Truncated || fetchreq.result.Truncated
+		result.MatchedSeries += matched
 	}
 
 	return result, nil
 }
 
+// isPrimary returns whether errors of the store fail queries, errors of
+// stores other than local storage are only logged so that queries are
+// served from local storage when remote backends are unavailable.
+func isPrimary(store storage.Storage) bool {
+	return store.Type() == storage.TypeLocalDC
+}
+
+func warnStoreError(ctx context.Context, op string, store storage.Storage, err error) {
+	logging.WithContext(ctx).Warn("partial results, unable to "+op+" from store",
+		zap.Int("store", int(store.Type())), zap.Any("error", err))
+}
+
 func (s *fanoutStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
-	var metrics models.Metrics
+	var (
+		metrics models.Metrics
+		seen    = make(map[string]struct{})
+	)
 
 	stores := filterStores(s.stores, s.fetchFilter, query)
 	for _, store := range stores {
 		results, err := store.FetchTags(ctx, query, options)
-		if err != nil {
+		if err != nil && isPrimary(store) {
 			return nil, err
 		}
-		metrics = append(metrics, results.Metrics...)
+		if err != nil {
+			warnStoreError(ctx, "fetch tags", store, err)
+			continue
+		}
+		for _, metric := range results.Metrics {
+			if _, ok := seen[metric.ID]; ok {
+				continue
+			}
+			seen[metric.ID] = struct{}{}
+			metrics = append(metrics, metric)
+		}
 	}
 
 	result := &storage.SearchResults{Metrics: metrics}
@@ -101,6 +150,27 @@ func (s *fanoutStorage) FetchTags(ctx context.Context, query *storage.FetchQuery
 	return result, nil
 }
 
+func (s *fanoutStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.CountResult, error) {
+	result := &storage.CountResult{Exhaustive: true}
+
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	for _, store := range stores {
+		storeResult, err := store.FetchCount(ctx, query, options)
+		if err != nil && isPrimary(store) {
+			return nil, err
+		}
+		if err != nil {
+			warnStoreError(ctx, "fetch count", store, err)
+			result.Exhaustive = false
+			continue
+		}
+		result.Count += storeResult.Count
+		result.Exhaustive = result.Exhaustive && storeResult.Exhaustive
+	}
+
+	return result, nil
+}
+
 func (s *fanoutStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	stores := filterStores(s.stores, s.writeFilter, query)
 	requests := make([]execution.Request, len(stores))
@@ -117,18 +187,22 @@ func (s *fanoutStorage) Type() storage.Type {
 
 func (s *fanoutStorage) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (block.Result, error) {
-	stores := filterStores(s.stores, s.writeFilter, query)
-	blockResult := block.Result{}
-	for _, store := range stores {
-		result, err := store.FetchBlocks(ctx, query, options)
-		if err != nil {
-			return block.Result{}, err
-		}
-
-		blockResult.Blocks = append(blockResult.Blocks, result.Blocks...)
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	switch len(stores) {
+	case 0:
+		return block.Result{}, nil
+	case 1:
+		return stores[0].FetchBlocks(ctx, query, options)
 	}
 
-	return blockResult, nil
+	// NB: the series of more than one store are merged and deduped before
+	// they are converted to blocks so that series returned by more than one
+	// store are not double counted.
+	result, err := s.Fetch(ctx, query, options)
+	if err != nil {
+		return block.Result{}, err
+	}
+	return storage.FetchResultToBlockResult(result, query, options)
 }
 
 func (s *fanoutStorage) Close() error {
@@ -159,6 +233,7 @@ type fetchRequest struct {
 	query   *storage.FetchQuery
 	options *storage.FetchOptions
 	result  *storage.FetchResult
+	err     error
 }
 
 func newFetchRequest(store storage.Storage, query *storage.FetchQuery, options *storage.FetchOptions) execution.Request {
@@ -171,11 +246,11 @@ func newFetchRequest(store storage.Storage, query *storage.FetchQuery, options *
 
 func (f *fetchRequest) Process(ctx context.Context) error {
 	result, err := f.store.Fetch(ctx, f.query, f.options)
-	if err != nil {
+	if err != nil && isPrimary(f.store) {
 		return err
 	}
 
-	f.result = result
+	f.result, f.err = result, err
 	return nil
 }
 
@@ -28,8 +28,10 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/query/errors"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/policy/filter"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
 	"github.com/m3db/m3/src/query/test/local"
 	"github.com/m3db/m3/src/query/test/seriesiter"
 	"github.com/m3db/m3/src/query/ts"
@@ -127,6 +129,47 @@ func TestFanoutReadSuccess(t *testing.T) {
 	assert.NoError(t, store.Close())
 }
 
+func newTestMockStorage(storeType storage.Type, ids []string, err error) mock.Storage {
+	store := mock.NewMockStorage()
+	store.SetTypeResult(storeType)
+	result := &storage.FetchResult{MatchedSeries: len(ids)}
+	for _, id := range ids {
+		result.SeriesList = append(result.SeriesList,
+			ts.NewSeries(id, ts.NewFixedStepValues(time.Second, 1, 1, time.Now()), models.Tags{}))
+	}
+	if err != nil {
+		result = nil
+	}
+	store.SetFetchResult(result, err)
+	return store
+}
+
+func TestFanoutReadDedupesAndIgnoresRemoteErrors(t *testing.T) {
+	setup()
+	stores := []storage.Storage{
+		newTestMockStorage(storage.TypeLocalDC, []string{"a", "b"}, nil),
+		newTestMockStorage(storage.TypeRemoteDC, []string{"b", "c"}, nil),
+		newTestMockStorage(storage.TypeRemoteDC, nil, fmt.Errorf("unavailable")),
+	}
+	store := NewStorage(stores, filter.AllowAll, filter.LocalOnly)
+
+	res, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	var ids []string
+	for _, series := range res.SeriesList {
+		ids = append(ids, series.Name())
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.Equal(t, 3, res.MatchedSeries)
+	assert.False(t, res.LocalOnly)
+
+	// errors of local storage still fail the query.
+	stores[0] = newTestMockStorage(storage.TypeLocalDC, nil, fmt.Errorf("unavailable"))
+	store = NewStorage(stores, filter.AllowAll, filter.LocalOnly)
+	_, err = store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	assert.Error(t, err)
+}
+
 func TestFanoutSearchEmpty(t *testing.T) {
 	store := setupFanoutRead(t, false)
 	res, err := store.FetchTags(context.TODO(), nil, nil)
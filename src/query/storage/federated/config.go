@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package federated
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+)
+
+var (
+	errNoName = errors.New("remote read backend name required")
+	errNoURL  = errors.New("remote read backend url required")
+)
+
+// BackendConfiguration is the configuration of a Prometheus remote read
+// endpoint that reads are fanned out to alongside local storage.
+type BackendConfiguration struct {
+	// Name identifies the backend in metrics and logs.
+	Name string `yaml:"name"`
+
+	// URL is the remote read endpoint of the backend.
+	URL string `yaml:"url"`
+
+	// SeriesURL is the series metadata endpoint of the backend, such as the
+	// /api/v1/series endpoint of Prometheus, used to fetch the tags of series
+	// without their samples. If not set the backend does not serve tag
+	// queries.
+	SeriesURL string `yaml:"seriesURL"`
+
+	// Timeout is the timeout of each read from the backend.
+	Timeout time.Duration `yaml:"timeout" validate:"min=0"`
+
+	// Headers are extra headers sent with each read, such as auth headers.
+	Headers map[string]string `yaml:"headers"`
+
+	// Range is the time range the backend serves reads for, if not set
+	// the backend serves reads for all time.
+	Range TimeRangeConfiguration `yaml:"range"`
+}
+
+// TimeRangeConfiguration is the time range that a backend is applicable for,
+// reads are clipped to the range and skipped if outside of it entirely.
+type TimeRangeConfiguration struct {
+	// Start is the earliest time the backend serves reads for.
+	Start *time.Time `yaml:"start"`
+
+	// End is the time the backend serves reads up until, such as the time
+	// writes were migrated away from the backend.
+	End *time.Time `yaml:"end"`
+
+	// MaxAge limits reads to the retention of the backend.
+	MaxAge time.Duration `yaml:"maxAge" validate:"min=0"`
+}
+
+// NewStorage returns a storage that reads from the backend.
+func (c BackendConfiguration) NewStorage(
+	instrumentOpts instrument.Options,
+) (storage.Storage, error) {
+	if c.Name == "" {
+		return nil, errNoName
+	}
+	if c.URL == "" {
+		return nil, errNoURL
+	}
+	if c.Range.Start != nil && c.Range.End != nil &&
+		!c.Range.End.After(*c.Range.Start) {
+		return nil, fmt.Errorf(
+			"remote read backend %s range end must be after start", c.Name)
+	}
+
+	timeout := defaultTimeout
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+
+	scope := instrumentOpts.MetricsScope().
+		SubScope("remote-read").
+		Tagged(map[string]string{"backend": c.Name})
+	return NewStorage(Options{
+		Name:       c.Name,
+		URL:        c.URL,
+		SeriesURL:  c.SeriesURL,
+		Headers:    c.Headers,
+		Range:      c.Range,
+		HTTPClient: &http.Client{Timeout: timeout},
+		Scope:      scope,
+	}), nil
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package federated
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/uber-go/tally"
+)
+
+const (
+	// maxErrorBodyBytes is the max number of bytes of an error response
+	// body included in the returned error.
+	maxErrorBodyBytes = 512
+)
+
+var (
+	errReadOnly          = errors.New("remote read backend is read only")
+	errFilterUnsupported = errors.New("remote read backend does not support filters")
+)
+
+// Options are the options of a remote read storage.
+type Options struct {
+	Name       string
+	URL        string
+	SeriesURL  string
+	Headers    map[string]string
+	Range      TimeRangeConfiguration
+	HTTPClient *http.Client
+	Scope      tally.Scope
+	NowFn      func() time.Time
+}
+
+type federatedStorageMetrics struct {
+	fetchSuccess tally.Counter
+	fetchErrors  tally.Counter
+	fetchSkipped tally.Counter
+	fetchLatency tally.Timer
+}
+
+func newFederatedStorageMetrics(scope tally.Scope) federatedStorageMetrics {
+	return federatedStorageMetrics{
+		fetchSuccess: scope.Counter("fetch.success"),
+		fetchErrors:  scope.Counter("fetch.errors"),
+		fetchSkipped: scope.Counter("fetch.skipped"),
+		fetchLatency: scope.Timer("fetch.latency"),
+	}
+}
+
+type federatedStorage struct {
+	opts    Options
+	metrics federatedStorageMetrics
+}
+
+// NewStorage returns a read only storage that fetches from a Prometheus
+// remote read endpoint, reads are clipped to the time range of the backend.
+func NewStorage(opts Options) storage.Storage {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if opts.Scope == nil {
+		opts.Scope = tally.NoopScope
+	}
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+	return &federatedStorage{
+		opts:    opts,
+		metrics: newFederatedStorageMetrics(opts.Scope),
+	}
+}
+
+// clip returns the query time range clipped to the range of the backend,
+// returning false if the ranges do not overlap.
+func (s *federatedStorage) clip(start, end time.Time) (time.Time, time.Time, bool) {
+	r := s.opts.Range
+	if r.MaxAge > 0 {
+		if earliest := s.opts.NowFn().Add(-r.MaxAge); start.Before(earliest) {
+			start = earliest
+		}
+	}
+	if r.Start != nil && start.Before(*r.Start) {
+		start = *r.Start
+	}
+	if r.End != nil && end.After(*r.End) {
+		end = *r.End
+	}
+	return start, end, start.Before(end)
+}
+
+func (s *federatedStorage) Fetch(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	start, end, ok := s.clip(query.Start, query.End)
+	if !ok {
+		s.metrics.fetchSkipped.Inc(1)
+		return &storage.FetchResult{SeriesList: ts.SeriesList{}}, nil
+	}
+	if query.Filter != nil {
+		return nil, errFilterUnsupported
+	}
+
+	fetchStart := time.Now()
+	result, err := s.fetch(ctx, query.TagMatchers, start, end)
+	s.metrics.fetchLatency.Record(time.Since(fetchStart))
+	if err != nil {
+		s.metrics.fetchErrors.Inc(1)
+		return nil, fmt.Errorf("remote read backend %s: %v", s.opts.Name, err)
+	}
+	s.metrics.fetchSuccess.Inc(1)
+
+	seriesList := make(ts.SeriesList, 0, len(result))
+	for _, series := range result {
+		tags := storage.PromLabelsToM3Tags(series.Labels)
+		datapoints := storage.PromSamplesToM3Datapoints(series.Samples)
+		seriesList = append(seriesList, ts.NewSeries(tags.ID(), datapoints, tags))
+	}
+
+	fetchResult := &storage.FetchResult{
+		SeriesList:    seriesList,
+		MatchedSeries: len(seriesList),
+	}
+	if options != nil && options.Limit > 0 && len(seriesList) > options.Limit {
+		fetchResult.SeriesList = seriesList[:options.Limit]
+		fetchResult.Truncated = true
+	}
+	return fetchResult, nil
+}
+
+func (s *federatedStorage) fetch(
+	ctx context.Context,
+	matchers models.Matchers,
+	start, end time.Time,
+) ([]*prompb.TimeSeries, error) {
+	promMatchers, err := storage.M3MatchersToProm(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(&prompb.ReadRequest{
+		Queries: []*prompb.Query{
+			{
+				StartTimestampMs: storage.TimeToTimestamp(start),
+				EndTimestampMs:   storage.TimeToTimestamp(end),
+				Matchers:         promMatchers,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.opts.URL,
+		bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
+	for name, value := range s.opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: maxErrorBodyBytes})
+		return nil, fmt.Errorf("unexpected status %d: %s",
+			resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	compressed, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	var readResp prompb.ReadResponse
+	if err := proto.Unmarshal(decoded, &readResp); err != nil {
+		return nil, err
+	}
+
+	var result []*prompb.TimeSeries
+	for _, queryResult := range readResp.Results {
+		result = append(result, queryResult.Timeseries...)
+	}
+	return result, nil
+}
+
+// FetchTags fetches the tags of the matching series from the series endpoint
+// of the backend so that their samples are not fetched, backends without a
+// series endpoint do not serve tag queries.
+func (s *federatedStorage) FetchTags(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.SearchResults, error) {
+	start, end, ok := s.clip(query.Start, query.End)
+	if !ok || s.opts.SeriesURL == "" {
+		s.metrics.fetchSkipped.Inc(1)
+		return &storage.SearchResults{}, nil
+	}
+	if query.Filter != nil {
+		return nil, errFilterUnsupported
+	}
+
+	fetchStart := time.Now()
+	result, err := s.fetchSeries(ctx, query.TagMatchers, start, end)
+	s.metrics.fetchLatency.Record(time.Since(fetchStart))
+	if err != nil {
+		s.metrics.fetchErrors.Inc(1)
+		return nil, fmt.Errorf("remote read backend %s: %v", s.opts.Name, err)
+	}
+	s.metrics.fetchSuccess.Inc(1)
+
+	metrics := make(models.Metrics, 0, len(result))
+	for _, labels := range result {
+		tags := models.Tags(labels)
+		metrics = append(metrics, &models.Metric{
+			ID:   tags.ID(),
+			Tags: tags,
+		})
+	}
+	if options != nil && options.Limit > 0 && len(metrics) > options.Limit {
+		metrics = metrics[:options.Limit]
+	}
+	return &storage.SearchResults{Metrics: metrics}, nil
+}
+
+type seriesResponse struct {
+	Status string              `json:"status"`
+	Data   []map[string]string `json:"data"`
+	Error  string              `json:"error"`
+}
+
+func (s *federatedStorage) fetchSeries(
+	ctx context.Context,
+	matchers models.Matchers,
+	start, end time.Time,
+) ([]map[string]string, error) {
+	selectors := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		selectors = append(selectors, matcher.String())
+	}
+
+	params := url.Values{}
+	params.Set("match[]", "{"+strings.Join(selectors, ",")+"}")
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	req, err := http.NewRequest(http.MethodGet,
+		s.opts.SeriesURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for name, value := range s.opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: maxErrorBodyBytes})
+		return nil, fmt.Errorf("unexpected status %d: %s",
+			resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var seriesResp seriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&seriesResp); err != nil {
+		return nil, err
+	}
+	if seriesResp.Status != "success" {
+		return nil, fmt.Errorf("unexpected series status %s: %s",
+			seriesResp.Status, seriesResp.Error)
+	}
+	return seriesResp.Data, nil
+}
+
+func (s *federatedStorage) FetchCount(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.CountResult, error) {
+	result, err := s.Fetch(ctx, query, options)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.CountResult{
+		Count:      result.MatchedSeries,
+		Exhaustive: true,
+	}, nil
+}
+
+func (s *federatedStorage) FetchBlocks(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (block.Result, error) {
+	result, err := s.Fetch(ctx, query, options)
+	if err != nil {
+		return block.Result{}, err
+	}
+	return storage.FetchResultToBlockResult(result, query, options)
+}
+
+func (s *federatedStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
+	return errReadOnly
+}
+
+func (s *federatedStorage) Type() storage.Type {
+	return storage.TypeRemoteDC
+}
+
+func (s *federatedStorage) Close() error {
+	return nil
+}
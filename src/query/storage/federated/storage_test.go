@@ -0,0 +1,204 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package federated
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackend(
+	t *testing.T,
+	requests *[]*prompb.ReadRequest,
+) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		compressed, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+
+		var req prompb.ReadRequest
+		require.NoError(t, proto.Unmarshal(data, &req))
+		*requests = append(*requests, &req)
+
+		query := req.Queries[0]
+		resp := &prompb.ReadResponse{
+			Results: []*prompb.QueryResult{
+				{
+					Timeseries: []*prompb.TimeSeries{
+						{
+							Labels: []*prompb.Label{{Name: "__name__", Value: "up"}},
+							Samples: []*prompb.Sample{
+								{Timestamp: query.StartTimestampMs, Value: 1},
+								{Timestamp: query.EndTimestampMs, Value: 2},
+							},
+						},
+					},
+				},
+			},
+		}
+		data, err = proto.Marshal(resp)
+		require.NoError(t, err)
+		w.Header().Set("Content-Encoding", "snappy")
+		_, err = w.Write(snappy.Encode(nil, data))
+		require.NoError(t, err)
+	}))
+}
+
+func newTestFetchQuery(t *testing.T, start, end time.Time) *storage.FetchQuery {
+	matcher, err := models.NewMatcher(models.MatchEqual, "__name__", "up")
+	require.NoError(t, err)
+	return &storage.FetchQuery{
+		TagMatchers: models.Matchers{matcher},
+		Start:       start,
+		End:         end,
+	}
+}
+
+func TestFederatedStorageFetchClipsToRange(t *testing.T) {
+	var requests []*prompb.ReadRequest
+	server := newTestBackend(t, &requests)
+	defer server.Close()
+
+	now := time.Unix(10000, 0)
+	end := now.Add(-time.Hour)
+	store := NewStorage(Options{
+		Name:    "prom",
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "secret"},
+		Range: TimeRangeConfiguration{
+			End:    &end,
+			MaxAge: 2 * time.Hour,
+		},
+		NowFn: func() time.Time { return now },
+	})
+
+	result, err := store.Fetch(context.Background(),
+		newTestFetchQuery(t, now.Add(-3*time.Hour), now), &storage.FetchOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	query := requests[0].Queries[0]
+	assert.Equal(t, storage.TimeToTimestamp(now.Add(-2*time.Hour)), query.StartTimestampMs)
+	assert.Equal(t, storage.TimeToTimestamp(end), query.EndTimestampMs)
+	require.Len(t, query.Matchers, 1)
+	assert.Equal(t, prompb.LabelMatcher_EQ, query.Matchers[0].Type)
+
+	require.Len(t, result.SeriesList, 1)
+	series := result.SeriesList[0]
+	assert.Equal(t, models.Tags{"__name__": "up"}, series.Tags)
+	require.Equal(t, 2, series.Len())
+	assert.Equal(t, 2.0, series.Values().ValueAt(1))
+	assert.False(t, result.LocalOnly)
+}
+
+func TestFederatedStorageFetchOutsideRangeSkipped(t *testing.T) {
+	var requests []*prompb.ReadRequest
+	server := newTestBackend(t, &requests)
+	defer server.Close()
+
+	now := time.Unix(10000, 0)
+	end := now.Add(-time.Hour)
+	store := NewStorage(Options{
+		Name:  "prom",
+		URL:   server.URL,
+		Range: TimeRangeConfiguration{End: &end},
+		NowFn: func() time.Time { return now },
+	})
+
+	result, err := store.Fetch(context.Background(),
+		newTestFetchQuery(t, now.Add(-30*time.Minute), now), &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.SeriesList, 0)
+	assert.Len(t, requests, 0)
+}
+
+func TestFederatedStorageFetchErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	store := NewStorage(Options{Name: "prom", URL: server.URL})
+	now := time.Now()
+	_, err := store.Fetch(context.Background(),
+		newTestFetchQuery(t, now.Add(-time.Hour), now), &storage.FetchOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unavailable")
+}
+
+func TestFederatedStorageFetchTagsUsesSeriesURL(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, err := w.Write([]byte(`{"status":"success","data":[{"__name__":"up","job":"prom"}]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Unix(10000, 0)
+	store := NewStorage(Options{
+		Name:      "prom",
+		URL:       server.URL + "/read",
+		SeriesURL: server.URL + "/series",
+		NowFn:     func() time.Time { return now },
+	})
+
+	result, err := store.FetchTags(context.Background(),
+		newTestFetchQuery(t, now.Add(-time.Hour), now), &storage.FetchOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/series", requests[0].URL.Path)
+	assert.Equal(t, `{__name__="up"}`, requests[0].URL.Query().Get("match[]"))
+	assert.Equal(t, "6400", requests[0].URL.Query().Get("start"))
+	assert.Equal(t, "10000", requests[0].URL.Query().Get("end"))
+
+	tags := models.Tags{"__name__": "up", "job": "prom"}
+	require.Len(t, result.Metrics, 1)
+	assert.Equal(t, tags, result.Metrics[0].Tags)
+	assert.Equal(t, tags.ID(), result.Metrics[0].ID)
+
+	// backends without a series endpoint do not serve tag queries.
+	store = NewStorage(Options{Name: "prom", URL: server.URL + "/read"})
+	result, err = store.FetchTags(context.Background(),
+		newTestFetchQuery(t, now.Add(-time.Hour), now), &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.Metrics, 0)
+	assert.Len(t, requests, 1)
+}
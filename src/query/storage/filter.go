@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// FilterOperator is the operator combining the sub filters of a filter.
+type FilterOperator string
+
+const (
+	// AndFilter matches series that match all of its sub filters.
+	AndFilter FilterOperator = "and"
+	// OrFilter matches series that match any of its sub filters.
+	OrFilter FilterOperator = "or"
+	// NotFilter matches series that do not match its single sub filter.
+	NotFilter FilterOperator = "not"
+)
+
+// Filter is a boolean combination of tag matchers, it is either a leaf with
+// a matcher and no operator or combines its sub filters with its operator.
+type Filter struct {
+	Operator FilterOperator  `json:"op,omitempty"`
+	Matcher  *models.Matcher `json:"matcher,omitempty"`
+	Filters  []*Filter       `json:"filters,omitempty"`
+}
+
+// Validate validates the filter and its sub filters.
+func (f *Filter) Validate() error {
+	if f.Operator == "" {
+		if f.Matcher == nil || len(f.Filters) != 0 {
+			return fmt.Errorf("filter without operator must have a matcher and no sub filters")
+		}
+		return nil
+	}
+
+	if f.Matcher != nil {
+		return fmt.Errorf("%s filter must not have a matcher", f.Operator)
+	}
+	switch f.Operator {
+	case AndFilter, OrFilter:
+		if len(f.Filters) == 0 {
+			return fmt.Errorf("%s filter must have at least one sub filter", f.Operator)
+		}
+	case NotFilter:
+		if len(f.Filters) != 1 {
+			return fmt.Errorf("not filter must have exactly one sub filter")
+		}
+	default:
+		return fmt.Errorf("unknown filter operator: %s", f.Operator)
+	}
+	for _, sub := range f.Filters {
+		if sub == nil {
+			return fmt.Errorf("%s filter has a nil sub filter", f.Operator)
+		}
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
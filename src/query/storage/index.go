@@ -94,10 +94,49 @@ func FetchQueryToM3Query(fetchQuery *FetchQuery) (index.Query, error) {
 		}
 	}
 
+	if fetchQuery.Filter != nil {
+		query, err := filterToQuery(fetchQuery.Filter)
+		if err != nil {
+			return index.Query{}, err
+		}
+		idxQueries = append(idxQueries, query)
+	}
+
 	q := idx.NewConjunctionQuery(idxQueries...)
 	return index.Query{Query: q}, nil
 }
 
+func filterToQuery(filter *Filter) (idx.Query, error) {
+	if err := filter.Validate(); err != nil {
+		return idx.Query{}, err
+	}
+	return validFilterToQuery(filter)
+}
+
+func validFilterToQuery(filter *Filter) (idx.Query, error) {
+	if filter.Matcher != nil {
+		return matcherToQuery(filter.Matcher)
+	}
+
+	queries := make([]idx.Query, 0, len(filter.Filters))
+	for _, sub := range filter.Filters {
+		query, err := validFilterToQuery(sub)
+		if err != nil {
+			return idx.Query{}, err
+		}
+		queries = append(queries, query)
+	}
+
+	switch filter.Operator {
+	case OrFilter:
+		return idx.NewDisjunctionQuery(queries...), nil
+	case NotFilter:
+		return idx.NewNegationQuery(queries[0]), nil
+	default:
+		return idx.NewConjunctionQuery(queries...), nil
+	}
+}
+
 func matcherToQuery(matcher *models.Matcher) (idx.Query, error) {
 	negate := false
 	switch matcher.Type {
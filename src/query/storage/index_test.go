@@ -136,3 +136,56 @@ func TestFetchQueryToM3Query(t *testing.T) {
 	}
 
 }
+
+func TestFetchQueryToM3QueryFilter(t *testing.T) {
+	matcher := func(t models.MatchType, name, value string) *Filter {
+		return &Filter{Matcher: &models.Matcher{Type: t, Name: name, Value: value}}
+	}
+
+	fetchQuery := &FetchQuery{
+		TagMatchers: models.Matchers{
+			{Type: models.MatchRegexp, Name: "name", Value: "foo.*"},
+		},
+		Filter: &Filter{
+			Operator: OrFilter,
+			Filters: []*Filter{
+				matcher(models.MatchEqual, "env", "prod"),
+				{
+					Operator: NotFilter,
+					Filters:  []*Filter{matcher(models.MatchEqual, "env", "staging")},
+				},
+			},
+		},
+	}
+
+	m3Query, err := FetchQueryToM3Query(fetchQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "conjunction(regexp(name, foo.*), "+
+		"disjunction(term(env, prod), negation(term(env, staging))))", m3Query.String())
+
+	fetchQuery.Filter = &Filter{
+		Operator: AndFilter,
+		Filters:  []*Filter{matcher(models.MatchNotEqual, "env", "staging")},
+	}
+	m3Query, err = FetchQueryToM3Query(fetchQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "conjunction(regexp(name, foo.*), negation(term(env, staging)))",
+		m3Query.String())
+}
+
+func TestFilterValidate(t *testing.T) {
+	leaf := &Filter{Matcher: &models.Matcher{Type: models.MatchEqual, Name: "t1", Value: "v1"}}
+	assert.NoError(t, leaf.Validate())
+
+	invalid := []*Filter{
+		{},
+		{Operator: AndFilter},
+		{Operator: OrFilter, Matcher: leaf.Matcher, Filters: []*Filter{leaf}},
+		{Operator: NotFilter, Filters: []*Filter{leaf, leaf}},
+		{Operator: "xor", Filters: []*Filter{leaf}},
+		{Operator: AndFilter, Filters: []*Filter{{Operator: NotFilter}}},
+	}
+	for _, filter := range invalid {
+		assert.Error(t, filter.Validate())
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingest
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultIdempotencyWindow is the default window that idempotency keys
+	// of applied write batches are remembered for.
+	DefaultIdempotencyWindow = 10 * time.Minute
+
+	// DefaultMaxIdempotencyKeys is the default max number of idempotency
+	// keys remembered.
+	DefaultMaxIdempotencyKeys = 1 << 20
+)
+
+// IdempotencyConfiguration is the configuration for deduplicating write
+// batches by their client generated idempotency keys.
+type IdempotencyConfiguration struct {
+	// Window is how long the keys of applied batches are remembered,
+	// defaults to DefaultIdempotencyWindow.
+	Window time.Duration `yaml:"window" validate:"min=0"`
+
+	// MaxKeys is the max number of keys remembered, the oldest key is
+	// forgotten when full, defaults to DefaultMaxIdempotencyKeys.
+	MaxKeys int `yaml:"maxKeys" validate:"min=0"`
+}
+
+// NewIdempotencyCache returns a new idempotency cache for the configuration.
+func (c IdempotencyConfiguration) NewIdempotencyCache() IdempotencyCache {
+	return NewIdempotencyCache(c.Window, c.MaxKeys, nil)
+}
+
+// IdempotencyCache remembers the client generated idempotency keys of
+// applied write batches for a dedup window, so that batches retried by at
+// least once producers after an ambiguous failure are not applied twice.
+// The writes of a batch that partially failed are remembered under its key
+// so that a retry only applies the writes that failed. Keys are remembered
+// in memory by each process so retries must reach the same process to be
+// deduplicated.
+type IdempotencyCache interface {
+	// Reserve reserves the key for a batch about to be applied and returns
+	// true with the writes of the batch applied by previous attempts, or
+	// returns false if the batch was applied within the dedup window. If a
+	// batch with the key is being applied it waits for that batch to be
+	// recorded or released, or for the context to be done.
+	Reserve(ctx context.Context, key string) (AppliedWrites, bool, error)
+
+	// Record records the key of a batch that was applied, releasing the
+	// reservation of the key.
+	Record(key string)
+
+	// Release releases the reservation of the key of a batch that was not
+	// fully applied, remembering the writes that were applied so that a
+	// retry of the batch only applies the writes that were not.
+	Release(key string, applied AppliedWrites)
+}
+
+// AppliedWrite identifies a write of a series of a batch.
+type AppliedWrite struct {
+	// Series is the index of the series in the batch.
+	Series int
+	// Aggregated is true for the write of the series to the downsampler.
+	Aggregated bool
+}
+
+// AppliedWrites is the set of writes of a batch that were applied.
+type AppliedWrites map[AppliedWrite]struct{}
+
+// Contains returns whether the write was applied.
+func (a AppliedWrites) Contains(w AppliedWrite) bool {
+	_, ok := a[w]
+	return ok
+}
+
+type idempotencyKey struct {
+	key        string
+	recordedAt time.Time
+	// applied are the writes of a batch that partially failed, nil once the
+	// batch was applied.
+	applied AppliedWrites
+}
+
+type idempotencyCache struct {
+	sync.Mutex
+
+	window   time.Duration
+	maxKeys  int
+	nowFn    func() time.Time
+	keys     map[string]*list.Element
+	order    *list.List
+	inFlight map[string]chan struct{}
+}
+
+// NewIdempotencyCache returns a new idempotency cache that remembers keys
+// for the window and at most maxKeys keys, forgetting the oldest key when
+// full.
+func NewIdempotencyCache(
+	window time.Duration,
+	maxKeys int,
+	nowFn func() time.Time,
+) IdempotencyCache {
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+	if maxKeys <= 0 {
+		maxKeys = DefaultMaxIdempotencyKeys
+	}
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+	return &idempotencyCache{
+		window:   window,
+		maxKeys:  maxKeys,
+		nowFn:    nowFn,
+		keys:     make(map[string]*list.Element),
+		order:    list.New(),
+		inFlight: make(map[string]chan struct{}),
+	}
+}
+
+func (c *idempotencyCache) Reserve(
+	ctx context.Context,
+	key string,
+) (AppliedWrites, bool, error) {
+	for {
+		now := c.nowFn()
+
+		c.Lock()
+		c.expireWithLock(now)
+		doneCh, ok := c.inFlight[key]
+		if !ok {
+			var applied AppliedWrites
+			if elem, ok := c.keys[key]; ok {
+				entry := elem.Value.(idempotencyKey)
+				if entry.applied == nil {
+					c.Unlock()
+					return nil, false, nil
+				}
+				applied = make(AppliedWrites, len(entry.applied))
+				for w := range entry.applied {
+					applied[w] = struct{}{}
+				}
+			}
+			c.inFlight[key] = make(chan struct{})
+			c.Unlock()
+			return applied, true, nil
+		}
+		c.Unlock()
+
+		// NB: wait for the batch in flight, once it is recorded the key is
+		// seen and once it is released the key can be reserved again.
+		select {
+		case <-doneCh:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+func (c *idempotencyCache) Record(key string) {
+	now := c.nowFn()
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.releaseWithLock(key)
+	c.expireWithLock(now)
+	if elem, ok := c.keys[key]; ok {
+		if elem.Value.(idempotencyKey).applied == nil {
+			return
+		}
+		c.removeWithLock(elem)
+	}
+	c.pushWithLock(idempotencyKey{key: key, recordedAt: now})
+}
+
+func (c *idempotencyCache) Release(key string, applied AppliedWrites) {
+	now := c.nowFn()
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.releaseWithLock(key)
+	c.expireWithLock(now)
+	if len(applied) == 0 {
+		return
+	}
+	merged := make(AppliedWrites, len(applied))
+	if elem, ok := c.keys[key]; ok {
+		entry := elem.Value.(idempotencyKey)
+		if entry.applied == nil {
+			// Recorded as applied by another attempt.
+			return
+		}
+		for w := range entry.applied {
+			merged[w] = struct{}{}
+		}
+		c.removeWithLock(elem)
+	}
+	for w := range applied {
+		merged[w] = struct{}{}
+	}
+	c.pushWithLock(idempotencyKey{key: key, recordedAt: now, applied: merged})
+}
+
+func (c *idempotencyCache) pushWithLock(entry idempotencyKey) {
+	if c.order.Len() >= c.maxKeys {
+		c.removeWithLock(c.order.Front())
+	}
+	c.keys[entry.key] = c.order.PushBack(entry)
+}
+
+func (c *idempotencyCache) releaseWithLock(key string) {
+	if doneCh, ok := c.inFlight[key]; ok {
+		delete(c.inFlight, key)
+		close(doneCh)
+	}
+}
+
+// expireWithLock forgets the keys recorded before the dedup window, keys are
+// kept in the order they were recorded so only the oldest are checked.
+func (c *idempotencyCache) expireWithLock(now time.Time) {
+	cutoff := now.Add(-c.window)
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		if elem.Value.(idempotencyKey).recordedAt.After(cutoff) {
+			return
+		}
+		c.removeWithLock(elem)
+	}
+}
+
+func (c *idempotencyCache) removeWithLock(elem *list.Element) {
+	delete(c.keys, elem.Value.(idempotencyKey).key)
+	c.order.Remove(elem)
+}
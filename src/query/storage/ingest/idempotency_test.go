@@ -0,0 +1,161 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyCacheReserveWithinWindow(t *testing.T) {
+	var (
+		ctx = context.Background()
+		now = time.Now()
+	)
+	cache := NewIdempotencyCache(time.Minute, 0, func() time.Time { return now })
+
+	requireReserve(t, cache, "a", true)
+	cache.Record("a")
+	requireReserve(t, cache, "a", false)
+	requireReserve(t, cache, "b", true)
+
+	now = now.Add(30 * time.Second)
+	cache.Record("b")
+	requireReserve(t, cache, "a", false)
+
+	now = now.Add(30 * time.Second)
+	requireReserve(t, cache, "a", true)
+	requireReserve(t, cache, "b", false)
+
+	now = now.Add(30 * time.Second)
+	applied, reserved, err := cache.Reserve(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Empty(t, applied)
+}
+
+func TestIdempotencyCacheForgetsOldestWhenFull(t *testing.T) {
+	now := time.Now()
+	cache := NewIdempotencyCache(time.Minute, 2, func() time.Time { return now })
+
+	cache.Record("a")
+	cache.Record("b")
+	cache.Record("a")
+	cache.Record("c")
+
+	requireReserve(t, cache, "b", false)
+	requireReserve(t, cache, "c", false)
+	requireReserve(t, cache, "a", true)
+}
+
+func TestIdempotencyCacheReserveWaitsForBatchInFlight(t *testing.T) {
+	cache := NewIdempotencyCache(time.Minute, 0, nil)
+	_, reserved, err := cache.Reserve(context.Background(), "a")
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// A concurrent retry waits for the batch in flight to be recorded.
+	resultCh := make(chan bool)
+	go func() {
+		_, reserved, err := cache.Reserve(context.Background(), "a")
+		assert.NoError(t, err)
+		resultCh <- reserved
+	}()
+	select {
+	case <-resultCh:
+		require.FailNow(t, "reserved key of batch in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cache.Record("a")
+	require.False(t, <-resultCh)
+
+	// A concurrent retry reserves the key once the batch in flight failed.
+	_, reserved, err = cache.Reserve(context.Background(), "b")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	go func() {
+		_, reserved, err := cache.Reserve(context.Background(), "b")
+		assert.NoError(t, err)
+		resultCh <- reserved
+	}()
+	cache.Release("b", nil)
+	require.True(t, <-resultCh)
+
+	// Waiting is abandoned once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = cache.Reserve(ctx, "b")
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestIdempotencyCacheRemembersAppliedWritesOfPartialFailure(t *testing.T) {
+	var (
+		ctx = context.Background()
+		now = time.Now()
+	)
+	cache := NewIdempotencyCache(time.Minute, 0, func() time.Time { return now })
+
+	_, reserved, err := cache.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	cache.Release("a", AppliedWrites{{Series: 0}: {}, {Series: 1, Aggregated: true}: {}})
+
+	// The retry only applies the writes that failed.
+	applied, reserved, err := cache.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Equal(t, AppliedWrites{{Series: 0}: {}, {Series: 1, Aggregated: true}: {}}, applied)
+	cache.Release("a", AppliedWrites{{Series: 1}: {}})
+
+	applied, reserved, err = cache.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.True(t, applied.Contains(AppliedWrite{Series: 0}))
+	require.True(t, applied.Contains(AppliedWrite{Series: 1}))
+	require.True(t, applied.Contains(AppliedWrite{Series: 1, Aggregated: true}))
+	require.False(t, applied.Contains(AppliedWrite{Series: 0, Aggregated: true}))
+	cache.Record("a")
+	requireReserve(t, cache, "a", false)
+
+	// The applied writes are forgotten after the window.
+	_, reserved, err = cache.Reserve(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	cache.Release("b", AppliedWrites{{Series: 0}: {}})
+	now = now.Add(2 * time.Minute)
+	applied, reserved, err = cache.Reserve(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Empty(t, applied)
+}
+
+func requireReserve(t *testing.T, cache IdempotencyCache, key string, expected bool) {
+	_, reserved, err := cache.Reserve(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, expected, reserved, key)
+	if reserved {
+		cache.Release(key, nil)
+	}
+}
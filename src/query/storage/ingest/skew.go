@@ -0,0 +1,157 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxWriters is the default max number of writers tracked.
+	DefaultMaxWriters = 1024
+)
+
+// WriterSkew is the clock skew of a writer, measured as the difference
+// between the latest datapoint timestamp of each of its requests and the
+// time the request was received, positive values are ahead of the clock.
+type WriterSkew struct {
+	Writer      string    `json:"writer"`
+	Requests    int64     `json:"requests"`
+	LastSeen    time.Time `json:"lastSeen"`
+	LastSkewSec float64   `json:"lastSkewSec"`
+	MinSkewSec  float64   `json:"minSkewSec"`
+	MaxSkewSec  float64   `json:"maxSkewSec"`
+}
+
+// SkewTracker tracks the clock skew of each writer.
+type SkewTracker interface {
+	// Record records the latest datapoint timestamp of a request of a writer.
+	Record(writer string, latest time.Time)
+
+	// Report returns the skew of each writer, in descending order of the
+	// largest absolute skew.
+	Report() []WriterSkew
+}
+
+type writerSkew struct {
+	requests int64
+	lastSeen time.Time
+	last     time.Duration
+	min      time.Duration
+	max      time.Duration
+}
+
+type skewTracker struct {
+	sync.Mutex
+
+	maxWriters int
+	nowFn      func() time.Time
+	writers    map[string]*writerSkew
+}
+
+// NewSkewTracker returns a new clock skew tracker that tracks at most
+// maxWriters writers, evicting the least recently seen writer when full.
+func NewSkewTracker(maxWriters int, nowFn func() time.Time) SkewTracker {
+	if maxWriters <= 0 {
+		maxWriters = DefaultMaxWriters
+	}
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+	return &skewTracker{
+		maxWriters: maxWriters,
+		nowFn:      nowFn,
+		writers:    make(map[string]*writerSkew),
+	}
+}
+
+func (t *skewTracker) Record(writer string, latest time.Time) {
+	now := t.nowFn()
+	skew := latest.Sub(now)
+
+	t.Lock()
+	defer t.Unlock()
+
+	w, ok := t.writers[writer]
+	if !ok {
+		if len(t.writers) >= t.maxWriters {
+			t.evictWithLock()
+		}
+		w = &writerSkew{min: skew, max: skew}
+		t.writers[writer] = w
+	}
+	w.requests++
+	w.lastSeen = now
+	w.last = skew
+	if skew < w.min {
+		w.min = skew
+	}
+	if skew > w.max {
+		w.max = skew
+	}
+}
+
+func (t *skewTracker) evictWithLock() {
+	var (
+		oldest     string
+		oldestSeen time.Time
+	)
+	for writer, w := range t.writers {
+		if oldest == "" || w.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = writer, w.lastSeen
+		}
+	}
+	delete(t.writers, oldest)
+}
+
+func (t *skewTracker) Report() []WriterSkew {
+	t.Lock()
+	results := make([]WriterSkew, 0, len(t.writers))
+	for writer, w := range t.writers {
+		results = append(results, WriterSkew{
+			Writer:      writer,
+			Requests:    w.requests,
+			LastSeen:    w.lastSeen,
+			LastSkewSec: w.last.Seconds(),
+			MinSkewSec:  w.min.Seconds(),
+			MaxSkewSec:  w.max.Seconds(),
+		})
+	}
+	t.Unlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := maxAbs(results[i]), maxAbs(results[j])
+		if a == b {
+			return results[i].Writer < results[j].Writer
+		}
+		return a > b
+	})
+	return results
+}
+
+func maxAbs(s WriterSkew) float64 {
+	if -s.MinSkewSec > s.MaxSkewSec {
+		return -s.MinSkewSec
+	}
+	return s.MaxSkewSec
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkewTrackerReport(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tr := NewSkewTracker(2, func() time.Time { return now })
+
+	tr.Record("a", now.Add(-10*time.Second))
+	tr.Record("a", now.Add(5*time.Second))
+	now = now.Add(time.Second)
+	tr.Record("b", now.Add(-time.Minute))
+
+	report := tr.Report()
+	require.Len(t, report, 2)
+	require.Equal(t, "b", report[0].Writer)
+	require.Equal(t, -60.0, report[0].MinSkewSec)
+	require.Equal(t, "a", report[1].Writer)
+	require.Equal(t, int64(2), report[1].Requests)
+	require.Equal(t, 5.0, report[1].LastSkewSec)
+	require.Equal(t, -10.0, report[1].MinSkewSec)
+	require.Equal(t, 5.0, report[1].MaxSkewSec)
+
+	// Evicts the least recently seen writer.
+	now = now.Add(time.Second)
+	tr.Record("c", now)
+	report = tr.Report()
+	require.Len(t, report, 2)
+	require.Equal(t, "b", report[0].Writer)
+	require.Equal(t, "c", report[1].Writer)
+}
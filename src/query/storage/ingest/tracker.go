@@ -0,0 +1,299 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package ingest tracks approximate per metric name ingest statistics over
+// a sliding window, using count-min sketches so that memory is bounded
+// regardless of the number of distinct metric names ingested.
+package ingest
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWindow is the default sliding window statistics are tracked over.
+	DefaultWindow = time.Minute
+
+	// DefaultBuckets is the default number of buckets the window is split into.
+	DefaultBuckets = 6
+
+	// DefaultSketchWidth is the default number of counters per sketch row.
+	DefaultSketchWidth = 2048
+
+	// DefaultSketchDepth is the default number of sketch rows.
+	DefaultSketchDepth = 4
+
+	// DefaultMaxCandidates is the default max number of heavy hitter
+	// candidates tracked per bucket.
+	DefaultMaxCandidates = 256
+)
+
+// MetricStats are the ingest statistics of a metric name.
+type MetricStats struct {
+	Name       string  `json:"name"`
+	Datapoints int64   `json:"datapoints"`
+	Rate       float64 `json:"rate"`
+}
+
+// Tracker tracks approximate per metric name ingest statistics.
+type Tracker interface {
+	// Record records datapoints ingested for a metric name.
+	Record(name string, datapoints int)
+
+	// Top returns the statistics of the k metric names with the most
+	// datapoints ingested within the window, in descending order.
+	Top(k int) []MetricStats
+
+	// Window returns the sliding window statistics are tracked over.
+	Window() time.Duration
+}
+
+// TrackerOptions are the options for a tracker, zero values use defaults.
+type TrackerOptions struct {
+	Window        time.Duration
+	Buckets       int
+	SketchWidth   int
+	SketchDepth   int
+	MaxCandidates int
+	NowFn         func() time.Time
+}
+
+type bucket struct {
+	epoch      int64
+	sketch     []int64
+	candidates map[string]int64
+	minName    string
+	minCount   int64
+}
+
+type tracker struct {
+	sync.Mutex
+
+	window        time.Duration
+	bucketSize    time.Duration
+	width         int
+	depth         int
+	maxCandidates int
+	nowFn         func() time.Time
+	buckets       []bucket
+}
+
+// NewTracker returns a new ingest statistics tracker.
+func NewTracker(opts TrackerOptions) Tracker {
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.Buckets <= 0 {
+		opts.Buckets = DefaultBuckets
+	}
+	if opts.SketchWidth <= 0 {
+		opts.SketchWidth = DefaultSketchWidth
+	}
+	if opts.SketchDepth <= 0 {
+		opts.SketchDepth = DefaultSketchDepth
+	}
+	if opts.MaxCandidates <= 0 {
+		opts.MaxCandidates = DefaultMaxCandidates
+	}
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	bucketSize := opts.Window / time.Duration(opts.Buckets)
+	if bucketSize <= 0 {
+		bucketSize = opts.Window
+	}
+	buckets := make([]bucket, opts.Buckets)
+	for i := range buckets {
+		buckets[i] = bucket{
+			epoch:      -1,
+			sketch:     make([]int64, opts.SketchWidth*opts.SketchDepth),
+			candidates: make(map[string]int64, opts.MaxCandidates),
+		}
+	}
+	return &tracker{
+		window:        opts.Window,
+		bucketSize:    bucketSize,
+		width:         opts.SketchWidth,
+		depth:         opts.SketchDepth,
+		maxCandidates: opts.MaxCandidates,
+		nowFn:         opts.NowFn,
+		buckets:       buckets,
+	}
+}
+
+func (t *tracker) Window() time.Duration {
+	return t.window
+}
+
+func (t *tracker) Record(name string, datapoints int) {
+	if datapoints <= 0 {
+		return
+	}
+
+	h1, h2 := hashes(name)
+	epoch := t.nowFn().UnixNano() / int64(t.bucketSize)
+
+	t.Lock()
+	defer t.Unlock()
+
+	b := &t.buckets[int(epoch%int64(len(t.buckets)))]
+	if b.epoch != epoch {
+		b.reset(epoch)
+	}
+
+	var estimate int64
+	for row := 0; row < t.depth; row++ {
+		idx := t.index(row, h1, h2)
+		b.sketch[idx] += int64(datapoints)
+		if row == 0 || b.sketch[idx] < estimate {
+			estimate = b.sketch[idx]
+		}
+	}
+	b.observe(name, estimate, t.maxCandidates)
+}
+
+func (t *tracker) Top(k int) []MetricStats {
+	if k <= 0 {
+		return nil
+	}
+
+	epoch := t.nowFn().UnixNano() / int64(t.bucketSize)
+	oldest := epoch - int64(len(t.buckets)) + 1
+
+	t.Lock()
+	var live []*bucket
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		if b.epoch >= oldest && b.epoch <= epoch {
+			live = append(live, b)
+		}
+	}
+
+	// Candidates are only heavy hitters within a single bucket, so the
+	// estimate for each is summed across all live buckets.
+	counts := make(map[string]int64)
+	for _, b := range live {
+		for name := range b.candidates {
+			if _, ok := counts[name]; ok {
+				continue
+			}
+			h1, h2 := hashes(name)
+			var total int64
+			for _, other := range live {
+				total += t.estimate(other, h1, h2)
+			}
+			counts[name] = total
+		}
+	}
+	t.Unlock()
+
+	results := make([]MetricStats, 0, len(counts))
+	seconds := t.window.Seconds()
+	for name, count := range counts {
+		results = append(results, MetricStats{
+			Name:       name,
+			Datapoints: count,
+			Rate:       float64(count) / seconds,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Datapoints == results[j].Datapoints {
+			return results[i].Name < results[j].Name
+		}
+		return results[i].Datapoints > results[j].Datapoints
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+func (t *tracker) index(row int, h1, h2 uint32) int {
+	return row*t.width + int((h1+uint32(row)*h2)%uint32(t.width))
+}
+
+func (t *tracker) estimate(b *bucket, h1, h2 uint32) int64 {
+	var estimate int64
+	for row := 0; row < t.depth; row++ {
+		v := b.sketch[t.index(row, h1, h2)]
+		if row == 0 || v < estimate {
+			estimate = v
+		}
+	}
+	return estimate
+}
+
+func (b *bucket) reset(epoch int64) {
+	b.epoch = epoch
+	for i := range b.sketch {
+		b.sketch[i] = 0
+	}
+	for name := range b.candidates {
+		delete(b.candidates, name)
+	}
+	b.minName = ""
+	b.minCount = 0
+}
+
+// observe tracks the name as a heavy hitter candidate of the bucket if it
+// is already tracked, there is room, or it outranks the least candidate.
+func (b *bucket) observe(name string, estimate int64, maxCandidates int) {
+	if _, ok := b.candidates[name]; ok {
+		b.candidates[name] = estimate
+		if name == b.minName {
+			b.updateMin()
+		}
+		return
+	}
+	if len(b.candidates) < maxCandidates {
+		b.candidates[name] = estimate
+		if b.minName == "" || estimate < b.minCount {
+			b.minName, b.minCount = name, estimate
+		}
+		return
+	}
+	if estimate <= b.minCount {
+		return
+	}
+	delete(b.candidates, b.minName)
+	b.candidates[name] = estimate
+	b.updateMin()
+}
+
+func (b *bucket) updateMin() {
+	b.minName, b.minCount = "", 0
+	for name, count := range b.candidates {
+		if b.minName == "" || count < b.minCount {
+			b.minName, b.minCount = name, count
+		}
+	}
+}
+
+// hashes returns the two hashes used to derive the sketch row indexes.
+func hashes(name string) (uint32, uint32) {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	sum := h.Sum64()
+	return uint32(sum), uint32(sum>>32) | 1
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ingest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerTop(t *testing.T) {
+	now := time.Unix(0, 0)
+	tr := NewTracker(TrackerOptions{
+		Window:        time.Minute,
+		Buckets:       6,
+		MaxCandidates: 4,
+		NowFn:         func() time.Time { return now },
+	})
+
+	for i := 0; i < 20; i++ {
+		tr.Record(fmt.Sprintf("quiet_%d", i), 1)
+	}
+	tr.Record("flood", 500)
+	now = now.Add(10 * time.Second)
+	tr.Record("flood", 500)
+	tr.Record("busy", 300)
+
+	top := tr.Top(2)
+	require.Len(t, top, 2)
+	assert.Equal(t, MetricStats{Name: "flood", Datapoints: 1000, Rate: 1000.0 / 60}, top[0])
+	assert.Equal(t, "busy", top[1].Name)
+	assert.Equal(t, int64(300), top[1].Datapoints)
+
+	// Once the window has passed the first bucket is no longer counted.
+	now = now.Add(55 * time.Second)
+	top = tr.Top(1)
+	require.Len(t, top, 1)
+	assert.Equal(t, "flood", top[0].Name)
+	assert.Equal(t, int64(500), top[0].Datapoints)
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, tr.Top(1))
+}
+
+func TestTrackerEvictsLeastCandidate(t *testing.T) {
+	now := time.Unix(0, 0)
+	tr := NewTracker(TrackerOptions{
+		MaxCandidates: 2,
+		NowFn:         func() time.Time { return now },
+	})
+
+	tr.Record("a", 1)
+	tr.Record("b", 5)
+	tr.Record("c", 3)
+	tr.Record("d", 1)
+
+	top := tr.Top(10)
+	require.Len(t, top, 2)
+	assert.Equal(t, "b", top[0].Name)
+	assert.Equal(t, "c", top[1].Name)
+}
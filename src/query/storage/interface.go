@@ -70,6 +70,11 @@ type FetchQuery struct {
 	Start       time.Time       `json:"start"`
 	End         time.Time       `json:"end"`
 	Interval    time.Duration   `json:"interval"`
+	// Filter is an optional combination of tag matchers using AND, OR and NOT
+	// that series must match in addition to the tag matchers, set by the
+	// "filter" field of JSON read and search requests. It is only supported
+	// when fetching from M3DB, other storages return an error.
+	Filter *Filter `json:"filter,omitempty"`
 }
 
 func (q *FetchQuery) String() string {
@@ -80,6 +85,13 @@ func (q *FetchQuery) String() string {
 type FetchOptions struct {
 	Limit    int
 	KillChan chan struct{}
+	// SampleOnLimit returns a deterministic sample of Limit series, rather
+	// than the first Limit series found, when more series match the query.
+	SampleOnLimit bool
+	// LookbackDuration is how far back a step looks for the most recent
+	// datapoint when fetching blocks, datapoints are carried forward
+	// indefinitely if zero.
+	LookbackDuration time.Duration
 }
 
 // Querier handles queries against a storage.
@@ -91,6 +103,10 @@ type Querier interface {
 		ctx context.Context, query *FetchQuery, options *FetchOptions) (*SearchResults, error)
 	FetchBlocks(
 		ctx context.Context, query *FetchQuery, options *FetchOptions) (block.Result, error)
+	// FetchCount returns the number of series matching a query without
+	// fetching their IDs or tags
+	FetchCount(
+		ctx context.Context, query *FetchQuery, options *FetchOptions) (*CountResult, error)
 }
 
 // WriteQuery represents the input timeseries that is written to M3DB
@@ -101,6 +117,9 @@ type WriteQuery struct {
 	Unit       xtime.Unit
 	Annotation []byte
 	Attributes Attributes
+	// ID is the caller provided series ID, only used by namespaces
+	// configured with the caller ID scheme.
+	ID string
 }
 
 func (q *WriteQuery) String() string {
@@ -118,11 +137,23 @@ type SearchResults struct {
 	Metrics models.Metrics
 }
 
+// CountResult is the number of series matching a query
+type CountResult struct {
+	Count int
+	// Exhaustive is false if the count was truncated by a limit
+	Exhaustive bool
+}
+
 // FetchResult provides a fetch result and meta information
 type FetchResult struct {
 	SeriesList ts.SeriesList // The aggregated list of results across all underlying storage calls
 	LocalOnly  bool
 	HasNext    bool
+	// Truncated is true if the series list was limited or sampled.
+	Truncated bool
+	// MatchedSeries is the number of series that matched the query, which
+	// is larger than the series list when the result was sampled.
+	MatchedSeries int
 }
 
 // QueryResult is the result from a query
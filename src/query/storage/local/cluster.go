@@ -68,6 +68,7 @@ type ClusterNamespace interface {
 	NamespaceID() ident.ID
 	Attributes() storage.Attributes
 	Session() client.Session
+	IDGenerator() IDGenerator
 }
 
 // ClusterNamespaces is a slice of ClusterNamespace instances.
@@ -91,6 +92,8 @@ type UnaggregatedClusterNamespaceDefinition struct {
 	NamespaceID ident.ID
 	Session     client.Session
 	Retention   time.Duration
+	// IDGenerator generates series IDs on write, defaults to the legacy scheme.
+	IDGenerator IDGenerator
 }
 
 // Validate will validate the cluster namespace definition.
@@ -115,6 +118,8 @@ type AggregatedClusterNamespaceDefinition struct {
 	Session     client.Session
 	Retention   time.Duration
 	Resolution  time.Duration
+	// IDGenerator generates series IDs on write, defaults to the legacy scheme.
+	IDGenerator IDGenerator
 }
 
 // Validate validates the cluster namespace definition.
@@ -243,6 +248,7 @@ type clusterNamespace struct {
 	namespaceID ident.ID
 	attributes  storage.Attributes
 	session     client.Session
+	idGenerator IDGenerator
 }
 
 func newUnaggregatedClusterNamespace(
@@ -257,7 +263,8 @@ func newUnaggregatedClusterNamespace(
 			MetricsType: storage.UnaggregatedMetricsType,
 			Retention:   def.Retention,
 		},
-		session: def.Session,
+		session:     def.Session,
+		idGenerator: idGeneratorOrDefault(def.IDGenerator),
 	}, nil
 }
 
@@ -274,7 +281,8 @@ func newAggregatedClusterNamespace(
 			Retention:   def.Retention,
 			Resolution:  def.Resolution,
 		},
-		session: def.Session,
+		session:     def.Session,
+		idGenerator: idGeneratorOrDefault(def.IDGenerator),
 	}, nil
 }
 
@@ -290,6 +298,17 @@ func (n *clusterNamespace) Session() client.Session {
 	return n.session
 }
 
+func (n *clusterNamespace) IDGenerator() IDGenerator {
+	return n.idGenerator
+}
+
+func idGeneratorOrDefault(g IDGenerator) IDGenerator {
+	if g == nil {
+		return legacyIDGenerator{}
+	}
+	return g
+}
+
 type syncMultiErrs struct {
 	sync.Mutex
 	multiErr xerrors.MultiError
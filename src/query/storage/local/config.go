@@ -29,6 +29,8 @@ import (
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/stores/m3db"
 	"github.com/m3db/m3x/ident"
+
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -70,6 +72,16 @@ type ClusterStaticNamespaceConfiguration struct {
 	StorageMetricsType storage.MetricsType `yaml:"storageMetricsType"`
 	Retention          time.Duration       `yaml:"retention" validate:"nonzero"`
 	Resolution         time.Duration       `yaml:"resolution" validate:"min=0"`
+	IDScheme           IDSchemeType        `yaml:"idScheme"`
+}
+
+func (c ClusterStaticNamespaceConfiguration) newIDGenerator(
+	scope tally.Scope,
+) (IDGenerator, error) {
+	if scope != nil {
+		scope = scope.Tagged(map[string]string{"namespace": c.Namespace})
+	}
+	return NewIDGenerator(c.IDScheme, scope)
 }
 
 type unaggregatedClusterNamespaceConfiguration struct {
@@ -93,6 +105,7 @@ type clusterConnectResult struct {
 // constructing clusters from config.
 type ClustersStaticConfigurationOptions struct {
 	AsyncSessions bool
+	MetricsScope  tally.Scope
 }
 
 // NewClusters instantiates a new Clusters instance.
@@ -189,10 +202,16 @@ func (c ClustersStaticConfiguration) NewClusters(
 			unaggregatedClusterNamespaceCfg.result.err)
 	}
 
+	idGenerator, err := unaggregatedClusterNamespaceCfg.namespace.newIDGenerator(opts.MetricsScope)
+	if err != nil {
+		return nil, err
+	}
+
 	unaggregatedClusterNamespace = UnaggregatedClusterNamespaceDefinition{
 		NamespaceID: ident.StringID(unaggregatedClusterNamespaceCfg.namespace.Namespace),
 		Session:     unaggregatedClusterNamespaceCfg.result.session,
 		Retention:   unaggregatedClusterNamespaceCfg.namespace.Retention,
+		IDGenerator: idGenerator,
 	}
 
 	for i, cfg := range aggregatedClusterNamespacesCfgs {
@@ -202,11 +221,17 @@ func (c ClustersStaticConfiguration) NewClusters(
 		}
 
 		for _, n := range cfg.namespaces {
+			idGenerator, err := n.newIDGenerator(opts.MetricsScope)
+			if err != nil {
+				return nil, err
+			}
+
 			def := AggregatedClusterNamespaceDefinition{
 				NamespaceID: ident.StringID(n.Namespace),
 				Session:     cfg.result.session,
 				Retention:   n.Retention,
 				Resolution:  n.Resolution,
+				IDGenerator: idGenerator,
 			}
 			aggregatedClusterNamespaces = append(aggregatedClusterNamespaces, def)
 		}
@@ -0,0 +1,214 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package local
+
+import (
+	"container/list"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/cespare/xxhash"
+	"github.com/spaolacci/murmur3"
+	"github.com/uber-go/tally"
+)
+
+const (
+	// defaultHashCollisionTrackingSize is the number of recently generated
+	// hash IDs remembered to detect collisions, the least recently generated
+	// ID is forgotten once full.
+	defaultHashCollisionTrackingSize = 1 << 16
+)
+
+var (
+	errCallerIDNotSet = errors.New("write query ID not set for caller ID scheme")
+
+	validIDSchemeTypes = []IDSchemeType{
+		LegacyIDSchemeType,
+		HashIDSchemeType,
+		CallerIDSchemeType,
+	}
+)
+
+// IDSchemeType describes how series IDs are generated from tags on write.
+type IDSchemeType uint
+
+const (
+	// LegacyIDSchemeType concatenates the sorted tag names and values.
+	LegacyIDSchemeType IDSchemeType = iota
+	// HashIDSchemeType uses a fixed length hash of the legacy ID, falling
+	// back to the legacy ID for series whose hash collides with the hash of
+	// a recently written series.
+	HashIDSchemeType
+	// CallerIDSchemeType uses the ID provided with the write query.
+	CallerIDSchemeType
+)
+
+func (t IDSchemeType) String() string {
+	switch t {
+	case LegacyIDSchemeType:
+		return "legacy"
+	case HashIDSchemeType:
+		return "hash"
+	case CallerIDSchemeType:
+		return "caller"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals an ID scheme type.
+func (t *IDSchemeType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*t = LegacyIDSchemeType
+		return nil
+	}
+	for _, valid := range validIDSchemeTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid IDSchemeType '%s' valid types are: %v",
+		str, validIDSchemeTypes)
+}
+
+// IDGenerator generates the series ID for a write query.
+type IDGenerator interface {
+	// Generate returns the series ID for the write query.
+	Generate(query *storage.WriteQuery) (string, error)
+}
+
+// NewIDGenerator returns a new ID generator for the given scheme.
+func NewIDGenerator(t IDSchemeType, scope tally.Scope) (IDGenerator, error) {
+	switch t {
+	case LegacyIDSchemeType:
+		return legacyIDGenerator{}, nil
+	case HashIDSchemeType:
+		if scope == nil {
+			scope = tally.NoopScope
+		}
+		return newHashIDGenerator(defaultHashCollisionTrackingSize, scope), nil
+	case CallerIDSchemeType:
+		return callerIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID scheme type: %v", t)
+	}
+}
+
+type legacyIDGenerator struct{}
+
+func (legacyIDGenerator) Generate(query *storage.WriteQuery) (string, error) {
+	return query.Tags.ID(), nil
+}
+
+type callerIDGenerator struct{}
+
+func (callerIDGenerator) Generate(query *storage.WriteQuery) (string, error) {
+	if query.ID == "" {
+		return "", errCallerIDNotSet
+	}
+	return query.ID, nil
+}
+
+type hashIDEntry struct {
+	id string
+	// fingerprint is the fingerprint of the legacy ID the hash ID was
+	// generated from.
+	fingerprint uint64
+}
+
+type hashIDGenerator struct {
+	sync.Mutex
+
+	trackingSize int
+	// seen maps recently generated hash IDs to their entry in order, which
+	// is ordered from the most to the least recently generated.
+	seen       map[string]*list.Element
+	order      *list.List
+	generated  tally.Counter
+	collisions tally.Counter
+}
+
+func newHashIDGenerator(trackingSize int, scope tally.Scope) *hashIDGenerator {
+	scope = scope.SubScope("id-scheme").Tagged(map[string]string{
+		"scheme": HashIDSchemeType.String(),
+	})
+	return &hashIDGenerator{
+		trackingSize: trackingSize,
+		seen:         make(map[string]*list.Element, trackingSize),
+		order:        list.New(),
+		generated:    scope.Counter("generated"),
+		collisions:   scope.Counter("collisions"),
+	}
+}
+
+func (g *hashIDGenerator) Generate(query *storage.WriteQuery) (string, error) {
+	legacy := []byte(query.Tags.ID())
+	hi, lo := murmur3.Sum128(legacy)
+
+	var b [16]byte
+	for i := 0; i < 8; i++ {
+		b[i] = byte(hi >> uint(56-8*i))
+		b[8+i] = byte(lo >> uint(56-8*i))
+	}
+	id := hex.EncodeToString(b[:])
+
+	g.generated.Inc(1)
+	if !g.track(id, xxhash.Sum64(legacy)) {
+		// NB: the series would be merged with the series whose hash collides
+		// with its hash, so it is written with its legacy ID instead which is
+		// unique and encodes its tags.
+		return string(legacy), nil
+	}
+	return id, nil
+}
+
+// track remembers the hash ID was generated from the legacy ID with the
+// given fingerprint, returning false if it was recently generated from a
+// different legacy ID.
+func (g *hashIDGenerator) track(id string, fingerprint uint64) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	if elem, ok := g.seen[id]; ok {
+		g.order.MoveToFront(elem)
+		if elem.Value.(*hashIDEntry).fingerprint != fingerprint {
+			g.collisions.Inc(1)
+			return false
+		}
+		return true
+	}
+
+	if len(g.seen) >= g.trackingSize {
+		oldest := g.order.Back()
+		g.order.Remove(oldest)
+		delete(g.seen, oldest.Value.(*hashIDEntry).id)
+	}
+	g.seen[id] = g.order.PushFront(&hashIDEntry{id: id, fingerprint: fingerprint})
+	return true
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package local
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestIDSchemeTypeUnmarshalYAML(t *testing.T) {
+	for _, value := range validIDSchemeTypes {
+		str := "idScheme: " + value.String() + "\n"
+		var cfg struct {
+			IDScheme IDSchemeType `yaml:"idScheme"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+		assert.Equal(t, value, cfg.IDScheme)
+	}
+
+	var cfg struct {
+		IDScheme IDSchemeType `yaml:"idScheme"`
+	}
+	require.Error(t, yaml.Unmarshal([]byte("idScheme: foo\n"), &cfg))
+}
+
+func TestIDGenerators(t *testing.T) {
+	query := &storage.WriteQuery{
+		Tags: models.Tags{"foo": "bar", "biz": "baz"},
+	}
+
+	legacy, err := NewIDGenerator(LegacyIDSchemeType, nil)
+	require.NoError(t, err)
+	id, err := legacy.Generate(query)
+	require.NoError(t, err)
+	assert.Equal(t, query.Tags.ID(), id)
+
+	hash, err := NewIDGenerator(HashIDSchemeType, nil)
+	require.NoError(t, err)
+	id, err = hash.Generate(query)
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+	again, err := hash.Generate(&storage.WriteQuery{
+		Tags: models.Tags{"biz": "baz", "foo": "bar"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	caller, err := NewIDGenerator(CallerIDSchemeType, nil)
+	require.NoError(t, err)
+	_, err = caller.Generate(query)
+	assert.Equal(t, errCallerIDNotSet, err)
+	query.ID = "custom"
+	id, err = caller.Generate(query)
+	require.NoError(t, err)
+	assert.Equal(t, "custom", id)
+}
+
+func TestHashIDGeneratorTracksCollisions(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	g := newHashIDGenerator(2, scope)
+
+	assert.True(t, g.track("a", 1))
+	assert.True(t, g.track("a", 1))
+	assert.False(t, g.track("a", 2))
+	assert.Equal(t, int64(1),
+		scope.Snapshot().Counters()["id-scheme.collisions+scheme=hash"].Value())
+
+	// The least recently generated ID is forgotten once full.
+	assert.True(t, g.track("b", 1))
+	assert.True(t, g.track("a", 1))
+	assert.True(t, g.track("c", 1))
+	assert.Len(t, g.seen, 2)
+	assert.False(t, g.track("a", 2))
+	assert.True(t, g.track("b", 2))
+}
+
+func TestHashIDGeneratorFallsBackToLegacyIDOnCollision(t *testing.T) {
+	g := newHashIDGenerator(16, tally.NoopScope)
+	query := &storage.WriteQuery{
+		Tags: models.Tags{"foo": "bar"},
+	}
+	id, err := g.Generate(query)
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+
+	// Simulate a collision with the hash ID of a different series.
+	g.seen[id].Value.(*hashIDEntry).fingerprint++
+	id, err = g.Generate(query)
+	require.NoError(t, err)
+	assert.Equal(t, query.Tags.ID(), id)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package local
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/cespare/xxhash"
+)
+
+// sampleScanFactor bounds the IDs resolved to sample from to a multiple of
+// the query limit.
+const sampleScanFactor = 100
+
+// sampleQuery resolves the IDs matching the query and, if there are more than
+// the query limit, returns a query for a deterministic sample of limit series
+// along with the number of series matched. Series are sampled by the lowest
+// hash of their ID so the same series are returned across refreshes. At most
+// sampleScanFactor times the limit IDs are resolved, if more series match the
+// sample is taken from the IDs resolved and the number matched is a lower
+// bound.
+func sampleQuery(
+	session client.Session,
+	namespaceID ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+) (index.Query, int, error) {
+	limit := opts.Limit
+	opts.Limit = limit * sampleScanFactor
+	iter, _, err := session.FetchTaggedIDs(namespaceID, query, opts)
+	if err != nil {
+		return index.Query{}, 0, err
+	}
+	defer iter.Finalize()
+
+	var (
+		matched int
+		samples = make(sampledIDs, 0, limit)
+	)
+	for iter.Next() {
+		_, id, _ := iter.Current()
+		matched++
+
+		sample := sampledID{hash: xxhash.Sum64(id.Bytes())}
+		if len(samples) == limit && !sample.lessBytes(samples[0], id.Bytes()) {
+			continue
+		}
+
+		// NB: Only copy the ID once it is known to be part of the sample.
+		sample.id = id.String()
+		if len(samples) < limit {
+			heap.Push(&samples, sample)
+			continue
+		}
+		samples[0] = sample
+		heap.Fix(&samples, 0)
+	}
+	if err := iter.Err(); err != nil {
+		return index.Query{}, 0, err
+	}
+
+	if matched <= limit {
+		return query, matched, nil
+	}
+
+	ids := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		ids = append(ids, sample.id)
+	}
+	sort.Strings(ids)
+	return newIDsQuery(ids), matched, nil
+}
+
+func newIDsQuery(ids []string) index.Query {
+	queries := make([]idx.Query, 0, len(ids))
+	for _, id := range ids {
+		queries = append(queries,
+			idx.NewTermQuery(doc.IDReservedFieldName, []byte(id)))
+	}
+	return index.Query{Query: idx.NewDisjunctionQuery(queries...)}
+}
+
+type sampledID struct {
+	hash uint64
+	id   string
+}
+
+// lessBytes returns whether the sample with the given ID sorts before other.
+func (s sampledID) lessBytes(other sampledID, id []byte) bool {
+	if s.hash != other.hash {
+		return s.hash < other.hash
+	}
+	return string(id) < other.id
+}
+
+// sampledIDs is a max heap of sampled IDs so the largest sample is evicted
+// first when a smaller one is found.
+type sampledIDs []sampledID
+
+func (s sampledIDs) Len() int { return len(s) }
+
+func (s sampledIDs) Less(i, j int) bool {
+	if s[i].hash != s[j].hash {
+		return s[i].hash > s[j].hash
+	}
+	return s[i].id > s[j].id
+}
+
+func (s sampledIDs) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *sampledIDs) Push(x interface{}) {
+	*s = append(*s, x.(sampledID))
+}
+
+func (s *sampledIDs) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[:n-1]
+	return x
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package local
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/cespare/xxhash"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTaggedIDsIterator(ctrl *gomock.Controller, ids []string) client.TaggedIDsIterator {
+	iter := client.NewMockTaggedIDsIterator(ctrl)
+	calls := make([]*gomock.Call, 0, 2*len(ids)+3)
+	for _, id := range ids {
+		calls = append(calls,
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(ident.StringID("ns"),
+				ident.StringID(id), ident.EmptyTagIterator))
+	}
+	calls = append(calls,
+		iter.EXPECT().Next().Return(false),
+		iter.EXPECT().Err().Return(nil),
+		iter.EXPECT().Finalize())
+	gomock.InOrder(calls...)
+	return iter
+}
+
+func TestSampleQueryDeterministic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	expected := append([]string(nil), ids...)
+	sort.Slice(expected, func(i, j int) bool {
+		return xxhash.Sum64([]byte(expected[i])) < xxhash.Sum64([]byte(expected[j]))
+	})
+	expected = expected[:3]
+	sort.Strings(expected)
+
+	reversed := make([]string, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		reversed = append(reversed, ids[i])
+	}
+
+	for _, order := range [][]string{ids, reversed} {
+		session := client.NewMockSession(ctrl)
+		session.EXPECT().
+			FetchTaggedIDs(gomock.Any(), gomock.Any(), index.QueryOptions{Limit: 3 * sampleScanFactor}).
+			Return(newTestTaggedIDsIterator(ctrl, order), true, nil)
+
+		query, matched, err := sampleQuery(session, ident.StringID("ns"),
+			index.Query{}, index.QueryOptions{Limit: 3})
+		require.NoError(t, err)
+		assert.Equal(t, len(ids), matched)
+		assert.True(t, newIDsQuery(expected).Equal(query.Query))
+	}
+}
+
+func TestSampleQueryUnderLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(newTestTaggedIDsIterator(ctrl, []string{"a", "b"}), true, nil)
+
+	original := newIDsQuery([]string{"x"})
+	query, matched, err := sampleQuery(session, ident.StringID("ns"),
+		original, index.QueryOptions{Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 2, matched)
+	assert.True(t, original.Equal(query.Query))
+}
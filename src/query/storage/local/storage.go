@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/util/execution"
+	"github.com/m3db/m3/src/query/util/logging"
 	xerrors "github.com/m3db/m3x/errors"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/pool"
@@ -80,6 +81,7 @@ func (s *localStorage) Fetch(ctx context.Context, query *storage.FetchQuery, opt
 		result     multiFetchResult
 		wg         sync.WaitGroup
 	)
+	opts.RequestMetadata = requestMetadata(ctx)
 	for _, namespace := range namespaces {
 		namespace := namespace // Capture var
 
@@ -94,7 +96,7 @@ func (s *localStorage) Fetch(ctx context.Context, query *storage.FetchQuery, opt
 
 		wg.Add(1)
 		go func() {
-			r, err := s.fetch(namespace, m3query, opts)
+			r, err := s.fetch(namespace, m3query, opts, options.SampleOnLimit)
 			result.add(namespace.Attributes(), r, err)
 			wg.Done()
 		}()
@@ -115,17 +117,37 @@ func (s *localStorage) fetch(
 	namespace ClusterNamespace,
 	query index.Query,
 	opts index.QueryOptions,
+	sampleOnLimit bool,
 ) (*storage.FetchResult, error) {
 	namespaceID := namespace.NamespaceID()
 	session := namespace.Session()
 
-	// TODO (nikunj): Handle second return param
-	iters, _, err := session.FetchTagged(namespaceID, query, opts)
+	matched := -1
+	if sampleOnLimit && opts.Limit > 0 {
+		var err error
+		query, matched, err = sampleQuery(session, namespaceID, query, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	iters, exhaustive, err := session.FetchTagged(namespaceID, query, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return storage.SeriesIteratorsToFetchResult(iters, namespaceID, s.workerPool)
+	result, err := storage.SeriesIteratorsToFetchResult(iters, namespaceID, s.workerPool)
+	if err != nil {
+		return nil, err
+	}
+
+	result.MatchedSeries = len(result.SeriesList)
+	result.Truncated = !exhaustive
+	if matched > result.MatchedSeries {
+		result.MatchedSeries = matched
+		result.Truncated = true
+	}
+	return result, nil
 }
 
 func (s *localStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
@@ -151,6 +173,7 @@ func (s *localStorage) FetchTags(ctx context.Context, query *storage.FetchQuery,
 		result     multiFetchTagsResult
 		wg         sync.WaitGroup
 	)
+	opts.RequestMetadata = requestMetadata(ctx)
 	for _, namespace := range namespaces {
 		namespace := namespace // Capture var
 
@@ -214,6 +237,79 @@ func (s *localStorage) fetchTags(
 	}, nil
 }
 
+func (s *localStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.CountResult, error) {
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-options.KillChan:
+		return nil, errors.ErrQueryInterrupted
+	default:
+	}
+
+	m3query, err := storage.FetchQueryToM3Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		opts       = storage.FetchOptionsToM3Options(options, query)
+		namespaces = s.clusters.ClusterNamespaces()
+		now        = time.Now()
+		fetches    = 0
+		result     multiFetchCountResult
+		wg         sync.WaitGroup
+	)
+	opts.RequestMetadata = requestMetadata(ctx)
+	for _, namespace := range namespaces {
+		namespace := namespace // Capture var
+
+		clusterStart := now.Add(-1 * namespace.Attributes().Retention)
+
+		// Only include if cluster can completely fulfill the range
+		if clusterStart.After(query.Start) {
+			continue
+		}
+
+		fetches++
+
+		wg.Add(1)
+		go func() {
+			result.add(s.fetchCount(namespace, m3query, opts))
+			wg.Done()
+		}()
+	}
+
+	if fetches == 0 {
+		return nil, errNoLocalClustersFulfillsQuery
+	}
+
+	wg.Wait()
+	if err := result.err.FinalError(); err != nil {
+		return nil, err
+	}
+	return result.result, nil
+}
+
+func (s *localStorage) fetchCount(
+	namespace ClusterNamespace,
+	query index.Query,
+	opts index.QueryOptions,
+) (*storage.CountResult, error) {
+	namespaceID := namespace.NamespaceID()
+	session := namespace.Session()
+
+	count, exhaustive, err := session.FetchTaggedCount(namespaceID, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.CountResult{
+		Count:      count,
+		Exhaustive: exhaustive,
+	}, nil
+}
+
 func (s *localStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	// Check if the query was interrupted.
 	select {
@@ -226,14 +322,22 @@ func (s *localStorage) Write(ctx context.Context, query *storage.WriteQuery) err
 		return errors.ErrNilWriteQuery
 	}
 
-	id := query.Tags.ID()
+	namespace, err := s.resolveClusterNamespace(query.Attributes)
+	if err != nil {
+		return err
+	}
+
+	id, err := namespace.IDGenerator().Generate(query)
+	if err != nil {
+		return err
+	}
+
 	common := &writeRequestCommon{
-		store:       s,
 		annotation:  query.Annotation,
 		unit:        query.Unit,
 		id:          id,
 		tagIterator: storage.TagsToIdentTagIterator(query.Tags),
-		attributes:  query.Attributes,
+		namespace:   namespace,
 	}
 
 	requests := make([]execution.Request, len(query.Datapoints))
@@ -243,6 +347,13 @@ func (s *localStorage) Write(ctx context.Context, query *storage.WriteQuery) err
 	return execution.ExecuteParallel(ctx, requests)
 }
 
+// requestMetadata returns the ID of the request being served, it is sent
+// with writes and fetches so that nodes include it in their logs and errors.
+func requestMetadata(ctx context.Context) string {
+	id, _ := logging.ContextID(ctx)
+	return id
+}
+
 func (s *localStorage) Type() storage.Type {
 	return storage.TypeLocalDC
 }
@@ -256,7 +367,7 @@ func (s *localStorage) FetchBlocks(
 		return block.Result{}, err
 	}
 
-	res, err := storage.FetchResultToBlockResult(fetchResult, query)
+	res, err := storage.FetchResultToBlockResult(fetchResult, query, options)
 	if err != nil {
 		return block.Result{}, err
 	}
@@ -268,51 +379,49 @@ func (s *localStorage) Close() error {
 	return nil
 }
 
-func (w *writeRequest) Process(ctx context.Context) error {
-	common := w.writeRequestCommon
-	store := common.store
-	id := ident.StringID(common.id)
-
-	var (
-		namespace ClusterNamespace
-		err       error
-	)
-	switch common.attributes.MetricsType {
+func (s *localStorage) resolveClusterNamespace(
+	attributes storage.Attributes,
+) (ClusterNamespace, error) {
+	switch attributes.MetricsType {
 	case storage.UnaggregatedMetricsType:
-		namespace = store.clusters.UnaggregatedClusterNamespace()
+		return s.clusters.UnaggregatedClusterNamespace(), nil
 	case storage.AggregatedMetricsType:
 		attrs := RetentionResolution{
-			Retention:  common.attributes.Retention,
-			Resolution: common.attributes.Resolution,
+			Retention:  attributes.Retention,
+			Resolution: attributes.Resolution,
 		}
-		var exists bool
-		namespace, exists = store.clusters.AggregatedClusterNamespace(attrs)
+		namespace, exists := s.clusters.AggregatedClusterNamespace(attrs)
 		if !exists {
-			err = fmt.Errorf("no configured cluster namespace for: retention=%s, resolution=%s",
+			return nil, fmt.Errorf("no configured cluster namespace for: retention=%s, resolution=%s",
 				attrs.Retention.String(), attrs.Resolution.String())
 		}
+		return namespace, nil
 	default:
-		metricsType := common.attributes.MetricsType
-		err = fmt.Errorf("invalid write request metrics type: %s (%d)",
+		metricsType := attributes.MetricsType
+		return nil, fmt.Errorf("invalid write request metrics type: %s (%d)",
 			metricsType.String(), uint(metricsType))
 	}
-	if err != nil {
-		return err
-	}
+}
 
+func (w *writeRequest) Process(ctx context.Context) error {
+	common := w.writeRequestCommon
+	id := ident.StringID(common.id)
+	namespace := common.namespace
 	namespaceID := namespace.NamespaceID()
 	session := namespace.Session()
+	if metadata := requestMetadata(ctx); metadata != "" {
+		session = session.WithRequestMetadata(metadata)
+	}
 	return session.WriteTagged(namespaceID, id, common.tagIterator,
 		w.timestamp, w.value, common.unit, common.annotation)
 }
 
 type writeRequestCommon struct {
-	store       *localStorage
 	annotation  []byte
 	unit        xtime.Unit
 	id          string
 	tagIterator ident.TagIterator
-	attributes  storage.Attributes
+	namespace   ClusterNamespace
 }
 
 type writeRequest struct {
@@ -363,6 +472,10 @@ func (r *multiFetchResult) add(
 
 	r.result.HasNext = r.result.HasNext && result.HasNext
 	r.result.LocalOnly = r.result.LocalOnly && result.LocalOnly
+	r.result.Truncated = r.result.Truncated || result.Truncated
+	if result.MatchedSeries > r.result.MatchedSeries {
+		r.result.MatchedSeries = result.MatchedSeries
+	}
 
 	// Need to dedupe
 	if r.dedupeMap == nil {
@@ -445,3 +558,28 @@ func (r *multiFetchTagsResult) add(
 		r.dedupeMap[id] = struct{}{}
 	}
 }
+
+// multiFetchCountResult keeps the largest count across namespaces since
+// series are written to each namespace that fulfills the query range.
+type multiFetchCountResult struct {
+	sync.Mutex
+	result *storage.CountResult
+	err    xerrors.MultiError
+}
+
+func (r *multiFetchCountResult) add(
+	result *storage.CountResult,
+	err error,
+) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err != nil {
+		r.err = r.err.Add(err)
+		return
+	}
+
+	if r.result == nil || result.Count > r.result.Count {
+		r.result = result
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3cluster/generated/proto/commonpb"
+	"github.com/m3db/m3cluster/kv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// KVKeyPrefix is the prefix of the KV keys metadata is stored under.
+	KVKeyPrefix = "_m3query.metric-metadata."
+
+	// numKVShards is the number of KV keys metadata is spread across so that
+	// no single value grows too large for the KV store.
+	numKVShards = 64
+
+	// maxCheckAndSetAttempts is the max number of attempts at updating a KV
+	// key that is concurrently updated by other coordinators.
+	maxCheckAndSetAttempts = 5
+
+	defaultKVSyncInterval = time.Minute
+)
+
+var errKVStoreClosed = errors.New("metadata store is closed")
+
+// KVStoreOptions are options for a KV backed metadata store.
+type KVStoreOptions struct {
+	// MaxMetrics is the max number of metrics tracked.
+	MaxMetrics int
+	// MaxPerMetric is the max number of distinct metadata entries tracked
+	// per metric.
+	MaxPerMetric int
+	// SyncInterval is how often metadata added is persisted to KV and the
+	// metadata added by other coordinators is loaded, defaults to 1m.
+	SyncInterval time.Duration
+}
+
+// KVStore is a metadata store persisted to a KV store.
+type KVStore interface {
+	Store
+
+	// Close persists the metadata not yet persisted and stops syncing.
+	Close() error
+}
+
+type kvStore struct {
+	sync.Mutex
+
+	cache    *store
+	kv       kv.Store
+	interval time.Duration
+	// dirty are the shards with metadata added since last persisted.
+	dirty   [numKVShards]bool
+	closed  bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewKVStore returns a metadata store backed by a KV store so metadata
+// survives restarts and is shared by the coordinators using the same KV
+// store, queries are served from memory and metadata is synced with the KV
+// store periodically.
+func NewKVStore(store kv.Store, opts KVStoreOptions) KVStore {
+	interval := opts.SyncInterval
+	if interval <= 0 {
+		interval = defaultKVSyncInterval
+	}
+	s := &kvStore{
+		cache:    newStore(opts.MaxMetrics, opts.MaxPerMetric),
+		kv:       store,
+		interval: interval,
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		// NB: the KV store being unavailable should not stop the coordinator
+		// starting, the metadata is loaded again on the next sync.
+		logging.WithContext(context.TODO()).Error("unable to load metric metadata",
+			zap.Error(err))
+	}
+	go s.syncLoop()
+	return s
+}
+
+func (s *kvStore) Add(metricName string, m Metadata) bool {
+	accepted, added := s.cache.add(metricName, m)
+	if added {
+		s.Lock()
+		s.dirty[kvShard(metricName)] = true
+		s.Unlock()
+	}
+	return accepted
+}
+
+func (s *kvStore) Query(q Query) map[string][]Metadata {
+	return s.cache.Query(q)
+}
+
+func (s *kvStore) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return errKVStoreClosed
+	}
+	s.closed = true
+	s.Unlock()
+
+	close(s.closeCh)
+	<-s.doneCh
+	return s.persist()
+}
+
+func (s *kvStore) syncLoop() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	logger := logging.WithContext(context.TODO())
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.persist(); err != nil {
+			logger.Error("unable to persist metric metadata", zap.Error(err))
+		}
+		if err := s.load(); err != nil {
+			logger.Error("unable to load metric metadata", zap.Error(err))
+		}
+	}
+}
+
+// load adds the metadata of each KV shard to the cache.
+func (s *kvStore) load() error {
+	for shard := 0; shard < numKVShards; shard++ {
+		metrics, _, err := s.get(shard)
+		if err != nil {
+			return err
+		}
+		for name, entries := range metrics {
+			for _, m := range entries {
+				s.cache.add(name, m)
+			}
+		}
+	}
+	return nil
+}
+
+// persist merges the metadata of each dirty shard into its KV shard.
+func (s *kvStore) persist() error {
+	s.Lock()
+	dirty := s.dirty
+	s.dirty = [numKVShards]bool{}
+	s.Unlock()
+
+	var lastErr error
+	for shard, isDirty := range dirty {
+		if !isDirty {
+			continue
+		}
+		if err := s.persistShard(shard); err != nil {
+			// NB: mark the shard dirty again so it is retried next sync.
+			s.Lock()
+			s.dirty[shard] = true
+			s.Unlock()
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (s *kvStore) persistShard(shard int) error {
+	for attempt := 0; attempt < maxCheckAndSetAttempts; attempt++ {
+		metrics, version, err := s.get(shard)
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		s.cache.forEach(func(name string) bool {
+			return kvShard(name) == shard
+		}, func(name string, entries []Metadata) {
+			for _, m := range entries {
+				if !contains(metrics[name], m) {
+					metrics[name] = appendEvicting(metrics[name], m, s.cache.maxPerMetric)
+					changed = true
+				}
+			}
+		})
+		if !changed {
+			return nil
+		}
+
+		value, err := json.Marshal(metrics)
+		if err != nil {
+			return err
+		}
+		_, err = s.kv.CheckAndSet(kvKey(shard), version,
+			&commonpb.StringProto{Value: string(value)})
+		if err == kv.ErrVersionMismatch {
+			// Updated concurrently by another coordinator, merge again.
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("unable to update metric metadata key %s: %v",
+		kvKey(shard), kv.ErrVersionMismatch)
+}
+
+// get returns the metadata of a KV shard and its version, version zero if
+// the shard has not been set.
+func (s *kvStore) get(shard int) (map[string][]Metadata, int, error) {
+	metrics := make(map[string][]Metadata)
+	value, err := s.kv.Get(kvKey(shard))
+	if err == kv.ErrNotFound {
+		return metrics, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var proto commonpb.StringProto
+	if err := value.Unmarshal(&proto); err != nil {
+		return nil, 0, err
+	}
+	if err := json.Unmarshal([]byte(proto.Value), &metrics); err != nil {
+		return nil, 0, fmt.Errorf("unable to parse metric metadata key %s: %v",
+			kvKey(shard), err)
+	}
+	return metrics, value.Version(), nil
+}
+
+func appendEvicting(entries []Metadata, m Metadata, maxEntries int) []Metadata {
+	if len(entries) >= maxEntries {
+		entries = append(entries[:0], entries[1:]...)
+	}
+	return append(entries, m)
+}
+
+func kvShard(metricName string) int {
+	h := fnv.New32a()
+	h.Write([]byte(metricName))
+	return int(h.Sum32() % numKVShards)
+}
+
+func kvKey(shard int) string {
+	return fmt.Sprintf("%s%d", KVKeyPrefix, shard)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3cluster/kv/mem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVStorePersistsAndShares(t *testing.T) {
+	kvStore := mem.NewStore()
+	opts := KVStoreOptions{MaxMetrics: 10, MaxPerMetric: 2, SyncInterval: time.Hour}
+
+	counter := Metadata{Type: "counter", Help: "Total requests."}
+	gauge := Metadata{Type: "gauge"}
+
+	first := NewKVStore(kvStore, opts)
+	second := NewKVStore(kvStore, opts)
+	require.True(t, first.Add("http_requests_total", counter))
+	require.True(t, second.Add("up", gauge))
+	require.True(t, second.Add("http_requests_total", counter))
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+	require.Error(t, second.Close())
+
+	// A restarted coordinator loads the metadata added by both.
+	restarted := NewKVStore(kvStore, opts)
+	defer restarted.Close()
+	assert.Equal(t, map[string][]Metadata{
+		"http_requests_total": {counter},
+		"up":                  {gauge},
+	}, restarted.Query(Query{}))
+}
+
+func TestKVStoreSyncLoadsMetadataAddedElsewhere(t *testing.T) {
+	kvStore := mem.NewStore()
+
+	syncing := NewKVStore(kvStore, KVStoreOptions{SyncInterval: time.Millisecond})
+	defer syncing.Close()
+
+	other := NewKVStore(kvStore, KVStoreOptions{SyncInterval: time.Hour})
+	require.True(t, other.Add("up", Metadata{Type: "gauge"}))
+	require.NoError(t, other.Close())
+
+	for len(syncing.Query(Query{Metric: "up"})) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package metadata provides storage for metric metadata such as the help
+// text, type and unit of metrics received via remote write.
+package metadata
+
+import (
+	"sort"
+	"sync"
+)
+
+// Metadata is the metadata of a metric.
+type Metadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// Query is a query for metric metadata.
+type Query struct {
+	// Metric restricts results to a single metric name if set.
+	Metric string
+	// Limit is the max number of metrics returned, unlimited if not positive.
+	Limit int
+	// LimitPerMetric is the max number of metadata entries returned per
+	// metric, unlimited if not positive.
+	LimitPerMetric int
+}
+
+// Store stores metric metadata.
+type Store interface {
+	// Add adds metadata for a metric, returning false if the store is full
+	// and the metric is not already tracked.
+	Add(metricName string, m Metadata) bool
+
+	// Query returns the metadata of metrics keyed by metric name.
+	Query(q Query) map[string][]Metadata
+}
+
+const (
+	// DefaultMaxMetrics is the default max number of metrics tracked.
+	DefaultMaxMetrics = 100000
+
+	// DefaultMaxPerMetric is the default max number of distinct metadata
+	// entries tracked per metric.
+	DefaultMaxPerMetric = 10
+)
+
+type store struct {
+	sync.RWMutex
+
+	maxMetrics   int
+	maxPerMetric int
+	metrics      map[string][]Metadata
+}
+
+// NewStore returns a new in memory metadata store that tracks at most
+// maxMetrics metrics and maxPerMetric distinct entries per metric, when
+// full the oldest entry of a metric is evicted.
+func NewStore(maxMetrics, maxPerMetric int) Store {
+	return newStore(maxMetrics, maxPerMetric)
+}
+
+func newStore(maxMetrics, maxPerMetric int) *store {
+	if maxMetrics <= 0 {
+		maxMetrics = DefaultMaxMetrics
+	}
+	if maxPerMetric <= 0 {
+		maxPerMetric = DefaultMaxPerMetric
+	}
+	return &store{
+		maxMetrics:   maxMetrics,
+		maxPerMetric: maxPerMetric,
+		metrics:      make(map[string][]Metadata),
+	}
+}
+
+func (s *store) Add(metricName string, m Metadata) bool {
+	accepted, _ := s.add(metricName, m)
+	return accepted
+}
+
+// add adds metadata for a metric, returning whether the metadata was
+// accepted and whether it was not already tracked.
+func (s *store) add(metricName string, m Metadata) (bool, bool) {
+	s.RLock()
+	entries, ok := s.metrics[metricName]
+	exists := ok && contains(entries, m)
+	s.RUnlock()
+	if exists {
+		return true, false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	entries, ok = s.metrics[metricName]
+	if !ok && len(s.metrics) >= s.maxMetrics {
+		return false, false
+	}
+	if contains(entries, m) {
+		return true, false
+	}
+	s.metrics[metricName] = appendEvicting(entries, m, s.maxPerMetric)
+	return true, true
+}
+
+// forEach calls fn with a copy of the metadata of each metric that matches
+// the filter.
+func (s *store) forEach(filter func(metricName string) bool, fn func(metricName string, entries []Metadata)) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for name, entries := range s.metrics {
+		if filter(name) {
+			fn(name, append([]Metadata(nil), entries...))
+		}
+	}
+}
+
+func (s *store) Query(q Query) map[string][]Metadata {
+	s.RLock()
+	defer s.RUnlock()
+
+	var names []string
+	if q.Metric != "" {
+		if _, ok := s.metrics[q.Metric]; ok {
+			names = append(names, q.Metric)
+		}
+	} else {
+		names = make([]string, 0, len(s.metrics))
+		for name := range s.metrics {
+			names = append(names, name)
+		}
+		// Sort for deterministic results when limiting.
+		sort.Strings(names)
+	}
+	if q.Limit > 0 && len(names) > q.Limit {
+		names = names[:q.Limit]
+	}
+
+	results := make(map[string][]Metadata, len(names))
+	for _, name := range names {
+		entries := s.metrics[name]
+		if q.LimitPerMetric > 0 && len(entries) > q.LimitPerMetric {
+			entries = entries[:q.LimitPerMetric]
+		}
+		results[name] = append([]Metadata(nil), entries...)
+	}
+	return results
+}
+
+func contains(entries []Metadata, m Metadata) bool {
+	for _, e := range entries {
+		if e == m {
+			return true
+		}
+	}
+	return false
+}
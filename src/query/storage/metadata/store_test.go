@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreAddAndQuery(t *testing.T) {
+	s := NewStore(2, 2)
+
+	counter := Metadata{Type: "counter", Help: "Total requests.", Unit: "requests"}
+	require.True(t, s.Add("http_requests_total", counter))
+	require.True(t, s.Add("http_requests_total", counter))
+	require.True(t, s.Add("up", Metadata{Type: "gauge"}))
+
+	// Store is full so new metrics are rejected.
+	require.False(t, s.Add("go_goroutines", Metadata{Type: "gauge"}))
+
+	assert.Equal(t, map[string][]Metadata{
+		"http_requests_total": {counter},
+		"up":                  {{Type: "gauge"}},
+	}, s.Query(Query{}))
+
+	assert.Equal(t, map[string][]Metadata{
+		"up": {{Type: "gauge"}},
+	}, s.Query(Query{Metric: "up"}))
+	assert.Equal(t, map[string][]Metadata{}, s.Query(Query{Metric: "missing"}))
+
+	assert.Equal(t, map[string][]Metadata{
+		"http_requests_total": {counter},
+	}, s.Query(Query{Limit: 1}))
+}
+
+func TestStoreEvictsOldestPerMetric(t *testing.T) {
+	s := NewStore(10, 2)
+
+	require.True(t, s.Add("up", Metadata{Help: "a"}))
+	require.True(t, s.Add("up", Metadata{Help: "b"}))
+	require.True(t, s.Add("up", Metadata{Help: "c"}))
+
+	assert.Equal(t, map[string][]Metadata{
+		"up": {{Help: "b"}, {Help: "c"}},
+	}, s.Query(Query{}))
+	assert.Equal(t, map[string][]Metadata{
+		"up": {{Help: "b"}},
+	}, s.Query(Query{LimitPerMetric: 1}))
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package mirror provides a storage that mirrors a percentage of writes to
+// a secondary storage, e.g. to validate a new cluster with the shape of
+// production load.
+package mirror
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQueueSize    = 4096
+	defaultConcurrency  = 4
+	defaultWriteTimeout = 10 * time.Second
+
+	// percentResolution is the number of buckets series are hashed to when
+	// deciding whether to mirror them.
+	percentResolution = 10000
+)
+
+// Options are the options of a mirror storage.
+type Options struct {
+	// Percent is the percentage of series whose writes are mirrored, series
+	// are selected by the hash of their ID so all writes of a selected series
+	// are mirrored.
+	Percent float64
+
+	// QueueSize is the number of writes queued for the secondary storage,
+	// writes are dropped when the queue is full.
+	QueueSize int
+
+	// Concurrency is the number of concurrent writes to the secondary storage.
+	Concurrency int
+
+	// Scope is the scope for the mirror metrics.
+	Scope tally.Scope
+}
+
+type mirrorMetrics struct {
+	enqueued tally.Counter
+	dropped  tally.Counter
+	success  tally.Counter
+	errors   tally.Counter
+}
+
+func newMirrorMetrics(scope tally.Scope) mirrorMetrics {
+	return mirrorMetrics{
+		enqueued: scope.Counter("enqueued"),
+		dropped:  scope.Counter("dropped"),
+		success:  scope.Counter("success"),
+		errors:   scope.Counter("errors"),
+	}
+}
+
+type mirrorStorage struct {
+	storage.Storage
+
+	sync.RWMutex
+	closed bool
+
+	secondary storage.Storage
+	threshold uint32
+	queue     chan *storage.WriteQuery
+	wg        sync.WaitGroup
+	metrics   mirrorMetrics
+}
+
+// NewStorage returns a storage that writes to the primary storage and
+// asynchronously mirrors a percentage of writes to the secondary storage,
+// all reads are served by the primary storage.
+func NewStorage(
+	primary storage.Storage,
+	secondary storage.Storage,
+	opts Options,
+) storage.Storage {
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	scope := opts.Scope
+	if scope == nil {
+		scope = tally.NoopScope
+	}
+
+	s := &mirrorStorage{
+		Storage:   primary,
+		secondary: secondary,
+		threshold: uint32(opts.Percent / 100 * percentResolution),
+		queue:     make(chan *storage.WriteQuery, queueSize),
+		metrics:   newMirrorMetrics(scope),
+	}
+	s.wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go s.mirrorLoop()
+	}
+	return s
+}
+
+func (s *mirrorStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
+	if err := s.Storage.Write(ctx, query); err != nil {
+		return err
+	}
+	if query == nil || !s.mirrored(query) {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	// NB: the query is not modified by writes so it is safe to mirror it
+	// after the write to the primary storage has returned.
+	select {
+	case s.queue <- query:
+		s.metrics.enqueued.Inc(1)
+	default:
+		s.metrics.dropped.Inc(1)
+	}
+	return nil
+}
+
+func (s *mirrorStorage) mirrored(query *storage.WriteQuery) bool {
+	if s.threshold == 0 {
+		return false
+	}
+	if s.threshold >= percentResolution {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(query.Tags.ID()))
+	return h.Sum32()%percentResolution < s.threshold
+}
+
+func (s *mirrorStorage) mirrorLoop() {
+	defer s.wg.Done()
+	for query := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultWriteTimeout)
+		err := s.secondary.Write(ctx, query)
+		cancel()
+		if err != nil {
+			s.metrics.errors.Inc(1)
+			logging.WithContext(ctx).Debug("unable to mirror write", zap.Error(err))
+			continue
+		}
+		s.metrics.success.Inc(1)
+	}
+}
+
+// Close drains the queued writes to the secondary storage and closes both
+// storages.
+func (s *mirrorStorage) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.Unlock()
+
+	s.wg.Wait()
+	secondaryErr := s.secondary.Close()
+	if err := s.Storage.Close(); err != nil {
+		return err
+	}
+	return secondaryErr
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStorage struct {
+	storage.Storage
+
+	sync.Mutex
+	writes []*storage.WriteQuery
+	closed bool
+}
+
+func (s *testStorage) Write(_ context.Context, query *storage.WriteQuery) error {
+	s.Lock()
+	s.writes = append(s.writes, query)
+	s.Unlock()
+	return nil
+}
+
+func (s *testStorage) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newTestWrite(i int) *storage.WriteQuery {
+	return &storage.WriteQuery{
+		Tags: models.Tags{models.MetricName: fmt.Sprintf("series_%d", i)},
+	}
+}
+
+func TestMirrorStorageMirrorsAllWrites(t *testing.T) {
+	primary, secondary := &testStorage{}, &testStorage{}
+	s := NewStorage(primary, secondary, Options{Percent: 100})
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Write(context.Background(), newTestWrite(i)))
+	}
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, 10, len(primary.writes))
+	assert.Equal(t, 10, len(secondary.writes))
+	assert.True(t, primary.closed)
+	assert.True(t, secondary.closed)
+
+	// Writes after close are not mirrored.
+	require.NoError(t, s.Write(context.Background(), newTestWrite(0)))
+	assert.Equal(t, 11, len(primary.writes))
+	assert.Equal(t, 10, len(secondary.writes))
+}
+
+func TestMirrorStorageMirrorsPercentOfSeries(t *testing.T) {
+	primary, secondary := &testStorage{}, &testStorage{}
+	s := NewStorage(primary, secondary, Options{Percent: 25})
+
+	// Every write of a mirrored series is mirrored.
+	for j := 0; j < 2; j++ {
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, s.Write(context.Background(), newTestWrite(i)))
+		}
+	}
+	require.NoError(t, s.Close())
+
+	counts := make(map[string]int)
+	for _, w := range secondary.writes {
+		counts[w.Tags.ID()]++
+	}
+	for id, n := range counts {
+		assert.Equal(t, 2, n, id)
+	}
+	assert.True(t, len(counts) > 150 && len(counts) < 350,
+		fmt.Sprintf("mirrored %d series", len(counts)))
+}
+
+func TestMirrorStorageNoneMirrored(t *testing.T) {
+	primary, secondary := &testStorage{}, &testStorage{}
+	s := NewStorage(primary, secondary, Options{})
+
+	require.NoError(t, s.Write(context.Background(), newTestWrite(0)))
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, 1, len(primary.writes))
+	assert.Equal(t, 0, len(secondary.writes))
+}
@@ -38,6 +38,7 @@ type Storage interface {
 	SetFetchTagsResult(*storage.SearchResults, error)
 	SetWriteResult(error)
 	SetFetchBlocksResult(block.Result, error)
+	SetFetchCountResult(*storage.CountResult, error)
 	SetCloseResult(error)
 	Writes() []*storage.WriteQuery
 }
@@ -62,6 +63,10 @@ type mockStorage struct {
 		result block.Result
 		err    error
 	}
+	fetchCountResult struct {
+		result *storage.CountResult
+		err    error
+	}
 	closeResult struct {
 		err error
 	}
@@ -106,6 +111,13 @@ func (s *mockStorage) SetFetchBlocksResult(result block.Result, err error) {
 	s.fetchBlocksResult.err = err
 }
 
+func (s *mockStorage) SetFetchCountResult(result *storage.CountResult, err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.fetchCountResult.result = result
+	s.fetchCountResult.err = err
+}
+
 func (s *mockStorage) SetCloseResult(err error) {
 	s.Lock()
 	defer s.Unlock()
@@ -169,3 +181,13 @@ func (s *mockStorage) FetchBlocks(
 	defer s.RUnlock()
 	return s.fetchBlocksResult.result, s.fetchBlocksResult.err
 }
+
+func (s *mockStorage) FetchCount(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.CountResult, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.fetchCountResult.result, s.fetchCountResult.err
+}
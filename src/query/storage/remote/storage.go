@@ -47,6 +47,10 @@ func (s *remoteStorage) FetchTags(ctx context.Context, query *storage.FetchQuery
 	return nil, errors.ErrNotImplemented
 }
 
+func (s *remoteStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.CountResult, error) {
+	return nil, errors.ErrNotImplemented
+}
+
 func (s *remoteStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	return s.client.Write(ctx, query)
 }
@@ -0,0 +1,340 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package selftelemetry writes the metrics of the query service itself to
+// storage, so small deployments can monitor M3 without an external
+// Prometheus scraping it.
+package selftelemetry
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWriteTimeout = 10 * time.Second
+
+	timerCountSuffix      = "_count"
+	timerSumSuffix        = "_sum"
+	histogramBucketSuffix = "_bucket"
+	histogramCountSuffix  = "_count"
+	histogramBucketTag    = "le"
+)
+
+// Reporter is a tally reporter that writes metrics to storage, counters are
+// written as cumulative values, timers as the cumulative count and sum of
+// their durations in seconds and histograms as cumulative buckets, all
+// following the Prometheus conventions.
+type Reporter interface {
+	tally.StatsReporter
+
+	// SetAppender sets the appender that metrics are written to, metrics
+	// reported before the appender is set are not written.
+	SetAppender(appender storage.Appender)
+}
+
+type metric struct {
+	tags  models.Tags
+	value float64
+}
+
+type timer struct {
+	count metric
+	sum   metric
+}
+
+type histogram struct {
+	buckets map[float64]*metric
+	count   metric
+	dirty   bool
+}
+
+type reporter struct {
+	sync.Mutex
+
+	prefix     string
+	attributes storage.Attributes
+	nowFn      func() time.Time
+	appender   storage.Appender
+	counters   map[string]*metric
+	timers     map[string]*timer
+	histograms map[string]*histogram
+	pending    []*metric
+}
+
+// NewReporter returns a new reporter that writes metrics with the given
+// prefix and storage attributes, the attributes select the namespace
+// reserved for the metrics.
+func NewReporter(prefix string, attributes storage.Attributes) Reporter {
+	return &reporter{
+		prefix:     prefix,
+		attributes: attributes,
+		nowFn:      time.Now,
+		counters:   make(map[string]*metric),
+		timers:     make(map[string]*timer),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+func (r *reporter) SetAppender(appender storage.Appender) {
+	r.Lock()
+	r.appender = appender
+	r.Unlock()
+}
+
+func (r *reporter) ReportCounter(name string, tags map[string]string, value int64) {
+	key := metricKey(name, tags)
+	r.Lock()
+	counter, ok := r.counters[key]
+	if !ok {
+		counter = &metric{tags: r.metricTags(name, tags)}
+		r.counters[key] = counter
+	}
+	counter.value += float64(value)
+	r.pending = append(r.pending, &metric{tags: counter.tags, value: counter.value})
+	r.Unlock()
+}
+
+func (r *reporter) ReportGauge(name string, tags map[string]string, value float64) {
+	m := &metric{tags: r.metricTags(name, tags), value: value}
+	r.Lock()
+	r.pending = append(r.pending, m)
+	r.Unlock()
+}
+
+func (r *reporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
+	key := metricKey(name, tags)
+	r.Lock()
+	t, ok := r.timers[key]
+	if !ok {
+		t = &timer{
+			count: metric{tags: r.metricTags(name+timerCountSuffix, tags)},
+			sum:   metric{tags: r.metricTags(name+timerSumSuffix, tags)},
+		}
+		r.timers[key] = t
+	}
+	t.count.value++
+	t.sum.value += interval.Seconds()
+	r.pending = append(r.pending,
+		&metric{tags: t.count.tags, value: t.count.value},
+		&metric{tags: t.sum.tags, value: t.sum.value})
+	r.Unlock()
+}
+
+func (r *reporter) ReportHistogramValueSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound float64,
+	samples int64,
+) {
+	upperBound := bucketUpperBound
+	if bucketUpperBound == math.MaxFloat64 {
+		upperBound = math.Inf(1)
+	}
+	r.reportHistogramSamples(name, tags, upperBound, samples)
+}
+
+func (r *reporter) ReportHistogramDurationSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound time.Duration,
+	samples int64,
+) {
+	upperBound := bucketUpperBound.Seconds()
+	if bucketUpperBound == time.Duration(math.MaxInt64) {
+		upperBound = math.Inf(1)
+	}
+	r.reportHistogramSamples(name, tags, upperBound, samples)
+}
+
+// reportHistogramSamples adds the samples to the bucket of the histogram, the
+// buckets are written once flushed since each bucket is cumulative.
+func (r *reporter) reportHistogramSamples(
+	name string,
+	tags map[string]string,
+	upperBound float64,
+	samples int64,
+) {
+	key := metricKey(name, tags)
+	r.Lock()
+	h, ok := r.histograms[key]
+	if !ok {
+		h = &histogram{
+			buckets: make(map[float64]*metric),
+			count:   metric{tags: r.metricTags(name+histogramCountSuffix, tags)},
+		}
+		r.histograms[key] = h
+	}
+	bucket, ok := h.buckets[upperBound]
+	if !ok {
+		bucketTags := make(map[string]string, len(tags)+1)
+		for k, v := range tags {
+			bucketTags[k] = v
+		}
+		bucketTags[histogramBucketTag] = strconv.FormatFloat(upperBound, 'g', -1, 64)
+		bucket = &metric{tags: r.metricTags(name+histogramBucketSuffix, bucketTags)}
+		h.buckets[upperBound] = bucket
+	}
+	bucket.value += float64(samples)
+	h.count.value += float64(samples)
+	h.dirty = true
+	r.Unlock()
+}
+
+// pendingHistogramsWithLock returns the cumulative buckets and count of each
+// histogram that has been reported since the last flush.
+func (r *reporter) pendingHistogramsWithLock() []*metric {
+	var pending []*metric
+	for _, h := range r.histograms {
+		if !h.dirty {
+			continue
+		}
+		h.dirty = false
+
+		upperBounds := make([]float64, 0, len(h.buckets))
+		for upperBound := range h.buckets {
+			upperBounds = append(upperBounds, upperBound)
+		}
+		sort.Float64s(upperBounds)
+
+		var cumulative float64
+		for _, upperBound := range upperBounds {
+			bucket := h.buckets[upperBound]
+			cumulative += bucket.value
+			pending = append(pending, &metric{tags: bucket.tags, value: cumulative})
+		}
+		pending = append(pending, &metric{tags: h.count.tags, value: h.count.value})
+	}
+	return pending
+}
+
+func (r *reporter) Capabilities() tally.Capabilities {
+	return r
+}
+
+func (r *reporter) Reporting() bool {
+	return true
+}
+
+func (r *reporter) Tagging() bool {
+	return true
+}
+
+func (r *reporter) Flush() {
+	r.Lock()
+	appender, pending := r.appender, append(r.pending, r.pendingHistogramsWithLock()...)
+	r.pending = nil
+	r.Unlock()
+
+	if appender == nil || len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWriteTimeout)
+	defer cancel()
+
+	var (
+		now       = r.nowFn()
+		numErrors int
+		lastErr   error
+		lastName  string
+	)
+	for _, m := range pending {
+		err := appender.Write(ctx, &storage.WriteQuery{
+			Tags:       m.tags,
+			Datapoints: ts.Datapoints{ts.Datapoint{Timestamp: now, Value: m.value}},
+			Unit:       xtime.Second,
+			Attributes: r.attributes,
+		})
+		if err != nil {
+			// NB: keep writing the remaining metrics, a metric failing to be
+			// written should not stop the others being written.
+			numErrors++
+			lastErr, lastName = err, m.tags[models.MetricName]
+		}
+	}
+
+	if numErrors > 0 {
+		logging.WithContext(ctx).Error("unable to write self telemetry",
+			zap.Int("numErrors", numErrors),
+			zap.Int("numMetrics", len(pending)),
+			zap.String("lastName", lastName),
+			zap.Error(lastErr))
+	}
+}
+
+func (r *reporter) metricTags(name string, tags map[string]string) models.Tags {
+	result := make(models.Tags, len(tags)+1)
+	for k, v := range tags {
+		result[sanitize(k)] = v
+	}
+	result[models.MetricName] = sanitize(r.prefix + name)
+	return result
+}
+
+// sanitize replaces the characters not valid in Prometheus metric and
+// label names, such as the separators of tally scopes.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == ':':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func metricKey(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteByte(',')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tags[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package selftelemetry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAppender struct {
+	writes []*storage.WriteQuery
+}
+
+func (a *testAppender) Write(_ context.Context, query *storage.WriteQuery) error {
+	a.writes = append(a.writes, query)
+	return nil
+}
+
+func TestReporterWritesCumulativeCounters(t *testing.T) {
+	now := time.Now()
+	attrs := storage.Attributes{
+		MetricsType: storage.AggregatedMetricsType,
+		Retention:   24 * time.Hour,
+		Resolution:  time.Minute,
+	}
+	r := NewReporter("coordinator_", attrs).(*reporter)
+	r.nowFn = func() time.Time { return now }
+
+	// Metrics reported before the appender is set are not written but
+	// counters still accumulate.
+	r.ReportCounter("fetch.errors", map[string]string{"type": "timeout"}, 2)
+	r.Flush()
+
+	appender := &testAppender{}
+	r.SetAppender(appender)
+
+	r.ReportCounter("fetch.errors", map[string]string{"type": "timeout"}, 3)
+	r.ReportGauge("goroutines", nil, 42)
+	r.Flush()
+
+	require.Equal(t, 2, len(appender.writes))
+	counter := appender.writes[0]
+	assert.Equal(t, models.Tags{
+		models.MetricName: "coordinator_fetch_errors",
+		"type":            "timeout",
+	}, counter.Tags)
+	require.Equal(t, 1, len(counter.Datapoints))
+	assert.Equal(t, float64(5), counter.Datapoints[0].Value)
+	assert.True(t, now.Equal(counter.Datapoints[0].Timestamp))
+	assert.Equal(t, attrs, counter.Attributes)
+
+	gauge := appender.writes[1]
+	assert.Equal(t, "coordinator_goroutines", gauge.Tags[models.MetricName])
+	assert.Equal(t, float64(42), gauge.Datapoints[0].Value)
+
+	// Nothing pending is not written.
+	r.Flush()
+	assert.Equal(t, 2, len(appender.writes))
+}
+
+func TestReporterWritesTimersAndHistograms(t *testing.T) {
+	r := NewReporter("", storage.Attributes{}).(*reporter)
+	appender := &testAppender{}
+	r.SetAppender(appender)
+
+	r.ReportTimer("latency", nil, time.Second)
+	r.ReportTimer("latency", nil, 500*time.Millisecond)
+	r.ReportHistogramValueSamples("size", nil, nil, 0, 10, 2)
+	r.ReportHistogramValueSamples("size", nil, nil, 10, 100, 3)
+	r.ReportHistogramValueSamples("size", nil, nil, 100, math.MaxFloat64, 1)
+	r.Flush()
+
+	values := make(map[string]float64)
+	for _, w := range appender.writes {
+		key := w.Tags[models.MetricName]
+		if le, ok := w.Tags["le"]; ok {
+			key += "{le=" + le + "}"
+		}
+		values[key] = w.Datapoints[0].Value
+	}
+	assert.Equal(t, float64(2), values["latency_count"])
+	assert.Equal(t, 1.5, values["latency_sum"])
+	assert.Equal(t, float64(2), values["size_bucket{le=10}"])
+	assert.Equal(t, float64(5), values["size_bucket{le=100}"])
+	assert.Equal(t, float64(6), values["size_bucket{le=+Inf}"])
+	assert.Equal(t, float64(6), values["size_count"])
+}
+
+type errAppender struct {
+	writes int
+}
+
+func (a *errAppender) Write(context.Context, *storage.WriteQuery) error {
+	a.writes++
+	return errors.New("unavailable")
+}
+
+func TestReporterFlushContinuesPastErrors(t *testing.T) {
+	r := NewReporter("", storage.Attributes{}).(*reporter)
+	appender := &errAppender{}
+	r.SetAppender(appender)
+
+	r.ReportGauge("a", nil, 1)
+	r.ReportGauge("b", nil, 2)
+	r.Flush()
+	assert.Equal(t, 2, appender.writes)
+}
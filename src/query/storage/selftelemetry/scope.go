@@ -0,0 +1,120 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package selftelemetry
+
+import (
+	"io"
+	"time"
+
+	"github.com/uber-go/tally"
+)
+
+// NewScope returns a scope that emits all metrics to scope, and also
+// writes them with the reporter every report interval.
+func NewScope(
+	scope tally.Scope,
+	reporter Reporter,
+	reportInterval time.Duration,
+) (tally.Scope, io.Closer) {
+	self, closer := tally.NewRootScope(tally.ScopeOptions{
+		Reporter: reporter,
+	}, reportInterval)
+	return &teeScope{Scope: scope, self: self}, closer
+}
+
+type teeScope struct {
+	tally.Scope
+
+	self tally.Scope
+}
+
+func (s *teeScope) Counter(name string) tally.Counter {
+	return teeCounter{s.Scope.Counter(name), s.self.Counter(name)}
+}
+
+func (s *teeScope) Gauge(name string) tally.Gauge {
+	return teeGauge{s.Scope.Gauge(name), s.self.Gauge(name)}
+}
+
+func (s *teeScope) Timer(name string) tally.Timer {
+	return teeTimer{s.Scope.Timer(name), s.self.Timer(name)}
+}
+
+func (s *teeScope) Histogram(name string, buckets tally.Buckets) tally.Histogram {
+	return teeHistogram{s.Scope.Histogram(name, buckets), s.self.Histogram(name, buckets)}
+}
+
+func (s *teeScope) Tagged(tags map[string]string) tally.Scope {
+	return &teeScope{Scope: s.Scope.Tagged(tags), self: s.self.Tagged(tags)}
+}
+
+func (s *teeScope) SubScope(name string) tally.Scope {
+	return &teeScope{Scope: s.Scope.SubScope(name), self: s.self.SubScope(name)}
+}
+
+type teeCounter [2]tally.Counter
+
+func (c teeCounter) Inc(delta int64) {
+	c[0].Inc(delta)
+	c[1].Inc(delta)
+}
+
+type teeGauge [2]tally.Gauge
+
+func (g teeGauge) Update(value float64) {
+	g[0].Update(value)
+	g[1].Update(value)
+}
+
+type teeTimer [2]tally.Timer
+
+func (t teeTimer) Record(value time.Duration) {
+	t[0].Record(value)
+	t[1].Record(value)
+}
+
+func (t teeTimer) Start() tally.Stopwatch {
+	return tally.NewStopwatch(time.Now(), t)
+}
+
+func (t teeTimer) RecordStopwatch(stopwatchStart time.Time) {
+	t.Record(time.Since(stopwatchStart))
+}
+
+type teeHistogram [2]tally.Histogram
+
+func (h teeHistogram) RecordValue(value float64) {
+	h[0].RecordValue(value)
+	h[1].RecordValue(value)
+}
+
+func (h teeHistogram) RecordDuration(value time.Duration) {
+	h[0].RecordDuration(value)
+	h[1].RecordDuration(value)
+}
+
+func (h teeHistogram) Start() tally.Stopwatch {
+	return tally.NewStopwatch(time.Now(), h)
+}
+
+func (h teeHistogram) RecordStopwatch(stopwatchStart time.Time) {
+	h.RecordDuration(time.Since(stopwatchStart))
+}
@@ -0,0 +1,464 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package verify provides a storage that verifies reads against a secondary
+// storage, e.g. to build confidence in a new cluster during a migration.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultMaxInFlight = 16
+)
+
+// Options are the options of a verifying storage.
+type Options struct {
+	// Percent is the percentage of fetches that are verified.
+	Percent float64
+
+	// Tolerance is the relative difference allowed between values of the
+	// primary and secondary storages.
+	Tolerance float64
+
+	// Timeout is the timeout of fetches from the secondary storage.
+	Timeout time.Duration
+
+	// MaxInFlight is the maximum number of concurrent verifications,
+	// fetches are not verified when it is reached.
+	MaxInFlight int
+
+	// Scope is the scope for the verification metrics.
+	Scope tally.Scope
+}
+
+type verifyMetrics struct {
+	verified       tally.Counter
+	skipped        tally.Counter
+	errors         tally.Counter
+	matched        tally.Counter
+	mismatched     tally.Counter
+	missingSeries  tally.Counter
+	extraSeries    tally.Counter
+	mismatchSeries tally.Counter
+}
+
+func newVerifyMetrics(scope tally.Scope) verifyMetrics {
+	return verifyMetrics{
+		verified:       scope.Counter("verified"),
+		skipped:        scope.Counter("skipped"),
+		errors:         scope.Counter("errors"),
+		matched:        scope.Counter("matched"),
+		mismatched:     scope.Counter("mismatched"),
+		missingSeries:  scope.Counter("missing-series"),
+		extraSeries:    scope.Counter("extra-series"),
+		mismatchSeries: scope.Counter("mismatch-series"),
+	}
+}
+
+type verifyStorage struct {
+	storage.Storage
+
+	secondary storage.Storage
+	percent   float64
+	tolerance float64
+	timeout   time.Duration
+	inFlight  chan struct{}
+	metrics   verifyMetrics
+	randFn    func() float64
+}
+
+// NewStorage returns a storage that serves reads and writes from the primary
+// storage and compares a percentage of fetches, including the block fetches
+// of PromQL queries, with the results of the secondary storage, differences
+// are logged and counted but never returned.
+func NewStorage(
+	primary storage.Storage,
+	secondary storage.Storage,
+	opts Options,
+) storage.Storage {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxInFlight := opts.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultMaxInFlight
+	}
+	scope := opts.Scope
+	if scope == nil {
+		scope = tally.NoopScope
+	}
+
+	return &verifyStorage{
+		Storage:   primary,
+		secondary: secondary,
+		percent:   opts.Percent,
+		tolerance: opts.Tolerance,
+		timeout:   timeout,
+		inFlight:  make(chan struct{}, maxInFlight),
+		metrics:   newVerifyMetrics(scope),
+		randFn:    rand.Float64,
+	}
+}
+
+func (s *verifyStorage) Fetch(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	secondaryCh := s.fetchSecondary(options, func(
+		ctx context.Context,
+		options *storage.FetchOptions,
+	) (seriesSummary, error) {
+		result, err := s.secondary.Fetch(ctx, query, options)
+		if err != nil {
+			return nil, err
+		}
+		return summarize(result.SeriesList), nil
+	})
+
+	result, err := s.Storage.Fetch(ctx, query, options)
+	if secondaryCh == nil {
+		return result, err
+	}
+	if err != nil {
+		s.release(secondaryCh)
+		return nil, err
+	}
+
+	// Summarize the primary result before returning it since callers may
+	// modify the series list.
+	s.verifyAsync(query, summarize(result.SeriesList), secondaryCh)
+	return result, nil
+}
+
+func (s *verifyStorage) FetchBlocks(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (block.Result, error) {
+	secondaryCh := s.fetchSecondary(options, func(
+		ctx context.Context,
+		options *storage.FetchOptions,
+	) (seriesSummary, error) {
+		result, err := s.secondary.FetchBlocks(ctx, query, options)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			for _, b := range result.Blocks {
+				b.Close()
+			}
+		}()
+		return summarizeBlocks(result.Blocks)
+	})
+
+	result, err := s.Storage.FetchBlocks(ctx, query, options)
+	if secondaryCh == nil {
+		return result, err
+	}
+	if err != nil {
+		s.release(secondaryCh)
+		return block.Result{}, err
+	}
+
+	// Summarize the primary result before returning it since callers close
+	// the blocks once they are done with them.
+	primary, err := summarizeBlocks(result.Blocks)
+	if err != nil {
+		s.metrics.errors.Inc(1)
+		s.release(secondaryCh)
+		return result, nil
+	}
+	s.verifyAsync(query, primary, secondaryCh)
+	return result, nil
+}
+
+// fetchSecondary reserves a verification slot and fetches from the secondary
+// storage, it returns nil if the fetch is not verified.
+func (s *verifyStorage) fetchSecondary(
+	options *storage.FetchOptions,
+	fetch func(context.Context, *storage.FetchOptions) (seriesSummary, error),
+) chan fetchResult {
+	if !s.sampled() {
+		return nil
+	}
+
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		s.metrics.skipped.Inc(1)
+		return nil
+	}
+
+	// NB: the secondary fetch is not tied to the request so that it is not
+	// cancelled once the primary result has been returned.
+	var secondaryOpts *storage.FetchOptions
+	if options != nil {
+		secondaryOpts = &storage.FetchOptions{Limit: options.Limit}
+	}
+	secondaryCh := make(chan fetchResult, 1)
+	go func() {
+		secondaryCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		summary, err := fetch(secondaryCtx, secondaryOpts)
+		secondaryCh <- fetchResult{summary: summary, err: err}
+	}()
+	return secondaryCh
+}
+
+// release releases the verification slot once the secondary fetch is done
+// when there is nothing to compare it against.
+func (s *verifyStorage) release(secondaryCh chan fetchResult) {
+	go func() {
+		<-secondaryCh
+		<-s.inFlight
+	}()
+}
+
+// verifyAsync compares the primary summary with the result of the secondary
+// fetch once it is done and releases the verification slot.
+func (s *verifyStorage) verifyAsync(
+	query *storage.FetchQuery,
+	primary seriesSummary,
+	secondaryCh chan fetchResult,
+) {
+	go func() {
+		defer func() { <-s.inFlight }()
+		secondary := <-secondaryCh
+		s.verify(query, primary, secondary)
+	}()
+}
+
+func (s *verifyStorage) sampled() bool {
+	if s.percent <= 0 {
+		return false
+	}
+	return s.percent >= 100 || s.randFn()*100 < s.percent
+}
+
+// seriesSummary holds the datapoints of each series of a result by ID.
+type seriesSummary map[string][]ts.Datapoint
+
+type fetchResult struct {
+	summary seriesSummary
+	err     error
+}
+
+func (s *verifyStorage) verify(
+	query *storage.FetchQuery,
+	primary seriesSummary,
+	secondary fetchResult,
+) {
+	logger := logging.WithContext(context.Background())
+	if secondary.err != nil {
+		s.metrics.errors.Inc(1)
+		logger.Debug("unable to fetch from secondary storage",
+			zap.String("query", query.String()), zap.Error(secondary.err))
+		return
+	}
+
+	s.metrics.verified.Inc(1)
+	diff := compare(primary, secondary.summary, s.tolerance)
+	if diff.empty() {
+		s.metrics.matched.Inc(1)
+		return
+	}
+
+	s.metrics.mismatched.Inc(1)
+	s.metrics.missingSeries.Inc(int64(len(diff.missing)))
+	s.metrics.extraSeries.Inc(int64(len(diff.extra)))
+	s.metrics.mismatchSeries.Inc(int64(len(diff.mismatch)))
+	logger.Warn("secondary storage fetch result differs",
+		zap.String("query", query.String()),
+		zap.Time("start", query.Start),
+		zap.Time("end", query.End),
+		zap.Strings("missing", diff.missing),
+		zap.Strings("extra", diff.extra),
+		zap.Strings("mismatch", diff.mismatch))
+}
+
+// Close closes both the primary and secondary storages.
+func (s *verifyStorage) Close() error {
+	secondaryErr := s.secondary.Close()
+	if err := s.Storage.Close(); err != nil {
+		return err
+	}
+	return secondaryErr
+}
+
+func summarize(seriesList ts.SeriesList) seriesSummary {
+	summary := make(seriesSummary, len(seriesList))
+	for _, series := range seriesList {
+		values := series.Values()
+		datapoints := make([]ts.Datapoint, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			dp := values.DatapointAt(i)
+			datapoints = append(datapoints, ts.Datapoint{
+				Timestamp: dp.Timestamp,
+				Value:     dp.Value,
+			})
+		}
+		summary[series.Tags.ID()] = datapoints
+	}
+	return summary
+}
+
+// summarizeBlocks summarizes the series of the blocks, the datapoints of a
+// series spanning several blocks are ordered by the start of the blocks.
+func summarizeBlocks(blocks []block.Block) (seriesSummary, error) {
+	iters := make([]block.SeriesIter, 0, len(blocks))
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+	}()
+	for _, b := range blocks {
+		iter, err := b.SeriesIter()
+		if err != nil {
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+	sort.Slice(iters, func(i, j int) bool {
+		return iters[i].Meta().Bounds.Start.Before(iters[j].Meta().Bounds.Start)
+	})
+
+	summary := make(seriesSummary)
+	for _, iter := range iters {
+		var (
+			meta       = iter.Meta()
+			seriesMeta = iter.SeriesMeta()
+		)
+		for i := 0; iter.Next(); i++ {
+			series, err := iter.Current()
+			if err != nil {
+				return nil, err
+			}
+			if i >= len(seriesMeta) {
+				return nil, fmt.Errorf("block has more series than series metadata: %d", len(seriesMeta))
+			}
+
+			tags := seriesMeta[i].Tags
+			if len(meta.Tags) > 0 {
+				tags = make(models.Tags, len(meta.Tags)+len(seriesMeta[i].Tags))
+				for k, v := range meta.Tags {
+					tags[k] = v
+				}
+				for k, v := range seriesMeta[i].Tags {
+					tags[k] = v
+				}
+			}
+
+			id := tags.ID()
+			datapoints := summary[id]
+			for step := 0; step < series.Len(); step++ {
+				t, err := meta.Bounds.TimeForIndex(step)
+				if err != nil {
+					return nil, err
+				}
+				datapoints = append(datapoints, ts.Datapoint{
+					Timestamp: t,
+					Value:     series.ValueAtStep(step),
+				})
+			}
+			summary[id] = datapoints
+		}
+	}
+	return summary, nil
+}
+
+// resultDiff holds the IDs of series that differ between results.
+type resultDiff struct {
+	missing  []string
+	extra    []string
+	mismatch []string
+}
+
+func (d resultDiff) empty() bool {
+	return len(d.missing) == 0 && len(d.extra) == 0 && len(d.mismatch) == 0
+}
+
+func compare(
+	primary seriesSummary,
+	secondary seriesSummary,
+	tolerance float64,
+) resultDiff {
+	var diff resultDiff
+	for id, datapoints := range primary {
+		other, ok := secondary[id]
+		if !ok {
+			diff.missing = append(diff.missing, id)
+			continue
+		}
+		if !datapointsEqual(datapoints, other, tolerance) {
+			diff.mismatch = append(diff.mismatch, id)
+		}
+	}
+	for id := range secondary {
+		if _, ok := primary[id]; !ok {
+			diff.extra = append(diff.extra, id)
+		}
+	}
+	sort.Strings(diff.missing)
+	sort.Strings(diff.extra)
+	sort.Strings(diff.mismatch)
+	return diff
+}
+
+func datapointsEqual(a, b []ts.Datapoint, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Timestamp.Equal(b[i].Timestamp) ||
+			!valuesEqual(a[i].Value, b[i].Value, tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func valuesEqual(a, b, tolerance float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type testStorage struct {
+	storage.Storage
+
+	result      *storage.FetchResult
+	blockResult block.Result
+	err         error
+}
+
+func (s *testStorage) Fetch(
+	context.Context, *storage.FetchQuery, *storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return s.result, s.err
+}
+
+func (s *testStorage) FetchBlocks(
+	context.Context, *storage.FetchQuery, *storage.FetchOptions,
+) (block.Result, error) {
+	return s.blockResult, s.err
+}
+
+func newTestBlockResult(values ...[]float64) block.Result {
+	bounds := block.Bounds{
+		Start:    time.Unix(0, 0),
+		Duration: time.Duration(len(values[0])) * time.Second,
+		StepSize: time.Second,
+	}
+	return block.Result{
+		Blocks: []block.Block{test.NewBlockFromValues(bounds, values)},
+	}
+}
+
+func newTestResult(values map[string][]float64) *storage.FetchResult {
+	start := time.Unix(0, 0)
+	result := &storage.FetchResult{}
+	for name, vals := range values {
+		datapoints := make(ts.Datapoints, 0, len(vals))
+		for i, v := range vals {
+			datapoints = append(datapoints, ts.Datapoint{
+				Timestamp: start.Add(time.Duration(i) * time.Second),
+				Value:     v,
+			})
+		}
+		result.SeriesList = append(result.SeriesList, ts.NewSeries(name,
+			datapoints, models.Tags{models.MetricName: name}))
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	primary := summarize(newTestResult(map[string][]float64{
+		"a": {1, 2, math.NaN()},
+		"b": {100},
+		"c": {1},
+	}).SeriesList)
+	secondary := summarize(newTestResult(map[string][]float64{
+		"a": {1, 2, math.NaN()},
+		"b": {101},
+		"d": {1},
+	}).SeriesList)
+
+	diff := compare(primary, secondary, 0.001)
+	assert.Equal(t, []string{models.Tags{models.MetricName: "c"}.ID()}, diff.missing)
+	assert.Equal(t, []string{models.Tags{models.MetricName: "d"}.ID()}, diff.extra)
+	assert.Equal(t, []string{models.Tags{models.MetricName: "b"}.ID()}, diff.mismatch)
+
+	diff = compare(primary, secondary, 0.1)
+	assert.Empty(t, diff.mismatch)
+}
+
+func TestVerifyStorageReturnsPrimaryResult(t *testing.T) {
+	primary := &testStorage{result: newTestResult(map[string][]float64{"a": {1}})}
+	secondary := &testStorage{result: newTestResult(map[string][]float64{"a": {2}})}
+	scope := tally.NewTestScope("", nil)
+	s := NewStorage(primary, secondary, Options{
+		Percent:     100,
+		MaxInFlight: 1,
+		Scope:       scope,
+	}).(*verifyStorage)
+
+	result, err := s.Fetch(context.Background(), &storage.FetchQuery{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary.result, result)
+
+	// Wait for the verification to release its in flight slot.
+	s.inFlight <- struct{}{}
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["mismatched+"].Value())
+	assert.Equal(t, int64(1), counters["mismatch-series+"].Value())
+}
+
+func TestVerifyStorageSecondaryError(t *testing.T) {
+	primary := &testStorage{result: newTestResult(map[string][]float64{"a": {1}})}
+	secondary := &testStorage{err: errors.New("unavailable")}
+	scope := tally.NewTestScope("", nil)
+	s := NewStorage(primary, secondary, Options{
+		Percent:     100,
+		MaxInFlight: 1,
+		Scope:       scope,
+	}).(*verifyStorage)
+
+	result, err := s.Fetch(context.Background(), &storage.FetchQuery{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary.result, result)
+
+	s.inFlight <- struct{}{}
+	assert.Equal(t, int64(1), scope.Snapshot().Counters()["errors+"].Value())
+}
+
+func TestVerifyStorageFetchBlocks(t *testing.T) {
+	primary := &testStorage{blockResult: newTestBlockResult([]float64{1, 2}, []float64{3, 4})}
+	secondary := &testStorage{blockResult: newTestBlockResult([]float64{1, 2}, []float64{3, 5})}
+	scope := tally.NewTestScope("", nil)
+	s := NewStorage(primary, secondary, Options{
+		Percent:     100,
+		MaxInFlight: 1,
+		Scope:       scope,
+	}).(*verifyStorage)
+
+	result, err := s.FetchBlocks(context.Background(), &storage.FetchQuery{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary.blockResult, result)
+
+	s.inFlight <- struct{}{}
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["mismatched+"].Value())
+	assert.Equal(t, int64(1), counters["mismatch-series+"].Value())
+}
@@ -147,6 +147,18 @@ func (s *AsyncSession) FetchTagged(namespace ident.ID, q index.Query, opts index
 	return s.session.FetchTagged(namespace, q, opts)
 }
 
+// FetchTaggedWithMetadata resolves the provided query to known IDs, and fetches the data for them,
+// returning metadata describing how complete the results are.
+func (s *AsyncSession) FetchTaggedWithMetadata(namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, client.FetchTaggedResultMetadata, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, client.FetchTaggedResultMetadata{}, s.err
+	}
+
+	return s.session.FetchTaggedWithMetadata(namespace, q, opts)
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (client.TaggedIDsIterator, bool, error) {
 	s.RLock()
@@ -158,17 +170,28 @@ func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts in
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
-// ShardID returns the given shard for an ID for callers
+// FetchTaggedCount resolves the provided query to the number of matching IDs.
+func (s *AsyncSession) FetchTaggedCount(namespace ident.ID, q index.Query, opts index.QueryOptions) (int, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return 0, false, s.err
+	}
+
+	return s.session.FetchTaggedCount(namespace, q, opts)
+}
+
+// ShardID returns the given shard for an ID of a namespace for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing
-func (s *AsyncSession) ShardID(id ident.ID) (uint32, error) {
+func (s *AsyncSession) ShardID(namespace, id ident.ID) (uint32, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if s.err != nil {
 		return 0, s.err
 	}
 
-	return s.session.ShardID(id)
+	return s.session.ShardID(namespace, id)
 }
 
 // IteratorPools exposes the internal iterator pools used by the session to clients
@@ -181,6 +204,29 @@ func (s *AsyncSession) IteratorPools() (encoding.IteratorPools, error) {
 	return s.session.IteratorPools()
 }
 
+// Backpressure returns the highest backpressure of the queues to the hosts
+func (s *AsyncSession) Backpressure() (float64, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.session.Backpressure()
+}
+
+// WithRequestMetadata returns a view of the session that sends the request
+// metadata with each write and fetch, the session itself is returned while
+// uninitialized so that calls return the uninitialized error.
+func (s *AsyncSession) WithRequestMetadata(requestMetadata string) client.Session {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return s
+	}
+
+	return s.session.WithRequestMetadata(requestMetadata)
+}
+
 // Close closes the session
 func (s *AsyncSession) Close() error {
 	s.RLock()
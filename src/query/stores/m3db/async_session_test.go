@@ -106,7 +106,7 @@ func TestAsyncSessionUninitialized(t *testing.T) {
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.Equal(t, err, errSessionUninitialized)
 
-	id, err := asyncSession.ShardID(nil)
+	id, err := asyncSession.ShardID(nil, nil)
 	assert.Equal(t, uint32(0), id)
 	assert.Equal(t, err, errSessionUninitialized)
 
@@ -154,8 +154,8 @@ func TestAsyncSessionInitialized(t *testing.T) {
 	_, _, err = asyncSession.FetchTaggedIDs(namespace, index.Query{}, index.QueryOptions{})
 	assert.NoError(t, err)
 
-	mockSession.EXPECT().ShardID(gomock.Any()).Return(uint32(0), nil)
-	_, err = asyncSession.ShardID(nil)
+	mockSession.EXPECT().ShardID(gomock.Any(), gomock.Any()).Return(uint32(0), nil)
+	_, err = asyncSession.ShardID(nil, nil)
 	assert.NoError(t, err)
 
 	mockSession.EXPECT().Close().Return(nil)
@@ -49,6 +49,11 @@ func (s *slowStorage) FetchTags(ctx context.Context, query *storage.FetchQuery,
 	return s.storage.FetchTags(ctx, query, options)
 }
 
+func (s *slowStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.CountResult, error) {
+	time.Sleep(s.delay)
+	return s.storage.FetchCount(ctx, query, options)
+}
+
 func (s *slowStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	time.Sleep(s.delay)
 	return s.storage.Write(ctx, query)
@@ -55,13 +55,18 @@ func (b blockReplicas) Less(i, j int) bool {
 }
 
 // ConvertM3DBSeriesIterators converts m3db SeriesIterators to SeriesBlocks
-// which are used to construct Blocks for query processing.
-func ConvertM3DBSeriesIterators(iterators encoding.SeriesIterators, iterAlloc encoding.ReaderIteratorAllocate) ([]SeriesBlocks, error) {
+// which are used to construct Blocks for query processing. Replicas read with
+// a codec other than the default are read with the codec from the registry.
+func ConvertM3DBSeriesIterators(
+	iterators encoding.SeriesIterators,
+	iterAlloc encoding.ReaderIteratorAllocate,
+	codecs encoding.CodecRegistry,
+) ([]SeriesBlocks, error) {
 	defer iterators.Close()
 	multiSeriesBlocks := make([]SeriesBlocks, iterators.Len())
 
 	for i, seriesIterator := range iterators.Iters() {
-		blockReplicas, err := blockReplicasFromSeriesIterator(seriesIterator, iterAlloc)
+		blockReplicas, err := blockReplicasFromSeriesIterator(seriesIterator, iterAlloc, codecs)
 		if err != nil {
 			return nil, err
 		}
@@ -77,9 +82,18 @@ func ConvertM3DBSeriesIterators(iterators encoding.SeriesIterators, iterAlloc en
 	return multiSeriesBlocks, nil
 }
 
-func blockReplicasFromSeriesIterator(seriesIterator encoding.SeriesIterator, iterAlloc encoding.ReaderIteratorAllocate) ([]blockReplica, error) {
+func blockReplicasFromSeriesIterator(
+	seriesIterator encoding.SeriesIterator,
+	iterAlloc encoding.ReaderIteratorAllocate,
+	codecs encoding.CodecRegistry,
+) ([]blockReplica, error) {
 	blockReplicas := make(blockReplicas, 0, initBlockReplicaLength)
 	for _, replica := range seriesIterator.Replicas() {
+		replicaIterAlloc, err := encoding.CodecReaderIteratorAllocate(codecs,
+			replica.Codec(), iterAlloc, encoding.NewOptions())
+		if err != nil {
+			return nil, err
+		}
 		perBlockSliceReaders := replica.Readers()
 		for next := true; next; next = perBlockSliceReaders.Next() {
 			l, start, bs := perBlockSliceReaders.CurrentReaders()
@@ -95,7 +109,7 @@ func blockReplicasFromSeriesIterator(seriesIterator encoding.SeriesIterator, ite
 				readers[i] = clonedReader
 			}
 			// todo(braskin): pooling
-			iter := encoding.NewMultiReaderIterator(iterAlloc, nil)
+			iter := encoding.NewMultiReaderIterator(replicaIterAlloc, nil)
 			iter.Reset(readers, start, bs)
 
 			inserted := false
@@ -55,7 +55,7 @@ func TestConversion(t *testing.T) {
 	require.NoError(t, err)
 	iterators := encoding.NewSeriesIterators([]encoding.SeriesIterator{iter}, nil)
 
-	blocks, err := ConvertM3DBSeriesIterators(iterators, testIterAlloc)
+	blocks, err := ConvertM3DBSeriesIterators(iterators, testIterAlloc, nil)
 	require.NoError(t, err)
 
 	for _, block := range blocks {
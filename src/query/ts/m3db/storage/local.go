@@ -37,6 +37,7 @@ import (
 type localStorage struct {
 	clusters   local.Clusters
 	workerPool pool.ObjectPool
+	codecs     encoding.CodecRegistry
 }
 
 var (
@@ -48,8 +49,16 @@ var (
 )
 
 // nolint: deadcode
-func newStorage(clusters local.Clusters, workerPool pool.ObjectPool) *localStorage {
-	return &localStorage{clusters: clusters, workerPool: workerPool}
+func newStorage(clusters local.Clusters, workerPool pool.ObjectPool) (*localStorage, error) {
+	codecs, err := m3tsz.NewCodecRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return &localStorage{
+		clusters:   clusters,
+		workerPool: workerPool,
+		codecs:     codecs,
+	}, nil
 }
 
 // nolint: unparam
@@ -85,7 +94,7 @@ func (s *localStorage) fetchBlocks(
 		return emptySeriesMap, err
 	}
 
-	seriesBlockList, err := m3block.ConvertM3DBSeriesIterators(seriesIters, iterAlloc)
+	seriesBlockList, err := m3block.ConvertM3DBSeriesIterators(seriesIters, iterAlloc, s.codecs)
 	if err != nil {
 		return emptySeriesMap, err
 	}
@@ -66,7 +66,8 @@ func setup(
 		Resolution:  time.Minute,
 	})
 	require.NoError(t, err)
-	storage := newStorage(clusters, nil)
+	storage, err := newStorage(clusters, nil)
+	require.NoError(t, err)
 	return storage, testSessions{
 		unaggregated1MonthRetention:                unaggregated1MonthRetention,
 		aggregated1MonthRetention1MinuteResolution: aggregated1MonthRetention1MinuteResolution,
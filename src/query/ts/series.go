@@ -54,9 +54,10 @@ func (s *Series) Len() int { return s.vals.Len() }
 // Values returns the underlying values interface
 func (s *Series) Values() Values { return s.vals }
 
-// Align adjusts the datapoints to start, end and a fixed interval
-func (s *Series) Align(start, end time.Time, interval time.Duration) (*Series, error) {
-	fixedVals, err := alignValues(s.Values(), start, end, interval)
+// Align adjusts the datapoints to start, end and a fixed interval, carrying
+// datapoints forward at most the lookback duration if set
+func (s *Series) Align(start, end time.Time, interval, lookback time.Duration) (*Series, error) {
+	fixedVals, err := alignValues(s.Values(), start, end, interval, lookback)
 	if err != nil {
 		return nil, err
 	}
@@ -64,10 +65,10 @@ func (s *Series) Align(start, end time.Time, interval time.Duration) (*Series, e
 	return NewSeries(s.name, fixedVals, s.Tags), nil
 }
 
-func alignValues(values Values, start, end time.Time, interval time.Duration) (FixedResolutionMutableValues, error) {
+func alignValues(values Values, start, end time.Time, interval, lookback time.Duration) (FixedResolutionMutableValues, error) {
 	switch vals := values.(type) {
 	case Datapoints:
-		return RawPointsToFixedStep(vals, start, end, interval)
+		return RawPointsToFixedStep(vals, start, end, interval, lookback)
 	case FixedResolutionMutableValues:
 		// TODO: Align fixed resolution as well once storages can return those directly
 		return vals, nil
@@ -100,11 +101,12 @@ func (seriesList SeriesList) Resolution() (time.Duration, error) {
 	return resolution, nil
 }
 
-// Align aligns each series to the given start, end and step.
-func (seriesList SeriesList) Align(start, end time.Time, interval time.Duration) (SeriesList, error) {
+// Align aligns each series to the given start, end and step, carrying
+// datapoints forward at most the lookback duration if set.
+func (seriesList SeriesList) Align(start, end time.Time, interval, lookback time.Duration) (SeriesList, error) {
 	alignedList := make(SeriesList, len(seriesList))
 	for i, s := range seriesList {
-		alignedSeries, err := s.Align(start, end, interval)
+		alignedSeries, err := s.Align(start, end, interval, lookback)
 		if err != nil {
 			return nil, err
 		}
@@ -28,6 +28,20 @@ import (
 	"github.com/m3db/m3/src/query/errors"
 )
 
+const (
+	// StaleNaNBits is the bit pattern of the NaN Prometheus writes as a
+	// staleness marker once a series stops being reported.
+	StaleNaNBits uint64 = 0x7ff0000000000002
+)
+
+// StaleNaN is the staleness marker value.
+var StaleNaN = math.Float64frombits(StaleNaNBits)
+
+// IsStaleNaN returns whether a value is a staleness marker.
+func IsStaleNaN(v float64) bool {
+	return math.Float64bits(v) == StaleNaNBits
+}
+
 // Values holds the values for a timeseries.  It provides a minimal interface
 // for storing and retrieving values in the series, with Series providing a
 // more convenient interface for applications to build on top of.  Values
@@ -50,6 +64,9 @@ type Values interface {
 type Datapoint struct {
 	Timestamp time.Time
 	Value     float64
+	// Annotation is the optional annotation written with the value, it is
+	// only set for raw datapoints read directly from storage.
+	Annotation []byte
 }
 
 // Datapoints is a list of datapoints.
@@ -143,7 +160,9 @@ func newFixedStepValues(millisPerStep time.Duration, numSteps int, initialValue
 }
 
 // RawPointsToFixedStep converts raw datapoints into the interval required within the bounds specified. For every time step, it finds the closest point.
-func RawPointsToFixedStep(datapoints Datapoints, start time.Time, end time.Time, interval time.Duration) (FixedResolutionMutableValues, error) {
+// If the lookback duration is set previous datapoints are only carried forward within it, otherwise they are carried forward indefinitely.
+// Staleness markers carry forward as NaN so that series which stop being reported disappear rather than repeating their last value.
+func RawPointsToFixedStep(datapoints Datapoints, start time.Time, end time.Time, interval time.Duration, lookback time.Duration) (FixedResolutionMutableValues, error) {
 	if end.Before(start) {
 		return nil, fmt.Errorf("start cannot be after end, start: %v, end: %v", start, end)
 	}
@@ -179,8 +198,8 @@ func RawPointsToFixedStep(datapoints Datapoints, start time.Time, end time.Time,
 		// If datapoint aligns to the time or its the first datapoint then take that
 		if datapoints.DatapointAt(dpIdx).Timestamp == t || dpIdx == 0 {
 			fixStepValues.values[fixedResIdx] = datapoints.ValueAt(dpIdx)
-		} else {
-			fixStepValues.values[fixedResIdx] = datapoints.ValueAt(dpIdx - 1)
+		} else if prev := datapoints.DatapointAt(dpIdx - 1); lookback <= 0 || t.Sub(prev.Timestamp) <= lookback {
+			fixStepValues.values[fixedResIdx] = prev.Value
 		}
 
 		fixedResIdx++
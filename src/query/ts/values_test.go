@@ -16,6 +16,7 @@ type testSample struct {
 	start       time.Time
 	end         time.Time
 	interval    time.Duration
+	lookback    time.Duration
 	hasNans     bool
 	description string
 }
@@ -85,6 +86,43 @@ func createExamples() []testSample {
 			hasNans:     true,
 			description: "first datapoint after start",
 		},
+		{
+			input: Datapoints{
+				{Timestamp: now, Value: 1},
+				{Timestamp: now.Add(10 * time.Minute), Value: 2},
+			},
+			expected:    []float64{1, 1, 1, math.NaN(), math.NaN(), 2},
+			start:       now,
+			end:         now.Add(12 * time.Minute),
+			interval:    2 * time.Minute,
+			lookback:    5 * time.Minute,
+			hasNans:     true,
+			description: "previous datapoint is only used within the lookback duration",
+		},
+		{
+			input: Datapoints{
+				{Timestamp: now, Value: 1},
+				{Timestamp: now.Add(10 * time.Minute), Value: 2},
+			},
+			expected:    []float64{1, 1, 1, 1, 1, 2},
+			start:       now,
+			end:         now.Add(12 * time.Minute),
+			interval:    2 * time.Minute,
+			description: "previous datapoint is carried forward without a lookback duration",
+		},
+		{
+			input: Datapoints{
+				{Timestamp: now, Value: 1},
+				{Timestamp: now.Add(time.Minute), Value: StaleNaN},
+				{Timestamp: now.Add(10 * time.Minute), Value: 2},
+			},
+			expected:    []float64{1, math.NaN(), math.NaN(), math.NaN()},
+			start:       now,
+			end:         now.Add(8 * time.Minute),
+			interval:    2 * time.Minute,
+			hasNans:     true,
+			description: "staleness markers end the series",
+		},
 	}
 	return samples
 }
@@ -92,7 +130,7 @@ func createExamples() []testSample {
 func TestRawPointsToFixedStep(t *testing.T) {
 	samples := createExamples()
 	for idx, sample := range samples {
-		fixdRes, err := RawPointsToFixedStep(sample.input, sample.start, sample.end, sample.interval)
+		fixdRes, err := RawPointsToFixedStep(sample.input, sample.start, sample.end, sample.interval, sample.lookback)
 		require.NoError(t, err)
 		if !sample.hasNans {
 			assert.Equal(t, fixdRes.(*fixedResolutionValues).values, sample.expected, "Datapoints: %s, description: %s", sample.input, sample.description)
@@ -112,3 +150,10 @@ func TestRawPointsToFixedStep(t *testing.T) {
 		}
 	}
 }
+
+func TestIsStaleNaN(t *testing.T) {
+	assert.True(t, IsStaleNaN(StaleNaN))
+	assert.True(t, math.IsNaN(StaleNaN))
+	assert.False(t, IsStaleNaN(math.NaN()))
+	assert.False(t, IsStaleNaN(1.0))
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"io"
+	"time"
+
+	rpc "github.com/m3db/m3/src/query/generated/proto/rpcpb"
+	"github.com/m3db/m3/src/query/storage/ingest"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBatchAckInterval   = 100 * time.Millisecond
+	defaultBatchAckMaxPending = 128
+)
+
+// WriteBatches writes the batches pushed on a long lived stream to local
+// storage, acknowledging the status of each batch periodically or once
+// enough batch statuses are pending.
+func (s *grpcServer) WriteBatches(stream rpc.BatchWrite_WriteBatchesServer) error {
+	var (
+		ctx      = stream.Context()
+		logger   = logging.WithContext(ctx)
+		statusCh = make(chan *rpc.WriteBatchStatus, s.batchAckMaxPending)
+		errCh    = make(chan error, 1)
+		doneCh   = make(chan struct{})
+	)
+	defer close(doneCh)
+
+	go func() {
+		defer close(statusCh)
+		for {
+			batch, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			select {
+			case statusCh <- s.writeBatch(ctx, batch):
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(s.batchAckInterval)
+	defer ticker.Stop()
+
+	pending := make([]*rpc.WriteBatchStatus, 0, s.batchAckMaxPending)
+	ack := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		err := stream.Send(&rpc.WriteBatchAck{Statuses: pending})
+		pending = pending[:0]
+		return err
+	}
+
+	for {
+		select {
+		case status, ok := <-statusCh:
+			if !ok {
+				select {
+				case err := <-errCh:
+					logger.Error("unable to receive write batch", zap.Any("error", err))
+					return err
+				default:
+				}
+				return ack()
+			}
+
+			pending = append(pending, status)
+			if len(pending) < s.batchAckMaxPending {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		if err := ack(); err != nil {
+			logger.Error("unable to send write batch ack", zap.Any("error", err))
+			return err
+		}
+	}
+}
+
+func (s *grpcServer) writeBatch(
+	ctx context.Context,
+	batch *rpc.WriteBatch,
+) *rpc.WriteBatchStatus {
+	status := &rpc.WriteBatchStatus{Sequence: batch.GetSequence()}
+	// NB: the key is reserved before writing so that concurrent retries of
+	// the same batch wait for the write in flight rather than writing too.
+	var (
+		key     = batch.GetIdempotencyKey()
+		applied ingest.AppliedWrites
+	)
+	if key != "" {
+		var (
+			reserved bool
+			err      error
+		)
+		applied, reserved, err = s.idempotency.Reserve(ctx, key)
+		if err != nil {
+			// None of the batch was written.
+			for i := range batch.GetQueries() {
+				status.Errors = append(status.Errors, &rpc.WriteBatchError{
+					Index: uint32(i),
+					Error: err.Error(),
+				})
+			}
+			return status
+		}
+		if !reserved {
+			// Already applied, acknowledge the retry without writing it again.
+			status.Duplicate = true
+			return status
+		}
+		if applied == nil {
+			applied = make(ingest.AppliedWrites)
+		}
+	}
+
+	for i, query := range batch.GetQueries() {
+		write := ingest.AppliedWrite{Series: i}
+		if applied.Contains(write) {
+			// Applied by a previous attempt of the batch.
+			status.Written++
+			continue
+		}
+		if err := s.storage.Write(ctx, decodeWriteQuery(query)); err != nil {
+			status.Errors = append(status.Errors, &rpc.WriteBatchError{
+				Index: uint32(i),
+				Error: err.Error(),
+			})
+			continue
+		}
+		if applied != nil {
+			applied[write] = struct{}{}
+		}
+		status.Written++
+	}
+
+	// NB: the writes of a batch that partially failed are remembered so that
+	// a retry of the batch only writes the queries that failed.
+	if key != "" {
+		if len(status.Errors) == 0 {
+			s.idempotency.Record(key)
+		} else {
+			s.idempotency.Release(key, applied)
+		}
+	}
+	return status
+}
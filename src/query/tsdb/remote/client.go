@@ -70,6 +70,11 @@ func NewGrpcClient(addresses []string, additionalDialOpts ...grpc.DialOption) (C
 
 // Fetch reads from remote client storage
 func (c *grpcClient) Fetch(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.FetchResult, error) {
+	// NB: filters are not encoded in fetch messages so cannot be applied remotely.
+	if query.Filter != nil {
+		return nil, errors.ErrFilterUnsupported
+	}
+
 	// Send the id from the client to the remote server so that provides logging
 	id := logging.ReadContextID(ctx)
 	fetchClient, err := c.client.Fetch(ctx, EncodeFetchMessage(query, id))
@@ -109,6 +114,10 @@ func (c *grpcClient) FetchTags(ctx context.Context, query *storage.FetchQuery, o
 	return nil, nil
 }
 
+func (c *grpcClient) FetchCount(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.CountResult, error) {
+	return nil, errors.ErrNotImplemented
+}
+
 // Write writes to remote client storage
 func (c *grpcClient) Write(ctx context.Context, query *storage.WriteQuery) error {
 	client := c.client
@@ -48,12 +48,16 @@ func initializeVars() {
 	iterAlloc = func(r io.Reader) encoding.ReaderIterator {
 		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
 	}
+
+	codecRegistry, codecRegistryErr = m3tsz.NewCodecRegistry()
 }
 
 var (
-	opts       checked.BytesOptions
-	iterAlloc  func(r io.Reader) encoding.ReaderIterator
-	initialize sync.Once
+	opts             checked.BytesOptions
+	iterAlloc        func(r io.Reader) encoding.ReaderIterator
+	codecRegistry    encoding.CodecRegistry
+	codecRegistryErr error
+	initialize       sync.Once
 )
 
 func compressedSegmentFromBlockReader(br xio.BlockReader) (*rpc.Segment, error) {
@@ -162,6 +166,7 @@ func compressedSeriesFromSeriesIterator(it encoding.SeriesIterator, iterPools en
 			if err != nil {
 				return nil, err
 			}
+			segments.Encoding = replica.Codec()
 			replicaSegments = append(replicaSegments, segments)
 		}
 		compressedReplicas = append(compressedReplicas, &rpc.CompressedValuesReplica{
@@ -326,6 +331,34 @@ func blockReadersFromCompressedSegments(
 	return blockReaders
 }
 
+// replicaIteratorAllocate returns the reader iterator allocate function for
+// the codec the segments were encoded with, iterators for codecs other than
+// the default are not returned to the default pool.
+func replicaIteratorAllocate(
+	segments []*rpc.Segments,
+	multiReaderPool encoding.MultiReaderIteratorPool,
+) (encoding.ReaderIteratorAllocate, encoding.MultiReaderIteratorPool, error) {
+	var codecName string
+	for _, s := range segments {
+		if s.GetEncoding() != "" {
+			codecName = s.GetEncoding()
+			break
+		}
+	}
+	if codecName == "" {
+		return iterAlloc, multiReaderPool, nil
+	}
+	if codecRegistryErr != nil {
+		return nil, nil, codecRegistryErr
+	}
+	alloc, err := encoding.CodecReaderIteratorAllocate(codecRegistry, codecName,
+		iterAlloc, encoding.NewOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+	return alloc, nil, nil
+}
+
 /*
 Creates a SeriesIterator from a compressed protobuf. This is the reverse of
 CompressedSeriesFromSeriesIterator, and takes an optional iteratorPool
@@ -369,11 +402,17 @@ func seriesIteratorFromCompressedSeries(
 	}
 
 	for _, replica := range replicas {
-		blockReaders := blockReadersFromCompressedSegments(replica.GetSegments(), checkedBytesWrapperPool)
+		segments := replica.GetSegments()
+		blockReaders := blockReadersFromCompressedSegments(segments, checkedBytesWrapperPool)
+
+		replicaIterAlloc, replicaPool, err := replicaIteratorAllocate(segments, multiReaderPool)
+		if err != nil {
+			return nil, err
+		}
 
 		// TODO arnikola investigate pooling these?
 		sliceOfSlicesIterator := xio.NewReaderSliceOfSlicesFromBlockReadersIterator(blockReaders)
-		perReplicaIterator := encoding.NewMultiReaderIterator(iterAlloc, multiReaderPool)
+		perReplicaIterator := encoding.NewMultiReaderIterator(replicaIterAlloc, replicaPool)
 		perReplicaIterator.ResetSliceOfSlices(sliceOfSlicesIterator)
 
 		allReplicaIterators = append(allReplicaIterators, perReplicaIterator)
@@ -370,6 +370,28 @@ func TestDecodeCompressedFetchResultWithIteratorPool(t *testing.T) {
 	assert.True(t, ip.msiPoolUsed)
 }
 
+func TestReplicaIteratorAllocateUsesSegmentsEncoding(t *testing.T) {
+	initialize.Do(initializeVars)
+	multiReaderPool := encoding.NewMultiReaderIteratorPool(nil)
+	multiReaderPool.Init(testIterAlloc)
+
+	_, p, err := replicaIteratorAllocate([]*rpc.Segments{{}}, multiReaderPool)
+	require.NoError(t, err)
+	assert.True(t, p == multiReaderPool)
+
+	alloc, p, err := replicaIteratorAllocate([]*rpc.Segments{
+		{Encoding: m3tsz.IntDeltaCodecName},
+	}, multiReaderPool)
+	require.NoError(t, err)
+	require.NotNil(t, alloc)
+	assert.Nil(t, p)
+
+	_, _, err = replicaIteratorAllocate([]*rpc.Segments{
+		{Encoding: "unknown"},
+	}, multiReaderPool)
+	require.Error(t, err)
+}
+
 // NB: make sure that SeriesIterator is not closed during conversion, or bytes will be empty
 func TestConversionDoesNotCloseSeriesIterator(t *testing.T) {
 	ctrl := gomock.NewController(t)
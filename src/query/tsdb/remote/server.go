@@ -23,9 +23,11 @@ package remote
 import (
 	"io"
 	"net"
+	"time"
 
 	rpc "github.com/m3db/m3/src/query/generated/proto/rpcpb"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingest"
 	"github.com/m3db/m3/src/query/util/logging"
 
 	"go.uber.org/zap"
@@ -33,20 +35,28 @@ import (
 )
 
 type grpcServer struct {
-	storage storage.Storage
+	storage            storage.Storage
+	idempotency        ingest.IdempotencyCache
+	batchAckInterval   time.Duration
+	batchAckMaxPending int
 }
 
-func newServer(store storage.Storage) *grpcServer {
+func newServer(store storage.Storage, idempotency ingest.IdempotencyCache) *grpcServer {
 	return &grpcServer{
-		storage: store,
+		storage:            store,
+		idempotency:        idempotency,
+		batchAckInterval:   defaultBatchAckInterval,
+		batchAckMaxPending: defaultBatchAckMaxPending,
 	}
 }
 
-// CreateNewGrpcServer creates server, given context local storage
-func CreateNewGrpcServer(store storage.Storage) *grpc.Server {
+// CreateNewGrpcServer creates server, given context local storage and the
+// cache that write batches are deduplicated by their idempotency keys with
+func CreateNewGrpcServer(store storage.Storage, idempotency ingest.IdempotencyCache) *grpc.Server {
 	server := grpc.NewServer()
-	grpcServer := newServer(store)
+	grpcServer := newServer(store, idempotency)
 	rpc.RegisterQueryServer(server, grpcServer)
+	rpc.RegisterBatchWriteServer(server, grpcServer)
 
 	return server
 }
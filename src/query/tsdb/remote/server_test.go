@@ -24,14 +24,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/query/block"
 	m3err "github.com/m3db/m3/src/query/errors"
+	rpc "github.com/m3db/m3/src/query/generated/proto/rpcpb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingest"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 
@@ -107,6 +110,10 @@ func (s *mockStorage) FetchTags(ctx context.Context, query *storage.FetchQuery,
 	return nil, nil
 }
 
+func (s *mockStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.CountResult, error) {
+	return nil, nil
+}
+
 func (s *mockStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	writeQueriesAreEqual(s.t, s.write, query)
 	return nil
@@ -140,7 +147,7 @@ func checkRemoteFetch(t *testing.T, res *storage.FetchResult) {
 }
 
 func startServer(t *testing.T, host string, store storage.Storage) {
-	server := CreateNewGrpcServer(store)
+	server := CreateNewGrpcServer(store, ingest.NewIdempotencyCache(0, 0, nil))
 	waitForStart := make(chan struct{})
 	go func() {
 		err := StartNewGrpcServer(server, host, waitForStart)
@@ -208,6 +215,96 @@ func TestRpc(t *testing.T) {
 	checkFetch(ctx, t, client, read, readOpts)
 }
 
+func writeBatches(
+	ctx context.Context,
+	t *testing.T,
+	host string,
+	write *storage.WriteQuery,
+	numBatches int,
+	idempotencyKey string,
+) []*rpc.WriteBatchStatus {
+	conn, err := grpc.Dial(host, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := rpc.NewBatchWriteClient(conn).WriteBatches(ctx)
+	require.NoError(t, err)
+	for i := 0; i < numBatches; i++ {
+		require.NoError(t, stream.Send(&rpc.WriteBatch{
+			Sequence:       uint64(i),
+			Queries:        []*rpc.WriteQuery{encodeWriteQuery(write), encodeWriteQuery(write)},
+			IdempotencyKey: idempotencyKey,
+		}))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var statuses []*rpc.WriteBatchStatus
+	for {
+		ack, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		statuses = append(statuses, ack.GetStatuses()...)
+	}
+	return statuses
+}
+
+func TestRpcWriteBatches(t *testing.T) {
+	ctx, read, write, _, host := createCtxReadWriteOpts(t)
+	store := &mockStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+
+	statuses := writeBatches(ctx, t, host, write, 3, "")
+	require.Len(t, statuses, 3)
+	for i, status := range statuses {
+		assert.Equal(t, uint64(i), status.GetSequence())
+		assert.Equal(t, uint32(2), status.GetWritten())
+		assert.Empty(t, status.GetErrors())
+		assert.False(t, status.GetDuplicate())
+	}
+}
+
+func TestRpcWriteBatchesDeduplicatesIdempotencyKey(t *testing.T) {
+	ctx, read, write, _, host := createCtxReadWriteOpts(t)
+	store := &mockStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+
+	statuses := writeBatches(ctx, t, host, write, 2, "batch-1")
+	require.Len(t, statuses, 2)
+	assert.Equal(t, uint32(2), statuses[0].GetWritten())
+	assert.False(t, statuses[0].GetDuplicate())
+	assert.Equal(t, uint32(0), statuses[1].GetWritten())
+	assert.True(t, statuses[1].GetDuplicate())
+}
+
+func TestRpcWriteBatchesReportsErrors(t *testing.T) {
+	ctx, read, write, _, host := createCtxReadWriteOpts(t)
+	store := &errStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+
+	statuses := writeBatches(ctx, t, host, write, 1, "")
+	require.Len(t, statuses, 1)
+	assert.Equal(t, uint32(0), statuses[0].GetWritten())
+	require.Len(t, statuses[0].GetErrors(), 2)
+	for i, batchErr := range statuses[0].GetErrors() {
+		assert.Equal(t, uint32(i), batchErr.GetIndex())
+		assert.Equal(t, errWrite.Error(), batchErr.GetError())
+	}
+}
+
 func TestRpcMultipleRead(t *testing.T) {
 	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
 	pages := 10
@@ -310,6 +407,10 @@ func (s *errStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, _
 	return nil, m3err.ErrNotImplemented
 }
 
+func (s *errStorage) FetchCount(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.CountResult, error) {
+	return nil, m3err.ErrNotImplemented
+}
+
 func (s *errStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
 	writeQueriesAreEqual(s.t, s.write, query)
 	return errWrite
@@ -0,0 +1,54 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// AccessLogRecord holds the fields of the access log of a request that are
+// only known to the handler serving the request.
+type AccessLogRecord struct {
+	series int64
+}
+
+// NewContextWithAccessLogRecord returns a context carrying a new access log
+// record for handlers to record fields of the request to.
+func NewContextWithAccessLogRecord(
+	ctx context.Context,
+) (context.Context, *AccessLogRecord) {
+	record := &AccessLogRecord{}
+	return context.WithValue(ctx, accessLogRecordKey, record), record
+}
+
+// Series returns the number of series read or written by the request.
+func (r *AccessLogRecord) Series() int64 {
+	return atomic.LoadInt64(&r.series)
+}
+
+// RecordSeries adds to the number of series read or written by the request
+// of the context, it is a no-op if the request is not access logged.
+func RecordSeries(ctx context.Context, n int) {
+	if record, ok := ctx.Value(accessLogRecordKey).(*AccessLogRecord); ok {
+		atomic.AddInt64(&record.series, int64(n))
+	}
+}
@@ -36,6 +36,7 @@ type loggerKeyType int
 const (
 	loggerKey loggerKeyType = iota
 	rqIDKey
+	accessLogRecordKey
 
 	undefinedID = "undefined"
 )
@@ -90,12 +91,18 @@ func NewContextWithID(ctx context.Context, id string) context.Context {
 
 // ReadContextID returns the context's id or "undefined"
 func ReadContextID(ctx context.Context) string {
-	if ctxID, ok := ctx.Value(rqIDKey).(string); ok {
+	if ctxID, ok := ContextID(ctx); ok {
 		return ctxID
 	}
 	return undefinedID
 }
 
+// ContextID returns the context's id and whether it has one
+func ContextID(ctx context.Context) (string, bool) {
+	ctxID, ok := ctx.Value(rqIDKey).(string)
+	return ctxID, ok
+}
+
 // WithContext returns a zap logger with as much context as possible
 func WithContext(ctx context.Context) *zap.Logger {
 	if ctx == nil {